@@ -25,8 +25,15 @@ import (
 	"github.com/decred/dcrd/wire"
 )
 
-// peersFilename is the default filename to store serialized peers.
-const peersFilename = "peers.json"
+const (
+	// peersFilename is the default filename to store serialized peers.
+	peersFilename = "peers.dat"
+
+	// legacyPeersFilename is the filename of the serialized peers in the
+	// legacy JSON format.  It is migrated to the current format when it is
+	// the only serialized state available.
+	legacyPeersFilename = "peers.json"
+)
 
 // AddrManager provides a concurrency safe address manager for caching potential
 // peers on the Decred network.
@@ -39,6 +46,10 @@ type AddrManager struct {
 	// is saved to and loaded from.
 	peersFile string
 
+	// legacyPeersFile is the path of the file that houses the address
+	// manager's serialized state in the legacy JSON format.
+	legacyPeersFile string
+
 	// lookupFunc is a function provided to the address manager that is used to
 	// perform DNS lookups for a given hostname.
 	// The provided function MUST be safe for concurrent access.
@@ -109,11 +120,15 @@ type AddrManager struct {
 
 	// triedBucketSize is the maximum number of addresses in each tried bucket.
 	triedBucketSize int
+
+	// asmap is an optional mapping of IP addresses to the autonomous system
+	// that announces them.  It is protected by the main mutex.
+	asmap *ASMap
 }
 
 // serializedKnownAddress is used to represent the serializable state of a
-// known address.  It excludes convenience fields that can be derived from the
-// address manager's state.
+// known address in the legacy JSON format.  It excludes convenience fields that
+// can be derived from the address manager's state.
 type serializedKnownAddress struct {
 	Addr        string
 	Src         string
@@ -124,7 +139,7 @@ type serializedKnownAddress struct {
 }
 
 // serializedAddrManager is used to represent the serializable state of an
-// address manager instance.
+// address manager instance in the legacy JSON format.
 type serializedAddrManager struct {
 	Version      int
 	Key          [32]byte
@@ -229,8 +244,12 @@ const (
 	// addresses is requested.
 	getKnownAddressPercentage = 23
 
+	// legacySerialisationVersion is the version of the legacy JSON on-disk
+	// format.
+	legacySerialisationVersion = 1
+
 	// serialisationVersion is the current version of the on-disk format.
-	serialisationVersion = 1
+	serialisationVersion = 2
 )
 
 // addOrUpdateAddress is a helper function to either update an address already known
@@ -442,57 +461,11 @@ func (a *AddrManager) savePeers() {
 		return
 	}
 
-	// First we make a serialisable data structure so we can encode it to JSON.
-	sam := new(serializedAddrManager)
-	sam.Version = serialisationVersion
-	copy(sam.Key[:], a.key[:])
-
-	sam.Addresses = make([]*serializedKnownAddress, len(a.addrIndex))
-	i := 0
-	for k, v := range a.addrIndex {
-		ska := new(serializedKnownAddress)
-		ska.Addr = k
-		ska.TimeStamp = v.na.Timestamp.Unix()
-		ska.Src = v.srcAddr.Key()
-		ska.Attempts = v.attempts
-		ska.LastAttempt = v.lastattempt.Unix()
-		ska.LastSuccess = v.lastsuccess.Unix()
-		// Tried and refs are implicit in the rest of the structure
-		// and will be worked out from context on unserialisation.
-		sam.Addresses[i] = ska
-		i++
-	}
-	for i := range a.addrNew {
-		sam.NewBuckets[i] = make([]string, len(a.addrNew[i]))
-		j := 0
-		for k := range a.addrNew[i] {
-			sam.NewBuckets[i][j] = k
-			j++
-		}
-	}
-	for i := range a.addrTried {
-		sam.TriedBuckets[i] = make([]string, len(a.addrTried[i]))
-		j := 0
-		for _, ka := range a.addrTried[i] {
-			sam.TriedBuckets[i][j] = ka.na.Key()
-			j++
-		}
-	}
-
 	// Write temporary peers file and then move it into place.
+	serialized := a.serializePeers()
 	tmpfile := a.peersFile + ".new"
-	w, err := os.Create(tmpfile)
-	if err != nil {
-		log.Errorf("Error opening file %s: %v", tmpfile, err)
-		return
-	}
-	enc := json.NewEncoder(w)
-	if err := enc.Encode(&sam); err != nil {
-		log.Errorf("Failed to encode file %s: %v", tmpfile, err)
-		return
-	}
-	if err := w.Close(); err != nil {
-		log.Errorf("Error closing file %s: %v", tmpfile, err)
+	if err := os.WriteFile(tmpfile, serialized, 0644); err != nil {
+		log.Errorf("Error writing file %s: %v", tmpfile, err)
 		return
 	}
 	if err := os.Rename(tmpfile, a.peersFile); err != nil {
@@ -500,35 +473,64 @@ func (a *AddrManager) savePeers() {
 		return
 	}
 	a.addrChanged = false
+
+	// Remove the legacy peers file now that its contents have been migrated
+	// to the current format.
+	err := os.Remove(a.legacyPeersFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove legacy peers file %s: %v",
+			a.legacyPeersFile, err)
+	}
 }
 
 // loadPeers loads the known addresses from a saved file.  If the file is empty,
 // missing, or malformed then no known addresses will be added to the address
 // manager from a call to this method.
+//
+// The known addresses are loaded from the legacy JSON peers file when there is
+// no peers file in the current format and they are migrated to the current
+// format the next time the peers are saved.
 func (a *AddrManager) loadPeers() {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
-	err := a.deserializePeers(a.peersFile)
+	peersFile := a.peersFile
+	serialized, err := os.ReadFile(peersFile)
+	switch {
+	case err == nil:
+		err = a.deserializePeers(serialized)
+
+	case os.IsNotExist(err):
+		peersFile = a.legacyPeersFile
+		if _, err := os.Stat(peersFile); os.IsNotExist(err) {
+			return
+		}
+		log.Infof("Migrating legacy peers file '%s'", peersFile)
+		err = a.deserializeLegacyPeers(peersFile)
+		a.addrChanged = true
+	}
 	if err != nil {
-		log.Errorf("Failed to parse file %s: %v", a.peersFile, err)
+		log.Errorf("Failed to parse file %s: %v", peersFile, err)
 		// if it is invalid we nuke the old one unconditionally.
-		err = os.Remove(a.peersFile)
+		err = os.Remove(peersFile)
 		if err != nil {
 			log.Warnf("Failed to remove corrupt peers file %s: %v",
-				a.peersFile, err)
+				peersFile, err)
 		}
 		a.reset()
 		return
 	}
-	log.Infof("Loaded %d addresses from file '%s'", a.numAddresses(), a.peersFile)
+	log.Infof("Loaded %d addresses from file '%s'", a.numAddresses(), peersFile)
 }
 
-func (a *AddrManager) deserializePeers(filePath string) error {
-	_, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		return nil
-	}
+// deserializeLegacyPeers restores the state of the address manager from the
+// provided file that houses the serialized state in the legacy JSON format.
+// The address manager must be reset prior to calling this function and must be
+// reset again when it returns an error since it may have been partially
+// restored.
+//
+// This function MUST be called with the address manager lock held (for writes).
+func (a *AddrManager) deserializeLegacyPeers(filePath string) error {
 	r, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("%s error opening file: %v", filePath, err)
@@ -542,7 +544,7 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		return fmt.Errorf("error reading %s: %v", filePath, err)
 	}
 
-	if sam.Version != serialisationVersion {
+	if sam.Version != legacySerialisationVersion {
 		return fmt.Errorf("unknown version %v in serialized "+
 			"addrmanager", sam.Version)
 	}
@@ -754,16 +756,32 @@ func (a *AddrManager) reset() {
 }
 
 // HostToNetAddress parses and returns a network address given a hostname in a
-// supported format (IPv4, IPv6, TORv2).  If the hostname cannot be immediately
-// converted from a known address format, it will be resolved using the lookup
-// function provided to the address manager. If it cannot be resolved, an error
-// is returned.
+// supported format (IPv4, IPv6, TORv2, TORv3, I2P).  If the hostname cannot be
+// immediately converted from a known address format, it will be resolved using
+// the lookup function provided to the address manager. If it cannot be
+// resolved, an error is returned.
 //
 // This function is safe for concurrent access.
 func (a *AddrManager) HostToNetAddress(host string, port uint16, services wire.ServiceFlag) (*NetAddress, error) {
-	// Tor address is 16 char base32 + ".onion"
+	// TORv2 address is 16 char base32 + ".onion" while TORv3 address is 56
+	// char base32 + ".onion".  I2P address is 52 char base32 + ".b32.i2p".
 	var ip net.IP
-	if len(host) == 22 && host[16:] == ".onion" {
+	if len(host) == torV3HostLen && strings.HasSuffix(host, ".onion") {
+		pubKey, err := decodeTORv3Host(host)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		return NewNetAddressFromParams(TORv3Address, pubKey, port, now,
+			services)
+	} else if strings.HasSuffix(host, i2pHostSuffix) {
+		hash, err := decodeI2PHost(host)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		return NewNetAddressFromParams(I2PAddress, hash, port, now, services)
+	} else if len(host) == 22 && host[16:] == ".onion" {
 		// go base32 encoding uses capitals (as does the rfc
 		// but Tor and bitcoind tend to user lowercase, so we switch
 		// case here.
@@ -1034,6 +1052,52 @@ func (a *AddrManager) SetServices(addr *NetAddress, services wire.ServiceFlag) e
 	return nil
 }
 
+// Services returns the services the provided known address was last known to
+// support.  If the address is unknown then an error is returned.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Services(addr *NetAddress) (wire.ServiceFlag, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		str := fmt.Sprintf("address %s not found", addr)
+		return 0, makeError(ErrAddressNotFound, str)
+	}
+
+	ka.mtx.Lock()
+	services := ka.na.Services
+	ka.mtx.Unlock()
+	return services, nil
+}
+
+// SetASMap sets the mapping of IP addresses to the autonomous system that
+// announces them that is used to determine the AS of network addresses.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) SetASMap(asmap *ASMap) {
+	a.mtx.Lock()
+	a.asmap = asmap
+	a.mtx.Unlock()
+}
+
+// ASN returns the number of the autonomous system that announces the provided
+// network address.  It returns 0 when no AS map is set, the address is not in
+// it, or the address is not an IPv4 or IPv6 address.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) ASN(na *NetAddress) uint32 {
+	a.mtx.Lock()
+	asmap := a.asmap
+	a.mtx.Unlock()
+
+	if asmap == nil || (na.Type != IPv4Address && na.Type != IPv6Address) {
+		return 0
+	}
+	return asmap.Lookup(na.IP)
+}
+
 // AddLocalAddress adds na to the list of known local addresses to advertise
 // with the given priority.
 //
@@ -1061,6 +1125,23 @@ func (a *AddrManager) AddLocalAddress(na *NetAddress, priority AddressPriority)
 	return nil
 }
 
+// RemoveLocalAddress removes na from the list of known local addresses to
+// advertise, such as when an address obtained from the network is no longer
+// valid.  It returns whether or not the address was known.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) RemoveLocalAddress(na *NetAddress) bool {
+	a.lamtx.Lock()
+	defer a.lamtx.Unlock()
+
+	key := na.Key()
+	if _, ok := a.localAddresses[key]; !ok {
+		return false
+	}
+	delete(a.localAddresses, key)
+	return true
+}
+
 // HasLocalAddress asserts if the manager has the provided local address.
 //
 // This function is safe for concurrent access.
@@ -1117,7 +1198,8 @@ const (
 	// Ipv6Strong represents a connection state between two IPV6 addresses.
 	Ipv6Strong
 
-	// Private represents a connection state connect between two Tor addresses.
+	// Private represents a connection state connect between two Tor addresses
+	// or two I2P addresses.
 	Private
 )
 
@@ -1130,8 +1212,17 @@ func getReachabilityFrom(localAddr, remoteAddr *NetAddress) NetAddressReach {
 		return Unreachable
 	}
 
-	if isOnionCatTor(remoteAddr.IP) {
-		if isOnionCatTor(localAddr.IP) {
+	// I2P addresses are only reachable from other I2P addresses since the I2P
+	// network does not provide a means to reach any other networks.
+	if remoteAddr.Type == I2PAddress || localAddr.Type == I2PAddress {
+		if remoteAddr.Type == localAddr.Type {
+			return Private
+		}
+		return Unreachable
+	}
+
+	if isOnion(remoteAddr) {
+		if isOnion(localAddr) {
 			return Private
 		}
 
@@ -1220,7 +1311,7 @@ func (a *AddrManager) GetBestLocalAddress(remoteAddr *NetAddress) *NetAddress {
 
 		// Send something unroutable if nothing suitable.
 		var ip net.IP
-		if !isIPv4(remoteAddr.IP) && !isOnionCatTor(remoteAddr.IP) {
+		if !isIPv4(remoteAddr.IP) && !isOnion(remoteAddr) {
 			ip = net.IPv6zero
 		} else {
 			ip = net.IPv4zero
@@ -1237,7 +1328,7 @@ func (a *AddrManager) GetBestLocalAddress(remoteAddr *NetAddress) *NetAddress {
 //
 // This function is safe for concurrent access.
 func (a *AddrManager) ValidatePeerNa(localAddr, remoteAddr *NetAddress) (bool, NetAddressReach) {
-	net := addressType(localAddr.IP)
+	net := localAddr.Type
 	reach := getReachabilityFrom(localAddr, remoteAddr)
 	valid := (net == IPv4Address && reach == Ipv4) || (net == IPv6Address &&
 		(reach == Ipv6Weak || reach == Ipv6Strong || reach == Teredo))
@@ -1250,6 +1341,7 @@ func (a *AddrManager) ValidatePeerNa(localAddr, remoteAddr *NetAddress) (bool, N
 func New(dataDir string, lookupFunc func(string) ([]net.IP, error)) *AddrManager {
 	am := AddrManager{
 		peersFile:       filepath.Join(dataDir, peersFilename),
+		legacyPeersFile: filepath.Join(dataDir, legacyPeersFilename),
 		lookupFunc:      lookupFunc,
 		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
 		quit:            make(chan struct{}),
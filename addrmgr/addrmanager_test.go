@@ -223,6 +223,24 @@ func TestAddLocalAddress(t *testing.T) {
 			t.Errorf("expected to find local address with key %v", netAddrKey)
 		}
 	}
+
+	// Ensure that removing a local address only removes that address and
+	// that removing an unknown address reports it was not known.
+	removeAddr := NewNetAddressIPPort(net.ParseIP("204.124.1.1"), testPort,
+		testServices)
+	if !amgr.RemoveLocalAddress(removeAddr) {
+		t.Fatalf("expected to remove local address %v", removeAddr)
+	}
+	if amgr.HasLocalAddress(removeAddr) {
+		t.Fatalf("expected to not have removed local address %v", removeAddr)
+	}
+	if amgr.RemoveLocalAddress(removeAddr) {
+		t.Fatalf("unexpected removal of unknown local address %v", removeAddr)
+	}
+	if got := len(amgr.LocalAddresses()); got != len(validLocalAddresses)-1 {
+		t.Fatalf("unexpected number of local addresses - got %d, want %d",
+			got, len(validLocalAddresses)-1)
+	}
 }
 
 func TestAttempt(t *testing.T) {
@@ -771,6 +789,16 @@ func TestHostToNetAddress(t *testing.T) {
 	// lookupFunc provided to the address manager instance for each test.
 	const hostnameForLookup = "hostname.test"
 	const services = wire.SFNodeNetwork
+	torV3Addr, err := NewNetAddressFromParams(TORv3Address, testTORv3Key(),
+		8333, time.Now(), services)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	i2pAddr, err := NewNetAddressFromParams(I2PAddress, testI2PHash(), 0,
+		time.Now(), services)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	tests := []struct {
 		name       string
@@ -795,6 +823,34 @@ func TestHostToNetAddress(t *testing.T) {
 		lookupFunc: nil,
 		wantErr:    true,
 		want:       nil,
+	}, {
+		name:       "valid torv3 onion address",
+		host:       testTORv3Host,
+		port:       8333,
+		lookupFunc: nil,
+		wantErr:    false,
+		want:       torV3Addr,
+	}, {
+		name:       "torv3 onion address with invalid checksum",
+		host:       "3gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion",
+		port:       8333,
+		lookupFunc: nil,
+		wantErr:    true,
+		want:       nil,
+	}, {
+		name:       "valid i2p address",
+		host:       testI2PHost,
+		port:       0,
+		lookupFunc: nil,
+		wantErr:    false,
+		want:       i2pAddr,
+	}, {
+		name:       "i2p address with invalid base32",
+		host:       "1hv7rggjo5z7nn75v3ac6epmda5u6dqjnzyb7uqi72x5qvupsuvq.b32.i2p",
+		port:       0,
+		lookupFunc: nil,
+		wantErr:    true,
+		want:       nil,
 	}, {
 		name: "unresolvable host name",
 		host: hostnameForLookup,
@@ -894,3 +950,44 @@ func TestSetServices(t *testing.T) {
 			netAddrB.Services, newServiceFlags)
 	}
 }
+
+// TestServices ensures the services of known addresses are returned as
+// expected and that an error is returned for unknown addresses.
+func TestServices(t *testing.T) {
+	addressManager := New("testServices", nil)
+	const services = wire.SFNodeNetwork | wire.SFNodeCF
+
+	// Attempt to get the services for an address not known to the address
+	// manager.
+	netAddr := NewNetAddressIPPort(net.ParseIP("1.2.3.4"), 8333, services)
+	_, err := addressManager.Services(netAddr)
+	if !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("unexpected error for unknown address: %v", err)
+	}
+
+	// Add the address and ensure its services are returned.
+	srcAddr := NewNetAddressIPPort(net.ParseIP("5.6.7.8"), 8333, services)
+	addressManager.addOrUpdateAddress(netAddr, srcAddr)
+	gotServices, err := addressManager.Services(netAddr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotServices != services {
+		t.Fatalf("unexpected services - got %v, want %v", gotServices,
+			services)
+	}
+
+	// Ensure updated services are returned.
+	const newServices = wire.SFNodeNetwork
+	if err := addressManager.SetServices(netAddr, newServices); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotServices, err = addressManager.Services(netAddr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotServices != newServices {
+		t.Fatalf("unexpected services - got %v, want %v", gotServices,
+			newServices)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ASMap maps IP addresses to the autonomous system (AS) that announces them.
+// It is used to diversify outbound connections across network operators since
+// a single operator may control many /16 netgroups.
+type ASMap struct {
+	// prefixes maps the string representation of each masked network prefix
+	// to the number of the AS that announces it.
+	prefixes map[string]uint32
+
+	// ipv4Lens and ipv6Lens are the distinct lengths of the IPv4 and IPv6
+	// prefixes, respectively, ordered from longest to shortest so lookups
+	// find the most specific prefix first.
+	ipv4Lens []int
+	ipv6Lens []int
+}
+
+// ParseASMap parses an AS map from the provided reader.  Each line consists of
+// a network prefix in CIDR notation followed by whitespace and the number of
+// the AS that announces it, optionally prefixed with "AS".  Empty lines and
+// lines starting with '#' are ignored.
+//
+// For example:
+//
+//	# prefix        asn
+//	203.0.113.0/24  AS64496
+//	2001:db8::/32   64497
+//
+// ErrInvalidASMap is returned when a line is malformed.
+func ParseASMap(r io.Reader) (*ASMap, error) {
+	m := &ASMap{prefixes: make(map[string]uint32)}
+	ipv4Lens := make(map[int]struct{})
+	ipv6Lens := make(map[int]struct{})
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			str := fmt.Sprintf("line %d: expected a prefix and an AS number",
+				lineNum)
+			return nil, makeError(ErrInvalidASMap, str)
+		}
+		_, prefix, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			str := fmt.Sprintf("line %d: invalid prefix %q", lineNum,
+				fields[0])
+			return nil, makeError(ErrInvalidASMap, str)
+		}
+		asnStr := strings.TrimPrefix(strings.ToUpper(fields[1]), "AS")
+		asn, err := strconv.ParseUint(asnStr, 10, 32)
+		if err != nil || asn == 0 {
+			str := fmt.Sprintf("line %d: invalid AS number %q", lineNum,
+				fields[1])
+			return nil, makeError(ErrInvalidASMap, str)
+		}
+
+		// IPv4-mapped IPv6 prefixes are treated as the equivalent IPv4
+		// prefix.
+		ones, bits := prefix.Mask.Size()
+		if bits == 8*net.IPv6len && ones >= 96 && prefix.IP.To4() != nil {
+			ones, bits = ones-96, 8*net.IPv4len
+		}
+		if bits == 8*net.IPv4len {
+			ipv4Lens[ones] = struct{}{}
+		} else {
+			ipv6Lens[ones] = struct{}{}
+		}
+		m.prefixes[prefix.String()] = uint32(asn)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sortedLens := func(lens map[int]struct{}) []int {
+		sorted := make([]int, 0, len(lens))
+		for l := range lens {
+			sorted = append(sorted, l)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+		return sorted
+	}
+	m.ipv4Lens = sortedLens(ipv4Lens)
+	m.ipv6Lens = sortedLens(ipv6Lens)
+	return m, nil
+}
+
+// Lookup returns the number of the AS that announces the most specific prefix
+// containing the provided IP address or 0 when it is not in the map.
+func (m *ASMap) Lookup(ip net.IP) uint32 {
+	lens, bits := m.ipv6Lens, 8*net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, lens, bits = ip4, m.ipv4Lens, 8*net.IPv4len
+	}
+	for _, l := range lens {
+		mask := net.CIDRMask(l, bits)
+		prefix := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		if asn, ok := m.prefixes[prefix.String()]; ok {
+			return asn
+		}
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// testASMap is an AS map used in the tests.
+const testASMap = `
+# prefix                 asn
+203.0.0.0/8              AS64496
+203.0.113.0/24           as64497
+::ffff:198.51.100.0/120  64498
+2001:db8::/32            64499
+2001:db8:1::/48          64500
+`
+
+// TestASMap ensures AS maps are parsed as expected and that IP addresses are
+// mapped to the AS that announces the most specific prefix containing them.
+func TestASMap(t *testing.T) {
+	asmap, err := ParseASMap(strings.NewReader(testASMap))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		want uint32
+	}{
+		{"ipv4 less specific prefix", "203.0.112.1", 64496},
+		{"ipv4 more specific prefix", "203.0.113.1", 64497},
+		{"ipv4-mapped ipv6 address", "::ffff:203.0.113.1", 64497},
+		{"ipv4-mapped ipv6 prefix", "198.51.100.7", 64498},
+		{"ipv4 not in map", "192.0.2.1", 0},
+		{"ipv6 less specific prefix", "2001:db8:2::1", 64499},
+		{"ipv6 more specific prefix", "2001:db8:1::1", 64500},
+		{"ipv6 not in map", "2001:db9::1", 0},
+	}
+	for _, test := range tests {
+		if got := asmap.Lookup(net.ParseIP(test.ip)); got != test.want {
+			t.Errorf("%q: unexpected asn - got %d, want %d", test.name, got,
+				test.want)
+		}
+	}
+
+	// Ensure malformed AS maps are rejected.
+	invalidTests := []struct {
+		name string
+		data string
+	}{
+		{"missing asn", "203.0.113.0/24"},
+		{"extra field", "203.0.113.0/24 64496 extra"},
+		{"invalid prefix", "203.0.113.0/33 64496"},
+		{"address without length", "203.0.113.0 64496"},
+		{"invalid asn", "203.0.113.0/24 ASX"},
+		{"zero asn", "203.0.113.0/24 0"},
+		{"asn too large", "203.0.113.0/24 4294967296"},
+	}
+	for _, test := range invalidTests {
+		_, err := ParseASMap(strings.NewReader(test.data))
+		if !errors.Is(err, ErrInvalidASMap) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name, err,
+				ErrInvalidASMap)
+		}
+	}
+}
+
+// TestAddrManagerASN ensures the address manager reports the AS of network
+// addresses according to the AS map it is configured with.
+func TestAddrManagerASN(t *testing.T) {
+	asmap, err := ParseASMap(strings.NewReader(testASMap))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ipv4Addr := NewNetAddressIPPort(net.ParseIP("203.0.113.1"), 9108,
+		wire.SFNodeNetwork)
+	torV3Addr, err := NewNetAddressFromParams(TORv3Address, testTORv3Key(),
+		9108, time.Now(), wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure no AS is reported without an AS map.
+	addrManager := New("testAddrManagerASN", nil)
+	if asn := addrManager.ASN(ipv4Addr); asn != 0 {
+		t.Fatalf("unexpected asn without map - got %d, want 0", asn)
+	}
+
+	// Ensure the AS is reported for IP addresses once the map is set, but
+	// not for other address types.
+	addrManager.SetASMap(asmap)
+	if asn := addrManager.ASN(ipv4Addr); asn != 64497 {
+		t.Fatalf("unexpected asn - got %d, want 64497", asn)
+	}
+	if asn := addrManager.ASN(torV3Addr); asn != 0 {
+		t.Fatalf("unexpected asn for torv3 address - got %d, want 0", asn)
+	}
+}
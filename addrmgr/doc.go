@@ -29,11 +29,24 @@ generally helps provide greater peer diversity, and perhaps more importantly,
 drastically reduces the chances an attacker is able to coerce your peer into
 only connecting to nodes they control.
 
-The address manager also understands routability and Tor addresses and tries
-hard to only return routable addresses.  In addition, it uses the information
-provided by the caller about connected, known good, and attempted addresses to
-periodically purge peers which no longer appear to be good peers as well as
-bias the selection toward known good peers.  The general idea is to make a best
-effort at only providing usable addresses.
+Since a single network operator may control many groups, the address manager
+may also be provided with an AS map via SetASMap so callers can determine the
+autonomous system that announces an address via ASN and further diversify their
+connections across network operators.
+
+The address manager also understands routability, Tor, and I2P addresses and
+tries hard to only return routable addresses.  In addition, it uses the
+information provided by the caller about connected, known good, and attempted
+addresses to periodically purge peers which no longer appear to be good peers
+as well as bias the selection toward known good peers.  The general idea is to
+make a best effort at only providing usable addresses.
+
+The known addresses are periodically persisted to a peers.dat file in the data
+directory using a versioned binary format that retains the bucket each address
+is in along with the information used to judge its quality, such as when it was
+last seen and the history of connection attempts to it.  The addresses are
+partitioned by network and ordered so that the same state is always persisted
+identically.  A peers.json file written by previous versions is automatically
+migrated to the current format.
 */
 package addrmgr
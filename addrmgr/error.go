@@ -14,6 +14,26 @@ const (
 	// ErrAddressNotFound indicates that an operation in the address manager
 	// failed due to an address lookup failure.
 	ErrAddressNotFound = ErrorKind("ErrAddressNotFound")
+
+	// ErrInvalidTORv3Address indicates that a TORv3 onion address is not
+	// valid due to either an invalid encoding, an unsupported version, or a
+	// checksum mismatch.
+	ErrInvalidTORv3Address = ErrorKind("ErrInvalidTORv3Address")
+
+	// ErrInvalidI2PAddress indicates that an I2P address is not valid due to
+	// an invalid encoding.
+	ErrInvalidI2PAddress = ErrorKind("ErrInvalidI2PAddress")
+
+	// ErrMismatchedAddressType indicates that the raw bytes of a network
+	// address are not valid for the network address type it was created with.
+	ErrMismatchedAddressType = ErrorKind("ErrMismatchedAddressType")
+
+	// ErrInvalidASMap indicates that an AS map is malformed.
+	ErrInvalidASMap = ErrorKind("ErrInvalidASMap")
+
+	// ErrMalformedPeersFile indicates that the serialized state of the address
+	// manager is corrupt or otherwise invalid.
+	ErrMalformedPeersFile = ErrorKind("ErrMalformedPeersFile")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -6,6 +6,10 @@ require (
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.3
 	github.com/decred/dcrd/wire v1.5.0
 	github.com/decred/slog v1.2.0
+	golang.org/x/crypto v0.8.0
 )
 
-require github.com/decred/dcrd/crypto/blake256 v1.0.0 // indirect
+require (
+	github.com/decred/dcrd/crypto/blake256 v1.0.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+)
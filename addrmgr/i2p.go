@@ -0,0 +1,50 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+const (
+	// i2pHashSize is the size of the SHA-256 hash of an I2P destination that
+	// identifies an I2P address.  I2P network addresses store the hash as
+	// their IP.
+	i2pHashSize = 32
+
+	// i2pHostSuffix is the suffix of the host of an I2P address, which is the
+	// base32 encoding of the destination hash.
+	i2pHostSuffix = ".b32.i2p"
+)
+
+// i2pEncoding is the base32 encoding used by I2P addresses, which omits the
+// padding.
+var i2pEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// encodeI2PHost returns the I2P address host, including the ".b32.i2p" suffix,
+// for the provided destination hash.
+func encodeI2PHost(hash []byte) string {
+	encoded := i2pEncoding.EncodeToString(hash)
+	return strings.ToLower(encoded) + i2pHostSuffix
+}
+
+// decodeI2PHost returns the destination hash encoded in the provided I2P
+// address host, which must include the ".b32.i2p" suffix.
+//
+// ErrInvalidI2PAddress is returned when the host is not a valid I2P address.
+func decodeI2PHost(host string) ([]byte, error) {
+	// Go base32 encoding uses capitals (as does the rfc), but I2P uses
+	// lowercase, so switch case here.
+	encoded := strings.ToUpper(strings.TrimSuffix(host, i2pHostSuffix))
+	hash, err := i2pEncoding.DecodeString(encoded)
+	if err != nil || len(hash) != i2pHashSize {
+		str := fmt.Sprintf("%s is not a valid base32 encoded I2P address",
+			host)
+		return nil, makeError(ErrInvalidI2PAddress, str)
+	}
+	return hash, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// testI2PHost is a valid I2P address host used in the tests.
+const testI2PHost = "3hv7rggjo5z7nn75v3ac6epmda5u6dqjnzyb7uqi72x5qvupsuvq.b32.i2p"
+
+// testI2PHash returns the destination hash encoded in testI2PHost.
+func testI2PHash() []byte {
+	hash, err := hex.DecodeString("d9ebf898c97773f6b7fdaec02f11ec183b4f0e" +
+		"096e701fd208feafd8568f952b")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+// TestI2PHost ensures I2P address hosts are encoded and decoded as expected and
+// that invalid hosts are rejected.
+func TestI2PHost(t *testing.T) {
+	// Ensure the destination hash round trips through the host encoding.
+	hash := testI2PHash()
+	if host := encodeI2PHost(hash); host != testI2PHost {
+		t.Fatalf("unexpected host - got %s, want %s", host, testI2PHost)
+	}
+	decoded, err := decodeI2PHost(testI2PHost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(hash, decoded) {
+		t.Fatalf("unexpected hash - got %x, want %x", decoded, hash)
+	}
+
+	tests := []struct {
+		name string
+		host string
+	}{{
+		name: "invalid base32",
+		host: "1hv7rggjo5z7nn75v3ac6epmda5u6dqjnzyb7uqi72x5qvupsuvq.b32.i2p",
+	}, {
+		name: "short hash",
+		host: "3hv7rggjo5z7nn75v3ac6epmda5u6dqjnzyb7uqi72x5qvup.b32.i2p",
+	}, {
+		name: "padded",
+		host: "3hv7rggjo5z7nn75v3ac6epmda5u6dqjnzyb7uqi72x5qvupsuvq====.b32.i2p",
+	}}
+	for _, test := range tests {
+		_, err := decodeI2PHost(test.host)
+		if !errors.Is(err, ErrInvalidI2PAddress) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name,
+				err, ErrInvalidI2PAddress)
+		}
+	}
+}
+
+// TestI2PAddress ensures I2P network addresses are identified, keyed, grouped,
+// and considered reachable as expected.
+func TestI2PAddress(t *testing.T) {
+	netAddr, err := NewNetAddressFromParams(I2PAddress, testI2PHash(), 0,
+		time.Now(), wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !netAddr.IsRoutable() {
+		t.Fatal("i2p address is not routable")
+	}
+	wantKey := testI2PHost + ":0"
+	if key := netAddr.Key(); key != wantKey {
+		t.Fatalf("unexpected key - got %s, want %s", key, wantKey)
+	}
+
+	// The group key is keyed off the first 4 bits of the hash (0xd9).
+	if key := netAddr.GroupKey(); key != "i2p:9" {
+		t.Fatalf("unexpected group key - got %s, want %s", key, "i2p:9")
+	}
+
+	// Ensure the address is parsed from its key.
+	addrManager := New("testI2PAddress", nil)
+	parsed, err := addrManager.newAddressFromString(wantKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Key() != wantKey || parsed.Type != I2PAddress {
+		t.Fatalf("unexpected parsed address %v", parsed)
+	}
+
+	// Ensure addresses with a hash of the wrong size are rejected.
+	_, err = NewNetAddressFromParams(I2PAddress, testI2PHash()[:31], 0,
+		time.Now(), wire.SFNodeNetwork)
+	if !errors.Is(err, ErrMismatchedAddressType) {
+		t.Fatalf("unexpected error - got %v, want %v", err,
+			ErrMismatchedAddressType)
+	}
+
+	// Ensure I2P addresses are only reachable from one another.
+	torV3Addr, err := NewNetAddressFromParams(TORv3Address, testTORv3Key(),
+		9108, time.Now(), wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ipv4Addr := NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108,
+		wire.SFNodeNetwork)
+	ipv6Addr := NewNetAddressIPPort(net.ParseIP("2001:470::1"), 9108,
+		wire.SFNodeNetwork)
+	tests := []struct {
+		name   string
+		local  *NetAddress
+		remote *NetAddress
+		want   NetAddressReach
+	}{
+		{"i2p to i2p", netAddr, netAddr, Private},
+		{"routable ipv4 to i2p", ipv4Addr, netAddr, Unreachable},
+		{"torv3 to i2p", torV3Addr, netAddr, Unreachable},
+		{"i2p to routable ipv4", netAddr, ipv4Addr, Unreachable},
+		{"i2p to routable ipv6", netAddr, ipv6Addr, Unreachable},
+		{"i2p to torv3", netAddr, torV3Addr, Unreachable},
+	}
+	for _, test := range tests {
+		reach := getReachabilityFrom(test.local, test.remote)
+		if reach != test.want {
+			t.Errorf("%q: unexpected reachability - got %v, want %v",
+				test.name, reach, test.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ package addrmgr
 
 import (
 	"encoding/base32"
+	"fmt"
 	"net"
 	"strconv"
 	"strings"
@@ -29,20 +30,35 @@ type NetAddress struct {
 
 	// Services represents the service flags supported by this network address.
 	Services wire.ServiceFlag
+
+	// Type represents the network the network address belongs to.  It
+	// determines how the IP field is interpreted.
+	Type NetAddressType
 }
 
 // IsRoutable returns a boolean indicating whether the network address is
-// routable.
+// routable.  TORv3 and I2P addresses are always considered routable.
 func (netAddr *NetAddress) IsRoutable() bool {
+	switch netAddr.Type {
+	case TORv3Address, I2PAddress:
+		return true
+	}
 	return IsRoutable(netAddr.IP)
 }
 
 // ipString returns a string representation of the network address' IP field.
-// If the ip is in the range used for TORv2 addresses then it will be
-// transformed into the respective .onion address.  It does not include the
-// port.
+// If the ip is in the range used for TORv2 addresses or is a TORv3 public key
+// then it will be transformed into the respective .onion address.  Likewise, I2P
+// destination hashes are transformed into the respective .b32.i2p address.  It
+// does not include the port.
 func (netAddr *NetAddress) ipString() string {
 	netIP := netAddr.IP
+	switch netAddr.Type {
+	case TORv3Address:
+		return encodeTORv3Host(netIP)
+	case I2PAddress:
+		return encodeI2PHost(netIP)
+	}
 	if isOnionCatTor(netIP) {
 		// We know now that na.IP is long enough.
 		base32 := base32.StdEncoding.EncodeToString(netIP[6:])
@@ -95,7 +111,8 @@ func (a *AddrManager) newAddressFromString(addr string) (*NetAddress, error) {
 }
 
 // NewNetAddressIPPort creates a new address manager network address given an ip,
-// port, and the supported service flags for the address.
+// port, and the supported service flags for the address.  The network the
+// address belongs to is determined from the ip.
 func NewNetAddressIPPort(ip net.IP, port uint16, services wire.ServiceFlag) *NetAddress {
 	timestamp := time.Unix(time.Now().Unix(), 0)
 	return &NetAddress{
@@ -103,5 +120,44 @@ func NewNetAddressIPPort(ip net.IP, port uint16, services wire.ServiceFlag) *Net
 		Port:      port,
 		Services:  services,
 		Timestamp: timestamp,
+		Type:      addressType(ip),
+	}
+}
+
+// NewNetAddressFromParams creates a new address manager network address given
+// the network it belongs to, its raw address bytes, port, last seen timestamp,
+// and the supported service flags for the address.  Unlike NewNetAddressIPPort,
+// this allows creating addresses that are not IP addresses, such as TORv3 and
+// I2P addresses, whose raw address bytes are the public key of the onion
+// service and the hash of the I2P destination, respectively.
+//
+// ErrMismatchedAddressType is returned when the length of the raw address
+// bytes is not valid for the provided network.
+func NewNetAddressFromParams(netAddrType NetAddressType, addrBytes []byte,
+	port uint16, timestamp time.Time, services wire.ServiceFlag) (*NetAddress, error) {
+
+	var valid bool
+	switch netAddrType {
+	case LocalAddress, IPv4Address:
+		valid = len(addrBytes) == net.IPv4len || len(addrBytes) == net.IPv6len
+	case IPv6Address, TORv2Address:
+		valid = len(addrBytes) == net.IPv6len
+	case TORv3Address:
+		valid = len(addrBytes) == torV3KeySize
+	case I2PAddress:
+		valid = len(addrBytes) == i2pHashSize
 	}
+	if !valid {
+		str := fmt.Sprintf("%d byte address is not valid for network "+
+			"address type %d", len(addrBytes), netAddrType)
+		return nil, makeError(ErrMismatchedAddressType, str)
+	}
+
+	return &NetAddress{
+		IP:        addrBytes,
+		Port:      port,
+		Services:  services,
+		Timestamp: time.Unix(timestamp.Unix(), 0),
+		Type:      netAddrType,
+	}, nil
 }
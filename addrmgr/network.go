@@ -116,6 +116,12 @@ func isOnionCatTor(netIP net.IP) bool {
 	return onionCatNet.Contains(netIP)
 }
 
+// isOnion returns whether or not the passed address is a Tor onion address of
+// any supported version.
+func isOnion(na *NetAddress) bool {
+	return na.Type == TORv3Address || isOnionCatTor(na.IP)
+}
+
 // NetAddressType is used to indicate which network a network address belongs
 // to.
 type NetAddressType uint8
@@ -125,9 +131,12 @@ const (
 	IPv4Address
 	IPv6Address
 	TORv2Address
+	TORv3Address
+	I2PAddress
 )
 
-// addressType returns the network address type of the provided network address.
+// addressType returns the network address type of the provided IP address.
+// Since TORv3 and I2P addresses are not IP addresses, they are never returned.
 func addressType(netIP net.IP) NetAddressType {
 	switch {
 	case isLocal(netIP):
@@ -259,10 +268,21 @@ func IsRoutable(netIP net.IP) bool {
 // GroupKey returns a string representing the network group an address is part
 // of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
 // "local" for a local address, the string "tor:key" where key is the /4 of the
-// onion address for Tor address, and the string "unroutable" for an unroutable
-// address.
+// onion address for TORv2 addresses, the string "torv3:key" where key is the /4
+// of the public key for TORv3 addresses, the string "i2p:key" where key is the
+// /4 of the destination hash for I2P addresses, and the string "unroutable" for
+// an unroutable address.  Since the group keys of TORv3 and I2P addresses are
+// distinct from all other group keys, they are placed into separate buckets.
 func (na *NetAddress) GroupKey() string {
 	netIP := net.IP(na.IP)
+	switch na.Type {
+	case TORv3Address:
+		// group is keyed off the first 4 bits of the public key.
+		return fmt.Sprintf("torv3:%d", netIP[0]&((1<<4)-1))
+	case I2PAddress:
+		// group is keyed off the first 4 bits of the destination hash.
+		return fmt.Sprintf("i2p:%d", netIP[0]&((1<<4)-1))
+	}
 	if isLocal(netIP) {
 		return "local"
 	}
@@ -0,0 +1,506 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// The address manager state is persisted in a versioned binary format that
+// records the bucket assignment and connection history of every known address
+// so the state is restored exactly as it was, as opposed to the legacy JSON
+// format which discarded the last seen time and services of the addresses.
+//
+// The serialized state is laid out as follows:
+//
+//	magic          [4]byte  "dcra"
+//	version        uint32   serialisationVersion
+//	key            [32]byte key used to map addresses to buckets
+//	num partitions uint8    number of network partitions that follow
+//	partitions     ...      partitions in ascending order of network
+//	checksum       [4]byte  first 4 bytes of the hash of all prior bytes
+//
+// Each partition houses all of the addresses that belong to a single network
+// (for example IPv4, IPv6, TORv3, or I2P) so the addresses of each network can
+// be counted and validated independently:
+//
+//	network        uint8    network address type of all addresses
+//	num addresses  uint32   number of addresses that follow
+//	addresses      ...      addresses in ascending order of their keys
+//
+// Each address is serialized as:
+//
+//	addr len       uint8    length of the raw address bytes
+//	addr           []byte   raw address bytes
+//	port           uint16   port
+//	timestamp      int64    last time the address was seen
+//	services       uint64   service flags supported by the address
+//	src network    uint8    network of the address that suggested the address
+//	src addr len   uint8    length of the raw source address bytes
+//	src addr       []byte   raw source address bytes
+//	src port       uint16   source port
+//	attempts       uint32   connection attempts since the last success
+//	last attempt   int64    time of the last connection attempt
+//	last success   int64    time of the last successful connection
+//	tried          uint8    1 when the address is in a tried bucket
+//	buckets        ...      bucket assignments
+//
+// The bucket assignments of tried addresses consist of the uint16 index of the
+// tried bucket the address is in.  Those of new addresses consist of a uint8
+// count followed by the uint16 indices of the new buckets the address is in
+// in ascending order.
+//
+// All integers are little endian and all times are unix timestamps.  Since
+// both the partitions and the addresses within them are ordered, serializing
+// the same state always produces the same bytes.
+
+// peersFileMagic identifies a serialized address manager state.
+var peersFileMagic = [4]byte{'d', 'c', 'r', 'a'}
+
+const (
+	// peersChecksumSize is the number of bytes of the checksum at the end of
+	// a serialized address manager state.
+	peersChecksumSize = 4
+
+	// maxSerializedAddrLen is the maximum length of the raw bytes of a
+	// serialized network address.
+	maxSerializedAddrLen = 32
+)
+
+// serializedPeersWriter provides helpers to write the fields of a serialized
+// address manager state.
+type serializedPeersWriter struct {
+	buf bytes.Buffer
+	tmp [8]byte
+}
+
+func (w *serializedPeersWriter) putUint8(v uint8) {
+	w.buf.WriteByte(v)
+}
+
+func (w *serializedPeersWriter) putUint16(v uint16) {
+	binary.LittleEndian.PutUint16(w.tmp[:2], v)
+	w.buf.Write(w.tmp[:2])
+}
+
+func (w *serializedPeersWriter) putUint32(v uint32) {
+	binary.LittleEndian.PutUint32(w.tmp[:4], v)
+	w.buf.Write(w.tmp[:4])
+}
+
+func (w *serializedPeersWriter) putUint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.tmp[:8], v)
+	w.buf.Write(w.tmp[:8])
+}
+
+func (w *serializedPeersWriter) putTime(t time.Time) {
+	w.putUint64(uint64(t.Unix()))
+}
+
+// putAddr writes the raw bytes of the provided network address prefixed by
+// their length followed by the port.
+func (w *serializedPeersWriter) putAddr(netAddr *NetAddress) {
+	w.putUint8(uint8(len(netAddr.IP)))
+	w.buf.Write(netAddr.IP)
+	w.putUint16(netAddr.Port)
+}
+
+// serializePeers returns the serialized state of the address manager in the
+// current format.
+//
+// This function MUST be called with the address manager lock held (for reads).
+func (a *AddrManager) serializePeers() []byte {
+	// Determine the new buckets each new address is in.
+	newBuckets := make(map[*KnownAddress][]uint16, a.nNew)
+	for i := range a.addrNew {
+		for _, ka := range a.addrNew[i] {
+			newBuckets[ka] = append(newBuckets[ka], uint16(i))
+		}
+	}
+	triedBuckets := make(map[*KnownAddress]uint16, a.nTried)
+	for i := range a.addrTried {
+		for _, ka := range a.addrTried[i] {
+			triedBuckets[ka] = uint16(i)
+		}
+	}
+
+	// Partition the addresses by network and order them by their keys.
+	partitions := make(map[NetAddressType][]*KnownAddress)
+	for _, ka := range a.addrIndex {
+		netType := ka.na.Type
+		partitions[netType] = append(partitions[netType], ka)
+	}
+	networks := make([]NetAddressType, 0, len(partitions))
+	for netType, addrs := range partitions {
+		networks = append(networks, netType)
+		sort.Slice(addrs, func(i, j int) bool {
+			return addrs[i].na.Key() < addrs[j].na.Key()
+		})
+	}
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i] < networks[j]
+	})
+
+	var w serializedPeersWriter
+	w.buf.Write(peersFileMagic[:])
+	w.putUint32(serialisationVersion)
+	w.buf.Write(a.key[:])
+	w.putUint8(uint8(len(networks)))
+	for _, netType := range networks {
+		addrs := partitions[netType]
+		w.putUint8(uint8(netType))
+		w.putUint32(uint32(len(addrs)))
+		for _, ka := range addrs {
+			w.putAddr(ka.na)
+			w.putTime(ka.na.Timestamp)
+			w.putUint64(uint64(ka.na.Services))
+			w.putUint8(uint8(ka.srcAddr.Type))
+			w.putAddr(ka.srcAddr)
+			w.putUint32(uint32(ka.attempts))
+			w.putTime(ka.lastattempt)
+			w.putTime(ka.lastsuccess)
+			if ka.tried {
+				w.putUint8(1)
+				w.putUint16(triedBuckets[ka])
+				continue
+			}
+			w.putUint8(0)
+			buckets := newBuckets[ka]
+			sort.Slice(buckets, func(i, j int) bool {
+				return buckets[i] < buckets[j]
+			})
+			w.putUint8(uint8(len(buckets)))
+			for _, bucket := range buckets {
+				w.putUint16(bucket)
+			}
+		}
+	}
+	checksum := chainhash.HashB(w.buf.Bytes())
+	w.buf.Write(checksum[:peersChecksumSize])
+	return w.buf.Bytes()
+}
+
+// errUnexpectedEnd is returned when attempting to read past the end of a
+// serialized address manager state.
+var errUnexpectedEnd = makeError(ErrMalformedPeersFile,
+	"unexpected end of serialized state")
+
+// serializedPeersReader provides helpers to read the fields of a serialized
+// address manager state.
+type serializedPeersReader struct {
+	r *bytes.Reader
+}
+
+func (r *serializedPeersReader) readBytes(b []byte) error {
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return errUnexpectedEnd
+	}
+	return nil
+}
+
+func (r *serializedPeersReader) readUint8() (uint8, error) {
+	v, err := r.r.ReadByte()
+	if err != nil {
+		return 0, errUnexpectedEnd
+	}
+	return v, nil
+}
+
+func (r *serializedPeersReader) readUint16() (uint16, error) {
+	var b [2]byte
+	if err := r.readBytes(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b[:]), nil
+}
+
+func (r *serializedPeersReader) readUint32() (uint32, error) {
+	var b [4]byte
+	if err := r.readBytes(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b[:]), nil
+}
+
+func (r *serializedPeersReader) readUint64() (uint64, error) {
+	var b [8]byte
+	if err := r.readBytes(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+func (r *serializedPeersReader) readTime() (time.Time, error) {
+	v, err := r.readUint64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(v), 0), nil
+}
+
+// readAddr reads the raw bytes and port of a network address.
+func (r *serializedPeersReader) readAddr() ([]byte, uint16, error) {
+	addrLen, err := r.readUint8()
+	if err != nil {
+		return nil, 0, err
+	}
+	if addrLen > maxSerializedAddrLen {
+		str := fmt.Sprintf("address length %d exceeds the maximum %d",
+			addrLen, maxSerializedAddrLen)
+		return nil, 0, makeError(ErrMalformedPeersFile, str)
+	}
+	addrBytes := make([]byte, addrLen)
+	if err := r.readBytes(addrBytes); err != nil {
+		return nil, 0, err
+	}
+	port, err := r.readUint16()
+	if err != nil {
+		return nil, 0, err
+	}
+	return addrBytes, port, nil
+}
+
+// readKnownAddress reads a known address that belongs to the provided network
+// along with the indices of the buckets it is in.  The returned tried bucket
+// is only set when the known address is tried and the returned new buckets are
+// only set when it is not.
+func (r *serializedPeersReader) readKnownAddress(netType NetAddressType) (*KnownAddress, int, []int, error) {
+	// Read the address along with its timestamp and services.  The address
+	// is created after reading them since they are part of it.
+	addrBytes, port, err := r.readAddr()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	timestamp, err := r.readTime()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	services, err := r.readUint64()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	netAddr, err := NewNetAddressFromParams(netType, addrBytes, port,
+		timestamp, wire.ServiceFlag(services))
+	if err != nil {
+		return nil, 0, nil, makeError(ErrMalformedPeersFile, err.Error())
+	}
+
+	srcType, err := r.readUint8()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	srcAddrBytes, srcPort, err := r.readAddr()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	srcAddr, err := NewNetAddressFromParams(NetAddressType(srcType),
+		srcAddrBytes, srcPort, timestamp, wire.ServiceFlag(services))
+	if err != nil {
+		return nil, 0, nil, makeError(ErrMalformedPeersFile, err.Error())
+	}
+
+	attempts, err := r.readUint32()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	lastAttempt, err := r.readTime()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	lastSuccess, err := r.readTime()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	ka := &KnownAddress{
+		na:          netAddr,
+		srcAddr:     srcAddr,
+		attempts:    int(attempts),
+		lastattempt: lastAttempt,
+		lastsuccess: lastSuccess,
+	}
+
+	tried, err := r.readUint8()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	switch tried {
+	case 1:
+		bucket, err := r.readUint16()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		ka.tried = true
+		return ka, int(bucket), nil, nil
+
+	case 0:
+		numBuckets, err := r.readUint8()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if numBuckets == 0 || numBuckets > newBucketsPerAddress {
+			str := fmt.Sprintf("new address %s is in %d buckets which is not "+
+				"in the range [1, %d]", netAddr, numBuckets,
+				newBucketsPerAddress)
+			return nil, 0, nil, makeError(ErrMalformedPeersFile, str)
+		}
+		buckets := make([]int, numBuckets)
+		for i := range buckets {
+			bucket, err := r.readUint16()
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			buckets[i] = int(bucket)
+		}
+		return ka, 0, buckets, nil
+	}
+
+	str := fmt.Sprintf("invalid tried flag %d for address %s", tried, netAddr)
+	return nil, 0, nil, makeError(ErrMalformedPeersFile, str)
+}
+
+// deserializePeers restores the state of the address manager from the provided
+// serialized state in the current format.  The address manager must be reset
+// prior to calling this function and must be reset again when it returns an
+// error since it may have been partially restored.
+//
+// ErrMalformedPeersFile is returned when the serialized state is corrupt or
+// otherwise invalid.
+//
+// This function MUST be called with the address manager lock held (for writes).
+func (a *AddrManager) deserializePeers(serialized []byte) error {
+	// Ensure the serialized state is not corrupt prior to parsing it.
+	minLen := len(peersFileMagic) + 4 + len(a.key) + 1 + peersChecksumSize
+	if len(serialized) < minLen {
+		str := fmt.Sprintf("serialized state is %d bytes which is less than "+
+			"the minimum %d bytes", len(serialized), minLen)
+		return makeError(ErrMalformedPeersFile, str)
+	}
+	checksumOffset := len(serialized) - peersChecksumSize
+	checksum := chainhash.HashB(serialized[:checksumOffset])
+	if !bytes.Equal(checksum[:peersChecksumSize], serialized[checksumOffset:]) {
+		str := "serialized state checksum mismatch"
+		return makeError(ErrMalformedPeersFile, str)
+	}
+	r := serializedPeersReader{r: bytes.NewReader(serialized[:checksumOffset])}
+
+	var magic [len(peersFileMagic)]byte
+	if err := r.readBytes(magic[:]); err != nil {
+		return err
+	}
+	if magic != peersFileMagic {
+		str := fmt.Sprintf("invalid magic %x", magic)
+		return makeError(ErrMalformedPeersFile, str)
+	}
+	version, err := r.readUint32()
+	if err != nil {
+		return err
+	}
+	if version != serialisationVersion {
+		str := fmt.Sprintf("unknown version %d", version)
+		return makeError(ErrMalformedPeersFile, str)
+	}
+	if err := r.readBytes(a.key[:]); err != nil {
+		return err
+	}
+
+	// Restore the addresses of each network partition along with their
+	// bucket assignments while ensuring the assignments are possible.
+	maxAddrs := newBucketCount*newBucketSize + triedBucketCount*a.triedBucketSize
+	numPartitions, err := r.readUint8()
+	if err != nil {
+		return err
+	}
+	var numAddrs int
+	var prevNetType NetAddressType
+	for i := uint8(0); i < numPartitions; i++ {
+		netTypeByte, err := r.readUint8()
+		if err != nil {
+			return err
+		}
+		netType := NetAddressType(netTypeByte)
+		if i > 0 && netType <= prevNetType {
+			str := fmt.Sprintf("network partition %d is not in ascending "+
+				"order", netType)
+			return makeError(ErrMalformedPeersFile, str)
+		}
+		prevNetType = netType
+
+		partitionLen, err := r.readUint32()
+		if err != nil {
+			return err
+		}
+		if uint64(numAddrs)+uint64(partitionLen) > uint64(maxAddrs) {
+			str := fmt.Sprintf("number of addresses exceeds the maximum %d",
+				maxAddrs)
+			return makeError(ErrMalformedPeersFile, str)
+		}
+		numAddrs += int(partitionLen)
+
+		for j := uint32(0); j < partitionLen; j++ {
+			ka, triedBucket, newBuckets, err := r.readKnownAddress(netType)
+			if err != nil {
+				return err
+			}
+			key := ka.na.Key()
+			if _, ok := a.addrIndex[key]; ok {
+				str := fmt.Sprintf("duplicate address %s", key)
+				return makeError(ErrMalformedPeersFile, str)
+			}
+
+			if ka.tried {
+				if triedBucket >= triedBucketCount {
+					str := fmt.Sprintf("tried bucket %d for address %s is "+
+						"out of range", triedBucket, key)
+					return makeError(ErrMalformedPeersFile, str)
+				}
+				if len(a.addrTried[triedBucket]) >= a.triedBucketSize {
+					str := fmt.Sprintf("tried bucket %d exceeds the maximum "+
+						"size", triedBucket)
+					return makeError(ErrMalformedPeersFile, str)
+				}
+				a.addrTried[triedBucket] = append(a.addrTried[triedBucket], ka)
+				a.nTried++
+				a.addrIndex[key] = ka
+				continue
+			}
+
+			for _, bucket := range newBuckets {
+				if bucket >= newBucketCount {
+					str := fmt.Sprintf("new bucket %d for address %s is out "+
+						"of range", bucket, key)
+					return makeError(ErrMalformedPeersFile, str)
+				}
+				if _, ok := a.addrNew[bucket][key]; ok {
+					str := fmt.Sprintf("duplicate new bucket %d for address "+
+						"%s", bucket, key)
+					return makeError(ErrMalformedPeersFile, str)
+				}
+				if len(a.addrNew[bucket]) >= newBucketSize {
+					str := fmt.Sprintf("new bucket %d exceeds the maximum "+
+						"size", bucket)
+					return makeError(ErrMalformedPeersFile, str)
+				}
+				a.addrNew[bucket][key] = ka
+				ka.refs++
+			}
+			a.nNew++
+			a.addrIndex[key] = ka
+		}
+	}
+	if r.r.Len() != 0 {
+		str := fmt.Sprintf("%d unexpected trailing bytes", r.r.Len())
+		return makeError(ErrMalformedPeersFile, str)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package addrmgr
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzDeserializePeers ensures deserializing arbitrary data never panics and
+// that any data that is successfully deserialized results in a state that
+// round trips.
+func FuzzDeserializePeers(f *testing.F) {
+	f.Add(newTestSerializeAddrManager(f).serializePeers())
+	f.Add(New(f.TempDir(), nil).serializePeers())
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		amgr := New(t.TempDir(), nil)
+		if err := amgr.deserializePeers(data); err != nil {
+			return
+		}
+
+		// Ensure the state round trips.  Note that the original data is not
+		// necessarily reproduced since it is not required to be ordered.
+		serialized := amgr.serializePeers()
+		restored := New(t.TempDir(), nil)
+		if err := restored.deserializePeers(serialized); err != nil {
+			t.Fatalf("failed to deserialize reserialized state: %v", err)
+		}
+		assertSameState(t, restored, amgr)
+		if !bytes.Equal(restored.serializePeers(), serialized) {
+			t.Fatal("serialization is not deterministic")
+		}
+	})
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// newTestSerializeAddrManager returns an address manager populated with new
+// and tried addresses from all supported networks for use in the serialization
+// tests.
+func newTestSerializeAddrManager(t testing.TB) *AddrManager {
+	t.Helper()
+
+	amgr := New(t.TempDir(), nil)
+	timestamp := time.Unix(1700000000, 0)
+	srcAddr := NewNetAddressIPPort(net.ParseIP("173.194.115.66"), 9108,
+		wire.SFNodeNetwork)
+	torV3Addr, err := NewNetAddressFromParams(TORv3Address, testTORv3Key(),
+		9108, timestamp, wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	i2pAddr, err := NewNetAddressFromParams(I2PAddress, testI2PHash(), 0,
+		timestamp, wire.SFNodeNetwork|wire.SFNodeCF)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addrs := []*NetAddress{
+		NewNetAddressIPPort(net.ParseIP("8.8.8.8"), 9108,
+			wire.SFNodeNetwork),
+		NewNetAddressIPPort(net.ParseIP("8.8.4.4"), 19108,
+			wire.SFNodeNetwork|wire.SFNodeCF),
+		NewNetAddressIPPort(net.ParseIP("2001:4860::8888"), 9108,
+			wire.SFNodeNetwork),
+		torV3Addr,
+		i2pAddr,
+	}
+	for _, addr := range addrs {
+		addr.Timestamp = timestamp
+	}
+	amgr.AddAddresses(addrs, srcAddr)
+
+	// Mark some of the addresses as attempted and good so the state includes
+	// connection history and tried addresses.
+	if err := amgr.Attempt(addrs[1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, addr := range addrs[2:4] {
+		if err := amgr.Attempt(addr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := amgr.Good(addr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return amgr
+}
+
+// bucketKeys returns the sorted keys of the provided known addresses.
+func bucketKeys(addrs []*KnownAddress) []string {
+	keys := make([]string, 0, len(addrs))
+	for _, ka := range addrs {
+		keys = append(keys, ka.na.Key())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// assertSameState ensures the state of the provided address managers is the
+// same aside from the sub-second precision of the times which is not
+// serialized.
+func assertSameState(t *testing.T, got, want *AddrManager) {
+	t.Helper()
+
+	if got.key != want.key {
+		t.Fatalf("mismatched key - got %x, want %x", got.key, want.key)
+	}
+	if got.nNew != want.nNew || got.nTried != want.nTried {
+		t.Fatalf("mismatched counts - got %d new and %d tried, want %d new "+
+			"and %d tried", got.nNew, got.nTried, want.nNew, want.nTried)
+	}
+	if len(got.addrIndex) != len(want.addrIndex) {
+		t.Fatalf("mismatched number of addresses - got %d, want %d",
+			len(got.addrIndex), len(want.addrIndex))
+	}
+	for key, wantKA := range want.addrIndex {
+		gotKA, ok := got.addrIndex[key]
+		if !ok {
+			t.Fatalf("missing address %s", key)
+		}
+		gotNA, wantNA := gotKA.na, wantKA.na
+		if gotNA.Type != wantNA.Type || !bytes.Equal(gotNA.IP, wantNA.IP) ||
+			gotNA.Port != wantNA.Port || gotNA.Services != wantNA.Services ||
+			gotNA.Timestamp.Unix() != wantNA.Timestamp.Unix() {
+
+			t.Fatalf("mismatched network address %s - got %+v, want %+v", key,
+				gotNA, wantNA)
+		}
+		if gotKA.srcAddr.Key() != wantKA.srcAddr.Key() {
+			t.Fatalf("mismatched source address for %s - got %s, want %s",
+				key, gotKA.srcAddr, wantKA.srcAddr)
+		}
+		if gotKA.attempts != wantKA.attempts ||
+			gotKA.lastattempt.Unix() != wantKA.lastattempt.Unix() ||
+			gotKA.lastsuccess.Unix() != wantKA.lastsuccess.Unix() {
+
+			t.Fatalf("mismatched connection history for %s", key)
+		}
+		if gotKA.tried != wantKA.tried || gotKA.refs != wantKA.refs {
+			t.Fatalf("mismatched bucket state for %s - got tried %v refs %d, "+
+				"want tried %v refs %d", key, gotKA.tried, gotKA.refs,
+				wantKA.tried, wantKA.refs)
+		}
+	}
+	for i := range want.addrNew {
+		gotKeys := make([]string, 0, len(got.addrNew[i]))
+		for key := range got.addrNew[i] {
+			gotKeys = append(gotKeys, key)
+		}
+		wantKeys := make([]string, 0, len(want.addrNew[i]))
+		for key := range want.addrNew[i] {
+			wantKeys = append(wantKeys, key)
+		}
+		sort.Strings(gotKeys)
+		sort.Strings(wantKeys)
+		if !reflect.DeepEqual(gotKeys, wantKeys) {
+			t.Fatalf("mismatched new bucket %d - got %v, want %v", i,
+				gotKeys, wantKeys)
+		}
+	}
+	for i := range want.addrTried {
+		gotKeys := bucketKeys(got.addrTried[i])
+		wantKeys := bucketKeys(want.addrTried[i])
+		if !reflect.DeepEqual(gotKeys, wantKeys) {
+			t.Fatalf("mismatched tried bucket %d - got %v, want %v", i,
+				gotKeys, wantKeys)
+		}
+	}
+}
+
+// TestSerializePeers ensures the serialized address manager state round trips
+// and that serializing the same state always produces the same bytes.
+func TestSerializePeers(t *testing.T) {
+	amgr := newTestSerializeAddrManager(t)
+	if amgr.nTried != 2 || amgr.nNew != 3 {
+		t.Fatalf("unexpected test state - got %d tried and %d new, want 2 "+
+			"tried and 3 new", amgr.nTried, amgr.nNew)
+	}
+	serialized := amgr.serializePeers()
+
+	restored := New(t.TempDir(), nil)
+	if err := restored.deserializePeers(serialized); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSameState(t, restored, amgr)
+
+	// Ensure serializing the restored state produces the same bytes.
+	if reserialized := restored.serializePeers(); !bytes.Equal(reserialized,
+		serialized) {
+
+		t.Fatalf("serialization is not deterministic:\ngot  %x\nwant %x",
+			reserialized, serialized)
+	}
+
+	// Ensure an empty address manager round trips.
+	empty := New(t.TempDir(), nil)
+	restored = New(t.TempDir(), nil)
+	if err := restored.deserializePeers(empty.serializePeers()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSameState(t, restored, empty)
+}
+
+// TestDeserializePeersErrors ensures deserializing malformed address manager
+// state is rejected with ErrMalformedPeersFile.
+func TestDeserializePeersErrors(t *testing.T) {
+	serialized := newTestSerializeAddrManager(t).serializePeers()
+
+	// withChecksum returns the provided serialized state with its checksum
+	// recalculated so the malformed data is detected by the parsing.
+	withChecksum := func(b []byte) []byte {
+		checksumOffset := len(b) - peersChecksumSize
+		checksum := chainhash.HashB(b[:checksumOffset])
+		copy(b[checksumOffset:], checksum[:peersChecksumSize])
+		return b
+	}
+
+	// modified returns a copy of the serialized state modified by the
+	// provided function with its checksum recalculated.
+	modified := func(modify func(b []byte) []byte) []byte {
+		b := append([]byte(nil), serialized...)
+		return withChecksum(modify(b))
+	}
+
+	// The offset of the first partition and the offset of the tried flag of
+	// the first address in it, which is an IPv4 address that is not tried.
+	const partitionsOffset = 4 + 4 + 32 + 1
+	const firstAddrOffset = partitionsOffset + 1 + 4
+	const firstTriedOffset = firstAddrOffset + 1 + 16 + 2 + 8 + 8 + 1 + 1 +
+		16 + 2 + 4 + 8 + 8
+
+	tests := []struct {
+		name string
+		data []byte
+	}{{
+		name: "empty",
+		data: nil,
+	}, {
+		name: "truncated",
+		data: withChecksum(append([]byte(nil), serialized[:len(serialized)/2]...)),
+	}, {
+		name: "checksum mismatch",
+		data: func() []byte {
+			b := append([]byte(nil), serialized...)
+			b[len(b)-1] ^= 0xff
+			return b
+		}(),
+	}, {
+		name: "invalid magic",
+		data: modified(func(b []byte) []byte {
+			b[0] = 'x'
+			return b
+		}),
+	}, {
+		name: "unknown version",
+		data: modified(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[4:8], serialisationVersion+1)
+			return b
+		}),
+	}, {
+		name: "duplicate partition",
+		data: modified(func(b []byte) []byte {
+			// The IPv4 addresses in the first partition are also valid IPv6
+			// addresses, so this results in two IPv6 partitions.
+			b[partitionsOffset] = uint8(IPv6Address)
+			return b
+		}),
+	}, {
+		name: "address mismatched with partition network",
+		data: modified(func(b []byte) []byte {
+			b[partitionsOffset] = uint8(TORv3Address)
+			return b
+		}),
+	}, {
+		name: "too many addresses",
+		data: modified(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[partitionsOffset+1:], 1<<31)
+			return b
+		}),
+	}, {
+		name: "address length exceeds maximum",
+		data: modified(func(b []byte) []byte {
+			b[firstAddrOffset] = maxSerializedAddrLen + 1
+			return b
+		}),
+	}, {
+		name: "invalid tried flag",
+		data: modified(func(b []byte) []byte {
+			b[firstTriedOffset] = 2
+			return b
+		}),
+	}, {
+		name: "no new buckets",
+		data: modified(func(b []byte) []byte {
+			b[firstTriedOffset+1] = 0
+			return b
+		}),
+	}, {
+		name: "too many new buckets",
+		data: modified(func(b []byte) []byte {
+			b[firstTriedOffset+1] = newBucketsPerAddress + 1
+			return b
+		}),
+	}, {
+		name: "new bucket out of range",
+		data: modified(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[firstTriedOffset+2:],
+				newBucketCount)
+			return b
+		}),
+	}, {
+		name: "trailing bytes",
+		data: modified(func(b []byte) []byte {
+			return append(b[:len(b)-peersChecksumSize], 0, 0, 0, 0, 0)
+		}),
+	}}
+	for _, test := range tests {
+		amgr := New(t.TempDir(), nil)
+		err := amgr.deserializePeers(test.data)
+		if !errors.Is(err, ErrMalformedPeersFile) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name, err,
+				ErrMalformedPeersFile)
+		}
+	}
+}
+
+// TestMigrateLegacyPeers ensures the known addresses in a legacy JSON peers
+// file are loaded and migrated to the current format.
+func TestMigrateLegacyPeers(t *testing.T) {
+	dir := t.TempDir()
+	const addr = "173.194.115.66:8333"
+	var legacy serializedAddrManager
+	legacy.Version = legacySerialisationVersion
+	legacy.Addresses = []*serializedKnownAddress{{
+		Addr:      addr,
+		Src:       addr,
+		TimeStamp: time.Now().Unix(),
+	}}
+	legacy.NewBuckets[0] = []string{addr}
+	serialized, err := json.Marshal(&legacy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	legacyPeersFile := filepath.Join(dir, legacyPeersFilename)
+	if err := os.WriteFile(legacyPeersFile, serialized, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	amgr := New(dir, nil)
+	amgr.Start()
+	if knownAddress := amgr.GetAddress(); knownAddress == nil ||
+		knownAddress.na.Key() != addr {
+
+		t.Fatalf("address manager does not contain legacy address %s", addr)
+	}
+	if err := amgr.Stop(); err != nil {
+		t.Fatalf("address manager failed to stop: %v", err)
+	}
+
+	// Ensure the legacy peers file was replaced by the current format.
+	if _, err := os.Stat(legacyPeersFile); !os.IsNotExist(err) {
+		t.Fatalf("legacy peers file was not removed: %v", err)
+	}
+	amgr = New(dir, nil)
+	amgr.Start()
+	if knownAddress := amgr.GetAddress(); knownAddress == nil ||
+		knownAddress.na.Key() != addr {
+
+		t.Fatalf("address manager does not contain migrated address %s", addr)
+	}
+	if err := amgr.Stop(); err != nil {
+		t.Fatalf("address manager failed to stop: %v", err)
+	}
+}
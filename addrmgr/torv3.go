@@ -0,0 +1,92 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// torV3KeySize is the size of the ed25519 public key that identifies a
+	// TORv3 onion service.  TORv3 network addresses store the public key as
+	// their IP.
+	torV3KeySize = 32
+
+	// torV3VersionByte is the version byte that is encoded into TORv3 onion
+	// addresses.
+	torV3VersionByte = 0x03
+
+	// torV3DecodedLen is the length of a decoded TORv3 onion address which
+	// consists of the public key, a 2-byte checksum, and the version byte.
+	torV3DecodedLen = torV3KeySize + 2 + 1
+
+	// torV3HostLen is the length of a TORv3 onion address host which is the
+	// base32 encoding of the decoded address followed by ".onion".
+	torV3HostLen = 56 + len(".onion")
+
+	// torV3ChecksumTag is the tag that is prepended to the public key and
+	// version when calculating the checksum of a TORv3 onion address.
+	torV3ChecksumTag = ".onion checksum"
+)
+
+// calcTORv3Checksum returns the checksum of a TORv3 onion address for the
+// provided public key as defined by the Tor rendezvous specification.
+func calcTORv3Checksum(pubKey []byte) [2]byte {
+	h := sha3.New256()
+	h.Write([]byte(torV3ChecksumTag))
+	h.Write(pubKey)
+	h.Write([]byte{torV3VersionByte})
+	var checksum [2]byte
+	copy(checksum[:], h.Sum(nil))
+	return checksum
+}
+
+// encodeTORv3Host returns the TORv3 onion address host, including the ".onion"
+// suffix, for the provided public key.
+func encodeTORv3Host(pubKey []byte) string {
+	var decoded [torV3DecodedLen]byte
+	copy(decoded[:], pubKey)
+	checksum := calcTORv3Checksum(pubKey)
+	copy(decoded[torV3KeySize:], checksum[:])
+	decoded[torV3DecodedLen-1] = torV3VersionByte
+	encoded := base32.StdEncoding.EncodeToString(decoded[:])
+	return strings.ToLower(encoded) + ".onion"
+}
+
+// decodeTORv3Host returns the public key encoded in the provided TORv3 onion
+// address host, which must include the ".onion" suffix.
+//
+// ErrInvalidTORv3Address is returned when the host is not a valid TORv3 onion
+// address.
+func decodeTORv3Host(host string) ([]byte, error) {
+	// Go base32 encoding uses capitals (as does the rfc), but Tor uses
+	// lowercase, so switch case here.
+	encoded := strings.ToUpper(strings.TrimSuffix(host, ".onion"))
+	decoded, err := base32.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) != torV3DecodedLen {
+		str := fmt.Sprintf("%s is not a valid base32 encoded TORv3 address",
+			host)
+		return nil, makeError(ErrInvalidTORv3Address, str)
+	}
+
+	pubKey := decoded[:torV3KeySize]
+	if version := decoded[torV3DecodedLen-1]; version != torV3VersionByte {
+		str := fmt.Sprintf("TORv3 address %s has unsupported version %d",
+			host, version)
+		return nil, makeError(ErrInvalidTORv3Address, str)
+	}
+	checksum := calcTORv3Checksum(pubKey)
+	if decoded[torV3KeySize] != checksum[0] ||
+		decoded[torV3KeySize+1] != checksum[1] {
+
+		str := fmt.Sprintf("TORv3 address %s has an invalid checksum", host)
+		return nil, makeError(ErrInvalidTORv3Address, str)
+	}
+	return pubKey, nil
+}
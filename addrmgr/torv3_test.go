@@ -0,0 +1,126 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// testTORv3Host is a valid TORv3 onion address host used in the tests.
+const testTORv3Host = "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion"
+
+// testTORv3Key returns the public key encoded in testTORv3Host.
+func testTORv3Key() net.IP {
+	pubKey, err := hex.DecodeString("d1b38b83a83b3ed918c5bb69dd444ad56bc8" +
+		"d5835a914de73447474e5f02591b")
+	if err != nil {
+		panic(err)
+	}
+	return net.IP(pubKey)
+}
+
+// TestTORv3Host ensures TORv3 onion address hosts are encoded and decoded as
+// expected and that invalid hosts are rejected.
+func TestTORv3Host(t *testing.T) {
+	// Ensure the public key round trips through the host encoding.
+	pubKey := testTORv3Key()
+	if host := encodeTORv3Host(pubKey); host != testTORv3Host {
+		t.Fatalf("unexpected host - got %s, want %s", host, testTORv3Host)
+	}
+	decoded, err := decodeTORv3Host(testTORv3Host)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pubKey.Equal(decoded) {
+		t.Fatalf("unexpected public key - got %x, want %x", decoded, pubKey)
+	}
+
+	tests := []struct {
+		name string
+		host string
+	}{{
+		name: "invalid base32",
+		host: "1gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion",
+	}, {
+		name: "invalid checksum",
+		host: "3gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wid.onion",
+	}, {
+		name: "invalid version",
+		host: "2gzyxa5ihm7nsggfxnu52rck2vv4rvmdlkiu3zzui5du4xyclen53wia.onion",
+	}}
+	for _, test := range tests {
+		_, err := decodeTORv3Host(test.host)
+		if !errors.Is(err, ErrInvalidTORv3Address) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name,
+				err, ErrInvalidTORv3Address)
+		}
+	}
+}
+
+// TestTORv3Address ensures TORv3 network addresses are identified, keyed,
+// grouped, and considered reachable as expected.
+func TestTORv3Address(t *testing.T) {
+	netAddr, err := NewNetAddressFromParams(TORv3Address, testTORv3Key(), 9108,
+		time.Now(), wire.SFNodeNetwork)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := netAddr.Type; got != TORv3Address {
+		t.Fatalf("unexpected address type - got %v, want %v", got,
+			TORv3Address)
+	}
+	if !netAddr.IsRoutable() {
+		t.Fatal("torv3 address is not routable")
+	}
+	wantKey := testTORv3Host + ":9108"
+	if key := netAddr.Key(); key != wantKey {
+		t.Fatalf("unexpected key - got %s, want %s", key, wantKey)
+	}
+
+	// The group key is keyed off the first 4 bits of the public key (0xd1).
+	if key := netAddr.GroupKey(); key != "torv3:1" {
+		t.Fatalf("unexpected group key - got %s, want %s", key, "torv3:1")
+	}
+
+	// Ensure the address is parsed from its key.
+	addrManager := New("testTORv3Address", nil)
+	parsed, err := addrManager.newAddressFromString(wantKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.Key() != wantKey || parsed.Type != TORv3Address {
+		t.Fatalf("unexpected parsed address %v", parsed)
+	}
+
+	// Ensure the reachability of onion addresses from one another is private
+	// while the reachability from a routable IPv4 address is IPv4.
+	torV2Addr := NewNetAddressIPPort(onionCatNet.IP, 9108, wire.SFNodeNetwork)
+	ipv4Addr := NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108,
+		wire.SFNodeNetwork)
+	tests := []struct {
+		name   string
+		local  *NetAddress
+		remote *NetAddress
+		want   NetAddressReach
+	}{
+		{"torv3 to torv3", netAddr, netAddr, Private},
+		{"torv2 to torv3", torV2Addr, netAddr, Private},
+		{"torv3 to torv2", netAddr, torV2Addr, Private},
+		{"routable ipv4 to torv3", ipv4Addr, netAddr, Ipv4},
+	}
+	for _, test := range tests {
+		reach := getReachabilityFrom(test.local, test.remote)
+		if reach != test.want {
+			t.Errorf("%q: unexpected reachability - got %v, want %v",
+				test.name, reach, test.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@
 package bech32
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -17,6 +18,40 @@ const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
 // gen encodes the generator polynomial for the bech32 BCH checksum.
 var gen = []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
 
+// Version identifies the variant of the checksum used by a bech32 string.
+type Version uint8
+
+const (
+	// Version0 is the original bech32 checksum variant specified in BIP 173.
+	Version0 Version = iota
+
+	// VersionM is the modified bech32 checksum variant, commonly referred to
+	// as bech32m, specified in BIP 350.  It addresses a weakness of the
+	// original variant where inserting or deleting 'q' characters right
+	// before a final 'p' character does not invalidate the checksum.
+	VersionM
+)
+
+// checksumConst returns the constant the polymod of a string with a valid
+// checksum of the checksum variant evaluates to.
+func (v Version) checksumConst() int {
+	if v == VersionM {
+		return 0x2bc830a3
+	}
+	return 1
+}
+
+// String returns the human-readable name of the checksum variant.
+func (v Version) String() string {
+	switch v {
+	case Version0:
+		return "bech32"
+	case VersionM:
+		return "bech32m"
+	}
+	return "unknown bech32 version " + strconv.Itoa(int(v))
+}
+
 // toBytes converts each character in the string 'chars' to the value of the
 // index of the corresponding character in 'charset'.
 func toBytes(chars string) ([]byte, error) {
@@ -115,16 +150,17 @@ func bech32Polymod(hrp string, values, checksum []byte) int {
 }
 
 // writeBech32Checksum calculates the checksum data expected for a string that
-// will have the given hrp and payload data and writes it to the provided string
-// builder.
+// will have the given hrp and payload data using the given checksum variant and
+// writes it to the provided string builder.
 //
 // The payload data MUST be encoded as a base 32 (5 bits per element) byte slice
 // and the hrp MUST only use the allowed character set (ascii chars between 33
 // and 126), otherwise the results are undefined.
 //
-// For more details on the checksum calculation, please refer to BIP 173.
-func writeBech32Checksum(hrp string, data []byte, bldr *strings.Builder) {
-	polymod := bech32Polymod(hrp, data, nil) ^ 1
+// For more details on the checksum calculation, please refer to BIP 173 and
+// BIP 350.
+func writeBech32Checksum(hrp string, data []byte, version Version, bldr *strings.Builder) {
+	polymod := bech32Polymod(hrp, data, nil) ^ version.checksumConst()
 	for i := 0; i < 6; i++ {
 		b := byte((polymod >> uint(5*(5-i))) & 31)
 
@@ -137,38 +173,28 @@ func writeBech32Checksum(hrp string, data []byte, bldr *strings.Builder) {
 
 // bech32VerifyChecksum verifies whether the bech32 string specified by the
 // provided hrp and payload data (encoded as 5 bits per element byte slice) has
-// the correct checksum suffix.
+// the correct checksum suffix for the given checksum variant.
 //
 // Data MUST have more than 6 elements, otherwise this function panics.
 //
-// For more details on the checksum verification, please refer to BIP 173.
-func bech32VerifyChecksum(hrp string, data []byte) bool {
+// For more details on the checksum verification, please refer to BIP 173 and
+// BIP 350.
+func bech32VerifyChecksum(hrp string, data []byte, version Version) bool {
 	checksum := data[len(data)-6:]
 	values := data[:len(data)-6]
 	polymod := bech32Polymod(hrp, values, checksum)
-	return polymod == 1
+	return polymod == version.checksumConst()
 }
 
-// DecodeNoLimit decodes a bech32 encoded string, returning the human-readable
-// part and the data part excluding the checksum.  This function does NOT
-// validate against the BIP-173 maximum length allowed for bech32 strings and
-// is meant for use in custom applications (such as lightning network payment
-// requests), NOT on-chain addresses.
-//
-// Note that the returned data is 5-bit (base32) encoded and the human-readable
-// part will be lowercase.
-func DecodeNoLimit(bech string) (string, []byte, error) {
-	// The minimum allowed size of a bech32 string is 8 characters, since it
-	// needs a non-empty HRP, a separator, and a 6 character checksum.
-	if len(bech) < 8 {
-		return "", nil, ErrInvalidLength(len(bech))
-	}
-
+// normalize ensures the provided string only consists of characters allowed in
+// bech32 strings that are either all lowercase or all uppercase and returns
+// its lowercase form.
+func normalize(bech string) (string, error) {
 	// Only ASCII characters between 33 and 126 are allowed.
 	var hasLower, hasUpper bool
 	for i := 0; i < len(bech); i++ {
 		if bech[i] < 33 || bech[i] > 126 {
-			return "", nil, ErrInvalidCharacter(bech[i])
+			return "", ErrInvalidCharacter(bech[i])
 		}
 
 		// The characters must be either all lowercase or all uppercase. Testing
@@ -176,7 +202,7 @@ func DecodeNoLimit(bech string) (string, []byte, error) {
 		hasLower = hasLower || (bech[i] >= 97 && bech[i] <= 122)
 		hasUpper = hasUpper || (bech[i] >= 65 && bech[i] <= 90)
 		if hasLower && hasUpper {
-			return "", nil, ErrMixedCase{}
+			return "", ErrMixedCase{}
 		}
 	}
 
@@ -185,6 +211,24 @@ func DecodeNoLimit(bech string) (string, []byte, error) {
 	if hasUpper {
 		bech = strings.ToLower(bech)
 	}
+	return bech, nil
+}
+
+// decodeNoLimit decodes a bech32 encoded string with the given checksum
+// variant, returning the human-readable part and the data part excluding the
+// checksum.  This function does NOT validate against the maximum length allowed
+// for bech32 strings.
+func decodeNoLimit(bech string, version Version) (string, []byte, error) {
+	// The minimum allowed size of a bech32 string is 8 characters, since it
+	// needs a non-empty HRP, a separator, and a 6 character checksum.
+	if len(bech) < 8 {
+		return "", nil, ErrInvalidLength(len(bech))
+	}
+
+	bech, err := normalize(bech)
+	if err != nil {
+		return "", nil, err
+	}
 
 	// The string is invalid if the last '1' is non-existent, it is the
 	// first character of the string (no human-readable part) or one of the
@@ -207,7 +251,7 @@ func DecodeNoLimit(bech string) (string, []byte, error) {
 
 	// Verify if the checksum (stored inside decoded[:]) is valid, given the
 	// previously decoded hrp.
-	if !bech32VerifyChecksum(hrp, decoded) {
+	if !bech32VerifyChecksum(hrp, decoded, version) {
 		// Invalid checksum. Calculate what it should have been, so that the
 		// error contains this information.
 
@@ -218,7 +262,7 @@ func DecodeNoLimit(bech string) (string, []byte, error) {
 		// Calculate the expected checksum, given the hrp and payload data.
 		var expectedBldr strings.Builder
 		expectedBldr.Grow(6)
-		writeBech32Checksum(hrp, payload, &expectedBldr)
+		writeBech32Checksum(hrp, payload, version, &expectedBldr)
 		expected := expectedBldr.String()
 
 		err = ErrInvalidChecksum{
@@ -232,6 +276,18 @@ func DecodeNoLimit(bech string) (string, []byte, error) {
 	return hrp, decoded[:len(decoded)-6], nil
 }
 
+// DecodeNoLimit decodes a bech32 encoded string, returning the human-readable
+// part and the data part excluding the checksum.  This function does NOT
+// validate against the BIP-173 maximum length allowed for bech32 strings and
+// is meant for use in custom applications (such as lightning network payment
+// requests), NOT on-chain addresses.
+//
+// Note that the returned data is 5-bit (base32) encoded and the human-readable
+// part will be lowercase.
+func DecodeNoLimit(bech string) (string, []byte, error) {
+	return decodeNoLimit(bech, Version0)
+}
+
 // Decode decodes a bech32 encoded string, returning the human-readable part and
 // the data part excluding the checksum.
 //
@@ -243,14 +299,27 @@ func Decode(bech string) (string, []byte, error) {
 		return "", nil, ErrInvalidLength(len(bech))
 	}
 
-	return DecodeNoLimit(bech)
+	return decodeNoLimit(bech, Version0)
 }
 
-// Encode encodes a byte slice into a bech32 string with the given
-// human-readable part (HRP).  The HRP will be converted to lowercase if needed
-// since mixed cased encodings are not permitted and lowercase is used for
-// checksum purposes.  Note that the bytes must each encode 5 bits (base32).
-func Encode(hrp string, data []byte) (string, error) {
+// DecodeM decodes a bech32m encoded string as specified in BIP 350, returning
+// the human-readable part and the data part excluding the checksum.  It is the
+// same as Decode except the string must have a bech32m checksum.
+//
+// Note that the returned data is 5-bit (base32) encoded and the human-readable
+// part will be lowercase.
+func DecodeM(bech string) (string, []byte, error) {
+	// The maximum allowed length for a bech32m string is 90.
+	if len(bech) > 90 {
+		return "", nil, ErrInvalidLength(len(bech))
+	}
+
+	return decodeNoLimit(bech, VersionM)
+}
+
+// encode encodes a byte slice into a bech32 string with the given
+// human-readable part (HRP) and checksum variant.
+func encode(hrp string, data []byte, version Version) (string, error) {
 	// The resulting bech32 string is the concatenation of the lowercase hrp,
 	// the separator 1, data and the 6-byte checksum.
 	hrp = strings.ToLower(hrp)
@@ -268,11 +337,89 @@ func Encode(hrp string, data []byte) (string, error) {
 	}
 
 	// Calculate and write the checksum of the data.
-	writeBech32Checksum(hrp, data, &bldr)
+	writeBech32Checksum(hrp, data, version, &bldr)
 
 	return bldr.String(), nil
 }
 
+// Encode encodes a byte slice into a bech32 string with the given
+// human-readable part (HRP).  The HRP will be converted to lowercase if needed
+// since mixed cased encodings are not permitted and lowercase is used for
+// checksum purposes.  Note that the bytes must each encode 5 bits (base32).
+func Encode(hrp string, data []byte) (string, error) {
+	return encode(hrp, data, Version0)
+}
+
+// EncodeM encodes a byte slice into a bech32m string as specified in BIP 350
+// with the given human-readable part (HRP).  It is the same as Encode except
+// the string has a bech32m checksum.  Note that the bytes must each encode 5
+// bits (base32).
+func EncodeM(hrp string, data []byte) (string, error) {
+	return encode(hrp, data, VersionM)
+}
+
+// LocateErrors returns the indices into the provided bech32 string of the
+// characters that are likely to be typos given the provided checksum variant.
+// It is intended to help users correct mistyped strings, such as addresses,
+// by highlighting the offending characters.
+//
+// Characters of the data part that are not part of the bech32 charset are
+// always reported.  Otherwise, when the checksum is invalid, the index of the
+// single character of the data part that, once substituted, results in a valid
+// checksum is reported.  The checksum is guaranteed to detect any combination
+// of up to four substitutions in strings of the maximum allowed length, so at
+// most one such character exists.
+//
+// No indices are returned when the string is valid or when the errors can't be
+// located, such as when more than one character was mistyped or characters
+// were inserted or deleted.  Thus, the lack of indices must NOT be treated as
+// an indication the string is valid.
+//
+// NOTE: The returned indices are only hints.  The corrected string must NOT be
+// used automatically since doing so defeats the purpose of the checksum.
+func LocateErrors(bech string, version Version) []int {
+	bech, err := normalize(bech)
+	if err != nil {
+		return nil
+	}
+	one := strings.LastIndexByte(bech, '1')
+	if one < 1 || one+7 > len(bech) || len(bech) > 90 {
+		return nil
+	}
+	hrp := bech[:one]
+	data := bech[one+1:]
+
+	// Report any characters that are not part of the charset.
+	var invalid []int
+	for i := 0; i < len(data); i++ {
+		if strings.IndexByte(charset, data[i]) < 0 {
+			invalid = append(invalid, one+1+i)
+		}
+	}
+	if len(invalid) > 0 {
+		return invalid
+	}
+	decoded, _ := toBytes(data)
+	if bech32VerifyChecksum(hrp, decoded, version) {
+		return nil
+	}
+
+	// Attempt to find a single substitution that results in a valid checksum.
+	for i, orig := range decoded {
+		for v := byte(0); v < byte(len(charset)); v++ {
+			if v == orig {
+				continue
+			}
+			decoded[i] = v
+			if bech32VerifyChecksum(hrp, decoded, version) {
+				return []int{one + 1 + i}
+			}
+		}
+		decoded[i] = orig
+	}
+	return nil
+}
+
 // ConvertBits converts a byte slice where each byte is encoding fromBits bits,
 // to a byte slice where each byte is encoding toBits bits.
 func ConvertBits(data []byte, fromBits, toBits uint8, pad bool) ([]byte, error) {
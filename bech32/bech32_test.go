@@ -87,6 +87,141 @@ func TestBech32(t *testing.T) {
 	}
 }
 
+// TestBech32M tests whether decoding and re-encoding the valid BIP-350 test
+// vectors works, that decoding invalid test vectors fails for the correct
+// reason, and that the checksum variants are not interchangeable.
+func TestBech32M(t *testing.T) {
+	tests := []struct {
+		str           string
+		expectedError error
+	}{
+		{"A1LQFN3A", nil},
+		{"a1lqfn3a", nil},
+		{"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6", nil},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", nil},
+		{"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8", nil},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", nil},
+		{"?1v759aa", nil},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", ErrInvalidChecksum{"lc445v", "2y9e3w"}}, // bech32 checksum
+		{"\x20" + "1xj0phk", ErrInvalidCharacter(0x20)},
+		{"an84characterslonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11d6pts4", ErrInvalidLength(91)},
+		{"qyrz8wqd2c9m", ErrInvalidSeparatorIndex(-1)},
+		{"1qyrz8wqd2c9m", ErrInvalidSeparatorIndex(0)},
+		{"y1b0jsk6g", ErrNonCharsetChar('b')},
+		{"lt1igcx5c0", ErrNonCharsetChar('i')},
+		{"in1muywd", ErrInvalidSeparatorIndex(2)},
+		{"mm1crxm3i", ErrNonCharsetChar('i')},
+		{"au1s5cgom", ErrNonCharsetChar('o')},
+		{"M1VUXWEZ", ErrInvalidChecksum{"w70eq6", "vuxwez"}},
+		{"16plkw9", ErrInvalidLength(7)},
+		{"1p2gdwpf", ErrInvalidSeparatorIndex(0)},
+	}
+
+	for i, test := range tests {
+		str := test.str
+		hrp, decoded, err := DecodeM(str)
+		if !errors.Is(err, test.expectedError) {
+			t.Errorf("%d: expected decoding error %v instead got %v", i,
+				test.expectedError, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		// Check that it encodes to the same string.
+		encoded, err := EncodeM(hrp, decoded)
+		if err != nil {
+			t.Errorf("%d: encoding failed: %v", i, err)
+			continue
+		}
+		if encoded != strings.ToLower(str) {
+			t.Errorf("%d: expected data to encode to %v, but got %v", i, str,
+				encoded)
+		}
+
+		// Ensure the string is not valid bech32.
+		if _, _, err := Decode(str); err == nil {
+			t.Errorf("%d: expected bech32 decoding to fail", i)
+		}
+	}
+}
+
+// TestLocateErrors ensures mistyped characters of bech32 and bech32m strings
+// are located as expected.
+func TestLocateErrors(t *testing.T) {
+	const (
+		valid  = "split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w"
+		validM = "split1checkupstagehandshakeupstreamerranterredcaperredlc445v"
+	)
+
+	// substitute returns the provided string with the character at the
+	// provided index replaced by the provided character.
+	substitute := func(s string, idx int, c byte) string {
+		return s[:idx] + string(c) + s[idx+1:]
+	}
+
+	tests := []struct {
+		name    string
+		str     string
+		version Version
+		want    []int
+	}{{
+		name:    "valid bech32",
+		str:     valid,
+		version: Version0,
+		want:    nil,
+	}, {
+		name:    "valid bech32m",
+		str:     validM,
+		version: VersionM,
+		want:    nil,
+	}, {
+		name:    "bech32 typo in data",
+		str:     substitute(valid, 10, 'q'),
+		version: Version0,
+		want:    []int{10},
+	}, {
+		name:    "bech32m typo in data",
+		str:     substitute(validM, 20, 'z'),
+		version: VersionM,
+		want:    []int{20},
+	}, {
+		name:    "bech32m typo in checksum",
+		str:     substitute(validM, len(validM)-1, 'q'),
+		version: VersionM,
+		want:    []int{len(validM) - 1},
+	}, {
+		name:    "uppercase bech32m typo",
+		str:     strings.ToUpper(substitute(validM, 6, 'x')),
+		version: VersionM,
+		want:    []int{6},
+	}, {
+		name:    "characters not in charset",
+		str:     substitute(substitute(validM, 7, 'b'), 30, 'o'),
+		version: VersionM,
+		want:    []int{7, 30},
+	}, {
+		name:    "two typos",
+		str:     substitute(substitute(validM, 7, 'q'), 30, 'q'),
+		version: VersionM,
+		want:    nil,
+	}, {
+		name:    "mixed case",
+		str:     "Split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		version: VersionM,
+		want:    nil,
+	}}
+
+	for _, test := range tests {
+		got := LocateErrors(test.str, test.version)
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%q: mismatched error locations -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
 // TestMixedCaseEncode ensures mixed case HRPs are converted to lowercase as
 // expected when encoding and that decoding the produced encoding when converted
 // to all uppercase produces the lowercase HRP and original data.
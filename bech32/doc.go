@@ -10,6 +10,10 @@ Bech32 strings consist of a human-readable part (hrp), followed by the
 separator 1, then a checksummed data part encoded using the 32 characters
 "qpzry9x8gf2tvdw0s3jn54khce6mua7l".
 
+The modified checksum variant, commonly referred to as bech32m, specified in
+BIP 350 is also supported via EncodeM and DecodeM.  Additionally, LocateErrors
+may be used to point out likely typos in strings with invalid checksums.
+
 More info: https://github.com/bitcoin/bips/blob/master/bip-0173.mediawiki
 */
 package bech32
@@ -123,6 +123,20 @@ func (sn *Node) MissedByBlock() []chainhash.Hash {
 	return missed
 }
 
+// NewlyMissedByBlock returns the tickets that became missed in this block. This
+// is a subset of the missed tickets returned by MissedByBlock. The output only
+// includes the initial miss of the ticket, not when a missed ticket is revoked.
+func (sn *Node) NewlyMissedByBlock() []chainhash.Hash {
+	var missed []chainhash.Hash
+	for _, undo := range sn.databaseUndoUpdate {
+		if undo.Missed && !undo.Revoked {
+			missed = append(missed, undo.TicketHash)
+		}
+	}
+
+	return missed
+}
+
 // ExpiredByBlock returns the tickets that expired in this block. This is a
 // subset of the missed tickets returned by MissedByBlock. The output only
 // includes the initial expiration of the ticket, not when an expired ticket is
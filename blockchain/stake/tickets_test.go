@@ -241,6 +241,10 @@ func nodesEqual(a *Node, b *Node) error {
 		return fmt.Errorf("missedbyblock were not equal between nodes; "+
 			"a: %x, b: %x", a.MissedByBlock(), b.MissedByBlock())
 	}
+	if !reflect.DeepEqual(a.NewlyMissedByBlock(), b.NewlyMissedByBlock()) {
+		return fmt.Errorf("newlymissedbyblock were not equal between nodes; "+
+			"a: %x, b: %x", a.NewlyMissedByBlock(), b.NewlyMissedByBlock())
+	}
 	if !reflect.DeepEqual(a.ExpiredByBlock(), b.ExpiredByBlock()) {
 		return fmt.Errorf("expiredbyblock were not equal between nodes; "+
 			"a: %x, b: %x", a.ExpiredByBlock(), b.ExpiredByBlock())
@@ -346,6 +350,21 @@ func TestTicketDBLongChain(t *testing.T) {
 			}
 		}
 
+		// Check that newly missed tickets in undo data are in the missed treap
+		// and not in the live or revoked treaps.
+		newlyMissed := bestNode.NewlyMissedByBlock()
+		for im := range newlyMissed {
+			if exists := bestNode.ExistsMissedTicket(newlyMissed[im]); !exists {
+				t.Errorf("newly missed ticket in undo data not in missed treap")
+			}
+			if exists := bestNode.ExistsLiveTicket(newlyMissed[im]); exists {
+				t.Errorf("newly missed ticket in undo data in live treap")
+			}
+			if exists := bestNode.ExistsRevokedTicket(newlyMissed[im]); exists {
+				t.Errorf("newly missed ticket in undo data in revoked treap")
+			}
+		}
+
 		// Check that the tickets returned by the expiring next block function for
 		// the previous block are now expired.
 		for _, ticketHash := range expiringNextBlock {
@@ -32,6 +32,7 @@ The provided functions fall into the following categories:
 
   - Converting to and from the compact target difficulty representation
   - Calculating work values based on the compact target difficulty
+  - Calculating the difficulty relative to the minimum difficulty
   - Checking a block hash satisfies a target difficulty and that target
     difficulty is within a valid range
 
@@ -45,6 +46,8 @@ The provided functions fall into the following categories:
   - Proof-of-work subsidy for a given height and number of votes
   - Stake vote subsidy for a given height
   - Treasury subsidy for a given height and number of votes
+  - Arbitrary subsidy schedules defined at runtime
+  - Cumulative emission at a given height for economic modeling
 
 # Merkle tree inclusion proofs
 
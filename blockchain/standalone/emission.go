@@ -0,0 +1,125 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+// EmissionScenario defines the assumptions used when simulating the emission
+// of coins over the life of a chain.
+type EmissionScenario struct {
+	// Voters is the number of votes every block contains once voting begins.
+	// The maximum number of votes per block is assumed when it is zero.
+	Voters uint16
+
+	// TreasuryActivationHeight is the height of the first block for which the
+	// treasury agenda is treated as active.  The agenda is never treated as
+	// active when it is zero.
+	TreasuryActivationHeight int64
+
+	// DCP0010ActivationHeight is the height of the first block for which the
+	// modified subsidy split defined in DCP0010 is treated as active.  The
+	// modified split is never treated as active when it is zero.
+	DCP0010ActivationHeight int64
+}
+
+// Emission houses the cumulative amount of coins, in atoms, emitted by a chain
+// broken down by their recipients.
+type Emission struct {
+	// BlockOne is the amount emitted by block height 1, which encompasses the
+	// initial coin distribution.
+	BlockOne int64
+
+	// Work is the amount emitted to the creators of blocks (PoW).
+	Work int64
+
+	// Stake is the amount emitted to the casters of stake votes (PoS).
+	Stake int64
+
+	// Treasury is the amount emitted to the project treasury.
+	Treasury int64
+}
+
+// Total returns the total amount of coins, in atoms, that were emitted.
+func (e *Emission) Total() int64 {
+	return e.BlockOne + e.Work + e.Stake + e.Treasury
+}
+
+// CalcCumulativeEmission simulates the emission of coins by a chain using the
+// subsidy parameters associated with the cache and returns the cumulative
+// amounts emitted by all blocks up to and including the provided height under
+// the provided scenario.  A nil scenario is treated as the zero value.
+//
+// The simulation applies the same subsidy rules used by consensus.  In
+// particular, votes included in a block receive the vote subsidy for the height
+// of the block being voted on, which is the previous block, and blocks that
+// contain fewer votes than required by consensus are treated as producing no
+// subsidy since they would be invalid.
+//
+// The calculation runs in time proportional to the number of reduction
+// intervals covered by the height as opposed to the height itself, so it is
+// suitable for economic modeling that involves querying arbitrary heights.
+//
+// This function is safe for concurrent access.
+func (c *SubsidyCache) CalcCumulativeEmission(height int64, scenario *EmissionScenario) Emission {
+	var sc EmissionScenario
+	if scenario != nil {
+		sc = *scenario
+	}
+	voters := sc.Voters
+	if voters == 0 {
+		voters = c.params.VotesPerBlock()
+	}
+	isActive := func(activationHeight, height int64) bool {
+		return activationHeight > 0 && height >= activationHeight
+	}
+
+	var emission Emission
+	if height < 1 {
+		return emission
+	}
+	emission.BlockOne = c.params.BlockOneSubsidy()
+
+	// Accumulate the emission of each run of blocks that emit the same
+	// amounts.  The amounts only change when the height of the block or the
+	// height it votes on crosses a reduction interval, when voting begins, or
+	// when any of the agendas activate.
+	interval := c.params.SubsidyReductionIntervalBlocks()
+	stakeValidationHeight := c.params.StakeValidationBeginHeight()
+	for start := int64(2); start <= height; {
+		end := height
+		limitRun := func(boundary int64) {
+			if boundary > start && boundary-1 < end {
+				end = boundary - 1
+			}
+		}
+		limitRun((start/interval + 1) * interval)
+		limitRun(((start-1)/interval+1)*interval + 1)
+		limitRun(stakeValidationHeight)
+		limitRun(sc.TreasuryActivationHeight)
+		limitRun(sc.DCP0010ActivationHeight)
+
+		// Stop once no further subsidy is possible.  This bounds the
+		// computation for heights well after the final reduction interval.
+		if c.CalcBlockSubsidy(start) == 0 && c.CalcBlockSubsidy(start-1) == 0 {
+			break
+		}
+
+		isTreasuryEnabled := isActive(sc.TreasuryActivationHeight, start)
+		useDCP0010 := isActive(sc.DCP0010ActivationHeight, start)
+		work := c.CalcWorkSubsidyV2(start, voters, useDCP0010)
+		treasury := c.CalcTreasurySubsidy(start, voters, isTreasuryEnabled)
+		var stake int64
+		if start >= stakeValidationHeight && voters >= c.minVotesRequired {
+			stake = c.CalcStakeVoteSubsidyV2(start-1, useDCP0010) *
+				int64(voters)
+		}
+
+		numBlocks := end - start + 1
+		emission.Work += work * numBlocks
+		emission.Stake += stake * numBlocks
+		emission.Treasury += treasury * numBlocks
+		start = end + 1
+	}
+
+	return emission
+}
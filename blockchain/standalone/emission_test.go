@@ -0,0 +1,142 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package standalone
+
+import (
+	"testing"
+)
+
+// TestCalcCumulativeEmission ensures the cumulative emission calculated for
+// various schedules, scenarios, and heights matches the emission calculated by
+// summing the subsidies of every individual block.
+func TestCalcCumulativeEmission(t *testing.T) {
+	t.Parallel()
+
+	// Define a small schedule so that every block can be summed.
+	schedule := &SubsidySchedule{
+		BlockOne:              1000000,
+		BaseSubsidy:           50000,
+		ReductionMultiplier:   9,
+		ReductionDivisor:      10,
+		ReductionInterval:     16,
+		WorkProportion:        6,
+		StakeProportion:       3,
+		TreasuryProportion:    1,
+		WorkProportionV2:      2,
+		StakeProportionV2:     7,
+		StakeValidationHeight: 20,
+		TicketsPerBlock:       5,
+	}
+	cache := NewSubsidyCache(schedule)
+
+	// blockEmission returns the total amount emitted by the block at the
+	// provided height under the provided scenario.
+	blockEmission := func(height int64, sc *EmissionScenario) int64 {
+		if height == 1 {
+			return schedule.BlockOne
+		}
+		voters := sc.Voters
+		if voters == 0 {
+			voters = schedule.TicketsPerBlock
+		}
+		isTreasuryEnabled := sc.TreasuryActivationHeight > 0 &&
+			height >= sc.TreasuryActivationHeight
+		useDCP0010 := sc.DCP0010ActivationHeight > 0 &&
+			height >= sc.DCP0010ActivationHeight
+		total := cache.CalcWorkSubsidyV2(height, voters, useDCP0010) +
+			cache.CalcTreasurySubsidy(height, voters, isTreasuryEnabled)
+		if height >= schedule.StakeValidationHeight && voters >= 3 {
+			total += cache.CalcStakeVoteSubsidyV2(height-1, useDCP0010) *
+				int64(voters)
+		}
+		return total
+	}
+
+	tests := []struct {
+		name     string
+		scenario EmissionScenario
+	}{{
+		name: "defaults",
+	}, {
+		name:     "minimum voters",
+		scenario: EmissionScenario{Voters: 3},
+	}, {
+		name:     "too few voters",
+		scenario: EmissionScenario{Voters: 2},
+	}, {
+		name: "agendas activate mid interval",
+		scenario: EmissionScenario{
+			Voters:                   4,
+			TreasuryActivationHeight: 37,
+			DCP0010ActivationHeight:  101,
+		},
+	}, {
+		name: "agendas active from start",
+		scenario: EmissionScenario{
+			TreasuryActivationHeight: 1,
+			DCP0010ActivationHeight:  1,
+		},
+	}}
+
+	for _, test := range tests {
+		var want int64
+		for height := int64(0); height <= 2000; height++ {
+			if height > 0 {
+				want += blockEmission(height, &test.scenario)
+			}
+			got := cache.CalcCumulativeEmission(height, &test.scenario)
+			if got.Total() != want {
+				t.Fatalf("%q: mismatched emission at height %d -- got %d, "+
+					"want %d", test.name, height, got.Total(), want)
+			}
+		}
+	}
+
+	// Ensure a nil scenario is treated as the zero value and the emission is
+	// broken down as expected.
+	got := cache.CalcCumulativeEmission(2, nil)
+	want := Emission{
+		BlockOne: schedule.BlockOne,
+		Work:     cache.CalcWorkSubsidy(2, 0),
+		Treasury: cache.CalcTreasurySubsidy(2, 0, noTreasury),
+	}
+	if got != want {
+		t.Fatalf("mismatched emission -- got %+v, want %+v", got, want)
+	}
+}
+
+// TestCalcCumulativeEmissionMainNet ensures the cumulative emission of the main
+// network once all subsidy has been produced matches the expected values.
+func TestCalcCumulativeEmissionMainNet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scenario *EmissionScenario
+		want     int64
+	}{{
+		name:     "original rules",
+		scenario: nil,
+		want:     2100000935675707,
+	}, {
+		// Note that the activation heights are the ones of the agendas on the
+		// main network.
+		name: "treasury and modified subsidy split",
+		scenario: &EmissionScenario{
+			TreasuryActivationHeight: 552448,
+			DCP0010ActivationHeight:  657280,
+		},
+		want: 2100001479147922,
+	}}
+
+	cache := NewSubsidyCache(mockMainNetParams())
+	for _, test := range tests {
+		got := cache.CalcCumulativeEmission(1<<40, test.scenario)
+		if got.Total() != test.want {
+			t.Errorf("%q: mismatched total emission -- got %d, want %d",
+				test.name, got.Total(), test.want)
+		}
+	}
+}
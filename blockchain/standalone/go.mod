@@ -4,7 +4,8 @@ go 1.17
 
 require (
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.2
+	github.com/decred/dcrd/crypto/blake256 v1.0.0
 	github.com/decred/dcrd/wire v1.4.0
 )
 
-require github.com/decred/dcrd/crypto/blake256 v1.0.0 // indirect
+replace github.com/decred/dcrd/crypto/blake256 => ../../crypto/blake256
@@ -6,9 +6,16 @@ package standalone
 
 import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/crypto/blake256"
 	"github.com/decred/dcrd/wire"
 )
 
+// merkleBatchMinParents is the minimum number of parent nodes in a level of a
+// merkle tree for the level to be hashed with the batch hashing API.  Smaller
+// levels are hashed individually since they are not large enough to make up
+// for the additional allocations.
+const merkleBatchMinParents = 32
+
 // CalcMerkleRootInPlace is an in-place version of CalcMerkleRoot that reuses
 // the backing array of the provided slice to perform the calculation thereby
 // preventing extra allocations.  It is the caller's responsibility to ensure it
@@ -17,7 +24,10 @@ import (
 // The function internally appends an additional entry in the case the number of
 // provided leaves is odd, so the caller may wish to pre-allocate space for one
 // additional element in the backing array in that case to ensure it doesn't
-// need to be reallocated to expand it.
+// need to be reallocated to expand it.  Note that large trees still require
+// some additional allocations since the nodes of their larger levels are
+// hashed in batches, which is significantly faster on CPUs that support
+// calculating multiple hashes in parallel.
 //
 // For example:
 //
@@ -39,6 +49,11 @@ func CalcMerkleRootInPlace(leaves []chainhash.Hash) chainhash.Hash {
 	var right = buf[chainhash.HashSize:]
 	var both = buf[:]
 
+	// These are only allocated when the tree has levels that are large enough
+	// to be hashed in batches and are then reused for all of them.
+	var batchBuf []byte
+	var batch [][]byte
+
 	// The following algorithm works by replacing the leftmost entries in the
 	// slice with the concatenations of each subsequent set of 2 hashes and
 	// shrinking the slice by half to account for the fact that each level of
@@ -60,13 +75,34 @@ func CalcMerkleRootInPlace(leaves []chainhash.Hash) chainhash.Hash {
 		}
 
 		// Set the parent node to the hash of the concatenation of the left and
-		// right children.
-		for i := 0; i < len(leaves)/2; i++ {
-			copy(left, leaves[i*2][:])
-			copy(right, leaves[i*2+1][:])
-			leaves[i] = chainhash.HashH(both)
+		// right children.  The children of all parents in large levels are
+		// hashed at once since that allows multiple hashes to be calculated in
+		// parallel.
+		numParents := len(leaves) / 2
+		if numParents >= merkleBatchMinParents {
+			if batchBuf == nil {
+				batchBuf = make([]byte, len(leaves)*chainhash.HashSize)
+				batch = make([][]byte, 0, numParents)
+			}
+			batch = batch[:0]
+			for i := range leaves {
+				copy(batchBuf[i*chainhash.HashSize:], leaves[i][:])
+			}
+			for i := 0; i < numParents; i++ {
+				offset := i * 2 * chainhash.HashSize
+				batch = append(batch, batchBuf[offset:offset+len(both)])
+			}
+			for i, digest := range blake256.Sum256Batch(batch) {
+				leaves[i] = digest
+			}
+		} else {
+			for i := 0; i < numParents; i++ {
+				copy(left, leaves[i*2][:])
+				copy(right, leaves[i*2+1][:])
+				leaves[i] = chainhash.HashH(both)
+			}
 		}
-		leaves = leaves[:len(leaves)/2]
+		leaves = leaves[:numParents]
 	}
 	return leaves[0]
 }
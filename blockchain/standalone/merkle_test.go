@@ -131,6 +131,44 @@ func TestCalcMerkleRoot(t *testing.T) {
 	}
 }
 
+// TestCalcMerkleRootBatch ensures the merkle roots of trees that are large
+// enough for their levels to be hashed in batches match those calculated by
+// hashing every node individually.
+func TestCalcMerkleRootBatch(t *testing.T) {
+	// calcMerkleRootRef calculates the merkle root by hashing the nodes of
+	// every level individually.
+	calcMerkleRootRef := func(leaves []chainhash.Hash) chainhash.Hash {
+		level := append([]chainhash.Hash(nil), leaves...)
+		for len(level) > 1 {
+			if len(level)&1 != 0 {
+				level = append(level, level[len(level)-1])
+			}
+			next := make([]chainhash.Hash, 0, len(level)/2)
+			for i := 0; i < len(level); i += 2 {
+				both := append(level[i][:], level[i+1][:]...)
+				next = append(next, chainhash.HashH(both))
+			}
+			level = next
+		}
+		return level[0]
+	}
+
+	for _, numLeaves := range []int{2*merkleBatchMinParents - 1,
+		2 * merkleBatchMinParents, 2*merkleBatchMinParents + 1, 1000, 1001} {
+
+		leaves := make([]chainhash.Hash, 0, numLeaves)
+		for i := 0; i < numLeaves; i++ {
+			leaves = append(leaves, chainhash.HashH([]byte{byte(i),
+				byte(i >> 8)}))
+		}
+		want := calcMerkleRootRef(leaves)
+		if got := CalcMerkleRoot(leaves); got != want {
+			t.Errorf("%d leaves: unexpected merkle root -- got %v, want %v",
+				numLeaves, got, want)
+		}
+	}
+}
+
 // TestCalcTxTreeMerkleRoot ensures the expected merkle root is produced for
 // known transactions.
 func TestCalcTxTreeMerkleRoot(t *testing.T) {
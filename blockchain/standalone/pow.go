@@ -156,6 +156,25 @@ func CalcWork(bits uint32) *big.Int {
 	return new(big.Int).Div(oneLsh256, denominator)
 }
 
+// CalcDifficultyRatio calculates the difficulty represented by the provided
+// compact target difficulty bits as a multiple of the minimum difficulty
+// represented by the provided compact proof-of-work limit bits.  Zero is
+// returned when either of the bits represent a number that is not positive.
+//
+// Note that the result only depends on the targets, so it applies to any
+// proof-of-work hash algorithm whose hashes are compared against the targets
+// as unsigned 256-bit numbers.  Also, note the minimum difficulty is derived
+// from the compact limit bits rather than the limit itself because block
+// headers encode the difficulty in the compact form which loses precision.
+func CalcDifficultyRatio(bits, powLimitBits uint32) *big.Rat {
+	max := CompactToBig(powLimitBits)
+	target := CompactToBig(bits)
+	if max.Sign() <= 0 || target.Sign() <= 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(max, target)
+}
+
 // checkProofOfWorkRange ensures the provided target difficulty is in min/max
 // range per the provided proof-of-work limit.
 func checkProofOfWorkRange(target *big.Int, powLimit *big.Int) error {
@@ -210,6 +210,57 @@ func TestCalcWork(t *testing.T) {
 	}
 }
 
+// TestCalcDifficultyRatio ensures calculating the difficulty relative to the
+// minimum difficulty from compact target difficulty bits produces the correct
+// results.
+func TestCalcDifficultyRatio(t *testing.T) {
+	tests := []struct {
+		name      string // test description
+		bits      uint32 // compact target difficulty bits to test
+		limitBits uint32 // compact proof-of-work limit bits to test
+		want      string // expected ratio
+	}{{
+		name:      "mainnet minimum difficulty",
+		bits:      0x1d00ffff,
+		limitBits: 0x1d00ffff,
+		want:      "1/1",
+	}, {
+		name:      "mainnet block 1",
+		bits:      0x1b01ffff,
+		limitBits: 0x1d00ffff,
+		want:      "4294901760/131071",
+	}, {
+		name:      "higher diff (exponent 24)",
+		bits:      0x185fb28a,
+		limitBits: 0x1d00ffff,
+		want:      "12009415754383360/1045271",
+	}, {
+		name:      "zero target difficulty",
+		bits:      0,
+		limitBits: 0x1d00ffff,
+		want:      "0/1",
+	}, {
+		name:      "negative target difficulty",
+		bits:      0x1810000,
+		limitBits: 0x1d00ffff,
+		want:      "0/1",
+	}, {
+		name:      "zero limit",
+		bits:      0x1d00ffff,
+		limitBits: 0,
+		want:      "0/1",
+	}}
+
+	for _, test := range tests {
+		result := CalcDifficultyRatio(test.bits, test.limitBits)
+		if result.String() != test.want {
+			t.Errorf("%q: mismatched result -- got %s, want %s", test.name,
+				result, test.want)
+			continue
+		}
+	}
+}
+
 // mockMainNetPowLimit returns the pow limit for the main network as of the
 // time this comment was written.  It is used to ensure the tests are stable
 // independent of any potential changes to chain parameters.
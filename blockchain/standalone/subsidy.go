@@ -83,6 +83,185 @@ type SubsidyParams interface {
 	VotesPerBlock() uint16
 }
 
+// SubsidySplitParamsV2 defines an optional interface that implementations of
+// SubsidyParams may also implement in order to override the modified subsidy
+// split defined in DCP0010.  The hard coded values defined in DCP0010 are used
+// when the parameters do not implement it.
+//
+// This allows networks, such as private and simulation networks, to use
+// arbitrary subsidy splits once the modified split is in effect.
+type SubsidySplitParamsV2 interface {
+	// WorkSubsidyProportionV2 returns the comparative proportion of the
+	// subsidy generated for creating a block (PoW) once the modified subsidy
+	// split defined in DCP0010 is in effect.  See the documentation for
+	// WorkSubsidyProportion of SubsidyParams for more details on how the
+	// parameter is used.
+	WorkSubsidyProportionV2() uint16
+
+	// StakeSubsidyProportionV2 returns the comparative proportion of the
+	// subsidy generated for casting stake votes (collectively, per block) once
+	// the modified subsidy split defined in DCP0010 is in effect.  See the
+	// documentation for WorkSubsidyProportion of SubsidyParams for more
+	// details on how the parameter is used.
+	StakeSubsidyProportionV2() uint16
+}
+
+// SubsidySchedule defines an arbitrary subsidy schedule by directly specifying
+// all of the parameters required when calculating block and vote subsidies.
+// It implements both the SubsidyParams and SubsidySplitParamsV2 interfaces.
+//
+// This is useful for networks that are defined at runtime as well as for
+// modeling the effects of alternative schedules.  See the documentation of the
+// corresponding SubsidyParams and SubsidySplitParamsV2 methods for details
+// regarding each parameter.
+//
+// The reduction interval, reduction divisor, and votes per block must be
+// positive and the sum of the proportions for each split must be positive or
+// the calculations will panic.
+type SubsidySchedule struct {
+	// BlockOne is the total subsidy of block height 1, which encompasses the
+	// initial coin distribution (premine).
+	BlockOne int64
+
+	// BaseSubsidy is the starting base max potential subsidy amount for mined
+	// blocks.
+	BaseSubsidy int64
+
+	// ReductionMultiplier, ReductionDivisor, and ReductionInterval control the
+	// exponential reduction of the subsidy.  The subsidy is multiplied by the
+	// multiplier and divided by the divisor every interval blocks.
+	ReductionMultiplier int64
+	ReductionDivisor    int64
+	ReductionInterval   int64
+
+	// WorkProportion, StakeProportion, and TreasuryProportion define the
+	// proportional split of the subsidy between PoW, PoS, and the Treasury
+	// prior to the modified subsidy split defined in DCP0010.
+	WorkProportion     uint16
+	StakeProportion    uint16
+	TreasuryProportion uint16
+
+	// WorkProportionV2 and StakeProportionV2 define the proportional split of
+	// the subsidy between PoW and PoS once the modified subsidy split defined
+	// in DCP0010 is in effect.  The Treasury proportion is the same for both
+	// splits.
+	WorkProportionV2  uint16
+	StakeProportionV2 uint16
+
+	// StakeValidationHeight is the height at which votes become required to
+	// extend a block.
+	StakeValidationHeight int64
+
+	// TicketsPerBlock is the maximum number of votes a block must contain to
+	// receive full subsidy once voting begins.
+	TicketsPerBlock uint16
+}
+
+// Ensure SubsidySchedule implements the subsidy parameter interfaces.
+var (
+	_ SubsidyParams        = (*SubsidySchedule)(nil)
+	_ SubsidySplitParamsV2 = (*SubsidySchedule)(nil)
+)
+
+// BlockOneSubsidy returns the total subsidy of block height 1 for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) BlockOneSubsidy() int64 {
+	return s.BlockOne
+}
+
+// BaseSubsidyValue returns the starting base max potential subsidy amount for
+// mined blocks for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) BaseSubsidyValue() int64 {
+	return s.BaseSubsidy
+}
+
+// SubsidyReductionMultiplier returns the multiplier to use when performing the
+// exponential subsidy reduction for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) SubsidyReductionMultiplier() int64 {
+	return s.ReductionMultiplier
+}
+
+// SubsidyReductionDivisor returns the divisor to use when performing the
+// exponential subsidy reduction for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) SubsidyReductionDivisor() int64 {
+	return s.ReductionDivisor
+}
+
+// SubsidyReductionIntervalBlocks returns the reduction interval in number of
+// blocks for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) SubsidyReductionIntervalBlocks() int64 {
+	return s.ReductionInterval
+}
+
+// WorkSubsidyProportion returns the comparative proportion of the subsidy
+// generated for creating a block (PoW) prior to the modified subsidy split
+// defined in DCP0010 for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) WorkSubsidyProportion() uint16 {
+	return s.WorkProportion
+}
+
+// StakeSubsidyProportion returns the comparative proportion of the subsidy
+// generated for casting stake votes (collectively, per block) prior to the
+// modified subsidy split defined in DCP0010 for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) StakeSubsidyProportion() uint16 {
+	return s.StakeProportion
+}
+
+// TreasurySubsidyProportion returns the comparative proportion of the subsidy
+// allocated to the project treasury for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) TreasurySubsidyProportion() uint16 {
+	return s.TreasuryProportion
+}
+
+// StakeValidationBeginHeight returns the height at which votes become required
+// to extend a block for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) StakeValidationBeginHeight() int64 {
+	return s.StakeValidationHeight
+}
+
+// VotesPerBlock returns the maximum number of votes a block must contain to
+// receive full subsidy once voting begins for the schedule.
+//
+// This is part of the SubsidyParams interface.
+func (s *SubsidySchedule) VotesPerBlock() uint16 {
+	return s.TicketsPerBlock
+}
+
+// WorkSubsidyProportionV2 returns the comparative proportion of the subsidy
+// generated for creating a block (PoW) once the modified subsidy split defined
+// in DCP0010 is in effect for the schedule.
+//
+// This is part of the SubsidySplitParamsV2 interface.
+func (s *SubsidySchedule) WorkSubsidyProportionV2() uint16 {
+	return s.WorkProportionV2
+}
+
+// StakeSubsidyProportionV2 returns the comparative proportion of the subsidy
+// generated for casting stake votes (collectively, per block) once the modified
+// subsidy split defined in DCP0010 is in effect for the schedule.
+//
+// This is part of the SubsidySplitParamsV2 interface.
+func (s *SubsidySchedule) StakeSubsidyProportionV2() uint16 {
+	return s.StakeProportionV2
+}
+
 // SubsidyCache provides efficient access to consensus-critical subsidy
 // calculations for blocks and votes, including the max potential subsidy for
 // given block heights, the proportional proof-of-work subsidy, the proportional
@@ -111,12 +290,23 @@ type SubsidyCache struct {
 	// be consider valid by consensus.
 	//
 	// totalProportions is the sum of the PoW, PoS, and Treasury proportions.
-	minVotesRequired uint16
-	totalProportions uint16
+	//
+	// workProportionV2, stakeProportionV2, and totalProportionsV2 are the
+	// PoW and PoS proportions and the sum of the PoW, PoS, and Treasury
+	// proportions once the modified subsidy split defined in DCP0010 is in
+	// effect.
+	minVotesRequired   uint16
+	totalProportions   uint16
+	workProportionV2   uint16
+	stakeProportionV2  uint16
+	totalProportionsV2 uint16
 }
 
 // NewSubsidyCache creates and initializes a new subsidy cache instance.  See
 // the SubsidyCache documentation for more details.
+//
+// The modified subsidy split defined in DCP0010 is overridden by the provided
+// parameters when they also implement the SubsidySplitParamsV2 interface.
 func NewSubsidyCache(params SubsidyParams) *SubsidyCache {
 	// Initialize the cache with the first interval set to the base subsidy and
 	// enough initial space for a few sparse entries for typical usage patterns.
@@ -125,6 +315,25 @@ func NewSubsidyCache(params SubsidyParams) *SubsidyCache {
 	cache := make(map[uint64]int64, prealloc)
 	cache[0] = baseSubsidy
 
+	// The work and stake vote subsidy proportions defined in DCP0010 are 10%
+	// and 80%, respectively.  Thus they are 1 and 8 since 1/10 = 10% and
+	// 8/10 = 80%.
+	//
+	// Note that the values are hard coded here as opposed to requiring them
+	// in the subsidy params in order to avoid the need for a major module
+	// bump that would be required if the subsidy params interface were
+	// changed.  Parameters that also implement the optional interface for the
+	// modified split override them.
+	workProportionV2 := uint16(1)
+	stakeProportionV2 := uint16(8)
+	totalProportionsV2 := uint16(10)
+	if splitParams, ok := params.(SubsidySplitParamsV2); ok {
+		workProportionV2 = splitParams.WorkSubsidyProportionV2()
+		stakeProportionV2 = splitParams.StakeSubsidyProportionV2()
+		totalProportionsV2 = workProportionV2 + stakeProportionV2 +
+			params.TreasurySubsidyProportion()
+	}
+
 	return &SubsidyCache{
 		cache:            cache,
 		cachedIntervals:  make([]uint64, 1, prealloc),
@@ -133,6 +342,9 @@ func NewSubsidyCache(params SubsidyParams) *SubsidyCache {
 		totalProportions: params.WorkSubsidyProportion() +
 			params.StakeSubsidyProportion() +
 			params.TreasurySubsidyProportion(),
+		workProportionV2:   workProportionV2,
+		stakeProportionV2:  stakeProportionV2,
+		totalProportionsV2: totalProportionsV2,
 	}
 }
 
@@ -316,16 +528,8 @@ func (c *SubsidyCache) CalcWorkSubsidyV2(height int64, voters uint16, useDCP0010
 		return c.CalcWorkSubsidy(height, voters)
 	}
 
-	// The work subsidy proportion defined in DCP0010 is 10%.  Thus it is 1
-	// since 1/10 = 10%.
-	//
-	// Note that the value is hard coded here as opposed to using the subsidy
-	// params in order to avoid the need for a major module bump that would be
-	// required if the subsidy params interface were changed.
-	const workSubsidyProportion = 1
-	const totalProportions = 10
-	return c.calcWorkSubsidy(height, voters, workSubsidyProportion,
-		totalProportions)
+	return c.calcWorkSubsidy(height, voters, c.workProportionV2,
+		c.totalProportionsV2)
 }
 
 // calcStakeVoteSubsidy returns the subsidy for a single stake vote for a block
@@ -400,16 +604,8 @@ func (c *SubsidyCache) CalcStakeVoteSubsidyV2(height int64, useDCP0010 bool) int
 		return c.CalcStakeVoteSubsidy(height)
 	}
 
-	// The stake vote subsidy proportion defined in DCP0010 is 80%.  Thus it is
-	// 8 since 8/10 = 80%.
-	//
-	// Note that the value is hard coded here as opposed to using the subsidy
-	// params in order to avoid the need for a major module bump that would be
-	// required if the subsidy params interface were changed.
-	const voteSubsidyProportion = 8
-	const totalProportions = 10
-	return c.calcStakeVoteSubsidy(height, voteSubsidyProportion,
-		totalProportions)
+	return c.calcStakeVoteSubsidy(height, c.stakeProportionV2,
+		c.totalProportionsV2)
 }
 
 // CalcTreasurySubsidy returns the subsidy required to go to the treasury for
@@ -1200,3 +1200,83 @@ func TestCalcBlockSubsidySparseCaching(t *testing.T) {
 		}
 	}
 }
+
+// TestSubsidyScheduleSplitV2 ensures the modified subsidy split defined in
+// DCP0010 is overridden by subsidy parameters that implement the optional
+// interface for it and the hard coded split is used otherwise.
+func TestSubsidyScheduleSplitV2(t *testing.T) {
+	t.Parallel()
+
+	// Create a schedule that matches the mock mainnet params aside from the
+	// modified subsidy split which is set to 30% PoW and 60% PoS.
+	mockMainNetParams := mockMainNetParams()
+	schedule := &SubsidySchedule{
+		BlockOne:              mockMainNetParams.blockOne,
+		BaseSubsidy:           mockMainNetParams.baseSubsidy,
+		ReductionMultiplier:   mockMainNetParams.reductionMultiplier,
+		ReductionDivisor:      mockMainNetParams.reductionDivisor,
+		ReductionInterval:     mockMainNetParams.reductionInterval,
+		WorkProportion:        mockMainNetParams.workProportion,
+		StakeProportion:       mockMainNetParams.voteProportion,
+		TreasuryProportion:    mockMainNetParams.treasuryProportion,
+		WorkProportionV2:      3,
+		StakeProportionV2:     6,
+		StakeValidationHeight: mockMainNetParams.stakeValidationHeight,
+		TicketsPerBlock:       mockMainNetParams.votesPerBlock,
+	}
+
+	const height = 6144 * 104
+	const voters = 5
+	mockCache := NewSubsidyCache(mockMainNetParams)
+	cache := NewSubsidyCache(schedule)
+	fullSubsidy := cache.CalcBlockSubsidy(height)
+	if fullSubsidy != mockCache.CalcBlockSubsidy(height) {
+		t.Fatalf("mismatched full subsidy -- got %d, want %d", fullSubsidy,
+			mockCache.CalcBlockSubsidy(height))
+	}
+
+	// Ensure the original split is the same for both.
+	if got, want := cache.CalcWorkSubsidyV2(height, voters, false),
+		mockCache.CalcWorkSubsidyV2(height, voters, false); got != want {
+
+		t.Fatalf("mismatched original work subsidy -- got %d, want %d", got,
+			want)
+	}
+	if got, want := cache.CalcStakeVoteSubsidyV2(height, false),
+		mockCache.CalcStakeVoteSubsidyV2(height, false); got != want {
+
+		t.Fatalf("mismatched original vote subsidy -- got %d, want %d", got,
+			want)
+	}
+
+	// Ensure the modified split uses the values from the schedule when they
+	// are provided and the values defined in DCP0010 otherwise.
+	tests := []struct {
+		name     string
+		cache    *SubsidyCache
+		wantWork int64
+		wantVote int64
+	}{{
+		name:     "schedule",
+		cache:    cache,
+		wantWork: fullSubsidy * 3 / 10,
+		wantVote: fullSubsidy * 6 / (10 * voters),
+	}, {
+		name:     "hard coded",
+		cache:    mockCache,
+		wantWork: fullSubsidy * 1 / 10,
+		wantVote: fullSubsidy * 8 / (10 * voters),
+	}}
+	for _, test := range tests {
+		work := test.cache.CalcWorkSubsidyV2(height, voters, true)
+		if work != test.wantWork {
+			t.Errorf("%q: mismatched work subsidy -- got %d, want %d",
+				test.name, work, test.wantWork)
+		}
+		vote := test.cache.CalcStakeVoteSubsidyV2(height, true)
+		if vote != test.wantVote {
+			t.Errorf("%q: mismatched vote subsidy -- got %d, want %d",
+				test.name, vote, test.wantVote)
+		}
+	}
+}
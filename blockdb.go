@@ -133,12 +133,12 @@ func loadBlockDB(params *chaincfg.Params) (database.DB, error) {
 		if err != nil {
 			return nil, err
 		}
-		return database.Create(cfg.DbType, dbPath, params.Net)
+		return database.Create(cfg.DbType, dbPath, params.Net, cfg.dbOpts)
 	}
 
 	// Open the existing database or create a new one as needed.
 	dcrdLog.Infof("Loading block database from '%s'", dbPath)
-	db, err := database.Open(cfg.DbType, dbPath, params.Net)
+	db, err := database.Open(cfg.DbType, dbPath, params.Net, cfg.dbOpts)
 	if err != nil {
 		// Return the error if it's not because the database doesn't exist.
 		if !errors.Is(err, database.ErrDbDoesNotExist) {
@@ -1,15 +1,18 @@
-// Copyright (c) 2018 The Decred developers
+// Copyright (c) 2018-2023 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package main
 
 import (
-	"crypto/elliptic"
+	"bytes"
+	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/pem"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestCertCreationWithHosts creates a certificate pair with extra hosts and
@@ -31,7 +34,8 @@ func TestCertCreationWithHosts(t *testing.T) {
 
 	// Generate cert pair with extra hosts.
 	hostnames := []string{"hostname1", "hostname2"}
-	err = genCertPair(certFile.Name(), keyFile.Name(), hostnames, elliptic.P521())
+	err = genCertPair(certFile.Name(), keyFile.Name(), hostnames, "P-521",
+		defaultTLSCertLifetime)
 	if err != nil {
 		t.Fatalf("Certificate was not created correctly: %s", err)
 	}
@@ -72,8 +76,100 @@ func TestCertCreationWithOutHosts(t *testing.T) {
 	defer os.Remove(keyFile.Name())
 
 	// Generate cert pair with no extra hosts.
-	err = genCertPair(certFile.Name(), keyFile.Name(), nil, elliptic.P521())
+	err = genCertPair(certFile.Name(), keyFile.Name(), nil, "P-521",
+		defaultTLSCertLifetime)
 	if err != nil {
 		t.Fatalf("Certificate was not created correctly: %s", err)
 	}
 }
+
+// TestCertCreationEd25519 ensures creating an Ed25519 certificate pair with a
+// custom lifetime works as intended.
+func TestCertCreationEd25519(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "rpc.cert")
+	keyFile := filepath.Join(dir, "rpc.key")
+
+	const lifetime = 48 * time.Hour
+	start := time.Now()
+	err := genCertPair(certFile, keyFile, nil, ed25519TLSCurve, lifetime)
+	if err != nil {
+		t.Fatalf("Certificate was not created correctly: %s", err)
+	}
+	certBytes, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("Unable to read the certfile: %s", err)
+	}
+	pemCert, _ := pem.Decode(certBytes)
+	x509Cert, err := x509.ParseCertificate(pemCert.Bytes)
+	if err != nil {
+		t.Fatalf("Unable to parse the certificate: %s", err)
+	}
+	if _, ok := x509Cert.PublicKey.(ed25519.PublicKey); !ok {
+		t.Fatalf("unexpected public key type %T", x509Cert.PublicKey)
+	}
+	wantNotAfter := start.Add(lifetime).Truncate(time.Second)
+	if x509Cert.NotAfter.Before(wantNotAfter) ||
+		x509Cert.NotAfter.After(wantNotAfter.Add(time.Minute)) {
+
+		t.Fatalf("unexpected expiration -- got %v, want %v",
+			x509Cert.NotAfter, wantNotAfter)
+	}
+}
+
+// TestRPCCertRenewal ensures the RPC certificate manager only renews
+// autogenerated certificates that expire within the renewal period and that
+// renewed certificates are served and notified.
+func TestRPCCertRenewal(t *testing.T) {
+	dir := t.TempDir()
+	m := &rpcCertManager{
+		certFile: filepath.Join(dir, "rpc.cert"),
+		keyFile:  filepath.Join(dir, "rpc.key"),
+		curve:    "P-256",
+		lifetime: 48 * time.Hour,
+		renewal:  24 * time.Hour,
+	}
+	err := genCertPair(m.certFile, m.keyFile, nil, m.curve, m.lifetime)
+	if err != nil {
+		t.Fatalf("Certificate was not created correctly: %s", err)
+	}
+	if err := m.load(); err != nil {
+		t.Fatalf("Unable to load certificate: %s", err)
+	}
+	if !m.autogenerated {
+		t.Fatal("certificate not detected as autogenerated")
+	}
+	origCert, _ := m.GetCertificate(nil)
+
+	// Ensure the certificate is only renewed once it expires within the
+	// renewal period.
+	now := time.Now()
+	if m.needsRenewal(now) {
+		t.Fatal("certificate needs renewal before the renewal period")
+	}
+	if !m.needsRenewal(now.Add(24*time.Hour + time.Minute)) {
+		t.Fatal("certificate does not need renewal within the renewal period")
+	}
+
+	var notifiedCert []byte
+	m.notifyRenewed = func(cert []byte, validUntil time.Time) {
+		notifiedCert = cert
+	}
+	if err := m.renew(); err != nil {
+		t.Fatalf("Unable to renew certificate: %s", err)
+	}
+	newCert, _ := m.GetCertificate(nil)
+	if bytes.Equal(origCert.Certificate[0], newCert.Certificate[0]) {
+		t.Fatal("renewed certificate was not served")
+	}
+	pemCert, _ := pem.Decode(notifiedCert)
+	if pemCert == nil || !bytes.Equal(pemCert.Bytes, newCert.Certificate[0]) {
+		t.Fatal("renewed certificate was not notified")
+	}
+
+	// Ensure certificates that were not autogenerated are never renewed.
+	m.autogenerated = false
+	if m.needsRenewal(now.Add(m.lifetime)) {
+		t.Fatal("externally managed certificate needs renewal")
+	}
+}
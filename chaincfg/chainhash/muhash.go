@@ -0,0 +1,155 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chainhash
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const (
+	// MuHashSerializedSize is the size in bytes of a serialized MuHash, which
+	// is the 3072-bit little-endian encoding of the group element that
+	// represents the set.
+	MuHashSerializedSize = 384
+
+	// muHashPrimeOffset is the value such that 2^3072 - muHashPrimeOffset is
+	// the largest 3072-bit safe prime, which is the modulus of the group.
+	muHashPrimeOffset = 1103717
+)
+
+// muHashPrime is the 3072-bit safe prime modulus of the multiplicative group
+// that data is mapped to by a MuHash.
+var muHashPrime = func() *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), MuHashSerializedSize*8)
+	return p.Sub(p, big.NewInt(muHashPrimeOffset))
+}()
+
+// MuHash is a rolling hash of a set of data items based on the multiplicative
+// group of integers modulo a 3072-bit safe prime.  Each item is mapped to an
+// element of the group, and the hash of the set is the product of the elements
+// of all of its items.  This means the resulting hash is independent of the
+// order items are added in and removing an item, which divides by its element,
+// is the exact inverse of adding it, which makes it well suited to hashing
+// large sets, such as the utxo set, incrementally.
+//
+// Items that are removed are accumulated separately so that only a single
+// modular inversion is needed when the hash is finalized or serialized.
+//
+// The zero value is not usable.  Use NewMuHash or DeserializeMuHash to create
+// instances.
+type MuHash struct {
+	numerator   *big.Int
+	denominator *big.Int
+}
+
+// NewMuHash returns a MuHash of the empty set.
+func NewMuHash() *MuHash {
+	return &MuHash{
+		numerator:   big.NewInt(1),
+		denominator: big.NewInt(1),
+	}
+}
+
+// DeserializeMuHash returns a MuHash of the set represented by the provided
+// serialized MuHash as produced by Serialize.  An error is returned if the
+// serialized data is not MuHashSerializedSize bytes or does not encode an
+// element of the group.
+func DeserializeMuHash(serialized []byte) (*MuHash, error) {
+	if len(serialized) != MuHashSerializedSize {
+		return nil, fmt.Errorf("invalid serialized muhash length of %d, "+
+			"want %d", len(serialized), MuHashSerializedSize)
+	}
+
+	// Reverse the bytes since big integers are big endian.
+	var reversed [MuHashSerializedSize]byte
+	for i := range serialized {
+		reversed[MuHashSerializedSize-1-i] = serialized[i]
+	}
+	element := new(big.Int).SetBytes(reversed[:])
+	if element.Sign() == 0 || element.Cmp(muHashPrime) >= 0 {
+		return nil, fmt.Errorf("serialized muhash is not an element of the " +
+			"group")
+	}
+	return &MuHash{
+		numerator:   element,
+		denominator: big.NewInt(1),
+	}, nil
+}
+
+// muHashElement maps the provided data to an element of the group by expanding
+// its BLAKE-256 hash to a 3072-bit little-endian integer.
+func muHashElement(data []byte) *big.Int {
+	var expanded [MuHashSerializedSize]byte
+	var seed [HashSize + 1]byte
+	copy(seed[:], HashB(data))
+	for i := 0; i < MuHashSerializedSize/HashSize; i++ {
+		seed[HashSize] = byte(i)
+		chunk := HashH(seed[:])
+		copy(expanded[i*HashSize:], chunk[:])
+	}
+
+	// Reverse the bytes since big integers are big endian.
+	for i, j := 0, len(expanded)-1; i < j; i, j = i+1, j-1 {
+		expanded[i], expanded[j] = expanded[j], expanded[i]
+	}
+	element := new(big.Int).SetBytes(expanded[:])
+	return element.Mod(element, muHashPrime)
+}
+
+// Add adds the provided data item to the set.
+func (h *MuHash) Add(data []byte) {
+	h.numerator.Mul(h.numerator, muHashElement(data))
+	h.numerator.Mod(h.numerator, muHashPrime)
+}
+
+// Remove removes the provided data item, which must have been previously
+// added, from the set.  Items may be removed before they are added, in which
+// case adding them later results in the same hash as if they were never added
+// or removed.
+func (h *MuHash) Remove(data []byte) {
+	h.denominator.Mul(h.denominator, muHashElement(data))
+	h.denominator.Mod(h.denominator, muHashPrime)
+}
+
+// Combine adds all of the items of the set represented by the provided MuHash
+// to the set and removes all of the items it removed.  This allows the hashes
+// of disjoint subsets to be calculated independently, such as in parallel, and
+// combined afterwards.
+func (h *MuHash) Combine(other *MuHash) {
+	h.numerator.Mul(h.numerator, other.numerator)
+	h.numerator.Mod(h.numerator, muHashPrime)
+	h.denominator.Mul(h.denominator, other.denominator)
+	h.denominator.Mod(h.denominator, muHashPrime)
+}
+
+// element returns the group element that represents the set.
+func (h *MuHash) element() *big.Int {
+	element := new(big.Int).ModInverse(h.denominator, muHashPrime)
+	element.Mul(element, h.numerator)
+	return element.Mod(element, muHashPrime)
+}
+
+// Serialize returns the 3072-bit little-endian encoding of the group element
+// that represents the set.  Since the encoding only depends on the items in
+// the set, it is also suitable for comparing sets without finalizing them.
+//
+// The MuHash is not modified, so more items may be added or removed
+// afterwards.
+func (h *MuHash) Serialize() [MuHashSerializedSize]byte {
+	var serialized [MuHashSerializedSize]byte
+	h.element().FillBytes(serialized[:])
+	for i, j := 0, len(serialized)-1; i < j; i, j = i+1, j-1 {
+		serialized[i], serialized[j] = serialized[j], serialized[i]
+	}
+	return serialized
+}
+
+// Finalize returns the BLAKE-256 hash of the serialized MuHash.  The MuHash is
+// not modified, so more items may be added or removed afterwards.
+func (h *MuHash) Finalize() Hash {
+	serialized := h.Serialize()
+	return HashH(serialized[:])
+}
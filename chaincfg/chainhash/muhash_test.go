@@ -0,0 +1,170 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chainhash
+
+import (
+	"testing"
+)
+
+// TestMuHash ensures the muhash of a set is independent of the order items are
+// added and removed in and that removing an item is the inverse of adding it.
+func TestMuHash(t *testing.T) {
+	t.Parallel()
+
+	// The muhash of the empty set is the hash of the encoding of one.
+	var one [MuHashSerializedSize]byte
+	one[0] = 1
+	emptyHash := HashH(one[:])
+	if got := NewMuHash().Finalize(); got != emptyHash {
+		t.Fatalf("unexpected empty set hash -- got %v, want %v", got,
+			emptyHash)
+	}
+
+	items := [][]byte{[]byte("item1"), []byte("item2"), []byte("item3")}
+	forward := NewMuHash()
+	for _, item := range items {
+		forward.Add(item)
+	}
+	reverse := NewMuHash()
+	for i := len(items) - 1; i >= 0; i-- {
+		reverse.Add(items[i])
+	}
+	setHash := forward.Finalize()
+	if got := reverse.Finalize(); got != setHash {
+		t.Fatalf("hash depends on order -- got %v, want %v", got, setHash)
+	}
+	if setHash == emptyHash {
+		t.Fatal("hash of non-empty set matches the empty set")
+	}
+
+	// Ensure removing an item results in the same hash as never adding it
+	// and that removing items before adding them is allowed.
+	partial := NewMuHash()
+	partial.Add(items[0])
+	partial.Add(items[2])
+	forward.Remove(items[1])
+	if got, want := forward.Finalize(), partial.Finalize(); got != want {
+		t.Fatalf("unexpected hash after removal -- got %v, want %v", got,
+			want)
+	}
+	removeFirst := NewMuHash()
+	removeFirst.Remove(items[1])
+	for _, item := range items {
+		removeFirst.Add(item)
+	}
+	if got, want := removeFirst.Finalize(), partial.Finalize(); got != want {
+		t.Fatalf("unexpected hash when removing first -- got %v, want %v",
+			got, want)
+	}
+
+	// Ensure combining the hashes of subsets results in the same hash as
+	// hashing the union of the subsets.
+	combined := NewMuHash()
+	combined.Add(items[0])
+	other := NewMuHash()
+	other.Add(items[1])
+	other.Add(items[2])
+	other.Remove(items[1])
+	combined.Combine(other)
+	if got, want := combined.Finalize(), partial.Finalize(); got != want {
+		t.Fatalf("unexpected hash after combining -- got %v, want %v", got,
+			want)
+	}
+}
+
+// TestMuHashVectors ensures the muhash of various sets matches known values so
+// that any changes to the mapping of items to group elements are detected.
+func TestMuHashVectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // test description
+		add    []string // items to add
+		remove []string // items to remove
+		want   string   // expected finalized hash
+	}{{
+		name: "single item",
+		add:  []string{"item1"},
+		want: "487c0da6335abe2c65db4584b3db21b2996ee8d43fce1a3426bf220d9665407d",
+	}, {
+		name:   "items added and removed",
+		add:    []string{"item1", "item2"},
+		remove: []string{"item3"},
+		want:   "33b7091c9334c93f196c27a745fa716c6037cd2be4e2705b8ea61e5bf1d7a643",
+	}}
+
+	for _, test := range tests {
+		h := NewMuHash()
+		for _, item := range test.add {
+			h.Add([]byte(item))
+		}
+		for _, item := range test.remove {
+			h.Remove([]byte(item))
+		}
+		if got := h.Finalize().String(); got != test.want {
+			t.Errorf("%q: mismatched hash -- got %s, want %s", test.name, got,
+				test.want)
+		}
+	}
+}
+
+// TestMuHashSerialize ensures serializing and deserializing a muhash round
+// trips, that the deserialized muhash can continue to be updated, and that
+// invalid serializations are rejected.
+func TestMuHashSerialize(t *testing.T) {
+	t.Parallel()
+
+	h := NewMuHash()
+	h.Add([]byte("item1"))
+	h.Add([]byte("item2"))
+	h.Remove([]byte("item3"))
+	serialized := h.Serialize()
+	restored, err := DeserializeMuHash(serialized[:])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := restored.Serialize(), serialized; got != want {
+		t.Fatalf("mismatched serialization -- got %x, want %x", got, want)
+	}
+	if got, want := restored.Finalize(), h.Finalize(); got != want {
+		t.Fatalf("mismatched hash -- got %v, want %v", got, want)
+	}
+
+	// Ensure the restored muhash continues to be updated the same way.
+	h.Add([]byte("item3"))
+	restored.Add([]byte("item3"))
+	if got, want := restored.Finalize(), h.Finalize(); got != want {
+		t.Fatalf("mismatched hash after update -- got %v, want %v", got,
+			want)
+	}
+
+	// Ensure invalid serializations are rejected.
+	var zero, prime [MuHashSerializedSize]byte
+	muHashPrime.FillBytes(prime[:])
+	for i, j := 0, len(prime)-1; i < j; i, j = i+1, j-1 {
+		prime[i], prime[j] = prime[j], prime[i]
+	}
+	tests := []struct {
+		name       string
+		serialized []byte
+	}{{
+		name:       "short",
+		serialized: serialized[:MuHashSerializedSize-1],
+	}, {
+		name:       "long",
+		serialized: append(serialized[:], 0x00),
+	}, {
+		name:       "zero",
+		serialized: zero[:],
+	}, {
+		name:       "modulus",
+		serialized: prime[:],
+	}}
+	for _, test := range tests {
+		if _, err := DeserializeMuHash(test.serialized); err == nil {
+			t.Errorf("%q: did not receive expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,625 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// hexBytes is a byte slice that is encoded as a hex string in JSON.
+type hexBytes []byte
+
+// UnmarshalJSON decodes the byte slice from a JSON hex string.
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// jsonDuration is a duration that is encoded as a JSON string in the format
+// accepted by time.ParseDuration such as "5m".
+type jsonDuration time.Duration
+
+// UnmarshalJSON decodes the duration from a JSON string.
+func (d *jsonDuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(duration)
+	return nil
+}
+
+// jsonTokenPayout is the JSON encoding of a TokenPayout.
+type jsonTokenPayout struct {
+	ScriptVersion uint16   `json:"scriptVersion"`
+	Script        hexBytes `json:"script"`
+	Amount        int64    `json:"amount"`
+}
+
+// customNetDefinition is the JSON encoding of the parameters of a custom
+// network.  All parameters other than the base network and those that identify
+// the network are optional and default to the value of the base network.
+type customNetDefinition struct {
+	// Base is the name of the standard network the parameters that are not
+	// specified are taken from.
+	Base string `json:"base"`
+
+	// The parameters that identify the network.  They must be specified and
+	// must differ from those of all standard networks.
+	Name        string   `json:"name"`
+	Net         uint32   `json:"net"`
+	DefaultPort string   `json:"defaultPort"`
+	Seeders     []string `json:"seeders"`
+
+	// GenesisBlock is the serialized genesis block.
+	GenesisBlock hexBytes `json:"genesisBlock"`
+
+	// Proof-of-work parameters.
+	PowLimitBits             *uint32       `json:"powLimitBits"`
+	PowHasher                *string       `json:"powHasher"`
+	GenerateSupported        *bool         `json:"generateSupported"`
+	MaximumBlockSizes        []int         `json:"maximumBlockSizes"`
+	MaxTxSize                *int          `json:"maxTxSize"`
+	TargetTimePerBlock       *jsonDuration `json:"targetTimePerBlock"`
+	WorkDiffAlpha            *int64        `json:"workDiffAlpha"`
+	WorkDiffWindowSize       *int64        `json:"workDiffWindowSize"`
+	WorkDiffWindows          *int64        `json:"workDiffWindows"`
+	RetargetAdjustmentFactor *int64        `json:"retargetAdjustmentFactor"`
+
+	// Subsidy schedule parameters.
+	BaseSubsidy              *int64            `json:"baseSubsidy"`
+	MulSubsidy               *int64            `json:"mulSubsidy"`
+	DivSubsidy               *int64            `json:"divSubsidy"`
+	SubsidyReductionInterval *int64            `json:"subsidyReductionInterval"`
+	WorkRewardProportion     *uint16           `json:"workRewardProportion"`
+	WorkRewardProportionV2   *uint16           `json:"workRewardProportionV2"`
+	StakeRewardProportion    *uint16           `json:"stakeRewardProportion"`
+	StakeRewardProportionV2  *uint16           `json:"stakeRewardProportionV2"`
+	BlockTaxProportion       *uint16           `json:"blockTaxProportion"`
+	BlockOneLedger           []jsonTokenPayout `json:"blockOneLedger"`
+
+	// Agenda parameters.
+	RuleChangeActivationQuorum     *uint32                          `json:"ruleChangeActivationQuorum"`
+	RuleChangeActivationMultiplier *uint32                          `json:"ruleChangeActivationMultiplier"`
+	RuleChangeActivationDivisor    *uint32                          `json:"ruleChangeActivationDivisor"`
+	RuleChangeActivationInterval   *uint32                          `json:"ruleChangeActivationInterval"`
+	Deployments                    map[uint32][]ConsensusDeployment `json:"deployments"`
+	BlockEnforceNumRequired        *uint64                          `json:"blockEnforceNumRequired"`
+	BlockRejectNumRequired         *uint64                          `json:"blockRejectNumRequired"`
+	BlockUpgradeNumToCheck         *uint64                          `json:"blockUpgradeNumToCheck"`
+
+	// Mempool parameters.
+	AcceptNonStdTxs *bool `json:"acceptNonStdTxs"`
+
+	// Address and key encoding parameters.
+	NetworkAddressPrefix *string  `json:"networkAddressPrefix"`
+	PubKeyAddrID         hexBytes `json:"pubKeyAddrID"`
+	PubKeyHashAddrID     hexBytes `json:"pubKeyHashAddrID"`
+	PKHEdwardsAddrID     hexBytes `json:"pkhEdwardsAddrID"`
+	PKHSchnorrAddrID     hexBytes `json:"pkhSchnorrAddrID"`
+	ScriptHashAddrID     hexBytes `json:"scriptHashAddrID"`
+	PrivateKeyID         hexBytes `json:"privateKeyID"`
+	AddressHRPV1         *string  `json:"addressHRPV1"`
+	HDPrivateKeyID       hexBytes `json:"hdPrivateKeyID"`
+	HDPublicKeyID        hexBytes `json:"hdPublicKeyID"`
+	SLIP0044CoinType     *uint32  `json:"slip0044CoinType"`
+	LegacyCoinType       *uint32  `json:"legacyCoinType"`
+
+	// Proof-of-stake parameters.
+	MinimumStakeDiff        *int64   `json:"minimumStakeDiff"`
+	TicketPoolSize          *uint16  `json:"ticketPoolSize"`
+	TicketsPerBlock         *uint16  `json:"ticketsPerBlock"`
+	TicketMaturity          *uint16  `json:"ticketMaturity"`
+	TicketExpiry            *uint32  `json:"ticketExpiry"`
+	CoinbaseMaturity        *uint16  `json:"coinbaseMaturity"`
+	SStxChangeMaturity      *uint16  `json:"sstxChangeMaturity"`
+	TicketPoolSizeWeight    *uint16  `json:"ticketPoolSizeWeight"`
+	StakeDiffAlpha          *int64   `json:"stakeDiffAlpha"`
+	StakeDiffWindowSize     *int64   `json:"stakeDiffWindowSize"`
+	StakeDiffWindows        *int64   `json:"stakeDiffWindows"`
+	StakeVersionInterval    *int64   `json:"stakeVersionInterval"`
+	MaxFreshStakePerBlock   *uint8   `json:"maxFreshStakePerBlock"`
+	StakeEnabledHeight      *int64   `json:"stakeEnabledHeight"`
+	StakeValidationHeight   *int64   `json:"stakeValidationHeight"`
+	StakeBaseSigScript      hexBytes `json:"stakeBaseSigScript"`
+	StakeMajorityMultiplier *int32   `json:"stakeMajorityMultiplier"`
+	StakeMajorityDivisor    *int32   `json:"stakeMajorityDivisor"`
+
+	// Treasury parameters.
+	OrganizationPkScript           hexBytes   `json:"organizationPkScript"`
+	OrganizationPkScriptVersion    *uint16    `json:"organizationPkScriptVersion"`
+	PiKeys                         []hexBytes `json:"piKeys"`
+	TreasuryVoteInterval           *uint64    `json:"treasuryVoteInterval"`
+	TreasuryVoteIntervalMultiplier *uint64    `json:"treasuryVoteIntervalMultiplier"`
+	TreasuryVoteQuorumMultiplier   *uint64    `json:"treasuryVoteQuorumMultiplier"`
+	TreasuryVoteQuorumDivisor      *uint64    `json:"treasuryVoteQuorumDivisor"`
+	TreasuryVoteRequiredMultiplier *uint64    `json:"treasuryVoteRequiredMultiplier"`
+	TreasuryVoteRequiredDivisor    *uint64    `json:"treasuryVoteRequiredDivisor"`
+	TreasuryExpenditureWindow      *uint64    `json:"treasuryExpenditureWindow"`
+	TreasuryExpenditurePolicy      *uint64    `json:"treasuryExpenditurePolicy"`
+	TreasuryExpenditureBootstrap   *uint64    `json:"treasuryExpenditureBootstrap"`
+
+	// Block signing parameters.
+	BlockSignChallenge hexBytes `json:"blockSignChallenge"`
+}
+
+// standardNetParams returns the parameters of all standard networks.
+func standardNetParams() []*Params {
+	return []*Params{MainNetParams(), TestNet3Params(), SimNetParams(),
+		RegNetParams()}
+}
+
+// compactToBig converts a compact representation of a whole number to an
+// unsigned 256-bit number.  See the CompactToBig function of the standalone
+// package of the blockchain module for details.
+func compactToBig(compact uint32) *big.Int {
+	mantissa := compact & 0x007fffff
+	isNegative := compact&0x00800000 != 0
+	exponent := uint(compact >> 24)
+
+	var bn *big.Int
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		bn = big.NewInt(int64(mantissa))
+	} else {
+		bn = big.NewInt(int64(mantissa))
+		bn.Lsh(bn, 8*(exponent-3))
+	}
+	if isNegative {
+		bn = bn.Neg(bn)
+	}
+	return bn
+}
+
+// copyAddrID copies the provided encoded address or key magic to the provided
+// destination when it was specified.  An error is returned when it does not
+// have the same length as the destination.
+func copyAddrID(dst []byte, src hexBytes, name string) error {
+	if src == nil {
+		return nil
+	}
+	if len(src) != len(dst) {
+		return fmt.Errorf("%s must be %d bytes instead of %d", name, len(dst),
+			len(src))
+	}
+	copy(dst, src)
+	return nil
+}
+
+// ParamsFromJSON returns the parameters of a custom network, such as a private
+// network, that are defined by the provided JSON document.
+//
+// The document is an object that must specify the standard network the
+// parameters are based on with the "base" key, which is one of "mainnet",
+// "testnet3", "simnet", or "regnet", along with the "name", "net" magic, and
+// "defaultPort" of the network which must all differ from those of the standard
+// networks.  All other parameters are optional and are taken from the base
+// network when they are not specified, with the exception of the seeders,
+// assumed valid block, and minimum known chain work which are always empty
+// since those of the base network do not apply.
+//
+// The keys of the remaining parameters are the names of the respective fields
+// of Params starting with a lowercase letter, such as "baseSubsidy".  Scripts,
+// keys, address magics, and the serialized "genesisBlock" are hex strings,
+// "targetTimePerBlock" is a duration string such as "5m", "powLimitBits" is the
+// compact form of the proof of work limit, "powHasher" is the name of a proof
+// of work hash algorithm registered with RegisterPowHasher, and "deployments"
+// maps stake versions to the agendas that are voted on for them.  The target
+// timespan is calculated from the target time per block and work difficulty
+// window size.
+//
+// Unknown keys are rejected so that mistakes in the definition are detected
+// rather than silently resulting in the parameters of the base network.  The
+// parameters are validated, however, it remains the responsibility of the
+// caller to ensure they are sensible for the intended network.
+func ParamsFromJSON(data []byte) (*Params, error) {
+	var def customNetDefinition
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid network definition: %w", err)
+	}
+
+	// Start from the parameters of the base network.
+	var p *Params
+	standardNets := standardNetParams()
+	for _, params := range standardNets {
+		if params.Name == def.Base {
+			p = params
+			break
+		}
+	}
+	if p == nil {
+		return nil, fmt.Errorf("unknown base network %q", def.Base)
+	}
+
+	// Ensure the network is identified uniquely.
+	if def.Name == "" {
+		return nil, fmt.Errorf("network name is not specified")
+	}
+	if def.Net == 0 {
+		return nil, fmt.Errorf("network magic is not specified")
+	}
+	if def.DefaultPort == "" {
+		return nil, fmt.Errorf("default port is not specified")
+	}
+	for _, params := range standardNets {
+		if def.Name == params.Name {
+			return nil, fmt.Errorf("network name %q is already used by a "+
+				"standard network", def.Name)
+		}
+		if wire.CurrencyNet(def.Net) == params.Net {
+			return nil, fmt.Errorf("network magic %#08x is already used by "+
+				"the %s network", def.Net, params.Name)
+		}
+	}
+	p.Name = def.Name
+	p.Net = wire.CurrencyNet(def.Net)
+	p.DefaultPort = def.DefaultPort
+	p.seeders = def.Seeders
+	p.DNSSeeds = nil
+	p.Checkpoints = nil
+	p.AssumeValid = chainhash.Hash{}
+	p.MinKnownChainWork = nil
+
+	if def.GenesisBlock != nil {
+		var genesis wire.MsgBlock
+		if err := genesis.FromBytes(def.GenesisBlock); err != nil {
+			return nil, fmt.Errorf("invalid genesis block: %w", err)
+		}
+		p.GenesisBlock = &genesis
+		p.GenesisHash = genesis.BlockHash()
+	}
+
+	if def.PowLimitBits != nil {
+		p.PowLimitBits = *def.PowLimitBits
+		p.PowLimit = compactToBig(p.PowLimitBits)
+	}
+	if def.PowHasher != nil {
+		hasher, ok := PowHasherByName(*def.PowHasher)
+		if !ok {
+			return nil, fmt.Errorf("unknown proof of work hash algorithm %q",
+				*def.PowHasher)
+		}
+		p.PowHasher = hasher
+	}
+	if def.GenerateSupported != nil {
+		p.GenerateSupported = *def.GenerateSupported
+	}
+	if def.MaximumBlockSizes != nil {
+		p.MaximumBlockSizes = def.MaximumBlockSizes
+	}
+	if def.MaxTxSize != nil {
+		p.MaxTxSize = *def.MaxTxSize
+	}
+	if def.TargetTimePerBlock != nil {
+		p.TargetTimePerBlock = time.Duration(*def.TargetTimePerBlock)
+	}
+	if def.WorkDiffAlpha != nil {
+		p.WorkDiffAlpha = *def.WorkDiffAlpha
+	}
+	if def.WorkDiffWindowSize != nil {
+		p.WorkDiffWindowSize = *def.WorkDiffWindowSize
+	}
+	if def.WorkDiffWindows != nil {
+		p.WorkDiffWindows = *def.WorkDiffWindows
+	}
+	p.TargetTimespan = p.TargetTimePerBlock *
+		time.Duration(p.WorkDiffWindowSize)
+	if def.RetargetAdjustmentFactor != nil {
+		p.RetargetAdjustmentFactor = *def.RetargetAdjustmentFactor
+	}
+
+	if def.BaseSubsidy != nil {
+		p.BaseSubsidy = *def.BaseSubsidy
+	}
+	if def.MulSubsidy != nil {
+		p.MulSubsidy = *def.MulSubsidy
+	}
+	if def.DivSubsidy != nil {
+		p.DivSubsidy = *def.DivSubsidy
+	}
+	if def.SubsidyReductionInterval != nil {
+		p.SubsidyReductionInterval = *def.SubsidyReductionInterval
+	}
+	if def.WorkRewardProportion != nil {
+		p.WorkRewardProportion = *def.WorkRewardProportion
+	}
+	if def.WorkRewardProportionV2 != nil {
+		p.WorkRewardProportionV2 = *def.WorkRewardProportionV2
+	}
+	if def.StakeRewardProportion != nil {
+		p.StakeRewardProportion = *def.StakeRewardProportion
+	}
+	if def.StakeRewardProportionV2 != nil {
+		p.StakeRewardProportionV2 = *def.StakeRewardProportionV2
+	}
+	if def.BlockTaxProportion != nil {
+		p.BlockTaxProportion = *def.BlockTaxProportion
+	}
+	if def.BlockOneLedger != nil {
+		p.BlockOneLedger = make([]TokenPayout, 0, len(def.BlockOneLedger))
+		for _, payout := range def.BlockOneLedger {
+			p.BlockOneLedger = append(p.BlockOneLedger, TokenPayout{
+				ScriptVersion: payout.ScriptVersion,
+				Script:        payout.Script,
+				Amount:        payout.Amount,
+			})
+		}
+	}
+
+	if def.RuleChangeActivationQuorum != nil {
+		p.RuleChangeActivationQuorum = *def.RuleChangeActivationQuorum
+	}
+	if def.RuleChangeActivationMultiplier != nil {
+		p.RuleChangeActivationMultiplier = *def.RuleChangeActivationMultiplier
+	}
+	if def.RuleChangeActivationDivisor != nil {
+		p.RuleChangeActivationDivisor = *def.RuleChangeActivationDivisor
+	}
+	if def.RuleChangeActivationInterval != nil {
+		p.RuleChangeActivationInterval = *def.RuleChangeActivationInterval
+	}
+	if def.Deployments != nil {
+		p.Deployments = def.Deployments
+	}
+	if def.BlockEnforceNumRequired != nil {
+		p.BlockEnforceNumRequired = *def.BlockEnforceNumRequired
+	}
+	if def.BlockRejectNumRequired != nil {
+		p.BlockRejectNumRequired = *def.BlockRejectNumRequired
+	}
+	if def.BlockUpgradeNumToCheck != nil {
+		p.BlockUpgradeNumToCheck = *def.BlockUpgradeNumToCheck
+	}
+
+	if def.AcceptNonStdTxs != nil {
+		p.AcceptNonStdTxs = *def.AcceptNonStdTxs
+	}
+
+	if def.NetworkAddressPrefix != nil {
+		p.NetworkAddressPrefix = *def.NetworkAddressPrefix
+	}
+	addrIDs := []struct {
+		dst  []byte
+		src  hexBytes
+		name string
+	}{
+		{p.PubKeyAddrID[:], def.PubKeyAddrID, "pubKeyAddrID"},
+		{p.PubKeyHashAddrID[:], def.PubKeyHashAddrID, "pubKeyHashAddrID"},
+		{p.PKHEdwardsAddrID[:], def.PKHEdwardsAddrID, "pkhEdwardsAddrID"},
+		{p.PKHSchnorrAddrID[:], def.PKHSchnorrAddrID, "pkhSchnorrAddrID"},
+		{p.ScriptHashAddrID[:], def.ScriptHashAddrID, "scriptHashAddrID"},
+		{p.PrivateKeyID[:], def.PrivateKeyID, "privateKeyID"},
+		{p.HDPrivateKeyID[:], def.HDPrivateKeyID, "hdPrivateKeyID"},
+		{p.HDPublicKeyID[:], def.HDPublicKeyID, "hdPublicKeyID"},
+	}
+	for _, addrID := range addrIDs {
+		if err := copyAddrID(addrID.dst, addrID.src, addrID.name); err != nil {
+			return nil, err
+		}
+	}
+	if def.AddressHRPV1 != nil {
+		p.AddressHRPV1 = *def.AddressHRPV1
+	}
+	if def.SLIP0044CoinType != nil {
+		p.SLIP0044CoinType = *def.SLIP0044CoinType
+	}
+	if def.LegacyCoinType != nil {
+		p.LegacyCoinType = *def.LegacyCoinType
+	}
+
+	if def.MinimumStakeDiff != nil {
+		p.MinimumStakeDiff = *def.MinimumStakeDiff
+	}
+	if def.TicketPoolSize != nil {
+		p.TicketPoolSize = *def.TicketPoolSize
+	}
+	if def.TicketsPerBlock != nil {
+		p.TicketsPerBlock = *def.TicketsPerBlock
+	}
+	if def.TicketMaturity != nil {
+		p.TicketMaturity = *def.TicketMaturity
+	}
+	if def.TicketExpiry != nil {
+		p.TicketExpiry = *def.TicketExpiry
+	}
+	if def.CoinbaseMaturity != nil {
+		p.CoinbaseMaturity = *def.CoinbaseMaturity
+	}
+	if def.SStxChangeMaturity != nil {
+		p.SStxChangeMaturity = *def.SStxChangeMaturity
+	}
+	if def.TicketPoolSizeWeight != nil {
+		p.TicketPoolSizeWeight = *def.TicketPoolSizeWeight
+	}
+	if def.StakeDiffAlpha != nil {
+		p.StakeDiffAlpha = *def.StakeDiffAlpha
+	}
+	if def.StakeDiffWindowSize != nil {
+		p.StakeDiffWindowSize = *def.StakeDiffWindowSize
+	}
+	if def.StakeDiffWindows != nil {
+		p.StakeDiffWindows = *def.StakeDiffWindows
+	}
+	if def.StakeVersionInterval != nil {
+		p.StakeVersionInterval = *def.StakeVersionInterval
+	}
+	if def.MaxFreshStakePerBlock != nil {
+		p.MaxFreshStakePerBlock = *def.MaxFreshStakePerBlock
+	}
+	if def.StakeEnabledHeight != nil {
+		p.StakeEnabledHeight = *def.StakeEnabledHeight
+	}
+	if def.StakeValidationHeight != nil {
+		p.StakeValidationHeight = *def.StakeValidationHeight
+	}
+	if def.StakeBaseSigScript != nil {
+		p.StakeBaseSigScript = def.StakeBaseSigScript
+	}
+	if def.StakeMajorityMultiplier != nil {
+		p.StakeMajorityMultiplier = *def.StakeMajorityMultiplier
+	}
+	if def.StakeMajorityDivisor != nil {
+		p.StakeMajorityDivisor = *def.StakeMajorityDivisor
+	}
+
+	if def.OrganizationPkScript != nil {
+		p.OrganizationPkScript = def.OrganizationPkScript
+	}
+	if def.OrganizationPkScriptVersion != nil {
+		p.OrganizationPkScriptVersion = *def.OrganizationPkScriptVersion
+	}
+	if def.PiKeys != nil {
+		p.PiKeys = make([][]byte, 0, len(def.PiKeys))
+		for _, key := range def.PiKeys {
+			p.PiKeys = append(p.PiKeys, key)
+		}
+	}
+	if def.TreasuryVoteInterval != nil {
+		p.TreasuryVoteInterval = *def.TreasuryVoteInterval
+	}
+	if def.TreasuryVoteIntervalMultiplier != nil {
+		p.TreasuryVoteIntervalMultiplier = *def.TreasuryVoteIntervalMultiplier
+	}
+	if def.TreasuryVoteQuorumMultiplier != nil {
+		p.TreasuryVoteQuorumMultiplier = *def.TreasuryVoteQuorumMultiplier
+	}
+	if def.TreasuryVoteQuorumDivisor != nil {
+		p.TreasuryVoteQuorumDivisor = *def.TreasuryVoteQuorumDivisor
+	}
+	if def.TreasuryVoteRequiredMultiplier != nil {
+		p.TreasuryVoteRequiredMultiplier = *def.TreasuryVoteRequiredMultiplier
+	}
+	if def.TreasuryVoteRequiredDivisor != nil {
+		p.TreasuryVoteRequiredDivisor = *def.TreasuryVoteRequiredDivisor
+	}
+	if def.TreasuryExpenditureWindow != nil {
+		p.TreasuryExpenditureWindow = *def.TreasuryExpenditureWindow
+	}
+	if def.TreasuryExpenditurePolicy != nil {
+		p.TreasuryExpenditurePolicy = *def.TreasuryExpenditurePolicy
+	}
+	if def.TreasuryExpenditureBootstrap != nil {
+		p.TreasuryExpenditureBootstrap = *def.TreasuryExpenditureBootstrap
+	}
+
+	if def.BlockSignChallenge != nil {
+		p.BlockSignChallenge = def.BlockSignChallenge
+	}
+
+	if err := validateCustomParams(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// maxAddressHRPLen is the maximum length of the human-readable part of version
+// 1 addresses that allows the bech32m encoding of the longest allowed program
+// to fit within the maximum length of a bech32m string.
+const maxAddressHRPLen = 18
+
+// isValidAddressHRP returns whether or not the provided human-readable part of
+// version 1 addresses only consists of the characters allowed by bech32m in
+// their lowercase form and is an allowed length.
+func isValidAddressHRP(hrp string) bool {
+	if len(hrp) == 0 || len(hrp) > maxAddressHRPLen {
+		return false
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 || (hrp[i] >= 'A' && hrp[i] <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCustomParams ensures the provided parameters of a custom network do
+// not contain values that would prevent the network from functioning or cause
+// calculations based on them to panic.
+func validateCustomParams(p *Params) error {
+	switch {
+	case p.PowLimit.Sign() <= 0:
+		return fmt.Errorf("proof of work limit must be positive")
+	case compactToBig(p.GenesisBlock.Header.Bits).Cmp(p.PowLimit) > 0:
+		return fmt.Errorf("genesis block difficulty bits %08x exceed the "+
+			"proof of work limit", p.GenesisBlock.Header.Bits)
+	case len(p.MaximumBlockSizes) == 0:
+		return fmt.Errorf("maximum block sizes must not be empty")
+	case p.TargetTimePerBlock <= 0:
+		return fmt.Errorf("target time per block must be positive")
+	case p.WorkDiffWindowSize <= 0 || p.WorkDiffWindows <= 0:
+		return fmt.Errorf("work difficulty window size and windows must " +
+			"be positive")
+	case p.StakeDiffWindowSize <= 0 || p.StakeDiffWindows <= 0:
+		return fmt.Errorf("stake difficulty window size and windows must " +
+			"be positive")
+	case p.BaseSubsidy <= 0 || p.BaseSubsidy > 140739635871744:
+		return fmt.Errorf("base subsidy must be positive and at most " +
+			"140739635871744 atoms")
+	case p.DivSubsidy <= 0 || p.SubsidyReductionInterval <= 0:
+		return fmt.Errorf("subsidy divisor and reduction interval must be " +
+			"positive")
+	case p.TicketsPerBlock == 0:
+		return fmt.Errorf("tickets per block must be positive")
+	case p.WorkRewardProportion+p.StakeRewardProportion+
+		p.BlockTaxProportion == 0:
+		return fmt.Errorf("subsidy proportions must not all be zero")
+	case p.WorkRewardProportionV2+p.StakeRewardProportionV2+
+		p.BlockTaxProportion == 0:
+		return fmt.Errorf("modified subsidy proportions must not all be zero")
+	case p.StakeValidationHeight < p.StakeEnabledHeight:
+		return fmt.Errorf("stake validation height must not be before the " +
+			"stake enabled height")
+	case p.StakeVersionInterval <= 0 || p.RuleChangeActivationInterval == 0:
+		return fmt.Errorf("stake version and rule change activation " +
+			"intervals must be positive")
+	case p.RuleChangeActivationDivisor == 0 || p.StakeMajorityDivisor <= 0:
+		return fmt.Errorf("rule change activation and stake majority " +
+			"divisors must be positive")
+	case p.TreasuryVoteInterval == 0:
+		return fmt.Errorf("treasury vote interval must be positive")
+	case !isValidAddressHRP(p.AddressHRPV1):
+		return fmt.Errorf("version 1 address human-readable part %q must be "+
+			"1 to %d lowercase ascii characters", p.AddressHRPV1,
+			maxAddressHRPLen)
+	}
+
+	for version, deployments := range p.Deployments {
+		index, err := validateDeployments(deployments)
+		if err != nil {
+			return fmt.Errorf("invalid agenda for version %d id %q: %w",
+				version, deployments[index].Vote.Id, err)
+		}
+		for _, deployment := range deployments {
+			if err := validateAgenda(deployment.Vote); err != nil {
+				return fmt.Errorf("invalid agenda for version %d id %q: %w",
+					version, deployment.Vote.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParamsFromJSON ensures the parameters of custom networks are loaded from
+// JSON definitions as expected.
+func TestParamsFromJSON(t *testing.T) {
+	// Create a genesis block that differs from the one of the base network.
+	genesis := *SimNetParams().GenesisBlock
+	genesis.Header.Timestamp = time.Unix(1700000000, 0)
+	genesisBytes, err := genesis.Bytes()
+	if err != nil {
+		t.Fatalf("failed to serialize genesis block: %v", err)
+	}
+	genesisHex := hex.EncodeToString(genesisBytes)
+
+	def := fmt.Sprintf(`{
+		"base": "simnet",
+		"name": "privnet",
+		"net": 305419896,
+		"defaultPort": "28108",
+		"seeders": ["seed.example.com"],
+		"genesisBlock": %q,
+		"targetTimePerBlock": "30s",
+		"workDiffWindowSize": 16,
+		"baseSubsidy": 100000000,
+		"subsidyReductionInterval": 512,
+		"blockOneLedger": [{"scriptVersion": 0, "script": "76a914", "amount": 5}],
+		"networkAddressPrefix": "P",
+		"pubKeyHashAddrID": "0e91",
+		"hdPublicKeyID": "0420bee1",
+		"addressHRPV1": "ps",
+		"ticketsPerBlock": 3,
+		"deployments": {"10": [{
+			"vote": {
+				"id": "testagenda",
+				"description": "Test agenda",
+				"mask": 6,
+				"choices": [
+					{"id": "abstain", "bits": 0, "isAbstain": true},
+					{"id": "no", "bits": 2, "isNo": true},
+					{"id": "yes", "bits": 4}
+				]
+			},
+			"startTime": 1,
+			"expireTime": 2
+		}]},
+		"blockSignChallenge": "51"
+	}`, genesisHex)
+	p, err := ParamsFromJSON([]byte(def))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure the specified parameters are set and the derived ones updated.
+	base := SimNetParams()
+	if p.Name != "privnet" || p.Net != 305419896 || p.DefaultPort != "28108" {
+		t.Fatalf("mismatched identity: got %s, %d, %s", p.Name, p.Net,
+			p.DefaultPort)
+	}
+	if !reflect.DeepEqual(p.Seeders(), []string{"seed.example.com"}) {
+		t.Fatalf("mismatched seeders: got %v", p.Seeders())
+	}
+	if p.GenesisHash != genesis.BlockHash() || p.GenesisHash == base.GenesisHash {
+		t.Fatalf("mismatched genesis hash: got %v, want %v", p.GenesisHash,
+			genesis.BlockHash())
+	}
+	if p.TargetTimePerBlock != 30*time.Second || p.WorkDiffWindowSize != 16 ||
+		p.TargetTimespan != 8*time.Minute {
+
+		t.Fatalf("mismatched timing: got %v, %d, %v", p.TargetTimePerBlock,
+			p.WorkDiffWindowSize, p.TargetTimespan)
+	}
+	if p.BaseSubsidy != 100000000 || p.SubsidyReductionInterval != 512 {
+		t.Fatalf("mismatched subsidy: got %d, %d", p.BaseSubsidy,
+			p.SubsidyReductionInterval)
+	}
+	wantLedger := []TokenPayout{{Script: []byte{0x76, 0xa9, 0x14}, Amount: 5}}
+	if !reflect.DeepEqual(p.BlockOneLedger, wantLedger) {
+		t.Fatalf("mismatched block one ledger: got %+v", p.BlockOneLedger)
+	}
+	if p.NetworkAddressPrefix != "P" || p.PubKeyHashAddrID != [2]byte{0x0e, 0x91} ||
+		p.HDPublicKeyID != [4]byte{0x04, 0x20, 0xbe, 0xe1} ||
+		p.AddrHRPV1() != "ps" {
+
+		t.Fatalf("mismatched address params: got %s, %x, %x, %s",
+			p.NetworkAddressPrefix, p.PubKeyHashAddrID, p.HDPublicKeyID,
+			p.AddrHRPV1())
+	}
+	if p.TicketsPerBlock != 3 {
+		t.Fatalf("mismatched tickets per block: got %d", p.TicketsPerBlock)
+	}
+	deployments := p.Deployments[10]
+	if len(p.Deployments) != 1 || len(deployments) != 1 ||
+		deployments[0].Vote.Id != "testagenda" ||
+		len(deployments[0].Vote.Choices) != 3 ||
+		!deployments[0].Vote.Choices[0].IsAbstain ||
+		deployments[0].ExpireTime != 2 {
+
+		t.Fatalf("mismatched deployments: got %+v", p.Deployments)
+	}
+	if !bytes.Equal(p.BlockSignChallenge, []byte{0x51}) {
+		t.Fatalf("mismatched block sign challenge: got %x",
+			p.BlockSignChallenge)
+	}
+
+	// Ensure the parameters that were not specified are taken from the base
+	// network.
+	if p.TicketPoolSize != base.TicketPoolSize ||
+		p.PubKeyAddrID != base.PubKeyAddrID ||
+		p.PowLimit.Cmp(base.PowLimit) != 0 ||
+		!bytes.Equal(p.OrganizationPkScript, base.OrganizationPkScript) {
+
+		t.Fatal("unspecified parameters differ from the base network")
+	}
+}
+
+// TestParamsFromJSONErrors ensures invalid custom network definitions are
+// rejected.
+func TestParamsFromJSONErrors(t *testing.T) {
+	const identity = `"base": "simnet", "name": "privnet", "net": 1, ` +
+		`"defaultPort": "28108"`
+	tests := []struct {
+		name    string
+		def     string
+		wantErr string
+	}{{
+		name:    "malformed json",
+		def:     `{`,
+		wantErr: "invalid network definition",
+	}, {
+		name:    "unknown key",
+		def:     `{` + identity + `, "ticketPoolSise": 10}`,
+		wantErr: "unknown field",
+	}, {
+		name:    "unknown base network",
+		def:     `{"base": "nonet", "name": "privnet", "net": 1, "defaultPort": "1"}`,
+		wantErr: "unknown base network",
+	}, {
+		name:    "missing name",
+		def:     `{"base": "simnet", "net": 1, "defaultPort": "28108"}`,
+		wantErr: "network name is not specified",
+	}, {
+		name:    "missing net",
+		def:     `{"base": "simnet", "name": "privnet", "defaultPort": "28108"}`,
+		wantErr: "network magic is not specified",
+	}, {
+		name:    "missing default port",
+		def:     `{"base": "simnet", "name": "privnet", "net": 1}`,
+		wantErr: "default port is not specified",
+	}, {
+		name: "standard network name",
+		def: `{"base": "simnet", "name": "testnet3", "net": 1, ` +
+			`"defaultPort": "28108"}`,
+		wantErr: "already used by a standard network",
+	}, {
+		name: "standard network magic",
+		def: fmt.Sprintf(`{"base": "simnet", "name": "privnet", "net": %d, `+
+			`"defaultPort": "28108"}`, SimNetParams().Net),
+		wantErr: "already used by the simnet network",
+	}, {
+		name:    "invalid genesis block",
+		def:     `{` + identity + `, "genesisBlock": "00"}`,
+		wantErr: "invalid genesis block",
+	}, {
+		name:    "invalid hex",
+		def:     `{` + identity + `, "organizationPkScript": "zz"}`,
+		wantErr: "invalid network definition",
+	}, {
+		name:    "invalid duration",
+		def:     `{` + identity + `, "targetTimePerBlock": "1 minute"}`,
+		wantErr: "invalid network definition",
+	}, {
+		name:    "wrong address magic length",
+		def:     `{` + identity + `, "scriptHashAddrID": "0e"}`,
+		wantErr: "scriptHashAddrID must be 2 bytes instead of 1",
+	}, {
+		name:    "genesis exceeds pow limit",
+		def:     `{` + identity + `, "powLimitBits": 469827583}`,
+		wantErr: "exceed the proof of work limit",
+	}, {
+		name:    "unknown pow hash algorithm",
+		def:     `{` + identity + `, "powHasher": "nohash"}`,
+		wantErr: "unknown proof of work hash algorithm",
+	}, {
+		name:    "zero tickets per block",
+		def:     `{` + identity + `, "ticketsPerBlock": 0}`,
+		wantErr: "tickets per block must be positive",
+	}, {
+		name: "zero modified subsidy proportions",
+		def: `{` + identity + `, "workRewardProportionV2": 0, ` +
+			`"stakeRewardProportionV2": 0, "blockTaxProportion": 0}`,
+		wantErr: "modified subsidy proportions must not all be zero",
+	}, {
+		name:    "uppercase address hrp",
+		def:     `{` + identity + `, "addressHRPV1": "PS"}`,
+		wantErr: "must be 1 to 18 lowercase ascii characters",
+	}, {
+		name:    "empty address hrp",
+		def:     `{` + identity + `, "addressHRPV1": ""}`,
+		wantErr: "must be 1 to 18 lowercase ascii characters",
+	}, {
+		name: "duplicate agenda",
+		def: `{` + identity + `, "deployments": {"1": [` +
+			`{"vote": {"id": "a"}}, {"vote": {"id": "A"}}]}}`,
+		wantErr: errDuplicateVoteId.Error(),
+	}, {
+		name: "invalid agenda",
+		def: `{` + identity + `, "deployments": {"1": [` +
+			`{"vote": {"id": "a", "mask": 5}}]}}`,
+		wantErr: errInvalidMask.Error(),
+	}}
+
+	for _, test := range tests {
+		_, err := ParamsFromJSON([]byte(test.def))
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%q: unexpected error: got %v, want error containing %q",
+				test.name, err, test.wantErr)
+		}
+	}
+}
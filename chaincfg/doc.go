@@ -56,4 +56,21 @@
 // Params struct may be created which defines the parameters for the
 // non-standard network.  As a general rule of thumb, all network parameters
 // should be unique to the network, but parameter collisions can still occur.
+//
+// Alternatively, ParamsFromJSON creates the parameters of such a network, for
+// example a private network, from a JSON definition that overrides the
+// parameters of one of the standard networks.  This allows applications to
+// support custom networks that are defined at runtime without modifying the
+// source.
+//
+// Custom networks may also specify a block signing challenge, which is a script
+// that must be satisfied by a signature over every block.  This allows
+// long-lived public test networks where only the holders of the keys that
+// satisfy the challenge are able to produce blocks.
+//
+// Research networks may use an alternative proof-of-work hash algorithm by
+// implementing the PowHasher interface and either setting it in the PowHasher
+// field of the parameters or registering it with RegisterPowHasher and
+// selecting it by name in the JSON definition.  All standard networks use
+// BLAKE-256.
 package chaincfg
@@ -402,6 +402,7 @@ func TestRequiredUnique(t *testing.T) {
 		pkhEdwardsAddrIDs = make(map[[2]byte]struct{})
 		pkhSchnorrAddrIDs = make(map[[2]byte]struct{})
 		scriptHashAddrIDs = make(map[[2]byte]struct{})
+		addressHRPsV1     = make(map[string]struct{})
 	)
 
 	for _, params := range allDefaultNetParams() {
@@ -446,5 +447,11 @@ func TestRequiredUnique(t *testing.T) {
 				params.ScriptHashAddrID)
 		}
 		scriptHashAddrIDs[params.ScriptHashAddrID] = struct{}{}
+
+		if _, ok := addressHRPsV1[params.AddressHRPV1]; ok {
+			t.Fatalf("%q: duplicate version 1 address hrp %s", params.Name,
+				params.AddressHRPV1)
+		}
+		addressHRPsV1[params.AddressHRPV1] = struct{}{}
 	}
 }
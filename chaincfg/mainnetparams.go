@@ -438,6 +438,7 @@ func MainNetParams() *Params {
 		PKHSchnorrAddrID:     [2]byte{0x07, 0x01}, // starts with DS
 		ScriptHashAddrID:     [2]byte{0x07, 0x1a}, // starts with Dc
 		PrivateKeyID:         [2]byte{0x22, 0xde}, // starts with Pm
+		AddressHRPV1:         "ds",
 
 		// BIP32 hierarchical deterministic extended key magics
 		HDPrivateKeyID: [4]byte{0x02, 0xfd, 0xa4, 0xe8}, // starts with dprv
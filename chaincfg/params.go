@@ -241,6 +241,11 @@ type Params struct {
 	// block in compact form.
 	PowLimitBits uint32
 
+	// PowHasher defines the proof of work hash algorithm of the network.
+	// BLAKE-256 is used when it is nil, which is the case for all standard
+	// networks.  See PowHashAlgorithm and PowHash.
+	PowHasher PowHasher
+
 	// ReduceMinDifficulty defines whether the network should reduce the
 	// minimum required difficulty after a long enough period of time has
 	// passed without finding a block.  This is really only useful for test
@@ -420,6 +425,10 @@ type Params struct {
 	ScriptHashAddrID [2]byte // First 2 bytes of a P2SH address
 	PrivateKeyID     [2]byte // First 2 bytes of a WIF private key
 
+	// AddressHRPV1 is the human-readable part used in the bech32m encoding of
+	// version 1 addresses.
+	AddressHRPV1 string
+
 	// BIP32 hierarchical deterministic extended key magics
 	HDPrivateKeyID [4]byte
 	HDPublicKeyID  [4]byte
@@ -560,6 +569,15 @@ type Params struct {
 	// window defined by TreasuryExpenditurePolicy.
 	TreasuryExpenditureBootstrap uint64
 
+	// BlockSignChallenge is the script that must be satisfied by a
+	// signature over every block after the genesis block.  The signature is
+	// housed by a provably pruneable output of the coinbase.  This allows
+	// test networks where only the holders of the keys that satisfy the
+	// challenge are able to produce blocks.  It is empty for networks that
+	// do not require blocks to be signed, which includes all standard
+	// networks.
+	BlockSignChallenge []byte
+
 	// seeders defines a list of seeders for the network that are used
 	// as one method to discover peers.
 	seeders []string
@@ -610,6 +628,12 @@ func (p *Params) AddrIDScriptHashV0() [2]byte {
 	return p.ScriptHashAddrID
 }
 
+// AddrHRPV1 returns the human-readable part used in the bech32m encoding of
+// version 1 addresses.
+func (p *Params) AddrHRPV1() string {
+	return p.AddressHRPV1
+}
+
 // BaseSubsidyValue returns the starting base max potential subsidy amount for
 // mined blocks.  This value is reduced over time and then split proportionally
 // between PoW, PoS, and the Treasury.  The reduction is controlled by the
@@ -676,6 +700,26 @@ func (p *Params) TreasurySubsidyProportion() uint16 {
 	return p.BlockTaxProportion
 }
 
+// WorkSubsidyProportionV2 returns the comparative proportion of the subsidy
+// generated for creating a block (PoW) using the proportions defined in
+// DCP0010.  See the documentation for WorkSubsidyProportion for more details on
+// how the parameter is used.
+//
+// This allows the subsidy calculations of the standalone package of the
+// blockchain module to use the modified split of the network, such as that of a
+// custom network, instead of the hard coded values defined in DCP0010.
+func (p *Params) WorkSubsidyProportionV2() uint16 {
+	return p.WorkRewardProportionV2
+}
+
+// StakeSubsidyProportionV2 returns the comparative proportion of the subsidy
+// generated for casting stake votes (collectively, per block) using the
+// proportions defined in DCP0010.  See the documentation for
+// WorkSubsidyProportion for more details on how the parameter is used.
+func (p *Params) StakeSubsidyProportionV2() uint16 {
+	return p.StakeRewardProportionV2
+}
+
 // VotesPerBlock returns the maximum number of votes a block must contain to
 // receive full subsidy.
 func (p *Params) VotesPerBlock() uint16 {
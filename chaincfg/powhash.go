@@ -0,0 +1,116 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// PowHasher defines the interface for proof-of-work hash algorithms.  It
+// allows research and test networks to use alternative proof-of-work
+// algorithms without modifying the code that validates and mines blocks.
+//
+// The resulting hash is interpreted as a little-endian unsigned 256-bit number
+// and compared against the target difficulty encoded in the block header in
+// the same way regardless of the algorithm.
+//
+// Implementations must be safe for concurrent access.
+type PowHasher interface {
+	// Name returns the unique name of the algorithm.  It is used to select
+	// the algorithm in custom network definitions.
+	Name() string
+
+	// PowHash returns the proof-of-work hash of the provided serialized
+	// block header.
+	PowHash(header []byte) chainhash.Hash
+}
+
+// Blake256PowHashName is the name of the BLAKE-256 proof-of-work hash
+// algorithm used by all standard networks.
+const Blake256PowHashName = "blake256"
+
+// blake256PowHasher implements the PowHasher interface for BLAKE-256 where the
+// proof-of-work hash is the block hash.
+type blake256PowHasher struct{}
+
+// Name returns the name of the algorithm.
+//
+// This is part of the PowHasher interface.
+func (blake256PowHasher) Name() string {
+	return Blake256PowHashName
+}
+
+// PowHash returns the BLAKE-256 hash of the provided serialized block header.
+//
+// This is part of the PowHasher interface.
+func (blake256PowHasher) PowHash(header []byte) chainhash.Hash {
+	return chainhash.HashH(header)
+}
+
+var (
+	// powHashersMtx protects powHashers.
+	powHashersMtx sync.RWMutex
+
+	// powHashers houses the registered proof-of-work hash algorithms keyed
+	// by their name.
+	powHashers = map[string]PowHasher{
+		Blake256PowHashName: blake256PowHasher{},
+	}
+)
+
+// RegisterPowHasher registers the provided proof-of-work hash algorithm so
+// that it may be selected by name in custom network definitions.  An error is
+// returned when an algorithm with the same name is already registered.
+//
+// This function is safe for concurrent access.
+func RegisterPowHasher(hasher PowHasher) error {
+	powHashersMtx.Lock()
+	defer powHashersMtx.Unlock()
+
+	name := hasher.Name()
+	if _, ok := powHashers[name]; ok {
+		return fmt.Errorf("proof-of-work hash algorithm %q is already "+
+			"registered", name)
+	}
+	powHashers[name] = hasher
+	return nil
+}
+
+// PowHasherByName returns the registered proof-of-work hash algorithm with the
+// provided name and whether or not it exists.
+//
+// This function is safe for concurrent access.
+func PowHasherByName(name string) (PowHasher, bool) {
+	powHashersMtx.RLock()
+	hasher, ok := powHashers[name]
+	powHashersMtx.RUnlock()
+	return hasher, ok
+}
+
+// PowHashAlgorithm returns the proof-of-work hash algorithm of the network the
+// parameters define.  This is BLAKE-256 when the PowHasher field is not set.
+func (p *Params) PowHashAlgorithm() PowHasher {
+	if p.PowHasher == nil {
+		return blake256PowHasher{}
+	}
+	return p.PowHasher
+}
+
+// PowHash returns the proof-of-work hash of the provided block header using the
+// proof-of-work hash algorithm of the network the parameters define.
+func (p *Params) PowHash(header *wire.BlockHeader) chainhash.Hash {
+	if p.PowHasher == nil {
+		return header.BlockHash()
+	}
+
+	// Serializing a block header can't fail since it writes to a buffer and
+	// all of its fields are fixed size.
+	headerBytes, _ := header.Bytes()
+	return p.PowHasher.PowHash(headerBytes)
+}
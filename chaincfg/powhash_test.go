@@ -0,0 +1,72 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// reversedPowHasher is a proof-of-work hash algorithm for testing that returns
+// the BLAKE-256 hash of the reversed serialized header.
+type reversedPowHasher struct{}
+
+func (reversedPowHasher) Name() string {
+	return "reversed"
+}
+
+func (reversedPowHasher) PowHash(header []byte) chainhash.Hash {
+	reversed := make([]byte, len(header))
+	for i, b := range header {
+		reversed[len(header)-1-i] = b
+	}
+	return chainhash.HashH(reversed)
+}
+
+// TestPowHasher ensures the proof-of-work hash algorithm defaults to BLAKE-256,
+// that alternative algorithms can be registered and selected by custom network
+// definitions, and that duplicate registrations are rejected.
+func TestPowHasher(t *testing.T) {
+	// Ensure the standard networks use BLAKE-256 where the proof-of-work hash
+	// is the block hash.
+	for _, params := range standardNetParams() {
+		if name := params.PowHashAlgorithm().Name(); name != Blake256PowHashName {
+			t.Fatalf("%s: unexpected pow hash algorithm %q", params.Name, name)
+		}
+		header := &params.GenesisBlock.Header
+		if got := params.PowHash(header); got != params.GenesisHash {
+			t.Fatalf("%s: unexpected pow hash -- got %v, want %v",
+				params.Name, got, params.GenesisHash)
+		}
+	}
+
+	// Ensure registering an algorithm with an existing name fails.
+	if err := RegisterPowHasher(blake256PowHasher{}); err == nil {
+		t.Fatal("registering duplicate pow hash algorithm did not fail")
+	}
+
+	// Register an alternative algorithm and ensure custom networks use it.
+	if err := RegisterPowHasher(reversedPowHasher{}); err != nil {
+		t.Fatalf("unexpected error registering pow hash algorithm: %v", err)
+	}
+	p, err := ParamsFromJSON([]byte(`{"base": "simnet", "name": "privnet", ` +
+		`"net": 1, "defaultPort": "28108", "powHasher": "reversed"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name := p.PowHashAlgorithm().Name(); name != "reversed" {
+		t.Fatalf("unexpected pow hash algorithm %q", name)
+	}
+	header := &p.GenesisBlock.Header
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("failed to serialize header: %v", err)
+	}
+	want := reversedPowHasher{}.PowHash(headerBytes)
+	if got := p.PowHash(header); got != want || got == p.GenesisHash {
+		t.Fatalf("unexpected pow hash -- got %v, want %v", got, want)
+	}
+}
@@ -434,6 +434,7 @@ func RegNetParams() *Params {
 		PKHSchnorrAddrID:     [2]byte{0x0d, 0xc2}, // starts with RS
 		ScriptHashAddrID:     [2]byte{0x0d, 0xdb}, // starts with Rc
 		PrivateKeyID:         [2]byte{0x22, 0xfe}, // starts with Pr
+		AddressHRPV1:         "rs",
 
 		// BIP32 hierarchical deterministic extended key magics
 		HDPrivateKeyID: [4]byte{0xea, 0xb4, 0x04, 0x48}, // starts with rprv
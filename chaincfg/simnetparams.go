@@ -154,6 +154,7 @@ func SimNetParams() *Params {
 		PKHSchnorrAddrID:     [2]byte{0x0e, 0x53}, // starts with SS
 		ScriptHashAddrID:     [2]byte{0x0e, 0x6c}, // starts with Sc
 		PrivateKeyID:         [2]byte{0x23, 0x07}, // starts with Ps
+		AddressHRPV1:         "ss",
 
 		// BIP32 hierarchical deterministic extended key magics
 		HDPrivateKeyID: [4]byte{0x04, 0x20, 0xb9, 0x03}, // starts with sprv
@@ -354,6 +354,7 @@ func TestNet3Params() *Params {
 		PKHSchnorrAddrID:     [2]byte{0x0e, 0xe3}, // starts with TS
 		ScriptHashAddrID:     [2]byte{0x0e, 0xfc}, // starts with Tc
 		PrivateKeyID:         [2]byte{0x23, 0x0e}, // starts with Pt
+		AddressHRPV1:         "ts",
 
 		// BIP32 hierarchical deterministic extended key magics
 		HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x97}, // starts with tprv
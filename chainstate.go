@@ -0,0 +1,192 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// optionalIndex describes an optional index that is able to be verified and
+// rebuilt independently of the rest of the chain state.
+type optionalIndex struct {
+	id      string
+	name    string
+	enabled func(*config) bool
+	verify  func(context.Context, database.DB, indexers.ChainQueryer) error
+	drop    func(context.Context, database.DB) error
+}
+
+// optionalIndexes houses all of the optional indexes in the order they are
+// verified.
+var optionalIndexes = []optionalIndex{{
+	id:      "txindex",
+	name:    "transaction index",
+	enabled: func(cfg *config) bool { return cfg.TxIndex },
+	verify:  indexers.VerifyTxIndex,
+	drop:    indexers.DropTxIndex,
+}, {
+	id:      "existsaddrindex",
+	name:    "exists address index",
+	enabled: func(cfg *config) bool { return !cfg.NoExistsAddrIndex },
+	verify:  indexers.VerifyExistsAddrIndex,
+	drop:    indexers.DropExistsAddrIndex,
+}, {
+	id:      "addrindex",
+	name:    "address index",
+	enabled: func(cfg *config) bool { return cfg.AddrIndex },
+	verify:  indexers.VerifyAddrIndex,
+	drop:    indexers.DropAddrIndex,
+}, {
+	id:      "spentindex",
+	name:    "spent output index",
+	enabled: func(cfg *config) bool { return cfg.SpentIndex },
+	verify:  indexers.VerifySpentIndex,
+	drop:    indexers.DropSpentIndex,
+}}
+
+// lookupOptionalIndex returns the optional index identified by the provided
+// id, which is the name of the option that enables it, such as "txindex".  An
+// error that lists the supported ids is returned when there is no such index.
+func lookupOptionalIndex(id string) (*optionalIndex, error) {
+	ids := make([]string, 0, len(optionalIndexes))
+	for i := range optionalIndexes {
+		if optionalIndexes[i].id == id {
+			return &optionalIndexes[i], nil
+		}
+		ids = append(ids, optionalIndexes[i].id)
+	}
+	return nil, fmt.Errorf("unknown optional index %q -- supported indexes "+
+		"are %v", id, ids)
+}
+
+// loadChain loads the chain from the provided databases for the purposes of
+// verifying it.
+func loadChain(ctx context.Context, db database.DB, utxoDb *leveldb.DB, params *chaincfg.Params) (*blockchain.BlockChain, error) {
+	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	chain, err := blockchain.New(ctx, &blockchain.Config{
+		DB:          db,
+		UtxoBackend: utxoBackend,
+		ChainParams: params,
+		TimeSource:  blockchain.NewMedianTime(),
+		UtxoCache: blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
+			Backend:      utxoBackend,
+			FlushBlockDB: db.Flush,
+			MaxSize:      uint64(cfg.UtxoCacheMaxSize) * 1024 * 1024,
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the chain state: %w", err)
+	}
+	return chain, nil
+}
+
+// verifyOptionalIndex loads the chain from the provided databases and verifies
+// the provided optional index is consistent with the main chain, logging the
+// first divergence found in it.  The index is not modified regardless of the
+// result.
+//
+// An error is returned when the index is damaged.
+func verifyOptionalIndex(ctx context.Context, db database.DB, utxoDb *leveldb.DB, params *chaincfg.Params, idx *optionalIndex) error {
+	chain, err := loadChain(ctx, db, utxoDb, params)
+	if err != nil {
+		return err
+	}
+
+	best := chain.BestSnapshot()
+	dcrdLog.Infof("Verifying the %s against the main chain up to block %s "+
+		"(height %d).  This might take a while...", idx.name, best.Hash,
+		best.Height)
+	queryer := &blockchain.ChainQueryerAdapter{BlockChain: chain}
+	err = idx.verify(ctx, db, queryer)
+	if errors.Is(err, indexers.ErrIndexCorruption) {
+		dcrdLog.Errorf("Optional index divergence: %v", err)
+		return fmt.Errorf("the %s is damaged (it may be rebuilt with "+
+			"--rebuildindex=%s)", idx.name, idx.id)
+	}
+	if err != nil {
+		return err
+	}
+	dcrdLog.Infof("The %s is consistent", idx.name)
+	return nil
+}
+
+// verifyChainState loads the chain from the provided databases and verifies
+// the block index, the utxo set, and every optional index are consistent with
+// each other, logging the first divergence found in each of them.
+//
+// Damaged optional indexes are dropped when repair is set so they are rebuilt
+// from the main chain the next time they are enabled.  The block index and
+// utxo set are not able to be repaired selectively, so they require the chain
+// to be resynced when they are damaged.
+//
+// An error is returned when any damage remains after the verification.
+func verifyChainState(ctx context.Context, db database.DB, utxoDb *leveldb.DB, params *chaincfg.Params, repair bool) error {
+	chain, err := loadChain(ctx, db, utxoDb, params)
+	if err != nil {
+		return err
+	}
+
+	best := chain.BestSnapshot()
+	dcrdLog.Infof("Verifying the block index and utxo set up to block %s "+
+		"(height %d).  This might take a while...", best.Hash, best.Height)
+	var damaged []string
+	err = chain.VerifyChainState(ctx)
+	switch {
+	case errors.Is(err, blockchain.ErrBlockIndexCorruption):
+		// The optional indexes are verified against the main chain, so there
+		// is nothing more to verify when it is damaged.
+		dcrdLog.Errorf("Block index divergence: %v", err)
+		return errors.New("the block index is damaged and the chain must " +
+			"be resynced")
+
+	case errors.Is(err, blockchain.ErrUtxoBackendCorruption):
+		dcrdLog.Errorf("Utxo set divergence: %v", err)
+		damaged = append(damaged, "utxo set")
+
+	case err != nil:
+		return err
+
+	default:
+		dcrdLog.Infof("The block index and utxo set are consistent")
+	}
+
+	queryer := &blockchain.ChainQueryerAdapter{BlockChain: chain}
+	for _, idx := range optionalIndexes {
+		err := idx.verify(ctx, db, queryer)
+		if !errors.Is(err, indexers.ErrIndexCorruption) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		dcrdLog.Errorf("Optional index divergence: %v", err)
+		if !repair {
+			damaged = append(damaged, idx.name)
+			continue
+		}
+		if err := idx.drop(ctx, db); err != nil {
+			return err
+		}
+		dcrdLog.Infof("Dropped the damaged %s.  It will be rebuilt the next "+
+			"time it is enabled", idx.name)
+	}
+
+	if len(damaged) > 0 {
+		return fmt.Errorf("chain state verification found damage in: %v "+
+			"(optional indexes may be rebuilt with --repairindexes while the "+
+			"utxo set requires the chain to be resynced)", damaged)
+	}
+	dcrdLog.Infof("Chain state verification complete")
+	return nil
+}
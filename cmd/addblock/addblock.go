@@ -87,7 +87,7 @@ func realMain() error {
 
 	// Load the UTXO database.
 	utxoDb, err := blockchain.LoadUtxoDB(context.Background(), activeNetParams,
-		cfg.DataDir)
+		cfg.DataDir, nil)
 	if err != nil {
 		log.Errorf("Failed to load UTXO database: %v", err)
 		return err
@@ -42,6 +42,8 @@ type config struct {
 	InFile            string `short:"i" long:"infile" description:"File containing the block(s)"`
 	NoExistsAddrIndex bool   `long:"noexistsaddrindex" description:"Do not build a full index of which addresses were ever seen on the blockchain"`
 	TxIndex           bool   `long:"txindex" description:"Build a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
+	AddrIndex         bool   `long:"addrindex" description:"Build a full address-based transaction index which makes the transactions involving an address available via the searchrawtransactions RPC"`
+	SpentIndex        bool   `long:"spentindex" description:"Build a spent output index which makes the transaction input that spends an output available via the getspentinfo RPC"`
 	Progress          int    `short:"p" long:"progress" description:"Show a progress message each time this number of seconds have passed -- Use 0 to disable progress announcements"`
 }
 
@@ -55,6 +55,8 @@ type blockImporter struct {
 
 	txIndex         *indexers.TxIndex
 	existsAddrIndex *indexers.ExistsAddrIndex
+	addrIndex       *indexers.AddrIndex
+	spentIndex      *indexers.SpentIndex
 	cancel          context.CancelFunc
 }
 
@@ -341,6 +343,8 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 	// Create the various indexes as needed.
 	var txIndex *indexers.TxIndex
 	var existsAddrIndex *indexers.ExistsAddrIndex
+	var addrIndex *indexers.AddrIndex
+	var spentIndex *indexers.SpentIndex
 	if cfg.TxIndex {
 		log.Info("Transaction index is enabled")
 
@@ -356,6 +360,20 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 			return nil, err
 		}
 	}
+	if cfg.AddrIndex {
+		log.Info("Address index is enabled")
+		addrIndex, err = indexers.NewAddrIndex(subber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SpentIndex {
+		log.Info("Spent output index is enabled")
+		spentIndex, err = indexers.NewSpentIndex(subber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	err = subber.CatchUp(ctx, db, queryer)
 	if err != nil {
@@ -374,6 +392,8 @@ func newBlockImporter(ctx context.Context, db database.DB, utxoDb *leveldb.DB, r
 		startTime:       time.Now(),
 		txIndex:         txIndex,
 		existsAddrIndex: existsAddrIndex,
+		addrIndex:       addrIndex,
+		spentIndex:      spentIndex,
 		cancel:          cancel,
 	}, nil
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/schnorr"
+	"github.com/decred/dcrd/txscript/v4"
+	flags "github.com/jessevdk/go-flags"
+)
+
+// testVectorsVersion is the version of the JSON format of the generated test
+// vector files.  It must be increased whenever the format changes in a way
+// that is not backwards compatible.
+const testVectorsVersion = 1
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+	os.Exit(1)
+}
+
+type config struct {
+	Seed  string `short:"s" description:"seed the test vectors are deterministically derived from"`
+	Count int    `short:"n" description:"number of test vectors to generate per file"`
+	Force bool   `short:"f" description:"overwrite existing files"`
+}
+
+// testVectorFile is the stable JSON format of the generated test vector files.
+type testVectorFile struct {
+	Version int         `json:"version"`
+	Kind    string      `json:"kind"`
+	Seed    string      `json:"seed"`
+	Vectors interface{} `json:"vectors"`
+}
+
+// writeVectors writes the provided test vectors to the named file in the
+// output directory.
+func writeVectors(dir, name, kind, seed string, vectors interface{}, force bool) error {
+	path := filepath.Join(dir, name)
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flag |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(&testVectorFile{
+		Version: testVectorsVersion,
+		Kind:    kind,
+		Seed:    seed,
+		Vectors: vectors,
+	})
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func main() {
+	cfg := config{
+		Seed:  "dcrd test vectors",
+		Count: 64,
+	}
+	parser := flags.NewParser(&cfg, flags.Default)
+	parser.Usage = "[OPTIONS] outdir"
+	args, err := parser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) {
+			if e.Type != flags.ErrHelp {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+	if len(args) != 1 {
+		parser.WriteHelp(os.Stderr)
+		os.Exit(2)
+	}
+	if cfg.Count <= 0 {
+		fatalf("count must be positive\n")
+	}
+	dir := args[0]
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fatalf("%v\n", err)
+	}
+
+	seed := []byte(cfg.Seed)
+	files := []struct {
+		name    string
+		kind    string
+		vectors interface{}
+	}{
+		{"schnorr.json", "schnorr", schnorr.GenerateTestVectors(seed, cfg.Count)},
+		{"ecdh.json", "ecdh", secp256k1.GenerateECDHTestVectors(seed, cfg.Count)},
+		{"sighash.json", "sighash", txscript.GenerateSigHashTestVectors(seed, cfg.Count)},
+	}
+	for _, file := range files {
+		err := writeVectors(dir, file.name, file.kind, cfg.Seed, file.vectors,
+			cfg.Force)
+		if err != nil {
+			fatalf("%v\n", err)
+		}
+	}
+}
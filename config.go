@@ -10,10 +10,13 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
+	"os/exec"
 	"os/user"
 	"path/filepath"
 	"regexp"
@@ -25,9 +28,14 @@ import (
 
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	_ "github.com/decred/dcrd/database/v3/ffldb"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/logging"
+	"github.com/decred/dcrd/internal/logrotate"
 	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/rpcserver"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/sampleconfig"
@@ -44,24 +52,48 @@ const (
 	defaultLogDirname       = "logs"
 	defaultLogFilename      = "dcrd.log"
 	defaultLogSize          = "10M"
+	defaultLogMaxRolls      = 3
 	defaultDbType           = "ffldb"
 	defaultLogLevel         = "info"
+	defaultLogFormat        = "text"
 	defaultSigCacheMaxSize  = 100000
 	defaultUtxoCacheMaxSize = 150
 	minUtxoCacheMaxSize     = 25
 	maxUtxoCacheMaxSize     = 32768 // 32 GiB
+	maxDBWriteBuffer        = 1024  // 1 GiB
+	maxDBCompactTrigger     = 64
+	maxDBCompactTable       = 1024 // 1 GiB
 
 	// Defaults for RPC server options and policy.
 	defaultTLSCurve             = "P-256"
+	defaultTLSCertLifetime      = 10 * 365 * 24 * time.Hour
+	defaultTLSCertRenewal       = 30 * 24 * time.Hour
+	minTLSCertLifetime          = 24 * time.Hour
 	defaultMaxRPCClients        = 10
 	defaultMaxRPCWebsockets     = 25
 	defaultMaxRPCConcurrentReqs = 20
 
+	// defaultMetricsPort is the default port of the metrics listeners.
+	defaultMetricsPort = "9190"
+
+	// defaultDiagPort is the default port of the diagnostics listeners.
+	defaultDiagPort = "9191"
+
+	// defaultTracingSampleRatio is the default ratio of blocks and
+	// transactions that are traced when tracing is enabled.
+	defaultTracingSampleRatio = 1.0
+
 	// Defaults for P2P network options.
 	defaultMaxSameIP       = 5
 	defaultMaxPeers        = 125
 	defaultDialTimeout     = time.Second * 30
 	defaultPeerIdleTimeout = time.Second * 120
+	defaultTrickleInterval = time.Millisecond * 500
+	minTrickleInterval     = time.Millisecond * 10
+
+	// noProxy is the value of the network-specific proxy options that
+	// indicates connections to the network are made directly.
+	noProxy = "none"
 
 	// Defaults for banning options.
 	defaultBanDuration  = time.Hour * 24
@@ -69,6 +101,7 @@ const (
 
 	// Defaults for relay and mempool policy options.
 	defaultMaxOrphanTransactions = 100
+	defaultMaxOrphanTxsPerPeer   = 25
 	defaultAllowOldVotes         = false
 
 	// Defaults for mining options and policy.
@@ -81,6 +114,8 @@ const (
 	// Defaults for indexing options.
 	defaultTxIndex           = false
 	defaultNoExistsAddrIndex = false
+	defaultAddrIndex         = false
+	defaultSpentIndex        = false
 
 	// Authorization types.
 	authTypeBasic      = "basic"
@@ -111,43 +146,80 @@ var runServiceCommand func(string) error
 // See loadConfig for details on the configuration load process.
 type config struct {
 	// General application behavior.
-	ShowVersion      bool   `short:"V" long:"version" description:"Display version information and exit"`
-	HomeDir          string `short:"A" long:"appdata" description:"Path to application home directory"`
-	ConfigFile       string `short:"C" long:"configfile" description:"Path to configuration file"`
-	DataDir          string `short:"b" long:"datadir" description:"Directory to store data"`
-	LogDir           string `long:"logdir" description:"Directory to log output"`
-	LogSize          string `long:"logsize" description:"Maximum size of log file before it is rotated"`
-	NoFileLogging    bool   `long:"nofilelogging" description:"Disable file logging"`
-	DbType           string `long:"dbtype" description:"Database backend to use for the block chain"`
-	Profile          string `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
-	CPUProfile       string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
-	MemProfile       string `long:"memprofile" description:"Write mem profile to the specified file"`
-	TestNet          bool   `long:"testnet" description:"Use the test network"`
-	SimNet           bool   `long:"simnet" description:"Use the simulation test network"`
-	RegNet           bool   `long:"regnet" description:"Use the regression test network"`
-	DebugLevel       string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	SigCacheMaxSize  uint   `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
-	UtxoCacheMaxSize uint   `long:"utxocachemaxsize" description:"The maximum size in MiB of the utxo cache; (min: 25, max: 32768)"`
+	ShowVersion        bool   `short:"V" long:"version" description:"Display version information and exit"`
+	HomeDir            string `short:"A" long:"appdata" description:"Path to application home directory"`
+	ConfigFile         string `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir            string `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir             string `long:"logdir" description:"Directory to log output"`
+	LogSize            string `long:"logsize" description:"Maximum size of log file before it is rotated"`
+	NoFileLogging      bool   `long:"nofilelogging" description:"Disable file logging"`
+	LogFormat          string `long:"logformat" description:"Format of log output {text, json}"`
+	DbType             string `long:"dbtype" description:"Database backend to use for the block chain"`
+	Profile            string `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
+	CPUProfile         string `long:"cpuprofile" description:"Write CPU profile to the specified file"`
+	MemProfile         string `long:"memprofile" description:"Write mem profile to the specified file"`
+	TestNet            bool   `long:"testnet" description:"Use the test network"`
+	SimNet             bool   `long:"simnet" description:"Use the simulation test network"`
+	RegNet             bool   `long:"regnet" description:"Use the regression test network"`
+	CustomNet          string `long:"customnet" description:"Use the custom network, such as a private network, defined by the specified JSON file"`
+	DebugLevel         string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	SigCacheMaxSize    uint   `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
+	UtxoCacheMaxSize   uint   `long:"utxocachemaxsize" description:"The maximum size in MiB of the utxo cache; (min: 25, max: 32768)"`
+	DBWriteBuffer      uint   `long:"dbwritebuffer" description:"The size in MiB of writes the block and utxo databases buffer in memory before writing them to disk; 0 uses the backend default (max: 1024)"`
+	DBMaxOpenFiles     uint   `long:"dbmaxopenfiles" description:"The maximum number of files the block and utxo databases each keep open; 0 uses the backend default"`
+	DBCompactTrigger   uint   `long:"dbcompacttrigger" description:"The number of level-0 tables that triggers a compaction of the block and utxo databases; 0 uses the backend default (max: 64)"`
+	DBCompactTable     uint   `long:"dbcompacttablesize" description:"The size in MiB of the tables produced by compactions of the block and utxo databases; 0 uses the backend default (max: 1024)"`
+	DBKeyFile          string `long:"dbkeyfile" description:"Encrypt the block and utxo databases at rest with the hex-encoded 32-byte key in the specified file -- NOTE: Encryption must be enabled when the databases are created"`
+	DBKeyCmd           string `long:"dbkeycmd" description:"Encrypt the block and utxo databases at rest with the hex-encoded 32-byte key printed by the specified command, such as one that reads it from the OS keyring -- NOTE: The command and its arguments are separated by spaces"`
+	DBBlockFilesPerDir uint   `long:"dbblockfilesperdir" description:"Store the block files in subdirectories that each house the specified number of files; 0 stores them directly in the block database directory -- NOTE: This must not be changed once set"`
+	DBColdDir          string `long:"dbcolddir" description:"Move older block files to the specified directory, typically on cheaper and slower storage, while keeping the most recent ones in the data directory"`
+	DBHotBlockFiles    uint   `long:"dbhotblockfiles" description:"The number of the most recent block files to keep in the data directory when dbcolddir is set; 0 uses the backend default"`
+	DBOpMetrics        bool   `long:"dbopmetrics" description:"Track the number, latency, and value sizes of block database operations by bucket for the RPC metrics endpoint"`
+
+	// Log rotation and retention options.
+	LogRotateInterval time.Duration `long:"logrotateinterval" description:"Interval at which the log file is rotated regardless of its size, such as 24h -- Rotations are aligned to multiples of the interval in UTC -- 0 disables time-based rotation"`
+	NoLogCompression  bool          `long:"nologcompression" description:"Disable gzip compression of rotated log files"`
+	LogMaxRolls       int           `long:"logmaxrolls" description:"Maximum number of rotated log files to keep -- 0 keeps all of them"`
+	LogMaxAge         time.Duration `long:"logmaxage" description:"Maximum age of rotated log files to keep, such as 720h -- 0 disables the age limit"`
+	LogMaxTotalSize   string        `long:"logmaxtotalsize" description:"Maximum total size of rotated log files to keep, such as 1G -- The oldest ones are removed first -- 0 disables the size limit"`
 
 	// RPC server options and policy.
-	DisableRPC           bool     `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
-	RPCListeners         []string `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
-	RPCUser              string   `short:"u" long:"rpcuser" description:"Username for RPC connections"`
-	RPCPass              string   `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
-	RPCAuthType          string   `long:"authtype" description:"Method for RPC client authentication (basic or clientcert)"`
-	RPCClientCAs         string   `long:"clientcafile" description:"File containing Certificate Authorities to verify TLS client certificates; requires authtype=clientcert"`
-	RPCLimitUser         string   `long:"rpclimituser" description:"Username for limited RPC connections"`
-	RPCLimitPass         string   `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
-	RPCCert              string   `long:"rpccert" description:"File containing the certificate file"`
-	RPCKey               string   `long:"rpckey" description:"File containing the certificate key"`
-	TLSCurve             string   `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
-	AltDNSNames          []string `long:"altdnsnames" description:"Specify additional DNS names to use when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
-	DisableTLS           bool     `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
-	RPCMaxClients        int      `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
-	RPCMaxWebsockets     int      `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
-	RPCMaxConcurrentReqs int      `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
-
-	// P2P proxy and Tor settings.
+	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
+	RPCUnixListeners     []string      `long:"rpclistenunix" description:"Add a unix domain socket path to listen for RPC connections on without TLS -- NOTE: Access is controlled by the permissions of the socket, which is only accessible by the owner, and connections do not require the RPC credentials"`
+	RPCUser              string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
+	RPCPass              string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	RPCAuthType          string        `long:"authtype" description:"Method for RPC client authentication (basic or clientcert)"`
+	RPCClientCAs         string        `long:"clientcafile" description:"File containing Certificate Authorities to verify TLS client certificates; requires authtype=clientcert"`
+	RPCLimitUser         string        `long:"rpclimituser" description:"Username for limited RPC connections"`
+	RPCLimitPass         string        `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
+	RPCAuth              []string      `long:"rpcauth" default-mask:"-" description:"Add an RPC user restricted to the listed methods and websocket notification types in the form user:pass:methods:notifications where each list is comma-separated and * allows all -- NOTE: Entries in the config file are reloaded on SIGUSR1"`
+	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
+	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
+	TLSCurve             string        `long:"tlscurve" description:"Curve to use when generating TLS keypairs (P-256, P-521, or Ed25519)"`
+	TLSCertLifetime      time.Duration `long:"tlscertlifetime" description:"Validity period of generated TLS certificates.  Valid time units are {m, h}.  Minimum 24 hours"`
+	TLSCertRenewal       time.Duration `long:"tlscertrenewal" description:"Automatically regenerate autogenerated TLS certificates when they expire within this duration and notify websocket clients of the new certificate (0 = disabled)"`
+	AltDNSNames          []string      `long:"altdnsnames" description:"Specify additional DNS names or IP addresses to use as subject alternative names when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
+	DisableTLS           bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
+	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
+	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCUserReqRate       float64       `long:"rpcuserreqrate" description:"Max number of RPC requests per second per authenticated user (0 = no limit)"`
+	RPCIPReqRate         float64       `long:"rpcipreqrate" description:"Max number of RPC requests per second per remote IP (0 = no limit)"`
+	RPCUserMaxReqs       int           `long:"rpcusermaxreqs" description:"Max number of RPC requests per authenticated user that may be processed concurrently (0 = no limit)"`
+	RPCIPMaxReqs         int           `long:"rpcipmaxreqs" description:"Max number of RPC requests per remote IP that may be processed concurrently (0 = no limit)"`
+	RPCSlowCall          time.Duration `long:"rpcslowcall" description:"Log RPC calls that take at least this long along with the client that issued them and their redacted parameters (0 = disabled)"`
+	EnableREST           bool          `long:"rest" description:"Enable the REST interface for blocks, headers, and unspent outputs on the RPC listeners -- NOTE: REST requests do not require the RPC credentials"`
+	RESTToken            string        `long:"resttoken" default-mask:"-" description:"Token REST clients must provide via a bearer authorization header; requires --rest"`
+	GRPCListeners        []string      `long:"grpclisten" description:"Add an interface/port to listen for gRPC connections using the same credentials and TLS settings as the RPC server -- NOTE: The gRPC server is disabled unless at least one address is specified (default port: 9112, testnet: 19112)"`
+	MetricsListeners     []string      `long:"metricslisten" description:"Add an interface/port to serve block validation, mempool, peer, RPC, and database metrics in the Prometheus text exposition format via HTTP at /metrics -- NOTE: Addresses other than loopback addresses require --metricsuser and --metricspass and the metrics are disabled unless at least one address is specified (default port: 9190)"`
+	MetricsUser          string        `long:"metricsuser" description:"Username metrics clients must provide via HTTP basic authentication"`
+	MetricsPass          string        `long:"metricspass" default-mask:"-" description:"Password metrics clients must provide via HTTP basic authentication"`
+	DiagListeners        []string      `long:"diaglisten" description:"Add an interface/port to serve pprof profiles, runtime metrics, and goroutine dumps via HTTP at /debug/ using the same admin credentials and TLS settings as the RPC server -- NOTE: Diagnostics are disabled unless at least one address is specified (default port: 9191)"`
+	TracingEndpoint      string        `long:"tracingendpoint" description:"Export traces of block download, validation, connection, and notification and of transaction acceptance and relay to the OTLP/HTTP traces endpoint of an OpenTelemetry collector at the specified URL (eg. http://127.0.0.1:4318/v1/traces) -- NOTE: Tracing is disabled unless an endpoint is specified"`
+	TracingSampleRatio   float64       `long:"tracingsampleratio" description:"The ratio of blocks and transactions that are traced when tracing is enabled (0-1)"`
+
+	// P2P proxy, Tor, and I2P settings.
 	Proxy          string `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser      string `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass      string `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
@@ -156,29 +228,47 @@ type config struct {
 	OnionProxyPass string `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
 	NoOnion        bool   `long:"noonion" description:"Disable connecting to tor hidden services"`
 	TorIsolation   bool   `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection"`
+	IPv4Proxy      string `long:"ipv4proxy" description:"Connect to IPv4 peers via SOCKS5 proxy instead of the one specified by --proxy -- Use 'none' to connect directly"`
+	IPv6Proxy      string `long:"ipv6proxy" description:"Connect to IPv6 peers via SOCKS5 proxy instead of the one specified by --proxy -- Use 'none' to connect directly"`
+	I2PSAM         string `long:"i2psam" description:"Connect to and accept connections from I2P peers via the SAM v3 bridge of an I2P router (eg. 127.0.0.1:7656)"`
 
 	// P2P network options.
-	AddPeers        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
-	ConnectPeers    []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	AddPeers        []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup, optionally prefixed with comma-separated permissions followed by @"`
+	ConnectPeers    []string      `long:"connect" description:"Connect only to the specified peers at startup, optionally prefixed with comma-separated permissions followed by @"`
 	DisableListen   bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
 	Listeners       []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
+	OutboundOnly    bool          `long:"outboundonly" description:"Never accept incoming connections, such as when behind a strict NAT -- NOTE: Implies --nolisten and may not be used with --listen, --upnp, or --natpmp"`
 	MaxSameIP       int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
 	MaxPeers        int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
 	DialTimeout     time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	PeerIdleTimeout time.Duration `long:"peeridletimeout" description:"The duration of inactivity before a peer is timed out.  Valid time units are {s,m,h}.  Minimum 15 seconds"`
+	TrickleInterval time.Duration `long:"trickleinterval" description:"The average interval between the randomly timed batches of transaction announcements sent to each outbound peer -- Inbound peers use twice the interval.  Valid time units are {ms,s,m,h}.  Minimum 10 milliseconds"`
+	P2PEncryption   bool          `long:"p2pencryption" description:"Encrypt connections with peers that support it in order to prevent passive network observers from fingerprinting traffic"`
+	ASMap           string        `long:"asmap" description:"Path to a file that maps IP prefixes to the autonomous systems that announce them which is used to spread outbound peers across network operators"`
+	EclipseResist   bool          `long:"eclipseresistance" description:"Persist connections to long-lived outbound peers (anchors) across restarts to make it harder for an attacker to monopolize outbound connections"`
+
+	// P2P bandwidth options.
+	MaxUploadRate           uint32 `long:"maxuploadrate" description:"Max combined rate to upload data to all peers in KiB/s -- 0 to disable"`
+	MaxDownloadRate         uint32 `long:"maxdownloadrate" description:"Max combined rate to download data from all peers in KiB/s -- 0 to disable"`
+	MaxInboundUploadRate    uint32 `long:"maxinbounduploadrate" description:"Max rate to upload data to each inbound peer in KiB/s -- 0 to disable"`
+	MaxInboundDownloadRate  uint32 `long:"maxinbounddownloadrate" description:"Max rate to download data from each inbound peer in KiB/s -- 0 to disable"`
+	MaxOutboundUploadRate   uint32 `long:"maxoutbounduploadrate" description:"Max rate to upload data to each outbound peer in KiB/s -- 0 to disable"`
+	MaxOutboundDownloadRate uint32 `long:"maxoutbounddownloadrate" description:"Max rate to download data from each outbound peer in KiB/s -- 0 to disable"`
 
 	// P2P network discovery options.
 	DisableSeeders bool     `long:"noseeders" description:"Disable seeding for peer discovery"`
+	Seeders        []string `long:"seeder" description:"Add an HTTPS seeder to query for peers before the default seeders, which are then only queried when more addresses are needed -- Prefix with a network name and a colon to only use it on that network (eg. testnet3:seed.example.com)"`
 	DisableDNSSeed bool     `long:"nodnsseed" description:"DEPRECATED: use --noseeders"`
 	ExternalIPs    []string `long:"externalip" description:"Add a public-facing IP to the list of local external IPs that dcrd will advertise to other peers"`
 	NoDiscoverIP   bool     `long:"nodiscoverip" description:"Disable automatic network address discovery of local external IPs"`
 	Upnp           bool     `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	NATPMP         bool     `long:"natpmp" description:"Use NAT-PMP or PCP to map our listening port outside of NAT when UPnP is not enabled or not available"`
 
 	// Banning options.
 	DisableBanning bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
 	BanDuration    time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	BanThreshold   uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers"`
-	Whitelists     []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned (eg. 192.168.1.0/24 or ::1)"`
+	Whitelists     []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned, optionally prefixed with comma-separated permissions followed by @ (eg. 192.168.1.0/24, ::1, or noban,mempool@10.0.0.0/8)"`
 
 	// Chain related options.
 	AllowOldForks  bool   `long:"allowoldforks" description:"Process forks deep in history.  Don't do this unless you know what you're doing"`
@@ -190,7 +280,9 @@ type config struct {
 	FreeTxRelayLimit float64 `long:"limitfreerelay" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	NoRelayPriority  bool    `long:"norelaypriority" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	MaxOrphanTxs     int     `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
-	BlocksOnly       bool    `long:"blocksonly" description:"Do not accept transactions from remote peers"`
+	MaxOrphanTxsPeer int     `long:"maxorphantxperpeer" description:"Max number of orphan transactions to keep in memory that were received from any single peer (0 to disable the per-peer limit)"`
+	BlocksOnly       bool    `long:"blocksonly" description:"Do not accept or request transactions from remote peers and ask them not to relay any -- NOTE: Transactions submitted via RPC are still relayed"`
+	TxReconciliation bool    `long:"txreconciliation" description:"Announce transactions to peers that support it via sketch-based set reconciliation instead of flooding in order to reduce bandwidth usage"`
 	AcceptNonStd     bool    `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network"`
 	RejectNonStd     bool    `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network"`
 	AllowOldVotes    bool    `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
@@ -198,6 +290,7 @@ type config struct {
 	// Mining options and policy.
 	Generate            bool     `long:"generate" description:"Generate (mine) coins using the CPU"`
 	MiningAddrs         []string `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks.  At least one address is required if the generate option is set"`
+	BlockSignKeyFile    string   `long:"blocksignkeyfile" description:"File containing the hex-encoded secp256k1 private key used to sign generated blocks on networks that require blocks to be signed"`
 	BlockMinSize        uint32   `long:"blockminsize" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
 	BlockMaxSize        uint32   `long:"blockmaxsize" description:"Maximum block size in bytes to be used when creating a block"`
 	BlockPrioritySize   uint32   `long:"blockprioritysize" description:"DEPRECATED: This behavior is no longer available and this option will be removed in a future version of the software"`
@@ -207,10 +300,18 @@ type config struct {
 	AllowUnsyncedMining bool     `long:"allowunsyncedmining" description:"Allow block templates to be generated even when the chain is not considered synced on networks other than the main network.  This is automatically enabled when the simnet option is set.  Don't do this unless you know what you're doing"`
 
 	// Indexing options.
-	TxIndex             bool `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
-	DropTxIndex         bool `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
-	NoExistsAddrIndex   bool `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
-	DropExistsAddrIndex bool `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
+	TxIndex             bool   `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
+	DropTxIndex         bool   `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits"`
+	NoExistsAddrIndex   bool   `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used"`
+	DropExistsAddrIndex bool   `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits"`
+	AddrIndex           bool   `long:"addrindex" description:"Maintain a full address-based transaction index which makes the transactions involving an address available via the searchrawtransactions RPC"`
+	DropAddrIndex       bool   `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits"`
+	SpentIndex          bool   `long:"spentindex" description:"Maintain a spent output index which makes the transaction input that spends an output available via the getspentinfo RPC"`
+	DropSpentIndex      bool   `long:"dropspentindex" description:"Deletes the spent output index from the database on start up and then exits"`
+	VerifyChainState    bool   `long:"verifychainstate" description:"Verifies the block index, utxo set, and optional indexes are consistent on start up, reports the first divergence in each, and then exits"`
+	RepairIndexes       bool   `long:"repairindexes" description:"Deletes any optional indexes found to be damaged by --verifychainstate so they are rebuilt on the next start"`
+	VerifyIndex         string `long:"verifyindex" description:"Verifies the named optional index (txindex, existsaddrindex, addrindex, or spentindex) is consistent with the main chain on start up without rebuilding it, reports the first divergence, and then exits"`
+	RebuildIndex        string `long:"rebuildindex" description:"Deletes the named optional index (txindex, existsaddrindex, addrindex, or spentindex) on start up so it is rebuilt from the main chain in the background without affecting the other indexes; the index must be enabled"`
 
 	// IPC options.
 	PipeRx          uint `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
@@ -219,17 +320,25 @@ type config struct {
 	BoundAddrEvents bool `long:"boundaddrevents" description:"Send notifications with the locally bound addresses of the P2P and RPC subsystems over the TX pipe"`
 
 	// Cooked options ready for use.
-	onionlookup   func(string) ([]net.IP, error)
-	lookup        func(string) ([]net.IP, error)
-	oniondial     func(context.Context, string, string) (net.Conn, error)
-	dial          func(context.Context, string, string) (net.Conn, error)
-	miningAddrs   []stdaddr.Address
-	minRelayTxFee dcrutil.Amount
-	whitelists    []*net.IPNet
-	ipv4NetInfo   types.NetworksResult
-	ipv6NetInfo   types.NetworksResult
-	onionNetInfo  types.NetworksResult
-	params        *params
+	onionlookup      func(string) ([]net.IP, error)
+	lookup           func(string) ([]net.IP, error)
+	oniondial        func(context.Context, string, string) (net.Conn, error)
+	dial             func(context.Context, string, string) (net.Conn, error)
+	ipv4dial         func(context.Context, string, string) (net.Conn, error)
+	ipv6dial         func(context.Context, string, string) (net.Conn, error)
+	miningAddrs      []stdaddr.Address
+	blockSignKey     *secp256k1.PrivateKey
+	dbOpts           *database.Options
+	minRelayTxFee    dcrutil.Amount
+	rpcUsers         []rpcserver.UserAuth
+	whitelists       []whitelistEntry
+	addPeerPerms     map[string]peerPermissions
+	connectPeerPerms map[string]peerPermissions
+	ipv4NetInfo      types.NetworksResult
+	ipv6NetInfo      types.NetworksResult
+	onionNetInfo     types.NetworksResult
+	i2pNetInfo       types.NetworksResult
+	params           *params
 }
 
 // serviceOptions defines the configuration options for the daemon as a service on
@@ -238,6 +347,103 @@ type serviceOptions struct {
 	ServiceCommand string `short:"s" long:"service" description:"Service command {install, remove, start, stop}"`
 }
 
+// parseRPCAuth parses the provided per-user RPC authorization entries.  Each
+// entry is of the form user:pass:methods:notifications where methods and
+// notifications are comma-separated allowlists that may be empty.  The
+// password may contain colons.
+func parseRPCAuth(entries []string) ([]rpcserver.UserAuth, error) {
+	splitList := func(list string) []string {
+		if list == "" {
+			return nil
+		}
+		return strings.Split(list, ",")
+	}
+
+	users := make([]rpcserver.UserAuth, 0, len(entries))
+	for _, entry := range entries {
+		userEnd := strings.IndexByte(entry, ':')
+		ntfnsStart := strings.LastIndexByte(entry, ':')
+		methodsStart := -1
+		if ntfnsStart > 0 {
+			methodsStart = strings.LastIndexByte(entry[:ntfnsStart], ':')
+		}
+		if userEnd == -1 || methodsStart <= userEnd {
+			// Avoid including the password in the error.
+			user := entry
+			if userEnd != -1 {
+				user = entry[:userEnd]
+			}
+			return nil, fmt.Errorf("invalid rpcauth entry for user %q: "+
+				"expected user:pass:methods:notifications", user)
+		}
+		users = append(users, rpcserver.UserAuth{
+			User:          entry[:userEnd],
+			Pass:          entry[userEnd+1 : methodsStart],
+			Methods:       splitList(entry[methodsStart+1 : ntfnsStart]),
+			Notifications: splitList(entry[ntfnsStart+1:]),
+		})
+	}
+	return users, nil
+}
+
+// loadRPCAuth reads and parses the per-user RPC authorization entries from the
+// provided config file.  All other options in the file are ignored.
+func loadRPCAuth(configFile string) ([]rpcserver.UserAuth, error) {
+	var fileCfg struct {
+		RPCAuth []string `long:"rpcauth"`
+	}
+	parser := flags.NewParser(&fileCfg, flags.IgnoreUnknown)
+	err := flags.NewIniParser(parser).ParseFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseRPCAuth(fileCfg.RPCAuth)
+}
+
+// runDBKeyCmd runs the provided command, which is made up of the program and
+// its arguments separated by spaces, and parses the hex-encoded database
+// encryption key it prints to stdout.  This allows the key to be stored in a
+// keyring, such as with secret-tool on Linux or security on macOS, rather than
+// in a file.
+func runDBKeyCmd(command string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("no command specified")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return dbcrypt.ParseKey(string(output))
+}
+
+// loadBlockSignKey reads the hex-encoded secp256k1 private key used to sign
+// generated blocks from the provided file.  Leading and trailing whitespace is
+// ignored.
+func loadBlockSignKey(keyFile string) (*secp256k1.PrivateKey, error) {
+	contents, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := hex.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if len(keyBytes) != secp256k1.PrivKeyBytesLen {
+		return nil, fmt.Errorf("private key must be %d bytes instead of %d",
+			secp256k1.PrivKeyBytesLen, len(keyBytes))
+	}
+	var keyScalar secp256k1.ModNScalar
+	if overflow := keyScalar.SetByteSlice(keyBytes); overflow ||
+		keyScalar.IsZero() {
+
+		return nil, errors.New("private key is out of range")
+	}
+	return secp256k1.NewPrivateKey(&keyScalar), nil
+}
+
 // cleanAndExpandPath expands environment variables and leading ~ in the
 // passed path, cleans the result, and returns it.
 func cleanAndExpandPath(path string) string {
@@ -312,6 +518,43 @@ func supportedSubsystems() []string {
 	return subsystems
 }
 
+// parseLogSize parses the provided log size, which is either 0 or a number
+// followed by one of the units k, K, KiB, m, M, MiB, g, G, or GiB, and returns
+// it in bytes along with whether or not it is valid.
+func parseLogSize(s string) (int64, bool) {
+	if s == "0" {
+		return 0, true
+	}
+
+	var units int
+	for i, r := range s {
+		if r < '0' || r > '9' {
+			units = i
+			break
+		}
+	}
+	if units == 0 {
+		return 0, false
+	}
+	// Parsing a 32-bit number prevents 64-bit overflow after unit
+	// multiplication.
+	size, err := strconv.ParseInt(s[:units], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	switch s[units:] {
+	case "k", "K", "KiB":
+		size <<= 10
+	case "m", "M", "MiB":
+		size <<= 20
+	case "g", "G", "GiB":
+		size <<= 30
+	default:
+		return 0, false
+	}
+	return size, true
+}
+
 // parseAndSetDebugLevels attempts to parse the specified debug level and set
 // the levels accordingly.  An appropriate error is returned if anything is
 // invalid.
@@ -444,6 +687,21 @@ func normalizeAddresses(addrs []string, defaultPort string, flags int) []string
 	return removeDuplicateAddresses(norm)
 }
 
+// isLoopbackAddr returns whether or not the host of the provided address,
+// which must include a port, is a loopback address.  Hosts other than
+// localhost are not resolved, so only IP addresses are otherwise considered.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // fileExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -516,7 +774,7 @@ func createDefaultConfigFile(destPath string, authType string) error {
 // available networks.
 func (cfg *config) generateNetworkInfo() []types.NetworksResult {
 	return []types.NetworksResult{cfg.ipv4NetInfo, cfg.ipv6NetInfo,
-		cfg.onionNetInfo}
+		cfg.onionNetInfo, cfg.i2pNetInfo}
 }
 
 // parseNetworkInterfaces updates all network interface states based on the
@@ -544,7 +802,8 @@ func parseNetworkInterfaces(cfg *config) error {
 		ipv4 := &cfg.ipv4NetInfo
 		ipv4.Reachable = !cfg.DisableListen
 		ipv4.Limited = v6Addrs == 0
-		ipv4.Proxy = cfg.Proxy
+		ipv4.Proxy = cfg.IPv4Proxy
+		ipv4.ProxyRandomizeCredentials = cfg.IPv4Proxy != "" && cfg.TorIsolation
 	}
 
 	// Set IPV6 interface state.
@@ -552,7 +811,8 @@ func parseNetworkInterfaces(cfg *config) error {
 		ipv6 := &cfg.ipv6NetInfo
 		ipv6.Reachable = !cfg.DisableListen
 		ipv6.Limited = v4Addrs == 0
-		ipv6.Proxy = cfg.Proxy
+		ipv6.Proxy = cfg.IPv6Proxy
+		ipv6.ProxyRandomizeCredentials = cfg.IPv6Proxy != "" && cfg.TorIsolation
 	}
 
 	// Set Onion interface state.
@@ -567,6 +827,13 @@ func parseNetworkInterfaces(cfg *config) error {
 		onion.ProxyRandomizeCredentials = cfg.TorIsolation
 	}
 
+	// Set I2P interface state.
+	if cfg.I2PSAM != "" {
+		i2p := &cfg.i2pNetInfo
+		i2p.Reachable = !cfg.DisableListen
+		i2p.Proxy = cfg.I2PSAM
+	}
+
 	return nil
 }
 
@@ -601,6 +868,9 @@ func loadConfig(appName string) (*config, []string, error) {
 		DataDir:          defaultDataDir,
 		LogDir:           defaultLogDir,
 		LogSize:          defaultLogSize,
+		LogMaxRolls:      defaultLogMaxRolls,
+		LogMaxTotalSize:  "0",
+		LogFormat:        defaultLogFormat,
 		DbType:           defaultDbType,
 		DebugLevel:       defaultLogLevel,
 		SigCacheMaxSize:  defaultSigCacheMaxSize,
@@ -612,24 +882,29 @@ func loadConfig(appName string) (*config, []string, error) {
 		RPCAuthType:          defaultRPCAuthType,
 		RPCClientCAs:         defaultRPCClientCAs,
 		TLSCurve:             defaultTLSCurve,
+		TLSCertLifetime:      defaultTLSCertLifetime,
+		TLSCertRenewal:       defaultTLSCertRenewal,
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		TracingSampleRatio:   defaultTracingSampleRatio,
 
 		// P2P network options.
 		MaxSameIP:       defaultMaxSameIP,
 		MaxPeers:        defaultMaxPeers,
 		DialTimeout:     defaultDialTimeout,
 		PeerIdleTimeout: defaultPeerIdleTimeout,
+		TrickleInterval: defaultTrickleInterval,
 
 		// Banning options.
 		BanDuration:  defaultBanDuration,
 		BanThreshold: defaultBanThreshold,
 
 		// Relay and mempool policy.
-		MinRelayTxFee: mempool.DefaultMinRelayTxFee.ToCoin(),
-		MaxOrphanTxs:  defaultMaxOrphanTransactions,
-		AllowOldVotes: defaultAllowOldVotes,
+		MinRelayTxFee:    mempool.DefaultMinRelayTxFee.ToCoin(),
+		MaxOrphanTxs:     defaultMaxOrphanTransactions,
+		MaxOrphanTxsPeer: defaultMaxOrphanTxsPerPeer,
+		AllowOldVotes:    defaultAllowOldVotes,
 
 		// Mining options and policy.
 		Generate:            defaultGenerate,
@@ -640,11 +915,14 @@ func loadConfig(appName string) (*config, []string, error) {
 		// Indexing options.
 		TxIndex:           defaultTxIndex,
 		NoExistsAddrIndex: defaultNoExistsAddrIndex,
+		AddrIndex:         defaultAddrIndex,
+		SpentIndex:        defaultSpentIndex,
 
 		// Cooked options ready for use.
 		ipv4NetInfo:  types.NetworksResult{Name: "IPV4"},
 		ipv6NetInfo:  types.NetworksResult{Name: "IPV6"},
 		onionNetInfo: types.NetworksResult{Name: "Onion"},
+		i2pNetInfo:   types.NetworksResult{Name: "I2P"},
 		params:       &mainNetParams,
 	}
 
@@ -810,9 +1088,20 @@ func loadConfig(appName string) (*config, []string, error) {
 		numNets++
 		cfg.params = &regNetParams
 	}
+	if cfg.CustomNet != "" {
+		numNets++
+		cfg.CustomNet = cleanAndExpandPath(cfg.CustomNet)
+		customNetParams, err := loadCustomNetParams(cfg.CustomNet)
+		if err != nil {
+			str := "%s: failed to load custom network %q: %v"
+			err := fmt.Errorf(str, funcName, cfg.CustomNet, err)
+			return nil, nil, err
+		}
+		cfg.params = customNetParams
+	}
 	if numNets > 1 {
-		str := "%s: the testnet, regnet, and simnet params can't be " +
-			"used together -- choose one of the three"
+		str := "%s: the testnet, regnet, simnet, and customnet params " +
+			"can't be used together -- choose one of the four"
 		err := fmt.Errorf(str, funcName)
 		return nil, nil, err
 	}
@@ -872,41 +1161,49 @@ func loadConfig(appName string) (*config, []string, error) {
 		cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
 		cfg.LogDir = filepath.Join(cfg.LogDir, cfg.params.Name)
 
-		var units int
-		for i, r := range cfg.LogSize {
-			if r < '0' || r > '9' {
-				units = i
-				break
-			}
-		}
-		invalidSize := func() (*config, []string, error) {
+		logSize, ok := parseLogSize(cfg.LogSize)
+		if !ok || logSize == 0 {
 			str := "%s: Invalid logsize: %v "
 			err := fmt.Errorf(str, funcName, cfg.LogSize)
 			return nil, nil, err
 		}
-		if units == 0 {
-			return invalidSize()
-		}
-		// Parsing a 32-bit number prevents 64-bit overflow after unit
-		// multiplication.
-		logsize, err := strconv.ParseInt(cfg.LogSize[:units], 10, 32)
-		if err != nil {
-			return invalidSize()
+		logMaxTotalSize, ok := parseLogSize(cfg.LogMaxTotalSize)
+		if !ok {
+			str := "%s: Invalid logmaxtotalsize: %v "
+			err := fmt.Errorf(str, funcName, cfg.LogMaxTotalSize)
+			return nil, nil, err
 		}
-		switch cfg.LogSize[units:] {
-		case "k", "K", "KiB":
-		case "m", "M", "MiB":
-			logsize <<= 10
-		case "g", "G", "GiB":
-			logsize <<= 20
-		default:
-			return invalidSize()
+		if cfg.LogRotateInterval < 0 || cfg.LogMaxAge < 0 ||
+			cfg.LogMaxRolls < 0 {
+
+			str := "%s: The logrotateinterval, logmaxage, and logmaxrolls " +
+				"options may not be negative"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
 		}
 
 		// Initialize log rotation.  After log rotation has been initialized, the
 		// logger variables may be used.
-		initLogRotator(filepath.Join(cfg.LogDir, defaultLogFilename), logsize)
+		initLogRotator(&logrotate.Config{
+			Filename:     filepath.Join(cfg.LogDir, defaultLogFilename),
+			MaxSize:      logSize,
+			Interval:     cfg.LogRotateInterval,
+			Compress:     !cfg.NoLogCompression,
+			MaxRolls:     cfg.LogMaxRolls,
+			MaxAge:       cfg.LogMaxAge,
+			MaxTotalSize: logMaxTotalSize,
+		})
+	}
+
+	// Validate and set the log format.
+	logFormat, ok := logging.ParseFormat(cfg.LogFormat)
+	if !ok {
+		str := "%s: invalid log format %q -- supported formats are text " +
+			"and json"
+		err := fmt.Errorf(str, funcName, cfg.LogFormat)
+		return nil, nil, err
 	}
+	backendLog.SetFormat(logFormat)
 
 	// Special show command to list supported subsystems and exit.
 	if cfg.DebugLevel == "show" {
@@ -935,6 +1232,77 @@ func loadConfig(appName string) (*config, []string, error) {
 		cfg.UtxoCacheMaxSize = maxUtxoCacheMaxSize
 	}
 
+	// Enforce the maximum database tuning options and convert them to the
+	// format the database backends expect.
+	if cfg.DBWriteBuffer > maxDBWriteBuffer {
+		str := "%s: the dbwritebuffer option may not be more than %d " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, maxDBWriteBuffer, cfg.DBWriteBuffer)
+		return nil, nil, err
+	}
+	if cfg.DBCompactTrigger > maxDBCompactTrigger {
+		str := "%s: the dbcompacttrigger option may not be more than %d " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, maxDBCompactTrigger,
+			cfg.DBCompactTrigger)
+		return nil, nil, err
+	}
+	if cfg.DBCompactTable > maxDBCompactTable {
+		str := "%s: the dbcompacttablesize option may not be more than %d " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, maxDBCompactTable, cfg.DBCompactTable)
+		return nil, nil, err
+	}
+	cfg.dbOpts = &database.Options{
+		WriteBufferSize:     int(cfg.DBWriteBuffer) * 1024 * 1024,
+		MaxOpenFiles:        int(cfg.DBMaxOpenFiles),
+		CompactionL0Trigger: int(cfg.DBCompactTrigger),
+		CompactionTableSize: int(cfg.DBCompactTable) * 1024 * 1024,
+	}
+
+	// Load the database encryption key when encryption at rest is enabled.
+	if cfg.DBKeyFile != "" && cfg.DBKeyCmd != "" {
+		str := "%s: the dbkeyfile and dbkeycmd options may not be " +
+			"specified together"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	switch {
+	case cfg.DBKeyFile != "":
+		cfg.DBKeyFile = cleanAndExpandPath(cfg.DBKeyFile)
+		key, err := dbcrypt.LoadKeyFile(cfg.DBKeyFile)
+		if err != nil {
+			err := fmt.Errorf("%s: %w", funcName, err)
+			return nil, nil, err
+		}
+		cfg.dbOpts.EncryptionKey = key
+
+	case cfg.DBKeyCmd != "":
+		key, err := runDBKeyCmd(cfg.DBKeyCmd)
+		if err != nil {
+			err := fmt.Errorf("%s: unable to obtain the database encryption "+
+				"key from dbkeycmd: %w", funcName, err)
+			return nil, nil, err
+		}
+		cfg.dbOpts.EncryptionKey = key
+	}
+
+	// Configure where the block files are stored.  The cold directory is
+	// namespaced per network the same way as the data directory.
+	if cfg.DBHotBlockFiles != 0 && cfg.DBColdDir == "" {
+		str := "%s: the dbhotblockfiles option requires the dbcolddir " +
+			"option"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	cfg.dbOpts.BlockFilesPerDir = int(cfg.DBBlockFilesPerDir)
+	cfg.dbOpts.HotBlockFiles = int(cfg.DBHotBlockFiles)
+	if cfg.DBColdDir != "" {
+		cfg.DBColdDir = cleanAndExpandPath(cfg.DBColdDir)
+		cfg.dbOpts.ColdBlockDir = filepath.Join(cfg.DBColdDir,
+			cfg.params.Name)
+	}
+
 	// Validate format of profile, can be an address:port, or just a port.
 	if cfg.Profile != "" {
 		// if profile is just a number, then add a default host of "127.0.0.1" such that Profile is a valid tcp address
@@ -980,18 +1348,34 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Ensure the trickle interval is not too short.
+	if cfg.TrickleInterval < minTrickleInterval {
+		str := "%s: the trickleinterval option may not be less than %v " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, minTrickleInterval,
+			cfg.TrickleInterval)
+		return nil, nil, err
+	}
+
 	// Validate any given whitelisted IP addresses and networks.
 	if len(cfg.Whitelists) > 0 {
 		var ip net.IP
-		cfg.whitelists = make([]*net.IPNet, 0, len(cfg.Whitelists))
+		cfg.whitelists = make([]whitelistEntry, 0, len(cfg.Whitelists))
 
-		for _, addr := range cfg.Whitelists {
+		for _, entry := range cfg.Whitelists {
+			perms, addr, err := splitPermissions(entry,
+				defaultWhitelistPermissions)
+			if err != nil {
+				str := "%s: the whitelist value of '%s' is invalid: %v"
+				err = fmt.Errorf(str, funcName, entry, err)
+				return nil, nil, err
+			}
 			_, ipnet, err := net.ParseCIDR(addr)
 			if err != nil {
 				ip = net.ParseIP(addr)
 				if ip == nil {
 					str := "%s: the whitelist value of '%s' is invalid"
-					err = fmt.Errorf(str, funcName, addr)
+					err = fmt.Errorf(str, funcName, entry)
 					return nil, nil, err
 				}
 				var bits int
@@ -1006,7 +1390,10 @@ func loadConfig(appName string) (*config, []string, error) {
 					Mask: net.CIDRMask(bits, bits),
 				}
 			}
-			cfg.whitelists = append(cfg.whitelists, ipnet)
+			cfg.whitelists = append(cfg.whitelists, whitelistEntry{
+				ipnet: ipnet,
+				perms: perms,
+			})
 		}
 	}
 
@@ -1018,6 +1405,24 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --outboundonly never accepts incoming connections, so it does not mix
+	// with options that only apply to listening.
+	if cfg.OutboundOnly {
+		if len(cfg.Listeners) > 0 {
+			str := "%s: the --outboundonly and --listen options can not " +
+				"be mixed"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		if cfg.Upnp || cfg.NATPMP {
+			str := "%s: the --outboundonly option can not be used with " +
+				"--upnp or --natpmp"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		cfg.DisableListen = true
+	}
+
 	// --proxy or --connect without --listen disables listening.
 	if (cfg.Proxy != "" || len(cfg.ConnectPeers) > 0) &&
 		len(cfg.Listeners) == 0 {
@@ -1029,6 +1434,9 @@ func loadConfig(appName string) (*config, []string, error) {
 		cfg.DisableSeeders = true
 	}
 
+	// Only use the additional seeders that apply to the active network.
+	cfg.Seeders = filterSeeders(cfg.Seeders, cfg.params.Name)
+
 	// Add the default listener if none were specified. The default
 	// listener is all addresses on the listen port for the network
 	// we are to connect to.
@@ -1054,12 +1462,34 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Parse the per-user RPC authorization entries.
+	cfg.rpcUsers, err = parseRPCAuth(cfg.RPCAuth)
+	if err != nil {
+		err := fmt.Errorf("%s: %w", funcName, err)
+		return nil, nil, err
+	}
+
 	// The RPC server is disabled if no username or password is provided
-	// under basic user/pass authentication.
-	if cfg.RPCAuthType == authTypeBasic &&
+	// under basic user/pass authentication unless it listens on unix domain
+	// sockets, which rely on filesystem permissions instead.  It may only
+	// listen on unix domain sockets in that case since connections on any
+	// other listeners would not require authentication.
+	noRPCCreds := cfg.RPCAuthType == authTypeBasic &&
 		(cfg.RPCUser == "" || cfg.RPCPass == "") &&
-		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
-		cfg.DisableRPC = true
+		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") &&
+		len(cfg.rpcUsers) == 0
+	if noRPCCreds {
+		switch {
+		case len(cfg.RPCUnixListeners) == 0:
+			cfg.DisableRPC = true
+
+		case !cfg.DisableRPC && (len(cfg.RPCListeners) > 0 ||
+			len(cfg.GRPCListeners) > 0):
+			str := "%s: the --rpclisten and --grpclisten options require " +
+				"RPC credentials"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
 	}
 
 	// Check to make sure RPC usernames and passwords are not provided under
@@ -1067,7 +1497,8 @@ func loadConfig(appName string) (*config, []string, error) {
 	if cfg.RPCAuthType == authTypeClientCert {
 		switch {
 		case cfg.RPCUser != "", cfg.RPCPass != "",
-			cfg.RPCLimitUser != "", cfg.RPCLimitPass != "":
+			cfg.RPCLimitUser != "", cfg.RPCLimitPass != "",
+			len(cfg.rpcUsers) != 0:
 			str := "%s: RPC usernames and passwords are not allowed " +
 				"with --authtype=clientcert"
 			err := fmt.Errorf(str, funcName)
@@ -1075,8 +1506,10 @@ func loadConfig(appName string) (*config, []string, error) {
 		}
 	}
 
-	// Default RPC to listen on localhost only.
-	if !cfg.DisableRPC && len(cfg.RPCListeners) == 0 {
+	// Default RPC to listen on localhost only.  There are no default RPC
+	// listeners when there are no credentials since the RPC server is only
+	// enabled in that case when it listens on unix domain sockets.
+	if !cfg.DisableRPC && len(cfg.RPCListeners) == 0 && !noRPCCreds {
 		addrs, err := net.LookupHost("localhost")
 		if err != nil {
 			return nil, nil, err
@@ -1095,6 +1528,28 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Validate the per-client RPC request limits.
+	if cfg.RPCUserReqRate < 0 || cfg.RPCIPReqRate < 0 {
+		str := "%s: the rpcuserreqrate and rpcipreqrate options may not be " +
+			"less than 0 -- parsed [%v] and [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCUserReqRate, cfg.RPCIPReqRate)
+		return nil, nil, err
+	}
+	if cfg.RPCUserMaxReqs < 0 || cfg.RPCIPMaxReqs < 0 {
+		str := "%s: the rpcusermaxreqs and rpcipmaxreqs options may not be " +
+			"less than 0 -- parsed [%d] and [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCUserMaxReqs, cfg.RPCIPMaxReqs)
+		return nil, nil, err
+	}
+
+	// Validate the slow RPC call threshold.
+	if cfg.RPCSlowCall < 0 {
+		str := "%s: the rpcslowcall option may not be less than 0 -- " +
+			"parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCSlowCall)
+		return nil, nil, err
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = dcrutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -1139,6 +1594,12 @@ func loadConfig(appName string) (*config, []string, error) {
 		err := fmt.Errorf(str, funcName, cfg.MaxOrphanTxs)
 		return nil, nil, err
 	}
+	if cfg.MaxOrphanTxsPeer < 0 {
+		str := "%s: the maxorphantxperpeer option may not be less than 0 " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.MaxOrphanTxsPeer)
+		return nil, nil, err
+	}
 
 	// --txindex and --droptxindex do not mix.
 	if cfg.TxIndex && cfg.DropTxIndex {
@@ -1155,6 +1616,66 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --addrindex and --dropaddrindex do not mix.
+	if cfg.AddrIndex && cfg.DropAddrIndex {
+		err := fmt.Errorf("%s: the --addrindex and --dropaddrindex "+
+			"options may not be activated at the same time",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --spentindex and --dropspentindex do not mix.
+	if cfg.SpentIndex && cfg.DropSpentIndex {
+		err := fmt.Errorf("%s: the --spentindex and --dropspentindex "+
+			"options may not be activated at the same time",
+			funcName)
+		return nil, nil, err
+	}
+
+	// --repairindexes requires --verifychainstate.
+	if cfg.RepairIndexes && !cfg.VerifyChainState {
+		err := fmt.Errorf("%s: the --repairindexes option requires "+
+			"--verifychainstate", funcName)
+		return nil, nil, err
+	}
+
+	// --verifyindex and --rebuildindex must name a known optional index and
+	// do not mix with each other or --verifychainstate.
+	if cfg.VerifyIndex != "" {
+		if _, err := lookupOptionalIndex(cfg.VerifyIndex); err != nil {
+			err := fmt.Errorf("%s: invalid --verifyindex option: %w",
+				funcName, err)
+			return nil, nil, err
+		}
+		if cfg.VerifyChainState || cfg.RebuildIndex != "" {
+			err := fmt.Errorf("%s: the --verifyindex option may not be "+
+				"activated at the same time as --verifychainstate or "+
+				"--rebuildindex", funcName)
+			return nil, nil, err
+		}
+	}
+	if cfg.RebuildIndex != "" {
+		idx, err := lookupOptionalIndex(cfg.RebuildIndex)
+		if err != nil {
+			err := fmt.Errorf("%s: invalid --rebuildindex option: %w",
+				funcName, err)
+			return nil, nil, err
+		}
+		if cfg.VerifyChainState {
+			err := fmt.Errorf("%s: the --rebuildindex and "+
+				"--verifychainstate options may not be activated at the "+
+				"same time", funcName)
+			return nil, nil, err
+		}
+
+		// Rebuilding an index that is not enabled would only drop it.
+		if !idx.enabled(&cfg) {
+			err := fmt.Errorf("%s: the --rebuildindex=%s option requires "+
+				"the %s to be enabled", funcName, idx.id, idx.name)
+			return nil, nil, err
+		}
+	}
+
 	// Check mining addresses are valid and saved parsed versions.
 	cfg.miningAddrs = make([]stdaddr.Address, 0, len(cfg.MiningAddrs))
 	for _, strAddr := range cfg.MiningAddrs {
@@ -1176,6 +1697,25 @@ func loadConfig(appName string) (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Load the key used to sign generated blocks when specified.  It is only
+	// valid on networks that require blocks to be signed.
+	if cfg.BlockSignKeyFile != "" {
+		if len(cfg.params.BlockSignChallenge) == 0 {
+			str := "%s: the blocksignkeyfile option is only valid on " +
+				"networks that require blocks to be signed"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		cfg.BlockSignKeyFile = cleanAndExpandPath(cfg.BlockSignKeyFile)
+		key, err := loadBlockSignKey(cfg.BlockSignKeyFile)
+		if err != nil {
+			str := "%s: unable to load block signing key from %q: %w"
+			err := fmt.Errorf(str, funcName, cfg.BlockSignKeyFile, err)
+			return nil, nil, err
+		}
+		cfg.blockSignKey = key
+	}
+
 	// Don't allow unsynchronized mining on mainnet.
 	if cfg.AllowUnsyncedMining && cfg.params == &mainNetParams {
 		str := "%s: allowunsyncedmining cannot be activated on mainnet"
@@ -1198,6 +1738,103 @@ func loadConfig(appName string) (*config, []string, error) {
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners,
 		cfg.params.rpcPort, normalizeInterfaceAddrs)
 
+	// Expand the rpc unix domain socket paths and remove duplicates.
+	for i, path := range cfg.RPCUnixListeners {
+		cfg.RPCUnixListeners[i] = cleanAndExpandPath(path)
+	}
+	cfg.RPCUnixListeners = removeDuplicateAddresses(cfg.RPCUnixListeners)
+
+	// Add default port to all grpc listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.GRPCListeners = normalizeAddresses(cfg.GRPCListeners,
+		cfg.params.grpcPort, normalizeInterfaceAddrs)
+
+	// Add default port to all metrics listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.MetricsListeners = normalizeAddresses(cfg.MetricsListeners,
+		defaultMetricsPort, normalizeInterfaceAddrs)
+
+	// Add default port to all diagnostics listener addresses if needed and
+	// remove duplicate addresses.
+	cfg.DiagListeners = normalizeAddresses(cfg.DiagListeners,
+		defaultDiagPort, normalizeInterfaceAddrs)
+
+	// The tracing endpoint must be an HTTP URL and the sample ratio must be
+	// in the range [0, 1].
+	if cfg.TracingEndpoint != "" {
+		u, err := url.Parse(cfg.TracingEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			str := "%s: the --tracingendpoint option must be an http or " +
+				"https URL -- parsed [%v]"
+			err := fmt.Errorf(str, funcName, cfg.TracingEndpoint)
+			return nil, nil, err
+		}
+	}
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		str := "%s: the --tracingsampleratio option must be between 0 and " +
+			"1 -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.TracingSampleRatio)
+		return nil, nil, err
+	}
+
+	// The gRPC server shares the configuration of the RPC server, so it may
+	// not be enabled without it.
+	if cfg.DisableRPC && len(cfg.GRPCListeners) > 0 {
+		str := "%s: the --grpclisten option requires the RPC server to be " +
+			"enabled"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// The diagnostics server shares the admin credentials and TLS settings of
+	// the RPC server, so it may not be enabled without them.  Note that the
+	// admin credentials are not used with client certificate authentication.
+	if len(cfg.DiagListeners) > 0 {
+		if cfg.RPCAuthType == authTypeBasic && (cfg.RPCUser == "" ||
+			cfg.RPCPass == "") {
+
+			str := "%s: the --diaglisten option requires the --rpcuser and " +
+				"--rpcpass options"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		if cfg.DisableRPC {
+			str := "%s: the --diaglisten option requires the RPC server to " +
+				"be enabled"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+	}
+
+	// The metrics do not require authentication without credentials, so they
+	// may only be served on loopback addresses unless both are provided.
+	if (cfg.MetricsUser == "") != (cfg.MetricsPass == "") {
+		str := "%s: the --metricsuser and --metricspass options must be " +
+			"specified together"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	if cfg.MetricsUser == "" {
+		for _, addr := range cfg.MetricsListeners {
+			if !isLoopbackAddr(addr) {
+				str := "%s: the --metricslisten option requires the " +
+					"--metricsuser and --metricspass options for " +
+					"addresses other than loopback addresses -- parsed [%v]"
+				err := fmt.Errorf(str, funcName, addr)
+				return nil, nil, err
+			}
+		}
+	}
+
+	// The REST token is only used by the REST interface.
+	if cfg.RESTToken != "" && !cfg.EnableREST {
+		str := "%s: the --resttoken option requires --rest"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
 	// The authtype config must be one of "basic" or "clientcert".
 	switch cfg.RPCAuthType {
 	case authTypeBasic, authTypeClientCert:
@@ -1215,7 +1852,12 @@ func loadConfig(appName string) (*config, []string, error) {
 			"127.0.0.1": {},
 			"::1":       {},
 		}
-		for _, addr := range cfg.RPCListeners {
+		listenAddrs := make([]string, 0, len(cfg.RPCListeners)+
+			len(cfg.GRPCListeners)+len(cfg.DiagListeners))
+		listenAddrs = append(listenAddrs, cfg.RPCListeners...)
+		listenAddrs = append(listenAddrs, cfg.GRPCListeners...)
+		listenAddrs = append(listenAddrs, cfg.DiagListeners...)
+		for _, addr := range listenAddrs {
 			host, _, err := net.SplitHostPort(addr)
 			if err != nil {
 				str := "%s: RPC listen interface '%s' is " +
@@ -1240,16 +1882,30 @@ func loadConfig(appName string) (*config, []string, error) {
 	}
 
 	// Add default port to all added peer addresses if needed and remove
-	// duplicate addresses.
-	cfg.AddPeers = normalizeAddresses(cfg.AddPeers,
-		cfg.params.DefaultPort, normalizeInterfaceFirstAddr)
-	cfg.ConnectPeers = normalizeAddresses(cfg.ConnectPeers,
-		cfg.params.DefaultPort, normalizeInterfaceFirstAddr)
-
-	// Tor stream isolation requires either proxy or onion proxy to be set.
-	if cfg.TorIsolation && cfg.Proxy == "" && cfg.OnionProxy == "" {
-		str := "%s: Tor stream isolation requires either proxy or " +
-			"onionproxy to be set"
+	// duplicate addresses.  Any permissions granted to the peers are split
+	// from the addresses in the process.
+	cfg.AddPeers, cfg.addPeerPerms, err = normalizePeerAddresses(
+		cfg.AddPeers, cfg.params.DefaultPort)
+	if err != nil {
+		err := fmt.Errorf("%s: invalid --addpeer option: %w", funcName, err)
+		return nil, nil, err
+	}
+	cfg.ConnectPeers, cfg.connectPeerPerms, err = normalizePeerAddresses(
+		cfg.ConnectPeers, cfg.params.DefaultPort)
+	if err != nil {
+		err := fmt.Errorf("%s: invalid --connect option: %w", funcName, err)
+		return nil, nil, err
+	}
+
+	// Tor stream isolation requires at least one proxy to be set.
+	isProxy := func(addr string) bool {
+		return addr != "" && addr != noProxy
+	}
+	if cfg.TorIsolation && cfg.Proxy == "" && cfg.OnionProxy == "" &&
+		!isProxy(cfg.IPv4Proxy) && !isProxy(cfg.IPv6Proxy) {
+
+		str := "%s: Tor stream isolation requires proxy, onionproxy, " +
+			"ipv4proxy, or ipv6proxy to be set"
 		err := fmt.Errorf(str, funcName)
 		return nil, nil, err
 	}
@@ -1335,6 +1991,26 @@ func loadConfig(appName string) (*config, []string, error) {
 		cfg.onionlookup = cfg.lookup
 	}
 
+	// Setup the dial functions for IPv4 and IPv6 addresses depending on the
+	// specified options.  The default is to use the normal dial function
+	// selected above.  However, when a network-specific proxy is specified,
+	// connections to addresses of that network are routed through it instead,
+	// or made directly when the proxy is 'none'.  This allows, for example,
+	// IPv4 traffic to be sent directly while IPv6 traffic is routed through
+	// Tor without mixing identities.
+	cfg.IPv4Proxy, cfg.ipv4dial, err = parseNetworkProxy(&cfg, cfg.IPv4Proxy)
+	if err != nil {
+		str := "%s: IPv4 proxy address '%s' is invalid: %w"
+		err := fmt.Errorf(str, funcName, cfg.IPv4Proxy, err)
+		return nil, nil, err
+	}
+	cfg.IPv6Proxy, cfg.ipv6dial, err = parseNetworkProxy(&cfg, cfg.IPv6Proxy)
+	if err != nil {
+		str := "%s: IPv6 proxy address '%s' is invalid: %w"
+		err := fmt.Errorf(str, funcName, cfg.IPv6Proxy, err)
+		return nil, nil, err
+	}
+
 	// Specifying --noonion means the onion address dial and DNS resolution
 	// (lookup) functions result in an error.
 	if cfg.NoOnion {
@@ -1346,6 +2022,23 @@ func loadConfig(appName string) (*config, []string, error) {
 		}
 	}
 
+	// Validate the I2P SAM bridge address when specified.
+	if cfg.I2PSAM != "" {
+		host, port, err := net.SplitHostPort(cfg.I2PSAM)
+		if err != nil {
+			str := "%s: I2P SAM bridge address '%s' is invalid: %w"
+			err := fmt.Errorf(str, funcName, cfg.I2PSAM, err)
+			return nil, nil, err
+		}
+		cfg.I2PSAM = normalizeAddresses([]string{host}, port,
+			normalizeInterfaceFirstAddr)[0]
+	}
+
+	// Expand the path to the AS map file when specified.
+	if cfg.ASMap != "" {
+		cfg.ASMap = cleanAndExpandPath(cfg.ASMap)
+	}
+
 	// Warn if old testnet directory is present.
 	for _, oldDir := range oldTestNets {
 		if fileExists(oldDir) {
@@ -1362,7 +2055,25 @@ func loadConfig(appName string) (*config, []string, error) {
 	}
 
 	// Prevent using an unsupported curve.
-	if _, err := tlsCurve(cfg.TLSCurve); err != nil {
+	if cfg.TLSCurve != ed25519TLSCurve {
+		if _, err := tlsCurve(cfg.TLSCurve); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Don't allow TLS certificate lifetimes that are too short or that would
+	// cause generated certificates to be immediately renewed.
+	if cfg.TLSCertLifetime < minTLSCertLifetime {
+		str := "%s: the tlscertlifetime option may not be less than %v -- " +
+			"parsed [%v]"
+		err := fmt.Errorf(str, funcName, minTLSCertLifetime,
+			cfg.TLSCertLifetime)
+		return nil, nil, err
+	}
+	if cfg.TLSCertRenewal < 0 || cfg.TLSCertRenewal >= cfg.TLSCertLifetime {
+		str := "%s: the tlscertrenewal option must not be negative and must " +
+			"be less than the tlscertlifetime option -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.TLSCertRenewal)
 		return nil, nil, err
 	}
 
@@ -1376,15 +2087,53 @@ func loadConfig(appName string) (*config, []string, error) {
 	return &cfg, remainingArgs, nil
 }
 
+// parseNetworkProxy returns the normalized proxy address and dial function to
+// use for a specific network given the proxy option for it.  An empty option
+// means the normal proxy and dial function are used, while the special value
+// 'none' means connections are made directly.  The returned address is empty
+// when connections are made directly.
+func parseNetworkProxy(cfg *config, proxyAddr string) (string, func(context.Context, string, string) (net.Conn, error), error) {
+	switch proxyAddr {
+	case "":
+		return cfg.Proxy, cfg.dial, nil
+	case noProxy:
+		var d net.Dialer
+		return "", d.DialContext, nil
+	}
+
+	host, port, err := net.SplitHostPort(proxyAddr)
+	if err != nil {
+		return proxyAddr, nil, err
+	}
+	proxyAddr = normalizeAddresses([]string{host}, port,
+		normalizeInterfaceFirstAddr)[0]
+	proxy := &socks.Proxy{
+		Addr:         proxyAddr,
+		Username:     cfg.ProxyUser,
+		Password:     cfg.ProxyPass,
+		TorIsolation: cfg.TorIsolation,
+	}
+	return proxyAddr, proxy.DialContext, nil
+}
+
 // dcrdDial connects to the address on the named network using the appropriate
 // dial function depending on the address and configuration options.  For
 // example, .onion addresses will be dialed using the onion specific proxy if
-// one was specified, but will otherwise use the normal dial function (which
-// could itself use a proxy or not).
+// one was specified and IPv4 and IPv6 addresses will be dialed using their
+// network-specific proxy if one was specified, but will otherwise use the
+// normal dial function (which could itself use a proxy or not).
 func dcrdDial(ctx context.Context, network, addr string) (net.Conn, error) {
 	if strings.Contains(addr, ".onion:") {
 		return cfg.oniondial(ctx, network, addr)
 	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			if ip.To4() != nil {
+				return cfg.ipv4dial(ctx, network, addr)
+			}
+			return cfg.ipv6dial(ctx, network, addr)
+		}
+	}
 	return cfg.dial(ctx, network, addr)
 }
 
@@ -1402,6 +2151,11 @@ func dcrdLookup(host string) ([]net.IP, error) {
 	return cfg.lookup(host)
 }
 
+// ed25519TLSCurve is the config option that indicates TLS keypairs should use
+// Ed25519 keys instead of ECDSA keys with one of the curves supported by
+// tlsCurve.
+const ed25519TLSCurve = "Ed25519"
+
 // tlsCurve returns the correct curve given a config option indicating the
 // curve to use or an error if the curve does not exist.
 func tlsCurve(curve string) (elliptic.Curve, error) {
@@ -5,8 +5,11 @@
 package main
 
 import (
+	"encoding/hex"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -86,3 +89,515 @@ func TestAltDNSNamesWithArg(t *testing.T) {
 func init() {
 	os.Args = os.Args[:1]
 }
+
+// TestParseRPCAuth ensures per-user RPC authorization entries are parsed as
+// intended and malformed entries are rejected.
+func TestParseRPCAuth(t *testing.T) {
+	users, err := parseRPCAuth([]string{
+		"explorer:pass:getblock,getblockhash:blocks",
+		"ntfns:pa:ss::*",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error parsing rpcauth entries: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("unexpected number of users: %d", len(users))
+	}
+	if users[0].User != "explorer" || users[0].Pass != "pass" ||
+		!reflect.DeepEqual(users[0].Methods, []string{"getblock", "getblockhash"}) ||
+		!reflect.DeepEqual(users[0].Notifications, []string{"blocks"}) {
+
+		t.Fatalf("unexpected first user: %+v", users[0])
+	}
+	if users[1].User != "ntfns" || users[1].Pass != "pa:ss" ||
+		users[1].Methods != nil ||
+		!reflect.DeepEqual(users[1].Notifications, []string{"*"}) {
+
+		t.Fatalf("unexpected second user: %+v", users[1])
+	}
+
+	for _, entry := range []string{"user", "user:pass", "user:pass:getblock"} {
+		if _, err := parseRPCAuth([]string{entry}); err == nil {
+			t.Errorf("%q: did not receive expected error", entry)
+		}
+	}
+}
+
+// TestRPCUnixListenersWithoutCreds ensures the RPC server is enabled without
+// credentials when it only listens on unix domain sockets and that other
+// listeners are rejected in that case.
+func TestRPCUnixListenersWithoutCreds(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	// Use an empty config file since the default one contains generated
+	// credentials.
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "dcrd.conf")
+	if err := os.WriteFile(configFile, nil, 0600); err != nil {
+		t.Fatalf("unable to create config file: %v", err)
+	}
+	sockPath := filepath.Join(dir, "rpc.sock")
+	os.Args = append(old, "--configfile="+configFile,
+		"--rpclistenunix="+sockPath)
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	if cfg.DisableRPC {
+		t.Fatal("RPC server is disabled with only unix domain sockets")
+	}
+	if len(cfg.RPCListeners) != 0 {
+		t.Fatalf("unexpected default RPC listeners: %v", cfg.RPCListeners)
+	}
+	if !reflect.DeepEqual(cfg.RPCUnixListeners, []string{sockPath}) {
+		t.Fatalf("unexpected RPC unix listeners -- got %v, want %v",
+			cfg.RPCUnixListeners, []string{sockPath})
+	}
+
+	os.Args = append(old, "--configfile="+configFile,
+		"--rpclistenunix="+sockPath, "--rpclisten=127.0.0.1")
+	if _, _, err := loadConfig(appName); err == nil {
+		t.Fatal("loaded config with RPC listeners and no credentials")
+	}
+}
+
+// TestNetworkProxies ensures the network-specific proxy options are parsed and
+// default to the normal proxy as expected.
+func TestNetworkProxies(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	tests := []struct {
+		name      string
+		args      []string
+		ipv4Proxy string
+		ipv6Proxy string
+		wantErr   bool
+	}{{
+		name:      "no proxies",
+		ipv4Proxy: "",
+		ipv6Proxy: "",
+	}, {
+		name:      "normal proxy only",
+		args:      []string{"--proxy=127.0.0.1:9050"},
+		ipv4Proxy: "127.0.0.1:9050",
+		ipv6Proxy: "127.0.0.1:9050",
+	}, {
+		name: "direct ipv4 with normal proxy",
+		args: []string{"--proxy=127.0.0.1:9050", "--ipv4proxy=none",
+			"--torisolation"},
+		ipv4Proxy: "",
+		ipv6Proxy: "127.0.0.1:9050",
+	}, {
+		name:      "distinct ipv6 proxy without normal proxy",
+		args:      []string{"--ipv6proxy=127.0.0.1:9150", "--torisolation"},
+		ipv4Proxy: "",
+		ipv6Proxy: "127.0.0.1:9150",
+	}, {
+		name:    "invalid ipv4 proxy",
+		args:    []string{"--ipv4proxy=127.0.0.1"},
+		wantErr: true,
+	}, {
+		name:    "tor isolation with only direct connections",
+		args:    []string{"--ipv4proxy=none", "--torisolation"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		os.Args = append(old, test.args...)
+		cfg, _, err := loadConfig(appName)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if cfg.IPv4Proxy != test.ipv4Proxy {
+			t.Errorf("%q: unexpected IPv4 proxy -- got %q, want %q",
+				test.name, cfg.IPv4Proxy, test.ipv4Proxy)
+		}
+		if cfg.IPv6Proxy != test.ipv6Proxy {
+			t.Errorf("%q: unexpected IPv6 proxy -- got %q, want %q",
+				test.name, cfg.IPv6Proxy, test.ipv6Proxy)
+		}
+		if cfg.ipv4dial == nil || cfg.ipv6dial == nil {
+			t.Errorf("%q: network-specific dial functions not set",
+				test.name)
+		}
+	}
+}
+
+// TestOutboundOnly ensures the outbound-only mode disables listening and may
+// not be combined with options that only apply to listening.
+func TestOutboundOnly(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	os.Args = append(old, "--outboundonly")
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	if !cfg.DisableListen {
+		t.Fatal("listening is not disabled in outbound-only mode")
+	}
+
+	for _, arg := range []string{"--listen=127.0.0.1", "--upnp", "--natpmp"} {
+		os.Args = append(old, "--outboundonly", arg)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%s: did not receive expected error", arg)
+		}
+	}
+}
+
+// TestMetricsListenerAuth ensures the metrics listeners are limited to
+// loopback addresses unless metrics credentials are provided.
+func TestMetricsListenerAuth(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	for _, args := range [][]string{
+		{"--metricslisten=127.0.0.1"},
+		{"--metricslisten=[::1]:9190"},
+		{"--metricslisten=localhost"},
+		{"--metricslisten=:9190", "--metricsuser=user", "--metricspass=pass"},
+		{"--metricslisten=10.0.0.1", "--metricsuser=user",
+			"--metricspass=pass"},
+	} {
+		os.Args = append(old, args...)
+		if _, _, err := loadConfig(appName); err != nil {
+			t.Errorf("%v: unexpected error: %v", args, err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--metricslisten=:9190"},
+		{"--metricslisten=0.0.0.0"},
+		{"--metricslisten=10.0.0.1"},
+		{"--metricslisten=example.com"},
+		{"--metricslisten=127.0.0.1", "--metricsuser=user"},
+		{"--metricslisten=127.0.0.1", "--metricspass=pass"},
+	} {
+		os.Args = append(old, args...)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%v: did not receive expected error", args)
+		}
+	}
+}
+
+// TestDBEncryptionKey ensures the database encryption key is loaded from the
+// key file or command and that invalid combinations are rejected.
+func TestDBEncryptionKey(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	keyHex := strings.Repeat("ab", 32)
+	keyPath := filepath.Join(t.TempDir(), "dbkey")
+	if err := os.WriteFile(keyPath, []byte(keyHex+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	os.Args = append(old, "--dbkeyfile="+keyPath)
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	if got := hex.EncodeToString(cfg.dbOpts.EncryptionKey); got != keyHex {
+		t.Fatalf("unexpected key from key file -- got %s, want %s", got,
+			keyHex)
+	}
+
+	if runtime.GOOS != "windows" {
+		os.Args = append(old, "--dbkeycmd=echo "+keyHex)
+		cfg, _, err = loadConfig(appName)
+		if err != nil {
+			t.Fatalf("Failed to load dcrd config: %s", err)
+		}
+		if got := hex.EncodeToString(cfg.dbOpts.EncryptionKey); got != keyHex {
+			t.Fatalf("unexpected key from command -- got %s, want %s", got,
+				keyHex)
+		}
+	}
+
+	badKeyPath := filepath.Join(t.TempDir(), "badkey")
+	if err := os.WriteFile(badKeyPath, []byte("abcd"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--dbkeyfile=" + keyPath, "--dbkeycmd=echo " + keyHex},
+		{"--dbkeyfile=" + badKeyPath},
+		{"--dbkeyfile=" + keyPath + ".missing"},
+	} {
+		os.Args = append(old, args...)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%v: did not receive expected error", args)
+		}
+	}
+}
+
+// TestDBBlockFileOptions ensures the options that determine where the block
+// files are stored are applied to the database options and that invalid
+// combinations are rejected.
+func TestDBBlockFileOptions(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	coldDir := t.TempDir()
+	os.Args = append(old, "--dbblockfilesperdir=100", "--dbcolddir="+coldDir,
+		"--dbhotblockfiles=4")
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	wantColdDir := filepath.Join(coldDir, cfg.params.Name)
+	if cfg.dbOpts.BlockFilesPerDir != 100 ||
+		cfg.dbOpts.ColdBlockDir != wantColdDir ||
+		cfg.dbOpts.HotBlockFiles != 4 {
+
+		t.Fatalf("unexpected database options -- got %d, %q, %d, want 100, "+
+			"%q, 4", cfg.dbOpts.BlockFilesPerDir, cfg.dbOpts.ColdBlockDir,
+			cfg.dbOpts.HotBlockFiles, wantColdDir)
+	}
+
+	os.Args = append(old, "--dbhotblockfiles=4")
+	if _, _, err := loadConfig(appName); err == nil {
+		t.Error("did not receive expected error for dbhotblockfiles " +
+			"without dbcolddir")
+	}
+}
+
+// TestSelectiveIndexOptions ensures the options to verify and rebuild a single
+// optional index accept the supported indexes and reject invalid combinations.
+func TestSelectiveIndexOptions(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	for _, args := range [][]string{
+		{"--verifyindex=txindex"},
+		{"--verifyindex=spentindex"},
+		{"--rebuildindex=existsaddrindex"},
+		{"--txindex", "--rebuildindex=txindex"},
+		{"--addrindex", "--rebuildindex=addrindex"},
+	} {
+		os.Args = append(old, args...)
+		if _, _, err := loadConfig(appName); err != nil {
+			t.Errorf("%v: unexpected error: %v", args, err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--verifyindex=cfindex"},
+		{"--rebuildindex=bogus"},
+		{"--rebuildindex=txindex"},
+		{"--noexistsaddrindex", "--rebuildindex=existsaddrindex"},
+		{"--verifyindex=txindex", "--verifychainstate"},
+		{"--verifyindex=txindex", "--txindex", "--rebuildindex=txindex"},
+		{"--spentindex", "--rebuildindex=spentindex", "--verifychainstate"},
+	} {
+		os.Args = append(old, args...)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%v: did not receive expected error", args)
+		}
+	}
+}
+
+// TestCustomNet ensures the parameters of a custom network are loaded from the
+// definition file specified by the customnet option and that invalid
+// definitions and combinations with other networks are rejected.
+func TestCustomNet(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	tempDir := t.TempDir()
+	defPath := filepath.Join(tempDir, "privnet.json")
+	const def = `{
+		"rpcPort": "28109",
+		"grpcPort": "28112",
+		"params": {
+			"base": "simnet",
+			"name": "privnet",
+			"net": 305419896,
+			"defaultPort": "28108"
+		}
+	}`
+	if err := os.WriteFile(defPath, []byte(def), 0600); err != nil {
+		t.Fatalf("Failed to write network definition: %v", err)
+	}
+	os.Args = append(old, "--appdata="+tempDir, "--customnet="+defPath)
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	if cfg.params.Name != "privnet" || cfg.params.Net != 305419896 ||
+		cfg.params.DefaultPort != "28108" || cfg.params.rpcPort != "28109" ||
+		cfg.params.grpcPort != "28112" {
+
+		t.Fatalf("unexpected network params -- got %s, %d, %s, %s, %s",
+			cfg.params.Name, cfg.params.Net, cfg.params.DefaultPort,
+			cfg.params.rpcPort, cfg.params.grpcPort)
+	}
+	wantDataDir := filepath.Join(tempDir, "data", "privnet")
+	if cfg.DataDir != wantDataDir {
+		t.Fatalf("unexpected data dir -- got %q, want %q", cfg.DataDir,
+			wantDataDir)
+	}
+
+	// Ensure invalid definitions and combinations are rejected.
+	noPortsPath := filepath.Join(tempDir, "noports.json")
+	noPortsDef := `{"params": {"base": "simnet", "name": "privnet", ` +
+		`"net": 305419896, "defaultPort": "28108"}}`
+	if err := os.WriteFile(noPortsPath, []byte(noPortsDef), 0600); err != nil {
+		t.Fatalf("Failed to write network definition: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--customnet=" + defPath, "--simnet"},
+		{"--customnet=" + noPortsPath},
+		{"--customnet=" + defPath + ".missing"},
+	} {
+		os.Args = append(old, append([]string{"--appdata=" + tempDir},
+			args...)...)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%v: did not receive expected error", args)
+		}
+	}
+}
+
+// TestBlockSignKeyFile ensures the key used to sign generated blocks is loaded
+// on networks that require blocks to be signed and rejected otherwise.
+func TestBlockSignKeyFile(t *testing.T) {
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+	old := os.Args
+	defer func() { os.Args = old }()
+
+	tempDir := t.TempDir()
+	defPath := filepath.Join(tempDir, "signet.json")
+	const def = `{
+		"rpcPort": "28109",
+		"grpcPort": "28112",
+		"params": {
+			"base": "simnet",
+			"name": "signet",
+			"net": 305419896,
+			"defaultPort": "28108",
+			"blockSignChallenge": "51"
+		}
+	}`
+	if err := os.WriteFile(defPath, []byte(def), 0600); err != nil {
+		t.Fatalf("Failed to write network definition: %v", err)
+	}
+	keyPath := filepath.Join(tempDir, "blocksign.key")
+	const keyHex = "0000000000000000000000000000000000000000000000000000000000000001"
+	if err := os.WriteFile(keyPath, []byte(keyHex+"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	os.Args = append(old, "--appdata="+tempDir, "--customnet="+defPath,
+		"--blocksignkeyfile="+keyPath)
+	cfg, _, err := loadConfig(appName)
+	if err != nil {
+		t.Fatalf("Failed to load dcrd config: %s", err)
+	}
+	if cfg.blockSignKey == nil || hex.EncodeToString(
+		cfg.blockSignKey.Serialize()) != keyHex {
+
+		t.Fatalf("unexpected block signing key -- got %v", cfg.blockSignKey)
+	}
+
+	// Ensure invalid keys and networks that do not require blocks to be signed
+	// are rejected.
+	badKeyPath := filepath.Join(tempDir, "bad.key")
+	if err := os.WriteFile(badKeyPath, []byte("00"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	for _, args := range [][]string{
+		{"--simnet", "--blocksignkeyfile=" + keyPath},
+		{"--customnet=" + defPath, "--blocksignkeyfile=" + badKeyPath},
+		{"--customnet=" + defPath, "--blocksignkeyfile=" + keyPath + ".missing"},
+	} {
+		os.Args = append(old, append([]string{"--appdata=" + tempDir},
+			args...)...)
+		if _, _, err := loadConfig(appName); err == nil {
+			t.Errorf("%v: did not receive expected error", args)
+		}
+	}
+}
+
+// TestSubsystemLogLevels ensures the reported subsystem logging levels reflect
+// the levels set at runtime and use the names accepted when setting them.
+func TestSubsystemLogLevels(t *testing.T) {
+	defer setLogLevels(defaultLogLevel)
+
+	setLogLevels("warn")
+	if err := parseAndSetDebugLevels("SYNC=trace"); err != nil {
+		t.Fatalf("Failed to set debug levels: %v", err)
+	}
+	levels := subsystemLogLevels()
+	if len(levels) != len(subsystemLoggers) {
+		t.Fatalf("mismatched number of subsystems -- got %d, want %d",
+			len(levels), len(subsystemLoggers))
+	}
+	for subsystemID, level := range levels {
+		want := "warn"
+		if subsystemID == "SYNC" {
+			want = "trace"
+		}
+		if level != want {
+			t.Errorf("%s: mismatched level -- got %q, want %q", subsystemID,
+				level, want)
+		}
+		if !validLogLevel(level) {
+			t.Errorf("%s: level %q is not accepted when setting levels",
+				subsystemID, level)
+		}
+	}
+}
+
+// TestParseLogSize ensures log sizes are parsed to the expected number of
+// bytes and invalid sizes are rejected.
+func TestParseLogSize(t *testing.T) {
+	tests := []struct {
+		size  string
+		want  int64
+		valid bool
+	}{
+		{size: "0", want: 0, valid: true},
+		{size: "512K", want: 512 << 10, valid: true},
+		{size: "10M", want: 10 << 20, valid: true},
+		{size: "10MiB", want: 10 << 20, valid: true},
+		{size: "2G", want: 2 << 30, valid: true},
+		{size: "", valid: false},
+		{size: "10", valid: false},
+		{size: "M", valid: false},
+		{size: "10T", valid: false},
+		{size: "99999999999G", valid: false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseLogSize(test.size)
+		if ok != test.valid || got != test.want {
+			t.Errorf("%q: mismatched result -- got %d (valid %v), want %d "+
+				"(valid %v)", test.size, got, ok, test.want, test.valid)
+		}
+	}
+}
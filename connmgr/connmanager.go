@@ -51,8 +51,30 @@ const (
 	ConnDisconnected
 	ConnFailed
 	ConnCanceled
+
+	// numConnStates is the number of connection states.  It is only used
+	// for tests and must be the final entry.
+	numConnStates
 )
 
+// connStateStrings is a map of connection states back to their constant names
+// for pretty printing.
+var connStateStrings = map[ConnState]string{
+	ConnPending:      "pending",
+	ConnEstablished:  "established",
+	ConnDisconnected: "disconnected",
+	ConnFailed:       "failed",
+	ConnCanceled:     "canceled",
+}
+
+// String returns the ConnState in human-readable form.
+func (s ConnState) String() string {
+	if str, ok := connStateStrings[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("Unknown ConnState (%d)", uint32(s))
+}
+
 // ConnReq is the connection request to a network address. If permanent, the
 // connection will be retried on disconnection.
 type ConnReq struct {
@@ -139,8 +161,16 @@ type Config struct {
 
 	// RetryDuration is the duration to wait before retrying connection
 	// requests. Defaults to 5s.
+	//
+	// This field will not have any effect if the Backoff field is
+	// specified.
 	RetryDuration time.Duration
 
+	// Backoff is the policy that determines how long to wait before retrying
+	// failed connections.  Defaults to a LinearBackoff that uses the
+	// configured RetryDuration.
+	Backoff BackoffPolicy
+
 	// OnConnection is a callback that is fired when a new outbound
 	// connection is established.
 	OnConnection func(*ConnReq, net.Conn)
@@ -150,9 +180,17 @@ type Config struct {
 	OnDisconnection func(*ConnReq)
 
 	// GetNewAddress is a way to get an address to make a network connection
-	// to.  If nil, no new connections will be made automatically.
+	// to.  If both it and AddrSelector are nil, no new connections will be
+	// made automatically.  Either GetNewAddress or AddrSelector may be
+	// specified (but not both).
 	GetNewAddress func() (net.Addr, error)
 
+	// AddrSelector is an alternative to GetNewAddress which allows callers
+	// to provide a custom implementation of the AddrSelector interface to
+	// select the addresses to make network connections to.  Either
+	// AddrSelector or GetNewAddress may be specified (but not both).
+	AddrSelector AddrSelector
+
 	// Dial connects to the address on the named network. Either Dial or
 	// DialAddr need to be specified (but not both).
 	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
@@ -207,13 +245,34 @@ type handleForEachConnReq struct {
 	done chan error
 }
 
+// Stats houses cumulative statistics about the connections made by the
+// connection manager since it was created.
+type Stats struct {
+	// DialAttempts is the number of outbound connection attempts.
+	DialAttempts uint64
+
+	// DialFailures is the number of outbound connection attempts that failed
+	// to establish a connection.
+	DialFailures uint64
+
+	// Accepted is the number of inbound connections accepted from the
+	// listeners.
+	Accepted uint64
+}
+
 // ConnManager provides a manager to handle network connections.
 type ConnManager struct {
 	// The following variables must only be used atomically.
 	//
 	// connReqCount is the number of connection requests that have been made and
 	// is primarily used to assign unique connection request IDs.
+	//
+	// dialAttempts, dialFailures, and accepted track the statistics returned
+	// by Stats.
 	connReqCount uint64
+	dialAttempts uint64
+	dialFailures uint64
+	accepted     uint64
 
 	// The following fields are used for lifecycle management of the connection
 	// manager.
@@ -240,10 +299,10 @@ type ConnManager struct {
 }
 
 // handleFailedConn handles a connection failed due to a disconnect or any
-// other failure. If permanent, it retries the connection after the configured
-// retry duration. Otherwise, if required, it makes a new connection request.
-// After maxFailedConnectionAttempts new connections will be retried after the
-// configured retry duration.
+// other failure. If permanent, it retries the connection after the delay
+// determined by the configured backoff policy. Otherwise, if required, it makes
+// a new connection request after the delay determined by the policy, which, by
+// default, is only imposed after maxFailedAttempts successive failures.
 func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 	// Ignore during shutdown.
 	if ctx.Err() != nil {
@@ -252,10 +311,7 @@ func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 
 	if c.Permanent {
 		c.retryCount++
-		d := time.Duration(c.retryCount) * cm.cfg.RetryDuration
-		if d > maxRetryDuration {
-			d = maxRetryDuration
-		}
+		d := cm.cfg.Backoff.RetryDelay(c, c.retryCount)
 		log.Debugf("Retrying connection to %v in %v", c, d)
 		go func() {
 			select {
@@ -264,15 +320,14 @@ func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 			case <-cm.quit:
 			}
 		}()
-	} else if cm.cfg.GetNewAddress != nil {
+	} else if cm.cfg.AddrSelector != nil {
 		cm.failedAttempts++
-		if cm.failedAttempts >= maxFailedAttempts {
-			log.Debugf("Max failed connection attempts reached: [%d] "+
-				"-- retrying connection in: %v", maxFailedAttempts,
-				cm.cfg.RetryDuration)
+		if d := cm.cfg.Backoff.NewConnDelay(cm.failedAttempts); d > 0 {
+			log.Debugf("Failed connection attempts: [%d] -- retrying "+
+				"connection in: %v", cm.failedAttempts, d)
 			go func() {
 				select {
-				case <-time.After(cm.cfg.RetryDuration):
+				case <-time.After(d):
 					cm.newConnReq(ctx)
 				case <-cm.quit:
 				}
@@ -487,7 +542,7 @@ func (cm *ConnManager) newConnReq(ctx context.Context) {
 		return
 	}
 
-	addr, err := cm.cfg.GetNewAddress()
+	addr, err := cm.cfg.AddrSelector.SelectAddr()
 	if err != nil {
 		select {
 		case cm.requests <- handleFailed{c, err}:
@@ -562,12 +617,14 @@ func (cm *ConnManager) Connect(ctx context.Context, c *ConnReq) {
 	}
 	var conn net.Conn
 	var err error
+	atomic.AddUint64(&cm.dialAttempts, 1)
 	if cm.cfg.Dial != nil {
 		conn, err = cm.cfg.Dial(ctx, c.Addr.Network(), c.Addr.String())
 	} else {
 		conn, err = cm.cfg.DialAddr(ctx, c.Addr)
 	}
 	if err != nil {
+		atomic.AddUint64(&cm.dialFailures, 1)
 		select {
 		case cm.requests <- handleFailed{c, err}:
 		case <-cm.quit:
@@ -649,6 +706,18 @@ func (cm *ConnManager) ForEachConnReq(f func(c *ConnReq) error) error {
 	}
 }
 
+// Stats returns cumulative statistics about the connections made by the
+// connection manager.
+//
+// This function is safe for concurrent access.
+func (cm *ConnManager) Stats() Stats {
+	return Stats{
+		DialAttempts: atomic.LoadUint64(&cm.dialAttempts),
+		DialFailures: atomic.LoadUint64(&cm.dialFailures),
+		Accepted:     atomic.LoadUint64(&cm.accepted),
+	}
+}
+
 // listenHandler accepts incoming connections on a given listener.  It must be
 // run as a goroutine.
 func (cm *ConnManager) listenHandler(ctx context.Context, listener net.Listener) {
@@ -662,6 +731,7 @@ func (cm *ConnManager) listenHandler(ctx context.Context, listener net.Listener)
 			}
 			continue
 		}
+		atomic.AddUint64(&cm.accepted, 1)
 		go cm.cfg.OnAccept(conn)
 	}
 
@@ -692,7 +762,7 @@ func (cm *ConnManager) Run(ctx context.Context) {
 
 	// Start enough outbound connections to reach the target number when not
 	// in manual connect mode.
-	if cm.cfg.GetNewAddress != nil {
+	if cm.cfg.AddrSelector != nil {
 		curConnReqCount := atomic.LoadUint64(&cm.connReqCount)
 		for i := curConnReqCount; i < uint64(cm.cfg.TargetOutbound); i++ {
 			go cm.newConnReq(ctx)
@@ -731,6 +801,10 @@ func New(cfg *Config) (*ConnManager, error) {
 		return nil, MakeError(ErrBothDialsFilled,
 			"cannot specify both Dial and DialAddr")
 	}
+	if cfg.GetNewAddress != nil && cfg.AddrSelector != nil {
+		return nil, MakeError(ErrBothAddrSourcesFilled,
+			"cannot specify both GetNewAddress and AddrSelector")
+	}
 	// Default to sane values
 	if cfg.RetryDuration <= 0 {
 		cfg.RetryDuration = defaultRetryDuration
@@ -743,5 +817,11 @@ func New(cfg *Config) (*ConnManager, error) {
 		requests: make(chan interface{}),
 		quit:     make(chan struct{}),
 	}
+	if cm.cfg.GetNewAddress != nil {
+		cm.cfg.AddrSelector = AddrSelectorFunc(cm.cfg.GetNewAddress)
+	}
+	if cm.cfg.Backoff == nil {
+		cm.cfg.Backoff = &LinearBackoff{RetryDuration: cm.cfg.RetryDuration}
+	}
 	return &cm, nil
 }
@@ -123,6 +123,17 @@ func TestNewConfig(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New unexpected error: %v", err)
 	}
+
+	getNewAddress := func() (net.Addr, error) { return nil, nil }
+	_, err = New(&Config{
+		Dial:          mockDialer,
+		GetNewAddress: getNewAddress,
+		AddrSelector:  AddrSelectorFunc(getNewAddress),
+	})
+	if !errors.Is(err, ErrBothAddrSourcesFilled) {
+		t.Fatalf("New unexpected error -- got %v, want %v", err,
+			ErrBothAddrSourcesFilled)
+	}
 }
 
 // assertConnReqID ensures the provided connection request has the given ID.
@@ -399,6 +410,122 @@ func TestMaxRetryDuration(t *testing.T) {
 	wg.Wait()
 }
 
+// mockBackoff is a backoff policy that records the retry counts and failed
+// attempts it is queried with and always returns a fixed delay.
+type mockBackoff struct {
+	delay          time.Duration
+	retryCounts    chan uint32
+	failedAttempts chan uint64
+}
+
+func (b *mockBackoff) RetryDelay(c *ConnReq, retryCount uint32) time.Duration {
+	b.retryCounts <- retryCount
+	return b.delay
+}
+
+func (b *mockBackoff) NewConnDelay(failedAttempts uint64) time.Duration {
+	b.failedAttempts <- failedAttempts
+	return b.delay
+}
+
+// TestCustomPolicies ensures the connection manager consults custom address
+// selectors and backoff policies when they are provided.
+func TestCustomPolicies(t *testing.T) {
+	networkUp := make(chan struct{})
+	failDialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		select {
+		case <-networkUp:
+			return mockDialer(ctx, network, addr)
+		default:
+			return nil, errors.New("network down")
+		}
+	}
+
+	var selections uint32
+	selector := AddrSelectorFunc(func() (net.Addr, error) {
+		atomic.AddUint32(&selections, 1)
+		return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18555}, nil
+	})
+	backoff := &mockBackoff{
+		delay:          time.Millisecond,
+		retryCounts:    make(chan uint32, 1),
+		failedAttempts: make(chan uint64, 1),
+	}
+	connected := make(chan *ConnReq, 2)
+	cmgr, err := New(&Config{
+		TargetOutbound: 1,
+		Dial:           failDialer,
+		AddrSelector:   selector,
+		Backoff:        backoff,
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	// Ensure the backoff policy is consulted for new connections after the
+	// automatic connection made with the selected address fails.
+	select {
+	case got := <-backoff.failedAttempts:
+		if got != 1 {
+			t.Fatalf("unexpected failed attempts -- got %d, want 1", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("backoff policy not consulted for new connection")
+	}
+	if atomic.LoadUint32(&selections) == 0 {
+		t.Fatal("address selector not consulted")
+	}
+
+	// Ensure the backoff policy is consulted for permanent connections.
+	cr := &ConnReq{
+		Addr:      &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 18556},
+		Permanent: true,
+	}
+	go cmgr.Connect(ctx, cr)
+	for {
+		select {
+		case <-backoff.failedAttempts:
+			continue
+		case got := <-backoff.retryCounts:
+			if got != 1 {
+				t.Fatalf("unexpected retry count -- got %d, want 1", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("backoff policy not consulted for permanent connection")
+		}
+		break
+	}
+
+	// Drain the policy queries while the connections are established once
+	// the network comes back up.
+	close(networkUp)
+	drainDone := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-backoff.failedAttempts:
+			case <-backoff.retryCounts:
+			case <-drainDone:
+				return
+			}
+		}
+	}()
+	select {
+	case <-connected:
+	case <-time.After(time.Second):
+		t.Fatal("connection not established after network came up")
+	}
+
+	// Ensure clean shutdown of connection manager.
+	shutdown()
+	wg.Wait()
+	close(drainDone)
+}
+
 // TestNetworkFailure tests that the connection manager handles a network
 // failure gracefully.
 func TestNetworkFailure(t *testing.T) {
@@ -1019,7 +1146,59 @@ out:
 		}
 	}
 
+	// Ensure the accepted connections are reflected in the stats.
+	if accepted := cmgr.Stats().Accepted; accepted != uint64(expectedNumConns) {
+		t.Fatalf("unexpected accepted connections -- got %d, want %d",
+			accepted, expectedNumConns)
+	}
+
 	// Ensure clean shutdown of connection manager.
 	shutdown()
 	wg.Wait()
 }
+
+// TestStats ensures the connection manager stats track outbound connection
+// attempts and failures.
+func TestStats(t *testing.T) {
+	dialErr := errors.New("dial failure")
+	cmgr, err := New(&Config{
+		Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if addr == "127.0.0.1:18556" {
+				return nil, dialErr
+			}
+			return mockDialer(ctx, network, addr)
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	for _, port := range []int{18555, 18556, 18557} {
+		cmgr.Connect(ctx, &ConnReq{
+			Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port},
+		})
+	}
+
+	want := Stats{DialAttempts: 3, DialFailures: 1}
+	if got := cmgr.Stats(); got != want {
+		t.Fatalf("unexpected stats -- got %+v, want %+v", got, want)
+	}
+
+	// Ensure clean shutdown of connection manager.
+	shutdown()
+	wg.Wait()
+}
+
+// TestConnStateStringer tests the stringized output for the connection states.
+func TestConnStateStringer(t *testing.T) {
+	for state := ConnState(0); state < numConnStates; state++ {
+		if _, ok := connStateStrings[state]; !ok {
+			t.Errorf("missing string for connection state %d", state)
+		}
+	}
+	want := "Unknown ConnState (255)"
+	if got := ConnState(255).String(); got != want {
+		t.Errorf("unexpected string -- got %q, want %q", got, want)
+	}
+}
@@ -11,5 +11,15 @@ Package connmgr implements a generic Decred network connection manager.
 Connection manager handles all the general connection concerns such as
 maintaining a set number of outbound connections, sourcing peers, banning,
 limiting max connections, tor lookup, etc.
+
+# Custom Policies
+
+The selection of the addresses to automatically make outbound connections to
+and the delays before retrying failed connections may be customized by
+providing implementations of the AddrSelector and BackoffPolicy interfaces,
+respectively, via the configuration.  This allows callers to, for example,
+reconnect aggressively on private networks while using the provided
+ExponentialBackoff policy with jitter on public ones.  The LinearBackoff policy
+is used by default.
 */
 package connmgr
@@ -18,6 +18,10 @@ const (
 	// cannot both be specified in the configuration.
 	ErrBothDialsFilled = ErrorKind("ErrBothDialsFilled")
 
+	// ErrBothAddrSourcesFilled is used to indicate that GetNewAddress and
+	// AddrSelector cannot both be specified in the configuration.
+	ErrBothAddrSourcesFilled = ErrorKind("ErrBothAddrSourcesFilled")
+
 	// ErrTorInvalidAddressResponse indicates an invalid address was
 	// returned by the Tor DNS resolver.
 	ErrTorInvalidAddressResponse = ErrorKind("ErrTorInvalidAddressResponse")
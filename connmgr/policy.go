@@ -0,0 +1,191 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// AddrSelector defines the interface the connection manager uses to select the
+// addresses to make new automatic outbound connections to.  Callers may provide
+// their own implementation to customize target selection.
+type AddrSelector interface {
+	// SelectAddr returns an address to make a new outbound connection to.
+	SelectAddr() (net.Addr, error)
+}
+
+// AddrSelectorFunc is an adapter to allow the use of ordinary functions as
+// address selectors.
+type AddrSelectorFunc func() (net.Addr, error)
+
+// SelectAddr returns an address to make a new outbound connection to by
+// invoking the function.
+//
+// This is part of the AddrSelector interface.
+func (f AddrSelectorFunc) SelectAddr() (net.Addr, error) {
+	return f()
+}
+
+// BackoffPolicy defines the interface the connection manager uses to determine
+// how long to wait before retrying failed connections.  Callers may provide
+// their own implementation to customize the retry behavior, for example, to
+// reconnect aggressively on private networks.
+type BackoffPolicy interface {
+	// RetryDelay returns the duration to wait before retrying the provided
+	// permanent connection request given the number of times it has been
+	// retried since its last successful connection, including the retry that
+	// is about to be made.
+	RetryDelay(c *ConnReq, retryCount uint32) time.Duration
+
+	// NewConnDelay returns the duration to wait before making a new automatic
+	// outbound connection request given the number of successive failed
+	// connection attempts since the last successful connection.  A duration
+	// of zero means the request is made immediately.
+	NewConnDelay(failedAttempts uint64) time.Duration
+}
+
+// LinearBackoff is a BackoffPolicy that retries permanent connections with
+// delays that increase linearly with the number of retries and delays new
+// connections by a fixed duration once too many successive connection attempts
+// have failed.  It is the policy used when none is specified.
+type LinearBackoff struct {
+	// RetryDuration is the duration the delay grows by for each retry of a
+	// permanent connection as well as the delay for new connections once
+	// the max number of failed attempts has been reached.
+	RetryDuration time.Duration
+
+	// MaxRetryDuration is the max duration the delay for retrying permanent
+	// connections is allowed to grow to.  Defaults to 5m.
+	MaxRetryDuration time.Duration
+
+	// MaxFailedAttempts is the number of successive failed connection
+	// attempts after which network failure is assumed and new connections
+	// are delayed.  Defaults to 25.
+	MaxFailedAttempts uint64
+}
+
+// Ensure LinearBackoff implements the BackoffPolicy interface.
+var _ BackoffPolicy = (*LinearBackoff)(nil)
+
+// RetryDelay returns the duration to wait before retrying the provided
+// permanent connection request.
+//
+// This is part of the BackoffPolicy interface.
+func (b *LinearBackoff) RetryDelay(c *ConnReq, retryCount uint32) time.Duration {
+	maxDelay := b.MaxRetryDuration
+	if maxDelay == 0 {
+		maxDelay = maxRetryDuration
+	}
+	d := time.Duration(retryCount) * b.RetryDuration
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// NewConnDelay returns the duration to wait before making a new automatic
+// outbound connection request.
+//
+// This is part of the BackoffPolicy interface.
+func (b *LinearBackoff) NewConnDelay(failedAttempts uint64) time.Duration {
+	maxAttempts := b.MaxFailedAttempts
+	if maxAttempts == 0 {
+		maxAttempts = maxFailedAttempts
+	}
+	if failedAttempts < maxAttempts {
+		return 0
+	}
+	return b.RetryDuration
+}
+
+// ExponentialBackoff is a BackoffPolicy that retries permanent connections with
+// delays that double with each retry and delays new connections by durations
+// that double with each failed attempt once too many successive connection
+// attempts have failed.  The delays are optionally jittered so that many nodes
+// that lose connectivity at the same time do not all retry in lockstep.
+type ExponentialBackoff struct {
+	// BaseDuration is the delay of the first retry.
+	BaseDuration time.Duration
+
+	// MaxDuration is the max duration the delays are allowed to grow to.
+	// Defaults to 5m.
+	MaxDuration time.Duration
+
+	// Jitter is the fraction of each delay, in the range [0, 1], that is
+	// randomized.  For example, a jitter of 0.2 results in delays anywhere
+	// from 80% to 100% of their unjittered value.
+	Jitter float64
+
+	// MaxFailedAttempts is the number of successive failed connection
+	// attempts after which network failure is assumed and new connections
+	// are delayed.  Defaults to 25.
+	MaxFailedAttempts uint64
+}
+
+// Ensure ExponentialBackoff implements the BackoffPolicy interface.
+var _ BackoffPolicy = (*ExponentialBackoff)(nil)
+
+// delay returns the jittered delay for the provided number of retries.  The
+// number of retries must be at least one.
+func (b *ExponentialBackoff) delay(retries uint64) time.Duration {
+	maxDelay := b.MaxDuration
+	if maxDelay == 0 {
+		maxDelay = maxRetryDuration
+	}
+	d := b.BaseDuration
+	for i := uint64(1); i < retries && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+
+	if b.Jitter > 0 {
+		jitter := b.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= time.Duration(float64(d) * jitter * randFloat64())
+	}
+	return d
+}
+
+// RetryDelay returns the duration to wait before retrying the provided
+// permanent connection request.
+//
+// This is part of the BackoffPolicy interface.
+func (b *ExponentialBackoff) RetryDelay(c *ConnReq, retryCount uint32) time.Duration {
+	if retryCount == 0 {
+		return 0
+	}
+	return b.delay(uint64(retryCount))
+}
+
+// NewConnDelay returns the duration to wait before making a new automatic
+// outbound connection request.
+//
+// This is part of the BackoffPolicy interface.
+func (b *ExponentialBackoff) NewConnDelay(failedAttempts uint64) time.Duration {
+	maxAttempts := b.MaxFailedAttempts
+	if maxAttempts == 0 {
+		maxAttempts = maxFailedAttempts
+	}
+	if failedAttempts < maxAttempts {
+		return 0
+	}
+	return b.delay(failedAttempts - maxAttempts + 1)
+}
+
+// randFloat64 returns a cryptographically random number in the range [0, 1).
+func randFloat64() float64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0
+	}
+	return float64(binary.LittleEndian.Uint64(b[:])>>11) / (1 << 53)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package connmgr
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLinearBackoff ensures the linear backoff policy produces the expected
+// delays.
+func TestLinearBackoff(t *testing.T) {
+	b := &LinearBackoff{
+		RetryDuration:     time.Second,
+		MaxRetryDuration:  3 * time.Second,
+		MaxFailedAttempts: 5,
+	}
+
+	retryTests := []struct {
+		retryCount uint32
+		want       time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 3 * time.Second},
+		{10, 3 * time.Second},
+	}
+	for _, test := range retryTests {
+		got := b.RetryDelay(nil, test.retryCount)
+		if got != test.want {
+			t.Errorf("unexpected retry delay for %d retries -- got %v, want "+
+				"%v", test.retryCount, got, test.want)
+		}
+	}
+
+	newConnTests := []struct {
+		failedAttempts uint64
+		want           time.Duration
+	}{
+		{1, 0},
+		{4, 0},
+		{5, time.Second},
+		{100, time.Second},
+	}
+	for _, test := range newConnTests {
+		got := b.NewConnDelay(test.failedAttempts)
+		if got != test.want {
+			t.Errorf("unexpected new conn delay for %d failed attempts -- "+
+				"got %v, want %v", test.failedAttempts, got, test.want)
+		}
+	}
+
+	// Ensure the package defaults are used when the limits are not set.
+	b = &LinearBackoff{RetryDuration: time.Millisecond}
+	if got := b.RetryDelay(nil, 100); got != maxRetryDuration {
+		t.Errorf("unexpected default max retry delay -- got %v, want %v",
+			got, maxRetryDuration)
+	}
+	if got := b.NewConnDelay(maxFailedAttempts - 1); got != 0 {
+		t.Errorf("unexpected new conn delay before default max failed "+
+			"attempts -- got %v, want 0", got)
+	}
+	if got := b.NewConnDelay(maxFailedAttempts); got != time.Millisecond {
+		t.Errorf("unexpected new conn delay at default max failed "+
+			"attempts -- got %v, want %v", got, time.Millisecond)
+	}
+}
+
+// TestExponentialBackoff ensures the exponential backoff policy produces the
+// expected delays both with and without jitter.
+func TestExponentialBackoff(t *testing.T) {
+	b := &ExponentialBackoff{
+		BaseDuration:      time.Second,
+		MaxDuration:       10 * time.Second,
+		MaxFailedAttempts: 5,
+	}
+
+	retryTests := []struct {
+		retryCount uint32
+		want       time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{1000, 10 * time.Second},
+	}
+	for _, test := range retryTests {
+		got := b.RetryDelay(nil, test.retryCount)
+		if got != test.want {
+			t.Errorf("unexpected retry delay for %d retries -- got %v, want "+
+				"%v", test.retryCount, got, test.want)
+		}
+	}
+
+	newConnTests := []struct {
+		failedAttempts uint64
+		want           time.Duration
+	}{
+		{4, 0},
+		{5, time.Second},
+		{6, 2 * time.Second},
+		{9, 10 * time.Second},
+	}
+	for _, test := range newConnTests {
+		got := b.NewConnDelay(test.failedAttempts)
+		if got != test.want {
+			t.Errorf("unexpected new conn delay for %d failed attempts -- "+
+				"got %v, want %v", test.failedAttempts, got, test.want)
+		}
+	}
+
+	// Ensure jittered delays are within the expected range.
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := b.RetryDelay(nil, 3)
+		if got < 2*time.Second || got > 4*time.Second {
+			t.Fatalf("jittered retry delay %v is not in the range [%v, %v]",
+				got, 2*time.Second, 4*time.Second)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package lru implements type safe generic least-recently-used caches with near
+O(1) perf along with optional per-item expiration and cost-based capacities.
+
+# LRU Cache
+
+A least-recently-used (LRU) cache is a cache that holds a limited number of
+items with an eviction policy such that when the capacity of the cache is
+exceeded, the least-recently-used items are automatically removed when inserting
+a new item.  The meaning of used in this implementation is either accessing the
+item via a lookup or adding the item into the cache, including when the item
+already exists.
+
+Two types are provided:
+
+  - Map is a cache of key/value pairs
+  - Set is a cache of items with no associated values
+
+# Capacity and Item Costs
+
+By default, every item has a cost of one, so the capacity is the maximum number
+of items in the cache.  A cost function may optionally be provided to assign
+arbitrary costs to items, such as their size in bytes, in which case the
+capacity is the maximum total cost of all items in the cache and as many of the
+least-recently-used items as needed are evicted to make room for new items.
+Items whose cost exceeds the capacity are never added.
+
+# Expiration
+
+Items may optionally be assigned a time to live (TTL), either by configuring a
+default TTL that applies to all items or per item, after which they are treated
+as if they do not exist.  Expired items are removed lazily when they are
+accessed or when they become the least-recently-used item while room is being
+made for new items.  EvictExpired may be used to proactively remove all expired
+items.
+
+# Eviction Callbacks
+
+A callback may optionally be configured to be notified with the reason when
+items are evicted due to capacity constraints or expiration.  The callback is
+invoked after the internal lock is released, so it may safely access the cache.
+It is not invoked for items that are explicitly deleted or replaced.
+
+# External Use
+
+This package has intentionally been designed so it can be used as a standalone
+package for any projects needing to make use of well-tested and concurrent safe
+least-recently-used caches with near O(1) performance characteristics for
+lookups, inserts, and deletions.
+*/
+package lru
@@ -0,0 +1,83 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package lru_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/container/lru"
+)
+
+// This example demonstrates creating a new map instance, inserting items into
+// the map, causing an eviction of the least-recently-used item, and removing
+// an item.
+func Example_basicMapUsage() {
+	// Create a new map instance with the desired limit.
+	const maxItems = 100
+	m := lru.NewMap[int, string](maxItems)
+
+	// Insert items into the map.
+	for i := 0; i < maxItems; i++ {
+		m.Put(i, fmt.Sprintf("item %d", i))
+	}
+
+	// At this point, the map has reached the limit, so the first entry will
+	// still be a member of the map.
+	value, ok := m.Get(0)
+	if !ok {
+		fmt.Println("map does not contain expected item 0")
+		return
+	}
+	fmt.Println(value)
+
+	// Adding another item will evict the least-recently-used item, which will
+	// be the key 1 since 0 was just accessed above.
+	m.Put(maxItems+1, "new item")
+	if m.Contains(1) {
+		fmt.Println("map contains unexpected item 1")
+		return
+	}
+
+	// Remove an item from the map.
+	m.Delete(3)
+	if m.Contains(3) {
+		fmt.Println("map contains unexpected item 3")
+		return
+	}
+
+	// Output:
+	// item 0
+}
+
+// This example demonstrates creating a new map instance that limits the total
+// size of the values it holds, expires items after a time to live, and reports
+// evicted items.
+func Example_costAndExpiration() {
+	m := lru.NewMapWithConfig(lru.Config[string, []byte]{
+		Capacity: 1024,
+		TTL:      time.Hour,
+		Cost: func(key string, value []byte) uint64 {
+			return uint64(len(value))
+		},
+		OnEvict: func(key string, value []byte, reason lru.EvictReason) {
+			fmt.Printf("evicted %s (%v)\n", key, reason)
+		},
+	})
+
+	// Adding the third item exceeds the capacity, so the least-recently-used
+	// item is evicted.
+	m.Put("a", make([]byte, 512))
+	m.Put("b", make([]byte, 256))
+	m.Put("c", make([]byte, 512))
+	fmt.Println("total size:", m.Cost())
+
+	// Items may also override the default time to live.
+	m.PutWithTTL("d", make([]byte, 128), time.Minute)
+
+	// Output:
+	// evicted a (capacity)
+	// total size: 768
+}
@@ -0,0 +1,486 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictReason identifies why an item was evicted from a cache.
+type EvictReason uint8
+
+const (
+	// EvictCapacity indicates an item was evicted because it was the
+	// least-recently-used item when room was needed for another item.
+	EvictCapacity EvictReason = iota
+
+	// EvictExpired indicates an item was evicted because its time to live
+	// elapsed.
+	EvictExpired
+)
+
+// String returns the EvictReason as a human-readable string.
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+// Config houses the parameters that define the behavior of a Map.  Only the
+// capacity is required.
+type Config[K comparable, V any] struct {
+	// Capacity is the maximum total cost of all items in the cache.  Since
+	// every item has a cost of one when no cost function is provided, it is
+	// the maximum number of items in that case.
+	Capacity uint64
+
+	// TTL is the default time to live for items added via Put.  A value of
+	// zero means items do not expire.
+	TTL time.Duration
+
+	// Cost optionally returns the cost of the provided item.  Every item has a
+	// cost of one when it is nil.
+	//
+	// NOTE: The function is invoked while the internal lock is held, so it
+	// must not access the cache.
+	Cost func(key K, value V) uint64
+
+	// OnEvict is optionally invoked with each item that is evicted due to
+	// capacity constraints or expiration along with the reason.  It is not
+	// invoked for items that are explicitly deleted or replaced.
+	//
+	// The function is invoked after the internal lock is released, so it may
+	// safely access the cache.
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+// element is an item in a Map along with the doubly-linked list pointers used
+// to track its usage order.
+type element[K comparable, V any] struct {
+	prev, next *element[K, V]
+	key        K
+	value      V
+	cost       uint64
+	expires    time.Time // zero when the item never expires
+}
+
+// expired returns whether or not the element has expired as of the provided
+// time.
+func (e *element[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// evicted is an item that was evicted from a Map along with the reason so the
+// eviction callback can be invoked after the lock is released.
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// Map provides a concurrency safe least-recently-used cache of key/value pairs
+// with nearly O(1) lookups, inserts, and deletions.  The cache is limited to a
+// maximum total cost of items with eviction of the least-recently-used items
+// when the limit is exceeded.  Items may also optionally expire.  See Config
+// for details.
+//
+// The NewMap or NewMapWithConfig functions must be used to create a usable
+// cache since the zero value of this struct is not valid.
+type Map[K comparable, V any] struct {
+	mtx   sync.RWMutex
+	items map[K]*element[K, V] // nearly O(1) lookups
+
+	// root is the sentinel of the circular doubly-linked list that tracks
+	// the usage order of the items for O(1) insert, update, and delete.  Its
+	// next element is the most-recently-used item and its previous element is
+	// the least-recently-used item.
+	root element[K, V]
+
+	// totalCost is the total cost of all items in the cache.
+	totalCost uint64
+
+	cfg Config[K, V]
+
+	// now returns the current time.  It is only overridden by the tests.
+	now func() time.Time
+}
+
+// NewMap returns an initialized and empty LRU cache of key/value pairs that is
+// limited to the provided maximum number of items that never expire.  See
+// the documentation for Map for more details.
+func NewMap[K comparable, V any](capacity uint64) *Map[K, V] {
+	return NewMapWithConfig(Config[K, V]{Capacity: capacity})
+}
+
+// NewMapWithConfig returns an initialized and empty LRU cache of key/value
+// pairs with the behavior defined by the provided config.  See the
+// documentation for Map and Config for more details.
+func NewMapWithConfig[K comparable, V any](cfg Config[K, V]) *Map[K, V] {
+	m := &Map[K, V]{
+		items: make(map[K]*element[K, V]),
+		cfg:   cfg,
+		now:   time.Now,
+	}
+	m.root.next = &m.root
+	m.root.prev = &m.root
+	return m
+}
+
+// pushFront inserts the provided element at the front of the usage list thereby
+// marking it most recently used.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) pushFront(e *element[K, V]) {
+	e.prev = &m.root
+	e.next = m.root.next
+	m.root.next.prev = e
+	m.root.next = e
+}
+
+// unlink removes the provided element from the usage list.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) unlink(e *element[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+}
+
+// moveToFront moves the provided element to the front of the usage list thereby
+// marking it most recently used.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) moveToFront(e *element[K, V]) {
+	if m.root.next == e {
+		return
+	}
+	m.unlink(e)
+	m.pushFront(e)
+}
+
+// remove removes the provided element from the cache.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) remove(e *element[K, V]) {
+	m.unlink(e)
+	delete(m.items, e.key)
+	m.totalCost -= e.cost
+}
+
+// evict removes the provided element from the cache and appends it to the
+// provided evicted items along with the reason when there is an eviction
+// callback.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) evict(e *element[K, V], reason EvictReason, evictions []evicted[K, V]) []evicted[K, V] {
+	m.remove(e)
+	if m.cfg.OnEvict == nil {
+		return evictions
+	}
+	return append(evictions, evicted[K, V]{e.key, e.value, reason})
+}
+
+// notify invokes the eviction callback for each of the provided evicted items.
+//
+// This function MUST be called without the lock held.
+func (m *Map[K, V]) notify(evictions []evicted[K, V]) {
+	for _, e := range evictions {
+		m.cfg.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// lookup returns the element for the provided key when it exists and has not
+// expired.  Expired elements are evicted and appended to the provided evicted
+// items.
+//
+// This function MUST be called with the lock held.
+func (m *Map[K, V]) lookup(key K, evictions []evicted[K, V]) (*element[K, V], []evicted[K, V]) {
+	e, ok := m.items[key]
+	if !ok {
+		return nil, evictions
+	}
+	if e.expired(m.now()) {
+		return nil, m.evict(e, EvictExpired, evictions)
+	}
+	return e, evictions
+}
+
+// Put adds the provided key/value pair to the cache with the default time to
+// live from the config and handles eviction of the least-recently-used items
+// if adding the new item would exceed the capacity.  Adding an existing key
+// replaces its value and makes it the most recently used item.
+//
+// Items whose cost exceeds the capacity are not added, and, in that case, any
+// existing item for the key is removed since its value is stale.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Put(key K, value V) {
+	m.PutWithTTL(key, value, m.cfg.TTL)
+}
+
+// PutWithTTL adds the provided key/value pair to the cache such that it expires
+// after the provided time to live and handles eviction of the
+// least-recently-used items if adding the new item would exceed the capacity.
+// A time to live of zero means the item does not expire.  Adding an existing
+// key replaces its value and time to live and makes it the most recently used
+// item.
+//
+// Items whose cost exceeds the capacity are not added, and, in that case, any
+// existing item for the key is removed since its value is stale.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = m.now().Add(ttl)
+	}
+
+	m.mtx.Lock()
+	cost := uint64(1)
+	if m.cfg.Cost != nil {
+		cost = m.cfg.Cost(key, value)
+	}
+
+	// Remove any existing entry for the key since its cost might differ.  Its
+	// element is reused below to avoid a new allocation.
+	e, exists := m.items[key]
+	if exists {
+		m.remove(e)
+	}
+
+	// Nothing more to do when the item can never fit in the cache.
+	if cost > m.cfg.Capacity {
+		m.mtx.Unlock()
+		return
+	}
+
+	// Evict the least-recently-used items (back of the list) until there is
+	// room for the new item.
+	var evictions []evicted[K, V]
+	now := m.now()
+	for m.totalCost+cost > m.cfg.Capacity {
+		lru := m.root.prev
+		reason := EvictCapacity
+		if lru.expired(now) {
+			reason = EvictExpired
+		}
+		evictions = m.evict(lru, reason, evictions)
+	}
+
+	if !exists {
+		e = &element[K, V]{key: key}
+	}
+	e.value = value
+	e.cost = cost
+	e.expires = expires
+	m.pushFront(e)
+	m.items[key] = e
+	m.totalCost += cost
+	m.mtx.Unlock()
+
+	m.notify(evictions)
+}
+
+// Get returns the value associated with the provided key when it exists and
+// has not expired and makes it the most recently used item.  The second return
+// value indicates whether or not the item exists.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	m.mtx.Lock()
+	e, evictions := m.lookup(key, nil)
+	var value V
+	if e != nil {
+		m.moveToFront(e)
+		value = e.value
+	}
+	m.mtx.Unlock()
+
+	m.notify(evictions)
+	return value, e != nil
+}
+
+// Peek returns the value associated with the provided key when it exists and
+// has not expired without modifying its usage order.  The second return value
+// indicates whether or not the item exists.
+//
+// Since the usage order is not modified, lookups of items that have not
+// expired only require a shared lock and therefore do not contend with each
+// other.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Peek(key K) (V, bool) {
+	m.mtx.RLock()
+	e, ok := m.items[key]
+	if !ok || !e.expired(m.now()) {
+		var value V
+		if ok {
+			value = e.value
+		}
+		m.mtx.RUnlock()
+		return value, ok
+	}
+	m.mtx.RUnlock()
+
+	// Evict the expired item under the exclusive lock.  Note that it is looked
+	// up again since it might have been replaced or removed in the meantime.
+	m.mtx.Lock()
+	e, evictions := m.lookup(key, nil)
+	var value V
+	if e != nil {
+		value = e.value
+	}
+	m.mtx.Unlock()
+
+	m.notify(evictions)
+	return value, e != nil
+}
+
+// Contains returns whether or not the provided key is a member of the cache and
+// has not expired.  Members are made the most recently used item.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Contains(key K) bool {
+	m.mtx.Lock()
+	e, evictions := m.lookup(key, nil)
+	if e != nil {
+		m.moveToFront(e)
+	}
+	m.mtx.Unlock()
+
+	m.notify(evictions)
+	return e != nil
+}
+
+// Delete deletes the item associated with the provided key from the cache (if
+// it exists) and returns whether or not it existed.  The eviction callback is
+// not invoked.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Delete(key K) bool {
+	m.mtx.Lock()
+	e, exists := m.items[key]
+	if exists {
+		m.remove(e)
+	}
+	m.mtx.Unlock()
+	return exists
+}
+
+// DeleteFunc deletes all items from the cache for which the provided predicate
+// returns true and returns the number of items deleted.  The eviction callback
+// is not invoked.
+//
+// NOTE: The predicate is invoked while the internal lock is held, so it must
+// not access the cache.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) DeleteFunc(predicate func(key K, value V) bool) int {
+	m.mtx.Lock()
+	var numDeleted int
+	for e := m.root.next; e != &m.root; {
+		next := e.next
+		if predicate(e.key, e.value) {
+			m.remove(e)
+			numDeleted++
+		}
+		e = next
+	}
+	m.mtx.Unlock()
+	return numDeleted
+}
+
+// Range invokes the provided function with each item in the cache that has not
+// expired, from the most recently used to the least recently used, until it
+// returns false.  The usage order of the items is not modified.
+//
+// NOTE: The function is invoked while the internal lock is held, so it must
+// not access the cache.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.now()
+	for e := m.root.next; e != &m.root; e = e.next {
+		if e.expired(now) {
+			continue
+		}
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// EvictExpired evicts all expired items from the cache and returns the number
+// of items evicted.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) EvictExpired() int {
+	m.mtx.Lock()
+	var numEvicted int
+	var evictions []evicted[K, V]
+	now := m.now()
+	for e := m.root.next; e != &m.root; {
+		next := e.next
+		if e.expired(now) {
+			evictions = m.evict(e, EvictExpired, evictions)
+			numEvicted++
+		}
+		e = next
+	}
+	m.mtx.Unlock()
+
+	m.notify(evictions)
+	return numEvicted
+}
+
+// Clear removes all items from the cache.  The eviction callback is not
+// invoked.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Clear() {
+	m.mtx.Lock()
+	m.items = make(map[K]*element[K, V])
+	m.root.next = &m.root
+	m.root.prev = &m.root
+	m.totalCost = 0
+	m.mtx.Unlock()
+}
+
+// Len returns the number of items in the cache.  Note that this includes items
+// that have expired but have not been evicted yet.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Len() int {
+	m.mtx.RLock()
+	n := len(m.items)
+	m.mtx.RUnlock()
+	return n
+}
+
+// Cost returns the total cost of all items in the cache.  Note that this
+// includes items that have expired but have not been evicted yet.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Cost() uint64 {
+	m.mtx.RLock()
+	cost := m.totalCost
+	m.mtx.RUnlock()
+	return cost
+}
+
+// Capacity returns the maximum total cost of all items in the cache.
+//
+// This function is safe for concurrent access.
+func (m *Map[K, V]) Capacity() uint64 {
+	return m.cfg.Capacity
+}
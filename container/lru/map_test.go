@@ -0,0 +1,403 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockClock provides a manually advanced clock for testing expiration.
+type mockClock struct {
+	now time.Time
+}
+
+// Now returns the current time of the mock clock.
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the mock clock forward by the provided duration.
+func (c *mockClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// keys returns the keys of all unexpired items in the provided map ordered from
+// most to least recently used.
+func keys[K comparable, V any](m *Map[K, V]) []K {
+	var result []K
+	m.Range(func(key K, value V) bool {
+		result = append(result, key)
+		return true
+	})
+	return result
+}
+
+// TestMap ensures the LRU Map behaves as expected including limiting, eviction
+// of least-recently-used entries, specific entry removal, and existence tests.
+func TestMap(t *testing.T) {
+	const numItems = 10
+
+	tests := []struct {
+		name     string
+		capacity uint64
+	}{
+		{name: "capacity 0", capacity: 0},
+		{name: "capacity 1", capacity: 1},
+		{name: "capacity 5", capacity: 5},
+		{name: "capacity one less than available", capacity: numItems - 1},
+		{name: "capacity all available", capacity: numItems},
+		{name: "capacity more than available", capacity: numItems + 1},
+	}
+
+	for _, test := range tests {
+		// Create a new map limited by the specified test capacity and add all
+		// of the test items.  This causes eviction when there are more test
+		// items than the capacity.
+		m := NewMap[int, string](test.capacity)
+		for i := 0; i < numItems; i++ {
+			m.Put(i, string(rune('a'+i)))
+		}
+
+		// Ensure the expected number of most recent entries exist with the
+		// expected values and the others do not.
+		numExpected := numItems
+		if test.capacity < numItems {
+			numExpected = int(test.capacity)
+		}
+		if m.Len() != numExpected {
+			t.Errorf("%q: unexpected len -- got %d, want %d", test.name,
+				m.Len(), numExpected)
+			continue
+		}
+		if m.Cost() != uint64(numExpected) {
+			t.Errorf("%q: unexpected cost -- got %d, want %d", test.name,
+				m.Cost(), numExpected)
+			continue
+		}
+		for i := 0; i < numItems; i++ {
+			value, ok := m.Peek(i)
+			wantOk := i >= numItems-numExpected
+			if ok != wantOk {
+				t.Errorf("%q: unexpected existence for %d -- got %v, want %v",
+					test.name, i, ok, wantOk)
+				continue
+			}
+			if wantValue := string(rune('a' + i)); ok && value != wantValue {
+				t.Errorf("%q: unexpected value for %d -- got %q, want %q",
+					test.name, i, value, wantValue)
+			}
+		}
+
+		// Access the entry that should currently be the least-recently used
+		// entry so it becomes the most-recently used entry, then force an
+		// eviction by adding an entry that doesn't exist and ensure the
+		// evicted entry is the new least-recently used entry.
+		//
+		// This check needs at least 2 entries.
+		if numExpected > 1 && test.capacity <= numItems {
+			origLRU := numItems - numExpected
+			if _, ok := m.Get(origLRU); !ok {
+				t.Errorf("%q: entry %d does not exist", test.name, origLRU)
+				continue
+			}
+			m.Put(numItems+1, "new")
+			if !m.Contains(origLRU) {
+				t.Errorf("%q: entry %d does not exist", test.name, origLRU)
+				continue
+			}
+			if m.Contains(origLRU + 1) {
+				t.Errorf("%q: entry %d exists", test.name, origLRU+1)
+				continue
+			}
+		}
+
+		// Ensure replacing the value of an existing entry works and does not
+		// change the number of entries.
+		if numExpected > 0 {
+			wantLen := m.Len()
+			m.Put(numItems-1, "replaced")
+			if value, _ := m.Get(numItems - 1); value != "replaced" {
+				t.Errorf("%q: unexpected replaced value -- got %q", test.name,
+					value)
+				continue
+			}
+			if m.Len() != wantLen {
+				t.Errorf("%q: unexpected len after replace -- got %d, want %d",
+					test.name, m.Len(), wantLen)
+				continue
+			}
+		}
+
+		// Delete all of the entries and ensure they no longer exist.
+		for i := 0; i < numItems+2; i++ {
+			m.Delete(i)
+			if m.Contains(i) {
+				t.Errorf("%q: deleted entry %d exists", test.name, i)
+				continue
+			}
+		}
+		if m.Len() != 0 || m.Cost() != 0 {
+			t.Errorf("%q: unexpected len %d and cost %d after delete",
+				test.name, m.Len(), m.Cost())
+		}
+	}
+}
+
+// TestMapOrder ensures the usage order of the items is updated by the methods
+// that are documented to do so and not by the others.
+func TestMapOrder(t *testing.T) {
+	m := NewMap[int, int](5)
+	for i := 0; i < 5; i++ {
+		m.Put(i, i)
+	}
+	if got, want := keys(m), []int{4, 3, 2, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected order -- got %v, want %v", got, want)
+	}
+
+	m.Get(0)
+	m.Contains(2)
+	m.Peek(1)
+	m.Put(3, 30)
+	if got, want := keys(m), []int{3, 2, 0, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected order -- got %v, want %v", got, want)
+	}
+
+	// Ensure range stops when the function returns false.
+	var visited int
+	m.Range(func(key, value int) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Fatalf("unexpected number of items visited -- got %d, want 2",
+			visited)
+	}
+
+	// Ensure deleting with a predicate removes the expected items.
+	numDeleted := m.DeleteFunc(func(key, value int) bool {
+		return key%2 == 0
+	})
+	if numDeleted != 3 {
+		t.Fatalf("unexpected number of deleted items -- got %d, want 3",
+			numDeleted)
+	}
+	if got, want := keys(m), []int{3, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+
+	m.Clear()
+	if m.Len() != 0 || m.Cost() != 0 || len(keys(m)) != 0 {
+		t.Fatalf("unexpected items after clear -- got %v", keys(m))
+	}
+	m.Put(1, 1)
+	if got, want := keys(m), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+}
+
+// TestMapCost ensures the LRU Map limits the total cost of the items when a
+// cost function is provided and evicts as many items as needed.
+func TestMapCost(t *testing.T) {
+	var evictions []string
+	m := NewMapWithConfig(Config[string, []byte]{
+		Capacity: 10,
+		Cost: func(key string, value []byte) uint64 {
+			return uint64(len(value))
+		},
+		OnEvict: func(key string, value []byte, reason EvictReason) {
+			if reason != EvictCapacity {
+				t.Errorf("unexpected evict reason for %q: %v", key, reason)
+			}
+			evictions = append(evictions, key)
+		},
+	})
+
+	m.Put("a", make([]byte, 3))
+	m.Put("b", make([]byte, 3))
+	m.Put("c", make([]byte, 3))
+	if m.Cost() != 9 {
+		t.Fatalf("unexpected cost -- got %d, want 9", m.Cost())
+	}
+
+	// Adding an item that needs more room than the least-recently-used item
+	// provides must evict multiple items.
+	m.Put("d", make([]byte, 5))
+	if got, want := keys(m), []string{"d", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+	if got, want := evictions, []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected evictions -- got %v, want %v", got, want)
+	}
+	if m.Cost() != 8 {
+		t.Fatalf("unexpected cost -- got %d, want 8", m.Cost())
+	}
+
+	// Replacing an item with one that has a different cost must update the
+	// total cost without evicting the item being replaced.
+	evictions = nil
+	m.Put("c", make([]byte, 5))
+	if m.Cost() != 10 || len(evictions) != 0 {
+		t.Fatalf("unexpected cost %d and evictions %v", m.Cost(), evictions)
+	}
+
+	// Items that exceed the capacity must not be added and must remove any
+	// existing stale entry without evicting any other items.
+	m.Put("d", make([]byte, 11))
+	if got, want := keys(m), []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+	if m.Cost() != 5 || len(evictions) != 0 {
+		t.Fatalf("unexpected cost %d and evictions %v", m.Cost(), evictions)
+	}
+}
+
+// TestMapExpiration ensures items expire after their time to live, that
+// expired items are evicted with the expected reason, and that the callback
+// may access the map.
+func TestMapExpiration(t *testing.T) {
+	clock := &mockClock{now: time.Unix(1700000000, 0)}
+	evictions := make(map[int]EvictReason)
+	var m *Map[int, int]
+	m = NewMapWithConfig(Config[int, int]{
+		Capacity: 4,
+		TTL:      time.Minute,
+		OnEvict: func(key, value int, reason EvictReason) {
+			evictions[key] = reason
+			_ = m.Len() // Ensure the lock is not held.
+		},
+	})
+	m.now = clock.Now
+
+	m.Put(0, 0)
+	m.PutWithTTL(1, 1, time.Second)
+	m.PutWithTTL(2, 2, 0)
+	m.Put(3, 3)
+
+	// Ensure nothing has expired before the time to live elapses.
+	clock.Advance(time.Second - 1)
+	if n := m.EvictExpired(); n != 0 {
+		t.Fatalf("unexpected number of expired items -- got %d, want 0", n)
+	}
+
+	// Ensure items expire once their time to live elapses and are evicted
+	// lazily when they are accessed.
+	clock.Advance(1)
+	if m.Contains(1) {
+		t.Fatal("expired item 1 exists")
+	}
+	if got, want := evictions, map[int]EvictReason{1: EvictExpired}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected evictions -- got %v, want %v", got, want)
+	}
+	if m.Len() != 3 {
+		t.Fatalf("unexpected len -- got %d, want 3", m.Len())
+	}
+
+	// Ensure expired items that are not accessed are not visible and are
+	// evicted with the expected reason when they become the least-recently
+	// used item while making room for new items.
+	clock.Advance(time.Minute)
+	if got, want := keys(m), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+	if _, ok := m.Peek(3); ok {
+		t.Fatal("expired item 3 exists")
+	}
+	m.Put(4, 4)
+	m.Put(5, 5)
+	m.Put(6, 6)
+	want := map[int]EvictReason{
+		0: EvictExpired,
+		1: EvictExpired,
+		3: EvictExpired,
+	}
+	if !reflect.DeepEqual(evictions, want) {
+		t.Fatalf("unexpected evictions -- got %v, want %v", evictions, want)
+	}
+	m.Put(7, 7)
+	want[2] = EvictCapacity
+	if !reflect.DeepEqual(evictions, want) {
+		t.Fatalf("unexpected evictions -- got %v, want %v", evictions, want)
+	}
+
+	// Ensure replacing an item resets its time to live and all expired items
+	// are evicted proactively.
+	clock.Advance(time.Minute - 1)
+	m.Put(4, 40)
+	clock.Advance(1)
+	if n := m.EvictExpired(); n != 3 {
+		t.Fatalf("unexpected number of expired items -- got %d, want 3", n)
+	}
+	if got, want := keys(m), []int{4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected items -- got %v, want %v", got, want)
+	}
+	if value, ok := m.Get(4); !ok || value != 40 {
+		t.Fatalf("unexpected value -- got %d (exists %v), want 40", value, ok)
+	}
+}
+
+// TestMapConcurrentPeek ensures concurrent lookups that do not modify the usage
+// order are safe while other goroutines modify the map and that they do not
+// prevent eviction of the least-recently-used items.
+func TestMapConcurrentPeek(t *testing.T) {
+	const numItems = 100
+	m := NewMap[int, int](numItems)
+	for i := 0; i < numItems; i++ {
+		m.Put(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < numItems; i++ {
+				if value, ok := m.Peek(i); ok && value != i {
+					t.Errorf("unexpected value for %d: %d", i, value)
+				}
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := numItems; i < numItems+numItems/2; i++ {
+			m.Put(i, i)
+		}
+	}()
+	wg.Wait()
+
+	// The original least-recently-used items must have been evicted despite
+	// the lookups.
+	for i := 0; i < numItems/2; i++ {
+		if m.Contains(i) {
+			t.Fatalf("item %d was not evicted", i)
+		}
+	}
+	if m.Len() != numItems {
+		t.Fatalf("unexpected len -- got %d, want %d", m.Len(), numItems)
+	}
+}
+
+// TestEvictReasonStringer tests the stringized output for the EvictReason
+// type.
+func TestEvictReasonStringer(t *testing.T) {
+	tests := []struct {
+		in   EvictReason
+		want string
+	}{
+		{EvictCapacity, "capacity"},
+		{EvictExpired, "expired"},
+		{0xff, "unknown"},
+	}
+
+	for _, test := range tests {
+		if got := test.in.String(); got != test.want {
+			t.Errorf("String(%d): got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package lru
+
+import "time"
+
+// Set provides a concurrency safe least-recently-used cache of items with
+// nearly O(1) lookups, inserts, and deletions.  It is a convenience wrapper
+// around a Map with no associated values, so see Map for more details.
+//
+// The NewSet or NewSetWithTTL functions must be used to create a usable cache
+// since the zero value of this struct is not valid.
+type Set[T comparable] struct {
+	m *Map[T, struct{}]
+}
+
+// NewSet returns an initialized and empty LRU cache of items that is limited to
+// the provided maximum number of items that never expire.
+func NewSet[T comparable](capacity uint64) *Set[T] {
+	return &Set[T]{m: NewMap[T, struct{}](capacity)}
+}
+
+// NewSetWithTTL returns an initialized and empty LRU cache of items that is
+// limited to the provided maximum number of items that each expire after the
+// provided time to live.
+func NewSetWithTTL[T comparable](capacity uint64, ttl time.Duration) *Set[T] {
+	cfg := Config[T, struct{}]{Capacity: capacity, TTL: ttl}
+	return &Set[T]{m: NewMapWithConfig(cfg)}
+}
+
+// Put adds the provided item to the cache and handles eviction of the
+// least-recently-used item if adding the new item would exceed the capacity.
+// Adding an existing item makes it the most recently used item and resets its
+// time to live.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) Put(item T) {
+	s.m.Put(item, struct{}{})
+}
+
+// Contains returns whether or not the provided item is a member of the cache
+// and has not expired.  Members are made the most recently used item.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) Contains(item T) bool {
+	return s.m.Contains(item)
+}
+
+// Delete deletes the provided item from the cache (if it exists) and returns
+// whether or not it existed.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) Delete(item T) bool {
+	return s.m.Delete(item)
+}
+
+// EvictExpired evicts all expired items from the cache and returns the number
+// of items evicted.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) EvictExpired() int {
+	return s.m.EvictExpired()
+}
+
+// Clear removes all items from the cache.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) Clear() {
+	s.m.Clear()
+}
+
+// Len returns the number of items in the cache.  Note that this includes items
+// that have expired but have not been evicted yet.
+//
+// This function is safe for concurrent access.
+func (s *Set[T]) Len() int {
+	return s.m.Len()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSet ensures the LRU Set behaves as expected including limiting, eviction
+// of least-recently-used entries, expiration, and specific entry removal.
+func TestSet(t *testing.T) {
+	s := NewSet[uint64](3)
+	for i := uint64(0); i < 3; i++ {
+		s.Put(i)
+	}
+
+	// Access the least-recently-used item so it becomes the most-recently-used
+	// item and ensure adding a new item evicts the next one.
+	if !s.Contains(0) {
+		t.Fatal("item 0 does not exist")
+	}
+	s.Put(3)
+	if s.Contains(1) {
+		t.Fatal("item 1 exists")
+	}
+	for _, item := range []uint64{0, 2, 3} {
+		if !s.Contains(item) {
+			t.Fatalf("item %d does not exist", item)
+		}
+	}
+	if s.Len() != 3 {
+		t.Fatalf("unexpected len -- got %d, want 3", s.Len())
+	}
+
+	if !s.Delete(2) || s.Delete(2) || s.Contains(2) {
+		t.Fatal("unexpected result when deleting item 2")
+	}
+	s.Clear()
+	if s.Len() != 0 || s.Contains(0) {
+		t.Fatal("items exist after clear")
+	}
+
+	// Ensure items expire after the time to live.
+	clock := &mockClock{now: time.Unix(1700000000, 0)}
+	s = NewSetWithTTL[uint64](3, time.Minute)
+	s.m.now = clock.Now
+	s.Put(0)
+	clock.Advance(time.Second)
+	s.Put(1)
+	clock.Advance(time.Minute - time.Second)
+	if s.Contains(0) || !s.Contains(1) {
+		t.Fatal("unexpected items after expiration")
+	}
+	clock.Advance(time.Second)
+	if n := s.EvictExpired(); n != 1 || s.Len() != 0 {
+		t.Fatalf("unexpected expired items %d and len %d", n, s.Len())
+	}
+}
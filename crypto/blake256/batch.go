@@ -0,0 +1,202 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import "encoding/binary"
+
+// laneState houses the chain values of the messages being hashed in parallel
+// by the multi-buffer compression function in a transposed layout such that
+// word i of lane j is at [i][j].  This allows the compression function to
+// process the same word of every lane with a single vector instruction.
+type laneState [8][batchLanes]uint32
+
+// laneBlock houses the message blocks being compressed in parallel by the
+// multi-buffer compression function in the same transposed layout as
+// laneState.  The words are already converted from big endian.
+type laneBlock [16][batchLanes]uint32
+
+// laneCounter houses the low and high words of the counters of the message
+// blocks being compressed in parallel by the multi-buffer compression function
+// in the same transposed layout as laneState.  A counter of zero indicates a
+// block that only consists of padding.
+type laneCounter [2][batchLanes]uint32
+
+// laneCompressFunc describes a multi-buffer compression function that
+// compresses the provided message blocks into the provided chain values of
+// each lane with a salt of zero.
+type laneCompressFunc func(h *laneState, m *laneBlock, t *laneCounter)
+
+// compressLanesGeneric is a multi-buffer compression function that compresses
+// each lane in turn with the pure Go compression function.  It is primarily
+// useful for testing the multi-buffer scheduling on all architectures.
+func compressLanesGeneric(h *laneState, m *laneBlock, t *laneCounter) {
+	var d digest
+	var p [BlockSize]byte
+	for lane := 0; lane < batchLanes; lane++ {
+		for i := range d.h {
+			d.h[i] = h[i][lane]
+		}
+		for i := 0; i < len(m); i++ {
+			binary.BigEndian.PutUint32(p[i*4:], m[i][lane])
+		}
+
+		// The compression function increments the counter prior to use.
+		counter := uint64(t[1][lane])<<32 | uint64(t[0][lane])
+		d.t = counter - 512
+		d.nullt = counter == 0
+		block(&d, p[:])
+		for i := range d.h {
+			h[i][lane] = d.h[i]
+		}
+	}
+}
+
+// batchMsg houses the state needed to feed a single message through a lane of
+// a multi-buffer compression function.
+type batchMsg struct {
+	index     int                 // index of the message in the batch
+	data      []byte              // full blocks of the message
+	tail      [2 * BlockSize]byte // padded final block(s) of the message
+	counters  [2]uint64           // counters of the tail blocks
+	numBlocks int                 // total number of blocks including the tail
+	next      int                 // index of the next block to compress
+}
+
+// reset prepares the message to be fed through a lane by padding it and
+// calculating the counters of its final blocks.
+func (msg *batchMsg) reset(index int, data []byte) {
+	// The message is padded with a one bit followed by zeros until its length
+	// is 8 bytes less than a multiple of the block size, at which point a
+	// second one bit is set as the final padding bit followed by the length of
+	// the message in bits as a big-endian uint64.  This means the tail
+	// consists of two blocks when there are more than 55 bytes remaining
+	// after the full blocks.
+	fullLen := len(data) &^ (BlockSize - 1)
+	remaining := len(data) - fullLen
+	tailLen := BlockSize
+	if remaining > BlockSize-9 {
+		tailLen = 2 * BlockSize
+	}
+	bits := uint64(len(data)) << 3
+	msg.tail = [2 * BlockSize]byte{}
+	copy(msg.tail[:], data[fullLen:])
+	msg.tail[remaining] = 0x80
+	msg.tail[tailLen-9] |= 0x01
+	binary.BigEndian.PutUint64(msg.tail[tailLen-8:], bits)
+
+	// The counter of each block is the number of message bits hashed through
+	// the end of that block except for blocks that only consist of padding,
+	// which have a counter of zero.
+	msg.counters = [2]uint64{}
+	if remaining > 0 {
+		msg.counters[0] = bits
+	}
+
+	msg.index = index
+	msg.data = data[:fullLen]
+	msg.numBlocks = (fullLen + tailLen) / BlockSize
+	msg.next = 0
+}
+
+// nextBlock returns the next block of the message to compress along with its
+// counter.
+func (msg *batchMsg) nextBlock() ([]byte, uint64) {
+	numFull := len(msg.data) / BlockSize
+	if msg.next < numFull {
+		offset := msg.next * BlockSize
+		return msg.data[offset : offset+BlockSize], uint64(offset+BlockSize) << 3
+	}
+	i := msg.next - numFull
+	return msg.tail[i*BlockSize : (i+1)*BlockSize], msg.counters[i]
+}
+
+// sum256Lanes calculates the BLAKE-256 checksums of each of the provided data
+// slices with the provided multi-buffer compression function and stores them
+// in the provided digests, which must be at least as long as the data.
+//
+// Each lane of the compression function is assigned the next message that has
+// not been hashed yet as soon as it finishes hashing its current one.  This
+// keeps all of the lanes busy until the final messages even when the messages
+// have varying lengths.
+func sum256Lanes(digests [][Size]byte, data [][]byte, compress laneCompressFunc) {
+	var (
+		h        laneState
+		m        laneBlock
+		t        laneCounter
+		msgs     [batchLanes]batchMsg
+		inUse    [batchLanes]bool
+		nextData int
+	)
+	for {
+		// Assign the next messages to any idle lanes and load the next block
+		// of each message into its lane.
+		var numActive int
+		for lane := range msgs {
+			msg := &msgs[lane]
+			if !inUse[lane] {
+				if nextData >= len(data) {
+					continue
+				}
+				msg.reset(nextData, data[nextData])
+				for i := range h {
+					h[i][lane] = iv256[i]
+				}
+				inUse[lane] = true
+				nextData++
+			}
+			numActive++
+
+			p, counter := msg.nextBlock()
+			for i := range m {
+				m[i][lane] = binary.BigEndian.Uint32(p[i*4:])
+			}
+			t[0][lane] = uint32(counter)
+			t[1][lane] = uint32(counter >> 32)
+		}
+		if numActive == 0 {
+			return
+		}
+
+		// Compress the blocks and output the checksums of the messages that
+		// are complete.  The results of any idle lanes are ignored.
+		compress(&h, &m, &t)
+		for lane := range msgs {
+			msg := &msgs[lane]
+			if !inUse[lane] {
+				continue
+			}
+			msg.next++
+			if msg.next < msg.numBlocks {
+				continue
+			}
+			digest := &digests[msg.index]
+			for i := range h {
+				binary.BigEndian.PutUint32(digest[i*4:], h[i][lane])
+			}
+			inUse[lane] = false
+		}
+	}
+}
+
+// Sum256Batch returns the BLAKE-256 checksums of each of the provided data
+// slices in the same order.
+//
+// The result is identical to calling Sum256 for each of them.  However, when
+// the CPU supports the necessary SIMD instructions, multiple checksums are
+// calculated in parallel, which is significantly faster than calculating them
+// individually.  This makes it well suited to hashing a large number of
+// independent messages, such as the transactions of a block or the nodes of a
+// level of a merkle tree.
+func Sum256Batch(data [][]byte) [][Size]byte {
+	digests := make([][Size]byte, len(data))
+	if !hasLaneSIMD || len(data) < minLaneBatchSize {
+		for i := range data {
+			digests[i] = Sum256(data[i])
+		}
+		return digests
+	}
+	sum256Lanes(digests, data, compressLanes)
+	return digests
+}
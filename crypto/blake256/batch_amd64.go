@@ -0,0 +1,74 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !purego
+// +build !purego
+
+package blake256
+
+const (
+	// batchLanes is the number of messages hashed in parallel by the
+	// multi-buffer compression function.  AVX2 provides 256-bit vectors which
+	// hold 8 32-bit words.
+	batchLanes = 8
+
+	// minLaneBatchSize is the minimum number of messages for which the
+	// multi-buffer compression function is used.  Smaller batches are faster
+	// to hash individually.
+	minLaneBatchSize = 2
+)
+
+// hasLaneSIMD indicates whether or not the CPU supports the instructions
+// required by the multi-buffer compression function.
+var hasLaneSIMD = supportsAVX2()
+
+// cpuid executes the CPUID instruction with the provided function and
+// sub-function and returns the resulting registers.
+//
+// It is implemented in batch_amd64.s.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv returns the contents of the XCR0 extended control register.
+//
+// It is implemented in batch_amd64.s.
+func xgetbv() (eax, edx uint32)
+
+// supportsAVX2 returns whether or not both the CPU and the operating system
+// support AVX2 instructions.  The operating system must save the YMM registers
+// on context switches for them to be usable.
+func supportsAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+
+	// Ensure the OS supports XSAVE and has enabled saving the XMM and YMM
+	// registers (XCR0 bits 1 and 2).
+	const osxsaveBit, avxBit = 1 << 27, 1 << 28
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&osxsaveBit == 0 || ecx1&avxBit == 0 {
+		return false
+	}
+	if xcr0, _ := xgetbv(); xcr0&0x6 != 0x6 {
+		return false
+	}
+
+	const avx2Bit = 1 << 5
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&avx2Bit != 0
+}
+
+// compressLanesAVX2 compresses the provided message blocks into the provided
+// chain values of each lane with a salt of zero using AVX2 instructions.
+//
+// It is implemented in batch_amd64.s.
+//
+//go:noescape
+func compressLanesAVX2(h *laneState, m *laneBlock, t *laneCounter)
+
+// compressLanes is the multi-buffer compression function for the current
+// architecture.
+func compressLanes(h *laneState, m *laneBlock, t *laneCounter) {
+	compressLanesAVX2(h, m, t)
+}
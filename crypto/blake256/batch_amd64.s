@@ -0,0 +1,296 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !purego
+// +build !purego
+
+#include "textflag.h"
+
+// consts houses the 16 BLAKE-256 constants.
+DATA consts<>+0x00(SB)/4, $0x243F6A88
+DATA consts<>+0x04(SB)/4, $0x85A308D3
+DATA consts<>+0x08(SB)/4, $0x13198A2E
+DATA consts<>+0x0c(SB)/4, $0x03707344
+DATA consts<>+0x10(SB)/4, $0xA4093822
+DATA consts<>+0x14(SB)/4, $0x299F31D0
+DATA consts<>+0x18(SB)/4, $0x082EFA98
+DATA consts<>+0x1c(SB)/4, $0xEC4E6C89
+DATA consts<>+0x20(SB)/4, $0x452821E6
+DATA consts<>+0x24(SB)/4, $0x38D01377
+DATA consts<>+0x28(SB)/4, $0xBE5466CF
+DATA consts<>+0x2c(SB)/4, $0x34E90C6C
+DATA consts<>+0x30(SB)/4, $0xC0AC29B7
+DATA consts<>+0x34(SB)/4, $0xC97C50DD
+DATA consts<>+0x38(SB)/4, $0x3F84D5B5
+DATA consts<>+0x3c(SB)/4, $0xB5470917
+GLOBL consts<>(SB), (NOPTR+RODATA), $64
+
+// rot16 is the byte shuffle mask that rotates each 32-bit word right by 16
+// bits.
+DATA rot16<>+0x00(SB)/8, $0x0504070601000302
+DATA rot16<>+0x08(SB)/8, $0x0D0C0F0E09080B0A
+DATA rot16<>+0x10(SB)/8, $0x0504070601000302
+DATA rot16<>+0x18(SB)/8, $0x0D0C0F0E09080B0A
+GLOBL rot16<>(SB), (NOPTR+RODATA), $32
+
+// rot8 is the byte shuffle mask that rotates each 32-bit word right by 8 bits.
+DATA rot8<>+0x00(SB)/8, $0x0407060500030201
+DATA rot8<>+0x08(SB)/8, $0x0C0F0E0D080B0A09
+DATA rot8<>+0x10(SB)/8, $0x0407060500030201
+DATA rot8<>+0x18(SB)/8, $0x0C0F0E0D080B0A09
+GLOBL rot8<>(SB), (NOPTR+RODATA), $32
+
+// G performs the BLAKE-256 G function on the 8 lanes of the state words a, b,
+// c, and d with the message words at mi and mj and the constants at offsets ci
+// and cj.  The c words are kept on the stack since there are not enough
+// registers to hold the entire state along with the temporaries TC and TX.
+#define G(a, b, c, d, mi, ci, mj, cj, TC, TX) \
+	VPBROADCASTD consts<>+ci(SB), TX; \
+	VPXOR        mi, TX, TX;          \
+	VPADDD       TX, a, a;            \
+	VPADDD       b, a, a;             \
+	VPXOR        a, d, d;             \
+	VPSHUFB      rot16<>(SB), d, d;   \
+	VMOVDQU      c, TC;               \
+	VPADDD       d, TC, TC;           \
+	VPXOR        TC, b, b;            \
+	VPSRLD       $12, b, TX;          \
+	VPSLLD       $20, b, b;           \
+	VPOR         TX, b, b;            \
+	VPBROADCASTD consts<>+cj(SB), TX; \
+	VPXOR        mj, TX, TX;          \
+	VPADDD       TX, a, a;            \
+	VPADDD       b, a, a;             \
+	VPXOR        a, d, d;             \
+	VPSHUFB      rot8<>(SB), d, d;    \
+	VPADDD       d, TC, TC;           \
+	VPXOR        TC, b, b;            \
+	VPSRLD       $7, b, TX;           \
+	VPSLLD       $25, b, b;           \
+	VPOR         TX, b, b;            \
+	VMOVDQU      TC, c
+
+// func compressLanesAVX2(h *laneState, m *laneBlock, t *laneCounter)
+TEXT ·compressLanesAVX2(SB), NOSPLIT, $128-24
+	MOVQ h+0(FP), DI
+	MOVQ m+8(FP), SI
+	MOVQ t+16(FP), DX
+
+	// Initialize the state.  Words 0-7 are the chain values, words 8-11 are
+	// the first four constants, and words 12-15 are the next four constants
+	// xored with the counter.  Words 8-11 are kept on the stack.
+	VMOVDQU 0(DI), Y0
+	VMOVDQU 32(DI), Y1
+	VMOVDQU 64(DI), Y2
+	VMOVDQU 96(DI), Y3
+	VMOVDQU 128(DI), Y4
+	VMOVDQU 160(DI), Y5
+	VMOVDQU 192(DI), Y6
+	VMOVDQU 224(DI), Y7
+	VPBROADCASTD consts<>+0(SB), Y12
+	VPBROADCASTD consts<>+4(SB), Y13
+	VPBROADCASTD consts<>+8(SB), Y14
+	VPBROADCASTD consts<>+12(SB), Y15
+	VMOVDQU Y12, 0(SP)
+	VMOVDQU Y13, 32(SP)
+	VMOVDQU Y14, 64(SP)
+	VMOVDQU Y15, 96(SP)
+	VMOVDQU 0(DX), Y12
+	VMOVDQU 32(DX), Y13
+	VPBROADCASTD consts<>+16(SB), Y8
+	VPBROADCASTD consts<>+20(SB), Y9
+	VPBROADCASTD consts<>+24(SB), Y10
+	VPBROADCASTD consts<>+28(SB), Y11
+	VPXOR Y12, Y8, Y8
+	VPXOR Y12, Y9, Y9
+	VPXOR Y13, Y10, Y10
+	VPXOR Y13, Y11, Y11
+
+	// Round 1.
+	G(Y0, Y4, 0(SP), Y8, 0(SI), 4, 32(SI), 0, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 64(SI), 12, 96(SI), 8, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 128(SI), 20, 160(SI), 16, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 192(SI), 28, 224(SI), 24, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 256(SI), 36, 288(SI), 32, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 320(SI), 44, 352(SI), 40, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 384(SI), 52, 416(SI), 48, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 448(SI), 60, 480(SI), 56, Y14, Y15)
+
+	// Round 2.
+	G(Y0, Y4, 0(SP), Y8, 448(SI), 40, 320(SI), 56, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 128(SI), 32, 256(SI), 16, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 288(SI), 60, 480(SI), 36, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 416(SI), 24, 192(SI), 52, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 32(SI), 48, 384(SI), 4, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 0(SI), 8, 64(SI), 0, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 352(SI), 28, 224(SI), 44, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 160(SI), 12, 96(SI), 20, Y14, Y15)
+
+	// Round 3.
+	G(Y0, Y4, 0(SP), Y8, 352(SI), 32, 256(SI), 44, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 384(SI), 0, 0(SI), 48, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 160(SI), 8, 64(SI), 20, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 480(SI), 52, 416(SI), 60, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 320(SI), 56, 448(SI), 40, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 96(SI), 24, 192(SI), 12, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 224(SI), 4, 32(SI), 28, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 288(SI), 16, 128(SI), 36, Y14, Y15)
+
+	// Round 4.
+	G(Y0, Y4, 0(SP), Y8, 224(SI), 36, 288(SI), 28, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 96(SI), 4, 32(SI), 12, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 416(SI), 48, 384(SI), 52, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 352(SI), 56, 448(SI), 44, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 64(SI), 24, 192(SI), 8, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 160(SI), 40, 320(SI), 20, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 128(SI), 0, 0(SI), 16, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 480(SI), 32, 256(SI), 60, Y14, Y15)
+
+	// Round 5.
+	G(Y0, Y4, 0(SP), Y8, 288(SI), 0, 0(SI), 36, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 160(SI), 28, 224(SI), 20, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 64(SI), 16, 128(SI), 8, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 320(SI), 60, 480(SI), 40, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 448(SI), 4, 32(SI), 56, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 352(SI), 48, 384(SI), 44, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 192(SI), 32, 256(SI), 24, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 96(SI), 52, 416(SI), 12, Y14, Y15)
+
+	// Round 6.
+	G(Y0, Y4, 0(SP), Y8, 64(SI), 48, 384(SI), 8, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 192(SI), 40, 320(SI), 24, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 0(SI), 44, 352(SI), 0, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 256(SI), 12, 96(SI), 32, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 128(SI), 52, 416(SI), 16, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 224(SI), 20, 160(SI), 28, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 480(SI), 56, 448(SI), 60, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 32(SI), 36, 288(SI), 4, Y14, Y15)
+
+	// Round 7.
+	G(Y0, Y4, 0(SP), Y8, 384(SI), 20, 160(SI), 48, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 32(SI), 60, 480(SI), 4, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 448(SI), 52, 416(SI), 56, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 128(SI), 40, 320(SI), 16, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 0(SI), 28, 224(SI), 0, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 192(SI), 12, 96(SI), 24, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 288(SI), 8, 64(SI), 36, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 256(SI), 44, 352(SI), 32, Y14, Y15)
+
+	// Round 8.
+	G(Y0, Y4, 0(SP), Y8, 416(SI), 44, 352(SI), 52, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 224(SI), 56, 448(SI), 28, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 384(SI), 4, 32(SI), 48, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 96(SI), 36, 288(SI), 12, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 160(SI), 0, 0(SI), 20, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 480(SI), 16, 128(SI), 60, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 256(SI), 24, 192(SI), 32, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 64(SI), 40, 320(SI), 8, Y14, Y15)
+
+	// Round 9.
+	G(Y0, Y4, 0(SP), Y8, 192(SI), 60, 480(SI), 24, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 448(SI), 36, 288(SI), 56, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 352(SI), 12, 96(SI), 44, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 0(SI), 32, 256(SI), 0, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 384(SI), 8, 64(SI), 48, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 416(SI), 28, 224(SI), 52, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 32(SI), 16, 128(SI), 4, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 320(SI), 20, 160(SI), 40, Y14, Y15)
+
+	// Round 10.
+	G(Y0, Y4, 0(SP), Y8, 320(SI), 8, 64(SI), 40, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 256(SI), 16, 128(SI), 32, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 224(SI), 24, 192(SI), 28, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 32(SI), 20, 160(SI), 4, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 480(SI), 44, 352(SI), 60, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 288(SI), 56, 448(SI), 36, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 96(SI), 48, 384(SI), 12, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 416(SI), 0, 0(SI), 52, Y14, Y15)
+
+	// Round 11.
+	G(Y0, Y4, 0(SP), Y8, 0(SI), 4, 32(SI), 0, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 64(SI), 12, 96(SI), 8, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 128(SI), 20, 160(SI), 16, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 192(SI), 28, 224(SI), 24, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 256(SI), 36, 288(SI), 32, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 320(SI), 44, 352(SI), 40, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 384(SI), 52, 416(SI), 48, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 448(SI), 60, 480(SI), 56, Y14, Y15)
+
+	// Round 12.
+	G(Y0, Y4, 0(SP), Y8, 448(SI), 40, 320(SI), 56, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 128(SI), 32, 256(SI), 16, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 288(SI), 60, 480(SI), 36, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 416(SI), 24, 192(SI), 52, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 32(SI), 48, 384(SI), 4, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 0(SI), 8, 64(SI), 0, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 352(SI), 28, 224(SI), 44, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 160(SI), 12, 96(SI), 20, Y14, Y15)
+
+	// Round 13.
+	G(Y0, Y4, 0(SP), Y8, 352(SI), 32, 256(SI), 44, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 384(SI), 0, 0(SI), 48, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 160(SI), 8, 64(SI), 20, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 480(SI), 52, 416(SI), 60, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 320(SI), 56, 448(SI), 40, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 96(SI), 24, 192(SI), 12, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 224(SI), 4, 32(SI), 28, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 288(SI), 16, 128(SI), 36, Y14, Y15)
+
+	// Round 14.
+	G(Y0, Y4, 0(SP), Y8, 224(SI), 36, 288(SI), 28, Y12, Y13)
+	G(Y1, Y5, 32(SP), Y9, 96(SI), 4, 32(SI), 12, Y14, Y15)
+	G(Y2, Y6, 64(SP), Y10, 416(SI), 48, 384(SI), 52, Y12, Y13)
+	G(Y3, Y7, 96(SP), Y11, 352(SI), 56, 448(SI), 44, Y14, Y15)
+	G(Y0, Y5, 64(SP), Y11, 64(SI), 24, 192(SI), 8, Y12, Y13)
+	G(Y1, Y6, 96(SP), Y8, 160(SI), 40, 320(SI), 20, Y14, Y15)
+	G(Y2, Y7, 0(SP), Y9, 128(SI), 0, 0(SI), 16, Y12, Y13)
+	G(Y3, Y4, 32(SP), Y10, 480(SI), 32, 256(SI), 60, Y14, Y15)
+
+	// Finalize the chain values: h[i] ^= v[i] ^ v[i+8].
+	VPXOR 0(SP), Y0, Y0
+	VPXOR 32(SP), Y1, Y1
+	VPXOR 64(SP), Y2, Y2
+	VPXOR 96(SP), Y3, Y3
+	VPXOR Y8, Y4, Y4
+	VPXOR Y9, Y5, Y5
+	VPXOR Y10, Y6, Y6
+	VPXOR Y11, Y7, Y7
+	VPXOR 0(DI), Y0, Y0
+	VPXOR 32(DI), Y1, Y1
+	VPXOR 64(DI), Y2, Y2
+	VPXOR 96(DI), Y3, Y3
+	VPXOR 128(DI), Y4, Y4
+	VPXOR 160(DI), Y5, Y5
+	VPXOR 192(DI), Y6, Y6
+	VPXOR 224(DI), Y7, Y7
+	VMOVDQU Y0, 0(DI)
+	VMOVDQU Y1, 32(DI)
+	VMOVDQU Y2, 64(DI)
+	VMOVDQU Y3, 96(DI)
+	VMOVDQU Y4, 128(DI)
+	VMOVDQU Y5, 160(DI)
+	VMOVDQU Y6, 192(DI)
+	VMOVDQU Y7, 224(DI)
+
+	VZEROUPPER
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
@@ -0,0 +1,39 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !purego
+// +build !purego
+
+package blake256
+
+const (
+	// batchLanes is the number of messages hashed in parallel by the
+	// multi-buffer compression function.  NEON provides 128-bit vectors which
+	// hold 4 32-bit words.
+	batchLanes = 4
+
+	// minLaneBatchSize is the minimum number of messages for which the
+	// multi-buffer compression function is used.  Smaller batches are faster
+	// to hash individually.
+	minLaneBatchSize = 2
+)
+
+// hasLaneSIMD indicates whether or not the CPU supports the instructions
+// required by the multi-buffer compression function.  NEON is a mandatory part
+// of ARMv8, so it is always available.
+const hasLaneSIMD = true
+
+// compressLanesNEON compresses the provided message blocks into the provided
+// chain values of each lane with a salt of zero using NEON instructions.
+//
+// It is implemented in batch_arm64.s.
+//
+//go:noescape
+func compressLanesNEON(h *laneState, m *laneBlock, t *laneCounter)
+
+// compressLanes is the multi-buffer compression function for the current
+// architecture.
+func compressLanes(h *laneState, m *laneBlock, t *laneCounter) {
+	compressLanesNEON(h, m, t)
+}
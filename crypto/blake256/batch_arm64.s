@@ -0,0 +1,267 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !purego
+// +build !purego
+
+#include "textflag.h"
+
+// consts houses the 16 BLAKE-256 constants with each one repeated for all 4
+// lanes.
+DATA consts<>+0x00(SB)/8, $0x243F6A88243F6A88
+DATA consts<>+0x08(SB)/8, $0x243F6A88243F6A88
+DATA consts<>+0x10(SB)/8, $0x85A308D385A308D3
+DATA consts<>+0x18(SB)/8, $0x85A308D385A308D3
+DATA consts<>+0x20(SB)/8, $0x13198A2E13198A2E
+DATA consts<>+0x28(SB)/8, $0x13198A2E13198A2E
+DATA consts<>+0x30(SB)/8, $0x0370734403707344
+DATA consts<>+0x38(SB)/8, $0x0370734403707344
+DATA consts<>+0x40(SB)/8, $0xA4093822A4093822
+DATA consts<>+0x48(SB)/8, $0xA4093822A4093822
+DATA consts<>+0x50(SB)/8, $0x299F31D0299F31D0
+DATA consts<>+0x58(SB)/8, $0x299F31D0299F31D0
+DATA consts<>+0x60(SB)/8, $0x082EFA98082EFA98
+DATA consts<>+0x68(SB)/8, $0x082EFA98082EFA98
+DATA consts<>+0x70(SB)/8, $0xEC4E6C89EC4E6C89
+DATA consts<>+0x78(SB)/8, $0xEC4E6C89EC4E6C89
+DATA consts<>+0x80(SB)/8, $0x452821E6452821E6
+DATA consts<>+0x88(SB)/8, $0x452821E6452821E6
+DATA consts<>+0x90(SB)/8, $0x38D0137738D01377
+DATA consts<>+0x98(SB)/8, $0x38D0137738D01377
+DATA consts<>+0xa0(SB)/8, $0xBE5466CFBE5466CF
+DATA consts<>+0xa8(SB)/8, $0xBE5466CFBE5466CF
+DATA consts<>+0xb0(SB)/8, $0x34E90C6C34E90C6C
+DATA consts<>+0xb8(SB)/8, $0x34E90C6C34E90C6C
+DATA consts<>+0xc0(SB)/8, $0xC0AC29B7C0AC29B7
+DATA consts<>+0xc8(SB)/8, $0xC0AC29B7C0AC29B7
+DATA consts<>+0xd0(SB)/8, $0xC97C50DDC97C50DD
+DATA consts<>+0xd8(SB)/8, $0xC97C50DDC97C50DD
+DATA consts<>+0xe0(SB)/8, $0x3F84D5B53F84D5B5
+DATA consts<>+0xe8(SB)/8, $0x3F84D5B53F84D5B5
+DATA consts<>+0xf0(SB)/8, $0xB5470917B5470917
+DATA consts<>+0xf8(SB)/8, $0xB5470917B5470917
+GLOBL consts<>(SB), (NOPTR+RODATA), $256
+
+// rot8 is the byte table lookup index that rotates each 32-bit word right by 8
+// bits.
+DATA rot8<>+0x00(SB)/8, $0x0407060500030201
+DATA rot8<>+0x08(SB)/8, $0x0C0F0E0D080B0A09
+GLOBL rot8<>(SB), (NOPTR+RODATA), $16
+
+// G performs the BLAKE-256 G function on the 4 lanes of the state words a, b,
+// c, and d with the message words at offsets mi and mj and the constants at
+// offsets ci and cj.  It uses V16-V18 as temporaries and expects the rot8
+// table in V19.
+#define G(a, b, c, d, mi, ci, mj, cj) \
+	FMOVQ  mi(R1), F16;               \
+	FMOVQ  ci(R4), F17;               \
+	VEOR   V17.B16, V16.B16, V16.B16; \
+	VADD   V16.S4, a.S4, a.S4;        \
+	VADD   b.S4, a.S4, a.S4;          \
+	VEOR   a.B16, d.B16, d.B16;       \
+	VREV32 d.H8, d.H8;                \
+	VADD   d.S4, c.S4, c.S4;          \
+	VEOR   c.B16, b.B16, b.B16;       \
+	VSHL   $20, b.S4, V18.S4;         \
+	VSRI   $12, b.S4, V18.S4;         \
+	VMOV   V18.B16, b.B16;            \
+	FMOVQ  mj(R1), F16;               \
+	FMOVQ  cj(R4), F17;               \
+	VEOR   V17.B16, V16.B16, V16.B16; \
+	VADD   V16.S4, a.S4, a.S4;        \
+	VADD   b.S4, a.S4, a.S4;          \
+	VEOR   a.B16, d.B16, d.B16;       \
+	VTBL   V19.B16, [d.B16], d.B16;   \
+	VADD   d.S4, c.S4, c.S4;          \
+	VEOR   c.B16, b.B16, b.B16;       \
+	VSHL   $25, b.S4, V18.S4;         \
+	VSRI   $7, b.S4, V18.S4;          \
+	VMOV   V18.B16, b.B16
+
+// func compressLanesNEON(h *laneState, m *laneBlock, t *laneCounter)
+TEXT ·compressLanesNEON(SB), NOSPLIT, $0-24
+	MOVD h+0(FP), R0
+	MOVD m+8(FP), R1
+	MOVD t+16(FP), R2
+	MOVD $consts<>(SB), R4
+	MOVD $rot8<>(SB), R5
+	VLD1 (R5), [V19.B16]
+
+	// Initialize the state.  Words 0-7 are the chain values, words 8-11 are
+	// the first four constants, and words 12-15 are the next four constants
+	// xored with the counter.
+	ADD  $64, R0, R3
+	VLD1 (R0), [V0.S4, V1.S4, V2.S4, V3.S4]
+	VLD1 (R3), [V4.S4, V5.S4, V6.S4, V7.S4]
+	ADD  $64, R4, R5
+	VLD1 (R4), [V8.S4, V9.S4, V10.S4, V11.S4]
+	VLD1 (R5), [V12.S4, V13.S4, V14.S4, V15.S4]
+	VLD1 (R2), [V16.S4, V17.S4]
+	VEOR V16.B16, V12.B16, V12.B16
+	VEOR V16.B16, V13.B16, V13.B16
+	VEOR V17.B16, V14.B16, V14.B16
+	VEOR V17.B16, V15.B16, V15.B16
+
+	// Round 1.
+	G(V0, V4, V8, V12, 0, 16, 16, 0)
+	G(V1, V5, V9, V13, 32, 48, 48, 32)
+	G(V2, V6, V10, V14, 64, 80, 80, 64)
+	G(V3, V7, V11, V15, 96, 112, 112, 96)
+	G(V0, V5, V10, V15, 128, 144, 144, 128)
+	G(V1, V6, V11, V12, 160, 176, 176, 160)
+	G(V2, V7, V8, V13, 192, 208, 208, 192)
+	G(V3, V4, V9, V14, 224, 240, 240, 224)
+
+	// Round 2.
+	G(V0, V4, V8, V12, 224, 160, 160, 224)
+	G(V1, V5, V9, V13, 64, 128, 128, 64)
+	G(V2, V6, V10, V14, 144, 240, 240, 144)
+	G(V3, V7, V11, V15, 208, 96, 96, 208)
+	G(V0, V5, V10, V15, 16, 192, 192, 16)
+	G(V1, V6, V11, V12, 0, 32, 32, 0)
+	G(V2, V7, V8, V13, 176, 112, 112, 176)
+	G(V3, V4, V9, V14, 80, 48, 48, 80)
+
+	// Round 3.
+	G(V0, V4, V8, V12, 176, 128, 128, 176)
+	G(V1, V5, V9, V13, 192, 0, 0, 192)
+	G(V2, V6, V10, V14, 80, 32, 32, 80)
+	G(V3, V7, V11, V15, 240, 208, 208, 240)
+	G(V0, V5, V10, V15, 160, 224, 224, 160)
+	G(V1, V6, V11, V12, 48, 96, 96, 48)
+	G(V2, V7, V8, V13, 112, 16, 16, 112)
+	G(V3, V4, V9, V14, 144, 64, 64, 144)
+
+	// Round 4.
+	G(V0, V4, V8, V12, 112, 144, 144, 112)
+	G(V1, V5, V9, V13, 48, 16, 16, 48)
+	G(V2, V6, V10, V14, 208, 192, 192, 208)
+	G(V3, V7, V11, V15, 176, 224, 224, 176)
+	G(V0, V5, V10, V15, 32, 96, 96, 32)
+	G(V1, V6, V11, V12, 80, 160, 160, 80)
+	G(V2, V7, V8, V13, 64, 0, 0, 64)
+	G(V3, V4, V9, V14, 240, 128, 128, 240)
+
+	// Round 5.
+	G(V0, V4, V8, V12, 144, 0, 0, 144)
+	G(V1, V5, V9, V13, 80, 112, 112, 80)
+	G(V2, V6, V10, V14, 32, 64, 64, 32)
+	G(V3, V7, V11, V15, 160, 240, 240, 160)
+	G(V0, V5, V10, V15, 224, 16, 16, 224)
+	G(V1, V6, V11, V12, 176, 192, 192, 176)
+	G(V2, V7, V8, V13, 96, 128, 128, 96)
+	G(V3, V4, V9, V14, 48, 208, 208, 48)
+
+	// Round 6.
+	G(V0, V4, V8, V12, 32, 192, 192, 32)
+	G(V1, V5, V9, V13, 96, 160, 160, 96)
+	G(V2, V6, V10, V14, 0, 176, 176, 0)
+	G(V3, V7, V11, V15, 128, 48, 48, 128)
+	G(V0, V5, V10, V15, 64, 208, 208, 64)
+	G(V1, V6, V11, V12, 112, 80, 80, 112)
+	G(V2, V7, V8, V13, 240, 224, 224, 240)
+	G(V3, V4, V9, V14, 16, 144, 144, 16)
+
+	// Round 7.
+	G(V0, V4, V8, V12, 192, 80, 80, 192)
+	G(V1, V5, V9, V13, 16, 240, 240, 16)
+	G(V2, V6, V10, V14, 224, 208, 208, 224)
+	G(V3, V7, V11, V15, 64, 160, 160, 64)
+	G(V0, V5, V10, V15, 0, 112, 112, 0)
+	G(V1, V6, V11, V12, 96, 48, 48, 96)
+	G(V2, V7, V8, V13, 144, 32, 32, 144)
+	G(V3, V4, V9, V14, 128, 176, 176, 128)
+
+	// Round 8.
+	G(V0, V4, V8, V12, 208, 176, 176, 208)
+	G(V1, V5, V9, V13, 112, 224, 224, 112)
+	G(V2, V6, V10, V14, 192, 16, 16, 192)
+	G(V3, V7, V11, V15, 48, 144, 144, 48)
+	G(V0, V5, V10, V15, 80, 0, 0, 80)
+	G(V1, V6, V11, V12, 240, 64, 64, 240)
+	G(V2, V7, V8, V13, 128, 96, 96, 128)
+	G(V3, V4, V9, V14, 32, 160, 160, 32)
+
+	// Round 9.
+	G(V0, V4, V8, V12, 96, 240, 240, 96)
+	G(V1, V5, V9, V13, 224, 144, 144, 224)
+	G(V2, V6, V10, V14, 176, 48, 48, 176)
+	G(V3, V7, V11, V15, 0, 128, 128, 0)
+	G(V0, V5, V10, V15, 192, 32, 32, 192)
+	G(V1, V6, V11, V12, 208, 112, 112, 208)
+	G(V2, V7, V8, V13, 16, 64, 64, 16)
+	G(V3, V4, V9, V14, 160, 80, 80, 160)
+
+	// Round 10.
+	G(V0, V4, V8, V12, 160, 32, 32, 160)
+	G(V1, V5, V9, V13, 128, 64, 64, 128)
+	G(V2, V6, V10, V14, 112, 96, 96, 112)
+	G(V3, V7, V11, V15, 16, 80, 80, 16)
+	G(V0, V5, V10, V15, 240, 176, 176, 240)
+	G(V1, V6, V11, V12, 144, 224, 224, 144)
+	G(V2, V7, V8, V13, 48, 192, 192, 48)
+	G(V3, V4, V9, V14, 208, 0, 0, 208)
+
+	// Round 11.
+	G(V0, V4, V8, V12, 0, 16, 16, 0)
+	G(V1, V5, V9, V13, 32, 48, 48, 32)
+	G(V2, V6, V10, V14, 64, 80, 80, 64)
+	G(V3, V7, V11, V15, 96, 112, 112, 96)
+	G(V0, V5, V10, V15, 128, 144, 144, 128)
+	G(V1, V6, V11, V12, 160, 176, 176, 160)
+	G(V2, V7, V8, V13, 192, 208, 208, 192)
+	G(V3, V4, V9, V14, 224, 240, 240, 224)
+
+	// Round 12.
+	G(V0, V4, V8, V12, 224, 160, 160, 224)
+	G(V1, V5, V9, V13, 64, 128, 128, 64)
+	G(V2, V6, V10, V14, 144, 240, 240, 144)
+	G(V3, V7, V11, V15, 208, 96, 96, 208)
+	G(V0, V5, V10, V15, 16, 192, 192, 16)
+	G(V1, V6, V11, V12, 0, 32, 32, 0)
+	G(V2, V7, V8, V13, 176, 112, 112, 176)
+	G(V3, V4, V9, V14, 80, 48, 48, 80)
+
+	// Round 13.
+	G(V0, V4, V8, V12, 176, 128, 128, 176)
+	G(V1, V5, V9, V13, 192, 0, 0, 192)
+	G(V2, V6, V10, V14, 80, 32, 32, 80)
+	G(V3, V7, V11, V15, 240, 208, 208, 240)
+	G(V0, V5, V10, V15, 160, 224, 224, 160)
+	G(V1, V6, V11, V12, 48, 96, 96, 48)
+	G(V2, V7, V8, V13, 112, 16, 16, 112)
+	G(V3, V4, V9, V14, 144, 64, 64, 144)
+
+	// Round 14.
+	G(V0, V4, V8, V12, 112, 144, 144, 112)
+	G(V1, V5, V9, V13, 48, 16, 16, 48)
+	G(V2, V6, V10, V14, 208, 192, 192, 208)
+	G(V3, V7, V11, V15, 176, 224, 224, 176)
+	G(V0, V5, V10, V15, 32, 96, 96, 32)
+	G(V1, V6, V11, V12, 80, 160, 160, 80)
+	G(V2, V7, V8, V13, 64, 0, 0, 64)
+	G(V3, V4, V9, V14, 240, 128, 128, 240)
+
+	// Finalize the chain values: h[i] ^= v[i] ^ v[i+8].
+	VEOR V8.B16, V0.B16, V0.B16
+	VEOR V9.B16, V1.B16, V1.B16
+	VEOR V10.B16, V2.B16, V2.B16
+	VEOR V11.B16, V3.B16, V3.B16
+	VEOR V12.B16, V4.B16, V4.B16
+	VEOR V13.B16, V5.B16, V5.B16
+	VEOR V14.B16, V6.B16, V6.B16
+	VEOR V15.B16, V7.B16, V7.B16
+	VLD1 (R0), [V16.S4, V17.S4, V18.S4, V19.S4]
+	VEOR V16.B16, V0.B16, V0.B16
+	VEOR V17.B16, V1.B16, V1.B16
+	VEOR V18.B16, V2.B16, V2.B16
+	VEOR V19.B16, V3.B16, V3.B16
+	VLD1 (R3), [V16.S4, V17.S4, V18.S4, V19.S4]
+	VEOR V16.B16, V4.B16, V4.B16
+	VEOR V17.B16, V5.B16, V5.B16
+	VEOR V18.B16, V6.B16, V6.B16
+	VEOR V19.B16, V7.B16, V7.B16
+	VST1 [V0.S4, V1.S4, V2.S4, V3.S4], (R0)
+	VST1 [V4.S4, V5.S4, V6.S4, V7.S4], (R3)
+	RET
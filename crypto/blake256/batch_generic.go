@@ -0,0 +1,30 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build (!amd64 && !arm64) || purego
+// +build !amd64,!arm64 purego
+
+package blake256
+
+const (
+	// batchLanes is the number of messages hashed in parallel by the
+	// multi-buffer compression function.  It only affects testing on
+	// architectures without a vectorized implementation.
+	batchLanes = 4
+
+	// minLaneBatchSize is the minimum number of messages for which the
+	// multi-buffer compression function is used.
+	minLaneBatchSize = 2
+)
+
+// hasLaneSIMD indicates whether or not the CPU supports the instructions
+// required by the multi-buffer compression function.  It is always false on
+// architectures without a vectorized implementation.
+const hasLaneSIMD = false
+
+// compressLanes is the multi-buffer compression function for the current
+// architecture.
+func compressLanes(h *laneState, m *laneBlock, t *laneCounter) {
+	compressLanesGeneric(h, m, t)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestSum256Batch ensures the checksums calculated by the batch API and the
+// multi-buffer compression functions match the ones calculated individually
+// for messages of varying lengths, including all of the lengths that require
+// special handling of the padding.
+func TestSum256Batch(t *testing.T) {
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	// Create messages of every length up to several blocks followed by
+	// messages of random lengths in a random order.
+	var data [][]byte
+	for i := 0; i <= 4*BlockSize; i++ {
+		msg := make([]byte, i)
+		rng.Read(msg)
+		data = append(data, msg)
+	}
+	for i := 0; i < 100; i++ {
+		msg := make([]byte, rng.Intn(2000))
+		rng.Read(msg)
+		data = append(data, msg)
+	}
+	rng.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	// checkDigests ensures the provided digests match the checksums of the
+	// data calculated individually.
+	checkDigests := func(desc string, digests [][Size]byte, data [][]byte) {
+		t.Helper()
+		if len(digests) != len(data) {
+			t.Fatalf("%s: mismatched number of digests -- got %d, want %d",
+				desc, len(digests), len(data))
+		}
+		for i := range data {
+			if want := Sum256(data[i]); digests[i] != want {
+				t.Fatalf("%s: mismatched digest for message %d (len %d) -- "+
+					"got %x, want %x", desc, i, len(data[i]), digests[i], want)
+			}
+		}
+	}
+
+	// Ensure batches of various sizes are hashed properly by the batch API and
+	// the multi-buffer scheduling with both the generic and architecture
+	// specific compression functions.
+	for _, num := range []int{0, 1, 2, batchLanes - 1, batchLanes,
+		batchLanes + 1, 3*batchLanes + 2, len(data)} {
+
+		batch := data[:num]
+		checkDigests("Sum256Batch", Sum256Batch(batch), batch)
+
+		digests := make([][Size]byte, num)
+		sum256Lanes(digests, batch, compressLanesGeneric)
+		checkDigests("generic lanes", digests, batch)
+
+		if hasLaneSIMD {
+			digests = make([][Size]byte, num)
+			sum256Lanes(digests, batch, compressLanes)
+			checkDigests("simd lanes", digests, batch)
+		}
+	}
+}
+
+// TestCompressLanes ensures the architecture specific multi-buffer compression
+// function produces the same results as the generic one for random chain
+// values, message blocks, and counters.
+func TestCompressLanes(t *testing.T) {
+	if !hasLaneSIMD {
+		t.Skip("multi-buffer compression is not accelerated on this CPU")
+	}
+
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	for iter := 0; iter < 1000; iter++ {
+		var h laneState
+		var m laneBlock
+		var c laneCounter
+		for i := range h {
+			for lane := range h[i] {
+				h[i][lane] = rng.Uint32()
+			}
+		}
+		for i := range m {
+			for lane := range m[i] {
+				m[i][lane] = rng.Uint32()
+			}
+		}
+		for lane := 0; lane < batchLanes; lane++ {
+			// Include counters of zero along with counters that are not.
+			if rng.Intn(4) != 0 {
+				c[0][lane] = rng.Uint32()
+				c[1][lane] = rng.Uint32()
+			}
+		}
+
+		want := h
+		mCopy, cCopy := m, c
+		compressLanesGeneric(&want, &mCopy, &cCopy)
+		got := h
+		compressLanes(&got, &m, &c)
+		if got != want {
+			t.Fatalf("mismatched chain values -- got %x, want %x", got, want)
+		}
+	}
+}
+
+// benchmarkSum256Batch benchmarks how long it takes to calculate the checksums
+// of a batch of messages of the provided length both with the batch API and
+// individually.
+func benchmarkSum256Batch(b *testing.B, msgLen int) {
+	const numMsgs = 256
+	data := make([][]byte, numMsgs)
+	for i := range data {
+		data[i] = make([]byte, msgLen)
+		data[i][0] = byte(i)
+	}
+
+	b.Run("batch", func(b *testing.B) {
+		b.SetBytes(int64(numMsgs * msgLen))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Sum256Batch(data)
+		}
+	})
+	b.Run("individual", func(b *testing.B) {
+		b.SetBytes(int64(numMsgs * msgLen))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for j := range data {
+				Sum256(data[j])
+			}
+		}
+	})
+}
+
+// BenchmarkSum256Batch64 benchmarks calculating the checksums of batches of
+// 64-byte messages, such as the nodes of merkle trees.
+func BenchmarkSum256Batch64(b *testing.B) {
+	benchmarkSum256Batch(b, 64)
+}
+
+// BenchmarkSum256Batch250 benchmarks calculating the checksums of batches of
+// 250-byte messages, which is around the size of typical transactions.
+func BenchmarkSum256Batch250(b *testing.B) {
+	benchmarkSum256Batch(b, 250)
+}
@@ -38,7 +38,7 @@ func (cmd *fetchBlockCmd) Execute(args []string) error {
 	}
 
 	// Load the block database.
-	db, err := loadBlockDB()
+	db, err := loadBlockDBReadOnly()
 	if err != nil {
 		return err
 	}
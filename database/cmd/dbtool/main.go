@@ -27,11 +27,32 @@ var (
 	shutdownChannel = make(chan error)
 )
 
-// loadBlockDB opens the block database and returns a handle to it.
-func loadBlockDB() (database.DB, error) {
+// blockDbPath returns the path to the block database based on the configured
+// data directory and database type.
+func blockDbPath() string {
 	// The database name is based on the database type.
 	dbName := blockDbNamePrefix + "_" + cfg.DbType
-	dbPath := filepath.Join(cfg.DataDir, dbName)
+	return filepath.Join(cfg.DataDir, dbName)
+}
+
+// loadBlockDBReadOnly opens the existing block database in read-only mode and
+// returns a handle to it.  It is used by the commands that only read from the
+// database so they are unable to modify it.
+func loadBlockDBReadOnly() (database.DB, error) {
+	dbPath := blockDbPath()
+	log.Infof("Loading block database from '%s' in read-only mode", dbPath)
+	db, err := database.OpenReadOnly(cfg.DbType, dbPath, activeNetParams.Net)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Block database loaded")
+	return db, nil
+}
+
+// loadBlockDB opens the block database and returns a handle to it.
+func loadBlockDB() (database.DB, error) {
+	dbPath := blockDbPath()
 
 	log.Infof("Loading block database from '%s'", dbPath)
 	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net)
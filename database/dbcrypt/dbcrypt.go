@@ -0,0 +1,311 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dbcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/decred/dcrd/database/v3"
+)
+
+const (
+	// KeySize is the size in bytes of the keys used to encrypt databases.
+	KeySize = 32
+
+	// recordSize is the maximum number of plaintext bytes sealed in a single
+	// record of an encrypted file.
+	recordSize = 16 * 1024
+
+	// fileMagic identifies an encrypted file.
+	fileMagic = "DCRDENC1"
+
+	// saltSize is the size of the random salt in the header of each
+	// encrypted file that the key of the file is derived from.
+	saltSize = 32
+
+	// checkSize is the size of the value in the header of each encrypted
+	// file that is used to detect the wrong key being provided.
+	checkSize = 16
+
+	// headerSize is the size of the header of an encrypted file.  The header
+	// format is:
+	//
+	//  [0:8]   Magic (8 bytes)
+	//  [8:40]  Salt (32 bytes)
+	//  [40:56] Key check (16 bytes)
+	headerSize = len(fileMagic) + saltSize + checkSize
+
+	// nonceSize and tagSize are the sizes of the nonce and authentication
+	// tag of each record.
+	nonceSize = 12
+	tagSize   = 16
+
+	// recordHeaderSize is the size of the part of each record that precedes
+	// the sealed data.  The record format is:
+	//
+	//  [0:4]   Plaintext length (4 bytes)
+	//  [4:16]  Nonce (12 bytes)
+	//  [16:]   Sealed plaintext followed by the authentication tag
+	recordHeaderSize = 4 + nonceSize
+)
+
+var (
+	// fileKeyLabel and keyCheckLabel separate the values derived from the
+	// database key for each file.
+	fileKeyLabel  = []byte("dcrd database file key")
+	keyCheckLabel = []byte("dcrd database key check")
+)
+
+// makeError creates a database.Error given a set of arguments.
+func makeError(kind database.ErrorKind, desc string) database.Error {
+	return database.Error{Err: kind, Description: desc}
+}
+
+// checkKey returns an error if the provided key is not a valid encryption key.
+func checkKey(key []byte) error {
+	if len(key) != KeySize {
+		str := fmt.Sprintf("encryption key is %d bytes instead of %d",
+			len(key), KeySize)
+		return makeError(database.ErrEncryptionKey, str)
+	}
+	return nil
+}
+
+// ParseKey decodes an encryption key from the provided hex-encoded string.
+// Leading and trailing whitespace is ignored.
+func ParseKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		str := fmt.Sprintf("encryption key is not hex-encoded: %v", err)
+		return nil, makeError(database.ErrEncryptionKey, str)
+	}
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// LoadKeyFile reads a hex-encoded encryption key from the file at the provided
+// path.
+func LoadKeyFile(path string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		str := fmt.Sprintf("unable to read encryption key file: %v", err)
+		return nil, makeError(database.ErrEncryptionKey, str)
+	}
+	return ParseKey(string(contents))
+}
+
+// deriveFileKeys derives the cipher used to seal the records of a file and the
+// value used to check the key from the provided database key and file salt.
+func deriveFileKeys(key, salt []byte) (cipher.AEAD, []byte, error) {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(fileKeyLabel)
+	mac.Write(salt)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mac = hmac.New(sha256.New, key)
+	mac.Write(keyCheckLabel)
+	mac.Write(salt)
+	return aead, mac.Sum(nil)[:checkSize], nil
+}
+
+// newHeader returns the header for a new encrypted file with a random salt
+// along with the cipher used to seal its records.
+func newHeader(key []byte) ([]byte, cipher.AEAD, error) {
+	header := make([]byte, headerSize)
+	copy(header, fileMagic)
+	salt := header[len(fileMagic) : len(fileMagic)+saltSize]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	aead, check, err := deriveFileKeys(key, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	copy(header[len(fileMagic)+saltSize:], check)
+	return header, aead, nil
+}
+
+// parseHeader ensures the provided header belongs to a file that was encrypted
+// with the provided key and returns the cipher used to open its records.
+func parseHeader(key, header []byte, name string) (cipher.AEAD, error) {
+	if len(header) < headerSize ||
+		!bytes.Equal(header[:len(fileMagic)], []byte(fileMagic)) {
+
+		str := fmt.Sprintf("%s is not encrypted", name)
+		return nil, makeError(database.ErrEncryptionKey, str)
+	}
+	salt := header[len(fileMagic) : len(fileMagic)+saltSize]
+	aead, check, err := deriveFileKeys(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(check, header[len(fileMagic)+saltSize:headerSize]) {
+		str := fmt.Sprintf("%s was encrypted with a different key", name)
+		return nil, makeError(database.ErrEncryptionKey, str)
+	}
+	return aead, nil
+}
+
+// readHeader reads the header of the provided encrypted file and returns the
+// cipher used to open its records.
+func readHeader(r io.ReaderAt, key []byte, name string) (cipher.AEAD, error) {
+	header := make([]byte, headerSize)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return parseHeader(key, header[:n], name)
+}
+
+// record identifies a sealed record of an encrypted file.
+type record struct {
+	plainOff int64
+	fileOff  int64
+	size     int
+}
+
+// recordIndex houses the location of every sealed record of an encrypted file
+// so any part of the plaintext is able to be read without decrypting the
+// entire file.
+type recordIndex struct {
+	name      string
+	aead      cipher.AEAD
+	records   []record
+	plainSize int64
+	fileSize  int64
+}
+
+// additionalData returns the data that is authenticated along with the record
+// that starts at the provided plaintext offset so records are not able to be
+// moved around within a file without detection.
+func additionalData(plainOff int64) []byte {
+	var ad [8]byte
+	binary.LittleEndian.PutUint64(ad[:], uint64(plainOff))
+	return ad[:]
+}
+
+// seal returns the record that contains the provided plaintext when it is
+// appended to the end of the file.  The index is not updated until add is
+// called after the record is written.
+func (idx *recordIndex) seal(plain []byte) ([]byte, error) {
+	rec := make([]byte, recordHeaderSize, recordHeaderSize+len(plain)+tagSize)
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(len(plain)))
+	nonce := rec[4:recordHeaderSize]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return idx.aead.Seal(rec, nonce, plain, additionalData(idx.plainSize)), nil
+}
+
+// add appends a record with the provided number of plaintext bytes and total
+// size that was written to the end of the file to the index.
+func (idx *recordIndex) add(plainLen, recLen int) {
+	idx.records = append(idx.records, record{
+		plainOff: idx.plainSize,
+		fileOff:  idx.fileSize,
+		size:     plainLen,
+	})
+	idx.plainSize += int64(plainLen)
+	idx.fileSize += int64(recLen)
+}
+
+// scan indexes all of the complete records of the provided file which is the
+// provided number of bytes long.  A partially written record at the end of the
+// file, such as one left by an unclean shutdown, is ignored.
+func (idx *recordIndex) scan(r io.ReaderAt, size int64) error {
+	idx.fileSize = int64(headerSize)
+	var lenBytes [4]byte
+	for idx.fileSize+recordHeaderSize+tagSize <= size {
+		if _, err := r.ReadAt(lenBytes[:], idx.fileSize); err != nil {
+			return err
+		}
+		plainLen := int(binary.LittleEndian.Uint32(lenBytes[:]))
+		if plainLen == 0 || plainLen > recordSize {
+			str := fmt.Sprintf("record at offset %d of %s has invalid "+
+				"length %d", idx.fileSize, idx.name, plainLen)
+			return makeError(database.ErrCorruption, str)
+		}
+		recLen := recordHeaderSize + plainLen + tagSize
+		if idx.fileSize+int64(recLen) > size {
+			break
+		}
+		idx.add(plainLen, recLen)
+	}
+	return nil
+}
+
+// open reads and decrypts the provided record using the provided buffer which
+// must be large enough to hold any record.
+func (idx *recordIndex) open(r io.ReaderAt, rec record, buf []byte) ([]byte, error) {
+	buf = buf[:recordHeaderSize+rec.size+tagSize]
+	if _, err := r.ReadAt(buf, rec.fileOff); err != nil {
+		str := fmt.Sprintf("unable to read record at offset %d of %s: %v",
+			rec.fileOff, idx.name, err)
+		return nil, makeError(database.ErrCorruption, str)
+	}
+	nonce := buf[4:recordHeaderSize]
+	sealed := buf[recordHeaderSize:]
+	plain, err := idx.aead.Open(sealed[:0], nonce, sealed,
+		additionalData(rec.plainOff))
+	if err != nil ||
+		binary.LittleEndian.Uint32(buf[0:4]) != uint32(rec.size) {
+
+		str := fmt.Sprintf("record at offset %d of %s failed authentication",
+			rec.fileOff, idx.name)
+		return nil, makeError(database.ErrCorruption, str)
+	}
+	return plain, nil
+}
+
+// find returns the position in the index of the record that contains the
+// provided plaintext offset.  The number of records is returned when the
+// offset is beyond the sealed plaintext.
+func (idx *recordIndex) find(off int64) int {
+	return sort.Search(len(idx.records), func(i int) bool {
+		rec := &idx.records[i]
+		return rec.plainOff+int64(rec.size) > off
+	})
+}
+
+// readAt decrypts the sealed plaintext starting at the provided offset into
+// the provided buffer and returns the number of bytes read.  Fewer bytes than
+// requested are only read when the end of the sealed plaintext is reached.
+func (idx *recordIndex) readAt(r io.ReaderAt, p []byte, off int64) (int, error) {
+	var n int
+	var buf []byte
+	for i := idx.find(off); n < len(p) && i < len(idx.records); i++ {
+		if buf == nil {
+			buf = make([]byte, recordHeaderSize+recordSize+tagSize)
+		}
+		rec := idx.records[i]
+		plain, err := idx.open(r, rec, buf)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], plain[off+int64(n)-rec.plainOff:])
+	}
+	return n, nil
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dbcrypt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// testKey returns a key for use in the tests that is made up of the provided
+// byte.
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+// TestParseKey ensures keys are parsed and loaded from key files as expected.
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want []byte
+		err  error
+	}{{
+		name: "valid key",
+		in:   strings.Repeat("01", KeySize),
+		want: testKey(0x01),
+	}, {
+		name: "valid key with surrounding whitespace",
+		in:   " " + strings.Repeat("ab", KeySize) + "\n",
+		want: testKey(0xab),
+	}, {
+		name: "not hex",
+		in:   strings.Repeat("zz", KeySize),
+		err:  database.ErrEncryptionKey,
+	}, {
+		name: "short key",
+		in:   strings.Repeat("01", KeySize-1),
+		err:  database.ErrEncryptionKey,
+	}, {
+		name: "empty",
+		in:   "",
+		err:  database.ErrEncryptionKey,
+	}}
+
+	for _, test := range tests {
+		key, err := ParseKey(test.in)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.err)
+			continue
+		}
+		if !bytes.Equal(key, test.want) {
+			t.Errorf("%q: unexpected key -- got %x, want %x", test.name, key,
+				test.want)
+		}
+	}
+
+	// Ensure keys are loaded from key files.
+	keyPath := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(keyPath, []byte(strings.Repeat("02", KeySize)+"\n"),
+		0600)
+	if err != nil {
+		t.Fatalf("unexpected error writing key file: %v", err)
+	}
+	key, err := LoadKeyFile(keyPath)
+	if err != nil {
+		t.Fatalf("unexpected error loading key file: %v", err)
+	}
+	if !bytes.Equal(key, testKey(0x02)) {
+		t.Fatalf("unexpected loaded key -- got %x, want %x", key,
+			testKey(0x02))
+	}
+	_, err = LoadKeyFile(keyPath + ".missing")
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error loading missing key file -- got %v, "+
+			"want %v", err, database.ErrEncryptionKey)
+	}
+}
+
+// checkFileContents ensures the provided file contains exactly the provided
+// plaintext.
+func checkFileContents(t *testing.T, f *File, want []byte) {
+	t.Helper()
+
+	if size := f.Size(); size != int64(len(want)) {
+		t.Fatalf("unexpected size -- got %d, want %d", size, len(want))
+	}
+	got := make([]byte, len(want))
+	if _, err := f.ReadAt(got, 0); err != nil {
+		t.Fatalf("unexpected error reading file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("file contents do not match the written data")
+	}
+
+	// Ensure reads that span records and extend beyond the end of the file
+	// return the available data along with io.EOF.
+	off := int64(len(want) - 10)
+	buf := make([]byte, 20)
+	n, err := f.ReadAt(buf, off)
+	if err != io.EOF {
+		t.Fatalf("unexpected error reading past end -- got %v, want %v",
+			err, io.EOF)
+	}
+	if n != 10 || !bytes.Equal(buf[:n], want[off:]) {
+		t.Fatalf("unexpected data reading past end -- got %d bytes", n)
+	}
+}
+
+// TestFile ensures encrypted files are written, read, truncated, and reopened
+// as expected and that they are only able to be opened with the right key.
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(0x01)
+	path := filepath.Join(t.TempDir(), "000000000.fdb")
+	f, err := OpenFile(path, os.O_RDWR|os.O_CREATE, 0600, key)
+	if err != nil {
+		t.Fatalf("unexpected error creating file: %v", err)
+	}
+
+	// Write enough data to span several records using writes of various sizes
+	// and ensure it is readable both before and after it is synced.
+	var want []byte
+	for i := 0; len(want) < recordSize*3+100; i++ {
+		data := bytes.Repeat([]byte{byte(i)}, 1000+i*37)
+		n, err := f.WriteAt(data, int64(len(want)))
+		if err != nil || n != len(data) {
+			t.Fatalf("unexpected write result -- n %d, err %v", n, err)
+		}
+		want = append(want, data...)
+	}
+	checkFileContents(t, f, want)
+	if err := f.Sync(); err != nil {
+		t.Fatalf("unexpected error syncing file: %v", err)
+	}
+	checkFileContents(t, f, want)
+
+	// Ensure writes that are not at the end of the file are rejected.
+	if _, err := f.WriteAt([]byte{1}, 0); err == nil {
+		t.Fatal("write before the end of the file did not fail")
+	}
+
+	// Ensure the plaintext is not stored in the file.
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading raw file: %v", err)
+	}
+	if bytes.Contains(raw, want[:1000]) {
+		t.Fatal("raw file contains the plaintext")
+	}
+
+	// Truncate in the middle of a sealed record, append more data, and ensure
+	// the result is as expected after reopening the file.
+	truncSize := int64(recordSize + 123)
+	if err := f.Truncate(truncSize); err != nil {
+		t.Fatalf("unexpected error truncating file: %v", err)
+	}
+	want = want[:truncSize]
+	checkFileContents(t, f, want)
+	extra := bytes.Repeat([]byte{0xff}, 500)
+	if _, err := f.WriteAt(extra, truncSize); err != nil {
+		t.Fatalf("unexpected error writing after truncate: %v", err)
+	}
+	want = append(want, extra...)
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error closing file: %v", err)
+	}
+	size, err := FileSize(path, key)
+	if err != nil {
+		t.Fatalf("unexpected error fetching file size: %v", err)
+	}
+	if size != int64(len(want)) {
+		t.Fatalf("unexpected file size -- got %d, want %d", size, len(want))
+	}
+
+	// Simulate a partially written record from an unclean shutdown and ensure
+	// it is discarded when the file is reopened for writing.
+	raw, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading raw file: %v", err)
+	}
+	err = os.WriteFile(path, append(raw, 0x10, 0x00, 0x00, 0x00, 0x01), 0600)
+	if err != nil {
+		t.Fatalf("unexpected error writing raw file: %v", err)
+	}
+	f, err = OpenFile(path, os.O_RDWR, 0600, key)
+	if err != nil {
+		t.Fatalf("unexpected error reopening file: %v", err)
+	}
+	checkFileContents(t, f, want)
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error closing file: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("unexpected error checking file: %v", err)
+	}
+	if fi.Size() != int64(len(raw)) {
+		t.Fatalf("partial record was not discarded -- got size %d, want %d",
+			fi.Size(), len(raw))
+	}
+
+	// Ensure a modified record is detected.
+	corrupt := append([]byte(nil), raw...)
+	corrupt[headerSize+recordHeaderSize] ^= 0x01
+	corruptPath := path + ".corrupt"
+	if err := os.WriteFile(corruptPath, corrupt, 0600); err != nil {
+		t.Fatalf("unexpected error writing raw file: %v", err)
+	}
+	f, err = OpenFile(corruptPath, os.O_RDONLY, 0, key)
+	if err != nil {
+		t.Fatalf("unexpected error opening corrupt file: %v", err)
+	}
+	_, err = f.ReadAt(make([]byte, 10), 0)
+	if !errors.Is(err, database.ErrCorruption) {
+		t.Fatalf("unexpected error reading corrupt file -- got %v, want %v",
+			err, database.ErrCorruption)
+	}
+	f.Close()
+
+	// Ensure the file is not able to be opened with the wrong key or without
+	// being encrypted.
+	_, err = OpenFile(path, os.O_RDONLY, 0, testKey(0x02))
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error opening with wrong key -- got %v, want %v",
+			err, database.ErrEncryptionKey)
+	}
+	plainPath := path + ".plain"
+	if err := os.WriteFile(plainPath, want, 0600); err != nil {
+		t.Fatalf("unexpected error writing plain file: %v", err)
+	}
+	_, err = OpenFile(plainPath, os.O_RDWR, 0600, key)
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error opening plain file -- got %v, want %v",
+			err, database.ErrEncryptionKey)
+	}
+}
+
+// TestOpenLevelDB ensures encrypted leveldb databases are usable, do not store
+// their contents in plaintext, and are only able to be opened with the right
+// key.
+func TestOpenLevelDB(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(0x01)
+	path := filepath.Join(t.TempDir(), "ldb")
+	opts := &opt.Options{ErrorIfExist: true, WriteBuffer: 64 * 1024}
+	db, err := OpenLevelDB(path, key, opts)
+	if err != nil {
+		t.Fatalf("unexpected error creating database: %v", err)
+	}
+
+	// Write enough entries to produce tables in addition to the journal.
+	value := []byte(strings.Repeat("plaintextvalue", 20))
+	for i := 0; i < 2000; i++ {
+		k := []byte{byte(i >> 8), byte(i)}
+		if err := db.Put(k, value, nil); err != nil {
+			t.Fatalf("unexpected error writing entry: %v", err)
+		}
+	}
+	if err := db.CompactRange(util.Range{}); err != nil {
+		t.Fatalf("unexpected error compacting database: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("unexpected error closing database: %v", err)
+	}
+
+	// Ensure none of the files contain the plaintext.
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading database dir: %v", err)
+	}
+	for _, entry := range entries {
+		raw, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %v", entry.Name(), err)
+		}
+		if bytes.Contains(raw, []byte("plaintextvalue")) {
+			t.Fatalf("%s contains the plaintext", entry.Name())
+		}
+	}
+
+	// Reopen the database in read-only mode and ensure all of the entries
+	// are intact.  This also ensures the storage was closed so the lock is
+	// able to be acquired again.
+	db, err = OpenLevelDB(path, key, &opt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("unexpected error reopening database: %v", err)
+	}
+	iter := db.NewIterator(nil, nil)
+	var count int
+	for iter.Next() {
+		if !bytes.Equal(iter.Value(), value) {
+			t.Fatalf("unexpected value for key %x", iter.Key())
+		}
+		count++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		t.Fatalf("unexpected error iterating database: %v", err)
+	}
+	if count != 2000 {
+		t.Fatalf("unexpected number of entries -- got %d, want 2000", count)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("unexpected error closing database: %v", err)
+	}
+
+	// Ensure the database is not able to be opened with the wrong key or
+	// without a key.
+	_, err = OpenLevelDB(path, testKey(0x02), nil)
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error opening with wrong key -- got %v, want %v",
+			err, database.ErrEncryptionKey)
+	}
+	_, err = OpenLevelDB(path, nil, nil)
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error opening without key -- got %v, want %v",
+			err, database.ErrEncryptionKey)
+	}
+
+	// Ensure an existing unencrypted database is not able to be opened with
+	// a key.
+	plainPath := filepath.Join(t.TempDir(), "plain")
+	plainDB, err := leveldb.OpenFile(plainPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating plain database: %v", err)
+	}
+	plainDB.Close()
+	_, err = OpenLevelDB(plainPath, key, nil)
+	if !errors.Is(err, database.ErrEncryptionKey) {
+		t.Fatalf("unexpected error opening plain database with key -- got "+
+			"%v, want %v", err, database.ErrEncryptionKey)
+	}
+}
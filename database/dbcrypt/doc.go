@@ -0,0 +1,29 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package dbcrypt provides encryption at rest for the files that back databases.
+
+It is intended for environments where the data directory must be encrypted to
+comply with policy, but full-disk encryption is not available.  The contents of
+every file are split into records that are each sealed with AES-256-GCM under a
+key derived from a 32-byte database key and a random salt stored in the header
+of the file.  Each record is bound to its position in the file, so reordering,
+modifying, or substituting records is detected when they are read.
+
+Two forms of access are provided.  OpenLevelDB opens a leveldb database whose
+tables, journals, and manifests are encrypted, and File provides an encrypted
+file that is read at arbitrary offsets and appended to, such as the flat files
+used to store blocks.
+
+Keys are 32 bytes and are typically loaded from a hex-encoded key file with
+LoadKeyFile, or parsed with ParseKey from the output of a command that fetches
+the key from a keyring.
+
+Encryption must be enabled when a database is created.  Existing unencrypted
+databases are not converted, and opening them with a key, opening an encrypted
+database without one, or opening it with a different key, results in an error
+with the kind database.ErrEncryptionKey.
+*/
+package dbcrypt
@@ -0,0 +1,269 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dbcrypt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// File is an encrypted file that is accessed in terms of its plaintext.  It
+// acts very similar to an *os.File except that writes are only permitted at the
+// end of the file.
+//
+// Writes are buffered in memory until a full record is available or Sync or
+// Close is called.  Reads include the buffered writes.
+//
+// It is safe for concurrent access.
+type File struct {
+	mtx      sync.RWMutex
+	file     *os.File
+	writable bool
+	idx      recordIndex
+	pending  []byte
+}
+
+// OpenFile opens the named encrypted file with the provided flags and
+// permissions the same way as os.OpenFile does.  An empty file that is opened
+// for writing is initialized with a new header.  Otherwise, the file must have
+// been encrypted with the provided key.
+//
+// Files that are opened for writing must be opened with os.O_RDWR since reading
+// the existing records is required to append to them.
+func OpenFile(name string, flag int, perm os.FileMode, key []byte) (*File, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	f := &File{
+		file:     file,
+		writable: flag&(os.O_WRONLY|os.O_RDWR) != 0,
+		idx:      recordIndex{name: fmt.Sprintf("file %q", name)},
+	}
+	if fi.Size() == 0 && f.writable {
+		header, aead, err := newHeader(key)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		if _, err := file.WriteAt(header, 0); err != nil {
+			file.Close()
+			return nil, err
+		}
+		f.idx.aead = aead
+		f.idx.fileSize = int64(headerSize)
+		return f, nil
+	}
+
+	f.idx.aead, err = readHeader(file, key, f.idx.name)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := f.idx.scan(file, fi.Size()); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Discard any partially written record so new records are appended
+	// directly after the last complete one.
+	if f.writable && f.idx.fileSize < fi.Size() {
+		if err := file.Truncate(f.idx.fileSize); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// FileSize returns the size of the plaintext of the named encrypted file.
+func FileSize(name string, key []byte) (int64, error) {
+	f, err := OpenFile(name, os.O_RDONLY, 0, key)
+	if err != nil {
+		return 0, err
+	}
+	size := f.Size()
+	return size, f.Close()
+}
+
+// Size returns the size of the plaintext of the file including any buffered
+// writes.
+func (f *File) Size() int64 {
+	f.mtx.RLock()
+	size := f.idx.plainSize + int64(len(f.pending))
+	f.mtx.RUnlock()
+	return size
+}
+
+// ReadAt reads len(p) bytes of plaintext starting at the provided offset.  It
+// returns io.EOF when fewer bytes are available.
+//
+// This is part of the io.ReaderAt interface implementation.
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+
+	var n int
+	if off < f.idx.plainSize {
+		sealed := p
+		if remaining := f.idx.plainSize - off; int64(len(sealed)) > remaining {
+			sealed = sealed[:remaining]
+		}
+		var err error
+		n, err = f.idx.readAt(f.file, sealed, off)
+		if err != nil {
+			return n, err
+		}
+	}
+	if pendingOff := off + int64(n) - f.idx.plainSize; n < len(p) &&
+		pendingOff < int64(len(f.pending)) {
+
+		n += copy(p[n:], f.pending[pendingOff:])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// writeRecord seals the provided plaintext into a new record at the end of the
+// file.
+//
+// This function MUST be called with the file mutex held (for writes).
+func (f *File) writeRecord(plain []byte) error {
+	rec, err := f.idx.seal(plain)
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.WriteAt(rec, f.idx.fileSize); err != nil {
+		return err
+	}
+	f.idx.add(len(plain), len(rec))
+	return nil
+}
+
+// WriteAt writes the provided plaintext at the provided offset which must be
+// the current size of the file.
+//
+// This is part of the io.WriterAt interface implementation.
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !f.writable {
+		return 0, fmt.Errorf("%s is not open for writing", f.idx.name)
+	}
+	if size := f.idx.plainSize + int64(len(f.pending)); off != size {
+		return 0, fmt.Errorf("%s only supports writes at its end (offset "+
+			"%d) -- requested offset %d", f.idx.name, size, off)
+	}
+
+	f.pending = append(f.pending, p...)
+	for len(f.pending) >= recordSize {
+		if err := f.writeRecord(f.pending[:recordSize]); err != nil {
+			return 0, err
+		}
+		f.pending = append(f.pending[:0], f.pending[recordSize:]...)
+	}
+	return len(p), nil
+}
+
+// Truncate changes the size of the plaintext of the file to the provided size
+// which may not be more than its current size.  The record that contains the
+// new end of the file, if any, is sealed again the next time the file is
+// synced.
+func (f *File) Truncate(size int64) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !f.writable {
+		return fmt.Errorf("%s is not open for writing", f.idx.name)
+	}
+	if size < 0 || size > f.idx.plainSize+int64(len(f.pending)) {
+		return fmt.Errorf("%s is unable to be truncated to %d bytes",
+			f.idx.name, size)
+	}
+	if size >= f.idx.plainSize {
+		f.pending = f.pending[:size-f.idx.plainSize]
+		return nil
+	}
+
+	// Keep the part of the record that contains the new end of the file as
+	// buffered data and remove it along with every record after it.
+	i := f.idx.find(size)
+	rec := f.idx.records[i]
+	var keep []byte
+	if size > rec.plainOff {
+		buf := make([]byte, recordHeaderSize+recordSize+tagSize)
+		plain, err := f.idx.open(f.file, rec, buf)
+		if err != nil {
+			return err
+		}
+		keep = append(keep, plain[:size-rec.plainOff]...)
+	}
+	if err := f.file.Truncate(rec.fileOff); err != nil {
+		return err
+	}
+	f.idx.records = f.idx.records[:i]
+	f.idx.plainSize = rec.plainOff
+	f.idx.fileSize = rec.fileOff
+	f.pending = keep
+	return nil
+}
+
+// flush seals any buffered writes into a new record.
+//
+// This function MUST be called with the file mutex held (for writes).
+func (f *File) flush() error {
+	if len(f.pending) == 0 {
+		return nil
+	}
+	if err := f.writeRecord(f.pending); err != nil {
+		return err
+	}
+	f.pending = f.pending[:0]
+	return nil
+}
+
+// Sync seals any buffered writes and commits the file to stable storage.
+func (f *File) Sync() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if err := f.flush(); err != nil {
+		return err
+	}
+	return f.file.Sync()
+}
+
+// Close seals any buffered writes and closes the file.
+//
+// This is part of the io.Closer interface implementation.
+func (f *File) Close() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if err := f.flush(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dbcrypt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+const (
+	// markerName is the name of the file in the directory of an encrypted
+	// leveldb database that marks it as encrypted.  It contains a file header
+	// so the key is checked before the database is opened.
+	markerName = "ENCRYPTION"
+
+	// currentName is the name of the file leveldb uses to identify the
+	// manifest of an existing database.
+	currentName = "CURRENT"
+)
+
+// reader provides read access to an encrypted leveldb storage file in terms of
+// its plaintext.
+//
+// It implements the storage.Reader interface.
+type reader struct {
+	r   storage.Reader
+	idx recordIndex
+	pos int64
+}
+
+// ReadAt reads len(p) bytes of plaintext starting at the provided offset.
+//
+// This is part of the io.ReaderAt interface implementation.
+func (r *reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	n, err := r.idx.readAt(r.r, p, off)
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Read reads up to len(p) bytes of plaintext from the current position.
+//
+// This is part of the io.Reader interface implementation.
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek sets the position for the next Read in terms of the plaintext.
+//
+// This is part of the io.Seeker interface implementation.
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.idx.plainSize
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// Close closes the underlying file.
+//
+// This is part of the io.Closer interface implementation.
+func (r *reader) Close() error {
+	return r.r.Close()
+}
+
+// writer seals the plaintext written to an encrypted leveldb storage file.
+// Every write is sealed into records immediately so the data leveldb writes is
+// handed to the underlying file in the same way it is without encryption.
+//
+// It implements the storage.Writer interface.
+type writer struct {
+	w   storage.Writer
+	idx recordIndex
+}
+
+// Write seals the provided plaintext and writes it to the underlying file.
+//
+// This is part of the io.Writer interface implementation.
+func (w *writer) Write(p []byte) (int, error) {
+	var n int
+	for n < len(p) {
+		plain := p[n:]
+		if len(plain) > recordSize {
+			plain = plain[:recordSize]
+		}
+		rec, err := w.idx.seal(plain)
+		if err != nil {
+			return n, err
+		}
+		if _, err := w.w.Write(rec); err != nil {
+			return n, err
+		}
+		w.idx.plainSize += int64(len(plain))
+		n += len(plain)
+	}
+	return n, nil
+}
+
+// Sync commits the underlying file to stable storage.
+//
+// This is part of the storage.Syncer interface implementation.
+func (w *writer) Sync() error {
+	return w.w.Sync()
+}
+
+// Close closes the underlying file.
+//
+// This is part of the io.Closer interface implementation.
+func (w *writer) Close() error {
+	return w.w.Close()
+}
+
+// storageLock releases the lock on an encrypted storage and closes the
+// underlying storage since leveldb does not close storages it did not open
+// itself.
+type storageLock struct {
+	storage.Locker
+	stor storage.Storage
+}
+
+// Unlock releases the lock and closes the underlying storage.
+//
+// This is part of the storage.Locker interface implementation.
+func (l *storageLock) Unlock() {
+	l.Locker.Unlock()
+	_ = l.stor.Close()
+}
+
+// encryptedStorage is a leveldb storage that encrypts the contents of the
+// tables, journals, and manifests of the database with AES-GCM.
+//
+// It implements the storage.Storage interface.
+type encryptedStorage struct {
+	storage.Storage
+	key []byte
+}
+
+// Lock locks the underlying storage.  The storage is closed when the returned
+// lock is released.
+//
+// This is part of the storage.Storage interface implementation.
+func (s *encryptedStorage) Lock() (storage.Locker, error) {
+	l, err := s.Storage.Lock()
+	if err != nil {
+		return nil, err
+	}
+	return &storageLock{Locker: l, stor: s.Storage}, nil
+}
+
+// Open opens the file with the provided descriptor for reading its plaintext.
+//
+// This is part of the storage.Storage interface implementation.
+func (s *encryptedStorage) Open(fd storage.FileDesc) (storage.Reader, error) {
+	r, err := s.Storage.Open(fd)
+	if err != nil {
+		return nil, err
+	}
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	idx := recordIndex{name: fmt.Sprintf("database file %s", fd)}
+	idx.aead, err = readHeader(r, s.key, idx.name)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	if err := idx.scan(r, size); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return &reader{r: r, idx: idx}, nil
+}
+
+// Create creates the file with the provided descriptor for writing plaintext
+// that is sealed before it is written to the file.
+//
+// This is part of the storage.Storage interface implementation.
+func (s *encryptedStorage) Create(fd storage.FileDesc) (storage.Writer, error) {
+	header, aead, err := newHeader(s.key)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.Storage.Create(fd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return &writer{
+		w: w,
+		idx: recordIndex{
+			name: fmt.Sprintf("database file %s", fd),
+			aead: aead,
+		},
+	}, nil
+}
+
+// checkMarker ensures the leveldb database at the provided path is encrypted
+// with the provided key, or not encrypted at all when the key is nil, and
+// returns whether the marker of an encrypted database needs to be created.
+func checkMarker(path string, key []byte) (bool, error) {
+	markerPath := filepath.Join(path, markerName)
+	header, err := os.ReadFile(markerPath)
+	switch {
+	case err == nil && key == nil:
+		str := fmt.Sprintf("database %q is encrypted and requires an "+
+			"encryption key", path)
+		return false, makeError(database.ErrEncryptionKey, str)
+
+	case err == nil:
+		_, err := parseHeader(key, header, fmt.Sprintf("database %q", path))
+		return false, err
+
+	case !os.IsNotExist(err):
+		return false, err
+
+	case key == nil:
+		return false, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(path, currentName)); err == nil {
+		str := fmt.Sprintf("database %q is not encrypted", path)
+		return false, makeError(database.ErrEncryptionKey, str)
+	}
+	return true, nil
+}
+
+// OpenLevelDB opens or creates the leveldb database at the provided path with
+// the provided options the same way as leveldb.OpenFile does while encrypting
+// its contents with the provided key.
+//
+// The database is opened without encryption when the key is nil.  An error with
+// the kind database.ErrEncryptionKey is returned when the key is not the one an
+// existing database was encrypted with, when a key is provided for an existing
+// database that is not encrypted, or when no key is provided for an encrypted
+// database.
+//
+// NOTE: The names of the files and the informational log leveldb writes to the
+// directory are not encrypted.
+func OpenLevelDB(path string, key []byte, o *opt.Options) (*leveldb.DB, error) {
+	if key != nil {
+		if err := checkKey(key); err != nil {
+			return nil, err
+		}
+	}
+	createMarker, err := checkMarker(path, key)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return leveldb.OpenFile(path, o)
+	}
+
+	stor, err := storage.OpenFile(path, o.GetReadOnly())
+	if err != nil {
+		return nil, err
+	}
+	if createMarker && !o.GetReadOnly() {
+		header, _, err := newHeader(key)
+		if err != nil {
+			stor.Close()
+			return nil, err
+		}
+		markerPath := filepath.Join(path, markerName)
+		if err := os.WriteFile(markerPath, header, 0600); err != nil {
+			stor.Close()
+			return nil, err
+		}
+	}
+	db, err := leveldb.Open(&encryptedStorage{Storage: stor, key: key}, o)
+	if err != nil {
+		// The storage is closed through the lock when leveldb fails after
+		// acquiring it, but closing it again is harmless.
+		_ = stor.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -20,16 +20,23 @@ A quick overview of the features database provides are as follows:
   - Efficient retrieval of block headers and regions (transactions, scripts, etc)
   - Read-only and read-write transactions with both manual and managed modes
   - Nested buckets
+  - Snapshot-consistent range and prefix iterators in forward or reverse order
   - Supports registration of backend databases
+  - Optional encryption at rest via the dbcrypt package
+  - Optional hooks to observe operations for metrics and tracing
   - Comprehensive test coverage
 
 # Database
 
 The main entry point is the DB interface.  It exposes functionality for
 transactional-based access and storage of metadata and block data.  It is
-obtained via the Create and Open functions which take a database type string
-that identifies the specific database driver (backend) to use as well as
-arguments specific to the specified driver.
+obtained via the Create, Open, and OpenReadOnly functions which take a database
+type string that identifies the specific database driver (backend) to use as
+well as arguments specific to the specified driver.
+
+A database obtained via OpenReadOnly only allows read-only transactions and
+never modifies the underlying storage, which makes it suitable for analysis
+tools that inspect existing data.
 
 # Namespaces
 
@@ -87,5 +94,14 @@ The CreateBucket and CreateBucketIfNotExists functions on the Bucket interface
 provide the ability to create an arbitrary number of nested buckets.  It is
 a good idea to avoid a lot of buckets with little data in them as it could lead
 to poor page utilization depending on the specific driver in use.
+
+# Hooks
+
+The WithHooks function wraps a database so that the operations performed on it
+are observed by a Hooks implementation.  The hooks are notified when each
+operation starts along with the bucket it is performed on and again when it
+finishes with the size of the values involved and any resulting error.  This
+allows per-bucket operation counts, latencies, and value sizes to be measured
+and operations to be associated with tracing spans regardless of the driver.
 */
 package database
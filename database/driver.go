@@ -28,6 +28,13 @@ type Driver struct {
 	// ErrDbDoesNotExist if the database has not already been created.
 	Open func(args ...interface{}) (DB, error)
 
+	// OpenReadOnly is the function that will be invoked with all
+	// user-specified arguments to open the database in read-only mode.  This
+	// function must return ErrDbDoesNotExist if the database has not already
+	// been created.  It may be nil when the driver does not support opening
+	// databases in read-only mode.
+	OpenReadOnly func(args ...interface{}) (DB, error)
+
 	// UseLogger uses a specified Logger to output package logging info.
 	UseLogger func(logger slog.Logger)
 }
@@ -88,3 +95,27 @@ func Open(dbType string, args ...interface{}) (DB, error) {
 
 	return drv.Open(args...)
 }
+
+// OpenReadOnly opens an existing database for the specified type in read-only
+// mode.  The arguments are specific to the database type driver.  See the
+// documentation for the database driver for further details.
+//
+// Any attempts to modify a database opened in read-only mode, such as starting
+// a read-write transaction, return ErrDbReadOnly.  This allows analysis tools
+// to safely inspect a database without any risk of modifying it.
+//
+// ErrDbUnknownType will be returned if the database type is not registered and
+// ErrDbReadOnly will be returned if the driver does not support read-only mode.
+func OpenReadOnly(dbType string, args ...interface{}) (DB, error) {
+	drv, exists := drivers[dbType]
+	if !exists {
+		str := fmt.Sprintf("driver %q is not registered", dbType)
+		return nil, makeError(ErrDbUnknownType, str)
+	}
+	if drv.OpenReadOnly == nil {
+		str := fmt.Sprintf("driver %q does not support read-only mode", dbType)
+		return nil, makeError(ErrDbReadOnly, str)
+	}
+
+	return drv.OpenReadOnly(args...)
+}
@@ -99,6 +99,14 @@ func TestCreateOpenFail(t *testing.T) {
 			openError)
 		return
 	}
+
+	// Ensure opening a database with the new type in read-only mode fails
+	// with the expected error since the driver does not support it.
+	testName := "open read-only with unsupported mode"
+	_, err = database.OpenReadOnly(dbType)
+	if !checkDbError(t, testName, err, database.ErrDbReadOnly) {
+		return
+	}
 }
 
 // TestCreateOpenUnsupported ensures that attempting to create or open an
@@ -120,4 +128,12 @@ func TestCreateOpenUnsupported(t *testing.T) {
 	if !checkDbError(t, testName, err, database.ErrDbUnknownType) {
 		return
 	}
+
+	// Ensure opening a database with an unsupported type in read-only mode
+	// fails with the expected error.
+	testName = "open read-only with unsupported database type"
+	_, err = database.OpenReadOnly(dbType)
+	if !checkDbError(t, testName, err, database.ErrDbUnknownType) {
+		return
+	}
 }
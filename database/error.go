@@ -44,6 +44,11 @@ const (
 	// is already open.
 	ErrDbAlreadyOpen = ErrorKind("ErrDbAlreadyOpen")
 
+	// ErrDbReadOnly indicates an attempt was made to modify a database that
+	// was opened in read-only mode or that a driver does not support opening
+	// databases in read-only mode.
+	ErrDbReadOnly = ErrorKind("ErrDbReadOnly")
+
 	// ErrInvalid indicates the specified database is not valid.
 	ErrInvalid = ErrorKind("ErrInvalid")
 
@@ -51,6 +56,12 @@ const (
 	// means the database is corrupt.
 	ErrCorruption = ErrorKind("ErrCorruption")
 
+	// ErrEncryptionKey indicates the encryption key provided for a database
+	// is malformed, is missing for an encrypted database, is provided for a
+	// database that is not encrypted, or is not the key the database was
+	// encrypted with.
+	ErrEncryptionKey = ErrorKind("ErrEncryptionKey")
+
 	// ------------------------------------------
 	// Errors related to database transactions.
 	// ------------------------------------------
@@ -23,8 +23,10 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrDbExists, "ErrDbExists"},
 		{ErrDbNotOpen, "ErrDbNotOpen"},
 		{ErrDbAlreadyOpen, "ErrDbAlreadyOpen"},
+		{ErrDbReadOnly, "ErrDbReadOnly"},
 		{ErrInvalid, "ErrInvalid"},
 		{ErrCorruption, "ErrCorruption"},
+		{ErrEncryptionKey, "ErrEncryptionKey"},
 		{ErrTxClosed, "ErrTxClosed"},
 		{ErrTxNotWritable, "ErrTxNotWritable"},
 		{ErrBucketNotFound, "ErrBucketNotFound"},
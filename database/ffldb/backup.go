@@ -0,0 +1,249 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	// backupBatchSize is the approximate number of bytes of metadata written
+	// to the backup in each batch.
+	backupBatchSize = 4 * 1024 * 1024
+
+	// backupChunkSize is the number of bytes of block data copied to the
+	// backup at a time.
+	backupChunkSize = 4 * 1024 * 1024
+)
+
+// backupState houses the progress of a backup and reports it to the caller.
+type backupState struct {
+	ctx      context.Context
+	progress func(database.BackupProgress)
+	copied   uint64
+	total    uint64
+}
+
+// advance adds the provided number of bytes to the number of bytes copied,
+// reports the progress to the caller, and returns an error when the backup
+// has been canceled.
+func (s *backupState) advance(n uint64) error {
+	s.copied += n
+	if s.copied > s.total {
+		s.total = s.copied
+	}
+	if s.progress != nil {
+		s.progress(database.BackupProgress{
+			BytesCopied: s.copied,
+			TotalBytes:  s.total,
+		})
+	}
+	return s.ctx.Err()
+}
+
+// backupMetadata writes all of the metadata in the provided snapshot to a new
+// metadata database at the provided path that is encrypted with the provided
+// key when it is not nil.
+func backupMetadata(snap *dbCacheSnapshot, dbPath string, encryptionKey []byte, state *backupState) error {
+	opts := opt.Options{
+		ErrorIfExist: true,
+		Strict:       opt.DefaultStrict,
+		Compression:  opt.NoCompression,
+		Filter:       filter.NewBloomFilter(10),
+	}
+	ldb, err := dbcrypt.OpenLevelDB(dbPath, encryptionKey, &opts)
+	if err != nil {
+		return convertErr(err.Error(), err)
+	}
+
+	iter := snap.NewIterator(&util.Range{})
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	var batchBytes uint64
+	for ok := iter.First(); ok; ok = iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		batch.Put(key, value)
+		batchBytes += uint64(len(key) + len(value))
+		if batchBytes < backupBatchSize {
+			continue
+		}
+		if err := ldb.Write(batch, nil); err != nil {
+			ldb.Close()
+			return convertErr("failed to write metadata backup", err)
+		}
+		batch.Reset()
+		if err := state.advance(batchBytes); err != nil {
+			ldb.Close()
+			return err
+		}
+		batchBytes = 0
+	}
+	if err := iter.Error(); err != nil {
+		ldb.Close()
+		return convertErr("failed to iterate metadata", err)
+	}
+	if err := ldb.Write(batch, &opt.WriteOptions{Sync: true}); err != nil {
+		ldb.Close()
+		return convertErr("failed to write metadata backup", err)
+	}
+	if err := ldb.Close(); err != nil {
+		return convertErr("failed to close metadata backup", err)
+	}
+	return state.advance(batchBytes)
+}
+
+// backupBlockFile copies the provided number of bytes of block data from the
+// start of the provided block file of the store to the destination block file
+// which is encrypted the same way as the block files of the store.
+func backupBlockFile(store *blockStore, fileNum uint32, dstPath string, size int64, state *backupState) error {
+	dst, err := openBlockFile(dstPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600,
+		store.encryptionKey)
+	if err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error())
+	}
+	var buf []byte
+	for offset := int64(0); offset < size; {
+		chunkSize := int64(backupChunkSize)
+		if chunkSize > size-offset {
+			chunkSize = size - offset
+		}
+		if buf == nil {
+			buf = make([]byte, chunkSize)
+		}
+		chunk := buf[:chunkSize]
+		if err := store.readFileData(fileNum, offset, chunk); err != nil {
+			dst.Close()
+			return err
+		}
+		if _, err := dst.WriteAt(chunk, offset); err != nil {
+			dst.Close()
+			str := fmt.Sprintf("failed to copy block file %d: %v", fileNum,
+				err)
+			return makeDbErr(database.ErrDriverSpecific, str)
+		}
+		offset += chunkSize
+		if err := state.advance(uint64(chunkSize)); err != nil {
+			dst.Close()
+			return err
+		}
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return makeDbErr(database.ErrDriverSpecific, err.Error())
+	}
+	if err := dst.Close(); err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error())
+	}
+	return nil
+}
+
+// backup is the implementation function for the Backup database method.  See
+// its documentation for more details.
+//
+// It is only separate so the backup directory can be removed on any failure.
+func (db *db) backup(ctx context.Context, dir string, state *backupState) error {
+	// Open a read-only transaction to obtain a consistent snapshot of the
+	// metadata.  The write cursor in the snapshot marks the end of the block
+	// data the metadata refers to.  Blocks are only ever appended to the flat
+	// files, so everything before it is unchanged while the database
+	// continues to be updated.
+	tx, err := db.begin(false)
+	if err != nil {
+		return err
+	}
+	writeRow := tx.snapshot.Get(bucketizedKey(metadataBucketID,
+		writeLocKeyName))
+	if writeRow == nil {
+		_ = tx.Rollback()
+		str := "write cursor does not exist"
+		return makeDbErr(database.ErrCorruption, str)
+	}
+	lastFileNum, lastFileOffset, err := deserializeWriteRow(writeRow)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	// Determine the sizes of the block files to copy.
+	fileSizes := make([]int64, 0, lastFileNum+1)
+	encryptionKey := db.store.encryptionKey
+	for fileNum := uint32(0); fileNum < lastFileNum; fileNum++ {
+		filePath := db.store.filePath(fileNum)
+		size, err := blockFileSize(filePath, encryptionKey)
+		if err != nil {
+			_ = tx.Rollback()
+			return makeDbErr(database.ErrDriverSpecific, err.Error())
+		}
+		fileSizes = append(fileSizes, size)
+		state.total += uint64(size)
+	}
+	fileSizes = append(fileSizes, int64(lastFileOffset))
+	state.total += uint64(lastFileOffset)
+	metadataSizes, err := db.cache.ldb.SizeOf([]util.Range{{}})
+	if err == nil {
+		state.total += uint64(metadataSizes.Sum())
+	}
+
+	// Copy the metadata and release the transaction once it is done so the
+	// database is able to reclaim the snapshot.
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		_ = tx.Rollback()
+		return makeDbErr(database.ErrDriverSpecific, err.Error())
+	}
+	err = backupMetadata(tx.snapshot, filepath.Join(dir, metadataDbName),
+		encryptionKey, state)
+	_ = tx.Rollback()
+	if err != nil {
+		return err
+	}
+
+	// Copy the block files up to the write cursor.  The current file is
+	// copied up to the cursor offset since any data after it is not yet
+	// committed and might be rolled back.  Notice that the current file is
+	// created even when it is empty so the block files match the cursor.
+	for fileNum, size := range fileSizes {
+		dstPath := blockFilePath(dir, uint32(fileNum))
+		err := backupBlockFile(db.store, uint32(fileNum), dstPath, size, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Backup writes a consistent copy of the database as of the time it is called
+// to the provided directory while the database remains usable by other
+// callers.  The directory must not already exist and is removed when the
+// backup fails.
+//
+// The provided function, which may be nil, is invoked periodically with the
+// progress of the backup.  The backup is aborted when the provided context is
+// canceled.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) Backup(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	if fileExists(dir) {
+		str := fmt.Sprintf("backup directory %q already exists", dir)
+		return makeDbErr(database.ErrDbExists, str)
+	}
+
+	state := &backupState{ctx: ctx, progress: progress}
+	if err := db.backup(ctx, dir, state); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	"github.com/decred/dcrd/wire"
 )
 
@@ -37,6 +38,12 @@ const (
 	// the future.
 	blockFilenameTemplate = "%09d.fdb"
 
+	// blockDirTemplate is the pattern used for naming the subdirectories
+	// that house the block files when they are sharded.  Each subdirectory
+	// is named after the block file number of the first file it houses
+	// divided by the number of files per subdirectory.
+	blockDirTemplate = "%06d"
+
 	// maxOpenFiles is the max number of open files to maintain in the
 	// open blocks cache.  Note that this does not include the current
 	// write file, so there will typically be one more than this value open.
@@ -50,6 +57,11 @@ const (
 	// constant.
 	maxBlockFileSize uint32 = 512 * 1024 * 1024 // 512 MiB
 
+	// defaultHotBlockFiles is the default number of the most recent block
+	// files that are kept in the base path when older files are moved to a
+	// cold path.
+	defaultHotBlockFiles = 8
+
 	// blockLocSize is the number of bytes the serialized block location
 	// data that is stored in the block index.
 	//
@@ -120,6 +132,28 @@ type blockStore struct {
 	// basePath is the base path used for the flat block files and metadata.
 	basePath string
 
+	// encryptionKey is the key the flat block files are encrypted with.  The
+	// files are not encrypted when it is nil.
+	encryptionKey []byte
+
+	// filesPerDir is the number of flat block files housed by each
+	// subdirectory of the base and cold paths.  The files are stored
+	// directly in the paths when it is zero.
+	filesPerDir uint32
+
+	// coldPath is the secondary path older flat block files are moved to.
+	// It is empty when the files are never moved.
+	//
+	// hotFiles is the number of the most recent flat block files that are
+	// kept in the base path when the cold path is set.
+	coldPath string
+	hotFiles uint32
+
+	// mover houses the state used to move the older flat block files to
+	// the cold path in the background.  It is nil when the files are never
+	// moved.
+	mover *coldMover
+
 	// The following fields are related to the flat files which hold the
 	// actual blocks.   The number of open files is limited by maxOpenFiles.
 	//
@@ -227,6 +261,77 @@ func blockFilePath(dbPath string, fileNum uint32) string {
 	return filepath.Join(dbPath, fileName)
 }
 
+// shardedBlockFilePath returns the file path for the provided block file number
+// when the block files are sharded into subdirectories that each house the
+// provided number of files.  The files are not sharded when it is zero.
+func shardedBlockFilePath(dbPath string, fileNum, filesPerDir uint32) string {
+	if filesPerDir == 0 {
+		return blockFilePath(dbPath, fileNum)
+	}
+	dirName := fmt.Sprintf(blockDirTemplate, fileNum/filesPerDir)
+	return blockFilePath(filepath.Join(dbPath, dirName), fileNum)
+}
+
+// findFile returns the path of the existing flat file for the passed block
+// file number in the provided path.  Both the sharded and unsharded layouts
+// are checked so the files are still found when the number of files per
+// subdirectory changes.  An empty string is returned when the file does not
+// exist in the path.
+func (s *blockStore) findFile(dbPath string, fileNum uint32) string {
+	filePath := shardedBlockFilePath(dbPath, fileNum, s.filesPerDir)
+	if fileExists(filePath) {
+		return filePath
+	}
+	if s.filesPerDir != 0 {
+		filePath = blockFilePath(dbPath, fileNum)
+		if fileExists(filePath) {
+			return filePath
+		}
+	}
+	return ""
+}
+
+// filePath returns the path of the flat file for the passed block file number.
+// Existing files are first looked up in the base path and then in the cold
+// path so that reads are transparent regardless of where the file is stored.
+// The path new files are created at in the base path is returned when the file
+// does not exist.
+func (s *blockStore) filePath(fileNum uint32) string {
+	if filePath := s.findFile(s.basePath, fileNum); filePath != "" {
+		return filePath
+	}
+	if s.coldPath != "" {
+		if filePath := s.findFile(s.coldPath, fileNum); filePath != "" {
+			return filePath
+		}
+	}
+	return shardedBlockFilePath(s.basePath, fileNum, s.filesPerDir)
+}
+
+// openBlockFile opens the block file at the provided path with the provided
+// flags and permissions.  The file is accessed through an encryption layer when
+// the provided key is not nil.
+func openBlockFile(filePath string, flag int, perm os.FileMode, key []byte) (filer, error) {
+	if key != nil {
+		return dbcrypt.OpenFile(filePath, flag, perm, key)
+	}
+	return os.OpenFile(filePath, flag, perm)
+}
+
+// blockFileSize returns the number of bytes of block data in the block file at
+// the provided path which is encrypted with the provided key when it is not
+// nil.
+func blockFileSize(filePath string, key []byte) (int64, error) {
+	if key != nil {
+		return dbcrypt.FileSize(filePath, key)
+	}
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
 // openWriteFile returns a file handle for the passed flat file number in
 // read/write mode.  The file will be created if needed.  It is typically used
 // for the current file that will have all new data appended.  Unlike openFile,
@@ -236,8 +341,19 @@ func (s *blockStore) openWriteFile(fileNum uint32) (filer, error) {
 	// The current block file needs to be read-write so it is possible to
 	// append to it.  Also, it shouldn't be part of the least recently used
 	// file.
-	filePath := blockFilePath(s.basePath, fileNum)
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0666)
+	filePath := s.filePath(fileNum)
+	if s.filesPerDir != 0 {
+		dirPath := filepath.Dir(filePath)
+		if !fileExists(dirPath) {
+			if err := os.MkdirAll(dirPath, 0700); err != nil {
+				str := fmt.Sprintf("failed to create directory %q: %v",
+					dirPath, err)
+				return nil, makeDbErr(database.ErrDriverSpecific, str)
+			}
+		}
+	}
+	file, err := openBlockFile(filePath, os.O_RDWR|os.O_CREATE, 0666,
+		s.encryptionKey)
 	if err != nil {
 		str := fmt.Sprintf("failed to open file %q: %v", filePath, err)
 		return nil, makeDbErr(database.ErrDriverSpecific, str)
@@ -255,8 +371,8 @@ func (s *blockStore) openWriteFile(fileNum uint32) (filer, error) {
 // for WRITES.
 func (s *blockStore) openFile(fileNum uint32) (*lockableFile, error) {
 	// Open the appropriate file as read-only.
-	filePath := blockFilePath(s.basePath, fileNum)
-	file, err := os.Open(filePath)
+	filePath := s.filePath(fileNum)
+	file, err := openBlockFile(filePath, os.O_RDONLY, 0, s.encryptionKey)
 	if err != nil {
 		str := fmt.Sprintf("failed to open read-only file %q: %v",
 			filePath, err)
@@ -304,7 +420,7 @@ func (s *blockStore) openFile(fileNum uint32) (*lockableFile, error) {
 // must already be closed and it is the responsibility of the caller to do any
 // other state cleanup necessary.
 func (s *blockStore) deleteFile(fileNum uint32) error {
-	filePath := blockFilePath(s.basePath, fileNum)
+	filePath := s.filePath(fileNum)
 	if err := os.Remove(filePath); err != nil {
 		str := fmt.Sprintf("failed to delete file %q: %v", filePath, err)
 		return makeDbErr(database.ErrDriverSpecific, str)
@@ -559,6 +675,26 @@ func (s *blockStore) readBlock(hash *chainhash.Hash, loc blockLocation) ([]byte,
 	return serializedData[8 : n-4], nil
 }
 
+// readFileData reads len(data) bytes from the provided block file starting at
+// the provided offset into the provided buffer.  Unlike reading the file
+// directly, it includes any data that has been written to the current write
+// file, but is still buffered by the encryption layer.
+func (s *blockStore) readFileData(fileNum uint32, offset int64, data []byte) error {
+	blockFile, err := s.blockFile(fileNum)
+	if err != nil {
+		return err
+	}
+	_, err = blockFile.file.ReadAt(data, offset)
+	blockFile.RUnlock()
+	if err != nil {
+		str := fmt.Sprintf("failed to read data from block file %d, "+
+			"offset %d, len %d: %v", fileNum, offset, len(data), err)
+		return makeDbErr(database.ErrDriverSpecific, str)
+	}
+
+	return nil
+}
+
 // readBlockRegion reads the specified amount of data at the provided offset for
 // a given block location.  The offset is relative to the start of the
 // serialized block (as opposed to the beginning of the block record).  This
@@ -718,42 +854,44 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 	}
 }
 
-// scanBlockFiles searches the database directory for all flat block files to
-// find the end of the most recent file.  This position is considered the
-// current write cursor which is also stored in the metadata.  Thus, it is used
-// to detect unexpected shutdowns in the middle of writes so the block files
-// can be reconciled.
-func scanBlockFiles(dbPath string) (int, uint32) {
+// scanBlockFiles searches the database and cold directories for all flat block
+// files to find the end of the most recent file.  This position is considered
+// the current write cursor which is also stored in the metadata.  Thus, it is
+// used to detect unexpected shutdowns in the middle of writes so the block
+// files can be reconciled.
+func (s *blockStore) scanBlockFiles() (int, uint32, error) {
 	lastFile := -1
-	fileLen := uint32(0)
 	for i := 0; ; i++ {
-		filePath := blockFilePath(dbPath, uint32(i))
-		st, err := os.Stat(filePath)
-		if err != nil {
+		filePath := s.filePath(uint32(i))
+		if !fileExists(filePath) {
 			break
 		}
 		lastFile = i
+	}
 
-		fileLen = uint32(st.Size())
+	// The length of the most recent file is the amount of block data in it
+	// which differs from its size on disk when it is encrypted.
+	var fileLen uint32
+	if lastFile != -1 {
+		filePath := s.filePath(uint32(lastFile))
+		size, err := blockFileSize(filePath, s.encryptionKey)
+		if err != nil {
+			str := fmt.Sprintf("failed to scan file %q: %v", filePath, err)
+			return 0, 0, makeDbErr(database.ErrDriverSpecific, str)
+		}
+		fileLen = uint32(size)
 	}
 
 	log.Tracef("Scan found latest block file #%d with length %d", lastFile,
 		fileLen)
-	return lastFile, fileLen
+	return lastFile, fileLen, nil
 }
 
 // newBlockStore returns a new block store with the current block file number
-// and offset set and all fields initialized.
-func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
-	// Look for the end of the latest block to file to determine what the
-	// write cursor position is from the viewpoint of the block files on
-	// disk.
-	fileNum, fileOff := scanBlockFiles(basePath)
-	if fileNum == -1 {
-		fileNum = 0
-		fileOff = 0
-	}
-
+// and offset set and all fields initialized.  The provided options, which may be
+// nil, determine the encryption key of the block files as well as where they
+// are stored.
+func newBlockStore(basePath string, network wire.CurrencyNet, dbOpts *database.Options) (*blockStore, error) {
 	store := &blockStore{
 		network:          network,
 		basePath:         basePath,
@@ -761,15 +899,40 @@ func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
 		openBlockFiles:   make(map[uint32]*lockableFile),
 		openBlocksLRU:    list.New(),
 		fileNumToLRUElem: make(map[uint32]*list.Element),
+	}
+	if dbOpts != nil {
+		store.encryptionKey = dbOpts.EncryptionKey
+		if dbOpts.BlockFilesPerDir > 0 {
+			store.filesPerDir = uint32(dbOpts.BlockFilesPerDir)
+		}
+		if dbOpts.ColdBlockDir != "" {
+			store.coldPath = dbOpts.ColdBlockDir
+			store.hotFiles = defaultHotBlockFiles
+			if dbOpts.HotBlockFiles > 0 {
+				store.hotFiles = uint32(dbOpts.HotBlockFiles)
+			}
+		}
+	}
+
+	// Look for the end of the latest block to file to determine what the
+	// write cursor position is from the viewpoint of the block files on
+	// disk.
+	fileNum, fileOff, err := store.scanBlockFiles()
+	if err != nil {
+		return nil, err
+	}
+	if fileNum == -1 {
+		fileNum = 0
+		fileOff = 0
+	}
 
-		writeCursor: &writeCursor{
-			curFile:    &lockableFile{},
-			curFileNum: uint32(fileNum),
-			curOffset:  fileOff,
-		},
+	store.writeCursor = &writeCursor{
+		curFile:    &lockableFile{},
+		curFileNum: uint32(fileNum),
+		curOffset:  fileOff,
 	}
 	store.openFileFunc = store.openFile
 	store.openWriteFileFunc = store.openWriteFile
 	store.deleteFileFunc = store.deleteFile
-	return store
+	return store, nil
 }
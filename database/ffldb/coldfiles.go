@@ -0,0 +1,245 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// This file contains the implementation functions for moving the older flat
+// files that house the blocks to a secondary cold path.
+
+package ffldb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/database/v3"
+)
+
+// coldMover houses the state used to move the older flat block files of a
+// block store to its cold path in the background.
+type coldMover struct {
+	// committedFileNum is the block file number of the write cursor as of
+	// the most recently committed transaction.  Only the files before it
+	// are ever moved since it and the files after it might still be
+	// modified.  It must be accessed atomically.
+	committedFileNum uint32
+
+	// nextFileNum is the number of the first block file that might still
+	// need to be moved.  It is only accessed by the mover goroutine.
+	nextFileNum uint32
+
+	signal chan struct{}
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// copyFile copies the contents of the file at the provided source path to a
+// new file at the provided destination path and syncs it to disk.
+func copyFile(srcPath, dstPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(dstPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// closeFile closes the read-only file handle for the passed flat file number
+// when it is open and removes it from the least recently used tracking.
+//
+// This function MUST be called with the overall files mutex (s.obfMutex) locked
+// for WRITES.
+func (s *blockStore) closeFile(fileNum uint32) {
+	obf, ok := s.openBlockFiles[fileNum]
+	if !ok {
+		return
+	}
+
+	s.lruMutex.Lock()
+	s.openBlocksLRU.Remove(s.fileNumToLRUElem[fileNum])
+	delete(s.fileNumToLRUElem, fileNum)
+	s.lruMutex.Unlock()
+
+	// Close the file under the write lock for the file in case any readers
+	// are currently reading from it so it's not closed out from under them.
+	obf.Lock()
+	_ = obf.file.Close()
+	obf.Unlock()
+	delete(s.openBlockFiles, fileNum)
+}
+
+// moveFileToCold moves the flat file for the passed block file number from the
+// base path to the cold path.  It has no effect when the file is not in the
+// base path.
+//
+// The file is renamed when both paths are on the same volume.  Otherwise, it is
+// copied to a temporary file in the cold path which is renamed once the copy is
+// complete and synced so that a partial copy is never found in the event of an
+// unexpected shutdown.  The original file is only removed after that, so the
+// file is always available from at least one of the paths.
+func (s *blockStore) moveFileToCold(fileNum uint32) error {
+	srcPath := s.findFile(s.basePath, fileNum)
+	if srcPath == "" {
+		return nil
+	}
+	dstPath := shardedBlockFilePath(s.coldPath, fileNum, s.filesPerDir)
+	dstDir := filepath.Dir(dstPath)
+	if !fileExists(dstDir) {
+		if err := os.MkdirAll(dstDir, 0700); err != nil {
+			str := fmt.Sprintf("failed to create directory %q: %v", dstDir,
+				err)
+			return makeDbErr(database.ErrDriverSpecific, str)
+		}
+	}
+
+	// Attempt to rename the file first.  The open file handle for it, if
+	// any, is closed first under the overall files lock since some
+	// operating systems do not allow open files to be renamed and to
+	// prevent it from being reopened at the old path in the mean time.
+	s.obfMutex.Lock()
+	s.closeFile(fileNum)
+	err := os.Rename(srcPath, dstPath)
+	s.obfMutex.Unlock()
+	if err == nil {
+		log.Debugf("Moved block file %d to %q", fileNum, dstPath)
+		return nil
+	}
+
+	// Fall back to copying the file since the paths are likely on separate
+	// volumes.
+	tmpPath := dstPath + ".tmp"
+	if err := copyFile(srcPath, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		str := fmt.Sprintf("failed to copy file %q to %q: %v", srcPath,
+			tmpPath, err)
+		return makeDbErr(database.ErrDriverSpecific, str)
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		_ = os.Remove(tmpPath)
+		str := fmt.Sprintf("failed to rename file %q to %q: %v", tmpPath,
+			dstPath, err)
+		return makeDbErr(database.ErrDriverSpecific, str)
+	}
+	s.obfMutex.Lock()
+	s.closeFile(fileNum)
+	err = os.Remove(srcPath)
+	s.obfMutex.Unlock()
+	if err != nil {
+		str := fmt.Sprintf("failed to delete file %q: %v", srcPath, err)
+		return makeDbErr(database.ErrDriverSpecific, str)
+	}
+
+	log.Debugf("Moved block file %d to %q", fileNum, dstPath)
+	return nil
+}
+
+// moveColdFiles moves the flat files for all block files before the configured
+// number of most recent committed files, which includes the current write file,
+// from the base path to the cold path.  It returns early without error when the
+// mover is stopped.
+//
+// This function MUST only be called from the mover goroutine.
+func (s *blockStore) moveColdFiles() error {
+	m := s.mover
+	numFiles := atomic.LoadUint32(&m.committedFileNum) + 1
+	if numFiles <= s.hotFiles {
+		return nil
+	}
+	for ; m.nextFileNum < numFiles-s.hotFiles; m.nextFileNum++ {
+		select {
+		case <-m.quit:
+			return nil
+		default:
+		}
+
+		if err := s.moveFileToCold(m.nextFileNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coldMoverHandler moves the older flat block files to the cold path each time
+// the mover is signaled until it is stopped.  Failures are logged and the move
+// is retried the next time the mover is signaled.
+//
+// It must be run as a goroutine.
+func (s *blockStore) coldMoverHandler() {
+	m := s.mover
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.signal:
+			if err := s.moveColdFiles(); err != nil {
+				log.Warnf("Unable to move block files to %q: %v",
+					s.coldPath, err)
+			}
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// notifyCommitted notifies the mover, if any, that the write cursor as of the
+// most recently committed transaction is in the passed block file number so it
+// moves any files that are no longer among the most recent files.
+func (s *blockStore) notifyCommitted(fileNum uint32) {
+	m := s.mover
+	if m == nil {
+		return
+	}
+	atomic.StoreUint32(&m.committedFileNum, fileNum)
+	select {
+	case m.signal <- struct{}{}:
+	default:
+	}
+}
+
+// startColdMover starts moving the older flat block files to the cold path in
+// the background when one is configured.  Any files that were not yet moved
+// when the database was previously closed are moved immediately.
+func (s *blockStore) startColdMover() {
+	if s.coldPath == "" {
+		return
+	}
+
+	s.mover = &coldMover{
+		signal: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+	s.mover.wg.Add(1)
+	go s.coldMoverHandler()
+
+	s.writeCursor.RLock()
+	curFileNum := s.writeCursor.curFileNum
+	s.writeCursor.RUnlock()
+	s.notifyCommitted(curFileNum)
+}
+
+// stopColdMover stops the mover, if any, and blocks until it has finished
+// moving the file it is currently moving.
+func (s *blockStore) stopColdMover() {
+	m := s.mover
+	if m == nil {
+		return
+	}
+	close(m.quit)
+	m.wg.Wait()
+}
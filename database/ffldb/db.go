@@ -18,6 +18,7 @@ import (
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	"github.com/decred/dcrd/database/v3/internal/treap"
 	"github.com/decred/dcrd/wire"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -83,6 +84,10 @@ const (
 	// error code.
 	errDbNotOpenStr = "database is not open"
 
+	// errDbReadOnlyStr is the text to use for the database.ErrDbReadOnly
+	// error code.
+	errDbReadOnlyStr = "database is open in read-only mode"
+
 	// errTxClosedStr is the text to use for the database.ErrTxClosed error
 	// code.
 	errTxClosedStr = "database tx is closed"
@@ -556,6 +561,147 @@ func newCursor(b *bucket, bucketID []byte, cursorTyp cursorType) *cursor {
 	return &cursor{bucket: b, dbIter: dbIter, pendingIter: pendingIter}
 }
 
+// snapshotIter is an internal type used to represent an iterator over a range
+// of keys in a bucket as they existed when it was created.  It implements the
+// database.Iterator interface.
+type snapshotIter struct {
+	bucket   *bucket
+	iter     *dbCacheIterator
+	reverse  bool
+	started  bool
+	released bool
+}
+
+// Enforce snapshotIter implements the database.Iterator interface.
+var _ database.Iterator = (*snapshotIter)(nil)
+
+// Next moves the iterator to the next key/value pair in its direction and
+// returns whether or not the pair exists.
+//
+// This function is part of the database.Iterator interface implementation.
+func (iter *snapshotIter) Next() bool {
+	// Ensure transaction state is valid.
+	if err := iter.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+
+	// Nothing to return if the iterator is released.
+	if iter.released {
+		return false
+	}
+
+	// Position the underlying iterator at the first key in the direction of
+	// iteration on the first call.
+	if !iter.started {
+		iter.started = true
+		if iter.reverse {
+			return iter.iter.Last()
+		}
+		return iter.iter.First()
+	}
+	if iter.reverse {
+		return iter.iter.Prev()
+	}
+	return iter.iter.Next()
+}
+
+// Key returns the current key the iterator is pointing to.
+//
+// This function is part of the database.Iterator interface implementation.
+func (iter *snapshotIter) Key() []byte {
+	// Ensure transaction state is valid.
+	if err := iter.bucket.tx.checkClosed(); err != nil {
+		return nil
+	}
+
+	// Nothing to return if the iterator is released or exhausted.
+	if iter.released || !iter.iter.Valid() {
+		return nil
+	}
+
+	// Slice out the actual key name and make a copy since it is no longer
+	// valid after iterating to the next item.
+	return copySlice(iter.iter.Key()[len(iter.bucket.id):])
+}
+
+// Value returns the current value the iterator is pointing to.
+//
+// This function is part of the database.Iterator interface implementation.
+func (iter *snapshotIter) Value() []byte {
+	// Ensure transaction state is valid.
+	if err := iter.bucket.tx.checkClosed(); err != nil {
+		return nil
+	}
+
+	// Nothing to return if the iterator is released or exhausted.
+	if iter.released || !iter.iter.Valid() {
+		return nil
+	}
+
+	return copySlice(iter.iter.Value())
+}
+
+// Release releases the underlying database iterator.
+//
+// This function is part of the database.Iterator interface implementation.
+func (iter *snapshotIter) Release() {
+	if !iter.released {
+		iter.iter.Release()
+		iter.released = true
+	}
+}
+
+// snapshotIterFinalizer is either invoked when a snapshot iterator is being
+// garbage collected or called manually to ensure the underlying database
+// iterator is released.
+func snapshotIterFinalizer(iter *snapshotIter) {
+	iter.Release()
+}
+
+// pendingSnapshot returns a cache snapshot of the keys the transaction has
+// stored or removed within the provided range so they are no longer affected
+// by further modifications made by the transaction.  The returned snapshot is
+// only suitable for creating iterators since it does not reference the
+// underlying database.
+func (tx *transaction) pendingSnapshot(slice *util.Range) *dbCacheSnapshot {
+	snap := &dbCacheSnapshot{
+		pendingKeys:   treap.NewImmutable(),
+		pendingRemove: treap.NewImmutable(),
+	}
+	iter := tx.pendingKeys.Iterator(slice.Start, slice.Limit)
+	for ok := iter.First(); ok; ok = iter.Next() {
+		snap.pendingKeys = snap.pendingKeys.Put(iter.Key(), iter.Value())
+	}
+	iter = tx.pendingRemove.Iterator(slice.Start, slice.Limit)
+	for ok := iter.First(); ok; ok = iter.Next() {
+		snap.pendingRemove = snap.pendingRemove.Put(iter.Key(), nil)
+	}
+	return snap
+}
+
+// newSnapshotIter returns a new iterator over the provided range of bucketized
+// keys in the passed bucket.
+func newSnapshotIter(b *bucket, slice *util.Range, reverse bool) *snapshotIter {
+	// The transaction snapshot is immutable, so iterating it directly is
+	// enough when the transaction has no pending keys of its own.
+	// Otherwise, the pending keys within the range are captured and layered
+	// on top of it the same way the database cache layers its pending keys
+	// on top of the underlying database.
+	iter := b.tx.snapshot.NewIterator(slice)
+	if b.tx.pendingKeys.Len() != 0 || b.tx.pendingRemove.Len() != 0 {
+		pending := b.tx.pendingSnapshot(slice)
+		iter = &dbCacheIterator{
+			dbIter:        iter,
+			cacheIter:     newLdbCacheIter(pending, slice),
+			cacheSnapshot: pending,
+		}
+	}
+
+	snapIter := &snapshotIter{bucket: b, iter: iter, reverse: reverse}
+	runtime.SetFinalizer(snapIter, snapshotIterFinalizer)
+	return snapIter
+}
+
 // bucket is an internal type used to represent a collection of key/value pairs
 // and implements the database.Bucket interface.
 type bucket struct {
@@ -785,6 +931,55 @@ func (b *bucket) Cursor() database.Cursor {
 	return c
 }
 
+// RangeIterator returns a new iterator over the key/value pairs in the bucket
+// with keys that are greater than or equal to the start key and less than the
+// limit key.  A nil start or limit key leaves the range unbounded on that side.
+// The pairs are visited in descending order when reverse is true.
+//
+// The iterator operates over a snapshot of the bucket taken when it is created,
+// so it does not observe any modifications made while it is in use.
+//
+// Returns the following errors as required by the interface contract:
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) RangeIterator(start, limit []byte, reverse bool) (database.Iterator, error) {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Convert the range to the bucketized keys of the bucket.  Nested
+	// buckets are never included since they are stored under the bucket
+	// index prefix.
+	slice := util.BytesPrefix(b.id[:])
+	if start != nil {
+		slice.Start = bucketizedKey(b.id, start)
+	}
+	if limit != nil {
+		slice.Limit = bucketizedKey(b.id, limit)
+	}
+	return newSnapshotIter(b, slice, reverse), nil
+}
+
+// PrefixIterator returns a new iterator over the key/value pairs in the bucket
+// with keys that start with the provided prefix.  It is otherwise the same as
+// RangeIterator.
+//
+// Returns the following errors as required by the interface contract:
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) PrefixIterator(prefix []byte, reverse bool) (database.Iterator, error) {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	slice := util.BytesPrefix(bucketizedKey(b.id, prefix))
+	return newSnapshotIter(b, slice, reverse), nil
+}
+
 // ForEach invokes the passed function with every key/value pair in the bucket.
 // This does not include nested buckets or the key/value pairs within those
 // nested buckets.
@@ -1730,7 +1925,17 @@ func (tx *transaction) writePendingAndCommit() error {
 
 	// Atomically update the database cache.  The cache automatically
 	// handles flushing to the underlying persistent storage database.
-	return tx.db.cache.commitTx(tx)
+	if err := tx.db.cache.commitTx(tx); err != nil {
+		return err
+	}
+
+	// Notify the block store when the transaction moved the write cursor to
+	// a new block file so any older files that are no longer among the
+	// most recent ones are moved to the cold path as needed.
+	if wc.curFileNum != oldBlkFileNum {
+		tx.db.store.notifyCommitted(wc.curFileNum)
+	}
+	return nil
 }
 
 // Commit commits all changes that have been made to the root metadata bucket
@@ -1794,8 +1999,10 @@ type db struct {
 	writeLock sync.Mutex   // Limit to one write transaction at a time.
 	closeLock sync.RWMutex // Make database close block while txns active.
 	closed    bool         // Is the database closed?
+	readOnly  bool         // Was the database opened in read-only mode?
 	store     *blockStore  // Handles read/writing blocks to flat files.
 	cache     *dbCache     // Cache layer which wraps underlying leveldb DB.
+	ldbOpts   *opt.Options // Options the underlying leveldb DB was opened with.
 }
 
 // Enforce db implements the database.DB interface.
@@ -1816,6 +2023,12 @@ func (db *db) Type() string {
 // which is used by the managed transaction code while the database method
 // returns the interface.
 func (db *db) begin(writable bool) (*transaction, error) {
+	// Read-write transactions are not allowed when the database was opened in
+	// read-only mode.
+	if writable && db.readOnly {
+		return nil, makeDbErr(database.ErrDbReadOnly, errDbReadOnlyStr)
+	}
+
 	// Whenever a new writable transaction is started, grab the write lock
 	// to ensure only a single write transaction can be active at the same
 	// time.  This lock will not be released until the transaction is
@@ -1977,6 +2190,10 @@ func (db *db) Close() error {
 	}
 	db.closed = true
 
+	// Stop moving the older block files to the cold path, if needed, since
+	// the open files are closed below.
+	db.store.stopColdMover()
+
 	// NOTE: Since the above lock waits for all transactions to finish and
 	// prevents any new ones from being started, it is safe to flush the
 	// cache and clear all state without the individual locks.
@@ -2065,7 +2282,10 @@ func initDB(ldb *leveldb.DB) error {
 
 // openDB opens the database at the provided path.  database.ErrDbDoesNotExist
 // is returned if the database doesn't exist and the create flag is not set.
-func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB, error) {
+// The provided tuning options, which may be nil, are applied to the metadata
+// database, both the metadata and block files are encrypted when they include
+// an encryption key, and they also determine where the block files are stored.
+func openDB(dbPath string, network wire.CurrencyNet, create, readOnly bool, dbOpts *database.Options) (database.DB, error) {
 	// Error if the database doesn't exist and the create flag is not set.
 	metadataDbPath := filepath.Join(dbPath, metadataDbName)
 	dbExists := fileExists(metadataDbPath)
@@ -2089,14 +2309,29 @@ func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB,
 	}
 
 	// Open the metadata database (will create it if needed).
+	//
+	// Notice that leveldb only takes a shared lock on the database when it is
+	// opened in read-only mode, so it may be opened in read-only mode by
+	// multiple processes at the same time, but not while it is open in
+	// read-write mode by another process such as a running node.
 	opts := opt.Options{
 		ErrorIfExist: create,
+		ReadOnly:     readOnly,
 		Strict:       opt.DefaultStrict,
 		Compression:  opt.NoCompression,
 		Filter:       filter.NewBloomFilter(10),
 	}
-	ldb, err := leveldb.OpenFile(metadataDbPath, &opts)
+	applyOptions(&opts, dbOpts)
+	var encryptionKey []byte
+	if dbOpts != nil {
+		encryptionKey = dbOpts.EncryptionKey
+	}
+	ldb, err := dbcrypt.OpenLevelDB(metadataDbPath, encryptionKey, &opts)
 	if err != nil {
+		var dbErr database.Error
+		if errors.As(err, &dbErr) {
+			return nil, dbErr
+		}
 		return nil, convertErr(err.Error(), err)
 	}
 
@@ -2105,11 +2340,26 @@ func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB,
 	// according to the data that is actually on disk.  Also create the
 	// database cache which wraps the underlying leveldb database to provide
 	// write caching.
-	store := newBlockStore(dbPath, network)
+	store, err := newBlockStore(dbPath, network, dbOpts)
+	if err != nil {
+		_ = ldb.Close()
+		return nil, err
+	}
 	cache := newDbCache(ldb, store, defaultCacheSize, defaultFlushSecs)
-	pdb := &db{store: store, cache: cache}
+	pdb := &db{store: store, cache: cache, readOnly: readOnly, ldbOpts: &opts}
 
 	// Perform any reconciliation needed between the block and metadata as
 	// well as database initialization, if needed.
-	return reconcileDB(pdb, create)
+	rdb, err := reconcileDB(pdb, create)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start moving the older block files to the cold path, if any, now that
+	// the block files match the metadata.  The block files are never moved
+	// in read-only mode.
+	if !readOnly {
+		store.startColdMover()
+	}
+	return rdb, nil
 }
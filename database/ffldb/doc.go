@@ -14,8 +14,9 @@ ensure data integrity.
 # Usage
 
 This package is a driver to the database package and provides the database type
-of "ffldb".  The parameters the Open and Create functions take are the
-database path as a string and the block network:
+of "ffldb".  The parameters the Open, OpenReadOnly, and Create functions take
+are the database path as a string, the block network, and optionally a
+*database.Options that tunes the underlying leveldb database:
 
 	db, err := database.Open("ffldb", "path/to/database", wire.MainNet)
 	if err != nil {
@@ -26,5 +27,52 @@ database path as a string and the block network:
 	if err != nil {
 		// Handle error
 	}
+
+# Read-Only Mode
+
+A database opened with OpenReadOnly rejects all read-write transactions and
+never modifies any files, including block data after the last position the
+metadata refers to that is ordinarily removed when the database is opened after
+an unclean shutdown.  The underlying leveldb database only takes a shared lock
+in read-only mode, so the same database may be opened in read-only mode by
+multiple processes at once, but not while another process, such as a running
+node, has it open in read-write mode.  Make a copy of the database with Backup
+in order to inspect the data of a running node.
+
+	db, err := database.OpenReadOnly("ffldb", "path/to/database", wire.MainNet)
+	if err != nil {
+		// Handle error
+	}
+
+# Encryption
+
+Both the metadata and the flat block files are encrypted at rest with the
+dbcrypt package when the provided options include an encryption key.  The key
+must be provided when the database is created and every time it is opened after
+that, including for backups, which are encrypted with the same key:
+
+	opts := &database.Options{EncryptionKey: key}
+	db, err := database.Create("ffldb", "path/to/database", wire.MainNet, opts)
+	if err != nil {
+		// Handle error
+	}
+
+# Block File Storage
+
+The flat block files may be sharded into subdirectories that each house the
+number of files specified by the BlockFilesPerDir option.  It must not be
+changed once it is set, however, it may be set for an existing database.
+
+Older flat block files may also be moved to a secondary directory, such as one
+on cheaper and slower storage, by specifying it with the ColdBlockDir option.
+The files are moved in the background while the number of most recent files
+specified by the HotBlockFiles option are kept in the database directory.
+Reads are transparent regardless of where a file is stored:
+
+	opts := &database.Options{ColdBlockDir: "path/to/cold", HotBlockFiles: 8}
+	db, err := database.Open("ffldb", "path/to/database", wire.MainNet, opts)
+	if err != nil {
+		// Handle error
+	}
 */
 package ffldb
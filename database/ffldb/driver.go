@@ -19,49 +19,71 @@ const (
 	dbType = "ffldb"
 )
 
-// parseArgs parses the arguments from the database Open/Create methods.
-func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet, error) {
-	if len(args) != 2 {
-		return "", 0, fmt.Errorf("invalid arguments to %s.%s -- "+
-			"expected database path and block network", dbType,
-			funcName)
+// parseArgs parses the arguments from the database Open/Create methods.  The
+// tuning options are optional and nil is returned for them when they are not
+// provided.
+func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet, *database.Options, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return "", 0, nil, fmt.Errorf("invalid arguments to %s.%s -- "+
+			"expected database path, block network, and optional tuning "+
+			"options", dbType, funcName)
 	}
 
 	dbPath, ok := args[0].(string)
 	if !ok {
-		return "", 0, fmt.Errorf("first argument to %s.%s is invalid -- "+
-			"expected database path string", dbType, funcName)
+		return "", 0, nil, fmt.Errorf("first argument to %s.%s is invalid "+
+			"-- expected database path string", dbType, funcName)
 	}
 
 	network, ok := args[1].(wire.CurrencyNet)
 	if !ok {
-		return "", 0, fmt.Errorf("second argument to %s.%s is invalid -- "+
-			"expected block network", dbType, funcName)
+		return "", 0, nil, fmt.Errorf("second argument to %s.%s is invalid "+
+			"-- expected block network", dbType, funcName)
 	}
 
-	return dbPath, network, nil
+	var dbOpts *database.Options
+	if len(args) == 3 {
+		dbOpts, ok = args[2].(*database.Options)
+		if !ok {
+			return "", 0, nil, fmt.Errorf("third argument to %s.%s is "+
+				"invalid -- expected tuning options", dbType, funcName)
+		}
+	}
+
+	return dbPath, network, dbOpts, nil
 }
 
 // openDBDriver is the callback provided during driver registration that opens
 // an existing database for use.
 func openDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Open", args...)
+	dbPath, network, dbOpts, err := parseArgs("Open", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return openDB(dbPath, network, false, false, dbOpts)
+}
+
+// openReadOnlyDBDriver is the callback provided during driver registration
+// that opens an existing database for use in read-only mode.
+func openReadOnlyDBDriver(args ...interface{}) (database.DB, error) {
+	dbPath, network, dbOpts, err := parseArgs("OpenReadOnly", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return openDB(dbPath, network, false)
+	return openDB(dbPath, network, false, true, dbOpts)
 }
 
 // createDBDriver is the callback provided during driver registration that
 // creates, initializes, and opens a database for use.
 func createDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Create", args...)
+	dbPath, network, dbOpts, err := parseArgs("Create", args...)
 	if err != nil {
 		return nil, err
 	}
 
-	return openDB(dbPath, network, true)
+	return openDB(dbPath, network, true, false, dbOpts)
 }
 
 // useLogger is the callback provided during driver registration that sets the
@@ -73,10 +95,11 @@ func useLogger(logger slog.Logger) {
 func init() {
 	// Register the driver.
 	driver := database.Driver{
-		DbType:    dbType,
-		Create:    createDBDriver,
-		Open:      openDBDriver,
-		UseLogger: useLogger,
+		DbType:       dbType,
+		Create:       createDBDriver,
+		Open:         openDBDriver,
+		OpenReadOnly: openReadOnlyDBDriver,
+		UseLogger:    useLogger,
 	}
 	if err := database.RegisterDriver(driver); err != nil {
 		panic(fmt.Sprintf("Failed to register database driver '%s': %v",
@@ -6,15 +6,20 @@
 package ffldb_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	"github.com/decred/dcrd/database/v3/ffldb"
 	"github.com/decred/dcrd/dcrutil/v4"
 )
@@ -38,8 +43,8 @@ func TestCreateOpenFail(t *testing.T) {
 	// Ensure that attempting to open a database with the wrong number of
 	// parameters returns the expected error.
 	wantErr := fmt.Errorf("invalid arguments to %s.Open -- expected "+
-		"database path and block network", dbType)
-	_, err = database.Open(dbType, 1, 2, 3)
+		"database path, block network, and optional tuning options", dbType)
+	_, err = database.Open(dbType, 1, 2, 3, 4)
 	if err.Error() != wantErr.Error() {
 		t.Errorf("Open: did not receive expected error - got %v, "+
 			"want %v", err, wantErr)
@@ -68,11 +73,22 @@ func TestCreateOpenFail(t *testing.T) {
 		return
 	}
 
+	// Ensure that attempting to open a database with an invalid type for
+	// the third parameter returns the expected error.
+	wantErr = fmt.Errorf("third argument to %s.Open is invalid -- "+
+		"expected tuning options", dbType)
+	_, err = database.Open(dbType, "noexist", blockDataNet, "invalid")
+	if err.Error() != wantErr.Error() {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
 	// Ensure that attempting to create a database with the wrong number of
 	// parameters returns the expected error.
 	wantErr = fmt.Errorf("invalid arguments to %s.Create -- expected "+
-		"database path and block network", dbType)
-	_, err = database.Create(dbType, 1, 2, 3)
+		"database path, block network, and optional tuning options", dbType)
+	_, err = database.Create(dbType, 1, 2, 3, 4)
 	if err.Error() != wantErr.Error() {
 		t.Errorf("Create: did not receive expected error - got %v, "+
 			"want %v", err, wantErr)
@@ -288,3 +304,691 @@ func TestInterface(t *testing.T) {
 		testInterface(t, db)
 	})
 }
+
+// TestInterfaceEncrypted performs all interfaces tests for this database driver
+// against a database that is encrypted at rest.
+func TestInterfaceEncrypted(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "db")
+	dbOpts := &database.Options{
+		EncryptionKey: bytes.Repeat([]byte{0x01}, dbcrypt.KeySize),
+	}
+	db, err := database.Create(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	ffldb.TstRunWithMaxBlockFileSize(db, 2048, func() {
+		testInterface(t, db)
+	})
+}
+
+// TestInterfaceColdStorage performs all interfaces tests for this database
+// driver against a database that shards its block files into subdirectories
+// and moves older block files to a cold directory.
+func TestInterfaceColdStorage(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "db")
+	dbOpts := &database.Options{
+		BlockFilesPerDir: 2,
+		ColdBlockDir:     filepath.Join(tempDir, "cold"),
+		HotBlockFiles:    1,
+	}
+	db, err := database.Create(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	ffldb.TstRunWithMaxBlockFileSize(db, 2048, func() {
+		testInterface(t, db)
+	})
+}
+
+// TestColdStorage ensures older block files are moved to the cold directory
+// while the most recent ones are kept in the primary directory, that new files
+// are sharded into subdirectories, and that all blocks remain available when
+// the options are enabled for an existing database.
+func TestColdStorage(t *testing.T) {
+	t.Parallel()
+
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("Unable to load blocks from test data: %v", err)
+	}
+
+	// storeBlocks stores the provided blocks in separate transactions with a
+	// small maximum file size to force multiple block files.
+	storeBlocks := func(db database.DB, blocks []*dcrutil.Block) {
+		t.Helper()
+		var err error
+		ffldb.TstRunWithMaxBlockFileSize(db, 2048, func() {
+			for _, block := range blocks {
+				err = db.Update(func(tx database.Tx) error {
+					return tx.StoreBlock(block)
+				})
+				if err != nil {
+					return
+				}
+			}
+		})
+		if err != nil {
+			t.Fatalf("Update: unexpected error: %v", err)
+		}
+	}
+
+	// Store some blocks in a database that does not use the options.
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "db")
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	const numBlocks = 40
+	storeBlocks(db, blocks[:numBlocks/2])
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	// Reopen the database with the options and store the remaining blocks.
+	coldPath := filepath.Join(tempDir, "cold")
+	dbOpts := &database.Options{
+		BlockFilesPerDir: 2,
+		ColdBlockDir:     coldPath,
+		HotBlockFiles:    2,
+	}
+	db, err = database.Open(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	storeBlocks(db, blocks[numBlocks/2:numBlocks])
+
+	// existingPath returns the path of the provided block file number in the
+	// provided directory in either layout or an empty string when it does
+	// not exist.
+	existingPath := func(dir string, fileNum int) string {
+		fileName := fmt.Sprintf("%09d.fdb", fileNum)
+		for _, path := range []string{
+			filepath.Join(dir, fmt.Sprintf("%06d", fileNum/2), fileName),
+			filepath.Join(dir, fileName),
+		} {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+		return ""
+	}
+
+	// Ensure the older block files are moved to the cold directory in the
+	// background while the most recent ones stay in the primary directory.
+	lastFileNum := -1
+	for existingPath(dbPath, lastFileNum+1) != "" ||
+		existingPath(coldPath, lastFileNum+1) != "" {
+
+		lastFileNum++
+	}
+	if lastFileNum < 4 {
+		t.Fatalf("unexpected number of block files %d", lastFileNum+1)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for fileNum := 0; fileNum <= lastFileNum; fileNum++ {
+		wantHot := fileNum > lastFileNum-2
+		for {
+			hotPath := existingPath(dbPath, fileNum)
+			coldFilePath := existingPath(coldPath, fileNum)
+			if wantHot && hotPath != "" && coldFilePath == "" {
+				break
+			}
+			wantColdPath := filepath.Join(coldPath,
+				fmt.Sprintf("%06d", fileNum/2),
+				fmt.Sprintf("%09d.fdb", fileNum))
+			if !wantHot && hotPath == "" && coldFilePath == wantColdPath {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("block file %d is in an unexpected location "+
+					"(hot %q, cold %q, want hot %v)", fileNum, hotPath,
+					coldFilePath, wantHot)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	// Ensure all blocks are available after reopening the database.
+	db, err = database.Open(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer db.Close()
+	err = db.View(func(tx database.Tx) error {
+		for _, block := range blocks[:numBlocks] {
+			gotBytes, err := tx.FetchBlock(block.Hash())
+			if err != nil {
+				return err
+			}
+			wantBytes, _ := block.Bytes()
+			if !bytes.Equal(gotBytes, wantBytes) {
+				return fmt.Errorf("block %v mismatch", block.Hash())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+}
+
+// TestEncryption ensures the blocks and metadata of an encrypted database are
+// not stored in plaintext, persist across reopening the database and backing
+// it up, and are only accessible with the key the database was created with.
+func TestEncryption(t *testing.T) {
+	t.Parallel()
+
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("Unable to load blocks from test data: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "db")
+	dbOpts := &database.Options{
+		EncryptionKey: bytes.Repeat([]byte{0x01}, dbcrypt.KeySize),
+	}
+	db, err := database.Create(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+
+	// Store some metadata and blocks in the database.
+	key, value := []byte("encryptionkey"), []byte("encryptionvalue")
+	const numBlocks = 10
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Metadata().Put(key, value); err != nil {
+			return err
+		}
+		for _, block := range blocks[:numBlocks] {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	backupPath := filepath.Join(tempDir, "backup")
+	if err := db.Backup(context.Background(), backupPath, nil); err != nil {
+		t.Fatalf("Backup: unexpected error: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	// Ensure the block data is not stored in plaintext.
+	raw, err := os.ReadFile(filepath.Join(dbPath, "000000000.fdb"))
+	if err != nil {
+		t.Fatalf("Unable to read block file: %v", err)
+	}
+	headerBytes, _ := blocks[1].MsgBlock().Header.Bytes()
+	if bytes.Contains(raw, headerBytes) {
+		t.Fatal("block file contains a plaintext block header")
+	}
+
+	// Ensure the database and its backup are not able to be opened without
+	// the key or with a different key.
+	wrongOpts := &database.Options{
+		EncryptionKey: bytes.Repeat([]byte{0x02}, dbcrypt.KeySize),
+	}
+	for _, path := range []string{dbPath, backupPath} {
+		_, err = database.Open(dbType, path, blockDataNet)
+		if !errors.Is(err, database.ErrEncryptionKey) {
+			t.Fatalf("Open: unexpected error without key -- got %v, want %v",
+				err, database.ErrEncryptionKey)
+		}
+		_, err = database.Open(dbType, path, blockDataNet, wrongOpts)
+		if !errors.Is(err, database.ErrEncryptionKey) {
+			t.Fatalf("Open: unexpected error with wrong key -- got %v, "+
+				"want %v", err, database.ErrEncryptionKey)
+		}
+	}
+
+	// Ensure the data is intact when the database and its backup are opened
+	// with the key.
+	for _, path := range []string{dbPath, backupPath} {
+		db, err := database.Open(dbType, path, blockDataNet, dbOpts)
+		if err != nil {
+			t.Fatalf("Open: unexpected error: %v", err)
+		}
+		err = db.View(func(tx database.Tx) error {
+			if got := tx.Metadata().Get(key); !bytes.Equal(got, value) {
+				return fmt.Errorf("unexpected value -- got %x, want %x", got,
+					value)
+			}
+			for _, block := range blocks[:numBlocks] {
+				gotBytes, err := tx.FetchBlock(block.Hash())
+				if err != nil {
+					return err
+				}
+				wantBytes, _ := block.Bytes()
+				if !bytes.Equal(gotBytes, wantBytes) {
+					return fmt.Errorf("block %v mismatch", block.Hash())
+				}
+			}
+			return nil
+		})
+		db.Close()
+		if err != nil {
+			t.Fatalf("View: unexpected error: %v", err)
+		}
+	}
+}
+
+// TestSnapshotIterators ensures iterators created by a read-only transaction do
+// not block writers, do not observe the updates they commit, and are exhausted
+// once the transaction is closed.
+func TestSnapshotIterators(t *testing.T) {
+	t.Parallel()
+
+	dbPath := filepath.Join(t.TempDir(), "ffldb-snapshotiters")
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	// putKeys stores the provided keys with values that are the same as the
+	// keys and removes the provided keys to delete.
+	putKeys := func(keys, deleteKeys []string) {
+		t.Helper()
+		err := db.Update(func(tx database.Tx) error {
+			for _, key := range keys {
+				err := tx.Metadata().Put([]byte(key), []byte(key))
+				if err != nil {
+					return err
+				}
+			}
+			for _, key := range deleteKeys {
+				if err := tx.Metadata().Delete([]byte(key)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Update: unexpected error: %v", err)
+		}
+	}
+	putKeys([]string{"idx1", "idx2", "idx3"}, nil)
+
+	tx, err := db.Begin(false)
+	if err != nil {
+		t.Fatalf("Begin: unexpected error: %v", err)
+	}
+	iter, err := tx.Metadata().PrefixIterator([]byte("idx"), true)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("PrefixIterator: unexpected error: %v", err)
+	}
+
+	// Commit updates while the iterator is in use and ensure they are not
+	// observed.
+	var got []string
+	for iter.Next() {
+		got = append(got, string(iter.Key()))
+		putKeys([]string{"idx0", "idx4"}, []string{"idx1"})
+	}
+	iter.Release()
+	want := []string{"idx3", "idx2", "idx1"}
+	if !reflect.DeepEqual(got, want) {
+		tx.Rollback()
+		t.Fatalf("unexpected keys -- got %q, want %q", got, want)
+	}
+
+	// Ensure an iterator is exhausted once its transaction is closed.
+	iter, err = tx.Metadata().RangeIterator(nil, nil, false)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("RangeIterator: unexpected error: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback: unexpected error: %v", err)
+	}
+	if iter.Next() || iter.Key() != nil || iter.Value() != nil {
+		t.Fatal("iterator is not exhausted after closing its transaction")
+	}
+	iter.Release()
+
+	// Ensure a new iterator observes the updates.
+	err = db.View(func(tx database.Tx) error {
+		iter, err := tx.Metadata().PrefixIterator([]byte("idx"), false)
+		if err != nil {
+			return err
+		}
+		defer iter.Release()
+
+		got = got[:0]
+		for iter.Next() {
+			got = append(got, string(iter.Key()))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+	want = []string{"idx0", "idx2", "idx3", "idx4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected keys -- got %q, want %q", got, want)
+	}
+}
+
+// TestBackup ensures a backup of the database contains the metadata and blocks
+// committed before the backup started, can be opened, and is not modified by
+// updates made to the database after the backup.
+func TestBackup(t *testing.T) {
+	t.Parallel()
+
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("Unable to load blocks from test data: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "db")
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	// Store some metadata and blocks in the database.
+	key, value := []byte("backupkey"), []byte("backupvalue")
+	const numBackupBlocks = 10
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Metadata().Put(key, value); err != nil {
+			return err
+		}
+		for _, block := range blocks[:numBackupBlocks] {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	// Ensure a backup to a directory that already exists fails.
+	ctx := context.Background()
+	err = db.Backup(ctx, tempDir, nil)
+	if !errors.Is(err, database.ErrDbExists) {
+		t.Fatalf("Backup: unexpected error -- got %v, want %v", err,
+			database.ErrDbExists)
+	}
+
+	// Ensure a canceled backup fails and does not leave a partial backup.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	canceledPath := filepath.Join(tempDir, "canceled")
+	if err := db.Backup(canceledCtx, canceledPath, nil); err == nil {
+		t.Fatal("Backup: did not fail with canceled context")
+	}
+	if _, err := os.Stat(canceledPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Backup: canceled backup directory exists (err: %v)", err)
+	}
+
+	// Backup the database and ensure the progress is reported.
+	backupPath := filepath.Join(tempDir, "backup")
+	var progress database.BackupProgress
+	err = db.Backup(ctx, backupPath, func(p database.BackupProgress) {
+		progress = p
+	})
+	if err != nil {
+		t.Fatalf("Backup: unexpected error: %v", err)
+	}
+	if progress.BytesCopied == 0 || progress.BytesCopied != progress.TotalBytes {
+		t.Fatalf("Backup: unexpected final progress %+v", progress)
+	}
+
+	// Store another block in the original database and ensure it is not in
+	// the backup.
+	err = db.Update(func(tx database.Tx) error {
+		return tx.StoreBlock(blocks[numBackupBlocks])
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	backupDB, err := database.Open(dbType, backupPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to open backup database: %v", err)
+	}
+	defer backupDB.Close()
+	err = backupDB.View(func(tx database.Tx) error {
+		if got := tx.Metadata().Get(key); !bytes.Equal(got, value) {
+			return fmt.Errorf("unexpected value -- got %x, want %x", got,
+				value)
+		}
+		for _, block := range blocks[:numBackupBlocks] {
+			gotBytes, err := tx.FetchBlock(block.Hash())
+			if err != nil {
+				return err
+			}
+			wantBytes, _ := block.Bytes()
+			if !bytes.Equal(gotBytes, wantBytes) {
+				return fmt.Errorf("block %v mismatch", block.Hash())
+			}
+		}
+		hasBlock, err := tx.HasBlock(blocks[numBackupBlocks].Hash())
+		if err != nil {
+			return err
+		}
+		if hasBlock {
+			return fmt.Errorf("block %v stored after the backup exists",
+				blocks[numBackupBlocks].Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+}
+
+// TestOpenReadOnly ensures a database opened in read-only mode provides access
+// to the existing data, rejects any modifications, and does not modify the
+// underlying files, even when they contain unreferenced block data.
+func TestOpenReadOnly(t *testing.T) {
+	t.Parallel()
+
+	blocks, err := loadBlocks(t, blockDataFile, blockDataNet)
+	if err != nil {
+		t.Fatalf("Unable to load blocks from test data: %v", err)
+	}
+
+	// Ensure attempting to open a database that doesn't exist in read-only
+	// mode returns the expected error and does not create it.
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "db")
+	_, err = database.OpenReadOnly(dbType, dbPath, blockDataNet)
+	if !checkDbError(t, "OpenReadOnly", err, database.ErrDbDoesNotExist) {
+		return
+	}
+	if _, err := os.Stat(dbPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("OpenReadOnly: database directory created (err: %v)", err)
+	}
+
+	// Create a database with some metadata and blocks.
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	key, value := []byte("readonlykey"), []byte("readonlyvalue")
+	const numBlocks = 5
+	err = db.Update(func(tx database.Tx) error {
+		if err := tx.Metadata().Put(key, value); err != nil {
+			return err
+		}
+		for _, block := range blocks[:numBlocks] {
+			if err := tx.StoreBlock(block); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+
+	// Append data that is not referenced by the metadata to the block file
+	// to simulate an unclean shutdown while a block was being written.
+	blockFile := filepath.Join(dbPath, "000000000.fdb")
+	f, err := os.OpenFile(blockFile, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("Unable to open block file: %v", err)
+	}
+	if _, err := f.Write([]byte{0x01, 0x02, 0x03, 0x04}); err != nil {
+		f.Close()
+		t.Fatalf("Unable to write block file: %v", err)
+	}
+	f.Close()
+	fi, err := os.Stat(blockFile)
+	if err != nil {
+		t.Fatalf("Unable to stat block file: %v", err)
+	}
+	wantSize := fi.Size()
+
+	// Open the database in read-only mode multiple times at once and ensure
+	// the data is accessible from all of them.
+	var dbs []database.DB
+	for i := 0; i < 2; i++ {
+		db, err := database.OpenReadOnly(dbType, dbPath, blockDataNet)
+		if err != nil {
+			t.Fatalf("OpenReadOnly #%d: unexpected error: %v", i, err)
+		}
+		dbs = append(dbs, db)
+	}
+	for i, db := range dbs {
+		err := db.View(func(tx database.Tx) error {
+			if got := tx.Metadata().Get(key); !bytes.Equal(got, value) {
+				return fmt.Errorf("unexpected value -- got %x, want %x",
+					got, value)
+			}
+			for _, block := range blocks[:numBlocks] {
+				gotBytes, err := tx.FetchBlock(block.Hash())
+				if err != nil {
+					return err
+				}
+				wantBytes, _ := block.Bytes()
+				if !bytes.Equal(gotBytes, wantBytes) {
+					return fmt.Errorf("block %v mismatch", block.Hash())
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("View #%d: unexpected error: %v", i, err)
+		}
+
+		// Ensure any attempts to modify the database are rejected.
+		err = db.Update(func(tx database.Tx) error {
+			return tx.StoreBlock(blocks[numBlocks])
+		})
+		if !checkDbError(t, "Update", err, database.ErrDbReadOnly) {
+			return
+		}
+		_, err = db.Begin(true)
+		if !checkDbError(t, "Begin", err, database.ErrDbReadOnly) {
+			return
+		}
+		if err := db.Flush(); err != nil {
+			t.Fatalf("Flush #%d: unexpected error: %v", i, err)
+		}
+		if err := db.Close(); err != nil {
+			t.Fatalf("Close #%d: unexpected error: %v", i, err)
+		}
+	}
+
+	// Ensure the unreferenced block data was not removed.
+	fi, err = os.Stat(blockFile)
+	if err != nil {
+		t.Fatalf("Unable to stat block file: %v", err)
+	}
+	if fi.Size() != wantSize {
+		t.Fatalf("block file modified -- got size %d, want %d", fi.Size(),
+			wantSize)
+	}
+}
+
+// TestStats ensures the tuning options are applied to the metadata database
+// and its statistics reflect the data written to it.
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	// Create a database with a write buffer that is small enough for the
+	// metadata written below to be written to tables.  None of it would be
+	// written to tables with the default write buffer size.
+	dbPath := filepath.Join(t.TempDir(), "db")
+	dbOpts := &database.Options{
+		WriteBufferSize:     32 * 1024,
+		CompactionL0Trigger: 2,
+	}
+	db, err := database.Create(dbType, dbPath, blockDataNet, dbOpts)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	value := make([]byte, 100)
+	err = db.Update(func(tx database.Tx) error {
+		for i := 0; i < 4000; i++ {
+			key := []byte(fmt.Sprintf("statskey%d", i))
+			if err := tx.Metadata().Put(key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatalf("Flush: unexpected error: %v", err)
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("Stats: unexpected error: %v", err)
+	}
+	var tables int
+	var written int64
+	for _, level := range stats.Levels {
+		tables += level.Tables
+		written += level.Written
+	}
+	if tables == 0 || written == 0 || stats.ReadAmplification == 0 {
+		t.Fatalf("Stats: unexpected stats for written metadata: %+v", stats)
+	}
+	if stats.WriteAmplification < 1 {
+		t.Fatalf("Stats: unexpected write amplification %v",
+			stats.WriteAmplification)
+	}
+
+	// Ensure fetching the stats of a closed database returns the expected
+	// error.
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: unexpected error: %v", err)
+	}
+	_, err = db.Stats()
+	if !checkDbError(t, "Stats", err, database.ErrDbNotOpen) {
+		return
+	}
+}
@@ -352,6 +352,187 @@ func testCursorInterface(tc *testContext, bucket database.Bucket) bool {
 	return true
 }
 
+// testIteratorValues ensures the provided iterator visits exactly the provided
+// key/value pairs in order and releases it.
+func testIteratorValues(tc *testContext, testName string, iter database.Iterator, values []keyPair) bool {
+	defer iter.Release()
+
+	curIdx := 0
+	for iter.Next() {
+		if curIdx >= len(values) {
+			tc.t.Errorf("%s: exceeded the expected range of values - "+
+				"num values %d", testName, len(values))
+			return false
+		}
+		k, v := iter.Key(), iter.Value()
+		if !testCursorKeyPair(tc, k, v, curIdx, values) {
+			tc.t.Errorf("%s: mismatched key/value pair", testName)
+			return false
+		}
+		curIdx++
+	}
+	if curIdx != len(values) {
+		tc.t.Errorf("%s: expected to iterate %d values, but only "+
+			"iterated %d", testName, len(values), curIdx)
+		return false
+	}
+
+	// Ensure the iterator remains exhausted.
+	if iter.Next() || iter.Key() != nil || iter.Value() != nil {
+		tc.t.Errorf("%s: iterator is not exhausted", testName)
+		return false
+	}
+	return true
+}
+
+// reverseKeyPairs returns a copy of the provided key/value pairs in reverse
+// order.
+func reverseKeyPairs(values []keyPair) []keyPair {
+	ret := make([]keyPair, len(values))
+	for i := range values {
+		ret[len(values)-1-i] = values[i]
+	}
+	return ret
+}
+
+// testIteratorInterface ensures the range and prefix iterators are working
+// properly by exercising them on the passed bucket.
+func testIteratorInterface(tc *testContext, bucket database.Bucket) bool {
+	// Ensure iterating the entire bucket in either direction visits the
+	// same key/value pairs as ForEach.
+	var allValues []keyPair
+	err := bucket.ForEach(func(k, v []byte) error {
+		allValues = append(allValues, keyPair{k, v})
+		return nil
+	})
+	if err != nil {
+		tc.t.Errorf("ForEach: unexpected error: %v", err)
+		return false
+	}
+	iter, err := bucket.RangeIterator(nil, nil, false)
+	if err != nil {
+		tc.t.Errorf("RangeIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "full range", iter, allValues) {
+		return false
+	}
+	iter, err = bucket.RangeIterator(nil, nil, true)
+	if err != nil {
+		tc.t.Errorf("RangeIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "reverse full range", iter,
+		reverseKeyPairs(allValues)) {
+
+		return false
+	}
+
+	if !tc.isWritable {
+		return true
+	}
+
+	values := []keyPair{
+		{[]byte("iter1a"), []byte("val1")},
+		{[]byte("iter1b"), []byte("val2")},
+		{[]byte("iter2a"), []byte("val3")},
+		{[]byte("iter2b"), []byte("val4")},
+		{[]byte("iter3"), []byte("val5")},
+	}
+	if !testPutValues(tc, bucket, values) {
+		return false
+	}
+
+	// Ensure range iteration only visits the keys in the range in both
+	// directions.
+	iter, err = bucket.RangeIterator([]byte("iter1b"), []byte("iter3"), false)
+	if err != nil {
+		tc.t.Errorf("RangeIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "range", iter, values[1:4]) {
+		return false
+	}
+	iter, err = bucket.RangeIterator([]byte("iter1b"), []byte("iter3"), true)
+	if err != nil {
+		tc.t.Errorf("RangeIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "reverse range", iter,
+		reverseKeyPairs(values[1:4])) {
+
+		return false
+	}
+
+	// Ensure prefix iteration only visits the keys with the prefix in both
+	// directions.
+	iter, err = bucket.PrefixIterator([]byte("iter2"), false)
+	if err != nil {
+		tc.t.Errorf("PrefixIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "prefix", iter, values[2:4]) {
+		return false
+	}
+	iter, err = bucket.PrefixIterator([]byte("iter2"), true)
+	if err != nil {
+		tc.t.Errorf("PrefixIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "reverse prefix", iter,
+		reverseKeyPairs(values[2:4])) {
+
+		return false
+	}
+
+	// Ensure modifications made after an iterator is created, including
+	// those made while iterating, are not observed by it.
+	iter, err = bucket.PrefixIterator([]byte("iter"), false)
+	if err != nil {
+		tc.t.Errorf("PrefixIterator: unexpected error: %v", err)
+		return false
+	}
+	if !iter.Next() {
+		tc.t.Error("PrefixIterator: no values")
+		iter.Release()
+		return false
+	}
+	if !testDeleteValues(tc, bucket, values[1:3]) {
+		iter.Release()
+		return false
+	}
+	modifiedValues := []keyPair{
+		{[]byte("iter1a"), []byte("newval1")},
+		{[]byte("iter1c"), []byte("val6")},
+		{[]byte("iter2b"), []byte("val4")},
+		{[]byte("iter3"), []byte("val5")},
+	}
+	if !testPutValues(tc, bucket, modifiedValues[:2]) {
+		iter.Release()
+		return false
+	}
+	if !testCursorKeyPair(tc, iter.Key(), iter.Value(), 0, values) {
+		iter.Release()
+		return false
+	}
+	if !testIteratorValues(tc, "modified prefix", iter, values[1:]) {
+		return false
+	}
+
+	// Ensure a new iterator observes the modifications.
+	iter, err = bucket.PrefixIterator([]byte("iter"), false)
+	if err != nil {
+		tc.t.Errorf("PrefixIterator: unexpected error: %v", err)
+		return false
+	}
+	if !testIteratorValues(tc, "new prefix", iter, modifiedValues) {
+		return false
+	}
+
+	// Remove the values to avoid leaving them around for future calls.
+	return testDeleteValues(tc, bucket, modifiedValues)
+}
+
 // testNestedBucket reruns the testBucketInterface against a nested bucket along
 // with a counter to only test a couple of level deep.
 func testNestedBucket(tc *testContext, testBucket database.Bucket) bool {
@@ -531,6 +712,11 @@ func testBucketInterface(tc *testContext, bucket database.Bucket) bool {
 			return false
 		}
 
+		// Ensure the iterator interface works as expected.
+		if !testIteratorInterface(tc, testBucket) {
+			return false
+		}
+
 		// Delete the test bucket to avoid leaving it around for future
 		// calls.
 		if err := bucket.DeleteBucket(testBucketName); err != nil {
@@ -586,6 +772,12 @@ func testBucketInterface(tc *testContext, bucket database.Bucket) bool {
 		if !testCursorInterface(tc, bucket) {
 			return false
 		}
+
+		// Ensure the iterator interface works as expected with
+		// read-only buckets.
+		if !testIteratorInterface(tc, bucket) {
+			return false
+		}
 	}
 
 	return true
@@ -1725,6 +1917,20 @@ func testClosedTxInterface(tc *testContext, tx database.Tx) bool {
 		return false
 	}
 
+	// Ensure RangeIterator returns expected error.
+	testName = "RangeIterator on closed tx"
+	_, err = bucket.RangeIterator(nil, nil, false)
+	if !checkDbError(tc.t, testName, err, wantErrKind) {
+		return false
+	}
+
+	// Ensure PrefixIterator returns expected error.
+	testName = "PrefixIterator on closed tx"
+	_, err = bucket.PrefixIterator(keyName, false)
+	if !checkDbError(tc.t, testName, err, wantErrKind) {
+		return false
+	}
+
 	// -------------------
 	// Metadata Cursor API
 	// -------------------
@@ -83,7 +83,22 @@ func reconcileDB(pdb *db, create bool) (database.DB, error) {
 	// the middle of being written.  Since the metadata isn't updated until
 	// after the block data is written, this is effectively just a rollback
 	// to the known good point before the unclean shutdown.
+	//
+	// The block files are not modified when the database is opened in
+	// read-only mode.  Instead, the write cursor is set to the position the
+	// metadata believes to be true since the data after it is not referenced
+	// by the metadata.
 	wc := pdb.store.writeCursor
+	if pdb.readOnly && (wc.curFileNum > curFileNum ||
+		(wc.curFileNum == curFileNum && wc.curOffset > curOffset)) {
+
+		log.Debugf("Metadata claims file %d, offset %d. Block data is "+
+			"at file %d, offset %d.  Ignoring unreferenced block data in "+
+			"read-only mode", curFileNum, curOffset, wc.curFileNum,
+			wc.curOffset)
+		wc.curFileNum = curFileNum
+		wc.curOffset = curOffset
+	}
 	if wc.curFileNum > curFileNum || (wc.curFileNum == curFileNum &&
 		wc.curOffset > curOffset) {
 
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"github.com/decred/dcrd/database/v3"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+const (
+	// writeL0SlowdownOffset and writeL0PauseOffset are the number of tables
+	// in the first level beyond the compaction trigger at which writes are
+	// slowed down and paused, respectively.  They match the spacing of the
+	// leveldb defaults so overriding the trigger does not immediately slow
+	// down writes.
+	writeL0SlowdownOffset = 4
+	writeL0PauseOffset    = 8
+)
+
+// applyOptions sets the leveldb options that correspond to the provided
+// tuning options, which may be nil.  Options that are not set are left at the
+// leveldb defaults.
+func applyOptions(opts *opt.Options, dbOpts *database.Options) {
+	if dbOpts == nil {
+		return
+	}
+	if dbOpts.WriteBufferSize > 0 {
+		opts.WriteBuffer = dbOpts.WriteBufferSize
+	}
+	if dbOpts.MaxOpenFiles > 0 {
+		opts.OpenFilesCacheCapacity = dbOpts.MaxOpenFiles
+	}
+	if dbOpts.CompactionL0Trigger > 0 {
+		opts.CompactionL0Trigger = dbOpts.CompactionL0Trigger
+		opts.WriteL0SlowdownTrigger = dbOpts.CompactionL0Trigger +
+			writeL0SlowdownOffset
+		opts.WriteL0PauseTrigger = dbOpts.CompactionL0Trigger +
+			writeL0PauseOffset
+	}
+	if dbOpts.CompactionTableSize > 0 {
+		opts.CompactionTableSize = dbOpts.CompactionTableSize
+	}
+}
+
+// ldbStats returns the statistics of the provided leveldb database that was
+// opened with the provided options.
+func ldbStats(ldb *leveldb.DB, opts *opt.Options) (*database.Stats, error) {
+	var ldbStats leveldb.DBStats
+	if err := ldb.Stats(&ldbStats); err != nil {
+		return nil, convertErr("failed to fetch database stats", err)
+	}
+
+	stats := &database.Stats{
+		Levels:         make([]database.LevelStats, len(ldbStats.LevelSizes)),
+		WriteDelays:    uint64(ldbStats.WriteDelayCount),
+		WriteDelayTime: ldbStats.WriteDelayDuration,
+		WritePaused:    ldbStats.WritePaused,
+	}
+	stats.Compactions = uint64(ldbStats.MemComp) +
+		uint64(ldbStats.Level0Comp) + uint64(ldbStats.NonLevel0Comp) +
+		uint64(ldbStats.SeekComp)
+	var totalWritten int64
+	for level := range stats.Levels {
+		ls := &stats.Levels[level]
+		ls.Size = ldbStats.LevelSizes[level]
+		if level < len(ldbStats.LevelTablesCounts) {
+			ls.Tables = ldbStats.LevelTablesCounts[level]
+		}
+		if level < len(ldbStats.LevelRead) {
+			ls.Read = ldbStats.LevelRead[level]
+		}
+		if level < len(ldbStats.LevelWrite) {
+			ls.Written = ldbStats.LevelWrite[level]
+		}
+		if level < len(ldbStats.LevelDurations) {
+			ls.CompactionTime = ldbStats.LevelDurations[level]
+		}
+		totalWritten += ls.Written
+
+		// Every table in the first level might contain any key, while the
+		// tables in the other levels do not overlap, so at most one table
+		// from each of them needs to be read.
+		//
+		// The first level is compacted based on its number of tables while
+		// the other levels are compacted based on their total size.
+		if level == 0 {
+			stats.ReadAmplification += ls.Tables
+			if ls.Tables >= opts.GetCompactionL0Trigger() {
+				stats.CompactionBacklog += ls.Size
+			}
+			continue
+		}
+		if ls.Tables > 0 {
+			stats.ReadAmplification++
+		}
+		if excess := ls.Size - opts.GetCompactionTotalSize(level); excess > 0 {
+			stats.CompactionBacklog += excess
+		}
+	}
+	if len(stats.Levels) > 0 && stats.Levels[0].Written > 0 {
+		stats.WriteAmplification = float64(totalWritten) /
+			float64(stats.Levels[0].Written)
+	}
+
+	return stats, nil
+}
+
+// Stats returns statistics about the leveldb database that houses the
+// metadata.  The flat files that house the blocks are only ever appended to,
+// so they are not included.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) Stats() (*database.Stats, error) {
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.closed {
+		return nil, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr)
+	}
+
+	return ldbStats(db.cache.ldb, db.ldbOpts)
+}
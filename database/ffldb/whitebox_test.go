@@ -150,7 +150,7 @@ func TestCornerCases(t *testing.T) {
 	// directory is needed.
 	testName := "openDB: fail due to file at target location"
 	wantErrKind := database.ErrDriverSpecific
-	idb, err := openDB(dbPath, blockDataNet, true)
+	idb, err := openDB(dbPath, blockDataNet, true, false, nil)
 	if !checkDbError(t, testName, err, wantErrKind) {
 		if err == nil {
 			idb.Close()
@@ -162,7 +162,7 @@ func TestCornerCases(t *testing.T) {
 	// Remove the file and create the database to run tests against.  It
 	// should be successful this time.
 	_ = os.RemoveAll(dbPath)
-	idb, err = openDB(dbPath, blockDataNet, true)
+	idb, err = openDB(dbPath, blockDataNet, true, false, nil)
 	if err != nil {
 		t.Errorf("openDB: unexpected error: %v", err)
 		return
@@ -0,0 +1,466 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// Op identifies a type of database operation that is observed by Hooks.
+type Op uint8
+
+// These constants define the types of database operations that are observed by
+// Hooks.
+const (
+	// OpView and OpUpdate are managed read-only and read-write transactions,
+	// respectively, from the time they start until they are closed.
+	OpView Op = iota
+	OpUpdate
+
+	// OpCommit is the commit of a transaction started with Begin.
+	OpCommit
+
+	// OpGet, OpPut, and OpDelete are the retrieval, storage, and removal of
+	// a single key in a bucket.  Removals via a cursor are also OpDelete.
+	OpGet
+	OpPut
+	OpDelete
+
+	// OpForEach is an iteration over all of the keys in a bucket.
+	OpForEach
+
+	// OpStoreBlock is the storage of a single block.
+	OpStoreBlock
+
+	// OpFetchBlock, OpFetchBlockHeader, and OpFetchBlockRegion are the
+	// retrieval of one or more blocks, block headers, and block regions,
+	// respectively.
+	OpFetchBlock
+	OpFetchBlockHeader
+	OpFetchBlockRegion
+
+	// numOps is the number of defined operation types.  It must be the last
+	// constant.
+	numOps
+)
+
+// opStrings is a map of operation types back to their constant names for pretty
+// printing.
+var opStrings = [numOps]string{
+	OpView:             "view",
+	OpUpdate:           "update",
+	OpCommit:           "commit",
+	OpGet:              "get",
+	OpPut:              "put",
+	OpDelete:           "delete",
+	OpForEach:          "foreach",
+	OpStoreBlock:       "storeblock",
+	OpFetchBlock:       "fetchblock",
+	OpFetchBlockHeader: "fetchblockheader",
+	OpFetchBlockRegion: "fetchblockregion",
+}
+
+// String returns the operation type as a human-readable name.
+func (op Op) String() string {
+	if op < numOps {
+		return opStrings[op]
+	}
+	return "unknown"
+}
+
+// Hooks observes the operations performed on a database that is wrapped with
+// WithHooks.  It allows the number, latency, and value sizes of operations to
+// be measured and operations to be associated with tracing spans without any
+// support from the database driver.
+//
+// Implementations must be safe for concurrent access since operations may be
+// performed by multiple transactions at the same time.
+type Hooks interface {
+	// StartOp is invoked when an operation of the provided type starts.
+	//
+	// The bucket is the path of the bucket the operation is performed on
+	// with the names of nested buckets separated by a slash.  It is empty
+	// for operations on the metadata bucket itself as well as operations
+	// that do not involve a bucket such as transactions and blocks.
+	//
+	// The returned function, which may be nil, is invoked when the
+	// operation finishes with the total size in bytes of the values that
+	// were retrieved or stored by it and the error, if any, that it
+	// resulted in.
+	StartOp(op Op, bucket string) func(size int, err error)
+}
+
+// WithHooks returns the provided database wrapped so that the provided hooks
+// observe the operations performed on it through the returned instance.  The
+// database is returned unmodified when the hooks are nil.
+//
+// Closing the returned instance closes the provided database.
+func WithHooks(db DB, hooks Hooks) DB {
+	if hooks == nil {
+		return db
+	}
+	return &hookedDB{DB: db, hooks: hooks}
+}
+
+// finishOp invokes the provided function returned by Hooks.StartOp when it is
+// not nil.
+func finishOp(done func(int, error), size int, err error) {
+	if done != nil {
+		done(size, err)
+	}
+}
+
+// hookedDB wraps a database so its operations are observed by hooks.
+//
+// It implements the DB interface.
+type hookedDB struct {
+	DB
+	hooks Hooks
+}
+
+// Enforce hookedDB implements the DB interface.
+var _ DB = (*hookedDB)(nil)
+
+// wrapTx returns the provided transaction wrapped so its operations are
+// observed by the hooks of the database.
+func (db *hookedDB) wrapTx(tx Tx) *hookedTx {
+	htx := &hookedTx{Tx: tx, hooks: db.hooks}
+	htx.meta = &hookedBucket{bucket: tx.Metadata(), hooks: db.hooks}
+	return htx
+}
+
+// Begin starts a transaction which is either read-only or read-write depending
+// on the specified flag.
+//
+// This function is part of the DB interface implementation.
+func (db *hookedDB) Begin(writable bool) (Tx, error) {
+	tx, err := db.DB.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return db.wrapTx(tx), nil
+}
+
+// View invokes the passed function in the context of a managed read-only
+// transaction.
+//
+// This function is part of the DB interface implementation.
+func (db *hookedDB) View(fn func(Tx) error) error {
+	done := db.hooks.StartOp(OpView, "")
+	err := db.DB.View(func(tx Tx) error {
+		return fn(db.wrapTx(tx))
+	})
+	finishOp(done, 0, err)
+	return err
+}
+
+// Update invokes the passed function in the context of a managed read-write
+// transaction.
+//
+// This function is part of the DB interface implementation.
+func (db *hookedDB) Update(fn func(Tx) error) error {
+	done := db.hooks.StartOp(OpUpdate, "")
+	err := db.DB.Update(func(tx Tx) error {
+		return fn(db.wrapTx(tx))
+	})
+	finishOp(done, 0, err)
+	return err
+}
+
+// hookedTx wraps a transaction so its operations are observed by hooks.
+//
+// It implements the Tx interface.
+type hookedTx struct {
+	Tx
+	hooks Hooks
+	meta  *hookedBucket
+}
+
+// Enforce hookedTx implements the Tx interface.
+var _ Tx = (*hookedTx)(nil)
+
+// Metadata returns the top-most bucket for all metadata storage.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) Metadata() Bucket {
+	return tx.meta
+}
+
+// StoreBlock stores the provided block into the database.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) StoreBlock(block BlockSerializer) error {
+	done := tx.hooks.StartOp(OpStoreBlock, "")
+	err := tx.Tx.StoreBlock(block)
+	if done != nil {
+		var size int
+		if err == nil {
+			blockBytes, _ := block.Bytes()
+			size = len(blockBytes)
+		}
+		done(size, err)
+	}
+	return err
+}
+
+// FetchBlockHeader returns the raw serialized bytes for the block header
+// identified by the given hash.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlockHeader(hash *chainhash.Hash) ([]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlockHeader, "")
+	header, err := tx.Tx.FetchBlockHeader(hash)
+	finishOp(done, len(header), err)
+	return header, err
+}
+
+// FetchBlockHeaders returns the raw serialized bytes for the block headers
+// identified by the given hashes.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlockHeaders(hashes []chainhash.Hash) ([][]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlockHeader, "")
+	headers, err := tx.Tx.FetchBlockHeaders(hashes)
+	finishOp(done, totalSize(headers), err)
+	return headers, err
+}
+
+// FetchBlock returns the raw serialized bytes for the block identified by the
+// given hash.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlock(hash *chainhash.Hash) ([]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlock, "")
+	block, err := tx.Tx.FetchBlock(hash)
+	finishOp(done, len(block), err)
+	return block, err
+}
+
+// FetchBlocks returns the raw serialized bytes for the blocks identified by the
+// given hashes.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlocks(hashes []chainhash.Hash) ([][]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlock, "")
+	blocks, err := tx.Tx.FetchBlocks(hashes)
+	finishOp(done, totalSize(blocks), err)
+	return blocks, err
+}
+
+// FetchBlockRegion returns the raw serialized bytes for the given block region.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlockRegion(region *BlockRegion) ([]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlockRegion, "")
+	regionBytes, err := tx.Tx.FetchBlockRegion(region)
+	finishOp(done, len(regionBytes), err)
+	return regionBytes, err
+}
+
+// FetchBlockRegions returns the raw serialized bytes for the given block
+// regions.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) FetchBlockRegions(regions []BlockRegion) ([][]byte, error) {
+	done := tx.hooks.StartOp(OpFetchBlockRegion, "")
+	regionsBytes, err := tx.Tx.FetchBlockRegions(regions)
+	finishOp(done, totalSize(regionsBytes), err)
+	return regionsBytes, err
+}
+
+// Commit commits all changes that have been made to the metadata or block
+// storage.
+//
+// This function is part of the Tx interface implementation.
+func (tx *hookedTx) Commit() error {
+	done := tx.hooks.StartOp(OpCommit, "")
+	err := tx.Tx.Commit()
+	finishOp(done, 0, err)
+	return err
+}
+
+// totalSize returns the total number of bytes in the provided slices.
+func totalSize(values [][]byte) int {
+	var size int
+	for _, v := range values {
+		size += len(v)
+	}
+	return size
+}
+
+// hookedBucket wraps a bucket so its operations are observed by hooks.
+//
+// It implements the Bucket interface.
+type hookedBucket struct {
+	bucket Bucket
+	hooks  Hooks
+	name   string
+}
+
+// Enforce hookedBucket implements the Bucket interface.
+var _ Bucket = (*hookedBucket)(nil)
+
+// wrapChild returns the provided bucket, which is the nested bucket with the
+// provided key, wrapped so its operations are observed by the same hooks.  It
+// returns nil when the provided bucket is nil.
+func (b *hookedBucket) wrapChild(key []byte, child Bucket) Bucket {
+	if child == nil {
+		return nil
+	}
+	name := string(key)
+	if b.name != "" {
+		name = b.name + "/" + name
+	}
+	return &hookedBucket{bucket: child, hooks: b.hooks, name: name}
+}
+
+// Bucket retrieves a nested bucket with the given key.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Bucket(key []byte) Bucket {
+	return b.wrapChild(key, b.bucket.Bucket(key))
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) CreateBucket(key []byte) (Bucket, error) {
+	child, err := b.bucket.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrapChild(key, child), nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) CreateBucketIfNotExists(key []byte) (Bucket, error) {
+	child, err := b.bucket.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrapChild(key, child), nil
+}
+
+// DeleteBucket removes a nested bucket with the given key.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) DeleteBucket(key []byte) error {
+	return b.bucket.DeleteBucket(key)
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) ForEach(fn func(k, v []byte) error) error {
+	done := b.hooks.StartOp(OpForEach, b.name)
+	var size int
+	err := b.bucket.ForEach(func(k, v []byte) error {
+		size += len(v)
+		return fn(k, v)
+	})
+	finishOp(done, size, err)
+	return err
+}
+
+// ForEachBucket invokes the passed function with the key of every nested
+// bucket in the current bucket.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) ForEachBucket(fn func(k []byte) error) error {
+	return b.bucket.ForEachBucket(fn)
+}
+
+// Cursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Cursor() Cursor {
+	return &hookedCursor{Cursor: b.bucket.Cursor(), bucket: b}
+}
+
+// RangeIterator returns a new iterator over the key/value pairs in the bucket
+// with keys in the range [start, limit).
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) RangeIterator(start, limit []byte, reverse bool) (Iterator, error) {
+	return b.bucket.RangeIterator(start, limit, reverse)
+}
+
+// PrefixIterator returns a new iterator over the key/value pairs in the bucket
+// with keys that start with the given prefix.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) PrefixIterator(prefix []byte, reverse bool) (Iterator, error) {
+	return b.bucket.PrefixIterator(prefix, reverse)
+}
+
+// Writable returns whether or not the bucket is writable.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Writable() bool {
+	return b.bucket.Writable()
+}
+
+// Put saves the specified key/value pair to the bucket.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Put(key, value []byte) error {
+	done := b.hooks.StartOp(OpPut, b.name)
+	err := b.bucket.Put(key, value)
+	finishOp(done, len(value), err)
+	return err
+}
+
+// Get returns the value for the given key.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Get(key []byte) []byte {
+	done := b.hooks.StartOp(OpGet, b.name)
+	value := b.bucket.Get(key)
+	finishOp(done, len(value), nil)
+	return value
+}
+
+// Delete removes the specified key from the bucket.
+//
+// This function is part of the Bucket interface implementation.
+func (b *hookedBucket) Delete(key []byte) error {
+	done := b.hooks.StartOp(OpDelete, b.name)
+	err := b.bucket.Delete(key)
+	finishOp(done, 0, err)
+	return err
+}
+
+// hookedCursor wraps a cursor so its operations are observed by hooks.
+//
+// It implements the Cursor interface.
+type hookedCursor struct {
+	Cursor
+	bucket *hookedBucket
+}
+
+// Enforce hookedCursor implements the Cursor interface.
+var _ Cursor = (*hookedCursor)(nil)
+
+// Bucket returns the bucket the cursor was created for.
+//
+// This function is part of the Cursor interface implementation.
+func (c *hookedCursor) Bucket() Bucket {
+	return c.bucket
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor.
+//
+// This function is part of the Cursor interface implementation.
+func (c *hookedCursor) Delete() error {
+	done := c.bucket.hooks.StartOp(OpDelete, c.bucket.name)
+	err := c.Cursor.Delete()
+	finishOp(done, 0, err)
+	return err
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// recordedOp describes an operation observed by recordingHooks.
+type recordedOp struct {
+	op     database.Op
+	bucket string
+	size   int
+	failed bool
+}
+
+// recordingHooks records the operations it observes.
+//
+// It implements the database.Hooks interface.
+type recordingHooks struct {
+	mtx sync.Mutex
+	ops []recordedOp
+}
+
+// StartOp records the provided operation once it finishes.
+//
+// This is part of the database.Hooks interface implementation.
+func (h *recordingHooks) StartOp(op database.Op, bucket string) func(int, error) {
+	return func(size int, err error) {
+		h.mtx.Lock()
+		h.ops = append(h.ops, recordedOp{op, bucket, size, err != nil})
+		h.mtx.Unlock()
+	}
+}
+
+// reset returns the operations recorded so far and clears them.
+func (h *recordingHooks) reset() []recordedOp {
+	h.mtx.Lock()
+	ops := h.ops
+	h.ops = nil
+	h.mtx.Unlock()
+	return ops
+}
+
+// TestHooks ensures the operations performed on a database wrapped with hooks
+// are observed with the expected types, buckets, and sizes.
+func TestHooks(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "hooks")
+	db, err := database.Create("ffldb", dbPath, wire.MainNet)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	defer os.RemoveAll(dbPath)
+	defer db.Close()
+
+	// Ensure the database is returned unmodified without hooks.
+	if got := database.WithHooks(db, nil); got != db {
+		t.Fatal("database was wrapped without hooks")
+	}
+
+	hooks := new(recordingHooks)
+	hdb := database.WithHooks(db, hooks)
+
+	// Ensure operations on the metadata bucket, nested buckets, and blocks
+	// in a managed read-write transaction are observed.
+	genesis := dcrutil.NewBlock(chaincfg.MainNetParams().GenesisBlock)
+	genesisBytes, err := genesis.Bytes()
+	if err != nil {
+		t.Fatalf("failed to serialize block: %v", err)
+	}
+	errTest := errors.New("test error")
+	err = hdb.Update(func(tx database.Tx) error {
+		meta := tx.Metadata()
+		if err := meta.Put([]byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		parent, err := meta.CreateBucket([]byte("parent"))
+		if err != nil {
+			return err
+		}
+		child, err := parent.CreateBucketIfNotExists([]byte("child"))
+		if err != nil {
+			return err
+		}
+		if err := child.Put([]byte("k1"), []byte("v1")); err != nil {
+			return err
+		}
+		if err := child.Put([]byte("k2"), []byte("v22")); err != nil {
+			return err
+		}
+		_ = meta.Bucket([]byte("parent")).Bucket([]byte("child")).Get([]byte("k1"))
+		_ = child.ForEach(func(k, v []byte) error { return nil })
+		c := child.Cursor()
+		if !c.First() {
+			return errors.New("cursor is unexpectedly empty")
+		}
+		if err := c.Bucket().Delete([]byte("k2")); err != nil {
+			return err
+		}
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		if err := tx.StoreBlock(genesis); err != nil {
+			return err
+		}
+		_, err = tx.FetchBlock(genesis.Hash())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to update database: %v", err)
+	}
+	want := []recordedOp{
+		{database.OpPut, "", 5, false},
+		{database.OpPut, "parent/child", 2, false},
+		{database.OpPut, "parent/child", 3, false},
+		{database.OpGet, "parent/child", 2, false},
+		{database.OpForEach, "parent/child", 5, false},
+		{database.OpDelete, "parent/child", 0, false},
+		{database.OpDelete, "parent/child", 0, false},
+		{database.OpStoreBlock, "", len(genesisBytes), false},
+		{database.OpFetchBlock, "", len(genesisBytes), false},
+		{database.OpUpdate, "", 0, false},
+	}
+	if got := hooks.reset(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched ops:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	// Ensure failures are observed.
+	err = hdb.View(func(tx database.Tx) error {
+		if _, err := tx.Metadata().Bucket([]byte("parent")).
+			Bucket([]byte("child")).CreateBucket([]byte("ro")); err == nil {
+			return errors.New("bucket created in read-only transaction")
+		}
+		return errTest
+	})
+	if !errors.Is(err, errTest) {
+		t.Fatalf("unexpected view error: got %v, want %v", err, errTest)
+	}
+	want = []recordedOp{{database.OpView, "", 0, true}}
+	if got := hooks.reset(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched ops:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	// Ensure operations in unmanaged transactions are observed.
+	tx, err := hdb.Begin(false)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	_, _ = tx.FetchBlockHeader(genesis.Hash())
+	if err := tx.Commit(); err == nil {
+		t.Fatal("read-only transaction committed")
+	}
+	want = []recordedOp{
+		{database.OpFetchBlockHeader, "", wire.MaxBlockHeaderPayload, false},
+		{database.OpCommit, "", 0, true},
+	}
+	if got := hooks.reset(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched ops:\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -9,6 +9,9 @@
 package database
 
 import (
+	"context"
+	"time"
+
 	"github.com/decred/dcrd/chaincfg/chainhash"
 )
 
@@ -63,6 +66,32 @@ type Cursor interface {
 	Value() []byte
 }
 
+// Iterator represents an iterator over a range of the key/value pairs in a
+// bucket as they existed when the iterator was created.  Unlike a Cursor, the
+// pairs it visits are not affected by any modifications made to the bucket
+// after it is created, including those made by the transaction it was created
+// from.  It does not visit nested buckets.
+//
+// The iterator is initially positioned before the first pair, so Next must be
+// called before the Key or Value functions.
+type Iterator interface {
+	// Next moves the iterator to the next key/value pair in its direction
+	// and returns whether or not the pair exists.  It returns false once
+	// the transaction the iterator was created from has been closed.
+	Next() bool
+
+	// Key returns the current key the iterator is pointing to.
+	Key() []byte
+
+	// Value returns the current value the iterator is pointing to.
+	Value() []byte
+
+	// Release releases the resources associated with the iterator.  The
+	// iterator is exhausted afterwards.  It is safe to call Release more
+	// than once.
+	Release()
+}
+
 // Bucket represents a collection of key/value pairs.
 type Bucket interface {
 	// Bucket retrieves a nested bucket with the given key.  Returns nil if
@@ -153,6 +182,31 @@ type Bucket interface {
 	// Value functions.
 	Cursor() Cursor
 
+	// RangeIterator returns a new iterator over the key/value pairs in the
+	// bucket with keys that are greater than or equal to the start key and
+	// less than the limit key.  A nil start or limit key leaves the range
+	// unbounded on that side.  The pairs are visited in descending order
+	// when reverse is true.
+	//
+	// The iterator operates over a snapshot of the bucket taken when it is
+	// created, so it does not observe any modifications made while it is
+	// in use and it is safe to modify the bucket while iterating.  The
+	// iterator must be released once it is no longer needed.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxClosed if the transaction has already been closed
+	RangeIterator(start, limit []byte, reverse bool) (Iterator, error)
+
+	// PrefixIterator returns a new iterator over the key/value pairs in the
+	// bucket with keys that start with the provided prefix.  It is
+	// otherwise the same as RangeIterator.
+	//
+	// The interface contract guarantees at least the following errors will
+	// be returned (other implementation-specific errors are possible):
+	//   - ErrTxClosed if the transaction has already been closed
+	PrefixIterator(prefix []byte, reverse bool) (Iterator, error)
+
 	// Writable returns whether or not the bucket is writable.
 	Writable() bool
 
@@ -478,4 +532,123 @@ type DB interface {
 
 	// Flush writes all outstanding cached entries to disk.
 	Flush() error
+
+	// Backup writes a consistent copy of the database as of the time it is
+	// called to the provided directory while the database remains usable
+	// by other callers.  The directory must not already exist and is
+	// removed when the backup fails.  The copy may be opened with the same
+	// driver as the original database.
+	//
+	// The provided function, which may be nil, is invoked periodically
+	// with the progress of the backup.  The backup is aborted when the
+	// provided context is canceled.
+	Backup(ctx context.Context, dir string, progress func(BackupProgress)) error
+
+	// Stats returns statistics about the storage backend of the database
+	// such as the size of each level and the amount of pending compaction.
+	Stats() (*Stats, error)
+}
+
+// BackupProgress describes the progress of a database backup.
+type BackupProgress struct {
+	// BytesCopied is the number of bytes copied so far.
+	BytesCopied uint64
+
+	// TotalBytes is the estimated total number of bytes to copy.  It is
+	// never less than BytesCopied.
+	TotalBytes uint64
+}
+
+// Options houses optional tuning parameters for the storage backend of a
+// database.  The zero value of each option selects the default of the driver
+// and drivers ignore options they do not support.
+type Options struct {
+	// WriteBufferSize is the number of bytes of writes that are buffered in
+	// memory before they are written to disk.
+	WriteBufferSize int
+
+	// MaxOpenFiles is the maximum number of storage files that are kept
+	// open at the same time.
+	MaxOpenFiles int
+
+	// CompactionL0Trigger is the number of tables in the first level that
+	// triggers a compaction.  Writes are slowed down and then paused as the
+	// number of tables in the first level grows beyond it.
+	CompactionL0Trigger int
+
+	// CompactionTableSize is the size in bytes of the tables produced by a
+	// compaction.
+	CompactionTableSize int
+
+	// EncryptionKey is the key the contents of the database are encrypted
+	// with when it is not nil.  It must be dbcrypt.KeySize bytes.  The key
+	// must be provided when the database is created and it must be the same
+	// every time the database is opened after that.
+	EncryptionKey []byte
+
+	// BlockFilesPerDir is the number of block files that are housed by each
+	// subdirectory of the directories block files are stored in.  The files
+	// are stored directly in the directories when it is zero.  Existing
+	// files that are stored directly in the directories are still found
+	// when it is set later, however, it must not be changed once set.
+	BlockFilesPerDir int
+
+	// ColdBlockDir is a secondary directory, typically on cheaper and slower
+	// storage, older block files are moved to in the background.  Reads are
+	// transparent regardless of where a file is stored.  Block files are
+	// never moved when it is empty.
+	ColdBlockDir string
+
+	// HotBlockFiles is the number of the most recent block files that are
+	// kept in the primary directory when ColdBlockDir is set.
+	HotBlockFiles int
+}
+
+// LevelStats describes a level of the storage backend of a database.
+type LevelStats struct {
+	// Tables is the number of tables in the level.
+	Tables int
+
+	// Size is the total size in bytes of the tables in the level.
+	Size int64
+
+	// Read and Written are the number of bytes read and written by
+	// compactions into the level.
+	Read    int64
+	Written int64
+
+	// CompactionTime is the total time spent compacting into the level.
+	CompactionTime time.Duration
+}
+
+// Stats describes the state of the storage backend of a database.
+type Stats struct {
+	// Levels describes each level of the storage backend starting with the
+	// first level.
+	Levels []LevelStats
+
+	// CompactionBacklog is the number of bytes in levels that exceed the
+	// size which triggers a compaction of the level.
+	CompactionBacklog int64
+
+	// ReadAmplification is the number of tables that might need to be read
+	// in order to look up a single key.
+	ReadAmplification int
+
+	// WriteAmplification is the ratio of the number of bytes written by all
+	// compactions to the number of bytes written to the first level.  It is
+	// zero when nothing has been written yet.
+	WriteAmplification float64
+
+	// Compactions is the number of compactions that have been performed.
+	Compactions uint64
+
+	// WriteDelays is the number of times writes were delayed due to pending
+	// compactions and WriteDelayTime is the total time they were delayed.
+	WriteDelays    uint64
+	WriteDelayTime time.Duration
+
+	// WritePaused indicates writes are currently paused due to pending
+	// compactions.
+	WritePaused bool
 }
@@ -0,0 +1,297 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/metrics"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// namedDBStats associates the statistics of a database with the name used for
+// it in the metrics.
+type namedDBStats struct {
+	name  string
+	stats *database.Stats
+}
+
+// dbOpKey identifies the operations of a given type on a given bucket that are
+// tracked by dbOpMetrics.
+type dbOpKey struct {
+	bucket string
+	op     database.Op
+}
+
+// dbOpStats houses the number of operations of a given type on a given bucket,
+// the number of those that resulted in an error, the total size of the values
+// they involved, and the total time spent performing them.
+type dbOpStats struct {
+	count    uint64
+	errors   uint64
+	bytes    uint64
+	duration time.Duration
+}
+
+// dbOpMetrics tracks per-bucket operation counts, latencies, and value sizes of
+// the block database so performance regressions in the storage layer are
+// measurable.
+//
+// It implements the database.Hooks interface.
+type dbOpMetrics struct {
+	mtx sync.Mutex
+	ops map[dbOpKey]*dbOpStats
+}
+
+// newDBOpMetrics returns a new empty instance of database operation metrics.
+func newDBOpMetrics() *dbOpMetrics {
+	return &dbOpMetrics{
+		ops: make(map[dbOpKey]*dbOpStats),
+	}
+}
+
+// StartOp records the start of an operation of the provided type on the
+// provided bucket and returns a function that records its completion.
+//
+// This is part of the database.Hooks interface implementation.
+func (m *dbOpMetrics) StartOp(op database.Op, bucket string) func(int, error) {
+	start := time.Now()
+	return func(size int, err error) {
+		elapsed := time.Since(start)
+		key := dbOpKey{bucket: bucket, op: op}
+
+		m.mtx.Lock()
+		stats, ok := m.ops[key]
+		if !ok {
+			stats = &dbOpStats{}
+			m.ops[key] = stats
+		}
+		stats.count++
+		if err != nil {
+			stats.errors++
+		}
+		stats.bytes += uint64(size)
+		stats.duration += elapsed
+		m.mtx.Unlock()
+	}
+}
+
+// writeTo writes the metrics of all tracked operations to the provided writer
+// in the Prometheus text exposition format.  The operations are sorted by
+// bucket and then by type.
+//
+// This function is safe for concurrent access.
+func (m *dbOpMetrics) writeTo(w io.Writer) error {
+	m.mtx.Lock()
+	keys := make([]dbOpKey, 0, len(m.ops))
+	snapshot := make(map[dbOpKey]dbOpStats, len(m.ops))
+	for key, stats := range m.ops {
+		keys = append(keys, key)
+		snapshot[key] = *stats
+	}
+	m.mtx.Unlock()
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].bucket != keys[j].bucket {
+			return keys[i].bucket < keys[j].bucket
+		}
+		return keys[i].op < keys[j].op
+	})
+
+	mw := metrics.NewWriter(w)
+	labels := func(key dbOpKey) []string {
+		return []string{"db", "block", "bucket", key.bucket, "op",
+			key.op.String()}
+	}
+	write := func(name, help string, value func(*dbOpStats) uint64) {
+		mw.Header(name, help, "counter")
+		for _, key := range keys {
+			stats := snapshot[key]
+			mw.Sample(name, value(&stats), labels(key)...)
+		}
+	}
+
+	write("dcrd_db_ops_total", "Number of block database operations by "+
+		"bucket and type.", func(s *dbOpStats) uint64 {
+		return s.count
+	})
+	write("dcrd_db_op_errors_total", "Number of block database operations "+
+		"that failed by bucket and type.", func(s *dbOpStats) uint64 {
+		return s.errors
+	})
+	write("dcrd_db_op_bytes_total", "Size of the values retrieved or stored "+
+		"by block database operations by bucket and type.",
+		func(s *dbOpStats) uint64 {
+			return s.bytes
+		})
+	mw.Header("dcrd_db_op_seconds_total", "Time spent performing block "+
+		"database operations by bucket and type.", "counter")
+	for _, key := range keys {
+		mw.FloatSample("dcrd_db_op_seconds_total",
+			snapshot[key].duration.Seconds(), labels(key)...)
+	}
+
+	return mw.Flush()
+}
+
+// dbMetrics provides statistics about the storage backends of the block and
+// utxo databases as well as the flushes of the utxo cache to the metrics
+// endpoint of the RPC server.  It also provides the metrics of the operations
+// on the block database when they are tracked.
+//
+// It implements the metrics.Source and rpcserver.MetricsSource interfaces.
+type dbMetrics struct {
+	blockDB    database.DB
+	utxoDB     *leveldb.DB
+	utxoDBOpts *database.Options
+	chain      *blockchain.BlockChain
+	ops        *dbOpMetrics
+}
+
+// writeDBMetrics writes the provided database statistics to the provided
+// writer in the Prometheus text exposition format.
+func writeDBMetrics(w io.Writer, dbs []namedDBStats) error {
+	mw := metrics.NewWriter(w)
+	writeLevels := func(name, help, typ string, value func(*database.LevelStats) float64) {
+		mw.Header(name, help, typ)
+		for _, db := range dbs {
+			for level := range db.stats.Levels {
+				mw.FloatSample(name, value(&db.stats.Levels[level]), "db",
+					db.name, "level", strconv.Itoa(level))
+			}
+		}
+	}
+	writeDBs := func(name, help, typ string, value func(*database.Stats) float64) {
+		mw.Header(name, help, typ)
+		for _, db := range dbs {
+			mw.FloatSample(name, value(db.stats), "db", db.name)
+		}
+	}
+
+	writeLevels("dcrd_db_level_tables", "Number of tables by database and "+
+		"level.", "gauge", func(ls *database.LevelStats) float64 {
+		return float64(ls.Tables)
+	})
+	writeLevels("dcrd_db_level_size_bytes", "Size of the tables by database "+
+		"and level.", "gauge", func(ls *database.LevelStats) float64 {
+		return float64(ls.Size)
+	})
+	writeLevels("dcrd_db_level_read_bytes_total", "Number of bytes read by "+
+		"compactions into each level by database.", "counter",
+		func(ls *database.LevelStats) float64 {
+			return float64(ls.Read)
+		})
+	writeLevels("dcrd_db_level_written_bytes_total", "Number of bytes "+
+		"written by compactions into each level by database.", "counter",
+		func(ls *database.LevelStats) float64 {
+			return float64(ls.Written)
+		})
+	writeLevels("dcrd_db_level_compaction_seconds_total", "Time spent "+
+		"compacting into each level by database.", "counter",
+		func(ls *database.LevelStats) float64 {
+			return ls.CompactionTime.Seconds()
+		})
+
+	writeDBs("dcrd_db_compaction_backlog_bytes", "Number of bytes in levels "+
+		"that exceed the size which triggers their compaction by database.",
+		"gauge", func(s *database.Stats) float64 {
+			return float64(s.CompactionBacklog)
+		})
+	writeDBs("dcrd_db_read_amplification", "Number of tables that might be "+
+		"read to look up a single key by database.", "gauge",
+		func(s *database.Stats) float64 {
+			return float64(s.ReadAmplification)
+		})
+	writeDBs("dcrd_db_write_amplification", "Ratio of bytes written by "+
+		"compactions to bytes written to the first level by database.",
+		"gauge", func(s *database.Stats) float64 {
+			return s.WriteAmplification
+		})
+	writeDBs("dcrd_db_compactions_total", "Number of compactions by "+
+		"database.", "counter", func(s *database.Stats) float64 {
+		return float64(s.Compactions)
+	})
+	writeDBs("dcrd_db_write_delays_total", "Number of writes delayed by "+
+		"pending compactions by database.", "counter",
+		func(s *database.Stats) float64 {
+			return float64(s.WriteDelays)
+		})
+	writeDBs("dcrd_db_write_delay_seconds_total", "Time writes were delayed "+
+		"by pending compactions by database.", "counter",
+		func(s *database.Stats) float64 {
+			return s.WriteDelayTime.Seconds()
+		})
+	writeDBs("dcrd_db_write_paused", "Whether writes are paused by pending "+
+		"compactions by database.", "gauge", func(s *database.Stats) float64 {
+		if s.WritePaused {
+			return 1
+		}
+		return 0
+	})
+
+	return mw.Flush()
+}
+
+// writeUtxoFlushMetrics writes the provided utxo cache flush statistics to the
+// provided writer in the Prometheus text exposition format.
+func writeUtxoFlushMetrics(w io.Writer, stats *blockchain.UtxoFlushStats) error {
+	mw := metrics.NewWriter(w)
+	mw.Counter("dcrd_utxo_flushes_total", "Number of flushes of the utxo "+
+		"cache.", stats.Flushes)
+	mw.Counter("dcrd_utxo_flush_entries_total", "Number of modified entries "+
+		"written by flushes of the utxo cache.", stats.Entries)
+	mw.Counter("dcrd_utxo_flush_bytes_total", "Size of the modified entries "+
+		"written by flushes of the utxo cache.", stats.Bytes)
+	mw.Header("dcrd_utxo_flush_seconds_total", "Time spent flushing the utxo "+
+		"cache.", "counter")
+	mw.FloatSample("dcrd_utxo_flush_seconds_total", stats.Duration.Seconds())
+	mw.Gauge("dcrd_utxo_flush_last_entries", "Number of modified entries "+
+		"written by the most recent flush of the utxo cache.",
+		float64(stats.LastEntries))
+	mw.Gauge("dcrd_utxo_flush_last_bytes", "Size of the modified entries "+
+		"written by the most recent flush of the utxo cache.",
+		float64(stats.LastBytes))
+	mw.Gauge("dcrd_utxo_flush_last_seconds", "Time spent on the most recent "+
+		"flush of the utxo cache.", stats.LastDuration.Seconds())
+
+	return mw.Flush()
+}
+
+// WriteMetrics writes the current statistics of the block and utxo databases,
+// the flushes of the utxo cache, and the operations on the block database, when
+// they are tracked, to the provided writer in the Prometheus text exposition
+// format.
+//
+// This is part of the rpcserver.MetricsSource interface implementation.
+func (m *dbMetrics) WriteMetrics(w io.Writer) error {
+	blockStats, err := m.blockDB.Stats()
+	if err != nil {
+		return err
+	}
+	utxoStats, err := blockchain.FetchUtxoDBStats(m.utxoDB, m.utxoDBOpts)
+	if err != nil {
+		return err
+	}
+	err = writeDBMetrics(w, []namedDBStats{
+		{"block", blockStats},
+		{"utxo", utxoStats},
+	})
+	if err != nil {
+		return err
+	}
+	flushStats := m.chain.UtxoCacheFlushStats()
+	if err := writeUtxoFlushMetrics(w, &flushStats); err != nil {
+		return err
+	}
+	if m.ops == nil {
+		return nil
+	}
+	return m.ops.writeTo(w)
+}
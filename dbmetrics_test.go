@@ -0,0 +1,153 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/internal/blockchain"
+)
+
+// TestDBMetrics ensures the database metrics are written in the expected
+// format.
+func TestDBMetrics(t *testing.T) {
+	dbs := []namedDBStats{{
+		name: "block",
+		stats: &database.Stats{
+			Levels: []database.LevelStats{
+				{Tables: 3, Size: 3000, Written: 3000},
+				{Tables: 1, Size: 5000, Read: 2000, Written: 1500,
+					CompactionTime: 2 * time.Second},
+			},
+			CompactionBacklog:  3000,
+			ReadAmplification:  4,
+			WriteAmplification: 1.5,
+			Compactions:        7,
+		},
+	}, {
+		name: "utxo",
+		stats: &database.Stats{
+			Levels:         []database.LevelStats{{}},
+			WriteDelays:    2,
+			WriteDelayTime: 500 * time.Millisecond,
+			WritePaused:    true,
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeDBMetrics(&buf, dbs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	wantLines := []string{
+		`# TYPE dcrd_db_level_tables gauge`,
+		`dcrd_db_level_tables{db="block",level="0"} 3`,
+		`dcrd_db_level_tables{db="block",level="1"} 1`,
+		`dcrd_db_level_tables{db="utxo",level="0"} 0`,
+		`dcrd_db_level_size_bytes{db="block",level="1"} 5000`,
+		`dcrd_db_level_read_bytes_total{db="block",level="1"} 2000`,
+		`dcrd_db_level_written_bytes_total{db="block",level="0"} 3000`,
+		`dcrd_db_level_compaction_seconds_total{db="block",level="1"} 2`,
+		`# TYPE dcrd_db_compaction_backlog_bytes gauge`,
+		`dcrd_db_compaction_backlog_bytes{db="block"} 3000`,
+		`dcrd_db_read_amplification{db="block"} 4`,
+		`dcrd_db_write_amplification{db="block"} 1.5`,
+		`dcrd_db_write_amplification{db="utxo"} 0`,
+		`# TYPE dcrd_db_compactions_total counter`,
+		`dcrd_db_compactions_total{db="block"} 7`,
+		`dcrd_db_write_delays_total{db="utxo"} 2`,
+		`dcrd_db_write_delay_seconds_total{db="utxo"} 0.5`,
+		`dcrd_db_write_paused{db="block"} 0`,
+		`dcrd_db_write_paused{db="utxo"} 1`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing expected line %q in:\n%s", line, got)
+		}
+	}
+}
+
+// TestUtxoFlushMetrics ensures the utxo cache flush metrics are written in the
+// expected format.
+func TestUtxoFlushMetrics(t *testing.T) {
+	stats := &blockchain.UtxoFlushStats{
+		Flushes:      3,
+		Entries:      1500,
+		Bytes:        96000,
+		Duration:     2500 * time.Millisecond,
+		LastEntries:  500,
+		LastBytes:    32000,
+		LastDuration: 750 * time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	if err := writeUtxoFlushMetrics(&buf, stats); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	wantLines := []string{
+		`# TYPE dcrd_utxo_flushes_total counter`,
+		`dcrd_utxo_flushes_total 3`,
+		`dcrd_utxo_flush_entries_total 1500`,
+		`dcrd_utxo_flush_bytes_total 96000`,
+		`dcrd_utxo_flush_seconds_total 2.5`,
+		`# TYPE dcrd_utxo_flush_last_entries gauge`,
+		`dcrd_utxo_flush_last_entries 500`,
+		`dcrd_utxo_flush_last_bytes 32000`,
+		`dcrd_utxo_flush_last_seconds 0.75`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing expected line %q in:\n%s", line, got)
+		}
+	}
+}
+
+// TestDBOpMetrics ensures the database operation metrics are tracked and
+// written in the expected format.
+func TestDBOpMetrics(t *testing.T) {
+	m := newDBOpMetrics()
+	m.StartOp(database.OpGet, "blockidxv3")(100, nil)
+	m.StartOp(database.OpGet, "blockidxv3")(0, nil)
+	m.StartOp(database.OpPut, "blockidxv3")(50, nil)
+	m.StartOp(database.OpPut, "blockidxv3")(0, errors.New("test error"))
+	m.StartOp(database.OpFetchBlock, "")(1000, nil)
+
+	var buf bytes.Buffer
+	if err := m.writeTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	wantLines := []string{
+		`# TYPE dcrd_db_ops_total counter`,
+		`dcrd_db_ops_total{db="block",bucket="",op="fetchblock"} 1`,
+		`dcrd_db_ops_total{db="block",bucket="blockidxv3",op="get"} 2`,
+		`dcrd_db_ops_total{db="block",bucket="blockidxv3",op="put"} 2`,
+		`dcrd_db_op_errors_total{db="block",bucket="blockidxv3",op="get"} 0`,
+		`dcrd_db_op_errors_total{db="block",bucket="blockidxv3",op="put"} 1`,
+		`dcrd_db_op_bytes_total{db="block",bucket="",op="fetchblock"} 1000`,
+		`dcrd_db_op_bytes_total{db="block",bucket="blockidxv3",op="get"} 100`,
+		`dcrd_db_op_bytes_total{db="block",bucket="blockidxv3",op="put"} 50`,
+		`# TYPE dcrd_db_op_seconds_total counter`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing expected line %q in:\n%s", line, got)
+		}
+	}
+
+	// Ensure the operations are sorted by bucket and then by type.
+	getIdx := strings.Index(got, `bucket="blockidxv3",op="get"`)
+	putIdx := strings.Index(got, `bucket="blockidxv3",op="put"`)
+	fetchIdx := strings.Index(got, `bucket="",op="fetchblock"`)
+	if fetchIdx > getIdx || getIdx > putIdx {
+		t.Errorf("operations are not sorted:\n%s", got)
+	}
+}
@@ -183,7 +183,8 @@ func dcrdMain() error {
 	}
 
 	// Load the UTXO database.
-	utxoDb, err := blockchain.LoadUtxoDB(ctx, cfg.params.Params, cfg.DataDir)
+	utxoDb, err := blockchain.LoadUtxoDB(ctx, cfg.params.Params, cfg.DataDir,
+		cfg.dbOpts)
 	if err != nil {
 		dcrdLog.Errorf("%v", err)
 		return err
@@ -201,10 +202,7 @@ func dcrdMain() error {
 
 	// Always drop the legacy address index if needed and drop any other indexes
 	// and exit if requested.
-	//
-	// NOTE: The order is important here because dropping the tx index also
-	// drops the address index since it relies on it.
-	if err := indexers.DropAddrIndex(ctx, db); err != nil {
+	if err := indexers.DropLegacyAddrIndex(ctx, db); err != nil {
 		dcrdLog.Errorf("%v", err)
 		return err
 	}
@@ -224,6 +222,48 @@ func dcrdMain() error {
 
 		return nil
 	}
+	if cfg.DropAddrIndex {
+		if err := indexers.DropAddrIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+	if cfg.DropSpentIndex {
+		if err := indexers.DropSpentIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+
+	// Verify a single optional index and exit if requested.
+	if cfg.VerifyIndex != "" {
+		idx, err := lookupOptionalIndex(cfg.VerifyIndex)
+		if err == nil {
+			err = verifyOptionalIndex(ctx, db, utxoDb, cfg.params.Params, idx)
+		}
+		if err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+
+	// Verify the chain state and exit if requested.
+	if cfg.VerifyChainState {
+		err := verifyChainState(ctx, db, utxoDb, cfg.params.Params,
+			cfg.RepairIndexes)
+		if err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 
 	// Drop the legacy v1 committed filter index if needed.
 	if err := indexers.DropCfIndex(ctx, db); err != nil {
@@ -231,6 +271,20 @@ func dcrdMain() error {
 		return err
 	}
 
+	// Drop a single optional index when requested so it is rebuilt in the
+	// background once the server starts.
+	if cfg.RebuildIndex != "" {
+		idx, err := lookupOptionalIndex(cfg.RebuildIndex)
+		if err == nil {
+			dcrdLog.Infof("Rebuilding the %s", idx.name)
+			err = idx.drop(ctx, db)
+		}
+		if err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+	}
+
 	// Create server.
 	lifetimeNotifier.notifyStartupEvent(lifetimeEventP2PServer)
 	svr, err := newServer(ctx, cfg.Listeners, db, utxoDb, cfg.params.Params,
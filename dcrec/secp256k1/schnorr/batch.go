@@ -0,0 +1,306 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"encoding/binary"
+
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// DefaultBatchSize is the maximum number of signatures verified together
+	// by a batch verifier when no batch size is specified.  Larger batches
+	// amortize more of the verification cost, but the cost of isolating
+	// invalid signatures in a failed batch also grows with its size.
+	DefaultBatchSize = 64
+
+	// batchWindowBits is the number of bits of the scalars processed per
+	// iteration of the multi-scalar multiplication used in batch verification.
+	batchWindowBits = 4
+
+	// batchTableSize is the number of multiples of each point precomputed for
+	// the multi-scalar multiplication used in batch verification.
+	batchTableSize = 1 << batchWindowBits
+)
+
+// BatchEntry houses a signature along with the hash and public key it is to be
+// verified against as part of a batch.
+type BatchEntry struct {
+	Sig    *Signature
+	Hash   []byte
+	PubKey *secp256k1.PublicKey
+}
+
+// batchRandomizers returns the random scalars used to form the linear
+// combination of the signature equations of the provided entries.
+//
+// The first scalar is one and the remaining ones are 128-bit values derived
+// from a hash that commits to all of the entries.  This ensures the scalars are
+// not known until after all of the signatures, hashes, and public keys in the
+// batch are fixed, which is what prevents a set of invalid signatures from
+// being crafted such that their errors cancel each other out, while also making
+// the result of the verification deterministic.
+func batchRandomizers(entries []BatchEntry) []secp256k1.ModNScalar {
+	hasher := blake256.New()
+	for _, entry := range entries {
+		hasher.Write(entry.PubKey.SerializeCompressed())
+		hasher.Write(entry.Hash)
+		hasher.Write(entry.Sig.Serialize())
+	}
+	var seed [blake256.Size + 4]byte
+	copy(seed[:], hasher.Sum(nil))
+
+	randomizers := make([]secp256k1.ModNScalar, len(entries))
+	randomizers[0].SetInt(1)
+	for i := 1; i < len(entries); i++ {
+		binary.BigEndian.PutUint32(seed[blake256.Size:], uint32(i))
+		digest := blake256.Sum256(seed[:])
+		randomizers[i].SetByteSlice(digest[:16])
+		if randomizers[i].IsZero() {
+			randomizers[i].SetInt(1)
+		}
+	}
+	return randomizers
+}
+
+// multiScalarMultNonConst calculates the sum of the provided points each
+// multiplied by the corresponding provided scalar and stores the result in the
+// provided result param in *non-constant* time.
+//
+// It uses the interleaved fixed window method, which shares the point
+// doublings across all of the points and is therefore significantly faster
+// than multiplying each point individually.
+//
+// NOTE: The points must be normalized for this function to return the correct
+// result.  The resulting point will be normalized.
+func multiScalarMultNonConst(scalars []secp256k1.ModNScalar, points []secp256k1.JacobianPoint, result *secp256k1.JacobianPoint) {
+	// Precompute the multiples [0*P, 1*P, ..., 15*P] of each point.
+	tables := make([][batchTableSize]secp256k1.JacobianPoint, len(points))
+	for i := range points {
+		table := &tables[i]
+		table[1].Set(&points[i])
+		secp256k1.DoubleNonConst(&points[i], &table[2])
+		for j := 3; j < batchTableSize; j++ {
+			secp256k1.AddNonConst(&table[j-1], &points[i], &table[j])
+		}
+	}
+	scalarBytes := make([][32]byte, len(scalars))
+	for i := range scalars {
+		scalarBytes[i] = scalars[i].Bytes()
+	}
+
+	// Process the scalars from the most significant window to the least
+	// significant one by doubling the accumulated result once per bit of the
+	// window and adding the precomputed multiple of each point selected by
+	// the window of its scalar.
+	var acc, tmp secp256k1.JacobianPoint
+	for window := 0; window < 256/batchWindowBits; window++ {
+		for i := 0; i < batchWindowBits; i++ {
+			secp256k1.DoubleNonConst(&acc, &tmp)
+			acc.Set(&tmp)
+		}
+		byteIdx, shift := window/2, uint(4*(1-window%2))
+		for i := range scalarBytes {
+			idx := (scalarBytes[i][byteIdx] >> shift) & (batchTableSize - 1)
+			if idx == 0 {
+				continue
+			}
+			secp256k1.AddNonConst(&acc, &tables[i][idx], &tmp)
+			acc.Set(&tmp)
+		}
+	}
+	result.Set(&acc)
+}
+
+// BatchVerify returns whether or not all of the signatures in the provided
+// entries are valid for their associated hashes and secp256k1 public keys.
+//
+// Rather than verifying each signature individually, it verifies a random
+// linear combination of the signature equations with a single multi-scalar
+// multiplication, which is faster for large numbers of signatures.  The result
+// is the same as verifying each signature individually with Verify, except with
+// a negligible probability.
+//
+// It does not identify which signatures are invalid when the result is false.
+// See BatchVerifier for a type that limits the size of the batches and isolates
+// the invalid signatures.
+func BatchVerify(entries []BatchEntry) bool {
+	// The algorithm for verifying EC-Schnorr-DCRv0 signatures in a batch is
+	// based on the individual verification algorithm described in README.md
+	// and is reproduced here for reference:
+	//
+	// G = curve generator
+	// n = curve order
+	// p = field prime
+	// u = number of signatures
+	// m_i, Q_i, (r_i, s_i) = message, pubkey, and signature i
+	//
+	// 1. Fail if any m_i is not 32 bytes
+	// 2. Fail if any Q_i is not a point on the curve
+	// 3. e_i = BLAKE-256(r_i || m_i) (Ensure r_i is padded to 32 bytes)
+	// 4. Fail if any e_i >= n
+	// 5. Fail if any r_i is not the x coordinate of a point on the curve
+	// 6. R_i = the point with x coordinate r_i and an even y coordinate
+	// 7. a_1 = 1 and a_2..a_u = 128-bit scalars derived from hashing all
+	//    Q_i, m_i, and (r_i, s_i)
+	// 8. Verified if (a_1*s_1 + ... + a_u*s_u)*G + (a_1*e_1)*Q_1 + ... +
+	//    (a_u*e_u)*Q_u - a_1*R_1 - ... - a_u*R_u is the point at infinity
+	//
+	// Note that r_i >= p and s_i >= n are already handled by the fact they are
+	// field elements and mod n scalars, respectively.
+	//
+	// For valid signatures, s_i*G + e_i*Q_i = R_i, so the combined equation is
+	// satisfied.  Conversely, when any of the signatures is invalid, the
+	// combined equation is only satisfied if the randomizers happen to cancel
+	// out the difference, which only happens with negligible probability since
+	// they are unknown until the entire batch is fixed.
+	switch len(entries) {
+	case 0:
+		return true
+	case 1:
+		entry := &entries[0]
+		return schnorrVerify(entry.Sig, entry.Hash, entry.PubKey) == nil
+	}
+
+	// Steps 1-6.
+	scalars := make([]secp256k1.ModNScalar, 0, len(entries)*2)
+	points := make([]secp256k1.JacobianPoint, 0, len(entries)*2)
+	for i := range entries {
+		entry := &entries[i]
+		if len(entry.Hash) != scalarSize || !entry.PubKey.IsOnCurve() {
+			return false
+		}
+
+		var commitmentInput [scalarSize * 2]byte
+		entry.Sig.r.PutBytesUnchecked(commitmentInput[0:scalarSize])
+		copy(commitmentInput[scalarSize:], entry.Hash)
+		commitment := blake256.Sum256(commitmentInput[:])
+		var e secp256k1.ModNScalar
+		if overflow := e.SetBytes(&commitment); overflow != 0 {
+			return false
+		}
+
+		// Negate the y coordinate of R_i so the multi-scalar multiplication
+		// subtracts it as required by the combined equation.
+		var R secp256k1.JacobianPoint
+		R.X.Set(&entry.Sig.r)
+		if !secp256k1.DecompressY(&R.X, false, &R.Y) {
+			return false
+		}
+		R.Y.Negate(2).Normalize()
+		R.Z.SetInt(1)
+
+		var Q secp256k1.JacobianPoint
+		entry.PubKey.AsJacobian(&Q)
+		scalars = append(scalars, e, secp256k1.ModNScalar{})
+		points = append(points, Q, R)
+	}
+
+	// Steps 7 and 8.
+	var sumS secp256k1.ModNScalar
+	randomizers := batchRandomizers(entries)
+	for i := range entries {
+		a := &randomizers[i]
+		scalars[i*2].Mul(a)
+		scalars[i*2+1].Set(a)
+		sumS.Add(new(secp256k1.ModNScalar).Mul2(a, &entries[i].Sig.s))
+	}
+	var sG, sum, result secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&sumS, &sG)
+	multiScalarMultNonConst(scalars, points, &sum)
+	secp256k1.AddNonConst(&sG, &sum, &result)
+	return (result.X.IsZero() && result.Y.IsZero()) || result.Z.IsZero()
+}
+
+// BatchVerifier accumulates signatures to verify them in batches of a
+// configurable maximum size and isolates any invalid signatures.
+//
+// It is suitable for verifying large numbers of signatures where the majority
+// are expected to be valid, such as those in blocks, since invalid signatures
+// are isolated by recursively splitting failed batches in half, which is more
+// expensive than verifying the signatures in the failed batch individually.
+//
+// The zero value is not usable.  Use NewBatchVerifier to create instances.
+type BatchVerifier struct {
+	batchSize int
+	entries   []BatchEntry
+}
+
+// NewBatchVerifier returns a batch verifier that verifies the signatures added
+// to it in batches of at most the provided size.  DefaultBatchSize is used when
+// the provided size is not positive.
+func NewBatchVerifier(batchSize int) *BatchVerifier {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &BatchVerifier{batchSize: batchSize}
+}
+
+// Add adds the provided signature along with the hash and public key it is to
+// be verified against to the batch verifier.
+func (v *BatchVerifier) Add(sig *Signature, hash []byte, pubKey *secp256k1.PublicKey) {
+	v.entries = append(v.entries, BatchEntry{Sig: sig, Hash: hash,
+		PubKey: pubKey})
+}
+
+// Len returns the number of signatures added to the batch verifier.
+func (v *BatchVerifier) Len() int {
+	return len(v.entries)
+}
+
+// Reset removes all signatures from the batch verifier so it can be reused.
+func (v *BatchVerifier) Reset() {
+	v.entries = v.entries[:0]
+}
+
+// Verify returns whether or not all of the signatures added to the batch
+// verifier are valid.  See InvalidSignatures to identify the invalid ones.
+func (v *BatchVerifier) Verify() bool {
+	for start := 0; start < len(v.entries); start += v.batchSize {
+		end := start + v.batchSize
+		if end > len(v.entries) {
+			end = len(v.entries)
+		}
+		if !BatchVerify(v.entries[start:end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isolateInvalid returns the indices of the invalid signatures in the provided
+// entries offset by the provided amount by recursively splitting them in half
+// until the halves are either valid or consist of a single invalid signature.
+func isolateInvalid(entries []BatchEntry, offset int, invalid []int) []int {
+	if BatchVerify(entries) {
+		return invalid
+	}
+	if len(entries) == 1 {
+		return append(invalid, offset)
+	}
+	mid := len(entries) / 2
+	invalid = isolateInvalid(entries[:mid], offset, invalid)
+	return isolateInvalid(entries[mid:], offset+mid, invalid)
+}
+
+// InvalidSignatures returns the indices, in the order they were added, of the
+// signatures added to the batch verifier that are not valid.  It returns nil
+// when all of them are valid.
+//
+// The signatures are verified in batches and any batches that fail are split
+// to isolate the invalid signatures.
+func (v *BatchVerifier) InvalidSignatures() []int {
+	var invalid []int
+	for start := 0; start < len(v.entries); start += v.batchSize {
+		end := start + v.batchSize
+		if end > len(v.entries) {
+			end = len(v.entries)
+		}
+		invalid = isolateInvalid(v.entries[start:end], start, invalid)
+	}
+	return invalid
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// randBatchEntries returns the requested number of entries with valid
+// signatures for random hashes created with random private keys.
+func randBatchEntries(t testing.TB, rng *rand.Rand, num int) []BatchEntry {
+	t.Helper()
+
+	entries := make([]BatchEntry, 0, num)
+	for i := 0; i < num; i++ {
+		var buf [32]byte
+		if _, err := rng.Read(buf[:]); err != nil {
+			t.Fatalf("failed to read random private key: %v", err)
+		}
+		var privKeyScalar secp256k1.ModNScalar
+		privKeyScalar.SetBytes(&buf)
+		privKey := secp256k1.NewPrivateKey(&privKeyScalar)
+
+		hash := make([]byte, 32)
+		if _, err := rng.Read(hash); err != nil {
+			t.Fatalf("failed to read random hash: %v", err)
+		}
+		sig, err := Sign(privKey, hash)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		entries = append(entries, BatchEntry{Sig: sig, Hash: hash,
+			PubKey: privKey.PubKey()})
+	}
+	return entries
+}
+
+// TestBatchVerify ensures batches of signatures are verified as expected,
+// including that batches that contain any invalid signatures are rejected and
+// that the invalid signatures are isolated.
+func TestBatchVerify(t *testing.T) {
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	// Ensure empty and valid batches of various sizes are accepted.
+	entries := randBatchEntries(t, rng, 40)
+	for _, num := range []int{0, 1, 2, 3, 17, 40} {
+		if !BatchVerify(entries[:num]) {
+			t.Fatalf("failed to verify valid batch of %d signatures", num)
+		}
+	}
+
+	// corrupt returns a copy of the provided entry with a random bit flipped
+	// in either its signature or its hash.
+	corrupt := func(entry BatchEntry) BatchEntry {
+		if rng.Intn(2) == 0 {
+			hash := make([]byte, len(entry.Hash))
+			copy(hash, entry.Hash)
+			hash[rng.Intn(len(hash))] ^= 1 << rng.Intn(8)
+			entry.Hash = hash
+			return entry
+		}
+		sigBytes := entry.Sig.Serialize()
+		for {
+			sigBytes[rng.Intn(len(sigBytes))] ^= 1 << rng.Intn(8)
+			if sig, err := ParseSignature(sigBytes); err == nil {
+				entry.Sig = sig
+				return entry
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string // test description
+		batchSize int    // batch size of the verifier
+		invalid   []int  // indices of the signatures to make invalid
+	}{{
+		name:      "all valid",
+		batchSize: 8,
+		invalid:   nil,
+	}, {
+		name:      "first invalid",
+		batchSize: 8,
+		invalid:   []int{0},
+	}, {
+		name:      "last invalid",
+		batchSize: 8,
+		invalid:   []int{39},
+	}, {
+		name:      "several invalid across batches",
+		batchSize: 8,
+		invalid:   []int{3, 4, 15, 16, 30},
+	}, {
+		name:      "all invalid in one batch",
+		batchSize: 7,
+		invalid:   []int{7, 8, 9, 10, 11, 12, 13},
+	}, {
+		name:      "default batch size",
+		batchSize: 0,
+		invalid:   []int{21},
+	}, {
+		name:      "single signature batches",
+		batchSize: 1,
+		invalid:   []int{5, 6},
+	}}
+
+	for _, test := range tests {
+		verifier := NewBatchVerifier(test.batchSize)
+		batch := make([]BatchEntry, len(entries))
+		copy(batch, entries)
+		for _, idx := range test.invalid {
+			batch[idx] = corrupt(batch[idx])
+		}
+		for _, entry := range batch {
+			verifier.Add(entry.Sig, entry.Hash, entry.PubKey)
+		}
+		if verifier.Len() != len(batch) {
+			t.Fatalf("%q: mismatched length -- got %d, want %d", test.name,
+				verifier.Len(), len(batch))
+		}
+
+		wantValid := len(test.invalid) == 0
+		if got := BatchVerify(batch); got != wantValid {
+			t.Errorf("%q: mismatched batch result -- got %v, want %v",
+				test.name, got, wantValid)
+			continue
+		}
+		if got := verifier.Verify(); got != wantValid {
+			t.Errorf("%q: mismatched verifier result -- got %v, want %v",
+				test.name, got, wantValid)
+			continue
+		}
+		got := verifier.InvalidSignatures()
+		if !reflect.DeepEqual(got, test.invalid) {
+			t.Errorf("%q: mismatched invalid signatures -- got %v, want %v",
+				test.name, got, test.invalid)
+			continue
+		}
+
+		// Ensure the verifier is empty after it is reset.
+		verifier.Reset()
+		if verifier.Len() != 0 || !verifier.Verify() {
+			t.Errorf("%q: verifier is not empty after reset", test.name)
+		}
+	}
+}
+
+// TestBatchVerifyErrors ensures signatures that fail individual verification
+// for specific reasons are also rejected when they are part of a batch of
+// otherwise valid signatures.
+func TestBatchVerifyErrors(t *testing.T) {
+	tests := []struct {
+		name string // test description
+		sigR string // hex encoded r component of signature to verify against
+		sigS string // hex encoded s component of signature to verify against
+		hash string // hex encoded hash of message to verify
+		pubX string // hex encoded x component of pubkey to verify against
+		pubY string // hex encoded y component of pubkey to verify against
+	}{{
+		// Signature created from private key 0x01, blake256(0x40) and removing
+		// the leading zero byte.  It is otherwise valid.
+		name: "hash too short",
+		sigR: "938de23d0785c7d4775f47bbcadaa2a56447dd98029c8196f2bbed0ab4b8457f",
+		sigS: "7de65bf205e14f81e5f75ad2fd80ea715a391f7b51e10fa43f0a1961039b1a6c",
+		hash: "0e0f08e2ee912478b77004ec62845b5e01418f03837b76cbdc8b1fb0480322",
+		pubX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		pubY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}, {
+		// Signature created from private key 0x01, blake256(0x01020304) over
+		// the secp256r1 curve (note the r1 instead of k1).
+		name: "pubkey not on the curve, signature valid for secp256r1 instead",
+		sigR: "c6c62660176b3daa90dbf4d7e21d9406ce93895771a16c7c5c91258a9b522174",
+		sigS: "f5b5583956a6b30e18ff5e865c77a8c4adf47b147d11ea3822b4de63c9f7b909",
+		hash: "c301ba9de5d6053caad9f5eb46523f007702add2c62fa39de03146a36b8026b7",
+		pubX: "6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296",
+		pubY: "4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5",
+	}, {
+		// Signature created from private key 0x01, blake256(0x01020304) and
+		// manually setting s = -ed.
+		name: "calculated R point at infinity",
+		sigR: "4c68976afe187ff0167919ad181cb30f187e2af1c8233b2cbebbbe0fc97fff61",
+		sigS: "14cc9e0544dd8fe6baa7c20fd2a141d0ee60114c419377efc850a49bd5c1ed36",
+		hash: "c301ba9de5d6053caad9f5eb46523f007702add2c62fa39de03146a36b8026b7",
+		pubX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		pubY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}, {
+		// Signature created from private key 0x01, blake256(0x01020304050607).
+		// It is otherwise valid.
+		name: "odd R",
+		sigR: "2c2c71f7bf3e183238b1f20d856e068dc6d37805c8b2d872d0f23d906bc95789",
+		sigS: "eb7670ca6ff95c1d5c6785bc72e0781f27c9778758317d82d3053fdbcc9c17b0",
+		hash: "ccf8c53a7631aad469d412963d495c729ff219dd2ae9a0c4de4bd1b4c777d49c",
+		pubX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		pubY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}, {
+		// Signature created from private key 0x01, blake256(0x01020304).  Thus,
+		// it is valid for that message.  Attempting to verify wrong message
+		// blake256(0x01020307).
+		name: "mismatched R",
+		sigR: "4c68976afe187ff0167919ad181cb30f187e2af1c8233b2cbebbbe0fc97fff61",
+		sigS: "e9ae2d0e306497236d4e328dc1a34244045745e87da69d806859348bc2a74525",
+		hash: "d4f9aea8c329f57a81397f0418269a8bd495957ea56ae0af0dfa886fb5977046",
+		pubX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		pubY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}, {
+		// Signature invented with an r value that is not the x coordinate of
+		// any point on the curve.
+		name: "r not on the curve",
+		sigR: "0000000000000000000000000000000000000000000000000000000000000005",
+		sigS: "e9ae2d0e306497236d4e328dc1a34244045745e87da69d806859348bc2a74525",
+		hash: "c301ba9de5d6053caad9f5eb46523f007702add2c62fa39de03146a36b8026b7",
+		pubX: "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		pubY: "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	}}
+
+	rng := rand.New(rand.NewSource(1))
+	valid := randBatchEntries(t, rng, 4)
+	for _, test := range tests {
+		pubX, pubY := hexToFieldVal(test.pubX), hexToFieldVal(test.pubY)
+		entry := BatchEntry{
+			Sig:    NewSignature(hexToFieldVal(test.sigR), hexToModNScalar(test.sigS)),
+			Hash:   hexToBytes(test.hash),
+			PubKey: secp256k1.NewPublicKey(pubX, pubY),
+		}
+		if entry.Sig.Verify(entry.Hash, entry.PubKey) {
+			t.Fatalf("%q: signature unexpectedly verified", test.name)
+		}
+
+		// Ensure the signature is rejected both on its own and as part of a
+		// batch of otherwise valid signatures.
+		if BatchVerify([]BatchEntry{entry}) {
+			t.Errorf("%q: verified invalid signature on its own", test.name)
+			continue
+		}
+		batch := append([]BatchEntry{entry}, valid...)
+		batch[0], batch[2] = batch[2], batch[0]
+		if BatchVerify(batch) {
+			t.Errorf("%q: verified batch with invalid signature", test.name)
+			continue
+		}
+		verifier := NewBatchVerifier(0)
+		for _, entry := range batch {
+			verifier.Add(entry.Sig, entry.Hash, entry.PubKey)
+		}
+		got := verifier.InvalidSignatures()
+		if !reflect.DeepEqual(got, []int{2}) {
+			t.Errorf("%q: mismatched invalid signatures -- got %v, want [2]",
+				test.name, got)
+		}
+	}
+}
@@ -75,6 +75,14 @@ scheme:
 See the README.md file for the specific details of the signing and verification
 algorithm as well as the signature serialization format.
 
+# Batch Verification
+
+BatchVerify verifies multiple signatures together by checking a random linear
+combination of their verification equations, which is faster than verifying
+each of them individually.  BatchVerifier builds on it by verifying signatures
+in batches of a configurable maximum size and isolating the invalid signatures
+in any batches that fail.
+
 # Future Design Considerations
 
 It is worth noting that there are some additional optimizations and
@@ -6,6 +6,7 @@ package schnorr
 
 import (
 	"encoding/hex"
+	"math/rand"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -113,3 +114,16 @@ func BenchmarkSigSerialize(b *testing.B) {
 		sig.Serialize()
 	}
 }
+
+// BenchmarkBatchVerify benchmarks how long it takes to verify a batch of
+// Schnorr signatures of the default batch size.
+func BenchmarkBatchVerify(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	entries := randBatchEntries(b, rng, DefaultBatchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(entries)
+	}
+}
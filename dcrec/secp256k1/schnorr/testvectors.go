@@ -0,0 +1,96 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// TestVector describes a single EC-Schnorr-DCRv0 signature verification test
+// vector suitable for validating other implementations.  All byte fields are
+// hex encoded and the JSON field names are stable.
+type TestVector struct {
+	// PrivKey is the 32-byte private key used to produce the signature.
+	PrivKey string `json:"privkey"`
+
+	// PubKey is the 33-byte compressed public key to verify the signature
+	// against.
+	PubKey string `json:"pubkey"`
+
+	// Hash is the 32-byte message hash the signature commits to.
+	Hash string `json:"hash"`
+
+	// Signature is the 64-byte serialized signature.
+	Signature string `json:"signature"`
+
+	// Valid specifies whether or not the signature is valid for the public key
+	// and hash.
+	Valid bool `json:"valid"`
+
+	// Comment describes the test vector.
+	Comment string `json:"comment"`
+}
+
+// testVectorBytes returns 32 bytes that are deterministically derived from the
+// provided seed, label, and index.
+func testVectorBytes(seed []byte, label string, index uint32) [32]byte {
+	var idx [4]byte
+	binary.LittleEndian.PutUint32(idx[:], index)
+	h := blake256.New()
+	h.Write(seed)
+	h.Write([]byte(label))
+	h.Write(idx[:])
+	var result [32]byte
+	copy(result[:], h.Sum(nil))
+	return result
+}
+
+// GenerateTestVectors returns the provided number of signature test vectors
+// deterministically derived from the provided seed.  The same seed and count
+// always produce the same vectors.
+//
+// The vectors cycle through valid signatures, signatures that commit to a
+// different message hash, and signatures with a modified s value so that both
+// acceptance and rejection are covered.
+func GenerateTestVectors(seed []byte, count int) []TestVector {
+	vectors := make([]TestVector, 0, count)
+	for i := uint32(0); i < uint32(count); i++ {
+		privKeyBytes := testVectorBytes(seed, "privkey", i)
+		privKey := secp256k1.PrivKeyFromBytes(privKeyBytes[:])
+		hash := testVectorBytes(seed, "hash", i)
+		sig, err := Sign(privKey, hash[:])
+		if err != nil {
+			// The probability of this happening is negligible, but skip the
+			// vector rather than producing an incorrect one.
+			continue
+		}
+
+		valid, comment := true, "valid signature"
+		switch i % 3 {
+		case 1:
+			hash[0] ^= 0x01
+			valid, comment = false, "signature for a different hash"
+		case 2:
+			var one secp256k1.ModNScalar
+			one.SetInt(1)
+			sig.s.Add(&one)
+			valid, comment = false, "signature with modified s"
+		}
+
+		vectors = append(vectors, TestVector{
+			PrivKey:   hex.EncodeToString(privKeyBytes[:]),
+			PubKey:    hex.EncodeToString(privKey.PubKey().SerializeCompressed()),
+			Hash:      hex.EncodeToString(hash[:]),
+			Signature: hex.EncodeToString(sig.Serialize()),
+			Valid:     valid,
+			Comment:   comment,
+		})
+	}
+	return vectors
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// TestGenerateTestVectors ensures the generated signature test vectors are
+// deterministic and that verifying them produces the expected results.
+func TestGenerateTestVectors(t *testing.T) {
+	seed := []byte("schnorr test vectors")
+	vectors := GenerateTestVectors(seed, 30)
+	if len(vectors) != 30 {
+		t.Fatalf("unexpected number of vectors -- got %d, want 30",
+			len(vectors))
+	}
+	if !reflect.DeepEqual(vectors, GenerateTestVectors(seed, 30)) {
+		t.Fatal("generated vectors are not deterministic")
+	}
+	if reflect.DeepEqual(vectors, GenerateTestVectors([]byte("other"), 30)) {
+		t.Fatal("generated vectors do not depend on the seed")
+	}
+
+	for i, vector := range vectors {
+		pubKeyBytes, err := hex.DecodeString(vector.PubKey)
+		if err != nil {
+			t.Fatalf("vector #%d: bad pubkey hex: %v", i, err)
+		}
+		pubKey, err := ParsePubKey(pubKeyBytes)
+		if err != nil {
+			t.Fatalf("vector #%d: bad pubkey: %v", i, err)
+		}
+		hash, err := hex.DecodeString(vector.Hash)
+		if err != nil {
+			t.Fatalf("vector #%d: bad hash hex: %v", i, err)
+		}
+		sigBytes, err := hex.DecodeString(vector.Signature)
+		if err != nil {
+			t.Fatalf("vector #%d: bad signature hex: %v", i, err)
+		}
+
+		// Signatures with a modified s may fail to parse in the unlikely event
+		// s overflows the group order, which is treated as invalid.
+		sig, err := ParseSignature(sigBytes)
+		valid := err == nil && sig.Verify(hash, pubKey)
+		if valid != vector.Valid {
+			t.Errorf("vector #%d (%s): unexpected verify result -- got %v, "+
+				"want %v", i, vector.Comment, valid, vector.Valid)
+		}
+	}
+}
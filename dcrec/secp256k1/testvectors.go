@@ -0,0 +1,57 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/decred/dcrd/crypto/blake256"
+)
+
+// ECDHTestVector describes a single ECDH shared secret test vector suitable
+// for validating other implementations.  All byte fields are hex encoded and
+// the JSON field names are stable.
+type ECDHTestVector struct {
+	// PrivKey is the 32-byte private key of the local party.
+	PrivKey string `json:"privkey"`
+
+	// PubKey is the 33-byte compressed public key of the remote party.
+	PubKey string `json:"pubkey"`
+
+	// SharedSecret is the 32-byte shared secret as returned by
+	// GenerateSharedSecret.
+	SharedSecret string `json:"sharedsecret"`
+}
+
+// testVectorKey returns a private key that is deterministically derived from
+// the provided seed, label, and index.
+func testVectorKey(seed []byte, label string, index uint32) *PrivateKey {
+	var idx [4]byte
+	binary.LittleEndian.PutUint32(idx[:], index)
+	h := blake256.New()
+	h.Write(seed)
+	h.Write([]byte(label))
+	h.Write(idx[:])
+	return PrivKeyFromBytes(h.Sum(nil))
+}
+
+// GenerateECDHTestVectors returns the provided number of ECDH test vectors
+// deterministically derived from the provided seed.  The same seed and count
+// always produce the same vectors.
+func GenerateECDHTestVectors(seed []byte, count int) []ECDHTestVector {
+	vectors := make([]ECDHTestVector, 0, count)
+	for i := uint32(0); i < uint32(count); i++ {
+		privKey := testVectorKey(seed, "local", i)
+		remotePubKey := testVectorKey(seed, "remote", i).PubKey()
+		privKeyBytes := privKey.Serialize()
+		vectors = append(vectors, ECDHTestVector{
+			PrivKey:      hex.EncodeToString(privKeyBytes),
+			PubKey:       hex.EncodeToString(remotePubKey.SerializeCompressed()),
+			SharedSecret: hex.EncodeToString(GenerateSharedSecret(privKey, remotePubKey)),
+		})
+	}
+	return vectors
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// TestGenerateECDHTestVectors ensures the generated ECDH test vectors are
+// deterministic and that both parties derive the generated shared secret.
+func TestGenerateECDHTestVectors(t *testing.T) {
+	seed := []byte("ecdh test vectors")
+	vectors := GenerateECDHTestVectors(seed, 10)
+	if len(vectors) != 10 {
+		t.Fatalf("unexpected number of vectors -- got %d, want 10",
+			len(vectors))
+	}
+	if !reflect.DeepEqual(vectors, GenerateECDHTestVectors(seed, 10)) {
+		t.Fatal("generated vectors are not deterministic")
+	}
+
+	for i, vector := range vectors {
+		privKeyBytes, err := hex.DecodeString(vector.PrivKey)
+		if err != nil {
+			t.Fatalf("vector #%d: bad privkey hex: %v", i, err)
+		}
+		privKey := PrivKeyFromBytes(privKeyBytes)
+		pubKeyBytes, err := hex.DecodeString(vector.PubKey)
+		if err != nil {
+			t.Fatalf("vector #%d: bad pubkey hex: %v", i, err)
+		}
+		pubKey, err := ParsePubKey(pubKeyBytes)
+		if err != nil {
+			t.Fatalf("vector #%d: bad pubkey: %v", i, err)
+		}
+		want, err := hex.DecodeString(vector.SharedSecret)
+		if err != nil {
+			t.Fatalf("vector #%d: bad shared secret hex: %v", i, err)
+		}
+
+		// Ensure the shared secret matches from both sides.
+		if got := GenerateSharedSecret(privKey, pubKey); !bytes.Equal(got, want) {
+			t.Errorf("vector #%d: unexpected shared secret -- got %x, want %x",
+				i, got, want)
+		}
+		remotePrivKey := testVectorKey(seed, "remote", uint32(i))
+		got := GenerateSharedSecret(remotePrivKey, privKey.PubKey())
+		if !bytes.Equal(got, want) {
+			t.Errorf("vector #%d: unexpected remote shared secret -- got %x, "+
+				"want %x", i, got, want)
+		}
+	}
+}
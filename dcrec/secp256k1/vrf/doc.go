@@ -0,0 +1,40 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package vrf provides a verifiable random function (VRF) via secp256k1.
+
+A VRF is the public-key version of a keyed cryptographic hash.  Only the holder
+of a private key is able to compute the output of the function for a given
+input, however, anyone with the corresponding public key is able to verify the
+output was computed correctly by means of a proof that accompanies it.
+
+Since the output is unique for a given public key and input and is
+indistinguishable from random to anyone without the private key, VRFs are
+useful as the basis for protocols that require unbiased and unpredictable
+randomness, such as lotteries and randomness beacons, where the participants
+must not be able to grind for favorable outcomes.
+
+# ECVRF-SECP256K1-SHA256-TAI
+
+This package implements the elliptic curve VRF (ECVRF) construction specified by
+RFC 9381 instantiated with the secp256k1 curve, SHA-256, and the
+try-and-increment method of hashing to the curve.  The cipher suite is
+identified by the suite string 0xfe.  The nonces are generated
+deterministically per RFC 6979 as specified by RFC 9381.
+
+Proofs are serialized as the 33-byte compressed gamma point followed by the
+16-byte challenge and the 32-byte scalar s, for a total of 81 bytes.  This is
+well below the maximum allowed size of data pushes in scripts, so serialized
+proofs may be included in transaction scripts by means of the typical data push
+helpers.  The output of the VRF is 32 bytes.
+
+# Usage
+
+A proof is produced for an input with Prove and serialized with Serialize.
+Verifiers parse the proof with ParseProof, verify it against the public key and
+input with Verify, and then obtain the output with Output.  The output must not
+be trusted unless the proof is verified.
+*/
+package vrf
@@ -0,0 +1,64 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vrf
+
+// ErrorKind identifies a kind of error.  It has full support for errors.Is
+// and errors.As, so the caller can directly check against an error kind
+// when determining the reason for an error.
+type ErrorKind string
+
+// These constants are used to identify a specific Error.
+const (
+	// ErrPrivateKeyIsZero indicates an attempt was made to produce a proof
+	// with a private key that is equal to zero.
+	ErrPrivateKeyIsZero = ErrorKind("ErrPrivateKeyIsZero")
+
+	// ErrHashToCurveFailed indicates the input could not be hashed to a point
+	// on the curve within the allowed number of attempts.  The probability of
+	// this happening is negligible.
+	ErrHashToCurveFailed = ErrorKind("ErrHashToCurveFailed")
+
+	// ErrProofTooShort is returned when a proof is too short.
+	ErrProofTooShort = ErrorKind("ErrProofTooShort")
+
+	// ErrProofTooLong is returned when a proof is too long.
+	ErrProofTooLong = ErrorKind("ErrProofTooLong")
+
+	// ErrProofGammaInvalid is returned when a proof has a gamma point that is
+	// not a valid compressed point on the curve.
+	ErrProofGammaInvalid = ErrorKind("ErrProofGammaInvalid")
+
+	// ErrProofSTooBig is returned when a proof has s with a value that is
+	// greater than or equal to the group order.
+	ErrProofSTooBig = ErrorKind("ErrProofSTooBig")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an error related to a VRF proof.  It has full support for
+// errors.Is and errors.As, so the caller can ascertain the specific reason for
+// the error by checking the underlying error.
+type Error struct {
+	Err         error
+	Description string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// proofError creates an Error given a set of arguments.
+func proofError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
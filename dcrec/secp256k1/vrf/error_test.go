@@ -0,0 +1,92 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vrf
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestErrorKindStringer tests the stringized output for the ErrorKind type.
+func TestErrorKindStringer(t *testing.T) {
+	tests := []struct {
+		in   ErrorKind
+		want string
+	}{
+		{ErrPrivateKeyIsZero, "ErrPrivateKeyIsZero"},
+		{ErrHashToCurveFailed, "ErrHashToCurveFailed"},
+		{ErrProofTooShort, "ErrProofTooShort"},
+		{ErrProofTooLong, "ErrProofTooLong"},
+		{ErrProofGammaInvalid, "ErrProofGammaInvalid"},
+		{ErrProofSTooBig, "ErrProofSTooBig"},
+	}
+
+	for i, test := range tests {
+		result := test.in.Error()
+		if result != test.want {
+			t.Errorf("#%d: got: %s want: %s", i, result, test.want)
+			continue
+		}
+	}
+}
+
+// TestErrorKindIsAs ensures both ErrorKind and Error can be identified
+// as being a specific error via errors.Is and unwrapped via errors.As.
+func TestErrorKindIsAs(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		target    error
+		wantMatch bool
+		wantAs    ErrorKind
+	}{{
+		name:      "ErrProofTooShort == ErrProofTooShort",
+		err:       ErrProofTooShort,
+		target:    ErrProofTooShort,
+		wantMatch: true,
+		wantAs:    ErrProofTooShort,
+	}, {
+		name:      "Error.ErrProofTooShort == ErrProofTooShort",
+		err:       proofError(ErrProofTooShort, ""),
+		target:    ErrProofTooShort,
+		wantMatch: true,
+		wantAs:    ErrProofTooShort,
+	}, {
+		name:      "ErrProofTooShort != ErrProofTooLong",
+		err:       ErrProofTooShort,
+		target:    ErrProofTooLong,
+		wantMatch: false,
+		wantAs:    ErrProofTooShort,
+	}, {
+		name:      "Error.ErrProofTooShort != ErrProofTooLong",
+		err:       proofError(ErrProofTooShort, ""),
+		target:    ErrProofTooLong,
+		wantMatch: false,
+		wantAs:    ErrProofTooShort,
+	}}
+
+	for _, test := range tests {
+		// Ensure the error matches or not depending on the expected result.
+		result := errors.Is(test.err, test.target)
+		if result != test.wantMatch {
+			t.Errorf("%s: incorrect error identification -- got %v, want %v",
+				test.name, result, test.wantMatch)
+			continue
+		}
+
+		// Ensure the underlying error kind can be unwrapped and is the
+		// expected kind.
+		var kind ErrorKind
+		if !errors.As(test.err, &kind) {
+			t.Errorf("%s: unable to unwrap to error kind", test.name)
+			continue
+		}
+		if kind != test.wantAs {
+			t.Errorf("%s: unexpected unwrapped error kind -- got %v, want %v",
+				test.name, kind, test.wantAs)
+			continue
+		}
+	}
+}
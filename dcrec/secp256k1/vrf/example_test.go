@@ -0,0 +1,55 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vrf_test
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/vrf"
+)
+
+// This example demonstrates producing a VRF proof for an input with a private
+// key, serializing it, and verifying it with the public key in order to obtain
+// the verified output.
+func Example_proveVerify() {
+	// Decode a hex-encoded private key.
+	pkBytes, err := hex.DecodeString("22a47fa09a223f2aa079edf85a7c2d4f8720ee6" +
+		"3e502ee2869afab7de234b80c")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	privKey := secp256k1.PrivKeyFromBytes(pkBytes)
+	pubKey := privKey.PubKey()
+
+	// Produce a proof for an input such as the hash of a block and serialize
+	// it so it can be published, for example in a script data push.
+	input := []byte("input such as a block hash")
+	proof, err := vrf.Prove(privKey, input)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	serializedProof := proof.Serialize()
+	fmt.Printf("Serialized proof size: %d\n", len(serializedProof))
+
+	// Parse the proof and verify it to obtain the output.
+	parsedProof, err := vrf.ParseProof(serializedProof)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	verified := parsedProof.Verify(pubKey, input)
+	output := parsedProof.Output()
+	fmt.Printf("Proof verified: %v\n", verified)
+	fmt.Printf("Output matches: %v\n", output == proof.Output())
+
+	// Output:
+	// Serialized proof size: 81
+	// Proof verified: true
+	// Output matches: true
+}
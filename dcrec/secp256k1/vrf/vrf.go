@@ -0,0 +1,272 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vrf
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// ProofSize is the size of a serialized proof.  It consists of the
+	// compressed gamma point, the challenge, and the scalar s.
+	ProofSize = pointSize + challengeSize + scalarSize
+
+	// OutputSize is the size of the output of the VRF.
+	OutputSize = sha256.Size
+
+	// suiteString is the identifier of the ECVRF-SECP256K1-SHA256-TAI cipher
+	// suite that is mixed into all hashes.
+	suiteString = 0xfe
+
+	// pointSize is the size of a compressed point.
+	pointSize = 33
+
+	// challengeSize is the size of the challenge.
+	challengeSize = 16
+
+	// scalarSize is the size of an encoded big endian scalar.
+	scalarSize = 32
+
+	// Domain separators of the hashes as specified by RFC 9381.
+	hashToCurveDomain = 0x01
+	challengeDomain   = 0x02
+	proofToHashDomain = 0x03
+	domainSepBack     = 0x00
+)
+
+// Proof is a type representing a VRF proof.  It proves that the output of the
+// VRF was correctly computed from an input by the holder of the private key
+// that corresponds to a public key.
+type Proof struct {
+	gamma secp256k1.JacobianPoint
+	c     secp256k1.ModNScalar
+	s     secp256k1.ModNScalar
+}
+
+// pointToString returns the compressed encoding of the provided point which
+// must be in affine coordinates.
+func pointToString(p *secp256k1.JacobianPoint) []byte {
+	return secp256k1.NewPublicKey(&p.X, &p.Y).SerializeCompressed()
+}
+
+// isInfinity returns whether or not the provided point is the point at
+// infinity.
+func isInfinity(p *secp256k1.JacobianPoint) bool {
+	return (p.X.IsZero() && p.Y.IsZero()) || p.Z.IsZero()
+}
+
+// hashToCurve hashes the provided public key and input to a point on the curve
+// using the try-and-increment method as specified by RFC 9381.  The resulting
+// point is in affine coordinates.
+func hashToCurve(pubKey *secp256k1.PublicKey, alpha []byte) (*secp256k1.JacobianPoint, error) {
+	pubKeyBytes := pubKey.SerializeCompressed()
+	h := sha256.New()
+	for ctr := 0; ctr < 256; ctr++ {
+		h.Reset()
+		h.Write([]byte{suiteString, hashToCurveDomain})
+		h.Write(pubKeyBytes)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), domainSepBack})
+		var x [32]byte
+		copy(x[:], h.Sum(nil))
+
+		// Interpret the hash as the x coordinate of a point with an even y
+		// coordinate and try again when it is not on the curve.
+		var point secp256k1.JacobianPoint
+		if overflow := point.X.SetBytes(&x); overflow != 0 {
+			continue
+		}
+		if !secp256k1.DecompressY(&point.X, false, &point.Y) {
+			continue
+		}
+		point.Y.Normalize()
+		point.Z.SetInt(1)
+		return &point, nil
+	}
+
+	return nil, proofError(ErrHashToCurveFailed, "unable to hash input to "+
+		"a point on the curve")
+}
+
+// challenge returns the challenge for the provided points which must all be in
+// affine coordinates.
+func challenge(points ...*secp256k1.JacobianPoint) secp256k1.ModNScalar {
+	h := sha256.New()
+	h.Write([]byte{suiteString, challengeDomain})
+	for _, point := range points {
+		h.Write(pointToString(point))
+	}
+	h.Write([]byte{domainSepBack})
+
+	// The challenge is the first challengeSize bytes of the hash interpreted
+	// as a big endian integer, which is always less than the group order.
+	var c secp256k1.ModNScalar
+	c.SetByteSlice(h.Sum(nil)[:challengeSize])
+	return c
+}
+
+// Prove returns a proof of the VRF output for the provided input using the
+// provided private key.  The proof is deterministic, so the same private key
+// and input always produce the same proof and output.
+//
+// The output is obtained from the proof via Output and the proof is verified
+// with the corresponding public key via Verify.
+func Prove(privKey *secp256k1.PrivateKey, alpha []byte) (*Proof, error) {
+	x := &privKey.Key
+	if x.IsZero() {
+		return nil, proofError(ErrPrivateKeyIsZero, "private key is zero")
+	}
+
+	// H = encode_to_curve(Y, alpha)
+	// Gamma = x*H
+	pubKey := privKey.PubKey()
+	h, err := hashToCurve(pubKey, alpha)
+	if err != nil {
+		return nil, err
+	}
+	var proof Proof
+	secp256k1.ScalarMultNonConst(x, h, &proof.gamma)
+	proof.gamma.ToAffine()
+
+	// k = RFC6979 nonce with the private key and the hash of H
+	// U = k*B
+	// V = k*H
+	hHash := sha256.Sum256(pointToString(h))
+	privKeyBytes := x.Bytes()
+	k := secp256k1.NonceRFC6979(privKeyBytes[:], hHash[:], nil, nil, 0)
+	zeroArray(&privKeyBytes)
+	var u, v, y secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(k, &u)
+	secp256k1.ScalarMultNonConst(k, h, &v)
+	u.ToAffine()
+	v.ToAffine()
+	pubKey.AsJacobian(&y)
+
+	// c = challenge(Y, H, Gamma, U, V)
+	// s = k + c*x mod n
+	proof.c = challenge(&y, h, &proof.gamma, &u, &v)
+	proof.s.Mul2(&proof.c, x).Add(k)
+	k.Zero()
+	return &proof, nil
+}
+
+// zeroArray zeroes the memory of a scalar array.
+func zeroArray(a *[scalarSize]byte) {
+	for i := 0; i < scalarSize; i++ {
+		a[i] = 0x00
+	}
+}
+
+// Serialize returns the proof encoded as the compressed gamma point followed by
+// the 16-byte challenge and the 32-byte scalar s, both big endian, for a total
+// of ProofSize bytes.
+//
+// The size of the serialized proof is well below the maximum allowed size of
+// data pushes in scripts, so it may be included in scripts by the data push
+// helpers such as AddData of the script builder of the txscript module.
+func (p *Proof) Serialize() []byte {
+	var b [ProofSize]byte
+	copy(b[:pointSize], pointToString(&p.gamma))
+	cBytes := p.c.Bytes()
+	copy(b[pointSize:], cBytes[scalarSize-challengeSize:])
+	p.s.PutBytesUnchecked(b[pointSize+challengeSize:])
+	return b[:]
+}
+
+// ParseProof parses a proof that was serialized with Serialize and enforces
+// the following additional restrictions:
+//   - The proof must be exactly ProofSize bytes
+//   - The gamma point must be a valid compressed point on the curve
+//   - The scalar s must be less than the order of the secp256k1 curve
+func ParseProof(proof []byte) (*Proof, error) {
+	if len(proof) < ProofSize {
+		str := fmt.Sprintf("malformed proof: too short: %d < %d", len(proof),
+			ProofSize)
+		return nil, proofError(ErrProofTooShort, str)
+	}
+	if len(proof) > ProofSize {
+		str := fmt.Sprintf("malformed proof: too long: %d > %d", len(proof),
+			ProofSize)
+		return nil, proofError(ErrProofTooLong, str)
+	}
+
+	// The gamma point must be a valid compressed point.  Note that the
+	// format is checked first since the public key parser also accepts other
+	// formats.
+	var p Proof
+	format := proof[0]
+	if format != secp256k1.PubKeyFormatCompressedEven &&
+		format != secp256k1.PubKeyFormatCompressedOdd {
+
+		str := fmt.Sprintf("invalid proof: gamma has unsupported format %#x",
+			format)
+		return nil, proofError(ErrProofGammaInvalid, str)
+	}
+	gamma, err := secp256k1.ParsePubKey(proof[:pointSize])
+	if err != nil {
+		str := fmt.Sprintf("invalid proof: gamma is not a valid point: %v",
+			err)
+		return nil, proofError(ErrProofGammaInvalid, str)
+	}
+	gamma.AsJacobian(&p.gamma)
+	p.c.SetByteSlice(proof[pointSize : pointSize+challengeSize])
+	if overflow := p.s.SetByteSlice(proof[pointSize+challengeSize:]); overflow {
+		str := "invalid proof: s >= group order"
+		return nil, proofError(ErrProofSTooBig, str)
+	}
+	return &p, nil
+}
+
+// Output returns the output of the VRF the proof commits to.  It must only be
+// trusted after the proof is verified with Verify.
+func (p *Proof) Output() [OutputSize]byte {
+	// beta = Hash(suite_string || 0x03 || point_to_string(Gamma) || 0x00)
+	//
+	// Note that the cofactor of secp256k1 is one.
+	h := sha256.New()
+	h.Write([]byte{suiteString, proofToHashDomain})
+	h.Write(pointToString(&p.gamma))
+	h.Write([]byte{domainSepBack})
+	var output [OutputSize]byte
+	copy(output[:], h.Sum(nil))
+	return output
+}
+
+// Verify returns whether or not the proof is valid for the provided input and
+// public key.  The output of the VRF is obtained from a valid proof via Output.
+func (p *Proof) Verify(pubKey *secp256k1.PublicKey, alpha []byte) bool {
+	if !pubKey.IsOnCurve() {
+		return false
+	}
+	h, err := hashToCurve(pubKey, alpha)
+	if err != nil {
+		return false
+	}
+
+	// U = s*B - c*Y
+	// V = s*H - c*Gamma
+	var y, u, v, sB, cY, sH, cGamma secp256k1.JacobianPoint
+	var negC secp256k1.ModNScalar
+	negC.NegateVal(&p.c)
+	pubKey.AsJacobian(&y)
+	secp256k1.ScalarBaseMultNonConst(&p.s, &sB)
+	secp256k1.ScalarMultNonConst(&negC, &y, &cY)
+	secp256k1.AddNonConst(&sB, &cY, &u)
+	secp256k1.ScalarMultNonConst(&p.s, h, &sH)
+	secp256k1.ScalarMultNonConst(&negC, &p.gamma, &cGamma)
+	secp256k1.AddNonConst(&sH, &cGamma, &v)
+	if isInfinity(&u) || isInfinity(&v) {
+		return false
+	}
+	u.ToAffine()
+	v.ToAffine()
+
+	// The proof is valid when c == challenge(Y, H, Gamma, U, V).
+	c := challenge(&y, h, &p.gamma, &u, &v)
+	return c.Equals(&p.c)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vrf
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected. It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestProve ensures proofs and outputs are produced deterministically and match
+// the expected values and that the proofs verify.
+//
+// The expected values were produced by this implementation and serve to detect
+// any unintentional changes to the construction.
+func TestProve(t *testing.T) {
+	tests := []struct {
+		name   string // test description
+		key    string // hex encoded private key
+		alpha  []byte // input
+		pubKey string // expected hex encoded compressed public key
+		proof  string // expected hex encoded serialized proof
+		output string // expected hex encoded output
+	}{{
+		name:   "private key 1, empty input",
+		key:    "0000000000000000000000000000000000000000000000000000000000000001",
+		alpha:  nil,
+		pubKey: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		proof: "024192220588c4ef502f5d2ab75552edfbe0256cebb0424efb9c4c58f438c3dcb4" +
+			"3740e701a78589f13a3577908db37b1d" +
+			"db55edaf0706552da59a41b69be3740878407cf6d13675cd94802a33b5e629f7",
+		output: "6bf7eda22a89f87fb8c8e17fa111727ca02d0a23db29fdcbe7ac84280e8bde24",
+	}, {
+		name:   "input \"sample\"",
+		key:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		alpha:  []byte("sample"),
+		pubKey: "032c8c31fc9f990c6b55e3865a184a4ce50e09481f2eaeb3e60ec1cea13a6ae645",
+		proof: "0338ec99b5d0f94ebcc2c704c04af3de8b4289df8798e5fb9f920d7f5d77ac03d7" +
+			"718b9677d1c9348649ac2ec4f7ecbe51" +
+			"9b30dd10c4eb5efc21dd5944709f2f3b7e97a25f6f095334593502d05103bc5b",
+		output: "d466c22e14dc3b7fd169668dd3ee9ac6351429a24aebc5e8af61a0f0de89b65a",
+	}, {
+		name:   "private key n-1, input \"decred\"",
+		key:    "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+		alpha:  []byte("decred"),
+		pubKey: "0379be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		proof: "03379a2f7906b5f7a328103ab52c986de816b36fdad9f80697af13707908fd0fb7" +
+			"34dbde470e2733c9ae71374d03ffb11e" +
+			"d9a7cddaf5f59510b0eb690efdd36ad392a5c7e2538f33f8a7a3410be8011cbc",
+		output: "db51b7221358c7d70c2a7c7690d22edcec7fc7e620911bf449a92305f3c79c6a",
+	}}
+
+	for _, test := range tests {
+		privKey := secp256k1.PrivKeyFromBytes(hexToBytes(test.key))
+		pubKey := privKey.PubKey()
+		gotPubKey := hex.EncodeToString(pubKey.SerializeCompressed())
+		if gotPubKey != test.pubKey {
+			t.Errorf("%q: mismatched public key -- got %s, want %s", test.name,
+				gotPubKey, test.pubKey)
+			continue
+		}
+
+		proof, err := Prove(privKey, test.alpha)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		gotProof := hex.EncodeToString(proof.Serialize())
+		if gotProof != test.proof {
+			t.Errorf("%q: mismatched proof -- got %s, want %s", test.name,
+				gotProof, test.proof)
+			continue
+		}
+		output := proof.Output()
+		if gotOutput := hex.EncodeToString(output[:]); gotOutput != test.output {
+			t.Errorf("%q: mismatched output -- got %s, want %s", test.name,
+				gotOutput, test.output)
+			continue
+		}
+
+		// Ensure the proof verifies after a serialization round trip and
+		// commits to the same output.
+		parsed, err := ParseProof(hexToBytes(test.proof))
+		if err != nil {
+			t.Errorf("%q: unexpected parse error: %v", test.name, err)
+			continue
+		}
+		if !parsed.Verify(pubKey, test.alpha) {
+			t.Errorf("%q: proof does not verify", test.name)
+			continue
+		}
+		if parsed.Output() != output {
+			t.Errorf("%q: mismatched output after parsing", test.name)
+			continue
+		}
+	}
+}
+
+// TestProveZeroKey ensures attempting to produce a proof with a zero private
+// key fails with the expected error.
+func TestProveZeroKey(t *testing.T) {
+	var privKey secp256k1.PrivateKey
+	_, err := Prove(&privKey, []byte("sample"))
+	if !errors.Is(err, ErrPrivateKeyIsZero) {
+		t.Fatalf("mismatched error -- got %v, want %v", err,
+			ErrPrivateKeyIsZero)
+	}
+}
+
+// TestVerifyInvalid ensures proofs do not verify with other public keys or
+// inputs or when they are modified.
+func TestVerifyInvalid(t *testing.T) {
+	privKey := secp256k1.PrivKeyFromBytes(hexToBytes("c9afa9d845ba75166b5c215" +
+		"767b1d6934e50c3db36e89b127b8a622b120f6721"))
+	pubKey := privKey.PubKey()
+	alpha := []byte("sample")
+	proof, err := Prove(privKey, alpha)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !proof.Verify(pubKey, alpha) {
+		t.Fatal("proof does not verify")
+	}
+	serialized := proof.Serialize()
+
+	// Ensure the proof does not verify for another public key or input.
+	otherPubKey := secp256k1.PrivKeyFromBytes([]byte{0x01}).PubKey()
+	if proof.Verify(otherPubKey, alpha) {
+		t.Fatal("proof verifies with other public key")
+	}
+	if proof.Verify(pubKey, []byte("samplf")) {
+		t.Fatal("proof verifies with other input")
+	}
+
+	// Ensure modifying the challenge, s, or gamma causes the proof to no
+	// longer verify.
+	tests := []struct {
+		name   string
+		offset int
+	}{
+		{"modified challenge", pointSize},
+		{"modified s", ProofSize - 1},
+	}
+	for _, test := range tests {
+		modified := append([]byte(nil), serialized...)
+		modified[test.offset] ^= 0x01
+		p, err := ParseProof(modified)
+		if err != nil {
+			t.Errorf("%q: unexpected parse error: %v", test.name, err)
+			continue
+		}
+		if p.Verify(pubKey, alpha) {
+			t.Errorf("%q: proof verifies", test.name)
+		}
+	}
+
+	// Replace gamma with another valid point.
+	modified := append([]byte(nil), serialized...)
+	copy(modified, otherPubKey.SerializeCompressed())
+	p, err := ParseProof(modified)
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+	if p.Verify(pubKey, alpha) {
+		t.Fatal("proof with modified gamma verifies")
+	}
+	if p.Output() == proof.Output() {
+		t.Fatal("proof with modified gamma commits to the same output")
+	}
+}
+
+// TestParseProof ensures that proofs are properly parsed including error paths.
+func TestParseProof(t *testing.T) {
+	const validProof = "0338ec99b5d0f94ebcc2c704c04af3de8b4289df8798e5fb9f920d7f" +
+		"5d77ac03d7718b9677d1c9348649ac2ec4f7ecbe519b30dd10c4eb5efc21dd5944709" +
+		"f2f3b7e97a25f6f095334593502d05103bc5b"
+	tests := []struct {
+		name  string // test description
+		proof string // hex encoded proof to parse
+		err   error  // expected error
+	}{{
+		name:  "valid proof",
+		proof: validProof,
+		err:   nil,
+	}, {
+		name:  "empty",
+		proof: "",
+		err:   ErrProofTooShort,
+	}, {
+		name:  "too short by one byte",
+		proof: validProof[:len(validProof)-2],
+		err:   ErrProofTooShort,
+	}, {
+		name:  "too long by one byte",
+		proof: validProof + "00",
+		err:   ErrProofTooLong,
+	}, {
+		name:  "uncompressed gamma format",
+		proof: "04" + validProof[2:],
+		err:   ErrProofGammaInvalid,
+	}, {
+		name: "gamma not on curve",
+		proof: "02" + "0000000000000000000000000000000000000000000000000000000000000005" +
+			validProof[66:],
+		err: ErrProofGammaInvalid,
+	}, {
+		name: "s == n",
+		proof: validProof[:98] +
+			"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+		err: ErrProofSTooBig,
+	}}
+
+	for _, test := range tests {
+		_, err := ParseProof(hexToBytes(test.proof))
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: mismatched err -- got %v, want %v", test.name, err,
+				test.err)
+			continue
+		}
+	}
+}
@@ -80,6 +80,12 @@ const (
 	ErrRPCReconsiderFailure RPCErrorCode = -50
 )
 
+// Errors related to the resource limits of the RPC server.  The codes mirror
+// the associated HTTP status codes.
+const (
+	ErrRPCTooManyRequests RPCErrorCode = -429
+)
+
 // Errors that are specific to btcd.
 const (
 	ErrRPCNoWallet      RPCErrorCode = -1
@@ -0,0 +1,268 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dcrjson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification the method
+// descriptions generated by this package conform to.
+const OpenRPCVersion = "1.2.6"
+
+// JSONSchema describes the JSON type of a method parameter or result as a
+// subset of JSON Schema suitable for OpenRPC documents.
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+// OpenRPCContentDescriptor describes a method parameter or result per the
+// OpenRPC specification.
+type OpenRPCContentDescriptor struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+}
+
+// OpenRPCMethod describes a method per the OpenRPC specification.  All methods
+// accept their parameters by position.
+type OpenRPCMethod struct {
+	Name           string                      `json:"name"`
+	Summary        string                      `json:"summary,omitempty"`
+	ParamStructure string                      `json:"paramStructure"`
+	Params         []*OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor   `json:"result,omitempty"`
+	Errors         []*RPCError                 `json:"errors,omitempty"`
+}
+
+// OpenRPCInfo houses the metadata about the API described by an OpenRPC
+// document.
+type OpenRPCInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenRPCDocument is an OpenRPC document that describes all methods of an API.
+type OpenRPCDocument struct {
+	OpenRPC string           `json:"openrpc"`
+	Info    OpenRPCInfo      `json:"info"`
+	Methods []*OpenRPCMethod `json:"methods"`
+}
+
+// reflectTypeToJSONSchema returns a JSON schema that describes the provided Go
+// type.  The descriptions of struct fields are looked up the same way as the
+// result help, while map values are described by the provided description key
+// with a "--desc" suffix.  The seen map tracks the structs that are currently
+// being described to prevent infinite recursion for self-referential types.
+func reflectTypeToJSONSchema(xT descLookupFunc, rt reflect.Type, fieldDescKey string, seen map[reflect.Type]struct{}) *JSONSchema {
+	// Indirect pointer if needed.
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	kind := rt.Kind()
+	switch {
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case isNumeric(kind):
+		return &JSONSchema{Type: "integer"}
+	}
+
+	switch kind {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+
+	case reflect.Array, reflect.Slice:
+		return &JSONSchema{
+			Type:  "array",
+			Items: reflectTypeToJSONSchema(xT, rt.Elem(), fieldDescKey, seen),
+		}
+
+	case reflect.Map:
+		valueSchema := reflectTypeToJSONSchema(xT, rt.Elem(),
+			fieldDescKey, seen)
+		valueSchema.Description = xT(fieldDescKey + "--desc")
+		return &JSONSchema{Type: "object", AdditionalProperties: valueSchema}
+
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object"}
+		if _, ok := seen[rt]; ok {
+			return schema
+		}
+		seen[rt] = struct{}{}
+		defer delete(seen, rt)
+
+		typeName := strings.ToLower(rt.Name())
+		numField := rt.NumField()
+		schema.Properties = make(map[string]*JSONSchema, numField)
+		for i := 0; i < numField; i++ {
+			rtf := rt.Field(i)
+			if rtf.PkgPath != "" {
+				continue
+			}
+
+			// The property name is the json name when it's available,
+			// otherwise use the lowercase field name.
+			var fieldName string
+			if tag := rtf.Tag.Get("json"); tag != "" {
+				fieldName = strings.Split(tag, ",")[0]
+			} else {
+				fieldName = strings.ToLower(rtf.Name)
+			}
+			if fieldName == "-" {
+				continue
+			}
+
+			descKey := typeName + "-" + fieldName
+			fieldSchema := reflectTypeToJSONSchema(xT, rtf.Type, descKey,
+				seen)
+			fieldSchema.Description = xT(descKey)
+			schema.Properties[fieldName] = fieldSchema
+		}
+		return schema
+	}
+
+	// Any other types, such as interfaces, may be any JSON value.
+	return &JSONSchema{}
+}
+
+// openRPCMethod generates and returns the OpenRPC method description for the
+// provided command and method info.  This is the main work horse for the
+// exported GenerateOpenRPCMethod function.
+func openRPCMethod(xT descLookupFunc, rtp reflect.Type, defaults map[int]reflect.Value, method string, resultTypes []interface{}) *OpenRPCMethod {
+	m := &OpenRPCMethod{
+		Name:           method,
+		Summary:        xT(method + "--synopsis"),
+		ParamStructure: "by-position",
+	}
+
+	// Describe each parameter of the command.  Optional parameters are
+	// pointers per the rules enforced by Register.
+	rt := rtp.Elem()
+	numFields := rt.NumField()
+	m.Params = make([]*OpenRPCContentDescriptor, 0, numFields)
+	seen := make(map[reflect.Type]struct{})
+	for i := 0; i < numFields; i++ {
+		rtf := rt.Field(i)
+		fieldName := strings.ToLower(rtf.Name)
+		descKey := method + "-" + fieldName
+		schema := reflectTypeToJSONSchema(xT, rtf.Type, descKey, seen)
+		if defVal, ok := defaults[i]; ok {
+			schema.Default = defVal.Elem().Interface()
+		}
+		m.Params = append(m.Params, &OpenRPCContentDescriptor{
+			Name:        fieldName,
+			Description: xT(descKey),
+			Required:    rtf.Type.Kind() != reflect.Ptr,
+			Schema:      schema,
+		})
+	}
+
+	// Describe the result.  When there is more than one result type, each
+	// of them is one of the possible results depending on the condition
+	// which triggers it.
+	schemas := make([]*JSONSchema, 0, len(resultTypes))
+	for i, resultType := range resultTypes {
+		if resultType == nil {
+			schemas = append(schemas, &JSONSchema{Type: "null"})
+			continue
+		}
+		descKey := fmt.Sprintf("%s--result%d", method, i)
+		schema := reflectTypeToJSONSchema(xT,
+			reflect.TypeOf(resultType).Elem(), descKey, seen)
+		schema.Description = xT(descKey)
+		if len(resultTypes) > 1 {
+			condKey := fmt.Sprintf("%s--condition%d", method, i)
+			switch cond := xT(condKey); {
+			case cond != "" && schema.Description != "":
+				schema.Description = cond + ": " + schema.Description
+			case cond != "":
+				schema.Description = cond
+			}
+		}
+		schemas = append(schemas, schema)
+	}
+	switch len(schemas) {
+	case 0:
+		m.Result = &OpenRPCContentDescriptor{
+			Name:   method + "result",
+			Schema: &JSONSchema{Type: "null"},
+		}
+	case 1:
+		m.Result = &OpenRPCContentDescriptor{
+			Name:   method + "result",
+			Schema: schemas[0],
+		}
+	default:
+		m.Result = &OpenRPCContentDescriptor{
+			Name:   method + "result",
+			Schema: &JSONSchema{OneOf: schemas},
+		}
+	}
+
+	return m
+}
+
+// GenerateOpenRPCMethod generates and returns an OpenRPC method description
+// for the provided method and result types given a map to provide the
+// descriptions of the method synopsis, parameters, conditions, and results.
+// The method must be associated with a registered type.  All commands provided
+// by this package are registered by default.
+//
+// The descriptions map and result types follow the same conventions as
+// GenerateHelp.  However, unlike GenerateHelp, descriptions that are missing
+// from the map are omitted from the generated description rather than
+// resulting in an error.
+func GenerateOpenRPCMethod(method interface{}, descs map[string]string, resultTypes ...interface{}) (*OpenRPCMethod, error) {
+	// Look up details about the provided method and error out if not
+	// registered.
+	registerLock.RLock()
+	rtp, ok := methodToConcreteType[method]
+	info := methodToInfo[method]
+	registerLock.RUnlock()
+	if !ok {
+		str := fmt.Sprintf("%#v is not registered", method)
+		return nil, makeError(ErrUnregisteredMethod, str)
+	}
+
+	// Validate each result type is a pointer to a supported type (or nil).
+	for i, resultType := range resultTypes {
+		if resultType == nil {
+			continue
+		}
+
+		rtp := reflect.TypeOf(resultType)
+		if rtp.Kind() != reflect.Ptr {
+			str := fmt.Sprintf("result #%d (%v) is not a pointer",
+				i, rtp.Kind())
+			return nil, makeError(ErrInvalidType, str)
+		}
+
+		elemKind := rtp.Elem().Kind()
+		if !isValidResultType(elemKind) {
+			str := fmt.Sprintf("result #%d (%v) is not an allowed "+
+				"type", i, elemKind)
+			return nil, makeError(ErrInvalidType, str)
+		}
+	}
+
+	xT := func(key string) string {
+		return descs[key]
+	}
+	methodStr := reflect.ValueOf(method).String()
+	return openRPCMethod(xT, rtp, info.defaults, methodStr, resultTypes), nil
+}
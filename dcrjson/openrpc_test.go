@@ -0,0 +1,141 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dcrjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestReflectTypeToJSONSchema ensures the JSON schemas generated for various Go
+// types are the expected values.
+func TestReflectTypeToJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	type nested struct {
+		Next  *nested           `json:"next"`
+		Items []string          `json:"items"`
+		Vals  map[string]uint32 `json:"vals"`
+	}
+
+	tests := []struct {
+		name string
+		rt   reflect.Type
+		want string
+	}{{
+		name: "int64",
+		rt:   reflect.TypeOf(int64(0)),
+		want: `{"type":"integer"}`,
+	}, {
+		name: "*float64",
+		rt:   reflect.TypeOf((*float64)(nil)),
+		want: `{"type":"number"}`,
+	}, {
+		name: "string",
+		rt:   reflect.TypeOf(""),
+		want: `{"type":"string"}`,
+	}, {
+		name: "bool",
+		rt:   reflect.TypeOf(false),
+		want: `{"type":"boolean"}`,
+	}, {
+		name: "[]bool",
+		rt:   reflect.TypeOf([]bool{}),
+		want: `{"type":"array","items":{"type":"boolean"}}`,
+	}, {
+		name: "interface",
+		rt:   reflect.TypeOf((*interface{})(nil)).Elem(),
+		want: `{}`,
+	}, {
+		name: "self-referential struct",
+		rt:   reflect.TypeOf(nested{}),
+		want: `{"type":"object","properties":{"items":{"type":"array",` +
+			`"description":"nested-items desc","items":{"type":"string"}},` +
+			`"next":{"type":"object","description":"nested-next desc"},` +
+			`"vals":{"type":"object","description":"nested-vals desc",` +
+			`"additionalProperties":{"type":"integer",` +
+			`"description":"nested-vals--desc desc"}}}}`,
+	}}
+
+	xT := func(key string) string { return key + " desc" }
+	for _, test := range tests {
+		schema := reflectTypeToJSONSchema(xT, test.rt, "fdk",
+			make(map[reflect.Type]struct{}))
+		got, err := json.Marshal(schema)
+		if err != nil {
+			t.Errorf("%s: unexpected marshal error: %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s: unexpected schema - got %s, want %s", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestGenerateOpenRPCMethod ensures the OpenRPC method descriptions generated
+// for registered methods include the expected parameters and results and that
+// invalid methods and result types are rejected.
+func TestGenerateOpenRPCMethod(t *testing.T) {
+	t.Parallel()
+
+	descs := map[string]string{
+		"getblock--synopsis":   "Returns a block",
+		"getblock-hash":        "The block hash",
+		"getblock--condition0": "verbose=false",
+		"getblock--result0":    "Hex-encoded block",
+	}
+	m, err := GenerateOpenRPCMethod("getblock", descs, (*string)(nil),
+		(*map[string]int64)(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	want := `{"name":"getblock","summary":"Returns a block",` +
+		`"paramStructure":"by-position","params":[{"name":"hash",` +
+		`"description":"The block hash","required":true,` +
+		`"schema":{"type":"string"}},{"name":"verbose",` +
+		`"schema":{"type":"boolean","default":true}},{"name":"verbosetx",` +
+		`"schema":{"type":"boolean","default":false}}],` +
+		`"result":{"name":"getblockresult","schema":{"oneOf":[{` +
+		`"type":"string","description":"verbose=false: Hex-encoded block"},` +
+		`{"type":"object","additionalProperties":{"type":"integer"}}]}}}`
+	if string(got) != want {
+		t.Fatalf("unexpected method - got %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		resultTypes []interface{}
+		err         error
+	}{{
+		name:   "unregistered command",
+		method: "boguscommand",
+		err:    ErrUnregisteredMethod,
+	}, {
+		name:        "non-pointer result type",
+		method:      "help",
+		resultTypes: []interface{}{0},
+		err:         ErrInvalidType,
+	}, {
+		name:        "invalid result type",
+		method:      "help",
+		resultTypes: []interface{}{(*complex64)(nil)},
+		err:         ErrInvalidType,
+	}}
+	for _, test := range tests {
+		_, err := GenerateOpenRPCMethod(test.method, nil, test.resultTypes...)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: mismatched error - got %v, want %v", test.name,
+				err, test.err)
+		}
+	}
+}
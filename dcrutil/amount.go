@@ -7,8 +7,29 @@ package dcrutil
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMalformedAmount describes an error where an amount string is not a
+	// plain decimal number.
+	ErrMalformedAmount = errors.New("malformed amount")
+
+	// ErrAmountPrecision describes an error where an amount string has more
+	// significant decimal places than the unit it is denominated in allows.
+	ErrAmountPrecision = errors.New("amount exceeds unit precision")
+
+	// ErrAmountOverflow describes an error where an amount does not fit in the
+	// range of an Amount.
+	ErrAmountOverflow = errors.New("amount overflows")
+
+	// ErrUnsupportedAmountUnit describes an error where an amount unit is not
+	// supported by an operation.
+	ErrUnsupportedAmountUnit = errors.New("unsupported amount unit")
 )
 
 // AmountUnit describes a method of converting an Amount to something
@@ -50,6 +71,37 @@ func (u AmountUnit) String() string {
 	}
 }
 
+// ParseAmountUnit returns the amount unit described by the provided string.
+// It accepts the strings returned by the String method for recognized units
+// along with "uDCR" for AmountMicroCoin and "Atoms" for AmountAtom.  The
+// strings are case sensitive since the SI prefixes "M" and "m" differ.
+func ParseAmountUnit(s string) (AmountUnit, error) {
+	switch s {
+	case "MDCR":
+		return AmountMegaCoin, nil
+	case "kDCR":
+		return AmountKiloCoin, nil
+	case "DCR":
+		return AmountCoin, nil
+	case "mDCR":
+		return AmountMilliCoin, nil
+	case "μDCR", "uDCR":
+		return AmountMicroCoin, nil
+	case "Atom", "Atoms":
+		return AmountAtom, nil
+	}
+	return 0, fmt.Errorf("%w %q", ErrUnsupportedAmountUnit, s)
+}
+
+// decimals returns the number of decimal places that are necessary to express
+// every amount in the unit without loss of precision.  It returns false when
+// the unit is smaller than the base unit or an amount expressed in the unit
+// can't be converted to the base unit with 64-bit integers.
+func (u AmountUnit) decimals() (int, bool) {
+	decimals := int(u) + 8
+	return decimals, decimals >= 0 && decimals <= 18
+}
+
 // Amount represents the base coin monetary unit (colloquially referred
 // to as an `Atom').  A single Amount is equal to 1e-8 of a coin.
 type Amount int64
@@ -121,6 +173,210 @@ func (a Amount) MulF64(f float64) Amount {
 	return round(float64(a) * f)
 }
 
+// ParseAmount parses the provided string as a decimal number of coins
+// denominated in the provided unit and returns the equivalent Amount.
+//
+// Unlike NewAmount, the conversion does not involve floating point numbers and
+// therefore is lossless.  The string must consist of an optional sign followed
+// by digits with an optional fractional part separated by a period, such as
+// "-12.345".  It is parsed the same way regardless of the locale, so grouping
+// separators, exponents, and any surrounding whitespace are rejected with
+// ErrMalformedAmount.
+//
+// ErrAmountPrecision is returned when the string has more significant decimal
+// places than the unit allows, such as a fraction of an atom, as opposed to
+// silently rounding the amount.  ErrAmountOverflow is returned when the amount
+// does not fit in an Amount.  As with NewAmount, the amount is not checked
+// against the total amount of coins producible.
+func ParseAmount(s string, u AmountUnit) (Amount, error) {
+	decimals, ok := u.decimals()
+	if !ok {
+		return 0, fmt.Errorf("%w %v", ErrUnsupportedAmountUnit, u)
+	}
+
+	// Split the string into its sign, integer, and fractional parts.
+	str := s
+	var sign string
+	if len(str) > 0 && (str[0] == '-' || str[0] == '+') {
+		sign, str = str[:1], str[1:]
+	}
+	intPart, fracPart := str, ""
+	if idx := strings.IndexByte(str, '.'); idx != -1 {
+		intPart, fracPart = str[:idx], str[idx+1:]
+	}
+	isDigits := func(s string) bool {
+		for i := 0; i < len(s); i++ {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		}
+		return true
+	}
+	if len(intPart)+len(fracPart) == 0 || !isDigits(intPart) ||
+		!isDigits(fracPart) {
+
+		return 0, fmt.Errorf("%w %q", ErrMalformedAmount, s)
+	}
+
+	// Trailing zeros beyond the precision of the unit do not change the
+	// amount, so only reject non-zero digits.
+	if len(fracPart) > decimals {
+		if strings.TrimRight(fracPart[decimals:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q has more than %d decimal places for "+
+				"unit %v", ErrAmountPrecision, s, decimals, u)
+		}
+		fracPart = fracPart[:decimals]
+	}
+
+	// Scale the amount to atoms by treating the integer and fractional parts
+	// padded to the precision of the unit as a single integer.
+	digits := intPart + fracPart + strings.Repeat("0", decimals-len(fracPart))
+	atoms, err := strconv.ParseInt(sign+digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q in unit %v", ErrAmountOverflow, s, u)
+	}
+	return Amount(atoms), nil
+}
+
+// ParseAmountWithUnit parses the provided string as a decimal number of coins
+// followed by a space and the unit it is denominated in, such as "1.5 DCR",
+// and returns the equivalent Amount.  It accepts the strings produced by Format
+// for the units recognized by ParseAmountUnit.
+//
+// See ParseAmount for details regarding the accepted numbers and errors.
+func ParseAmountWithUnit(s string) (Amount, error) {
+	idx := strings.LastIndexByte(s, ' ')
+	if idx == -1 {
+		return 0, fmt.Errorf("%w: %q does not specify a unit",
+			ErrMalformedAmount, s)
+	}
+	u, err := ParseAmountUnit(s[idx+1:])
+	if err != nil {
+		return 0, err
+	}
+	return ParseAmount(s[:idx], u)
+}
+
+// AmountFormatFlags specifies options that modify the format produced by
+// FormatWithFlags.  The options may be combined with a bitwise OR.
+type AmountFormatFlags uint8
+
+const (
+	// AmountFormatFixedDecimals formats the amount with all of the decimal
+	// places of the unit, such as "1.00000000 DCR", as opposed to removing
+	// trailing zeros.
+	AmountFormatFixedDecimals AmountFormatFlags = 1 << iota
+
+	// AmountFormatNoUnit omits the label describing the unit.
+	AmountFormatNoUnit
+
+	// AmountFormatPlusSign prefixes positive amounts with a plus sign.
+	AmountFormatPlusSign
+)
+
+// FormatWithFlags formats a monetary amount counted in coin base units as a
+// string for a given unit using the provided formatting options.
+//
+// The conversion is exact for the units supported by ParseAmount and the
+// result with no options is the same as the one produced by Format.  The
+// results for other units are approximated with floating point numbers.
+func (a Amount) FormatWithFlags(u AmountUnit, flags AmountFormatFlags) string {
+	var bldr strings.Builder
+	if a > 0 && flags&AmountFormatPlusSign != 0 {
+		bldr.WriteByte('+')
+	}
+
+	decimals, ok := u.decimals()
+	if !ok {
+		prec := -int(u + 8)
+		if decimals > 0 && flags&AmountFormatFixedDecimals != 0 {
+			prec = decimals
+		}
+		bldr.WriteString(strconv.FormatFloat(a.ToUnit(u), 'f', prec, 64))
+	} else {
+		// Split the magnitude into its integer and fractional parts.  Note
+		// that the negation of the magnitude as an unsigned integer is also
+		// correct for the minimum amount.
+		magnitude := uint64(a)
+		if a < 0 {
+			bldr.WriteByte('-')
+			magnitude = -magnitude
+		}
+		scale := uint64(1)
+		for i := 0; i < decimals; i++ {
+			scale *= 10
+		}
+		bldr.WriteString(strconv.FormatUint(magnitude/scale, 10))
+		var frac string
+		if decimals > 0 {
+			frac = strconv.FormatUint(magnitude%scale, 10)
+			frac = strings.Repeat("0", decimals-len(frac)) + frac
+		}
+		if flags&AmountFormatFixedDecimals == 0 {
+			frac = strings.TrimRight(frac, "0")
+		}
+		if frac != "" {
+			bldr.WriteByte('.')
+			bldr.WriteString(frac)
+		}
+	}
+
+	if flags&AmountFormatNoUnit == 0 {
+		bldr.WriteByte(' ')
+		bldr.WriteString(u.String())
+	}
+	return bldr.String()
+}
+
+// MulDiv multiplies an Amount by the provided numerator and divides the result
+// by the provided denominator with the result rounded to the nearest atom,
+// with halfway cases rounded away from zero.  The intermediate product is
+// calculated exactly, so it is suitable for applying fixed-point rates, such as
+// percentages expressed in basis points, without the loss of precision
+// involved with MulF64.
+//
+// An error is returned when the denominator is zero or the result does not fit
+// in an Amount.
+func (a Amount) MulDiv(num, denom int64) (Amount, error) {
+	if denom == 0 {
+		return 0, errors.New("division by zero")
+	}
+
+	// Calculate round(a * num / denom) by adding half of the denominator to
+	// the magnitude of the product prior to the truncating division.
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(num))
+	d := big.NewInt(denom)
+	negative := product.Sign()*d.Sign() < 0
+	product.Abs(product)
+	d.Abs(d)
+	product.Add(product, new(big.Int).Rsh(d, 1))
+	product.Quo(product, d)
+	if negative {
+		product.Neg(product)
+	}
+	if !product.IsInt64() {
+		return 0, fmt.Errorf("%w: %d * %d / %d", ErrAmountOverflow, a, num,
+			denom)
+	}
+	return Amount(product.Int64()), nil
+}
+
+// SumAmounts returns the sum of the provided amounts or ErrAmountOverflow when
+// the sum, or any intermediate sum, does not fit in an Amount.
+func SumAmounts(amounts ...Amount) (Amount, error) {
+	var sum Amount
+	for _, amount := range amounts {
+		if (amount > 0 && sum > math.MaxInt64-amount) ||
+			(amount < 0 && sum < math.MinInt64-amount) {
+
+			return 0, fmt.Errorf("%w: sum of %d amounts", ErrAmountOverflow,
+				len(amounts))
+		}
+		sum += amount
+	}
+	return sum, nil
+}
+
 // AmountSorter implements sort.Interface to allow a slice of Amounts to
 // be sorted.
 type AmountSorter []Amount
@@ -6,6 +6,7 @@
 package dcrutil
 
 import (
+	"errors"
 	"math"
 	"reflect"
 	"sort"
@@ -309,6 +310,234 @@ func TestAmountMulF64(t *testing.T) {
 	}
 }
 
+// TestParseAmount ensures amount strings in various units are parsed without
+// loss of precision and that invalid strings are rejected with the expected
+// errors.
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		unit AmountUnit
+		want Amount
+		err  error
+	}{
+		{name: "zero", s: "0", unit: AmountCoin, want: 0},
+		{name: "one coin", s: "1", unit: AmountCoin, want: 1e8},
+		{name: "fraction", s: "0.01234567", unit: AmountCoin, want: 1234567},
+		{name: "no integer part", s: ".5", unit: AmountCoin, want: 5e7},
+		{name: "no fractional part", s: "5.", unit: AmountCoin, want: 5e8},
+		{name: "negative", s: "-21000000", unit: AmountCoin, want: -MaxAmount},
+		{name: "plus sign", s: "+1.5", unit: AmountCoin, want: 15e7},
+		{name: "leading zeros", s: "0007", unit: AmountAtom, want: 7},
+		{name: "excess zeros", s: "1.000000000", unit: AmountCoin, want: 1e8},
+		{name: "float64 imprecise", s: "20999999.99999999", unit: AmountCoin,
+			want: MaxAmount - 1},
+		{name: "milli", s: "1.23456", unit: AmountMilliCoin, want: 123456},
+		{name: "micro", s: "12.34", unit: AmountMicroCoin, want: 1234},
+		{name: "atoms", s: "123", unit: AmountAtom, want: 123},
+		{name: "mega", s: "0.5", unit: AmountMegaCoin, want: 5e13},
+		{name: "max", s: "92233720368.54775807", unit: AmountCoin,
+			want: math.MaxInt64},
+		{name: "min", s: "-92233720368.54775808", unit: AmountCoin,
+			want: math.MinInt64},
+		{name: "sub-atom precision", s: "0.000000001", unit: AmountCoin,
+			err: ErrAmountPrecision},
+		{name: "fractional atoms", s: "1.5", unit: AmountAtom,
+			err: ErrAmountPrecision},
+		{name: "overflow", s: "92233720368.54775808", unit: AmountCoin,
+			err: ErrAmountOverflow},
+		{name: "empty", s: "", unit: AmountCoin, err: ErrMalformedAmount},
+		{name: "only sign", s: "-", unit: AmountCoin, err: ErrMalformedAmount},
+		{name: "only point", s: ".", unit: AmountCoin, err: ErrMalformedAmount},
+		{name: "comma separator", s: "1,5", unit: AmountCoin,
+			err: ErrMalformedAmount},
+		{name: "grouping", s: "1,000.5", unit: AmountCoin,
+			err: ErrMalformedAmount},
+		{name: "two points", s: "1.0.0", unit: AmountCoin,
+			err: ErrMalformedAmount},
+		{name: "exponent", s: "1e8", unit: AmountAtom, err: ErrMalformedAmount},
+		{name: "whitespace", s: " 1", unit: AmountCoin,
+			err: ErrMalformedAmount},
+		{name: "double sign", s: "--1", unit: AmountCoin,
+			err: ErrMalformedAmount},
+		{name: "unsupported unit", s: "1", unit: AmountUnit(-9),
+			err: ErrUnsupportedAmountUnit},
+	}
+
+	for _, test := range tests {
+		got, err := ParseAmount(test.s, test.unit)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: mismatched error -- got %v, want %v", test.name,
+				err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched amount -- got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestParseAmountWithUnit ensures amount strings that specify their unit are
+// parsed as expected, including that the strings produced by Format round trip.
+func TestParseAmountWithUnit(t *testing.T) {
+	const amount = Amount(44433322211100)
+	units := []AmountUnit{AmountMegaCoin, AmountKiloCoin, AmountCoin,
+		AmountMilliCoin, AmountMicroCoin, AmountAtom}
+	for _, unit := range units {
+		got, err := ParseAmountWithUnit(amount.Format(unit))
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", unit, err)
+			continue
+		}
+		if got != amount {
+			t.Errorf("%v: mismatched amount -- got %d, want %d", unit, got,
+				amount)
+		}
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		want Amount
+		err  error
+	}{
+		{name: "ascii micro", s: "1.5 uDCR", want: 150},
+		{name: "plural atoms", s: "10 Atoms", want: 10},
+		{name: "no unit", s: "1.5", err: ErrMalformedAmount},
+		{name: "unknown unit", s: "1.5 BTC", err: ErrUnsupportedAmountUnit},
+		{name: "wrong case unit", s: "1.5 dcr", err: ErrUnsupportedAmountUnit},
+		{name: "sub-atom precision", s: "1.5 Atom", err: ErrAmountPrecision},
+	}
+	for _, test := range tests {
+		got, err := ParseAmountWithUnit(test.s)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: mismatched error -- got %v, want %v", test.name,
+				err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched amount -- got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestAmountFormatWithFlags ensures amounts are formatted as expected with the
+// various formatting options and that the result without any options matches
+// Format.
+func TestAmountFormatWithFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		unit   AmountUnit
+		flags  AmountFormatFlags
+		want   string
+	}{
+		{name: "no flags", amount: 44433322211100, unit: AmountCoin,
+			want: "444333.222111 DCR"},
+		{name: "whole coins", amount: 5e8, unit: AmountCoin, want: "5 DCR"},
+		{name: "fixed decimals", amount: 5e8, unit: AmountCoin,
+			flags: AmountFormatFixedDecimals, want: "5.00000000 DCR"},
+		{name: "fixed decimals milli", amount: -1, unit: AmountMilliCoin,
+			flags: AmountFormatFixedDecimals, want: "-0.00001 mDCR"},
+		{name: "no unit", amount: 12345, unit: AmountMicroCoin,
+			flags: AmountFormatNoUnit, want: "123.45"},
+		{name: "plus sign", amount: 1, unit: AmountAtom,
+			flags: AmountFormatPlusSign, want: "+1 Atom"},
+		{name: "plus sign negative", amount: -1, unit: AmountAtom,
+			flags: AmountFormatPlusSign, want: "-1 Atom"},
+		{name: "plus sign zero", amount: 0, unit: AmountCoin,
+			flags: AmountFormatPlusSign, want: "0 DCR"},
+		{name: "combined", amount: 15e7, unit: AmountCoin,
+			flags: AmountFormatFixedDecimals | AmountFormatNoUnit |
+				AmountFormatPlusSign, want: "+1.50000000"},
+		{name: "max amount exact", amount: math.MaxInt64, unit: AmountCoin,
+			want: "92233720368.54775807 DCR"},
+		{name: "min amount exact", amount: math.MinInt64, unit: AmountCoin,
+			want: "-92233720368.54775808 DCR"},
+		{name: "non-standard unit", amount: 44433322211100,
+			unit: AmountUnit(-1), want: "4443332.22111 1e-1 DCR"},
+	}
+
+	for _, test := range tests {
+		got := test.amount.FormatWithFlags(test.unit, test.flags)
+		if got != test.want {
+			t.Errorf("%s: mismatched format -- got %q, want %q", test.name,
+				got, test.want)
+		}
+	}
+
+	// Ensure the result without any options matches Format for a variety of
+	// amounts and units.
+	amounts := []Amount{0, 1, -1, 10, 100000, 123456789, -987654321, 1e8,
+		44433322211100, MaxAmount, -MaxAmount}
+	units := []AmountUnit{AmountMegaCoin, AmountKiloCoin, AmountCoin,
+		AmountMilliCoin, AmountMicroCoin, AmountAtom}
+	for _, amount := range amounts {
+		for _, unit := range units {
+			got, want := amount.FormatWithFlags(unit, 0), amount.Format(unit)
+			if got != want {
+				t.Errorf("mismatched format for %d in %v -- got %q, want %q",
+					int64(amount), unit, got, want)
+			}
+		}
+	}
+}
+
+// TestAmountMulDiv ensures multiplying amounts by fixed-point rates produces
+// the expected exactly rounded results and errors.
+func TestAmountMulDiv(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		num    int64
+		denom  int64
+		want   Amount
+		err    bool
+	}{
+		{name: "basis points", amount: 1e8, num: 25, denom: 10000, want: 25e4},
+		{name: "round down", amount: 100, num: 1, denom: 3, want: 33},
+		{name: "round up", amount: 200, num: 1, denom: 3, want: 67},
+		{name: "halfway", amount: 1, num: 1, denom: 2, want: 1},
+		{name: "negative halfway", amount: -1, num: 1, denom: 2, want: -1},
+		{name: "negative denominator", amount: 200, num: 1, denom: -3,
+			want: -67},
+		{name: "large intermediate", amount: MaxAmount, num: MaxAmount,
+			denom: MaxAmount, want: MaxAmount},
+		{name: "zero denominator", amount: 1, num: 1, denom: 0, err: true},
+		{name: "overflow", amount: math.MaxInt64, num: 2, denom: 1, err: true},
+	}
+
+	for _, test := range tests {
+		got, err := test.amount.MulDiv(test.num, test.denom)
+		if (err != nil) != test.err {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched amount -- got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestSumAmounts ensures summing amounts detects overflow.
+func TestSumAmounts(t *testing.T) {
+	sum, err := SumAmounts(1, 2, -4, MaxAmount)
+	if err != nil || sum != MaxAmount-1 {
+		t.Fatalf("unexpected sum %d with error %v", sum, err)
+	}
+	_, err = SumAmounts(math.MaxInt64, 1)
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Fatalf("mismatched error -- got %v, want %v", err, ErrAmountOverflow)
+	}
+	_, err = SumAmounts(math.MinInt64, -1)
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Fatalf("mismatched error -- got %v, want %v", err, ErrAmountOverflow)
+	}
+}
+
 func TestAmountSorter(t *testing.T) {
 	tests := []struct {
 		name string
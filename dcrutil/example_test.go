@@ -77,3 +77,22 @@ func ExampleAmount_unitConversions() {
 	// Atom to MicroCoin: 444333222111 μDCR
 	// Atom to Atom: 44433322211100 Atom
 }
+
+func ExampleParseAmount() {
+	amount, err := dcrutil.ParseAmount("20999999.99999999", dcrutil.AmountCoin)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(int64(amount))
+	fmt.Println(amount.FormatWithFlags(dcrutil.AmountMilliCoin,
+		dcrutil.AmountFormatFixedDecimals))
+
+	_, err = dcrutil.ParseAmount("0.000000001", dcrutil.AmountCoin)
+	fmt.Println(err)
+
+	// Output:
+	// 2099999999999999
+	// 20999999999.99999 mDCR
+	// amount exceeds unit precision: "0.000000001" has more than 8 decimal places for unit DCR
+}
@@ -32,6 +32,8 @@ Application Options:
 	    --logsize=               Maximum size of log file before it is rotated
 	                             (default: 10 MiB)
 	    --nofilelogging          Disable file logging
+	    --logformat=             Format of log output {text, json} (default:
+	                             text)
 	    --dbtype=                Database backend to use for the block chain
 	                             (default: ffldb)
 	    --profile=               Enable HTTP profiling on given [addr:]port --
@@ -41,6 +43,8 @@ Application Options:
 	    --testnet                Use the test network
 	    --simnet                 Use the simulation test network
 	    --regnet                 Use the regression test network
+	    --customnet=             Use the custom network, such as a private
+	                             network, defined by the specified JSON file
 	-d, --debuglevel=            Logging level for all subsystems {trace, debug,
 	                             info, warn, error, critical} -- You may also
 	                             specify
@@ -51,12 +55,66 @@ Application Options:
 	                             verification cache (default: 100000)
 	    --utxocachemaxsize=      The maximum size in MiB of the utxo cache
 	                             (default: 150, minimum: 25, maximum: 32768)
+	    --dbwritebuffer=         The size in MiB of writes the block and utxo
+	                             databases buffer in memory before writing them
+	                             to disk; 0 uses the backend default (max: 1024)
+	    --dbmaxopenfiles=        The maximum number of files the block and utxo
+	                             databases each keep open; 0 uses the backend
+	                             default
+	    --dbcompacttrigger=      The number of level-0 tables that triggers a
+	                             compaction of the block and utxo databases; 0
+	                             uses the backend default (max: 64)
+	    --dbcompacttablesize=    The size in MiB of the tables produced by
+	                             compactions of the block and utxo databases; 0
+	                             uses the backend default (max: 1024)
+	    --dbkeyfile=             Encrypt the block and utxo databases at rest with
+	                             the hex-encoded 32-byte key in the specified
+	                             file -- NOTE: Encryption must be enabled when the
+	                             databases are created
+	    --dbkeycmd=              Encrypt the block and utxo databases at rest with
+	                             the hex-encoded 32-byte key printed by the
+	                             specified command, such as one that reads it
+	                             from the OS keyring -- NOTE: The command and its
+	                             arguments are separated by spaces
+	    --dbblockfilesperdir=    Store the block files in subdirectories that
+	                             each house the specified number of files; 0
+	                             stores them directly in the block database
+	                             directory -- NOTE: This must not be changed once
+	                             set
+	    --dbcolddir=             Move older block files to the specified
+	                             directory, typically on cheaper and slower
+	                             storage, while keeping the most recent ones in
+	                             the data directory
+	    --dbhotblockfiles=       The number of the most recent block files to
+	                             keep in the data directory when dbcolddir is
+	                             set; 0 uses the backend default
+	    --dbopmetrics            Track the number, latency, and value sizes of
+	                             block database operations by bucket for the RPC
+	                             metrics endpoint
+	    --logrotateinterval=     Interval at which the log file is rotated
+	                             regardless of its size, such as 24h --
+	                             Rotations are aligned to multiples of the
+	                             interval in UTC -- 0 disables time-based
+	                             rotation
+	    --nologcompression       Disable gzip compression of rotated log files
+	    --logmaxrolls=           Maximum number of rotated log files to keep -- 0
+	                             keeps all of them (default: 3)
+	    --logmaxage=             Maximum age of rotated log files to keep, such
+	                             as 720h -- 0 disables the age limit
+	    --logmaxtotalsize=       Maximum total size of rotated log files to
+	                             keep, such as 1G -- The oldest ones are removed
+	                             first -- 0 disables the size limit (default: 0)
 	    --norpc                  Disable built-in RPC server -- NOTE: The RPC
 	                             server is disabled by default if no
 	                             rpcuser/rpcpass or rpclimituser/rpclimitpass is
 	                             specified
 	    --rpclisten=             Add an interface/port to listen for RPC
 	                             connections (default port: 9109, testnet: 19109)
+	    --rpclistenunix=         Add a unix domain socket path to listen for RPC
+	                             connections on without TLS -- NOTE: Access is
+	                             controlled by the permissions of the socket,
+	                             which is only accessible by the owner, and
+	                             connections do not require the RPC credentials
 	-u, --rpcuser=               Username for RPC connections
 	-P, --rpcpass=               Password for RPC connections
 	    --authtype=              Method for RPC client authentication
@@ -66,12 +124,27 @@ Application Options:
 	                             authtype=clientcert
 	    --rpclimituser=          Username for limited RPC connections
 	    --rpclimitpass=          Password for limited RPC connections
+	    --rpcauth=               Add an RPC user restricted to the listed
+	                             methods and websocket notification types in the
+	                             form user:pass:methods:notifications where each
+	                             list is comma-separated and * allows all --
+	                             NOTE: Entries in the config file are reloaded
+	                             on SIGUSR1
 	    --rpccert=               File containing the certificate file
 	    --rpckey=                File containing the certificate key
-	    --tlscurve=              Curve to use when generating the TLS keypair
-	                             (default: P-256)
-	    --altdnsnames            Specify additional dns names to use when
-	                             generating the rpc server certificate
+	    --tlscurve=              Curve to use when generating TLS keypairs
+	                             (P-256, P-521, or Ed25519) (default: P-256)
+	    --tlscertlifetime=       Validity period of generated TLS certificates.
+	                             Valid time units are {m, h}.  Minimum 24 hours
+	                             (default: 87600h0m0s)
+	    --tlscertrenewal=        Automatically regenerate autogenerated TLS
+	                             certificates when they expire within this
+	                             duration and notify websocket clients of the
+	                             new certificate (0 = disabled) (default:
+	                             720h0m0s)
+	    --altdnsnames            Specify additional DNS names or IP addresses to
+	                             use as subject alternative names when
+	                             generating the RPC server certificate
 	                             [supports DCRD_ALT_DNSNAMES environment variable]
 	    --notls                  Disable TLS for the RPC server -- NOTE: This is
 	                             only allowed if the RPC server is bound to
@@ -82,6 +155,59 @@ Application Options:
 	                             (default: 25)
 	    --rpcmaxconcurrentreqs=  Max number of concurrent RPC requests that may
 	                             be processed concurrently (default: 20)
+	    --rpcuserreqrate=        Max number of RPC requests per second per
+	                             authenticated user (0 = no limit)
+	    --rpcipreqrate=          Max number of RPC requests per second per
+	                             remote IP (0 = no limit)
+	    --rpcusermaxreqs=        Max number of RPC requests per authenticated
+	                             user that may be processed concurrently (0 = no
+	                             limit)
+	    --rpcipmaxreqs=          Max number of RPC requests per remote IP that
+	                             may be processed concurrently (0 = no limit)
+	    --rpcslowcall=           Log RPC calls that take at least this long
+	                             along with the client that issued them and
+	                             their redacted parameters (0 = disabled)
+	    --rest                   Enable the REST interface for blocks, headers,
+	                             and unspent outputs on the RPC listeners --
+	                             NOTE: REST requests do not require the RPC
+	                             credentials
+	    --resttoken=             Token REST clients must provide via a bearer
+	                             authorization header; requires --rest
+	    --grpclisten=            Add an interface/port to listen for gRPC
+	                             connections using the same credentials and TLS
+	                             settings as the RPC server -- NOTE: The gRPC
+	                             server is disabled unless at least one address
+	                             is specified (default port: 9112, testnet:
+	                             19112)
+	    --metricslisten=         Add an interface/port to serve block
+	                             validation, mempool, peer, RPC, and database
+	                             metrics in the Prometheus text exposition
+	                             format via HTTP at /metrics -- NOTE:
+	                             Addresses other than loopback addresses
+	                             require --metricsuser and --metricspass and the
+	                             metrics are disabled unless at least one
+	                             address is specified (default port: 9190)
+	    --metricsuser=           Username metrics clients must provide via HTTP
+	                             basic authentication
+	    --metricspass=           Password metrics clients must provide via HTTP
+	                             basic authentication
+	    --diaglisten=            Add an interface/port to serve pprof profiles,
+	                             runtime metrics, and goroutine dumps via HTTP
+	                             at /debug/ using the same admin credentials and
+	                             TLS settings as the RPC server -- NOTE:
+	                             Diagnostics are disabled unless at least one
+	                             address is specified (default port: 9191)
+	    --tracingendpoint=       Export traces of block download, validation,
+	                             connection, and notification and of transaction
+	                             acceptance and relay to the OTLP/HTTP traces
+	                             endpoint of an OpenTelemetry collector at the
+	                             specified URL (eg.
+	                             http://127.0.0.1:4318/v1/traces) -- NOTE:
+	                             Tracing is disabled unless an endpoint is
+	                             specified
+	    --tracingsampleratio=    The ratio of blocks and transactions that are
+	                             traced when tracing is enabled (0-1) (default:
+	                             1)
 	    --proxy=                 Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
 	    --proxyuser=             Username for proxy server
 	    --proxypass=             Password for proxy server
@@ -92,8 +218,21 @@ Application Options:
 	    --noonion                Disable connecting to tor hidden services
 	    --torisolation           Enable Tor stream isolation by randomizing user
 	                             credentials for each connection
-	-a, --addpeer=               Add a peer to connect with at startup
-	    --connect=               Connect only to the specified peers at startup
+	    --ipv4proxy=             Connect to IPv4 peers via SOCKS5 proxy instead
+	                             of the one specified by --proxy -- Use 'none'
+	                             to connect directly
+	    --ipv6proxy=             Connect to IPv6 peers via SOCKS5 proxy instead
+	                             of the one specified by --proxy -- Use 'none'
+	                             to connect directly
+	    --i2psam=                Connect to and accept connections from I2P
+	                             peers via the SAM v3 bridge of an I2P router
+	                             (eg. 127.0.0.1:7656)
+	-a, --addpeer=               Add a peer to connect with at startup,
+	                             optionally prefixed with comma-separated
+	                             permissions followed by @
+	    --connect=               Connect only to the specified peers at startup,
+	                             optionally prefixed with comma-separated
+	                             permissions followed by @
 	    --nolisten               Disable listening for incoming connections --
 	                             NOTE: Listening is automatically disabled if
 	                             the --connect or --proxy options are used
@@ -102,6 +241,10 @@ Application Options:
 	    --listen=                Add an interface/port to listen for connections
 	                             (default all interfaces port: 9108, testnet:
 	                             19108)
+	    --outboundonly           Never accept incoming connections, such as when
+	                             behind a strict NAT -- NOTE: Implies --nolisten
+	                             and may not be used with --listen, --upnp, or
+	                             --natpmp
 	    --maxsameip=             Max number of connections with the same IP -- 0
 	                             to disable (default: 5)
 	    --maxpeers=              Max number of inbound and outbound peers
@@ -112,7 +255,43 @@ Application Options:
 	    --peeridletimeout        The duration of inactivity before a peer is
 	                             timed out.  Valid time units are {s,m,h}.
 	                             Minimum 15 seconds (default: 2m0s)
+	    --trickleinterval=       The average interval between the randomly timed
+	                             batches of transaction announcements sent to
+	                             each outbound peer -- Inbound peers use twice
+	                             the interval.  Valid time units are
+	                             {ms,s,m,h}.  Minimum 10 milliseconds (default:
+	                             500ms)
+	    --p2pencryption          Encrypt connections with peers that support it
+	                             in order to prevent passive network observers
+	                             from fingerprinting traffic
+	    --asmap=                 Path to a file that maps IP prefixes to the
+	                             autonomous systems that announce them which is
+	                             used to spread outbound peers across network
+	                             operators
+	    --eclipseresistance      Persist connections to long-lived outbound peers
+	                             (anchors) across restarts to make it harder for
+	                             an attacker to monopolize outbound connections
+	    --maxuploadrate=         Max combined rate to upload data to all peers in
+	                             KiB/s -- 0 to disable
+	    --maxdownloadrate=       Max combined rate to download data from all
+	                             peers in KiB/s -- 0 to disable
+	    --maxinbounduploadrate=  Max rate to upload data to each inbound peer in
+	                             KiB/s -- 0 to disable
+	    --maxinbounddownloadrate=
+	                             Max rate to download data from each inbound peer
+	                             in KiB/s -- 0 to disable
+	    --maxoutbounduploadrate= Max rate to upload data to each outbound peer in
+	                             KiB/s -- 0 to disable
+	    --maxoutbounddownloadrate=
+	                             Max rate to download data from each outbound
+	                             peer in KiB/s -- 0 to disable
 	    --noseeders              Disable seeding for peer discovery
+	    --seeder=                Add an HTTPS seeder to query for peers before
+	                             the default seeders, which are then only
+	                             queried when more addresses are needed --
+	                             Prefix with a network name and a colon to only
+	                             use it on that network (eg.
+	                             testnet3:seed.example.com)
 	    --nodnsseed              DEPRECATED: use --noseeders
 	    --externalip=            Add a public-facing IP to the list of local
 	                             external IPs that dcrd will advertise to other
@@ -120,14 +299,23 @@ Application Options:
 	    --nodiscoverip           Disable automatic network address discovery of
 	                             local external IPs
 	    --upnp                   Use UPnP to map our listening port outside of NAT
+	    --natpmp                 Use NAT-PMP or PCP to map our listening port
+	                             outside of NAT when UPnP is not enabled or not
+	                             available
 	    --nobanning              Disable banning of misbehaving peers
 	    --banduration=           How long to ban misbehaving peers.  Valid time
 	                             units are {s, m, h}.  Minimum 1 second (default:
 	                             24h0m0s)
 	    --banthreshold=          Maximum allowed ban score before disconnecting
 	                             and banning misbehaving peers (default: 100)
-	    --whitelist=             Add an IP network or IP that will not be banned
-	                             (eg. 192.168.1.0/24 or ::1)
+	    --whitelist=             Add an IP network or IP that will not be banned,
+	                             optionally prefixed with comma-separated
+	                             permissions followed by @ (eg. 192.168.1.0/24,
+	                             ::1, or noban,mempool@10.0.0.0/8) -- NOTE: The
+	                             available permissions are noban, nolimits,
+	                             forcerelay, mempool, addr, and all.  Entries
+	                             without permissions are granted noban and
+	                             nolimits
 	    --allowoldforks          Process forks deep in history.  Don't do this
 	                             unless you know what you're doing
 	    --dumpblockchain=        Write blockchain as a flat file of blocks for
@@ -147,7 +335,16 @@ Application Options:
 	                             version of the software
 	    --maxorphantx=           Max number of orphan transactions to keep in
 	                             memory (default: 100)
-	    --blocksonly             Do not accept transactions from remote peers
+	    --maxorphantxperpeer=    Max number of orphan transactions to keep in
+	                             memory that were received from any single peer
+	                             (0 to disable the per-peer limit) (default: 25)
+	    --blocksonly             Do not accept or request transactions from remote
+	                             peers and ask them not to relay any -- NOTE:
+	                             Transactions submitted via RPC are still
+	                             relayed
+	    --txreconciliation       Announce transactions to peers that support it
+	                             via sketch-based set reconciliation instead of
+	                             flooding in order to reduce bandwidth usage
 	    --acceptnonstd           Accept and relay non-standard transactions to
 	                             the network regardless of the default settings
 	                             for the active network
@@ -160,6 +357,9 @@ Application Options:
 	                             of addresses to use for generated blocks.  At
 	                             least one address is required if the generate
 	                             option is set
+	    --blocksignkeyfile=      File containing the hex-encoded secp256k1
+	                             private key used to sign generated blocks on
+	                             networks that require blocks to be signed
 	    --blockminsize=          DEPRECATED: This behavior is no longer available
 	                             and this option will be removed in a future
 	                             version of the software
@@ -189,6 +389,33 @@ Application Options:
 	                             whether or not an address has even been used
 	    --dropexistsaddrindex    Deletes the exists address index from the
 	                             database on start up and then exits
+	    --addrindex              Maintain a full address-based transaction index
+	                             which makes the transactions involving an
+	                             address available via the searchrawtransactions
+	                             RPC
+	    --dropaddrindex          Deletes the address-based transaction index from
+	                             the database on start up and then exits
+	    --spentindex             Maintain a spent output index which makes the
+	                             transaction input that spends an output
+	                             available via the getspentinfo RPC
+	    --dropspentindex         Deletes the spent output index from the
+	                             database on start up and then exits
+	    --verifychainstate       Verifies the block index, utxo set, and optional
+	                             indexes are consistent on start up, reports the
+	                             first divergence in each, and then exits
+	    --repairindexes          Deletes any optional indexes found to be
+	                             damaged by --verifychainstate so they are
+	                             rebuilt on the next start
+	    --verifyindex=           Verifies the named optional index (txindex,
+	                             existsaddrindex, addrindex, or spentindex) is
+	                             consistent with the main chain on start up
+	                             without rebuilding it, reports the first
+	                             divergence, and then exits
+	    --rebuildindex=          Deletes the named optional index (txindex,
+	                             existsaddrindex, addrindex, or spentindex) on
+	                             start up so it is rebuilt from the main chain
+	                             in the background without affecting the other
+	                             indexes; the index must be enabled
 	    --piperx=                File descriptor of read end pipe to enable
 	                             parent -> child process communication
 	    --pipetx=                File descriptor of write end pipe to enable
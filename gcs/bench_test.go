@@ -159,3 +159,49 @@ func BenchmarkHash(b *testing.B) {
 		globalHashResult = filter.Hash()
 	}
 }
+
+// BenchmarkMatcherSequence benchmarks querying a sequence of filters for a list
+// of values with a matcher.
+func BenchmarkMatcherSequence(b *testing.B) {
+	const numFilters = 100
+	prng := rand.New(rand.NewSource(0))
+	filters := make([]*FilterV2, 0, numFilters)
+	keys := make([][KeySize]byte, numFilters)
+	for i := 0; i < numFilters; i++ {
+		contents, err := genFilterElements(20, prng)
+		if err != nil {
+			b.Fatalf("unable to generate random item: %v", err)
+		}
+		prng.Read(keys[i][:])
+		filter, err := NewFilterV2(benchB, benchM, keys[i], contents)
+		if err != nil {
+			b.Fatalf("Failed to build filter")
+		}
+		filters = append(filters, filter)
+	}
+
+	// Generate matches using a separate prng seed so they're very likely all
+	// misses.
+	matchList, err := genFilterElements(20, rand.New(rand.NewSource(1)))
+	if err != nil {
+		b.Fatalf("unable to generate random item: %v", err)
+	}
+
+	b.Run("MatchAny", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j, filter := range filters {
+				globalMatch = filter.MatchAny(keys[j], matchList)
+			}
+		}
+	})
+
+	b.Run("Matcher", func(b *testing.B) {
+		matcher := NewMatcher(matchList)
+		matches := make([]bool, numFilters)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			matcher.MatchSequenceV2(filters, keys, matches)
+		}
+	})
+}
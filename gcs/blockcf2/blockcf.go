@@ -246,6 +246,24 @@ type PrevScripter interface {
 // For revocations:
 //   - Output scripts that pay the original ticket commitments
 func Regular(block *wire.MsgBlock, prevScripts PrevScripter) (*gcs.FilterV2, error) {
+	return RegularWithParams(block, prevScripts, B, M)
+}
+
+// RegularWithParams builds a GCS filter from a block and the previous output
+// scripts it references as inputs with the same contents and key as Regular,
+// but with the provided tunable bits parameter b and inverse of the target
+// false positive rate m instead of the parameters defined by this package.
+//
+// This allows applications such as private networks and research deployments
+// to trade the size of the filters against their false positive rate.  See
+// gcs.NewFilterV2 for details regarding the parameters along with
+// gcs.OptimalB, gcs.OptimalM, and gcs.EstimateSize for help choosing them.
+//
+// NOTE: Filters built with parameters other than B and M do NOT match the
+// filters that are committed to by blocks, so they are not able to be verified
+// against the block headers and must only be exchanged out of band with
+// parties that use the same parameters.
+func RegularWithParams(block *wire.MsgBlock, prevScripts PrevScripter, b uint8, m uint64) (*gcs.FilterV2, error) {
 	// There will typically be data entries for at least one output and one
 	// input per regular transaction in the block, excepting the coinbase, and
 	// an average of two per stake transaction, though stake transactions vary
@@ -457,5 +475,5 @@ func Regular(block *wire.MsgBlock, prevScripts PrevScripter) (*gcs.FilterV2, err
 	// Create the key by truncating the block's merkle root and use it to create
 	// the filter.
 	key := Key(&block.Header.MerkleRoot)
-	return gcs.NewFilterV2(B, M, key, data)
+	return gcs.NewFilterV2(b, m, key, data)
 }
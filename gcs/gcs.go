@@ -333,11 +333,18 @@ func (f *filter) MatchAny(key [KeySize]byte, data [][]byte) bool {
 	}
 	sort.Sort((*uint64s)(values))
 
+	return f.matchSorted(*values)
+}
+
+// matchSorted checks whether any of the provided hashed and reduced search
+// values, which MUST be sorted in ascending order, is likely to be a member of
+// the set represented by the filter.
+func (f *filter) matchSorted(values []uint64) bool {
 	// Zip down the filters, comparing values until we either run out of
 	// values to compare in one of the filters or we reach a matching
 	// value.
 	b := newBitReader(f.filterData)
-	searchSize := len(data)
+	searchSize := len(values)
 	var searchIdx int
 	var filterVal uint64
 nextFilterVal:
@@ -352,7 +359,7 @@ nextFilterVal:
 		// Iterate through the values to search until either a match is found
 		// or the search value exceeds the current filter value.
 		for ; searchIdx < searchSize; searchIdx++ {
-			searchVal := (*values)[searchIdx]
+			searchVal := values[searchIdx]
 			if searchVal == filterVal {
 				return true
 			}
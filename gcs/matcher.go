@@ -0,0 +1,147 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/dchest/siphash"
+)
+
+// Matcher efficiently checks whether any of a fixed set of data elements is
+// likely (within collision probability) to be a member of the sets represented
+// by many filters.  It is primarily intended for rescanning a sequence of
+// filters for a set of scripts, which would otherwise require calling MatchAny
+// for every filter.
+//
+// The matcher hashes the search elements once per unique filter key and keeps
+// the sorted hashes so that consecutive filters created with the same key only
+// require the hashes to be reduced for the size of each filter before they are
+// merged with the filter contents.  Further, since the reduction function used
+// by version 2 filters preserves the order of the hashes, the reduced values
+// never need to be sorted for them.
+//
+// All state is reused across calls, so matching does not allocate once the
+// matcher has matched against its first filter.
+//
+// A matcher is NOT safe for concurrent access.  Separate matchers must be used
+// by concurrent callers.
+type Matcher struct {
+	data   [][]byte
+	key    [KeySize]byte
+	keyed  bool
+	hashes []uint64
+	values []uint64
+}
+
+// NewMatcher returns a new matcher for the provided data elements.  Empty
+// elements are ignored since they can't possibly match anything.
+//
+// The caller MUST NOT modify the data elements while the matcher is in use.
+func NewMatcher(data [][]byte) *Matcher {
+	return &Matcher{
+		data:   data,
+		hashes: make([]uint64, 0, len(data)),
+		values: make([]uint64, 0, len(data)),
+	}
+}
+
+// setKey hashes the search elements with the provided SipHash key and sorts
+// the resulting hashes when the key differs from the one the current hashes
+// were calculated with.
+func (m *Matcher) setKey(key [KeySize]byte) {
+	if m.keyed && m.key == key {
+		return
+	}
+
+	k0 := binary.LittleEndian.Uint64(key[0:8])
+	k1 := binary.LittleEndian.Uint64(key[8:16])
+	m.hashes = m.hashes[:0]
+	for _, d := range m.data {
+		if len(d) == 0 {
+			continue
+		}
+		m.hashes = append(m.hashes, siphash.Hash(k0, k1, d))
+	}
+	sort.Sort((*uint64s)(&m.hashes))
+	m.key = key
+	m.keyed = true
+}
+
+// match checks whether any of the search elements is likely to be a member of
+// the set represented by the provided filter which was created with the
+// provided key.
+func (m *Matcher) match(f *filter, key [KeySize]byte) bool {
+	// An empty filter or empty data can't possibly match anything.
+	if len(f.filterData) == 0 || len(m.data) == 0 {
+		return false
+	}
+
+	m.setKey(key)
+	if len(m.hashes) == 0 {
+		return false
+	}
+
+	// Reduce the hashes with the same parameters as the filter.  The version
+	// 1 reduction function does not preserve the order of the hashes, so the
+	// reduced values must be sorted for version 1 filters.
+	reduceFn := chooseReduceFunc(f.version)
+	m.values = m.values[:0]
+	for _, h := range m.hashes {
+		m.values = append(m.values, reduceFn(h, f.modulusNM))
+	}
+	if f.version == 1 {
+		sort.Sort((*uint64s)(&m.values))
+	}
+
+	return f.matchSorted(m.values)
+}
+
+// MatchV1 checks whether any of the search elements is likely (within
+// collision probability) to be a member of the set represented by the provided
+// version 1 filter which was created with the provided key.
+//
+// The result is identical to calling MatchAny on the filter with the search
+// elements.
+func (m *Matcher) MatchV1(f *FilterV1, key [KeySize]byte) bool {
+	return m.match(&f.filter, key)
+}
+
+// MatchV2 checks whether any of the search elements is likely (within
+// collision probability) to be a member of the set represented by the provided
+// version 2 filter which was created with the provided key.
+//
+// The result is identical to calling MatchAny on the filter with the search
+// elements.
+func (m *Matcher) MatchV2(f *FilterV2, key [KeySize]byte) bool {
+	return m.match(&f.filter, key)
+}
+
+// MatchSequenceV2 checks whether any of the search elements is likely (within
+// collision probability) to be a member of each of the sets represented by the
+// provided sequence of version 2 filters, which were created with the
+// respective provided keys, and stores the result for each filter in the
+// respective entry of the provided matches slice.  It returns the number of
+// filters that matched.
+//
+// This function will panic if the keys and matches slices do not have the same
+// length as the filters slice.
+func (m *Matcher) MatchSequenceV2(filters []*FilterV2, keys [][KeySize]byte, matches []bool) int {
+	if len(keys) != len(filters) || len(matches) != len(filters) {
+		panic(fmt.Sprintf("mismatched number of filters (%d), keys (%d), "+
+			"and matches (%d)", len(filters), len(keys), len(matches)))
+	}
+
+	var numMatches int
+	for i, f := range filters {
+		matches[i] = m.match(&f.filter, keys[i])
+		if matches[i] {
+			numMatches++
+		}
+	}
+	return numMatches
+}
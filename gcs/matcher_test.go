@@ -0,0 +1,116 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestMatcher ensures matching search elements against a sequence of filters
+// with a matcher produces the same results as calling MatchAny on each filter
+// and does not allocate once it is in use.
+func TestMatcher(t *testing.T) {
+	prng := rand.New(rand.NewSource(0))
+	const numFilters = 50
+	const b, m = 19, 784931
+
+	// Generate filters with a mix of unique and shared keys where every other
+	// filter contains one of the search elements.
+	search, err := genFilterElements(20, prng)
+	if err != nil {
+		t.Fatalf("unable to generate search elements: %v", err)
+	}
+	search = append(search, nil)
+	filtersV1 := make([]*FilterV1, 0, numFilters)
+	filtersV2 := make([]*FilterV2, 0, numFilters)
+	keys := make([][KeySize]byte, 0, numFilters)
+	wantMatches := 0
+	for i := 0; i < numFilters; i++ {
+		var key [KeySize]byte
+		if i%3 != 0 {
+			prng.Read(key[:])
+		}
+		contents, err := genFilterElements(uint(prng.Intn(100)+1), prng)
+		if err != nil {
+			t.Fatalf("unable to generate filter elements: %v", err)
+		}
+		if i%2 == 0 {
+			contents = append(contents, search[prng.Intn(len(search)-1)])
+			wantMatches++
+		}
+
+		f1, err := NewFilterV1(b, key, contents)
+		if err != nil {
+			t.Fatalf("unable to create filter: %v", err)
+		}
+		f2, err := NewFilterV2(b, m, key, contents)
+		if err != nil {
+			t.Fatalf("unable to create filter: %v", err)
+		}
+		filtersV1 = append(filtersV1, f1)
+		filtersV2 = append(filtersV2, f2)
+		keys = append(keys, key)
+	}
+
+	// Ensure the results are the same as matching each filter individually.
+	matcher := NewMatcher(search)
+	for i := range filtersV2 {
+		want := filtersV1[i].MatchAny(keys[i], search)
+		if got := matcher.MatchV1(filtersV1[i], keys[i]); got != want {
+			t.Fatalf("v1 filter %d: mismatched match -- got %v, want %v", i,
+				got, want)
+		}
+		want = filtersV2[i].MatchAny(keys[i], search)
+		if got := matcher.MatchV2(filtersV2[i], keys[i]); got != want {
+			t.Fatalf("v2 filter %d: mismatched match -- got %v, want %v", i,
+				got, want)
+		}
+		if i%2 == 0 && !want {
+			t.Fatalf("v2 filter %d: did not match contained element", i)
+		}
+	}
+	matches := make([]bool, numFilters)
+	numMatches := matcher.MatchSequenceV2(filtersV2, keys, matches)
+	if numMatches < wantMatches {
+		t.Fatalf("unexpected number of matches -- got %d, want at least %d",
+			numMatches, wantMatches)
+	}
+	for i, matched := range matches {
+		if want := filtersV2[i].MatchAny(keys[i], search); matched != want {
+			t.Fatalf("filter %d: mismatched sequence match -- got %v, want "+
+				"%v", i, matched, want)
+		}
+	}
+
+	// Ensure matching does not allocate.
+	allocs := testing.AllocsPerRun(10, func() {
+		matcher.MatchSequenceV2(filtersV2, keys, matches)
+	})
+	if allocs != 0 {
+		t.Fatalf("unexpected allocations -- got %v, want 0", allocs)
+	}
+
+	// Ensure empty filters and matchers without usable elements never match.
+	emptyFilter, err := NewFilterV2(b, m, keys[0], nil)
+	if err != nil {
+		t.Fatalf("unable to create filter: %v", err)
+	}
+	if matcher.MatchV2(emptyFilter, keys[0]) {
+		t.Fatal("empty filter matched")
+	}
+	emptyMatcher := NewMatcher([][]byte{nil, {}})
+	if emptyMatcher.MatchV2(filtersV2[0], keys[0]) {
+		t.Fatal("matcher without usable elements matched")
+	}
+
+	// Ensure mismatched sequence lengths panic.
+	defer func() {
+		if recover() == nil {
+			t.Fatal("did not panic for mismatched sequence lengths")
+		}
+	}()
+	matcher.MatchSequenceV2(filtersV2, keys[1:], matches)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"math"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// OptimalB returns the optimal value of the tunable bits parameter B for
+// constructing version 2 filters that minimizes the size of the filter for the
+// provided inverse of the target false positive rate M.  It is calculated as
+// floor(log_2(M) - 0.055256) and clamped to the range [0, 32].
+func OptimalB(M uint64) uint8 {
+	if M < 2 {
+		return 0
+	}
+	b := math.Floor(math.Log2(float64(M)) - 0.055256)
+	switch {
+	case b < 0:
+		return 0
+	case b > 32:
+		return 32
+	}
+	return uint8(b)
+}
+
+// OptimalM returns the optimal value of the inverse of the target false
+// positive rate M for constructing version 2 filters that minimizes the size of
+// the filter for the provided tunable bits parameter B.  It is calculated as
+// ceil(1.497137 * 2^B).
+//
+// Values of B greater than 32 are treated as 32 since that is the maximum
+// allowed value for B.
+func OptimalM(B uint8) uint64 {
+	if B > 32 {
+		B = 32
+	}
+	return uint64(math.Ceil(1.497137 * float64(uint64(1)<<B)))
+}
+
+// EstimateSize returns the approximate size in bytes of the serialized form of
+// a version 2 filter constructed with the provided tunable bits parameter B and
+// inverse of the target false positive rate M that contains the provided number
+// of unique items.
+//
+// The estimate models the differences between the sorted values in the filter
+// as geometrically distributed with a mean of M, which is accurate for all but
+// very small numbers of items.  It is intended to help choose parameters that
+// balance the bandwidth required to transmit filters against their false
+// positive rate.
+func EstimateSize(B uint8, M uint64, n uint32) uint64 {
+	// Empty filters have no serialized data.
+	if n == 0 || M == 0 {
+		return 0
+	}
+	if B > 32 {
+		B = 32
+	}
+
+	// Each item is encoded with a B-bit remainder and a quotient in unary that
+	// takes one bit more than its value.  The quotient for differences that
+	// are geometrically distributed with a mean of M is also geometrically
+	// distributed with a success probability of p = 1 - e^(-2^B/M) and thus
+	// has an expected value of (1 - p) / p.
+	x := float64(uint64(1)<<B) / float64(M)
+	p := -math.Expm1(-x)
+	bitsPerItem := float64(B) + 1 + math.Exp(-x)/p
+	dataBytes := uint64(math.Ceil(float64(n) * bitsPerItem / 8))
+	return uint64(wire.VarIntSerializeSize(uint64(n))) + dataBytes
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestOptimalParams ensures the optimal parameter calculations produce the
+// expected values including the parameters used for block filters.
+func TestOptimalParams(t *testing.T) {
+	tests := []struct {
+		b uint8
+		m uint64
+	}{
+		{b: 0, m: 2},
+		{b: 10, m: 1534},
+		{b: 19, m: 784931},
+		{b: 20, m: 1569862},
+		{b: 32, m: 6430154453},
+	}
+	for _, test := range tests {
+		if got := OptimalM(test.b); got != test.m {
+			t.Errorf("OptimalM(%d): got %d, want %d", test.b, got, test.m)
+		}
+		if got := OptimalB(test.m); got != test.b {
+			t.Errorf("OptimalB(%d): got %d, want %d", test.m, got, test.b)
+		}
+	}
+
+	// Ensure out of range values are clamped.
+	if got := OptimalM(40); got != OptimalM(32) {
+		t.Errorf("OptimalM(40): got %d, want %d", got, OptimalM(32))
+	}
+	if got := OptimalB(0); got != 0 {
+		t.Errorf("OptimalB(0): got %d, want 0", got)
+	}
+	if got := OptimalB(^uint64(0)); got != 32 {
+		t.Errorf("OptimalB(max): got %d, want 32", got)
+	}
+}
+
+// TestEstimateSize ensures the estimated filter sizes are close to the actual
+// sizes of filters constructed with various parameters.
+func TestEstimateSize(t *testing.T) {
+	prng := rand.New(rand.NewSource(0))
+	tests := []struct {
+		b uint8
+		m uint64
+		n uint32
+	}{
+		{b: 19, m: 784931, n: 1000},
+		{b: 19, m: 784931, n: 20000},
+		{b: 10, m: OptimalM(10), n: 5000},
+		{b: 24, m: OptimalM(24), n: 5000},
+		{b: 16, m: 1 << 20, n: 5000},
+	}
+	for _, test := range tests {
+		contents, err := genFilterElements(uint(test.n), prng)
+		if err != nil {
+			t.Fatalf("unable to generate filter elements: %v", err)
+		}
+		var key [KeySize]byte
+		f, err := NewFilterV2(test.b, test.m, key, contents)
+		if err != nil {
+			t.Fatalf("unable to create filter: %v", err)
+		}
+
+		// Allow the estimate to differ by up to 2% of the actual size.
+		actual := uint64(len(f.Bytes()))
+		estimate := EstimateSize(test.b, test.m, test.n)
+		diff := int64(estimate) - int64(actual)
+		if diff < 0 {
+			diff = -diff
+		}
+		if uint64(diff)*50 > actual {
+			t.Errorf("B=%d, M=%d, N=%d: estimate %d is not close to actual "+
+				"size %d", test.b, test.m, test.n, estimate, actual)
+		}
+	}
+
+	// Ensure empty filters are estimated to have no serialized data.
+	if got := EstimateSize(19, 784931, 0); got != 0 {
+		t.Errorf("unexpected size for empty filter: got %d, want 0", got)
+	}
+}
@@ -46,6 +46,23 @@ the HardenedKeyStart constant + the hardened key number as the index to the
 Child function.  This provides the ability to cascade the keys into a tree and
 hence generate the hierarchical deterministic key chains.
 
+# Derivation Paths
+
+Derivation path strings, such as "m/44'/42'/0'/0/5", may be parsed with
+ParseDerivationPath and the resulting DerivationPath used to derive the
+identified extended key with the DerivePath function.  Hardened indices may be
+marked with any of ', h, or H.  The DerivePaths function derives the keys for
+multiple paths at once while only deriving the intermediate keys they share a
+single time.
+
+# Ed25519 Extended Keys
+
+Hardened ed25519 extended keys may be derived as specified by SLIP-0010 by
+creating a master node with NewEd25519Master and deriving its children with the
+Child or DerivePath functions of the resulting Ed25519ExtendedKey.  Only
+hardened children are supported since ed25519 does not support deriving child
+public keys from a parent public key.
+
 # BIP0032 Conformity
 
 The Child function derives extended keys with a modified scheme based on
@@ -0,0 +1,153 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath describes an error in which a derivation path string is not
+// properly formatted or contains an index that is out of range.
+var ErrInvalidPath = errors.New("invalid derivation path")
+
+// DerivationPath is a sequence of child indices that identifies an extended key
+// relative to another one, typically the master node.  Hardened indices are
+// HardenedKeyStart or greater.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses the provided derivation path string, such as
+// "m/44'/42'/0'/0/5", into its child indices.
+//
+// The components of the path are separated by slashes and each one is a
+// decimal index less than HardenedKeyStart that is optionally followed by a
+// hardened marker, which may be any of ', h, or H.  The path may optionally
+// start with an "m" component to indicate it is relative to the master node,
+// and "m" by itself is the empty path.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	components := strings.Split(path, "/")
+	if components[0] == "m" {
+		components = components[1:]
+	}
+
+	parsed := make(DerivationPath, 0, len(components))
+	for _, component := range components {
+		hardened := false
+		index := component
+		if n := len(index); n > 0 {
+			switch index[n-1] {
+			case '\'', 'h', 'H':
+				hardened = true
+				index = index[:n-1]
+			}
+		}
+
+		// Only plain decimal digits are allowed.  Notably, this means signs
+		// and whitespace are rejected.
+		if index == "" || strings.TrimLeft(index, "0123456789") != "" {
+			return nil, fmt.Errorf("%w: component %q of path %q is not a "+
+				"valid index", ErrInvalidPath, component, path)
+		}
+		value, err := strconv.ParseUint(index, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("%w: component %q of path %q exceeds the "+
+				"maximum index of %d", ErrInvalidPath, component, path,
+				HardenedKeyStart-1)
+		}
+		if hardened {
+			value += HardenedKeyStart
+		}
+		parsed = append(parsed, uint32(value))
+	}
+	return parsed, nil
+}
+
+// String returns the derivation path as a string that is relative to the
+// master node and marks hardened indices with an apostrophe.  For example,
+// "m/44'/42'/0'/0/5".
+func (p DerivationPath) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range p {
+		sb.WriteByte('/')
+		if index >= HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(index-HardenedKeyStart), 10))
+			sb.WriteByte('\'')
+			continue
+		}
+		sb.WriteString(strconv.FormatUint(uint64(index), 10))
+	}
+	return sb.String()
+}
+
+// DerivePath derives the extended key identified by the provided derivation
+// path relative to the extended key by deriving each child in turn with Child.
+//
+// An error is returned when any of the children can't be derived.  See Child
+// for details.
+func (k *ExtendedKey) DerivePath(path DerivationPath) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// DerivePaths derives the extended keys identified by each of the provided
+// derivation paths relative to the extended key and returns them in the same
+// order.
+//
+// It is equivalent to calling DerivePath for each path, except the
+// intermediate keys shared by multiple paths are only derived once, which is
+// significantly faster when deriving many keys from the same branch, such as
+// all of the addresses of an account.
+//
+// NOTE: Paths that are duplicated or that are a prefix of another one result
+// in the same returned instance for each of them, so care must be taken when
+// zeroing the returned keys.
+func (k *ExtendedKey) DerivePaths(paths []DerivationPath) ([]*ExtendedKey, error) {
+	// Cache the derived intermediate keys keyed by their path.
+	type cacheEntry struct {
+		key *ExtendedKey
+		err error
+	}
+	cache := make(map[string]cacheEntry)
+
+	// deriveCached derives the key at the provided path while caching all of
+	// the intermediate keys.
+	var deriveCached func(path DerivationPath) (*ExtendedKey, error)
+	deriveCached = func(path DerivationPath) (*ExtendedKey, error) {
+		if len(path) == 0 {
+			return k, nil
+		}
+		cacheKey := path.String()
+		if entry, ok := cache[cacheKey]; ok {
+			return entry.key, entry.err
+		}
+		parent, err := deriveCached(path[:len(path)-1])
+		var key *ExtendedKey
+		if err == nil {
+			key, err = parent.Child(path[len(path)-1])
+		}
+		cache[cacheKey] = cacheEntry{key: key, err: err}
+		return key, err
+	}
+
+	keys := make([]*ExtendedKey, 0, len(paths))
+	for _, path := range paths {
+		key, err := deriveCached(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive %v: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestParseDerivationPath ensures derivation path strings are parsed and
+// formatted as expected and that invalid paths are rejected.
+func TestParseDerivationPath(t *testing.T) {
+	const hkStart = HardenedKeyStart
+	tests := []struct {
+		name string         // test description
+		path string         // path string to parse
+		want DerivationPath // expected parsed path
+		str  string         // expected string encoding
+		err  error          // expected error
+	}{{
+		name: "master only",
+		path: "m",
+		want: DerivationPath{},
+		str:  "m",
+	}, {
+		name: "bip44 account address",
+		path: "m/44'/42'/0'/0/5",
+		want: DerivationPath{hkStart + 44, hkStart + 42, hkStart, 0, 5},
+		str:  "m/44'/42'/0'/0/5",
+	}, {
+		name: "alternate hardened markers",
+		path: "m/44h/42H/0'",
+		want: DerivationPath{hkStart + 44, hkStart + 42, hkStart},
+		str:  "m/44'/42'/0'",
+	}, {
+		name: "relative path",
+		path: "0/5",
+		want: DerivationPath{0, 5},
+		str:  "m/0/5",
+	}, {
+		name: "maximum indices",
+		path: "m/2147483647/2147483647'",
+		want: DerivationPath{hkStart - 1, 1<<32 - 1},
+		str:  "m/2147483647/2147483647'",
+	}, {
+		name: "empty",
+		path: "",
+		err:  ErrInvalidPath,
+	}, {
+		name: "trailing slash",
+		path: "m/0/",
+		err:  ErrInvalidPath,
+	}, {
+		name: "master not first",
+		path: "0/m",
+		err:  ErrInvalidPath,
+	}, {
+		name: "index too large",
+		path: "m/2147483648",
+		err:  ErrInvalidPath,
+	}, {
+		name: "hardened index too large",
+		path: "m/2147483648'",
+		err:  ErrInvalidPath,
+	}, {
+		name: "hardened marker only",
+		path: "m/'",
+		err:  ErrInvalidPath,
+	}, {
+		name: "double hardened marker",
+		path: "m/0''",
+		err:  ErrInvalidPath,
+	}, {
+		name: "sign",
+		path: "m/+1",
+		err:  ErrInvalidPath,
+	}, {
+		name: "whitespace",
+		path: "m/ 1",
+		err:  ErrInvalidPath,
+	}}
+
+	for _, test := range tests {
+		got, err := ParseDerivationPath(test.path)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: mismatched error -- got %v, want %v", test.name, err,
+				test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: mismatched path -- got %v, want %v", test.name,
+				[]uint32(got), []uint32(test.want))
+			continue
+		}
+		if got.String() != test.str {
+			t.Errorf("%q: mismatched string -- got %s, want %s", test.name,
+				got.String(), test.str)
+		}
+	}
+}
+
+// TestDerivePaths ensures deriving extended keys by their paths, both
+// individually and in batches, produces the same keys as deriving each child in
+// turn.
+func TestDerivePaths(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMaster(seed, mockMainNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// deriveChildren derives the provided path one child at a time.
+	deriveChildren := func(path DerivationPath) *ExtendedKey {
+		t.Helper()
+		key := master
+		for _, index := range path {
+			key, err = key.Child(index)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return key
+	}
+
+	pathStrs := []string{"m/44'/42'/0'/0/0", "m/44'/42'/0'/0/1", "m",
+		"m/44'/42'/0'/1/0", "m/44'/42'/0'/0/1", "m/44'/42'/1'", "m/0/1/2"}
+	var paths []DerivationPath
+	for _, pathStr := range pathStrs {
+		path, err := ParseDerivationPath(pathStr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", pathStr, err)
+		}
+		paths = append(paths, path)
+	}
+
+	keys, err := master.DerivePaths(paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != len(paths) {
+		t.Fatalf("mismatched number of keys -- got %d, want %d", len(keys),
+			len(paths))
+	}
+	for i, path := range paths {
+		want := deriveChildren(path).String()
+		key, err := master.DerivePath(path)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", pathStrs[i], err)
+		}
+		if got := key.String(); got != want {
+			t.Errorf("%q: mismatched key -- got %s, want %s", pathStrs[i], got,
+				want)
+		}
+		if got := keys[i].String(); got != want {
+			t.Errorf("%q: mismatched batch key -- got %s, want %s",
+				pathStrs[i], got, want)
+		}
+	}
+
+	// Ensure hardened paths can't be derived from public keys.
+	pubKey := master.Neuter()
+	hardened := DerivationPath{0, HardenedKeyStart}
+	if _, err := pubKey.DerivePath(hardened); !errors.Is(err,
+		ErrDeriveHardFromPublic) {
+
+		t.Fatalf("mismatched error -- got %v, want %v", err,
+			ErrDeriveHardFromPublic)
+	}
+	_, err = pubKey.DerivePaths([]DerivationPath{{0}, hardened})
+	if !errors.Is(err, ErrDeriveHardFromPublic) {
+		t.Fatalf("mismatched batch error -- got %v, want %v", err,
+			ErrDeriveHardFromPublic)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+// References:
+//   [SLIP10]: SLIP-0010 - Universal private key derivation from master private key
+//   https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/decred/dcrd/crypto/ripemd160"
+)
+
+// ErrDeriveNonHardenedEd25519 describes an error in which the caller attempted
+// to derive a non-hardened ed25519 extended key, which is not supported by
+// [SLIP10].
+var ErrDeriveNonHardenedEd25519 = errors.New("cannot derive a non-hardened " +
+	"ed25519 key")
+
+// ed25519MasterKey is the key used along with a seed to generate the master
+// node of a hierarchical tree of ed25519 keys per [SLIP10].
+var ed25519MasterKey = []byte("ed25519 seed")
+
+// Ed25519ExtendedKey houses all the information needed to support a
+// hierarchical deterministic extended ed25519 private key as specified by
+// [SLIP10].
+//
+// Unlike secp256k1 extended keys, only hardened children may be derived and
+// there are no extended public keys since ed25519 does not support deriving
+// child public keys from a parent public key.  This also means every possible
+// seed and child index results in a valid key.
+type Ed25519ExtendedKey struct {
+	key       [32]byte
+	chainCode [32]byte
+	parentFP  uint32
+	childNum  uint32
+	depth     uint16
+}
+
+// NewEd25519Master creates a new master node for use in creating a
+// hierarchical deterministic key chain of ed25519 keys per [SLIP10].  The seed
+// must be between 128 and 512 bits and should be generated by a
+// cryptographically secure random generation source.
+func NewEd25519Master(seed []byte) (*Ed25519ExtendedKey, error) {
+	// Per [SLIP10], the seed must be in range [MinSeedBytes, MaxSeedBytes].
+	if len(seed) < MinSeedBytes || len(seed) > MaxSeedBytes {
+		return nil, ErrInvalidSeedLen
+	}
+
+	// First take the HMAC-SHA512 of the master key and the seed data:
+	//   I = HMAC-SHA512(Key = "ed25519 seed", Data = S)
+	hmac512 := hmac.New(sha512.New, ed25519MasterKey)
+	hmac512.Write(seed)
+	lr := hmac512.Sum(nil)
+	defer zero(lr)
+
+	// Split "I" into two 32-byte sequences Il and Ir where:
+	//   Il = master secret key
+	//   Ir = master chain code
+	var k Ed25519ExtendedKey
+	copy(k.key[:], lr[:len(lr)/2])
+	copy(k.chainCode[:], lr[len(lr)/2:])
+	return &k, nil
+}
+
+// ChildNum returns the child number of the extended key.
+func (k *Ed25519ExtendedKey) ChildNum() uint32 {
+	return k.childNum
+}
+
+// Depth returns the depth of the extended key.
+func (k *Ed25519ExtendedKey) Depth() uint16 {
+	return k.depth
+}
+
+// ParentFingerprint returns a fingerprint of the parent extended key from which
+// this one was derived.
+func (k *Ed25519ExtendedKey) ParentFingerprint() uint32 {
+	return k.parentFP
+}
+
+// ChainCode returns a copy of the chain code of the extended key.
+func (k *Ed25519ExtendedKey) ChainCode() []byte {
+	chainCode := make([]byte, len(k.chainCode))
+	copy(chainCode, k.chainCode[:])
+	return chainCode
+}
+
+// PrivateKey returns the ed25519 private key of the extended key.  Note that
+// the key of the extended key is the seed of the returned private key.
+func (k *Ed25519ExtendedKey) PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.key[:])
+}
+
+// PublicKey returns the ed25519 public key of the extended key.
+func (k *Ed25519ExtendedKey) PublicKey() ed25519.PublicKey {
+	return k.PrivateKey().Public().(ed25519.PublicKey)
+}
+
+// SerializedPubKey returns the public key of the extended key serialized as
+// specified by [SLIP10], which is a zero byte followed by the 32-byte public
+// key.
+func (k *Ed25519ExtendedKey) SerializedPubKey() []byte {
+	serialized := make([]byte, 1, 1+ed25519.PublicKeySize)
+	return append(serialized, k.PublicKey()...)
+}
+
+// fingerprint returns the fingerprint of the extended key which is the first
+// four bytes of RIPEMD160(SHA256(serialized public key)) as specified by
+// [SLIP10].
+func (k *Ed25519ExtendedKey) fingerprint() uint32 {
+	sha256Hash := sha256.Sum256(k.SerializedPubKey())
+	h := ripemd160.New()
+	h.Write(sha256Hash[:])
+	return binary.BigEndian.Uint32(h.Sum(nil))
+}
+
+// Child derives the hardened child extended key at the provided index, which
+// must be HardenedKeyStart or greater, per [SLIP10].  ErrDeriveNonHardenedEd25519
+// is returned for non-hardened indices.
+func (k *Ed25519ExtendedKey) Child(i uint32) (*Ed25519ExtendedKey, error) {
+	if i < HardenedKeyStart {
+		return nil, ErrDeriveNonHardenedEd25519
+	}
+
+	// The data used to derive the child key is:
+	//   0x00 || ser256(parentKey) || ser32(i)
+	var data [1 + 32 + 4]byte
+	copy(data[1:], k.key[:])
+	binary.BigEndian.PutUint32(data[33:], i)
+	defer zero(data[:])
+
+	// Take the HMAC-SHA512 of the current key's chain code and the derived
+	// data:
+	//   I = HMAC-SHA512(Key = chainCode, Data = data)
+	hmac512 := hmac.New(sha512.New, k.chainCode[:])
+	hmac512.Write(data[:])
+	ilr := hmac512.Sum(nil)
+	defer zero(ilr)
+
+	// Split "I" into two 32-byte sequences Il and Ir where:
+	//   Il = child secret key
+	//   Ir = child chain code
+	child := Ed25519ExtendedKey{
+		parentFP: k.fingerprint(),
+		childNum: i,
+		depth:    k.depth + 1,
+	}
+	copy(child.key[:], ilr[:len(ilr)/2])
+	copy(child.chainCode[:], ilr[len(ilr)/2:])
+	return &child, nil
+}
+
+// DerivePath derives the extended key identified by the provided derivation
+// path relative to the extended key by deriving each child in turn with Child.
+// All of the indices in the path must be hardened.
+func (k *Ed25519ExtendedKey) DerivePath(path DerivationPath) (*Ed25519ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Zero manually clears all fields and bytes in the extended key.  This can be
+// used to explicitly clear key material from memory for enhanced security
+// against memory scraping.  This function only clears this particular key and
+// not any children that have already been derived.
+func (k *Ed25519ExtendedKey) Zero() {
+	*k = Ed25519ExtendedKey{}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hdkeychain
+
+// References:
+//   [SLIP10]: SLIP-0010 - Universal private key derivation from master private key
+//   https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// TestSLIP0010Ed25519Vectors ensures the ed25519 extended keys derived from
+// the test vectors in [SLIP10] match the expected values.
+func TestSLIP0010Ed25519Vectors(t *testing.T) {
+	tests := []struct {
+		name      string // test description
+		seed      string // hex encoded seed
+		path      string // derivation path
+		fp        uint32 // expected parent fingerprint
+		chainCode string // expected hex encoded chain code
+		privKey   string // expected hex encoded private key
+		pubKey    string // expected hex encoded serialized public key
+	}{{
+		name:      "test vector 1 chain m",
+		seed:      "000102030405060708090a0b0c0d0e0f",
+		path:      "m",
+		fp:        0x00000000,
+		chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+		privKey:   "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+		pubKey:    "00a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+	}, {
+		name:      "test vector 1 chain m/0H",
+		seed:      "000102030405060708090a0b0c0d0e0f",
+		path:      "m/0H",
+		fp:        0xddebc675,
+		chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+		privKey:   "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+		pubKey:    "008c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+	}, {
+		name:      "test vector 1 chain m/0H/1H",
+		seed:      "000102030405060708090a0b0c0d0e0f",
+		path:      "m/0H/1H",
+		fp:        0x13dab143,
+		chainCode: "a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+		privKey:   "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2",
+		pubKey:    "001932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+	}, {
+		name:      "test vector 1 chain m/0H/1H/2H/2H/1000000000H",
+		seed:      "000102030405060708090a0b0c0d0e0f",
+		path:      "m/0H/1H/2H/2H/1000000000H",
+		fp:        0xd6322ccd,
+		chainCode: "68789923a0cac2cd5a29172a475fe9e0fb14cd6adb5ad98a3fa70333e7afa230",
+		privKey:   "8f94d394a8e8fd6b1bc2f3f49f5c47e385281d5c17e65324b0f62483e37e8793",
+		pubKey:    "003c24da049451555d51a7014a37337aa4e12d41e485abccfa46b47dfb2af54b7a",
+	}}
+
+	for _, test := range tests {
+		seed, _ := hex.DecodeString(test.seed)
+		master, err := NewEd25519Master(seed)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		path, err := ParseDerivationPath(test.path)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		key, err := master.DerivePath(path)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if key.ParentFingerprint() != test.fp {
+			t.Errorf("%q: mismatched parent fingerprint -- got %08x, want "+
+				"%08x", test.name, key.ParentFingerprint(), test.fp)
+		}
+		if got := hex.EncodeToString(key.ChainCode()); got != test.chainCode {
+			t.Errorf("%q: mismatched chain code -- got %s, want %s",
+				test.name, got, test.chainCode)
+		}
+		privKey := key.PrivateKey()
+		if got := hex.EncodeToString(privKey.Seed()); got != test.privKey {
+			t.Errorf("%q: mismatched private key -- got %s, want %s",
+				test.name, got, test.privKey)
+		}
+		if got := hex.EncodeToString(key.SerializedPubKey()); got != test.pubKey {
+			t.Errorf("%q: mismatched public key -- got %s, want %s",
+				test.name, got, test.pubKey)
+		}
+		if key.Depth() != uint16(len(path)) {
+			t.Errorf("%q: mismatched depth -- got %d, want %d", test.name,
+				key.Depth(), len(path))
+		}
+		wantChildNum := uint32(0)
+		if len(path) > 0 {
+			wantChildNum = path[len(path)-1]
+		}
+		if key.ChildNum() != wantChildNum {
+			t.Errorf("%q: mismatched child num -- got %d, want %d", test.name,
+				key.ChildNum(), wantChildNum)
+		}
+
+		// Ensure the keys are usable for signing.
+		msg := []byte("test message")
+		sig := ed25519.Sign(privKey, msg)
+		if !ed25519.Verify(key.PublicKey(), msg, sig) {
+			t.Errorf("%q: failed to verify signature", test.name)
+		}
+	}
+}
+
+// TestEd25519ExtendedKeyErrors ensures the ed25519 extended key API returns the
+// expected errors.
+func TestEd25519ExtendedKeyErrors(t *testing.T) {
+	// Ensure seeds with invalid lengths are rejected.
+	for _, seedLen := range []int{MinSeedBytes - 1, MaxSeedBytes + 1} {
+		_, err := NewEd25519Master(make([]byte, seedLen))
+		if !errors.Is(err, ErrInvalidSeedLen) {
+			t.Fatalf("mismatched error for seed length %d -- got %v, want %v",
+				seedLen, err, ErrInvalidSeedLen)
+		}
+	}
+
+	// Ensure non-hardened children can't be derived.
+	master, err := NewEd25519Master(make([]byte, RecommendedSeedLen))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = master.DerivePath(DerivationPath{HardenedKeyStart, 1})
+	if !errors.Is(err, ErrDeriveNonHardenedEd25519) {
+		t.Fatalf("mismatched error -- got %v, want %v", err,
+			ErrDeriveNonHardenedEd25519)
+	}
+
+	// Ensure zeroing the key clears it.
+	master.Zero()
+	if !bytes.Equal(master.ChainCode(), make([]byte, 32)) || master.Depth() != 0 {
+		t.Fatal("key was not zeroed")
+	}
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package banmgr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/connmgr/v3"
+)
+
+const (
+	// banFileVersion is the current version of the serialized ban file.
+	banFileVersion = 1
+
+	// saveDelay is the amount of time to wait after a change to the bans
+	// before persisting them so that bursts of changes, such as when several
+	// misbehaving peers are banned at once, only result in a single write.
+	saveDelay = 5 * time.Second
+)
+
+var (
+	// ErrInvalidSubnet indicates a provided address or subnet could not be
+	// parsed.
+	ErrInvalidSubnet = errors.New("invalid address or subnet")
+
+	// ErrNotBanned indicates an attempt to remove a ban for an address or
+	// subnet that is not banned.
+	ErrNotBanned = errors.New("address or subnet is not banned")
+
+	// ErrInvalidBanFile indicates the ban file is malformed or of an
+	// unsupported version.
+	ErrInvalidBanFile = errors.New("invalid ban file")
+)
+
+// Reason identifies why an address or subnet is banned.
+type Reason uint8
+
+const (
+	// ReasonManual indicates the ban was explicitly requested, such as via
+	// the setban RPC.
+	ReasonManual Reason = iota
+
+	// ReasonMisbehaving indicates a peer in the banned range was discouraged
+	// as a result of its misbehavior score exceeding the ban threshold.
+	ReasonMisbehaving
+)
+
+// reasonStrings is a map of ban reasons back to their constant names for pretty
+// printing.
+var reasonStrings = map[Reason]string{
+	ReasonManual:      "manual",
+	ReasonMisbehaving: "misbehaving",
+}
+
+// String returns the Reason in human-readable form.
+func (r Reason) String() string {
+	if s, ok := reasonStrings[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown Reason (%d)", uint8(r))
+}
+
+// parseReason returns the Reason associated with the provided human-readable
+// form.
+func parseReason(s string) (Reason, bool) {
+	for r, str := range reasonStrings {
+		if str == s {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// Scorer tracks the misbehavior of a peer.  Implementations determine how the
+// score is calculated, including how, if at all, it decays over time.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type Scorer interface {
+	// Int returns the current misbehavior score.
+	Int() uint32
+
+	// Increase increases the score by the provided persistent and transient
+	// amounts and returns the resulting score.
+	Increase(persistent, transient uint32) uint32
+
+	// Reset sets the score to zero.
+	Reset()
+}
+
+// Ensure connmgr.DynamicBanScore implements the Scorer interface.
+var _ Scorer = (*connmgr.DynamicBanScore)(nil)
+
+// Entry describes a banned address or subnet.
+type Entry struct {
+	// Subnet is the banned range of addresses.  Individual addresses are
+	// represented by a subnet with a full mask.
+	Subnet *net.IPNet
+
+	// Created is the time the ban was created.
+	Created time.Time
+
+	// Expires is the time the ban is lifted.
+	Expires time.Time
+
+	// Reason is the reason for the ban.
+	Reason Reason
+}
+
+// Config houses the parameters used to create a ban manager.
+type Config struct {
+	// BanFile is the path of the file the bans are persisted to.  Bans are
+	// only kept in memory when it is empty.
+	BanFile string
+
+	// NewScorer returns a new instance of the scorer used to track the
+	// misbehavior of a peer.  A connmgr.DynamicBanScore, which consists of a
+	// persistent component and a transient component that decays over time,
+	// is used when it is nil.
+	NewScorer func() Scorer
+
+	// TimeNow returns the current time.  time.Now is used when it is nil.
+	TimeNow func() time.Time
+}
+
+// Manager tracks banned addresses and subnets and persists them across
+// restarts.  It is safe for concurrent access.
+type Manager struct {
+	cfg Config
+
+	// saveNeeded is signalled when the bans change so they are persisted by
+	// Run and saveMtx serializes writes to the ban file.
+	saveNeeded chan struct{}
+	saveMtx    sync.Mutex
+
+	// bans houses all bans keyed by their subnet while subnets only houses
+	// the bans that cover more than a single address.  This allows bans for
+	// individual addresses, which are by far the most common, to be looked up
+	// directly while only the subnet bans need to be searched.
+	//
+	// dirty indicates there are changes to the bans that have not yet been
+	// persisted.
+	mtx     sync.Mutex
+	bans    map[string]*Entry
+	subnets map[string]*Entry
+	dirty   bool
+}
+
+// New returns a new ban manager with the provided configuration.  Any bans
+// previously persisted must be loaded via Load and Run must be running for
+// changes to be persisted.
+func New(cfg *Config) *Manager {
+	m := &Manager{
+		cfg:        *cfg,
+		saveNeeded: make(chan struct{}, 1),
+		bans:       make(map[string]*Entry),
+		subnets:    make(map[string]*Entry),
+	}
+	if m.cfg.NewScorer == nil {
+		m.cfg.NewScorer = func() Scorer { return new(connmgr.DynamicBanScore) }
+	}
+	if m.cfg.TimeNow == nil {
+		m.cfg.TimeNow = time.Now
+	}
+	return m
+}
+
+// NewScorer returns a new instance of the configured scorer used to track the
+// misbehavior of a peer.
+func (m *Manager) NewScorer() Scorer {
+	return m.cfg.NewScorer()
+}
+
+// normalizeSubnet returns the provided subnet with its address masked and, in
+// the case of IPv4, converted to its 4-byte representation so that equivalent
+// subnets are keyed the same.
+func normalizeSubnet(subnet *net.IPNet) *net.IPNet {
+	ip, mask := subnet.IP, subnet.Mask
+	if ip4 := ip.To4(); ip4 != nil && len(mask) == net.IPv6len {
+		ones, _ := mask.Size()
+		if ones >= 96 {
+			ip, mask = ip4, net.CIDRMask(ones-96, 32)
+		}
+	} else if ip4 != nil {
+		ip = ip4
+	}
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
+// SubnetForIP returns a subnet that consists of only the provided address.
+func SubnetForIP(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// ParseSubnet parses the provided string as either an individual IP address or
+// a subnet in CIDR notation.
+func ParseSubnet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, subnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSubnet, s)
+		}
+		return normalizeSubnet(subnet), nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSubnet, s)
+	}
+	return SubnetForIP(ip), nil
+}
+
+// isSingleAddress returns whether or not the provided subnet consists of only
+// a single address.
+func isSingleAddress(subnet *net.IPNet) bool {
+	ones, bits := subnet.Mask.Size()
+	return ones == bits
+}
+
+// addEntry adds the provided ban while replacing any existing ban for the same
+// subnet.
+//
+// This function MUST be called with the manager mutex held (for writes).
+func (m *Manager) addEntry(entry *Entry) {
+	key := entry.Subnet.String()
+	m.bans[key] = entry
+	if !isSingleAddress(entry.Subnet) {
+		m.subnets[key] = entry
+	}
+}
+
+// removeEntry removes the ban with the provided key.
+//
+// This function MUST be called with the manager mutex held (for writes).
+func (m *Manager) removeEntry(key string) {
+	delete(m.bans, key)
+	delete(m.subnets, key)
+}
+
+// pruneExpired removes all bans that have expired.
+//
+// This function MUST be called with the manager mutex held (for writes).
+func (m *Manager) pruneExpired(now time.Time) {
+	for key, entry := range m.bans {
+		if !now.Before(entry.Expires) {
+			m.removeEntry(key)
+		}
+	}
+}
+
+// markDirty notes that the bans have changed and signals Run to persist them
+// when a ban file is configured.
+//
+// This function MUST be called with the manager mutex held (for writes).
+func (m *Manager) markDirty() {
+	if m.cfg.BanFile == "" {
+		return
+	}
+	m.dirty = true
+	select {
+	case m.saveNeeded <- struct{}{}:
+	default:
+	}
+}
+
+// Ban bans the provided subnet until the given expiration time for the
+// provided reason.  Any existing ban for the same subnet is replaced.  The ban
+// is in effect immediately and is persisted shortly after by Run.
+func (m *Manager) Ban(subnet *net.IPNet, expires time.Time, reason Reason) {
+	subnet = normalizeSubnet(subnet)
+
+	m.mtx.Lock()
+	m.addEntry(&Entry{
+		Subnet:  subnet,
+		Created: m.cfg.TimeNow(),
+		Expires: expires,
+		Reason:  reason,
+	})
+	m.markDirty()
+	m.mtx.Unlock()
+}
+
+// Unban removes the ban for the provided subnet.  The removal is persisted
+// shortly after by Run.
+//
+// ErrNotBanned is returned when there is no ban for the exact subnet.
+func (m *Manager) Unban(subnet *net.IPNet) error {
+	subnet = normalizeSubnet(subnet)
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	key := subnet.String()
+	if _, ok := m.bans[key]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotBanned, key)
+	}
+	m.removeEntry(key)
+	m.markDirty()
+	return nil
+}
+
+// Clear removes all bans.  The removal is persisted shortly after by Run.
+func (m *Manager) Clear() {
+	m.mtx.Lock()
+	m.bans = make(map[string]*Entry)
+	m.subnets = make(map[string]*Entry)
+	m.markDirty()
+	m.mtx.Unlock()
+}
+
+// IsBanned returns whether or not the provided address is included in any
+// unexpired ban.  The ban with the latest expiration time that includes the
+// address is also returned when it is.
+func (m *Manager) IsBanned(ip net.IP) (Entry, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := m.cfg.TimeNow()
+	var match *Entry
+	if entry, ok := m.bans[SubnetForIP(ip).String()]; ok &&
+		now.Before(entry.Expires) {
+
+		match = entry
+	}
+	for _, entry := range m.subnets {
+		if !now.Before(entry.Expires) || !entry.Subnet.Contains(ip) {
+			continue
+		}
+		if match == nil || entry.Expires.After(match.Expires) {
+			match = entry
+		}
+	}
+	if match == nil {
+		return Entry{}, false
+	}
+	return *match, true
+}
+
+// Entries returns all unexpired bans ordered by their creation time.
+func (m *Manager) Entries() []Entry {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.pruneExpired(m.cfg.TimeNow())
+	entries := make([]Entry, 0, len(m.bans))
+	for _, entry := range m.bans {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Created.Equal(entries[j].Created) {
+			return entries[i].Subnet.String() < entries[j].Subnet.String()
+		}
+		return entries[i].Created.Before(entries[j].Created)
+	})
+	return entries
+}
+
+// serializedEntry is the serialized form of a ban entry.
+type serializedEntry struct {
+	Subnet  string `json:"subnet"`
+	Created int64  `json:"created"`
+	Expires int64  `json:"expires"`
+	Reason  string `json:"reason"`
+}
+
+// serializedBans is the serialized form of all bans.
+type serializedBans struct {
+	Version int               `json:"version"`
+	Bans    []serializedEntry `json:"bans"`
+}
+
+// Save writes all unexpired bans to the ban file when one is configured and
+// there are changes that have not yet been persisted.  The file is first
+// written to a temporary file which then replaces the existing one so that a
+// failure does not corrupt it.  The bans remain in effect while they are being
+// written.
+//
+// It is typically not necessary to call this function since Run persists the
+// bans after they change.
+func (m *Manager) Save() error {
+	m.saveMtx.Lock()
+	defer m.saveMtx.Unlock()
+
+	m.mtx.Lock()
+	if !m.dirty {
+		m.mtx.Unlock()
+		return nil
+	}
+	m.pruneExpired(m.cfg.TimeNow())
+	sbans := serializedBans{
+		Version: banFileVersion,
+		Bans:    make([]serializedEntry, 0, len(m.bans)),
+	}
+	for key, entry := range m.bans {
+		sbans.Bans = append(sbans.Bans, serializedEntry{
+			Subnet:  key,
+			Created: entry.Created.Unix(),
+			Expires: entry.Expires.Unix(),
+			Reason:  entry.Reason.String(),
+		})
+	}
+	m.dirty = false
+	m.mtx.Unlock()
+
+	if err := m.writeBanFile(&sbans); err != nil {
+		// Ensure the bans are written again on the next attempt.
+		m.mtx.Lock()
+		m.dirty = true
+		m.mtx.Unlock()
+		return err
+	}
+	return nil
+}
+
+// writeBanFile writes the provided serialized bans to the ban file.
+func (m *Manager) writeBanFile(sbans *serializedBans) error {
+	sort.Slice(sbans.Bans, func(i, j int) bool {
+		return sbans.Bans[i].Subnet < sbans.Bans[j].Subnet
+	})
+	serialized, err := json.Marshal(sbans)
+	if err != nil {
+		return err
+	}
+
+	tmpFile := m.cfg.BanFile + ".new"
+	if err := os.WriteFile(tmpFile, serialized, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, m.cfg.BanFile)
+}
+
+// Load replaces the current bans with the unexpired bans persisted to the ban
+// file.  It is not an error for the file to not exist.
+//
+// ErrInvalidBanFile is returned when the file is malformed.
+func (m *Manager) Load() error {
+	if m.cfg.BanFile == "" {
+		return nil
+	}
+
+	serialized, err := os.ReadFile(m.cfg.BanFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var sbans serializedBans
+	if err := json.Unmarshal(serialized, &sbans); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBanFile, err)
+	}
+	if sbans.Version != banFileVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBanFile,
+			sbans.Version)
+	}
+
+	entries := make([]*Entry, 0, len(sbans.Bans))
+	for _, sentry := range sbans.Bans {
+		subnet, err := ParseSubnet(sentry.Subnet)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBanFile, err)
+		}
+		reason, ok := parseReason(sentry.Reason)
+		if !ok {
+			return fmt.Errorf("%w: unknown ban reason %q", ErrInvalidBanFile,
+				sentry.Reason)
+		}
+		entries = append(entries, &Entry{
+			Subnet:  subnet,
+			Created: time.Unix(sentry.Created, 0),
+			Expires: time.Unix(sentry.Expires, 0),
+			Reason:  reason,
+		})
+	}
+
+	m.mtx.Lock()
+	m.bans = make(map[string]*Entry, len(entries))
+	m.subnets = make(map[string]*Entry)
+	for _, entry := range entries {
+		m.addEntry(entry)
+	}
+	m.pruneExpired(m.cfg.TimeNow())
+	m.mtx.Unlock()
+	return nil
+}
+
+// Run persists the bans shortly after they change until the provided context
+// is cancelled, at which point any remaining changes are persisted.  Changes
+// are only persisted while it is running.
+//
+// This function MUST be run as a goroutine.
+func (m *Manager) Run(ctx context.Context) {
+	save := func() {
+		if err := m.Save(); err != nil {
+			log.Errorf("Unable to persist bans: %v", err)
+		}
+	}
+	for {
+		select {
+		case <-m.saveNeeded:
+			// Wait for a short time before persisting the bans so that any
+			// further changes are written along with them.
+			select {
+			case <-time.After(saveDelay):
+			case <-ctx.Done():
+			}
+			save()
+
+		case <-ctx.Done():
+			save()
+			return
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package banmgr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestParseSubnet ensures individual addresses and subnets are parsed and
+// normalized as expected.
+func TestParseSubnet(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{{
+		name: "ipv4 address",
+		in:   "192.168.0.1",
+		want: "192.168.0.1/32",
+	}, {
+		name: "ipv4 subnet",
+		in:   "192.168.0.1/24",
+		want: "192.168.0.0/24",
+	}, {
+		name: "ipv4-mapped ipv6 subnet",
+		in:   "::ffff:192.168.0.1/112",
+		want: "192.168.0.0/16",
+	}, {
+		name: "ipv6 address",
+		in:   "2001:db8::1",
+		want: "2001:db8::1/128",
+	}, {
+		name: "ipv6 subnet",
+		in:   "2001:db8::1/32",
+		want: "2001:db8::/32",
+	}, {
+		name:    "invalid address",
+		in:      "192.168.0",
+		wantErr: ErrInvalidSubnet,
+	}, {
+		name:    "invalid subnet",
+		in:      "192.168.0.1/33",
+		wantErr: ErrInvalidSubnet,
+	}, {
+		name:    "hostname",
+		in:      "example.com",
+		wantErr: ErrInvalidSubnet,
+	}}
+
+	for _, test := range tests {
+		subnet, err := ParseSubnet(test.in)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name, err,
+				test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := subnet.String(); got != test.want {
+			t.Errorf("%q: unexpected subnet - got %s, want %s", test.name, got,
+				test.want)
+		}
+	}
+}
+
+// TestManager ensures banning, unbanning, and expiration work as expected.
+func TestManager(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	mgr := New(&Config{TimeNow: func() time.Time { return now }})
+
+	mustParseSubnet := func(s string) *net.IPNet {
+		subnet, err := ParseSubnet(s)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %v", s, err)
+		}
+		return subnet
+	}
+
+	// Ban an individual address and a subnet with different expirations.
+	mgr.Ban(mustParseSubnet("10.0.0.1"), now.Add(time.Hour),
+		ReasonMisbehaving)
+	mgr.Ban(mustParseSubnet("10.0.0.0/8"), now.Add(time.Minute),
+		ReasonManual)
+	mgr.Ban(mustParseSubnet("10.0.0.2"), now.Add(time.Second),
+		ReasonMisbehaving)
+
+	tests := []struct {
+		name   string
+		ip     string
+		banned bool
+		reason Reason
+	}{
+		{"address ban", "10.0.0.1", true, ReasonMisbehaving},
+		{"ipv4-mapped address ban", "::ffff:10.0.0.1", true, ReasonMisbehaving},
+		{"subnet ban", "10.1.2.3", true, ReasonManual},
+		{"subnet ban outlasts address ban", "10.0.0.2", true, ReasonManual},
+		{"not banned", "11.0.0.1", false, 0},
+	}
+	for _, test := range tests {
+		entry, banned := mgr.IsBanned(net.ParseIP(test.ip))
+		if banned != test.banned {
+			t.Errorf("%q: unexpected banned state - got %v, want %v",
+				test.name, banned, test.banned)
+			continue
+		}
+		if banned && entry.Reason != test.reason {
+			t.Errorf("%q: unexpected reason - got %v, want %v", test.name,
+				entry.Reason, test.reason)
+		}
+	}
+	if entries := mgr.Entries(); len(entries) != 3 {
+		t.Fatalf("unexpected number of entries - got %d, want 3",
+			len(entries))
+	}
+
+	// Ensure the subnet ban expires while the address ban remains.
+	now = now.Add(2 * time.Minute)
+	if _, banned := mgr.IsBanned(net.ParseIP("10.1.2.3")); banned {
+		t.Fatal("expired subnet ban is still in effect")
+	}
+	if _, banned := mgr.IsBanned(net.ParseIP("10.0.0.1")); !banned {
+		t.Fatal("address ban is no longer in effect")
+	}
+	if entries := mgr.Entries(); len(entries) != 1 {
+		t.Fatalf("unexpected number of entries - got %d, want 1",
+			len(entries))
+	}
+
+	// Ensure unbanning removes the ban and that unbanning an address that is
+	// not banned is an error.
+	if err := mgr.Unban(mustParseSubnet("10.0.0.1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, banned := mgr.IsBanned(net.ParseIP("10.0.0.1")); banned {
+		t.Fatal("address is still banned after unban")
+	}
+	err := mgr.Unban(mustParseSubnet("10.0.0.1"))
+	if !errors.Is(err, ErrNotBanned) {
+		t.Fatalf("unexpected error - got %v, want %v", err, ErrNotBanned)
+	}
+
+	// Ensure clearing removes all bans.
+	mgr.Ban(mustParseSubnet("2001:db8::/32"), now.Add(time.Hour),
+		ReasonManual)
+	mgr.Clear()
+	if _, banned := mgr.IsBanned(net.ParseIP("2001:db8::1")); banned {
+		t.Fatal("address is still banned after clear")
+	}
+	if entries := mgr.Entries(); len(entries) != 0 {
+		t.Fatalf("unexpected number of entries - got %d, want 0",
+			len(entries))
+	}
+}
+
+// TestPersistence ensures bans are persisted to and loaded from the ban file
+// as expected.
+func TestPersistence(t *testing.T) {
+	banFile := filepath.Join(t.TempDir(), "banlist.json")
+	now := time.Unix(1700000000, 0)
+	timeNow := func() time.Time { return now }
+
+	// Loading a ban file that does not exist is not an error.
+	mgr := New(&Config{BanFile: banFile, TimeNow: timeNow})
+	if err := mgr.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	subnet, _ := ParseSubnet("2001:db8::/32")
+	mgr.Ban(subnet, now.Add(time.Hour), ReasonManual)
+	subnet, _ = ParseSubnet("192.168.1.1")
+	mgr.Ban(subnet, now.Add(time.Minute), ReasonMisbehaving)
+	if err := mgr.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure both bans are loaded by a new manager.
+	mgr = New(&Config{BanFile: banFile, TimeNow: timeNow})
+	if err := mgr.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := mgr.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("unexpected number of entries - got %d, want 2",
+			len(entries))
+	}
+	entry, banned := mgr.IsBanned(net.ParseIP("192.168.1.1"))
+	if !banned {
+		t.Fatal("persisted address ban is not in effect")
+	}
+	if entry.Reason != ReasonMisbehaving || !entry.Created.Equal(now) ||
+		!entry.Expires.Equal(now.Add(time.Minute)) {
+
+		t.Fatalf("unexpected persisted entry %+v", entry)
+	}
+
+	// Ensure expired bans are not loaded.
+	now = now.Add(2 * time.Minute)
+	mgr = New(&Config{BanFile: banFile, TimeNow: timeNow})
+	if err := mgr.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries := mgr.Entries(); len(entries) != 1 {
+		t.Fatalf("unexpected number of entries - got %d, want 1",
+			len(entries))
+	}
+
+	// Ensure malformed ban files are rejected.
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed json", `{"version":1,"bans":[`},
+		{"unsupported version", `{"version":2,"bans":[]}`},
+		{"invalid subnet", `{"version":1,"bans":[{"subnet":"bogus"}]}`},
+		{"unknown reason", `{"version":1,"bans":[{"subnet":"10.0.0.1/32",` +
+			`"reason":"bogus"}]}`},
+	}
+	for _, test := range tests {
+		if err := os.WriteFile(banFile, []byte(test.data), 0600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := mgr.Load()
+		if !errors.Is(err, ErrInvalidBanFile) {
+			t.Errorf("%q: unexpected error - got %v, want %v", test.name,
+				err, ErrInvalidBanFile)
+		}
+	}
+}
+
+// TestRunPersists ensures changes to the bans are persisted by Run, including
+// those that have not yet been written when it is shutdown.
+func TestRunPersists(t *testing.T) {
+	banFile := filepath.Join(t.TempDir(), "banlist.json")
+	now := time.Unix(1700000000, 0)
+	timeNow := func() time.Time { return now }
+
+	// Changes are not persisted without Run.
+	mgr := New(&Config{BanFile: banFile, TimeNow: timeNow})
+	subnet, _ := ParseSubnet("10.0.0.1")
+	mgr.Ban(subnet, now.Add(time.Hour), ReasonManual)
+	if _, err := os.Stat(banFile); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ban file written without Run: %v", err)
+	}
+
+	// Ensure the pending change is persisted once Run shuts down.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for Run to shutdown")
+	}
+
+	loaded := New(&Config{BanFile: banFile, TimeNow: timeNow})
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, banned := loaded.IsBanned(net.ParseIP("10.0.0.1")); !banned {
+		t.Fatal("ban was not persisted on shutdown")
+	}
+
+	// Saving without any changes does not write the file.
+	if err := os.Remove(banFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mgr.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(banFile); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ban file written without changes: %v", err)
+	}
+}
+
+// TestReasonStringer tests the stringized output for the Reason type.
+func TestReasonStringer(t *testing.T) {
+	tests := []struct {
+		in   Reason
+		want string
+	}{
+		{ReasonManual, "manual"},
+		{ReasonMisbehaving, "misbehaving"},
+		{0xff, "Unknown Reason (255)"},
+	}
+	for i, test := range tests {
+		if result := test.in.String(); result != test.want {
+			t.Errorf("String #%d\n got: %s want: %s", i, result, test.want)
+		}
+	}
+}
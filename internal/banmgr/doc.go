@@ -0,0 +1,20 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package banmgr provides tracking of peer misbehavior along with a persistent
+ban list of individual addresses and subnets.
+
+Misbehavior is tracked per peer via the Scorer interface which allows the
+policy used to calculate scores to be swapped out.  The default scorer is the
+dynamic ban score provided by the connmgr package which consists of a
+persistent component and a transient component that decays over time.
+
+Peers whose score exceeds the ban threshold are discouraged by banning their
+address for a configured duration.  Bans may also be requested explicitly, in
+which case they may cover entire subnets specified in CIDR notation.  Both
+kinds of bans are persisted to a file so they survive restarts, and expired
+bans are dropped automatically.
+*/
+package banmgr
@@ -0,0 +1,241 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// blockSigScriptFlags are the script flags used when verifying that the
+	// signature of a block satisfies the block signing challenge of the
+	// network.  They are fixed so that the validity of a block signature
+	// does not depend on the state of any agendas.
+	blockSigScriptFlags = txscript.ScriptVerifyCleanStack |
+		txscript.ScriptVerifySigPushOnly |
+		txscript.ScriptVerifyCheckLockTimeVerify |
+		txscript.ScriptVerifyCheckSequenceVerify |
+		txscript.ScriptVerifySHA256
+)
+
+// blockSigMagic is the prefix of the data pushed by the coinbase output that
+// houses the signature of a block.  It distinguishes the output from any other
+// provably pruneable outputs of the coinbase.
+var blockSigMagic = []byte{0xec, 0xc7, 0xda, 0xa2}
+
+// extractBlockSig returns the signature script of a block housed by the
+// provided coinbase output script along with whether or not the script is of
+// the form used to house it, which is OP_RETURN followed by a single data push
+// of the magic prefix and the signature script.
+func extractBlockSig(scriptVersion uint16, pkScript []byte) ([]byte, bool) {
+	if scriptVersion != 0 || len(pkScript) == 0 ||
+		pkScript[0] != txscript.OP_RETURN {
+
+		return nil, false
+	}
+
+	// NOTE: This is intentionally not using script type determination
+	// functions from stdscript because those are specifically for standardness
+	// checks which can change over time and this function is used to enforce
+	// consensus rules.
+	tokenizer := txscript.MakeScriptTokenizer(scriptVersion, pkScript[1:])
+	if !tokenizer.Next() || !tokenizer.Done() ||
+		tokenizer.Opcode() > txscript.OP_PUSHDATA4 {
+
+		return nil, false
+	}
+	data := tokenizer.Data()
+	if !bytes.HasPrefix(data, blockSigMagic) {
+		return nil, false
+	}
+	return data[len(blockSigMagic):], true
+}
+
+// blockSigOutputIndex returns the index of the output of the provided coinbase
+// that houses the signature of the block.  The last such output is used when
+// there are several of them.  It returns -1 when there is no such output.
+func blockSigOutputIndex(coinbase *wire.MsgTx) int {
+	for i := len(coinbase.TxOut) - 1; i >= 0; i-- {
+		txOut := coinbase.TxOut[i]
+		if _, ok := extractBlockSig(txOut.Version, txOut.PkScript); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// blockSigPkScript returns the coinbase output script that houses the provided
+// signature script of a block.
+func blockSigPkScript(sigScript []byte) ([]byte, error) {
+	data := make([]byte, 0, len(blockSigMagic)+len(sigScript))
+	data = append(data, blockSigMagic...)
+	data = append(data, sigScript...)
+	return txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).
+		AddData(data).Script()
+}
+
+// BlockSigHash returns the hash that the signature of the provided block
+// commits to when the network requires blocks to be signed.
+//
+// The hash commits to the entire block with the exception of the signature
+// itself and the fields of the header that are modified while solving the
+// block, namely the timestamp, nonce, and extra data, along with the fields
+// that depend on the signature, namely the merkle and stake roots and the size.
+// In their place, it commits to the merkle roots of the regular and stake
+// transaction trees with the signature removed from the coinbase.  This allows
+// a block to be signed before it is solved.
+//
+// The coinbase output that houses the signature is added with an empty
+// signature for the purposes of calculating the hash when the block does not
+// already contain one.
+func BlockSigHash(block *wire.MsgBlock) (chainhash.Hash, error) {
+	if len(block.Transactions) == 0 {
+		return chainhash.Hash{}, fmt.Errorf("block does not contain a coinbase")
+	}
+
+	// Remove the signature from a copy of the coinbase.
+	emptySigPkScript, err := blockSigPkScript(nil)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	coinbase := block.Transactions[0].Copy()
+	if idx := blockSigOutputIndex(coinbase); idx != -1 {
+		coinbase.TxOut[idx].PkScript = emptySigPkScript
+	} else {
+		coinbase.AddTxOut(wire.NewTxOut(0, emptySigPkScript))
+	}
+	regularTxns := make([]*wire.MsgTx, 0, len(block.Transactions))
+	regularTxns = append(regularTxns, coinbase)
+	regularTxns = append(regularTxns, block.Transactions[1:]...)
+
+	header := block.Header
+	header.MerkleRoot = standalone.CalcTxTreeMerkleRoot(regularTxns)
+	header.StakeRoot = standalone.CalcTxTreeMerkleRoot(block.STransactions)
+	header.Timestamp = time.Unix(0, 0)
+	header.Nonce = 0
+	header.ExtraData = [32]byte{}
+	header.Size = 0
+	return header.BlockHash(), nil
+}
+
+// blockSigTx returns the virtual transaction used to verify the provided
+// signature script of a block with the provided signature hash against the
+// provided block signing challenge.  The transaction spends an output that pays
+// to the challenge script with the signature script and commits to the
+// signature hash via its only output, so a signature over it with a signature
+// hash type of SigHashAll commits to the block.
+//
+// NOTE: The signature hash is committed to via an output as opposed to the
+// signature script of the transaction that creates the spent output, as is
+// done by some other projects, since signature scripts are not part of the
+// transaction prefix and thus do not affect transaction hashes.
+func blockSigTx(sigHash *chainhash.Hash, challenge, sigScript []byte) (*wire.MsgTx, error) {
+	commitScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_RETURN).AddData(sigHash[:]).Script()
+	if err != nil {
+		return nil, err
+	}
+
+	toSpend := wire.NewMsgTx()
+	toSpend.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex, wire.TxTreeRegular),
+		BlockHeight: wire.NullBlockHeight,
+		BlockIndex:  wire.NullBlockIndex,
+	})
+	toSpend.AddTxOut(wire.NewTxOut(0, challenge))
+
+	toSign := wire.NewMsgTx()
+	toSpendHash := toSpend.TxHash()
+	toSign.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&toSpendHash, 0,
+			wire.TxTreeRegular),
+		BlockHeight:     wire.NullBlockHeight,
+		BlockIndex:      wire.NullBlockIndex,
+		SignatureScript: sigScript,
+	})
+	toSign.AddTxOut(wire.NewTxOut(0, commitScript))
+	return toSign, nil
+}
+
+// BlockSigningTx returns the virtual transaction that must be signed in order
+// to create the signature script of the provided block for the provided block
+// signing challenge.  The signature script is the one that spends the first
+// input of the transaction, which pays to the challenge script, with a
+// signature hash type of SigHashAll.
+//
+// See BlockSigHash for details regarding which parts of the block are committed
+// to by the signature.
+func BlockSigningTx(block *wire.MsgBlock, challenge []byte) (*wire.MsgTx, error) {
+	sigHash, err := BlockSigHash(block)
+	if err != nil {
+		return nil, err
+	}
+	return blockSigTx(&sigHash, challenge, nil)
+}
+
+// SetBlockSig sets the signature script of the provided block, which is housed
+// by a provably pruneable output of its coinbase, to the provided signature
+// script.  The output is added to the coinbase when it does not already contain
+// one.
+//
+// NOTE: The merkle and stake roots as well as the size in the header of the
+// block must be updated after the signature is set.
+func SetBlockSig(block *wire.MsgBlock, sigScript []byte) error {
+	if len(block.Transactions) == 0 {
+		return fmt.Errorf("block does not contain a coinbase")
+	}
+
+	pkScript, err := blockSigPkScript(sigScript)
+	if err != nil {
+		return err
+	}
+	coinbase := block.Transactions[0]
+	if idx := blockSigOutputIndex(coinbase); idx != -1 {
+		coinbase.TxOut[idx].PkScript = pkScript
+		return nil
+	}
+	coinbase.AddTxOut(wire.NewTxOut(0, pkScript))
+	return nil
+}
+
+// checkBlockSig ensures the provided block contains a signature that satisfies
+// the provided block signing challenge.
+func checkBlockSig(block *wire.MsgBlock, challenge []byte) error {
+	coinbase := block.Transactions[0]
+	idx := blockSigOutputIndex(coinbase)
+	if idx == -1 {
+		str := "block does not contain a block signature"
+		return ruleError(ErrMissingBlockSig, str)
+	}
+	sigScript, _ := extractBlockSig(0, coinbase.TxOut[idx].PkScript)
+
+	sigHash, err := BlockSigHash(block)
+	if err != nil {
+		return ruleError(ErrBadBlockSig, err.Error())
+	}
+	toSign, err := blockSigTx(&sigHash, challenge, sigScript)
+	if err != nil {
+		return ruleError(ErrBadBlockSig, err.Error())
+	}
+	vm, err := txscript.NewEngine(challenge, toSign, 0, blockSigScriptFlags,
+		0, nil)
+	if err == nil {
+		err = vm.Execute()
+	}
+	if err != nil {
+		str := fmt.Sprintf("block signature does not satisfy the block "+
+			"signing challenge: %v", err)
+		return ruleError(ErrBadBlockSig, str)
+	}
+	return nil
+}
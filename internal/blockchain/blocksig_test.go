@@ -0,0 +1,125 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/sign"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestBlockSig ensures blocks signed with the key that satisfies a block
+// signing challenge are accepted while blocks that are not signed, signed with
+// the wrong key, or modified after being signed are rejected.
+func TestBlockSig(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	privKey := secp256k1.NewPrivateKey(new(secp256k1.ModNScalar).SetInt(1))
+	wrongKey := secp256k1.NewPrivateKey(new(secp256k1.ModNScalar).SetInt(2))
+	pubKeyHash := stdaddr.Hash160(privKey.PubKey().SerializeCompressed())
+	addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pubKeyHash,
+		params)
+	if err != nil {
+		t.Fatalf("unexpected error creating address: %v", err)
+	}
+	_, challenge := addr.PaymentScript()
+
+	// signBlock signs the provided block with the provided key.
+	signBlock := func(block *wire.MsgBlock, key *secp256k1.PrivateKey) {
+		t.Helper()
+		tx, err := BlockSigningTx(block, challenge)
+		if err != nil {
+			t.Fatalf("unexpected error creating signing tx: %v", err)
+		}
+		keyBytes := key.Serialize()
+		sigScript, err := sign.SignTxOutput(params, tx, 0, challenge,
+			txscript.SigHashAll, sign.KeyClosure(func(stdaddr.Address) ([]byte,
+				dcrec.SignatureType, bool, error) {
+
+				return keyBytes, dcrec.STEcdsaSecp256k1, true, nil
+			}), nil, nil, false)
+		if err != nil {
+			t.Fatalf("unexpected error signing block: %v", err)
+		}
+		if err := SetBlockSig(block, sigScript); err != nil {
+			t.Fatalf("unexpected error setting block signature: %v", err)
+		}
+	}
+
+	// newBlock returns a block with a coinbase that pays to the challenge.
+	newBlock := func() *wire.MsgBlock {
+		coinbase := wire.NewMsgTx()
+		coinbase.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: *wire.NewOutPoint(zeroHash,
+				wire.MaxPrevOutIndex, wire.TxTreeRegular),
+			SignatureScript: []byte{txscript.OP_0, txscript.OP_0},
+		})
+		coinbase.AddTxOut(wire.NewTxOut(1000, challenge))
+		block := &wire.MsgBlock{Header: params.GenesisBlock.Header}
+		block.Header.Height = 1
+		block.AddTransaction(coinbase)
+		return block
+	}
+
+	// Ensure a block without a signature is rejected.
+	block := newBlock()
+	err = checkBlockSig(block, challenge)
+	if !errors.Is(err, ErrMissingBlockSig) {
+		t.Fatalf("unexpected error: got %v, want %v", err, ErrMissingBlockSig)
+	}
+
+	// Ensure a properly signed block is accepted and remains valid when the
+	// fields modified while solving it change.
+	signBlock(block, privKey)
+	if err := checkBlockSig(block, challenge); err != nil {
+		t.Fatalf("unexpected error checking signed block: %v", err)
+	}
+	block.Header.Nonce++
+	block.Header.ExtraData[0]++
+	block.Header.Timestamp = block.Header.Timestamp.Add(1)
+	if err := checkBlockSig(block, challenge); err != nil {
+		t.Fatalf("unexpected error checking solved block: %v", err)
+	}
+
+	// Ensure signing an already signed block replaces the signature instead
+	// of adding another one.
+	numOuts := len(block.Transactions[0].TxOut)
+	signBlock(block, privKey)
+	if got := len(block.Transactions[0].TxOut); got != numOuts {
+		t.Fatalf("mismatched number of coinbase outputs: got %d, want %d",
+			got, numOuts)
+	}
+	if err := checkBlockSig(block, challenge); err != nil {
+		t.Fatalf("unexpected error checking re-signed block: %v", err)
+	}
+
+	// Ensure a block that is modified after it is signed is rejected.
+	block.Header.VoteBits ^= 1
+	err = checkBlockSig(block, challenge)
+	if !errors.Is(err, ErrBadBlockSig) {
+		t.Fatalf("unexpected error: got %v, want %v", err, ErrBadBlockSig)
+	}
+	block.Header.VoteBits ^= 1
+	block.Transactions[0].TxOut[0].Value++
+	err = checkBlockSig(block, challenge)
+	if !errors.Is(err, ErrBadBlockSig) {
+		t.Fatalf("unexpected error: got %v, want %v", err, ErrBadBlockSig)
+	}
+
+	// Ensure a block signed with a key that does not satisfy the challenge is
+	// rejected.
+	block = newBlock()
+	signBlock(block, wrongKey)
+	err = checkBlockSig(block, challenge)
+	if !errors.Is(err, ErrBadBlockSig) {
+		t.Fatalf("unexpected error: got %v, want %v", err, ErrBadBlockSig)
+	}
+}
@@ -19,12 +19,14 @@ import (
 	"github.com/decred/dcrd/blockchain/standalone/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/container/lru"
 	"github.com/decred/dcrd/database/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/gcs/v4"
 	"github.com/decred/dcrd/gcs/v4/blockcf2"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
-	"github.com/decred/dcrd/lru"
+	"github.com/decred/dcrd/internal/metrics"
+	"github.com/decred/dcrd/internal/tracing"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/wire"
@@ -153,6 +155,8 @@ type BlockChain struct {
 	indexSubscriber          *indexers.IndexSubscriber
 	interrupt                <-chan struct{}
 	utxoCache                UtxoCacher
+	processBlockTime         *metrics.Histogram
+	tracer                   *tracing.Tracer
 
 	// subsidyCache is the cache that provides quick lookup of subsidy
 	// values.
@@ -176,8 +180,13 @@ type BlockChain struct {
 	//
 	// bestChain tracks the current active chain by making use of an
 	// efficient chain view into the block index.
-	index     *blockIndex
-	bestChain *chainView
+	//
+	// medianTimes houses the past median times of the blocks in the current
+	// active chain to efficiently find blocks by time.  It is updated
+	// whenever the tip of the best chain changes.
+	index       *blockIndex
+	bestChain   *chainView
+	medianTimes medianTimeIndex
 
 	// isCurrentLatch tracks whether or not the chain believes it is current in
 	// such a way that once it becomes current it latches to that state unless
@@ -194,8 +203,8 @@ type BlockChain struct {
 	// recentContextChecks tracks recent blocks that have successfully passed
 	// all contextual checks and is primarily used as an optimization to avoid
 	// running the checks again when possible.
-	recentBlocks        lru.KVCache
-	recentContextChecks lru.Cache
+	recentBlocks        *lru.Map[chainhash.Hash, *dcrutil.Block]
+	recentContextChecks *lru.Set[chainhash.Hash]
 
 	// These fields house a cached view that represents a block that votes
 	// against its parent and therefore contains all changes as a result
@@ -420,7 +429,7 @@ func (b *BlockChain) TipGeneration() ([]chainhash.Hash, error) {
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) addRecentBlock(block *dcrutil.Block) {
-	b.recentBlocks.Add(*block.Hash(), block)
+	b.recentBlocks.Put(*block.Hash(), block)
 }
 
 // lookupRecentBlock attempts to return the requested block from the recent
@@ -429,11 +438,7 @@ func (b *BlockChain) addRecentBlock(block *dcrutil.Block) {
 //
 // This function is safe for concurrent access.
 func (b *BlockChain) lookupRecentBlock(hash *chainhash.Hash) (*dcrutil.Block, bool) {
-	block, ok := b.recentBlocks.Lookup(*hash)
-	if ok {
-		return block.(*dcrutil.Block), true
-	}
-	return nil, false
+	return b.recentBlocks.Get(*hash)
 }
 
 // fetchMainChainBlockByNode returns the block from the main chain associated
@@ -707,6 +712,7 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *dcrutil.Block,
 
 	// This node is now the end of the best chain.
 	b.bestChain.SetTip(node)
+	b.medianTimes.setTip(node)
 	b.index.MaybePruneCachedTips(node)
 
 	// Update the state for the best block.  Notice how this replaces the
@@ -749,13 +755,14 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *dcrutil.Block,
 			return err
 		}
 
-		// Notify of new tickets.
+		// Notify of new and missed tickets.
 		b.sendNotification(NTNewTickets,
 			&TicketNotificationsData{
 				Hash:            node.hash,
 				Height:          node.height,
 				StakeDifficulty: nextStakeDiff,
 				TicketsNew:      node.stakeNode.NewTickets(),
+				TicketsMissed:   node.stakeNode.NewlyMissedByBlock(),
 			})
 	}
 
@@ -894,6 +901,7 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block, parent *dcrutil.Blo
 
 	// This node's parent is now the end of the best chain.
 	b.bestChain.SetTip(node.parent)
+	b.medianTimes.setTip(node.parent)
 
 	// Update the state for the best block.  Notice how this replaces the
 	// entire struct instead of updating the existing one.  This effectively
@@ -1160,6 +1168,7 @@ func (b *BlockChain) reorganizeChainInternal(target *blockNode) error {
 		// Skip validation if the block has already been validated.  However,
 		// the utxo view still needs to be updated and the stxos and header
 		// commitment data are still needed.
+		validateSpan := b.tracer.Start(&n.hash, "chain.validate_block")
 		numSpentOutputs := countSpentOutputs(block)
 		stxos := make([]spentTxOut, 0, numSpentOutputs)
 		var hdrCommitments headerCommitmentData
@@ -1169,14 +1178,19 @@ func (b *BlockChain) reorganizeChainInternal(target *blockNode) error {
 			// In the case the block votes against the parent, also disconnect
 			// all of the regular transactions in the parent block.  Finally,
 			// provide an stxo slice so the spent txout details are generated.
+			validateSpan.SetAttribute("block.prevalidated", true)
 			err := view.connectBlock(b.db, block, parent, &stxos,
 				isTreasuryEnabled)
 			if err != nil {
+				validateSpan.SetError(err)
+				validateSpan.End()
 				return err
 			}
 
 			filter, err := b.loadOrCreateFilter(block, view)
 			if err != nil {
+				validateSpan.SetError(err)
+				validateSpan.End()
 				return err
 			}
 			hdrCommitments.filter = filter
@@ -1189,12 +1203,14 @@ func (b *BlockChain) reorganizeChainInternal(target *blockNode) error {
 				if errors.As(err, &rerr) {
 					b.index.MarkBlockFailedValidation(n)
 				}
+				validateSpan.SetError(err)
+				validateSpan.End()
 				return err
 			}
 
 			// Mark the block as recently checked to avoid checking it again
 			// when processing.
-			b.recentContextChecks.Add(n.hash)
+			b.recentContextChecks.Put(n.hash)
 
 			// In the case the block is determined to be invalid due to a rule
 			// violation, mark it as invalid and mark all of its descendants as
@@ -1206,13 +1222,20 @@ func (b *BlockChain) reorganizeChainInternal(target *blockNode) error {
 				if errors.As(err, &rerr) {
 					b.index.MarkBlockFailedValidation(n)
 				}
+				validateSpan.SetError(err)
+				validateSpan.End()
 				return err
 			}
 			b.index.SetStatusFlags(n, statusValidated)
 		}
+		validateSpan.End()
 
-		// Update the database and chain state.
+		// Update the database and chain state.  This includes notifying the
+		// caller that the block was connected.
+		connectSpan := b.tracer.Start(&n.hash, "chain.connect_block")
 		err = b.connectBlock(n, block, parent, view, stxos, &hdrCommitments)
+		connectSpan.SetError(err)
+		connectSpan.End()
 		if err != nil {
 			return err
 		}
@@ -1705,6 +1728,117 @@ func (b *BlockChain) MainChainHasBlock(hash *chainhash.Hash) bool {
 	return node != nil && b.bestChain.Contains(node)
 }
 
+// SpentOutput houses details about a transaction output that was spent by a
+// transaction in a block in the main chain.
+type SpentOutput struct {
+	Amount          int64
+	ScriptVersion   uint16
+	PkScript        []byte
+	BlockHeight     uint32
+	BlockIndex      uint32
+	IsCoinBase      bool
+	TransactionType stake.TxType
+}
+
+// FetchSpentOutputs returns the details of all transaction outputs spent by
+// the transactions in the passed block keyed by the outpoint of the spent
+// output.  The details are loaded from the spend journal which is only
+// available for blocks in the main chain, so an error is returned for blocks
+// that are not part of it.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) FetchSpentOutputs(block *dcrutil.Block) (map[wire.OutPoint]SpentOutput, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	node := b.index.LookupNode(block.Hash())
+	if node == nil || !b.bestChain.Contains(node) {
+		str := fmt.Sprintf("block %s is not in the main chain", block.Hash())
+		return nil, errNotInMainChain(str)
+	}
+
+	// Determine if treasury agenda is active as of the block.
+	isTreasuryEnabled, err := b.isTreasuryAgendaActive(node.parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var spent map[wire.OutPoint]SpentOutput
+	err = b.db.View(func(dbTx database.Tx) error {
+		spent, err = dbFetchSpentOutputs(dbTx, block, isTreasuryEnabled)
+		return err
+	})
+	return spent, err
+}
+
+// dbFetchSpentOutputs uses an existing database transaction to load the
+// details of all transaction outputs spent by the transactions in the passed
+// block from the spend journal keyed by the outpoint of the spent output.  An
+// error is returned when the block does not have a spend journal entry, which
+// is the case for blocks that are not in the main chain.
+func dbFetchSpentOutputs(dbTx database.Tx, block *dcrutil.Block, isTreasuryEnabled bool) (map[wire.OutPoint]SpentOutput, error) {
+	// Determine the transactions that spend outputs in the order they appear
+	// in the spend journal.  The spend journal contains an entry for every
+	// input of the transactions in the stake tree followed by the regular tree
+	// with the exception of the coinbase, treasurybase, treasury spends, and
+	// vote stakebases, since they do not spend any outputs.
+	msgBlock := block.MsgBlock()
+	blockTxns := make([]*wire.MsgTx, 0, len(msgBlock.STransactions)+
+		len(msgBlock.Transactions))
+	for i, tx := range msgBlock.STransactions {
+		if isTreasuryEnabled && (i == 0 || stake.IsTSpend(tx)) {
+			continue
+		}
+		blockTxns = append(blockTxns, tx)
+	}
+	if len(msgBlock.Transactions) > 1 {
+		blockTxns = append(blockTxns, msgBlock.Transactions[1:]...)
+	}
+
+	// Load all of the spent txos for the block from the spend journal while
+	// ensuring the entry exists since it is removed when the block is
+	// disconnected from the main chain.
+	spendBucket := dbTx.Metadata().Bucket(spendJournalBucketName)
+	if len(blockTxns) > 0 && spendBucket.Get(block.Hash()[:]) == nil {
+		str := fmt.Sprintf("block %s is not in the main chain", block.Hash())
+		return nil, errNotInMainChain(str)
+	}
+	stxos, err := dbFetchSpendJournalEntry(dbTx, block, isTreasuryEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	// Associate the spent txos with the outpoints they are spent by.
+	spent := make(map[wire.OutPoint]SpentOutput, len(stxos))
+	var stxoIdx int
+	for _, tx := range blockTxns {
+		isVote := stake.IsSSGen(tx)
+		for txInIdx, txIn := range tx.TxIn {
+			if txInIdx == 0 && isVote {
+				continue
+			}
+			if stxoIdx >= len(stxos) {
+				str := fmt.Sprintf("missing spend journal entries for "+
+					"block %s", block.Hash())
+				return nil, AssertError(str)
+			}
+			stxo := &stxos[stxoIdx]
+			stxoIdx++
+			spent[txIn.PreviousOutPoint] = SpentOutput{
+				Amount:          stxo.amount,
+				ScriptVersion:   stxo.scriptVersion,
+				PkScript:        stxo.pkScript,
+				BlockHeight:     stxo.blockHeight,
+				BlockIndex:      stxo.blockIndex,
+				IsCoinBase:      stxo.IsCoinBase(),
+				TransactionType: stxo.TransactionType(),
+			}
+		}
+	}
+
+	return spent, nil
+}
+
 // MedianTimeByHash returns the median time of a block by the given hash or an
 // error if it doesn't exist.  Note that this will return times from both the
 // main chain and any side chains.
@@ -1746,6 +1880,36 @@ func (b *BlockChain) BlockHashByHeight(height int64) (*chainhash.Hash, error) {
 	return &node.hash, nil
 }
 
+// HeightByMedianTime returns the height of the first block in the main chain
+// with a past median time at or after the provided time.  An error is returned
+// when the past median time of the current best chain tip is before the
+// provided time.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) HeightByMedianTime(t time.Time) (int64, error) {
+	height, ok := b.medianTimes.HeightAtOrAfter(t)
+	if !ok {
+		str := fmt.Sprintf("no block with a median time at or after %v "+
+			"exists", t)
+		return 0, errNotInMainChain(str)
+	}
+
+	return height, nil
+}
+
+// HeightRangeByMedianTime returns the heights of the blocks in the main chain
+// with a past median time in the half open range [start, end) as the half
+// open range of heights [startHeight, endHeight).  The start and end heights
+// are the same when there are no such blocks.
+//
+// The returned range may be used with HeightRange to iterate the hashes of the
+// blocks in the time range.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) HeightRangeByMedianTime(start, end time.Time) (int64, int64) {
+	return b.medianTimes.HeightRange(start, end)
+}
+
 // HeightRange returns a range of block hashes for the given start and end
 // heights.  It is inclusive of the start height and exclusive of the end
 // height.  In other words, it is the half open range [startHeight, endHeight).
@@ -2034,6 +2198,37 @@ func (q *ChainQueryerAdapter) BlockHeaderByHash(hash *chainhash.Hash) (wire.Bloc
 	return q.HeaderByHash(hash)
 }
 
+// PrevScripts returns a source of the scripts and script versions of all
+// transaction outputs spent by the transactions in the passed main chain block.
+// The treasury flag must indicate whether or not the treasury agenda is active
+// as of the block.
+//
+// Unlike FetchSpentOutputs, the chain lock is not acquired, so it is safe to
+// call while processing notifications sent with the chain lock held.  An error
+// is returned when the block is not in the main chain.
+//
+// This is part of the indexers.ChainQueryer interface.
+func (q *ChainQueryerAdapter) PrevScripts(block *dcrutil.Block, isTreasuryEnabled bool) (indexers.PrevScripter, error) {
+	var spent map[wire.OutPoint]SpentOutput
+	err := q.db.View(func(dbTx database.Tx) error {
+		var err error
+		spent, err = dbFetchSpentOutputs(dbTx, block, isTreasuryEnabled)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	source := make(scriptSource, len(spent))
+	for outpoint, output := range spent {
+		source[outpoint] = scriptSourceEntry{
+			version: output.ScriptVersion,
+			script:  output.PkScript,
+		}
+	}
+	return source, nil
+}
+
 // Config is a descriptor which specifies the blockchain instance configuration.
 type Config struct {
 	// DB defines the database which houses the blocks and will be used to
@@ -2113,6 +2308,16 @@ type Config struct {
 	//
 	// This field is required.
 	UtxoCache UtxoCacher
+
+	// ProcessBlockTime defines an optional histogram that is updated with the
+	// time taken to process each block that is not already known, including
+	// validating and connecting it along with any descendants that become
+	// eligible for validation as a result.  Nothing is tracked when it is nil.
+	ProcessBlockTime *metrics.Histogram
+
+	// Tracer defines an optional tracer that is used to trace the processing
+	// of blocks.  Nothing is traced when it is nil.
+	Tracer *tracing.Tracer
 }
 
 // New returns a BlockChain instance using the provided configuration details.
@@ -2191,8 +2396,8 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		subsidyCache:                  subsidyCache,
 		index:                         newBlockIndex(config.DB),
 		bestChain:                     newChainView(nil),
-		recentBlocks:                  lru.NewKVCache(recentBlockCacheSize),
-		recentContextChecks:           lru.NewCache(contextCheckCacheSize),
+		recentBlocks:                  lru.NewMap[chainhash.Hash, *dcrutil.Block](recentBlockCacheSize),
+		recentContextChecks:           lru.NewSet[chainhash.Hash](contextCheckCacheSize),
 		deploymentCaches:              newThresholdCaches(params),
 		isVoterMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		isStakeMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
@@ -2200,6 +2405,8 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		calcVoterVersionIntervalCache: make(map[[chainhash.HashSize]byte]uint32),
 		calcStakeVersionCache:         make(map[[chainhash.HashSize]byte]uint32),
 		utxoCache:                     config.UtxoCache,
+		processBlockTime:              config.ProcessBlockTime,
+		tracer:                        config.Tracer,
 	}
 	b.pruner = newChainPruner(&b)
 
@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/decred/dcrd/blockchain/stake/v5"
 	"github.com/decred/dcrd/blockchain/v5/chaingen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -799,3 +800,119 @@ func TestLocateInventory(t *testing.T) {
 		}
 	}
 }
+
+// TestFetchSpentOutputs ensures the outputs spent by the transactions in main
+// chain blocks are returned with the details of the original outputs and that
+// side chain blocks are rejected.
+func TestFetchSpentOutputs(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g := newChaingenHarness(t, params)
+
+	// ---------------------------------------------------------------------
+	// Generate and accept enough blocks to reach stake validation height
+	// followed by enough blocks to have mature coinbase outputs.
+	// ---------------------------------------------------------------------
+
+	g.AdvanceToStakeValidationHeight()
+	coinbaseMaturity := params.CoinbaseMaturity
+	for i := uint16(0); i < coinbaseMaturity; i++ {
+		outs := g.OldestCoinbaseOuts()
+		blockName := fmt.Sprintf("bbm%d", i)
+		g.NextBlock(blockName, nil, outs[1:])
+		g.SaveTipCoinbaseOuts()
+		g.AcceptTipBlock()
+	}
+
+	// Create a block that spends a coinbase output in the regular tree and
+	// includes votes and ticket purchases in the stake tree.
+	//
+	//   ... -> bbm# -> b1
+	outs := g.OldestCoinbaseOuts()
+	g.NextBlock("b1", &outs[0], outs[1:])
+	g.AcceptTipBlock()
+
+	// Collect all outputs created by the main chain.
+	type createdOut struct {
+		txOut  *wire.TxOut
+		height uint32
+		index  uint32
+	}
+	created := make(map[wire.OutPoint]createdOut)
+	best := g.chain.BestSnapshot()
+	for height := int64(0); height <= best.Height; height++ {
+		block, err := g.chain.BlockByHeight(height)
+		if err != nil {
+			t.Fatalf("unable to fetch block at height %d: %v", height, err)
+		}
+		addOuts := func(txns []*wire.MsgTx, tree int8) {
+			for txIdx, tx := range txns {
+				txHash := tx.TxHash()
+				for i, txOut := range tx.TxOut {
+					outpoint := wire.OutPoint{Hash: txHash, Index: uint32(i),
+						Tree: tree}
+					created[outpoint] = createdOut{txOut, uint32(height),
+						uint32(txIdx)}
+				}
+			}
+		}
+		addOuts(block.MsgBlock().Transactions, wire.TxTreeRegular)
+		addOuts(block.MsgBlock().STransactions, wire.TxTreeStake)
+	}
+
+	// Ensure every output spent by the tip block is returned with the details
+	// of the original output.
+	b1 := dcrutil.NewBlock(g.Tip())
+	spent, err := g.chain.FetchSpentOutputs(b1)
+	if err != nil {
+		t.Fatalf("unexpected error fetching spent outputs: %v", err)
+	}
+	var numSpent int
+	var allTxns []*wire.MsgTx
+	allTxns = append(allTxns, b1.MsgBlock().Transactions[1:]...)
+	allTxns = append(allTxns, b1.MsgBlock().STransactions...)
+	for _, tx := range allTxns {
+		for i, txIn := range tx.TxIn {
+			if i == 0 && stake.IsSSGen(tx) {
+				continue
+			}
+			numSpent++
+			prevOut := txIn.PreviousOutPoint
+			got, ok := spent[prevOut]
+			if !ok {
+				t.Fatalf("missing spent output %v", prevOut)
+			}
+			want, ok := created[prevOut]
+			if !ok {
+				t.Fatalf("spent output %v was not created by main chain",
+					prevOut)
+			}
+			if got.Amount != want.txOut.Value ||
+				got.ScriptVersion != want.txOut.Version ||
+				!bytes.Equal(got.PkScript, want.txOut.PkScript) ||
+				got.BlockHeight != want.height ||
+				got.BlockIndex != want.index {
+
+				t.Fatalf("mismatched spent output %v -- got %+v, want %+v "+
+					"(height %d, index %d)", prevOut, got, want.txOut,
+					want.height, want.index)
+			}
+		}
+	}
+	if len(spent) != numSpent {
+		t.Fatalf("unexpected number of spent outputs -- got %d, want %d",
+			len(spent), numSpent)
+	}
+
+	// Ensure side chain blocks are rejected.
+	//
+	//   ... -> bbm# -> b1
+	//              \-> b1a
+	g.SetTip(fmt.Sprintf("bbm%d", coinbaseMaturity-1))
+	g.NextBlock("b1a", &outs[0], outs[1:])
+	g.AcceptedToSideChainWithExpectedTip("b1")
+	_, err = g.chain.FetchSpentOutputs(dcrutil.NewBlock(g.Tip()))
+	if !isNotInMainChainErr(err) {
+		t.Fatalf("unexpected error for side chain block: %v", err)
+	}
+}
@@ -1681,6 +1681,7 @@ func (b *BlockChain) initChainState(ctx context.Context,
 				"chain tip %s in block index", state.hash))
 		}
 		b.bestChain.SetTip(tip)
+		b.medianTimes.setTip(tip)
 		b.index.MaybePruneCachedTips(tip)
 
 		// Add the best chain tip to the set of candidates since it is required
@@ -0,0 +1,244 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// verifyHeaderBatchSize is the number of main chain blocks that are
+	// checked against the database in each database transaction when
+	// verifying the block index.
+	verifyHeaderBatchSize = 2000
+
+	// verifyBlockCacheSize is the maximum number of blocks that are kept in
+	// memory when verifying the utxo set.
+	verifyBlockCacheSize = 100
+)
+
+// verifyMainChainNode ensures the provided main chain node is at the provided
+// height, links to the provided parent, is consistent with its header, and has
+// its block stored in the database.
+//
+// This function MUST be called with the chain lock held (for reads).
+func (b *BlockChain) verifyMainChainNode(dbTx database.Tx, node, parent *blockNode, height int64) error {
+	if node == nil {
+		str := fmt.Sprintf("main chain is missing the block at height %d",
+			height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	if node.height != height {
+		str := fmt.Sprintf("main chain block %s at height %d claims height %d",
+			node.hash, height, node.height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	if node.parent != parent {
+		str := fmt.Sprintf("main chain block %s (height %d) does not link to "+
+			"the previous main chain block", node.hash, height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	if header := node.Header(); header.BlockHash() != node.hash {
+		str := fmt.Sprintf("block index entry for main chain block %s "+
+			"(height %d) does not match its header", node.hash, height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	status := b.index.NodeStatus(node)
+	if !status.HaveData() {
+		str := fmt.Sprintf("main chain block %s (height %d) is not marked as "+
+			"having its data stored", node.hash, height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	if status.KnownInvalid() {
+		str := fmt.Sprintf("main chain block %s (height %d) is marked as "+
+			"invalid", node.hash, height)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+
+	serialized, err := dbTx.FetchBlockHeader(&node.hash)
+	if err != nil {
+		if errors.Is(err, database.ErrBlockNotFound) {
+			str := fmt.Sprintf("main chain block %s (height %d) is not "+
+				"stored in the database", node.hash, height)
+			return contextError(ErrBlockIndexCorruption, str)
+		}
+		return err
+	}
+	var header wire.BlockHeader
+	if err := header.FromBytes(serialized); err != nil {
+		str := fmt.Sprintf("unable to deserialize the stored header of main "+
+			"chain block %s (height %d): %v", node.hash, height, err)
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+	if header.BlockHash() != node.hash {
+		str := fmt.Sprintf("stored header of main chain block %s (height %d) "+
+			"has hash %s", node.hash, height, header.BlockHash())
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+
+	return nil
+}
+
+// verifyBlockIndex ensures every block in the main chain links to its parent,
+// is consistent with its header, and is stored in the database.
+//
+// This function MUST be called with the chain lock held (for reads).
+func (b *BlockChain) verifyBlockIndex(ctx context.Context) error {
+	genesis := b.bestChain.Genesis()
+	if genesis == nil || genesis.hash != b.chainParams.GenesisHash {
+		str := "main chain does not start with the genesis block of the " +
+			"network"
+		return contextError(ErrBlockIndexCorruption, str)
+	}
+
+	tip := b.bestChain.Tip()
+	var parent *blockNode
+	for start := int64(0); start <= tip.height; start += verifyHeaderBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := start + verifyHeaderBatchSize - 1
+		if end > tip.height {
+			end = tip.height
+		}
+		err := b.db.View(func(dbTx database.Tx) error {
+			for height := start; height <= end; height++ {
+				node := b.bestChain.NodeByHeight(height)
+				err := b.verifyMainChainNode(dbTx, node, parent, height)
+				if err != nil {
+					return err
+				}
+				parent = node
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyUtxoSet ensures every entry in the utxo set matches the output it
+// refers to in the main chain and that the utxo set is as of the current main
+// chain tip.
+//
+// This function MUST be called with the chain lock held (for reads).
+func (b *BlockChain) verifyUtxoSet(ctx context.Context) error {
+	tip := b.bestChain.Tip()
+	blocks := make(map[int64]*dcrutil.Block, verifyBlockCacheSize)
+	err := b.utxoCache.ForEachEntry(&tip.hash, uint32(tip.height),
+		func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			height := entry.BlockHeight()
+			if height < 0 || height > tip.height {
+				str := fmt.Sprintf("utxo %v refers to block height %d which "+
+					"is not in the main chain", outpoint, height)
+				return contextError(ErrUtxoBackendCorruption, str)
+			}
+
+			// Load the block the output was created in.  The utxos are
+			// ordered by outpoint rather than height, so only a limited
+			// number of recently used blocks are kept around.
+			block, ok := blocks[height]
+			if !ok {
+				node := b.bestChain.NodeByHeight(height)
+				var err error
+				block, err = b.fetchMainChainBlockByNode(node)
+				if err != nil {
+					return err
+				}
+				if len(blocks) >= verifyBlockCacheSize {
+					for h := range blocks {
+						delete(blocks, h)
+						break
+					}
+				}
+				blocks[height] = block
+			}
+
+			txns := block.Transactions()
+			if outpoint.Tree == wire.TxTreeStake {
+				txns = block.STransactions()
+			}
+			blockIndex := entry.BlockIndex()
+			if blockIndex >= uint32(len(txns)) ||
+				*txns[blockIndex].Hash() != outpoint.Hash {
+
+				str := fmt.Sprintf("utxo %v does not refer to a transaction "+
+					"in block %s (height %d)", outpoint, block.Hash(), height)
+				return contextError(ErrUtxoBackendCorruption, str)
+			}
+			msgTx := txns[blockIndex].MsgTx()
+			if outpoint.Index >= uint32(len(msgTx.TxOut)) {
+				str := fmt.Sprintf("utxo %v refers to an output that does "+
+					"not exist", outpoint)
+				return contextError(ErrUtxoBackendCorruption, str)
+			}
+			txOut := msgTx.TxOut[outpoint.Index]
+			if txOut.Value != entry.Amount() ||
+				txOut.Version != entry.ScriptVersion() ||
+				!bytes.Equal(txOut.PkScript, entry.PkScript()) {
+
+				str := fmt.Sprintf("utxo %v does not match the output in "+
+					"block %s (height %d)", outpoint, block.Hash(), height)
+				return contextError(ErrUtxoBackendCorruption, str)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	// Ensure the utxo set is as of the main chain tip now that it has been
+	// flushed.
+	state, err := b.utxoCache.FetchBackendState()
+	if err != nil {
+		return err
+	}
+	if state == nil || state.lastFlushHash != tip.hash ||
+		int64(state.lastFlushHeight) != tip.height {
+
+		str := fmt.Sprintf("utxo set is not as of the main chain tip %s "+
+			"(height %d)", tip.hash, tip.height)
+		return contextError(ErrUtxoBackendCorruption, str)
+	}
+
+	return nil
+}
+
+// VerifyChainState walks the main chain in the block index and the entire utxo
+// set to ensure they are consistent with each other and with the blocks stored
+// in the database.
+//
+// A ContextError with the kind ErrBlockIndexCorruption is returned when the
+// first divergence is in the block index, while one with the kind
+// ErrUtxoBackendCorruption is returned when it is in the utxo set.  The
+// verification is aborted when the provided context is canceled.
+//
+// Blocks are NOT connected or disconnected while the chain state is verified.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) VerifyChainState(ctx context.Context) error {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if err := b.verifyBlockIndex(ctx); err != nil {
+		return err
+	}
+	return b.verifyUtxoSet(ctx)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestVerifyChainState ensures verifying the chain state succeeds for a
+// consistent chain and reports divergences in the utxo set and block index.
+func TestVerifyChainState(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g := newChaingenHarness(t, params)
+
+	// Generate and accept enough blocks to reach stake validation height so
+	// the utxo set contains a variety of outputs.
+	g.AdvanceToStakeValidationHeight()
+	for i := 0; i < 2; i++ {
+		g.NextBlock(fmt.Sprintf("bsvh%d", i), nil, nil)
+		g.AcceptTipBlock()
+	}
+
+	ctx := context.Background()
+	if err := g.chain.VerifyChainState(ctx); err != nil {
+		t.Fatalf("unexpected error verifying consistent chain state: %v", err)
+	}
+
+	// Ensure a canceled context aborts the verification.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err := g.chain.VerifyChainState(canceledCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error verifying with canceled context -- got "+
+			"%v, want %v", err, context.Canceled)
+	}
+
+	// Modify the amount of an entry in the utxo backend and ensure the
+	// divergence is detected.
+	backend := g.chain.utxoCache.(*UtxoCache).backend
+	var outpoint wire.OutPoint
+	var entry *UtxoEntry
+	err = backend.ForEachUtxo(func(op wire.OutPoint, e *UtxoEntry) error {
+		if entry == nil && e.Amount() > 0 {
+			outpoint, entry = op, e.Clone()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error iterating utxo set: %v", err)
+	}
+	state, err := backend.FetchState()
+	if err != nil {
+		t.Fatalf("unexpected error fetching utxo set state: %v", err)
+	}
+	corrupted := entry.Clone()
+	corrupted.amount++
+	corrupted.state |= utxoStateModified
+	err = backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{outpoint: corrupted},
+		state)
+	if err != nil {
+		t.Fatalf("unexpected error corrupting utxo: %v", err)
+	}
+	err = g.chain.VerifyChainState(ctx)
+	if !errors.Is(err, ErrUtxoBackendCorruption) {
+		t.Fatalf("unexpected error verifying corrupt utxo set -- got %v, "+
+			"want %v", err, ErrUtxoBackendCorruption)
+	}
+
+	// Restore the entry and mark a main chain block as invalid to ensure the
+	// block index divergence is detected before the utxo set is checked.
+	entry.state |= utxoStateModified
+	err = backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{outpoint: entry},
+		state)
+	if err != nil {
+		t.Fatalf("unexpected error restoring utxo: %v", err)
+	}
+	if err := g.chain.VerifyChainState(ctx); err != nil {
+		t.Fatalf("unexpected error verifying restored chain state: %v", err)
+	}
+	node := g.chain.bestChain.Tip().parent
+	g.chain.index.setStatusFlags(node, statusValidateFailed)
+	err = g.chain.VerifyChainState(ctx)
+	if !errors.Is(err, ErrBlockIndexCorruption) {
+		t.Fatalf("unexpected error verifying corrupt block index -- got %v, "+
+			"want %v", err, ErrBlockIndexCorruption)
+	}
+}
@@ -22,10 +22,10 @@ import (
 	"github.com/decred/dcrd/blockchain/v5/chaingen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/container/lru"
 	"github.com/decred/dcrd/database/v3"
 	_ "github.com/decred/dcrd/database/v3/ffldb"
 	"github.com/decred/dcrd/dcrutil/v4"
-	"github.com/decred/dcrd/lru"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/txscript/v4/sign"
 	"github.com/decred/dcrd/wire"
@@ -199,7 +199,8 @@ func newFakeChain(params *chaincfg.Params) *BlockChain {
 		deploymentCaches:              newThresholdCaches(params),
 		index:                         index,
 		bestChain:                     newChainView(node),
-		recentBlocks:                  lru.NewKVCache(recentBlockCacheSize),
+		recentBlocks:                  lru.NewMap[chainhash.Hash, *dcrutil.Block](recentBlockCacheSize),
+		recentContextChecks:           lru.NewSet[chainhash.Hash](contextCheckCacheSize),
 		isVoterMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		isStakeMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		calcPriorStakeVersionCache:    make(map[[chainhash.HashSize]byte]uint32),
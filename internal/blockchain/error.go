@@ -600,6 +600,10 @@ const (
 	// ErrSerializeHeader indicates an attempt to serialize a block header failed.
 	ErrSerializeHeader = ErrorKind("ErrSerializeHeader")
 
+	// ErrBlockIndexCorruption indicates the block index is not consistent
+	// with the main chain blocks stored in the database.
+	ErrBlockIndexCorruption = ErrorKind("ErrBlockIndexCorruption")
+
 	// ------------------------------------------
 	// Errors related to the UTXO backend.
 	// ------------------------------------------
@@ -636,6 +640,18 @@ const (
 	// ErrNoMissedTicketRevocation indicates that the block does not contain a
 	// revocation for a ticket that is becoming missed as of that block.
 	ErrNoMissedTicketRevocation = ErrorKind("ErrNoMissedTicketRevocation")
+
+	// ------------------------------------------
+	// Errors related to signed blocks.
+	// ------------------------------------------
+
+	// ErrMissingBlockSig indicates that a block on a network that requires
+	// blocks to be signed does not contain a signature.
+	ErrMissingBlockSig = ErrorKind("ErrMissingBlockSig")
+
+	// ErrBadBlockSig indicates that the signature of a block does not satisfy
+	// the block signing challenge of the network.
+	ErrBadBlockSig = ErrorKind("ErrBadBlockSig")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
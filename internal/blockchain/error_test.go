@@ -153,6 +153,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrNoTreasuryBalance, "ErrNoTreasuryBalance"},
 		{ErrInvalidateGenesisBlock, "ErrInvalidateGenesisBlock"},
 		{ErrSerializeHeader, "ErrSerializeHeader"},
+		{ErrBlockIndexCorruption, "ErrBlockIndexCorruption"},
 		{ErrUtxoBackend, "ErrUtxoBackend"},
 		{ErrUtxoBackendCorruption, "ErrUtxoBackendCorruption"},
 		{ErrUtxoBackendNotOpen, "ErrUtxoBackendNotOpen"},
@@ -160,6 +161,8 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrInvalidRevocationTxVersion, "ErrInvalidRevocationTxVersion"},
 		{ErrNoExpiredTicketRevocation, "ErrNoExpiredTicketRevocation"},
 		{ErrNoMissedTicketRevocation, "ErrNoMissedTicketRevocation"},
+		{ErrMissingBlockSig, "ErrMissingBlockSig"},
+		{ErrBadBlockSig, "ErrBadBlockSig"},
 	}
 
 	t.Logf("Running %d tests", len(tests))
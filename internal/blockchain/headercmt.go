@@ -5,6 +5,7 @@
 package blockchain
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/decred/dcrd/blockchain/standalone/v2"
@@ -191,3 +192,124 @@ func (b *BlockChain) FilterByBlockHash(hash *chainhash.Hash) (*gcs.FilterV2, *He
 	}
 	return filter, headerProof, nil
 }
+
+// MainChainFilter houses the header of a block in the main chain along with its
+// version 2 GCS filter and the header commitment inclusion proof that links the
+// hash of the filter to the commitment root in the header.
+type MainChainFilter struct {
+	Height int64
+	Hash   chainhash.Hash
+	Header wire.BlockHeader
+	Filter *gcs.FilterV2
+	Proof  HeaderProof
+}
+
+// mainChainFiltersBatchSize is the maximum number of filters loaded from the
+// database in a single database transaction by MainChainFilters.
+const mainChainFiltersBatchSize = 500
+
+// MainChainFilters invokes the provided function with the header, version 2 GCS
+// filter, and header commitment inclusion proof of each block in the main
+// chain with a height in the range [startHeight, endHeight] in order of
+// increasing height.  The range is truncated to the current tip of the main
+// chain.  Iteration stops early when the function returns an error, which is
+// then returned, or the provided context is canceled.
+//
+// The filters are loaded from the database in batches, so this is
+// significantly more efficient than looking up the filter for each block
+// individually, which makes it well suited for serving ranges of filters to
+// light clients.
+//
+// Every block passed to the function is self-consistent, but the blocks in the
+// range may span a chain reorganization that happens during the iteration.
+// Callers that require a consistent range should ensure the hash of the final
+// block is still part of the main chain once the iteration completes.
+//
+// The data passed to the function is only valid for the duration of the call
+// since it is reused for subsequent blocks.
+//
+// An error that wraps ErrNoFilter will be returned when the filter for one of
+// the blocks does not exist.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) MainChainFilters(ctx context.Context, startHeight, endHeight int64, fn func(*MainChainFilter) error) error {
+	if startHeight < 0 {
+		startHeight = 0
+	}
+	if tipHeight := b.bestChain.Height(); endHeight > tipHeight {
+		endHeight = tipHeight
+	}
+
+	nodes := make([]*blockNode, 0, mainChainFiltersBatchSize)
+	filters := make([]MainChainFilter, 0, mainChainFiltersBatchSize)
+	for height := startHeight; height <= endHeight; {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		// Determine the main chain blocks in the next batch.
+		nodes = nodes[:0]
+		for ; height <= endHeight && len(nodes) < cap(nodes); height++ {
+			node := b.bestChain.NodeByHeight(height)
+			if node == nil {
+				// The main chain was reorganized to a lower height.
+				break
+			}
+			nodes = append(nodes, node)
+		}
+		if len(nodes) == 0 {
+			return nil
+		}
+
+		// Load the filters and associated header commitments of the batch.
+		filters = filters[:0]
+		err := b.db.View(func(dbTx database.Tx) error {
+			for _, node := range nodes {
+				filter, err := dbFetchGCSFilter(dbTx, &node.hash)
+				if err != nil {
+					return err
+				}
+				if filter == nil {
+					str := fmt.Sprintf("no filter available for block %s",
+						node.hash)
+					return contextError(ErrNoFilter, str)
+				}
+
+				leaves, err := dbFetchHeaderCommitments(dbTx, &node.hash)
+				if err != nil {
+					return err
+				}
+
+				const proofIndex = HeaderCmtFilterIndex
+				proof := standalone.GenerateInclusionProof(leaves, proofIndex)
+				filters = append(filters, MainChainFilter{
+					Height: node.height,
+					Hash:   node.hash,
+					Header: node.Header(),
+					Filter: filter,
+					Proof: HeaderProof{
+						ProofIndex:  proofIndex,
+						ProofHashes: proof,
+					},
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range filters {
+			if err := fn(&filters[i]); err != nil {
+				return err
+			}
+		}
+		if len(nodes) < cap(nodes) && height <= endHeight {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -5,9 +5,12 @@
 package blockchain
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
 )
 
 // TestCalcCommitmentRootV1 ensures the expected version 1 commitment root is
@@ -54,3 +57,85 @@ func TestCalcCommitmentRootV1(t *testing.T) {
 		}
 	}
 }
+
+// TestMainChainFilters ensures iterating the filters of a range of main chain
+// blocks produces the same filters and proofs as looking them up individually.
+func TestMainChainFilters(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g := newChaingenHarness(t, params)
+	g.AdvanceToStakeValidationHeight()
+	tipHeight := g.chain.BestSnapshot().Height
+
+	// assertRange ensures iterating the provided range of heights produces the
+	// expected blocks.
+	ctx := context.Background()
+	assertRange := func(startHeight, endHeight, wantStart, wantEnd int64) {
+		t.Helper()
+
+		nextHeight := wantStart
+		err := g.chain.MainChainFilters(ctx, startHeight, endHeight,
+			func(f *MainChainFilter) error {
+				if f.Height != nextHeight {
+					t.Fatalf("unexpected height: got %d, want %d", f.Height,
+						nextHeight)
+				}
+				nextHeight++
+
+				hash, err := g.chain.BlockHashByHeight(f.Height)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if f.Hash != *hash || f.Header.BlockHash() != *hash {
+					t.Fatalf("unexpected block at height %d: got %s, want %s",
+						f.Height, f.Hash, hash)
+				}
+
+				filter, proof, err := g.chain.FilterByBlockHash(hash)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if f.Filter.Hash() != filter.Hash() {
+					t.Fatalf("unexpected filter for block %s", hash)
+				}
+				if f.Proof.ProofIndex != proof.ProofIndex ||
+					len(f.Proof.ProofHashes) != len(proof.ProofHashes) {
+					t.Fatalf("unexpected proof for block %s", hash)
+				}
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if nextHeight != wantEnd+1 {
+			t.Fatalf("unexpected final height: got %d, want %d",
+				nextHeight-1, wantEnd)
+		}
+	}
+	assertRange(0, tipHeight, 0, tipHeight)
+	assertRange(5, 10, 5, 10)
+	assertRange(-1, 2, 0, 2)
+	assertRange(tipHeight-1, tipHeight+10, tipHeight-1, tipHeight)
+
+	// Ensure errors returned by the function stop the iteration.
+	errStop := errors.New("stop")
+	var numCalls int
+	err := g.chain.MainChainFilters(ctx, 0, tipHeight,
+		func(f *MainChainFilter) error {
+			numCalls++
+			return errStop
+		})
+	if !errors.Is(err, errStop) || numCalls != 1 {
+		t.Fatalf("unexpected result -- got err %v after %d calls, want %v "+
+			"after 1 call", err, numCalls, errStop)
+	}
+
+	// Ensure a canceled context aborts the iteration.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = g.chain.MainChainFilters(canceledCtx, 0, tipHeight,
+		func(f *MainChainFilter) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, context.Canceled)
+	}
+}
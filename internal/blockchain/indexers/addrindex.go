@@ -0,0 +1,747 @@
+// Copyright (c) 2016 The btcsuite developers
+// Copyright (c) 2016-2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// addrIndexName is the human-readable name for the index.
+	addrIndexName = "address index"
+
+	// addrIndexVersion is the current version of the address index.
+	addrIndexVersion = 1
+
+	// addrEntryPrefix is the prefix of the keys of the address entries in
+	// the address index.
+	addrEntryPrefix = 'a'
+
+	// addrBlockPrefix is the prefix of the keys of the per-block records of
+	// the addresses used by each block in the address index.
+	addrBlockPrefix = 'b'
+
+	// addrEntryKeySize is the size of an address entry key.  It consists of
+	// 1 byte prefix + 21 bytes address key + 4 bytes block height + 1 byte
+	// tx tree + 4 bytes tx index + 1 byte direction + 4 bytes input or
+	// output index.
+	addrEntryKeySize = 1 + addrKeySize + 4 + 1 + 4 + 1 + 4
+
+	// addrEntryValueSize is the size of an address entry value.  It consists
+	// of 32 bytes transaction hash + 8 bytes amount.
+	addrEntryValueSize = chainhash.HashSize + 8
+
+	// addrTxPrefixSize is the number of bytes of an address entry key that
+	// identify the transaction the entry belongs to.
+	addrTxPrefixSize = 1 + addrKeySize + 4 + 1 + 4
+
+	// addrBlockKeySize is the size of a block record key.  It consists of
+	// 1 byte prefix + 4 bytes block height.
+	addrBlockKeySize = 1 + 4
+)
+
+var (
+	// addrIndexKey is the key of the address index and the db bucket used
+	// to house it.
+	addrIndexKey = []byte("addrtxidx")
+)
+
+// -----------------------------------------------------------------------------
+// The address index maps every address involved in a transaction in the main
+// chain to the transaction along with whether the address was involved by way
+// of an input or an output and the associated amount.  Only standard address
+// types supported by addrToKey are indexed and pay-to-pubkey addresses are
+// indexed as their pay-to-pubkey-hash variants.
+//
+// A single flat bucket houses two kinds of records that are distinguished by
+// a one byte prefix.  The first kind are the address entries which are keyed
+// such that all entries for a given address are stored contiguously in the
+// order they appear in the main chain.  The second kind are per-block records
+// of the addresses used by the block which allow the entries for a block to be
+// removed when it is disconnected without requiring access to the outputs it
+// spent, since those are no longer available once a block is disconnected.
+//
+// All multi-byte integers in keys are serialized as big endian so the keys
+// sort in chain order.  Values use the byte order of the other indexes.
+//
+// The serialized format for keys and values of the address entries is:
+//
+//   <prefix><addr key><height><tree><tx index><direction><io index> =
+//     <txhash><amount>
+//
+//   Field           Type              Size
+//   prefix          byte              1 byte ('a')
+//   addr key        [21]byte          21 bytes
+//   height          uint32            4 bytes
+//   tree            byte              1 byte
+//   tx index        uint32            4 bytes
+//   direction       byte              1 byte (0 = output, 1 = input)
+//   io index        uint32            4 bytes
+//   txhash          chainhash.Hash    32 bytes
+//   amount          int64             8 bytes
+//   -----
+//   Total: 76 bytes
+//
+// The serialized format for keys and values of the block records is:
+//
+//   <prefix><height> = <addr key>...
+//
+//   Field           Type              Size
+//   prefix          byte              1 byte ('b')
+//   height          uint32            4 bytes
+//   addr key        [21]byte          21 bytes per distinct address
+// -----------------------------------------------------------------------------
+
+// AddrIndexEntry describes how an address is involved in a transaction.
+type AddrIndexEntry struct {
+	// IsInput specifies whether the address is involved by way of an input
+	// that spends an output paying to it as opposed to an output paying to
+	// it.
+	IsInput bool
+
+	// Index is the index of the input or output within the transaction.
+	Index uint32
+
+	// Amount is the amount of the input or output in atoms.  For ticket
+	// commitment outputs, it is the committed amount.
+	Amount int64
+}
+
+// AddrIndexTx describes a transaction in the address index along with all of
+// the ways the queried address is involved in it.
+type AddrIndexTx struct {
+	// TxHash is the hash of the transaction.
+	TxHash chainhash.Hash
+
+	// BlockHeight is the height of the block that contains the transaction.
+	BlockHeight int64
+
+	// TxTree is the tree of the block that contains the transaction.
+	TxTree int8
+
+	// TxIndex is the index of the transaction within its tree of the block.
+	TxIndex uint32
+
+	// Entries houses the inputs and outputs of the transaction that involve
+	// the address.
+	Entries []AddrIndexEntry
+}
+
+// addrEntry houses the details of a single address entry of a block.
+type addrEntry struct {
+	addrKey [addrKeySize]byte
+	tree    int8
+	txIndex uint32
+	isInput bool
+	ioIndex uint32
+	txHash  *chainhash.Hash
+	amount  int64
+}
+
+// serializeAddrEntryKey returns the serialized key for the provided address
+// entry at the provided block height.
+func serializeAddrEntryKey(entry *addrEntry, height uint32) []byte {
+	key := make([]byte, addrEntryKeySize)
+	key[0] = addrEntryPrefix
+	offset := 1
+	offset += copy(key[offset:], entry.addrKey[:])
+	binary.BigEndian.PutUint32(key[offset:], height)
+	offset += 4
+	key[offset] = byte(entry.tree)
+	offset++
+	binary.BigEndian.PutUint32(key[offset:], entry.txIndex)
+	offset += 4
+	if entry.isInput {
+		key[offset] = 1
+	}
+	offset++
+	binary.BigEndian.PutUint32(key[offset:], entry.ioIndex)
+	return key
+}
+
+// serializeAddrEntryValue returns the serialized value for the provided
+// address entry.
+func serializeAddrEntryValue(entry *addrEntry) []byte {
+	value := make([]byte, addrEntryValueSize)
+	copy(value, entry.txHash[:])
+	byteOrder.PutUint64(value[chainhash.HashSize:], uint64(entry.amount))
+	return value
+}
+
+// addrEntriesPrefix returns the key prefix shared by all entries of the
+// provided address key.
+func addrEntriesPrefix(addrKey [addrKeySize]byte) []byte {
+	prefix := make([]byte, 1+addrKeySize)
+	prefix[0] = addrEntryPrefix
+	copy(prefix[1:], addrKey[:])
+	return prefix
+}
+
+// addrBlockEntriesPrefix returns the key prefix shared by all entries of the
+// provided address key at the provided block height.
+func addrBlockEntriesPrefix(addrKey [addrKeySize]byte, height uint32) []byte {
+	prefix := make([]byte, 1+addrKeySize+4)
+	copy(prefix, addrEntriesPrefix(addrKey))
+	binary.BigEndian.PutUint32(prefix[1+addrKeySize:], height)
+	return prefix
+}
+
+// addrBlockKey returns the key of the block record at the provided height.
+func addrBlockKey(height uint32) []byte {
+	var key [addrBlockKeySize]byte
+	key[0] = addrBlockPrefix
+	binary.BigEndian.PutUint32(key[1:], height)
+	return key[:]
+}
+
+// blockAddrEntries returns the address entries for all transactions in the
+// provided block.  The previous scripts are used to determine the addresses
+// involved by way of inputs, so inputs are not included when they are nil.
+func blockAddrEntries(block *dcrutil.Block, prevScripts PrevScripter, params *chaincfg.Params) []addrEntry {
+	var entries []addrEntry
+	addTxEntries := func(tx *dcrutil.Tx, tree int8, txIndex uint32) {
+		addEntry := func(addr stdaddr.Address, isInput bool, ioIndex uint32, amount int64) {
+			addrKey, err := addrToKey(addr)
+			if err != nil {
+				// Ignore unsupported address types.
+				return
+			}
+			entries = append(entries, addrEntry{
+				addrKey: addrKey,
+				tree:    tree,
+				txIndex: txIndex,
+				isInput: isInput,
+				ioIndex: ioIndex,
+				txHash:  tx.Hash(),
+				amount:  amount,
+			})
+		}
+
+		msgTx := tx.MsgTx()
+		if prevScripts != nil {
+			for i, txIn := range msgTx.TxIn {
+				// Inputs that do not spend an output, such as coinbases and
+				// stakebases, are not in the previous scripts.
+				version, script, ok := prevScripts.PrevScript(&txIn.PreviousOutPoint)
+				if !ok {
+					continue
+				}
+				_, addrs := stdscript.ExtractAddrs(version, script, params)
+				for _, addr := range addrs {
+					addEntry(addr, true, uint32(i), txIn.ValueIn)
+				}
+			}
+		}
+
+		isSStx := stake.IsSStx(msgTx)
+		for i, txOut := range msgTx.TxOut {
+			scriptType, addrs := stdscript.ExtractAddrs(txOut.Version,
+				txOut.PkScript, params)
+			if isSStx && scriptType == stdscript.STNullData {
+				addr, err := stake.AddrFromSStxPkScrCommitment(txOut.PkScript,
+					params)
+				if err != nil {
+					continue
+				}
+				amount, err := stake.AmountFromSStxPkScrCommitment(txOut.PkScript)
+				if err != nil {
+					continue
+				}
+				addEntry(addr, false, uint32(i), int64(amount))
+				continue
+			}
+			for _, addr := range addrs {
+				addEntry(addr, false, uint32(i), txOut.Value)
+			}
+		}
+	}
+
+	for i, tx := range block.Transactions() {
+		addTxEntries(tx, wire.TxTreeRegular, uint32(i))
+	}
+	for i, tx := range block.STransactions() {
+		addTxEntries(tx, wire.TxTreeStake, uint32(i))
+	}
+	return entries
+}
+
+// AddrIndex implements an address to transaction index.  That is to say, it
+// supports querying all main chain transactions that involve a given address
+// by way of either their inputs or their outputs.
+type AddrIndex struct {
+	// These fields provide access to the chain queryer and the
+	// database of the index.
+	db    database.DB
+	chain ChainQueryer
+
+	// These fields track the notification subscription for the index
+	// and its subscribers.
+	sub         *IndexSubscription
+	subscribers map[chan bool]struct{}
+
+	mtx    sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Ensure the AddrIndex type implements the Indexer interface.
+var _ Indexer = (*AddrIndex)(nil)
+
+// Ensure the AddrIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*AddrIndex)(nil)
+
+// NewAddrIndex returns a new instance of an indexer that is used to create a
+// mapping of all addresses involved in transactions in the blockchain to the
+// respective transactions, whether they are involved by way of an input or an
+// output, and the associated amounts.
+func NewAddrIndex(subscriber *IndexSubscriber, db database.DB, chain ChainQueryer) (*AddrIndex, error) {
+	idx := &AddrIndex{
+		db:          db,
+		chain:       chain,
+		subscribers: make(map[chan bool]struct{}),
+		cancel:      subscriber.cancel,
+	}
+
+	// The address index is an optional index. It has no prequisite and
+	// is updated asynchronously.
+	sub, err := subscriber.Subscribe(idx, noPrereqs)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.sub = sub
+
+	err = idx.Init(subscriber.ctx, chain.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Init initializes the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Init(ctx context.Context, chainParams *chaincfg.Params) error {
+	if interruptRequested(ctx) {
+		return indexerError(ErrInterruptRequested, interruptMsg)
+	}
+
+	// Finish any drops that were previously interrupted.
+	if err := finishDrop(ctx, idx); err != nil {
+		return err
+	}
+
+	// Create the initial state for the index as needed.
+	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Upgrade the index as needed.
+	if err := upgradeIndex(ctx, idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Recover the address index and its dependents to the main chain if
+	// needed.
+	return recoverIndex(ctx, idx)
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Key() []byte {
+	return addrIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Name() string {
+	return addrIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Version() uint32 {
+	return addrIndexVersion
+}
+
+// DB returns the database of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) DB() database.DB {
+	return idx.db
+}
+
+// Queryer returns the chain queryer.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Queryer() ChainQueryer {
+	return idx.chain
+}
+
+// Tip returns the current tip of the index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Tip() (int64, *chainhash.Hash, error) {
+	return tip(idx.db, idx.Key())
+}
+
+// IndexSubscription returns the subscription for index updates.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) IndexSubscription() *IndexSubscription {
+	return idx.sub
+}
+
+// Subscribers returns all client channels waiting for the next index update.
+//
+// This is part of the Indexer interface.
+// Deprecated: This will be removed in the next major version bump.
+func (idx *AddrIndex) Subscribers() map[chan bool]struct{} {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	return idx.subscribers
+}
+
+// NotifySyncSubscribers signals subscribers of an index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) NotifySyncSubscribers() {
+	idx.mtx.Lock()
+	notifySyncSubscribers(idx.subscribers)
+	idx.mtx.Unlock()
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) WaitForSync() chan bool {
+	c := make(chan bool)
+
+	idx.mtx.Lock()
+	idx.subscribers[c] = struct{}{}
+	idx.mtx.Unlock()
+
+	return c
+}
+
+// Create is invoked when the index is created for the first time.  It creates
+// the bucket for the address index.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(addrIndexKey)
+	return err
+}
+
+// connectBlock adds an entry for every input and output of the transactions
+// in the passed block that involve an address along with a record of the
+// addresses used by the block.
+func (idx *AddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block, isTreasuryEnabled bool) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index for the same reasons it is
+	// ignored by the transaction index.  See the comments in the connectBlock
+	// method of the transaction index for the specifics.
+
+	// Load the scripts of the outputs spent by the block in order to
+	// determine the addresses involved by way of inputs.  Since the index is
+	// updated asynchronously, the block might already have been disconnected
+	// from the main chain, in which case the spent outputs are no longer
+	// available.  Index the outputs alone in that case since the block will
+	// be disconnected from the index by a later notification anyway.
+	prevScripts, err := idx.chain.PrevScripts(block, isTreasuryEnabled)
+	if err != nil {
+		if idx.chain.MainChainHasBlock(block.Hash()) {
+			return err
+		}
+		log.Debugf("Indexing outputs of block %s (height %d) only since it "+
+			"is no longer in the main chain", block.Hash(), block.Height())
+		prevScripts = nil
+	}
+
+	height := uint32(block.Height())
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	entries := blockAddrEntries(block, prevScripts, idx.chain.ChainParams())
+	usedAddrs := make(map[[addrKeySize]byte]struct{})
+	var blockRecord []byte
+	for i := range entries {
+		entry := &entries[i]
+		err := bucket.Put(serializeAddrEntryKey(entry, height),
+			serializeAddrEntryValue(entry))
+		if err != nil {
+			return err
+		}
+
+		if _, ok := usedAddrs[entry.addrKey]; !ok {
+			usedAddrs[entry.addrKey] = struct{}{}
+			blockRecord = append(blockRecord, entry.addrKey[:]...)
+		}
+	}
+
+	// Record the addresses used by the block so the entries can be removed
+	// when it is disconnected.
+	if err := bucket.Put(addrBlockKey(height), blockRecord); err != nil {
+		return err
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), block.Hash(), int32(block.Height()))
+}
+
+// disconnectBlock removes all entries for the transactions in the passed block
+// along with the record of the addresses used by the block.
+func (idx *AddrIndex) disconnectBlock(dbTx database.Tx, block *dcrutil.Block) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored when disconnecting blocks because it is also
+	// ignored when connecting the block.
+
+	height := uint32(block.Height())
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	blockKey := addrBlockKey(height)
+	blockRecord := bucket.Get(blockKey)
+	if len(blockRecord)%addrKeySize != 0 {
+		str := fmt.Sprintf("corrupt address index block record at height %d",
+			height)
+		return makeDbErr(database.ErrCorruption, str)
+	}
+
+	// Remove the entries of all addresses used by the block.  The iterator
+	// is a snapshot, so it is not affected by the removals.
+	for offset := 0; offset < len(blockRecord); offset += addrKeySize {
+		var addrKey [addrKeySize]byte
+		copy(addrKey[:], blockRecord[offset:])
+		iter, err := bucket.PrefixIterator(addrBlockEntriesPrefix(addrKey,
+			height), false)
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := bucket.Delete(iter.Key()); err != nil {
+				iter.Release()
+				return err
+			}
+		}
+		iter.Release()
+	}
+	if err := bucket.Delete(blockKey); err != nil {
+		return err
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), &block.MsgBlock().Header.PrevBlock,
+		int32(block.Height()-1))
+}
+
+// dbFetchAddrIndexTxns uses an existing database transaction to fetch the
+// transactions that involve the provided address key from the address index.
+// The provided number of transactions are skipped and at most count
+// transactions are returned in chain order, or in reverse chain order when the
+// reverse flag is set.
+func dbFetchAddrIndexTxns(dbTx database.Tx, addrKey [addrKeySize]byte, skip, count int, reverse bool) ([]AddrIndexTx, error) {
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	iter, err := bucket.PrefixIterator(addrEntriesPrefix(addrKey), reverse)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Release()
+
+	var txns []AddrIndexTx
+	var curTxPrefix []byte
+	var numTxns int
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if len(key) != addrEntryKeySize || len(value) != addrEntryValueSize {
+			str := fmt.Sprintf("corrupt address index entry %x", key)
+			return nil, makeDbErr(database.ErrCorruption, str)
+		}
+
+		// Start a new transaction when the entry belongs to a different
+		// transaction than the previous one.
+		if !bytes.Equal(key[:addrTxPrefixSize], curTxPrefix) {
+			curTxPrefix = key[:addrTxPrefixSize]
+			numTxns++
+			if numTxns <= skip {
+				continue
+			}
+			if len(txns) >= count {
+				break
+			}
+
+			offset := 1 + addrKeySize
+			tx := AddrIndexTx{
+				BlockHeight: int64(binary.BigEndian.Uint32(key[offset:])),
+				TxTree:      int8(key[offset+4]),
+				TxIndex:     binary.BigEndian.Uint32(key[offset+5:]),
+			}
+			copy(tx.TxHash[:], value)
+			txns = append(txns, tx)
+		}
+		if numTxns <= skip {
+			continue
+		}
+
+		offset := addrTxPrefixSize
+		tx := &txns[len(txns)-1]
+		tx.Entries = append(tx.Entries, AddrIndexEntry{
+			IsInput: key[offset] == 1,
+			Index:   binary.BigEndian.Uint32(key[offset+1:]),
+			Amount:  int64(byteOrder.Uint64(value[chainhash.HashSize:])),
+		})
+	}
+
+	// Entries within each transaction are visited in reverse order when
+	// iterating in reverse, so restore their natural order.
+	if reverse {
+		for i := range txns {
+			entries := txns[i].Entries
+			for j, k := 0, len(entries)-1; j < k; j, k = j+1, k-1 {
+				entries[j], entries[k] = entries[k], entries[j]
+			}
+		}
+	}
+	return txns, nil
+}
+
+// Transactions returns the main chain transactions that involve the provided
+// address from the address index along with the inputs and outputs through
+// which the address is involved.  The provided number of transactions are
+// skipped and at most count transactions are returned in chain order, or in
+// reverse chain order when the reverse flag is set.
+//
+// This function is safe for concurrent access.
+func (idx *AddrIndex) Transactions(addr stdaddr.Address, skip, count int, reverse bool) ([]AddrIndexTx, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var txns []AddrIndexTx
+	err = idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		txns, err = dbFetchAddrIndexTxns(dbTx, addrKey, skip, count, reverse)
+		return err
+	})
+	return txns, err
+}
+
+// DropAddrIndex drops the address index from the provided database if it
+// exists.
+func DropAddrIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, addrIndexKey, addrIndexName)
+}
+
+// DropIndex drops the address index from the provided database if it exists.
+func (*AddrIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropAddrIndex(ctx, db)
+}
+
+// verifyAddrIndexEntries ensures the address index has a correct entry for
+// every input and output of the transactions in the provided block that
+// involve an address along with a record of the addresses used by the block.
+func verifyAddrIndexEntries(dbTx database.Tx, chain ChainQueryer, block *dcrutil.Block) error {
+	isTreasuryEnabled, err := chain.IsTreasuryAgendaActive(
+		&block.MsgBlock().Header.PrevBlock)
+	if err != nil {
+		return err
+	}
+	prevScripts, err := chain.PrevScripts(block, isTreasuryEnabled)
+	if err != nil {
+		return err
+	}
+
+	height := uint32(block.Height())
+	bucket := dbTx.Metadata().Bucket(addrIndexKey)
+	blockRecord := bucket.Get(addrBlockKey(height))
+	recordHasAddr := func(addrKey [addrKeySize]byte) bool {
+		for offset := 0; offset+addrKeySize <= len(blockRecord); offset += addrKeySize {
+			if bytes.Equal(blockRecord[offset:offset+addrKeySize], addrKey[:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	entries := blockAddrEntries(block, prevScripts, chain.ChainParams())
+	for i := range entries {
+		entry := &entries[i]
+		value := bucket.Get(serializeAddrEntryKey(entry, height))
+		if !bytes.Equal(value, serializeAddrEntryValue(entry)) {
+			msg := fmt.Sprintf("%s: missing or incorrect entry for address "+
+				"with key %x in transaction %s in block %s (height %d)",
+				addrIndexName, entry.addrKey, entry.txHash, block.Hash(),
+				block.Height())
+			return indexerError(ErrIndexCorruption, msg)
+		}
+		if !recordHasAddr(entry.addrKey) {
+			msg := fmt.Sprintf("%s: address record for block %s (height %d) "+
+				"is missing address with key %x", addrIndexName, block.Hash(),
+				block.Height(), entry.addrKey)
+			return indexerError(ErrIndexCorruption, msg)
+		}
+	}
+	return nil
+}
+
+// VerifyAddrIndex ensures the address index in the provided database, if it
+// exists, has a correct entry for every input and output that involves an
+// address in the main chain blocks up to the index tip.  An error with the
+// kind ErrIndexCorruption that describes the first divergence is returned when
+// it does not.
+//
+// A damaged index can be rebuilt by dropping it with DropAddrIndex and loading
+// it again.
+func VerifyAddrIndex(ctx context.Context, db database.DB, chain ChainQueryer) error {
+	return verifyIndex(ctx, db, chain, addrIndexKey, addrIndexName,
+		func(dbTx database.Tx, block *dcrutil.Block) error {
+			return verifyAddrIndexEntries(dbTx, chain, block)
+		})
+}
+
+// ProcessNotification indexes the provided notification based on its
+// notification type.
+//
+// This is part of the Indexer interface.
+func (idx *AddrIndex) ProcessNotification(dbTx database.Tx, ntfn *IndexNtfn) error {
+	switch ntfn.NtfnType {
+	case ConnectNtfn:
+		err := idx.connectBlock(dbTx, ntfn.Block, ntfn.IsTreasuryEnabled)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to connect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrConnectBlock, msg)
+		}
+
+	case DisconnectNtfn:
+		err := idx.disconnectBlock(dbTx, ntfn.Block)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to disconnect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrDisconnectBlock, msg)
+		}
+
+	default:
+		msg := fmt.Sprintf("%s: unknown notification type received: %d",
+			idx.Name(), ntfn.NtfnType)
+		return indexerError(ErrInvalidNotificationType, msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/v5/chaingen"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// TestAddrIndex ensures the address index indexes the inputs and outputs of
+// transactions, supports paginated queries, and removes the entries of blocks
+// that are disconnected.
+func TestAddrIndex(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain followed by a block that spends a
+	// coinbase output of the second block.
+	addBlock(t, chain, &g, "bk1")
+	addBlock(t, chain, &g, "bk2")
+	bk3 := addBlock(t, chain, &g, "bk3")
+	outs := g.OldestCoinbaseOuts()
+	bk4 := dcrutil.NewBlock(g.NextBlock("bk4", &outs[0], nil))
+	g.SaveTipCoinbaseOuts()
+	if err := chain.AddBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+
+	// Initialize the address index.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	idx, err := NewAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index got synced to bk4 on initialization.
+	tipHeight, tipHash, err := idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk4.Height() || *tipHash != *bk4.Hash() {
+		t.Fatalf("expected tip %s (height %d), got %s (height %d)",
+			bk4.Hash(), bk4.Height(), tipHash, tipHeight)
+	}
+
+	// Ensure the spending transaction is indexed with both its input and its
+	// output since they involve the same address.
+	addr := g.P2shOpTrueAddr()
+	txns, err := idx.Transactions(addr, 0, 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spendTx := bk4.Transactions()[1]
+	var found bool
+	for _, tx := range txns {
+		if tx.TxHash != *spendTx.Hash() {
+			continue
+		}
+		found = true
+		want := AddrIndexTx{
+			TxHash:      *spendTx.Hash(),
+			BlockHeight: bk4.Height(),
+			TxTree:      0,
+			TxIndex:     1,
+			Entries: []AddrIndexEntry{{
+				IsInput: false,
+				Index:   0,
+				Amount:  spendTx.MsgTx().TxOut[0].Value,
+			}, {
+				IsInput: true,
+				Index:   0,
+				Amount:  spendTx.MsgTx().TxIn[0].ValueIn,
+			}},
+		}
+		if !reflect.DeepEqual(tx, want) {
+			t.Fatalf("mismatched spend entry: got %+v, want %+v", tx, want)
+		}
+	}
+	if !found {
+		t.Fatalf("spending transaction %s is not indexed", spendTx.Hash())
+	}
+
+	// Ensure pagination and reverse ordering work as expected.
+	if len(txns) < 3 {
+		t.Fatalf("expected at least 3 transactions, got %d", len(txns))
+	}
+	page, err := idx.Transactions(addr, 1, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(page, txns[1:3]) {
+		t.Fatalf("mismatched page: got %+v, want %+v", page, txns[1:3])
+	}
+	reversed, err := idx.Transactions(addr, 0, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reversed) != 1 || !reflect.DeepEqual(reversed[0], txns[len(txns)-1]) {
+		t.Fatalf("mismatched reverse page: got %+v, want %+v", reversed,
+			txns[len(txns)-1])
+	}
+
+	// Ensure the index verifies against the chain.
+	if err := VerifyAddrIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+
+	// Ensure the entries of bk4 are removed when it is disconnected.
+	if err := chain.RemoveBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+	g.SetTip("bk3")
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk4,
+		Parent:   bk3,
+	})
+
+	afterTxns, err := idx.Transactions(addr, 0, 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tx := range afterTxns {
+		if tx.BlockHeight >= bk4.Height() {
+			t.Fatalf("transaction %s at height %d still indexed after "+
+				"disconnect", tx.TxHash, tx.BlockHeight)
+		}
+	}
+	if len(afterTxns) >= len(txns) {
+		t.Fatalf("expected fewer than %d transactions after disconnect, "+
+			"got %d", len(txns), len(afterTxns))
+	}
+	err = db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(addrIndexKey)
+		if bucket.Get(addrBlockKey(uint32(bk4.Height()))) != nil {
+			t.Fatal("block record for bk4 still exists after disconnect")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure verification detects a missing entry.
+	err = db.Update(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(addrIndexKey)
+		iter, err := bucket.PrefixIterator([]byte{addrEntryPrefix}, true)
+		if err != nil {
+			return err
+		}
+		defer iter.Release()
+		if !iter.Next() {
+			t.Fatal("no address entries to remove")
+		}
+		return bucket.Delete(iter.Key())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyAddrIndex(ctx, db, chain)
+	if !errors.Is(err, ErrIndexCorruption) {
+		t.Fatalf("expected corruption error, got %v", err)
+	}
+}
@@ -67,6 +67,17 @@ type ChainQueryer interface {
 	// IsTreasuryAgendaActive returns true if the treasury agenda is active at
 	// the provided block.
 	IsTreasuryAgendaActive(*chainhash.Hash) (bool, error)
+
+	// PrevScripts returns a source of previous transaction scripts and their
+	// associated versions spent by the given main chain block.
+	PrevScripts(*dcrutil.Block, bool) (PrevScripter, error)
+}
+
+// PrevScripter defines an interface that provides access to scripts and their
+// associated version keyed by an outpoint.  The boolean indicates whether or
+// not the script and version for the provided outpoint was found.
+type PrevScripter interface {
+	PrevScript(*wire.OutPoint) (uint16, []byte, bool)
 }
 
 // Indexer defines a generic interface for an indexer.
@@ -648,7 +659,7 @@ func notifyDependent(ctx context.Context, indexer Indexer, ntfn *IndexNtfn) erro
 
 // updateIndex processes the notification for the provided index.
 func updateIndex(ctx context.Context, indexer Indexer, ntfn *IndexNtfn) error {
-	tip, _, err := indexer.Tip()
+	tip, tipHash, err := indexer.Tip()
 	if err != nil {
 		msg := fmt.Sprintf("%s: unable to fetch index tip: %v",
 			indexer.Name(), err)
@@ -667,6 +678,36 @@ func updateIndex(ctx context.Context, indexer Indexer, ntfn *IndexNtfn) error {
 		return indexerError(ErrInvalidNotificationType, msg)
 	}
 
+	// Notifications may be delivered out of step with an index that is being
+	// caught up in the background since the catch up process indexes blocks
+	// fetched directly from the chain concurrently with the notifications.
+	//
+	// Notifications for blocks after the index tip are ignored while catching
+	// up since the catch up process indexes those blocks once it reaches
+	// them.  Similarly, notifications that do not build on or disconnect the
+	// index tip are ignored since the catch up process may have already
+	// indexed the blocks of the chain that replaced them.
+	var backgroundCatchUp, catchingUp bool
+	if sub := indexer.IndexSubscription(); sub != nil && sub.subscriber != nil {
+		backgroundCatchUp = sub.subscriber.catchUpQueryer != nil
+		catchingUp = sub.subscriber.isCatchingUp()
+	}
+	if backgroundCatchUp && ntfn.Block.Height() == expectedHeight {
+		skip := false
+		switch ntfn.NtfnType {
+		case ConnectNtfn:
+			skip = catchingUp && ntfn.Block.MsgBlock().Header.PrevBlock != *tipHash
+		case DisconnectNtfn:
+			skip = *ntfn.Block.Hash() != *tipHash
+		}
+		if skip {
+			log.Tracef("%s: ignoring notification for block %s (height %d) "+
+				"that does not apply to the index tip", indexer.Name(),
+				ntfn.Block.Hash(), ntfn.Block.Height())
+			return nil
+		}
+	}
+
 	switch {
 	case ntfn.Block.Height() < expectedHeight:
 		// Relay the notification to the dependent if its height is less
@@ -676,6 +717,13 @@ func updateIndex(ctx context.Context, indexer Indexer, ntfn *IndexNtfn) error {
 			indexer.Name(), ntfn.Block.Height())
 		notifyDependent(ctx, indexer, ntfn)
 
+	case ntfn.Block.Height() > expectedHeight && (catchingUp ||
+		(backgroundCatchUp && ntfn.NtfnType == DisconnectNtfn)):
+		// Ignore notifications for blocks the index has not reached yet
+		// while it is being caught up in the background as described above.
+		log.Tracef("%s: ignoring notification for height %d while catching "+
+			"up", indexer.Name(), ntfn.Block.Height())
+
 	case ntfn.Block.Height() > expectedHeight:
 		// Receiving a notification with a height higher than the expected
 		// implies a missed index update.
@@ -705,3 +753,84 @@ func updateIndex(ctx context.Context, indexer Indexer, ntfn *IndexNtfn) error {
 
 	return nil
 }
+
+// verifyIndex ensures the index keyed by idxKey is consistent with every main
+// chain block up to the index tip by invoking the provided function with each
+// of them.  The function must return an error with the kind ErrIndexCorruption
+// when the index entries for the block are not consistent with it.
+//
+// The tip of the index is not required to be on the main chain since the index
+// is recovered to the main chain when it is next loaded, so only the blocks up
+// to the point the index tip forks from the main chain are verified in that
+// case.  Nothing is verified when the index does not exist.
+func verifyIndex(ctx context.Context, db database.DB, chain ChainQueryer, idxKey []byte, idxName string, verifyBlock func(database.Tx, *dcrutil.Block) error) error {
+	exists, err := existsIndex(db, idxKey)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Infof("Not verifying %s because it does not exist", idxName)
+		return nil
+	}
+
+	height, hash, err := tip(db, idxKey)
+	if err != nil {
+		return err
+	}
+
+	// Find the point the index tip forks from the main chain.
+	for !chain.MainChainHasBlock(hash) {
+		header, err := chain.BlockHeaderByHash(hash)
+		if err != nil {
+			msg := fmt.Sprintf("%s: tip %s (height %d) is not a known block",
+				idxName, hash, height)
+			return indexerError(ErrIndexCorruption, msg)
+		}
+		hash = &header.PrevBlock
+		height--
+	}
+	mainHeight, err := chain.BlockHeightByHash(hash)
+	if err != nil {
+		return err
+	}
+	if mainHeight != height {
+		msg := fmt.Sprintf("%s: tip %s is recorded at height %d instead of "+
+			"height %d", idxName, hash, height, mainHeight)
+		return indexerError(ErrIndexCorruption, msg)
+	}
+
+	log.Infof("Verifying %s up to height %d.  This might take a while...",
+		idxName, height)
+
+	progressLogger := progresslog.NewBlockProgressLogger("Verified", log)
+	var parent *dcrutil.Block
+	for h := int64(0); h <= height; h++ {
+		if interruptRequested(ctx) {
+			return indexerError(ErrInterruptRequested, interruptMsg)
+		}
+
+		blockHash, err := chain.BlockHashByHeight(h)
+		if err != nil {
+			return err
+		}
+		block, err := chain.BlockByHash(blockHash)
+		if err != nil {
+			return err
+		}
+
+		// The genesis block is never indexed.
+		if h > 0 {
+			err = db.View(func(dbTx database.Tx) error {
+				return verifyBlock(dbTx, block)
+			})
+			if err != nil {
+				return err
+			}
+			progressLogger.LogBlockHeight(block.MsgBlock(), parent.MsgBlock())
+		}
+		parent = block
+	}
+
+	log.Infof("Verified %s", idxName)
+	return nil
+}
@@ -42,6 +42,10 @@ const (
 	// ErrBlockNotOnMainChain indicates the provided block is not on the
 	// main chain.
 	ErrBlockNotOnMainChain = ErrorKind("ErrBlockNotOnMainChain")
+
+	// ErrIndexCorruption indicates an index is not consistent with the main
+	// chain.
+	ErrIndexCorruption = ErrorKind("ErrIndexCorruption")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
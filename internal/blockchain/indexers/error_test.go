@@ -26,6 +26,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrFetchTip, "ErrFetchTip"},
 		{ErrMissingNotification, "ErrMissingNotification"},
 		{ErrBlockNotOnMainChain, "ErrBlockNotOnMainChain"},
+		{ErrIndexCorruption, "ErrIndexCorruption"},
 	}
 
 	for i, test := range tests {
@@ -95,7 +95,7 @@ func addrToKey(addr stdaddr.Address) ([addrKeySize]byte, error) {
 		return result, nil
 	}
 	return [addrKeySize]byte{}, indexerError(ErrUnsupportedAddressType,
-		"address type is not supported by the exists address index")
+		"address type is not supported by the address indexes")
 }
 
 // ExistsAddrIndex implements an "ever seen" address index.  Any address that
@@ -378,22 +378,9 @@ func (idx *ExistsAddrIndex) ExistsAddresses(addrs []stdaddr.Address) ([]bool, er
 	return exists, nil
 }
 
-// connectBlock adds all addresses associated with transactions in the
-// provided block.
-//
-// This is part of the Indexer interface.
-func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block) error {
-	// NOTE: The fact that the block can disapprove the regular tree of the
-	// previous block is ignored for this index because even though technically
-	// the address might become unused again if its only use was in a
-	// transaction that was disapproved, the chances of that are extremely low
-	// since disapproved transactions are nearly always mined again in another
-	// block.
-	//
-	// More importantly, the primary purpose of this index is to track whether
-	// or not addresses have ever been seen, so even if they technically end up
-	// becoming unused, they were still seen.
-
+// usedAddrKeys returns the keys of all addresses used by the transactions in
+// the provided block that are tracked by the exists address index.
+func usedAddrKeys(block *dcrutil.Block, params *chaincfg.Params) map[[addrKeySize]byte]struct{} {
 	usedAddrs := make(map[[addrKeySize]byte]struct{})
 	blockTxns := make([]*dcrutil.Tx, 0, len(block.Transactions())+
 		len(block.STransactions()))
@@ -410,7 +397,7 @@ func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block)
 				continue
 			}
 			rs := stdscript.MultiSigRedeemScriptFromScriptSigV0(txIn.SignatureScript)
-			typ, addrs := stdscript.ExtractAddrsV0(rs, idx.chain.ChainParams())
+			typ, addrs := stdscript.ExtractAddrsV0(rs, params)
 			if typ != stdscript.STMultiSig {
 				// This should never happen, but be paranoid.
 				continue
@@ -428,7 +415,7 @@ func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block)
 
 		for _, txOut := range tx.MsgTx().TxOut {
 			scriptType, addrs := stdscript.ExtractAddrs(txOut.Version,
-				txOut.PkScript, idx.chain.ChainParams())
+				txOut.PkScript, params)
 			if scriptType == stdscript.STNonStandard {
 				// Non-standard outputs are skipped.
 				continue
@@ -436,7 +423,7 @@ func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block)
 
 			if isSStx && scriptType == stdscript.STNullData {
 				addr, err := stake.AddrFromSStxPkScrCommitment(txOut.PkScript,
-					idx.chain.ChainParams())
+					params)
 				if err != nil {
 					// Ignore unsupported address types.
 					continue
@@ -457,6 +444,27 @@ func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block)
 		}
 	}
 
+	return usedAddrs
+}
+
+// connectBlock adds all addresses associated with transactions in the
+// provided block.
+//
+// This is part of the Indexer interface.
+func (idx *ExistsAddrIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because even though technically
+	// the address might become unused again if its only use was in a
+	// transaction that was disapproved, the chances of that are extremely low
+	// since disapproved transactions are nearly always mined again in another
+	// block.
+	//
+	// More importantly, the primary purpose of this index is to track whether
+	// or not addresses have ever been seen, so even if they technically end up
+	// becoming unused, they were still seen.
+
+	usedAddrs := usedAddrKeys(block, idx.chain.ChainParams())
+
 	// Write all the newly used addresses to the database,
 	// skipping any keys that already exist. Write any
 	// addresses we see in mempool at this time, too,
@@ -600,6 +608,30 @@ func (*ExistsAddrIndex) DropIndex(ctx context.Context, db database.DB) error {
 	return DropExistsAddrIndex(ctx, db)
 }
 
+// VerifyExistsAddrIndex ensures the exists address index in the provided
+// database, if it exists, contains every address used in the main chain blocks
+// up to the index tip.  An error with the kind ErrIndexCorruption that
+// describes the first divergence is returned when it does not.
+//
+// A damaged index can be rebuilt by dropping it with DropExistsAddrIndex and
+// loading it again.
+func VerifyExistsAddrIndex(ctx context.Context, db database.DB, chain ChainQueryer) error {
+	params := chain.ChainParams()
+	return verifyIndex(ctx, db, chain, existsAddrIndexKey,
+		existsAddressIndexName, func(dbTx database.Tx, block *dcrutil.Block) error {
+			bucket := dbTx.Metadata().Bucket(existsAddrIndexKey)
+			for addrKey := range usedAddrKeys(block, params) {
+				if bucket.Get(addrKey[:]) == nil {
+					msg := fmt.Sprintf("%s: missing address with key %x "+
+						"used in block %s (height %d)", existsAddressIndexName,
+						addrKey, block.Hash(), block.Height())
+					return indexerError(ErrIndexCorruption, msg)
+				}
+			}
+			return nil
+		})
+}
+
 // ProcessNotification indexes the provided notification based on its
 // notification type.
 //
@@ -6,11 +6,13 @@ package indexers
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/decred/dcrd/blockchain/v5/chaingen"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
 	"github.com/decred/dcrd/txscript/v4/stdscript"
 )
 
@@ -311,3 +313,72 @@ func TestExistsAddrIndexAsync(t *testing.T) {
 		t.Fatalf("expected tip hash to be %s, got %s", bk4a.Hash(), tipHash)
 	}
 }
+
+// TestVerifyExistsAddrIndex ensures verifying the exists address index
+// succeeds for a consistent index and reports missing addresses.
+func TestVerifyExistsAddrIndex(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain.
+	addBlock(t, chain, &g, "bk1")
+	bk2 := addBlock(t, chain, &g, "bk2")
+	addBlock(t, chain, &g, "bk3")
+
+	// Initialize the exists address index.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	_, err = NewExistsAddrIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyExistsAddrIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected error verifying consistent index: %v", err)
+	}
+
+	// Remove an address used in bk2 and ensure the divergence is detected.
+	addrKeys := usedAddrKeys(bk2, chain.ChainParams())
+	if len(addrKeys) == 0 {
+		t.Fatal("expected bk2 to use at least one address")
+	}
+	err = db.Update(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(existsAddrIndexKey)
+		for addrKey := range addrKeys {
+			if err := bucket.Delete(addrKey[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyExistsAddrIndex(ctx, db, chain)
+	if !errors.Is(err, ErrIndexCorruption) {
+		t.Fatalf("expected error %v, got %v", ErrIndexCorruption, err)
+	}
+
+	// Ensure an interrupt aborts the verification.
+	cancel()
+	err = VerifyExistsAddrIndex(ctx, db, chain)
+	if !errors.Is(err, ErrInterruptRequested) {
+		t.Fatalf("expected error %v, got %v", ErrInterruptRequested, err)
+	}
+}
@@ -39,6 +39,11 @@ var (
 	// syncUpdateInterval is the time between periodically checking indexes
 	// and notifying their synchronization subscribers if synced.
 	syncUpdateInterval = time.Millisecond * 500
+
+	// catchUpRetryInterval is the time to wait before retrying to catch up
+	// indexes in the background when the next block to index is not
+	// available due to a chain reorganization in progress.
+	catchUpRetryInterval = time.Millisecond * 100
 )
 
 // IndexNtfn represents an index notification detailing a block connection
@@ -123,6 +128,12 @@ func (s *IndexSubscription) stop() error {
 // IndexSubscriber subscribes clients for index updates.
 type IndexSubscriber struct {
 	subscribers uint32 // update atomically.
+	catchingUp  uint32 // update atomically.
+
+	// catchUpQueryer is the chain queryer used to catch up the subscribed
+	// indexes in the background when the subscriber is run.  It is nil when
+	// no background catch up was requested.
+	catchUpQueryer ChainQueryer
 
 	c             chan IndexNtfn
 	subscriptions map[string]*IndexSubscription
@@ -216,8 +227,9 @@ func (s *IndexSubscriber) findLowestIndexTipHeight(queryer ChainQueryer) (int64,
 
 		// Ensure the index tip is on the main chain.
 		if !queryer.MainChainHasBlock(tipHash) {
-			return 0, bestHeight, fmt.Errorf("%s: index tip (%s) is not on the "+
-				"main chain", sub.idx.Name(), tipHash)
+			msg := fmt.Sprintf("%s: index tip (%s) is not on the main chain",
+				sub.idx.Name(), tipHash)
+			return 0, bestHeight, indexerError(ErrBlockNotOnMainChain, msg)
 		}
 
 		if tipHeight < lowestHeight {
@@ -243,6 +255,50 @@ func (s *IndexSubscriber) findLowestIndexTipHeight(queryer ChainQueryer) (int64,
 	return lowestHeight, bestHeight, nil
 }
 
+// catchUpNtfn returns a connect notification for the main chain block at the
+// provided height.  The parent of the block is only fetched when the provided
+// cached parent, which may be nil, is not the parent of the block.
+func catchUpNtfn(queryer ChainQueryer, height int64, cachedParent *dcrutil.Block) (*IndexNtfn, error) {
+	hash, err := queryer.BlockHashByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the next tip hash is on the main chain.
+	if !queryer.MainChainHasBlock(hash) {
+		msg := fmt.Sprintf("the next block being synced to (%s) "+
+			"at height %d is not on the main chain", hash, height)
+		return nil, indexerError(ErrBlockNotOnMainChain, msg)
+	}
+
+	child, err := queryer.BlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := cachedParent
+	prevHash := &child.MsgBlock().Header.PrevBlock
+	if parent == nil || *parent.Hash() != *prevHash {
+		parent, err = queryer.BlockByHash(prevHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Construct the index notification.
+	isTreasuryEnabled, err := queryer.IsTreasuryAgendaActive(parent.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexNtfn{
+		NtfnType:          ConnectNtfn,
+		Block:             child,
+		Parent:            parent,
+		IsTreasuryEnabled: isTreasuryEnabled,
+	}, nil
+}
+
 // CatchUp syncs all subscribed indexes to the main chain by connecting blocks
 // from after the lowest index tip to the current main chain tip.
 //
@@ -272,67 +328,135 @@ func (s *IndexSubscriber) CatchUp(ctx context.Context, db database.DB, queryer C
 			return indexerError(ErrInterruptRequested, interruptMsg)
 		}
 
-		hash, err := queryer.BlockHashByHeight(height)
+		ntfn, err := catchUpNtfn(queryer, height, cachedParent)
 		if err != nil {
 			return err
 		}
 
-		// Ensure the next tip hash is on the main chain.
-		if !queryer.MainChainHasBlock(hash) {
-			msg := fmt.Sprintf("the next block being synced to (%s) "+
-				"at height %d is not on the main chain", hash, height)
-			return indexerError(ErrBlockNotOnMainChain, msg)
-		}
-
-		var parent *dcrutil.Block
-		if cachedParent == nil && height > 0 {
-			parentHash, err := queryer.BlockHashByHeight(height - 1)
-			if err != nil {
-				return err
-			}
-			parent, err = queryer.BlockByHash(parentHash)
+		// Relay the index update to subscribed indexes.
+		for _, sub := range s.subscriptions {
+			err := updateIndex(ctx, sub.idx, ntfn)
 			if err != nil {
+				s.cancel()
 				return err
 			}
-		} else {
-			parent = cachedParent
 		}
 
-		child, err := queryer.BlockByHash(hash)
+		cachedParent = ntfn.Block
+
+		progressLogger.LogBlockHeight(ntfn.Block.MsgBlock(),
+			ntfn.Parent.MsgBlock())
+	}
+
+	log.Infof("Caught up to height %d", bestHeight)
+
+	return nil
+}
+
+// CatchUpInBackground arranges for all subscribed indexes to be synced to the
+// main chain in the background once the subscriber is run instead of blocking
+// the caller until they are synced as CatchUp does.
+//
+// Notifications for blocks after the tip of an index that is still being
+// caught up are ignored while catching up since the catch up process indexes
+// those blocks once it reaches them.  Callers that depend on an index must
+// therefore make use of its tip and WaitForSync to determine when the index
+// is synced.
+//
+// This should be called after all indexes have subscribed for updates and
+// before the subscriber is run.
+func (s *IndexSubscriber) CatchUpInBackground(queryer ChainQueryer) {
+	s.catchUpQueryer = queryer
+	atomic.StoreUint32(&s.catchingUp, 1)
+}
+
+// isCatchingUp returns whether or not the subscribed indexes are being caught
+// up in the background.
+func (s *IndexSubscriber) isCatchingUp() bool {
+	return atomic.LoadUint32(&s.catchingUp) == 1
+}
+
+// handleCatchUp syncs all subscribed indexes to the main chain in the
+// background by repeatedly connecting the block after the lowest index tip
+// until all indexes are synced to the current main chain tip.
+//
+// The subscriber mutex is only held while indexing each block so that
+// notifications for newly connected and disconnected blocks are processed
+// concurrently with the catch up process.
+//
+// This should be run as a goroutine.
+func (s *IndexSubscriber) handleCatchUp(ctx context.Context, queryer ChainQueryer) {
+	defer s.wg.Done()
+
+	// Log the per-index details of the blocks that need to be indexed.
+	s.mtx.Lock()
+	bestHeight, _ := queryer.Best()
+	for _, sub := range s.subscriptions {
+		tipHeight, _, err := sub.idx.Tip()
 		if err != nil {
-			return err
+			s.mtx.Unlock()
+			log.Errorf("%s: unable to fetch index tip: %v", sub.idx.Name(),
+				err)
+			s.cancel()
+			return
+		}
+		if tipHeight < bestHeight {
+			log.Infof("Catching up %s from height %d to %d in the "+
+				"background", sub.idx.Name(), tipHeight, bestHeight)
 		}
+	}
+	s.mtx.Unlock()
 
-		// Construct and send the index notification.
-		isTreasuryEnabled, err := queryer.IsTreasuryAgendaActive(parent.Hash())
-		if err != nil {
-			return err
+	progressLogger := progresslog.NewBlockProgressLogger("Indexed", log)
+	var cachedParent *dcrutil.Block
+	for {
+		if interruptRequested(ctx) {
+			return
 		}
 
-		ntfn := &IndexNtfn{
-			NtfnType:          ConnectNtfn,
-			Block:             child,
-			Parent:            parent,
-			IsTreasuryEnabled: isTreasuryEnabled,
+		s.mtx.Lock()
+		lowestHeight, bestHeight, err := s.findLowestIndexTipHeight(queryer)
+		if err == nil && lowestHeight >= bestHeight {
+			atomic.StoreUint32(&s.catchingUp, 0)
+			s.mtx.Unlock()
+			log.Infof("Caught up to height %d", bestHeight)
+			return
+		}
+		var ntfn *IndexNtfn
+		if err == nil {
+			ntfn, err = catchUpNtfn(queryer, lowestHeight+1, cachedParent)
 		}
+		if err != nil {
+			s.mtx.Unlock()
 
-		// Relay the index update to subscribed indexes.
+			// The chain is expected to change while catching up, so retry
+			// once the notifications for the reorganization that caused
+			// the next block to no longer be available are processed.
+			log.Debugf("Unable to fetch next block to index: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(catchUpRetryInterval):
+			}
+			continue
+		}
 		for _, sub := range s.subscriptions {
-			err := updateIndex(ctx, sub.idx, ntfn)
+			err = updateIndex(ctx, sub.idx, ntfn)
 			if err != nil {
-				s.cancel()
-				return err
+				break
 			}
 		}
+		s.mtx.Unlock()
+		if err != nil {
+			log.Error(err)
+			s.cancel()
+			return
+		}
 
-		cachedParent = child
-
-		progressLogger.LogBlockHeight(child.MsgBlock(), parent.MsgBlock())
+		cachedParent = ntfn.Block
+		progressLogger.LogBlockHeight(ntfn.Block.MsgBlock(),
+			ntfn.Parent.MsgBlock())
 	}
-
-	log.Infof("Caught up to height %d", bestHeight)
-
-	return nil
 }
 
 // handleSyncSubscribers updates index sync subscribers when a subscribed
@@ -406,13 +530,19 @@ func (s *IndexSubscriber) handleIndexUpdates(ctx context.Context) {
 	}
 }
 
-// Run relays index notifications to subscribed indexes.
+// Run relays index notifications to subscribed indexes.  It also catches up
+// the subscribed indexes in the background when requested via
+// CatchUpInBackground.
 //
 // This should be run as a goroutine.
 func (s *IndexSubscriber) Run(ctx context.Context) {
 	s.wg.Add(2)
 	go s.handleIndexUpdates(ctx)
 	go s.handleSyncSubscribers(ctx)
+	if s.catchUpQueryer != nil {
+		s.wg.Add(1)
+		go s.handleCatchUp(ctx, s.catchUpQueryer)
+	}
 	s.wg.Wait()
 
 	log.Infof("Index subscriber shutting down")
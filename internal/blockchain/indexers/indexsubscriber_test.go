@@ -191,3 +191,97 @@ func TestIndexSubscriberAsync(t *testing.T) {
 			existsAddrIdxTipHash)
 	}
 }
+
+// TestIndexSubscriberCatchUpInBackground ensures the index subscriber catches
+// up its indexes in the background while processing notifications.
+func TestIndexSubscriberCatchUpInBackground(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain.
+	addBlock(t, chain, &g, "bk1")
+	addBlock(t, chain, &g, "bk2")
+	bk3 := addBlock(t, chain, &g, "bk3")
+
+	ctx, pCancel := context.WithCancel(context.Background())
+	defer pCancel()
+
+	subber := NewIndexSubscriber(ctx)
+
+	txIdx, err := NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subber.CatchUpInBackground(chain)
+
+	// Ensure notifications for blocks after the index tip are ignored while
+	// catching up instead of being treated as missed notifications.
+	ntfn := &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk3,
+	}
+	if err := updateIndex(ctx, txIdx, ntfn); err != nil {
+		t.Fatalf("unexpected error for ignored notification: %v", err)
+	}
+	tipHeight, _, err := txIdx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != 0 {
+		t.Fatalf("expected tip height to be 0, got %d", tipHeight)
+	}
+
+	go subber.Run(ctx)
+
+	// Extend the chain while the index is being caught up.
+	bk4 := addBlock(t, chain, &g, "bk4")
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: ConnectNtfn,
+		Block:    bk4,
+		Parent:   bk3,
+	})
+
+	// Ensure the index is caught up to the current chain tip (bk4).
+	deadline := time.After(time.Second * 5)
+	for subber.isCatchingUp() {
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for the index to catch up")
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+	tipHeight, tipHash, err := txIdx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk4.Height() || *tipHash != *bk4.Hash() {
+		t.Fatalf("expected tip %s (height %d), got %s (height %d)",
+			bk4.Hash(), bk4.Height(), tipHash, tipHeight)
+	}
+
+	// Ensure the index remains in sync with the main chain once caught up.
+	bk5 := addBlock(t, chain, &g, "bk5")
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: ConnectNtfn,
+		Block:    bk5,
+		Parent:   bk4,
+	})
+	tipHeight, tipHash, err = txIdx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk5.Height() || *tipHash != *bk5.Hash() {
+		t.Fatalf("expected tip %s (height %d), got %s (height %d)",
+			bk5.Hash(), bk5.Height(), tipHash, tipHeight)
+	}
+}
@@ -0,0 +1,514 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// spentIndexName is the human-readable name for the index.
+	spentIndexName = "spent output index"
+
+	// spentIndexVersion is the current version of the spent output index.
+	spentIndexVersion = 1
+
+	// spentKeySize is the size of a spent output index key.  It consists of
+	// 32 bytes transaction hash + 4 bytes output index.
+	spentKeySize = chainhash.HashSize + 4
+
+	// spentEntrySize is the size of a spent output index entry.  It consists
+	// of 32 bytes spending transaction hash + 4 bytes input index + 1 byte
+	// tx tree + 4 bytes block height.
+	spentEntrySize = chainhash.HashSize + 4 + 1 + 4
+)
+
+var (
+	// spentIndexKey is the key of the spent output index and the db bucket
+	// used to house it.
+	spentIndexKey = []byte("spentoutidx")
+)
+
+// -----------------------------------------------------------------------------
+// The spent output index consists of an entry for every transaction output
+// spent in the main chain that maps the output to the transaction input that
+// spends it along with the height of the block that contains the spending
+// transaction.
+//
+// Since the outputs spent by a block are identified by the inputs of its
+// transactions, the entries for a block can be removed when it is disconnected
+// without requiring any additional information.
+//
+// The serialized format for the keys and values in the spent output index
+// bucket is:
+//
+//   <txhash><output index> = <spending txhash><input index><tree><height>
+//
+//   Field              Type              Size
+//   txhash             chainhash.Hash    32 bytes
+//   output index       uint32            4 bytes
+//   spending txhash    chainhash.Hash    32 bytes
+//   input index        uint32            4 bytes
+//   tree               byte              1 byte
+//   height             uint32            4 bytes
+//   -----
+//   Total: 77 bytes
+// -----------------------------------------------------------------------------
+
+// SpentIndexEntry houses information about the transaction input that spends
+// a given transaction output.
+type SpentIndexEntry struct {
+	// TxHash is the hash of the transaction that spends the output.
+	TxHash chainhash.Hash
+
+	// InputIndex is the index of the input of the spending transaction that
+	// spends the output.
+	InputIndex uint32
+
+	// TxTree is the tree of the block that contains the spending
+	// transaction.
+	TxTree int8
+
+	// BlockHeight is the height of the block that contains the spending
+	// transaction.
+	BlockHeight int64
+}
+
+// serializeSpentKey returns the key of the spent output index entry for the
+// provided outpoint.
+func serializeSpentKey(outpoint *wire.OutPoint) []byte {
+	key := make([]byte, spentKeySize)
+	copy(key, outpoint.Hash[:])
+	byteOrder.PutUint32(key[chainhash.HashSize:], outpoint.Index)
+	return key
+}
+
+// serializeSpentEntry returns the serialized spent output index entry for the
+// provided spending transaction input details.
+func serializeSpentEntry(txHash *chainhash.Hash, inputIndex uint32, tree int8, height uint32) []byte {
+	entry := make([]byte, spentEntrySize)
+	copy(entry, txHash[:])
+	offset := chainhash.HashSize
+	byteOrder.PutUint32(entry[offset:], inputIndex)
+	offset += 4
+	entry[offset] = byte(tree)
+	offset++
+	byteOrder.PutUint32(entry[offset:], height)
+	return entry
+}
+
+// dbFetchSpentIndexEntry uses an existing database transaction to fetch the
+// details of the transaction input that spends the provided outpoint from the
+// spent output index.  When there is no entry for the provided outpoint, nil
+// will be returned for both the entry and the error.
+func dbFetchSpentIndexEntry(dbTx database.Tx, outpoint *wire.OutPoint) (*SpentIndexEntry, error) {
+	bucket := dbTx.Metadata().Bucket(spentIndexKey)
+	serialized := bucket.Get(serializeSpentKey(outpoint))
+	if len(serialized) == 0 {
+		return nil, nil
+	}
+
+	// Ensure the serialized data has enough bytes to properly deserialize.
+	if len(serialized) < spentEntrySize {
+		str := fmt.Sprintf("corrupt spent output index entry for %s",
+			outpoint)
+		return nil, makeDbErr(database.ErrCorruption, str)
+	}
+
+	offset := chainhash.HashSize
+	entry := SpentIndexEntry{
+		InputIndex:  byteOrder.Uint32(serialized[offset:]),
+		TxTree:      int8(serialized[offset+4]),
+		BlockHeight: int64(byteOrder.Uint32(serialized[offset+5:])),
+	}
+	copy(entry.TxHash[:], serialized)
+	return &entry, nil
+}
+
+// spendingTxIn describes a transaction input of a block that spends an output.
+type spendingTxIn struct {
+	tx         *dcrutil.Tx
+	tree       int8
+	inputIndex uint32
+	prevOut    *wire.OutPoint
+}
+
+// blockSpendingTxIns returns all transaction inputs of the provided block that
+// spend outputs.  This excludes the inputs of the coinbase, treasurybase, and
+// treasury spends as well as the stakebase inputs of votes since they do not
+// spend any outputs.
+func blockSpendingTxIns(block *dcrutil.Block, isTreasuryEnabled bool) []spendingTxIn {
+	var txIns []spendingTxIn
+	for i, tx := range block.STransactions() {
+		msgTx := tx.MsgTx()
+		if isTreasuryEnabled && (i == 0 || stake.IsTSpend(msgTx)) {
+			continue
+		}
+		isVote := stake.IsSSGen(msgTx)
+		for txInIdx, txIn := range msgTx.TxIn {
+			if txInIdx == 0 && isVote {
+				continue
+			}
+			txIns = append(txIns, spendingTxIn{
+				tx:         tx,
+				tree:       wire.TxTreeStake,
+				inputIndex: uint32(txInIdx),
+				prevOut:    &txIn.PreviousOutPoint,
+			})
+		}
+	}
+	txns := block.Transactions()
+	if len(txns) > 1 {
+		for _, tx := range txns[1:] {
+			for txInIdx, txIn := range tx.MsgTx().TxIn {
+				txIns = append(txIns, spendingTxIn{
+					tx:         tx,
+					tree:       wire.TxTreeRegular,
+					inputIndex: uint32(txInIdx),
+					prevOut:    &txIn.PreviousOutPoint,
+				})
+			}
+		}
+	}
+	return txIns
+}
+
+// SpentIndex implements a spent output index.  That is to say, it supports
+// querying the transaction input that spends a given transaction output in the
+// main chain.
+type SpentIndex struct {
+	// These fields provide access to the chain queryer and the
+	// database of the index.
+	db    database.DB
+	chain ChainQueryer
+
+	// These fields track the notification subscription for the index
+	// and its subscribers.
+	sub         *IndexSubscription
+	subscribers map[chan bool]struct{}
+
+	mtx    sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Ensure the SpentIndex type implements the Indexer interface.
+var _ Indexer = (*SpentIndex)(nil)
+
+// Ensure the SpentIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*SpentIndex)(nil)
+
+// NewSpentIndex returns a new instance of an indexer that is used to create a
+// mapping of all transaction outputs spent in the blockchain to the respective
+// transaction inputs that spend them and the height of the blocks that
+// contain the spending transactions.
+func NewSpentIndex(subscriber *IndexSubscriber, db database.DB, chain ChainQueryer) (*SpentIndex, error) {
+	idx := &SpentIndex{
+		db:          db,
+		chain:       chain,
+		subscribers: make(map[chan bool]struct{}),
+		cancel:      subscriber.cancel,
+	}
+
+	// The spent output index is an optional index. It has no prequisite and
+	// is updated asynchronously.
+	sub, err := subscriber.Subscribe(idx, noPrereqs)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.sub = sub
+
+	err = idx.Init(subscriber.ctx, chain.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Init initializes the spent output index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Init(ctx context.Context, chainParams *chaincfg.Params) error {
+	if interruptRequested(ctx) {
+		return indexerError(ErrInterruptRequested, interruptMsg)
+	}
+
+	// Finish any drops that were previously interrupted.
+	if err := finishDrop(ctx, idx); err != nil {
+		return err
+	}
+
+	// Create the initial state for the index as needed.
+	if err := createIndex(idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Upgrade the index as needed.
+	if err := upgradeIndex(ctx, idx, &chainParams.GenesisHash); err != nil {
+		return err
+	}
+
+	// Recover the spent output index and its dependents to the main chain if
+	// needed.
+	return recoverIndex(ctx, idx)
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Key() []byte {
+	return spentIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Name() string {
+	return spentIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Version() uint32 {
+	return spentIndexVersion
+}
+
+// DB returns the database of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) DB() database.DB {
+	return idx.db
+}
+
+// Queryer returns the chain queryer.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Queryer() ChainQueryer {
+	return idx.chain
+}
+
+// Tip returns the current tip of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Tip() (int64, *chainhash.Hash, error) {
+	return tip(idx.db, idx.Key())
+}
+
+// IndexSubscription returns the subscription for index updates.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) IndexSubscription() *IndexSubscription {
+	return idx.sub
+}
+
+// Subscribers returns all client channels waiting for the next index update.
+//
+// This is part of the Indexer interface.
+// Deprecated: This will be removed in the next major version bump.
+func (idx *SpentIndex) Subscribers() map[chan bool]struct{} {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+	return idx.subscribers
+}
+
+// NotifySyncSubscribers signals subscribers of an index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) NotifySyncSubscribers() {
+	idx.mtx.Lock()
+	notifySyncSubscribers(idx.subscribers)
+	idx.mtx.Unlock()
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) WaitForSync() chan bool {
+	c := make(chan bool)
+
+	idx.mtx.Lock()
+	idx.subscribers[c] = struct{}{}
+	idx.mtx.Unlock()
+
+	return c
+}
+
+// Create is invoked when the index is created for the first time.  It creates
+// the bucket for the spent output index.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(spentIndexKey)
+	return err
+}
+
+// connectBlock adds an entry for every transaction output spent by the
+// transactions in the passed block.
+func (idx *SpentIndex) connectBlock(dbTx database.Tx, block *dcrutil.Block, isTreasuryEnabled bool) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because even though the
+	// disapproved transactions no longer apply spend semantics, they still
+	// exist within the block.  The outputs they spent are nearly always spent
+	// again by the same transactions when they are mined into a later block,
+	// in which case the entries are updated to refer to the later block.
+
+	height := uint32(block.Height())
+	bucket := dbTx.Metadata().Bucket(spentIndexKey)
+	for _, txIn := range blockSpendingTxIns(block, isTreasuryEnabled) {
+		entry := serializeSpentEntry(txIn.tx.Hash(), txIn.inputIndex,
+			txIn.tree, height)
+		err := bucket.Put(serializeSpentKey(txIn.prevOut), entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), block.Hash(), int32(block.Height()))
+}
+
+// disconnectBlock removes the entries for every transaction output spent by
+// the transactions in the passed block.
+func (idx *SpentIndex) disconnectBlock(dbTx database.Tx, block *dcrutil.Block, isTreasuryEnabled bool) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored when disconnecting blocks because it is also
+	// ignored when connecting the block.
+
+	bucket := dbTx.Metadata().Bucket(spentIndexKey)
+	for _, txIn := range blockSpendingTxIns(block, isTreasuryEnabled) {
+		if err := bucket.Delete(serializeSpentKey(txIn.prevOut)); err != nil {
+			return err
+		}
+	}
+
+	// Update the current index tip.
+	return dbPutIndexerTip(dbTx, idx.Key(), &block.MsgBlock().Header.PrevBlock,
+		int32(block.Height()-1))
+}
+
+// SpentInfo returns details about the transaction input that spends the
+// provided outpoint in the main chain from the spent output index.  When there
+// is no entry for the provided outpoint, which is the case when it is unspent
+// or does not exist, nil will be returned for both the entry and the error.
+//
+// This function is safe for concurrent access.
+func (idx *SpentIndex) SpentInfo(outpoint *wire.OutPoint) (*SpentIndexEntry, error) {
+	var entry *SpentIndexEntry
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		entry, err = dbFetchSpentIndexEntry(dbTx, outpoint)
+		return err
+	})
+	return entry, err
+}
+
+// DropSpentIndex drops the spent output index from the provided database if it
+// exists.
+func DropSpentIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, spentIndexKey, spentIndexName)
+}
+
+// DropIndex drops the spent output index from the provided database if it
+// exists.
+func (*SpentIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropSpentIndex(ctx, db)
+}
+
+// verifySpentIndexEntries ensures the spent output index has an entry for
+// every transaction output spent by the transactions in the provided block
+// that refers to the input that spends it.  Outputs that are spent again by a
+// later main chain block, which is only possible when the regular tree of the
+// provided block is disapproved, are only required to refer to that block.
+func verifySpentIndexEntries(dbTx database.Tx, chain ChainQueryer, block *dcrutil.Block) error {
+	isTreasuryEnabled, err := chain.IsTreasuryAgendaActive(
+		&block.MsgBlock().Header.PrevBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, txIn := range blockSpendingTxIns(block, isTreasuryEnabled) {
+		entry, err := dbFetchSpentIndexEntry(dbTx, txIn.prevOut)
+		if err != nil {
+			msg := fmt.Sprintf("%s: %v", spentIndexName, err)
+			return indexerError(ErrIndexCorruption, msg)
+		}
+		if entry == nil {
+			msg := fmt.Sprintf("%s: missing entry for output %s spent in "+
+				"block %s (height %d)", spentIndexName, txIn.prevOut,
+				block.Hash(), block.Height())
+			return indexerError(ErrIndexCorruption, msg)
+		}
+		if entry.BlockHeight > block.Height() {
+			continue
+		}
+		if entry.BlockHeight != block.Height() ||
+			entry.TxHash != *txIn.tx.Hash() ||
+			entry.InputIndex != txIn.inputIndex || entry.TxTree != txIn.tree {
+
+			msg := fmt.Sprintf("%s: entry for output %s does not refer to "+
+				"input %d of transaction %s in block %s (height %d)",
+				spentIndexName, txIn.prevOut, txIn.inputIndex, txIn.tx.Hash(),
+				block.Hash(), block.Height())
+			return indexerError(ErrIndexCorruption, msg)
+		}
+	}
+	return nil
+}
+
+// VerifySpentIndex ensures the spent output index in the provided database, if
+// it exists, has a correct entry for every transaction output spent in the main
+// chain blocks up to the index tip.  An error with the kind ErrIndexCorruption
+// that describes the first divergence is returned when it does not.
+//
+// A damaged index can be rebuilt by dropping it with DropSpentIndex and loading
+// it again.
+func VerifySpentIndex(ctx context.Context, db database.DB, chain ChainQueryer) error {
+	return verifyIndex(ctx, db, chain, spentIndexKey, spentIndexName,
+		func(dbTx database.Tx, block *dcrutil.Block) error {
+			return verifySpentIndexEntries(dbTx, chain, block)
+		})
+}
+
+// ProcessNotification indexes the provided notification based on its
+// notification type.
+//
+// This is part of the Indexer interface.
+func (idx *SpentIndex) ProcessNotification(dbTx database.Tx, ntfn *IndexNtfn) error {
+	switch ntfn.NtfnType {
+	case ConnectNtfn:
+		err := idx.connectBlock(dbTx, ntfn.Block, ntfn.IsTreasuryEnabled)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to connect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrConnectBlock, msg)
+		}
+
+	case DisconnectNtfn:
+		err := idx.disconnectBlock(dbTx, ntfn.Block, ntfn.IsTreasuryEnabled)
+		if err != nil {
+			msg := fmt.Sprintf("%s: unable to disconnect block: %v",
+				idx.Name(), err)
+			return indexerError(ErrDisconnectBlock, msg)
+		}
+
+	default:
+		msg := fmt.Sprintf("%s: unknown notification type received: %d",
+			idx.Name(), ntfn.NtfnType)
+		return indexerError(ErrInvalidNotificationType, msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/v5/chaingen"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// TestSpentIndex ensures the spent output index maps spent outputs to the
+// inputs that spend them and removes the entries of blocks that are
+// disconnected.
+func TestSpentIndex(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain followed by a block that spends a
+	// coinbase output of the second block.
+	addBlock(t, chain, &g, "bk1")
+	addBlock(t, chain, &g, "bk2")
+	bk3 := addBlock(t, chain, &g, "bk3")
+	outs := g.OldestCoinbaseOuts()
+	bk4 := dcrutil.NewBlock(g.NextBlock("bk4", &outs[0], nil))
+	g.SaveTipCoinbaseOuts()
+	if err := chain.AddBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+
+	// Initialize the spent output index.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	idx, err := NewSpentIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the index got synced to bk4 on initialization.
+	tipHeight, tipHash, err := idx.Tip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tipHeight != bk4.Height() || *tipHash != *bk4.Hash() {
+		t.Fatalf("expected tip %s (height %d), got %s (height %d)",
+			bk4.Hash(), bk4.Height(), tipHash, tipHeight)
+	}
+
+	// Ensure the output spent by bk4 refers to the spending input.
+	spendTx := bk4.Transactions()[1]
+	spentOut := &spendTx.MsgTx().TxIn[0].PreviousOutPoint
+	entry, err := idx.SpentInfo(spentOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SpentIndexEntry{
+		TxHash:      *spendTx.Hash(),
+		InputIndex:  0,
+		TxTree:      0,
+		BlockHeight: bk4.Height(),
+	}
+	if entry == nil || *entry != want {
+		t.Fatalf("mismatched spent entry: got %+v, want %+v", entry, want)
+	}
+
+	// Ensure unspent outputs do not have an entry.
+	unspentOut := outs[1].PrevOut()
+	entry, err = idx.SpentInfo(&unspentOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Fatalf("unexpected entry for unspent output: %+v", entry)
+	}
+
+	// Ensure the index verifies against the chain.
+	if err := VerifySpentIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+
+	// Ensure verification detects an entry that refers to the wrong input.
+	err = db.Update(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(spentIndexKey)
+		return bucket.Put(serializeSpentKey(spentOut),
+			serializeSpentEntry(spendTx.Hash(), 1, 0, uint32(bk4.Height())))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifySpentIndex(ctx, db, chain)
+	if !errors.Is(err, ErrIndexCorruption) {
+		t.Fatalf("expected corruption error, got %v", err)
+	}
+
+	// Ensure the entries of bk4 are removed when it is disconnected.
+	if err := chain.RemoveBlock(bk4); err != nil {
+		t.Fatal(err)
+	}
+	g.SetTip("bk3")
+	notifyAndWait(t, subber, &IndexNtfn{
+		NtfnType: DisconnectNtfn,
+		Block:    bk4,
+		Parent:   bk3,
+	})
+
+	entry, err = idx.SpentInfo(spentOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry != nil {
+		t.Fatalf("unexpected entry after disconnect: %+v", entry)
+	}
+}
@@ -695,6 +695,77 @@ func (*TxIndex) DropIndex(ctx context.Context, db database.DB) error {
 	return DropTxIndex(ctx, db)
 }
 
+// verifyTxIndexEntries ensures the transaction index has an entry for every
+// transaction in the provided block that refers to its location in the block.
+// Transactions that are also included in a later main chain block are only
+// required to refer to that block since only the most recent one is indexed.
+func verifyTxIndexEntries(dbTx database.Tx, chain ChainQueryer, block *dcrutil.Block) error {
+	txLocs, stakeTxLocs, err := block.TxLoc()
+	if err != nil {
+		return err
+	}
+
+	verifyEntries := func(txns []*dcrutil.Tx, txLocs []wire.TxLoc) error {
+		for i, tx := range txns {
+			entry, err := dbFetchTxIndexEntry(dbTx, tx.Hash())
+			if err != nil {
+				if errors.Is(err, database.ErrCorruption) {
+					msg := fmt.Sprintf("%s: %v", txIndexName, err)
+					return indexerError(ErrIndexCorruption, msg)
+				}
+				return err
+			}
+			if entry == nil {
+				msg := fmt.Sprintf("%s: missing entry for transaction %s "+
+					"in block %s (height %d)", txIndexName, tx.Hash(),
+					block.Hash(), block.Height())
+				return indexerError(ErrIndexCorruption, msg)
+			}
+
+			if *entry.BlockRegion.Hash != *block.Hash() {
+				height, err := chain.BlockHeightByHash(entry.BlockRegion.Hash)
+				if err != nil || height <= block.Height() {
+					msg := fmt.Sprintf("%s: entry for transaction %s in "+
+						"block %s (height %d) refers to block %s",
+						txIndexName, tx.Hash(), block.Hash(), block.Height(),
+						entry.BlockRegion.Hash)
+					return indexerError(ErrIndexCorruption, msg)
+				}
+				continue
+			}
+			if entry.BlockIndex != uint32(i) ||
+				entry.BlockRegion.Offset != uint32(txLocs[i].TxStart) ||
+				entry.BlockRegion.Len != uint32(txLocs[i].TxLen) {
+
+				msg := fmt.Sprintf("%s: entry for transaction %s does not "+
+					"match its location in block %s (height %d)",
+					txIndexName, tx.Hash(), block.Hash(), block.Height())
+				return indexerError(ErrIndexCorruption, msg)
+			}
+		}
+		return nil
+	}
+
+	if err := verifyEntries(block.Transactions(), txLocs); err != nil {
+		return err
+	}
+	return verifyEntries(block.STransactions(), stakeTxLocs)
+}
+
+// VerifyTxIndex ensures the transaction index in the provided database, if it
+// exists, has a correct entry for every transaction in the main chain blocks
+// up to the index tip.  An error with the kind ErrIndexCorruption that
+// describes the first divergence is returned when it does not.
+//
+// A damaged index can be rebuilt by dropping it with DropTxIndex and loading
+// it again.
+func VerifyTxIndex(ctx context.Context, db database.DB, chain ChainQueryer) error {
+	return verifyIndex(ctx, db, chain, txIndexKey, txIndexName,
+		func(dbTx database.Tx, block *dcrutil.Block) error {
+			return verifyTxIndexEntries(dbTx, chain, block)
+		})
+}
+
 // ProcessNotification indexes the provided notification based on its
 // notification type.
 //
@@ -6,6 +6,7 @@ package indexers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -252,6 +253,64 @@ func (tc *testChain) BlockHeaderByHash(hash *chainhash.Hash) (wire.BlockHeader,
 	return blk.MsgBlock().Header, nil
 }
 
+// testScriptSource provides a mock source of previous transaction scripts
+// as defined by the indexer.PrevScripter interface.
+type testScriptSource map[wire.OutPoint]*wire.TxOut
+
+// PrevScript returns the script and script version associated with the
+// provided previous outpoint.
+func (s testScriptSource) PrevScript(prevOut *wire.OutPoint) (uint16, []byte, bool) {
+	txOut, ok := s[*prevOut]
+	if !ok {
+		return 0, nil, false
+	}
+	return txOut.Version, txOut.PkScript, true
+}
+
+// PrevScripts returns a source of the previous transaction scripts spent by
+// the provided block.  The spent outputs are looked up from all blocks known
+// to the chain.
+func (tc *testChain) PrevScripts(blk *dcrutil.Block, _ bool) (PrevScripter, error) {
+	tc.mtx.Lock()
+	defer tc.mtx.Unlock()
+
+	if _, ok := tc.keyedByHash[*blk.Hash()]; !ok {
+		return nil, fmt.Errorf("block %s is not in the main chain", blk.Hash())
+	}
+
+	txns := make(map[chainhash.Hash]*wire.MsgTx)
+	addTxns := func(blocks map[chainhash.Hash]*dcrutil.Block) {
+		for _, b := range blocks {
+			for _, tx := range b.MsgBlock().Transactions {
+				txns[tx.TxHash()] = tx
+			}
+			for _, tx := range b.MsgBlock().STransactions {
+				txns[tx.TxHash()] = tx
+			}
+		}
+	}
+	addTxns(tc.keyedByHash)
+	addTxns(tc.orphans)
+
+	source := make(testScriptSource)
+	addInputs := func(txns []*wire.MsgTx, prevTxns map[chainhash.Hash]*wire.MsgTx) {
+		for _, tx := range txns {
+			for _, txIn := range tx.TxIn {
+				prevOut := txIn.PreviousOutPoint
+				prevTx, ok := prevTxns[prevOut.Hash]
+				if !ok || int(prevOut.Index) >= len(prevTx.TxOut) {
+					continue
+				}
+				source[prevOut] = prevTx.TxOut[prevOut.Index]
+			}
+		}
+	}
+	addInputs(blk.MsgBlock().Transactions, txns)
+	addInputs(blk.MsgBlock().STransactions, txns)
+
+	return source, nil
+}
+
 // notifyAndWait sends the provided notification and waits for done signal
 // with a one second timeout.
 func notifyAndWait(t *testing.T, subber *IndexSubscriber, ntfn *IndexNtfn) {
@@ -586,3 +645,68 @@ func TestTxIndexAsync(t *testing.T) {
 		t.Fatalf("expected tip hash to be %s, got %s", bk4a.Hash(), tipHash)
 	}
 }
+
+// TestVerifyTxIndex ensures verifying the transaction index succeeds for a
+// consistent index and reports missing entries.
+func TestVerifyTxIndex(t *testing.T) {
+	db := setupDB(t)
+
+	chain, err := newTestChain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := chaingen.MakeGenerator(chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Add three blocks to the chain.
+	addBlock(t, chain, &g, "bk1")
+	bk2 := addBlock(t, chain, &g, "bk2")
+	addBlock(t, chain, &g, "bk3")
+
+	// Ensure verifying the index succeeds when it does not exist.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := VerifyTxIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected error verifying missing index: %v", err)
+	}
+
+	// Initialize the tx index.
+	subber := NewIndexSubscriber(ctx)
+	go subber.Run(ctx)
+
+	_, err = NewTxIndex(subber, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = subber.CatchUp(ctx, db, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyTxIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected error verifying consistent index: %v", err)
+	}
+
+	// Remove the entry for the coinbase of bk2 and ensure the divergence is
+	// detected.
+	err = db.Update(func(dbTx database.Tx) error {
+		return dbRemoveTxIndexEntry(dbTx, bk2.Transactions()[0].Hash())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyTxIndex(ctx, db, chain)
+	if !errors.Is(err, ErrIndexCorruption) {
+		t.Fatalf("expected error %v, got %v", ErrIndexCorruption, err)
+	}
+
+	// Ensure the damaged index passes verification once it is dropped.
+	if err := DropTxIndex(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTxIndex(ctx, db, chain); err != nil {
+		t.Fatalf("unexpected error verifying dropped index: %v", err)
+	}
+}
@@ -153,12 +153,13 @@ type ReorganizationNtfnsData struct {
 }
 
 // TicketNotificationsData is the structure for data indicating information
-// about new tickets in a connected block.
+// about new and missed tickets in a connected block.
 type TicketNotificationsData struct {
 	Hash            chainhash.Hash
 	Height          int64
 	StakeDifficulty int64
 	TicketsNew      []chainhash.Hash
+	TicketsMissed   []chainhash.Hash
 }
 
 // Notification defines notification that is sent to the caller via the callback
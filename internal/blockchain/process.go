@@ -8,6 +8,7 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/decred/dcrd/blockchain/stake/v5"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -393,7 +394,7 @@ func (b *BlockChain) maybeAcceptBlocks(curTip *blockNode, nodes []*blockNode, fl
 		// happens under normal operation, especially once the chain is fully
 		// synced.
 		b.addRecentBlock(linkedBlock)
-		b.recentContextChecks.Add(n.hash)
+		b.recentContextChecks.Put(n.hash)
 
 		// Notify the caller when the block intends to extend the main chain,
 		// the chain believes it is current, and the block has passed all of the
@@ -465,6 +466,18 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 		return 0, ruleError(ErrDuplicateBlock, str)
 	}
 
+	// Track the time taken to process the block when requested.
+	if h := b.processBlockTime; h != nil {
+		start := time.Now()
+		defer func() { h.ObserveDuration(time.Since(start)) }()
+	}
+
+	// Trace the processing of the block when requested.  The span is nil, and
+	// therefore ignored, when the block is not traced.
+	span := b.tracer.Start(blockHash, "chain.process_block")
+	span.SetAttribute("block.height", block.MsgBlock().Header.Height)
+	defer span.End()
+
 	b.chainLock.Lock()
 	defer b.chainLock.Unlock()
 
@@ -473,6 +486,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	node := b.index.LookupNode(block.Hash())
 	if node != nil {
 		if err := b.checkKnownInvalidBlock(node); err != nil {
+			span.SetError(err)
 			return 0, err
 		}
 	}
@@ -483,8 +497,12 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	// significantly increase the cost to attackers.  Of particular note is that
 	// the checks include proof-of-work validation which means a significant
 	// amount of work must have been done in order to pass this check.
+	sanitySpan := span.StartChild("chain.check_block_sanity")
 	err := checkBlockSanity(block, b.timeSource, BFNone, b.chainParams)
+	sanitySpan.SetError(err)
+	sanitySpan.End()
 	if err != nil {
+		span.SetError(err)
 		// When there is a block index entry for the block, which will be the
 		// case if the header was previously seen and passed all validation,
 		// mark it as having failed validation and all of its descendants as
@@ -508,6 +526,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 		header := &block.MsgBlock().Header
 		node, err = b.maybeAcceptBlockHeader(header, checkHeaderSanity)
 		if err != nil {
+			span.SetError(err)
 			return 0, err
 		}
 	}
@@ -532,8 +551,12 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	//
 	// The returned linked block nodes are for those aforementioned blocks that
 	// are now eligible for validation.
+	acceptSpan := span.StartChild("chain.accept_block_data")
 	linkedNodes, err := b.maybeAcceptBlockData(node, block, flags)
+	acceptSpan.SetError(err)
+	acceptSpan.End()
 	if err != nil {
+		span.SetError(err)
 		return 0, err
 	}
 
@@ -541,6 +564,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	// headers will have added a new entry and the block will be marked as now
 	// having its data stored.
 	if err := b.flushBlockIndex(); err != nil {
+		span.SetError(err)
 		return 0, err
 	}
 
@@ -560,7 +584,10 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	var finalErr error
 	currentTip := b.bestChain.Tip()
 	b.addRecentBlock(block)
+	acceptBlocksSpan := span.StartChild("chain.accept_blocks")
 	acceptedNodes, err := b.maybeAcceptBlocks(currentTip, linkedNodes, flags)
+	acceptBlocksSpan.SetError(err)
+	acceptBlocksSpan.End()
 	if err != nil {
 		finalErr = err
 
@@ -603,7 +630,10 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	// Note that any errors that take place in the reorg will be attributed to
 	// the block being processed.  The calling code currently depends on this
 	// behavior, so care must be taken if this behavior is changed.
+	reorgSpan := span.StartChild("chain.reorganize")
 	reorgErr := b.reorganizeChain(target)
+	reorgSpan.SetError(reorgErr)
+	reorgSpan.End()
 	switch {
 	// The final error is just the reorg error in the case there was no error
 	// carried forward from above.
@@ -635,6 +665,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 	// above so that the information is relative to the final best chain after
 	// validation.
 	newTip := b.bestChain.Tip()
+	notifySpan := span.StartChild("chain.notify_accepted")
 	b.chainLock.Unlock()
 	for _, n := range acceptedNodes {
 		// Skip any blocks which either themselves failed validation or are
@@ -654,6 +685,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 		})
 	}
 	b.chainLock.Lock()
+	notifySpan.End()
 
 	var forkLen int64
 	if finalErr == nil {
@@ -661,6 +693,7 @@ func (b *BlockChain) ProcessBlock(block *dcrutil.Block) (int64, error) {
 			forkLen = node.height - fork.height
 		}
 	}
+	span.SetError(finalErr)
 	return forkLen, finalErr
 }
 
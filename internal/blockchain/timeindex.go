@@ -0,0 +1,116 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// medianTimeIndex provides a compact index of the past median times of the
+// blocks in a branch of the block chain keyed by their height.  It is used to
+// efficiently find the blocks in the main chain by time.
+//
+// The consensus rules require the timestamp of every block to be after the
+// past median time of its parent which means the past median times of the
+// blocks in a branch never decrease as the height increases.  This property
+// allows the index to be binary searched.
+//
+// The median times are stored as 32-bit unix timestamps since that is the
+// precision of the timestamps in the block headers.
+type medianTimeIndex struct {
+	mtx   sync.RWMutex
+	times []uint32
+}
+
+// setTip updates the index so it houses the past median times of the branch
+// that ends with the provided block node.  Only the entries for the blocks
+// after the previous tip of the index are calculated, so it is expected that
+// the index is updated whenever a block is connected to or disconnected from
+// the branch.  Passing nil as the tip will result in an empty index.
+//
+// This function is safe for concurrent access.
+func (idx *medianTimeIndex) setTip(node *blockNode) {
+	idx.mtx.Lock()
+	defer idx.mtx.Unlock()
+
+	if node == nil {
+		idx.times = nil
+		return
+	}
+
+	// Remove the entries for the blocks after the new tip.
+	numEntries := node.height + 1
+	if int64(len(idx.times)) > numEntries {
+		idx.times = idx.times[:numEntries]
+		return
+	}
+
+	// Add the entries for the blocks from the new tip back to the previous
+	// tip.
+	prevNumEntries := int64(len(idx.times))
+	if numEntries > int64(cap(idx.times)) {
+		newTimes := make([]uint32, numEntries, numEntries+approxNodesPerWeek)
+		copy(newTimes, idx.times)
+		idx.times = newTimes
+	} else {
+		idx.times = idx.times[:numEntries]
+	}
+	for n := node; n != nil && n.height >= prevNumEntries; n = n.parent {
+		idx.times[n.height] = uint32(n.CalcPastMedianTime().Unix())
+	}
+}
+
+// firstHeightAtOrAfter returns the height of the first block in the index with
+// a past median time at or after the provided time.  The number of entries in
+// the index, which is one more than the height of the tip, is returned when
+// there is no such block.
+//
+// This function MUST be called with the index mutex locked (for reads).
+func (idx *medianTimeIndex) firstHeightAtOrAfter(t time.Time) int64 {
+	unixTime := t.Unix()
+	switch {
+	case unixTime <= 0:
+		return 0
+	case unixTime > math.MaxUint32:
+		return int64(len(idx.times))
+	}
+	target := uint32(unixTime)
+	return int64(sort.Search(len(idx.times), func(i int) bool {
+		return idx.times[i] >= target
+	}))
+}
+
+// HeightAtOrAfter returns the height of the first block in the index with a
+// past median time at or after the provided time along with whether or not
+// such a block exists.
+//
+// This function is safe for concurrent access.
+func (idx *medianTimeIndex) HeightAtOrAfter(t time.Time) (int64, bool) {
+	idx.mtx.RLock()
+	height := idx.firstHeightAtOrAfter(t)
+	exists := height < int64(len(idx.times))
+	idx.mtx.RUnlock()
+	return height, exists
+}
+
+// HeightRange returns the heights of the blocks in the index with a past
+// median time in the half open range [start, end) as the half open range of
+// heights [startHeight, endHeight).  The start and end heights are the same
+// when there are no such blocks.
+//
+// This function is safe for concurrent access.
+func (idx *medianTimeIndex) HeightRange(start, end time.Time) (int64, int64) {
+	idx.mtx.RLock()
+	startHeight := idx.firstHeightAtOrAfter(start)
+	endHeight := idx.firstHeightAtOrAfter(end)
+	idx.mtx.RUnlock()
+	if endHeight < startHeight {
+		endHeight = startHeight
+	}
+	return startHeight, endHeight
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMedianTimeIndex ensures the median time index finds the expected heights
+// by time as the tip it tracks is updated, including across reorganizations.
+func TestMedianTimeIndex(t *testing.T) {
+	// Construct a synthetic block chain with a block index consisting of the
+	// following structure.
+	// 	genesis -> 1 -> 2 -> ... -> 15 -> 16  -> 17  -> ... -> 24
+	// 	                              \-> 16a -> 17a -> ... -> 29a
+	branch0Nodes := chainedFakeNodes(nil, 25)
+	branch1Nodes := chainedFakeNodes(branch0Nodes[15], 14)
+
+	// firstAtOrAfter returns the height of the first node of the branch that
+	// ends with the provided tip with a median time at or after the provided
+	// time by checking every node.
+	firstAtOrAfter := func(tip *blockNode, t time.Time) (int64, bool) {
+		var height int64 = -1
+		for n := tip; n != nil; n = n.parent {
+			if n.CalcPastMedianTime().Before(t) {
+				break
+			}
+			height = n.height
+		}
+		return height, height != -1
+	}
+
+	// assertIndex ensures the index finds the expected heights for the median
+	// times of every node of the branch that ends with the provided tip as
+	// well as for times before and after them.
+	assertIndex := func(idx *medianTimeIndex, tip *blockNode) {
+		t.Helper()
+
+		if len(idx.times) != int(tip.height+1) {
+			t.Fatalf("unexpected number of entries: got %d, want %d",
+				len(idx.times), tip.height+1)
+		}
+		for n := tip; n != nil; n = n.parent {
+			mtp := n.CalcPastMedianTime()
+			if idx.times[n.height] != uint32(mtp.Unix()) {
+				t.Fatalf("unexpected median time for height %d: got %d, "+
+					"want %d", n.height, idx.times[n.height], mtp.Unix())
+			}
+			for _, searchTime := range []time.Time{mtp, mtp.Add(time.Second)} {
+				gotHeight, gotOk := idx.HeightAtOrAfter(searchTime)
+				wantHeight, wantOk := firstAtOrAfter(tip, searchTime)
+				if gotOk != wantOk || (wantOk && gotHeight != wantHeight) {
+					t.Fatalf("unexpected height for time %d: got %d (%v), "+
+						"want %d (%v)", searchTime.Unix(), gotHeight, gotOk,
+						wantHeight, wantOk)
+				}
+			}
+		}
+
+		// Ensure times before the first block and after the tip are handled.
+		height, ok := idx.HeightAtOrAfter(time.Unix(0, 0))
+		if !ok || height != 0 {
+			t.Fatalf("unexpected height for zero time: got %d (%v)", height,
+				ok)
+		}
+		tipTime := tip.CalcPastMedianTime()
+		_, ok = idx.HeightAtOrAfter(tipTime.Add(time.Second))
+		if ok {
+			t.Fatal("unexpected height for time after tip")
+		}
+	}
+
+	var idx medianTimeIndex
+	idx.setTip(branchTip(branch0Nodes))
+	assertIndex(&idx, branchTip(branch0Nodes))
+
+	// Ensure the range of heights for the median times of a subset of the
+	// nodes is the expected one and that an empty range is returned when no
+	// blocks are in the time range.
+	start := branch0Nodes[12].CalcPastMedianTime()
+	end := branch0Nodes[20].CalcPastMedianTime()
+	startHeight, endHeight := idx.HeightRange(start, end)
+	wantStart, _ := firstAtOrAfter(branchTip(branch0Nodes), start)
+	wantEnd, _ := firstAtOrAfter(branchTip(branch0Nodes), end)
+	if startHeight != wantStart || endHeight != wantEnd {
+		t.Fatalf("unexpected height range: got [%d, %d), want [%d, %d)",
+			startHeight, endHeight, wantStart, wantEnd)
+	}
+	startHeight, endHeight = idx.HeightRange(end, start)
+	if startHeight != endHeight {
+		t.Fatalf("unexpected non-empty height range [%d, %d)", startHeight,
+			endHeight)
+	}
+
+	// Disconnect blocks back to the fork point and connect the side chain
+	// one block at a time as would happen during a reorganization.
+	for n := branchTip(branch0Nodes); n != branch0Nodes[15]; n = n.parent {
+		idx.setTip(n.parent)
+		assertIndex(&idx, n.parent)
+	}
+	for _, n := range branch1Nodes {
+		idx.setTip(n)
+		assertIndex(&idx, n)
+	}
+
+	// Ensure clearing the tip results in an empty index.
+	idx.setTip(nil)
+	if _, ok := idx.HeightAtOrAfter(time.Unix(0, 0)); ok {
+		t.Fatal("unexpected height for empty index")
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/decred/dcrd/blockchain/standalone/v2"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	"github.com/decred/dcrd/wire"
 	"github.com/syndtr/goleveldb/leveldb"
 	ldberrors "github.com/syndtr/goleveldb/leveldb/errors"
@@ -29,6 +31,11 @@ const (
 
 	// utxoDbName is the name of the UTXO database.
 	utxoDbName = "utxodb"
+
+	// utxoFlushBatchSize is the approximate number of bytes of updates that
+	// are written to the UTXO database in each batch when flushing the UTXO
+	// set.
+	utxoFlushBatchSize = 16 * 1024 * 1024 // 16 MiB
 )
 
 // -----------------------------------------------------------------------------
@@ -47,9 +54,10 @@ type utxoKeySet uint8
 
 // These constants define the available UTXO backend key sets.
 const (
-	utxoKeySetDbInfo    utxoKeySet = iota + 1 // 1
-	utxoKeySetUtxoState                       // 2
-	utxoKeySetUtxoSet                         // 3
+	utxoKeySetDbInfo       utxoKeySet = iota + 1 // 1
+	utxoKeySetUtxoState                          // 2
+	utxoKeySetUtxoSet                            // 3
+	utxoKeySetFlushJournal                       // 4
 )
 
 // utxoKeySetNoVersion defines the value to be used for the version of key sets
@@ -62,9 +70,10 @@ var utxoKeySetVersions = map[utxoKeySet]uint8{
 	// Note: The database info key set must remain at fixed keys so that older
 	// software can properly load the database versioning info, detect newer
 	// versions, and throw an error.
-	utxoKeySetDbInfo:    utxoKeySetNoVersion,
-	utxoKeySetUtxoState: 1,
-	utxoKeySetUtxoSet:   3,
+	utxoKeySetDbInfo:       utxoKeySetNoVersion,
+	utxoKeySetUtxoState:    1,
+	utxoKeySetUtxoSet:      3,
+	utxoKeySetFlushJournal: 1,
 }
 
 // These variables define the serialized prefix for each key set and associated
@@ -82,6 +91,12 @@ var (
 	// utxoPrefixUtxoSet is the prefix for all keys in the UTXO set key set.
 	utxoPrefixUtxoSet = []byte{byte(utxoKeySetUtxoSet),
 		utxoKeySetVersions[utxoKeySetUtxoSet]}
+
+	// utxoPrefixFlushJournal is the prefix for all keys in the flush journal
+	// key set.  The keys consist of the prefix followed by the key of the
+	// entry in the UTXO set the update is for.
+	utxoPrefixFlushJournal = []byte{byte(utxoKeySetFlushJournal),
+		utxoKeySetVersions[utxoKeySetFlushJournal]}
 )
 
 // prefixedKey returns a new byte slice that consists of the provided prefix
@@ -139,6 +154,17 @@ var (
 	// utxoSetStateKey is the database key used to house the state of the
 	// unspent transaction output set.
 	utxoSetStateKey = prefixedKey(utxoPrefixUtxoState, utxoSetStateKeyName)
+
+	// utxoFlushJournalKeyName is the name of the database key used to house
+	// the state of the unspent transaction output set a committed flush
+	// journal updates it to.  It is itself under utxoPrefixUtxoState.
+	utxoFlushJournalKeyName = []byte("flushjournal")
+
+	// utxoFlushJournalKey is the database key used to house the state of the
+	// unspent transaction output set a committed flush journal updates it
+	// to.
+	utxoFlushJournalKey = prefixedKey(utxoPrefixUtxoState,
+		utxoFlushJournalKeyName)
 )
 
 // -----------------------------------------------------------------------------
@@ -170,6 +196,7 @@ type UtxoStats struct {
 	Size           int64
 	Total          int64
 	SerializedHash chainhash.Hash
+	MuHash         chainhash.Hash
 }
 
 // UtxoBackend represents a persistent storage layer for the UTXO set.
@@ -177,6 +204,13 @@ type UtxoStats struct {
 // The interface contract requires that all of these methods are safe for
 // concurrent access.
 type UtxoBackend interface {
+	// Backup writes a consistent copy of the UTXO backend as of the time it
+	// is called to the provided directory while the backend remains usable.
+	// The directory must not already exist and is removed when the backup
+	// fails.  The provided function, which may be nil, is invoked
+	// periodically with the progress of the backup.
+	Backup(ctx context.Context, dir string, progress func(database.BackupProgress)) error
+
 	// FetchEntry returns the specified transaction output from the UTXO set.
 	//
 	// When there is no entry for the provided output, nil will be returned for
@@ -193,6 +227,13 @@ type UtxoBackend interface {
 	// FetchStats returns statistics on the current UTXO set.
 	FetchStats() (*UtxoStats, error)
 
+	// ForEachUtxo invokes the provided function with every unspent transaction
+	// output in the UTXO set ordered by outpoint.  Iteration stops as soon as
+	// the function returns an error and that error is returned.
+	//
+	// The entries are NOT safe to retain or modify after the function returns.
+	ForEachUtxo(fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error
+
 	// Get returns the value for the given key.  It returns nil if the key does
 	// not exist.  An empty slice is returned for keys that exist but have no
 	// value assigned.
@@ -222,10 +263,16 @@ type UtxoBackend interface {
 	// backend.
 	PutInfo(info *UtxoBackendInfo) error
 
-	// PutUtxos atomically updates the UTXO set with the entries from the
-	// provided map along with the current state.
+	// PutUtxos updates the UTXO set with the entries from the provided map
+	// along with the current state.  The UTXO set and its state are always
+	// consistent once RecoverFlush is called after an unclean shutdown.
 	PutUtxos(utxos map[wire.OutPoint]*UtxoEntry, state *UtxoSetState) error
 
+	// RecoverFlush completes or discards an update of the UTXO set that was
+	// interrupted by an unclean shutdown.  It must be called before the UTXO
+	// set is used.
+	RecoverFlush() error
+
 	// Update invokes the passed function in the context of a UTXO Backend
 	// transaction.  Any errors returned from the user-supplied function will
 	// cause the transaction to be rolled back and are returned from this
@@ -244,6 +291,10 @@ type levelDbUtxoBackend struct {
 	// db is the database that contains the UTXO set.  It is set when the
 	// instance is created and is not changed afterward.
 	db *leveldb.DB
+
+	// encryptionKey is the key the database is encrypted with, if any.  It is
+	// used to encrypt backups of the database the same way.
+	encryptionKey []byte
 }
 
 // Ensure levelDbUtxoBackend implements the UtxoBackend interface.
@@ -320,10 +371,41 @@ func fileExists(name string) bool {
 	return true
 }
 
+// applyUtxoDBOptions sets the leveldb options that correspond to the provided
+// tuning options, which may be nil.  Options that are not set are left at the
+// leveldb defaults.
+//
+// The number of tables in the first level at which writes are slowed down and
+// paused is kept at the same distance from the compaction trigger as the
+// leveldb defaults so overriding the trigger does not immediately slow down
+// writes.
+func applyUtxoDBOptions(opts *opt.Options, dbOpts *database.Options) {
+	if dbOpts == nil {
+		return
+	}
+	if dbOpts.WriteBufferSize > 0 {
+		opts.WriteBuffer = dbOpts.WriteBufferSize
+	}
+	if dbOpts.MaxOpenFiles > 0 {
+		opts.OpenFilesCacheCapacity = dbOpts.MaxOpenFiles
+	}
+	if dbOpts.CompactionL0Trigger > 0 {
+		opts.CompactionL0Trigger = dbOpts.CompactionL0Trigger
+		opts.WriteL0SlowdownTrigger = dbOpts.CompactionL0Trigger + 4
+		opts.WriteL0PauseTrigger = dbOpts.CompactionL0Trigger + 8
+	}
+	if dbOpts.CompactionTableSize > 0 {
+		opts.CompactionTableSize = dbOpts.CompactionTableSize
+	}
+}
+
 // LoadUtxoDB loads (or creates when needed) the UTXO database and returns a
 // handle to it.  It also contains additional logic such as ensuring the
 // regression test database is clean when in regression test mode.
-func LoadUtxoDB(ctx context.Context, params *chaincfg.Params, dataDir string) (*leveldb.DB, error) {
+//
+// The provided tuning options, which may be nil, are applied to the database,
+// and its contents are encrypted when they include an encryption key.
+func LoadUtxoDB(ctx context.Context, params *chaincfg.Params, dataDir string, dbOpts *database.Options) (*leveldb.DB, error) {
 	// Set the database path based on the data directory and UTXO database name.
 	dbPath := filepath.Join(dataDir, utxoDbName)
 
@@ -345,7 +427,7 @@ func LoadUtxoDB(ctx context.Context, params *chaincfg.Params, dataDir string) (*
 	// Ensure the full path to the database exists.
 	dbExists := fileExists(dbPath)
 	if !dbExists {
-		// The error can be ignored here since the call to leveldb.OpenFile will
+		// The error can be ignored here since the call to open the database will
 		// fail if the directory couldn't be created.
 		//
 		// NOTE: It is important that os.MkdirAll is only called if the database
@@ -365,7 +447,12 @@ func LoadUtxoDB(ctx context.Context, params *chaincfg.Params, dataDir string) (*
 		Compression:  opt.NoCompression,
 		Filter:       filter.NewBloomFilter(10),
 	}
-	db, err := leveldb.OpenFile(dbPath, &opts)
+	applyUtxoDBOptions(&opts, dbOpts)
+	var encryptionKey []byte
+	if dbOpts != nil {
+		encryptionKey = dbOpts.EncryptionKey
+	}
+	db, err := dbcrypt.OpenLevelDB(dbPath, encryptionKey, &opts)
 	if err != nil {
 		return nil, convertLdbErr(err, "failed to open UTXO database")
 	}
@@ -375,6 +462,126 @@ func LoadUtxoDB(ctx context.Context, params *chaincfg.Params, dataDir string) (*
 	return db, nil
 }
 
+// OpenUtxoDBReadOnly opens the existing UTXO database relative to the provided
+// data directory in read-only mode and returns a handle to it.  It is intended
+// for tools that inspect the UTXO set without any risk of modifying it, so,
+// unlike LoadUtxoDB, it never creates, moves, or removes the database.
+//
+// All attempts to write to the returned database fail.  It may be opened in
+// read-only mode by multiple processes at once, but not while another process,
+// such as a running node, has it open in read-write mode.  BackupUtxoDB may be
+// used to make a copy of the database of a running node for that purpose.
+//
+// The encryption key must be the one the database was created with, or nil
+// when it is not encrypted.
+func OpenUtxoDBReadOnly(dataDir string, encryptionKey []byte) (*leveldb.DB, error) {
+	dbPath := filepath.Join(dataDir, utxoDbName)
+	if !fileExists(dbPath) {
+		str := fmt.Sprintf("UTXO database %q does not exist", dbPath)
+		return nil, contextError(ErrUtxoBackend, str)
+	}
+
+	log.Infof("Loading UTXO database from '%s' in read-only mode", dbPath)
+	opts := opt.Options{
+		ErrorIfMissing: true,
+		ReadOnly:       true,
+		Strict:         opt.DefaultStrict,
+		Compression:    opt.NoCompression,
+		Filter:         filter.NewBloomFilter(10),
+	}
+	db, err := dbcrypt.OpenLevelDB(dbPath, encryptionKey, &opts)
+	if err != nil {
+		return nil, convertLdbErr(err, "failed to open UTXO database")
+	}
+
+	log.Info("UTXO database loaded")
+
+	return db, nil
+}
+
+// FetchUtxoDBStats returns statistics about the storage of the provided UTXO
+// database such as the size of each level and the amount of pending
+// compaction.  The tuning options must be the same ones the database was
+// loaded with.
+func FetchUtxoDBStats(db *leveldb.DB, dbOpts *database.Options) (*database.Stats, error) {
+	var ldbStats leveldb.DBStats
+	if err := db.Stats(&ldbStats); err != nil {
+		return nil, convertLdbErr(err, "failed to fetch UTXO database stats")
+	}
+	var opts opt.Options
+	applyUtxoDBOptions(&opts, dbOpts)
+
+	stats := &database.Stats{
+		Levels:         make([]database.LevelStats, len(ldbStats.LevelSizes)),
+		WriteDelays:    uint64(ldbStats.WriteDelayCount),
+		WriteDelayTime: ldbStats.WriteDelayDuration,
+		WritePaused:    ldbStats.WritePaused,
+	}
+	stats.Compactions = uint64(ldbStats.MemComp) +
+		uint64(ldbStats.Level0Comp) + uint64(ldbStats.NonLevel0Comp) +
+		uint64(ldbStats.SeekComp)
+	var totalWritten int64
+	for level := range stats.Levels {
+		ls := &stats.Levels[level]
+		ls.Size = ldbStats.LevelSizes[level]
+		if level < len(ldbStats.LevelTablesCounts) {
+			ls.Tables = ldbStats.LevelTablesCounts[level]
+		}
+		if level < len(ldbStats.LevelRead) {
+			ls.Read = ldbStats.LevelRead[level]
+		}
+		if level < len(ldbStats.LevelWrite) {
+			ls.Written = ldbStats.LevelWrite[level]
+		}
+		if level < len(ldbStats.LevelDurations) {
+			ls.CompactionTime = ldbStats.LevelDurations[level]
+		}
+		totalWritten += ls.Written
+
+		// Every table in the first level might contain any key and it is
+		// compacted based on its number of tables, while the tables in the
+		// other levels do not overlap and they are compacted based on their
+		// total size.
+		if level == 0 {
+			stats.ReadAmplification += ls.Tables
+			if ls.Tables >= opts.GetCompactionL0Trigger() {
+				stats.CompactionBacklog += ls.Size
+			}
+			continue
+		}
+		if ls.Tables > 0 {
+			stats.ReadAmplification++
+		}
+		if excess := ls.Size - opts.GetCompactionTotalSize(level); excess > 0 {
+			stats.CompactionBacklog += excess
+		}
+	}
+	if len(stats.Levels) > 0 && stats.Levels[0].Written > 0 {
+		stats.WriteAmplification = float64(totalWritten) /
+			float64(stats.Levels[0].Written)
+	}
+
+	return stats, nil
+}
+
+// BackupUtxoDB writes a consistent copy of the UTXO database as of the time it
+// is called to the location relative to the provided data directory that
+// LoadUtxoDB loads it from while the chain continues to process blocks.
+//
+// The copy only includes the entries that have been flushed from the UTXO
+// cache, so it might be behind the block database.  That is handled the same
+// way as an unclean shutdown when the chain is loaded from the copy.
+//
+// The provided function, which may be nil, is invoked periodically with the
+// progress of the backup.  The backup is aborted when the provided context is
+// canceled.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) BackupUtxoDB(ctx context.Context, dataDir string, progress func(database.BackupProgress)) error {
+	dbPath := filepath.Join(dataDir, utxoDbName)
+	return b.utxoCache.BackupBackend(ctx, dbPath, progress)
+}
+
 // NewLevelDbUtxoBackend returns a new instance of a backend that uses the
 // provided leveldb database for its underlying storage.
 func NewLevelDbUtxoBackend(db *leveldb.DB) UtxoBackend {
@@ -383,6 +590,116 @@ func NewLevelDbUtxoBackend(db *leveldb.DB) UtxoBackend {
 	}
 }
 
+// NewEncryptedLevelDbUtxoBackend returns a new instance of a backend that uses
+// the provided leveldb database, which is encrypted with the provided key, for
+// its underlying storage.  Backups of the database are encrypted with the same
+// key.  It is equivalent to NewLevelDbUtxoBackend when the key is nil.
+func NewEncryptedLevelDbUtxoBackend(db *leveldb.DB, encryptionKey []byte) UtxoBackend {
+	return &levelDbUtxoBackend{
+		db:            db,
+		encryptionKey: encryptionKey,
+	}
+}
+
+// backup is the implementation function for the Backup method.  See its
+// documentation for more details.
+//
+// It is only separate so the backup directory can be removed on any failure.
+func (l *levelDbUtxoBackend) backup(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	// Obtain a snapshot so the copy is consistent while the database
+	// continues to be updated.
+	snapshot, err := l.db.GetSnapshot()
+	if err != nil {
+		return convertLdbErr(err, "failed to open UTXO database snapshot")
+	}
+	defer snapshot.Release()
+
+	var total uint64
+	if sizes, err := l.db.SizeOf([]util.Range{{}}); err == nil {
+		total = uint64(sizes.Sum())
+	}
+
+	opts := opt.Options{
+		ErrorIfExist: true,
+		Strict:       opt.DefaultStrict,
+		Compression:  opt.NoCompression,
+		Filter:       filter.NewBloomFilter(10),
+	}
+	backupDB, err := dbcrypt.OpenLevelDB(dir, l.encryptionKey, &opts)
+	if err != nil {
+		return convertLdbErr(err, "failed to create UTXO database backup")
+	}
+	defer backupDB.Close()
+
+	// Copy the entries in batches and report the progress after each one.
+	const batchSize = 4 * 1024 * 1024
+	iter := snapshot.NewIterator(nil, nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	var copied, batchBytes uint64
+	for {
+		hasNext := iter.Next()
+		if hasNext {
+			key, value := iter.Key(), iter.Value()
+			batch.Put(key, value)
+			batchBytes += uint64(len(key) + len(value))
+			if batchBytes < batchSize {
+				continue
+			}
+		}
+
+		writeOpts := &opt.WriteOptions{Sync: !hasNext}
+		if err := backupDB.Write(batch, writeOpts); err != nil {
+			return convertLdbErr(err, "failed to write UTXO database backup")
+		}
+		batch.Reset()
+		copied += batchBytes
+		batchBytes = 0
+		if copied > total {
+			total = copied
+		}
+		if progress != nil {
+			progress(database.BackupProgress{
+				BytesCopied: copied,
+				TotalBytes:  total,
+			})
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !hasNext {
+			break
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return convertLdbErr(err, "failed to iterate UTXO database")
+	}
+
+	if err := backupDB.Close(); err != nil {
+		return convertLdbErr(err, "failed to close UTXO database backup")
+	}
+	return nil
+}
+
+// Backup writes a consistent copy of the UTXO backend as of the time it is
+// called to the provided directory while the backend remains usable.  The
+// directory must not already exist and is removed when the backup fails.  The
+// provided function, which may be nil, is invoked periodically with the
+// progress of the backup.
+//
+// This is part of the UtxoBackend interface.
+func (l *levelDbUtxoBackend) Backup(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	if fileExists(dir) {
+		str := fmt.Sprintf("backup directory %q already exists", dir)
+		return contextError(ErrUtxoBackend, str)
+	}
+	if err := l.backup(ctx, dir, progress); err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
 // Get gets the value for the given key from the leveldb database.  It
 // returns nil for both the value and the error if the database does not
 // contain the key.
@@ -530,6 +847,8 @@ func (l *levelDbUtxoBackend) FetchStats() (*UtxoStats, error) {
 	var stats UtxoStats
 	transactions := make(map[chainhash.Hash]struct{})
 	leaves := make([]chainhash.Hash, 0)
+	muHash := chainhash.NewMuHash()
+	var muHashData []byte
 	iter := l.NewIterator(utxoPrefixUtxoSet)
 	defer iter.Release()
 
@@ -557,6 +876,8 @@ func (l *levelDbUtxoBackend) FetchStats() (*UtxoStats, error) {
 		transactions[outpoint.Hash] = struct{}{}
 
 		leaves = append(leaves, chainhash.HashH(serializedUtxo))
+		muHashData = putUtxoMuHashData(muHashData, outpoint, serializedUtxo)
+		muHash.Add(muHashData)
 
 		// Deserialize the utxo entry.
 		entry, err := deserializeUtxoEntry(serializedUtxo, outpoint.Index)
@@ -579,11 +900,63 @@ func (l *levelDbUtxoBackend) FetchStats() (*UtxoStats, error) {
 	}
 
 	stats.SerializedHash = standalone.CalcMerkleRootInPlace(leaves)
+	stats.MuHash = muHash.Finalize()
 	stats.Transactions = int64(len(transactions))
 
 	return &stats, nil
 }
 
+// ForEachUtxo invokes the provided function with every unspent transaction
+// output in the UTXO set ordered by outpoint.  Iteration stops as soon as the
+// function returns an error and that error is returned.
+//
+// The entries are NOT safe to retain or modify after the function returns.
+func (l *levelDbUtxoBackend) ForEachUtxo(fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error {
+	iter := l.NewIterator(utxoPrefixUtxoSet)
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		var outpoint wire.OutPoint
+		err := decodeOutpointKey(key, &outpoint)
+		if err != nil {
+			str := fmt.Sprintf("corrupt outpoint for key %x: %v", key, err)
+			return contextError(ErrUtxoBackendCorruption, str)
+		}
+
+		// A non-nil zero-length entry means there is an entry in the database
+		// for a spent transaction output which should never be the case.
+		serializedUtxo := iter.Value()
+		if len(serializedUtxo) == 0 {
+			return AssertError(fmt.Sprintf("database contains entry for "+
+				"spent tx output %v", outpoint))
+		}
+
+		// Deserialize the utxo entry.
+		entry, err := deserializeUtxoEntry(serializedUtxo, outpoint.Index)
+		if err != nil {
+			// Ensure any deserialization errors are returned as UTXO backend
+			// corruption errors.
+			if isDeserializeErr(err) {
+				str := fmt.Sprintf("corrupt utxo entry for %v: %v", outpoint,
+					err)
+				return contextError(ErrUtxoBackendCorruption, str)
+			}
+
+			return err
+		}
+
+		if err := fn(outpoint, entry); err != nil {
+			return err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return convertLdbErr(err, "failed to iterate utxo set")
+	}
+
+	return nil
+}
+
 // dbPutUtxoBackendInfo uses an existing UTXO backend transaction to store the
 // backend information.
 func (l *levelDbUtxoBackend) dbPutUtxoBackendInfo(tx UtxoBackendTx,
@@ -804,28 +1177,146 @@ func (l *levelDbUtxoBackend) dbPutUtxoEntry(tx UtxoBackendTx,
 	return nil
 }
 
-// PutUtxos atomically updates the UTXO set with the entries from the provided
-// map along with the current state.
+// writeBatch writes the provided batch to the database and resets it.  The
+// write is synced to disk when the sync flag is set.
+func (l *levelDbUtxoBackend) writeBatch(batch *leveldb.Batch, sync bool) error {
+	err := l.db.Write(batch, &opt.WriteOptions{Sync: sync})
+	if err != nil {
+		return convertLdbErr(err, "failed to write batch")
+	}
+	batch.Reset()
+	return nil
+}
+
+// finishFlush writes the provided batch, which contains the final updates of a
+// flush, along with the provided serialized UTXO set state and the removal of
+// the committed flush journal.  The write is synced to disk so the journal is
+// guaranteed to be empty before the next flush starts writing to it.
+func (l *levelDbUtxoBackend) finishFlush(batch *leveldb.Batch, serializedState []byte) error {
+	batch.Put(utxoSetStateKey, serializedState)
+	batch.Delete(utxoFlushJournalKey)
+	return l.writeBatch(batch, true)
+}
+
+// PutUtxos updates the UTXO set with the entries from the provided map along
+// with the current state.
+//
+// The updates are written in batches of a bounded size rather than a single
+// transaction so the memory needed does not depend on the number of entries.
+// In order to keep the UTXO set consistent with its state in the event of an
+// unclean shutdown in the middle of the updates, they are first written to a
+// write-ahead journal which is committed along with the new state before any
+// of them are applied.  RecoverFlush completes an interrupted flush from the
+// journal when it was committed and discards it otherwise, in which case the
+// UTXO set and its state were not modified.
 func (l *levelDbUtxoBackend) PutUtxos(utxos map[wire.OutPoint]*UtxoEntry,
 	state *UtxoSetState) error {
 
-	// Update the database with the provided entries and UTXO set state.
-	//
-	// It is important that the UTXO set state is always updated in the same
-	// UTXO backend transaction as the utxo set itself so that it is always in
-	// sync.
-	return l.Update(func(tx UtxoBackendTx) error {
-		for outpoint, entry := range utxos {
-			// Write the entry to the database.
-			err := l.dbPutUtxoEntry(tx, outpoint, entry)
-			if err != nil {
+	// Write the updates to the journal.  Entries that were not modified do
+	// not need to be updated and spent entries are journaled with an empty
+	// value to indicate they are removed.
+	batch := new(leveldb.Batch)
+	for outpoint, entry := range utxos {
+		if entry == nil || !entry.isModified() {
+			continue
+		}
+
+		key := outpointKey(outpoint)
+		batch.Put(prefixedKey(utxoPrefixFlushJournal, *key),
+			serializeUtxoEntry(entry))
+		recycleOutpointKey(key)
+		if len(batch.Dump()) >= utxoFlushBatchSize {
+			if err := l.writeBatch(batch, false); err != nil {
 				return err
 			}
 		}
+	}
 
-		// Update the UTXO set state in the database.
-		return tx.Put(utxoSetStateKey, serializeUtxoSetState(state))
-	})
+	// Commit the journal along with the new state.  The write is synced so
+	// the full journal is guaranteed to be on disk before any of the updates
+	// are applied.
+	serializedState := serializeUtxoSetState(state)
+	batch.Put(utxoFlushJournalKey, serializedState)
+	if err := l.writeBatch(batch, true); err != nil {
+		return err
+	}
+
+	// Apply the updates to the UTXO set while removing them from the journal
+	// and finally update the UTXO set state.
+	for outpoint, entry := range utxos {
+		if entry == nil || !entry.isModified() {
+			continue
+		}
+
+		key := outpointKey(outpoint)
+		if entry.IsSpent() {
+			batch.Delete(*key)
+		} else {
+			batch.Put(*key, serializeUtxoEntry(entry))
+		}
+		batch.Delete(prefixedKey(utxoPrefixFlushJournal, *key))
+		recycleOutpointKey(key)
+		if len(batch.Dump()) >= utxoFlushBatchSize {
+			if err := l.writeBatch(batch, false); err != nil {
+				return err
+			}
+		}
+	}
+	return l.finishFlush(batch, serializedState)
+}
+
+// RecoverFlush completes a flush of the UTXO set that was interrupted by an
+// unclean shutdown after its journal was committed by applying the updates that
+// remain in the journal.  Otherwise, the journal of a flush that was
+// interrupted before that is discarded since the UTXO set and its state were
+// not modified.  It has no effect when no flush was interrupted.
+func (l *levelDbUtxoBackend) RecoverFlush() error {
+	serializedState, err := l.Get(utxoFlushJournalKey)
+	if err != nil {
+		return err
+	}
+
+	// Apply the updates in a committed journal while removing them from the
+	// journal.  Applying an update more than once has no additional effect,
+	// so it does not matter if some of them were already applied.
+	var numUpdates uint64
+	batch := new(leveldb.Batch)
+	iter := l.db.NewIterator(util.BytesPrefix(utxoPrefixFlushJournal), nil)
+	for iter.Next() {
+		journalKey := iter.Key()
+		if serializedState != nil {
+			key := journalKey[len(utxoPrefixFlushJournal):]
+			if value := iter.Value(); len(value) == 0 {
+				batch.Delete(key)
+			} else {
+				batch.Put(key, value)
+			}
+		}
+		batch.Delete(journalKey)
+		numUpdates++
+		if len(batch.Dump()) >= utxoFlushBatchSize {
+			if err := l.writeBatch(batch, false); err != nil {
+				iter.Release()
+				return err
+			}
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return convertLdbErr(err, "failed to iterate flush journal")
+	}
+
+	if serializedState == nil {
+		if numUpdates == 0 {
+			return nil
+		}
+		log.Infof("Discarding %d updates of an interrupted UTXO cache flush",
+			numUpdates)
+		return l.writeBatch(batch, true)
+	}
+	log.Infof("Completing an interrupted UTXO cache flush (%d updates)",
+		numUpdates)
+	return l.finishFlush(batch, serializedState)
 }
 
 // Upgrade upgrades the UTXO backend by applying all possible upgrades
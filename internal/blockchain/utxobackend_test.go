@@ -5,12 +5,18 @@
 package blockchain
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/database/v3/dbcrypt"
 	"github.com/decred/dcrd/wire"
 	"github.com/syndtr/goleveldb/leveldb"
 	ldberrors "github.com/syndtr/goleveldb/leveldb/errors"
@@ -294,6 +300,263 @@ func TestPutUtxos(t *testing.T) {
 	}
 }
 
+// TestRecoverFlush validates that a flush of the UTXO set that was interrupted
+// by an unclean shutdown is completed from its journal when the journal was
+// committed and discarded otherwise.
+func TestRecoverFlush(t *testing.T) {
+	t.Parallel()
+
+	// Create test hashes to be used throughout the tests.
+	block1000Hash := mustParseHash("0000000000004740ad140c86753f9295e09f9cc81" +
+		"b1bb75d7f5552aeeedb7012")
+	block2000Hash := mustParseHash("0000000000000c8a886e3f7c32b1bb08422066dcf" +
+		"d008de596471f11a5aff475")
+	state1000 := &UtxoSetState{
+		lastFlushHash:   *block1000Hash,
+		lastFlushHeight: 1000,
+	}
+	state2000 := &UtxoSetState{
+		lastFlushHash:   *block2000Hash,
+		lastFlushHeight: 2000,
+	}
+
+	// entry299 is added and entry1100 is removed by the interrupted flush.
+	outpoint299, outpoint1100 := outpoint299(), outpoint1100()
+	entry1100Modified := entry1100()
+	entry1100Modified.state |= utxoStateModified
+
+	tests := []struct {
+		name               string
+		committed          bool
+		wantBackendEntries map[wire.OutPoint]*UtxoEntry
+		wantState          *UtxoSetState
+	}{{
+		name:      "committed journal is completed",
+		committed: true,
+		wantBackendEntries: map[wire.OutPoint]*UtxoEntry{
+			outpoint299: entry299(),
+		},
+		wantState: state2000,
+	}, {
+		name:      "uncommitted journal is discarded",
+		committed: false,
+		wantBackendEntries: map[wire.OutPoint]*UtxoEntry{
+			outpoint1100: entry1100(),
+		},
+		wantState: state1000,
+	}}
+
+	for _, test := range tests {
+		// Create a test backend with the entry that is removed by the
+		// interrupted flush.
+		backend := createTestUtxoBackend(t)
+		err := backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{
+			outpoint1100: entry1100Modified,
+		}, state1000)
+		if err != nil {
+			t.Fatalf("%q: unexpected error adding entries to test backend: %v",
+				test.name, err)
+		}
+
+		// Simulate a flush that was interrupted after writing its journal and
+		// applying one of its updates without removing it from the journal.
+		err = backend.Update(func(tx UtxoBackendTx) error {
+			key299 := outpointKey(outpoint299)
+			defer recycleOutpointKey(key299)
+			key1100 := outpointKey(outpoint1100)
+			defer recycleOutpointKey(key1100)
+			err := tx.Put(prefixedKey(utxoPrefixFlushJournal, *key299),
+				serializeUtxoEntry(entry299()))
+			if err != nil {
+				return err
+			}
+			err = tx.Put(prefixedKey(utxoPrefixFlushJournal, *key1100), nil)
+			if err != nil {
+				return err
+			}
+			if !test.committed {
+				return nil
+			}
+			err = tx.Put(utxoFlushJournalKey, serializeUtxoSetState(state2000))
+			if err != nil {
+				return err
+			}
+			return tx.Put(*key299, serializeUtxoEntry(entry299()))
+		})
+		if err != nil {
+			t.Fatalf("%q: unexpected error writing journal: %v", test.name, err)
+		}
+
+		if err := backend.RecoverFlush(); err != nil {
+			t.Fatalf("%q: unexpected error recovering flush: %v", test.name,
+				err)
+		}
+
+		// Validate the backend entries and state match the expected values.
+		backendEntries := make(map[wire.OutPoint]*UtxoEntry)
+		for _, outpoint := range []wire.OutPoint{outpoint299, outpoint1100} {
+			entry, err := backend.FetchEntry(outpoint)
+			if err != nil {
+				t.Fatalf("%q: unexpected error fetching entries from test "+
+					"backend: %v", test.name, err)
+			}
+			if entry != nil {
+				backendEntries[outpoint] = entry
+			}
+		}
+		if !reflect.DeepEqual(backendEntries, test.wantBackendEntries) {
+			t.Fatalf("%q: mismatched backend entries:\nwant: %+v\n got: %+v\n",
+				test.name, test.wantBackendEntries, backendEntries)
+		}
+		gotState, err := backend.FetchState()
+		if err != nil {
+			t.Fatalf("%q: error fetching utxo set state: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(gotState, test.wantState) {
+			t.Fatalf("%q: mismatched state:\nwant: %+v\n got: %+v\n", test.name,
+				test.wantState, gotState)
+		}
+
+		// Validate the journal is empty.
+		iter := backend.NewIterator(utxoPrefixFlushJournal)
+		hasJournalEntries := iter.Next()
+		iter.Release()
+		journalState, err := backend.Get(utxoFlushJournalKey)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if hasJournalEntries || journalState != nil {
+			t.Fatalf("%q: journal is not empty", test.name)
+		}
+	}
+}
+
+// TestForEachUtxo ensures that iterating the utxo set in the backend visits
+// every unspent output and stops when the provided function returns an error.
+func TestForEachUtxo(t *testing.T) {
+	t.Parallel()
+
+	// Create a test backend and add entries to it.
+	backend := createTestUtxoBackend(t)
+	wantEntries := map[wire.OutPoint]*UtxoEntry{
+		outpoint299():  entry299(),
+		outpoint1100(): entry1100(),
+		outpoint1200(): entry1200(),
+	}
+	putEntries := make(map[wire.OutPoint]*UtxoEntry, len(wantEntries))
+	for outpoint, entry := range wantEntries {
+		entry := entry.Clone()
+		entry.state |= utxoStateModified
+		putEntries[outpoint] = entry
+	}
+	err := backend.PutUtxos(putEntries, &UtxoSetState{})
+	if err != nil {
+		t.Fatalf("unexpected error adding entries to test backend: %v", err)
+	}
+
+	// Ensure all entries are visited.
+	gotEntries := make(map[wire.OutPoint]*UtxoEntry)
+	err = backend.ForEachUtxo(func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+		gotEntries[outpoint] = entry
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error iterating utxo set: %v", err)
+	}
+	if !reflect.DeepEqual(gotEntries, wantEntries) {
+		t.Fatalf("mismatched entries:\nwant: %+v\n got: %+v\n", wantEntries,
+			gotEntries)
+	}
+
+	// Ensure iteration stops when the function returns an error.
+	errStop := errors.New("stop")
+	var numVisited int
+	err = backend.ForEachUtxo(func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+		numVisited++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, errStop)
+	}
+	if numVisited != 1 {
+		t.Fatalf("unexpected number of visited entries -- got %d, want 1",
+			numVisited)
+	}
+}
+
+// TestUtxoBackendBackup ensures that a backup of the backend contains the
+// entries stored before the backup started and can be loaded as a backend.
+func TestUtxoBackendBackup(t *testing.T) {
+	t.Parallel()
+
+	// Create a test backend and add entries to it.
+	backend := createTestUtxoBackend(t)
+	wantEntries := map[wire.OutPoint]*UtxoEntry{
+		outpoint299():  entry299(),
+		outpoint1100(): entry1100(),
+	}
+	putEntries := make(map[wire.OutPoint]*UtxoEntry, len(wantEntries))
+	for outpoint, entry := range wantEntries {
+		entry := entry.Clone()
+		entry.state |= utxoStateModified
+		putEntries[outpoint] = entry
+	}
+	err := backend.PutUtxos(putEntries, &UtxoSetState{})
+	if err != nil {
+		t.Fatalf("unexpected error adding entries to test backend: %v", err)
+	}
+
+	// Ensure a backup to a directory that already exists fails.
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	if err := backend.Backup(ctx, tempDir, nil); !errors.Is(err,
+		ErrUtxoBackend) {
+
+		t.Fatalf("unexpected error -- got %v, want %v", err, ErrUtxoBackend)
+	}
+
+	// Backup the backend and ensure the progress is reported.
+	backupPath := filepath.Join(tempDir, "backup")
+	var progress database.BackupProgress
+	err = backend.Backup(ctx, backupPath, func(p database.BackupProgress) {
+		progress = p
+	})
+	if err != nil {
+		t.Fatalf("unexpected error backing up backend: %v", err)
+	}
+	if progress.BytesCopied == 0 || progress.BytesCopied != progress.TotalBytes {
+		t.Fatalf("unexpected final progress %+v", progress)
+	}
+
+	// Add another entry to the original backend and ensure the backup only
+	// contains the entries that existed before it started.
+	entry := entry1200().Clone()
+	entry.state |= utxoStateModified
+	err = backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{
+		outpoint1200(): entry,
+	}, &UtxoSetState{})
+	if err != nil {
+		t.Fatalf("unexpected error adding entry to test backend: %v", err)
+	}
+	backupDB, err := leveldb.OpenFile(backupPath, nil)
+	if err != nil {
+		t.Fatalf("unexpected error opening backup: %v", err)
+	}
+	defer backupDB.Close()
+	gotEntries := make(map[wire.OutPoint]*UtxoEntry)
+	err = NewLevelDbUtxoBackend(backupDB).ForEachUtxo(func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+		gotEntries[outpoint] = entry
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error iterating backup utxo set: %v", err)
+	}
+	if !reflect.DeepEqual(gotEntries, wantEntries) {
+		t.Fatalf("mismatched entries:\nwant: %+v\n got: %+v\n", wantEntries,
+			gotEntries)
+	}
+}
+
 // TestFetchState ensures that fetching the utxo set state from the backend
 // works as expected.
 func TestFetchState(t *testing.T) {
@@ -391,3 +654,167 @@ func TestPutInfo(t *testing.T) {
 		}
 	}
 }
+
+// TestOpenUtxoDBReadOnly ensures opening the UTXO database in read-only mode
+// provides access to the existing entries and rejects modifications.
+func TestOpenUtxoDBReadOnly(t *testing.T) {
+	t.Parallel()
+
+	// Ensure attempting to open a database that doesn't exist fails and does
+	// not create it.
+	dataDir := t.TempDir()
+	if _, err := OpenUtxoDBReadOnly(dataDir, nil); !errors.Is(err, ErrUtxoBackend) {
+		t.Fatalf("unexpected error -- got %v, want %v", err, ErrUtxoBackend)
+	}
+	if fileExists(filepath.Join(dataDir, utxoDbName)) {
+		t.Fatal("UTXO database created when opening in read-only mode")
+	}
+
+	// Create a UTXO database with an entry in the data directory by backing
+	// up a test backend to it.
+	backend := createTestUtxoBackend(t)
+	entry := entry299().Clone()
+	entry.state |= utxoStateModified
+	err := backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{
+		outpoint299(): entry,
+	}, &UtxoSetState{})
+	if err != nil {
+		t.Fatalf("unexpected error adding entry to test backend: %v", err)
+	}
+	dbPath := filepath.Join(dataDir, utxoDbName)
+	if err := backend.Backup(context.Background(), dbPath, nil); err != nil {
+		t.Fatalf("unexpected error backing up backend: %v", err)
+	}
+
+	// Open the database in read-only mode and ensure the entry is available.
+	db, err := OpenUtxoDBReadOnly(dataDir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error opening UTXO database: %v", err)
+	}
+	defer db.Close()
+	readOnlyBackend := NewLevelDbUtxoBackend(db)
+	gotEntry, err := readOnlyBackend.FetchEntry(outpoint299())
+	if err != nil {
+		t.Fatalf("unexpected error fetching entry: %v", err)
+	}
+	if !reflect.DeepEqual(gotEntry, entry299()) {
+		t.Fatalf("mismatched entry:\nwant: %+v\n got: %+v\n", entry299(),
+			gotEntry)
+	}
+
+	// Ensure attempts to modify the database fail.
+	entry = entry1100().Clone()
+	entry.state |= utxoStateModified
+	err = readOnlyBackend.PutUtxos(map[wire.OutPoint]*UtxoEntry{
+		outpoint1100(): entry,
+	}, &UtxoSetState{})
+	if err == nil {
+		t.Fatal("modified UTXO database opened in read-only mode")
+	}
+}
+
+// TestLoadUtxoDBOptions ensures the tuning options are applied when loading
+// the UTXO database and its statistics reflect the data written to it.
+func TestLoadUtxoDBOptions(t *testing.T) {
+	t.Parallel()
+
+	// Load a UTXO database with a write buffer that is small enough for the
+	// entries written below to be written to tables.  None of them would be
+	// written to tables with the default write buffer size.
+	dbOpts := &database.Options{
+		WriteBufferSize:     32 * 1024,
+		CompactionL0Trigger: 2,
+	}
+	db, err := LoadUtxoDB(context.Background(), chaincfg.MainNetParams(),
+		t.TempDir(), dbOpts)
+	if err != nil {
+		t.Fatalf("unexpected error loading UTXO database: %v", err)
+	}
+	defer db.Close()
+
+	backend := NewLevelDbUtxoBackend(db)
+	entries := make(map[wire.OutPoint]*UtxoEntry)
+	for i := uint32(0); i < 4000; i++ {
+		entry := entry299().Clone()
+		entry.state |= utxoStateModified
+		outpoint := outpoint299()
+		outpoint.Index = i
+		entries[outpoint] = entry
+	}
+	if err := backend.PutUtxos(entries, &UtxoSetState{}); err != nil {
+		t.Fatalf("unexpected error adding entries: %v", err)
+	}
+
+	stats, err := FetchUtxoDBStats(db, dbOpts)
+	if err != nil {
+		t.Fatalf("unexpected error fetching stats: %v", err)
+	}
+	var tables int
+	var written int64
+	for _, level := range stats.Levels {
+		tables += level.Tables
+		written += level.Written
+	}
+	if tables == 0 || written == 0 || stats.ReadAmplification == 0 {
+		t.Fatalf("unexpected stats for written entries: %+v", stats)
+	}
+	if stats.WriteAmplification < 1 {
+		t.Fatalf("unexpected write amplification %v",
+			stats.WriteAmplification)
+	}
+}
+
+// TestUtxoDBEncryption ensures an encrypted UTXO database and its backups are
+// only able to be opened with the key it was loaded with.
+func TestUtxoDBEncryption(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{0x01}, dbcrypt.KeySize)
+	dataDir := t.TempDir()
+	db, err := LoadUtxoDB(context.Background(), chaincfg.MainNetParams(),
+		dataDir, &database.Options{EncryptionKey: key})
+	if err != nil {
+		t.Fatalf("unexpected error loading UTXO database: %v", err)
+	}
+	backend := NewEncryptedLevelDbUtxoBackend(db, key)
+	entry := entry299().Clone()
+	entry.state |= utxoStateModified
+	err = backend.PutUtxos(map[wire.OutPoint]*UtxoEntry{
+		outpoint299(): entry,
+	}, &UtxoSetState{})
+	if err != nil {
+		t.Fatalf("unexpected error adding entry: %v", err)
+	}
+
+	// Backup the database to another data directory.
+	backupDataDir := t.TempDir()
+	backupPath := filepath.Join(backupDataDir, utxoDbName)
+	if err := backend.Backup(context.Background(), backupPath, nil); err != nil {
+		t.Fatalf("unexpected error backing up backend: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("unexpected error closing UTXO database: %v", err)
+	}
+
+	// Ensure neither the database nor its backup are able to be opened
+	// without the key and that the entry is available with it.
+	for _, dir := range []string{dataDir, backupDataDir} {
+		if _, err := OpenUtxoDBReadOnly(dir, nil); err == nil {
+			t.Fatalf("opened encrypted UTXO database in %q without a key",
+				dir)
+		}
+		db, err := OpenUtxoDBReadOnly(dir, key)
+		if err != nil {
+			t.Fatalf("unexpected error opening UTXO database: %v", err)
+		}
+		gotEntry, err := NewLevelDbUtxoBackend(db).FetchEntry(outpoint299())
+		db.Close()
+		if err != nil {
+			t.Fatalf("unexpected error fetching entry: %v", err)
+		}
+		if !reflect.DeepEqual(gotEntry, entry299()) {
+			t.Fatalf("mismatched entry:\nwant: %+v\n got: %+v\n",
+				entry299(), gotEntry)
+		}
+	}
+}
@@ -65,6 +65,12 @@ const (
 // The interface contract requires that all of these methods are safe for
 // concurrent access.
 type UtxoCacher interface {
+	// BackupBackend writes a consistent copy of the utxo set backend to the
+	// provided directory.  Entries that have not been flushed from the cache
+	// are not included.  See UtxoBackend.Backup for more details.
+	BackupBackend(ctx context.Context, dir string,
+		progress func(database.BackupProgress)) error
+
 	// Commit updates the cache based on the state of each entry in the provided
 	// view.
 	//
@@ -99,6 +105,16 @@ type UtxoCacher interface {
 	// FetchStats returns statistics on the current utxo set.
 	FetchStats(bestHash *chainhash.Hash, bestHeight uint32) (*UtxoStats, error)
 
+	// FlushStats returns statistics about the flushes of the cache to the
+	// backend.
+	FlushStats() UtxoFlushStats
+
+	// ForEachEntry invokes the provided function with every unspent
+	// transaction output in the utxo set ordered by outpoint.  Iteration stops
+	// as soon as the function returns an error and that error is returned.
+	ForEachEntry(bestHash *chainhash.Hash, bestHeight uint32,
+		fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error
+
 	// Initialize initializes the utxo cache and underlying utxo backend.  This
 	// entails running any database migrations as well as ensuring that the utxo
 	// set is caught up to the tip of the best chain.
@@ -110,6 +126,28 @@ type UtxoCacher interface {
 		logFlush bool) error
 }
 
+// UtxoFlushStats houses statistics about the flushes of the utxo cache to the
+// backend.
+type UtxoFlushStats struct {
+	// Flushes is the total number of flushes.
+	Flushes uint64
+
+	// Entries is the total number of modified entries that were written to
+	// the backend and Bytes is their total size in bytes.
+	Entries uint64
+	Bytes   uint64
+
+	// Duration is the total time spent flushing.
+	Duration time.Duration
+
+	// LastEntries, LastBytes, and LastDuration are the number of modified
+	// entries, their size in bytes, and the time spent for the most recent
+	// flush.
+	LastEntries  uint64
+	LastBytes    uint64
+	LastDuration time.Duration
+}
+
 // UtxoCache is an unspent transaction output cache that sits on top of the
 // utxo set backend and provides significant runtime performance benefits at
 // the cost of some additional memory usage.  It drastically reduces the amount
@@ -192,6 +230,12 @@ type UtxoCache struct {
 	// this cache instance, but an alternative can be provided for testing
 	// purposes.
 	maybeFlushFn func(*chainhash.Hash, uint32, bool, bool) error
+
+	// flushStats houses the statistics about the flushes of the cache.  It is
+	// protected by a separate mutex rather than the cache lock so the
+	// statistics remain available while a flush is in progress.
+	flushStatsMtx sync.Mutex
+	flushStats    UtxoFlushStats
 }
 
 // Ensure UtxoCache implements the UtxoCacher interface.
@@ -525,6 +569,15 @@ func (c *UtxoCache) FetchBackendState() (*UtxoSetState, error) {
 	return c.backend.FetchState()
 }
 
+// BackupBackend writes a consistent copy of the utxo set backend to the
+// provided directory.  Entries that have not been flushed from the cache are
+// not included.  See UtxoBackend.Backup for more details.
+//
+// This function is safe for concurrent access.
+func (c *UtxoCache) BackupBackend(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	return c.backend.Backup(ctx, dir, progress)
+}
+
 // FetchStats returns statistics on the current utxo set.
 func (c *UtxoCache) FetchStats(bestHash *chainhash.Hash, bestHeight uint32) (*UtxoStats, error) {
 	// Force a UTXO cache flush.  This is required in order for the backend to
@@ -537,6 +590,25 @@ func (c *UtxoCache) FetchStats(bestHash *chainhash.Hash, bestHeight uint32) (*Ut
 	return c.backend.FetchStats()
 }
 
+// ForEachEntry invokes the provided function with every unspent transaction
+// output in the utxo set ordered by outpoint.  Iteration stops as soon as the
+// function returns an error and that error is returned.
+//
+// This function is safe for concurrent access however the entries provided to
+// the function are NOT safe to retain or modify after it returns.
+func (c *UtxoCache) ForEachEntry(bestHash *chainhash.Hash, bestHeight uint32,
+	fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error {
+
+	// Force a UTXO cache flush.  This is required in order for the backend to
+	// iterate the full UTXO set.
+	err := c.maybeFlushFn(bestHash, bestHeight, true, false)
+	if err != nil {
+		return err
+	}
+
+	return c.backend.ForEachUtxo(fn)
+}
+
 // Commit updates the cache based on the state of each entry in the provided
 // view.
 //
@@ -679,6 +751,8 @@ func (c *UtxoCache) shouldFlush(bestHash *chainhash.Hash) bool {
 //
 // This function MUST be called with the cache lock held.
 func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush bool) error {
+	start := time.Now()
+
 	// If the maximum allowed size of the cache has been reached, determine the
 	// eviction height.
 	var evictionHeight uint32
@@ -715,8 +789,18 @@ func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush
 		return err
 	}
 
-	// Atomically flush all of the entries in the cache along with the best hash
-	// and best height to the backend.
+	// Determine the number and size of the modified entries for the flush
+	// statistics.
+	var numModified, modifiedSize uint64
+	for _, entry := range c.entries {
+		if entry != nil && entry.isModified() {
+			numModified++
+			modifiedSize += entry.size()
+		}
+	}
+
+	// Flush all of the entries in the cache along with the best hash and best
+	// height to the backend.
 	err = c.backend.PutUtxos(c.entries, &UtxoSetState{
 		lastFlushHeight: bestHeight,
 		lastFlushHash:   *bestHash,
@@ -765,6 +849,18 @@ func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush
 		c.lastEvictionHeight = evictionHeight
 	}
 
+	// Update the flush statistics.
+	duration := time.Since(start)
+	c.flushStatsMtx.Lock()
+	c.flushStats.Flushes++
+	c.flushStats.Entries += numModified
+	c.flushStats.Bytes += modifiedSize
+	c.flushStats.Duration += duration
+	c.flushStats.LastEntries = numModified
+	c.flushStats.LastBytes = modifiedSize
+	c.flushStats.LastDuration = duration
+	c.flushStatsMtx.Unlock()
+
 	// Log that the flush has been completed and indicate the updated memory
 	// usage as it will be reduced due to evicting entries above.
 	if logFlush {
@@ -774,13 +870,24 @@ func (c *UtxoCache) flush(bestHash *chainhash.Hash, bestHeight uint32, logFlush
 		memUsageMiB := float64(memUsage) / 1024 / 1024
 		memUsagePercent := float64(memUsage) / float64(c.maxSize) * 100
 		log.Debugf("UTXO cache flush completed (%d entries flushed, %d "+
-			"entries remaining, %.2f MiB (%.2f%%))", flushedEntries,
-			remainingEntries, memUsageMiB, memUsagePercent)
+			"entries remaining, %.2f MiB (%.2f%%), %v)", flushedEntries,
+			remainingEntries, memUsageMiB, memUsagePercent,
+			duration.Round(time.Millisecond))
 	}
 
 	return nil
 }
 
+// FlushStats returns statistics about the flushes of the cache to the backend.
+//
+// This function is safe for concurrent access.
+func (c *UtxoCache) FlushStats() UtxoFlushStats {
+	c.flushStatsMtx.Lock()
+	stats := c.flushStats
+	c.flushStatsMtx.Unlock()
+	return stats
+}
+
 // MaybeFlush conditionally flushes the cache to the backend.
 //
 // If the maximum size of the cache has been reached, or if the periodic flush
@@ -816,8 +923,15 @@ func (c *UtxoCache) Initialize(ctx context.Context, b *BlockChain) error {
 	log.Infof("UTXO cache initializing (max size: %d MiB)...",
 		c.maxSize/1024/1024)
 
+	// Complete or discard a flush to the UTXO backend that was interrupted
+	// by an unclean shutdown so the utxo set is consistent with its state.
+	err := c.backend.RecoverFlush()
+	if err != nil {
+		return err
+	}
+
 	// Upgrade the UTXO backend as needed.
-	err := c.backend.Upgrade(ctx, b)
+	err = c.backend.Upgrade(ctx, b)
 	if err != nil {
 		return err
 	}
@@ -1084,3 +1198,27 @@ func (b *BlockChain) FetchUtxoStats() (*UtxoStats, error) {
 	tip := b.bestChain.Tip()
 	return b.utxoCache.FetchStats(&tip.hash, uint32(tip.height))
 }
+
+// UtxoCacheFlushStats returns statistics about the flushes of the utxo cache to
+// the utxo backend.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) UtxoCacheFlushStats() UtxoFlushStats {
+	return b.utxoCache.FlushStats()
+}
+
+// ForEachUtxo invokes the provided function with every unspent transaction
+// output in the utxo set ordered by outpoint.  Iteration stops as soon as the
+// function returns an error and that error is returned.
+//
+// The utxo set is iterated as of the main chain tip when the iteration begins,
+// however, blocks that are connected while it is in progress may or may not be
+// reflected.  Callers that require a consistent view should compare the main
+// chain tip before and after the iteration.
+//
+// This function is safe for concurrent access however the entries provided to
+// the function are NOT safe to retain or modify after it returns.
+func (b *BlockChain) ForEachUtxo(fn func(outpoint wire.OutPoint, entry *UtxoEntry) error) error {
+	tip := b.bestChain.Tip()
+	return b.utxoCache.ForEachEntry(&tip.hash, uint32(tip.height), fn)
+}
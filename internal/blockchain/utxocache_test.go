@@ -1138,6 +1138,7 @@ func TestMaybeFlush(t *testing.T) {
 		wantLastEvictionHeight   uint32
 		wantLastFlushHash        *chainhash.Hash
 		wantUpdatedLastFlushTime bool
+		wantFlushedEntries       uint64
 	}{{
 		name:               "flush not required",
 		maxSize:            1000,
@@ -1166,6 +1167,7 @@ func TestMaybeFlush(t *testing.T) {
 		wantLastEvictionHeight:   0,
 		wantLastFlushHash:        block1000Hash,
 		wantUpdatedLastFlushTime: false,
+		wantFlushedEntries:       0,
 	}, {
 		name:               "all entries flushed, some entries evicted",
 		maxSize:            0,
@@ -1199,6 +1201,7 @@ func TestMaybeFlush(t *testing.T) {
 		wantLastEvictionHeight:   300,
 		wantLastFlushHash:        block2000Hash,
 		wantUpdatedLastFlushTime: true,
+		wantFlushedEntries:       3,
 	}}
 
 	for _, test := range tests {
@@ -1284,6 +1287,21 @@ func TestMaybeFlush(t *testing.T) {
 				test.wantLastEvictionHeight)
 		}
 
+		// Validate the flush statistics.
+		stats := utxoCache.FlushStats()
+		var wantFlushes uint64
+		if test.wantUpdatedLastFlushTime {
+			wantFlushes = 1
+		}
+		if stats.Flushes != wantFlushes ||
+			stats.Entries != test.wantFlushedEntries ||
+			stats.LastEntries != test.wantFlushedEntries {
+
+			t.Fatalf("%q: unexpected flush stats -- got %+v, want %d "+
+				"flushes of %d entries", test.name, stats, wantFlushes,
+				test.wantFlushedEntries)
+		}
+
 		// Validate the updated total entry size of the cache.
 		wantTotalEntrySize := uint64(0)
 		for _, entry := range test.wantCachedEntries {
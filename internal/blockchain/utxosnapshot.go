@@ -0,0 +1,138 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// A utxo set snapshot consists of a header that identifies the network and the
+// block the snapshot is of followed by a record for every unspent transaction
+// output in the utxo set ordered by outpoint until the end of the snapshot.
+//
+// The serialized header format is:
+//
+//   <network><version><block hash><block height>
+//
+//   Field                Type              Size
+//   network              wire.CurrencyNet  4 bytes
+//   version              uint32            4 bytes
+//   block hash           chainhash.Hash    chainhash.HashSize
+//   block height         uint32            4 bytes
+//
+// The serialized record format is:
+//
+//   <hash><tree><output index><entry size><entry>
+//
+//   Field                Type              Size
+//   hash                 chainhash.Hash    chainhash.HashSize
+//   tree                 int8              1 byte
+//   output index         uint32            4 bytes
+//   entry size           varint            variable
+//   entry                []byte            variable
+//
+// The entry is the utxo entry serialized with the same format used by the utxo
+// backend.  All integers are little endian.
+//
+// The muhash of the utxo set is calculated over the records without the entry
+// size.
+// -----------------------------------------------------------------------------
+
+// utxoSnapshotVersion is the current version of the utxo set snapshot format.
+const utxoSnapshotVersion = 1
+
+// UtxoSnapshotInfo describes a utxo set snapshot.
+type UtxoSnapshotInfo struct {
+	// BlockHash and BlockHeight identify the main chain block the snapshot is
+	// of.
+	BlockHash   chainhash.Hash
+	BlockHeight int64
+
+	// Utxos is the number of unspent transaction outputs in the snapshot.
+	Utxos int64
+
+	// MuHash is the muhash of the utxo set.
+	MuHash chainhash.Hash
+}
+
+// putUtxoMuHashData serializes the provided outpoint followed by the
+// serialized utxo entry into the provided buffer as described by the utxo set
+// snapshot format and returns the result.  The buffer is grown as needed.
+func putUtxoMuHashData(buf []byte, outpoint wire.OutPoint, serializedUtxo []byte) []byte {
+	buf = append(buf[:0], outpoint.Hash[:]...)
+	buf = append(buf, byte(outpoint.Tree))
+	var idx [4]byte
+	binary.LittleEndian.PutUint32(idx[:], outpoint.Index)
+	buf = append(buf, idx[:]...)
+	return append(buf, serializedUtxo...)
+}
+
+// DumpUtxoSet writes a snapshot of the utxo set as of the current main chain
+// tip to the provided writer and returns information about it.  The format of
+// the snapshot is described in detail above.
+//
+// Blocks are NOT connected or disconnected while the snapshot is written in
+// order to ensure it is consistent with the block it is of.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) DumpUtxoSet(w io.Writer) (*UtxoSnapshotInfo, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	tip := b.bestChain.Tip()
+	info := &UtxoSnapshotInfo{
+		BlockHash:   tip.hash,
+		BlockHeight: tip.height,
+	}
+
+	bw := bufio.NewWriter(w)
+	var header [12 + chainhash.HashSize]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(b.chainParams.Net))
+	binary.LittleEndian.PutUint32(header[4:8], utxoSnapshotVersion)
+	copy(header[8:], tip.hash[:])
+	binary.LittleEndian.PutUint32(header[8+chainhash.HashSize:],
+		uint32(tip.height))
+	if _, err := bw.Write(header[:]); err != nil {
+		return nil, err
+	}
+
+	muHash := chainhash.NewMuHash()
+	var record []byte
+	err := b.utxoCache.ForEachEntry(&tip.hash, uint32(tip.height),
+		func(outpoint wire.OutPoint, entry *UtxoEntry) error {
+			serialized := serializeUtxoEntry(entry)
+			record = putUtxoMuHashData(record, outpoint, serialized)
+			muHash.Add(record)
+
+			const outpointSize = chainhash.HashSize + 5
+			if _, err := bw.Write(record[:outpointSize]); err != nil {
+				return err
+			}
+			err := wire.WriteVarInt(bw, 0, uint64(len(serialized)))
+			if err != nil {
+				return err
+			}
+			if _, err := bw.Write(serialized); err != nil {
+				return err
+			}
+			info.Utxos++
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+
+	info.MuHash = muHash.Finalize()
+	return info, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestDumpUtxoSet ensures dumping the utxo set produces a snapshot that is
+// consistent with the utxo set statistics and contains every unspent output.
+func TestDumpUtxoSet(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g := newChaingenHarness(t, params)
+
+	// Generate and accept enough blocks to reach stake validation height so
+	// the utxo set contains a variety of outputs.
+	g.AdvanceToStakeValidationHeight()
+	for i := 0; i < 2; i++ {
+		g.NextBlock(fmt.Sprintf("bsvh%d", i), nil, nil)
+		g.AcceptTipBlock()
+	}
+
+	var buf bytes.Buffer
+	info, err := g.chain.DumpUtxoSet(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error dumping utxo set: %v", err)
+	}
+	best := g.chain.BestSnapshot()
+	if info.BlockHash != best.Hash || info.BlockHeight != best.Height {
+		t.Fatalf("unexpected snapshot block -- got %v (%d), want %v (%d)",
+			info.BlockHash, info.BlockHeight, best.Hash, best.Height)
+	}
+	stats, err := g.chain.FetchUtxoStats()
+	if err != nil {
+		t.Fatalf("unexpected error fetching utxo stats: %v", err)
+	}
+	if info.Utxos != stats.Utxos || info.MuHash != stats.MuHash {
+		t.Fatalf("snapshot does not match utxo stats -- got %d utxos with "+
+			"muhash %v, want %d utxos with muhash %v", info.Utxos,
+			info.MuHash, stats.Utxos, stats.MuHash)
+	}
+
+	// Ensure the header identifies the network and best block.
+	snapshot := buf.Bytes()
+	const headerSize = 12 + chainhash.HashSize
+	if len(snapshot) < headerSize {
+		t.Fatalf("snapshot is too short: %d bytes", len(snapshot))
+	}
+	net := wire.CurrencyNet(binary.LittleEndian.Uint32(snapshot[0:4]))
+	version := binary.LittleEndian.Uint32(snapshot[4:8])
+	height := binary.LittleEndian.Uint32(snapshot[8+chainhash.HashSize:])
+	if net != params.Net || version != utxoSnapshotVersion ||
+		!bytes.Equal(snapshot[8:8+chainhash.HashSize], best.Hash[:]) ||
+		int64(height) != best.Height {
+
+		t.Fatalf("unexpected snapshot header %x", snapshot[:headerSize])
+	}
+
+	// Ensure every record decodes to an unspent output in the utxo set and
+	// that the records produce the same muhash.
+	r := bytes.NewReader(snapshot[headerSize:])
+	muHash := chainhash.NewMuHash()
+	var numUtxos int64
+	for r.Len() > 0 {
+		var outpoint wire.OutPoint
+		var tree [1]byte
+		var idx [4]byte
+		if _, err := r.Read(outpoint.Hash[:]); err != nil {
+			t.Fatalf("unable to read record hash: %v", err)
+		}
+		if _, err := r.Read(tree[:]); err != nil {
+			t.Fatalf("unable to read record tree: %v", err)
+		}
+		if _, err := r.Read(idx[:]); err != nil {
+			t.Fatalf("unable to read record index: %v", err)
+		}
+		outpoint.Tree = int8(tree[0])
+		outpoint.Index = binary.LittleEndian.Uint32(idx[:])
+		size, err := wire.ReadVarInt(r, 0)
+		if err != nil {
+			t.Fatalf("unable to read record entry size: %v", err)
+		}
+		serialized := make([]byte, size)
+		if _, err := r.Read(serialized); err != nil {
+			t.Fatalf("unable to read record entry: %v", err)
+		}
+		entry, err := deserializeUtxoEntry(serialized, outpoint.Index)
+		if err != nil {
+			t.Fatalf("unable to deserialize entry for %v: %v", outpoint, err)
+		}
+		wantEntry, err := g.chain.FetchUtxoEntry(outpoint)
+		if err != nil || wantEntry == nil {
+			t.Fatalf("unable to fetch entry for %v: %v", outpoint, err)
+		}
+		if entry.Amount() != wantEntry.Amount() ||
+			!bytes.Equal(entry.PkScript(), wantEntry.PkScript()) {
+
+			t.Fatalf("mismatched entry for %v", outpoint)
+		}
+		muHash.Add(putUtxoMuHashData(nil, outpoint, serialized))
+		numUtxos++
+	}
+	if numUtxos != info.Utxos || muHash.Finalize() != info.MuHash {
+		t.Fatalf("snapshot records do not match -- got %d utxos, want %d",
+			numUtxos, info.Utxos)
+	}
+}
@@ -668,15 +668,17 @@ func standaloneToChainRuleError(err error) error {
 }
 
 // checkProofOfWork ensures the block header bits which indicate the target
-// difficulty is in min/max range and that the block hash is less than the
-// target difficulty as claimed.
+// difficulty is in min/max range and that the proof of work hash of the block
+// header, as calculated by the proof of work hash algorithm of the network, is
+// less than the target difficulty as claimed.
 //
 // The flags modify the behavior of this function as follows:
-//   - BFNoPoWCheck: The check to ensure the block hash is less than the target
-//     difficulty is not performed.
-func checkProofOfWork(header *wire.BlockHeader, powLimit *big.Int, flags BehaviorFlags) error {
+//   - BFNoPoWCheck: The check to ensure the proof of work hash is less than the
+//     target difficulty is not performed.
+func checkProofOfWork(header *wire.BlockHeader, chainParams *chaincfg.Params, flags BehaviorFlags) error {
 	// Only ensure the target difficulty bits are in the valid range when the
 	// the flag to avoid proof of work checks is set.
+	powLimit := chainParams.PowLimit
 	if flags&BFNoPoWCheck == BFNoPoWCheck {
 		err := standalone.CheckProofOfWorkRange(header.Bits, powLimit)
 		return standaloneToChainRuleError(err)
@@ -686,9 +688,9 @@ func checkProofOfWork(header *wire.BlockHeader, powLimit *big.Int, flags Behavio
 	//
 	// - The target difficulty must be larger than zero.
 	// - The target difficulty must be less than the maximum allowed.
-	// - The block hash must be less than the claimed target.
-	blockHash := header.BlockHash()
-	err := standalone.CheckProofOfWork(&blockHash, header.Bits, powLimit)
+	// - The proof of work hash must be less than the claimed target.
+	powHash := chainParams.PowHash(header)
+	err := standalone.CheckProofOfWork(&powHash, header.Bits, powLimit)
 	return standaloneToChainRuleError(err)
 }
 
@@ -712,7 +714,7 @@ func checkBlockHeaderSanity(header *wire.BlockHeader, timeSource MedianTimeSourc
 	// Ensure the proof of work bits in the block header is in min/max
 	// range and the block hash is less than the target value described by
 	// the bits.
-	err := checkProofOfWork(header, chainParams.PowLimit, flags)
+	err := checkProofOfWork(header, chainParams, flags)
 	if err != nil {
 		return err
 	}
@@ -908,6 +910,15 @@ func checkBlockSanity(block *dcrutil.Block, timeSource MedianTimeSource, flags B
 		existingTxHashes[*hash] = struct{}{}
 	}
 
+	// Blocks other than the genesis block must be signed in a way that
+	// satisfies the block signing challenge of networks that have one.
+	if len(chainParams.BlockSignChallenge) != 0 && header.Height != 0 {
+		err := checkBlockSig(msgBlock, chainParams.BlockSignChallenge)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
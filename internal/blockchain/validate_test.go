@@ -274,6 +274,42 @@ func TestCheckBlockSanity(t *testing.T) {
 	}
 }
 
+// maxPowHasher is a proof of work hash algorithm for testing that always
+// produces the maximum possible hash.
+type maxPowHasher struct{}
+
+func (maxPowHasher) Name() string {
+	return "max"
+}
+
+func (maxPowHasher) PowHash([]byte) chainhash.Hash {
+	var hash chainhash.Hash
+	for i := range hash {
+		hash[i] = 0xff
+	}
+	return hash
+}
+
+// TestCheckProofOfWorkHasher ensures the proof of work check uses the proof of
+// work hash algorithm of the network.
+func TestCheckProofOfWorkHasher(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	header := &params.GenesisBlock.Header
+	if err := checkProofOfWork(header, params, BFNone); err != nil {
+		t.Fatalf("unexpected error with the default algorithm: %v", err)
+	}
+
+	params.PowHasher = maxPowHasher{}
+	err := checkProofOfWork(header, params, BFNone)
+	if !errors.Is(err, ErrHighHash) {
+		t.Fatalf("unexpected error with alternative algorithm -- got %v, "+
+			"want %v", err, ErrHighHash)
+	}
+	if err := checkProofOfWork(header, params, BFNoPoWCheck); err != nil {
+		t.Fatalf("unexpected error without pow check: %v", err)
+	}
+}
+
 // TestCheckBlockHeaderContext tests that genesis block passes context headers
 // because its parent is nil.
 func TestCheckBlockHeaderContext(t *testing.T) {
@@ -0,0 +1,280 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmpctblock
+
+import (
+	"errors"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// Version is the version of compact block relay implemented by this package.
+const Version = 1
+
+var (
+	// ErrMalformedCmpctBlock indicates a compact block is not valid, such as
+	// when it contains duplicate or out of range prefilled transaction
+	// indexes.
+	ErrMalformedCmpctBlock = errors.New("malformed compact block")
+
+	// ErrUnexpectedBlockTxn indicates the transactions provided to fill a
+	// partial block are not the ones that were requested.
+	ErrUnexpectedBlockTxn = errors.New("unexpected block transactions")
+
+	// ErrIncompleteBlock indicates an attempt to obtain the block from a
+	// partial block that is still missing transactions.
+	ErrIncompleteBlock = errors.New("block is missing transactions")
+
+	// ErrMerkleRootMismatch indicates the merkle roots of a reconstructed
+	// block do not match its header.  This is typically the result of a
+	// short id collision.
+	ErrMerkleRootMismatch = errors.New("reconstructed block merkle roots " +
+		"do not match the header")
+)
+
+// NewMsgCmpctBlock returns a compact block for the provided block keyed by the
+// passed nonce.  The coinbase, treasurybase, and votes are prefilled while all
+// other transactions are identified by their short ids.
+func NewMsgCmpctBlock(block *wire.MsgBlock, nonce uint64) *wire.MsgCmpctBlock {
+	msg := wire.NewMsgCmpctBlock(&block.Header, nonce)
+	k0, k1 := shortIDKeys(&block.Header, nonce)
+	for i, tx := range block.Transactions {
+		if i == 0 {
+			msg.PrefilledTxns = append(msg.PrefilledTxns, wire.PrefilledTx{
+				Index: uint32(i),
+				Tx:    tx,
+			})
+			continue
+		}
+		txHash := tx.TxHash()
+		msg.ShortIDs = append(msg.ShortIDs, shortID(k0, k1, &txHash))
+	}
+	for i, stx := range block.STransactions {
+		if stake.IsSSGen(stx) || stake.IsTreasuryBase(stx) {
+			msg.PrefilledSTxns = append(msg.PrefilledSTxns, wire.PrefilledTx{
+				Index: uint32(i),
+				Tx:    stx,
+			})
+			continue
+		}
+		txHash := stx.TxHash()
+		msg.SShortIDs = append(msg.SShortIDs, shortID(k0, k1, &txHash))
+	}
+	return msg
+}
+
+// partialTree houses the transactions of a transaction tree of a block that is
+// being reconstructed from a compact block.
+type partialTree struct {
+	// txns houses the transactions of the tree in order.  Transactions that
+	// are not yet known are nil.
+	txns []*wire.MsgTx
+
+	// slots maps the short ids in the compact block to the index of the
+	// transaction they identify.  Short ids that identify more than one
+	// transaction are mapped to -1 so the transactions are requested from
+	// the sender instead.
+	slots map[uint64]int
+}
+
+// newPartialTree returns a partial transaction tree from the provided short ids
+// and prefilled transactions of a compact block.
+func newPartialTree(shortIDs []uint64, prefilled []wire.PrefilledTx) (*partialTree, error) {
+	numTxns := len(shortIDs) + len(prefilled)
+	t := &partialTree{
+		txns:  make([]*wire.MsgTx, numTxns),
+		slots: make(map[uint64]int, len(shortIDs)),
+	}
+	for _, ptx := range prefilled {
+		if int(ptx.Index) >= numTxns || ptx.Tx == nil ||
+			t.txns[ptx.Index] != nil {
+
+			return nil, ErrMalformedCmpctBlock
+		}
+		t.txns[ptx.Index] = ptx.Tx
+	}
+
+	// The short ids identify the remaining transactions in order.
+	var index int
+	for _, id := range shortIDs {
+		for t.txns[index] != nil {
+			index++
+		}
+		if _, ok := t.slots[id]; ok {
+			t.slots[id] = -1
+		} else {
+			t.slots[id] = index
+		}
+		index++
+	}
+	return t, nil
+}
+
+// add adds the provided transaction with the given short id to the tree when
+// the short id identifies one of its transactions.  A transaction is not added
+// and is instead treated as missing when more than one transaction has the
+// same short id.
+func (t *partialTree) add(id uint64, tx *wire.MsgTx) {
+	index, ok := t.slots[id]
+	if !ok || index == -1 {
+		return
+	}
+	if t.txns[index] != nil {
+		t.txns[index] = nil
+		t.slots[id] = -1
+		return
+	}
+	t.txns[index] = tx
+}
+
+// missing returns the indexes of the transactions of the tree that are not yet
+// known.
+func (t *partialTree) missing() []uint32 {
+	missing := make([]uint32, 0)
+	for i, tx := range t.txns {
+		if tx == nil {
+			missing = append(missing, uint32(i))
+		}
+	}
+	return missing
+}
+
+// fill sets the transactions at the provided indexes of the tree to the passed
+// transactions.
+func (t *partialTree) fill(indexes []uint32, txns []*wire.MsgTx) error {
+	if len(indexes) != len(txns) {
+		return ErrUnexpectedBlockTxn
+	}
+	for i, index := range indexes {
+		if txns[i] == nil {
+			return ErrUnexpectedBlockTxn
+		}
+		t.txns[index] = txns[i]
+	}
+	return nil
+}
+
+// PartialBlock houses a block that is being reconstructed from a compact block
+// and the transactions in the memory pool.
+type PartialBlock struct {
+	header  wire.BlockHeader
+	regular *partialTree
+	stake   *partialTree
+}
+
+// NewPartialBlock returns a partial block for the provided compact block with
+// any of its transactions that are in the passed memory pool transactions
+// filled in.
+//
+// ErrMalformedCmpctBlock is returned when the compact block is not valid.
+func NewPartialBlock(msg *wire.MsgCmpctBlock, mempoolTxns []*wire.MsgTx) (*PartialBlock, error) {
+	regular, err := newPartialTree(msg.ShortIDs, msg.PrefilledTxns)
+	if err != nil {
+		return nil, err
+	}
+	stake, err := newPartialTree(msg.SShortIDs, msg.PrefilledSTxns)
+	if err != nil {
+		return nil, err
+	}
+
+	k0, k1 := shortIDKeys(&msg.Header, msg.Nonce)
+	for _, tx := range mempoolTxns {
+		txHash := tx.TxHash()
+		id := shortID(k0, k1, &txHash)
+		regular.add(id, tx)
+		stake.add(id, tx)
+	}
+
+	return &PartialBlock{
+		header:  msg.Header,
+		regular: regular,
+		stake:   stake,
+	}, nil
+}
+
+// Hash returns the hash of the block.
+func (b *PartialBlock) Hash() chainhash.Hash {
+	return b.header.BlockHash()
+}
+
+// Header returns the header of the block.
+func (b *PartialBlock) Header() *wire.BlockHeader {
+	return &b.header
+}
+
+// Missing returns a getblocktxn message that requests the transactions of the
+// block that are not yet known.  It returns nil when the block is complete.
+func (b *PartialBlock) Missing() *wire.MsgGetBlockTxn {
+	missing := b.regular.missing()
+	missingStake := b.stake.missing()
+	if len(missing) == 0 && len(missingStake) == 0 {
+		return nil
+	}
+	blockHash := b.header.BlockHash()
+	msg := wire.NewMsgGetBlockTxn(&blockHash)
+	msg.TxIndexes = missing
+	msg.STxIndexes = missingStake
+	return msg
+}
+
+// Fill fills in the missing transactions of the block with the transactions in
+// the provided blocktxn message.
+//
+// ErrUnexpectedBlockTxn is returned when the message is not for the block or
+// does not contain exactly the missing transactions.
+func (b *PartialBlock) Fill(msg *wire.MsgBlockTxn) error {
+	if msg.BlockHash != b.header.BlockHash() {
+		return ErrUnexpectedBlockTxn
+	}
+	missing := b.regular.missing()
+	missingStake := b.stake.missing()
+	if len(msg.Transactions) != len(missing) ||
+		len(msg.STransactions) != len(missingStake) {
+
+		return ErrUnexpectedBlockTxn
+	}
+	if err := b.regular.fill(missing, msg.Transactions); err != nil {
+		return err
+	}
+	return b.stake.fill(missingStake, msg.STransactions)
+}
+
+// Block returns the reconstructed block.
+//
+// ErrIncompleteBlock is returned when the block is still missing transactions
+// and ErrMerkleRootMismatch is returned when the merkle roots of the
+// reconstructed block do not match its header.
+func (b *PartialBlock) Block() (*wire.MsgBlock, error) {
+	if b.Missing() != nil {
+		return nil, ErrIncompleteBlock
+	}
+
+	block := &wire.MsgBlock{
+		Header:        b.header,
+		Transactions:  b.regular.txns,
+		STransactions: b.stake.txns,
+	}
+
+	// The header either commits to the combined merkle root of both trees
+	// or to the merkle roots of the individual trees depending on whether
+	// or not the header commitments agenda is active.  Accept either form
+	// here since the full contextual check is performed when the block is
+	// processed.
+	header := &block.Header
+	combinedRoot := standalone.CalcCombinedTxTreeMerkleRoot(
+		block.Transactions, block.STransactions)
+	if header.MerkleRoot == combinedRoot {
+		return block, nil
+	}
+	merkleRoot := standalone.CalcTxTreeMerkleRoot(block.Transactions)
+	stakeRoot := standalone.CalcTxTreeMerkleRoot(block.STransactions)
+	if header.MerkleRoot != merkleRoot || header.StakeRoot != stakeRoot {
+		return nil, ErrMerkleRootMismatch
+	}
+	return block, nil
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmpctblock
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/standalone/v2"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// testTx returns a unique transaction for use in tests.
+func testTx(n uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, n,
+		wire.TxTreeRegular), 0, nil))
+	tx.AddTxOut(wire.NewTxOut(int64(n), []byte{0x51}))
+	tx.LockTime = n
+	return tx
+}
+
+// testBlock returns a block with the provided number of regular and stake
+// transactions that commits to the combined merkle root of its transaction
+// trees when combined is true and the individual merkle roots otherwise.
+func testBlock(numTxns, numSTxns int, combined bool) *wire.MsgBlock {
+	block := &wire.MsgBlock{Header: wire.BlockHeader{Height: 1000}}
+	for i := 0; i < numTxns; i++ {
+		block.Transactions = append(block.Transactions, testTx(uint32(i)))
+	}
+	for i := 0; i < numSTxns; i++ {
+		block.STransactions = append(block.STransactions,
+			testTx(uint32(1000+i)))
+	}
+	if combined {
+		block.Header.MerkleRoot = standalone.CalcCombinedTxTreeMerkleRoot(
+			block.Transactions, block.STransactions)
+	} else {
+		block.Header.MerkleRoot = standalone.CalcTxTreeMerkleRoot(
+			block.Transactions)
+		block.Header.StakeRoot = standalone.CalcTxTreeMerkleRoot(
+			block.STransactions)
+	}
+	return block
+}
+
+// TestReconstruct ensures blocks are reconstructed from compact blocks and the
+// transactions in the memory pool as expected, including requesting and
+// filling in any missing transactions.
+func TestReconstruct(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		combined     bool
+		mempool      func(block *wire.MsgBlock) []*wire.MsgTx
+		wantMissing  []uint32
+		wantSMissing []uint32
+	}{{
+		name:     "all transactions in mempool",
+		combined: true,
+		mempool: func(block *wire.MsgBlock) []*wire.MsgTx {
+			txns := append([]*wire.MsgTx{testTx(5000)},
+				block.Transactions[1:]...)
+			return append(txns, block.STransactions...)
+		},
+	}, {
+		name:     "all transactions in mempool with individual roots",
+		combined: false,
+		mempool: func(block *wire.MsgBlock) []*wire.MsgTx {
+			txns := append([]*wire.MsgTx{}, block.Transactions[1:]...)
+			return append(txns, block.STransactions...)
+		},
+	}, {
+		name:     "missing transactions",
+		combined: true,
+		mempool: func(block *wire.MsgBlock) []*wire.MsgTx {
+			return []*wire.MsgTx{block.Transactions[2]}
+		},
+		wantMissing:  []uint32{1, 3},
+		wantSMissing: []uint32{0, 1},
+	}, {
+		name:         "empty mempool",
+		combined:     true,
+		mempool:      func(block *wire.MsgBlock) []*wire.MsgTx { return nil },
+		wantMissing:  []uint32{1, 2, 3},
+		wantSMissing: []uint32{0, 1},
+	}}
+
+	for _, test := range tests {
+		block := testBlock(4, 2, test.combined)
+		msg := NewMsgCmpctBlock(block, 0x0102030405060708)
+		if len(msg.PrefilledTxns) != 1 || msg.PrefilledTxns[0].Tx !=
+			block.Transactions[0] {
+
+			t.Errorf("%q: coinbase not prefilled", test.name)
+			continue
+		}
+
+		partial, err := NewPartialBlock(msg, test.mempool(block))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if partial.Hash() != block.BlockHash() {
+			t.Errorf("%q: unexpected hash -- got %v, want %v", test.name,
+				partial.Hash(), block.BlockHash())
+			continue
+		}
+
+		// Ensure the expected transactions are requested and the missing
+		// transactions are filled in.
+		getBlockTxn := partial.Missing()
+		if test.wantMissing != nil || test.wantSMissing != nil {
+			if getBlockTxn == nil {
+				t.Errorf("%q: no missing transactions", test.name)
+				continue
+			}
+			if !reflect.DeepEqual(getBlockTxn.TxIndexes, test.wantMissing) ||
+				!reflect.DeepEqual(getBlockTxn.STxIndexes,
+					test.wantSMissing) {
+
+				t.Errorf("%q: unexpected missing transactions -- got %v/%v, "+
+					"want %v/%v", test.name, getBlockTxn.TxIndexes,
+					getBlockTxn.STxIndexes, test.wantMissing,
+					test.wantSMissing)
+				continue
+			}
+			if _, err := partial.Block(); !errors.Is(err, ErrIncompleteBlock) {
+				t.Errorf("%q: unexpected error for incomplete block: %v",
+					test.name, err)
+				continue
+			}
+
+			blockTxn := wire.NewMsgBlockTxn(&getBlockTxn.BlockHash)
+			for _, index := range getBlockTxn.TxIndexes {
+				blockTxn.Transactions = append(blockTxn.Transactions,
+					block.Transactions[index])
+			}
+			for _, index := range getBlockTxn.STxIndexes {
+				blockTxn.STransactions = append(blockTxn.STransactions,
+					block.STransactions[index])
+			}
+			if err := partial.Fill(blockTxn); err != nil {
+				t.Errorf("%q: unexpected error filling block: %v",
+					test.name, err)
+				continue
+			}
+		} else if getBlockTxn != nil {
+			t.Errorf("%q: unexpected missing transactions %v/%v", test.name,
+				getBlockTxn.TxIndexes, getBlockTxn.STxIndexes)
+			continue
+		}
+
+		gotBlock, err := partial.Block()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(gotBlock, block) {
+			t.Errorf("%q: mismatched reconstructed block", test.name)
+			continue
+		}
+	}
+}
+
+// TestReconstructErrors ensures malformed compact blocks, unexpected block
+// transactions, and reconstructed blocks that do not match their header are
+// detected.
+func TestReconstructErrors(t *testing.T) {
+	t.Parallel()
+
+	// Ensure duplicate and out of range prefilled indexes are rejected.
+	block := testBlock(3, 1, true)
+	msg := NewMsgCmpctBlock(block, 1)
+	dupMsg := *msg
+	dupMsg.PrefilledTxns = []wire.PrefilledTx{msg.PrefilledTxns[0],
+		msg.PrefilledTxns[0]}
+	if _, err := NewPartialBlock(&dupMsg, nil); !errors.Is(err,
+		ErrMalformedCmpctBlock) {
+
+		t.Fatalf("unexpected error for duplicate prefilled index: %v", err)
+	}
+	rangeMsg := *msg
+	rangeMsg.PrefilledSTxns = []wire.PrefilledTx{{Index: 2,
+		Tx: block.STransactions[0]}}
+	if _, err := NewPartialBlock(&rangeMsg, nil); !errors.Is(err,
+		ErrMalformedCmpctBlock) {
+
+		t.Fatalf("unexpected error for out of range prefilled index: %v",
+			err)
+	}
+
+	// Ensure block transactions for another block or with the wrong number
+	// of transactions are rejected.
+	partial, err := NewPartialBlock(msg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	blockTxn := wire.NewMsgBlockTxn(&chainhash.Hash{})
+	blockTxn.Transactions = block.Transactions[1:]
+	blockTxn.STransactions = block.STransactions
+	if err := partial.Fill(blockTxn); !errors.Is(err, ErrUnexpectedBlockTxn) {
+		t.Fatalf("unexpected error for wrong block hash: %v", err)
+	}
+	blockTxn.BlockHash = block.BlockHash()
+	blockTxn.Transactions = block.Transactions[1:2]
+	if err := partial.Fill(blockTxn); !errors.Is(err, ErrUnexpectedBlockTxn) {
+		t.Fatalf("unexpected error for wrong number of txns: %v", err)
+	}
+
+	// Ensure a reconstructed block with the wrong transactions is detected.
+	blockTxn.Transactions = []*wire.MsgTx{block.Transactions[2],
+		block.Transactions[1]}
+	if err := partial.Fill(blockTxn); err != nil {
+		t.Fatalf("unexpected error filling block: %v", err)
+	}
+	if _, err := partial.Block(); !errors.Is(err, ErrMerkleRootMismatch) {
+		t.Fatalf("unexpected error for mismatched merkle root: %v", err)
+	}
+}
+
+// TestShortIDCollisions ensures transactions whose short ids collide are
+// treated as missing.
+func TestShortIDCollisions(t *testing.T) {
+	t.Parallel()
+
+	// Duplicate short ids in the compact block.
+	tree, err := newPartialTree([]uint64{1, 1, 2}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree.add(1, testTx(1))
+	tree.add(2, testTx(2))
+	if got, want := tree.missing(), []uint32{0, 1}; !reflect.DeepEqual(got,
+		want) {
+
+		t.Fatalf("unexpected missing txns -- got %v, want %v", got, want)
+	}
+
+	// Multiple memory pool transactions with the same short id.
+	tree, err = newPartialTree([]uint64{1, 2}, []wire.PrefilledTx{{Index: 1,
+		Tx: testTx(0)}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree.add(1, testTx(1))
+	tree.add(1, testTx(2))
+	tree.add(1, testTx(3))
+	tree.add(2, testTx(4))
+	if got, want := tree.missing(), []uint32{0}; !reflect.DeepEqual(got,
+		want) {
+
+		t.Fatalf("unexpected missing txns -- got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package cmpctblock implements compact block relay between peers.
+
+Rather than relaying every block in full, peers that both support compact
+blocks relay new blocks as the block header along with short ids that identify
+the transactions in each of the transaction trees of the block.  Since peers
+that are synced to the network almost always already have the vast majority of
+the transactions of a new block in their memory pool, they are typically able
+to reconstruct the block without any further round trips, which significantly
+reduces both the bandwidth and the latency of block propagation.
+
+Transactions are identified by 48-bit short ids which are keyed by the block
+header and a random nonce chosen by the sender to prevent an attacker from
+precomputing collisions.
+
+The coinbase, the treasurybase, and the votes of the block are always
+prefilled in full since they are either not relayed beforehand or are likely
+to differ from the versions in the memory pool of the receiver.
+
+A compact block is relayed as follows:
+
+ 1. The sender announces the block via a cmpctblock message to each peer that
+    requested high-bandwidth compact block announcements via a sendcmpct
+    message.
+ 2. The receiver attempts to reconstruct the block from the prefilled
+    transactions and the transactions in its memory pool.
+ 3. When any transactions are missing, the receiver requests them by their
+    index via a getblocktxn message and the sender replies with a blocktxn
+    message that contains them.
+ 4. The receiver ensures the merkle roots of the reconstructed block match the
+    header and falls back to requesting the full block when they do not, which
+    may happen in the case of short id collisions.
+*/
+package cmpctblock
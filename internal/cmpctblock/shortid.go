@@ -0,0 +1,36 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cmpctblock
+
+import (
+	"encoding/binary"
+
+	"github.com/dchest/siphash"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// shortIDKeyTag is the tag that is prepended to the block hash and nonce when
+// deriving the short id keys for a compact block.
+const shortIDKeyTag = "cmpctblock shortid"
+
+// shortIDKeys derives the keys used to compute transaction short ids for a
+// compact block from the block header and the nonce chosen by the sender.
+func shortIDKeys(header *wire.BlockHeader, nonce uint64) (uint64, uint64) {
+	blockHash := header.BlockHash()
+	var b [len(shortIDKeyTag) + chainhash.HashSize + 8]byte
+	copy(b[:], shortIDKeyTag)
+	copy(b[len(shortIDKeyTag):], blockHash[:])
+	binary.LittleEndian.PutUint64(b[len(shortIDKeyTag)+chainhash.HashSize:],
+		nonce)
+	h := chainhash.HashH(b[:])
+	return binary.LittleEndian.Uint64(h[0:8]), binary.LittleEndian.Uint64(h[8:16])
+}
+
+// shortID returns the short id for the provided transaction hash using the
+// passed keys.
+func shortID(k0, k1 uint64, txHash *chainhash.Hash) uint64 {
+	return siphash.Hash(k0, k1, txHash[:]) & wire.MaxCmpctBlockShortID
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package diagnostics provides an authenticated HTTP server that exposes runtime
+profiling and diagnostics data so performance problems of production nodes can
+be investigated without rebuilding or restarting them.
+
+The following paths are served:
+
+  - /debug/pprof/ serves the profiles of the net/http/pprof package, such as
+    CPU profiles at /debug/pprof/profile, heap profiles at /debug/pprof/heap,
+    and execution traces at /debug/pprof/trace, which can be analyzed with
+    go tool pprof and go tool trace
+  - /debug/runtime serves the metrics provided by the runtime/metrics package
+    along with the number of goroutines in plain text
+  - /debug/goroutines serves the stack traces of all goroutines in plain text
+
+Since profiles expose sensitive details about the process, such as its command
+line, all requests must provide the configured credentials via HTTP basic
+authentication.  The server may additionally be configured with TLS, including
+the verification of client certificates in place of credentials.
+*/
+package diagnostics
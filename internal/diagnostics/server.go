@@ -0,0 +1,234 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimemetrics "runtime/metrics"
+	runtimepprof "runtime/pprof"
+	"sync"
+	"time"
+)
+
+const (
+	// readHeaderTimeout is the maximum amount of time allowed to read the
+	// headers of requests.  Note that there is no write timeout since CPU
+	// profiles and execution traces are collected for the duration requested
+	// by the client.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout is the maximum amount of time allowed for in-flight
+	// requests to complete when the server shuts down.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Config is a descriptor containing the diagnostics server configuration.
+type Config struct {
+	// Listeners defines a slice of listeners for which the diagnostics server
+	// will take ownership of and accept connections.  Since the diagnostics
+	// server takes ownership of these listeners, they will be closed when the
+	// diagnostics server is stopped.
+	Listeners []net.Listener
+
+	// TLSConfig is the optional TLS configuration used to serve connections
+	// on the listeners.
+	TLSConfig *tls.Config
+
+	// Username and Password are the credentials all requests must provide via
+	// HTTP basic authentication.  They may only be empty when the TLS
+	// configuration requires and verifies client certificates.
+	Username string
+	Password string
+}
+
+// Server provides an HTTP server that serves runtime profiling and diagnostics
+// data to authenticated clients.
+type Server struct {
+	cfg         Config
+	httpServer  http.Server
+	requireAuth bool
+	authsha     [sha256.Size]byte
+}
+
+// authSHA returns the SHA256 hash of the HTTP basic authorization value for
+// the provided credentials.
+func authSHA(user, pass string) [sha256.Size]byte {
+	login := user + ":" + pass
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	return sha256.Sum256([]byte(auth))
+}
+
+// New returns a new instance of the Server struct with the provided config.
+// An error is returned when the config does not require clients to
+// authenticate.
+func New(cfg *Config) (*Server, error) {
+	s := &Server{cfg: *cfg}
+	switch {
+	case cfg.Username != "" && cfg.Password != "":
+		s.authsha = authSHA(cfg.Username, cfg.Password)
+		s.requireAuth = true
+
+	case cfg.TLSConfig == nil ||
+		cfg.TLSConfig.ClientAuth != tls.RequireAndVerifyClientCert:
+		return nil, errors.New("the diagnostics server requires " +
+			"credentials or verified TLS client certificates")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.RedirectHandler("/debug/pprof/", http.StatusSeeOther))
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", handleRuntime)
+	mux.HandleFunc("/debug/goroutines", handleGoroutines)
+	s.httpServer = http.Server{
+		Handler:           s.authenticate(mux),
+		TLSConfig:         cfg.TLSConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return s, nil
+}
+
+// authenticate returns a handler that ensures requests provide valid
+// credentials prior to invoking the provided handler when authentication is
+// required.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.requireAuth {
+			authsha := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+			if subtle.ConstantTimeCompare(authsha[:], s.authsha[:]) != 1 {
+				log.Warnf("Diagnostics authentication failure from %s",
+					r.RemoteAddr)
+				w.Header().Set("WWW-Authenticate",
+					`Basic realm="dcrd diagnostics"`)
+				http.Error(w, "401 Unauthorized.", http.StatusUnauthorized)
+				return
+			}
+		}
+		log.Debugf("Serving %s to %s", r.URL.Path, r.RemoteAddr)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// histogramQuantile returns an approximation of the provided quantile of the
+// provided runtime histogram based on the boundaries of its buckets.
+func histogramQuantile(h *runtimemetrics.Float64Histogram, count uint64, q float64) float64 {
+	target := uint64(math.Ceil(q * float64(count)))
+	var cumulative uint64
+	for i, n := range h.Counts {
+		cumulative += n
+		if n == 0 || cumulative < target {
+			continue
+		}
+		// Use the upper boundary of the bucket unless it is unbounded.
+		if upper := h.Buckets[i+1]; !math.IsInf(upper, 1) {
+			return upper
+		}
+		return h.Buckets[i]
+	}
+	return 0
+}
+
+// handleRuntime serves the metrics provided by the runtime/metrics package
+// along with the number of goroutines in plain text with one metric per line.
+// Histograms are summarized by their total count and approximate quantiles.
+func handleRuntime(w http.ResponseWriter, r *http.Request) {
+	descs := runtimemetrics.All()
+	samples := make([]runtimemetrics.Sample, len(descs))
+	for i := range descs {
+		samples[i].Name = descs[i].Name
+	}
+	runtimemetrics.Read(samples)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "go_version %s\n", runtime.Version())
+	fmt.Fprintf(bw, "goroutines %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(bw, "gomaxprocs %d\n", runtime.GOMAXPROCS(0))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case runtimemetrics.KindUint64:
+			fmt.Fprintf(bw, "%s %d\n", sample.Name, sample.Value.Uint64())
+
+		case runtimemetrics.KindFloat64:
+			fmt.Fprintf(bw, "%s %g\n", sample.Name, sample.Value.Float64())
+
+		case runtimemetrics.KindFloat64Histogram:
+			h := sample.Value.Float64Histogram()
+			var count uint64
+			for _, n := range h.Counts {
+				count += n
+			}
+			fmt.Fprintf(bw, "%s count=%d p50=%g p90=%g p99=%g\n", sample.Name,
+				count, histogramQuantile(h, count, 0.5),
+				histogramQuantile(h, count, 0.9),
+				histogramQuantile(h, count, 0.99))
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		log.Debugf("Failed to write runtime metrics to %s: %v", r.RemoteAddr,
+			err)
+	}
+}
+
+// handleGoroutines serves the stack traces of all goroutines in plain text in
+// the same format as an unrecovered panic.
+func handleGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	const debugAllStacks = 2
+	err := runtimepprof.Lookup("goroutine").WriteTo(w, debugAllStacks)
+	if err != nil {
+		log.Debugf("Failed to write goroutine dump to %s: %v", r.RemoteAddr,
+			err)
+	}
+}
+
+// Run starts the diagnostics server and blocks until the provided context is
+// cancelled.
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, listener := range s.cfg.Listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			log.Infof("Diagnostics server listening on %s", listener.Addr())
+			var err error
+			if s.cfg.TLSConfig != nil {
+				err = s.httpServer.ServeTLS(listener, "", "")
+			} else {
+				err = s.httpServer.Serve(listener)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Diagnostics server on %s stopped: %v",
+					listener.Addr(), err)
+			}
+			log.Tracef("Diagnostics listener done for %s", listener.Addr())
+			wg.Done()
+		}(listener)
+	}
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		shutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("Diagnostics server shutdown: %v", err)
+	}
+	wg.Wait()
+	log.Infof("Diagnostics server shutdown complete")
+}
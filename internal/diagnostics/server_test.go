@@ -0,0 +1,155 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package diagnostics
+
+import (
+	"crypto/tls"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"runtime/metrics"
+	"strings"
+	"testing"
+)
+
+// TestNew ensures creating a server fails unless clients are required to
+// authenticate with either credentials or TLS client certificates.
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{{
+		name: "credentials",
+		cfg:  Config{Username: "user", Password: "pass"},
+	}, {
+		name: "client certificates",
+		cfg: Config{TLSConfig: &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+		}},
+	}, {
+		name:    "no credentials",
+		cfg:     Config{},
+		wantErr: true,
+	}, {
+		name:    "no password",
+		cfg:     Config{Username: "user"},
+		wantErr: true,
+	}, {
+		name:    "unverified client certificates",
+		cfg:     Config{TLSConfig: &tls.Config{}},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		_, err := New(&test.cfg)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: mismatched error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+		}
+	}
+}
+
+// TestAuthentication ensures requests are only served when they provide the
+// configured credentials.
+func TestAuthentication(t *testing.T) {
+	s, err := New(&Config{Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		user, pass string
+		noAuth     bool
+		wantCode   int
+	}{
+		{name: "valid", user: "user", pass: "pass", wantCode: http.StatusOK},
+		{name: "bad password", user: "user", pass: "bad",
+			wantCode: http.StatusUnauthorized},
+		{name: "bad user", user: "bad", pass: "pass",
+			wantCode: http.StatusUnauthorized},
+		{name: "missing", noAuth: true, wantCode: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		if !test.noAuth {
+			req.SetBasicAuth(test.user, test.pass)
+		}
+		rec := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != test.wantCode {
+			t.Errorf("%s: mismatched status -- got %d, want %d", test.name,
+				rec.Code, test.wantCode)
+		}
+	}
+}
+
+// TestEndpoints ensures the runtime metrics and goroutine dumps are served in
+// the expected format.
+func TestEndpoints(t *testing.T) {
+	s, err := New(&Config{Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{{
+		path: "/debug/runtime",
+		want: []string{"\ngoroutines ", "\n/gc/cycles/total:gc-cycles ",
+			"\n/sched/latencies:seconds count="},
+	}, {
+		path: "/debug/goroutines",
+		want: []string{"goroutine ", "TestEndpoints"},
+	}, {
+		path: "/debug/pprof/",
+		want: []string{"heap", "goroutine"},
+	}}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.SetBasicAuth("user", "pass")
+		rec := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: mismatched status -- got %d, want %d", test.path,
+				rec.Code, http.StatusOK)
+		}
+		body := rec.Body.String()
+		for _, want := range test.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: response does not contain %q", test.path, want)
+			}
+		}
+	}
+}
+
+// TestHistogramQuantile ensures quantiles of runtime histograms are
+// approximated by the expected bucket boundaries.
+func TestHistogramQuantile(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{5, 0, 4, 1},
+		Buckets: []float64{0, 1, 2, 3, math.Inf(1)},
+	}
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{q: 0.5, want: 1},
+		{q: 0.6, want: 3},
+		{q: 0.9, want: 3},
+		{q: 0.99, want: 3},
+	}
+	for _, test := range tests {
+		got := histogramQuantile(h, 10, test.q)
+		if got != test.want {
+			t.Errorf("q%v: mismatched quantile -- got %v, want %v", test.q,
+				got, test.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package grpcserver implements the dcrd gRPC API defined by the dcrdrpc package.
+
+The gRPC server is an alternative to the JSON-RPC server for clients that
+prefer strongly-typed bindings generated from the protocol buffer definitions.
+It provides chain queries, transaction submission, and notification streaming
+for blocks and memory pool events.
+
+The server shares its credentials with the JSON-RPC server.  Calls are
+authenticated via HTTP basic authorization provided in the call metadata unless
+no credentials are configured, such as when TLS client certificates are used
+for authentication instead.  Each method is mapped to the JSON-RPC method that
+provides equivalent functionality and clients are only authorized to invoke it
+when they are authorized to invoke the JSON-RPC method.  For example, the
+limited user may submit transactions, but may not subscribe to memory pool
+events.  Calls are also subject to the request limits of the JSON-RPC server.
+*/
+package grpcserver
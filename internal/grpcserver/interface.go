@@ -0,0 +1,96 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package grpcserver
+
+import (
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/wire"
+)
+
+// Chain represents a chain for use with the gRPC server.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type Chain interface {
+	// BestSnapshot returns information about the current best chain block and
+	// related state as of the current point in time.  The returned instance
+	// must be treated as immutable since it is shared by all callers.
+	BestSnapshot() *blockchain.BestState
+
+	// BlockByHash returns the block for the given hash, regardless of whether
+	// the block is part of the main chain or not.
+	BlockByHash(hash *chainhash.Hash) (*dcrutil.Block, error)
+
+	// BlockHashByHeight returns the hash of the block at the given height in
+	// the main chain.
+	BlockHashByHeight(height int64) (*chainhash.Hash, error)
+
+	// HeaderByHash returns the block header identified by the given hash or
+	// an error if it doesn't exist.  Note that this will return headers from
+	// both the main chain and any side chains.
+	HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error)
+
+	// MainChainHasBlock returns whether or not the block with the given hash
+	// is in the main chain.
+	MainChainHasBlock(hash *chainhash.Hash) bool
+}
+
+// TxMempooler represents a source of mempool transaction data for the gRPC
+// server.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type TxMempooler interface {
+	// ProcessTransaction is the main workhorse for handling insertion of new
+	// free-standing transactions into the memory pool.  It returns a slice
+	// of transactions added to the mempool.
+	ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool,
+		tag mempool.Tag) ([]*dcrutil.Tx, error)
+
+	// TxHashesWithSequence returns a slice of hashes for all of the
+	// transactions in the main pool along with the sequence number of the
+	// most recent transaction event at the time the hashes were collected.
+	TxHashesWithSequence() ([]*chainhash.Hash, uint64)
+}
+
+// Authorizer represents an authorizer for use with the gRPC server that
+// authenticates clients and authorizes their calls.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type Authorizer interface {
+	// AuthorizeCall authenticates the client that made a call from the
+	// provided remote address with the provided HTTP basic access
+	// authorization value, which is empty when the client did not provide
+	// one, and ensures it is authorized to invoke the provided JSON-RPC
+	// method, which provides functionality equivalent to the call, without
+	// exceeding its request limits.
+	//
+	// The returned function is called once the call has been processed when
+	// it is authorized.  ErrUnauthenticated must be returned when the
+	// credentials are invalid, ErrPermissionDenied must be returned when the
+	// client is not authorized to invoke the method, and an error that wraps
+	// ErrTooManyRequests must be returned when the call would exceed the
+	// request limits of the client.
+	AuthorizeCall(auth, method, remoteAddr string) (func(), error)
+}
+
+// ConnManager represents a connection manager for use with the gRPC server.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type ConnManager interface {
+	// AddRebroadcastInventory adds the provided inventory to the list of
+	// inventories to be rebroadcast at random intervals until they show up
+	// in a block.
+	AddRebroadcastInventory(iv *wire.InvVect, data interface{})
+
+	// RelayTransactions generates and relays inventory vectors for all of
+	// the passed transactions to all connected peers.
+	RelayTransactions(txns []*dcrutil.Tx)
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package grpcserver
+
+import (
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/rpc/grpc/dcrdrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// notificationQueueSize is the maximum number of notifications that may be
+// queued for a single subscription before the subscription is considered to
+// have fallen behind and is terminated.  This ensures slow clients are unable
+// to block the subsystems that generate the notifications.
+const notificationQueueSize = 1000
+
+// ntfnKind identifies a kind of notification stream.
+type ntfnKind int
+
+// These constants define the kinds of notification streams.
+const (
+	ntfnBlocks ntfnKind = iota
+	ntfnMempool
+
+	// numNtfnKinds is the number of notification kinds.  It must be the
+	// final entry.
+	numNtfnKinds
+)
+
+// blockNtfn houses a block that was connected to or disconnected from the
+// main chain.
+type blockNtfn struct {
+	connected bool
+	block     *dcrutil.Block
+}
+
+// subscription houses the queue of pending notifications for a single
+// notification stream.
+type subscription struct {
+	ntfns chan interface{}
+
+	// overflow is closed when the queue is full in order to signal the
+	// stream to terminate.
+	overflow chan struct{}
+}
+
+// subscribe adds and returns a new subscription for the provided kind of
+// notifications.
+//
+// This function is safe for concurrent access.
+func (s *Server) subscribe(kind ntfnKind) *subscription {
+	sub := &subscription{
+		ntfns:    make(chan interface{}, notificationQueueSize),
+		overflow: make(chan struct{}),
+	}
+	s.ntfnMtx.Lock()
+	s.subs[kind][sub] = struct{}{}
+	s.ntfnMtx.Unlock()
+	return sub
+}
+
+// unsubscribe removes the provided subscription for the provided kind of
+// notifications.  It has no effect if the subscription was already removed.
+//
+// This function is safe for concurrent access.
+func (s *Server) unsubscribe(kind ntfnKind, sub *subscription) {
+	s.ntfnMtx.Lock()
+	delete(s.subs[kind], sub)
+	s.ntfnMtx.Unlock()
+}
+
+// notify queues the provided notification for all subscriptions of the
+// provided kind without blocking.  Subscriptions that do not have room for the
+// notification are removed and signalled to terminate.
+//
+// This function is safe for concurrent access.
+func (s *Server) notify(kind ntfnKind, ntfn interface{}) {
+	s.ntfnMtx.Lock()
+	for sub := range s.subs[kind] {
+		select {
+		case sub.ntfns <- ntfn:
+		default:
+			delete(s.subs[kind], sub)
+			close(sub.overflow)
+		}
+	}
+	s.ntfnMtx.Unlock()
+}
+
+// NotifyBlockConnected notifies subscribed clients that the provided block was
+// connected to the main chain.
+//
+// This function is safe for concurrent access.
+func (s *Server) NotifyBlockConnected(block *dcrutil.Block) {
+	s.notify(ntfnBlocks, &blockNtfn{connected: true, block: block})
+}
+
+// NotifyBlockDisconnected notifies subscribed clients that the provided block
+// was disconnected from the main chain.
+//
+// This function is safe for concurrent access.
+func (s *Server) NotifyBlockDisconnected(block *dcrutil.Block) {
+	s.notify(ntfnBlocks, &blockNtfn{connected: false, block: block})
+}
+
+// NotifyMempoolEvent notifies subscribed clients of the provided mempool
+// transaction event.  It does not block, so it is safe to call with the
+// mempool lock held.
+//
+// This function is safe for concurrent access.
+func (s *Server) NotifyMempoolEvent(event *mempool.TxEvent) {
+	s.notify(ntfnMempool, event)
+}
+
+// errOverflow is the status returned to clients whose notification stream was
+// terminated due to not keeping up with the rate of notifications.
+var errOverflow = status.Error(codes.ResourceExhausted,
+	"client did not keep up with the rate of notifications")
+
+// mempoolEventTypes maps mempool event types to their gRPC equivalents.
+var mempoolEventTypes = map[mempool.TxEventType]dcrdrpc.MempoolEvent_Type{
+	mempool.TxEventAdded:   dcrdrpc.MempoolEvent_ADDED,
+	mempool.TxEventRemoved: dcrdrpc.MempoolEvent_REMOVED,
+}
+
+// removalReasons maps mempool removal reasons to their gRPC equivalents.
+var removalReasons = map[mempool.RemovalReason]dcrdrpc.MempoolEvent_RemovalReason{
+	mempool.RemovalReasonNone:          dcrdrpc.MempoolEvent_NONE,
+	mempool.RemovalReasonMined:         dcrdrpc.MempoolEvent_MINED,
+	mempool.RemovalReasonConflict:      dcrdrpc.MempoolEvent_CONFLICT,
+	mempool.RemovalReasonExpired:       dcrdrpc.MempoolEvent_EXPIRED,
+	mempool.RemovalReasonStale:         dcrdrpc.MempoolEvent_STALE,
+	mempool.RemovalReasonParentRemoved: dcrdrpc.MempoolEvent_PARENT_REMOVED,
+	mempool.RemovalReasonStaged:        dcrdrpc.MempoolEvent_STAGED,
+	mempool.RemovalReasonInvalid:       dcrdrpc.MempoolEvent_INVALID,
+}
+
+// notificationServer implements the dcrdrpc.NotificationServiceServer
+// interface.
+type notificationServer struct {
+	dcrdrpc.UnimplementedNotificationServiceServer
+	s *Server
+}
+
+// BlockNotifications streams notifications for blocks connected to and
+// disconnected from the main chain until the client cancels the stream or
+// falls behind.
+func (n *notificationServer) BlockNotifications(req *dcrdrpc.BlockNotificationsRequest, stream dcrdrpc.NotificationService_BlockNotificationsServer) error {
+	sub := n.s.subscribe(ntfnBlocks)
+	defer n.s.unsubscribe(ntfnBlocks, sub)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case <-sub.overflow:
+			return errOverflow
+
+		case ntfn := <-sub.ntfns:
+			bn := ntfn.(*blockNtfn)
+			msgBlock := bn.block.MsgBlock()
+			header, err := msgBlock.Header.Bytes()
+			if err != nil {
+				return status.Errorf(codes.Internal, "%v", err)
+			}
+			msg := &dcrdrpc.BlockNotification{
+				Type:   dcrdrpc.BlockNotification_CONNECTED,
+				Hash:   bn.block.Hash()[:],
+				Height: bn.block.Height(),
+				Header: header,
+			}
+			if !bn.connected {
+				msg.Type = dcrdrpc.BlockNotification_DISCONNECTED
+			}
+			if req.IncludeBlock {
+				msg.Block, err = bn.block.Bytes()
+				if err != nil {
+					return status.Errorf(codes.Internal, "%v", err)
+				}
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// MempoolEvents streams notifications for transactions added to and removed
+// from the memory pool until the client cancels the stream or falls behind.
+func (n *notificationServer) MempoolEvents(req *dcrdrpc.MempoolEventsRequest, stream dcrdrpc.NotificationService_MempoolEventsServer) error {
+	sub := n.s.subscribe(ntfnMempool)
+	defer n.s.unsubscribe(ntfnMempool, sub)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case <-sub.overflow:
+			return errOverflow
+
+		case ntfn := <-sub.ntfns:
+			event := ntfn.(*mempool.TxEvent)
+			msg := &dcrdrpc.MempoolEvent{
+				Sequence: event.Sequence,
+				Type:     mempoolEventTypes[event.Type],
+				Hash:     event.Tx.Hash()[:],
+				Reason:   removalReasons[event.Reason],
+			}
+			if event.ReplacedBy != nil {
+				msg.ReplacedBy = event.ReplacedBy[:]
+			}
+			if req.IncludeTransaction && event.Type == mempool.TxEventAdded {
+				txBytes, err := event.Tx.MsgTx().Bytes()
+				if err != nil {
+					return status.Errorf(codes.Internal, "%v", err)
+				}
+				msg.Transaction = txBytes
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
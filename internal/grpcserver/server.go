@@ -0,0 +1,403 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/rpc/grpc/dcrdrpc"
+	"github.com/decred/dcrd/wire"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// API version constants
+const (
+	apiSemverMajor = 1
+	apiSemverMinor = 0
+	apiSemverPatch = 0
+)
+
+// apiSemverString is the gRPC server's semantic API version formatted as a
+// string.
+var apiSemverString = fmt.Sprintf("%d.%d.%d", apiSemverMajor, apiSemverMinor,
+	apiSemverPatch)
+
+var (
+	// ErrUnauthenticated is returned by an Authorizer when a call does not
+	// provide valid credentials.
+	ErrUnauthenticated = errors.New("invalid credentials")
+
+	// ErrPermissionDenied is returned by an Authorizer when the client that
+	// made a call is not authorized to invoke the method.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrTooManyRequests is returned by an Authorizer when a call would
+	// exceed the request limits of the client that made it.
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// rpcMethods maps the full names of the gRPC methods to the JSON-RPC methods
+// that provide equivalent functionality.  Clients are only authorized to
+// invoke a gRPC method when they are authorized to invoke the equivalent
+// JSON-RPC method so that the gRPC server is subject to the same access
+// control as the JSON-RPC server, including the allowlist of the limited user.
+var rpcMethods = map[string]string{
+	dcrdrpc.VersionService_Version_FullMethodName:                 "version",
+	dcrdrpc.ChainService_BestBlock_FullMethodName:                 "getbestblock",
+	dcrdrpc.ChainService_BlockHash_FullMethodName:                 "getblockhash",
+	dcrdrpc.ChainService_BlockHeader_FullMethodName:               "getblockheader",
+	dcrdrpc.ChainService_Block_FullMethodName:                     "getblock",
+	dcrdrpc.MempoolService_SubmitTransaction_FullMethodName:       "sendrawtransaction",
+	dcrdrpc.MempoolService_MempoolTransactions_FullMethodName:     "getrawmempool",
+	dcrdrpc.NotificationService_BlockNotifications_FullMethodName: "notifyblocks",
+	dcrdrpc.NotificationService_MempoolEvents_FullMethodName:      "notifymempoolevents",
+}
+
+// Config is a descriptor containing the gRPC server configuration.
+type Config struct {
+	// Listeners defines a slice of listeners for which the gRPC server will
+	// take ownership of and accept connections.  Since the gRPC server takes
+	// ownership of these listeners, they will be closed when the gRPC server
+	// is stopped.
+	Listeners []net.Listener
+
+	// TLSConfig defines the TLS configuration to use for all connections.
+	// TLS is disabled when it is nil.
+	TLSConfig *tls.Config
+
+	// Chain defines the chain instance to query.
+	Chain Chain
+
+	// TxMempooler defines the transaction memory pool to interact with.
+	TxMempooler TxMempooler
+
+	// ConnMgr defines the connection manager used to relay transactions
+	// submitted via the gRPC server.
+	ConnMgr ConnManager
+
+	// Authorizer defines the authorizer used to authenticate clients and to
+	// authorize their calls.
+	Authorizer Authorizer
+}
+
+// Server provides a gRPC server that implements the services defined by the
+// dcrdrpc package.
+type Server struct {
+	cfg        Config
+	grpcServer *grpc.Server
+
+	// These fields track the clients that are subscribed to the various
+	// notification streams.  They are protected by the ntfnMtx mutex.
+	ntfnMtx sync.Mutex
+	subs    [numNtfnKinds]map[*subscription]struct{}
+}
+
+// authorize ensures the client that made the call associated with the
+// provided context is authorized to invoke the provided gRPC method per the
+// credentials it provides via the call metadata and that the call does not
+// exceed its request limits.  The returned function must be called once the
+// call has been processed when it is authorized.
+func (s *Server) authorize(ctx context.Context, fullMethod string) (func(), error) {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	method, ok := rpcMethods[fullMethod]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"not authorized for %s", fullMethod)
+	}
+	var auth string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auths := md.Get("authorization"); len(auths) == 1 {
+		auth = auths[0]
+	}
+
+	release, err := s.cfg.Authorizer.AuthorizeCall(auth, method, remoteAddr)
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+
+	case errors.Is(err, ErrPermissionDenied):
+		log.Debugf("Denied %s to %s", fullMethod, remoteAddr)
+		return nil, status.Errorf(codes.PermissionDenied,
+			"not authorized for %s", fullMethod)
+
+	case errors.Is(err, ErrTooManyRequests):
+		log.Debugf("Rejected %s from %s: %v", fullMethod, remoteAddr, err)
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+
+	case err != nil:
+		log.Errorf("Failed to authorize %s from %s: %v", fullMethod,
+			remoteAddr, err)
+		return nil, status.Error(codes.Internal, "unable to authorize call")
+	}
+	return release, nil
+}
+
+// unaryAuthInterceptor authorizes unary calls prior to invoking their
+// handlers.  Calls count towards the concurrent request limits of the client
+// until their handlers return.
+func (s *Server) unaryAuthInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+
+	release, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+// streamAuthInterceptor authorizes streaming calls prior to invoking their
+// handlers.  Streams are long lived, so, much like websocket notification
+// registrations, they only count towards the concurrent request limits of the
+// client while they are being established.
+func (s *Server) streamAuthInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	release, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	release()
+	return handler(srv, ss)
+}
+
+// Run starts the gRPC server and blocks until the provided context is
+// cancelled.  All listeners are closed and all active calls are terminated
+// when it returns.
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, listener := range s.cfg.Listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			log.Infof("gRPC server listening on %s", listener.Addr())
+			err := s.grpcServer.Serve(listener)
+			if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				log.Errorf("gRPC server on %s stopped: %v", listener.Addr(),
+					err)
+			}
+			log.Tracef("gRPC listener done for %s", listener.Addr())
+			wg.Done()
+		}(listener)
+	}
+
+	<-ctx.Done()
+	s.grpcServer.Stop()
+	wg.Wait()
+	log.Infof("gRPC server shutdown complete")
+}
+
+// New returns a new instance of the Server struct with the provided config.
+func New(cfg *Config) *Server {
+	s := &Server{cfg: *cfg}
+	for i := range s.subs {
+		s.subs[i] = make(map[*subscription]struct{})
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryAuthInterceptor),
+		grpc.StreamInterceptor(s.streamAuthInterceptor),
+	}
+	if cfg.TLSConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLSConfig)))
+	}
+	s.grpcServer = grpc.NewServer(opts...)
+	dcrdrpc.RegisterVersionServiceServer(s.grpcServer, &versionServer{})
+	dcrdrpc.RegisterChainServiceServer(s.grpcServer, &chainServer{s: s})
+	dcrdrpc.RegisterMempoolServiceServer(s.grpcServer, &mempoolServer{s: s})
+	dcrdrpc.RegisterNotificationServiceServer(s.grpcServer,
+		&notificationServer{s: s})
+	return s
+}
+
+// parseHash converts the provided bytes to a hash and returns an appropriate
+// status error when they are not a valid hash.
+func parseHash(b []byte) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHash(b)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hash: %v",
+			err)
+	}
+	return hash, nil
+}
+
+// versionServer implements the dcrdrpc.VersionServiceServer interface.
+type versionServer struct {
+	dcrdrpc.UnimplementedVersionServiceServer
+}
+
+// Version returns the semantic version of the API.
+func (*versionServer) Version(context.Context, *dcrdrpc.VersionRequest) (*dcrdrpc.VersionResponse, error) {
+	return &dcrdrpc.VersionResponse{
+		VersionString: apiSemverString,
+		Major:         apiSemverMajor,
+		Minor:         apiSemverMinor,
+		Patch:         apiSemverPatch,
+	}, nil
+}
+
+// chainServer implements the dcrdrpc.ChainServiceServer interface.
+type chainServer struct {
+	dcrdrpc.UnimplementedChainServiceServer
+	s *Server
+}
+
+// BestBlock returns the hash and height of the current best chain tip.
+func (c *chainServer) BestBlock(context.Context, *dcrdrpc.BestBlockRequest) (*dcrdrpc.BestBlockResponse, error) {
+	best := c.s.cfg.Chain.BestSnapshot()
+	return &dcrdrpc.BestBlockResponse{
+		Hash:   best.Hash[:],
+		Height: best.Height,
+	}, nil
+}
+
+// BlockHash returns the hash of the main chain block at the requested height.
+func (c *chainServer) BlockHash(_ context.Context, req *dcrdrpc.BlockHashRequest) (*dcrdrpc.BlockHashResponse, error) {
+	best := c.s.cfg.Chain.BestSnapshot()
+	if req.Height < 0 || req.Height > best.Height {
+		return nil, status.Errorf(codes.OutOfRange, "block height %d out "+
+			"of range [0, %d]", req.Height, best.Height)
+	}
+	hash, err := c.s.cfg.Chain.BlockHashByHeight(req.Height)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	return &dcrdrpc.BlockHashResponse{Hash: hash[:]}, nil
+}
+
+// BlockHeader returns the serialized header of the requested block.
+func (c *chainServer) BlockHeader(_ context.Context, req *dcrdrpc.BlockHeaderRequest) (*dcrdrpc.BlockHeaderResponse, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	header, err := c.s.cfg.Chain.HeaderByHash(hash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrUnknownBlock) {
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &dcrdrpc.BlockHeaderResponse{
+		Header:    headerBytes,
+		MainChain: c.s.cfg.Chain.MainChainHasBlock(hash),
+	}, nil
+}
+
+// Block returns the requested serialized block.
+func (c *chainServer) Block(_ context.Context, req *dcrdrpc.BlockRequest) (*dcrdrpc.BlockResponse, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := c.s.cfg.Chain.BlockByHash(hash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrUnknownBlock) {
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &dcrdrpc.BlockResponse{Block: blockBytes}, nil
+}
+
+// mempoolServer implements the dcrdrpc.MempoolServiceServer interface.
+type mempoolServer struct {
+	dcrdrpc.UnimplementedMempoolServiceServer
+	s *Server
+}
+
+// SubmitTransaction validates the provided transaction, adds it to the memory
+// pool, and relays it to the network.
+func (m *mempoolServer) SubmitTransaction(_ context.Context, req *dcrdrpc.SubmitTransactionRequest) (*dcrdrpc.SubmitTransactionResponse, error) {
+	msgTx := wire.NewMsgTx()
+	err := msgTx.Deserialize(bytes.NewReader(req.Transaction))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode "+
+			"transaction: %v", err)
+	}
+
+	// Use 0 for the tag to represent local node.
+	tx := dcrutil.NewTx(msgTx)
+	acceptedTxs, err := m.s.cfg.TxMempooler.ProcessTransaction(tx, false,
+		req.AllowHighFees, 0)
+	if err != nil {
+		// When the error is a rule error, it means the transaction was
+		// simply rejected as opposed to something actually going wrong, so
+		// log it as such.  Otherwise, something really did go wrong, so log
+		// it as an actual error.
+		var rErr mempool.RuleError
+		if errors.As(err, &rErr) {
+			err = fmt.Errorf("rejected transaction %v: %w", tx.Hash(), err)
+			log.Debugf("%v", err)
+			if errors.Is(rErr, mempool.ErrDuplicate) ||
+				errors.Is(rErr, mempool.ErrAlreadyExists) {
+
+				return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+			}
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+
+		err = fmt.Errorf("failed to process transaction %v: %w", tx.Hash(),
+			err)
+		log.Errorf("%v", err)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	// Generate and relay inventory vectors for all newly accepted
+	// transactions.
+	m.s.cfg.ConnMgr.RelayTransactions(acceptedTxs)
+
+	// Keep track of all the submitted transactions so that they can be
+	// rebroadcast if they don't make their way into a block.
+	//
+	// Note that votes are only valid for a specific block and are time
+	// sensitive, so they should not be added to the rebroadcast logic.
+	if stake.DetermineTxType(msgTx) != stake.TxTypeSSGen {
+		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
+		m.s.cfg.ConnMgr.AddRebroadcastInventory(iv, tx)
+	}
+
+	return &dcrdrpc.SubmitTransactionResponse{Hash: tx.Hash()[:]}, nil
+}
+
+// MempoolTransactions returns the hashes of all transactions in the memory
+// pool along with the sequence number of the most recent mempool event.
+func (m *mempoolServer) MempoolTransactions(context.Context, *dcrdrpc.MempoolTransactionsRequest) (*dcrdrpc.MempoolTransactionsResponse, error) {
+	hashes, seq := m.s.cfg.TxMempooler.TxHashesWithSequence()
+	resp := &dcrdrpc.MempoolTransactionsResponse{
+		Hashes:   make([][]byte, 0, len(hashes)),
+		Sequence: seq,
+	}
+	for _, hash := range hashes {
+		resp.Hashes = append(resp.Hashes, hash[:])
+	}
+	return resp, nil
+}
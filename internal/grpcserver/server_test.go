@@ -0,0 +1,632 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package grpcserver
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/rpc/grpc/dcrdrpc"
+	"github.com/decred/dcrd/wire"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testChain provides a mock chain that houses a single block.
+type testChain struct {
+	block *dcrutil.Block
+}
+
+// BestSnapshot returns the best state with the test block as the tip.
+func (c *testChain) BestSnapshot() *blockchain.BestState {
+	return &blockchain.BestState{
+		Hash:   *c.block.Hash(),
+		Height: c.block.Height(),
+	}
+}
+
+// BlockByHash returns the test block when its hash is provided.
+func (c *testChain) BlockByHash(hash *chainhash.Hash) (*dcrutil.Block, error) {
+	if *hash != *c.block.Hash() {
+		return nil, blockchain.ErrUnknownBlock
+	}
+	return c.block, nil
+}
+
+// BlockHashByHeight returns the hash of the test block when its height is
+// provided.
+func (c *testChain) BlockHashByHeight(height int64) (*chainhash.Hash, error) {
+	if height != c.block.Height() {
+		return nil, blockchain.ErrUnknownBlock
+	}
+	return c.block.Hash(), nil
+}
+
+// HeaderByHash returns the header of the test block when its hash is provided.
+func (c *testChain) HeaderByHash(hash *chainhash.Hash) (wire.BlockHeader, error) {
+	if *hash != *c.block.Hash() {
+		return wire.BlockHeader{}, blockchain.ErrUnknownBlock
+	}
+	return c.block.MsgBlock().Header, nil
+}
+
+// MainChainHasBlock returns whether the provided hash is the test block.
+func (c *testChain) MainChainHasBlock(hash *chainhash.Hash) bool {
+	return *hash == *c.block.Hash()
+}
+
+// testMempool provides a mock mempool that accepts all transactions unless
+// configured to return an error.
+type testMempool struct {
+	processErr error
+}
+
+// ProcessTransaction returns the configured error or otherwise accepts the
+// transaction.
+func (m *testMempool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool, tag mempool.Tag) ([]*dcrutil.Tx, error) {
+	if m.processErr != nil {
+		return nil, m.processErr
+	}
+	return []*dcrutil.Tx{tx}, nil
+}
+
+// TxHashesWithSequence returns a single hash and a fixed sequence number.
+func (m *testMempool) TxHashesWithSequence() ([]*chainhash.Hash, uint64) {
+	return []*chainhash.Hash{{0x01}}, 5
+}
+
+// testConnManager provides a mock connection manager that records relayed
+// transactions.
+type testConnManager struct {
+	mtx     sync.Mutex
+	relayed []*dcrutil.Tx
+}
+
+// AddRebroadcastInventory does nothing.
+func (c *testConnManager) AddRebroadcastInventory(*wire.InvVect, interface{}) {}
+
+// RelayTransactions records the relayed transactions.
+func (c *testConnManager) RelayTransactions(txns []*dcrutil.Tx) {
+	c.mtx.Lock()
+	c.relayed = append(c.relayed, txns...)
+	c.mtx.Unlock()
+}
+
+// testAuthorizer provides a mock authorizer that authorizes the users it houses
+// for the JSON-RPC methods in their allowlists, where a nil allowlist allows all
+// methods.  All calls are authorized when it does not house any users.  Calls
+// are also limited to the provided number of concurrent calls when it is
+// nonzero.
+type testAuthorizer struct {
+	users    map[string]map[string]struct{}
+	maxCalls int
+
+	mtx      sync.Mutex
+	inFlight int
+}
+
+// AuthorizeCall authorizes the call per the user that matches the provided
+// authorization value and the concurrent call limit.
+func (a *testAuthorizer) AuthorizeCall(auth, method, remoteAddr string) (func(), error) {
+	if len(a.users) != 0 {
+		methods, ok := a.users[auth]
+		if !ok {
+			return nil, ErrUnauthenticated
+		}
+		if _, ok := methods[method]; methods != nil && !ok {
+			return nil, ErrPermissionDenied
+		}
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if a.maxCalls > 0 && a.inFlight >= a.maxCalls {
+		return nil, ErrTooManyRequests
+	}
+	a.inFlight++
+	release := func() {
+		a.mtx.Lock()
+		a.inFlight--
+		a.mtx.Unlock()
+	}
+	return release, nil
+}
+
+// callsInFlight returns the number of authorized calls that have not been
+// released.
+func (a *testAuthorizer) callsInFlight() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.inFlight
+}
+
+// basicAuth returns the HTTP basic authorization value for the provided
+// credentials.
+func basicAuth(user, pass string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+}
+
+// testHarness houses a running gRPC server along with a client connection to
+// it.
+type testHarness struct {
+	server   *Server
+	conn     *grpc.ClientConn
+	chain    *testChain
+	mempool  *testMempool
+	connMgr  *testConnManager
+	shutdown func()
+}
+
+// newTestHarness starts a gRPC server with the provided authorizer on an
+// in-memory listener and connects to it.
+func newTestHarness(t *testing.T, authorizer Authorizer) *testHarness {
+	t.Helper()
+
+	block := dcrutil.NewBlock(&wire.MsgBlock{
+		Header: wire.BlockHeader{Height: 100},
+	})
+	h := &testHarness{
+		chain:   &testChain{block: block},
+		mempool: &testMempool{},
+		connMgr: &testConnManager{},
+	}
+	listener := bufconn.Listen(1 << 20)
+	h.server = New(&Config{
+		Listeners:   []net.Listener{listener},
+		Chain:       h.chain,
+		TxMempooler: h.mempool,
+		ConnMgr:     h.connMgr,
+		Authorizer:  authorizer,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.server.Run(ctx)
+		close(done)
+	}()
+
+	dialer := func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(dialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		cancel()
+		t.Fatalf("unable to dial server: %v", err)
+	}
+	h.conn = conn
+	h.shutdown = func() {
+		conn.Close()
+		cancel()
+		<-done
+	}
+	return h
+}
+
+// withAuth returns a context that provides the given credentials as HTTP basic
+// authorization metadata.
+func withAuth(ctx context.Context, user, pass string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization",
+		basicAuth(user, pass))
+}
+
+// TestAuthentication ensures calls are only permitted with valid credentials
+// when credentials are configured.
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, &testAuthorizer{
+		users: map[string]map[string]struct{}{basicAuth("user", "pass"): nil},
+	})
+	defer h.shutdown()
+
+	client := dcrdrpc.NewVersionServiceClient(h.conn)
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{{
+		name:     "no credentials",
+		ctx:      context.Background(),
+		wantCode: codes.Unauthenticated,
+	}, {
+		name:     "wrong password",
+		ctx:      withAuth(context.Background(), "user", "wrong"),
+		wantCode: codes.Unauthenticated,
+	}, {
+		name:     "valid credentials",
+		ctx:      withAuth(context.Background(), "user", "pass"),
+		wantCode: codes.OK,
+	}}
+	for _, test := range tests {
+		resp, err := client.Version(test.ctx, &dcrdrpc.VersionRequest{})
+		if code := status.Code(err); code != test.wantCode {
+			t.Errorf("%q: unexpected code -- got %v, want %v", test.name,
+				code, test.wantCode)
+			continue
+		}
+		if err == nil && resp.VersionString != apiSemverString {
+			t.Errorf("%q: unexpected version -- got %q, want %q", test.name,
+				resp.VersionString, apiSemverString)
+		}
+	}
+}
+
+// TestAuthorization ensures every method is mapped to an equivalent JSON-RPC
+// method and that calls are only permitted when the client is authorized for
+// the equivalent JSON-RPC method.
+func TestAuthorization(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, &testAuthorizer{
+		users: map[string]map[string]struct{}{
+			basicAuth("admin", "pass"): nil,
+			basicAuth("limit", "pass"): {
+				"getbestblock":       {},
+				"sendrawtransaction": {},
+			},
+		},
+	})
+	defer h.shutdown()
+
+	// Ensure all registered methods are mapped to a JSON-RPC method.
+	for service, info := range h.server.grpcServer.GetServiceInfo() {
+		for _, method := range info.Methods {
+			fullMethod := "/" + service + "/" + method.Name
+			if _, ok := rpcMethods[fullMethod]; !ok {
+				t.Errorf("%s is not mapped to a JSON-RPC method", fullMethod)
+			}
+		}
+	}
+
+	chainClient := dcrdrpc.NewChainServiceClient(h.conn)
+	mempoolClient := dcrdrpc.NewMempoolServiceClient(h.conn)
+	ntfnClient := dcrdrpc.NewNotificationServiceClient(h.conn)
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context) error
+		user     string
+		wantCode codes.Code
+	}{{
+		name: "limited unary call in allowlist",
+		call: func(ctx context.Context) error {
+			_, err := chainClient.BestBlock(ctx, &dcrdrpc.BestBlockRequest{})
+			return err
+		},
+		user:     "limit",
+		wantCode: codes.OK,
+	}, {
+		name: "limited unary call not in allowlist",
+		call: func(ctx context.Context) error {
+			_, err := mempoolClient.MempoolTransactions(ctx,
+				&dcrdrpc.MempoolTransactionsRequest{})
+			return err
+		},
+		user:     "limit",
+		wantCode: codes.PermissionDenied,
+	}, {
+		name: "limited stream not in allowlist",
+		call: func(ctx context.Context) error {
+			stream, err := ntfnClient.MempoolEvents(ctx,
+				&dcrdrpc.MempoolEventsRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		},
+		user:     "limit",
+		wantCode: codes.PermissionDenied,
+	}, {
+		name: "admin unary call",
+		call: func(ctx context.Context) error {
+			_, err := mempoolClient.MempoolTransactions(ctx,
+				&dcrdrpc.MempoolTransactionsRequest{})
+			return err
+		},
+		user:     "admin",
+		wantCode: codes.OK,
+	}}
+	for _, test := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := test.call(withAuth(ctx, test.user, "pass"))
+		cancel()
+		if code := status.Code(err); code != test.wantCode {
+			t.Errorf("%q: unexpected code -- got %v, want %v", test.name,
+				code, test.wantCode)
+		}
+	}
+}
+
+// TestLimits ensures calls that exceed the limits of the authorizer are
+// rejected and that calls are released once they complete, while streams are
+// released once they are established.
+func TestLimits(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &testAuthorizer{maxCalls: 1}
+	h := newTestHarness(t, authorizer)
+	defer h.shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := dcrdrpc.NewVersionServiceClient(h.conn)
+	for i := 0; i < 2; i++ {
+		_, err := client.Version(ctx, &dcrdrpc.VersionRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error for call %d: %v", i, err)
+		}
+	}
+
+	ntfnClient := dcrdrpc.NewNotificationServiceClient(h.conn)
+	_, err := ntfnClient.BlockNotifications(ctx,
+		&dcrdrpc.BlockNotificationsRequest{})
+	if err != nil {
+		t.Fatalf("BlockNotifications: unexpected error: %v", err)
+	}
+	waitForSubscriptions(t, h.server, ntfnBlocks, 1)
+	if n := authorizer.callsInFlight(); n != 0 {
+		t.Fatalf("unexpected calls in flight with open stream: %d", n)
+	}
+
+	// Ensure calls that exceed the limits are rejected.
+	release, _ := authorizer.AuthorizeCall("", "version", "")
+	defer release()
+	_, err = client.Version(ctx, &dcrdrpc.VersionRequest{})
+	if code := status.Code(err); code != codes.ResourceExhausted {
+		t.Fatalf("unexpected code for limited call -- got %v, want %v", code,
+			codes.ResourceExhausted)
+	}
+}
+
+// TestChainService ensures the chain service returns the expected results and
+// errors.
+func TestChainService(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, &testAuthorizer{})
+	defer h.shutdown()
+
+	ctx := context.Background()
+	client := dcrdrpc.NewChainServiceClient(h.conn)
+	blockHash := h.chain.block.Hash()
+
+	best, err := client.BestBlock(ctx, &dcrdrpc.BestBlockRequest{})
+	if err != nil {
+		t.Fatalf("BestBlock: unexpected error: %v", err)
+	}
+	if string(best.Hash) != string(blockHash[:]) || best.Height != 100 {
+		t.Fatalf("BestBlock: unexpected result -- got %x (height %d)",
+			best.Hash, best.Height)
+	}
+
+	hashResp, err := client.BlockHash(ctx, &dcrdrpc.BlockHashRequest{
+		Height: 100,
+	})
+	if err != nil {
+		t.Fatalf("BlockHash: unexpected error: %v", err)
+	}
+	if string(hashResp.Hash) != string(blockHash[:]) {
+		t.Fatalf("BlockHash: unexpected hash -- got %x", hashResp.Hash)
+	}
+	_, err = client.BlockHash(ctx, &dcrdrpc.BlockHashRequest{Height: 101})
+	if code := status.Code(err); code != codes.OutOfRange {
+		t.Fatalf("BlockHash: unexpected code -- got %v, want %v", code,
+			codes.OutOfRange)
+	}
+
+	headerResp, err := client.BlockHeader(ctx, &dcrdrpc.BlockHeaderRequest{
+		Hash: blockHash[:],
+	})
+	if err != nil {
+		t.Fatalf("BlockHeader: unexpected error: %v", err)
+	}
+	var header wire.BlockHeader
+	if err := header.FromBytes(headerResp.Header); err != nil {
+		t.Fatalf("BlockHeader: unable to decode header: %v", err)
+	}
+	if header.BlockHash() != *blockHash || !headerResp.MainChain {
+		t.Fatalf("BlockHeader: unexpected result -- got %v (main chain %v)",
+			header.BlockHash(), headerResp.MainChain)
+	}
+
+	_, err = client.Block(ctx, &dcrdrpc.BlockRequest{Hash: []byte{0x01}})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("Block: unexpected code -- got %v, want %v", code,
+			codes.InvalidArgument)
+	}
+	unknownHash := chainhash.Hash{0x01}
+	_, err = client.Block(ctx, &dcrdrpc.BlockRequest{Hash: unknownHash[:]})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Fatalf("Block: unexpected code -- got %v, want %v", code,
+			codes.NotFound)
+	}
+}
+
+// TestMempoolService ensures the mempool service submits and relays
+// transactions and maps rejections to the expected codes.
+func TestMempoolService(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, &testAuthorizer{})
+	defer h.shutdown()
+
+	ctx := context.Background()
+	client := dcrdrpc.NewMempoolServiceClient(h.conn)
+
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 1, nil))
+	msgTx.AddTxOut(wire.NewTxOut(1, nil))
+	txBytes, err := msgTx.Bytes()
+	if err != nil {
+		t.Fatalf("unable to serialize transaction: %v", err)
+	}
+	resp, err := client.SubmitTransaction(ctx, &dcrdrpc.SubmitTransactionRequest{
+		Transaction: txBytes,
+	})
+	if err != nil {
+		t.Fatalf("SubmitTransaction: unexpected error: %v", err)
+	}
+	txHash := msgTx.TxHash()
+	if string(resp.Hash) != string(txHash[:]) {
+		t.Fatalf("SubmitTransaction: unexpected hash -- got %x, want %x",
+			resp.Hash, txHash[:])
+	}
+	h.connMgr.mtx.Lock()
+	numRelayed := len(h.connMgr.relayed)
+	h.connMgr.mtx.Unlock()
+	if numRelayed != 1 {
+		t.Fatalf("SubmitTransaction: unexpected number of relayed txns -- "+
+			"got %d, want 1", numRelayed)
+	}
+
+	// Ensure malformed and rejected transactions return the expected codes.
+	_, err = client.SubmitTransaction(ctx, &dcrdrpc.SubmitTransactionRequest{
+		Transaction: []byte{0x01},
+	})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("SubmitTransaction: unexpected code -- got %v, want %v",
+			code, codes.InvalidArgument)
+	}
+	h.mempool.processErr = mempool.RuleError{Err: mempool.ErrDuplicate}
+	_, err = client.SubmitTransaction(ctx, &dcrdrpc.SubmitTransactionRequest{
+		Transaction: txBytes,
+	})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Fatalf("SubmitTransaction: unexpected code -- got %v, want %v",
+			code, codes.AlreadyExists)
+	}
+
+	txnsResp, err := client.MempoolTransactions(ctx,
+		&dcrdrpc.MempoolTransactionsRequest{})
+	if err != nil {
+		t.Fatalf("MempoolTransactions: unexpected error: %v", err)
+	}
+	if len(txnsResp.Hashes) != 1 || txnsResp.Sequence != 5 {
+		t.Fatalf("MempoolTransactions: unexpected result -- got %d hashes "+
+			"and sequence %d", len(txnsResp.Hashes), txnsResp.Sequence)
+	}
+}
+
+// waitForSubscriptions waits until the server has the provided number of
+// subscriptions for the given kind of notifications.
+func waitForSubscriptions(t *testing.T, s *Server, kind ntfnKind, num int) {
+	t.Helper()
+
+	for i := 0; i < 500; i++ {
+		s.ntfnMtx.Lock()
+		n := len(s.subs[kind])
+		s.ntfnMtx.Unlock()
+		if n == num {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("timeout waiting for %d subscriptions", num)
+}
+
+// TestNotificationService ensures block and mempool notifications are streamed
+// to subscribed clients and that clients which fall behind are disconnected.
+func TestNotificationService(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, &testAuthorizer{})
+	defer h.shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := dcrdrpc.NewNotificationServiceClient(h.conn)
+
+	blockStream, err := client.BlockNotifications(ctx,
+		&dcrdrpc.BlockNotificationsRequest{IncludeBlock: true})
+	if err != nil {
+		t.Fatalf("BlockNotifications: unexpected error: %v", err)
+	}
+	mempoolStream, err := client.MempoolEvents(ctx,
+		&dcrdrpc.MempoolEventsRequest{})
+	if err != nil {
+		t.Fatalf("MempoolEvents: unexpected error: %v", err)
+	}
+	waitForSubscriptions(t, h.server, ntfnBlocks, 1)
+	waitForSubscriptions(t, h.server, ntfnMempool, 1)
+
+	block := h.chain.block
+	h.server.NotifyBlockConnected(block)
+	h.server.NotifyBlockDisconnected(block)
+	for _, wantType := range []dcrdrpc.BlockNotification_Type{
+		dcrdrpc.BlockNotification_CONNECTED,
+		dcrdrpc.BlockNotification_DISCONNECTED,
+	} {
+		ntfn, err := blockStream.Recv()
+		if err != nil {
+			t.Fatalf("BlockNotifications: unexpected error: %v", err)
+		}
+		if ntfn.Type != wantType || ntfn.Height != block.Height() ||
+			string(ntfn.Hash) != string(block.Hash()[:]) ||
+			len(ntfn.Block) == 0 {
+
+			t.Fatalf("BlockNotifications: unexpected notification %v", ntfn)
+		}
+	}
+
+	tx := dcrutil.NewTx(wire.NewMsgTx())
+	replacedBy := &chainhash.Hash{0x02}
+	h.server.NotifyMempoolEvent(&mempool.TxEvent{
+		Sequence:   7,
+		Type:       mempool.TxEventRemoved,
+		Tx:         tx,
+		Reason:     mempool.RemovalReasonConflict,
+		ReplacedBy: replacedBy,
+	})
+	event, err := mempoolStream.Recv()
+	if err != nil {
+		t.Fatalf("MempoolEvents: unexpected error: %v", err)
+	}
+	if event.Sequence != 7 || event.Type != dcrdrpc.MempoolEvent_REMOVED ||
+		event.Reason != dcrdrpc.MempoolEvent_CONFLICT ||
+		string(event.ReplacedBy) != string(replacedBy[:]) {
+
+		t.Fatalf("MempoolEvents: unexpected event %v", event)
+	}
+
+	// Ensure a subscription that does not keep up with the notifications is
+	// removed and signalled to terminate.
+	sub := h.server.subscribe(ntfnMempool)
+	for i := 0; i < notificationQueueSize+1; i++ {
+		h.server.notify(ntfnMempool, &mempool.TxEvent{
+			Sequence: uint64(i),
+			Type:     mempool.TxEventAdded,
+			Tx:       tx,
+		})
+	}
+	select {
+	case <-sub.overflow:
+	default:
+		t.Fatal("subscription was not signalled to terminate")
+	}
+	h.server.ntfnMtx.Lock()
+	_, ok := h.server.subs[ntfnMempool][sub]
+	h.server.ntfnMtx.Unlock()
+	if ok {
+		t.Fatal("subscription was not removed")
+	}
+
+	// Ensure the stream subscription is removed when the client cancels it.
+	cancel()
+	waitForSubscriptions(t, h.server, ntfnMempool, 0)
+	waitForSubscriptions(t, h.server, ntfnBlocks, 0)
+}
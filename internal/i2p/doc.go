@@ -0,0 +1,31 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package i2p implements a client for the SAM v3 bridge provided by I2P routers
+which allows connecting to and accepting connections from peers on the I2P
+anonymity network.
+
+I2P is an alternative to Tor for concealing the network location of peers.
+Unlike Tor, it does not provide exits to the public internet, so peers on I2P
+are only reachable by other peers on I2P.
+
+Every peer on I2P is identified by a destination, which consists of public keys
+along with a certificate, and is addressed by the base32 encoding of the
+SHA-256 hash of its destination followed by ".b32.i2p".  The SAM bridge is
+responsible for building the tunnels that route traffic and for resolving
+addresses to their full destination when connecting.
+
+A Session maintains a streaming session with the SAM bridge for a single
+destination.  The private key of the destination may be provided when creating
+a session so the same address is used across restarts, otherwise a new one is
+created by the bridge.  Connections to remote destinations are made via the
+Dial method of the session, which is compatible with net.Dialer.DialContext,
+and connections from remote destinations are accepted via the Accept method
+since the session implements the net.Listener interface.
+
+I2P does not have a notion of ports for the streaming protocol used by the
+bridge, so all I2P addresses use the Port constant by convention.
+*/
+package i2p
@@ -0,0 +1,436 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package i2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDest returns a serialized destination with a key certificate whose bytes
+// are derived from the provided seed.
+func testDest(seed byte) []byte {
+	const certLen = 7
+	dest := make([]byte, destMinLen+certLen)
+	for i := range dest {
+		dest[i] = seed + byte(i)
+	}
+	dest[destMinLen-3] = 5 // Key certificate.
+	dest[destMinLen-2] = 0
+	dest[destMinLen-1] = certLen
+	return dest
+}
+
+// testHost returns the I2P address host for the provided destination.  It is
+// calculated independently from destHost.
+func testHost(dest []byte) string {
+	hash := sha256.Sum256(dest)
+	encoded := base32.StdEncoding.EncodeToString(hash[:])
+	return strings.ToLower(strings.TrimRight(encoded, "=")) + ".b32.i2p"
+}
+
+// fakeSAM is a minimal SAM bridge that supports enough of the protocol to test
+// sessions.  Streams are echoed back to the caller.
+type fakeSAM struct {
+	listener      net.Listener
+	privKey       string
+	dests         map[string]string
+	peerDest      string
+	sessionResult string
+	blockAccept   bool
+
+	mtx      sync.Mutex
+	sessions map[string]struct{}
+	creates  []string
+}
+
+// newFakeSAM starts a new fake SAM bridge that creates sessions with the
+// provided private key, resolves the provided destinations, and reports
+// accepted streams as being from the provided peer destination.
+func newFakeSAM(t *testing.T, privKey []byte, peerDest []byte, dests ...[]byte) *fakeSAM {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	f := &fakeSAM{
+		listener:      listener,
+		privKey:       destEncoding.EncodeToString(privKey),
+		dests:         make(map[string]string),
+		peerDest:      destEncoding.EncodeToString(peerDest),
+		sessionResult: "OK",
+		sessions:      make(map[string]struct{}),
+	}
+	for _, dest := range dests {
+		f.dests[testHost(dest)] = destEncoding.EncodeToString(dest)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return f
+}
+
+// handle serves the requests made on the provided connection.
+func (f *fakeSAM) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\n", args...)
+	}
+	for {
+		line, err := readLine(conn)
+		if err != nil {
+			return
+		}
+		r, err := parseReply(line)
+		if err != nil {
+			return
+		}
+		switch r.cmd {
+		case "HELLO VERSION":
+			reply("HELLO REPLY RESULT=OK VERSION=%s", samVersion)
+
+		case "SESSION CREATE":
+			f.mtx.Lock()
+			f.creates = append(f.creates, r.args["DESTINATION"])
+			if f.sessionResult == "OK" {
+				f.sessions[r.args["ID"]] = struct{}{}
+			}
+			f.mtx.Unlock()
+			if f.sessionResult != "OK" {
+				reply(`SESSION STATUS RESULT=%s MESSAGE="session failed"`,
+					f.sessionResult)
+				return
+			}
+			reply("SESSION STATUS RESULT=OK DESTINATION=%s", f.privKey)
+
+		case "NAMING LOOKUP":
+			dest, ok := f.dests[r.args["NAME"]]
+			if !ok {
+				reply("NAMING REPLY RESULT=KEY_NOT_FOUND NAME=%s",
+					r.args["NAME"])
+				continue
+			}
+			reply("NAMING REPLY RESULT=OK NAME=%s VALUE=%s", r.args["NAME"],
+				dest)
+
+		case "STREAM CONNECT", "STREAM ACCEPT":
+			f.mtx.Lock()
+			_, ok := f.sessions[r.args["ID"]]
+			f.mtx.Unlock()
+			if !ok {
+				reply("STREAM STATUS RESULT=INVALID_ID")
+				continue
+			}
+			reply("STREAM STATUS RESULT=OK")
+			if r.cmd == "STREAM ACCEPT" {
+				if f.blockAccept {
+					io.Copy(io.Discard, conn)
+					return
+				}
+				reply("%s FROM_PORT=0 TO_PORT=0", f.peerDest)
+			}
+			io.Copy(conn, conn)
+			return
+		}
+	}
+}
+
+// forgetSessions causes the fake SAM bridge to forget all existing sessions.
+func (f *fakeSAM) forgetSessions() {
+	f.mtx.Lock()
+	f.sessions = make(map[string]struct{})
+	f.mtx.Unlock()
+}
+
+// sessionCreates returns the destinations requested by every session creation
+// request made to the fake SAM bridge.
+func (f *fakeSAM) sessionCreates() []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]string(nil), f.creates...)
+}
+
+// testEcho ensures data written to the provided connection is echoed back.
+func testEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	data := []byte("dcrd i2p echo")
+	if _, err := conn.Write(data); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("mismatched data -- got %q, want %q", got, data)
+	}
+}
+
+// TestParseReply ensures replies from the SAM bridge are parsed as expected.
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *reply
+		wantErr error
+	}{{
+		name: "hello reply",
+		line: "HELLO REPLY RESULT=OK VERSION=3.1",
+		want: &reply{cmd: "HELLO REPLY", args: map[string]string{
+			"RESULT": "OK", "VERSION": "3.1",
+		}},
+	}, {
+		name: "quoted message with extra spaces",
+		line: `SESSION STATUS  RESULT=I2P_ERROR MESSAGE="no tunnels  built"`,
+		want: &reply{cmd: "SESSION STATUS", args: map[string]string{
+			"RESULT": "I2P_ERROR", "MESSAGE": "no tunnels  built",
+		}},
+	}, {
+		name: "key without value",
+		line: "STREAM STATUS RESULT=OK SILENT",
+		want: &reply{cmd: "STREAM STATUS", args: map[string]string{
+			"RESULT": "OK", "SILENT": "",
+		}},
+	}, {
+		name:    "single word",
+		line:    "HELLO",
+		wantErr: ErrInvalidReply,
+	}, {
+		name:    "unterminated quote",
+		line:    `SESSION STATUS RESULT=I2P_ERROR MESSAGE="failed`,
+		wantErr: ErrInvalidReply,
+	}}
+
+	for _, test := range tests {
+		got, err := parseReply(test.line)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected reply -- got %+v, want %+v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestDecodeDest ensures destinations are decoded from both destinations and
+// private keys and that invalid destinations are rejected.
+func TestDecodeDest(t *testing.T) {
+	dest := testDest(1)
+	privKey := append(append([]byte(nil), dest...), testDest(2)...)
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr error
+	}{{
+		name:    "destination",
+		encoded: destEncoding.EncodeToString(dest),
+	}, {
+		name:    "private key",
+		encoded: destEncoding.EncodeToString(privKey),
+	}, {
+		name: "standard base64",
+		encoded: strings.NewReplacer("-", "+", "~", "/").Replace(
+			destEncoding.EncodeToString(dest)),
+		wantErr: ErrInvalidDestination,
+	}, {
+		name:    "truncated certificate",
+		encoded: destEncoding.EncodeToString(dest[:len(dest)-1]),
+		wantErr: ErrInvalidDestination,
+	}, {
+		name:    "too short",
+		encoded: destEncoding.EncodeToString(dest[:destMinLen-1]),
+		wantErr: ErrInvalidDestination,
+	}}
+
+	for _, test := range tests {
+		got, err := decodeDest(test.encoded)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if err == nil && !bytes.Equal(got, dest) {
+			t.Errorf("%q: unexpected destination -- got %x, want %x",
+				test.name, got, dest)
+		}
+	}
+}
+
+// TestSession ensures sessions are created with the SAM bridge and that
+// connections are both made and accepted via them.
+func TestSession(t *testing.T) {
+	localDest, peerDest, remoteDest := testDest(1), testDest(2), testDest(3)
+	privKey := append(append([]byte(nil), localDest...), testDest(4)...)
+	sam := newFakeSAM(t, privKey, peerDest, remoteDest)
+
+	ctx := context.Background()
+	s, err := NewSession(ctx, &Config{SAMAddr: sam.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("unexpected error creating session: %v", err)
+	}
+	defer s.Close()
+
+	// Ensure the session reports the expected private key and address.
+	if got, want := s.PrivateKey(), destEncoding.EncodeToString(privKey); got != want {
+		t.Fatalf("unexpected private key -- got %s, want %s", got, want)
+	}
+	localAddr := &Addr{Host: testHost(localDest)}
+	if got := s.Addr(); !reflect.DeepEqual(got, localAddr) {
+		t.Fatalf("unexpected address -- got %v, want %v", got, localAddr)
+	}
+	if got, want := localAddr.String(), testHost(localDest)+":0"; got != want {
+		t.Fatalf("unexpected address string -- got %s, want %s", got, want)
+	}
+
+	// Ensure connections are made to known destinations.
+	remoteAddr := &Addr{Host: testHost(remoteDest)}
+	conn, err := s.Dial(ctx, "tcp", remoteAddr.String())
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.RemoteAddr(); !reflect.DeepEqual(got, remoteAddr) {
+		t.Fatalf("unexpected remote address -- got %v, want %v", got,
+			remoteAddr)
+	}
+	if got := conn.LocalAddr(); !reflect.DeepEqual(got, localAddr) {
+		t.Fatalf("unexpected local address -- got %v, want %v", got,
+			localAddr)
+	}
+	testEcho(t, conn)
+
+	// Ensure connections are accepted and report the remote destination.
+	conn, err = s.Accept()
+	if err != nil {
+		t.Fatalf("unexpected accept error: %v", err)
+	}
+	defer conn.Close()
+	peerAddr := &Addr{Host: testHost(peerDest)}
+	if got := conn.RemoteAddr(); !reflect.DeepEqual(got, peerAddr) {
+		t.Fatalf("unexpected remote address -- got %v, want %v", got,
+			peerAddr)
+	}
+	testEcho(t, conn)
+
+	// Ensure dialing unknown destinations and addresses that are not I2P
+	// addresses fail with the expected errors.
+	unknownAddr := &Addr{Host: testHost(testDest(5))}
+	_, err = s.Dial(ctx, "tcp", unknownAddr.String())
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrRequestFailed)
+	}
+	_, err = s.Dial(ctx, "tcp", "127.0.0.1:9108")
+	if !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrInvalidAddress)
+	}
+}
+
+// TestSessionRecreate ensures sessions that the SAM bridge no longer knows
+// about are recreated with the same destination.
+func TestSessionRecreate(t *testing.T) {
+	localDest, remoteDest := testDest(1), testDest(3)
+	privKey := append(append([]byte(nil), localDest...), testDest(4)...)
+	sam := newFakeSAM(t, privKey, nil, remoteDest)
+
+	ctx := context.Background()
+	s, err := NewSession(ctx, &Config{SAMAddr: sam.listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("unexpected error creating session: %v", err)
+	}
+	defer s.Close()
+
+	// Ensure dialing fails once the SAM bridge forgets the session and that
+	// the next dial succeeds with a new session that uses the same
+	// destination.
+	sam.forgetSessions()
+	remoteAddr := &Addr{Host: testHost(remoteDest)}
+	_, err = s.Dial(ctx, "tcp", remoteAddr.String())
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrRequestFailed)
+	}
+	conn, err := s.Dial(ctx, "tcp", remoteAddr.String())
+	if err != nil {
+		t.Fatalf("unexpected dial error: %v", err)
+	}
+	defer conn.Close()
+	testEcho(t, conn)
+
+	wantCreates := []string{"TRANSIENT", destEncoding.EncodeToString(privKey)}
+	creates := sam.sessionCreates()
+	if !reflect.DeepEqual(creates, wantCreates) {
+		t.Fatalf("unexpected session creations -- got %v, want %v", creates,
+			wantCreates)
+	}
+}
+
+// TestSessionErrors ensures failures to create sessions are reported and that
+// closing a session unblocks pending accepts.
+func TestSessionErrors(t *testing.T) {
+	localDest := testDest(1)
+	privKey := append(append([]byte(nil), localDest...), testDest(4)...)
+	sam := newFakeSAM(t, privKey, nil)
+	sam.sessionResult = "I2P_ERROR"
+
+	ctx := context.Background()
+	cfg := &Config{SAMAddr: sam.listener.Addr().String()}
+	_, err := NewSession(ctx, cfg)
+	if !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrRequestFailed)
+	}
+
+	// Ensure closing the session unblocks a pending accept.
+	sam.sessionResult = "OK"
+	sam.blockAccept = true
+	s, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating session: %v", err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := s.Accept()
+		errChan <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("unexpected error -- got %v, want %v", err,
+				net.ErrClosed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("accept did not return after close")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package i2p
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// samVersion is the version of the SAM protocol that is negotiated with
+	// the SAM bridge.
+	samVersion = "3.1"
+
+	// requestTimeout is the maximum amount of time allowed for the SAM bridge
+	// to reply to a request.  It is fairly long since replies to some
+	// requests, such as creating sessions and connecting to destinations,
+	// are not sent until I2P tunnels are built.
+	requestTimeout = time.Minute
+
+	// maxReplyLen is the maximum allowed length of a reply line from the SAM
+	// bridge.  It is large enough to hold private keys for all signature
+	// types.
+	maxReplyLen = 8192
+
+	// destMinLen is the minimum length of a serialized I2P destination, which
+	// consists of a 256-byte public key, a 128-byte signing public key, and a
+	// certificate with a 1-byte type and 2-byte payload length.
+	destMinLen = 256 + 128 + 1 + 2
+
+	// hostSuffix is the suffix of the host of an I2P address.
+	hostSuffix = ".b32.i2p"
+)
+
+var (
+	// ErrInvalidReply indicates the SAM bridge sent a reply that is malformed
+	// or is not the reply to the request that was sent.
+	ErrInvalidReply = errors.New("invalid SAM reply")
+
+	// ErrRequestFailed indicates the SAM bridge reported that a request
+	// failed.
+	ErrRequestFailed = errors.New("SAM request failed")
+
+	// ErrInvalidDestination indicates an I2P destination or private key is
+	// not properly encoded.
+	ErrInvalidDestination = errors.New("invalid I2P destination")
+
+	// ErrInvalidAddress indicates an address is not an I2P address.
+	ErrInvalidAddress = errors.New("invalid I2P address")
+)
+
+// destEncoding is the base64 encoding used by I2P, which substitutes '-' and
+// '~' for '+' and '/'.
+var destEncoding = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+	"abcdefghijklmnopqrstuvwxyz0123456789-~")
+
+// hostEncoding is the base32 encoding used by I2P addresses, which omits the
+// padding.
+var hostEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// reply houses a parsed reply from the SAM bridge.
+type reply struct {
+	cmd  string
+	args map[string]string
+}
+
+// parseReply parses a reply line from the SAM bridge, which consists of a
+// two-word command followed by key=value pairs whose values may be quoted.
+func parseReply(line string) (*reply, error) {
+	var fields []string
+	var field strings.Builder
+	var quoted, started bool
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case r == ' ' && !quoted:
+			if started {
+				fields = append(fields, field.String())
+				field.Reset()
+				started = false
+			}
+		default:
+			field.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, field.String())
+	}
+	if quoted || len(fields) < 2 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidReply, line)
+	}
+
+	r := &reply{
+		cmd:  fields[0] + " " + fields[1],
+		args: make(map[string]string, len(fields)-2),
+	}
+	for _, f := range fields[2:] {
+		idx := strings.IndexByte(f, '=')
+		if idx < 0 {
+			r.args[f] = ""
+			continue
+		}
+		r.args[f[:idx]] = f[idx+1:]
+	}
+	return r, nil
+}
+
+// readLine reads a single newline-terminated line from the connection.  It
+// reads a byte at a time to ensure no data that follows the line, such as
+// stream data after a successful connect or accept, is consumed.
+func readLine(conn net.Conn) (string, error) {
+	var line []byte
+	var b [1]byte
+	for {
+		if _, err := conn.Read(b[:]); err != nil {
+			return "", err
+		}
+		if b[0] == '\n' {
+			return string(line), nil
+		}
+		if len(line) >= maxReplyLen {
+			return "", fmt.Errorf("%w: line exceeds %d bytes",
+				ErrInvalidReply, maxReplyLen)
+		}
+		line = append(line, b[0])
+	}
+}
+
+// request sends the provided request to the SAM bridge and returns its reply
+// after ensuring it is the expected reply and indicates success.
+func request(ctx context.Context, conn net.Conn, req, wantCmd string) (*reply, error) {
+	deadline := time.Now().Add(requestTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := conn.Write([]byte(req + "\n")); err != nil {
+		return nil, err
+	}
+	line, err := readLine(conn)
+	if err != nil {
+		return nil, err
+	}
+	r, err := parseReply(line)
+	if err != nil {
+		return nil, err
+	}
+	if r.cmd != wantCmd {
+		return nil, fmt.Errorf("%w: got %q, want %q", ErrInvalidReply, r.cmd,
+			wantCmd)
+	}
+	if result := r.args["RESULT"]; result != "OK" {
+		return r, fmt.Errorf("%w: %s %s: %s", ErrRequestFailed, wantCmd,
+			result, r.args["MESSAGE"])
+	}
+	return r, nil
+}
+
+// hello negotiates the SAM protocol version with the SAM bridge.  It must be
+// the first request on every connection to the bridge.
+func hello(ctx context.Context, conn net.Conn) error {
+	req := fmt.Sprintf("HELLO VERSION MIN=%s MAX=%s", samVersion, samVersion)
+	_, err := request(ctx, conn, req, "HELLO REPLY")
+	return err
+}
+
+// decodeDest decodes the I2P destination at the start of the provided base64
+// string, which is either a destination or a private key, which starts with
+// the destination it is for.
+func decodeDest(encoded string) ([]byte, error) {
+	b, err := destEncoding.DecodeString(encoded)
+	if err != nil || len(b) < destMinLen {
+		return nil, ErrInvalidDestination
+	}
+	certLen := int(binary.BigEndian.Uint16(b[destMinLen-2 : destMinLen]))
+	if len(b) < destMinLen+certLen {
+		return nil, ErrInvalidDestination
+	}
+	return b[:destMinLen+certLen], nil
+}
+
+// destHost returns the I2P address host, including the ".b32.i2p" suffix, for
+// the provided serialized destination.
+func destHost(dest []byte) string {
+	hash := sha256.Sum256(dest)
+	return strings.ToLower(hostEncoding.EncodeToString(hash[:])) + hostSuffix
+}
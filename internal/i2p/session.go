@@ -0,0 +1,419 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package i2p
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Port is the port used for all I2P addresses.  I2P does not have a
+	// notion of ports for the streaming protocol negotiated with the SAM
+	// bridge, so a port of 0 is used by convention.
+	Port = 0
+
+	// acceptRetryDelay is the amount of time Accept waits before returning an
+	// error so that callers which immediately retry do not spin while the SAM
+	// bridge is unavailable.
+	acceptRetryDelay = 5 * time.Second
+
+	// transientSigType is the signature type requested for newly created
+	// destinations, which is EdDSA-SHA512-Ed25519.
+	transientSigType = 7
+)
+
+// Addr represents the address of an I2P destination.  It implements the
+// net.Addr interface.
+type Addr struct {
+	// Host is the base32 encoded hash of the destination followed by the
+	// ".b32.i2p" suffix.
+	Host string
+}
+
+// Network returns the name of the network.  This is part of the net.Addr
+// interface.
+func (a *Addr) Network() string {
+	return "i2p"
+}
+
+// String returns the address in the form host:port.  This is part of the
+// net.Addr interface.
+func (a *Addr) String() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(Port))
+}
+
+// conn wraps a connection to the SAM bridge that has been turned into a
+// stream to a remote destination so that it reports the I2P addresses of both
+// ends.
+type conn struct {
+	net.Conn
+	local  *Addr
+	remote *Addr
+}
+
+// LocalAddr returns the I2P address of the local destination.  This is part of
+// the net.Conn interface.
+func (c *conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr returns the I2P address of the remote destination.  This is part
+// of the net.Conn interface.
+func (c *conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// DialFunc is the function used to establish connections to the SAM bridge.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Config houses the parameters used to create a session.
+type Config struct {
+	// SAMAddr is the address of the SAM bridge.
+	SAMAddr string
+
+	// PrivateKey is the base64 encoded private key of the destination to use
+	// for the session.  A new destination is created when it is empty.  The
+	// private key of the destination in use is available via the PrivateKey
+	// method of the session so it may be persisted in order to keep the same
+	// I2P address.
+	PrivateKey string
+
+	// Dial is the function used to establish connections to the SAM bridge.
+	// A net.Dialer is used when it is nil.
+	Dial DialFunc
+}
+
+// Session represents a streaming session with an I2P SAM bridge that is used
+// to both connect to and accept connections from remote I2P destinations.  It
+// implements the net.Listener interface.
+//
+// The session is transparently recreated with the same destination when the
+// SAM bridge loses it, such as when the I2P router restarts.
+type Session struct {
+	cfg  Config
+	quit chan struct{}
+
+	mtx       sync.Mutex
+	privKey   string
+	addr      *Addr
+	id        string
+	control   net.Conn
+	accepting map[net.Conn]struct{}
+	closed    bool
+}
+
+// Ensure Session implements the net.Listener interface.
+var _ net.Listener = (*Session)(nil)
+
+// NewSession creates a new streaming session with the SAM bridge specified by
+// the provided configuration.
+func NewSession(ctx context.Context, cfg *Config) (*Session, error) {
+	s := &Session{
+		cfg:       *cfg,
+		quit:      make(chan struct{}),
+		privKey:   cfg.PrivateKey,
+		accepting: make(map[net.Conn]struct{}),
+	}
+	if s.cfg.Dial == nil {
+		var dialer net.Dialer
+		s.cfg.Dial = dialer.DialContext
+	}
+	if _, err := s.session(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect establishes a new connection to the SAM bridge and negotiates the
+// protocol version.
+func (s *Session) connect(ctx context.Context) (net.Conn, error) {
+	conn, err := s.cfg.Dial(ctx, "tcp", s.cfg.SAMAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := hello(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// createSession creates a new session with the SAM bridge.  The destination of
+// any previous session is reused so the I2P address remains the same.
+//
+// This function MUST be called with the session mutex held (for writes).
+func (s *Session) createSession(ctx context.Context) error {
+	control, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var idBytes [8]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		control.Close()
+		return err
+	}
+	id := hex.EncodeToString(idBytes[:])
+	dest := s.privKey
+	if dest == "" {
+		dest = fmt.Sprintf("TRANSIENT SIGNATURE_TYPE=%d", transientSigType)
+	}
+	req := fmt.Sprintf("SESSION CREATE STYLE=STREAM ID=%s DESTINATION=%s", id,
+		dest)
+	r, err := request(ctx, control, req, "SESSION STATUS")
+	if err != nil {
+		control.Close()
+		return err
+	}
+
+	// The reply contains the private key of the destination, which starts
+	// with the destination itself.
+	privKey := r.args["DESTINATION"]
+	pubDest, err := decodeDest(privKey)
+	if err != nil {
+		control.Close()
+		return err
+	}
+
+	s.privKey = privKey
+	s.addr = &Addr{Host: destHost(pubDest)}
+	s.id = id
+	s.control = control
+	go s.monitor(control)
+	return nil
+}
+
+// session returns the id of the current session with the SAM bridge after
+// creating a new one when there is none.
+func (s *Session) session(ctx context.Context) (string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return "", net.ErrClosed
+	}
+	if s.control == nil {
+		if err := s.createSession(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.id, nil
+}
+
+// monitor waits for the provided control connection of a session to be closed
+// and clears the session when it is so that a new one is created on next use.
+// The SAM bridge never sends anything on the control connection after the
+// session is created with the negotiated protocol version, so any read
+// returning an error means the session is gone.
+//
+// This MUST be run as a goroutine.
+func (s *Session) monitor(control net.Conn) {
+	var b [1]byte
+	for {
+		if _, err := control.Read(b[:]); err != nil {
+			break
+		}
+	}
+	s.invalidate(control)
+}
+
+// invalidate closes the provided control connection and clears the session
+// when it is still the current one.
+func (s *Session) invalidate(control net.Conn) {
+	s.mtx.Lock()
+	if s.control == control {
+		s.control = nil
+		s.id = ""
+	}
+	s.mtx.Unlock()
+	control.Close()
+}
+
+// checkSessionErr invalidates the current session when the provided error
+// indicates the SAM bridge no longer knows about the session with the given
+// id.
+func (s *Session) checkSessionErr(id string, r *reply, err error) {
+	if !errors.Is(err, ErrRequestFailed) || r == nil ||
+		r.args["RESULT"] != "INVALID_ID" {
+
+		return
+	}
+	s.mtx.Lock()
+	control := s.control
+	if s.id != id {
+		control = nil
+	}
+	s.mtx.Unlock()
+	if control != nil {
+		s.invalidate(control)
+	}
+}
+
+// PrivateKey returns the base64 encoded private key of the destination used by
+// the session.
+func (s *Session) PrivateKey() string {
+	s.mtx.Lock()
+	privKey := s.privKey
+	s.mtx.Unlock()
+	return privKey
+}
+
+// Addr returns the I2P address of the destination used by the session.  This
+// is part of the net.Listener interface.
+func (s *Session) Addr() net.Addr {
+	s.mtx.Lock()
+	addr := s.addr
+	s.mtx.Unlock()
+	return addr
+}
+
+// Dial connects to the provided I2P address, which must be in the form
+// host:port where host is a .b32.i2p address.  The port is ignored since I2P
+// does not have a notion of ports.  The network is ignored as well since all
+// connections are made via the SAM bridge.  This signature matches that of
+// net.Dialer.DialContext so it may be used in its place.
+func (s *Session) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(host, hostSuffix) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+
+	id, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the full destination for the address since it is required to
+	// connect to it.
+	req := "NAMING LOOKUP NAME=" + host
+	r, err := request(ctx, c, req, "NAMING REPLY")
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	dest := r.args["VALUE"]
+
+	req = fmt.Sprintf("STREAM CONNECT ID=%s DESTINATION=%s SILENT=false", id,
+		dest)
+	r, err = request(ctx, c, req, "STREAM STATUS")
+	if err != nil {
+		c.Close()
+		s.checkSessionErr(id, r, err)
+		return nil, err
+	}
+
+	local := s.Addr().(*Addr)
+	return &conn{Conn: c, local: local, remote: &Addr{Host: host}}, nil
+}
+
+// accept waits for and returns the next connection from a remote destination.
+func (s *Session) accept() (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	id, err := s.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track the connection so it is closed, and therefore unblocks, when the
+	// session is closed.
+	s.mtx.Lock()
+	if s.closed {
+		s.mtx.Unlock()
+		c.Close()
+		return nil, net.ErrClosed
+	}
+	s.accepting[c] = struct{}{}
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		delete(s.accepting, c)
+		s.mtx.Unlock()
+	}()
+
+	req := fmt.Sprintf("STREAM ACCEPT ID=%s SILENT=false", id)
+	r, err := request(ctx, c, req, "STREAM STATUS")
+	if err != nil {
+		c.Close()
+		s.checkSessionErr(id, r, err)
+		return nil, err
+	}
+
+	// The SAM bridge sends the destination of the remote peer, optionally
+	// followed by additional fields, once it connects.
+	line, err := readLine(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	remoteDest, err := decodeDest(strings.SplitN(line, " ", 2)[0])
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	local := s.Addr().(*Addr)
+	remote := &Addr{Host: destHost(remoteDest)}
+	return &conn{Conn: c, local: local, remote: remote}, nil
+}
+
+// Accept waits for and returns the next connection from a remote destination.
+// This is part of the net.Listener interface.
+func (s *Session) Accept() (net.Conn, error) {
+	c, err := s.accept()
+	if err != nil {
+		if !errors.Is(err, net.ErrClosed) {
+			select {
+			case <-time.After(acceptRetryDelay):
+			case <-s.quit:
+			}
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the session along with any pending accepts.  Connections
+// previously returned from Dial and Accept are not affected.  This is part of
+// the net.Listener interface.
+func (s *Session) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return net.ErrClosed
+	}
+	s.closed = true
+	close(s.quit)
+	for c := range s.accepting {
+		c.Close()
+	}
+	if s.control != nil {
+		s.control.Close()
+		s.control = nil
+		s.id = ""
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// This file is ignored during the regular tests due to the following build tag.
+//go:build rpctest
+
+package rpctests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/internal/peertest"
+	"github.com/decred/dcrtest/dcrdtest"
+)
+
+// TestP2PConformance ensures a running node passes the peer-to-peer protocol
+// conformance scenarios.
+func TestP2PConformance(t *testing.T) {
+	// Disable banning since several scenarios intentionally misbehave and
+	// all of them connect from the same address.
+	params := chaincfg.RegNetParams()
+	args := []string{"--nobanning"}
+	harness, err := dcrdtest.New(t, params, nil, args)
+	if err != nil {
+		t.Fatalf("unable to create harness: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := harness.SetUp(ctx, false, 0); err != nil {
+		// Even though the harness was not fully setup, it still needs
+		// to be torn down to ensure all resources such as temp
+		// directories are cleaned up.  The error is intentionally
+		// ignored since this is already an error path and nothing else
+		// could be done about it anyways.
+		_ = harness.TearDown()
+		t.Fatalf("unable to setup harness: %v", err)
+	}
+	defer harness.TearDownInTest(t)
+
+	cfg := &peertest.Config{Net: params.Net, StepTimeout: 30 * time.Second}
+	for _, scenario := range peertest.ConformanceScenarios() {
+		t.Logf("=== Running scenario: %v ===", scenario.Name)
+		err := peertest.Run(ctx, cfg, harness.P2PAddress(), scenario)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package logging provides a logging backend for slog subsystem loggers that
+supports structured key-value fields and writes records in either the human
+readable text format of the slog package or as JSON objects.
+
+# Formats
+
+The text format is identical to that of slog backends, including the flags
+configured with the LOGFLAGS environment variable, with the fields of the
+logger appended to the message as key=value pairs.
+
+The JSON format writes each record as a JSON object on a single line, which
+allows log aggregation systems to index records without parsing the messages.
+For example:
+
+	{"time":"2023-06-01T12:00:00.000Z","level":"info","subsystem":"SYNC","msg":"Processed 1 block","peer":"1.2.3.4:9108"}
+
+The format of a backend may be changed at any time with SetFormat, so the
+subsystem loggers can be created before the configuration is loaded.
+
+# Fields
+
+The subsystem is always included.  Additional fields such as the peer or the
+hash of a block are added by deriving a logger with With.  Since packages are
+typically provided with a slog.Logger, the With function of this package
+derives a logger when the logger supports fields and otherwise returns it
+unchanged.
+*/
+package logging
@@ -0,0 +1,512 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/decred/slog"
+)
+
+// Format specifies the encoding of the log records written by a backend.
+type Format uint32
+
+// These constants define the supported log formats.
+const (
+	// FormatText is the human readable format of the slog package where each
+	// record is a line of the form 'YYYY-MM-DD hh:mm:ss.sss [LVL] TAG: msg'
+	// that is followed by any fields of the logger as key=value pairs.
+	FormatText Format = iota
+
+	// FormatJSON encodes each record as a JSON object on a single line with
+	// the time, level, subsystem, message, and fields of the logger as
+	// members.
+	FormatJSON
+)
+
+// String returns the name of the format as accepted by ParseFormat.
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	}
+	return "unknown"
+}
+
+// ParseFormat returns the log format with the provided name and whether or not
+// it is valid.
+func ParseFormat(s string) (Format, bool) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, true
+	case "json":
+		return FormatJSON, true
+	}
+	return FormatText, false
+}
+
+// defaultFlags are the slog backend flags configured with the LOGFLAGS
+// environment variable the same way the slog package does so that the text
+// format is identical to that of slog backends.
+var defaultFlags = func() uint32 {
+	var flags uint32
+	for _, f := range strings.Split(os.Getenv("LOGFLAGS"), ",") {
+		switch f {
+		case "longfile":
+			flags |= slog.Llongfile
+		case "shortfile":
+			flags |= slog.Lshortfile
+		case "UTC":
+			flags |= slog.LUTC
+		case "nodatetime":
+			flags |= slog.Lnodatetime
+		}
+	}
+	return flags
+}()
+
+// jsonLevels defines the level names used by the JSON format.
+var jsonLevels = [...]string{"trace", "debug", "info", "warn", "error",
+	"critical", "off"}
+
+// Backend is a logging backend that writes the records of all subsystem
+// loggers created from it to a single writer in the configured format.  The
+// format may be changed at any time, which allows the subsystem loggers to be
+// created before the configuration is loaded.
+type Backend struct {
+	w      io.Writer
+	mtx    sync.Mutex // ensures atomic writes
+	flag   uint32
+	format uint32 // Updated atomically.
+}
+
+// NewBackend returns a new logging backend that writes to the provided writer
+// using the text format.
+func NewBackend(w io.Writer) *Backend {
+	return &Backend{w: w, flag: defaultFlags}
+}
+
+// SetFormat sets the format of all records written after it returns.
+//
+// This function is safe for concurrent access.
+func (b *Backend) SetFormat(format Format) {
+	atomic.StoreUint32(&b.format, uint32(format))
+}
+
+// Format returns the current format of the backend.
+//
+// This function is safe for concurrent access.
+func (b *Backend) Format() Format {
+	return Format(atomic.LoadUint32(&b.format))
+}
+
+// Logger returns a new logger for the provided subsystem that writes to the
+// backend.  The subsystem is the tag of text records and the subsystem member
+// of JSON records.  The returned logger implements FieldLogger.
+func (b *Backend) Logger(subsystem string) slog.Logger {
+	lvl := uint32(slog.LevelInfo)
+	return &logger{b: b, subsystem: subsystem, lvl: &lvl}
+}
+
+// field is a key-value pair that is included in all records of a logger.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// makeFields returns the fields for the provided alternating keys and values.
+// Keys that are not strings are converted to strings and a missing final value
+// is reported as such rather than being silently dropped.
+func makeFields(keyvals []interface{}) []field {
+	fields := make([]field, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			key = fmt.Sprint(keyvals[i])
+		}
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fields = append(fields, field{key: key, value: value})
+	}
+	return fields
+}
+
+// callsite returns the file name and line number of the caller of the logger
+// method that is calldepth frames up the stack.
+func callsite(flag uint32, calldepth int) (string, int) {
+	_, file, line, ok := runtime.Caller(calldepth)
+	if !ok {
+		return "???", 0
+	}
+	if flag&slog.Lshortfile != 0 {
+		for i := len(file) - 1; i > 0; i-- {
+			if os.IsPathSeparator(file[i]) {
+				file = file[i+1:]
+				break
+			}
+		}
+	}
+	return file, line
+}
+
+// itoa appends the provided integer as fixed-width decimal ASCII to the
+// provided buffer.  A negative width avoids zero-padding.
+func itoa(buf []byte, i int, wid int) []byte {
+	var b [20]byte
+	bp := len(b) - 1
+	for i >= 10 || wid > 1 {
+		wid--
+		q := i / 10
+		b[bp] = byte('0' + i - q*10)
+		bp--
+		i = q
+	}
+	b[bp] = byte('0' + i)
+	return append(buf, b[bp:]...)
+}
+
+// appendTextHeader appends the header of a text record in the format
+// 'YYYY-MM-DD hh:mm:ss.sss [LVL] TAG: ' to the provided buffer.  The file name
+// and line number are included after the tag when the file is not empty and
+// the date and time are omitted when the time is zero.
+func appendTextHeader(buf []byte, t time.Time, lvl slog.Level, tag, file string, line int) []byte {
+	if !t.IsZero() {
+		year, month, day := t.Date()
+		hour, min, sec := t.Clock()
+		buf = itoa(buf, year, 4)
+		buf = append(buf, '-')
+		buf = itoa(buf, int(month), 2)
+		buf = append(buf, '-')
+		buf = itoa(buf, day, 2)
+		buf = append(buf, ' ')
+		buf = itoa(buf, hour, 2)
+		buf = append(buf, ':')
+		buf = itoa(buf, min, 2)
+		buf = append(buf, ':')
+		buf = itoa(buf, sec, 2)
+		buf = append(buf, '.')
+		buf = itoa(buf, t.Nanosecond()/1e6, 3)
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, '[')
+	buf = append(buf, lvl.String()...)
+	buf = append(buf, "] "...)
+	buf = append(buf, tag...)
+	if file != "" {
+		buf = append(buf, ' ')
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = itoa(buf, line, -1)
+	}
+	return append(buf, ": "...)
+}
+
+// needsQuoting returns whether or not the provided text field value must be
+// quoted in order to be unambiguous.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError ||
+			r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldString returns the provided field value as a string.
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// jsonValue returns the provided field value in a form that encodes to a
+// natural JSON value.  Strings, booleans, and numbers are kept as is while all
+// other values are converted to strings.
+func jsonValue(value interface{}) interface{} {
+	switch value.(type) {
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16,
+		uint32, uint64, float32, float64:
+		return value
+	}
+	return fieldString(value)
+}
+
+// appendJSONString appends the JSON encoding of the provided string to the
+// provided buffer.
+func appendJSONString(buf []byte, s string) []byte {
+	// Marshalling a string never fails.
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
+
+// write writes a record with the provided level, subsystem, message, and fields
+// in the current format of the backend.  The calldepth is the number of stack
+// frames between the caller of the logger and this function.
+func (b *Backend) write(calldepth int, lvl slog.Level, subsystem, msg string, fields []field) {
+	var t time.Time
+	if b.flag&slog.Lnodatetime == 0 {
+		t = time.Now()
+		if b.flag&slog.LUTC != 0 {
+			t = t.UTC()
+		}
+	}
+	var file string
+	var line int
+	if b.flag&(slog.Lshortfile|slog.Llongfile) != 0 {
+		file, line = callsite(b.flag, calldepth+1)
+	}
+
+	buf := make([]byte, 0, 120)
+	switch b.Format() {
+	case FormatJSON:
+		buf = append(buf, '{')
+		if !t.IsZero() {
+			buf = append(buf, `"time":`...)
+			buf = appendJSONString(buf, t.Format(
+				"2006-01-02T15:04:05.000Z07:00"))
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `"level":`...)
+		buf = appendJSONString(buf, jsonLevels[lvl])
+		buf = append(buf, `,"subsystem":`...)
+		buf = appendJSONString(buf, subsystem)
+		if file != "" {
+			buf = append(buf, `,"caller":`...)
+			buf = appendJSONString(buf, file+":"+strconv.Itoa(line))
+		}
+		buf = append(buf, `,"msg":`...)
+		buf = appendJSONString(buf, msg)
+		for _, f := range fields {
+			v, err := json.Marshal(jsonValue(f.value))
+			if err != nil {
+				// Only non-finite floats fail to encode.
+				v, _ = json.Marshal(fieldString(f.value))
+			}
+			buf = append(buf, ',')
+			buf = appendJSONString(buf, f.key)
+			buf = append(buf, ':')
+			buf = append(buf, v...)
+		}
+		buf = append(buf, "}\n"...)
+
+	default:
+		buf = appendTextHeader(buf, t, lvl, subsystem, file, line)
+		buf = append(buf, msg...)
+		for _, f := range fields {
+			v := fieldString(f.value)
+			if needsQuoting(v) {
+				v = strconv.Quote(v)
+			}
+			buf = append(buf, ' ')
+			buf = append(buf, f.key...)
+			buf = append(buf, '=')
+			buf = append(buf, v...)
+		}
+		buf = append(buf, '\n')
+	}
+
+	b.mtx.Lock()
+	b.w.Write(buf)
+	b.mtx.Unlock()
+}
+
+// FieldLogger is a subsystem logger that supports creating loggers that
+// include additional key-value fields in all of their records.
+type FieldLogger interface {
+	slog.Logger
+
+	// With returns a logger that includes the provided alternating keys and
+	// values in all of its records in addition to the fields of the logger.
+	// The returned logger shares the level of the logger.
+	With(keyvals ...interface{}) slog.Logger
+}
+
+// With returns a logger that includes the provided alternating keys and values
+// in all of its records when the provided logger implements FieldLogger.
+// Otherwise, the provided logger is returned unchanged, which allows packages
+// to add fields regardless of the logger they are configured with.
+func With(log slog.Logger, keyvals ...interface{}) slog.Logger {
+	if fl, ok := log.(FieldLogger); ok {
+		return fl.With(keyvals...)
+	}
+	return log
+}
+
+// logger is a subsystem logger that writes to a backend.  It implements the
+// FieldLogger interface.
+type logger struct {
+	b         *Backend
+	subsystem string
+	lvl       *uint32 // Updated atomically and shared with derived loggers.
+	fields    []field
+}
+
+// Ensure logger implements the FieldLogger interface.
+var _ FieldLogger = (*logger)(nil)
+
+// logDepth is the number of stack frames between the caller of a logger method
+// and the call to write.
+const logDepth = 3
+
+// enabled returns whether or not records with the provided level are written.
+func (l *logger) enabled(lvl slog.Level) bool {
+	return l.Level() <= lvl
+}
+
+// print writes a record with the provided level and the arguments formatted
+// with the default formats.
+func (l *logger) print(lvl slog.Level, args []interface{}) {
+	msg := fmt.Sprintln(args...)
+	l.b.write(logDepth, lvl, l.subsystem, msg[:len(msg)-1], l.fields)
+}
+
+// printf writes a record with the provided level and the arguments formatted
+// according to the provided format specifier.
+func (l *logger) printf(lvl slog.Level, format string, args []interface{}) {
+	l.b.write(logDepth, lvl, l.subsystem, fmt.Sprintf(format, args...),
+		l.fields)
+}
+
+// With returns a logger that includes the provided alternating keys and values
+// in all of its records in addition to the fields of the logger.
+//
+// This is part of the FieldLogger interface implementation.
+func (l *logger) With(keyvals ...interface{}) slog.Logger {
+	fields := make([]field, 0, len(l.fields)+(len(keyvals)+1)/2)
+	fields = append(fields, l.fields...)
+	fields = append(fields, makeFields(keyvals)...)
+	return &logger{b: l.b, subsystem: l.subsystem, lvl: l.lvl, fields: fields}
+}
+
+// Tracef formats message according to format specifier and writes to log with
+// LevelTrace.
+func (l *logger) Tracef(format string, args ...interface{}) {
+	if l.enabled(slog.LevelTrace) {
+		l.printf(slog.LevelTrace, format, args)
+	}
+}
+
+// Debugf formats message according to format specifier and writes to log with
+// LevelDebug.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	if l.enabled(slog.LevelDebug) {
+		l.printf(slog.LevelDebug, format, args)
+	}
+}
+
+// Infof formats message according to format specifier and writes to log with
+// LevelInfo.
+func (l *logger) Infof(format string, args ...interface{}) {
+	if l.enabled(slog.LevelInfo) {
+		l.printf(slog.LevelInfo, format, args)
+	}
+}
+
+// Warnf formats message according to format specifier and writes to log with
+// LevelWarn.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	if l.enabled(slog.LevelWarn) {
+		l.printf(slog.LevelWarn, format, args)
+	}
+}
+
+// Errorf formats message according to format specifier and writes to log with
+// LevelError.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	if l.enabled(slog.LevelError) {
+		l.printf(slog.LevelError, format, args)
+	}
+}
+
+// Criticalf formats message according to format specifier and writes to log
+// with LevelCritical.
+func (l *logger) Criticalf(format string, args ...interface{}) {
+	if l.enabled(slog.LevelCritical) {
+		l.printf(slog.LevelCritical, format, args)
+	}
+}
+
+// Trace formats message using the default formats for its operands and writes
+// to log with LevelTrace.
+func (l *logger) Trace(args ...interface{}) {
+	if l.enabled(slog.LevelTrace) {
+		l.print(slog.LevelTrace, args)
+	}
+}
+
+// Debug formats message using the default formats for its operands and writes
+// to log with LevelDebug.
+func (l *logger) Debug(args ...interface{}) {
+	if l.enabled(slog.LevelDebug) {
+		l.print(slog.LevelDebug, args)
+	}
+}
+
+// Info formats message using the default formats for its operands and writes
+// to log with LevelInfo.
+func (l *logger) Info(args ...interface{}) {
+	if l.enabled(slog.LevelInfo) {
+		l.print(slog.LevelInfo, args)
+	}
+}
+
+// Warn formats message using the default formats for its operands and writes
+// to log with LevelWarn.
+func (l *logger) Warn(args ...interface{}) {
+	if l.enabled(slog.LevelWarn) {
+		l.print(slog.LevelWarn, args)
+	}
+}
+
+// Error formats message using the default formats for its operands and writes
+// to log with LevelError.
+func (l *logger) Error(args ...interface{}) {
+	if l.enabled(slog.LevelError) {
+		l.print(slog.LevelError, args)
+	}
+}
+
+// Critical formats message using the default formats for its operands and
+// writes to log with LevelCritical.
+func (l *logger) Critical(args ...interface{}) {
+	if l.enabled(slog.LevelCritical) {
+		l.print(slog.LevelCritical, args)
+	}
+}
+
+// Level returns the current logging level.
+func (l *logger) Level() slog.Level {
+	return slog.Level(atomic.LoadUint32(l.lvl))
+}
+
+// SetLevel changes the logging level to the passed level.  It also changes the
+// level of all loggers derived from the logger with With.
+func (l *logger) SetLevel(level slog.Level) {
+	atomic.StoreUint32(l.lvl, uint32(level))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/slog"
+)
+
+// TestParseFormat ensures formats are parsed from and converted to the
+// expected names.
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		valid  bool
+	}{
+		{name: "text", format: FormatText, valid: true},
+		{name: "json", format: FormatJSON, valid: true},
+		{name: "JSON", format: FormatJSON, valid: true},
+		{name: "xml", format: FormatText, valid: false},
+	}
+
+	for _, test := range tests {
+		format, ok := ParseFormat(test.name)
+		if format != test.format || ok != test.valid {
+			t.Errorf("%q: mismatched format -- got %v (valid %v), want %v "+
+				"(valid %v)", test.name, format, ok, test.format, test.valid)
+			continue
+		}
+		if ok && format.String() != strings.ToLower(test.name) {
+			t.Errorf("%q: mismatched format name -- got %q", test.name,
+				format.String())
+		}
+	}
+}
+
+// TestTextFormat ensures records are written in the text format with the
+// fields of the logger appended.
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewBackend(&buf)
+	b.flag = slog.Lnodatetime
+	log := b.Logger("TEST")
+
+	log.Infof("processed %d blocks", 2)
+	log.Debugf("filtered")
+	With(log, "peer", "1.2.3.4:9108", "reason", "bad block", "n", 5,
+		"err", errors.New("oops"), "missing").Warn("rejected", "block")
+
+	want := "[INF] TEST: processed 2 blocks\n" +
+		"[WRN] TEST: rejected block peer=1.2.3.4:9108 reason=\"bad block\" " +
+		"n=5 err=oops missing=(MISSING)\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("mismatched text output -- got %q, want %q", got, want)
+	}
+}
+
+// TestJSONFormat ensures records are written as JSON objects with the
+// subsystem, message, and fields of the logger as members.
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewBackend(&buf)
+	b.flag = slog.Lnodatetime
+	b.SetFormat(FormatJSON)
+	log := b.Logger("SYNC")
+	log.SetLevel(slog.LevelDebug)
+
+	plog := With(log, "peer", "1.2.3.4:9108", "height", int64(100))
+	plog.Debugf("received block %q", "x")
+	With(plog, "valid", true).Error("failed")
+
+	want := []map[string]interface{}{{
+		"level":     "debug",
+		"subsystem": "SYNC",
+		"msg":       `received block "x"`,
+		"peer":      "1.2.3.4:9108",
+		"height":    float64(100),
+	}, {
+		"level":     "error",
+		"subsystem": "SYNC",
+		"msg":       "failed",
+		"peer":      "1.2.3.4:9108",
+		"height":    float64(100),
+		"valid":     true,
+	}}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("mismatched number of records -- got %d, want %d",
+			len(lines), len(want))
+	}
+	for i, line := range lines {
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("record %d is not valid JSON: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("mismatched record %d -- got %v, want %v", i, got,
+				want[i])
+		}
+	}
+}
+
+// TestDerivedLevel ensures loggers derived with With share the level of the
+// logger they are derived from.
+func TestDerivedLevel(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewBackend(&buf)
+	log := b.Logger("TEST")
+	derived := With(log, "key", "value")
+
+	log.SetLevel(slog.LevelOff)
+	if got := derived.Level(); got != slog.LevelOff {
+		t.Fatalf("mismatched derived level -- got %v, want %v", got,
+			slog.LevelOff)
+	}
+	derived.Critical("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected output with logging disabled: %q", buf.String())
+	}
+}
+
+// TestWithUnsupported ensures With returns loggers that do not support fields
+// unchanged.
+func TestWithUnsupported(t *testing.T) {
+	if got := With(slog.Disabled, "key", "value"); got != slog.Disabled {
+		t.Fatalf("mismatched logger -- got %v, want slog.Disabled", got)
+	}
+}
+
+// TestCallsite ensures the callsite reported when the file flags are set is
+// that of the caller of the logger.
+func TestCallsite(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewBackend(&buf)
+	b.flag = slog.Lnodatetime | slog.Lshortfile
+	b.Logger("TEST").Info("msg")
+	if got := buf.String(); !strings.HasPrefix(got,
+		"[INF] TEST logging_test.go:") {
+
+		t.Fatalf("unexpected callsite in %q", got)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package logrotate provides a log file writer that rotates the log file based on
+its size and the time, compresses the rotated log files, and removes them
+according to a retention policy.
+
+# Rotation
+
+The log file is rotated once it reaches a maximum size and at the end of each
+rotation interval, which are aligned to multiples of the interval in UTC.  Log
+files are only rotated after writes that end with a newline so log lines are
+never split between files.
+
+Rotated log files are named after the log file with an increasing roll number
+appended, such as dcrd.log.1, dcrd.log.2, and so on, and an additional .gz
+extension when they are compressed with gzip.  The roll number of the newest
+rotated log file is always the highest.
+
+# Retention
+
+Rotated log files are removed, oldest first, once the configured maximum number
+of rotated log files, maximum age, or maximum total size is exceeded.
+Compression and removal happen in the background so they never block writes.
+*/
+package logrotate
@@ -0,0 +1,370 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logrotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errOutput is where failures to rotate, compress, and prune log files are
+// reported since they can not be logged to the log file itself.
+var errOutput io.Writer = os.Stderr
+
+// closeBeforeRename specifies whether the current log file must be closed
+// before it is renamed.  Files are opened without FILE_SHARE_DELETE on Windows,
+// so renaming the log file while it is open always fails there.
+var closeBeforeRename = runtime.GOOS == "windows"
+
+// openFile opens log files.  It is a variable so that tests can force opening
+// the log file to fail.
+var openFile = os.OpenFile
+
+// reportError writes the provided formatted error message to errOutput.
+func reportError(format string, args ...interface{}) {
+	fmt.Fprintf(errOutput, "logrotate: "+format+"\n", args...)
+}
+
+// Config is a descriptor containing the log rotation configuration.
+type Config struct {
+	// Filename is the path of the log file.  Rotated log files are stored in
+	// the same directory with an increasing roll number appended to the name,
+	// such as dcrd.log.1, and an additional .gz extension when compressed.
+	Filename string
+
+	// MaxSize is the size in bytes after which the log file is rotated.  The
+	// log file is not rotated based on its size when it is zero.
+	MaxSize int64
+
+	// Interval is the interval at which the log file is rotated.  Rotations
+	// are aligned to multiples of the interval in UTC, so, for example, an
+	// interval of 24 hours rotates the log file at midnight UTC.  The log file
+	// is not rotated based on time when it is zero.
+	Interval time.Duration
+
+	// Compress specifies whether or not rotated log files are compressed with
+	// gzip.
+	Compress bool
+
+	// MaxRolls is the maximum number of rotated log files that are kept.  The
+	// number is not limited when it is zero.
+	MaxRolls int
+
+	// MaxAge is the maximum amount of time since rotated log files were last
+	// modified before they are removed.  The age is not limited when it is
+	// zero.
+	MaxAge time.Duration
+
+	// MaxTotalSize is the maximum total size in bytes of the rotated log files
+	// that are kept.  The oldest rotated log files are removed first.  The
+	// total size is not limited when it is zero.
+	MaxTotalSize int64
+}
+
+// Rotator writes to a log file and rotates it according to the configured size
+// and time thresholds.  Rotated log files are optionally compressed and removed
+// according to the configured retention policy in the background.
+type Rotator struct {
+	cfg Config
+
+	mtx        sync.Mutex
+	out        *os.File
+	size       int64
+	nextRotate time.Time
+
+	// pruneMtx serializes compressing and removing rotated log files and wg
+	// tracks the background goroutines that do so.
+	pruneMtx sync.Mutex
+	wg       sync.WaitGroup
+}
+
+// New returns a new rotator that appends to the configured log file.  The log
+// file is rotated immediately when it already exceeds the maximum size or when
+// it was last modified before the start of the current rotation interval.
+func New(cfg *Config) (*Rotator, error) {
+	f, err := openFile(cfg.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		0644)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r := &Rotator{cfg: *cfg, out: f, size: stat.Size()}
+	rotate := r.cfg.MaxSize > 0 && r.size >= r.cfg.MaxSize
+	if r.cfg.Interval > 0 {
+		start := time.Now().Truncate(r.cfg.Interval)
+		r.nextRotate = start.Add(r.cfg.Interval)
+		if r.size > 0 && stat.ModTime().Before(start) {
+			rotate = true
+		}
+	}
+	if rotate {
+		if err := r.rotate(); err != nil {
+			r.out.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Write writes the provided bytes to the log file.  The log file is rotated
+// after the write when it ends with a newline and either the maximum size is
+// reached or the current rotation interval has ended.  This ensures log lines
+// are never split between files.
+//
+// Failures to rotate the log file are reported on stderr instead of being
+// returned since the bytes were written.  Writes continue to the current log
+// file in that case and rotation is attempted again once the maximum size is
+// reached or the rotation interval ends again.
+//
+// This function is safe for concurrent access.
+func (r *Rotator) Write(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	n, err := r.out.Write(p)
+	r.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		return n, nil
+	}
+
+	sizeReached := r.cfg.MaxSize > 0 && r.size >= r.cfg.MaxSize
+	intervalEnded := !r.nextRotate.IsZero() && !time.Now().Before(r.nextRotate)
+	if sizeReached || intervalEnded {
+		if err := r.rotate(); err != nil {
+			reportError("unable to rotate log file %s: %v", r.cfg.Filename,
+				err)
+			r.resetThresholds()
+		}
+	}
+	return n, nil
+}
+
+// Close closes the log file and waits for any rotated log files to be
+// compressed and pruned.
+//
+// This function is safe for concurrent access.
+func (r *Rotator) Close() error {
+	r.mtx.Lock()
+	err := r.out.Close()
+	r.mtx.Unlock()
+	r.wg.Wait()
+	return err
+}
+
+// roll describes a rotated log file.
+type roll struct {
+	num  int
+	path string
+}
+
+// rolls returns the rotated log files of the log file sorted from oldest to
+// newest.
+func (r *Rotator) rolls() ([]roll, error) {
+	paths, err := filepath.Glob(r.cfg.Filename + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(r.cfg.Filename) + "."
+	rolls := make([]roll, 0, len(paths))
+	for _, path := range paths {
+		suffix := strings.TrimPrefix(filepath.Base(path), prefix)
+		num, err := strconv.Atoi(strings.TrimSuffix(suffix, ".gz"))
+		if err != nil || num < 1 {
+			continue
+		}
+		rolls = append(rolls, roll{num: num, path: path})
+	}
+	sort.Slice(rolls, func(i, j int) bool {
+		return rolls[i].num < rolls[j].num
+	})
+	return rolls, nil
+}
+
+// resetThresholds resets the size and next rotation time tracked for the
+// current log file to their values for a newly rotated log file.
+//
+// This function MUST be called with the rotator mutex held (for writes).
+func (r *Rotator) resetThresholds() {
+	r.size = 0
+	if r.cfg.Interval > 0 {
+		r.nextRotate = time.Now().Truncate(r.cfg.Interval).Add(r.cfg.Interval)
+	}
+}
+
+// rotate renames the current log file to the next roll number, opens a new log
+// file in its place, and compresses and prunes the rotated log files in the
+// background.  The current log file is only closed once the new one is open so
+// that it remains usable when rotation fails.  On platforms that do not permit
+// renaming open files, it is instead closed before the rename and reopened
+// when rotation fails.
+//
+// This function MUST be called with the rotator mutex held (for writes).
+func (r *Rotator) rotate() error {
+	rolls, err := r.rolls()
+	if err != nil {
+		return err
+	}
+	num := 1
+	if len(rolls) > 0 {
+		num = rolls[len(rolls)-1].num + 1
+	}
+
+	rotated := fmt.Sprintf("%s.%d", r.cfg.Filename, num)
+	if closeBeforeRename {
+		if err := r.out.Close(); err != nil {
+			reportError("unable to close log file %s: %v", r.cfg.Filename,
+				err)
+		}
+	}
+	if err := os.Rename(r.cfg.Filename, rotated); err != nil {
+		if closeBeforeRename {
+			r.reopen()
+		}
+		return err
+	}
+	out, err := openFile(r.cfg.Filename, os.O_CREATE|os.O_TRUNC|
+		os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		// Restore the name of the current log file since writes continue to
+		// it.  There is nothing more that can be done when that fails too.
+		if rerr := os.Rename(rotated, r.cfg.Filename); rerr != nil {
+			reportError("unable to restore log file %s: %v",
+				r.cfg.Filename, rerr)
+		}
+		if closeBeforeRename {
+			r.reopen()
+		}
+		return err
+	}
+	if !closeBeforeRename {
+		if err := r.out.Close(); err != nil {
+			reportError("unable to close rotated log file %s: %v", rotated,
+				err)
+		}
+	}
+	r.out = out
+	r.resetThresholds()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.pruneMtx.Lock()
+		defer r.pruneMtx.Unlock()
+
+		if r.cfg.Compress {
+			if err := compress(rotated); err != nil {
+				reportError("unable to compress rotated log file %s: %v",
+					rotated, err)
+			} else {
+				os.Remove(rotated)
+			}
+		}
+		r.prune()
+	}()
+	return nil
+}
+
+// reopen reopens the current log file for appending after it was closed by a
+// failed rotation.  Further writes fail when it can not be reopened.
+//
+// This function MUST be called with the rotator mutex held (for writes).
+func (r *Rotator) reopen() {
+	out, err := openFile(r.cfg.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		0644)
+	if err != nil {
+		reportError("unable to reopen log file %s: %v", r.cfg.Filename, err)
+		return
+	}
+	r.out = out
+}
+
+// prune removes the rotated log files that are not retained according to the
+// configured retention policy.
+//
+// This function MUST be called with the prune mutex held.
+func (r *Rotator) prune() {
+	if r.cfg.MaxRolls == 0 && r.cfg.MaxAge == 0 && r.cfg.MaxTotalSize == 0 {
+		return
+	}
+	rolls, err := r.rolls()
+	if err != nil {
+		reportError("unable to list rotated log files of %s: %v",
+			r.cfg.Filename, err)
+		return
+	}
+
+	// Iterate from the newest rotated log file to the oldest so that the
+	// oldest ones are removed first once a limit is exceeded.
+	now := time.Now()
+	var kept int
+	var totalSize int64
+	var limitExceeded bool
+	for i := len(rolls) - 1; i >= 0; i-- {
+		path := rolls[i].path
+		stat, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		totalSize += stat.Size()
+		limitExceeded = limitExceeded ||
+			(r.cfg.MaxRolls > 0 && kept >= r.cfg.MaxRolls) ||
+			(r.cfg.MaxAge > 0 && now.Sub(stat.ModTime()) > r.cfg.MaxAge) ||
+			(r.cfg.MaxTotalSize > 0 && totalSize > r.cfg.MaxTotalSize)
+		if limitExceeded {
+			if err := os.Remove(path); err != nil {
+				reportError("unable to remove rotated log file %s: %v", path,
+					err)
+			}
+			continue
+		}
+		kept++
+	}
+}
+
+// compress writes a gzip compressed copy of the provided file to a file with
+// the same name and an additional .gz extension.
+func compress(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	arc, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_EXCL|os.O_WRONLY,
+		0644)
+	if err != nil {
+		return err
+	}
+	z := gzip.NewWriter(arc)
+	if _, err := io.Copy(z, f); err != nil {
+		arc.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := z.Close(); err != nil {
+		arc.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	return arc.Close()
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package logrotate
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rolledFiles returns the sorted base names of the rotated log files of the
+// provided log file.
+func rolledFiles(t *testing.T, filename string) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(filename + ".*")
+	if err != nil {
+		t.Fatalf("unexpected glob error: %v", err)
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeLines writes the provided lines to the rotator.
+func writeLines(t *testing.T, r *Rotator, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if _, err := r.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+}
+
+// readGzip returns the decompressed contents of the provided gzip file.
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected open error: %v", err)
+	}
+	defer f.Close()
+	z, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("unexpected gzip error: %v", err)
+	}
+	b, err := io.ReadAll(z)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	return string(b)
+}
+
+// TestSizeRotation ensures the log file is rotated and compressed once it
+// reaches the maximum size and that lines are never split between files.
+func TestSizeRotation(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	r, err := New(&Config{Filename: filename, MaxSize: 10, Compress: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating rotator: %v", err)
+	}
+
+	// Write a partial line that exceeds the maximum size followed by the rest
+	// of the line to ensure it is not split.
+	if _, err := r.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	writeLines(t, r, "abc", "second line")
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	want := []string{"test.log.1.gz", "test.log.2.gz"}
+	if got := rolledFiles(t, filename); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched rotated files -- got %v, want %v", got, want)
+	}
+	if got := readGzip(t, filename+".1.gz"); got != "0123456789abc\n" {
+		t.Fatalf("mismatched first rotated file contents -- got %q", got)
+	}
+	if got := readGzip(t, filename+".2.gz"); got != "second line\n" {
+		t.Fatalf("mismatched second rotated file contents -- got %q", got)
+	}
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if len(b) != 0 {
+		t.Fatalf("unexpected log file contents %q", b)
+	}
+}
+
+// TestRotationFailure ensures failures to rotate the log file are reported and
+// that writes continue to the current log file.
+func TestRotationFailure(t *testing.T) {
+	var reported bytes.Buffer
+	errOutput = &reported
+	defer func() { errOutput = os.Stderr }()
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatalf("unexpected mkdir error: %v", err)
+	}
+	filename := filepath.Join(dir, "test.log")
+	r, err := New(&Config{Filename: filename, MaxSize: 10})
+	if err != nil {
+		t.Fatalf("unexpected error creating rotator: %v", err)
+	}
+	defer r.Close()
+
+	// Remove the directory of the log file so that renaming it fails and
+	// ensure the failure is reported while writes continue to succeed.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("unexpected remove error: %v", err)
+	}
+	writeLines(t, r, "0123456789")
+	if !strings.Contains(reported.String(), "unable to rotate log file") {
+		t.Fatalf("rotation failure not reported -- got %q",
+			reported.String())
+	}
+
+	// Ensure rotation is not attempted again until the maximum size is
+	// reached again.
+	reported.Reset()
+	writeLines(t, r, "abc")
+	if reported.Len() != 0 {
+		t.Fatalf("unexpected reported errors %q", reported.String())
+	}
+	writeLines(t, r, "0123456789")
+	if !strings.Contains(reported.String(), "unable to rotate log file") {
+		t.Fatalf("rotation failure not reported -- got %q",
+			reported.String())
+	}
+}
+
+// TestRotationOpenFailure ensures the log file keeps its name and writes
+// continue to it when opening the new log file fails during rotation, both when
+// the current log file is renamed while open and when it is closed first.
+func TestRotationOpenFailure(t *testing.T) {
+	var reported bytes.Buffer
+	errOutput = &reported
+	defer func() {
+		errOutput = os.Stderr
+		closeBeforeRename = runtime.GOOS == "windows"
+		openFile = os.OpenFile
+	}()
+
+	for _, closeFirst := range []bool{false, true} {
+		closeBeforeRename = closeFirst
+		openFile = os.OpenFile
+		reported.Reset()
+
+		filename := filepath.Join(t.TempDir(), "test.log")
+		r, err := New(&Config{Filename: filename, MaxSize: 10})
+		if err != nil {
+			t.Fatalf("unexpected error creating rotator: %v", err)
+		}
+
+		// Force opening the new log file to fail.
+		errOpen := errors.New("forced open failure")
+		var failed bool
+		openFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+			if !failed {
+				failed = true
+				return nil, errOpen
+			}
+			return os.OpenFile(name, flag, perm)
+		}
+		writeLines(t, r, "0123456789")
+		if !strings.Contains(reported.String(), errOpen.Error()) {
+			t.Fatalf("%v: rotation failure not reported -- got %q",
+				closeFirst, reported.String())
+		}
+		if got := rolledFiles(t, filename); len(got) != 0 {
+			t.Fatalf("%v: unexpected rotated files %v", closeFirst, got)
+		}
+
+		writeLines(t, r, "abc")
+		if err := r.Close(); err != nil {
+			t.Fatalf("%v: unexpected close error: %v", closeFirst, err)
+		}
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("%v: unexpected read error: %v", closeFirst, err)
+		}
+		if want := "0123456789\nabc\n"; string(b) != want {
+			t.Fatalf("%v: mismatched log file contents -- got %q, want %q",
+				closeFirst, b, want)
+		}
+	}
+}
+
+// TestIntervalRotation ensures the log file is rotated once the rotation
+// interval ends as well as on creation when the existing log file was last
+// modified during a previous interval.
+func TestIntervalRotation(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(filename, []byte("stale\n"), 0644); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filename, old, old); err != nil {
+		t.Fatalf("unexpected chtimes error: %v", err)
+	}
+
+	cfg := Config{Filename: filename, Interval: 24 * time.Hour}
+	r, err := New(&cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating rotator: %v", err)
+	}
+	writeLines(t, r, "current")
+
+	// Simulate the end of the interval.
+	r.mtx.Lock()
+	r.nextRotate = time.Now().Add(-time.Second)
+	r.mtx.Unlock()
+	writeLines(t, r, "last")
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	want := []string{"test.log.1", "test.log.2"}
+	if got := rolledFiles(t, filename); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched rotated files -- got %v, want %v", got, want)
+	}
+	for i, want := range []string{"stale\n", "current\nlast\n"} {
+		path := fmt.Sprintf("%s.%d", filename, i+1)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		if string(b) != want {
+			t.Fatalf("mismatched contents of %s -- got %q, want %q", path, b,
+				want)
+		}
+	}
+}
+
+// TestRetention ensures rotated log files are removed, oldest first, according
+// to the retention policy.
+func TestRetention(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{{
+		name: "max rolls",
+		cfg:  Config{MaxRolls: 2},
+		want: []string{"test.log.4", "test.log.5"},
+	}, {
+		name: "max age",
+		cfg:  Config{MaxAge: time.Hour},
+		want: []string{"test.log.3", "test.log.4", "test.log.5"},
+	}, {
+		name: "max total size",
+		cfg:  Config{MaxTotalSize: 25},
+		want: []string{"test.log.3", "test.log.4", "test.log.5"},
+	}, {
+		name: "unlimited",
+		cfg:  Config{},
+		want: []string{"test.log.1", "test.log.2", "test.log.3",
+			"test.log.4", "test.log.5"},
+	}}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "test.log")
+
+		// Create existing rotated log files that are 8 bytes each where the
+		// first two were last modified two hours ago.  Note that the one
+		// created by the rotator below is 8 bytes as well.
+		for i := 1; i <= 4; i++ {
+			path := fmt.Sprintf("%s.%d", filename, i)
+			if err := os.WriteFile(path, []byte(strings.Repeat("x", 7)+"\n"),
+				0644); err != nil {
+
+				t.Fatalf("%s: unexpected write error: %v", test.name, err)
+			}
+			if i <= 2 {
+				old := time.Now().Add(-2 * time.Hour)
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatalf("%s: unexpected chtimes error: %v", test.name,
+						err)
+				}
+			}
+		}
+
+		cfg := test.cfg
+		cfg.Filename = filename
+		cfg.MaxSize = 8
+		r, err := New(&cfg)
+		if err != nil {
+			t.Fatalf("%s: unexpected error creating rotator: %v", test.name,
+				err)
+		}
+		writeLines(t, r, "1234567")
+		if err := r.Close(); err != nil {
+			t.Fatalf("%s: unexpected close error: %v", test.name, err)
+		}
+
+		if got := rolledFiles(t, filename); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: mismatched rotated files -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
@@ -60,6 +60,7 @@ be an exhaustive list.
 # Configurable Transaction Acceptance Policy
 
   - Option to accept or reject standard transactions
+  - Extensible chain of policies that define standard transactions
   - Option to accept or reject transactions based on priority calculations
   - Minimum fee threshold
   - Max signature operations per transaction
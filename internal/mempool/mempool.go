@@ -8,6 +8,7 @@ package mempool
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,7 +20,9 @@ import (
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/metrics"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/tracing"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/wire"
 )
@@ -56,6 +59,16 @@ const (
 	// scans of the orphan pool to evict expired transactions.
 	orphanExpireScanInterval = time.Minute * 5
 
+	// feeDeltaTTL is the maximum amount of time a fee delta set via
+	// PrioritiseTransaction for a transaction that is not in the pool is
+	// retained before it is removed.
+	feeDeltaTTL = time.Hour * 24
+
+	// maxFeeDeltas is the maximum number of fee deltas set via
+	// PrioritiseTransaction that are retained.  The oldest deltas are removed
+	// to make room for new ones once the limit is reached.
+	maxFeeDeltas = 10000
+
 	// MempoolMaxConcurrentTSpends is the maximum number of TSpends that
 	// are allowed in the mempool. The number 7 is also the amount of
 	// physical space available for TSpend votes and thus is a hard limit.
@@ -154,9 +167,29 @@ type Config struct {
 	// tspend in the mempool.
 	OnTSpendReceived func(voteTx *dcrutil.Tx)
 
+	// OnTxEvent defines the function used to signal transactions being added
+	// to and removed from the main pool.  It is invoked synchronously with the
+	// mempool lock held in the order the events happen, so it must not call
+	// back into the pool and should return quickly.
+	OnTxEvent func(event *TxEvent)
+
 	// TSpendMinedOnAncestor returns an error if the provided tspend has
 	// been mined in an ancestor block.
 	TSpendMinedOnAncestor func(tspend chainhash.Hash) error
+
+	// AcceptedTxns, OrphanedTxns, and RejectedTxns define optional metrics
+	// that track the number of processed transactions that were accepted to
+	// the main pool, added to the orphan pool, and rejected by the kind of
+	// error, respectively.  Transactions that are accepted as a result of
+	// their orphaned ancestors being accepted are included in the accepted
+	// transactions.  Nothing is tracked for the metrics that are nil.
+	AcceptedTxns *metrics.Counter
+	OrphanedTxns *metrics.Counter
+	RejectedTxns *metrics.CounterVec
+
+	// Tracer defines an optional tracer that is used to trace the processing
+	// of transactions.  Nothing is traced when it is nil.
+	Tracer *tracing.Tracer
 }
 
 // Policy houses the policy (configuration parameters) which is used to
@@ -168,6 +201,12 @@ type Policy struct {
 	// network. Otherwise, all non-standard transactions will be rejected.
 	AcceptNonStd bool
 
+	// TxPolicies is the ordered chain of policies that transactions must
+	// satisfy in addition to the consensus rules in order to be accepted.
+	// The policies returned by DefaultTxPolicies are used when it is nil.
+	// None of the policies are applied when AcceptNonStd is true.
+	TxPolicies []TxPolicy
+
 	// MaxOrphanTxs is the maximum number of orphan transactions
 	// that can be queued.
 	MaxOrphanTxs int
@@ -177,12 +216,23 @@ type Policy struct {
 	// of big orphans.
 	MaxOrphanTxSize int
 
+	// MaxOrphanTxsPerTag is the maximum number of orphan transactions with
+	// the same tag that can be queued.  Since tags are typically peer IDs,
+	// this prevents a single peer from monopolizing the orphan pool.  A value
+	// of zero disables the per-tag limit.
+	MaxOrphanTxsPerTag int
+
+	// MaxOrphanPoolSize is the maximum total serialized size in bytes of all
+	// orphan transactions that can be queued.  A value of zero disables the
+	// size limit so that only the number of orphans is limited.
+	MaxOrphanPoolSize int
+
 	// MaxSigOpsPerTx is the maximum number of signature operations
 	// in a single transaction we will relay or mine.  It is a fraction
 	// of the max signature operations for a block.
 	MaxSigOpsPerTx int
 
-	// MinRelayTxFee defines the minimum transaction fee in DCR/kB to be
+	// MinRelayTxFee defines the minimum transaction fee in atoms/kB to be
 	// considered a non-zero fee.
 	MinRelayTxFee dcrutil.Amount
 
@@ -224,17 +274,64 @@ type VerboseTxDesc struct {
 	// Depends enumerates any unconfirmed transactions in the pool used as
 	// inputs for the transaction.
 	Depends []*TxDesc
+
+	// SpentBy enumerates any transactions in the pool that spend outputs of
+	// the transaction.
+	SpentBy []*TxDesc
 }
 
 // orphanTx is a normal transaction that references an ancestor transaction
 // that is not yet available.  It also contains additional information related
-// to it such as an expiration time to help prevent caching the orphan forever.
+// to it such as an expiration time to help prevent caching the orphan forever
+// and the order in which it was received so that orphans are evicted and
+// reconsidered deterministically.
 type orphanTx struct {
 	tx         *dcrutil.Tx
 	tag        Tag
+	size       int
+	sequence   uint64
 	expiration time.Time
 }
 
+// OrphanStats houses statistics about the current state of the orphan pool
+// along with cumulative counters of the actions taken on it since the memory
+// pool was created.
+type OrphanStats struct {
+	// Count is the number of orphans currently in the pool.
+	Count int
+
+	// Size is the total serialized size of all orphans currently in the pool.
+	Size int
+
+	// NumTags is the number of distinct tags (typically peers) that have at
+	// least one orphan in the pool.
+	NumTags int
+
+	// Added is the total number of orphans that have been added to the pool.
+	Added uint64
+
+	// Accepted is the total number of orphans that were moved from the
+	// orphan pool to the main pool once their parents became available.
+	Accepted uint64
+
+	// Expired is the total number of orphans that were removed because they
+	// stayed in the pool longer than the maximum allowed time.
+	Expired uint64
+
+	// Evicted is the total number of orphans that were removed in order to
+	// make room for new orphans.
+	Evicted uint64
+
+	// Rejected is the total number of orphans that were not added to the
+	// pool due to the orphan policy.
+	Rejected uint64
+
+	// Removed is the total number of orphans that were removed for any other
+	// reason such as becoming invalid, being double spent, or their tag being
+	// removed.
+	Removed uint64
+}
+
 // TxPool is used as a source of transactions that need to be mined into blocks
 // and relayed to other peers.  It is safe for concurrent access from multiple
 // peers.
@@ -248,6 +345,10 @@ type TxPool struct {
 
 	orphans       map[chainhash.Hash]*orphanTx
 	orphansByPrev map[wire.OutPoint]map[chainhash.Hash]*dcrutil.Tx
+	orphansByTag  map[Tag]int
+	orphanBytes   int
+	nextOrphanSeq uint64
+	orphanStats   OrphanStats
 	outpoints     map[wire.OutPoint]*TxDesc
 	miningView    *mining.TxMiningView
 
@@ -256,6 +357,15 @@ type TxPool struct {
 
 	transient map[chainhash.Hash]*dcrutil.Tx
 
+	// txEventSeq is the sequence number of the most recent transaction
+	// event.
+	txEventSeq uint64
+
+	// feeDeltaTimes houses the time the fee delta of each transaction with a
+	// fee delta in the mining view was last set.  It is used to remove the
+	// deltas once they expire or the maximum number of deltas is reached.
+	feeDeltaTimes map[chainhash.Hash]time.Time
+
 	// Votes on blocks.
 	votesMtx sync.RWMutex
 	votes    map[chainhash.Hash][]mining.VoteDesc
@@ -263,6 +373,10 @@ type TxPool struct {
 	// TSpends. Access MUST be protected by the mempool mutex.
 	tspends map[chainhash.Hash]*dcrutil.Tx
 
+	// rejected houses recently rejected transactions along with the reason
+	// they were rejected.  It is safe for concurrent access.
+	rejected *rejectedTxCache
+
 	// nextExpireScan is the time after which the orphan pool will be
 	// scanned in order to evict orphans.  This is NOT a hard deadline as
 	// the scan will only run when an orphan is added to the pool as opposed
@@ -368,15 +482,17 @@ func (mp *TxPool) TSpendHashes() []chainhash.Hash {
 var _ mining.TxSource = (*TxPool)(nil)
 
 // removeOrphan is the internal function which implements the public
-// RemoveOrphan.  See the comment for RemoveOrphan for more details.
+// RemoveOrphan.  See the comment for RemoveOrphan for more details.  It
+// returns the total number of orphans that were removed, which includes any
+// redeemers when requested.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) uint64 {
 	// Nothing to do if the passed tx does not exist in the orphan pool.
 	txHash := tx.Hash()
 	otx, exists := mp.orphans[*txHash]
 	if !exists {
-		return
+		return 0
 	}
 
 	log.Tracef("Removing orphan transaction %v", txHash)
@@ -395,6 +511,15 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 		}
 	}
 
+	// Remove the transaction from the orphan pool and update the accounting
+	// for its tag and size.
+	delete(mp.orphans, *txHash)
+	mp.orphanBytes -= otx.size
+	if mp.orphansByTag[otx.tag]--; mp.orphansByTag[otx.tag] <= 0 {
+		delete(mp.orphansByTag, otx.tag)
+	}
+	numRemoved := uint64(1)
+
 	// Remove any orphans that redeem outputs from this one if requested.
 	if removeRedeemers {
 		txType := stake.DetermineTxType(tx.MsgTx())
@@ -407,13 +532,12 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 		for txOutIdx := range tx.MsgTx().TxOut {
 			outpoint.Index = uint32(txOutIdx)
 			for _, orphan := range mp.orphansByPrev[outpoint] {
-				mp.removeOrphan(orphan, true)
+				numRemoved += mp.removeOrphan(orphan, true)
 			}
 		}
 	}
 
-	// Remove the transaction from the orphan pool.
-	delete(mp.orphans, *txHash)
+	return numRemoved
 }
 
 // RemoveOrphan removes the passed orphan transaction from the orphan pool and
@@ -422,7 +546,7 @@ func (mp *TxPool) removeOrphan(tx *dcrutil.Tx, removeRedeemers bool) {
 // This function is safe for concurrent access.
 func (mp *TxPool) RemoveOrphan(tx *dcrutil.Tx) {
 	mp.mtx.Lock()
-	mp.removeOrphan(tx, false)
+	mp.orphanStats.Removed += mp.removeOrphan(tx, false)
 	mp.mtx.Unlock()
 }
 
@@ -431,87 +555,148 @@ func (mp *TxPool) RemoveOrphan(tx *dcrutil.Tx) {
 //
 // This function is safe for concurrent access.
 func (mp *TxPool) RemoveOrphansByTag(tag Tag) uint64 {
-
 	var numEvicted uint64
 	mp.mtx.Lock()
 	for _, otx := range mp.orphans {
 		if otx.tag == tag {
-			mp.removeOrphan(otx.tx, true)
-			numEvicted++
+			numEvicted += mp.removeOrphan(otx.tx, true)
 		}
 	}
+	mp.orphanStats.Removed += numEvicted
 	mp.mtx.Unlock()
 	return numEvicted
 }
 
-// limitNumOrphans limits the number of orphan transactions by evicting a random
-// orphan if adding a new one would cause it to overflow the max allowed.
+// expireOrphans removes all orphans that have been in the orphan pool longer
+// than the maximum allowed time along with any orphans that redeem them.  The
+// scan is only performed when the next scheduled scan time has passed unless
+// the force flag is set.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) limitNumOrphans() {
-	// Scan through the orphan pool and remove any expired orphans when it's
-	// time.  This is done for efficiency so the scan only happens periodically
-	// instead of on every orphan added to the pool.
-	if now := time.Now(); now.After(mp.nextExpireScan) {
-		origNumOrphans := len(mp.orphans)
-		for _, otx := range mp.orphans {
-			if now.After(otx.expiration) {
-				// Remove redeemers too because the missing parents are very
-				// unlikely to ever materialize since the orphan has already
-				// been around more than long enough for them to be delivered.
-				mp.removeOrphan(otx.tx, true)
-			}
+func (mp *TxPool) expireOrphans(now time.Time, force bool) {
+	if !force && !now.After(mp.nextExpireScan) {
+		return
+	}
+
+	var numExpired uint64
+	for _, otx := range mp.orphans {
+		if now.After(otx.expiration) {
+			// Remove redeemers too because the missing parents are very
+			// unlikely to ever materialize since the orphan has already
+			// been around more than long enough for them to be delivered.
+			numExpired += mp.removeOrphan(otx.tx, true)
 		}
+	}
 
-		// Set next expiration scan to occur after the scan interval.
-		mp.nextExpireScan = now.Add(orphanExpireScanInterval)
+	// Set next expiration scan to occur after the scan interval.
+	mp.nextExpireScan = now.Add(orphanExpireScanInterval)
 
+	if numExpired > 0 {
+		mp.orphanStats.Expired += numExpired
 		numOrphans := len(mp.orphans)
-		if numExpired := origNumOrphans - numOrphans; numExpired > 0 {
-			log.Debugf("Expired %d %s (remaining: %d)", numExpired,
-				pickNoun(numExpired, "orphan", "orphans"), numOrphans)
-		}
+		log.Debugf("Expired %d %s (remaining: %d)", numExpired,
+			pickNoun(int(numExpired), "orphan", "orphans"), numOrphans)
 	}
+}
 
-	// Nothing to do if adding another orphan will not cause the pool to
-	// exceed the limit.
-	if len(mp.orphans)+1 <= mp.cfg.Policy.MaxOrphanTxs {
-		return
+// ExpireOrphans removes all orphans that have been in the orphan pool longer
+// than the maximum allowed time along with any orphans that redeem them.
+//
+// Expired orphans are also periodically removed as new orphans are added, so
+// calling this is only necessary when the caller wishes to reclaim the memory
+// sooner.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ExpireOrphans() {
+	mp.mtx.Lock()
+	mp.expireOrphans(time.Now(), true)
+	mp.mtx.Unlock()
+}
+
+// orphanToEvict returns the orphan that should be evicted in order to make room
+// for a new orphan.  It selects the oldest orphan associated with the tag that
+// currently has the most orphans in the pool so that a single tag (typically a
+// peer) flooding the pool only results in the eviction of its own orphans
+// while orphan chains that other tags provided are left intact.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) orphanToEvict() *orphanTx {
+	var evictTag Tag
+	var maxTagCount int
+	for tag, count := range mp.orphansByTag {
+		if count > maxTagCount || (count == maxTagCount && tag < evictTag) {
+			evictTag, maxTagCount = tag, count
+		}
 	}
 
-	// Remove a random entry from the map.  For most compilers, Go's
-	// range statement iterates starting at a random item although
-	// that is not 100% guaranteed by the spec.  The iteration order
-	// is not important here because an adversary would have to be
-	// able to pull off preimage attacks on the hashing function in
-	// order to target eviction of specific entries anyways.
+	var oldest *orphanTx
 	for _, otx := range mp.orphans {
-		// Don't remove redeemers in the case of a random eviction since
-		// it is quite possible it might be needed again shortly.
-		mp.removeOrphan(otx.tx, false)
-		break
+		if otx.tag != evictTag {
+			continue
+		}
+		if oldest == nil || otx.sequence < oldest.sequence {
+			oldest = otx
+		}
 	}
+	return oldest
+}
+
+// limitOrphans limits the number and total size of orphan transactions by
+// evicting orphans if adding a new one of the provided size would cause it to
+// overflow the max allowed.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) limitOrphans(newOrphanSize int) {
+	// Scan through the orphan pool and remove any expired orphans when it's
+	// time.  This is done for efficiency so the scan only happens periodically
+	// instead of on every orphan added to the pool.
+	mp.expireOrphans(time.Now(), false)
+
+	// Evict orphans until adding another orphan will not cause the pool to
+	// exceed the limits.
+	maxOrphans := mp.cfg.Policy.MaxOrphanTxs
+	maxSize := mp.cfg.Policy.MaxOrphanPoolSize
+	exceedsLimits := func() bool {
+		return len(mp.orphans)+1 > maxOrphans ||
+			(maxSize > 0 && mp.orphanBytes+newOrphanSize > maxSize)
+	}
+	var numEvicted uint64
+	for len(mp.orphans) > 0 && exceedsLimits() {
+		// Don't remove redeemers in the case of an eviction since it is quite
+		// possible they might be needed again shortly.
+		otx := mp.orphanToEvict()
+		log.Tracef("Evicting orphan transaction %v from tag %d to make room",
+			otx.tx.Hash(), otx.tag)
+		numEvicted += mp.removeOrphan(otx.tx, false)
+	}
+	mp.orphanStats.Evicted += numEvicted
 }
 
 // addOrphan adds an orphan transaction to the orphan pool.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) addOrphan(tx *dcrutil.Tx, tag Tag) {
+func (mp *TxPool) addOrphan(tx *dcrutil.Tx, tag Tag, size int) {
 	// Nothing to do if no orphans are allowed.
 	if mp.cfg.Policy.MaxOrphanTxs <= 0 {
 		return
 	}
 
-	// Limit the number orphan transactions to prevent memory exhaustion.
-	// This will periodically remove any expired orphans and evict a random
-	// orphan if space is still needed.
-	mp.limitNumOrphans()
+	// Limit the number and size of orphan transactions to prevent memory
+	// exhaustion.  This will periodically remove any expired orphans and
+	// evict orphans from the most prolific tag if space is still needed.
+	mp.limitOrphans(size)
 
 	mp.orphans[*tx.Hash()] = &orphanTx{
 		tx:         tx,
 		tag:        tag,
+		size:       size,
+		sequence:   mp.nextOrphanSeq,
 		expiration: time.Now().Add(orphanTTL),
 	}
+	mp.nextOrphanSeq++
+	mp.orphansByTag[tag]++
+	mp.orphanBytes += size
+	mp.orphanStats.Added++
 	for _, txIn := range tx.MsgTx().TxIn {
 		if _, exists := mp.orphansByPrev[txIn.PreviousOutPoint]; !exists {
 			mp.orphansByPrev[txIn.PreviousOutPoint] =
@@ -534,20 +719,32 @@ func (mp *TxPool) maybeAddOrphan(tx *dcrutil.Tx, tag Tag) error {
 	// it will ultimately be rebroadcast after the parent transactions
 	// have been mined or otherwise received.
 	//
-	// Note that the number of orphan transactions in the orphan pool is
-	// also limited, so this equates to a maximum memory used of
-	// mp.cfg.Policy.MaxOrphanTxSize * mp.cfg.Policy.MaxOrphanTxs (which is ~5MB
-	// using the default values at the time this comment was written).
+	// Note that the number and total size of orphan transactions in the
+	// orphan pool are also limited, so this equates to a maximum memory used
+	// of the lesser of mp.cfg.Policy.MaxOrphanPoolSize and
+	// mp.cfg.Policy.MaxOrphanTxSize * mp.cfg.Policy.MaxOrphanTxs.
 	serializedLen := tx.MsgTx().SerializeSize()
 	if serializedLen > mp.cfg.Policy.MaxOrphanTxSize {
+		mp.orphanStats.Rejected++
 		str := fmt.Sprintf("orphan transaction size of %d bytes is "+
 			"larger than max allowed size of %d bytes",
 			serializedLen, mp.cfg.Policy.MaxOrphanTxSize)
 		return txRuleError(ErrOrphanPolicyViolation, str)
 	}
 
+	// Ignore orphan transactions from tags that have already reached their
+	// quota.  This prevents a single peer from being able to evict all of the
+	// orphans provided by other peers.
+	maxPerTag := mp.cfg.Policy.MaxOrphanTxsPerTag
+	if maxPerTag > 0 && mp.orphansByTag[tag] >= maxPerTag {
+		mp.orphanStats.Rejected++
+		str := fmt.Sprintf("orphan transaction %v exceeds the max allowed "+
+			"number of orphans per peer of %d", tx.Hash(), maxPerTag)
+		return txRuleError(ErrOrphanPolicyViolation, str)
+	}
+
 	// Add the orphan if the none of the above disqualified it.
-	mp.addOrphan(tx, tag)
+	mp.addOrphan(tx, tag, serializedLen)
 
 	return nil
 }
@@ -563,11 +760,45 @@ func (mp *TxPool) removeOrphanDoubleSpends(tx *dcrutil.Tx) {
 	msgTx := tx.MsgTx()
 	for _, txIn := range msgTx.TxIn {
 		for _, orphan := range mp.orphansByPrev[txIn.PreviousOutPoint] {
-			mp.removeOrphan(orphan, true)
+			mp.orphanStats.Removed += mp.removeOrphan(orphan, true)
 		}
 	}
 }
 
+// orphansRedeeming returns all orphans that redeem the provided outpoint sorted
+// by the order in which they were added to the orphan pool.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) orphansRedeeming(outpoint wire.OutPoint) []*orphanTx {
+	redeemers, exists := mp.orphansByPrev[outpoint]
+	if !exists {
+		return nil
+	}
+
+	orphans := make([]*orphanTx, 0, len(redeemers))
+	for hash := range redeemers {
+		orphans = append(orphans, mp.orphans[hash])
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].sequence < orphans[j].sequence
+	})
+	return orphans
+}
+
+// OrphanStats returns statistics about the current state of the orphan pool
+// along with cumulative counters of the actions taken on it.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) OrphanStats() OrphanStats {
+	mp.mtx.RLock()
+	stats := mp.orphanStats
+	stats.Count = len(mp.orphans)
+	stats.Size = mp.orphanBytes
+	stats.NumTags = len(mp.orphansByTag)
+	mp.mtx.RUnlock()
+	return stats
+}
+
 // isTransactionInPool returns whether or not the passed transaction already
 // exists in the main pool.
 //
@@ -779,8 +1010,15 @@ func (mp *TxPool) HaveAllTransactions(hashes []chainhash.Hash) bool {
 // removeTransaction is the internal function which implements the public
 // RemoveTransaction.  See the comment for RemoveTransaction for more details.
 //
+// The provided reason is reported in the transaction event for the removed
+// transaction along with the hash of the transaction that replaced it, which
+// should only be non-nil for conflicts.  Any redeemers that are removed are
+// reported as removed because their parent was removed.
+//
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
+func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool,
+	reason RemovalReason, replacedBy *chainhash.Hash) {
+
 	txHash := tx.Hash()
 	if removeRedeemers {
 		// Remove any transactions which rely on this one.
@@ -794,7 +1032,8 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 		for i := uint32(0); i < uint32(len(tx.MsgTx().TxOut)); i++ {
 			outpoint.Index = i
 			if txRedeemerDesc, exists := mp.outpoints[outpoint]; exists {
-				mp.removeTransaction(txRedeemerDesc.Tx, true)
+				mp.removeTransaction(txRedeemerDesc.Tx, true,
+					RemovalReasonParentRemoved, nil)
 				continue
 			}
 			if txRedeemerDesc, exists := mp.stagedOutpoints[outpoint]; exists {
@@ -819,6 +1058,12 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 		updateDescendantStats := !removeRedeemers
 		mp.miningView.RemoveTransaction(tx.Hash(), updateDescendantStats)
 
+		// Fee deltas no longer apply once the transaction is mined or
+		// expires.
+		if reason == RemovalReasonMined || reason == RemovalReasonExpired {
+			mp.removeFeeDelta(txHash)
+		}
+
 		delete(mp.pool, *txHash)
 
 		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
@@ -831,6 +1076,8 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 
 		// Stop tracking if it's a tspend.
 		delete(mp.tspends, *txHash)
+
+		mp.notifyTxEvent(TxEventRemoved, tx, reason, replacedBy)
 	}
 }
 
@@ -839,11 +1086,15 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 // removed transaction will also be removed recursively from the mempool, as
 // they would otherwise become orphans.
 //
+// Since it is intended to be used to remove transactions that are included in
+// blocks connected to the main chain, the removed transaction is reported as
+// mined in the associated transaction event.
+//
 // This function is safe for concurrent access.
 func (mp *TxPool) RemoveTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	mp.removeTransaction(tx, removeRedeemers)
+	mp.removeTransaction(tx, removeRedeemers, RemovalReasonMined, nil)
 	mp.mtx.Unlock()
 }
 
@@ -853,6 +1104,9 @@ func (mp *TxPool) RemoveTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 // necessary when a block is connected to the main chain because the block may
 // contain transactions which were previously unknown to the memory pool.
 //
+// The removed transactions are reported as replaced by the passed transaction
+// in the associated transaction events.
+//
 // This function is safe for concurrent access.
 func (mp *TxPool) RemoveDoubleSpends(tx *dcrutil.Tx) {
 	// Protect concurrent access.
@@ -860,7 +1114,8 @@ func (mp *TxPool) RemoveDoubleSpends(tx *dcrutil.Tx) {
 	for _, txIn := range tx.MsgTx().TxIn {
 		if txRedeemerDesc, ok := mp.outpoints[txIn.PreviousOutPoint]; ok {
 			if txRedeemerDesc.Tx.Hash() != tx.Hash() {
-				mp.removeTransaction(txRedeemerDesc.Tx, true)
+				mp.removeTransaction(txRedeemerDesc.Tx, true,
+					RemovalReasonConflict, tx.Hash())
 			}
 		}
 		if txRedeemerDesc, ok := mp.stagedOutpoints[txIn.PreviousOutPoint]; ok {
@@ -922,6 +1177,8 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, txDesc *TxD
 	if mp.cfg.AddTxToFeeEstimation != nil {
 		mp.cfg.AddTxToFeeEstimation(txHash, txDesc.Fee, txDesc.TxSize, txType)
 	}
+
+	mp.notifyTxEvent(TxEventAdded, tx, RemovalReasonNone, nil)
 }
 
 // checkPoolDoubleSpend checks whether or not the passed transaction is
@@ -1174,6 +1431,19 @@ func (mp *TxPool) MaybeAcceptDependents(tx *dcrutil.Tx, isTreasuryEnabled bool)
 	return acceptedTxns
 }
 
+// txPolicies returns the ordered chain of policies to apply to transactions
+// per the memory pool policy configuration.  It returns nil when non-standard
+// transactions are accepted.
+func (mp *TxPool) txPolicies() []TxPolicy {
+	if mp.cfg.Policy.AcceptNonStd {
+		return nil
+	}
+	if mp.cfg.Policy.TxPolicies == nil {
+		return defaultTxPolicies
+	}
+	return mp.cfg.Policy.TxPolicies
+}
+
 // maybeAcceptTransaction is the internal function which implements the public
 // MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
 // more details.
@@ -1281,12 +1551,21 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		return nil, txRuleError(ErrInvalid, str)
 	}
 
-	// Don't allow non-standard transactions if the mempool config forbids
-	// their acceptance and relaying.
+	// Don't allow transactions that violate the configured policies unless
+	// the mempool config allows the acceptance and relaying of non-standard
+	// transactions.
 	medianTime := mp.cfg.PastMedianTime()
-	if !mp.cfg.Policy.AcceptNonStd {
-		err := checkTransactionStandard(tx, txType, nextBlockHeight,
-			medianTime, mp.cfg.Policy.MinRelayTxFee)
+	policyTx := &PolicyTx{
+		Tx:                tx,
+		Type:              txType,
+		NextBlockHeight:   nextBlockHeight,
+		MedianTime:        medianTime,
+		MinRelayTxFee:     mp.cfg.Policy.MinRelayTxFee,
+		IsTreasuryEnabled: isTreasuryEnabled,
+	}
+	txPolicies := mp.txPolicies()
+	for _, policy := range txPolicies {
+		err := policy.CheckTransaction(policyTx)
 		if err != nil {
 			str := fmt.Sprintf("transaction %v is not standard: %v",
 				txHash, err)
@@ -1528,10 +1807,11 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		return nil, err
 	}
 
-	// Don't allow transactions with non-standard inputs if the mempool config
-	// forbids their acceptance and relaying.
-	if !mp.cfg.Policy.AcceptNonStd {
-		err := checkInputsStandard(tx, txType, utxoView, isTreasuryEnabled)
+	// Don't allow transactions with inputs that violate the configured
+	// policies unless the mempool config allows the acceptance and relaying
+	// of non-standard transactions.
+	for _, policy := range txPolicies {
+		err := policy.CheckInputs(policyTx, utxoView)
 		if err != nil {
 			str := fmt.Sprintf("transaction %v has a non-standard "+
 				"input: %v", txHash, err)
@@ -1737,7 +2017,8 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, allowHighFees,
 		mp.forEachRedeemer(tx, func(redeemerTxDesc *TxDesc) {
 			if redeemerTxDesc.Type == stake.TxTypeSStx {
 				redeemerTx := redeemerTxDesc.Tx
-				mp.removeTransaction(redeemerTx, true)
+				mp.removeTransaction(redeemerTx, true, RemovalReasonStaged,
+					nil)
 				mp.stageTransaction(redeemerTxDesc)
 				log.Debugf("Moved ticket %v dependent on %v into stage pool",
 					redeemerTx.Hash(), txHash)
@@ -1868,7 +2149,7 @@ func (mp *TxPool) MaybeAcceptTransactions(txns []*dcrutil.Tx) error {
 		delete(transientPool, *tx.Hash())
 		_, err := mp.maybeAcceptTransaction(tx, false, true, true, checkTxFlags)
 		if err != nil && !isDoubleSpendOrDuplicateError(err) {
-			mp.removeTransaction(tx, true)
+			mp.removeTransaction(tx, true, RemovalReasonInvalid, nil)
 			continue
 		}
 		if err != nil {
@@ -1920,24 +2201,34 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 			// being able to purposely construct orphans that
 			// would otherwise make outputs unspendable.
 			//
+			// The orphans are considered in the order they were received so
+			// that the first one seen wins in the case of double spends.
+			//
 			// Skip to the next available output if there are none.
 			outpoint.Index = uint32(txOutIdx)
-			orphans, exists := mp.orphansByPrev[outpoint]
-			if !exists {
+			orphans := mp.orphansRedeeming(outpoint)
+			if len(orphans) == 0 {
 				continue
 			}
 
 			// Potentially accept an orphan into the tx pool.
-			for _, tx := range orphans {
+			for _, otx := range orphans {
+				// Skip orphans that were already removed while handling a
+				// prior one that redeems the same output.
+				tx := otx.tx
+				if !mp.isOrphanInPool(tx.Hash()) {
+					continue
+				}
+
 				missing, err := mp.maybeAcceptTransaction(tx, true, true, false,
 					checkTxFlags)
 				if err != nil {
-					// The orphan is now invalid, so there
-					// is no way any other orphans which
-					// redeem any of its outputs can be
-					// accepted.  Remove them.
-					mp.removeOrphan(tx, true)
-					break
+					// The orphan is now invalid, so there is no way any
+					// other orphans which redeem any of its outputs can be
+					// accepted.  Remove them and try the next orphan which
+					// redeems this output since it might still be valid.
+					mp.orphanStats.Removed += mp.removeOrphan(tx, true)
+					continue
 				}
 
 				// Transaction is still an orphan.  Try the next
@@ -1952,9 +2243,12 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 				// that are no longer orphans, remove it from
 				// the orphan pool, and add it to the list of
 				// transactions to process so any orphans that
-				// depend on it are handled too.
+				// depend on it are handled too.  Since the list is
+				// processed in order, orphans are always reconsidered
+				// after all of their ancestors.
 				acceptedTxns = append(acceptedTxns, tx)
 				mp.removeOrphan(tx, false)
+				mp.orphanStats.Accepted++
 				processList = append(processList, tx)
 
 				// Only one transaction for this outpoint can be
@@ -1985,17 +2279,17 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 		txType := txDesc.Type
 		if txType == stake.TxTypeSStx &&
 			txDesc.Height+int64(heightDiffToPruneTicket) < height {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonStale, nil)
 			continue
 		}
 		if txType == stake.TxTypeSStx &&
 			txDesc.Tx.MsgTx().TxOut[0].Value < requiredStakeDifficulty {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonStale, nil)
 			continue
 		}
 		if (txType == stake.TxTypeSSRtx || txType == stake.TxTypeSSGen) &&
 			txDesc.Height+int64(heightDiffToPruneVotes) < height {
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonStale, nil)
 			continue
 		}
 		if isAutoRevocationsEnabled && txType == stake.TxTypeSSRtx {
@@ -2004,7 +2298,7 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 			// longer valid and should be removed since they require using the header
 			// of the previous block in order to properly calculate the return
 			// amounts.
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonStale, nil)
 			continue
 		}
 	}
@@ -2030,7 +2324,7 @@ func (mp *TxPool) pruneStakeTx(requiredStakeDifficulty, height int64, isAutoRevo
 			// longer valid and should be removed since they require using the header
 			// of the previous block in order to properly calculate the return
 			// amounts.
-			mp.removeTransaction(txDesc.Tx, true)
+			mp.removeTransaction(txDesc.Tx, true, RemovalReasonStale, nil)
 			continue
 		}
 	}
@@ -2067,7 +2361,7 @@ func (mp *TxPool) pruneExpiredTx(height int64) {
 		if blockchain.IsExpired(tx, nextBlockHeight) {
 			log.Debugf("Pruning expired transaction %v from the mempool",
 				tx.Hash())
-			mp.removeTransaction(tx, true)
+			mp.removeTransaction(tx, true, RemovalReasonExpired, nil)
 		}
 	}
 
@@ -2079,6 +2373,8 @@ func (mp *TxPool) pruneExpiredTx(height int64) {
 			mp.removeStagedTransaction(tx)
 		}
 	}
+
+	mp.pruneFeeDeltas(time.Now())
 }
 
 // PruneExpiredTx prunes expired transactions that are no longer able to be
@@ -2121,7 +2417,16 @@ func (mp *TxPool) ProcessOrphans(acceptedTx *dcrutil.Tx, checkTxFlags blockchain
 // passed one being accepted.
 //
 // This function is safe for concurrent access.
-func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool, tag Tag) ([]*dcrutil.Tx, error) {
+func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees bool, tag Tag) (acceptedTxs []*dcrutil.Tx, err error) {
+	// Trace the processing of the transaction when requested.  The span is
+	// nil, and therefore ignored, when the transaction is not traced.
+	span := mp.cfg.Tracer.Start(tx.Hash(), "mempool.process_transaction")
+	defer func() {
+		span.SetAttribute("tx.accepted", len(acceptedTxs))
+		span.SetError(err)
+		span.End()
+	}()
+
 	// Create agenda flags for checking transactions based on which ones are
 	// active or should otherwise always be enforced.
 	checkTxFlags, err := mp.determineCheckTxFlags()
@@ -2136,6 +2441,31 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees
 		if err != nil {
 			log.Tracef("Failed to process transaction %v: %s",
 				tx.Hash(), err.Error())
+
+			// Keep track of the reason transactions that violate the rules
+			// are rejected.  Duplicates are not tracked since they are not
+			// really rejections.
+			var rErr RuleError
+			if errors.As(err, &rErr) && !errors.Is(err, ErrDuplicate) {
+				mp.rejected.add(tx.Hash(), tag, err)
+				if mp.cfg.RejectedTxns != nil {
+					kind := rejectKind(err)
+					if kind == "" {
+						kind = "unknown"
+					}
+					mp.cfg.RejectedTxns.Inc(kind)
+				}
+			}
+			return
+		}
+
+		// Remove any record of a prior rejection now that the transaction
+		// has been accepted to either the main or orphan pool.
+		mp.rejected.remove(tx.Hash())
+		if len(acceptedTxs) == 0 {
+			mp.cfg.OrphanedTxns.Inc()
+		} else {
+			mp.cfg.AcceptedTxns.Add(uint64(len(acceptedTxs)))
 		}
 	}()
 
@@ -2153,7 +2483,7 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees
 		// are now available) and repeat for those accepted
 		// transactions until there are no more.
 		newTxs := mp.processOrphans(tx, checkTxFlags)
-		acceptedTxs := make([]*dcrutil.Tx, len(newTxs)+1)
+		acceptedTxs = make([]*dcrutil.Tx, len(newTxs)+1)
 
 		// Add the parent transaction first so remote nodes
 		// do not add orphans.
@@ -2178,7 +2508,8 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, allowHighFees
 		str := fmt.Sprintf("orphan transaction %v references "+
 			"outputs of unknown or fully-spent "+
 			"transaction %v", tx.Hash(), missingParents[0])
-		return nil, txRuleError(ErrOrphan, str)
+		err = txRuleError(ErrOrphan, str)
+		return nil, err
 	}
 
 	// Potentially add the orphan transaction to the orphan pool.
@@ -2233,6 +2564,19 @@ func (mp *TxPool) TxDescs() []*TxDesc {
 	return descs
 }
 
+// TxDesc returns the descriptor for the transaction with the provided hash in
+// the main pool.  The second return value is false when the transaction is not
+// in the main pool.  The descriptor must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TxDesc(txHash *chainhash.Hash) (*TxDesc, bool) {
+	mp.mtx.RLock()
+	desc, exists := mp.pool[*txHash]
+	mp.mtx.RUnlock()
+
+	return desc, exists
+}
+
 // VerboseTxDescs returns a slice of verbose descriptors for all the
 // transactions in the pool.  The descriptors must be treated as read only.
 //
@@ -2246,21 +2590,118 @@ func (mp *TxPool) VerboseTxDescs() []*VerboseTxDesc {
 
 	result := make([]*VerboseTxDesc, 0, len(mp.pool))
 	for _, desc := range mp.pool {
-		// Create the descriptor and add dependencies as needed.
-		vtxd := &VerboseTxDesc{
-			TxDesc: *desc,
+		result = append(result, mp.verboseTxDesc(desc))
+	}
+
+	return result
+}
+
+// verboseTxDesc returns a verbose descriptor for the provided transaction
+// descriptor with the dependencies and redeemers in the main pool populated.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) verboseTxDesc(desc *TxDesc) *VerboseTxDesc {
+	vtxd := &VerboseTxDesc{
+		TxDesc: *desc,
+	}
+	for _, txIn := range desc.Tx.MsgTx().TxIn {
+		hash := &txIn.PreviousOutPoint.Hash
+		if depDesc, ok := mp.pool[*hash]; ok {
+			vtxd.Depends = append(vtxd.Depends, depDesc)
 		}
+	}
+	mp.forEachRedeemer(desc.Tx, func(redeemer *TxDesc) {
+		vtxd.SpentBy = append(vtxd.SpentBy, redeemer)
+	})
+
+	return vtxd
+}
+
+// VerboseTxDesc returns a verbose descriptor for the transaction with the
+// provided hash in the main pool.  The second return value is false when the
+// transaction is not in the main pool.  The descriptor must be treated as read
+// only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) VerboseTxDesc(txHash *chainhash.Hash) (*VerboseTxDesc, bool) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, false
+	}
+	return mp.verboseTxDesc(desc), true
+}
+
+// Ancestors returns the descriptors of all transactions in the main pool that
+// the transaction with the provided hash directly or indirectly spends outputs
+// of.  The second return value is false when the transaction is not in the
+// main pool.  The descriptors must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Ancestors(txHash *chainhash.Hash) ([]*TxDesc, bool) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, false
+	}
+
+	var ancestors []*TxDesc
+	seen := map[chainhash.Hash]struct{}{*txHash: {}}
+	queue := []*TxDesc{desc}
+	for len(queue) > 0 {
+		desc := queue[0]
+		queue = queue[1:]
 		for _, txIn := range desc.Tx.MsgTx().TxIn {
-			hash := &txIn.PreviousOutPoint.Hash
-			if depDesc, ok := mp.pool[*hash]; ok {
-				vtxd.Depends = append(vtxd.Depends, depDesc)
+			hash := txIn.PreviousOutPoint.Hash
+			if _, ok := seen[hash]; ok {
+				continue
+			}
+			if parent, ok := mp.pool[hash]; ok {
+				seen[hash] = struct{}{}
+				ancestors = append(ancestors, parent)
+				queue = append(queue, parent)
 			}
 		}
-
-		result = append(result, vtxd)
 	}
+	return ancestors, true
+}
 
-	return result
+// Descendants returns the descriptors of all transactions in the main pool
+// that directly or indirectly spend outputs of the transaction with the
+// provided hash.  The second return value is false when the transaction is not
+// in the main pool.  The descriptors must be treated as read only.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) Descendants(txHash *chainhash.Hash) ([]*TxDesc, bool) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, false
+	}
+
+	var descendants []*TxDesc
+	seen := map[chainhash.Hash]struct{}{*txHash: {}}
+	queue := []*TxDesc{desc}
+	for len(queue) > 0 {
+		desc := queue[0]
+		queue = queue[1:]
+		mp.forEachRedeemer(desc.Tx, func(redeemer *TxDesc) {
+			hash := redeemer.Tx.Hash()
+			if _, ok := seen[*hash]; ok {
+				return
+			}
+			seen[*hash] = struct{}{}
+			descendants = append(descendants, redeemer)
+			queue = append(queue, redeemer)
+		})
+	}
+	return descendants, true
 }
 
 // miningDescs returns a slice of mining descriptors for all transactions
@@ -2299,6 +2740,110 @@ func (mp *TxPool) MiningView() *mining.TxMiningView {
 	return view
 }
 
+// removeFeeDelta removes any fee delta for the transaction with the provided
+// hash.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) removeFeeDelta(txHash *chainhash.Hash) {
+	if _, exists := mp.feeDeltaTimes[*txHash]; !exists {
+		return
+	}
+	delete(mp.feeDeltaTimes, *txHash)
+	mp.miningView.SetFeeDelta(txHash, 0)
+}
+
+// pruneFeeDeltas removes the fee deltas for transactions that are not in the
+// pool and were last set more than feeDeltaTTL prior to the provided time.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) pruneFeeDeltas(now time.Time) {
+	for txHash, setTime := range mp.feeDeltaTimes {
+		if _, exists := mp.pool[txHash]; exists {
+			continue
+		}
+		if now.Sub(setTime) > feeDeltaTTL {
+			txHash := txHash
+			log.Debugf("Removing expired fee delta for transaction %v",
+				txHash)
+			mp.removeFeeDelta(&txHash)
+		}
+	}
+}
+
+// limitFeeDeltas removes expired fee deltas and then the oldest remaining fee
+// deltas as needed to make room for a new one when the maximum number of fee
+// deltas is reached.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) limitFeeDeltas(now time.Time) {
+	if len(mp.feeDeltaTimes) < maxFeeDeltas {
+		return
+	}
+	mp.pruneFeeDeltas(now)
+	for len(mp.feeDeltaTimes) >= maxFeeDeltas {
+		var oldestHash chainhash.Hash
+		var oldestTime time.Time
+		for txHash, setTime := range mp.feeDeltaTimes {
+			if oldestTime.IsZero() || setTime.Before(oldestTime) {
+				oldestHash, oldestTime = txHash, setTime
+			}
+		}
+		log.Debugf("Evicting fee delta for transaction %v", oldestHash)
+		mp.removeFeeDelta(&oldestHash)
+	}
+}
+
+// PrioritiseTransaction adjusts the fee of the transaction with the provided
+// hash by the given delta when prioritizing it for inclusion in block templates
+// and returns the resulting total delta for the transaction.  Deltas are
+// cumulative, so multiple calls add to the existing delta, and a resulting
+// total of zero removes it.  The fee the transaction actually pays, and
+// therefore its acceptance to the pool, is not affected.
+//
+// The transaction does not need to be in the pool.  Any delta for a
+// transaction that is not in the pool applies to it should it be added later.
+// Deltas are removed when the transaction is mined or expires, and deltas for
+// transactions that are not in the pool are removed once they have not been
+// set for feeDeltaTTL.  At most maxFeeDeltas deltas are retained, so the
+// oldest ones are removed to make room for new ones once the limit is reached.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) PrioritiseTransaction(hash *chainhash.Hash, feeDelta int64) int64 {
+	mp.mtx.Lock()
+	totalDelta := mp.miningView.FeeDelta(hash) + feeDelta
+	if totalDelta == 0 {
+		mp.removeFeeDelta(hash)
+	} else {
+		now := time.Now()
+		if _, exists := mp.feeDeltaTimes[*hash]; !exists {
+			mp.limitFeeDeltas(now)
+		}
+		mp.miningView.SetFeeDelta(hash, totalDelta)
+		mp.feeDeltaTimes[*hash] = now
+	}
+	if _, exists := mp.pool[*hash]; exists {
+		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
+	}
+	mp.mtx.Unlock()
+
+	log.Debugf("Set fee delta for transaction %v to %d atoms", hash,
+		totalDelta)
+
+	return totalDelta
+}
+
+// FeeDelta returns the amount the fee of the transaction with the provided
+// hash is adjusted by when prioritizing it for inclusion in block templates as
+// set by PrioritiseTransaction.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) FeeDelta(hash *chainhash.Hash) int64 {
+	mp.mtx.RLock()
+	feeDelta := mp.miningView.FeeDelta(hash)
+	mp.mtx.RUnlock()
+	return feeDelta
+}
+
 // New returns a new memory pool for validating and storing standalone
 // transactions until they are mined into a block.
 func New(cfg *Config) *TxPool {
@@ -2307,6 +2852,7 @@ func New(cfg *Config) *TxPool {
 		pool:            make(map[chainhash.Hash]*TxDesc),
 		orphans:         make(map[chainhash.Hash]*orphanTx),
 		orphansByPrev:   make(map[wire.OutPoint]map[chainhash.Hash]*dcrutil.Tx),
+		orphansByTag:    make(map[Tag]int),
 		outpoints:       make(map[wire.OutPoint]*TxDesc),
 		votes:           make(map[chainhash.Hash][]mining.VoteDesc),
 		tspends:         make(map[chainhash.Hash]*dcrutil.Tx),
@@ -2314,6 +2860,8 @@ func New(cfg *Config) *TxPool {
 		staged:          make(map[chainhash.Hash]*TxDesc),
 		stagedOutpoints: make(map[wire.OutPoint]*TxDesc),
 		transient:       make(map[chainhash.Hash]*dcrutil.Tx),
+		rejected:        newRejectedTxCache(maxRejectedTxns),
+		feeDeltaTimes:   make(map[chainhash.Hash]time.Time),
 	}
 
 	// for a given transaction, scan the mempool to find which transactions
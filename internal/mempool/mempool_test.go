@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"reflect"
 	"runtime"
 	"sync"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/metrics"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/txscript/v4/sign"
@@ -1316,6 +1318,861 @@ func TestOrphanEviction(t *testing.T) {
 	}
 }
 
+// createIndependentOrphans creates the requested number of transactions that
+// each spend an output of a different unknown transaction such that they are
+// all orphans that do not depend on each other.
+func createIndependentOrphans(harness *poolHarness, numOrphans int) ([]*dcrutil.Tx, error) {
+	orphans := make([]*dcrutil.Tx, 0, numOrphans)
+	for i := 0; i < numOrphans; i++ {
+		var fakeHash chainhash.Hash
+		fakeHash[0], fakeHash[1] = byte(i), byte(i>>8)
+		tx, err := harness.CreateSignedTx([]spendableOutput{{
+			amount:   dcrutil.Amount(5000000000),
+			outPoint: wire.OutPoint{Hash: fakeHash, Index: 0},
+		}}, 1)
+		if err != nil {
+			return nil, err
+		}
+		orphans = append(orphans, tx)
+	}
+	return orphans, nil
+}
+
+// TestOrphanTagLimit ensures that the number of orphans that are allowed in
+// the orphan pool for any given tag is limited as expected.
+func TestOrphanTagLimit(t *testing.T) {
+	t.Parallel()
+
+	const maxOrphansPerTag = 2
+	harness, _, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	harness.txPool.cfg.Policy.MaxOrphanTxsPerTag = maxOrphansPerTag
+	tc := &testContext{t, harness}
+
+	orphans, err := createIndependentOrphans(harness, maxOrphansPerTag+1)
+	if err != nil {
+		t.Fatalf("unable to create orphans: %v", err)
+	}
+
+	// Ensure orphans are accepted up to the per-tag limit.
+	const tag1, tag2 = Tag(1), Tag(2)
+	for _, tx := range orphans[:maxOrphansPerTag] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, true, tag1)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid orphan %v",
+				err)
+		}
+		testPoolMembership(tc, tx, true, false)
+	}
+
+	// Ensure an orphan that would exceed the per-tag limit is rejected.
+	tx := orphans[maxOrphansPerTag]
+	_, err = harness.txPool.ProcessTransaction(tx, true, true, tag1)
+	if !errors.Is(err, ErrOrphanPolicyViolation) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrOrphanPolicyViolation)
+	}
+	testPoolMembership(tc, tx, false, false)
+
+	// Ensure the same orphan is accepted when it has a different tag.
+	_, err = harness.txPool.ProcessTransaction(tx, true, true, tag2)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid orphan %v", err)
+	}
+	testPoolMembership(tc, tx, true, false)
+
+	// Ensure the orphan stats reflect the expected state.
+	var totalSize int
+	for _, tx := range orphans {
+		totalSize += tx.MsgTx().SerializeSize()
+	}
+	want := OrphanStats{
+		Count:    maxOrphansPerTag + 1,
+		Size:     totalSize,
+		NumTags:  2,
+		Added:    maxOrphansPerTag + 1,
+		Rejected: 1,
+	}
+	if got := harness.txPool.OrphanStats(); got != want {
+		t.Fatalf("unexpected orphan stats -- got %+v, want %+v", got, want)
+	}
+
+	// Ensure removing the orphans by tag updates the stats accordingly.
+	harness.txPool.RemoveOrphansByTag(tag1)
+	want.Count, want.NumTags, want.Removed = 1, 1, maxOrphansPerTag
+	want.Size = tx.MsgTx().SerializeSize()
+	if got := harness.txPool.OrphanStats(); got != want {
+		t.Fatalf("unexpected orphan stats -- got %+v, want %+v", got, want)
+	}
+}
+
+// TestOrphanEvictionByTag ensures that the orphans evicted to make room for a
+// new orphan are the oldest ones from the tag with the most orphans.
+func TestOrphanEvictionByTag(t *testing.T) {
+	t.Parallel()
+
+	const maxOrphans = 4
+	harness, _, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	harness.txPool.cfg.Policy.MaxOrphanTxs = maxOrphans
+	tc := &testContext{t, harness}
+
+	orphans, err := createIndependentOrphans(harness, maxOrphans+2)
+	if err != nil {
+		t.Fatalf("unable to create orphans: %v", err)
+	}
+
+	// Fill the orphan pool with a single orphan from the first tag and the
+	// remaining ones from the second tag.
+	const tag1, tag2 = Tag(1), Tag(2)
+	tags := []Tag{tag1, tag2, tag2, tag2, tag1, tag1}
+	for i, tx := range orphans[:maxOrphans] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, true, tags[i])
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid orphan %v",
+				err)
+		}
+		testPoolMembership(tc, tx, true, false)
+	}
+
+	// Add another orphan from the first tag and ensure the oldest orphan from
+	// the second tag was evicted since it has the most orphans.
+	_, err = harness.txPool.ProcessTransaction(orphans[4], true, true, tags[4])
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid orphan %v", err)
+	}
+	testPoolMembership(tc, orphans[0], true, false)
+	testPoolMembership(tc, orphans[1], false, false)
+	testPoolMembership(tc, orphans[2], true, false)
+	testPoolMembership(tc, orphans[3], true, false)
+	testPoolMembership(tc, orphans[4], true, false)
+
+	// Add another orphan from the first tag and ensure the oldest orphan from
+	// the first tag was evicted since both tags have the same number of
+	// orphans and the first tag sorts first.
+	_, err = harness.txPool.ProcessTransaction(orphans[5], true, true, tags[5])
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid orphan %v", err)
+	}
+	testPoolMembership(tc, orphans[0], false, false)
+	testPoolMembership(tc, orphans[2], true, false)
+	testPoolMembership(tc, orphans[3], true, false)
+	testPoolMembership(tc, orphans[4], true, false)
+	testPoolMembership(tc, orphans[5], true, false)
+
+	if got := harness.txPool.OrphanStats().Evicted; got != 2 {
+		t.Fatalf("unexpected number of evictions -- got %d, want %d", got, 2)
+	}
+}
+
+// TestOrphanPoolSizeLimit ensures that the total size of all orphans in the
+// orphan pool is limited as expected.
+func TestOrphanPoolSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	harness, _, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	orphans, err := createIndependentOrphans(harness, 3)
+	if err != nil {
+		t.Fatalf("unable to create orphans: %v", err)
+	}
+
+	// Limit the orphan pool to the size of the last two orphans and add all of
+	// them to the pool.
+	maxSize := orphans[1].MsgTx().SerializeSize() +
+		orphans[2].MsgTx().SerializeSize()
+	harness.txPool.cfg.Policy.MaxOrphanPoolSize = maxSize
+	for _, tx := range orphans {
+		_, err := harness.txPool.ProcessTransaction(tx, true, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid orphan %v",
+				err)
+		}
+		testPoolMembership(tc, tx, true, false)
+	}
+
+	// Ensure the oldest orphan was evicted to stay within the size limit.
+	testPoolMembership(tc, orphans[0], false, false)
+	stats := harness.txPool.OrphanStats()
+	if stats.Size != maxSize {
+		t.Fatalf("unexpected orphan pool size -- got %d, want %d", stats.Size,
+			maxSize)
+	}
+	if stats.Evicted != 1 {
+		t.Fatalf("unexpected number of evictions -- got %d, want %d",
+			stats.Evicted, 1)
+	}
+}
+
+// TestOrphanExpiration ensures that orphans are removed from the orphan pool
+// along with their redeemers once they expire.
+func TestOrphanExpiration(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Create a chain of transactions rooted with the first spendable output
+	// and add all but the first one to the orphan pool.
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[1:] {
+		_, err := harness.txPool.ProcessTransaction(tx, true, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid orphan %v",
+				err)
+		}
+	}
+
+	// Force the first orphan to expire and ensure it is removed along with the
+	// orphan that redeems it.
+	harness.txPool.mtx.Lock()
+	harness.txPool.orphans[*chainedTxns[1].Hash()].expiration = time.Now()
+	harness.txPool.mtx.Unlock()
+	harness.txPool.ExpireOrphans()
+	testPoolMembership(tc, chainedTxns[1], false, false)
+	testPoolMembership(tc, chainedTxns[2], false, false)
+
+	stats := harness.txPool.OrphanStats()
+	if stats.Count != 0 || stats.Size != 0 || stats.Expired != 2 {
+		t.Fatalf("unexpected orphan stats %+v", stats)
+	}
+}
+
+// TestRejectedTxns ensures that the reason transactions are rejected is
+// tracked and that the record is removed once the transaction is accepted.
+func TestRejectedTxns(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+
+	// Ensure an orphan that is rejected because orphans are not allowed is
+	// recorded as rejected with the expected details.
+	const tag = Tag(5)
+	orphan := chainedTxns[1]
+	_, err = txPool.ProcessTransaction(orphan, false, true, tag)
+	if !errors.Is(err, ErrOrphan) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrOrphan)
+	}
+	rejected, ok := txPool.RejectedTx(orphan.Hash())
+	if !ok {
+		t.Fatalf("RejectedTx: no record of rejected transaction %v",
+			orphan.Hash())
+	}
+	if rejected.Hash != *orphan.Hash() || rejected.Tag != tag ||
+		rejected.Kind != string(ErrOrphan) || rejected.Reason != err.Error() {
+
+		t.Fatalf("RejectedTx: unexpected record %+v", rejected)
+	}
+
+	// Ensure transactions that are accepted are not recorded as rejected and
+	// that duplicates are not recorded as rejected either.
+	parent := chainedTxns[0]
+	_, err = txPool.ProcessTransaction(parent, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(parent, false, true, 0)
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrDuplicate)
+	}
+	if _, ok := txPool.RejectedTx(parent.Hash()); ok {
+		t.Fatalf("RejectedTx: unexpected record of transaction %v",
+			parent.Hash())
+	}
+
+	// Ensure the record of the previously rejected orphan is removed once it
+	// is accepted.
+	_, err = txPool.ProcessTransaction(orphan, false, true, tag)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	if _, ok := txPool.RejectedTx(orphan.Hash()); ok {
+		t.Fatalf("RejectedTx: unexpected record of transaction %v",
+			orphan.Hash())
+	}
+}
+
+// TestTxMetrics ensures the metrics of processed transactions that are accepted,
+// orphaned, and rejected are updated as expected.
+func TestTxMetrics(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+	accepted, orphaned := metrics.NewCounter(), metrics.NewCounter()
+	rejected := metrics.NewCounterVec()
+	txPool.cfg.AcceptedTxns = accepted
+	txPool.cfg.OrphanedTxns = orphaned
+	txPool.cfg.RejectedTxns = rejected
+
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+
+	// Ensure an orphan that is rejected because orphans are not allowed is
+	// counted as rejected by the kind of error and an orphan that is allowed
+	// is counted as orphaned.
+	orphan := chainedTxns[1]
+	_, err = txPool.ProcessTransaction(orphan, false, true, 0)
+	if !errors.Is(err, ErrOrphan) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrOrphan)
+	}
+	_, err = txPool.ProcessTransaction(orphan, true, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept orphan: %v", err)
+	}
+
+	// Ensure the parent and the orphan that becomes accepted as a result are
+	// both counted as accepted and that duplicates are not counted.
+	parent := chainedTxns[0]
+	_, err = txPool.ProcessTransaction(parent, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(parent, false, true, 0)
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrDuplicate)
+	}
+
+	if got := accepted.Value(); got != 2 {
+		t.Fatalf("unexpected accepted transactions -- got %d, want 2", got)
+	}
+	if got := orphaned.Value(); got != 1 {
+		t.Fatalf("unexpected orphaned transactions -- got %d, want 1", got)
+	}
+	wantRejected := map[string]uint64{string(ErrOrphan): 1}
+	if got := rejected.Values(); !reflect.DeepEqual(got, wantRejected) {
+		t.Fatalf("unexpected rejected transactions -- got %v, want %v", got,
+			wantRejected)
+	}
+}
+
+// testTxPolicy is a TxPolicy that rejects transactions with the configured
+// hashes for use in testing custom policies.
+type testTxPolicy struct {
+	rejectTx     map[chainhash.Hash]struct{}
+	rejectInputs map[chainhash.Hash]struct{}
+}
+
+// CheckTransaction rejects transactions with the configured hashes.
+//
+// This is part of the TxPolicy interface.
+func (p *testTxPolicy) CheckTransaction(ptx *PolicyTx) error {
+	if _, ok := p.rejectTx[*ptx.Tx.Hash()]; ok {
+		return txRuleError(ErrFeeTooHigh, "rejected by test policy")
+	}
+	return nil
+}
+
+// CheckInputs rejects transactions with the configured hashes.
+//
+// This is part of the TxPolicy interface.
+func (p *testTxPolicy) CheckInputs(ptx *PolicyTx, utxoView *blockchain.UtxoViewpoint) error {
+	if _, ok := p.rejectInputs[*ptx.Tx.Hash()]; ok {
+		return errors.New("inputs rejected by test policy")
+	}
+	return nil
+}
+
+// TestTxPolicies ensures the chain of policies configured on the pool is
+// applied to transactions as expected.
+func TestTxPolicies(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	txPool := harness.txPool
+
+	// Create and accept a transaction that splits the spendable output into
+	// several outputs to use throughout the test.
+	splitTx, err := harness.CreateSignedTx(outputs, 4)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(splitTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	outputs = make([]spendableOutput, 0, len(splitTx.MsgTx().TxOut))
+	for i := range splitTx.MsgTx().TxOut {
+		outputs = append(outputs, txOutToSpendableOut(splitTx, uint32(i),
+			wire.TxTreeRegular))
+	}
+
+	// Create a transaction with a dust output.
+	dustTx, err := harness.CreateSignedTx([]spendableOutput{outputs[0]}, 1,
+		func(tx *wire.MsgTx) {
+			tx.TxOut[0].Value -= 10000
+			tx.AddTxOut(newTxOut(1, harness.payScriptVer, harness.payScript))
+		})
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+
+	// Ensure the transaction is rejected by the default policies.
+	_, err = txPool.ProcessTransaction(dustTx, false, true, 0)
+	if !errors.Is(err, ErrDustOutput) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrDustOutput)
+	}
+	testPoolMembership(tc, dustTx, false, false)
+
+	// Create transactions that are rejected by a custom policy that is added
+	// to the default policies along with one that is not.
+	rejectTx, err := harness.CreateTx(outputs[1])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	rejectInputsTx, err := harness.CreateTx(outputs[2])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	acceptTx, err := harness.CreateTx(outputs[3])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	policy := &testTxPolicy{
+		rejectTx: map[chainhash.Hash]struct{}{
+			*rejectTx.Hash(): {},
+		},
+		rejectInputs: map[chainhash.Hash]struct{}{
+			*rejectInputsTx.Hash(): {},
+		},
+	}
+	txPool.cfg.Policy.TxPolicies = append(DefaultTxPolicies(), policy)
+
+	// Ensure the error kind from a policy rule error is retained and that
+	// other errors are treated as non-standard.
+	_, err = txPool.ProcessTransaction(rejectTx, false, true, 0)
+	if !errors.Is(err, ErrFeeTooHigh) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrFeeTooHigh)
+	}
+	testPoolMembership(tc, rejectTx, false, false)
+	_, err = txPool.ProcessTransaction(rejectInputsTx, false, true, 0)
+	if !errors.Is(err, ErrNonStandard) {
+		t.Fatalf("ProcessTransaction: unexpected error -- got %v, want %v",
+			err, ErrNonStandard)
+	}
+	testPoolMembership(tc, rejectInputsTx, false, false)
+	_, err = txPool.ProcessTransaction(acceptTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	testPoolMembership(tc, acceptTx, false, true)
+
+	// Ensure none of the policies are applied when non-standard transactions
+	// are accepted.
+	txPool.cfg.Policy.AcceptNonStd = true
+	_, err = txPool.ProcessTransaction(rejectTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	testPoolMembership(tc, rejectTx, false, true)
+	txPool.cfg.Policy.AcceptNonStd = false
+
+	// Ensure the transaction with the dust output is accepted once the
+	// policy that rejects dust outputs is removed from the chain.
+	txPool.cfg.Policy.TxPolicies = []TxPolicy{StandardTxPolicy{}}
+	_, err = txPool.ProcessTransaction(dustTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	testPoolMembership(tc, dustTx, false, true)
+}
+
+// TestPrioritiseTransaction ensures fee deltas applied to transactions via
+// PrioritiseTransaction accumulate, are reflected in the mining view, persist
+// when the transaction is removed from the pool for reasons other than being
+// mined, and are removed once the transaction is mined.
+func TestPrioritiseTransaction(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	txPool := harness.txPool
+
+	tx, err := harness.CreateTx(outputs[0])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	txHash := tx.Hash()
+
+	// Ensure a delta may be applied prior to the transaction being added to
+	// the pool and that deltas accumulate.
+	if got := txPool.PrioritiseTransaction(txHash, 5000); got != 5000 {
+		t.Fatalf("PrioritiseTransaction: unexpected delta -- got %d, want %d",
+			got, 5000)
+	}
+	if got := txPool.PrioritiseTransaction(txHash, -2000); got != 3000 {
+		t.Fatalf("PrioritiseTransaction: unexpected delta -- got %d, want %d",
+			got, 3000)
+	}
+	_, err = txPool.ProcessTransaction(tx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	testPoolMembership(tc, tx, false, true)
+
+	// Ensure the delta is reflected in the mining view and that the actual
+	// fee of the transaction is not modified.
+	if got := txPool.MiningView().FeeDelta(txHash); got != 3000 {
+		t.Fatalf("MiningView: unexpected delta -- got %d, want %d", got, 3000)
+	}
+	txDesc := txPool.pool[*txHash]
+	wantFee := outputs[0].amount - dcrutil.Amount(tx.MsgTx().TxOut[0].Value)
+	if txDesc.Fee != int64(wantFee) {
+		t.Fatalf("unexpected fee -- got %d, want %d", txDesc.Fee, wantFee)
+	}
+
+	// Ensure the delta persists when the transaction is removed for a reason
+	// other than being mined and that a resulting total of zero removes it.
+	txPool.mtx.Lock()
+	txPool.removeTransaction(tx, false, RemovalReasonInvalid, nil)
+	txPool.mtx.Unlock()
+	testPoolMembership(tc, tx, false, false)
+	if got := txPool.FeeDelta(txHash); got != 3000 {
+		t.Fatalf("FeeDelta: unexpected delta -- got %d, want %d", got, 3000)
+	}
+	if got := txPool.PrioritiseTransaction(txHash, -3000); got != 0 {
+		t.Fatalf("PrioritiseTransaction: unexpected delta -- got %d, want 0",
+			got)
+	}
+	if got := txPool.MiningView().FeeDelta(txHash); got != 0 {
+		t.Fatalf("MiningView: unexpected delta -- got %d, want 0", got)
+	}
+	if len(txPool.feeDeltaTimes) != 0 {
+		t.Fatalf("unexpected number of fee deltas -- got %d, want 0",
+			len(txPool.feeDeltaTimes))
+	}
+
+	// Ensure the delta is removed once the transaction is mined.
+	txPool.PrioritiseTransaction(txHash, 1000)
+	_, err = txPool.ProcessTransaction(tx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	txPool.RemoveTransaction(tx, false)
+	testPoolMembership(tc, tx, false, false)
+	if got := txPool.FeeDelta(txHash); got != 0 {
+		t.Fatalf("FeeDelta: unexpected delta for mined tx -- got %d, want 0",
+			got)
+	}
+}
+
+// TestFeeDeltaLimits ensures fee deltas for transactions that are not in the
+// pool expire and that the number of fee deltas is limited by evicting the
+// oldest ones.
+func TestFeeDeltaLimits(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Add a transaction with a delta to the pool and set a delta for a
+	// transaction that is not in the pool.
+	tx, err := harness.CreateTx(outputs[0])
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = txPool.ProcessTransaction(tx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	txPool.PrioritiseTransaction(tx.Hash(), 1000)
+	missingHash := chainhash.Hash{0x01}
+	txPool.PrioritiseTransaction(&missingHash, 1000)
+
+	// Ensure only the delta for the transaction that is not in the pool is
+	// removed once the deltas expire.
+	txPool.mtx.Lock()
+	txPool.pruneFeeDeltas(time.Now().Add(feeDeltaTTL + time.Minute))
+	txPool.mtx.Unlock()
+	if got := txPool.FeeDelta(&missingHash); got != 0 {
+		t.Fatalf("FeeDelta: unexpected delta for expired entry -- got %d, "+
+			"want 0", got)
+	}
+	if got := txPool.FeeDelta(tx.Hash()); got != 1000 {
+		t.Fatalf("FeeDelta: unexpected delta for pool tx -- got %d, want %d",
+			got, 1000)
+	}
+
+	// Ensure the oldest deltas are evicted once the maximum number of deltas
+	// is reached.
+	for i := 0; i < maxFeeDeltas; i++ {
+		var hash chainhash.Hash
+		binary.LittleEndian.PutUint32(hash[1:], uint32(i))
+		txPool.PrioritiseTransaction(&hash, 1000)
+	}
+	if len(txPool.feeDeltaTimes) != maxFeeDeltas {
+		t.Fatalf("unexpected number of fee deltas -- got %d, want %d",
+			len(txPool.feeDeltaTimes), maxFeeDeltas)
+	}
+	if got := txPool.FeeDelta(tx.Hash()); got != 0 {
+		t.Fatalf("FeeDelta: unexpected delta for evicted entry -- got %d, "+
+			"want 0", got)
+	}
+}
+
+// TestTxEvents ensures the expected transaction events are generated with
+// increasing sequence numbers as transactions are added to and removed from
+// the pool for various reasons.
+func TestTxEvents(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+	var events []*TxEvent
+	txPool.cfg.OnTxEvent = func(event *TxEvent) {
+		events = append(events, event)
+	}
+
+	// Create a chain of transactions along with a transaction that conflicts
+	// with the first one in the chain.
+	chainedTxns, err := harness.CreateTxChain(outputs[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	conflictTx, err := harness.CreateSignedTx(outputs, 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+
+	// Add the chain to the pool, remove it due to the conflicting transaction,
+	// and then add and remove the conflicting transaction as if it were mined.
+	for _, tx := range chainedTxns {
+		_, err = txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+		}
+	}
+	txPool.RemoveDoubleSpends(conflictTx)
+	_, err = txPool.ProcessTransaction(conflictTx, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+	}
+	txPool.RemoveTransaction(conflictTx, false)
+
+	// Ensure the expected events were generated in order.
+	wantEvents := []TxEvent{{
+		Type: TxEventAdded,
+		Tx:   chainedTxns[0],
+	}, {
+		Type: TxEventAdded,
+		Tx:   chainedTxns[1],
+	}, {
+		Type:   TxEventRemoved,
+		Tx:     chainedTxns[1],
+		Reason: RemovalReasonParentRemoved,
+	}, {
+		Type:       TxEventRemoved,
+		Tx:         chainedTxns[0],
+		Reason:     RemovalReasonConflict,
+		ReplacedBy: conflictTx.Hash(),
+	}, {
+		Type: TxEventAdded,
+		Tx:   conflictTx,
+	}, {
+		Type:   TxEventRemoved,
+		Tx:     conflictTx,
+		Reason: RemovalReasonMined,
+	}}
+	if len(events) != len(wantEvents) {
+		t.Fatalf("unexpected number of events -- got %d, want %d",
+			len(events), len(wantEvents))
+	}
+	for i, event := range events {
+		want := &wantEvents[i]
+		if event.Sequence != uint64(i+1) || event.Type != want.Type ||
+			*event.Tx.Hash() != *want.Tx.Hash() || event.Reason != want.Reason {
+
+			t.Fatalf("unexpected event %d -- got (%d, %v, %v, %v), want "+
+				"(%d, %v, %v, %v)", i, event.Sequence, event.Type,
+				event.Tx.Hash(), event.Reason, i+1, want.Type, want.Tx.Hash(),
+				want.Reason)
+		}
+		if (event.ReplacedBy == nil) != (want.ReplacedBy == nil) ||
+			(event.ReplacedBy != nil && *event.ReplacedBy != *want.ReplacedBy) {
+
+			t.Fatalf("unexpected replacement for event %d -- got %v, want %v",
+				i, event.ReplacedBy, want.ReplacedBy)
+		}
+	}
+
+	// Ensure the current sequence number matches the final event.
+	if got := txPool.TxEventSequence(); got != uint64(len(wantEvents)) {
+		t.Fatalf("unexpected sequence -- got %d, want %d", got,
+			len(wantEvents))
+	}
+	hashes, seq := txPool.TxHashesWithSequence()
+	if len(hashes) != 0 || seq != uint64(len(wantEvents)) {
+		t.Fatalf("unexpected hashes with sequence -- got %d hashes and "+
+			"sequence %d, want 0 hashes and sequence %d", len(hashes), seq,
+			len(wantEvents))
+	}
+}
+
+// TestStakeTxnsForNextBlock ensures the stake view for the next block only
+// includes eligible stake transactions and that they are sorted as expected.
+func TestStakeTxnsForNextBlock(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Create a chain of transactions to fund ticket purchases and add them to
+	// the fake chain as if they were mined.
+	fundingTxns, err := harness.CreateTxChain(outputs[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range fundingTxns {
+		harness.AddFakeUTXO(tx, harness.chain.BestHeight(), 0)
+	}
+
+	// Create two ticket purchases that will be added to the pool along with a
+	// third ticket that is added to the fake chain so it can be voted with.
+	ticket1, err := harness.CreateTicketPurchaseFromTx(fundingTxns[0], 40000)
+	if err != nil {
+		t.Fatalf("unable to create ticket purchase transaction: %v", err)
+	}
+	ticket2, err := harness.CreateTicketPurchaseFromTx(fundingTxns[1], 50000)
+	if err != nil {
+		t.Fatalf("unable to create ticket purchase transaction: %v", err)
+	}
+	votedTicket, err := harness.CreateTicketPurchaseFromTx(fundingTxns[2], 40000)
+	if err != nil {
+		t.Fatalf("unable to create ticket purchase transaction: %v", err)
+	}
+	harness.AddFakeUTXO(votedTicket,
+		int64(votedTicket.MsgTx().TxIn[0].BlockHeight), wire.NullBlockIndex)
+
+	harness.chain.SetHeight(harness.chainParams.StakeValidationHeight)
+	vote, err := harness.CreateVote(votedTicket)
+	if err != nil {
+		t.Fatalf("unable to create vote: %v", err)
+	}
+	for _, tx := range []*dcrutil.Tx{ticket1, ticket2, vote} {
+		_, err = txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid tx: %v", err)
+		}
+	}
+
+	// Prioritize the first ticket so it is deterministically sorted first.
+	txPool.PrioritiseTransaction(ticket1.Hash(), 1e6)
+
+	// assertView ensures the stake view contains exactly the provided votes
+	// and tickets in order.
+	assertView := func(wantVotes, wantTickets []*dcrutil.Tx) {
+		t.Helper()
+
+		view, err := txPool.StakeTxnsForNextBlock()
+		if err != nil {
+			t.Fatalf("StakeTxnsForNextBlock: unexpected error: %v", err)
+		}
+		if view.TipHash != *harness.chain.BestHash() ||
+			view.TipHeight != harness.chain.BestHeight() {
+
+			t.Fatalf("unexpected tip -- got %v (height %d), want %v "+
+				"(height %d)", view.TipHash, view.TipHeight,
+				harness.chain.BestHash(), harness.chain.BestHeight())
+		}
+		checkDescs := func(kind string, got []*TxDesc, want []*dcrutil.Tx) {
+			t.Helper()
+
+			if len(got) != len(want) {
+				t.Fatalf("unexpected number of %s -- got %d, want %d", kind,
+					len(got), len(want))
+			}
+			for i, desc := range got {
+				if *desc.Tx.Hash() != *want[i].Hash() {
+					t.Fatalf("unexpected %s at index %d -- got %v, want %v",
+						kind, i, desc.Tx.Hash(), want[i].Hash())
+				}
+			}
+		}
+		checkDescs("votes", view.Votes, wantVotes)
+		checkDescs("tickets", view.Tickets, wantTickets)
+		checkDescs("revocations", view.Revocations, nil)
+	}
+	assertView([]*dcrutil.Tx{vote}, []*dcrutil.Tx{ticket1, ticket2})
+
+	// Ensure tickets that no longer pay the required stake difficulty are
+	// excluded.
+	harness.chain.SetNextStakeDifficulty(45000)
+	assertView([]*dcrutil.Tx{vote}, []*dcrutil.Tx{ticket2})
+
+	// Ensure votes that do not vote on the current tip are excluded.
+	harness.chain.SetBestHash(&chainhash.Hash{0x01})
+	assertView(nil, []*dcrutil.Tx{ticket2})
+
+	// Ensure the view uses the stake difficulty for the tip it was created
+	// against when the tip changes while the stake difficulty is queried by
+	// simulating a new block that lowers the stake difficulty.
+	var tipChanged bool
+	txPool.cfg.NextStakeDifficulty = func() (int64, error) {
+		nextStakeDiff, err := harness.chain.NextStakeDifficulty()
+		if !tipChanged {
+			tipChanged = true
+			harness.chain.SetBestHash(&chainhash.Hash{0x02})
+			harness.chain.SetHeight(harness.chain.BestHeight() + 1)
+			harness.chain.SetNextStakeDifficulty(40000)
+		}
+		return nextStakeDiff, err
+	}
+	assertView(nil, []*dcrutil.Tx{ticket1, ticket2})
+	if !tipChanged {
+		t.Fatal("stake difficulty was not queried")
+	}
+}
+
 // TestExpirationPruning ensures that transactions that expire without being
 // mined are removed.
 func TestExpirationPruning(t *testing.T) {
@@ -2253,6 +3110,107 @@ func TestFetchTransaction(t *testing.T) {
 	}
 }
 
+// TestTxRelatives ensures the descriptor, verbose descriptor, ancestors, and
+// descendants of transactions in the main pool are the expected transactions.
+func TestTxRelatives(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+
+	// Create and accept a chain of transactions rooted with the first
+	// spendable output provided by the harness.
+	chainedTxns, err := harness.CreateTxChain(spendableOuts[0], 3)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(tx, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+	}
+
+	// descHashes returns the hashes of the transactions of the provided
+	// descriptors.
+	descHashes := func(descs []*TxDesc) []chainhash.Hash {
+		hashes := make([]chainhash.Hash, 0, len(descs))
+		for _, desc := range descs {
+			hashes = append(hashes, *desc.Tx.Hash())
+		}
+		return hashes
+	}
+
+	// Ensure the descriptor of the middle transaction is for it.
+	tx0, tx1, tx2 := chainedTxns[0], chainedTxns[1], chainedTxns[2]
+	txd, ok := harness.txPool.TxDesc(tx1.Hash())
+	if !ok {
+		t.Fatal("TxDesc: transaction not found")
+	}
+	if *txd.Tx.Hash() != *tx1.Hash() {
+		t.Fatalf("TxDesc: unexpected transaction %v", txd.Tx.Hash())
+	}
+
+	// Ensure the verbose descriptor of the middle transaction includes its
+	// parent and child.
+	vtxd, ok := harness.txPool.VerboseTxDesc(tx1.Hash())
+	if !ok {
+		t.Fatal("VerboseTxDesc: transaction not found")
+	}
+	if got := descHashes(vtxd.Depends); !reflect.DeepEqual(got,
+		[]chainhash.Hash{*tx0.Hash()}) {
+
+		t.Fatalf("VerboseTxDesc: unexpected depends %v", got)
+	}
+	if got := descHashes(vtxd.SpentBy); !reflect.DeepEqual(got,
+		[]chainhash.Hash{*tx2.Hash()}) {
+
+		t.Fatalf("VerboseTxDesc: unexpected spent by %v", got)
+	}
+
+	// Ensure the ancestors and descendants include the full chain in order
+	// of distance from the transaction.
+	ancestors, ok := harness.txPool.Ancestors(tx2.Hash())
+	if !ok {
+		t.Fatal("Ancestors: transaction not found")
+	}
+	if got := descHashes(ancestors); !reflect.DeepEqual(got,
+		[]chainhash.Hash{*tx1.Hash(), *tx0.Hash()}) {
+
+		t.Fatalf("Ancestors: unexpected ancestors %v", got)
+	}
+	descendants, ok := harness.txPool.Descendants(tx0.Hash())
+	if !ok {
+		t.Fatal("Descendants: transaction not found")
+	}
+	if got := descHashes(descendants); !reflect.DeepEqual(got,
+		[]chainhash.Hash{*tx1.Hash(), *tx2.Hash()}) {
+
+		t.Fatalf("Descendants: unexpected descendants %v", got)
+	}
+	if descendants, _ := harness.txPool.Descendants(tx2.Hash()); len(descendants) != 0 {
+		t.Fatalf("Descendants: unexpected descendants of last tx %v",
+			descHashes(descendants))
+	}
+
+	// Ensure transactions that are not in the main pool are reported as such.
+	var unknown chainhash.Hash
+	if _, ok := harness.txPool.TxDesc(&unknown); ok {
+		t.Fatal("TxDesc: found unknown transaction")
+	}
+	if _, ok := harness.txPool.VerboseTxDesc(&unknown); ok {
+		t.Fatal("VerboseTxDesc: found unknown transaction")
+	}
+	if _, ok := harness.txPool.Ancestors(&unknown); ok {
+		t.Fatal("Ancestors: found unknown transaction")
+	}
+	if _, ok := harness.txPool.Descendants(&unknown); ok {
+		t.Fatal("Descendants: found unknown transaction")
+	}
+}
+
 // TestRemoveDoubleSpends verifies that a ticket in the stage pool that has a
 // double-spent input due to a reorg is removed from the stage pool.
 func TestRemoveDoubleSpends(t *testing.T) {
@@ -0,0 +1,179 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// TxEventType represents the type of a transaction event.
+type TxEventType int
+
+// Constants for the type of a transaction event.
+const (
+	// TxEventAdded indicates the associated transaction was added to the main
+	// pool.
+	TxEventAdded TxEventType = iota
+
+	// TxEventRemoved indicates the associated transaction was removed from the
+	// main pool.  The reason it was removed is specified by the event.
+	TxEventRemoved
+)
+
+// txEventTypeStrings is a map of transaction event types back to their
+// human-readable names.
+var txEventTypeStrings = map[TxEventType]string{
+	TxEventAdded:   "added",
+	TxEventRemoved: "removed",
+}
+
+// String returns the TxEventType in human-readable form.
+func (t TxEventType) String() string {
+	if s, ok := txEventTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown TxEventType (%d)", int(t))
+}
+
+// RemovalReason identifies the reason a transaction was removed from the main
+// pool.
+type RemovalReason int
+
+// Constants for the reason a transaction was removed from the main pool.
+const (
+	// RemovalReasonNone indicates no removal reason applies.  It is the
+	// reason associated with events for transactions that were added.
+	RemovalReasonNone RemovalReason = iota
+
+	// RemovalReasonMined indicates the transaction was included in a block
+	// that was connected to the main chain.
+	RemovalReasonMined
+
+	// RemovalReasonConflict indicates the transaction was replaced by a
+	// conflicting transaction that spends one or more of the same outputs
+	// and was included in a block that was connected to the main chain.
+	RemovalReasonConflict
+
+	// RemovalReasonExpired indicates the transaction expired and is no longer
+	// able to be included in a block.
+	RemovalReasonExpired
+
+	// RemovalReasonStale indicates the transaction is a stake transaction
+	// that is no longer able to be included in a block, such as an old vote
+	// or a ticket that no longer pays the required stake difficulty.
+	RemovalReasonStale
+
+	// RemovalReasonParentRemoved indicates the transaction was removed
+	// because a transaction it spends from was removed.
+	RemovalReasonParentRemoved
+
+	// RemovalReasonStaged indicates the transaction is a ticket that was
+	// moved to the stage pool because it now spends an output of another
+	// transaction in the pool.
+	RemovalReasonStaged
+
+	// RemovalReasonInvalid indicates the transaction is no longer valid, for
+	// example, because it was added back to the pool during a reorganization
+	// and failed to pass validation.
+	RemovalReasonInvalid
+)
+
+// removalReasonStrings is a map of removal reasons back to their
+// human-readable names.
+var removalReasonStrings = map[RemovalReason]string{
+	RemovalReasonNone:          "none",
+	RemovalReasonMined:         "mined",
+	RemovalReasonConflict:      "conflict",
+	RemovalReasonExpired:       "expired",
+	RemovalReasonStale:         "stale",
+	RemovalReasonParentRemoved: "parentremoved",
+	RemovalReasonStaged:        "staged",
+	RemovalReasonInvalid:       "invalid",
+}
+
+// String returns the RemovalReason in human-readable form.
+func (r RemovalReason) String() string {
+	if s, ok := removalReasonStrings[r]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown RemovalReason (%d)", int(r))
+}
+
+// TxEvent describes a transaction being added to or removed from the main
+// pool.
+type TxEvent struct {
+	// Sequence is the sequence number of the event.  Sequence numbers start
+	// at one and increase by one for every event, so they may be used to
+	// detect missed events.
+	Sequence uint64
+
+	// Type is the type of the event.
+	Type TxEventType
+
+	// Tx is the transaction the event applies to.
+	Tx *dcrutil.Tx
+
+	// Reason is the reason the transaction was removed.  It is
+	// RemovalReasonNone for added transactions.
+	Reason RemovalReason
+
+	// ReplacedBy is the hash of the transaction that replaced the removed
+	// transaction when the reason is RemovalReasonConflict.  It is nil
+	// otherwise.
+	ReplacedBy *chainhash.Hash
+}
+
+// notifyTxEvent assigns the next sequence number to a new event for the
+// provided transaction and passes it to the configured callback, if any.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) notifyTxEvent(eventType TxEventType, tx *dcrutil.Tx,
+	reason RemovalReason, replacedBy *chainhash.Hash) {
+
+	mp.txEventSeq++
+	if mp.cfg.OnTxEvent == nil {
+		return
+	}
+	mp.cfg.OnTxEvent(&TxEvent{
+		Sequence:   mp.txEventSeq,
+		Type:       eventType,
+		Tx:         tx,
+		Reason:     reason,
+		ReplacedBy: replacedBy,
+	})
+}
+
+// TxEventSequence returns the sequence number of the most recent transaction
+// event.  It is zero when no events have happened.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TxEventSequence() uint64 {
+	mp.mtx.RLock()
+	seq := mp.txEventSeq
+	mp.mtx.RUnlock()
+	return seq
+}
+
+// TxHashesWithSequence returns a slice of hashes for all of the transactions in
+// the main pool along with the sequence number of the most recent transaction
+// event at the time the hashes were collected.  Callers that mirror the pool
+// via transaction events may use the sequence number to determine which events
+// are already reflected in the returned hashes.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) TxHashesWithSequence() ([]*chainhash.Hash, uint64) {
+	mp.mtx.RLock()
+	hashes := make([]*chainhash.Hash, 0, len(mp.pool))
+	for hash := range mp.pool {
+		hashCopy := hash
+		hashes = append(hashes, &hashCopy)
+	}
+	seq := mp.txEventSeq
+	mp.mtx.RUnlock()
+	return hashes, seq
+}
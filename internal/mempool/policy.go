@@ -27,6 +27,12 @@ const (
 	// for mining.
 	MaxStandardTxSize = 100000
 
+	// DefaultMaxOrphanPoolSize is the default maximum total serialized size
+	// of all transactions in the orphan pool.  It allows the orphan pool to
+	// hold a reasonable number of typical orphans while preventing it from
+	// growing to the size implied by a full pool of max standard size orphans.
+	DefaultMaxOrphanPoolSize = 5000000
+
 	// maxStandardSigScriptSize is the maximum size allowed for a
 	// transaction input signature script to be considered standard.  This
 	// value allows for a 15-of-15 CHECKMULTISIG pay-to-script-hash with
@@ -298,6 +304,24 @@ func isDust(txOut *wire.TxOut, minRelayTxFee dcrutil.Amount) bool {
 func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 	medianTime time.Time, minRelayTxFee dcrutil.Amount) error {
 
+	err := checkTxFormStandard(tx, txType, height, medianTime)
+	if err != nil {
+		return err
+	}
+	return checkOutputsStandard(tx, txType, minRelayTxFee)
+}
+
+// checkTxFormStandard performs the checks on a transaction that ensure its
+// overall form is "standard".  That is to say it ensures the transaction is
+// serialized with all required data, is finalized, conforms to the standard
+// size constraints, and only has signature scripts that are of a standard size
+// and only push data.
+//
+// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
+// instance.
+func checkTxFormStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
+	medianTime time.Time) error {
+
 	// The transaction must be a currently supported serialize type.
 	msgTx := tx.MsgTx()
 	if msgTx.SerType != wire.TxSerializeFull {
@@ -352,8 +376,21 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 		}
 	}
 
+	return nil
+}
+
+// checkOutputsStandard performs the checks on the outputs of a transaction to
+// ensure they are "standard".  That is to say none of the output public key
+// scripts are non-standard scripts or "dust" (except when the script is a null
+// data script) and regular transactions do not have an excessive number of
+// outputs that only carry data.
+//
+// Note: all non-nil errors MUST be RuleError with an underlying TxRuleError
+// instance.
+func checkOutputsStandard(tx *dcrutil.Tx, txType stake.TxType, minRelayTxFee dcrutil.Amount) error {
 	// None of the output public key scripts can be a non-standard script or
 	// be "dust" (except when the script is a null data script).
+	msgTx := tx.MsgTx()
 	numNullDataOutputs := 0
 	for i, txOut := range msgTx.TxOut {
 		scriptType := stdscript.DetermineScriptType(txOut.Version,
@@ -388,3 +425,125 @@ func checkTransactionStandard(tx *dcrutil.Tx, txType stake.TxType, height int64,
 
 	return nil
 }
+
+// PolicyTx houses a transaction that is being considered for acceptance to the
+// memory pool along with additional details about the context it is being
+// considered in that are useful when applying policy checks to it.
+type PolicyTx struct {
+	// Tx is the transaction being considered.
+	Tx *dcrutil.Tx
+
+	// Type is the stake transaction type of the transaction.
+	Type stake.TxType
+
+	// NextBlockHeight is the height of the block after the current best
+	// chain tip.
+	NextBlockHeight int64
+
+	// MedianTime is the median time of the current best chain tip.
+	MedianTime time.Time
+
+	// MinRelayTxFee is the minimum transaction fee in atoms/kB required by
+	// the memory pool policy.
+	MinRelayTxFee dcrutil.Amount
+
+	// IsTreasuryEnabled specifies whether or not the treasury agenda is
+	// active.
+	IsTreasuryEnabled bool
+}
+
+// TxPolicy describes a set of policy checks that a transaction must pass in
+// order to be accepted to the memory pool.  Policy checks are more restrictive
+// than the consensus rules and are intended to limit the transactions that are
+// relayed and considered for mining to those that are well understood and do
+// not impose undue burden on the network.
+//
+// The memory pool applies an ordered chain of policies to every transaction
+// in addition to the consensus rules, which are always enforced and can't be
+// modified by policies.  The transaction is rejected as soon as any policy in
+// the chain returns an error.
+//
+// Implementations should return a RuleError from the errors they generate so
+// they are treated as rejections as opposed to internal failures.  Errors that
+// are not rule errors are converted to rule errors with ErrNonStandard as the
+// underlying ErrorKind.
+type TxPolicy interface {
+	// CheckTransaction performs the policy checks that only depend on the
+	// transaction itself.  It is called prior to fetching the outputs the
+	// transaction references, so it should be used for inexpensive checks in
+	// order to reject unwanted transactions as early as possible.
+	CheckTransaction(ptx *PolicyTx) error
+
+	// CheckInputs performs the policy checks that depend on the outputs the
+	// transaction references.  The provided view contains all of the
+	// referenced outputs and is called after the transaction has passed all
+	// consensus checks on its inputs.
+	CheckInputs(ptx *PolicyTx, utxoView *blockchain.UtxoViewpoint) error
+}
+
+// StandardTxPolicy is a TxPolicy which ensures transactions are of a standard
+// form and only spend standard inputs.  See StandardOutputsPolicy for the
+// related policy that applies to the outputs of transactions.
+//
+// In particular, it ensures the transaction is serialized with all required
+// data, is finalized, conforms to the standard size constraints, only has
+// signature scripts that are of a standard size and only push data, and only
+// spends outputs with standard scripts that do not have an excessive number of
+// signature operations.
+type StandardTxPolicy struct{}
+
+// Ensure StandardTxPolicy implements the TxPolicy interface.
+var _ TxPolicy = StandardTxPolicy{}
+
+// CheckTransaction ensures the transaction is of a standard form.
+//
+// This is part of the TxPolicy interface.
+func (StandardTxPolicy) CheckTransaction(ptx *PolicyTx) error {
+	return checkTxFormStandard(ptx.Tx, ptx.Type, ptx.NextBlockHeight,
+		ptx.MedianTime)
+}
+
+// CheckInputs ensures the transaction only spends standard inputs.
+//
+// This is part of the TxPolicy interface.
+func (StandardTxPolicy) CheckInputs(ptx *PolicyTx, utxoView *blockchain.UtxoViewpoint) error {
+	return checkInputsStandard(ptx.Tx, ptx.Type, utxoView,
+		ptx.IsTreasuryEnabled)
+}
+
+// StandardOutputsPolicy is a TxPolicy which ensures transactions only create
+// outputs with standard scripts that are not dust and that regular transactions
+// do not have an excessive number of outputs that only carry data.
+//
+// Callers that wish to permit additional script classes or change the limits
+// on data carrier outputs may replace this policy with their own.
+type StandardOutputsPolicy struct{}
+
+// Ensure StandardOutputsPolicy implements the TxPolicy interface.
+var _ TxPolicy = StandardOutputsPolicy{}
+
+// CheckTransaction ensures the transaction only creates standard outputs.
+//
+// This is part of the TxPolicy interface.
+func (StandardOutputsPolicy) CheckTransaction(ptx *PolicyTx) error {
+	return checkOutputsStandard(ptx.Tx, ptx.Type, ptx.MinRelayTxFee)
+}
+
+// CheckInputs does not perform any checks since the policy only applies to
+// the outputs of transactions.
+//
+// This is part of the TxPolicy interface.
+func (StandardOutputsPolicy) CheckInputs(*PolicyTx, *blockchain.UtxoViewpoint) error {
+	return nil
+}
+
+// DefaultTxPolicies returns the ordered chain of policies that are applied to
+// transactions by default when no policies are specified in the memory pool
+// policy configuration.  Callers may use the result as a base to extend.
+func DefaultTxPolicies() []TxPolicy {
+	return []TxPolicy{StandardTxPolicy{}, StandardOutputsPolicy{}}
+}
+
+// defaultTxPolicies houses the default chain of policies so it does not need
+// to be recreated for every transaction.
+var defaultTxPolicies = DefaultTxPolicies()
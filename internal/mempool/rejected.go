@@ -0,0 +1,127 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"errors"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/container/lru"
+	"github.com/decred/dcrd/internal/blockchain"
+)
+
+const (
+	// maxRejectedTxns is the maximum number of recently rejected transactions
+	// to keep track of along with the reason they were rejected.
+	maxRejectedTxns = 2000
+)
+
+// RejectedTx describes a transaction that was recently rejected by the memory
+// pool along with the reason it was rejected.
+type RejectedTx struct {
+	// Hash is the hash of the rejected transaction.
+	Hash chainhash.Hash
+
+	// Tag is the tag that was provided along with the transaction when it was
+	// processed.  It is typically the ID of the peer that relayed it.
+	Tag Tag
+
+	// Time is when the transaction was rejected.
+	Time time.Time
+
+	// Kind is the specific kind of error that caused the rejection, such as
+	// ErrInsufficientFee, when it is known.  Note that it may also be one of
+	// the error kinds defined by the blockchain or stake packages for
+	// rejections due to consensus rules.  It is empty when the specific kind
+	// of error is not known.
+	Kind string
+
+	// Reason is a human-readable description of the reason the transaction
+	// was rejected.
+	Reason string
+}
+
+// rejectedTxCache houses a bounded cache of recently rejected transactions
+// keyed by their hash.  The least recently rejected entry is evicted when the
+// cache is full.
+type rejectedTxCache struct {
+	cache *lru.Map[chainhash.Hash, *RejectedTx]
+}
+
+// newRejectedTxCache returns a new rejected transaction cache that is limited
+// to the provided maximum number of entries.
+func newRejectedTxCache(limit uint64) *rejectedTxCache {
+	cache := lru.NewMap[chainhash.Hash, *RejectedTx](limit)
+	return &rejectedTxCache{cache: cache}
+}
+
+// rejectKind attempts to determine the specific kind of error that caused a
+// transaction to be rejected from the provided error.  It returns an empty
+// string when the kind is not known.
+func rejectKind(err error) string {
+	var kind ErrorKind
+	if errors.As(err, &kind) {
+		return string(kind)
+	}
+	var chainKind blockchain.ErrorKind
+	if errors.As(err, &chainKind) {
+		return string(chainKind)
+	}
+	var stakeKind stake.ErrorKind
+	if errors.As(err, &stakeKind) {
+		return string(stakeKind)
+	}
+	return ""
+}
+
+// add records the provided transaction hash as rejected for the reason
+// described by the provided error.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) add(hash *chainhash.Hash, tag Tag, err error) {
+	c.cache.Put(*hash, &RejectedTx{
+		Hash:   *hash,
+		Tag:    tag,
+		Time:   time.Now(),
+		Kind:   rejectKind(err),
+		Reason: err.Error(),
+	})
+}
+
+// remove removes any existing rejection entry for the provided transaction
+// hash.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) remove(hash *chainhash.Hash) {
+	c.cache.Delete(*hash)
+}
+
+// lookup returns the rejection entry for the provided transaction hash when it
+// exists.
+//
+// This function is safe for concurrent access.
+func (c *rejectedTxCache) lookup(hash *chainhash.Hash) (*RejectedTx, bool) {
+	entry, ok := c.cache.Get(*hash)
+	if !ok {
+		return nil, false
+	}
+	rejected := *entry
+	return &rejected, true
+}
+
+// RejectedTx returns details about why the transaction with the provided hash
+// was rejected when it was recently rejected by the memory pool.  The second
+// return value is false when there is no record of the transaction being
+// rejected, either because it never was or because the record was evicted to
+// make room for more recent rejections.
+//
+// Note that the record is removed should the transaction later be accepted.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) RejectedTx(hash *chainhash.Hash) (*RejectedTx, bool) {
+	return mp.rejected.lookup(hash)
+}
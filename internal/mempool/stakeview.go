@@ -0,0 +1,144 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/internal/blockchain"
+)
+
+// StakeView houses a consistent snapshot of the stake transactions in the main
+// pool that are eligible for inclusion in the block after the current best
+// chain tip.  Each slice is sorted by fee rate in descending order, taking any
+// fee deltas set via PrioritiseTransaction into account, with ties broken by
+// transaction hash so the ordering is deterministic.
+type StakeView struct {
+	// TipHash and TipHeight identify the best chain tip the view was
+	// created against.  The eligible transactions are only valid for a
+	// block that builds on this tip.
+	TipHash   chainhash.Hash
+	TipHeight int64
+
+	// NextStakeDiff is the stake difficulty required of ticket purchases in
+	// the block after the tip.
+	NextStakeDiff int64
+
+	// Votes are the votes on the tip block.  Votes on any other block are
+	// not eligible for inclusion in the next block and are excluded.
+	Votes []*TxDesc
+
+	// Tickets are the ticket purchases that pay at least the required stake
+	// difficulty.  Note that blocks are limited to MaxFreshStakePerBlock
+	// ticket purchases as defined by the chain parameters, so callers must
+	// limit the number they include accordingly.
+	Tickets []*TxDesc
+
+	// Revocations are the revocations currently in the pool.
+	Revocations []*TxDesc
+}
+
+// stakeViewEntry pairs a transaction descriptor with its fee rate for the
+// purposes of sorting.
+type stakeViewEntry struct {
+	desc    *TxDesc
+	feeRate float64
+}
+
+// sortStakeViewEntries sorts the provided entries by fee rate in descending
+// order with ties broken by transaction hash and returns the resulting
+// descriptors.
+func sortStakeViewEntries(entries []stakeViewEntry) []*TxDesc {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].feeRate != entries[j].feeRate {
+			return entries[i].feeRate > entries[j].feeRate
+		}
+		return bytes.Compare(entries[i].desc.Tx.Hash()[:],
+			entries[j].desc.Tx.Hash()[:]) < 0
+	})
+	descs := make([]*TxDesc, 0, len(entries))
+	for i := range entries {
+		descs = append(descs, entries[i].desc)
+	}
+	return descs
+}
+
+// StakeTxnsForNextBlock returns a consistent view of the votes, ticket
+// purchases, and revocations in the main pool that are eligible for inclusion
+// in the block after the current best chain tip.  See StakeView for details on
+// the exact criteria and ordering.
+//
+// Transactions that are still in the stage pool or that would be expired in
+// the next block are never included.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) StakeTxnsForNextBlock() (*StakeView, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	// The best chain tip and next stake difficulty are queried separately
+	// and the tip may change at any point since it is not protected by the
+	// pool mutex, so retry until the tip is the same both before and after
+	// the stake difficulty is queried to ensure they are consistent.
+	var tipHash chainhash.Hash
+	var tipHeight, nextStakeDiff int64
+	for {
+		tipHash = *mp.cfg.BestHash()
+		tipHeight = mp.cfg.BestHeight()
+		var err error
+		nextStakeDiff, err = mp.cfg.NextStakeDifficulty()
+		if err != nil {
+			return nil, err
+		}
+		if *mp.cfg.BestHash() == tipHash {
+			break
+		}
+	}
+	nextBlockHeight := tipHeight + 1
+
+	var votes, tickets, revocations []stakeViewEntry
+	for _, desc := range mp.pool {
+		tx := desc.Tx
+		if blockchain.IsExpired(tx, nextBlockHeight) {
+			continue
+		}
+
+		fee := desc.Fee + mp.miningView.FeeDelta(tx.Hash())
+		entry := stakeViewEntry{
+			desc:    desc,
+			feeRate: float64(fee) / float64(tx.MsgTx().SerializeSize()),
+		}
+		switch desc.Type {
+		case stake.TxTypeSSGen:
+			// Votes are only eligible when they vote on the current tip.
+			votedHash, votedHeight := stake.SSGenBlockVotedOn(tx.MsgTx())
+			if votedHash != tipHash || int64(votedHeight) != tipHeight {
+				continue
+			}
+			votes = append(votes, entry)
+
+		case stake.TxTypeSStx:
+			if tx.MsgTx().TxOut[0].Value < nextStakeDiff {
+				continue
+			}
+			tickets = append(tickets, entry)
+
+		case stake.TxTypeSSRtx:
+			revocations = append(revocations, entry)
+		}
+	}
+
+	return &StakeView{
+		TipHash:       tipHash,
+		TipHeight:     tipHeight,
+		NextStakeDiff: nextStakeDiff,
+		Votes:         sortStakeViewEntries(votes),
+		Tickets:       sortStakeViewEntries(tickets),
+		Revocations:   sortStakeViewEntries(revocations),
+	}, nil
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package metrics provides the primitives used to instrument the various
+subsystems along with an HTTP server that serves the collected metrics in the
+Prometheus text exposition format.
+
+Metrics are disabled by default.  The instrumented subsystems accept optional
+metrics in their configuration and the primitives provided by this package
+treat nil instances as disabled, so subsystems that are not provided with
+metrics only incur the cost of a nil check.
+
+Each subsystem provides its metrics via the Source interface which writes them
+in the text exposition format.  The server serves the metrics of all of its
+sources at the /metrics path.  Requests are only required to authenticate via
+HTTP basic authentication when the server is configured with credentials, so
+servers without them should only be exposed to trusted networks.
+*/
+package metrics
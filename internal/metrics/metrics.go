@@ -0,0 +1,324 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Source represents a source of metrics that are written in the Prometheus
+// text exposition format.
+type Source interface {
+	// WriteMetrics writes the current metrics to the provided writer in the
+	// Prometheus text exposition format.
+	WriteMetrics(w io.Writer) error
+}
+
+// Counter is a monotonically increasing count that is safe for concurrent
+// access.  A nil counter is valid and ignores all updates, which allows
+// instrumented code to remain unconditional when metrics are disabled.
+type Counter struct {
+	// The following variables must only be used atomically.
+	value uint64
+}
+
+// NewCounter returns a new counter with a value of zero.
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+// Add increases the counter by the provided amount.
+//
+// This function is safe for concurrent access.
+func (c *Counter) Add(n uint64) {
+	if c == nil {
+		return
+	}
+	atomic.AddUint64(&c.value, n)
+}
+
+// Inc increases the counter by one.
+//
+// This function is safe for concurrent access.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Value returns the current value of the counter.
+//
+// This function is safe for concurrent access.
+func (c *Counter) Value() uint64 {
+	if c == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&c.value)
+}
+
+// CounterVec is a set of counters that are partitioned by the value of a
+// single label.  Callers must ensure the number of distinct label values is
+// bounded.  A nil counter vector is valid and ignores all updates.
+type CounterVec struct {
+	mtx    sync.Mutex
+	values map[string]uint64
+}
+
+// NewCounterVec returns a new empty counter vector.
+func NewCounterVec() *CounterVec {
+	return &CounterVec{values: make(map[string]uint64)}
+}
+
+// Inc increases the counter for the provided label value by one.
+//
+// This function is safe for concurrent access.
+func (v *CounterVec) Inc(label string) {
+	if v == nil {
+		return
+	}
+	v.mtx.Lock()
+	v.values[label]++
+	v.mtx.Unlock()
+}
+
+// Values returns a snapshot of the counters keyed by their label values.
+//
+// This function is safe for concurrent access.
+func (v *CounterVec) Values() map[string]uint64 {
+	if v == nil {
+		return nil
+	}
+	v.mtx.Lock()
+	values := make(map[string]uint64, len(v.values))
+	for label, value := range v.values {
+		values[label] = value
+	}
+	v.mtx.Unlock()
+	return values
+}
+
+// DurationBuckets are the default upper bounds, in seconds, of the buckets of
+// histograms that track durations.
+var DurationBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25,
+	0.5, 1, 2.5, 5, 10, 30}
+
+// Histogram tracks the distribution of observed values in a set of buckets
+// along with their count and sum.  It is safe for concurrent access.  A nil
+// histogram is valid and ignores all observations.
+type Histogram struct {
+	bounds []float64
+
+	mtx    sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a new histogram with buckets that have the provided
+// upper bounds, which must be sorted in increasing order.
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+// Observe adds the provided value to the histogram.
+//
+// This function is safe for concurrent access.
+func (h *Histogram) Observe(value float64) {
+	if h == nil {
+		return
+	}
+	bucket := sort.SearchFloat64s(h.bounds, value)
+	h.mtx.Lock()
+	if bucket < len(h.counts) {
+		h.counts[bucket]++
+	}
+	h.count++
+	h.sum += value
+	h.mtx.Unlock()
+}
+
+// ObserveDuration adds the provided duration to the histogram in seconds.
+//
+// This function is safe for concurrent access.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+// HistogramSnapshot houses the state of a histogram at a point in time.
+type HistogramSnapshot struct {
+	// Bounds are the upper bounds of the buckets.
+	Bounds []float64
+
+	// Cumulative houses the number of observations less than or equal to the
+	// upper bound of each bucket.
+	Cumulative []uint64
+
+	// Count and Sum are the total number of observations and the sum of the
+	// observed values, respectively.
+	Count uint64
+	Sum   float64
+}
+
+// Snapshot returns the current state of the histogram.
+//
+// This function is safe for concurrent access.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	if h == nil {
+		return HistogramSnapshot{}
+	}
+	snap := HistogramSnapshot{
+		Bounds:     h.bounds,
+		Cumulative: make([]uint64, len(h.bounds)),
+	}
+	h.mtx.Lock()
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		snap.Cumulative[i] = cumulative
+	}
+	snap.Count = h.count
+	snap.Sum = h.sum
+	h.mtx.Unlock()
+	return snap
+}
+
+// maxExactFloat is the largest magnitude below which all integers are exactly
+// representable as a float64.
+const maxExactFloat = 1 << 53
+
+// FormatFloat returns the provided value formatted for the Prometheus text
+// exposition format.  Integral values that are exactly representable are
+// formatted without an exponent so that counts and sizes converted to floating
+// point remain readable.
+func FormatFloat(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < maxExactFloat {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Writer writes metrics in the Prometheus text exposition format.  Errors are
+// deferred until Flush is called.
+type Writer struct {
+	bw *bufio.Writer
+}
+
+// NewWriter returns a new writer that writes metrics to the provided writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{bw: bufio.NewWriter(w)}
+}
+
+// Header writes the help and type comments for the metric with the provided
+// name.
+func (w *Writer) Header(name, help, typ string) {
+	fmt.Fprintf(w.bw, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w.bw, "# TYPE %s %s\n", name, typ)
+}
+
+// writeSeries writes the name of the series of the metric with the provided
+// name that is identified by the provided labels, which alternate between
+// label names and values.
+func (w *Writer) writeSeries(name string, labels []string) {
+	w.bw.WriteString(name)
+	if len(labels) == 0 {
+		return
+	}
+	w.bw.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			w.bw.WriteByte(',')
+		}
+		fmt.Fprintf(w.bw, "%s=%q", labels[i], labels[i+1])
+	}
+	w.bw.WriteByte('}')
+}
+
+// Sample writes a sample with the provided value for the series of the metric
+// with the provided name that is identified by the provided labels, which
+// alternate between label names and values.  The header of the metric must
+// have already been written.
+func (w *Writer) Sample(name string, value uint64, labels ...string) {
+	w.writeSeries(name, labels)
+	fmt.Fprintf(w.bw, " %d\n", value)
+}
+
+// FloatSample writes a sample with the provided floating point value for the
+// series of the metric with the provided name that is identified by the
+// provided labels, which alternate between label names and values.  The
+// header of the metric must have already been written.
+func (w *Writer) FloatSample(name string, value float64, labels ...string) {
+	w.writeSeries(name, labels)
+	fmt.Fprintf(w.bw, " %s\n", FormatFloat(value))
+}
+
+// HistogramSample writes the buckets, sum, and count of the provided
+// histogram state for the series of the metric with the provided name that is
+// identified by the provided labels, which alternate between label names and
+// values.  The header of the metric must have already been written.
+func (w *Writer) HistogramSample(name string, snap *HistogramSnapshot, labels ...string) {
+	bucketLabels := make([]string, len(labels), len(labels)+2)
+	copy(bucketLabels, labels)
+	bucketLabels = append(bucketLabels, "le", "")
+	le := len(bucketLabels) - 1
+	for i, bound := range snap.Bounds {
+		bucketLabels[le] = FormatFloat(bound)
+		w.Sample(name+"_bucket", snap.Cumulative[i], bucketLabels...)
+	}
+	bucketLabels[le] = "+Inf"
+	w.Sample(name+"_bucket", snap.Count, bucketLabels...)
+	w.FloatSample(name+"_sum", snap.Sum, labels...)
+	w.Sample(name+"_count", snap.Count, labels...)
+}
+
+// Counter writes the metric with the provided name as a counter with the
+// provided value.
+func (w *Writer) Counter(name, help string, value uint64) {
+	w.Header(name, help, "counter")
+	w.Sample(name, value)
+}
+
+// CounterVec writes the metric with the provided name as a counter with a
+// series for each of the provided values labeled by the provided label name.
+// The series are sorted by their label values.
+func (w *Writer) CounterVec(name, help, label string, values map[string]uint64) {
+	w.Header(name, help, "counter")
+	labels := make([]string, 0, len(values))
+	for value := range values {
+		labels = append(labels, value)
+	}
+	sort.Strings(labels)
+	for _, value := range labels {
+		w.Sample(name, values[value], label, value)
+	}
+}
+
+// Gauge writes the metric with the provided name as a gauge with the provided
+// value.
+func (w *Writer) Gauge(name, help string, value float64) {
+	w.Header(name, help, "gauge")
+	w.FloatSample(name, value)
+}
+
+// Histogram writes the metric with the provided name as a histogram with the
+// provided state.
+func (w *Writer) Histogram(name, help string, snap *HistogramSnapshot) {
+	w.Header(name, help, "histogram")
+	w.HistogramSample(name, snap)
+}
+
+// Flush writes any buffered metrics to the underlying writer and returns the
+// first error that occurred while writing, if any.
+func (w *Writer) Flush() error {
+	return w.bw.Flush()
+}
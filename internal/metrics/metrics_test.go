@@ -0,0 +1,160 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestNilMetrics ensures nil metrics ignore all updates and report zero values.
+func TestNilMetrics(t *testing.T) {
+	var c *Counter
+	c.Inc()
+	c.Add(10)
+	if got := c.Value(); got != 0 {
+		t.Fatalf("mismatched nil counter value -- got %d, want 0", got)
+	}
+
+	var v *CounterVec
+	v.Inc("label")
+	if got := v.Values(); len(got) != 0 {
+		t.Fatalf("mismatched nil counter vector values -- got %v, want none",
+			got)
+	}
+
+	var h *Histogram
+	h.Observe(1)
+	h.ObserveDuration(time.Second)
+	if got := h.Snapshot(); got.Count != 0 {
+		t.Fatalf("mismatched nil histogram count -- got %d, want 0", got.Count)
+	}
+}
+
+// TestCounters ensures counters and counter vectors track the expected
+// values.
+func TestCounters(t *testing.T) {
+	c := NewCounter()
+	c.Inc()
+	c.Add(41)
+	if got := c.Value(); got != 42 {
+		t.Fatalf("mismatched counter value -- got %d, want 42", got)
+	}
+
+	v := NewCounterVec()
+	v.Inc("a")
+	v.Inc("b")
+	v.Inc("a")
+	want := map[string]uint64{"a": 2, "b": 1}
+	if got := v.Values(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched counter vector values -- got %v, want %v", got,
+			want)
+	}
+}
+
+// TestHistogram ensures histograms place observations in the expected buckets
+// and track the expected count and sum.
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 5, 10})
+	for _, value := range []float64{0.5, 1, 3, 7, 20} {
+		h.Observe(value)
+	}
+	h.ObserveDuration(2 * time.Second)
+
+	got := h.Snapshot()
+	want := HistogramSnapshot{
+		Bounds:     []float64{1, 5, 10},
+		Cumulative: []uint64{2, 4, 5},
+		Count:      6,
+		Sum:        33.5,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched snapshot -- got %+v, want %+v", got, want)
+	}
+}
+
+// TestWriter ensures the writer produces the expected output in the
+// Prometheus text exposition format.
+func TestWriter(t *testing.T) {
+	h := NewHistogram([]float64{0.5, 1})
+	h.Observe(0.25)
+	h.Observe(2)
+	snap := h.Snapshot()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Counter("test_total", "A counter.", 7)
+	w.CounterVec("test_vec_total", "A counter vector.", "reason",
+		map[string]uint64{"b": 2, "a": 1})
+	w.Gauge("test_gauge", "A gauge.", 1.5)
+	w.Histogram("test_seconds", "A histogram.", &snap)
+	w.Header("test_labeled", "Labeled samples.", "gauge")
+	w.Sample("test_labeled", 3, "a", "x", "b", "y")
+	w.FloatSample("test_labeled", 0.5, "a", "z", "b", "\"q\"")
+	w.Header("test_labeled_seconds", "A labeled histogram.", "histogram")
+	w.HistogramSample("test_labeled_seconds", &snap, "method", "m")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing: %v", err)
+	}
+
+	want := `# HELP test_total A counter.
+# TYPE test_total counter
+test_total 7
+# HELP test_vec_total A counter vector.
+# TYPE test_vec_total counter
+test_vec_total{reason="a"} 1
+test_vec_total{reason="b"} 2
+# HELP test_gauge A gauge.
+# TYPE test_gauge gauge
+test_gauge 1.5
+# HELP test_seconds A histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.5"} 1
+test_seconds_bucket{le="1"} 1
+test_seconds_bucket{le="+Inf"} 2
+test_seconds_sum 2.25
+test_seconds_count 2
+# HELP test_labeled Labeled samples.
+# TYPE test_labeled gauge
+test_labeled{a="x",b="y"} 3
+test_labeled{a="z",b="\"q\""} 0.5
+# HELP test_labeled_seconds A labeled histogram.
+# TYPE test_labeled_seconds histogram
+test_labeled_seconds_bucket{method="m",le="0.5"} 1
+test_labeled_seconds_bucket{method="m",le="1"} 1
+test_labeled_seconds_bucket{method="m",le="+Inf"} 2
+test_labeled_seconds_sum{method="m"} 2.25
+test_labeled_seconds_count{method="m"} 2
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("mismatched output -- got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestFormatFloat ensures values are formatted as expected.
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0"},
+		{1.5, "1.5"},
+		{0.001, "0.001"},
+		{-3, "-3"},
+		{2500000000, "2500000000"},
+		{1 << 53, "9.007199254740992e+15"},
+		{math.Inf(1), "+Inf"},
+		{math.NaN(), "NaN"},
+	}
+	for _, test := range tests {
+		if got := FormatFloat(test.value); got != test.want {
+			t.Errorf("FormatFloat(%v): got %q, want %q", test.value, got,
+				test.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// metricsPath is the path the metrics are served at.
+	metricsPath = "/metrics"
+
+	// readHeaderTimeout is the maximum amount of time allowed to read the
+	// headers of requests.
+	readHeaderTimeout = 10 * time.Second
+
+	// shutdownTimeout is the maximum amount of time allowed for in-flight
+	// requests to complete when the server shuts down.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Config is a descriptor containing the metrics server configuration.
+type Config struct {
+	// Listeners defines a slice of listeners for which the metrics server
+	// will take ownership of and accept connections.  Since the metrics
+	// server takes ownership of these listeners, they will be closed when the
+	// metrics server is stopped.
+	Listeners []net.Listener
+
+	// Sources defines the sources of the metrics that are served.  The
+	// metrics of each source are written in order.
+	Sources []Source
+
+	// Username and Password are the optional credentials all requests must
+	// provide via HTTP basic authentication.  Authentication is only required
+	// when both are set.
+	Username string
+	Password string
+}
+
+// Server provides an HTTP server that serves the metrics of all of its
+// sources in the Prometheus text exposition format.
+type Server struct {
+	cfg         Config
+	httpServer  http.Server
+	requireAuth bool
+	authsha     [sha256.Size]byte
+}
+
+// authSHA returns the SHA256 hash of the HTTP basic authorization value for
+// the provided credentials.
+func authSHA(user, pass string) [sha256.Size]byte {
+	login := user + ":" + pass
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	return sha256.Sum256([]byte(auth))
+}
+
+// New returns a new instance of the Server struct with the provided config.
+func New(cfg *Config) *Server {
+	s := &Server{cfg: *cfg}
+	if cfg.Username != "" && cfg.Password != "" {
+		s.authsha = authSHA(cfg.Username, cfg.Password)
+		s.requireAuth = true
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, s)
+	s.httpServer = http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return s
+}
+
+// ServeHTTP serves the metrics of all sources in the Prometheus text exposition
+// format.
+//
+// This is part of the http.Handler interface implementation.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method not allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.requireAuth {
+		authsha := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+		if subtle.ConstantTimeCompare(authsha[:], s.authsha[:]) != 1 {
+			log.Warnf("Metrics authentication failure from %s", r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Basic realm="dcrd metrics"`)
+			http.Error(w, "401 Unauthorized.", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, source := range s.cfg.Sources {
+		if err := source.WriteMetrics(w); err != nil {
+			log.Debugf("Failed to write metrics to %s: %v", r.RemoteAddr,
+				err)
+			return
+		}
+	}
+}
+
+// Run starts the metrics server and blocks until the provided context is
+// cancelled.
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, listener := range s.cfg.Listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			log.Infof("Metrics server listening on %s", listener.Addr())
+			err := s.httpServer.Serve(listener)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Metrics server on %s stopped: %v",
+					listener.Addr(), err)
+			}
+			log.Tracef("Metrics listener done for %s", listener.Addr())
+			wg.Done()
+		}(listener)
+	}
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(),
+		shutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("Metrics server shutdown: %v", err)
+	}
+	wg.Wait()
+	log.Infof("Metrics server shutdown complete")
+}
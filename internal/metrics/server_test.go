@@ -0,0 +1,153 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testSource is a metrics source that writes a fixed counter or returns an
+// error.
+type testSource struct {
+	name string
+	err  error
+}
+
+// WriteMetrics writes a counter with the name of the source.
+//
+// This is part of the Source interface implementation.
+func (s *testSource) WriteMetrics(w io.Writer) error {
+	if s.err != nil {
+		return s.err
+	}
+	_, err := fmt.Fprintf(w, "%s 1\n", s.name)
+	return err
+}
+
+// TestServeHTTP ensures the server serves the metrics of all of its sources in
+// order and rejects unsupported methods.
+func TestServeHTTP(t *testing.T) {
+	s := New(&Config{
+		Sources: []Source{
+			&testSource{name: "first"},
+			&testSource{name: "second"},
+			&testSource{err: errors.New("failed")},
+			&testSource{name: "third"},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("mismatched status -- got %d, want %d", rec.Code,
+			http.StatusOK)
+	}
+	const want = "first 1\nsecond 1\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("mismatched body -- got %q, want %q", got, want)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, metricsPath, nil)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("mismatched status -- got %d, want %d", rec.Code,
+			http.StatusMethodNotAllowed)
+	}
+}
+
+// TestServeHTTPAuth ensures the server requires valid credentials when it is
+// configured with them.
+func TestServeHTTPAuth(t *testing.T) {
+	s := New(&Config{
+		Sources:  []Source{&testSource{name: "metric"}},
+		Username: "user",
+		Password: "pass",
+	})
+
+	tests := []struct {
+		name       string
+		user, pass string
+		setAuth    bool
+		wantCode   int
+	}{
+		{name: "no credentials", wantCode: http.StatusUnauthorized},
+		{name: "wrong password", user: "user", pass: "wrong", setAuth: true,
+			wantCode: http.StatusUnauthorized},
+		{name: "wrong user", user: "wrong", pass: "pass", setAuth: true,
+			wantCode: http.StatusUnauthorized},
+		{name: "valid credentials", user: "user", pass: "pass", setAuth: true,
+			wantCode: http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+		if test.setAuth {
+			req.SetBasicAuth(test.user, test.pass)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != test.wantCode {
+			t.Fatalf("%s: mismatched status -- got %d, want %d", test.name,
+				rec.Code, test.wantCode)
+		}
+		if test.wantCode == http.StatusOK && rec.Body.String() != "metric 1\n" {
+			t.Fatalf("%s: mismatched body -- got %q", test.name,
+				rec.Body.String())
+		}
+		if test.wantCode == http.StatusUnauthorized &&
+			rec.Header().Get("WWW-Authenticate") == "" {
+
+			t.Fatalf("%s: missing authentication challenge", test.name)
+		}
+	}
+}
+
+// TestRun ensures the server serves metrics on its listeners until the
+// provided context is cancelled.
+func TestRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	s := New(&Config{
+		Listeners: []net.Listener{listener},
+		Sources:   []Source{&testSource{name: "metric"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	url := "http://" + listener.Addr().String() + metricsPath
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error requesting metrics: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("unexpected error reading metrics: %v", err)
+	}
+	if got, want := string(body), "metric 1\n"; got != want {
+		t.Fatalf("mismatched body -- got %q, want %q", got, want)
+	}
+
+	cancel()
+	<-done
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("metrics server still serving after shutdown")
+	}
+}
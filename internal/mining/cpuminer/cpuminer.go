@@ -272,6 +272,9 @@ func (m *CPUMiner) solveBlock(ctx context.Context, header *wire.BlockHeader, sta
 		return false
 	}
 
+	// Use the proof of work hash algorithm of the network.
+	powHasher := m.cfg.ChainParams.PowHashAlgorithm()
+
 	// Serialize the header once so only the specific bytes that need to be
 	// updated can be done in the main loops below.
 	hdrBytes, err := header.Bytes()
@@ -343,14 +346,14 @@ func (m *CPUMiner) solveBlock(ctx context.Context, header *wire.BlockHeader, sta
 			}
 
 			// Update the nonce in the serialized header bytes directly and
-			// compute the block header hash.
+			// compute the proof of work hash of the block header.
 			const nonceSerOffset = 140
 			littleEndian.PutUint32(hdrBytes[nonceSerOffset:], nonce)
-			hash := chainhash.HashH(hdrBytes)
+			hash := powHasher.PowHash(hdrBytes)
 			hashesCompleted++
 
-			// The block is solved when the new block hash is less than the
-			// target difficulty.  Yay!
+			// The block is solved when the new proof of work hash is less
+			// than the target difficulty.  Yay!
 			if n := primitives.HashToUint256(&hash); n.LtEq(&targetDiff) {
 				// Update the nonce and extra nonce fields in the block template
 				// header to the solution.
@@ -58,6 +58,11 @@ const (
 
 	// ErrSerializeHeader indicates an attempt to serialize a block header failed.
 	ErrSerializeHeader = ErrorKind("ErrSerializeHeader")
+
+	// ErrSignBlock indicates that signing a newly created block template on a
+	// network that requires blocks to be signed failed or resulted in a block
+	// that exceeds the max block size.
+	ErrSignBlock = ErrorKind("ErrSignBlock")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -28,6 +28,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrCalcCommitmentRoot, "ErrCalcCommitmentRoot"},
 		{ErrGetTicketInfo, "ErrGetTicketInfo"},
 		{ErrSerializeHeader, "ErrSerializeHeader"},
+		{ErrSignBlock, "ErrSignBlock"},
 	}
 
 	for i, test := range tests {
@@ -186,6 +186,20 @@ type Config struct {
 	// transaction output view.
 	NewUtxoViewpoint func() *blockchain.UtxoViewpoint
 
+	// SignBlock defines the function to use to sign the provided block on
+	// networks that require blocks to be signed.  The signature must be added
+	// to the block by the function and it is called prior to calculating the
+	// merkle roots and size of the block.
+	//
+	// It may be nil for networks that do not require blocks to be signed.
+	SignBlock func(block *wire.MsgBlock) error
+
+	// BlockSigOverhead is the number of bytes reserved for the signature that
+	// is added to blocks by SignBlock.  It is only used when SignBlock is set
+	// and must be large enough for any signature it adds since templates that
+	// exceed the max block size once they are signed are rejected.
+	BlockSigOverhead uint32
+
 	// TipGeneration defines the function to use to get the entire generation of
 	// blocks stemming from the parent of the current tip.
 	TipGeneration func() ([]chainhash.Hash, error)
@@ -884,6 +898,14 @@ func (g *BlkTmplGenerator) handleTooFewVoters(nextHeight int64,
 			block.Header.Bits = requiredDifficulty
 		}
 
+		// Sign the block on networks that require it.
+		if g.cfg.SignBlock != nil {
+			if err := g.cfg.SignBlock(&block); err != nil {
+				str := fmt.Sprintf("failed to sign block: %v", err)
+				return nil, makeError(ErrSignBlock, str)
+			}
+		}
+
 		// Recalculate the size.
 		block.Header.Size = uint32(block.SerializeSize())
 
@@ -1092,13 +1114,14 @@ func NewBlkTmplGenerator(cfg *Config) *BlkTmplGenerator {
 }
 
 // calcFeePerKb returns an adjusted fee per kilobyte taking the provided
-// transaction and its ancestors into account.
-func calcFeePerKb(txDesc *TxDesc, ancestorStats *TxAncestorStats) float64 {
+// transaction, the fee it pays after any adjustments, and its ancestors into
+// account.
+func calcFeePerKb(txDesc *TxDesc, fee int64, ancestorStats *TxAncestorStats) float64 {
 	txSize := txDesc.Tx.MsgTx().SerializeSize()
 	if ancestorStats.Fees < 0 || ancestorStats.SizeBytes < 0 {
-		return (float64(txDesc.Fee) * float64(kilobyte)) / float64(txSize)
+		return (float64(fee) * float64(kilobyte)) / float64(txSize)
 	}
-	return (float64(txDesc.Fee+ancestorStats.Fees) * float64(kilobyte)) /
+	return (float64(fee+ancestorStats.Fees) * float64(kilobyte)) /
 		float64(int64(txSize)+ancestorStats.SizeBytes)
 }
 
@@ -1396,9 +1419,14 @@ mempoolLoop:
 		// during calcMinRelayFee which rounds up to the nearest full
 		// kilobyte boundary.  This is beneficial since it provides an
 		// incentive to create smaller transactions.
+		//
+		// Also note that the fee used for prioritization includes any fee
+		// delta applied to the transaction, while the fee recorded in the
+		// template is the fee the transaction actually pays.
 		ancestorStats, hasStats := miningView.AncestorStats(tx.Hash())
-		prioItem.feePerKB = calcFeePerKb(txDesc, ancestorStats)
-		prioItem.fee = txDesc.Fee + ancestorStats.Fees
+		modifiedFee := miningView.modifiedFee(txDesc)
+		prioItem.feePerKB = calcFeePerKb(txDesc, modifiedFee, ancestorStats)
+		prioItem.fee = modifiedFee + ancestorStats.Fees
 		prioItemMap[*tx.Hash()] = prioItem
 		hasParents := miningView.hasParents(tx.Hash())
 
@@ -1426,6 +1454,9 @@ mempoolLoop:
 	// possible transaction count size, plus the size of the coinbase
 	// transaction.
 	blockSize := uint32(blockHeaderOverhead)
+	if g.cfg.SignBlock != nil {
+		blockSize += g.cfg.BlockSigOverhead
+	}
 
 	// Guesstimate for sigops based on valid txs in loop below. This number
 	// tends to overestimate sigops because of the way the loop below is
@@ -1645,7 +1676,8 @@ nextPriorityQueueItem:
 		ancestors := miningView.ancestors(tx.Hash())
 		ancestorStats, _ := miningView.AncestorStats(tx.Hash())
 		oldFee := prioItem.feePerKB
-		prioItem.feePerKB = calcFeePerKb(prioItem.txDesc, ancestorStats)
+		prioItem.feePerKB = calcFeePerKb(prioItem.txDesc,
+			miningView.modifiedFee(prioItem.txDesc), ancestorStats)
 
 		feeDecreased := oldFee > prioItem.feePerKB
 		if feeDecreased && ancestorStats.NumAncestors == 0 {
@@ -2284,6 +2316,25 @@ nextPriorityQueueItem:
 		}
 	}
 
+	// Sign the block on networks that require it.
+	if g.cfg.SignBlock != nil {
+		if err := g.cfg.SignBlock(&msgBlock); err != nil {
+			str := fmt.Sprintf("failed to sign block when making new block "+
+				"template: %v", err)
+			return nil, makeError(ErrSignBlock, str)
+		}
+
+		// Ensure the signature did not exceed the space reserved for it.
+		signedSize := uint32(msgBlock.SerializeSize())
+		if signedSize > g.cfg.Policy.BlockMaxSize {
+			str := fmt.Sprintf("signed block template size %d exceeds the "+
+				"max block size %d -- the signature is larger than the %d "+
+				"bytes reserved for it", signedSize, g.cfg.Policy.BlockMaxSize,
+				g.cfg.BlockSigOverhead)
+			return nil, makeError(ErrSignBlock, str)
+		}
+	}
+
 	// Calculate the merkle root depending on the result of the header
 	// commitments agenda vote.
 	hdrCmtActive, err := g.cfg.IsHeaderCommitmentsAgendaActive(&prevHash)
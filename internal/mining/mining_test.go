@@ -59,6 +59,34 @@ func TestNewBlockTemplateBasicErrorScenarios(t *testing.T) {
 			err, errTreasuryAgenda)
 	}
 	harness.chain.isTreasuryAgendaActiveErr = nil
+
+	// Test error signing the block.
+	var errSignBlock = errors.New("error signing block")
+	harness.generator.cfg.SignBlock = func(*wire.MsgBlock) error {
+		return errSignBlock
+	}
+	_, err = harness.generator.NewBlockTemplate(address)
+	if !errors.Is(err, ErrSignBlock) {
+		t.Fatalf("unexpected error signing block -- got %v, want %v", err,
+			ErrSignBlock)
+	}
+
+	// Test signing the block resulting in a block that exceeds the max block
+	// size due to a signature that is larger than the space reserved for it.
+	harness.generator.cfg.BlockSigOverhead = 100
+	harness.generator.cfg.SignBlock = func(block *wire.MsgBlock) error {
+		maxSize := harness.generator.cfg.Policy.BlockMaxSize
+		sig := make([]byte, maxSize)
+		block.Transactions[0].AddTxOut(wire.NewTxOut(0, sig))
+		return nil
+	}
+	_, err = harness.generator.NewBlockTemplate(address)
+	if !errors.Is(err, ErrSignBlock) {
+		t.Fatalf("unexpected error for oversized signed block -- got %v, "+
+			"want %v", err, ErrSignBlock)
+	}
+	harness.generator.cfg.SignBlock = nil
+	harness.generator.cfg.BlockSigOverhead = 0
 }
 
 // TestNewBlockTemplate tests the generation of a new block template containing
@@ -27,6 +27,7 @@ type TxMiningView struct {
 	txDescs            []*TxDesc
 	trackAncestorStats bool
 	ancestorStats      map[chainhash.Hash]*TxAncestorStats
+	feeDeltas          map[chainhash.Hash]int64
 }
 
 // NewTxMiningView creates a new mining view instance.  The forEachRedeemer
@@ -41,13 +42,22 @@ func NewTxMiningView(enableAncestorTracking bool,
 		txDescs:            nil,
 		trackAncestorStats: enableAncestorTracking,
 		ancestorStats:      make(map[chainhash.Hash]*TxAncestorStats),
+		feeDeltas:          make(map[chainhash.Hash]int64),
 	}
 }
 
+// modifiedFee returns the fee of the provided transaction adjusted by any fee
+// delta that applies to it in the view.
+//
+// This function is NOT safe for concurrent access.
+func (mv *TxMiningView) modifiedFee(txDesc *TxDesc) int64 {
+	return txDesc.Fee + mv.feeDeltas[*txDesc.Tx.Hash()]
+}
+
 // addAncestorTo modifies the TxAncestorStats instance to include the provided
 // TxDesc's statistics.
-func addAncestorTo(stats *TxAncestorStats, txDesc *TxDesc) {
-	stats.Fees += txDesc.Fee
+func (mv *TxMiningView) addAncestorTo(stats *TxAncestorStats, txDesc *TxDesc) {
+	stats.Fees += mv.modifiedFee(txDesc)
 	stats.SizeBytes += txDesc.TxSize
 	stats.TotalSigOps += txDesc.TotalSigOps
 	stats.NumAncestors++
@@ -61,8 +71,8 @@ func addDescendantTo(stats *TxAncestorStats) {
 
 // removeAncestorFrom modifies the TxAncestorStats instance to stop storing
 // the statistics of the provided TxDesc.
-func removeAncestorFrom(stats *TxAncestorStats, txDesc *TxDesc) {
-	stats.Fees -= txDesc.Fee
+func (mv *TxMiningView) removeAncestorFrom(stats *TxAncestorStats, txDesc *TxDesc) {
+	stats.Fees -= mv.modifiedFee(txDesc)
 	stats.SizeBytes -= txDesc.TxSize
 	stats.TotalSigOps -= txDesc.TotalSigOps
 	stats.NumAncestors--
@@ -100,7 +110,7 @@ func (mv *TxMiningView) ancestors(txHash *chainhash.Hash) []*TxDesc {
 			baseTxStats = &TxAncestorStats{}
 		}
 
-		addAncestorTo(baseTxStats, txDesc)
+		mv.addAncestorTo(baseTxStats, txDesc)
 		ancestors = append(ancestors, txDesc)
 	})
 
@@ -161,6 +171,7 @@ func (mv *TxMiningView) Clone(txDescs []*TxDesc, fetchTx TxDescFind) *TxMiningVi
 		trackAncestorStats: mv.trackAncestorStats,
 		ancestorStats: make(map[chainhash.Hash]*TxAncestorStats,
 			len(mv.ancestorStats)),
+		feeDeltas: make(map[chainhash.Hash]int64, len(mv.feeDeltas)),
 	}
 
 	for key, value := range mv.ancestorStats {
@@ -172,6 +183,9 @@ func (mv *TxMiningView) Clone(txDescs []*TxDesc, fetchTx TxDescFind) *TxMiningVi
 			NumDescendants: value.NumDescendants,
 		}
 	}
+	for key, value := range mv.feeDeltas {
+		view.feeDeltas[key] = value
+	}
 
 	return view
 }
@@ -301,7 +315,7 @@ func (mv *TxMiningView) maybeUpdateAncestorStats(txDesc *TxDesc,
 	// during a prior walk that limits what enters the seenAncestors map.
 	baseTxnAncestorStats := &TxAncestorStats{}
 	for ancestorTxHash, ancestorTxDesc := range seenAncestors {
-		addAncestorTo(baseTxnAncestorStats, ancestorTxDesc)
+		mv.addAncestorTo(baseTxnAncestorStats, ancestorTxDesc)
 		addDescendantTo(mv.ancestorStats[ancestorTxHash])
 	}
 	mv.ancestorStats[*baseTxHash] = baseTxnAncestorStats
@@ -358,7 +372,7 @@ func (mv *TxMiningView) updateStatsDescendantsAdded(baseTxDesc *TxDesc) {
 			// Update the stats for this descendant since it is allowed to have
 			// ancestor statistics tracked. Also add the descendant to the
 			// statistics of the base transaction.
-			addAncestorTo(descendantStats, baseTxDesc)
+			mv.addAncestorTo(descendantStats, baseTxDesc)
 			addDescendantTo(baseTxStats)
 			return true
 		})
@@ -410,7 +424,7 @@ func (mv *TxMiningView) updateStatsDescendantsRemoved(baseTxDesc *TxDesc) {
 			}
 
 			if hasStats {
-				removeAncestorFrom(descendantStats, baseTxDesc)
+				mv.removeAncestorFrom(descendantStats, baseTxDesc)
 				return true
 			}
 
@@ -467,6 +481,55 @@ func (mv *TxMiningView) RemoveTransaction(txHash *chainhash.Hash, updateDescenda
 	delete(mv.ancestorStats, *txHash)
 }
 
+// SetFeeDelta sets the amount to adjust the fee of the transaction with the
+// provided hash by when prioritizing it for inclusion in block templates.  The
+// fee the transaction actually pays is not modified.  A delta of zero removes
+// any existing adjustment.
+//
+// The delta may be set regardless of whether or not the transaction is in the
+// view and it applies to the transaction if it is added to the view later.
+// The ancestor statistics for all of the transaction's descendants in the view
+// are updated to account for the new delta.
+//
+// This function is NOT safe for concurrent access.
+func (mv *TxMiningView) SetFeeDelta(txHash *chainhash.Hash, delta int64) {
+	oldDelta := mv.feeDeltas[*txHash]
+	if delta == 0 {
+		delete(mv.feeDeltas, *txHash)
+	} else {
+		mv.feeDeltas[*txHash] = delta
+	}
+
+	// Update the ancestor stats of all descendants that include the
+	// transaction in their stats.  Descendants of a transaction without
+	// ancestor stats tracked do not have them tracked either.
+	diff := delta - oldDelta
+	if diff == 0 || !mv.trackAncestorStats {
+		return
+	}
+	if _, hasStats := mv.ancestorStats[*txHash]; !hasStats {
+		return
+	}
+	seen := make(map[chainhash.Hash]struct{}, ancestorTrackingLimit)
+	mv.txGraph.forEachDescendantPreOrder(txHash, seen,
+		func(descendant *TxDesc) bool {
+			stats, hasStats := mv.ancestorStats[*descendant.Tx.Hash()]
+			if !hasStats {
+				return false
+			}
+			stats.Fees += diff
+			return true
+		})
+}
+
+// FeeDelta returns the amount the fee of the transaction with the provided hash
+// is adjusted by when prioritizing it for inclusion in block templates.
+//
+// This function is NOT safe for concurrent access.
+func (mv *TxMiningView) FeeDelta(txHash *chainhash.Hash) int64 {
+	return mv.feeDeltas[*txHash]
+}
+
 // reject stops tracking the transaction in the view, if it exists, and all
 // of its descendants.  Also flags the provided transaction as rejected and
 // tracks the hash as rejected in this instance of the mining view. Rejected
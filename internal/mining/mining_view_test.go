@@ -519,3 +519,89 @@ func TestAncestorTrackingLimits(t *testing.T) {
 		}
 	}
 }
+
+// TestMiningViewFeeDeltas ensures that fee deltas set on the mining view are
+// reflected in the ancestor stats of descendants, are carried over to clones,
+// and apply to transactions that are added to the view after the delta is set.
+func TestMiningViewFeeDeltas(t *testing.T) {
+	harness, spendableOuts, err := newMiningHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create mining harness: %v", err)
+	}
+
+	// Create a chain of transactions.
+	var allTxns []*dcrutil.Tx
+	prevSpendableOut := spendableOuts[0]
+	for i := 0; i < 3; i++ {
+		tx, _ := harness.CreateSignedTx([]spendableOutput{
+			prevSpendableOut,
+		}, 1)
+
+		allTxns = append(allTxns, tx)
+		prevSpendableOut = txOutToSpendableOut(tx, 0, wire.TxTreeRegular)
+	}
+	txA, txB, txC := allTxns[0], allTxns[1], allTxns[2]
+
+	// Set a fee delta for the last transaction prior to adding the
+	// transactions to the tx source.
+	const deltaA, deltaC = 100000, -5000
+	miningView := harness.txSource.miningView
+	miningView.SetFeeDelta(txC.Hash(), deltaC)
+	for _, tx := range allTxns {
+		_, err = harness.AddTransactionToTxSource(tx)
+		if err != nil {
+			t.Fatalf("unable to add transaction to the tx source: %v", err)
+		}
+	}
+
+	ancestorFees := func(view *TxMiningView, tx *dcrutil.Tx) int64 {
+		t.Helper()
+		stats, hasStats := view.AncestorStats(tx.Hash())
+		if !hasStats {
+			t.Fatalf("expected transaction %v to have ancestor tracking "+
+				"enabled", tx.Hash())
+		}
+		return stats.Fees
+	}
+	feeB := ancestorFees(miningView, txC) - ancestorFees(miningView, txB)
+	feeA := ancestorFees(miningView, txB)
+
+	// Ensure the delta for the first transaction is reflected in the stats of
+	// all of its descendants.
+	miningView.SetFeeDelta(txA.Hash(), deltaA)
+	if got, want := ancestorFees(miningView, txB), feeA+deltaA; got != want {
+		t.Fatalf("unexpected ancestor fees for txB -- got %d, want %d", got,
+			want)
+	}
+	if got, want := ancestorFees(miningView, txC), feeA+deltaA+feeB; got != want {
+		t.Fatalf("unexpected ancestor fees for txC -- got %d, want %d", got,
+			want)
+	}
+
+	// Ensure clones of the view retain the deltas and that the modified fee
+	// of transactions includes them.
+	view := harness.txSource.MiningView()
+	if got := view.FeeDelta(txA.Hash()); got != deltaA {
+		t.Fatalf("unexpected fee delta for txA -- got %d, want %d", got,
+			deltaA)
+	}
+	txDescC := harness.txSource.pool[*txC.Hash()]
+	if got, want := view.modifiedFee(txDescC), txDescC.Fee+deltaC; got != want {
+		t.Fatalf("unexpected modified fee for txC -- got %d, want %d", got,
+			want)
+	}
+
+	// Ensure removing the delta restores the original stats.
+	miningView.SetFeeDelta(txA.Hash(), 0)
+	if got := ancestorFees(miningView, txB); got != feeA {
+		t.Fatalf("unexpected ancestor fees for txB -- got %d, want %d", got,
+			feeA)
+	}
+	if got := ancestorFees(miningView, txC); got != feeA+feeB {
+		t.Fatalf("unexpected ancestor fees for txC -- got %d, want %d", got,
+			feeA+feeB)
+	}
+	if got := miningView.FeeDelta(txA.Hash()); got != 0 {
+		t.Fatalf("unexpected fee delta for txA -- got %d, want 0", got)
+	}
+}
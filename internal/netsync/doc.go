@@ -7,8 +7,15 @@ Package netsync implements a concurrency safe block syncing protocol.
 
 The provided implementation of SyncManager communicates with connected peers to
 perform an initial block download, keep the chain in sync, and announce new
-blocks connected to the chain.  Currently the sync manager selects a single sync
-peer that it downloads all blocks from until it is up to date with the longest
-chain the sync peer is aware of.
+blocks connected to the chain.  The sync manager selects a single sync peer that
+it downloads the block headers from until it is up to date with the longest
+chain the sync peer is aware of.  The block bodies are then downloaded in
+parallel by requesting disjoint ranges of blocks from all peers that have them.
+Peers that stall the download are disconnected and the blocks they failed to
+deliver are requested from the other peers.
+
+The progress of the sync process, such as the current stage, the number of
+blocks downloaded and validated, the block validation rate, and the estimated
+time remaining, is available via SyncStatus.
 */
 package netsync
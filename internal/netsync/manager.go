@@ -21,8 +21,11 @@ import (
 	"github.com/decred/dcrd/database/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/cmpctblock"
+	"github.com/decred/dcrd/internal/logging"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/progresslog"
+	"github.com/decred/dcrd/internal/tracing"
 	"github.com/decred/dcrd/math/uint256"
 	peerpkg "github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/wire"
@@ -87,6 +90,16 @@ const (
 	// during the header sync process before stalling the sync and disconnecting
 	// the peer.
 	headerSyncStallTimeoutSecs = (3 + wire.MaxBlockHeadersPerMsg/1000) * 2
+
+	// blockStallTimeout is the amount of time to wait for a peer to deliver
+	// any of the blocks requested from it during the initial chain sync
+	// before it is considered to be stalling the download and disconnected so
+	// the blocks can be requested from other peers.
+	blockStallTimeout = 30 * time.Second
+
+	// blockStallCheckInterval is the interval at which peers are checked for
+	// stalled block downloads.
+	blockStallCheckInterval = 5 * time.Second
 )
 
 // zeroHash is the zero value hash (all zeros).  It is defined as a convenience.
@@ -119,6 +132,22 @@ type headersMsg struct {
 	peer    *peerpkg.Peer
 }
 
+// cmpctBlockMsg packages a Decred cmpctblock message and the peer it came from
+// together so the event handler has access to that information.
+type cmpctBlockMsg struct {
+	cmpctBlock *wire.MsgCmpctBlock
+	peer       *peerpkg.Peer
+	reply      chan struct{}
+}
+
+// blockTxnMsg packages a Decred blocktxn message and the peer it came from
+// together so the event handler has access to that information.
+type blockTxnMsg struct {
+	blockTxn *wire.MsgBlockTxn
+	peer     *peerpkg.Peer
+	reply    chan struct{}
+}
+
 // notFoundMsg packages a Decred notfound message and the peer it came from
 // together so the event handler has access to that information.
 type notFoundMsg struct {
@@ -185,9 +214,12 @@ type processBlockMsg struct {
 type syncMgrPeer struct {
 	*peerpkg.Peer
 
-	syncCandidate   bool
-	requestedTxns   map[chainhash.Hash]struct{}
-	requestedBlocks map[chainhash.Hash]struct{}
+	syncCandidate bool
+	requestedTxns map[chainhash.Hash]struct{}
+
+	// requestedBlocks tracks the blocks requested from the peer along with
+	// the time they were requested.
+	requestedBlocks map[chainhash.Hash]time.Time
 
 	// initialStateRequested tracks whether or not the initial state data has
 	// been requested from the peer.
@@ -199,6 +231,17 @@ type syncMgrPeer struct {
 	// longer useful or are otherwise being malicious.
 	numConsecutiveOrphanHeaders int32
 
+	// partialBlock houses the block announced by the peer via a compact
+	// block that is awaiting the missing transactions requested from the
+	// peer, if any.
+	partialBlock *cmpctblock.PartialBlock
+
+	// lastBlockProgress is the last time the peer either delivered a
+	// requested block or was sent a request for blocks while it did not have
+	// any others in flight.  It is used to detect peers that stall the block
+	// download.
+	lastBlockProgress time.Time
+
 	lastAnnouncedBlock *chainhash.Hash
 }
 
@@ -323,6 +366,18 @@ type SyncManager struct {
 	nextBlocksHeader chainhash.Hash
 	nextBlocksBuf    [512]chainhash.Hash
 	nextNeededBlocks []chainhash.Hash
+
+	// The following fields are used to track the progress of the chain sync
+	// process for the purposes of reporting the sync status.
+	//
+	// blocksDownloaded is the total number of requested blocks that have been
+	// received from peers.
+	//
+	// blockRateSamples houses periodic samples of the best block height over
+	// the most recent rate window and is used to calculate the rate blocks are
+	// validated.
+	blocksDownloaded uint64
+	blockRateSamples []blockRateSample
 }
 
 // lookupPeer returns the sync manager peer that maintains additional state for
@@ -383,6 +438,10 @@ func (m *SyncManager) maybeUpdateNextNeededBlocks() {
 
 // fetchNextBlocks creates and sends a request to the provided peer for the next
 // blocks to be downloaded based on the current headers.
+//
+// Since the needed blocks are removed from the list once they are requested,
+// calling this for multiple peers results in each of them being requested a
+// disjoint range of blocks.
 func (m *SyncManager) fetchNextBlocks(peer *syncMgrPeer) {
 	// Nothing to do if the target maximum number of blocks to request from the
 	// peer at the same time are already in flight.
@@ -404,12 +463,26 @@ func (m *SyncManager) fetchNextBlocks(peer *syncMgrPeer) {
 	if numNeeded > maxNeeded {
 		numNeeded = maxNeeded
 	}
+	chain := m.cfg.Chain
+	// Note that blocks that are skipped because they are already in flight
+	// do not count towards the number of blocks requested from the peer.
 	gdmsg := wire.NewMsgGetDataSizeHint(uint(numNeeded))
-	for i := 0; i < numNeeded && len(gdmsg.InvList) < wire.MaxInvPerMsg; i++ {
+	for len(gdmsg.InvList) < numNeeded && len(m.nextNeededBlocks) > 0 &&
+		len(gdmsg.InvList) < wire.MaxInvPerMsg {
+
+		// Stop once the next needed block is beyond the best block the peer
+		// is known to have since it would not be able to provide it.  The
+		// needed blocks are in height order, so the same applies to all
+		// remaining blocks as well.  Other peers will request them instead.
+		hash := &m.nextNeededBlocks[0]
+		header, err := chain.HeaderByHash(hash)
+		if err == nil && int64(header.Height) > peer.LastBlock() {
+			break
+		}
+
 		// The block is either going to be skipped because it has already been
 		// requested or it will be requested, but in either case, the block is
 		// no longer needed for future iterations.
-		hash := &m.nextNeededBlocks[0]
 		m.nextNeededBlocks = m.nextNeededBlocks[1:]
 
 		// Skip blocks that have already been requested.  The needed blocks
@@ -421,14 +494,84 @@ func (m *SyncManager) fetchNextBlocks(peer *syncMgrPeer) {
 
 		iv := wire.NewInvVect(wire.InvTypeBlock, hash)
 		m.requestedBlocks[*hash] = struct{}{}
-		peer.requestedBlocks[*hash] = struct{}{}
+		peer.requestedBlocks[*hash] = time.Now()
 		gdmsg.AddInvVect(iv)
 	}
 	if len(gdmsg.InvList) > 0 {
+		if numInFlight == 0 {
+			peer.lastBlockProgress = time.Now()
+		}
 		peer.QueueMessage(gdmsg, nil)
 	}
 }
 
+// isBlockDownloadPeer returns whether or not blocks may be downloaded from the
+// provided peer during the chain sync process.
+func (m *SyncManager) isBlockDownloadPeer(peer *syncMgrPeer) bool {
+	return (peer.syncCandidate || peer == m.syncPeer) && peer.Connected()
+}
+
+// fetchNextBlocksFromPeers requests the next blocks to be downloaded from all
+// peers that are eligible to provide them once the initial headers sync is
+// done.  Each peer is requested a disjoint range of blocks in order to download
+// them in parallel.
+//
+// This function is NOT safe for concurrent access.  It must be called from the
+// event handler goroutine.
+func (m *SyncManager) fetchNextBlocksFromPeers() {
+	if !m.hdrSyncState.headersSynced {
+		return
+	}
+
+	// Request blocks from the sync peer first since it is the most updated
+	// peer.
+	if m.syncPeer != nil && m.syncPeer.Connected() {
+		m.fetchNextBlocks(m.syncPeer)
+	}
+	for _, peer := range m.peers {
+		if peer == m.syncPeer || !m.isBlockDownloadPeer(peer) {
+			continue
+		}
+		m.fetchNextBlocks(peer)
+	}
+}
+
+// requeueNeededBlocks forces the list of the next blocks to download to be
+// updated the next time blocks are requested.  It is used to ensure blocks
+// that were requested, but will not be delivered, are requested again.
+func (m *SyncManager) requeueNeededBlocks() {
+	m.nextBlocksHeader = zeroHash
+	m.nextNeededBlocks = nil
+}
+
+// disconnectBlockStallers disconnects all peers that have not delivered any
+// of the blocks requested from them within the block stall timeout during the
+// initial chain sync.  The blocks are requested from other peers once the
+// stalling peers are removed.
+//
+// This function is NOT safe for concurrent access.  It must be called from the
+// event handler goroutine.
+func (m *SyncManager) disconnectBlockStallers(now time.Time) {
+	// Blocks are only downloaded in parallel during the initial chain sync.
+	if m.cfg.Chain.IsCurrent() {
+		return
+	}
+
+	for _, peer := range m.peers {
+		if len(peer.requestedBlocks) == 0 || peer.lastBlockProgress.IsZero() {
+			continue
+		}
+		if now.Sub(peer.lastBlockProgress) < blockStallTimeout {
+			continue
+		}
+
+		log.Debugf("Block download stalled from peer %s (%d blocks in "+
+			"flight) -- disconnecting", peer, len(peer.requestedBlocks))
+		peer.syncCandidate = false
+		peer.Disconnect()
+	}
+}
+
 // startSync will choose the best peer among the available candidate peers to
 // download/sync the blockchain from.  When syncing is already running, it
 // simply returns.  It also examines the candidates for any which are no longer
@@ -483,10 +626,15 @@ func (m *SyncManager) startSync() {
 
 	// Start syncing from the best peer.
 
-	// Clear the requestedBlocks if the sync peer changes, otherwise
-	// we may ignore blocks we need that the last sync peer failed
-	// to send.
+	// Reset the requestedBlocks to the blocks that are still in flight from
+	// the remaining peers if the sync peer changes, otherwise we may ignore
+	// blocks we need that the last sync peer failed to send.
 	m.requestedBlocks = make(map[chainhash.Hash]struct{})
+	for _, peer := range m.peers {
+		for hash := range peer.requestedBlocks {
+			m.requestedBlocks[hash] = struct{}{}
+		}
+	}
 
 	syncHeight := bestPeer.LastBlock()
 
@@ -540,9 +688,7 @@ func (m *SyncManager) startSync() {
 	// This is done in addition to the header request above to avoid waiting
 	// for the round trip when there are still blocks that are needed
 	// regardless of the headers response.
-	if headersSynced {
-		m.fetchNextBlocks(m.syncPeer)
-	}
+	m.fetchNextBlocksFromPeers()
 }
 
 // maybeRequestInitialState potentially requests initial state information from
@@ -590,13 +736,20 @@ func (m *SyncManager) onInitialChainSyncDone() {
 
 	// Request initial state from all peers that are marked as needing it now
 	// that the initial chain sync is done when enabled.
-	if !m.cfg.NoMiningStateSync {
+	if m.miningStateSyncEnabled() {
 		for _, peer := range m.peers {
 			maybeRequestInitialState(peer)
 		}
 	}
 }
 
+// miningStateSyncEnabled returns whether or not the initial mining state is to
+// be synchronized with peers.  It is disabled in blocks-only mode since it
+// involves requesting transactions.
+func (m *SyncManager) miningStateSyncEnabled() bool {
+	return !m.cfg.NoMiningStateSync && !m.cfg.BlocksOnly
+}
+
 // isSyncCandidate returns whether or not the peer is a candidate to consider
 // syncing from.
 func (m *SyncManager) isSyncCandidate(peer *peerpkg.Peer) bool {
@@ -613,7 +766,8 @@ func (m *SyncManager) handleNewPeerMsg(ctx context.Context, peer *peerpkg.Peer)
 	default:
 	}
 
-	log.Infof("New valid peer %s (%s)", peer, peer.UserAgent())
+	logging.With(log, "peer", peer.String(), "useragent", peer.UserAgent()).
+		Infof("New valid peer %s (%s)", peer, peer.UserAgent())
 
 	// Initialize the peer state
 	isSyncCandidate := m.isSyncCandidate(peer)
@@ -621,18 +775,22 @@ func (m *SyncManager) handleNewPeerMsg(ctx context.Context, peer *peerpkg.Peer)
 		Peer:            peer,
 		syncCandidate:   isSyncCandidate,
 		requestedTxns:   make(map[chainhash.Hash]struct{}),
-		requestedBlocks: make(map[chainhash.Hash]struct{}),
+		requestedBlocks: make(map[chainhash.Hash]time.Time),
 	}
 
-	// Start syncing by choosing the best candidate if needed.
+	// Start syncing by choosing the best candidate if needed.  Otherwise,
+	// start downloading blocks from the peer in parallel with the others
+	// when it is a candidate.
 	if isSyncCandidate && m.syncPeer == nil {
 		m.startSync()
+	} else if isSyncCandidate && m.hdrSyncState.headersSynced {
+		m.fetchNextBlocks(m.peers[peer])
 	}
 
 	// Request the initial state from this peer now when enabled and the manager
 	// believes the chain is fully synced.  Otherwise, it will be requested when
 	// the initial chain sync process is complete.
-	if !m.cfg.NoMiningStateSync && m.IsCurrent() {
+	if m.miningStateSyncEnabled() && m.IsCurrent() {
 		maybeRequestInitialState(m.peers[peer])
 	}
 }
@@ -658,17 +816,23 @@ func (m *SyncManager) handleDonePeerMsg(p *peerpkg.Peer) {
 
 	// Remove requested blocks from the global map so that they will be
 	// fetched from elsewhere.
-	// TODO(oga) we could possibly here check which peers have these blocks
-	// and request them now to speed things up a little.
 	for blockHash := range peer.requestedBlocks {
 		delete(m.requestedBlocks, blockHash)
 	}
 
 	// Attempt to find a new peer to sync from and reset the final requested
 	// block when the quitting peer is the sync peer.
+	//
+	// Otherwise, immediately request any blocks that were in flight from the
+	// quitting peer from the remaining peers.
+	if len(peer.requestedBlocks) > 0 {
+		m.requeueNeededBlocks()
+	}
 	if m.syncPeer == peer {
 		m.syncPeer = nil
 		m.startSync()
+	} else if len(peer.requestedBlocks) > 0 {
+		m.fetchNextBlocksFromPeers()
 	}
 }
 
@@ -692,11 +856,18 @@ func (m *SyncManager) handleTxMsg(tmsg *txMsg) {
 	// Ignore transactions that have already been rejected.  The transaction was
 	// unsolicited if it was already previously rejected.
 	if m.rejectedTxns.Contains(txHash[:]) {
-		log.Debugf("Ignoring unsolicited previously rejected transaction %v "+
+		txLog := logging.With(log, "peer", peer.String(), "tx", txHash.String())
+		txLog.Debugf("Ignoring unsolicited previously rejected transaction %v "+
 			"from %s", txHash, peer)
 		return
 	}
 
+	// Trace the transaction when requested.  The span is nil, and therefore
+	// ignored, when the transaction is not traced.
+	span := m.cfg.Tracer.StartRoot(txHash, "netsync.transaction", time.Now())
+	span.SetAttribute("peer", peer.String())
+	defer span.End()
+
 	// Process the transaction to include validation, insertion in the
 	// memory pool, orphan handling, etc.
 	allowOrphans := m.cfg.MaxOrphanTxs > 0
@@ -714,16 +885,18 @@ func (m *SyncManager) handleTxMsg(tmsg *txMsg) {
 		// Do not request this transaction again until a new block has been
 		// processed.
 		m.rejectedTxns.Add(txHash[:])
+		span.SetError(err)
 
 		// When the error is a rule error, it means the transaction was
 		// simply rejected as opposed to something actually going wrong,
 		// so log it as such.  Otherwise, something really did go wrong,
 		// so log it as an actual error.
+		txLog := logging.With(log, "peer", peer.String(), "tx", txHash.String())
 		var rErr mempool.RuleError
 		if errors.As(err, &rErr) {
-			log.Debugf("Rejected transaction %v from %s: %v", txHash, peer, err)
+			txLog.Debugf("Rejected transaction %v from %s: %v", txHash, peer, err)
 		} else {
-			log.Errorf("Failed to process transaction %v: %v", txHash, err)
+			txLog.Errorf("Failed to process transaction %v: %v", txHash, err)
 		}
 		return
 	}
@@ -792,13 +965,25 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 
 	// The remote peer is misbehaving when the block was not requested.
 	blockHash := bmsg.block.Hash()
-	if _, exists := peer.requestedBlocks[*blockHash]; !exists {
-		log.Warnf("Got unrequested block %v from %s -- disconnecting",
+	requestedAt, exists := peer.requestedBlocks[*blockHash]
+	if !exists {
+		blockLog := logging.With(log, "peer", peer.String(), "block",
+			blockHash.String())
+		blockLog.Warnf("Got unrequested block %v from %s -- disconnecting",
 			blockHash, peer)
 		peer.Disconnect()
 		return
 	}
 
+	// Trace the block from the time it was requested when requested.  The
+	// download span includes the time the block was queued for processing.
+	// The spans are nil, and therefore ignored, when the block is not traced.
+	span := m.cfg.Tracer.StartRoot(blockHash, "netsync.block", requestedAt)
+	span.SetAttribute("block.height", bmsg.block.MsgBlock().Header.Height)
+	span.SetAttribute("peer", peer.String())
+	span.StartChildAt("netsync.download", requestedAt).End()
+	defer span.End()
+
 	// Save whether or not the chain believes it is current prior to processing
 	// the block for use below in determining logging behavior.
 	chain := m.cfg.Chain
@@ -812,12 +997,19 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 	forkLen, err := m.processBlock(bmsg.block)
 	delete(peer.requestedBlocks, *blockHash)
 	delete(m.requestedBlocks, *blockHash)
+	now := time.Now()
+	peer.lastBlockProgress = now
+	m.blocksDownloaded++
+	if peer.partialBlock != nil && peer.partialBlock.Hash() == *blockHash {
+		peer.partialBlock = nil
+	}
 	if err != nil {
 		// Ideally there should never be any requests for duplicate blocks, but
 		// ignore any that manage to make it through.
 		if errors.Is(err, blockchain.ErrDuplicateBlock) {
 			return
 		}
+		span.SetError(err)
 
 		// When the error is a rule error, it means the block was simply
 		// rejected as opposed to something actually going wrong, so log it as
@@ -826,11 +1018,13 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		//
 		// Note that orphan blocks are never requested so there is no need to
 		// test for that rule error separately.
+		blockLog := logging.With(log, "peer", peer.String(), "block",
+			blockHash.String())
 		var rErr blockchain.RuleError
 		if errors.As(err, &rErr) {
-			log.Infof("Rejected block %v from %s: %v", blockHash, peer, err)
+			blockLog.Infof("Rejected block %v from %s: %v", blockHash, peer, err)
 		} else {
-			log.Errorf("Failed to process block %v: %v", blockHash, err)
+			blockLog.Errorf("Failed to process block %v: %v", blockHash, err)
 		}
 		if errors.Is(err, database.ErrCorruption) ||
 			errors.Is(err, blockchain.ErrUtxoBackendCorruption) {
@@ -840,6 +1034,9 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 		return
 	}
 
+	// Update the samples used to calculate the block validation rate.
+	m.recordBlockRateSample(now, chain.BestSnapshot().Height)
+
 	// Log information about the block.  Use the progress logger when the chain
 	// was not already current prior to processing the block to provide nicer
 	// periodic logging with a progress percentage.  Otherwise, log the block
@@ -911,7 +1108,9 @@ func (m *SyncManager) handleBlockMsg(bmsg *blockMsg) {
 
 	// Request more blocks using the headers when the request queue is getting
 	// short.
-	if peer == m.syncPeer && len(peer.requestedBlocks) < minInFlightBlocks {
+	if m.hdrSyncState.headersSynced && m.isBlockDownloadPeer(peer) &&
+		len(peer.requestedBlocks) < minInFlightBlocks {
+
 		m.fetchNextBlocks(peer)
 	}
 }
@@ -1198,8 +1397,9 @@ func (m *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 			}
 
 			iv := wire.NewInvVect(wire.InvTypeBlock, hash)
-			limitAdd(m.requestedBlocks, *hash, maxRequestedBlocks)
-			limitAdd(peer.requestedBlocks, *hash, maxRequestedBlocks)
+			limitAdd(m.requestedBlocks, *hash, struct{}{}, maxRequestedBlocks)
+			limitAdd(peer.requestedBlocks, *hash, time.Now(),
+				maxRequestedBlocks)
 			gdmsg.AddInvVect(iv)
 		}
 		if len(gdmsg.InvList) > 0 {
@@ -1209,9 +1409,137 @@ func (m *SyncManager) handleHeadersMsg(hmsg *headersMsg) {
 
 	// Download any blocks needed to catch the local chain up to the best known
 	// header (if any) once the initial headers sync is done.
-	if headersSynced && m.syncPeer != nil {
-		m.fetchNextBlocks(m.syncPeer)
+	m.fetchNextBlocksFromPeers()
+}
+
+// requestFullBlock requests the full block with the provided hash from the
+// peer.  It is used when a block announced via a compact block can't be
+// reconstructed.  The block must already be tracked as requested from the
+// peer.
+func (m *SyncManager) requestFullBlock(peer *syncMgrPeer, blockHash *chainhash.Hash) {
+	gdmsg := wire.NewMsgGetDataSizeHint(1)
+	gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, blockHash))
+	peer.QueueMessage(gdmsg, nil)
+}
+
+// processPartialBlock processes the block reconstructed from the provided
+// partial block, which must not be missing any transactions, the same as if
+// the full block had been received from the peer.  The full block is requested
+// from the peer instead when the reconstructed block does not match its header.
+func (m *SyncManager) processPartialBlock(peer *syncMgrPeer, partial *cmpctblock.PartialBlock) {
+	msgBlock, err := partial.Block()
+	if err != nil {
+		blockHash := partial.Hash()
+		log.Debugf("Unable to reconstruct block %v from peer %s: %v -- "+
+			"requesting full block", blockHash, peer, err)
+		m.requestFullBlock(peer, &blockHash)
+		return
+	}
+	m.handleBlockMsg(&blockMsg{block: dcrutil.NewBlock(msgBlock), peer: peer.Peer})
+}
+
+// handleCmpctBlockMsg handles cmpctblock messages from all peers.
+//
+// The header of the compact block is processed the same as a header
+// announcement.  The block is then reconstructed from the transactions in the
+// memory pool when the chain is current and the parent of the block is known,
+// and any missing transactions are requested from the peer.  Otherwise, the
+// full block is requested as usual for header announcements.
+func (m *SyncManager) handleCmpctBlockMsg(cmsg *cmpctBlockMsg) {
+	peer := lookupPeer(cmsg.peer, m.peers)
+	if peer == nil {
+		return
+	}
+
+	// Determine whether or not the block should be reconstructed and mark it
+	// as requested from the peer prior to processing the header when it
+	// should so that it is not also requested in full.
+	chain := m.cfg.Chain
+	header := &cmsg.cmpctBlock.Header
+	blockHash := header.BlockHash()
+	_, isRequestedBlock := m.requestedBlocks[blockHash]
+	reconstruct := !isRequestedBlock && chain.IsCurrent() &&
+		!chain.HaveBlock(&blockHash) && chain.HaveBlock(&header.PrevBlock)
+	if reconstruct {
+		limitAdd(m.requestedBlocks, blockHash, struct{}{},
+			maxRequestedBlocks)
+		limitAdd(peer.requestedBlocks, blockHash, time.Now(),
+			maxRequestedBlocks)
+	}
+	headers := &wire.MsgHeaders{Headers: []*wire.BlockHeader{header}}
+	m.handleHeadersMsg(&headersMsg{headers: headers, peer: cmsg.peer})
+	if !reconstruct {
+		return
+	}
+
+	// Nothing more to do when the header was rejected.
+	if !chain.HaveHeader(&blockHash) {
+		delete(peer.requestedBlocks, blockHash)
+		delete(m.requestedBlocks, blockHash)
+		return
+	}
+
+	// Attempt to reconstruct the block from the transactions in the memory
+	// pool.
+	txDescs := m.cfg.TxMemPool.TxDescs()
+	mempoolTxns := make([]*wire.MsgTx, 0, len(txDescs))
+	for _, txDesc := range txDescs {
+		mempoolTxns = append(mempoolTxns, txDesc.Tx.MsgTx())
+	}
+	partial, err := cmpctblock.NewPartialBlock(cmsg.cmpctBlock, mempoolTxns)
+	if err != nil {
+		log.Debugf("Received invalid compact block %v from peer %s: %v -- "+
+			"disconnecting", blockHash, peer, err)
+		delete(peer.requestedBlocks, blockHash)
+		delete(m.requestedBlocks, blockHash)
+		peer.Disconnect()
+		return
+	}
+
+	// Request any missing transactions from the peer.  The full block is
+	// requested instead for any block that is still awaiting transactions
+	// from the peer since only one is tracked at a time.
+	if getBlockTxn := partial.Missing(); getBlockTxn != nil {
+		if peer.partialBlock != nil {
+			prevHash := peer.partialBlock.Hash()
+			m.requestFullBlock(peer, &prevHash)
+		}
+		log.Debugf("Requesting %d missing transactions for compact block %v "+
+			"from peer %s", len(getBlockTxn.TxIndexes)+
+			len(getBlockTxn.STxIndexes), blockHash, peer)
+		peer.partialBlock = partial
+		peer.QueueMessage(getBlockTxn, nil)
+		return
+	}
+
+	m.processPartialBlock(peer, partial)
+}
+
+// handleBlockTxnMsg handles blocktxn messages from all peers by filling in the
+// missing transactions of the block the peer previously announced via a
+// compact block and processing it.
+func (m *SyncManager) handleBlockTxnMsg(bmsg *blockTxnMsg) {
+	peer := lookupPeer(bmsg.peer, m.peers)
+	if peer == nil {
+		return
 	}
+
+	partial := peer.partialBlock
+	blockHash := bmsg.blockTxn.BlockHash
+	if partial == nil || partial.Hash() != blockHash {
+		log.Debugf("Ignoring unrequested block transactions for block %v "+
+			"from peer %s", blockHash, peer)
+		return
+	}
+	peer.partialBlock = nil
+
+	if err := partial.Fill(bmsg.blockTxn); err != nil {
+		log.Debugf("Unable to fill compact block %v from peer %s: %v -- "+
+			"requesting full block", blockHash, peer, err)
+		m.requestFullBlock(peer, &blockHash)
+		return
+	}
+	m.processPartialBlock(peer, partial)
 }
 
 // handleNotFoundMsg handles notfound messages from all peers.
@@ -1221,6 +1549,7 @@ func (m *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 		return
 	}
 
+	var requeueBlocks bool
 	for _, inv := range nfmsg.notFound.InvList {
 		// verify the hash was actually announced by the peer
 		// before deleting from the global requested maps.
@@ -1229,6 +1558,7 @@ func (m *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 			if _, exists := peer.requestedBlocks[inv.Hash]; exists {
 				delete(peer.requestedBlocks, inv.Hash)
 				delete(m.requestedBlocks, inv.Hash)
+				requeueBlocks = true
 			}
 		case wire.InvTypeTx:
 			if _, exists := peer.requestedTxns[inv.Hash]; exists {
@@ -1237,6 +1567,18 @@ func (m *SyncManager) handleNotFoundMsg(nfmsg *notFoundMsg) {
 			}
 		}
 	}
+
+	// Request any blocks the peer does not have from the other peers.
+	if requeueBlocks {
+		m.requeueNeededBlocks()
+		if m.hdrSyncState.headersSynced {
+			for _, p := range m.peers {
+				if p != peer && m.isBlockDownloadPeer(p) {
+					m.fetchNextBlocks(p)
+				}
+			}
+		}
+	}
 }
 
 // needTx returns whether or not the transaction needs to be downloaded.  For
@@ -1304,21 +1646,23 @@ func (m *SyncManager) handleInvMsg(imsg *invMsg) {
 			// known to have.
 			peer.AddKnownInventory(iv)
 
-			// Ignore transaction announcements before the chain is current or
-			// are otherwise not needed, such as when they were recently
-			// rejected or are already known.
+			// Ignore transaction announcements in blocks-only mode, before the
+			// chain is current, or when they are otherwise not needed, such as
+			// when they were recently rejected or are already known.
 			//
 			// Transaction announcements are based on the state of the fully
 			// synced ledger, so they are likely to be invalid before the chain
 			// is current.
-			if !isCurrent || !m.needTx(&iv.Hash) {
+			if m.cfg.BlocksOnly || !isCurrent || !m.needTx(&iv.Hash) {
 				continue
 			}
 
 			// Request the transaction if there is not one already pending.
 			if _, exists := m.requestedTxns[iv.Hash]; !exists {
-				limitAdd(m.requestedTxns, iv.Hash, maxRequestedTxns)
-				limitAdd(peer.requestedTxns, iv.Hash, maxRequestedTxns)
+				limitAdd(m.requestedTxns, iv.Hash, struct{}{},
+					maxRequestedTxns)
+				limitAdd(peer.requestedTxns, iv.Hash, struct{}{},
+					maxRequestedTxns)
 				requestQueue = append(requestQueue, iv)
 			}
 		}
@@ -1362,9 +1706,9 @@ func (m *SyncManager) handleInvMsg(imsg *invMsg) {
 }
 
 // limitAdd is a helper function for maps that require a maximum limit by
-// evicting a random value if adding the new value would cause it to
-// overflow the maximum allowed.
-func limitAdd(m map[chainhash.Hash]struct{}, hash chainhash.Hash, limit int) {
+// evicting a random entry if adding the new entry would cause it to overflow
+// the maximum allowed.
+func limitAdd[T any](m map[chainhash.Hash]T, hash chainhash.Hash, value T, limit int) {
 	if len(m)+1 > limit {
 		// Remove a random entry from the map.  For most compilers, Go's
 		// range statement iterates starting at a random item although
@@ -1377,7 +1721,7 @@ func limitAdd(m map[chainhash.Hash]struct{}, hash chainhash.Hash, limit int) {
 			break
 		}
 	}
-	m[hash] = struct{}{}
+	m[hash] = value
 }
 
 // eventHandler is the main handler for the sync manager.  It must be run as a
@@ -1387,6 +1731,9 @@ func limitAdd(m map[chainhash.Hash]struct{}, hash chainhash.Hash, limit int) {
 // because the sync manager controls which blocks are needed and how the
 // fetching should proceed.
 func (m *SyncManager) eventHandler(ctx context.Context) {
+	blockStallTicker := time.NewTicker(blockStallCheckInterval)
+	defer blockStallTicker.Stop()
+
 out:
 	for {
 		select {
@@ -1415,6 +1762,20 @@ out:
 			case *headersMsg:
 				m.handleHeadersMsg(msg)
 
+			case *cmpctBlockMsg:
+				m.handleCmpctBlockMsg(msg)
+				select {
+				case msg.reply <- struct{}{}:
+				case <-ctx.Done():
+				}
+
+			case *blockTxnMsg:
+				m.handleBlockTxnMsg(msg)
+				select {
+				case msg.reply <- struct{}{}:
+				case <-ctx.Done():
+				}
+
 			case *notFoundMsg:
 				m.handleNotFoundMsg(msg)
 
@@ -1428,6 +1789,9 @@ out:
 				}
 				msg.reply <- peerID
 
+			case getSyncStatusMsg:
+				msg.reply <- m.syncStatus()
+
 			case requestFromPeerMsg:
 				err := m.requestFromPeer(msg.peer, msg.blocks, msg.voteHashes,
 					msg.tSpendHashes)
@@ -1436,7 +1800,11 @@ out:
 				}
 
 			case processBlockMsg:
+				span := m.cfg.Tracer.StartRoot(msg.block.Hash(),
+					"netsync.process_block", time.Now())
 				forkLen, err := m.processBlock(msg.block)
+				span.SetError(err)
+				span.End()
 				if err != nil {
 					msg.reply <- processBlockResponse{
 						forkLen: forkLen,
@@ -1474,6 +1842,9 @@ out:
 				m.syncPeer.Disconnect()
 			}
 
+		case now := <-blockStallTicker.C:
+			m.disconnectBlockStallers(now)
+
 		case <-ctx.Done():
 			break out
 		}
@@ -1511,6 +1882,26 @@ func (m *SyncManager) QueueBlock(block *dcrutil.Block, peer *peerpkg.Peer, done
 	}
 }
 
+// QueueCmpctBlock adds the passed cmpctblock message and peer to the event
+// handling queue.
+func (m *SyncManager) QueueCmpctBlock(cmpctBlock *wire.MsgCmpctBlock, peer *peerpkg.Peer, done chan struct{}) {
+	select {
+	case m.msgChan <- &cmpctBlockMsg{cmpctBlock: cmpctBlock, peer: peer, reply: done}:
+	case <-m.quit:
+		done <- struct{}{}
+	}
+}
+
+// QueueBlockTxn adds the passed blocktxn message and peer to the event
+// handling queue.
+func (m *SyncManager) QueueBlockTxn(blockTxn *wire.MsgBlockTxn, peer *peerpkg.Peer, done chan struct{}) {
+	select {
+	case m.msgChan <- &blockTxnMsg{blockTxn: blockTxn, peer: peer, reply: done}:
+	case <-m.quit:
+		done <- struct{}{}
+	}
+}
+
 // QueueInv adds the passed inv message and peer to the event handling queue.
 func (m *SyncManager) QueueInv(inv *wire.MsgInv, peer *peerpkg.Peer) {
 	select {
@@ -1620,7 +2011,7 @@ func (m *SyncManager) requestFromPeer(p *peerpkg.Peer, blocks, voteHashes,
 				bh, err.Error())
 		}
 
-		peer.requestedBlocks[*bh] = struct{}{}
+		peer.requestedBlocks[*bh] = time.Now()
 		m.requestedBlocks[*bh] = struct{}{}
 	}
 
@@ -1786,6 +2177,12 @@ type Config struct {
 	// believed to be fully synced.
 	NoMiningStateSync bool
 
+	// BlocksOnly indicates whether or not the sync manager should refrain from
+	// requesting transactions from peers.  This includes transactions
+	// announced by peers as well as those that are part of the initial mining
+	// state synchronization, which is skipped.
+	BlocksOnly bool
+
 	// MaxPeers specifies the maximum number of peers the server is expected to
 	// be connected with.  It is primarily used as a hint for more efficient
 	// synchronization.
@@ -1799,6 +2196,11 @@ type Config struct {
 	// and querying the most recently confirmed transactions.  It is useful for
 	// preventing duplicate requests.
 	RecentlyConfirmedTxns *apbf.Filter
+
+	// Tracer defines an optional tracer that is used to trace the download
+	// and processing of blocks and the processing of transactions received
+	// from peers.  Nothing is traced when it is nil.
+	Tracer *tracing.Tracer
 }
 
 // New returns a new network chain synchronization manager.  Use Run to begin
@@ -0,0 +1,355 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v5/chaingen"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
+	_ "github.com/decred/dcrd/database/v3/ffldb"
+	"github.com/decred/dcrd/internal/blockchain"
+	peerpkg "github.com/decred/dcrd/peer/v3"
+	"github.com/decred/dcrd/wire"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// testPeer houses a peer that is connected to a simulated remote peer along
+// with the getdata messages the remote peer receives and the blocks it has been
+// asked to provide by them so far.
+type testPeer struct {
+	*peerpkg.Peer
+	getData  chan *wire.MsgGetData
+	received map[chainhash.Hash]struct{}
+}
+
+// newTestPeer returns a peer that is connected to a simulated remote peer that
+// claims to have the blocks up to the provided height.  The remote peer
+// completes the version handshake and reports the getdata messages it receives
+// on the getData channel of the returned test peer.
+func newTestPeer(t *testing.T, params *chaincfg.Params, lastBlock int32) *testPeer {
+	t.Helper()
+
+	localConn, remoteConn := net.Pipe()
+	p, err := peerpkg.NewOutboundPeer(&peerpkg.Config{
+		UserAgentName:    "netsynctest",
+		UserAgentVersion: "1.0",
+		Net:              params.Net,
+	}, "127.0.0.1:18555")
+	if err != nil {
+		t.Fatalf("unable to create peer: %v", err)
+	}
+
+	tp := &testPeer{
+		Peer:     p,
+		getData:  make(chan *wire.MsgGetData, 100),
+		received: make(map[chainhash.Hash]struct{}),
+	}
+	handshakeDone := make(chan struct{})
+	go func() {
+		defer remoteConn.Close()
+		pver := wire.ProtocolVersion
+		for {
+			msg, _, err := wire.ReadMessage(remoteConn, pver, params.Net)
+			if err != nil {
+				return
+			}
+			switch msg := msg.(type) {
+			case *wire.MsgVersion:
+				nonce, err := wire.RandomUint64()
+				if err != nil {
+					return
+				}
+				me := &wire.NetAddress{Services: wire.SFNodeNetwork}
+				you := &wire.NetAddress{Services: msg.Services}
+				ver := wire.NewMsgVersion(me, you, nonce, lastBlock)
+				ver.Services = wire.SFNodeNetwork
+				err = wire.WriteMessage(remoteConn, ver, pver, params.Net)
+				if err != nil {
+					return
+				}
+				err = wire.WriteMessage(remoteConn, wire.NewMsgVerAck(), pver,
+					params.Net)
+				if err != nil {
+					return
+				}
+
+			case *wire.MsgVerAck:
+				close(handshakeDone)
+
+			case *wire.MsgGetData:
+				tp.getData <- msg
+			}
+		}
+	}()
+	p.AssociateConnection(localConn)
+	t.Cleanup(p.Disconnect)
+
+	select {
+	case <-handshakeDone:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting for version handshake")
+	}
+	return tp
+}
+
+// newTestSyncManager returns a sync manager for a new chain instance that
+// knows the headers of the provided number of blocks, but does not have any of
+// the blocks, along with the hashes of those blocks in height order.  The
+// initial headers sync is marked done so blocks are downloaded as soon as
+// peers are added.
+func newTestSyncManager(t *testing.T, params *chaincfg.Params, numBlocks int) (*SyncManager, []chainhash.Hash) {
+	t.Helper()
+
+	// Create the block and UTXO databases and a chain instance backed by them.
+	dataDir := t.TempDir()
+	db, err := database.Create("ffldb", filepath.Join(dataDir, "blocks"),
+		params.Net)
+	if err != nil {
+		t.Fatalf("unable to create block database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	utxoDb, err := leveldb.OpenFile(filepath.Join(dataDir, "utxo"), nil)
+	if err != nil {
+		t.Fatalf("unable to create utxo database: %v", err)
+	}
+	t.Cleanup(func() { utxoDb.Close() })
+	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	chain, err := blockchain.New(context.Background(), &blockchain.Config{
+		DB:          db,
+		UtxoBackend: utxoBackend,
+		ChainParams: params,
+		TimeSource:  blockchain.NewMedianTime(),
+		UtxoCache: blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
+			Backend:      utxoBackend,
+			FlushBlockDB: func() error { return nil },
+			MaxSize:      1024 * 1024,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	// Generate the blocks and process their headers.
+	g, err := chaingen.MakeGenerator(params)
+	if err != nil {
+		t.Fatalf("unable to create generator: %v", err)
+	}
+	hashes := make([]chainhash.Hash, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		var block *wire.MsgBlock
+		if i == 0 {
+			block = g.CreateBlockOne("bfb", 0)
+		} else {
+			block = g.NextBlock(fmt.Sprintf("bm%d", i), nil, nil)
+		}
+		if err := chain.ProcessBlockHeader(&block.Header); err != nil {
+			t.Fatalf("unable to process header %d: %v", i+1, err)
+		}
+		hashes = append(hashes, block.BlockHash())
+	}
+
+	m := New(&Config{
+		ChainParams: params,
+		Chain:       chain,
+		TimeSource:  blockchain.NewMedianTime(),
+		MaxPeers:    8,
+	})
+	m.hdrSyncState.headersSynced = true
+	return m, hashes
+}
+
+// requestedHashes returns the sorted hashes of the blocks that are in flight
+// from the provided peer.
+func requestedHashes(m *SyncManager, p *testPeer) []chainhash.Hash {
+	peer := m.peers[p.Peer]
+	hashes := make([]chainhash.Hash, 0, len(peer.requestedBlocks))
+	for hash := range peer.requestedBlocks {
+		hashes = append(hashes, hash)
+	}
+	sortHashes(hashes)
+	return hashes
+}
+
+// sortHashes sorts the provided hashes in place and returns them.
+func sortHashes(hashes []chainhash.Hash) []chainhash.Hash {
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].String() < hashes[j].String()
+	})
+	return hashes
+}
+
+// concatHashes returns a new slice with the provided hashes.
+func concatHashes(hashSlices ...[]chainhash.Hash) []chainhash.Hash {
+	var hashes []chainhash.Hash
+	for _, s := range hashSlices {
+		hashes = append(hashes, s...)
+	}
+	return hashes
+}
+
+// assertRequested ensures the blocks in flight from the provided peer are
+// exactly the provided blocks and that the remote peer was sent getdata
+// messages for all of them.
+func assertRequested(t *testing.T, m *SyncManager, p *testPeer, want []chainhash.Hash) {
+	t.Helper()
+
+	got := requestedHashes(m, p)
+	want = sortHashes(append([]chainhash.Hash(nil), want...))
+	if len(got) != len(want) {
+		t.Fatalf("peer %s has %d blocks in flight, want %d", p, len(got),
+			len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("peer %s has unexpected blocks in flight -- got %v, "+
+				"want %v", p, got, want)
+		}
+	}
+	for _, hash := range got {
+		if _, ok := m.requestedBlocks[hash]; !ok {
+			t.Fatalf("block %s in flight from peer %s is not tracked as "+
+				"requested", hash, p)
+		}
+	}
+
+	// Ensure the remote peer is sent getdata messages for all of the blocks
+	// in flight.
+	allReceived := func() bool {
+		for _, hash := range got {
+			if _, ok := p.received[hash]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	for !allReceived() {
+		select {
+		case msg := <-p.getData:
+			for _, iv := range msg.InvList {
+				if iv.Type != wire.InvTypeBlock {
+					t.Fatalf("unexpected inventory type %v requested from "+
+						"peer %s", iv.Type, p)
+				}
+				p.received[iv.Hash] = struct{}{}
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout waiting for getdata from peer %s", p)
+		}
+	}
+}
+
+// TestBlockDownloadScheduling ensures the blocks needed during the initial
+// chain sync are requested from all eligible peers in disjoint ranges that
+// respect the maximum number of blocks in flight per peer and the best block
+// each peer is known to have.
+func TestBlockDownloadScheduling(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	m, hashes := newTestSyncManager(t, params, 30)
+	ctx := context.Background()
+
+	// Ensure the first peer becomes the sync peer and is only requested up to
+	// the maximum number of blocks in flight even though more are needed.
+	peer1 := newTestPeer(t, params, 30)
+	m.handleNewPeerMsg(ctx, peer1.Peer)
+	if m.syncPeer == nil || m.syncPeer.Peer != peer1.Peer {
+		t.Fatal("first peer did not become the sync peer")
+	}
+	assertRequested(t, m, peer1, hashes[:maxInFlightBlocks])
+
+	// Ensure the second peer is requested the next blocks, but only up to the
+	// best block it is known to have.
+	peer2 := newTestPeer(t, params, 24)
+	m.handleNewPeerMsg(ctx, peer2.Peer)
+	assertRequested(t, m, peer2, hashes[maxInFlightBlocks:24])
+
+	// Ensure the third peer is requested the remaining blocks.
+	peer3 := newTestPeer(t, params, 30)
+	m.handleNewPeerMsg(ctx, peer3.Peer)
+	assertRequested(t, m, peer3, hashes[24:])
+
+	// Ensure requesting blocks again does not request any additional blocks
+	// since all of the needed blocks are already in flight.
+	m.fetchNextBlocksFromPeers()
+	assertRequested(t, m, peer1, hashes[:maxInFlightBlocks])
+	assertRequested(t, m, peer2, hashes[maxInFlightBlocks:24])
+	assertRequested(t, m, peer3, hashes[24:])
+	if len(m.requestedBlocks) != len(hashes) {
+		t.Fatalf("unexpected number of requested blocks -- got %d, want %d",
+			len(m.requestedBlocks), len(hashes))
+	}
+}
+
+// TestBlockDownloadRequeue ensures blocks a peer reports it does not have are
+// requested from other peers, that peers that stall the block download are
+// disconnected, and that the blocks in flight from disconnected peers are
+// requested from the remaining peers.
+func TestBlockDownloadRequeue(t *testing.T) {
+	params := chaincfg.RegNetParams()
+	m, hashes := newTestSyncManager(t, params, 20)
+	ctx := context.Background()
+
+	peer1 := newTestPeer(t, params, 20)
+	m.handleNewPeerMsg(ctx, peer1.Peer)
+	peer2 := newTestPeer(t, params, 20)
+	m.handleNewPeerMsg(ctx, peer2.Peer)
+	assertRequested(t, m, peer1, hashes[:maxInFlightBlocks])
+	assertRequested(t, m, peer2, hashes[maxInFlightBlocks:])
+
+	// Ensure blocks the first peer reports it does not have are requested
+	// from the second peer instead.
+	notFound := wire.NewMsgNotFound()
+	for i := 0; i < 3; i++ {
+		iv := wire.NewInvVect(wire.InvTypeBlock, &hashes[i])
+		if err := notFound.AddInvVect(iv); err != nil {
+			t.Fatalf("unable to add inventory vector: %v", err)
+		}
+	}
+	m.handleNotFoundMsg(&notFoundMsg{notFound: notFound, peer: peer1.Peer})
+	assertRequested(t, m, peer1, hashes[3:maxInFlightBlocks])
+	assertRequested(t, m, peer2, concatHashes(hashes[:3],
+		hashes[maxInFlightBlocks:]))
+
+	// Ensure only the first peer is disconnected when it has not delivered
+	// any blocks within the stall timeout while the second peer has.
+	now := time.Now()
+	m.peers[peer1.Peer].lastBlockProgress = now.Add(-blockStallTimeout)
+	m.peers[peer2.Peer].lastBlockProgress = now.Add(-blockStallTimeout / 2)
+	m.disconnectBlockStallers(now)
+	if peer1.Connected() {
+		t.Fatal("stalling peer was not disconnected")
+	}
+	if !peer2.Connected() {
+		t.Fatal("peer that is not stalling was disconnected")
+	}
+
+	// Ensure the blocks that were in flight from the disconnected peer are
+	// requested from the second peer up to the maximum number of blocks in
+	// flight once it is removed and that the second peer becomes the sync
+	// peer.
+	m.handleDonePeerMsg(peer1.Peer)
+	if m.syncPeer == nil || m.syncPeer.Peer != peer2.Peer {
+		t.Fatal("second peer did not become the sync peer")
+	}
+	assertRequested(t, m, peer2, concatHashes(hashes[:12],
+		hashes[maxInFlightBlocks:]))
+
+	// Ensure the remaining blocks are requested from a new peer.
+	peer3 := newTestPeer(t, params, 20)
+	m.handleNewPeerMsg(ctx, peer3.Peer)
+	assertRequested(t, m, peer3, hashes[12:maxInFlightBlocks])
+	if len(m.requestedBlocks) != len(hashes) {
+		t.Fatalf("unexpected number of requested blocks -- got %d, want %d",
+			len(m.requestedBlocks), len(hashes))
+	}
+}
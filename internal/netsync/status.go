@@ -0,0 +1,216 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package netsync
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// blockRateWindow is the period of time over which the rate blocks are
+	// validated is calculated for the purposes of reporting the sync status.
+	blockRateWindow = time.Minute
+
+	// blockRateSampleInterval is the minimum interval between the samples
+	// used to calculate the rate blocks are validated.
+	blockRateSampleInterval = time.Second
+)
+
+// SyncStage identifies the stage of the chain sync process.
+type SyncStage uint8
+
+const (
+	// SyncStageHeaders indicates the block headers are being synced.
+	SyncStageHeaders SyncStage = iota
+
+	// SyncStageBlocks indicates the block headers are synced and the blocks
+	// are being downloaded and validated.
+	SyncStageBlocks
+
+	// SyncStageCurrent indicates the chain is believed to be fully synced
+	// with the network.
+	SyncStageCurrent
+)
+
+// syncStageStrings is a map of sync stages back to their constant names for
+// pretty printing.
+var syncStageStrings = map[SyncStage]string{
+	SyncStageHeaders: "headers",
+	SyncStageBlocks:  "blocks",
+	SyncStageCurrent: "current",
+}
+
+// String returns the SyncStage as a human-readable name.
+func (s SyncStage) String() string {
+	if str, ok := syncStageStrings[s]; ok {
+		return str
+	}
+	return fmt.Sprintf("Unknown SyncStage (%d)", uint8(s))
+}
+
+// SyncStatus houses information about the progress of the chain sync process.
+type SyncStatus struct {
+	// Stage is the current stage of the chain sync process.
+	Stage SyncStage
+
+	// SyncPeerID is the id of the peer the headers are being synced from or
+	// zero when there is no sync peer.
+	SyncPeerID int32
+
+	// SyncHeight is the latest known height being synced to as reported by
+	// peers.
+	SyncHeight int64
+
+	// BestHeaderHeight is the height of the best known block header.
+	BestHeaderHeight int64
+
+	// HeaderProgress is the estimated progress of the headers sync as a
+	// percentage.
+	HeaderProgress float64
+
+	// BlocksDownloaded is the number of blocks that have been downloaded from
+	// peers since the sync manager was started.
+	BlocksDownloaded uint64
+
+	// BlocksInFlight is the number of blocks that have been requested from
+	// peers and not yet received.
+	BlocksInFlight int
+
+	// BestHeight is the height of the best fully validated block.
+	BestHeight int64
+
+	// VerifyProgress is the estimated progress of the block validation as a
+	// percentage.
+	VerifyProgress float64
+
+	// BlocksPerSecond is the rate blocks were validated over the last
+	// minute.
+	BlocksPerSecond float64
+
+	// ETA is the estimated amount of time remaining until the blocks are
+	// validated up to the best known header.  It is zero when the chain is
+	// current or there is not enough information to estimate it, such as
+	// while the headers are still being synced.
+	ETA time.Duration
+}
+
+// getSyncStatusMsg is a message type to be sent across the message channel for
+// retrieving the current sync status.
+type getSyncStatusMsg struct {
+	reply chan SyncStatus
+}
+
+// blockRateSample houses the best block height at a given time and is used to
+// calculate the rate blocks are validated.
+type blockRateSample struct {
+	time   time.Time
+	height int64
+}
+
+// recordBlockRateSample records the provided best block height as of the given
+// time for use in calculating the rate blocks are validated.  Samples that are
+// taken more frequently than the sample interval are ignored and those that
+// are older than the rate window are discarded.
+//
+// This function is NOT safe for concurrent access.  It must be called from the
+// event handler goroutine.
+func (m *SyncManager) recordBlockRateSample(now time.Time, height int64) {
+	samples := m.blockRateSamples
+	if len(samples) > 0 {
+		last := samples[len(samples)-1]
+		if now.Sub(last.time) < blockRateSampleInterval {
+			return
+		}
+	}
+
+	// Discard samples that are older than the rate window while keeping at
+	// least one to calculate the rate from.
+	var numExpired int
+	for numExpired < len(samples) &&
+		now.Sub(samples[numExpired].time) > blockRateWindow {
+
+		numExpired++
+	}
+	if numExpired > 0 && numExpired == len(samples) {
+		numExpired--
+	}
+	samples = append(samples[numExpired:], blockRateSample{now, height})
+	m.blockRateSamples = samples
+}
+
+// blockRate returns the rate blocks were validated in blocks per second based
+// on the recorded samples.
+//
+// This function is NOT safe for concurrent access.  It must be called from the
+// event handler goroutine.
+func (m *SyncManager) blockRate() float64 {
+	samples := m.blockRateSamples
+	if len(samples) < 2 {
+		return 0
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	elapsed := last.time.Sub(first.time).Seconds()
+	if elapsed <= 0 || last.height <= first.height {
+		return 0
+	}
+	return float64(last.height-first.height) / elapsed
+}
+
+// syncStatus returns the current status of the chain sync process.
+//
+// This function is NOT safe for concurrent access.  It must be called from the
+// event handler goroutine.
+func (m *SyncManager) syncStatus() SyncStatus {
+	chain := m.cfg.Chain
+	_, bestHeaderHeight := chain.BestHeader()
+	bestHeight := chain.BestSnapshot().Height
+	status := SyncStatus{
+		Stage:            SyncStageHeaders,
+		SyncHeight:       m.SyncHeight(),
+		BestHeaderHeight: bestHeaderHeight,
+		HeaderProgress:   m.headerSyncProgress(),
+		BlocksDownloaded: m.blocksDownloaded,
+		BlocksInFlight:   len(m.requestedBlocks),
+		BestHeight:       bestHeight,
+		VerifyProgress:   chain.VerifyProgress(),
+		BlocksPerSecond:  m.blockRate(),
+	}
+	if m.syncPeer != nil {
+		status.SyncPeerID = m.syncPeer.ID()
+	}
+	switch {
+	case m.IsCurrent():
+		status.Stage = SyncStageCurrent
+		status.HeaderProgress = 100
+
+	case m.hdrSyncState.headersSynced:
+		status.Stage = SyncStageBlocks
+		remaining := bestHeaderHeight - bestHeight
+		if remaining > 0 && status.BlocksPerSecond > 0 {
+			secs := float64(remaining) / status.BlocksPerSecond
+			status.ETA = time.Duration(secs * float64(time.Second))
+		}
+	}
+	return status
+}
+
+// SyncStatus returns the current status of the chain sync process.
+//
+// This function is safe for concurrent access.
+func (m *SyncManager) SyncStatus() SyncStatus {
+	reply := make(chan SyncStatus, 1)
+	select {
+	case m.msgChan <- getSyncStatusMsg{reply: reply}:
+	case <-m.quit:
+	}
+
+	select {
+	case status := <-reply:
+		return status
+	case <-m.quit:
+		return SyncStatus{}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package p2ptransport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+)
+
+const (
+	// MaxFramePayload is the maximum number of plaintext bytes in a single
+	// frame.  Writes that are larger are split across multiple frames.
+	MaxFramePayload = 1 << 16
+
+	// nonceSize is the size of the nonces used with AES-GCM.
+	nonceSize = 12
+
+	// tagSize is the size of the authentication tag AES-GCM appends to each
+	// sealed payload.
+	tagSize = 16
+
+	// frameLenSize is the size of the encrypted length prefix of each frame.
+	frameLenSize = 4 + tagSize
+)
+
+// cipherState houses the state used to encrypt or decrypt the frames sent in a
+// single direction of a connection.
+type cipherState struct {
+	aead  cipher.AEAD
+	nonce uint64
+}
+
+// newCipherState returns a cipher state that uses the provided 32-byte key.
+func newCipherState(key []byte) (*cipherState, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &cipherState{aead: aead}, nil
+}
+
+// nextNonce returns the nonce to use for the next seal or open operation and
+// advances the counter.  Nonces are never reused since each direction of a
+// connection uses a separate key.
+func (c *cipherState) nextNonce() []byte {
+	var nonce [nonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], c.nonce)
+	c.nonce++
+	return nonce[:]
+}
+
+// seal encrypts and authenticates the provided plaintext and appends the
+// result to dst.
+func (c *cipherState) seal(dst, plaintext []byte) []byte {
+	return c.aead.Seal(dst, c.nextNonce(), plaintext, nil)
+}
+
+// open authenticates and decrypts the provided ciphertext and appends the
+// result to dst.
+//
+// ErrAuthFailed is returned when the ciphertext does not authenticate.
+func (c *cipherState) open(dst, ciphertext []byte) ([]byte, error) {
+	plaintext, err := c.aead.Open(dst, c.nextNonce(), ciphertext, nil)
+	if err != nil {
+		return nil, ErrAuthFailed
+	}
+	return plaintext, nil
+}
+
+// Conn is a net.Conn that encrypts and authenticates all data written to and
+// read from the underlying connection.  It is created by performing a handshake
+// with Initiate or Accept.
+//
+// Conn is safe for a single concurrent reader and a single concurrent writer.
+type Conn struct {
+	net.Conn
+
+	sessionID [32]byte
+
+	readMtx sync.Mutex
+	recv    *cipherState
+	readBuf []byte
+
+	writeMtx sync.Mutex
+	send     *cipherState
+}
+
+// SessionID returns an identifier for the session that both sides of the
+// connection derive during the handshake.  Comparing it out of band allows the
+// peers to detect a man-in-the-middle.
+func (c *Conn) SessionID() [32]byte {
+	return c.sessionID
+}
+
+// writeFrame encrypts the provided payload, which must not exceed
+// MaxFramePayload, into a single frame and writes it to the underlying
+// connection.
+//
+// This function MUST be called with the write mutex held (for writes).
+func (c *Conn) writeFrame(payload []byte) error {
+	var lenBytes [4]byte
+	binary.LittleEndian.PutUint32(lenBytes[:], uint32(len(payload)))
+	frame := make([]byte, 0, frameLenSize+len(payload)+tagSize)
+	frame = c.send.seal(frame, lenBytes[:])
+	frame = c.send.seal(frame, payload)
+	_, err := c.Conn.Write(frame)
+	return err
+}
+
+// readFrame reads a single frame from the underlying connection and returns
+// its decrypted payload.
+//
+// ErrAuthFailed is returned when the frame does not authenticate and
+// ErrFrameTooLarge is returned when it claims to be larger than
+// MaxFramePayload.
+//
+// This function MUST be called with the read mutex held (for reads).
+func (c *Conn) readFrame() ([]byte, error) {
+	var sealedLen [frameLenSize]byte
+	if _, err := io.ReadFull(c.Conn, sealedLen[:]); err != nil {
+		return nil, err
+	}
+	lenBytes, err := c.recv.open(nil, sealedLen[:])
+	if err != nil {
+		return nil, err
+	}
+	payloadLen := binary.LittleEndian.Uint32(lenBytes)
+	if payloadLen > MaxFramePayload {
+		return nil, ErrFrameTooLarge
+	}
+
+	sealed := make([]byte, int(payloadLen)+tagSize)
+	if _, err := io.ReadFull(c.Conn, sealed); err != nil {
+		return nil, err
+	}
+	return c.recv.open(sealed[:0], sealed)
+}
+
+// Read reads decrypted data from the connection.  This is part of the net.Conn
+// interface implementation.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.readMtx.Lock()
+	defer c.readMtx.Unlock()
+
+	for len(c.readBuf) == 0 {
+		payload, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		c.readBuf = payload
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+// Write encrypts the provided data and writes it to the connection.  This is
+// part of the net.Conn interface implementation.
+func (c *Conn) Write(b []byte) (int, error) {
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+
+	var n int
+	for len(b) > 0 {
+		payload := b
+		if len(payload) > MaxFramePayload {
+			payload = payload[:MaxFramePayload]
+		}
+		if err := c.writeFrame(payload); err != nil {
+			return n, err
+		}
+		n += len(payload)
+		b = b[len(payload):]
+	}
+	return n, nil
+}
+
+// prefixConn is a net.Conn that returns the provided bytes, which were already
+// read from the underlying connection, before reading any further data from
+// it.
+type prefixConn struct {
+	net.Conn
+	prefix []byte
+}
+
+// Read reads data from the connection.  This is part of the net.Conn interface
+// implementation.
+func (c *prefixConn) Read(b []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(b, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
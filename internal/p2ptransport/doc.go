@@ -0,0 +1,50 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package p2ptransport implements an opt-in encrypted and authenticated transport
+for the peer-to-peer protocol.
+
+The plaintext peer-to-peer protocol makes it trivial for passive network
+observers, such as ISPs and anyone else on the path between peers, to identify
+Decred traffic and to fingerprint which peers originate transactions.  This
+package wraps a connection in a transport that encrypts and authenticates all
+traffic after a short handshake so that passive observers only see
+pseudorandom bytes.
+
+Peers that support the transport advertise it via the SFNodeEncryption service
+flag.  The handshake is as follows:
+
+ 1. The initiator generates an ephemeral secp256k1 key pair and sends the
+    compressed public key.
+ 2. The responder generates its own ephemeral key pair and replies with its
+    compressed public key.
+ 3. Both sides perform elliptic curve Diffie-Hellman and derive a key for each
+    direction along with a session id via HKDF-SHA256 keyed by the network
+    magic and both public keys.
+ 4. Both sides send an encrypted confirmation frame and ensure the one they
+    receive authenticates under the derived keys.
+
+All traffic thereafter is split into frames that are each encrypted and
+authenticated with AES-256-GCM, including the length of the frame, so that the
+message boundaries of the underlying protocol are not revealed either.
+
+Responders accept both plaintext and encrypted connections on the same listener
+by examining the first bytes sent by the initiator since plaintext connections
+always start with the network magic while encrypted connections start with a
+compressed public key.
+
+Since the ephemeral keys are not tied to a long-term identity, the transport
+does not protect against active man-in-the-middle attacks on its own.  However,
+it raises the cost of surveillance from passively recording traffic to actively
+intercepting connections, which is far more expensive and detectable.
+
+Downgrade attacks, where an active attacker strips the service flag from
+address relay or interferes with the handshake to force peers to communicate
+in plaintext, are detected by callers refusing to fall back to the plaintext
+protocol when connecting to a peer that is known to support the transport and
+by ensuring the services negotiated in the version message agree with the
+transport that is in use.
+*/
+package p2ptransport
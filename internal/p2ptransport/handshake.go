@@ -0,0 +1,234 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package p2ptransport
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// handshakeTimeout is the maximum amount of time allowed to complete the
+	// handshake.
+	handshakeTimeout = 10 * time.Second
+
+	// pubKeySize is the size of the compressed ephemeral public keys
+	// exchanged during the handshake.
+	pubKeySize = secp256k1.PubKeyBytesLenCompressed
+
+	// saltTag is the tag that is prepended to the network magic to form the
+	// salt used when deriving the session keys.
+	saltTag = "dcrd p2p transport"
+
+	// confirmTag is the payload of the confirmation frame each side sends to
+	// prove it derived the same keys.
+	confirmTag = "dcrd p2p transport confirm"
+)
+
+var (
+	// ErrInvalidPubKey indicates the remote peer sent an ephemeral public key
+	// that is not a valid compressed secp256k1 public key.
+	ErrInvalidPubKey = errors.New("invalid handshake public key")
+
+	// ErrHandshakeMismatch indicates the remote peer did not derive the same
+	// session keys, such as when it is on a different network.
+	ErrHandshakeMismatch = errors.New("handshake confirmation mismatch")
+
+	// ErrAuthFailed indicates a frame failed to authenticate, which means it
+	// was either tampered with or not encrypted with the session keys.
+	ErrAuthFailed = errors.New("frame authentication failed")
+
+	// ErrFrameTooLarge indicates a frame claims to have a payload larger than
+	// MaxFramePayload.
+	ErrFrameTooLarge = errors.New("frame exceeds max payload size")
+)
+
+// hkdf derives a 32-byte key for the provided info from the passed
+// pseudorandom key per the HKDF-Expand step of RFC 5869.  Only a single block
+// is ever needed, so the output is the first block of the expansion.
+func hkdf(prk []byte, info string) []byte {
+	mac := hmac.New(sha256.New, prk)
+	mac.Write([]byte(info))
+	mac.Write([]byte{0x01})
+	return mac.Sum(nil)
+}
+
+// sessionKeys houses the keys derived from a handshake.
+type sessionKeys struct {
+	initiator []byte
+	responder []byte
+	sessionID [32]byte
+}
+
+// deriveKeys derives the session keys from the ECDH shared secret and the
+// ephemeral public keys of both sides for the provided network.
+func deriveKeys(shared, initiatorPub, responderPub []byte, network wire.CurrencyNet) sessionKeys {
+	var salt [len(saltTag) + 4]byte
+	copy(salt[:], saltTag)
+	binary.LittleEndian.PutUint32(salt[len(saltTag):], uint32(network))
+
+	// HKDF-Extract.
+	mac := hmac.New(sha256.New, salt[:])
+	mac.Write(shared)
+	mac.Write(initiatorPub)
+	mac.Write(responderPub)
+	prk := mac.Sum(nil)
+
+	keys := sessionKeys{
+		initiator: hkdf(prk, "initiator key"),
+		responder: hkdf(prk, "responder key"),
+	}
+	copy(keys.sessionID[:], hkdf(prk, "session id"))
+	return keys
+}
+
+// handshake performs the handshake over the provided connection.  The
+// initiator's ephemeral public key is provided when it has already been read
+// by the responder.
+func handshake(conn net.Conn, network wire.CurrencyNet, initiator bool, remotePub []byte) (*Conn, error) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	localPub := privKey.PubKey().SerializeCompressed()
+
+	// Exchange ephemeral public keys.  The initiator sends first.
+	if initiator {
+		if _, err := conn.Write(localPub); err != nil {
+			return nil, err
+		}
+		remotePub = make([]byte, pubKeySize)
+		if _, err := io.ReadFull(conn, remotePub); err != nil {
+			return nil, err
+		}
+	}
+	remoteKey, err := secp256k1.ParsePubKey(remotePub)
+	if err != nil || len(remotePub) != pubKeySize {
+		return nil, ErrInvalidPubKey
+	}
+	if !initiator {
+		if _, err := conn.Write(localPub); err != nil {
+			return nil, err
+		}
+	}
+
+	// Derive the session keys and assign them to the appropriate direction.
+	shared := secp256k1.GenerateSharedSecret(privKey, remoteKey)
+	initiatorPub, responderPub := localPub, remotePub
+	if !initiator {
+		initiatorPub, responderPub = remotePub, localPub
+	}
+	keys := deriveKeys(shared, initiatorPub, responderPub, network)
+	sendKey, recvKey := keys.initiator, keys.responder
+	if !initiator {
+		sendKey, recvKey = keys.responder, keys.initiator
+	}
+	send, err := newCipherState(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recv, err := newCipherState(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{
+		Conn:      conn,
+		sessionID: keys.sessionID,
+		send:      send,
+		recv:      recv,
+	}
+
+	// Exchange confirmation frames to ensure both sides derived the same keys.
+	// The initiator sends first.
+	readConfirm := func() error {
+		payload, err := c.readFrame()
+		if errors.Is(err, ErrAuthFailed) || errors.Is(err, ErrFrameTooLarge) ||
+			(err == nil && !bytes.Equal(payload, []byte(confirmTag))) {
+
+			return ErrHandshakeMismatch
+		}
+		return err
+	}
+	if !initiator {
+		if err := readConfirm(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.writeFrame([]byte(confirmTag)); err != nil {
+		return nil, err
+	}
+	if initiator {
+		if err := readConfirm(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Initiate performs the handshake for an outbound connection to a peer that
+// supports the encrypted transport on the provided network and returns the
+// resulting encrypted connection.
+//
+// Callers MUST NOT fall back to the plaintext protocol with a peer that is
+// known to support the encrypted transport when the handshake fails since that
+// would allow an attacker to downgrade the connection.
+func Initiate(conn net.Conn, network wire.CurrencyNet) (*Conn, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return nil, err
+	}
+	c, err := handshake(conn, network, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Accept performs the handshake for an inbound connection on the provided
+// network.  It returns a connection that replays the data that was already
+// read along with false when the remote peer is using the plaintext protocol
+// and the resulting encrypted connection along with true otherwise.
+func Accept(conn net.Conn, network wire.CurrencyNet) (net.Conn, bool, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return nil, false, err
+	}
+
+	// Plaintext connections always start with the network magic while
+	// encrypted connections start with a compressed public key which never
+	// has the same leading byte as any of the network magics.
+	prefix := make([]byte, pubKeySize)
+	if _, err := io.ReadFull(conn, prefix[:4]); err != nil {
+		return nil, false, err
+	}
+	if binary.LittleEndian.Uint32(prefix[:4]) == uint32(network) {
+		if err := conn.SetDeadline(time.Time{}); err != nil {
+			return nil, false, err
+		}
+		return &prefixConn{Conn: conn, prefix: prefix[:4]}, false, nil
+	}
+
+	if _, err := io.ReadFull(conn, prefix[4:]); err != nil {
+		return nil, false, err
+	}
+	c, err := handshake(conn, network, false, prefix)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package p2ptransport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// acceptResult houses the result of accepting a connection in a separate
+// goroutine.
+type acceptResult struct {
+	conn      net.Conn
+	encrypted bool
+	err       error
+}
+
+// acceptAsync accepts the provided connection on the passed network in a
+// separate goroutine and returns a channel that receives the result.
+func acceptAsync(conn net.Conn, network wire.CurrencyNet) chan acceptResult {
+	resultChan := make(chan acceptResult, 1)
+	go func() {
+		c, encrypted, err := Accept(conn, network)
+		resultChan <- acceptResult{conn: c, encrypted: encrypted, err: err}
+	}()
+	return resultChan
+}
+
+// TestHandshake ensures the handshake derives the same session on both sides
+// and that data larger than a single frame is transferred intact in both
+// directions.
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	resultChan := acceptAsync(server, wire.MainNet)
+	initiated, err := Initiate(client, wire.MainNet)
+	if err != nil {
+		t.Fatalf("unexpected initiate error: %v", err)
+	}
+	result := <-resultChan
+	if result.err != nil {
+		t.Fatalf("unexpected accept error: %v", result.err)
+	}
+	if !result.encrypted {
+		t.Fatal("accepted connection is not encrypted")
+	}
+	accepted := result.conn.(*Conn)
+	if initiated.SessionID() != accepted.SessionID() {
+		t.Fatalf("mismatched session ids -- initiator %x, responder %x",
+			initiated.SessionID(), accepted.SessionID())
+	}
+
+	// Ensure data is transferred intact in both directions.
+	transfer := func(from, to net.Conn, data []byte) {
+		t.Helper()
+		errChan := make(chan error, 1)
+		go func() {
+			_, err := from.Write(data)
+			errChan <- err
+		}()
+		got := make([]byte, len(data))
+		if _, err := io.ReadFull(to, got); err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+		if err := <-errChan; err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatal("mismatched data")
+		}
+	}
+	large := make([]byte, MaxFramePayload*2+100)
+	for i := range large {
+		large[i] = byte(i)
+	}
+	transfer(initiated, accepted, large)
+	transfer(accepted, initiated, []byte("pong"))
+	transfer(initiated, accepted, []byte("ping"))
+}
+
+// TestAcceptPlaintext ensures connections that use the plaintext protocol are
+// detected and the data that was read to detect them is replayed.
+func TestAcceptPlaintext(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	var buf bytes.Buffer
+	err := wire.WriteMessage(&buf, wire.NewMsgPing(1), wire.ProtocolVersion,
+		wire.MainNet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go client.Write(buf.Bytes())
+
+	conn, encrypted, err := Accept(server, wire.MainNet)
+	if err != nil {
+		t.Fatalf("unexpected accept error: %v", err)
+	}
+	if encrypted {
+		t.Fatal("plaintext connection detected as encrypted")
+	}
+	_, msg, _, err := wire.ReadMessageN(conn, wire.ProtocolVersion,
+		wire.MainNet)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if ping, ok := msg.(*wire.MsgPing); !ok || ping.Nonce != 1 {
+		t.Fatalf("unexpected message %v", msg)
+	}
+}
+
+// TestHandshakeErrors ensures handshakes with invalid public keys and with
+// peers on a different network fail with the expected errors.
+func TestHandshakeErrors(t *testing.T) {
+	t.Parallel()
+
+	// Ensure an invalid public key is rejected.
+	client, server := net.Pipe()
+	resultChan := acceptAsync(server, wire.MainNet)
+	invalidPubKey := bytes.Repeat([]byte{0xff}, pubKeySize)
+	invalidPubKey[0] = 0x02
+	go client.Write(invalidPubKey)
+	result := <-resultChan
+	if !errors.Is(result.err, ErrInvalidPubKey) {
+		t.Fatalf("unexpected error for invalid public key: %v", result.err)
+	}
+	client.Close()
+	server.Close()
+
+	// Ensure peers on different networks fail to complete the handshake.
+	client, server = net.Pipe()
+	resultChan = acceptAsync(server, wire.TestNet3)
+	initErrChan := make(chan error, 1)
+	go func() {
+		_, err := Initiate(client, wire.MainNet)
+		initErrChan <- err
+	}()
+	result = <-resultChan
+	if !errors.Is(result.err, ErrHandshakeMismatch) {
+		t.Fatalf("unexpected error for network mismatch: %v", result.err)
+	}
+	server.Close()
+	if err := <-initErrChan; err == nil {
+		t.Fatal("initiator did not fail the mismatched handshake")
+	}
+	client.Close()
+}
+
+// TestTamperedFrame ensures frames that were not encrypted with the session
+// keys are rejected.
+func TestTamperedFrame(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	resultChan := acceptAsync(server, wire.MainNet)
+	initiated, err := Initiate(client, wire.MainNet)
+	if err != nil {
+		t.Fatalf("unexpected initiate error: %v", err)
+	}
+	result := <-resultChan
+	if result.err != nil {
+		t.Fatalf("unexpected accept error: %v", result.err)
+	}
+
+	// Write garbage directly to the underlying connection.
+	go initiated.Conn.Write(make([]byte, frameLenSize))
+	_, err = result.conn.Read(make([]byte, 1))
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("unexpected error for tampered frame: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peertest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// steps concatenates the provided steps and groups of steps into a single
+// slice.
+func steps(groups ...[]Step) []Step {
+	var all []Step
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return all
+}
+
+// ConformanceScenarios returns the scenarios every node is expected to pass.
+// They cover the expected handshake along with the handling of handshake
+// variations, malformed messages, and peers that send data slowly.
+func ConformanceScenarios() []*Scenario {
+	oldVersion := NewVersion()
+	oldVersion.ProtocolVersion = 0
+
+	// corruptChecksum flips the bits of the first byte of the checksum in the
+	// message header.
+	corruptChecksum := func(data []byte) []byte {
+		data[wire.MessageHeaderSize-4] ^= 0xff
+		return data
+	}
+
+	// corruptMagic flips the bits of the first byte of the network magic in
+	// the message header.
+	corruptMagic := func(data []byte) []byte {
+		data[0] ^= 0xff
+		return data
+	}
+
+	// oversizedLength sets the payload length in the message header to the
+	// max possible value without sending the payload.
+	oversizedLength := func(data []byte) []byte {
+		const lenOffset = 4 + wire.CommandSize
+		for i := lenOffset; i < lenOffset+4; i++ {
+			data[i] = 0xff
+		}
+		return data[:wire.MessageHeaderSize]
+	}
+
+	return []*Scenario{{
+		Name: "standard handshake",
+		Steps: steps(
+			Handshake(NewVersion()),
+			[]Step{
+				Send(wire.NewMsgPing(1)),
+				ExpectMatch(wire.CmdPong, func(msg wire.Message) error {
+					if nonce := msg.(*wire.MsgPong).Nonce; nonce != 1 {
+						return fmt.Errorf("unexpected pong nonce %d",
+							nonce)
+					}
+					return nil
+				}),
+				ExpectConnected(time.Second),
+			},
+		),
+	}, {
+		Name: "verack before version",
+		Steps: []Step{
+			Send(wire.NewMsgVerAck()),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "non-version first message",
+		Steps: []Step{
+			Send(wire.NewMsgPing(1)),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "unsupported protocol version",
+		Steps: []Step{
+			Send(oldVersion),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "duplicate version",
+		Steps: steps(
+			Handshake(NewVersion()),
+			[]Step{
+				Send(NewVersion()),
+				ExpectDisconnect(),
+			},
+		),
+	}, {
+		Name: "malformed version payload",
+		Steps: []Step{
+			SendPayload(wire.CmdVersion, []byte{0x01, 0x02, 0x03}),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "bad checksum",
+		Steps: steps(
+			Handshake(NewVersion()),
+			[]Step{
+				SendCorrupted(wire.NewMsgPing(1), corruptChecksum),
+				ExpectDisconnect(),
+			},
+		),
+	}, {
+		Name: "wrong network",
+		Steps: []Step{
+			SendCorrupted(NewVersion(), corruptMagic),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "oversized payload length",
+		Steps: steps(
+			Handshake(NewVersion()),
+			[]Step{
+				SendCorrupted(wire.NewMsgPing(1), oversizedLength),
+				ExpectDisconnect(),
+			},
+		),
+	}, {
+		Name: "garbage instead of message",
+		Steps: []Step{
+			SendRaw([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")),
+			ExpectDisconnect(),
+		},
+	}, {
+		Name: "slow drip version",
+		Steps: []Step{
+			Drip(NewVersion(), 16, 20*time.Millisecond),
+			Expect(wire.CmdVersion),
+			Expect(wire.CmdVerAck),
+			Send(wire.NewMsgVerAck()),
+			ExpectConnected(time.Second),
+		},
+	}}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package peertest provides a harness for testing the conformance of nodes to the
+peer-to-peer protocol by impersonating a remote peer.
+
+The behavior of the remote peer is described declaratively by a Scenario, which
+is a sequence of steps such as sending messages, sending raw or corrupted data,
+slowly dripping a message to the node, and expecting the node to send specific
+messages, remain connected, or disconnect.  Scenarios are run against any node
+that is reachable over the network, such as a node launched by an integration
+test harness, so changes that harden the handling of the protocol are covered
+by regression tests.
+
+For example, the following scenario ensures a node disconnects peers that send
+a second version message after the handshake:
+
+	scenario := &peertest.Scenario{
+		Name: "duplicate version",
+		Steps: append(peertest.Handshake(peertest.NewVersion()),
+			peertest.Send(peertest.NewVersion()),
+			peertest.ExpectDisconnect()),
+	}
+	cfg := &peertest.Config{Net: wire.SimNet}
+	err := peertest.Run(ctx, cfg, nodeAddr, scenario)
+
+Pings from the node are answered automatically while a scenario runs so that
+the node does not disconnect the remote peer during long scenarios.
+
+The scenarios returned by ConformanceScenarios cover the handshake along with
+variations of it, malformed messages, and peers that send data slowly.  Every
+node is expected to pass them.
+*/
+package peertest
@@ -0,0 +1,211 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// defaultStepTimeout is the default maximum amount of time steps that wait
+	// on the node under test are allowed to take.
+	defaultStepTimeout = 10 * time.Second
+
+	// maxPendingMsgs is the maximum number of messages received from the node
+	// under test that are buffered while waiting for a step to consume them.
+	maxPendingMsgs = 1000
+)
+
+// Config houses the parameters used to impersonate a remote peer.
+type Config struct {
+	// Net is the network the node under test is running on.
+	Net wire.CurrencyNet
+
+	// ProtocolVersion is the protocol version used to encode and decode
+	// messages.  Defaults to wire.ProtocolVersion.
+	ProtocolVersion uint32
+
+	// StepTimeout is the maximum amount of time steps that wait on the node
+	// under test are allowed to take.  Defaults to 10 seconds.
+	StepTimeout time.Duration
+
+	// Dial is used to connect to the node under test.  Defaults to dialing
+	// TCP directly.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Scenario is a declarative description of the behavior of a remote peer in
+// terms of the steps it performs against the node under test.
+type Scenario struct {
+	// Name is a short human-readable description of the scenario.
+	Name string
+
+	// Steps are the steps the remote peer performs in order.
+	Steps []Step
+}
+
+// StepError describes a step of a scenario that failed.
+type StepError struct {
+	// Scenario is the name of the scenario.
+	Scenario string
+
+	// Step is the index of the step that failed along with its description.
+	Step     int
+	StepDesc string
+
+	// Err is the underlying reason the step failed.
+	Err error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *StepError) Error() string {
+	return fmt.Sprintf("scenario %q: step %d (%s): %v", e.Scenario, e.Step,
+		e.StepDesc, e.Err)
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// session houses the state of a connection to the node under test while a
+// scenario is run.
+type session struct {
+	cfg  *Config
+	conn net.Conn
+
+	// writeMtx protects writes to the connection since the reader responds
+	// to pings concurrently with the steps.
+	writeMtx sync.Mutex
+
+	// msgs receives the messages read from the node under test.  It is
+	// closed once the connection is closed and readErr is set.
+	msgs    chan wire.Message
+	readErr error
+}
+
+// read reads messages from the node under test until the connection is
+// closed.  Pings are answered automatically so that the node does not
+// disconnect the remote peer during long scenarios.  It must be run as a
+// goroutine.
+func (s *session) read() {
+	defer close(s.msgs)
+	for {
+		_, msg, _, err := wire.ReadMessageN(s.conn, s.cfg.ProtocolVersion,
+			s.cfg.Net)
+		if err != nil {
+			// Skip messages that are not understood, such as those for
+			// newer protocol versions, since the remainder of the stream
+			// is still intact.
+			var msgErr *wire.MessageError
+			if errors.As(err, &msgErr) {
+				continue
+			}
+			s.readErr = err
+			return
+		}
+		if ping, ok := msg.(*wire.MsgPing); ok {
+			_ = s.writeMsg(wire.NewMsgPong(ping.Nonce))
+		}
+		select {
+		case s.msgs <- msg:
+		default:
+			// Drop messages that are never consumed.
+		}
+	}
+}
+
+// write writes the provided raw bytes to the node under test.
+func (s *session) write(data []byte) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+	_, err := s.conn.Write(data)
+	return err
+}
+
+// writeMsg encodes and writes the provided message to the node under test.
+func (s *session) writeMsg(msg wire.Message) error {
+	s.writeMtx.Lock()
+	defer s.writeMtx.Unlock()
+	_, err := wire.WriteMessageN(s.conn, msg, s.cfg.ProtocolVersion,
+		s.cfg.Net)
+	return err
+}
+
+// Run connects to the node under test at the provided address and runs the
+// provided scenario against it.  A StepError is returned when a step fails.
+func Run(ctx context.Context, cfg *Config, addr string, scenario *Scenario) error {
+	dial := cfg.Dial
+	if dial == nil {
+		var dialer net.Dialer
+		dial = dialer.DialContext
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", addr, err)
+	}
+	return RunConn(ctx, cfg, conn, scenario)
+}
+
+// RunConn runs the provided scenario against the node under test over the
+// provided connection.  The connection is closed when the scenario completes.
+// A StepError is returned when a step fails.
+func RunConn(ctx context.Context, cfg *Config, conn net.Conn, scenario *Scenario) error {
+	// Apply defaults without modifying the caller's config.
+	sessCfg := *cfg
+	if sessCfg.ProtocolVersion == 0 {
+		sessCfg.ProtocolVersion = wire.ProtocolVersion
+	}
+	if sessCfg.StepTimeout == 0 {
+		sessCfg.StepTimeout = defaultStepTimeout
+	}
+
+	s := &session{
+		cfg:  &sessCfg,
+		conn: conn,
+		msgs: make(chan wire.Message, maxPendingMsgs),
+	}
+	go s.read()
+	defer func() {
+		conn.Close()
+		for range s.msgs {
+			// Wait for the reader to exit.
+		}
+	}()
+
+	// Close the connection when the context is canceled so any blocked
+	// reads and writes return.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for i, step := range scenario.Steps {
+		if err := step.run(ctx, s); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			return &StepError{
+				Scenario: scenario.Name,
+				Step:     i,
+				StepDesc: step.desc,
+				Err:      err,
+			}
+		}
+	}
+	return nil
+}
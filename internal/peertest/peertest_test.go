@@ -0,0 +1,129 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peertest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/peer/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// newTestNode starts a listener that serves each accepted connection with an
+// inbound peer from the peer package and returns its address.  The listener is
+// closed when the test completes.
+func newTestNode(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	peerCfg := &peer.Config{
+		UserAgentName:    "peertest",
+		UserAgentVersion: "1.0",
+		Net:              wire.SimNet,
+		IdleTimeout:      time.Minute,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			p := peer.NewInboundPeer(peerCfg)
+			p.AssociateConnection(conn)
+			t.Cleanup(p.Disconnect)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// TestConformanceScenarios ensures the conformance scenarios pass against the
+// peer package.
+func TestConformanceScenarios(t *testing.T) {
+	addr := newTestNode(t)
+	cfg := &Config{Net: wire.SimNet, StepTimeout: 5 * time.Second}
+	for _, scenario := range ConformanceScenarios() {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			t.Parallel()
+			err := Run(context.Background(), cfg, addr, scenario)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestStepErrors ensures failing steps are reported with the details of the
+// scenario and step that failed.
+func TestStepErrors(t *testing.T) {
+	addr := newTestNode(t)
+	cfg := &Config{Net: wire.SimNet, StepTimeout: 250 * time.Millisecond}
+
+	tests := []struct {
+		name     string
+		scenario *Scenario
+		wantStep int
+		wantErr  error
+	}{{
+		name: "expected message never sent",
+		scenario: &Scenario{
+			Name:  "no response to verack",
+			Steps: []Step{Send(wire.NewMsgVerAck()), Expect(wire.CmdVerAck)},
+		},
+		wantStep: 1,
+		wantErr:  ErrDisconnected,
+	}, {
+		name: "unexpected disconnect",
+		scenario: &Scenario{
+			Name: "connected after garbage",
+			Steps: []Step{
+				SendRaw([]byte{0x00, 0x01, 0x02, 0x03}),
+				Wait(10 * time.Millisecond),
+				SendPayload(wire.CmdVersion, nil),
+				ExpectConnected(time.Second),
+			},
+		},
+		wantStep: 3,
+		wantErr:  ErrDisconnected,
+	}, {
+		name: "expected disconnect never happens",
+		scenario: &Scenario{
+			Name:  "disconnect after handshake",
+			Steps: append(Handshake(NewVersion()), ExpectDisconnect()),
+		},
+		wantStep: 4,
+	}}
+
+	for _, test := range tests {
+		err := Run(context.Background(), cfg, addr, test.scenario)
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) {
+			t.Errorf("%q: unexpected error type %T (%v)", test.name, err,
+				err)
+			continue
+		}
+		if stepErr.Scenario != test.scenario.Name {
+			t.Errorf("%q: unexpected scenario -- got %q, want %q",
+				test.name, stepErr.Scenario, test.scenario.Name)
+		}
+		if stepErr.Step != test.wantStep {
+			t.Errorf("%q: unexpected failed step -- got %d, want %d",
+				test.name, stepErr.Step, test.wantStep)
+		}
+		if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peertest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// ErrDisconnected indicates the node under test disconnected the remote peer
+// while a step expected the connection to remain open.
+var ErrDisconnected = errors.New("disconnected by node")
+
+// Step is a single action performed by the remote peer or expectation about
+// the behavior of the node under test.
+type Step struct {
+	desc string
+	run  func(ctx context.Context, s *session) error
+}
+
+// String returns a human-readable description of the step.
+func (s Step) String() string {
+	return s.desc
+}
+
+// NewVersion returns a version message suitable for the remote peer to send
+// during the handshake with a random nonce and the current protocol version.
+// Callers may modify the returned message to exercise handshake variations.
+func NewVersion() *wire.MsgVersion {
+	na := wire.NewNetAddressIPPort(net.IPv4zero, 0, 0)
+	nonce, _ := wire.RandomUint64()
+	return wire.NewMsgVersion(na, na, nonce, 0)
+}
+
+// Send returns a step that sends the provided message to the node under test.
+func Send(msg wire.Message) Step {
+	return Step{
+		desc: fmt.Sprintf("send %s", msg.Command()),
+		run: func(ctx context.Context, s *session) error {
+			return s.writeMsg(msg)
+		},
+	}
+}
+
+// SendRaw returns a step that sends the provided raw bytes to the node under
+// test as is.  It is useful to send data that is not a valid message.
+func SendRaw(data []byte) Step {
+	return Step{
+		desc: fmt.Sprintf("send %d raw bytes", len(data)),
+		run: func(ctx context.Context, s *session) error {
+			return s.write(data)
+		},
+	}
+}
+
+// SendPayload returns a step that sends a message with the provided command
+// and arbitrary payload to the node under test.  The message header is valid
+// for the payload, so it is useful to send payloads that fail to decode.
+func SendPayload(command string, payload []byte) Step {
+	return Step{
+		desc: fmt.Sprintf("send %s with %d byte payload", command,
+			len(payload)),
+		run: func(ctx context.Context, s *session) error {
+			return s.write(encodeRaw(s.cfg.Net, command, payload))
+		},
+	}
+}
+
+// SendCorrupted returns a step that serializes the provided message and passes
+// the result, including the message header, to the provided function to
+// corrupt before it is sent to the node under test.
+func SendCorrupted(msg wire.Message, corrupt func(data []byte) []byte) Step {
+	return Step{
+		desc: fmt.Sprintf("send corrupted %s", msg.Command()),
+		run: func(ctx context.Context, s *session) error {
+			data, err := encodeMsg(s.cfg, msg)
+			if err != nil {
+				return err
+			}
+			return s.write(corrupt(data))
+		},
+	}
+}
+
+// Drip returns a step that sends the provided message to the node under test
+// slowly by splitting it into chunks of the provided size that are each sent
+// after waiting for the provided interval.
+func Drip(msg wire.Message, chunkSize int, interval time.Duration) Step {
+	return Step{
+		desc: fmt.Sprintf("drip %s in %d byte chunks every %v",
+			msg.Command(), chunkSize, interval),
+		run: func(ctx context.Context, s *session) error {
+			data, err := encodeMsg(s.cfg, msg)
+			if err != nil {
+				return err
+			}
+			for len(data) > 0 {
+				if err := sleep(ctx, interval); err != nil {
+					return err
+				}
+				n := chunkSize
+				if n > len(data) {
+					n = len(data)
+				}
+				if err := s.write(data[:n]); err != nil {
+					return err
+				}
+				data = data[n:]
+			}
+			return nil
+		},
+	}
+}
+
+// Expect returns a step that waits for the node under test to send a message
+// with the provided command.  Any other messages received in the mean time are
+// skipped.
+func Expect(command string) Step {
+	return ExpectMatch(command, nil)
+}
+
+// ExpectMatch returns a step that waits for the node under test to send a
+// message with the provided command for which the provided function, if any,
+// returns a nil error.  Any other messages received in the mean time are
+// skipped.  The error from the most recent message that did not match is
+// returned when the step times out.
+func ExpectMatch(command string, match func(msg wire.Message) error) Step {
+	return Step{
+		desc: fmt.Sprintf("expect %s", command),
+		run: func(ctx context.Context, s *session) error {
+			timeout := time.NewTimer(s.cfg.StepTimeout)
+			defer timeout.Stop()
+
+			var lastErr error
+			for {
+				select {
+				case msg, ok := <-s.msgs:
+					if !ok {
+						return fmt.Errorf("%w: %v", ErrDisconnected,
+							s.readErr)
+					}
+					if msg.Command() != command {
+						continue
+					}
+					if match == nil {
+						return nil
+					}
+					if lastErr = match(msg); lastErr == nil {
+						return nil
+					}
+
+				case <-timeout.C:
+					if lastErr != nil {
+						return fmt.Errorf("timeout waiting for "+
+							"matching %s: %w", command, lastErr)
+					}
+					return fmt.Errorf("timeout waiting for %s", command)
+
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		},
+	}
+}
+
+// ExpectNone returns a step that ensures the node under test does not send a
+// message with the provided command for the provided duration.
+func ExpectNone(command string, d time.Duration) Step {
+	return Step{
+		desc: fmt.Sprintf("expect no %s for %v", command, d),
+		run: func(ctx context.Context, s *session) error {
+			timeout := time.NewTimer(d)
+			defer timeout.Stop()
+			for {
+				select {
+				case msg, ok := <-s.msgs:
+					if !ok {
+						return fmt.Errorf("%w: %v", ErrDisconnected,
+							s.readErr)
+					}
+					if msg.Command() == command {
+						return fmt.Errorf("unexpected %s", command)
+					}
+
+				case <-timeout.C:
+					return nil
+
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		},
+	}
+}
+
+// ExpectConnected returns a step that ensures the node under test does not
+// disconnect the remote peer for the provided duration.
+func ExpectConnected(d time.Duration) Step {
+	return Step{
+		desc: fmt.Sprintf("expect connected for %v", d),
+		run: func(ctx context.Context, s *session) error {
+			timeout := time.NewTimer(d)
+			defer timeout.Stop()
+			for {
+				select {
+				case _, ok := <-s.msgs:
+					if !ok {
+						return fmt.Errorf("%w: %v", ErrDisconnected,
+							s.readErr)
+					}
+
+				case <-timeout.C:
+					return nil
+
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		},
+	}
+}
+
+// ExpectDisconnect returns a step that waits for the node under test to
+// disconnect the remote peer.
+func ExpectDisconnect() Step {
+	return Step{
+		desc: "expect disconnect",
+		run: func(ctx context.Context, s *session) error {
+			timeout := time.NewTimer(s.cfg.StepTimeout)
+			defer timeout.Stop()
+			for {
+				select {
+				case _, ok := <-s.msgs:
+					if !ok {
+						return nil
+					}
+
+				case <-timeout.C:
+					return errors.New("timeout waiting for disconnect")
+
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		},
+	}
+}
+
+// Wait returns a step that waits for the provided duration without performing
+// any action.
+func Wait(d time.Duration) Step {
+	return Step{
+		desc: fmt.Sprintf("wait %v", d),
+		run: func(ctx context.Context, s *session) error {
+			return sleep(ctx, d)
+		},
+	}
+}
+
+// Handshake returns the steps to perform a standard version handshake with the
+// node under test using the provided version message.
+func Handshake(version *wire.MsgVersion) []Step {
+	return []Step{
+		Send(version),
+		Expect(wire.CmdVersion),
+		Expect(wire.CmdVerAck),
+		Send(wire.NewMsgVerAck()),
+	}
+}
+
+// sleep waits for the provided duration or until the context is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// encodeMsg returns the serialized message including the message header.
+func encodeMsg(cfg *Config, msg wire.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := wire.WriteMessageN(&buf, msg, cfg.ProtocolVersion, cfg.Net)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeRaw returns a message with the provided command and payload including
+// a valid message header.
+func encodeRaw(net wire.CurrencyNet, command string, payload []byte) []byte {
+	var buf bytes.Buffer
+	_, _ = wire.WriteMessageN(&buf, &rawMsg{command, payload}, 0, net)
+	return buf.Bytes()
+}
+
+// rawMsg is a message with an arbitrary command and payload.  It is only used
+// to encode messages since the payload is not decoded.
+type rawMsg struct {
+	command string
+	payload []byte
+}
+
+// BtcDecode is not supported for raw messages.
+//
+// This is part of the wire.Message interface implementation.
+func (m *rawMsg) BtcDecode(r io.Reader, pver uint32) error {
+	return errors.New("raw messages can not be decoded")
+}
+
+// BtcEncode writes the raw payload.
+//
+// This is part of the wire.Message interface implementation.
+func (m *rawMsg) BtcEncode(w io.Writer, pver uint32) error {
+	_, err := w.Write(m.payload)
+	return err
+}
+
+// Command returns the raw command.
+//
+// This is part of the wire.Message interface implementation.
+func (m *rawMsg) Command() string {
+	return m.command
+}
+
+// MaxPayloadLength returns the length of the raw payload so it is always
+// allowed.
+//
+// This is part of the wire.Message interface implementation.
+func (m *rawMsg) MaxPayloadLength(pver uint32) uint32 {
+	return uint32(len(m.payload))
+}
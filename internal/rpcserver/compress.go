@@ -0,0 +1,128 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// compressMinSize is the minimum size of a response before it is
+	// compressed.  Smaller responses are sent uncompressed since compressing
+	// them saves little to no bandwidth at the expense of extra processing.
+	compressMinSize = 1024
+
+	// encodingGzip and encodingDeflate are the supported HTTP content codings
+	// for compressed responses.
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+var (
+	// gzipWriters and zlibWriters are pools of compressors that are reused
+	// across responses to avoid allocating their relatively large internal
+	// state for every response.
+	gzipWriters = sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}}
+	zlibWriters = sync.Pool{New: func() interface{} {
+		return zlib.NewWriter(nil)
+	}}
+)
+
+// negotiateEncoding returns the content coding to use for a response to a
+// request with the provided Accept-Encoding header value.  It returns the
+// supported coding with the highest quality value the client accepts,
+// preferring gzip when they are equal, or an empty string when the client does
+// not accept any of them.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64, 3)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			kv := strings.SplitN(param, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				parsed = 0
+			}
+			q = parsed
+		}
+		qualities[coding] = q
+	}
+
+	// Codings that are not explicitly listed are acceptable with the quality
+	// of the wildcard when one is present.
+	quality := func(coding string) float64 {
+		if q, ok := qualities[coding]; ok {
+			return q
+		}
+		return qualities["*"]
+	}
+	gzipQ, deflateQ := quality(encodingGzip), quality(encodingDeflate)
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return encodingGzip
+	case deflateQ > 0:
+		return encodingDeflate
+	}
+	return ""
+}
+
+// compressResponse compresses the provided response with the content coding
+// negotiated from the Accept-Encoding header of the provided request and sets
+// the associated response headers.  The response is returned unmodified when
+// it is too small to be worth compressing or the client does not accept any
+// of the supported codings.
+func compressResponse(r *http.Request, headers http.Header, resp []byte) ([]byte, error) {
+	if len(resp) < compressMinSize {
+		return resp, nil
+	}
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		return resp, nil
+	}
+
+	var buf bytes.Buffer
+	var w interface {
+		io.WriteCloser
+		Reset(io.Writer)
+	}
+	switch encoding {
+	case encodingGzip:
+		gw := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(gw)
+		w = gw
+	case encodingDeflate:
+		zw := zlibWriters.Get().(*zlib.Writer)
+		defer zlibWriters.Put(zw)
+		w = zw
+	}
+	w.Reset(&buf)
+	if _, err := w.Write(resp); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	headers.Set("Content-Encoding", encoding)
+	headers.Add("Vary", "Accept-Encoding")
+	return buf.Bytes(), nil
+}
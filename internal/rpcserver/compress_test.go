@@ -0,0 +1,222 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestNegotiateEncoding ensures the content coding negotiated from various
+// Accept-Encoding header values is the expected one.
+func TestNegotiateEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "empty", accept: "", want: ""},
+		{name: "identity only", accept: "identity", want: ""},
+		{name: "gzip", accept: "gzip", want: encodingGzip},
+		{name: "deflate", accept: "deflate", want: encodingDeflate},
+		{name: "both prefers gzip", accept: "deflate, gzip", want: encodingGzip},
+		{name: "case insensitive", accept: "GZIP", want: encodingGzip},
+		{name: "higher deflate quality", accept: "gzip;q=0.5, deflate",
+			want: encodingDeflate},
+		{name: "gzip not acceptable", accept: "gzip;q=0, deflate;q=0.1",
+			want: encodingDeflate},
+		{name: "none acceptable", accept: "gzip;q=0, deflate;q=0", want: ""},
+		{name: "wildcard", accept: "*", want: encodingGzip},
+		{name: "wildcard excludes gzip", accept: "gzip;q=0, *;q=0.5",
+			want: encodingDeflate},
+		{name: "invalid quality", accept: "gzip;q=abc", want: ""},
+		{name: "br only", accept: "br", want: ""},
+	}
+	for _, test := range tests {
+		if got := negotiateEncoding(test.accept); got != test.want {
+			t.Errorf("%s: unexpected encoding -- got %q, want %q", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestCompressResponse ensures responses are only compressed when they are
+// large enough and the client accepts a supported coding and that compressed
+// responses decompress to the original.
+func TestCompressResponse(t *testing.T) {
+	t.Parallel()
+
+	large := []byte(strings.Repeat(`{"result":"0123456789abcdef"}`, 100))
+	small := []byte(`{"result":1}`)
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		encodingGzip: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		},
+		encodingDeflate: func(r io.Reader) (io.Reader, error) {
+			return zlib.NewReader(r)
+		},
+	}
+
+	tests := []struct {
+		name     string
+		accept   string
+		resp     []byte
+		encoding string
+	}{
+		{name: "small gzip", accept: "gzip", resp: small},
+		{name: "large no accept", resp: large},
+		{name: "large gzip", accept: "gzip", resp: large,
+			encoding: encodingGzip},
+		{name: "large deflate", accept: "deflate", resp: large,
+			encoding: encodingDeflate},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if test.accept != "" {
+			r.Header.Set("Accept-Encoding", test.accept)
+		}
+		headers := make(http.Header)
+		got, err := compressResponse(r, headers, test.resp)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if encoding := headers.Get("Content-Encoding"); encoding !=
+			test.encoding {
+
+			t.Errorf("%s: unexpected content encoding -- got %q, want %q",
+				test.name, encoding, test.encoding)
+			continue
+		}
+		if test.encoding == "" {
+			if !bytes.Equal(got, test.resp) {
+				t.Errorf("%s: uncompressed response was modified", test.name)
+			}
+			continue
+		}
+		if len(got) >= len(test.resp) {
+			t.Errorf("%s: response was not compressed", test.name)
+		}
+		dr, err := decoders[test.encoding](bytes.NewReader(got))
+		if err != nil {
+			t.Errorf("%s: unable to create decoder: %v", test.name, err)
+			continue
+		}
+		decompressed, err := io.ReadAll(dr)
+		if err != nil {
+			t.Errorf("%s: unable to decompress: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(decompressed, test.resp) {
+			t.Errorf("%s: decompressed response does not match", test.name)
+		}
+	}
+}
+
+// TestCompressedRPCResponse ensures JSON-RPC replies sent via HTTP POST are
+// compressed when the client accepts it.
+func TestCompressedRPCResponse(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCMaxClients = 10
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	// Request the help for all commands since it is large enough to be
+	// compressed.  The transport transparently decompresses gzip responses
+	// when it added the Accept-Encoding header itself, so disable that to
+	// observe the raw response.
+	body := `{"jsonrpc":"1.0","id":1,"method":"help","params":[]}`
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL,
+		strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unable to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	client := &http.Client{Transport: &http.Transport{
+		DisableCompression: true,
+	}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error posting request: %v", err)
+	}
+	defer resp.Body.Close()
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("unexpected content encoding -- got %q, want %q", encoding,
+			"gzip")
+	}
+	gr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to create gzip reader: %v", err)
+	}
+	reply, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("unable to decompress reply: %v", err)
+	}
+	if !bytes.HasPrefix(reply, []byte(`{"jsonrpc":"1.0","result":"`)) ||
+		!bytes.HasSuffix(reply, []byte("}\n")) {
+
+		t.Fatalf("unexpected decompressed reply: %.100s", reply)
+	}
+}
+
+// TestWebsocketDecompressedReadLimit ensures the websocket read limits apply to
+// the decompressed messages so clients are unable to send messages that use
+// per-message compression to decompress to sizes that exceed the limits.
+func TestWebsocketDecompressedReadLimit(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCMaxClients = 10
+	cfg.RPCMaxWebsockets = 10
+	cfg.RPCUser, cfg.RPCPass = "user", "pass"
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.ntfnMgr.Run(ctx)
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	// Connect without credentials so the unauthenticated read limit applies.
+	dialer := websocket.Dialer{EnableCompression: true}
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	conn, _, err := dialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("unable to dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	// Send a message that compresses to well below the read limit, but
+	// exceeds it once decompressed.
+	msg := bytes.Repeat([]byte(" "), websocketReadLimitUnauthenticated*64)
+	if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+		t.Fatalf("unable to write message: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("unexpected error for oversized message -- got %v, want "+
+			"close %d", err, websocket.CloseMessageTooBig)
+	}
+}
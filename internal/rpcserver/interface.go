@@ -6,18 +6,23 @@ package rpcserver
 
 import (
 	"context"
+	"io"
 	"net"
 	"time"
 
 	"github.com/decred/dcrd/addrmgr/v2"
 	"github.com/decred/dcrd/blockchain/stake/v5"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/gcs/v4"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
@@ -110,6 +115,10 @@ type ConnManager interface {
 	// network for all peers.
 	NetTotals() (uint64, uint64)
 
+	// NetTotalsPerMsg returns the bytes received and sent across the network
+	// for all peers keyed by message command.
+	NetTotalsPerMsg() (map[string]uint64, map[string]uint64)
+
 	// ConnectedPeers returns an array consisting of all connected peers.
 	ConnectedPeers() []Peer
 
@@ -135,6 +144,20 @@ type ConnManager interface {
 
 	// Lookup defines the DNS lookup function to be used.
 	Lookup(host string) ([]net.IP, error)
+
+	// Ban bans the provided subnet until the given expiration time and
+	// disconnects all connected peers within it.
+	Ban(subnet *net.IPNet, expires time.Time) error
+
+	// Unban removes the ban for the provided subnet.  Attempting to remove a
+	// subnet that is not banned will return an error.
+	Unban(subnet *net.IPNet) error
+
+	// BannedSubnets returns all unexpired bans.
+	BannedSubnets() []banmgr.Entry
+
+	// ClearBanned removes all bans.
+	ClearBanned() error
 }
 
 // SyncManager represents a sync manager for use with the RPC server.
@@ -156,6 +179,9 @@ type SyncManager interface {
 	// SyncHeight returns latest known block being synced to.
 	SyncHeight() int64
 
+	// SyncStatus returns the current status of the chain sync process.
+	SyncStatus() netsync.SyncStatus
+
 	// ProcessTransaction relays the provided transaction validation and
 	// insertion into the memory pool.
 	ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool, allowHighFees bool,
@@ -260,6 +286,10 @@ type Chain interface {
 	// rule change activation interval.
 	CountVoteVersion(version uint32) (uint32, error)
 
+	// DumpUtxoSet writes a snapshot of the utxo set as of the current main
+	// chain tip to the provided writer and returns information about it.
+	DumpUtxoSet(w io.Writer) (*blockchain.UtxoSnapshotInfo, error)
+
 	// EstimateNextStakeDifficulty estimates the next stake difficulty by pretending
 	// the provided number of tickets will be purchased in the remainder of the
 	// interval unless the flag to use max tickets is set in which case it will use
@@ -267,6 +297,11 @@ type Chain interface {
 	// the interval.
 	EstimateNextStakeDifficulty(hash *chainhash.Hash, newTickets int64, useMaxTickets bool) (int64, error)
 
+	// FetchSpentOutputs returns the details of all transaction outputs spent by
+	// the transactions in the passed block keyed by the outpoint of the spent
+	// output.  An error is returned for blocks that are not in the main chain.
+	FetchSpentOutputs(block *dcrutil.Block) (map[wire.OutPoint]blockchain.SpentOutput, error)
+
 	// FetchUtxoEntry loads and returns the requested unspent transaction output
 	// from the point of view of the main chain tip.
 	//
@@ -282,6 +317,14 @@ type Chain interface {
 	// FetchUtxoStats returns statistics on the current utxo set.
 	FetchUtxoStats() (*blockchain.UtxoStats, error)
 
+	// ForEachUtxo invokes the provided function with every unspent transaction
+	// output in the utxo set ordered by outpoint.  Iteration stops as soon as
+	// the function returns an error and that error is returned.
+	//
+	// The entries provided to the function are NOT safe to retain or modify
+	// after it returns.
+	ForEachUtxo(fn func(outpoint wire.OutPoint, entry UtxoEntry) error) error
+
 	// GetStakeVersions returns a cooked array of StakeVersions.  We do this in
 	// order to not bloat memory by returning raw blocks.
 	GetStakeVersions(hash *chainhash.Hash, count int32) ([]blockchain.StakeVersions, error)
@@ -304,6 +347,11 @@ type Chain interface {
 	// chain.
 	HeaderByHeight(height int64) (wire.BlockHeader, error)
 
+	// HeightByMedianTime returns the height of the first block in the main
+	// chain with a past median time at or after the provided time.  An error
+	// is returned when there is no such block.
+	HeightByMedianTime(t time.Time) (int64, error)
+
 	// HeightRange returns a range of block hashes for the given start and end
 	// heights.  It is inclusive of the start height and exclusive of the end
 	// height.  In other words, it is the half open range [startHeight, endHeight).
@@ -311,6 +359,11 @@ type Chain interface {
 	// The end height will be limited to the current main chain height.
 	HeightRange(startHeight, endHeight int64) ([]chainhash.Hash, error)
 
+	// HeightRangeByMedianTime returns the heights of the blocks in the main
+	// chain with a past median time in the half open range [start, end) as the
+	// half open range of heights [startHeight, endHeight).
+	HeightRangeByMedianTime(start, end time.Time) (int64, int64)
+
 	// IsCurrent returns whether or not the chain believes it is current.  Several
 	// factors are used to guess, but the key factors that allow the chain to
 	// believe it is current are:
@@ -456,6 +509,11 @@ type LogManager interface {
 	// the levels accordingly.  An appropriate error must be returned if anything
 	// is invalid.
 	ParseAndSetDebugLevels(debugLevel string) error
+
+	// DebugLevels returns the current logging level of each supported
+	// subsystem keyed by the subsystem.  The levels must use the same names
+	// that are accepted by ParseAndSetDebugLevels.
+	DebugLevels() map[string]string
 }
 
 // SanityChecker represents a block sanity checker for use with the RPC server.
@@ -545,6 +603,13 @@ type FiltererV2 interface {
 	// An error of type blockchain.ErrNoFilter must be returned when the filter
 	// for the given block hash does not exist.
 	FilterByBlockHash(hash *chainhash.Hash) (*gcs.FilterV2, *blockchain.HeaderProof, error)
+
+	// MainChainFilters invokes the provided function with the header, version
+	// 2 GCS filter, and header commitment inclusion proof of each block in the
+	// main chain with a height in the provided inclusive range in order of
+	// increasing height.  The range is truncated to the current tip of the main
+	// chain.
+	MainChainFilters(ctx context.Context, startHeight, endHeight int64, fn func(*blockchain.MainChainFilter) error) error
 }
 
 // ExistsAddresser represents a source of exists address methods for the RPC
@@ -593,6 +658,26 @@ type TxMempooler interface {
 	// only.
 	VerboseTxDescs() []*mempool.VerboseTxDesc
 
+	// VerboseTxDesc returns a verbose descriptor for the transaction with
+	// the provided hash in the main pool.  The second return value is false
+	// when the transaction is not in the main pool.  The descriptor must be
+	// treated as read only.
+	VerboseTxDesc(txHash *chainhash.Hash) (*mempool.VerboseTxDesc, bool)
+
+	// Ancestors returns the descriptors of all transactions in the main pool
+	// that the transaction with the provided hash directly or indirectly
+	// spends outputs of.  The second return value is false when the
+	// transaction is not in the main pool.  The descriptors must be treated
+	// as read only.
+	Ancestors(txHash *chainhash.Hash) ([]*mempool.TxDesc, bool)
+
+	// Descendants returns the descriptors of all transactions in the main
+	// pool that directly or indirectly spend outputs of the transaction with
+	// the provided hash.  The second return value is false when the
+	// transaction is not in the main pool.  The descriptors must be treated
+	// as read only.
+	Descendants(txHash *chainhash.Hash) ([]*mempool.TxDesc, bool)
+
 	// Count returns the number of transactions in the main pool. It does
 	// not include the orphan pool.
 	Count() int
@@ -605,6 +690,18 @@ type TxMempooler interface {
 	// TSpendHashes returns the hashes of the treasury spend transactions
 	// currently in the mempool.
 	TSpendHashes() []chainhash.Hash
+
+	// RejectedTx returns details about why the transaction with the provided
+	// hash was rejected when it was recently rejected by the mempool.  The
+	// second return value is false when there is no record of the transaction
+	// being rejected.
+	RejectedTx(hash *chainhash.Hash) (*mempool.RejectedTx, bool)
+
+	// PrioritiseTransaction adjusts the fee of the transaction with the
+	// provided hash by the given delta when prioritizing it for inclusion in
+	// block templates and returns the resulting total delta for the
+	// transaction.
+	PrioritiseTransaction(hash *chainhash.Hash, feeDelta int64) int64
 }
 
 // TxIndexer provides an interface for retrieving details for a given
@@ -629,6 +726,51 @@ type TxIndexer interface {
 	Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, error)
 }
 
+// AddrIndexer provides an interface for retrieving the transactions that
+// involve a given address.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type AddrIndexer interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+
+	// WaitForSync subscribes clients for the next index sync update.
+	WaitForSync() chan bool
+
+	// Transactions returns the main chain transactions that involve the
+	// provided address along with the inputs and outputs through which the
+	// address is involved.  The provided number of transactions are skipped
+	// and at most count transactions are returned in chain order, or in
+	// reverse chain order when the reverse flag is set.
+	Transactions(addr stdaddr.Address, skip, count int, reverse bool) ([]indexers.AddrIndexTx, error)
+}
+
+// SpentIndexer provides an interface for retrieving the transaction input that
+// spends a given transaction output.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type SpentIndexer interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+
+	// WaitForSync subscribes clients for the next index sync update.
+	WaitForSync() chan bool
+
+	// SpentInfo returns details about the transaction input that spends the
+	// provided outpoint in the main chain.  When there is no entry for the
+	// provided outpoint, nil must be returned for both the entry and the
+	// error.
+	SpentInfo(outpoint *wire.OutPoint) (*indexers.SpentIndexEntry, error)
+}
+
 // NtfnManager provides an interface for processing and sending chain
 // notifications.
 //
@@ -653,6 +795,10 @@ type NtfnManager interface {
 	// the manager for processing.
 	NotifyReorganization(rd *blockchain.ReorganizationNtfnsData)
 
+	// NotifyTLSCertRenewed passes a newly generated PEM-encoded TLS
+	// certificate and its expiration time to the manager for processing.
+	NotifyTLSCertRenewed(cert []byte, validUntil time.Time)
+
 	// NotifyWinningTickets passes newly winning tickets to the manager for
 	// processing.
 	NotifyWinningTickets(wtnd *WinningTicketsNtfnData)
@@ -665,6 +811,10 @@ type NtfnManager interface {
 	// manager for processing.
 	NotifyMempoolTx(tx *dcrutil.Tx, isNew bool)
 
+	// NotifyMempoolEvent passes a transaction being added to or removed from
+	// the mempool to the manager for processing.
+	NotifyMempoolEvent(event *mempool.TxEvent)
+
 	// NumClients returns the number of clients actively being served.
 	NumClients() int
 
@@ -672,6 +822,11 @@ type NtfnManager interface {
 	// websocket client.
 	RegisterBlockUpdates(wsc *wsClient)
 
+	// RegisterBlockUpdatesSince requests block update notifications to the
+	// passed websocket client after replaying the notifications for all blocks
+	// connected to the main chain after the block with the provided hash.
+	RegisterBlockUpdatesSince(wsc *wsClient, since *chainhash.Hash)
+
 	// UnregisterBlockUpdates removes block update notifications for the passed
 	// websocket client.
 	UnregisterBlockUpdates(wsc *wsClient)
@@ -716,6 +871,32 @@ type NtfnManager interface {
 	// client when new transaction are added to the memory pool.
 	UnregisterNewMempoolTxsUpdates(wsc *wsClient)
 
+	// RegisterMempoolEvents requests notifications to the passed websocket
+	// client when transactions are added to or removed from the memory pool.
+	RegisterMempoolEvents(wsc *wsClient)
+
+	// UnregisterMempoolEvents removes notifications to the passed websocket
+	// client when transactions are added to or removed from the memory pool.
+	UnregisterMempoolEvents(wsc *wsClient)
+
+	// RegisterTicketEvents requests notifications to the passed websocket
+	// client when tickets are purchased, mature, vote, are missed, or are
+	// revoked.
+	RegisterTicketEvents(wsc *wsClient)
+
+	// UnregisterTicketEvents removes notifications to the passed websocket
+	// client when tickets are purchased, mature, vote, are missed, or are
+	// revoked.
+	UnregisterTicketEvents(wsc *wsClient)
+
+	// RegisterTemplateDeltas requests block template delta notifications to
+	// the passed websocket client.
+	RegisterTemplateDeltas(wsc *wsClient)
+
+	// UnregisterTemplateDeltas removes block template delta notifications for
+	// the passed websocket client.
+	UnregisterTemplateDeltas(wsc *wsClient)
+
 	// AddClient adds the passed websocket client to the notification manager.
 	AddClient(wsc *wsClient)
 
@@ -729,6 +910,31 @@ type NtfnManager interface {
 	Run(ctx context.Context)
 }
 
+// MetricsSource represents a source of additional metrics that are served by
+// the metrics endpoint along with the RPC metrics.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type MetricsSource interface {
+	// WriteMetrics writes the current metrics to the provided writer in the
+	// Prometheus text exposition format.
+	WriteMetrics(w io.Writer) error
+}
+
+// ChainStateBackuper represents a source of consistent copies of the chain
+// state that are written while the node continues to run.
+//
+// The interface contract requires that all of these methods are safe for
+// concurrent access.
+type ChainStateBackuper interface {
+	// BackupChainState writes a consistent copy of the block and UTXO
+	// databases to the provided directory, which must not already exist, in
+	// the same layout as the network data directory.  The provided function,
+	// which may be nil, is invoked periodically with the progress of the
+	// backup.  The backup is aborted when the provided context is canceled.
+	BackupChainState(ctx context.Context, dir string, progress func(database.BackupProgress)) error
+}
+
 // RPCHelpCacher represents a cacher that provides help and usage text for RPC
 // server commands and caches the results.
 //
@@ -740,4 +946,8 @@ type RPCHelpCacher interface {
 
 	// RPCUsage returns one-line usage for all supported RPC commands.
 	RPCUsage(includeWebsockets bool) (string, error)
+
+	// OpenRPCDocument returns an OpenRPC document that describes all
+	// supported RPC commands.
+	OpenRPCDocument() (*dcrjson.OpenRPCDocument, error)
 }
@@ -0,0 +1,530 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// restPathPrefix is the path prefix for all REST endpoints.
+	restPathPrefix = "/rest/"
+
+	// maxRESTHeadersCount is the maximum number of headers that may be
+	// requested from the REST headers endpoint in a single request.
+	maxRESTHeadersCount = 2000
+
+	// maxRESTGetUtxosOutpoints is the maximum number of outpoints that may be
+	// requested from the REST getutxos endpoint in a single request.
+	maxRESTGetUtxosOutpoints = 15
+)
+
+// restFormat identifies the encoding of a REST response.
+type restFormat int
+
+// These constants define the supported REST response encodings.
+const (
+	restFormatBinary restFormat = iota
+	restFormatHex
+	restFormatJSON
+)
+
+// restFormats maps the supported REST path suffixes to their associated
+// response encodings.
+var restFormats = map[string]restFormat{
+	"bin":  restFormatBinary,
+	"hex":  restFormatHex,
+	"json": restFormatJSON,
+}
+
+// restError is an error that is returned to REST clients along with the HTTP
+// status code that describes it.
+type restError struct {
+	status      int
+	description string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e restError) Error() string {
+	return e.description
+}
+
+// restErrorf creates a restError with the provided HTTP status code and
+// formatted description.
+func restErrorf(status int, format string, a ...interface{}) restError {
+	return restError{status: status, description: fmt.Sprintf(format, a...)}
+}
+
+// splitRESTFormat splits the provided path into the portion that precedes the
+// format suffix and the format identified by the suffix.
+func splitRESTFormat(path string) (string, restFormat, error) {
+	idx := strings.LastIndexByte(path, '.')
+	if idx == -1 {
+		return "", 0, restErrorf(http.StatusBadRequest, "output format "+
+			"not specified (available: .bin, .hex, .json)")
+	}
+	format, ok := restFormats[path[idx+1:]]
+	if !ok {
+		return "", 0, restErrorf(http.StatusBadRequest, "unsupported "+
+			"output format %q (available: .bin, .hex, .json)", path[idx+1:])
+	}
+	return path[:idx], format, nil
+}
+
+// parseRESTHash parses the provided hex-encoded hash for use with a REST
+// endpoint.
+func parseRESTHash(s string) (*chainhash.Hash, error) {
+	if len(s) != chainhash.MaxHashStringSize {
+		return nil, restErrorf(http.StatusBadRequest, "invalid hash: %q", s)
+	}
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return nil, restErrorf(http.StatusBadRequest, "invalid hash: %q", s)
+	}
+	return hash, nil
+}
+
+// restResult houses the binary and JSON representations of a REST response.
+// The hex representation is derived from the binary one.
+type restResult struct {
+	binary []byte
+	json   interface{}
+}
+
+// restCheckToken returns whether or not the provided request carries the
+// configured REST token.  It always succeeds when no token is configured.
+func (s *Server) restCheckToken(r *http.Request) bool {
+	if s.cfg.RESTToken == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	authhdr := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authhdr, prefix) {
+		return false
+	}
+	token := []byte(authhdr[len(prefix):])
+	return subtle.ConstantTimeCompare(token, []byte(s.cfg.RESTToken)) == 1
+}
+
+// handleREST is the HTTP handler for all REST endpoints.  REST requests do not
+// require the RPC credentials, but they are subject to the RPC client limits,
+// including the per-IP request limits, and must provide the REST token via a
+// bearer authorization header when one is configured.
+func (s *Server) handleREST(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Connection", "close")
+	r.Close = true
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method not allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Limit the number of connections to max allowed.
+	if s.limitConnections(w, r.RemoteAddr) {
+		return
+	}
+
+	// Keep track of the number of connected clients.
+	s.incrementClients()
+	defer s.decrementClients()
+
+	if !s.restCheckToken(r) {
+		log.Warnf("REST authentication failure from %s", r.RemoteAddr)
+		w.Header().Add("WWW-Authenticate", `Bearer realm="dcrd REST"`)
+		http.Error(w, "401 Unauthorized.", http.StatusUnauthorized)
+		return
+	}
+
+	// Reject the request when it would exceed the per-IP request limits.
+	// REST clients are not associated with a user, so they are not subject
+	// to the per-user limits.
+	release, limitErr := s.limiter.acquire(openAuthUser, r.RemoteAddr)
+	if limitErr != nil {
+		log.Debugf("Rejected REST request %s from %s: %v", r.URL.Path,
+			r.RemoteAddr, limitErr.Message)
+		http.Error(w, "429 "+limitErr.Message, http.StatusTooManyRequests)
+		return
+	}
+	defer release()
+
+	var result *restResult
+	path, format, err := splitRESTFormat(strings.TrimPrefix(r.URL.Path,
+		restPathPrefix))
+	if err == nil {
+		endpoint, args := path, ""
+		if idx := strings.IndexByte(path, '/'); idx != -1 {
+			endpoint, args = path[:idx], path[idx+1:]
+		}
+		switch endpoint {
+		case "block":
+			result, err = s.restBlock(r.Context(), args, format)
+		case "headers":
+			result, err = s.restHeaders(r.Context(), args, format)
+		case "getutxos":
+			result, err = s.restGetUtxos(args, format)
+		default:
+			err = restErrorf(http.StatusNotFound, "unknown REST "+
+				"endpoint %q", endpoint)
+		}
+	}
+	if err != nil {
+		var rErr restError
+		if !errors.As(err, &rErr) {
+			log.Errorf("Unhandled REST error: %v", err)
+			rErr = restErrorf(http.StatusInternalServerError, "%v", err)
+		}
+		http.Error(w, rErr.description, rErr.status)
+		return
+	}
+
+	var reply []byte
+	switch format {
+	case restFormatBinary:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		reply = result.binary
+	case restFormatHex:
+		w.Header().Set("Content-Type", "text/plain")
+		reply = make([]byte, hex.EncodedLen(len(result.binary))+1)
+		hex.Encode(reply, result.binary)
+		reply[len(reply)-1] = '\n'
+	case restFormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		reply, err = json.Marshal(result.json)
+		if err != nil {
+			log.Errorf("Failed to marshal REST reply: %v", err)
+			http.Error(w, "500 Internal server error.",
+				http.StatusInternalServerError)
+			return
+		}
+		reply = append(reply, '\n')
+	}
+	reply, err = compressResponse(r, w.Header(), reply)
+	if err != nil {
+		log.Errorf("Failed to compress REST reply: %v", err)
+		http.Error(w, "500 Internal server error.",
+			http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(reply)))
+	if _, err := w.Write(reply); err != nil {
+		log.Errorf("Failed to write REST reply: %v", err)
+	}
+}
+
+// restHandlerErr converts an error returned by an RPC handler that is invoked
+// to produce the JSON representation of a REST response into a REST error.
+func restHandlerErr(err error) error {
+	var rpcErr *dcrjson.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == dcrjson.ErrRPCBlockNotFound {
+		return restErrorf(http.StatusNotFound, "%s", rpcErr.Message)
+	}
+	return err
+}
+
+// restBlock returns the block identified by the hash in the provided path
+// arguments.  The path arguments must be of the form <hash>.
+//
+// The binary representation is the serialized block and the JSON
+// representation is the same as the verbose result of the getblock RPC with
+// verbose transactions.
+func (s *Server) restBlock(ctx context.Context, args string, format restFormat) (*restResult, error) {
+	hash, err := parseRESTHash(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == restFormatJSON {
+		result, err := handleGetBlock(ctx, s, &types.GetBlockCmd{
+			Hash:      hash.String(),
+			Verbose:   dcrjson.Bool(true),
+			VerboseTx: dcrjson.Bool(true),
+		})
+		if err != nil {
+			return nil, restHandlerErr(err)
+		}
+		return &restResult{json: result}, nil
+	}
+
+	blk, err := s.cfg.Chain.BlockByHash(hash)
+	if err != nil {
+		return nil, restErrorf(http.StatusNotFound, "block %s not found",
+			hash)
+	}
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &restResult{binary: blkBytes}, nil
+}
+
+// restHeaders returns up to the requested number of main chain block headers
+// starting with the header of the block identified by the hash in the provided
+// path arguments.  The path arguments must be of the form <count>/<hash>.
+//
+// The binary representation is the concatenation of the serialized headers and
+// the JSON representation is an array of verbose getblockheader RPC results.
+func (s *Server) restHeaders(ctx context.Context, args string, format restFormat) (*restResult, error) {
+	parts := strings.Split(args, "/")
+	if len(parts) != 2 {
+		return nil, restErrorf(http.StatusBadRequest, "invalid URI "+
+			"format: expected /rest/headers/<count>/<hash>")
+	}
+	count, err := strconv.Atoi(parts[0])
+	if err != nil || count < 1 || count > maxRESTHeadersCount {
+		return nil, restErrorf(http.StatusBadRequest, "header count must "+
+			"be between 1 and %d: %q", maxRESTHeadersCount, parts[0])
+	}
+	hash, err := parseRESTHash(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	chain := s.cfg.Chain
+	height, err := chain.BlockHeightByHash(hash)
+	if err != nil {
+		return nil, restErrorf(http.StatusNotFound, "block %s not found "+
+			"in the main chain", hash)
+	}
+	endHeight := height + int64(count) - 1
+	if best := chain.BestSnapshot(); endHeight > best.Height {
+		endHeight = best.Height
+	}
+
+	headers := make([]wire.BlockHeader, 0, endHeight-height+1)
+	for h := height; h <= endHeight; h++ {
+		header, err := chain.HeaderByHeight(h)
+		if err != nil {
+			// The chain might have been reorganized while the headers were
+			// being loaded, so stop at the first missing header.
+			break
+		}
+		headers = append(headers, header)
+	}
+
+	if format == restFormatJSON {
+		results := make([]interface{}, 0, len(headers))
+		for i := range headers {
+			result, err := handleGetBlockHeader(ctx, s,
+				&types.GetBlockHeaderCmd{
+					Hash:    headers[i].BlockHash().String(),
+					Verbose: dcrjson.Bool(true),
+				})
+			if err != nil {
+				return nil, restHandlerErr(err)
+			}
+			results = append(results, result)
+		}
+		return &restResult{json: results}, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(headers) * wire.MaxBlockHeaderPayload)
+	for i := range headers {
+		if err := headers[i].Serialize(&buf); err != nil {
+			return nil, err
+		}
+	}
+	return &restResult{binary: buf.Bytes()}, nil
+}
+
+// restUtxo models an unspent transaction output in the JSON representation of
+// a REST getutxos response.
+type restUtxo struct {
+	Height        int64   `json:"height"`
+	Value         float64 `json:"value"`
+	ScriptVersion uint16  `json:"scriptversion"`
+	PkScript      string  `json:"pkscript"`
+}
+
+// restGetUtxosResult models the JSON representation of a REST getutxos
+// response.
+type restGetUtxosResult struct {
+	ChainHeight int64      `json:"chainheight"`
+	ChainTip    string     `json:"chaintiphash"`
+	Bitmap      string     `json:"bitmap"`
+	Utxos       []restUtxo `json:"utxos"`
+}
+
+// parseRESTOutpoint parses an outpoint of the form <txid>-<index>[-<tree>]
+// for use with the REST getutxos endpoint.  The tree defaults to the regular
+// transaction tree when it is not specified.
+func parseRESTOutpoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, restErrorf(http.StatusBadRequest, "invalid outpoint "+
+			"%q: expected <txid>-<index>[-<tree>]", s)
+	}
+	hash, err := parseRESTHash(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, restErrorf(http.StatusBadRequest, "invalid output "+
+			"index in outpoint %q", s)
+	}
+	tree := wire.TxTreeRegular
+	if len(parts) == 3 {
+		t, err := strconv.ParseInt(parts[2], 10, 8)
+		if err != nil || (int8(t) != wire.TxTreeRegular &&
+			int8(t) != wire.TxTreeStake) {
+
+			return nil, restErrorf(http.StatusBadRequest, "invalid tree "+
+				"in outpoint %q", s)
+		}
+		tree = int8(t)
+	}
+	return wire.NewOutPoint(hash, uint32(index), tree), nil
+}
+
+// restGetUtxos returns the unspent transaction outputs for the outpoints in
+// the provided path arguments.  The path arguments must be of the form
+// [checkmempool/]<outpoint>[/<outpoint>...] where each outpoint is of the form
+// <txid>-<index>[-<tree>].
+//
+// When checkmempool is specified, outputs of transactions in the memory pool
+// are also considered unspent and are reported with a height of zero.  Note
+// that outputs are not checked against spends by memory pool transactions.
+//
+// The result includes the current chain height and tip along with a bitmap
+// that identifies which of the requested outpoints are unspent, where the
+// least significant bit of the first byte corresponds to the first outpoint,
+// followed by the unspent outputs in the order they were requested.
+//
+// The binary representation is:
+//
+//	chain height (uint32 LE) || chain tip hash (32 bytes) ||
+//	bitmap (varbytes) || number of utxos (varint) || utxos
+//
+// where each utxo is:
+//
+//	height (uint32 LE) || value (int64 LE) || script version (uint16 LE) ||
+//	pkscript (varbytes)
+func (s *Server) restGetUtxos(args string, format restFormat) (*restResult, error) {
+	parts := strings.Split(args, "/")
+	checkMempool := len(parts) > 0 && parts[0] == "checkmempool"
+	if checkMempool {
+		parts = parts[1:]
+	}
+	if len(parts) == 0 || (len(parts) == 1 && parts[0] == "") {
+		return nil, restErrorf(http.StatusBadRequest, "no outpoints "+
+			"specified")
+	}
+	if len(parts) > maxRESTGetUtxosOutpoints {
+		return nil, restErrorf(http.StatusBadRequest, "too many outpoints "+
+			"(%d > %d)", len(parts), maxRESTGetUtxosOutpoints)
+	}
+	outpoints := make([]*wire.OutPoint, 0, len(parts))
+	for _, part := range parts {
+		outpoint, err := parseRESTOutpoint(part)
+		if err != nil {
+			return nil, err
+		}
+		outpoints = append(outpoints, outpoint)
+	}
+
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	bitmap := make([]byte, (len(outpoints)+7)/8)
+	utxos := make([]restUtxo, 0, len(outpoints))
+	var pkScripts [][]byte
+	var values []int64
+	for i, outpoint := range outpoints {
+		var height, value int64
+		var scriptVersion uint16
+		var pkScript []byte
+		var found bool
+		if checkMempool {
+			tx, _ := s.cfg.TxMempooler.FetchTransaction(&outpoint.Hash)
+			if tx != nil && tx.Tree() == outpoint.Tree {
+				mtx := tx.MsgTx()
+				if outpoint.Index < uint32(len(mtx.TxOut)) {
+					txOut := mtx.TxOut[outpoint.Index]
+					value = txOut.Value
+					scriptVersion = txOut.Version
+					pkScript = txOut.PkScript
+					found = true
+				}
+			}
+		}
+		if !found {
+			entry, err := chain.FetchUtxoEntry(*outpoint)
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil && !entry.IsSpent() {
+				height = entry.BlockHeight()
+				value = entry.Amount()
+				scriptVersion = entry.ScriptVersion()
+				pkScript = entry.PkScript()
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		bitmap[i/8] |= 1 << (uint(i) % 8)
+		utxos = append(utxos, restUtxo{
+			Height:        height,
+			Value:         dcrutil.Amount(value).ToCoin(),
+			ScriptVersion: scriptVersion,
+			PkScript:      hex.EncodeToString(pkScript),
+		})
+		values = append(values, value)
+		pkScripts = append(pkScripts, pkScript)
+	}
+
+	if format == restFormatJSON {
+		return &restResult{json: &restGetUtxosResult{
+			ChainHeight: best.Height,
+			ChainTip:    best.Hash.String(),
+			Bitmap:      hex.EncodeToString(bitmap),
+			Utxos:       utxos,
+		}}, nil
+	}
+
+	var buf bytes.Buffer
+	var scratch [8]byte
+	binary.LittleEndian.PutUint32(scratch[:4], uint32(best.Height))
+	buf.Write(scratch[:4])
+	buf.Write(best.Hash[:])
+	if err := wire.WriteVarBytes(&buf, 0, bitmap); err != nil {
+		return nil, err
+	}
+	if err := wire.WriteVarInt(&buf, 0, uint64(len(utxos))); err != nil {
+		return nil, err
+	}
+	for i := range utxos {
+		binary.LittleEndian.PutUint32(scratch[:4], uint32(utxos[i].Height))
+		buf.Write(scratch[:4])
+		binary.LittleEndian.PutUint64(scratch[:], uint64(values[i]))
+		buf.Write(scratch[:])
+		binary.LittleEndian.PutUint16(scratch[:2], utxos[i].ScriptVersion)
+		buf.Write(scratch[:2])
+		if err := wire.WriteVarBytes(&buf, 0, pkScripts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &restResult{binary: buf.Bytes()}, nil
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// testRESTServer returns a server backed by the default mock configuration
+// with the REST interface enabled along with its mock chain.
+func testRESTServer(token string) (*Server, *testRPCChain) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.EnableREST = true
+	cfg.RESTToken = token
+	cfg.RPCMaxClients = 10
+	s := &Server{cfg: *cfg, limiter: newRPCLimiter(0, 0, 0, 0)}
+	return s, cfg.Chain.(*testRPCChain)
+}
+
+// doREST performs a REST request for the provided path against the server and
+// returns the recorded response.
+func doREST(s *Server, method, path, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.handleREST(w, r)
+	return w
+}
+
+// TestRESTAuth ensures the REST interface enforces the configured token and
+// only allows read requests.
+func TestRESTAuth(t *testing.T) {
+	blk := dcrutil.NewBlock(&block432100)
+	path := "/rest/block/" + blk.Hash().String() + ".hex"
+
+	s, _ := testRESTServer("")
+	if w := doREST(s, http.MethodGet, path, ""); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status without token configured: %d", w.Code)
+	}
+	if w := doREST(s, http.MethodPost, path, ""); w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status for POST request: %d", w.Code)
+	}
+
+	s, _ = testRESTServer("secret")
+	tests := []struct {
+		token  string
+		status int
+	}{
+		{token: "", status: http.StatusUnauthorized},
+		{token: "wrong", status: http.StatusUnauthorized},
+		{token: "secret", status: http.StatusOK},
+	}
+	for _, test := range tests {
+		w := doREST(s, http.MethodGet, path, test.token)
+		if w.Code != test.status {
+			t.Errorf("token %q: unexpected status -- got %d, want %d",
+				test.token, w.Code, test.status)
+		}
+	}
+}
+
+// TestRESTLimits ensures REST requests are subject to the per-IP request
+// limits.
+func TestRESTLimits(t *testing.T) {
+	blk := dcrutil.NewBlock(&block432100)
+	path := "/rest/block/" + blk.Hash().String() + ".hex"
+
+	s, _ := testRESTServer("")
+	s.limiter = newRPCLimiter(0, 1, 0, 0)
+	if w := doREST(s, http.MethodGet, path, ""); w.Code != http.StatusOK {
+		t.Fatalf("unexpected status for first request: %d", w.Code)
+	}
+	w := doREST(s, http.MethodGet, path, "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status for limited request: %d", w.Code)
+	}
+	if info := s.limiter.info(); info.Allowed != 1 || info.RateLimited != 1 {
+		t.Fatalf("unexpected limiter info: %+v", info)
+	}
+}
+
+// TestRESTBlock ensures the REST block endpoint returns blocks in all of the
+// supported formats and rejects invalid requests.
+func TestRESTBlock(t *testing.T) {
+	blk := dcrutil.NewBlock(&block432100)
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing block: %v", err)
+	}
+	blkHash := blk.Hash().String()
+
+	s, chain := testRESTServer("")
+	w := doREST(s, http.MethodGet, "/rest/block/"+blkHash+".bin", "")
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), blkBytes) {
+		t.Fatalf("unexpected binary block response (status %d)", w.Code)
+	}
+	w = doREST(s, http.MethodGet, "/rest/block/"+blkHash+".hex", "")
+	if got, want := w.Body.String(), hex.EncodeToString(blkBytes)+"\n"; got != want {
+		t.Fatalf("unexpected hex block response -- got %q, want %q", got,
+			want)
+	}
+	w = doREST(s, http.MethodGet, "/rest/block/"+blkHash+".json", "")
+	var result struct {
+		Hash string        `json:"hash"`
+		Tx   []interface{} `json:"rawtx"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unexpected error decoding json block response: %v", err)
+	}
+	if result.Hash != blkHash || len(result.Tx) != len(blk.Transactions()) {
+		t.Fatalf("unexpected json block response: %s", w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{{
+		name:   "no format",
+		path:   "/rest/block/" + blkHash,
+		status: http.StatusBadRequest,
+	}, {
+		name:   "unsupported format",
+		path:   "/rest/block/" + blkHash + ".xml",
+		status: http.StatusBadRequest,
+	}, {
+		name:   "invalid hash",
+		path:   "/rest/block/abcd.bin",
+		status: http.StatusBadRequest,
+	}, {
+		name:   "unknown endpoint",
+		path:   "/rest/tx/" + blkHash + ".bin",
+		status: http.StatusNotFound,
+	}}
+	for _, test := range tests {
+		w := doREST(s, http.MethodGet, test.path, "")
+		if w.Code != test.status {
+			t.Errorf("%s: unexpected status -- got %d, want %d", test.name,
+				w.Code, test.status)
+		}
+	}
+
+	chain.blockByHashErr = errors.New("not found")
+	for _, format := range []string{".bin", ".json"} {
+		w := doREST(s, http.MethodGet, "/rest/block/"+blkHash+format, "")
+		if w.Code != http.StatusNotFound {
+			t.Errorf("unknown block %s: unexpected status -- got %d, "+
+				"want %d", format, w.Code, http.StatusNotFound)
+		}
+	}
+}
+
+// TestRESTHeaders ensures the REST headers endpoint returns the requested
+// headers limited by the current best chain.
+func TestRESTHeaders(t *testing.T) {
+	blk := dcrutil.NewBlock(&block432100)
+	blkHash := blk.Hash().String()
+	header := block432100.Header
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing header: %v", err)
+	}
+
+	s, chain := testRESTServer("")
+	chain.headerByHeight = header
+	chain.blockHeightByHash = chain.bestSnapshot.Height - 2
+
+	// Only three headers are available from the starting height.
+	w := doREST(s, http.MethodGet, "/rest/headers/5/"+blkHash+".bin", "")
+	want := bytes.Repeat(headerBytes, 3)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), want) {
+		t.Fatalf("unexpected binary headers response (status %d, len %d)",
+			w.Code, w.Body.Len())
+	}
+	w = doREST(s, http.MethodGet, "/rest/headers/2/"+blkHash+".json", "")
+	var results []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &results); err != nil {
+		t.Fatalf("unexpected error decoding json headers response: %v", err)
+	}
+	if len(results) != 2 || results[0]["hash"] != blkHash {
+		t.Fatalf("unexpected json headers response: %s", w.Body.String())
+	}
+
+	for _, count := range []string{"0", "2001", "x"} {
+		path := "/rest/headers/" + count + "/" + blkHash + ".bin"
+		w := doREST(s, http.MethodGet, path, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("count %s: unexpected status -- got %d, want %d",
+				count, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	chain.blockHeightByHashErr = errors.New("not in main chain")
+	w = doREST(s, http.MethodGet, "/rest/headers/1/"+blkHash+".bin", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unexpected status for unknown block -- got %d, want %d",
+			w.Code, http.StatusNotFound)
+	}
+}
+
+// TestRESTGetUtxos ensures the REST getutxos endpoint reports unspent outputs
+// from the chain and optionally the mempool.
+func TestRESTGetUtxos(t *testing.T) {
+	pkScript := hexToBytes("76a914f59833f104faa3c7fd0c7dc1e3967fe77a9c152988ac")
+	entry := &testRPCUtxoEntry{
+		amount:   100000000,
+		height:   432000,
+		pkScript: pkScript,
+	}
+	s, chain := testRESTServer("")
+	chain.fetchUtxoEntry = entry
+	best := chain.bestSnapshot
+
+	txid := "4c2d1b1b3d2c4f9b8b5a6a8f8c6e7d4b3a291817161514131211100f0e0d0c0b"
+	path := "/rest/getutxos/" + txid + "-0/" + txid + "-1-1.json"
+	w := doREST(s, http.MethodGet, path, "")
+	var result restGetUtxosResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unexpected error decoding json response: %v", err)
+	}
+	if result.ChainHeight != best.Height || result.ChainTip != best.Hash.String() ||
+		result.Bitmap != "03" || len(result.Utxos) != 2 ||
+		result.Utxos[0].Height != 432000 || result.Utxos[0].Value != 1 {
+
+		t.Fatalf("unexpected json response: %s", w.Body.String())
+	}
+
+	// Ensure spent outputs are not reported and the binary encoding matches
+	// the documented format.
+	entry.isSpent = true
+	w = doREST(s, http.MethodGet, "/rest/getutxos/"+txid+"-0.bin", "")
+	var want bytes.Buffer
+	var scratch [4]byte
+	binary.LittleEndian.PutUint32(scratch[:], uint32(best.Height))
+	want.Write(scratch[:])
+	want.Write(best.Hash[:])
+	wire.WriteVarBytes(&want, 0, []byte{0x00})
+	wire.WriteVarInt(&want, 0, 0)
+	if !bytes.Equal(w.Body.Bytes(), want.Bytes()) {
+		t.Fatalf("unexpected binary response -- got %x, want %x",
+			w.Body.Bytes(), want.Bytes())
+	}
+
+	// Ensure outputs of mempool transactions are reported when requested.
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&best.Hash, 0, 0), 0, nil))
+	tx.AddTxOut(wire.NewTxOut(5000, pkScript))
+	mp := s.cfg.TxMempooler.(*testTxMempooler)
+	mp.fetchTransaction = dcrutil.NewTx(tx)
+	mp.fetchTransaction.SetTree(wire.TxTreeRegular)
+	path = "/rest/getutxos/checkmempool/" + tx.TxHash().String() + "-0.json"
+	w = doREST(s, http.MethodGet, path, "")
+	result = restGetUtxosResult{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unexpected error decoding json response: %v", err)
+	}
+	if result.Bitmap != "01" || len(result.Utxos) != 1 ||
+		result.Utxos[0].Height != 0 || result.Utxos[0].Value != 0.00005 {
+
+		t.Fatalf("unexpected mempool json response: %s", w.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "no outpoints", path: "/rest/getutxos.json"},
+		{name: "only checkmempool", path: "/rest/getutxos/checkmempool.json"},
+		{name: "bad index", path: "/rest/getutxos/" + txid + "-x.json"},
+		{name: "bad tree", path: "/rest/getutxos/" + txid + "-0-2.json"},
+		{name: "missing index", path: "/rest/getutxos/" + txid + ".json"},
+		{name: "too many", path: "/rest/getutxos" +
+			string(bytes.Repeat([]byte("/"+txid+"-0"), 16)) + ".json"},
+	}
+	for _, test := range tests {
+		w := doREST(s, http.MethodGet, test.path, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: unexpected status -- got %d, want %d", test.name,
+				w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// allowAll is the allowlist entry that grants access to all RPC methods or
+// websocket notification types.
+const allowAll = "*"
+
+var (
+	// ErrUnauthenticated is returned by AuthorizeCall when a call does not
+	// provide valid credentials.
+	ErrUnauthenticated = errors.New("invalid credentials")
+
+	// ErrUnauthorized is returned by AuthorizeCall when the user that made a
+	// call is not authorized to invoke the method.
+	ErrUnauthorized = errors.New("user not authorized for this method")
+
+	// ErrTooManyRequests is returned by AuthorizeCall when a call would
+	// exceed the request limits of the client that made it.
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// ntfnMethodTypes maps the websocket methods that register and unregister for
+// notifications to the notification type they control.  Access to these
+// methods is governed by the notification allowlist of a user instead of the
+// method allowlist.
+var ntfnMethodTypes = map[string]string{
+	"notifyblocks":              "blocks",
+	"stopnotifyblocks":          "blocks",
+	"notifywork":                "work",
+	"stopnotifywork":            "work",
+	"notifytemplatedeltas":      "work",
+	"stopnotifytemplatedeltas":  "work",
+	"notifytspend":              "tspend",
+	"stopnotifytspend":          "tspend",
+	"notifymempoolevents":       "mempoolevents",
+	"stopnotifymempoolevents":   "mempoolevents",
+	"notifyticketevents":        "ticketevents",
+	"stopnotifyticketevents":    "ticketevents",
+	"notifywinningtickets":      "winningtickets",
+	"notifynewtickets":          "newtickets",
+	"notifynewtransactions":     "newtransactions",
+	"stopnotifynewtransactions": "newtransactions",
+}
+
+// UserAuth describes the credentials of an RPC user along with the RPC methods
+// and websocket notification types the user is authorized to access.
+type UserAuth struct {
+	// User and Pass are the credentials the user must provide via HTTP basic
+	// access authentication or the websocket authenticate command.
+	User string
+	Pass string
+
+	// Methods is the allowlist of RPC methods the user may invoke.  The
+	// single entry "*" allows all methods.  Note that the websocket methods
+	// used to register for notifications are governed by Notifications
+	// instead.
+	Methods []string
+
+	// Notifications is the allowlist of websocket notification types, such
+	// as "blocks" and "newtransactions", the user may register for.  The
+	// single entry "*" allows all notification types.
+	Notifications []string
+}
+
+// rpcAuthUser houses the authorization details of an authenticated RPC user.
+type rpcAuthUser struct {
+	// name is the username of the user.
+	name string
+
+	// mac is the MAC of the HTTP basic access authorization header of the
+	// user and identifies the credentials that were used to authenticate.
+	mac [sha256.Size]byte
+
+	allMethods bool
+	methods    map[string]struct{}
+	allNtfns   bool
+	ntfns      map[string]struct{}
+}
+
+// openAuthUser is the user for all connections when no RPC credentials are
+// configured, which is the case when TLS client certificates are used for
+// authentication.  It is authorized for everything.
+var openAuthUser = &rpcAuthUser{allMethods: true, allNtfns: true}
+
+// isAdmin returns whether or not the user is authorized for all methods and
+// notification types.
+func (u *rpcAuthUser) isAdmin() bool {
+	return u.allMethods && u.allNtfns
+}
+
+// authorized returns whether or not the user is authorized to invoke the
+// provided RPC method.
+func (u *rpcAuthUser) authorized(method string) bool {
+	if ntfnType, ok := ntfnMethodTypes[method]; ok {
+		if u.allNtfns {
+			return true
+		}
+		_, ok := u.ntfns[ntfnType]
+		return ok
+	}
+	if u.allMethods {
+		return true
+	}
+	_, ok := u.methods[method]
+	return ok
+}
+
+// rpcAuthState houses the configured RPC users keyed by the MAC of their
+// authorization headers.  It is immutable once created so that it may be
+// replaced atomically when the users are rotated.
+type rpcAuthState struct {
+	users map[[sha256.Size]byte]*rpcAuthUser
+}
+
+// isKnownMethod returns whether or not the provided method is handled by the
+// RPC server via either HTTP POST or websockets.
+func isKnownMethod(method string) bool {
+	_, ok := rpcHandlers[types.Method(method)]
+	if !ok {
+		_, ok = wsHandlers[types.Method(method)]
+	}
+	return ok
+}
+
+// isKnownNtfnType returns whether or not the provided websocket notification
+// type is recognized.
+func isKnownNtfnType(ntfnType string) bool {
+	for _, t := range ntfnMethodTypes {
+		if t == ntfnType {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedUserAuth returns the authorization for the legacy limited user with
+// the provided credentials, which is authorized for the methods and
+// notifications in rpcLimited.
+func limitedUserAuth(user, pass string) UserAuth {
+	auth := UserAuth{User: user, Pass: pass}
+	for method := range rpcLimited {
+		if ntfnType, ok := ntfnMethodTypes[method]; ok {
+			auth.Notifications = append(auth.Notifications, ntfnType)
+			continue
+		}
+		auth.Methods = append(auth.Methods, method)
+	}
+	return auth
+}
+
+// buildAuthState validates the provided users and returns the resulting
+// authorization state.
+func (s *Server) buildAuthState(users []UserAuth) (*rpcAuthState, error) {
+	state := &rpcAuthState{
+		users: make(map[[sha256.Size]byte]*rpcAuthUser, len(users)),
+	}
+	names := make(map[string]struct{}, len(users))
+	for i := range users {
+		auth := &users[i]
+		if auth.User == "" || auth.Pass == "" {
+			return nil, fmt.Errorf("RPC user %d: username and password "+
+				"must not be empty", i)
+		}
+		if _, ok := names[auth.User]; ok {
+			return nil, fmt.Errorf("RPC user %q is specified more than "+
+				"once", auth.User)
+		}
+		names[auth.User] = struct{}{}
+
+		user := &rpcAuthUser{
+			name:    auth.User,
+			methods: make(map[string]struct{}, len(auth.Methods)),
+			ntfns:   make(map[string]struct{}, len(auth.Notifications)),
+		}
+		for _, method := range auth.Methods {
+			switch {
+			case method == allowAll:
+				user.allMethods = true
+			case ntfnMethodTypes[method] != "":
+				return nil, fmt.Errorf("RPC user %q: notification "+
+					"method %q must be authorized via its notification "+
+					"type %q", auth.User, method, ntfnMethodTypes[method])
+			case !isKnownMethod(method):
+				return nil, fmt.Errorf("RPC user %q: unknown RPC "+
+					"method %q", auth.User, method)
+			}
+			user.methods[method] = struct{}{}
+		}
+		for _, ntfnType := range auth.Notifications {
+			switch {
+			case ntfnType == allowAll:
+				user.allNtfns = true
+			case !isKnownNtfnType(ntfnType):
+				return nil, fmt.Errorf("RPC user %q: unknown "+
+					"notification type %q", auth.User, ntfnType)
+			}
+			user.ntfns[ntfnType] = struct{}{}
+		}
+
+		login := auth.User + ":" + auth.Pass
+		authHdr := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+		s.authMAC(user.mac[:0], []byte(authHdr))
+		state.users[user.mac] = user
+	}
+	return state, nil
+}
+
+// loadAuthState returns the current authorization state.
+//
+// This function is safe for concurrent access.
+func (s *Server) loadAuthState() *rpcAuthState {
+	return s.authState.Load().(*rpcAuthState)
+}
+
+// SetUsers replaces the RPC users configured via the Users field of the server
+// config with the provided users.  The users configured via the RPCUser and
+// RPCLimitUser fields are retained.  Connected websocket clients whose
+// credentials are no longer valid are disconnected when they issue their next
+// request, while the requests of the remaining clients are subject to their
+// updated authorization.
+//
+// Servers created without any users do not require authentication, so an
+// error is returned for them.  Likewise, removing all users does not disable
+// authentication.  Instead, no clients are able to authenticate.
+//
+// This function is safe for concurrent access.
+func (s *Server) SetUsers(users []UserAuth) error {
+	if s.openAuth {
+		return fmt.Errorf("RPC users may not be set on a server that was " +
+			"created without any users")
+	}
+	all := make([]UserAuth, 0, len(s.legacyUsers)+len(users))
+	all = append(all, s.legacyUsers...)
+	all = append(all, users...)
+	state, err := s.buildAuthState(all)
+	if err != nil {
+		return err
+	}
+	s.authState.Store(state)
+	return nil
+}
+
+// currentAuthUser returns the current authorization for the credentials the
+// provided user authenticated with or nil when they are no longer valid.
+//
+// This function is safe for concurrent access.
+func (s *Server) currentAuthUser(user *rpcAuthUser) *rpcAuthUser {
+	if s.openAuth {
+		return openAuthUser
+	}
+	return s.loadAuthState().users[user.mac]
+}
+
+// AuthorizeCall authenticates a call made from the provided remote address via
+// a transport other than JSON-RPC, such as gRPC, with the provided HTTP basic
+// access authorization value, ensures the user is authorized to invoke the
+// provided RPC method, which must provide functionality equivalent to the
+// call, and checks the call against the request limits of the user and remote
+// address.  This ensures the other transports are subject to the same users,
+// allowlists, credential rotations, and request limits as the RPC server.  The
+// authorization value is ignored when the server does not require
+// authentication.
+//
+// The returned function must be called once the call has been processed when
+// it is allowed.  ErrUnauthenticated is returned when the credentials are
+// invalid, ErrUnauthorized is returned when the user is not authorized for the
+// method, and an error that wraps ErrTooManyRequests is returned when the call
+// would exceed the request limits.
+//
+// This function is safe for concurrent access.
+func (s *Server) AuthorizeCall(auth, method, remoteAddr string) (func(), error) {
+	user := openAuthUser
+	if !s.openAuth {
+		user = s.checkAuthMAC(auth, remoteAddr)
+		if user == nil {
+			return nil, ErrUnauthenticated
+		}
+	}
+	if !user.authorized(method) {
+		return nil, ErrUnauthorized
+	}
+	release, limitErr := s.limiter.acquire(user, remoteAddr)
+	if limitErr != nil {
+		log.Debugf("Rejected call <%s> from %s: %v", method, remoteAddr,
+			limitErr.Message)
+		return nil, fmt.Errorf("%w: %s", ErrTooManyRequests, limitErr.Message)
+	}
+	return release, nil
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// TestUserAuthorization ensures users are only authorized for the methods and
+// notification types in their allowlists.
+func TestUserAuthorization(t *testing.T) {
+	s, err := New(&Config{
+		RPCUser:      "admin",
+		RPCPass:      "adminpass",
+		RPCLimitUser: "limit",
+		RPCLimitPass: "limitpass",
+		Users: []UserAuth{{
+			User:          "explorer",
+			Pass:          "explorerpass",
+			Methods:       []string{"getblock", "getblockhash"},
+			Notifications: []string{"blocks"},
+		}, {
+			User:          "ntfns",
+			Pass:          "ntfnspass",
+			Notifications: []string{"*"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+
+	tests := []struct {
+		user   string
+		pass   string
+		method string
+		want   bool
+	}{
+		{"admin", "adminpass", "stop", true},
+		{"admin", "adminpass", "notifywork", true},
+		{"limit", "limitpass", "getblock", true},
+		{"limit", "limitpass", "notifyblocks", true},
+		{"limit", "limitpass", "stopnotifynewtransactions", true},
+		{"limit", "limitpass", "notifywork", false},
+		{"limit", "limitpass", "stop", false},
+		{"explorer", "explorerpass", "getblock", true},
+		{"explorer", "explorerpass", "getblockhash", true},
+		{"explorer", "explorerpass", "getblockcount", false},
+		{"explorer", "explorerpass", "notifyblocks", true},
+		{"explorer", "explorerpass", "stopnotifyblocks", true},
+		{"explorer", "explorerpass", "notifynewtransactions", false},
+		{"ntfns", "ntfnspass", "notifywork", true},
+		{"ntfns", "ntfnspass", "getblock", false},
+	}
+	for _, test := range tests {
+		user := s.checkAuthUserPass(test.user, test.pass, "addr")
+		if user == nil {
+			t.Errorf("%s: failed to authenticate", test.user)
+			continue
+		}
+		if got := user.authorized(test.method); got != test.want {
+			t.Errorf("%s: unexpected authorization for %q -- got %v, "+
+				"want %v", test.user, test.method, got, test.want)
+		}
+	}
+}
+
+// TestInvalidUsers ensures invalid user configurations are rejected.
+func TestInvalidUsers(t *testing.T) {
+	tests := []struct {
+		name  string
+		users []UserAuth
+	}{{
+		name:  "empty password",
+		users: []UserAuth{{User: "u", Methods: []string{"getblock"}}},
+	}, {
+		name: "duplicate username",
+		users: []UserAuth{
+			{User: "u", Pass: "p1", Methods: []string{"getblock"}},
+			{User: "u", Pass: "p2", Methods: []string{"getblock"}},
+		},
+	}, {
+		name:  "duplicate of legacy username",
+		users: []UserAuth{{User: "admin", Pass: "p", Methods: []string{"*"}}},
+	}, {
+		name:  "unknown method",
+		users: []UserAuth{{User: "u", Pass: "p", Methods: []string{"nope"}}},
+	}, {
+		name: "notification method",
+		users: []UserAuth{{User: "u", Pass: "p",
+			Methods: []string{"notifyblocks"}}},
+	}, {
+		name: "unknown notification type",
+		users: []UserAuth{{User: "u", Pass: "p",
+			Notifications: []string{"nope"}}},
+	}}
+	for _, test := range tests {
+		_, err := New(&Config{
+			RPCUser: "admin",
+			RPCPass: "adminpass",
+			Users:   test.users,
+		})
+		if err == nil {
+			t.Errorf("%s: did not receive expected error", test.name)
+		}
+	}
+}
+
+// TestSetUsers ensures the configured users may be rotated at runtime while
+// retaining the legacy users and that the authorization of previously
+// authenticated users reflects the rotation.
+func TestSetUsers(t *testing.T) {
+	s, err := New(&Config{
+		RPCLimitUser: "limit",
+		RPCLimitPass: "limitpass",
+		Users: []UserAuth{{
+			User:    "u",
+			Pass:    "old",
+			Methods: []string{"getblock"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	oldUser := s.checkAuthUserPass("u", "old", "addr")
+	if oldUser == nil {
+		t.Fatal("failed to authenticate with initial credentials")
+	}
+	limitUser := s.checkAuthUserPass("limit", "limitpass", "addr")
+	if limitUser == nil {
+		t.Fatal("failed to authenticate limited user")
+	}
+
+	// Invalid users must not replace the existing ones.
+	err = s.SetUsers([]UserAuth{{User: "u", Pass: "new",
+		Methods: []string{"nope"}}})
+	if err == nil {
+		t.Fatal("did not receive expected error for invalid users")
+	}
+	if s.currentAuthUser(oldUser) == nil {
+		t.Fatal("invalid users replaced the existing users")
+	}
+
+	err = s.SetUsers([]UserAuth{{User: "u", Pass: "new",
+		Methods: []string{"getblock", "getblockcount"}}})
+	if err != nil {
+		t.Fatalf("unexpected error setting users: %v", err)
+	}
+	if s.checkAuthUserPass("u", "old", "addr") != nil {
+		t.Fatal("authenticated with rotated credentials")
+	}
+	if s.currentAuthUser(oldUser) != nil {
+		t.Fatal("rotated credentials are still valid")
+	}
+	newUser := s.checkAuthUserPass("u", "new", "addr")
+	if newUser == nil || !newUser.authorized("getblockcount") {
+		t.Fatal("failed to authenticate with new credentials")
+	}
+	if s.currentAuthUser(limitUser) == nil {
+		t.Fatal("legacy limited user was not retained")
+	}
+
+	// Removing all users must not disable authentication.
+	if err := s.SetUsers(nil); err != nil {
+		t.Fatalf("unexpected error setting users: %v", err)
+	}
+	if s.checkAuthUserPass("u", "new", "addr") != nil {
+		t.Fatal("authenticated with removed credentials")
+	}
+	if s.currentAuthUser(limitUser) == nil {
+		t.Fatal("legacy limited user was not retained")
+	}
+
+	// Servers that do not require authentication must reject users.
+	s, err = New(&Config{})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	if err := s.SetUsers([]UserAuth{{User: "u", Pass: "p",
+		Methods: []string{"*"}}}); err == nil {
+		t.Fatal("did not receive expected error setting users on open server")
+	}
+	if s.currentAuthUser(openAuthUser) != openAuthUser {
+		t.Fatal("server without users is not open")
+	}
+}
+
+// TestAuthorizeCall ensures calls made via other transports are authenticated
+// and authorized per the configured users.
+func TestAuthorizeCall(t *testing.T) {
+	s, err := New(&Config{
+		RPCUser:      "admin",
+		RPCPass:      "adminpass",
+		RPCLimitUser: "limit",
+		RPCLimitPass: "limitpass",
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+
+	basicAuth := func(user, pass string) string {
+		login := user + ":" + pass
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	}
+	admin := basicAuth("admin", "adminpass")
+	limit := basicAuth("limit", "limitpass")
+	tests := []struct {
+		name   string
+		auth   string
+		method string
+		want   error
+	}{
+		{"no credentials", "", "getblock", ErrUnauthenticated},
+		{"wrong password", basicAuth("admin", "x"), "getblock",
+			ErrUnauthenticated},
+		{"admin", admin, "notifymempoolevents", nil},
+		{"limited method", limit, "getblock", nil},
+		{"limited ntfn", limit, "notifyblocks", nil},
+		{"limited denied", limit, "notifymempoolevents", ErrUnauthorized},
+		{"limited admin method", limit, "stop", ErrUnauthorized},
+	}
+	for _, test := range tests {
+		_, err := s.AuthorizeCall(test.auth, test.method, "addr")
+		if !errors.Is(err, test.want) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.want)
+		}
+	}
+
+	// Servers that do not require authentication authorize all calls.
+	s, err = New(&Config{})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	if _, err := s.AuthorizeCall("", "stop", "addr"); err != nil {
+		t.Fatalf("unexpected error on open server: %v", err)
+	}
+}
+
+// TestAuthorizeCallUsers ensures calls made via other transports are subject
+// to the allowlists of users added via the Users field, even when they are the
+// only configured users, as well as to rotations of those users.
+func TestAuthorizeCallUsers(t *testing.T) {
+	s, err := New(&Config{
+		Users: []UserAuth{{
+			User:    "explorer",
+			Pass:    "old",
+			Methods: []string{"getblock"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+
+	basicAuth := func(user, pass string) string {
+		login := user + ":" + pass
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	}
+	oldAuth := basicAuth("explorer", "old")
+	newAuth := basicAuth("explorer", "new")
+	_, err = s.AuthorizeCall("", "getblock", "addr")
+	if err != ErrUnauthenticated {
+		t.Fatalf("unexpected error without credentials -- got %v, want %v",
+			err, ErrUnauthenticated)
+	}
+	if _, err := s.AuthorizeCall(oldAuth, "getblock", "addr"); err != nil {
+		t.Fatalf("unexpected error for allowed method: %v", err)
+	}
+	_, err = s.AuthorizeCall(oldAuth, "sendrawtransaction", "addr")
+	if err != ErrUnauthorized {
+		t.Fatalf("unexpected error for disallowed method -- got %v, want %v",
+			err, ErrUnauthorized)
+	}
+
+	// Ensure rotated credentials apply to subsequent calls.
+	err = s.SetUsers([]UserAuth{{User: "explorer", Pass: "new",
+		Methods: []string{"getblock"}}})
+	if err != nil {
+		t.Fatalf("unexpected error setting users: %v", err)
+	}
+	_, err = s.AuthorizeCall(oldAuth, "getblock", "addr")
+	if err != ErrUnauthenticated {
+		t.Fatalf("unexpected error with rotated credentials -- got %v, "+
+			"want %v", err, ErrUnauthenticated)
+	}
+	if _, err := s.AuthorizeCall(newAuth, "getblock", "addr"); err != nil {
+		t.Fatalf("unexpected error with new credentials: %v", err)
+	}
+}
+
+// TestAuthorizeCallLimits ensures calls made via other transports are subject
+// to the request limits of the RPC server and that releasing a call frees up
+// its slot.
+func TestAuthorizeCallLimits(t *testing.T) {
+	s, err := New(&Config{RPCUser: "admin", RPCPass: "adminpass"})
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	s.limiter = newRPCLimiter(0, 0, 1, 0)
+
+	login := "admin:adminpass"
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	release, err := s.AuthorizeCall(auth, "getblock", "127.0.0.1:1000")
+	if err != nil {
+		t.Fatalf("unexpected error for first call: %v", err)
+	}
+	_, err = s.AuthorizeCall(auth, "getblock", "127.0.0.2:1000")
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("unexpected error for concurrent call -- got %v, want %v",
+			err, ErrTooManyRequests)
+	}
+	release()
+	release, err = s.AuthorizeCall(auth, "getblock", "127.0.0.2:1000")
+	if err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+const (
+	// rpcHealthPath is the path of the endpoint that reports whether or not
+	// the node is healthy.  It is intended for liveness probes.
+	rpcHealthPath = "/health"
+
+	// rpcReadyPath is the path of the endpoint that reports whether or not
+	// the node is ready to serve requests.  It is intended for load balancer
+	// and readiness probes.
+	rpcReadyPath = "/ready"
+)
+
+// indexTipper describes an optional index that reports its current tip so its
+// sync progress can be determined.
+type indexTipper interface {
+	// Name returns the human-readable name of the index.
+	Name() string
+
+	// Tip returns the current index tip.
+	Tip() (int64, *chainhash.Hash, error)
+}
+
+// nodeStatus returns the current status of the node.  The node is healthy when
+// its database is available and it is ready when it is also synced to the
+// network, connected to at least one peer, and all of its optional indexes are
+// caught up to the best chain.  The reasons the node is not healthy or ready,
+// if any, are included in the result.
+func (s *Server) nodeStatus() *types.GetNodeStatusResult {
+	best := s.cfg.Chain.BestSnapshot()
+	status := &types.GetNodeStatusResult{
+		Reasons:    []string{},
+		Synced:     s.cfg.SyncMgr.IsCurrent(),
+		Peers:      s.cfg.ConnMgr.ConnectedCount(),
+		BestHeight: best.Height,
+		BestHash:   best.Hash.String(),
+		Indexes:    []types.NodeIndexStatus{},
+	}
+	header, err := s.cfg.Chain.HeaderByHash(&best.Hash)
+	if err == nil {
+		status.LastBlockAge = int64(s.cfg.Clock.Since(header.Timestamp).Seconds())
+	}
+
+	// Ensure the database is able to service reads.
+	err = s.cfg.DB.View(func(dbTx database.Tx) error { return nil })
+	if err != nil {
+		log.Warnf("Database health check failed: %v", err)
+		status.Reasons = append(status.Reasons, "database unavailable")
+	}
+	status.DatabaseOK = err == nil
+	if !status.Synced {
+		status.Reasons = append(status.Reasons, "chain not synced")
+	}
+	if status.Peers == 0 {
+		status.Reasons = append(status.Reasons, "no connected peers")
+	}
+
+	// Determine the sync progress of the optional indexes.
+	var indexes []indexTipper
+	if s.cfg.TxIndexer != nil {
+		indexes = append(indexes, s.cfg.TxIndexer)
+	}
+	if s.cfg.ExistsAddresser != nil {
+		indexes = append(indexes, s.cfg.ExistsAddresser)
+	}
+	if s.cfg.AddrIndexer != nil {
+		indexes = append(indexes, s.cfg.AddrIndexer)
+	}
+	if s.cfg.SpentIndexer != nil {
+		indexes = append(indexes, s.cfg.SpentIndexer)
+	}
+	indexesSynced := true
+	for _, index := range indexes {
+		indexStatus := types.NodeIndexStatus{Name: index.Name(), Progress: 1}
+		tipHeight, tipHash, err := index.Tip()
+		if err != nil {
+			log.Warnf("Unable to fetch the %s tip: %v", index.Name(), err)
+			status.Reasons = append(status.Reasons, fmt.Sprintf("%s "+
+				"unavailable", index.Name()))
+			status.Indexes = append(status.Indexes, indexStatus)
+			indexesSynced = false
+			continue
+		}
+		indexStatus.Height = tipHeight
+		indexStatus.Synced = tipHeight == best.Height && *tipHash == best.Hash
+		if best.Height > 0 && tipHeight < best.Height {
+			indexStatus.Progress = float64(tipHeight) / float64(best.Height)
+		}
+		if !indexStatus.Synced {
+			status.Reasons = append(status.Reasons, fmt.Sprintf("%s not "+
+				"synced", index.Name()))
+			indexesSynced = false
+		}
+		status.Indexes = append(status.Indexes, indexStatus)
+	}
+
+	status.Healthy = status.DatabaseOK
+	status.Ready = status.Healthy && status.Synced && status.Peers > 0 &&
+		indexesSynced
+	return status
+}
+
+// probeResult is the response of the health and readiness endpoints.
+type probeResult struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// handleProbe serves the health endpoint when ready is false and the readiness
+// endpoint otherwise.  The endpoints respond with a 200 status code when the
+// node is healthy or ready, respectively, and a 503 status code along with the
+// reasons otherwise.  They do not require the RPC credentials so they are
+// usable by load balancers and orchestration probes, but they are subject to
+// the RPC client limits.
+func (s *Server) handleProbe(ready bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Connection", "close")
+		r.Close = true
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "405 Method not allowed.",
+				http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Limit the number of connections to max allowed.
+		if s.limitConnections(w, r.RemoteAddr) {
+			return
+		}
+
+		// Keep track of the number of connected clients.
+		s.incrementClients()
+		defer s.decrementClients()
+
+		status := s.nodeStatus()
+		ok := status.Healthy
+		if ready {
+			ok = status.Ready
+		}
+		result := probeResult{Status: "ok"}
+		code := http.StatusOK
+		if !ok {
+			result = probeResult{Status: "unavailable", Reasons: status.Reasons}
+			code = http.StatusServiceUnavailable
+		}
+		reply, err := json.Marshal(&result)
+		if err != nil {
+			log.Errorf("Failed to marshal probe reply: %v", err)
+			http.Error(w, "500 Internal server error.",
+				http.StatusInternalServerError)
+			return
+		}
+		reply = append(reply, '\n')
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(reply)))
+		w.WriteHeader(code)
+		if _, err := w.Write(reply); err != nil {
+			log.Debugf("Failed to write probe reply to %s: %v",
+				r.RemoteAddr, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestHandleProbe ensures the health and readiness endpoints report the status
+// of the node without requiring authentication.
+func TestHandleProbe(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCMaxClients = 10
+	cfg.RPCUser, cfg.RPCPass = "admin", "adminpass"
+	syncMgr := defaultMockSyncManager()
+	cfg.SyncMgr = syncMgr
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	do := func(method, path string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, httpServer.URL+path, nil)
+		if err != nil {
+			t.Fatalf("unable to create request: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error issuing request: %v", err)
+		}
+		defer resp.Body.Close()
+		reply, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading reply: %v", err)
+		}
+		return resp.StatusCode, string(reply)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		isCurrent  bool
+		wantStatus int
+		wantReply  string
+	}{{
+		name:       "healthy while not synced",
+		method:     http.MethodGet,
+		path:       rpcHealthPath,
+		wantStatus: http.StatusOK,
+		wantReply:  "{\"status\":\"ok\"}\n",
+	}, {
+		name:       "not ready while not synced",
+		method:     http.MethodGet,
+		path:       rpcReadyPath,
+		wantStatus: http.StatusServiceUnavailable,
+		wantReply: "{\"status\":\"unavailable\"," +
+			"\"reasons\":[\"chain not synced\"]}\n",
+	}, {
+		name:       "ready once synced",
+		method:     http.MethodGet,
+		path:       rpcReadyPath,
+		isCurrent:  true,
+		wantStatus: http.StatusOK,
+		wantReply:  "{\"status\":\"ok\"}\n",
+	}, {
+		name:       "head request",
+		method:     http.MethodHead,
+		path:       rpcReadyPath,
+		isCurrent:  true,
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "method not allowed",
+		method:     http.MethodPost,
+		path:       rpcHealthPath,
+		wantStatus: http.StatusMethodNotAllowed,
+		wantReply:  "405 Method not allowed.\n",
+	}}
+	for _, test := range tests {
+		syncMgr.isCurrent = test.isCurrent
+		status, reply := do(test.method, test.path)
+		if status != test.wantStatus || reply != test.wantReply {
+			t.Errorf("%s: unexpected reply -- got %d %q, want %d %q",
+				test.name, status, reply, test.wantStatus, test.wantReply)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/internal/metrics"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// rpcLimiterPruneInterval is the minimum amount of time between passes that
+// remove the quotas of clients that no longer need them.
+const rpcLimiterPruneInterval = time.Minute
+
+// clientQuota tracks the request rate and concurrent requests of a single
+// authenticated user or remote IP against the configured limits.
+type clientQuota struct {
+	// id identifies the client in the limiter statistics.  It is the name of
+	// the user or the remote IP.
+	id string
+
+	// tokens is the number of requests the client may currently issue before
+	// being rate limited and updated is the last time it was refilled.
+	tokens  float64
+	updated time.Time
+
+	// inFlight is the number of requests of the client that are currently
+	// being processed.
+	inFlight int
+
+	// allowed and limited are the number of requests of the client that were
+	// allowed and rejected, respectively.
+	allowed uint64
+	limited uint64
+}
+
+// refill adds the tokens that accrued since the quota was last updated at the
+// provided rate without exceeding the burst size.
+func (q *clientQuota) refill(now time.Time, rate, burst float64) {
+	elapsed := now.Sub(q.updated).Seconds()
+	if elapsed > 0 {
+		q.tokens = math.Min(burst, q.tokens+elapsed*rate)
+	}
+	q.updated = now
+}
+
+// prunable returns whether or not the provided quota does not have any
+// requests in flight and has been idle long enough to fully refill at the
+// given rate, in which case it is no longer needed.
+func (q *clientQuota) prunable(now time.Time, rate float64) bool {
+	if q.inFlight != 0 || now.Sub(q.updated) < rpcLimiterPruneInterval {
+		return false
+	}
+	if rate > 0 {
+		q.refill(now, rate, rateBurst(rate))
+		return q.tokens >= rateBurst(rate)
+	}
+	return true
+}
+
+// rpcLimiter enforces per-user and per-IP limits on the rate of RPC requests
+// and on the number of requests that are processed concurrently so that a
+// single misbehaving client is unable to starve the others.
+//
+// The request rates are enforced with token buckets that allow bursts of up to
+// one second worth of requests, or a single request for rates below one
+// request per second.
+type rpcLimiter struct {
+	userRate    float64
+	ipRate      float64
+	maxUserReqs int
+	maxIPReqs   int
+
+	// The following fields are protected by the mutex.
+	mtx                sync.Mutex
+	users              map[[sha256.Size]byte]*clientQuota
+	ips                map[string]*clientQuota
+	lastPrune          time.Time
+	allowed            uint64
+	rateLimited        uint64
+	concurrencyLimited uint64
+}
+
+// newRPCLimiter returns a limiter that enforces the provided limits.  A limit
+// of zero disables it.
+func newRPCLimiter(userRate, ipRate float64, maxUserReqs, maxIPReqs int) *rpcLimiter {
+	return &rpcLimiter{
+		userRate:    userRate,
+		ipRate:      ipRate,
+		maxUserReqs: maxUserReqs,
+		maxIPReqs:   maxIPReqs,
+		users:       make(map[[sha256.Size]byte]*clientQuota),
+		ips:         make(map[string]*clientQuota),
+	}
+}
+
+// rateBurst returns the token bucket size for the provided request rate.
+func rateBurst(rate float64) float64 {
+	return math.Max(1, rate)
+}
+
+// limitsUsers returns whether or not any per-user limits are configured.
+func (l *rpcLimiter) limitsUsers() bool {
+	return l.userRate > 0 || l.maxUserReqs > 0
+}
+
+// limitsIPs returns whether or not any per-IP limits are configured.
+func (l *rpcLimiter) limitsIPs() bool {
+	return l.ipRate > 0 || l.maxIPReqs > 0
+}
+
+// rpcTooManyRequestsError returns a JSON-RPC error that indicates a request
+// was rejected due to exceeding the provided limit of a client.
+func rpcTooManyRequestsError(fmtStr string, args ...interface{}) *dcrjson.RPCError {
+	return dcrjson.NewRPCError(dcrjson.ErrRPCTooManyRequests,
+		"Too many requests: "+fmt.Sprintf(fmtStr, args...))
+}
+
+// remoteHost returns the host portion of the provided remote address or the
+// address itself when it does not include a port.
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// acquire checks the provided request of the given user from the provided
+// remote address against the configured limits.  It returns a function that
+// must be called once the request has been processed when the request is
+// allowed.  Otherwise, it returns a JSON-RPC error that describes the exceeded
+// limit.
+//
+// Users are not subject to the per-user limits when authentication is not
+// required since all clients are then the same user.
+//
+// This function is safe for concurrent access.
+func (l *rpcLimiter) acquire(user *rpcAuthUser, remoteAddr string) (func(), *dcrjson.RPCError) {
+	limitUser := l.limitsUsers() && user != openAuthUser
+	limitIP := l.limitsIPs()
+	if !limitUser && !limitIP {
+		return func() {}, nil
+	}
+
+	now := time.Now()
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.Sub(l.lastPrune) >= rpcLimiterPruneInterval {
+		for mac, q := range l.users {
+			if q.prunable(now, l.userRate) {
+				delete(l.users, mac)
+			}
+		}
+		for host, q := range l.ips {
+			if q.prunable(now, l.ipRate) {
+				delete(l.ips, host)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	// Lookup or create the quotas that apply to the request.
+	var userQuota, ipQuota *clientQuota
+	if limitUser {
+		userQuota = l.users[user.mac]
+		if userQuota == nil {
+			userQuota = &clientQuota{
+				id:      user.name,
+				tokens:  rateBurst(l.userRate),
+				updated: now,
+			}
+			l.users[user.mac] = userQuota
+		}
+		if l.userRate > 0 {
+			userQuota.refill(now, l.userRate, rateBurst(l.userRate))
+		}
+	}
+	host := remoteHost(remoteAddr)
+	if limitIP {
+		ipQuota = l.ips[host]
+		if ipQuota == nil {
+			ipQuota = &clientQuota{
+				id:      host,
+				tokens:  rateBurst(l.ipRate),
+				updated: now,
+			}
+			l.ips[host] = ipQuota
+		}
+		if l.ipRate > 0 {
+			ipQuota.refill(now, l.ipRate, rateBurst(l.ipRate))
+		}
+	}
+
+	// Reject the request when any of the limits would be exceeded.
+	var jsonErr *dcrjson.RPCError
+	switch {
+	case userQuota != nil && l.maxUserReqs > 0 &&
+		userQuota.inFlight >= l.maxUserReqs:
+
+		userQuota.limited++
+		l.concurrencyLimited++
+		jsonErr = rpcTooManyRequestsError("user %q exceeded the limit of "+
+			"%d concurrent requests", user.name, l.maxUserReqs)
+
+	case ipQuota != nil && l.maxIPReqs > 0 && ipQuota.inFlight >= l.maxIPReqs:
+		ipQuota.limited++
+		l.concurrencyLimited++
+		jsonErr = rpcTooManyRequestsError("%s exceeded the limit of %d "+
+			"concurrent requests", host, l.maxIPReqs)
+
+	case userQuota != nil && l.userRate > 0 && userQuota.tokens < 1:
+		userQuota.limited++
+		l.rateLimited++
+		jsonErr = rpcTooManyRequestsError("user %q exceeded the limit of "+
+			"%g requests per second", user.name, l.userRate)
+
+	case ipQuota != nil && l.ipRate > 0 && ipQuota.tokens < 1:
+		ipQuota.limited++
+		l.rateLimited++
+		jsonErr = rpcTooManyRequestsError("%s exceeded the limit of %g "+
+			"requests per second", host, l.ipRate)
+	}
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+
+	// Charge the request against the quotas.
+	for _, q := range []*clientQuota{userQuota, ipQuota} {
+		if q != nil {
+			q.tokens--
+			q.inFlight++
+			q.allowed++
+		}
+	}
+	l.allowed++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			l.mtx.Lock()
+			for _, q := range []*clientQuota{userQuota, ipQuota} {
+				if q != nil {
+					q.inFlight--
+				}
+			}
+			l.mtx.Unlock()
+		})
+	}
+	return release, nil
+}
+
+// info returns the configured limits along with statistics about the requests
+// that were allowed and rejected.
+//
+// This function is safe for concurrent access.
+func (l *rpcLimiter) info() *types.GetRPCLimitInfoResult {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	result := &types.GetRPCLimitInfoResult{
+		UserReqRate:           l.userRate,
+		IPReqRate:             l.ipRate,
+		UserMaxConcurrentReqs: l.maxUserReqs,
+		IPMaxConcurrentReqs:   l.maxIPReqs,
+		Allowed:               l.allowed,
+		RateLimited:           l.rateLimited,
+		ConcurrencyLimited:    l.concurrencyLimited,
+		Clients: make([]types.RPCLimitClientInfo, 0,
+			len(l.users)+len(l.ips)),
+	}
+	addClients := func(clientType string, quotas []*clientQuota) {
+		sort.Slice(quotas, func(i, j int) bool {
+			return quotas[i].id < quotas[j].id
+		})
+		for _, q := range quotas {
+			result.Clients = append(result.Clients, types.RPCLimitClientInfo{
+				Type:     clientType,
+				ID:       q.id,
+				InFlight: q.inFlight,
+				Allowed:  q.allowed,
+				Limited:  q.limited,
+			})
+		}
+	}
+	userQuotas := make([]*clientQuota, 0, len(l.users))
+	for _, q := range l.users {
+		userQuotas = append(userQuotas, q)
+	}
+	ipQuotas := make([]*clientQuota, 0, len(l.ips))
+	for _, q := range l.ips {
+		ipQuotas = append(ipQuotas, q)
+	}
+	addClients("user", userQuotas)
+	addClients("ip", ipQuotas)
+	return result
+}
+
+// writeTo writes the limiter statistics to the provided writer in the
+// Prometheus text exposition format.  Only aggregate statistics are included
+// since the number of remote IPs is unbounded.
+//
+// This function is safe for concurrent access.
+func (l *rpcLimiter) writeTo(w io.Writer) error {
+	l.mtx.Lock()
+	allowed, rateLimited := l.allowed, l.rateLimited
+	concurrencyLimited := l.concurrencyLimited
+	numUsers, numIPs := len(l.users), len(l.ips)
+	l.mtx.Unlock()
+
+	mw := metrics.NewWriter(w)
+	mw.CounterVec("dcrd_rpc_limiter_requests_total", "Number of requests "+
+		"checked against the RPC client limits by result.", "result",
+		map[string]uint64{
+			"allowed":             allowed,
+			"rate_limited":        rateLimited,
+			"concurrency_limited": concurrencyLimited,
+		})
+
+	mw.Header("dcrd_rpc_limiter_clients", "Number of clients currently "+
+		"tracked by the RPC client limits by type.", "gauge")
+	mw.Sample("dcrd_rpc_limiter_clients", uint64(numUsers), "type", "user")
+	mw.Sample("dcrd_rpc_limiter_clients", uint64(numIPs), "type", "ip")
+
+	return mw.Flush()
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+)
+
+// TestRPCLimiter ensures the limiter enforces the per-user and per-IP request
+// rate and concurrent request limits and tracks the associated statistics.
+func TestRPCLimiter(t *testing.T) {
+	alice := &rpcAuthUser{name: "alice", mac: [32]byte{1}}
+	bob := &rpcAuthUser{name: "bob", mac: [32]byte{2}}
+	const addr1, addr2 = "127.0.0.1:1000", "127.0.0.2:1000"
+
+	// Ensure the request rate of each user is limited independently.
+	l := newRPCLimiter(1, 0, 0, 0)
+	release, jsonErr := l.acquire(alice, addr1)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error for first request: %v", jsonErr)
+	}
+	release()
+	if _, jsonErr := l.acquire(alice, addr2); jsonErr == nil ||
+		jsonErr.Code != dcrjson.ErrRPCTooManyRequests {
+
+		t.Fatalf("unexpected error for rate limited request: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(bob, addr1); jsonErr != nil {
+		t.Fatalf("unexpected error for other user: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(openAuthUser, addr1); jsonErr != nil {
+		t.Fatalf("unexpected error for open user: %v", jsonErr)
+	}
+
+	// Ensure the concurrent requests of each IP are limited independently
+	// and that releasing a request frees up its slot.
+	l = newRPCLimiter(0, 0, 0, 2)
+	release1, jsonErr := l.acquire(alice, addr1)
+	if jsonErr != nil {
+		t.Fatalf("unexpected error for first request: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(bob, addr1+"1"); jsonErr != nil {
+		t.Fatalf("unexpected error for second request: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(alice, addr1); jsonErr == nil {
+		t.Fatal("did not receive expected error for third request")
+	}
+	if _, jsonErr := l.acquire(alice, addr2); jsonErr != nil {
+		t.Fatalf("unexpected error for other IP: %v", jsonErr)
+	}
+	release1()
+	release1()
+	if _, jsonErr := l.acquire(alice, addr1); jsonErr != nil {
+		t.Fatalf("unexpected error after release: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(alice, addr1); jsonErr == nil {
+		t.Fatal("releasing a request more than once freed multiple slots")
+	}
+
+	info := l.info()
+	if info.Allowed != 4 || info.ConcurrencyLimited != 2 ||
+		info.RateLimited != 0 || info.IPMaxConcurrentReqs != 2 {
+
+		t.Fatalf("unexpected limiter info: %+v", info)
+	}
+	if len(info.Clients) != 2 || info.Clients[0].ID != "127.0.0.1" ||
+		info.Clients[0].Type != "ip" || info.Clients[0].InFlight != 2 ||
+		info.Clients[0].Allowed != 3 || info.Clients[0].Limited != 2 {
+
+		t.Fatalf("unexpected limiter clients: %+v", info.Clients)
+	}
+
+	// Ensure the user limits also apply when combined with the IP limits.
+	l = newRPCLimiter(0, 10, 1, 0)
+	if _, jsonErr := l.acquire(alice, addr1); jsonErr != nil {
+		t.Fatalf("unexpected error for first request: %v", jsonErr)
+	}
+	if _, jsonErr := l.acquire(alice, addr2); jsonErr == nil {
+		t.Fatal("did not receive expected error for concurrent user request")
+	}
+}
+
+// TestRPCLimitedResponse ensures single HTTP POST requests that exceed the
+// limits are responded to with a too many requests status and a JSON-RPC
+// error.
+func TestRPCLimitedResponse(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCMaxClients = 10
+	cfg.RPCIPReqRate = 1
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	post := func() (int, *dcrjson.RPCError) {
+		t.Helper()
+		body := `{"jsonrpc":"1.0","method":"getblockcount","params":[],"id":1}`
+		resp, err := http.Post(httpServer.URL, "application/json",
+			strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error posting request: %v", err)
+		}
+		defer resp.Body.Close()
+		var reply struct {
+			Error *dcrjson.RPCError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+			t.Fatalf("unexpected error decoding reply: %v", err)
+		}
+		return resp.StatusCode, reply.Error
+	}
+
+	if status, jsonErr := post(); status != http.StatusOK || jsonErr != nil {
+		t.Fatalf("unexpected reply for first request: %d (%v)", status,
+			jsonErr)
+	}
+	status, jsonErr := post()
+	if status != http.StatusTooManyRequests || jsonErr == nil ||
+		jsonErr.Code != dcrjson.ErrRPCTooManyRequests {
+
+		t.Fatalf("unexpected reply for limited request: %d (%v)", status,
+			jsonErr)
+	}
+}
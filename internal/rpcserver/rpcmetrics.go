@@ -0,0 +1,295 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/internal/metrics"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+const (
+	// rpcMetricsPath is the path the RPC metrics are served at in the
+	// Prometheus text exposition format.
+	rpcMetricsPath = "/metrics"
+
+	// slowCallMaxParamLen is the maximum length of the string parameters of
+	// slow calls that are logged before they are elided.
+	slowCallMaxParamLen = 64
+)
+
+// methodMetrics houses the latency histogram, number of errors, and number of
+// in-flight calls of a single RPC method.
+type methodMetrics struct {
+	// inFlight is the number of calls to the method that are currently being
+	// processed.
+	inFlight int64
+
+	// errors is the number of completed calls to the method that resulted in
+	// an error.
+	errors uint64
+
+	// latency tracks the time spent processing the completed calls.
+	latency *metrics.Histogram
+}
+
+// methodSnapshot houses the state of the metrics of a single RPC method at the
+// time they are written.
+type methodSnapshot struct {
+	inFlight int64
+	errors   uint64
+	latency  metrics.HistogramSnapshot
+}
+
+// rpcMetrics tracks per-method latency histograms, error counts, and in-flight
+// calls for the RPC server so operators are able to identify expensive calls.
+//
+// Only methods with registered handlers are tracked, which ensures the number
+// of tracked methods is bounded regardless of the requests clients send.
+type rpcMetrics struct {
+	mtx     sync.Mutex
+	methods map[types.Method]*methodMetrics
+}
+
+// newRPCMetrics returns a new empty instance of RPC metrics.
+func newRPCMetrics() *rpcMetrics {
+	return &rpcMetrics{
+		methods: make(map[types.Method]*methodMetrics),
+	}
+}
+
+// method returns the metrics for the provided method while creating them if
+// needed.
+//
+// This function MUST be called with the mutex held (for writes).
+func (m *rpcMetrics) method(method types.Method) *methodMetrics {
+	mm, ok := m.methods[method]
+	if !ok {
+		mm = &methodMetrics{
+			latency: metrics.NewHistogram(metrics.DurationBuckets),
+		}
+		m.methods[method] = mm
+	}
+	return mm
+}
+
+// begin records the start of a call to the provided method.
+//
+// This function is safe for concurrent access.
+func (m *rpcMetrics) begin(method types.Method) {
+	m.mtx.Lock()
+	m.method(method).inFlight++
+	m.mtx.Unlock()
+}
+
+// end records the completion of a call to the provided method that took the
+// provided amount of time and whether or not it resulted in an error.
+//
+// This function is safe for concurrent access.
+func (m *rpcMetrics) end(method types.Method, elapsed time.Duration, failed bool) {
+	m.mtx.Lock()
+	mm := m.method(method)
+	mm.inFlight--
+	if failed {
+		mm.errors++
+	}
+	mm.latency.ObserveDuration(elapsed)
+	m.mtx.Unlock()
+}
+
+// writeTo writes the metrics of all tracked methods to the provided writer in
+// the Prometheus text exposition format.  The methods are sorted by name.
+//
+// This function is safe for concurrent access.
+func (m *rpcMetrics) writeTo(w io.Writer) error {
+	m.mtx.Lock()
+	names := make([]string, 0, len(m.methods))
+	snapshot := make(map[string]*methodSnapshot, len(m.methods))
+	for method, mm := range m.methods {
+		names = append(names, string(method))
+		snapshot[string(method)] = &methodSnapshot{
+			inFlight: mm.inFlight,
+			errors:   mm.errors,
+			latency:  mm.latency.Snapshot(),
+		}
+	}
+	m.mtx.Unlock()
+	sort.Strings(names)
+
+	mw := metrics.NewWriter(w)
+	mw.Header("dcrd_rpc_request_duration_seconds", "Time taken to process "+
+		"RPC requests by method.", "histogram")
+	for _, name := range names {
+		mw.HistogramSample("dcrd_rpc_request_duration_seconds",
+			&snapshot[name].latency, "method", name)
+	}
+
+	mw.Header("dcrd_rpc_request_errors_total", "Number of RPC requests that "+
+		"resulted in an error by method.", "counter")
+	for _, name := range names {
+		mw.Sample("dcrd_rpc_request_errors_total", snapshot[name].errors,
+			"method", name)
+	}
+
+	mw.Header("dcrd_rpc_requests_in_flight", "Number of RPC requests "+
+		"currently being processed by method.", "gauge")
+	for _, name := range names {
+		mw.FloatSample("dcrd_rpc_requests_in_flight",
+			float64(snapshot[name].inFlight), "method", name)
+	}
+
+	return mw.Flush()
+}
+
+// redactValue returns the provided JSON-decoded value with the values of any
+// object keys that look like they hold credentials replaced and long strings,
+// such as serialized transactions, elided.
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if len(v) > slowCallMaxParamLen {
+			return fmt.Sprintf("%s...(%d bytes)", v[:slowCallMaxParamLen/4],
+				len(v))
+		}
+		return v
+
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+
+	case map[string]interface{}:
+		for key, val := range v {
+			if strings.Contains(strings.ToLower(key), "pass") {
+				v[key] = "[redacted]"
+				continue
+			}
+			v[key] = redactValue(val)
+		}
+		return v
+	}
+
+	return v
+}
+
+// redactParams returns a string representation of the provided parsed command
+// parameters that is suitable for logging.  The values of parameters that look
+// like they hold credentials are replaced and long strings are elided.
+func redactParams(params interface{}) string {
+	if params == nil {
+		return "{}"
+	}
+	marshalled, err := json.Marshal(params)
+	if err != nil {
+		return "[unavailable]"
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(marshalled))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "[unavailable]"
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "[unavailable]"
+	}
+	return string(redacted)
+}
+
+// observeCall records the start of a call to the handler of the provided
+// command in the per-method metrics and returns a function that must be
+// invoked with the error returned by the handler, if any, once it completes.
+//
+// Calls that take at least the configured slow call threshold are logged
+// along with the client that issued them and their redacted parameters.
+func (s *Server) observeCall(cmd *parsedRPCCmd, user *rpcAuthUser, remoteAddr string) func(error) {
+	s.metrics.begin(cmd.method)
+	start := time.Now()
+	return func(err error) {
+		elapsed := time.Since(start)
+		s.metrics.end(cmd.method, elapsed, err != nil)
+
+		threshold := s.cfg.RPCSlowCallThreshold
+		if threshold > 0 && elapsed >= threshold {
+			var userName string
+			if user != nil {
+				userName = user.name
+			}
+			log.Warnf("Slow RPC call <%s> from %s (user %q) took %v with "+
+				"params %s", cmd.method, remoteAddr, userName,
+				elapsed.Round(time.Millisecond), redactParams(cmd.params))
+		}
+	}
+}
+
+// WriteMetrics writes the current RPC metrics, including the client limiter
+// statistics, to the provided writer in the Prometheus text exposition format.
+// It does not include the metrics of the additional metrics sources.
+//
+// This allows the RPC metrics to be served by other metrics servers.
+func (s *Server) WriteMetrics(w io.Writer) error {
+	if err := s.metrics.writeTo(w); err != nil {
+		return err
+	}
+	return s.limiter.writeTo(w)
+}
+
+// handleMetrics serves the RPC metrics in the Prometheus text exposition
+// format.  The metrics are only available to users that are authorized for all
+// methods.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Connection", "close")
+	r.Close = true
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method not allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Limit the number of connections to max allowed.
+	if s.limitConnections(w, r.RemoteAddr) {
+		return
+	}
+
+	// Keep track of the number of connected clients.
+	s.incrementClients()
+	defer s.decrementClients()
+
+	_, user, err := s.checkAuth(r, true)
+	if err != nil {
+		jsonAuthFail(w)
+		return
+	}
+	if !user.isAdmin() {
+		http.Error(w, "403 Forbidden.", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := s.WriteMetrics(w); err != nil {
+		log.Debugf("Failed to write RPC metrics to %s: %v", r.RemoteAddr,
+			err)
+		return
+	}
+	for _, source := range s.cfg.MetricsSources {
+		if err := source.WriteMetrics(w); err != nil {
+			log.Debugf("Failed to write metrics to %s: %v", r.RemoteAddr,
+				err)
+			return
+		}
+	}
+}
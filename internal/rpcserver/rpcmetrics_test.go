@@ -0,0 +1,186 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// TestRPCMetrics ensures the per-method metrics track the latency, errors, and
+// in-flight calls and are written in the expected format.
+func TestRPCMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := newRPCMetrics()
+	m.begin("getblock")
+	m.begin("getblock")
+	m.end("getblock", 3*time.Millisecond, false)
+	m.begin("getinfo")
+	m.end("getinfo", time.Minute, true)
+
+	var buf bytes.Buffer
+	if err := m.writeTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	wantLines := []string{
+		`# TYPE dcrd_rpc_request_duration_seconds histogram`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getblock",le="0.001"} 0`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getblock",le="0.005"} 1`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getblock",le="30"} 1`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getblock",le="+Inf"} 1`,
+		`dcrd_rpc_request_duration_seconds_sum{method="getblock"} 0.003`,
+		`dcrd_rpc_request_duration_seconds_count{method="getblock"} 1`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getinfo",le="30"} 0`,
+		`dcrd_rpc_request_duration_seconds_bucket{method="getinfo",le="+Inf"} 1`,
+		`dcrd_rpc_request_duration_seconds_sum{method="getinfo"} 60`,
+		`dcrd_rpc_request_errors_total{method="getblock"} 0`,
+		`dcrd_rpc_request_errors_total{method="getinfo"} 1`,
+		`dcrd_rpc_requests_in_flight{method="getblock"} 1`,
+		`dcrd_rpc_requests_in_flight{method="getinfo"} 0`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing expected line %q in:\n%s", line, got)
+		}
+	}
+	if strings.Index(got, `method="getblock"`) > strings.Index(got,
+		`method="getinfo"`) {
+
+		t.Errorf("methods are not sorted:\n%s", got)
+	}
+}
+
+// TestRedactParams ensures the parameters of slow calls are redacted as
+// expected.
+func TestRedactParams(t *testing.T) {
+	t.Parallel()
+
+	longHex := strings.Repeat("ab", 100)
+	tests := []struct {
+		name   string
+		params interface{}
+		want   string
+	}{{
+		name:   "no params",
+		params: nil,
+		want:   `{}`,
+	}, {
+		name:   "large integer",
+		params: types.NewGetBlockHashCmd(1000000),
+		want:   `{"Index":1000000}`,
+	}, {
+		name:   "passphrase",
+		params: types.NewAuthenticateCmd("user", "secret"),
+		want:   `{"Passphrase":"[redacted]","Username":"user"}`,
+	}, {
+		name:   "long string",
+		params: types.NewSendRawTransactionCmd(longHex, nil),
+		want:   `{"AllowHighFees":null,"HexTx":"abababababababab...(200 bytes)"}`,
+	}}
+	for _, test := range tests {
+		if got := redactParams(test.params); got != test.want {
+			t.Errorf("%s: unexpected params -- got %s, want %s", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// testMetricsSource provides fixed metrics for testing the metrics endpoint.
+type testMetricsSource string
+
+// WriteMetrics writes the fixed metrics to the provided writer.
+func (m testMetricsSource) WriteMetrics(w io.Writer) error {
+	_, err := io.WriteString(w, string(m))
+	return err
+}
+
+// TestHandleMetrics ensures the metrics endpoint reflects the calls processed
+// by the RPC server along with any additional metrics sources and is only
+// available to users that are authorized for all methods.
+func TestHandleMetrics(t *testing.T) {
+	const extraMetrics = "dcrd_test_metric 1\n"
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.MetricsSources = []MetricsSource{testMetricsSource(extraMetrics)}
+	cfg.RPCMaxClients = 10
+	cfg.RPCIPReqRate = 100
+	cfg.RPCUser, cfg.RPCPass = "admin", "adminpass"
+	cfg.RPCLimitUser, cfg.RPCLimitPass = "limited", "limitedpass"
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	do := func(method, path, body, user, pass string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, httpServer.URL+path,
+			strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unable to create request: %v", err)
+		}
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error issuing request: %v", err)
+		}
+		defer resp.Body.Close()
+		reply, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading reply: %v", err)
+		}
+		return resp.StatusCode, string(reply)
+	}
+
+	body := `{"jsonrpc":"1.0","method":"getblockcount","params":[],"id":1}`
+	if status, _ := do(http.MethodPost, "/", body, "admin",
+		"adminpass"); status != http.StatusOK {
+
+		t.Fatalf("unexpected status for RPC request: %d", status)
+	}
+
+	status, reply := do(http.MethodGet, rpcMetricsPath, "", "admin",
+		"adminpass")
+	want := `dcrd_rpc_request_duration_seconds_count{method="getblockcount"} 1`
+	if status != http.StatusOK || !strings.Contains(reply, want) {
+		t.Fatalf("unexpected metrics reply: %d\n%s", status, reply)
+	}
+	want = `dcrd_rpc_limiter_requests_total{result="allowed"} 1`
+	if !strings.Contains(reply, want) {
+		t.Fatalf("missing limiter metrics in reply:\n%s", reply)
+	}
+	if !strings.HasSuffix(reply, extraMetrics) {
+		t.Fatalf("missing additional metrics in reply:\n%s", reply)
+	}
+	if status, _ := do(http.MethodGet, rpcMetricsPath, "", "limited",
+		"limitedpass"); status != http.StatusForbidden {
+
+		t.Fatalf("unexpected status for limited user: %d", status)
+	}
+	if status, _ := do(http.MethodGet, rpcMetricsPath, "", "", ""); status !=
+		http.StatusUnauthorized {
+
+		t.Fatalf("unexpected status without credentials: %d", status)
+	}
+	if status, _ := do(http.MethodPost, rpcMetricsPath, "", "admin",
+		"adminpass"); status != http.StatusMethodNotAllowed {
+
+		t.Fatalf("unexpected status for POST: %d", status)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -26,6 +25,8 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -46,12 +47,15 @@ import (
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/blockchain/indexers"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/ownership"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/dcrd/txscript/v4/stdscript"
 	"github.com/decred/dcrd/wire"
@@ -74,6 +78,11 @@ const (
 	// JSON-RPC message read from a client.
 	rpcReadLimitAuthenticated = 1 << 23 // 8 MiB
 
+	// rpcMaxBatchSize is the maximum number of requests allowed in a single
+	// batched JSON-RPC request.  Larger batches are rejected as a whole so
+	// a single message is unable to tie up the server for extended periods.
+	rpcMaxBatchSize = 100
+
 	// uint256Size is the number of bytes needed to represent an unsigned
 	// 256-bit integer.
 	uint256Size = 32
@@ -97,6 +106,18 @@ const (
 	// the template pool.
 	getworkExpirationDiff = 3
 
+	// searchRawTransactionsMaxCount is the maximum number of transactions
+	// that may be requested by a single searchrawtransactions request.
+	searchRawTransactionsMaxCount = 1000
+
+	// getBlocksByTimeMaxCount is the maximum number of blocks that may be
+	// requested by a single getblocksbytime request.
+	getBlocksByTimeMaxCount = 1000
+
+	// getCFiltersV2MaxCount is the maximum number of filters that may be
+	// requested by a single getcfiltersv2 request.
+	getCFiltersV2MaxCount = 1000
+
 	// sstxCommitmentString is the string to insert when a verbose
 	// transaction output's pkscript type is a ticket commitment.
 	sstxCommitmentString = "sstxcommitment"
@@ -108,6 +129,10 @@ const (
 	// syncWait is the maximum time in seconds to wait for an index
 	// to sync with the main chain.
 	syncWait = time.Second * 3
+
+	// maxScanTxOutSetObjects is the maximum number of scan objects allowed
+	// in a single scantxoutset command.
+	maxScanTxOutSetObjects = 1000
 )
 
 var (
@@ -155,78 +180,102 @@ type commandHandler func(context.Context, *Server, interface{}) (interface{}, er
 // a dependency loop.
 var rpcHandlers map[types.Method]commandHandler
 var rpcHandlersBeforeInit = map[types.Method]commandHandler{
-	"addnode":               handleAddNode,
-	"createrawsstx":         handleCreateRawSStx,
-	"createrawssrtx":        handleCreateRawSSRtx,
-	"createrawtransaction":  handleCreateRawTransaction,
-	"debuglevel":            handleDebugLevel,
-	"decoderawtransaction":  handleDecodeRawTransaction,
-	"decodescript":          handleDecodeScript,
-	"estimatefee":           handleEstimateFee,
-	"estimatesmartfee":      handleEstimateSmartFee,
-	"estimatestakediff":     handleEstimateStakeDiff,
-	"existsaddress":         handleExistsAddress,
-	"existsaddresses":       handleExistsAddresses,
-	"existsliveticket":      handleExistsLiveTicket,
-	"existslivetickets":     handleExistsLiveTickets,
-	"existsmempooltxs":      handleExistsMempoolTxs,
-	"generate":              handleGenerate,
-	"getaddednodeinfo":      handleGetAddedNodeInfo,
-	"getbestblock":          handleGetBestBlock,
-	"getbestblockhash":      handleGetBestBlockHash,
-	"getblock":              handleGetBlock,
-	"getblockchaininfo":     handleGetBlockchainInfo,
-	"getblockcount":         handleGetBlockCount,
-	"getblockhash":          handleGetBlockHash,
-	"getblockheader":        handleGetBlockHeader,
-	"getblocksubsidy":       handleGetBlockSubsidy,
-	"getcfilterv2":          handleGetCFilterV2,
-	"getchaintips":          handleGetChainTips,
-	"getcoinsupply":         handleGetCoinSupply,
-	"getconnectioncount":    handleGetConnectionCount,
-	"getcurrentnet":         handleGetCurrentNet,
-	"getdifficulty":         handleGetDifficulty,
-	"getgenerate":           handleGetGenerate,
-	"gethashespersec":       handleGetHashesPerSec,
-	"getheaders":            handleGetHeaders,
-	"getinfo":               handleGetInfo,
-	"getmempoolinfo":        handleGetMempoolInfo,
-	"getmininginfo":         handleGetMiningInfo,
-	"getnettotals":          handleGetNetTotals,
-	"getnetworkhashps":      handleGetNetworkHashPS,
-	"getnetworkinfo":        handleGetNetworkInfo,
-	"getpeerinfo":           handleGetPeerInfo,
-	"getrawmempool":         handleGetRawMempool,
-	"getrawtransaction":     handleGetRawTransaction,
-	"getstakedifficulty":    handleGetStakeDifficulty,
-	"getstakeversioninfo":   handleGetStakeVersionInfo,
-	"getstakeversions":      handleGetStakeVersions,
-	"getticketpoolvalue":    handleGetTicketPoolValue,
-	"gettreasurybalance":    handleGetTreasuryBalance,
-	"gettreasuryspendvotes": handleGetTreasurySpendVotes,
-	"getvoteinfo":           handleGetVoteInfo,
-	"gettxout":              handleGetTxOut,
-	"gettxoutsetinfo":       handleGetTxOutSetInfo,
-	"getwork":               handleGetWork,
-	"help":                  handleHelp,
-	"invalidateblock":       handleInvalidateBlock,
-	"livetickets":           handleLiveTickets,
-	"node":                  handleNode,
-	"ping":                  handlePing,
-	"reconsiderblock":       handleReconsiderBlock,
-	"regentemplate":         handleRegenTemplate,
-	"sendrawtransaction":    handleSendRawTransaction,
-	"setgenerate":           handleSetGenerate,
-	"stop":                  handleStop,
-	"submitblock":           handleSubmitBlock,
-	"ticketfeeinfo":         handleTicketFeeInfo,
-	"ticketsforaddress":     handleTicketsForAddress,
-	"ticketvwap":            handleTicketVWAP,
-	"txfeeinfo":             handleTxFeeInfo,
-	"validateaddress":       handleValidateAddress,
-	"verifychain":           handleVerifyChain,
-	"verifymessage":         handleVerifyMessage,
-	"version":               handleVersion,
+	"addnode":                handleAddNode,
+	"backupchainstate":       handleBackupChainState,
+	"clearbanned":            handleClearBanned,
+	"createrawsstx":          handleCreateRawSStx,
+	"createrawssrtx":         handleCreateRawSSRtx,
+	"createrawtransaction":   handleCreateRawTransaction,
+	"debuglevel":             handleDebugLevel,
+	"decoderawtransaction":   handleDecodeRawTransaction,
+	"decodescript":           handleDecodeScript,
+	"dumptxoutset":           handleDumpTxOutSet,
+	"estimatefee":            handleEstimateFee,
+	"estimatesmartfee":       handleEstimateSmartFee,
+	"estimatestakediff":      handleEstimateStakeDiff,
+	"existsaddress":          handleExistsAddress,
+	"existsaddresses":        handleExistsAddresses,
+	"existsliveticket":       handleExistsLiveTicket,
+	"existslivetickets":      handleExistsLiveTickets,
+	"existsmempooltxs":       handleExistsMempoolTxs,
+	"generate":               handleGenerate,
+	"getaddednodeinfo":       handleGetAddedNodeInfo,
+	"getbestblock":           handleGetBestBlock,
+	"getbestblockhash":       handleGetBestBlockHash,
+	"getblock":               handleGetBlock,
+	"getblockbytime":         handleGetBlockByTime,
+	"getblockchaininfo":      handleGetBlockchainInfo,
+	"getblockcount":          handleGetBlockCount,
+	"getblockhash":           handleGetBlockHash,
+	"getblockheader":         handleGetBlockHeader,
+	"getblocksbytime":        handleGetBlocksByTime,
+	"getblocksubsidy":        handleGetBlockSubsidy,
+	"getcfiltersv2":          handleGetCFiltersV2,
+	"getcfilterv2":           handleGetCFilterV2,
+	"getchaintips":           handleGetChainTips,
+	"getcoinsupply":          handleGetCoinSupply,
+	"getconnectioncount":     handleGetConnectionCount,
+	"getcurrentnet":          handleGetCurrentNet,
+	"getdebuglevels":         handleGetDebugLevels,
+	"getdifficulty":          handleGetDifficulty,
+	"getgenerate":            handleGetGenerate,
+	"gethashespersec":        handleGetHashesPerSec,
+	"getheaders":             handleGetHeaders,
+	"getinfo":                handleGetInfo,
+	"getmempoolancestors":    handleGetMempoolAncestors,
+	"getmempooldescendants":  handleGetMempoolDescendants,
+	"getmempoolentry":        handleGetMempoolEntry,
+	"getmempoolinfo":         handleGetMempoolInfo,
+	"getmininginfo":          handleGetMiningInfo,
+	"getnettotals":           handleGetNetTotals,
+	"getnetworkhashps":       handleGetNetworkHashPS,
+	"getnodestatus":          handleGetNodeStatus,
+	"getnetworkinfo":         handleGetNetworkInfo,
+	"getpeerinfo":            handleGetPeerInfo,
+	"getrawmempool":          handleGetRawMempool,
+	"getrawtransaction":      handleGetRawTransaction,
+	"getrejectedtransaction": handleGetRejectedTransaction,
+	"getrpclimitinfo":        handleGetRPCLimitInfo,
+	"getspentinfo":           handleGetSpentInfo,
+	"getstakedifficulty":     handleGetStakeDifficulty,
+	"getstakeversioninfo":    handleGetStakeVersionInfo,
+	"getstakeversions":       handleGetStakeVersions,
+	"getsubmitblockstatus":   handleGetSubmitBlockStatus,
+	"getsyncstatus":          handleGetSyncStatus,
+	"getticketpoolvalue":     handleGetTicketPoolValue,
+	"gettreasurybalance":     handleGetTreasuryBalance,
+	"gettreasuryspendvotes":  handleGetTreasurySpendVotes,
+	"getvoteinfo":            handleGetVoteInfo,
+	"gettxout":               handleGetTxOut,
+	"gettxoutsetinfo":        handleGetTxOutSetInfo,
+	"getwork":                handleGetWork,
+	"help":                   handleHelp,
+	"invalidateblock":        handleInvalidateBlock,
+	"listbanned":             handleListBanned,
+	"livetickets":            handleLiveTickets,
+	"node":                   handleNode,
+	"ping":                   handlePing,
+	"prioritisetransaction":  handlePrioritiseTransaction,
+	"reconsiderblock":        handleReconsiderBlock,
+	"regentemplate":          handleRegenTemplate,
+	"rpc.discover":           handleRPCDiscover,
+	"scantxoutset":           handleScanTxOutSet,
+	"searchrawtransactions":  handleSearchRawTransactions,
+	"sendrawtransaction":     handleSendRawTransaction,
+	"setban":                 handleSetBan,
+	"setgenerate":            handleSetGenerate,
+	"stop":                   handleStop,
+	"submitblock":            handleSubmitBlock,
+	"submitblocknowait":      handleSubmitBlockNoWait,
+	"ticketfeeinfo":          handleTicketFeeInfo,
+	"ticketsforaddress":      handleTicketsForAddress,
+	"ticketvwap":             handleTicketVWAP,
+	"txfeeinfo":              handleTxFeeInfo,
+	"validateaddress":        handleValidateAddress,
+	"verifychain":            handleVerifyChain,
+	"verifymessage":          handleVerifyMessage,
+	"verifyownershipproof":   handleVerifyOwnershipProof,
+	"version":                handleVersion,
 }
 
 // list of commands that we recognize, but for which dcrd has no support because
@@ -324,63 +373,80 @@ var rpcLimited = map[string]struct{}{
 	"notifyblocks":          {},
 	"notifynewtransactions": {},
 	"rescan":                {},
+	"rescanfromheight":      {},
 	"session":               {},
+	"streamrawmempool":      {},
 	"rebroadcastwinners":    {},
 
 	// Websockets AND HTTP/S commands
-	"help": {},
+	"help":         {},
+	"rpc.discover": {},
 
 	// HTTP/S-only commands
-	"createrawsstx":        {},
-	"createrawssrtx":       {},
-	"createrawtransaction": {},
-	"decoderawtransaction": {},
-	"decodescript":         {},
-	"estimatefee":          {},
-	"estimatesmartfee":     {},
-	"estimatestakediff":    {},
-	"existsaddress":        {},
-	"existsaddresses":      {},
-	"existsliveticket":     {},
-	"existslivetickets":    {},
-	"existsmempooltxs":     {},
-	"getbestblock":         {},
-	"getbestblockhash":     {},
-	"getblock":             {},
-	"getblockchaininfo":    {},
-	"getblockcount":        {},
-	"getblockhash":         {},
-	"getblockheader":       {},
-	"getblocksubsidy":      {},
-	"getcfilterv2":         {},
-	"getchaintips":         {},
-	"getcoinsupply":        {},
-	"getcurrentnet":        {},
-	"getdifficulty":        {},
-	"getheaders":           {},
-	"getinfo":              {},
-	"getnettotals":         {},
-	"getnetworkhashps":     {},
-	"getnetworkinfo":       {},
-	"getrawmempool":        {},
-	"getstakedifficulty":   {},
-	"getstakeversioninfo":  {},
-	"getstakeversions":     {},
-	"getrawtransaction":    {},
-	"gettreasurybalance":   {},
-	"gettxout":             {},
-	"getvoteinfo":          {},
-	"livetickets":          {},
-	"regentemplate":        {},
-	"sendrawtransaction":   {},
-	"submitblock":          {},
-	"ticketfeeinfo":        {},
-	"ticketsforaddress":    {},
-	"ticketvwap":           {},
-	"txfeeinfo":            {},
-	"validateaddress":      {},
-	"verifymessage":        {},
-	"version":              {},
+	"createrawsstx":          {},
+	"createrawssrtx":         {},
+	"createrawtransaction":   {},
+	"decoderawtransaction":   {},
+	"decodescript":           {},
+	"estimatefee":            {},
+	"estimatesmartfee":       {},
+	"estimatestakediff":      {},
+	"existsaddress":          {},
+	"existsaddresses":        {},
+	"existsliveticket":       {},
+	"existslivetickets":      {},
+	"existsmempooltxs":       {},
+	"getbestblock":           {},
+	"getbestblockhash":       {},
+	"getblock":               {},
+	"getblockbytime":         {},
+	"getblockchaininfo":      {},
+	"getblockcount":          {},
+	"getblockhash":           {},
+	"getblockheader":         {},
+	"getblocksbytime":        {},
+	"getblocksubsidy":        {},
+	"getcfiltersv2":          {},
+	"getcfilterv2":           {},
+	"getchaintips":           {},
+	"getcoinsupply":          {},
+	"getcurrentnet":          {},
+	"getdifficulty":          {},
+	"getheaders":             {},
+	"getinfo":                {},
+	"getmempoolancestors":    {},
+	"getmempooldescendants":  {},
+	"getmempoolentry":        {},
+	"getnettotals":           {},
+	"getnetworkhashps":       {},
+	"getnodestatus":          {},
+	"getnetworkinfo":         {},
+	"getrawmempool":          {},
+	"getstakedifficulty":     {},
+	"getstakeversioninfo":    {},
+	"getstakeversions":       {},
+	"getsubmitblockstatus":   {},
+	"getsyncstatus":          {},
+	"getrawtransaction":      {},
+	"getrejectedtransaction": {},
+	"getspentinfo":           {},
+	"gettreasurybalance":     {},
+	"gettxout":               {},
+	"getvoteinfo":            {},
+	"livetickets":            {},
+	"regentemplate":          {},
+	"searchrawtransactions":  {},
+	"sendrawtransaction":     {},
+	"submitblock":            {},
+	"submitblocknowait":      {},
+	"ticketfeeinfo":          {},
+	"ticketsforaddress":      {},
+	"ticketvwap":             {},
+	"txfeeinfo":              {},
+	"validateaddress":        {},
+	"verifymessage":          {},
+	"verifyownershipproof":   {},
+	"version":                {},
 }
 
 // rpcInternalError is a convenience function to convert an internal error to
@@ -664,6 +730,87 @@ func handleNode(_ context.Context, s *Server, cmd interface{}) (interface{}, err
 	return nil, nil
 }
 
+// handleSetBan implements the setban command.
+func handleSetBan(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SetBanCmd)
+
+	subnet, err := banmgr.ParseSubnet(c.Subnet)
+	if err != nil {
+		return nil, rpcInvalidError("%v", err)
+	}
+
+	connMgr := s.cfg.ConnMgr
+	switch c.SubCmd {
+	case types.SBAdd:
+		var banTime int64
+		if c.BanTime != nil {
+			banTime = *c.BanTime
+		}
+		absolute := c.Absolute != nil && *c.Absolute
+		if banTime < 0 {
+			return nil, rpcInvalidError("%v: ban time must not be negative",
+				c.SubCmd)
+		}
+
+		var expires time.Time
+		switch {
+		case absolute:
+			expires = time.Unix(banTime, 0)
+			if !expires.After(s.cfg.Clock.Now()) {
+				return nil, rpcInvalidError("%v: absolute ban time must be "+
+					"in the future", c.SubCmd)
+			}
+		case banTime == 0:
+			expires = s.cfg.Clock.Now().Add(s.cfg.BanDuration)
+		default:
+			expires = s.cfg.Clock.Now().Add(time.Duration(banTime) *
+				time.Second)
+		}
+		err = connMgr.Ban(subnet, expires)
+
+	case types.SBRemove:
+		err = connMgr.Unban(subnet)
+		if errors.Is(err, banmgr.ErrNotBanned) {
+			return nil, rpcInvalidError("%v: %v", c.SubCmd, err)
+		}
+
+	default:
+		return nil, rpcInvalidError("%v: invalid subcommand for setban",
+			c.SubCmd)
+	}
+	if err != nil {
+		return nil, rpcMiscError(fmt.Sprintf("%v: %v", c.SubCmd, err))
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
+// handleListBanned implements the listbanned command.
+func handleListBanned(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	entries := s.cfg.ConnMgr.BannedSubnets()
+	result := make([]types.ListBannedResult, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, types.ListBannedResult{
+			Address:     entry.Subnet.String(),
+			BanCreated:  entry.Created.Unix(),
+			BannedUntil: entry.Expires.Unix(),
+			BanReason:   entry.Reason.String(),
+		})
+	}
+	return result, nil
+}
+
+// handleClearBanned implements the clearbanned command.
+func handleClearBanned(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	if err := s.cfg.ConnMgr.ClearBanned(); err != nil {
+		return nil, rpcMiscError(err.Error())
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // peerExists determines if a certain peer is currently connected given
 // information about all currently connected peers. Peer existence is
 // determined using either a target address or node id.
@@ -1098,6 +1245,11 @@ func handleDebugLevel(_ context.Context, s *Server, cmd interface{}) (interface{
 	return "Done.", nil
 }
 
+// handleGetDebugLevels implements the getdebuglevels command.
+func handleGetDebugLevels(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	return s.cfg.LogManager.DebugLevels(), nil
+}
+
 // createVinList returns a slice of JSON objects for the inputs of the passed
 // transaction.
 func createVinList(mtx *wire.MsgTx, isTreasuryEnabled bool) []types.Vin {
@@ -1179,6 +1331,107 @@ func createVinList(mtx *wire.MsgTx, isTreasuryEnabled bool) []types.Vin {
 	return vinList
 }
 
+// createPrevOut returns a JSON object for the passed previous output spent by
+// a transaction input.
+func createPrevOut(spent *blockchain.SpentOutput, chainParams *chaincfg.Params) *types.PrevOut {
+	// The disassembled string will contain [error] inline if the script
+	// doesn't fully parse, so ignore the error here.
+	disbuf, _ := txscript.DisasmString(spent.PkScript)
+
+	// Attempt to extract known addresses associated with the script.
+	st, addrs := stdscript.ExtractAddrs(spent.ScriptVersion, spent.PkScript,
+		chainParams)
+	encodedAddrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		encodedAddrs[i] = addr.String()
+	}
+	reqSigs := stdscript.DetermineRequiredSigs(spent.ScriptVersion,
+		spent.PkScript)
+
+	return &types.PrevOut{
+		Value: dcrutil.Amount(spent.Amount).ToCoin(),
+		ScriptPubKey: types.ScriptPubKeyResult{
+			Asm:       disbuf,
+			Hex:       hex.EncodeToString(spent.PkScript),
+			ReqSigs:   int32(reqSigs),
+			Type:      st.String(),
+			Addresses: encodedAddrs,
+			Version:   spent.ScriptVersion,
+		},
+	}
+}
+
+// addVinPrevOuts sets the previous output of every input in the passed list
+// that spends an output to the associated entry in the provided spent outputs.
+// Inputs that do not spend an output, such as coinbases and stakebases, along
+// with inputs that do not have an associated entry are not modified.
+func addVinPrevOuts(vinList []types.Vin, mtx *wire.MsgTx,
+	spent map[wire.OutPoint]blockchain.SpentOutput,
+	chainParams *chaincfg.Params) {
+
+	for i := range vinList {
+		vin := &vinList[i]
+		if vin.Txid == "" {
+			continue
+		}
+		spentOut, ok := spent[mtx.TxIn[i].PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		vin.PrevOut = createPrevOut(&spentOut, chainParams)
+	}
+}
+
+// fetchUnminedSpentOutputs returns the details of all outputs spent by the
+// passed transaction that is not yet in a block keyed by the outpoint of the
+// spent output.  The outputs are loaded from the set of unspent transaction
+// outputs as of the current best chain tip or the transactions in the memory
+// pool they are created by.  Outputs that are not found are not included.
+func (s *Server) fetchUnminedSpentOutputs(mtx *wire.MsgTx) (map[wire.OutPoint]blockchain.SpentOutput, error) {
+	spent := make(map[wire.OutPoint]blockchain.SpentOutput, len(mtx.TxIn))
+	for _, txIn := range mtx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if prevOut.Hash == zeroHash {
+			continue
+		}
+
+		entry, err := s.cfg.Chain.FetchUtxoEntry(prevOut)
+		if err != nil {
+			context := "Failed to fetch previous output"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		if entry != nil && !entry.IsSpent() {
+			spent[prevOut] = blockchain.SpentOutput{
+				Amount:          entry.Amount(),
+				ScriptVersion:   entry.ScriptVersion(),
+				PkScript:        entry.PkScript(),
+				BlockHeight:     uint32(entry.BlockHeight()),
+				BlockIndex:      txIn.BlockIndex,
+				IsCoinBase:      entry.IsCoinBase(),
+				TransactionType: entry.TransactionType(),
+			}
+			continue
+		}
+
+		// Fall back to the transaction in the memory pool that creates the
+		// output when it is not in the set of unspent outputs.
+		prevTx, err := s.cfg.TxMempooler.FetchTransaction(&prevOut.Hash)
+		if err != nil || prevOut.Index >= uint32(len(prevTx.MsgTx().TxOut)) {
+			continue
+		}
+		txOut := prevTx.MsgTx().TxOut[prevOut.Index]
+		spent[prevOut] = blockchain.SpentOutput{
+			Amount:          txOut.Value,
+			ScriptVersion:   txOut.Version,
+			PkScript:        txOut.PkScript,
+			BlockHeight:     wire.NullBlockHeight,
+			BlockIndex:      wire.NullBlockIndex,
+			TransactionType: stake.DetermineTxType(prevTx.MsgTx()),
+		}
+	}
+	return spent, nil
+}
+
 // createVoutList returns a slice of JSON objects for the outputs of the passed
 // transaction.
 func createVoutList(mtx *wire.MsgTx, chainParams *chaincfg.Params,
@@ -1351,26 +1604,55 @@ func handleDecodeRawTransaction(_ context.Context, s *Server, cmd interface{}) (
 	return txReply, nil
 }
 
-// handleDecodeScript handles decodescript commands.
-func handleDecodeScript(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	c := cmd.(*types.DecodeScriptCmd)
-
-	// Convert the hex script to bytes.
-	hexStr := c.HexScript
-	if len(hexStr)%2 != 0 {
-		hexStr = "0" + hexStr
+// stakeSubClass returns the standard type of the script that is tagged by the
+// stake opcode that begins the provided script or an empty string when the
+// script is not tagged.
+func stakeSubClass(scriptVersion uint16, script []byte) string {
+	if len(script) == 0 {
+		return ""
 	}
-	script, err := hex.DecodeString(hexStr)
-	if err != nil {
-		return nil, rpcDecodeHexError(hexStr)
+	switch script[0] {
+	case txscript.OP_SSTX, txscript.OP_SSGEN, txscript.OP_SSRTX,
+		txscript.OP_SSTXCHANGE, txscript.OP_TGEN:
+	default:
+		return ""
+	}
+	subClass := stdscript.DetermineScriptType(scriptVersion, script[1:])
+	if subClass == stdscript.STNonStandard {
+		return ""
+	}
+	return subClass.String()
+}
+
+// decodeScriptOps returns the disassembly of each opcode in the provided script
+// along with its offset in the script.  A final entry with a disassembly of
+// [error] is included when the script does not fully parse.
+func decodeScriptOps(scriptVersion uint16, script []byte) []types.DecodeScriptOp {
+	ops := make([]types.DecodeScriptOp, 0)
+	var offset int
+	tokenizer := txscript.MakeScriptTokenizer(scriptVersion, script)
+	for tokenizer.Next() {
+		next := int(tokenizer.ByteIndex())
+		disbuf, _ := txscript.DisasmString(script[offset:next])
+		ops = append(ops, types.DecodeScriptOp{
+			Offset: offset,
+			Asm:    disbuf,
+		})
+		offset = next
 	}
-
-	// Fetch the script version if provided.
-	scriptVersion := uint16(0)
-	if c.Version != nil {
-		scriptVersion = *c.Version
+	if tokenizer.Err() != nil {
+		ops = append(ops, types.DecodeScriptOp{
+			Offset: offset,
+			Asm:    "[error]",
+		})
 	}
+	return ops
+}
 
+// decodeScript returns a structured breakdown of the provided script.  The
+// provided redeem script, if any, is decoded as well when it matches the
+// script hash committed to by a pay-to-script-hash script.
+func decodeScript(s *Server, scriptVersion uint16, script, redeemScript []byte) (*types.DecodeScriptResult, error) {
 	// The disassembled string will contain [error] inline if the script
 	// doesn't fully parse, so ignore the error here.
 	disbuf, _ := txscript.DisasmString(script)
@@ -1397,19 +1679,218 @@ func handleDecodeScript(_ context.Context, s *Server, cmd interface{}) (interfac
 			"Failed to convert script to pay-to-script-hash")
 	}
 
-	// Generate and return the reply.
-	reply := types.DecodeScriptResult{
-		Asm:       disbuf,
-		ReqSigs:   int32(reqSigs),
-		Type:      scriptType.String(),
-		Addresses: addresses,
+	reply := &types.DecodeScriptResult{
+		Asm:           disbuf,
+		ReqSigs:       int32(reqSigs),
+		Type:          scriptType.String(),
+		StakeSubClass: stakeSubClass(scriptVersion, script),
+		Addresses:     addresses,
+		Ops:           decodeScriptOps(scriptVersion, script),
 	}
 	if scriptType != stdscript.STScriptHash {
 		reply.P2sh = p2sh.String()
 	}
+
+	// Decode the redeem script when provided after ensuring it matches the
+	// script hash.  This applies to both plain and stake-tagged
+	// pay-to-script-hash scripts.
+	if redeemScript != nil {
+		var scriptHash *stdaddr.AddressScriptHashV0
+		if len(addrs) == 1 {
+			scriptHash, _ = addrs[0].(*stdaddr.AddressScriptHashV0)
+		}
+		if scriptHash == nil {
+			return nil, rpcInvalidError("A redeem script may only be " +
+				"provided for pay-to-script-hash scripts")
+		}
+		if !bytes.Equal(stdaddr.Hash160(redeemScript), scriptHash.Hash160()[:]) {
+			return nil, rpcInvalidError("The redeem script does not match " +
+				"the script hash")
+		}
+		redeemReply, err := decodeScript(s, scriptVersion, redeemScript, nil)
+		if err != nil {
+			return nil, err
+		}
+		reply.RedeemScript = &types.DecodeRedeemScriptResult{
+			Asm:           redeemReply.Asm,
+			ReqSigs:       redeemReply.ReqSigs,
+			Type:          redeemReply.Type,
+			StakeSubClass: redeemReply.StakeSubClass,
+			Addresses:     redeemReply.Addresses,
+			P2sh:          redeemReply.P2sh,
+			Ops:           redeemReply.Ops,
+		}
+	}
+
 	return reply, nil
 }
 
+// handleDecodeScript handles decodescript commands.
+func handleDecodeScript(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.DecodeScriptCmd)
+
+	// Convert the hex script to bytes.
+	hexStr := c.HexScript
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	script, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+
+	// Fetch the script version if provided.
+	scriptVersion := uint16(0)
+	if c.Version != nil {
+		scriptVersion = *c.Version
+	}
+
+	// Convert the hex redeem script to bytes if provided.
+	var redeemScript []byte
+	if c.RedeemScript != nil {
+		redeemScript, err = hex.DecodeString(*c.RedeemScript)
+		if err != nil {
+			return nil, rpcDecodeHexError(*c.RedeemScript)
+		}
+	}
+
+	// Generate and return the reply.
+	reply, err := decodeScript(s, scriptVersion, script, redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	return *reply, nil
+}
+
+// chainStateBackupLogInterval is the minimum amount of time between log
+// messages that report the progress of a backupchainstate command.
+const chainStateBackupLogInterval = 10 * time.Second
+
+// handleBackupChainState implements the backupchainstate command.
+func handleBackupChainState(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.BackupChainStateCmd)
+
+	backuper := s.cfg.ChainStateBackuper
+	if backuper == nil {
+		return nil, rpcMiscError("Chain state backups are not available")
+	}
+
+	// Relative paths are relative to the data directory.
+	if c.Dir == "" {
+		return nil, rpcInvalidError("A directory must be specified")
+	}
+	dir := c.Dir
+	if !filepath.IsAbs(dir) {
+		if s.cfg.DataDir == "" {
+			return nil, rpcInvalidError("The directory must be absolute")
+		}
+		dir = filepath.Join(s.cfg.DataDir, dir)
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return nil, rpcInvalidError("Directory %s already exists", dir)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, rpcInternalError(err.Error(), "Unable to access directory")
+	}
+
+	// Only allow a single backup at a time since they are expensive.
+	if !atomic.CompareAndSwapInt32(&s.chainStateBackup, 0, 1) {
+		return nil, rpcMiscError("Chain state backup already in progress")
+	}
+	defer atomic.StoreInt32(&s.chainStateBackup, 0)
+
+	// Write the backup to a temporary directory that is renamed once it is
+	// complete so that a partially written backup is never mistaken for a
+	// complete one.
+	log.Infof("Backing up chain state to %s", dir)
+	start := s.cfg.Clock.Now()
+	lastLog := start
+	var bytesCopied uint64
+	tmpDir := dir + ".incomplete"
+	err := backuper.BackupChainState(ctx, tmpDir, func(p database.BackupProgress) {
+		bytesCopied = p.BytesCopied
+		if s.cfg.Clock.Since(lastLog) < chainStateBackupLogInterval {
+			return
+		}
+		lastLog = s.cfg.Clock.Now()
+		var percent float64
+		if p.TotalBytes > 0 {
+			percent = float64(p.BytesCopied) / float64(p.TotalBytes) * 100
+		}
+		log.Infof("Chain state backup progress: %d of %d bytes (%.2f%%)",
+			p.BytesCopied, p.TotalBytes, percent)
+	})
+	if err == nil {
+		err = os.Rename(tmpDir, dir)
+	}
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, rpcInternalError(err.Error(), "Unable to back up chain "+
+			"state")
+	}
+	elapsed := s.cfg.Clock.Since(start)
+	log.Infof("Backed up %d bytes of chain state to %s in %v", bytesCopied,
+		dir, elapsed.Round(time.Millisecond))
+
+	return &types.BackupChainStateResult{
+		Dir:     dir,
+		Bytes:   bytesCopied,
+		Elapsed: elapsed.Seconds(),
+	}, nil
+}
+
+// handleDumpTxOutSet implements the dumptxoutset command.
+func handleDumpTxOutSet(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.DumpTxOutSetCmd)
+
+	// Relative paths are relative to the data directory.
+	if c.Path == "" {
+		return nil, rpcInvalidError("A path must be specified")
+	}
+	path := c.Path
+	if !filepath.IsAbs(path) {
+		if s.cfg.DataDir == "" {
+			return nil, rpcInvalidError("The path must be absolute")
+		}
+		path = filepath.Join(s.cfg.DataDir, path)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, rpcInvalidError("File %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, rpcInternalError(err.Error(), "Unable to access file")
+	}
+
+	// Write the snapshot to a temporary file that is renamed once it is
+	// complete so that a partially written snapshot is never mistaken for a
+	// complete one.
+	tmpPath := path + ".incomplete"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Unable to create file")
+	}
+	info, err := s.cfg.Chain.DumpUtxoSet(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, rpcInternalError(err.Error(), "Unable to dump utxo set")
+	}
+
+	return &types.DumpTxOutSetResult{
+		Utxos:     info.Utxos,
+		BlockHash: info.BlockHash.String(),
+		Height:    info.BlockHeight,
+		MuHash:    info.MuHash.String(),
+		Path:      path,
+	}, nil
+}
+
 // handleEstimateFee implements the estimatefee command.
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
@@ -1875,14 +2356,7 @@ func handleGetBestBlockHash(_ context.Context, s *Server, _ interface{}) (interf
 // getDifficultyRatio returns the proof-of-work difficulty as a multiple of the
 // minimum difficulty using the passed bits field from the header of a block.
 func getDifficultyRatio(bits uint32, params *chaincfg.Params) float64 {
-	// The minimum difficulty is the max possible proof-of-work limit bits
-	// converted back to a number.  Note this is not the same as the proof
-	// of work limit directly because the block difficulty is encoded in a
-	// block with the compact form which loses precision.
-	max := standalone.CompactToBig(params.PowLimitBits)
-	target := standalone.CompactToBig(bits)
-
-	difficulty := new(big.Rat).SetFrac(max, target)
+	difficulty := standalone.CalcDifficultyRatio(bits, params.PowLimitBits)
 	outString := difficulty.FloatString(8)
 	diff, err := strconv.ParseFloat(outString, 64)
 	if err != nil {
@@ -2003,6 +2477,22 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 
 		blockReply.STx = stxNames
 	} else {
+		// Load the outputs spent by the block when the previous outputs are
+		// requested.  Note that they are only available for blocks in the
+		// main chain.
+		var spent map[wire.OutPoint]blockchain.SpentOutput
+		if c.VerbosePrevOut != nil && *c.VerbosePrevOut {
+			if confirmations == -1 {
+				return nil, rpcMiscError("Previous outputs are only " +
+					"available for blocks in the main chain")
+			}
+			spent, err = chain.FetchSpentOutputs(blk)
+			if err != nil {
+				context := "Failed to fetch spent outputs"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+		}
+
 		txns := blk.Transactions()
 		chainParams := s.cfg.ChainParams
 		rawTxns := make([]types.TxRawResult, len(txns))
@@ -2016,6 +2506,9 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 				return nil, rpcInternalError(err.Error(),
 					"Could not create transaction")
 			}
+			if spent != nil {
+				addVinPrevOuts(rawTxn.Vin, tx.MsgTx(), spent, chainParams)
+			}
 			rawTxns[i] = *rawTxn
 		}
 		blockReply.RawTx = rawTxns
@@ -2032,6 +2525,9 @@ func handleGetBlock(_ context.Context, s *Server, cmd interface{}) (interface{},
 				return nil, rpcInternalError(err.Error(),
 					"Could not create stake transaction")
 			}
+			if spent != nil {
+				addVinPrevOuts(rawSTxn.Vin, tx.MsgTx(), spent, chainParams)
+			}
 			rawSTxns[i] = *rawSTxn
 		}
 		blockReply.RawSTx = rawSTxns
@@ -2157,6 +2653,40 @@ func handleGetBlockCount(_ context.Context, s *Server, _ interface{}) (interface
 	return best.Height, nil
 }
 
+// handleGetBlockByTime implements the getblockbytime command.
+func handleGetBlockByTime(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockByTimeCmd)
+
+	chain := s.cfg.Chain
+	height, err := chain.HeightByMedianTime(time.Unix(c.Timestamp, 0))
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("No block with a median time at or after "+
+				"%d", c.Timestamp),
+		}
+	}
+
+	// The main chain might have changed since the height was found, so
+	// ensure the block still exists.
+	hash, err := chain.BlockHashByHeight(height)
+	if err != nil {
+		context := "Failed to retrieve block hash"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	medianTime, err := chain.MedianTimeByHash(hash)
+	if err != nil {
+		context := "Failed to retrieve block median time"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return &types.GetBlockByTimeResult{
+		Hash:       hash.String(),
+		Height:     height,
+		MedianTime: medianTime.Unix(),
+	}, nil
+}
+
 // handleGetBlockHash implements the getblockhash command.
 func handleGetBlockHash(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetBlockHashCmd)
@@ -2263,6 +2793,55 @@ func handleGetBlockHeader(_ context.Context, s *Server, cmd interface{}) (interf
 	return blockHeaderReply, nil
 }
 
+// handleGetBlocksByTime implements the getblocksbytime command.
+func handleGetBlocksByTime(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlocksByTimeCmd)
+
+	count := 100
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 1 || count > getBlocksByTimeMaxCount {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			getBlocksByTimeMaxCount)
+	}
+	if c.EndTime < c.StartTime {
+		return nil, rpcInvalidError("End time %d must not be before start "+
+			"time %d", c.EndTime, c.StartTime)
+	}
+
+	// Find the heights of the blocks in the time range and limit them to the
+	// requested number of blocks.
+	chain := s.cfg.Chain
+	startHeight, endHeight := chain.HeightRangeByMedianTime(
+		time.Unix(c.StartTime, 0), time.Unix(c.EndTime, 0))
+	if endHeight-startHeight > int64(count) {
+		endHeight = startHeight + int64(count)
+	}
+	hashes, err := chain.HeightRange(startHeight, endHeight)
+	if err != nil {
+		context := "Failed to retrieve block hashes"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	results := make([]types.GetBlockByTimeResult, 0, len(hashes))
+	for i := range hashes {
+		hash := &hashes[i]
+		medianTime, err := chain.MedianTimeByHash(hash)
+		if err != nil {
+			context := "Failed to retrieve block median time"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		results = append(results, types.GetBlockByTimeResult{
+			Hash:       hash.String(),
+			Height:     startHeight + int64(i),
+			MedianTime: medianTime.Unix(),
+		})
+	}
+
+	return results, nil
+}
+
 // handleGetBlockSubsidy implements the getblocksubsidy command.
 func handleGetBlockSubsidy(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetBlockSubsidyCmd)
@@ -2402,40 +2981,106 @@ func handleGetHeaders(_ context.Context, s *Server, cmd interface{}) (interface{
 	return &types.GetHeadersResult{Headers: hexBlockHeaders}, nil
 }
 
-// handleGetCFilterV2 implements the getcfilterv2 command.
-func handleGetCFilterV2(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	c := cmd.(*types.GetCFilterV2Cmd)
-	hash, err := chainhash.NewHashFromStr(c.BlockHash)
-	if err != nil {
-		return nil, rpcDecodeHexError(c.BlockHash)
+// proofHashStrings returns the hashes of the provided header commitment
+// inclusion proof as strings.
+func proofHashStrings(proof *blockchain.HeaderProof) []string {
+	if len(proof.ProofHashes) == 0 {
+		return nil
+	}
+	proofHashes := make([]string, 0, len(proof.ProofHashes))
+	for i := range proof.ProofHashes {
+		proofHashes = append(proofHashes, proof.ProofHashes[i].String())
 	}
+	return proofHashes
+}
 
-	filter, proof, err := s.cfg.FiltererV2.FilterByBlockHash(hash)
-	if err != nil {
-		if errors.Is(err, blockchain.ErrNoFilter) {
-			return nil, &dcrjson.RPCError{
-				Code:    dcrjson.ErrRPCBlockNotFound,
-				Message: fmt.Sprintf("Block not found: %v", hash),
-			}
-		}
+// handleGetCFiltersV2 implements the getcfiltersv2 command.
+func handleGetCFiltersV2(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetCFiltersV2Cmd)
 
-		context := fmt.Sprintf("Failed to load filter for block %s", hash)
-		return nil, rpcInternalError(err.Error(), context)
+	count := 100
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 1 || count > getCFiltersV2MaxCount {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			getCFiltersV2MaxCount)
 	}
+	includeData := c.IncludeData == nil || *c.IncludeData
 
-	var proofHashes []string
-	if len(proof.ProofHashes) > 0 {
-		proofHashes = make([]string, 0, len(proof.ProofHashes))
-		for i := range proof.ProofHashes {
-			proofHashes[i] = proof.ProofHashes[i].String()
+	best := s.cfg.Chain.BestSnapshot()
+	if c.StartHeight < 0 || c.StartHeight > best.Height {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.StartHeight),
 		}
 	}
 
+	// Load the filters of the requested range of main chain blocks along with
+	// their headers and the proofs that link the filters to them.
+	endHeight := c.StartHeight + int64(count) - 1
+	if endHeight > best.Height {
+		endHeight = best.Height
+	}
+	results := make([]types.GetCFiltersV2Result, 0, endHeight-c.StartHeight+1)
+	var headerBuf bytes.Buffer
+	headerBuf.Grow(wire.MaxBlockHeaderPayload)
+	err := s.cfg.FiltererV2.MainChainFilters(ctx, c.StartHeight, endHeight,
+		func(f *blockchain.MainChainFilter) error {
+			headerBuf.Reset()
+			if err := f.Header.Serialize(&headerBuf); err != nil {
+				return err
+			}
+
+			result := types.GetCFiltersV2Result{
+				BlockHash:   f.Hash.String(),
+				Height:      f.Height,
+				Header:      hex.EncodeToString(headerBuf.Bytes()),
+				FilterHash:  f.Filter.Hash().String(),
+				ProofIndex:  f.Proof.ProofIndex,
+				ProofHashes: proofHashStrings(&f.Proof),
+			}
+			if includeData {
+				result.Data = hex.EncodeToString(f.Filter.Bytes())
+			}
+			results = append(results, result)
+			return nil
+		})
+	if err != nil {
+		context := "Failed to load filters"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return results, nil
+}
+
+// handleGetCFilterV2 implements the getcfilterv2 command.
+func handleGetCFilterV2(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetCFilterV2Cmd)
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.BlockHash)
+	}
+
+	filter, proof, err := s.cfg.FiltererV2.FilterByBlockHash(hash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrNoFilter) {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCBlockNotFound,
+				Message: fmt.Sprintf("Block not found: %v", hash),
+			}
+		}
+
+		context := fmt.Sprintf("Failed to load filter for block %s", hash)
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
 	result := &types.GetCFilterV2Result{
 		BlockHash:   c.BlockHash,
 		Data:        hex.EncodeToString(filter.Bytes()),
 		ProofIndex:  proof.ProofIndex,
-		ProofHashes: proofHashes,
+		ProofHashes: proofHashStrings(proof),
 	}
 	return result, nil
 }
@@ -2461,6 +3106,147 @@ func handleGetInfo(_ context.Context, s *Server, _ interface{}) (interface{}, er
 	return ret, nil
 }
 
+// mempoolTxTypeString returns the string used to describe the provided
+// transaction type in mempool entry results.
+func mempoolTxTypeString(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeRegular:
+		return "regular"
+	case stake.TxTypeSStx:
+		return "ticket"
+	case stake.TxTypeSSGen:
+		return "vote"
+	case stake.TxTypeSSRtx:
+		return "revocation"
+	case stake.TxTypeTAdd:
+		return "tadd"
+	case stake.TxTypeTSpend:
+		return "tspend"
+	case stake.TxTypeTreasuryBase:
+		return "treasurybase"
+	}
+	return "unknown"
+}
+
+// mempoolEntryResult returns the mempool entry result for the provided verbose
+// transaction descriptor.  The ancestor and descendant aggregates include the
+// transaction itself.
+func mempoolEntryResult(s *Server, desc *mempool.VerboseTxDesc) *types.GetMempoolEntryResult {
+	tx := desc.Tx
+	size := int64(tx.MsgTx().SerializeSize())
+	result := &types.GetMempoolEntryResult{
+		Size:            int32(size),
+		Fee:             dcrutil.Amount(desc.Fee).ToCoin(),
+		Time:            desc.Added.Unix(),
+		Height:          desc.Height,
+		Type:            mempoolTxTypeString(desc.Type),
+		Depends:         make([]string, len(desc.Depends)),
+		SpentBy:         make([]string, len(desc.SpentBy)),
+		AncestorCount:   1,
+		AncestorSize:    size,
+		DescendantCount: 1,
+		DescendantSize:  size,
+	}
+	for i, depDesc := range desc.Depends {
+		result.Depends[i] = depDesc.Tx.Hash().String()
+	}
+	for i, spenderDesc := range desc.SpentBy {
+		result.SpentBy[i] = spenderDesc.Tx.Hash().String()
+	}
+
+	ancestorFees, descendantFees := desc.Fee, desc.Fee
+	ancestors, _ := s.cfg.TxMempooler.Ancestors(tx.Hash())
+	for _, ancestor := range ancestors {
+		result.AncestorCount++
+		result.AncestorSize += int64(ancestor.Tx.MsgTx().SerializeSize())
+		ancestorFees += ancestor.Fee
+	}
+	descendants, _ := s.cfg.TxMempooler.Descendants(tx.Hash())
+	for _, descendant := range descendants {
+		result.DescendantCount++
+		result.DescendantSize += int64(descendant.Tx.MsgTx().SerializeSize())
+		descendantFees += descendant.Fee
+	}
+	result.AncestorFees = dcrutil.Amount(ancestorFees).ToCoin()
+	result.DescendantFees = dcrutil.Amount(descendantFees).ToCoin()
+
+	return result
+}
+
+// mempoolRelativesResult returns the result for the getmempoolancestors and
+// getmempooldescendants commands given the provided related transactions.
+// The result is either a sorted slice of the transaction hashes or, when
+// verbose, the mempool entries keyed by the transaction hashes.
+func mempoolRelativesResult(s *Server, relatives []*mempool.TxDesc, verbose bool) interface{} {
+	if !verbose {
+		hashes := make([]string, 0, len(relatives))
+		for _, desc := range relatives {
+			hashes = append(hashes, desc.Tx.Hash().String())
+		}
+		sort.Strings(hashes)
+		return hashes
+	}
+
+	result := make(map[string]*types.GetMempoolEntryResult, len(relatives))
+	for _, desc := range relatives {
+		// Skip any transactions that were removed from the pool since the
+		// relatives were determined.
+		verboseDesc, ok := s.cfg.TxMempooler.VerboseTxDesc(desc.Tx.Hash())
+		if !ok {
+			continue
+		}
+		result[desc.Tx.Hash().String()] = mempoolEntryResult(s, verboseDesc)
+	}
+	return result
+}
+
+// handleGetMempoolAncestors implements the getmempoolancestors command.
+func handleGetMempoolAncestors(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolAncestorsCmd)
+	txHash, err := chainhash.NewHashFromStr(c.TxHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxHash)
+	}
+
+	ancestors, ok := s.cfg.TxMempooler.Ancestors(txHash)
+	if !ok {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+	verbose := c.Verbose != nil && *c.Verbose
+	return mempoolRelativesResult(s, ancestors, verbose), nil
+}
+
+// handleGetMempoolDescendants implements the getmempooldescendants command.
+func handleGetMempoolDescendants(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolDescendantsCmd)
+	txHash, err := chainhash.NewHashFromStr(c.TxHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxHash)
+	}
+
+	descendants, ok := s.cfg.TxMempooler.Descendants(txHash)
+	if !ok {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+	verbose := c.Verbose != nil && *c.Verbose
+	return mempoolRelativesResult(s, descendants, verbose), nil
+}
+
+// handleGetMempoolEntry implements the getmempoolentry command.
+func handleGetMempoolEntry(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetMempoolEntryCmd)
+	txHash, err := chainhash.NewHashFromStr(c.TxHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.TxHash)
+	}
+
+	desc, ok := s.cfg.TxMempooler.VerboseTxDesc(txHash)
+	if !ok {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+	return mempoolEntryResult(s, desc), nil
+}
+
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	mempoolTxns := s.cfg.TxMempooler.TxDescs()
@@ -2515,10 +3301,13 @@ func handleGetMiningInfo(ctx context.Context, s *Server, _ interface{}) (interfa
 // handleGetNetTotals implements the getnettotals command.
 func handleGetNetTotals(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	totalBytesRecv, totalBytesSent := s.cfg.ConnMgr.NetTotals()
+	bytesRecvPerMsg, bytesSentPerMsg := s.cfg.ConnMgr.NetTotalsPerMsg()
 	reply := &types.GetNetTotalsResult{
-		TotalBytesRecv: totalBytesRecv,
-		TotalBytesSent: totalBytesSent,
-		TimeMillis:     s.cfg.Clock.Now().UTC().UnixNano() / int64(time.Millisecond),
+		TotalBytesRecv:  totalBytesRecv,
+		TotalBytesSent:  totalBytesSent,
+		BytesRecvPerMsg: bytesRecvPerMsg,
+		BytesSentPerMsg: bytesSentPerMsg,
+		TimeMillis:      s.cfg.Clock.Now().UTC().UnixNano() / int64(time.Millisecond),
 	}
 	return reply, nil
 }
@@ -2620,6 +3409,11 @@ func handleGetNetworkHashPS(_ context.Context, s *Server, cmd interface{}) (inte
 	return hashesPerSec.Int64(), nil
 }
 
+// handleGetNodeStatus implements the getnodestatus command.
+func handleGetNodeStatus(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	return s.nodeStatus(), nil
+}
+
 // handleGetNetworkInfo implements the getnetworkinfo command.
 func handleGetNetworkInfo(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	lAddrs := s.cfg.AddrManager.LocalAddresses()
@@ -2655,6 +3449,15 @@ func handleGetPeerInfo(_ context.Context, s *Server, _ interface{}) (interface{}
 	infos := make([]*types.GetPeerInfoResult, 0, len(peers))
 	for _, p := range peers {
 		statsSnap := p.StatsSnapshot()
+		pingStats := &statsSnap.PingStats
+		histogram := pingStats.Histogram()
+		pingHistogram := make([]types.PeerPingBucket, 0, len(histogram))
+		for _, bucket := range histogram {
+			pingHistogram = append(pingHistogram, types.PeerPingBucket{
+				UpperBound: float64(bucket.UpperBound.Microseconds()),
+				Count:      bucket.Count,
+			})
+		}
 		var addrLocalStr string
 		if addrLocal := p.LocalAddr(); addrLocal != nil {
 			addrLocalStr = addrLocal.String()
@@ -2679,6 +3482,17 @@ func handleGetPeerInfo(_ context.Context, s *Server, _ interface{}) (interface{}
 			CurrentHeight:  statsSnap.LastBlock,
 			BanScore:       int32(p.BanScore()),
 			SyncNode:       p.ID() == syncPeerID,
+
+			NumPings:      pingStats.NumPings,
+			MinPingTime:   float64(pingStats.Min.Microseconds()),
+			AvgPingTime:   float64(pingStats.Avg().Microseconds()),
+			MaxPingTime:   float64(pingStats.Max.Microseconds()),
+			PingHistogram: pingHistogram,
+
+			BytesSentPerMsg: statsSnap.BytesSentPerMsg,
+			BytesRecvPerMsg: statsSnap.BytesRecvPerMsg,
+			MsgsSentPerMsg:  statsSnap.MsgsSentPerMsg,
+			MsgsRecvPerMsg:  statsSnap.MsgsRecvPerMsg,
 		}
 		if p.LastPingNonce() != 0 {
 			wait := float64(s.cfg.Clock.Since(statsSnap.LastPingTime).Nanoseconds())
@@ -2693,85 +3507,167 @@ func handleGetPeerInfo(_ context.Context, s *Server, _ interface{}) (interface{}
 	return infos, nil
 }
 
+// paginateHashes returns at most count of the provided transaction hash
+// strings that sort after the provided cursor along with the cursor to use to
+// retrieve the next page.  The returned cursor is empty when there are no more
+// hashes after the returned page.  The provided slice is sorted in place.
+func paginateHashes(hashes []string, count int, cursor *string) ([]string, string, error) {
+	if count <= 0 {
+		return nil, "", rpcInvalidError("Count must be positive: %d", count)
+	}
+
+	sort.Strings(hashes)
+	var start int
+	if cursor != nil && *cursor != "" {
+		cursorHash, err := chainhash.NewHashFromStr(*cursor)
+		if err != nil {
+			return nil, "", rpcDecodeHexError(*cursor)
+		}
+		cursorStr := cursorHash.String()
+		start = sort.Search(len(hashes), func(i int) bool {
+			return hashes[i] > cursorStr
+		})
+	}
+
+	end := start + count
+	if end >= len(hashes) {
+		return hashes[start:], "", nil
+	}
+	return hashes[start:end], hashes[end-1], nil
+}
+
+// parseRawMempoolTxType returns the transaction type to filter raw mempool
+// results by based on the provided param.  A filter type of nil means no
+// filtering.
+func parseRawMempoolTxType(txType *string) (*stake.TxType, error) {
+	if txType == nil {
+		return nil, nil
+	}
+
+	var filterType stake.TxType
+	switch types.GetRawMempoolTxTypeCmd(*txType) {
+	case types.GRMRegular:
+		filterType = stake.TxTypeRegular
+	case types.GRMTickets:
+		filterType = stake.TxTypeSStx
+	case types.GRMVotes:
+		filterType = stake.TxTypeSSGen
+	case types.GRMRevocations:
+		filterType = stake.TxTypeSSRtx
+	case types.GRMTSpend:
+		filterType = stake.TxTypeTSpend
+	case types.GRMTAdd:
+		filterType = stake.TxTypeTAdd
+	case types.GRMAll:
+		return nil, nil
+	default:
+		supported := []types.GetRawMempoolTxTypeCmd{types.GRMRegular,
+			types.GRMTickets, types.GRMVotes, types.GRMRevocations,
+			types.GRMTSpend, types.GRMTAdd, types.GRMAll}
+		return nil, rpcInvalidError("Invalid transaction type: %s -- "+
+			"supported types: %v", *txType, supported)
+	}
+	return &filterType, nil
+}
+
+// rawMempoolHashes returns the hash strings of all transactions in the mempool
+// that match the provided filter type.
+func rawMempoolHashes(s *Server, filterType *stake.TxType) []string {
+	descs := s.cfg.TxMempooler.TxDescs()
+	hashStrings := make([]string, 0, len(descs))
+	for i := range descs {
+		if filterType != nil && descs[i].Type != *filterType {
+			continue
+		}
+		hashStrings = append(hashStrings, descs[i].Tx.Hash().String())
+	}
+	return hashStrings
+}
+
+// rawMempoolVerboseResults returns the verbose results for all transactions in
+// the mempool that match the provided filter type keyed by their hash strings.
+func rawMempoolVerboseResults(s *Server, filterType *stake.TxType) map[string]*types.GetRawMempoolVerboseResult {
+	descs := s.cfg.TxMempooler.VerboseTxDescs()
+	result := make(map[string]*types.GetRawMempoolVerboseResult, len(descs))
+	for i := range descs {
+		desc := descs[i]
+		if filterType != nil && desc.Type != *filterType {
+			continue
+		}
+
+		tx := desc.Tx
+		mpd := &types.GetRawMempoolVerboseResult{
+			Size:             int32(tx.MsgTx().SerializeSize()),
+			Fee:              dcrutil.Amount(desc.Fee).ToCoin(),
+			Time:             desc.Added.Unix(),
+			Height:           desc.Height,
+			StartingPriority: 0,
+			CurrentPriority:  0,
+			Depends:          make([]string, len(desc.Depends)),
+		}
+		for j, depDesc := range desc.Depends {
+			mpd.Depends[j] = depDesc.Tx.Hash().String()
+		}
+
+		result[tx.Hash().String()] = mpd
+	}
+	return result
+}
+
 // handleGetRawMempool implements the getrawmempool command.
 func handleGetRawMempool(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetRawMempoolCmd)
 
 	// Choose the type to filter the results by based on the provided param.
-	// A filter type of nil means no filtering.
-	var filterType *stake.TxType
-	if c.TxType != nil {
-		switch types.GetRawMempoolTxTypeCmd(*c.TxType) {
-		case types.GRMRegular:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeRegular
-		case types.GRMTickets:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeSStx
-		case types.GRMVotes:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeSSGen
-		case types.GRMRevocations:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeSSRtx
-		case types.GRMTSpend:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeTSpend
-		case types.GRMTAdd:
-			filterType = new(stake.TxType)
-			*filterType = stake.TxTypeTAdd
-		case types.GRMAll:
-			// Nothing to do
-		default:
-			supported := []types.GetRawMempoolTxTypeCmd{types.GRMRegular,
-				types.GRMTickets, types.GRMVotes, types.GRMRevocations,
-				types.GRMTSpend, types.GRMTAdd, types.GRMAll}
-			return nil, rpcInvalidError("Invalid transaction type: %s -- "+
-				"supported types: %v", *c.TxType, supported)
-		}
+	filterType, err := parseRawMempoolTxType(c.TxType)
+	if err != nil {
+		return nil, err
 	}
+	verbose := c.Verbose != nil && *c.Verbose
 
-	// Return verbose results if requested.
-	if c.Verbose != nil && *c.Verbose {
-		descs := s.cfg.TxMempooler.VerboseTxDescs()
-		result := make(map[string]*types.GetRawMempoolVerboseResult, len(descs))
-		for i := range descs {
-			desc := descs[i]
-			if filterType != nil && desc.Type != *filterType {
-				continue
+	// Return a single page of results along with the cursor for the next page
+	// when a count is provided.
+	if c.Count != nil {
+		if verbose {
+			all := rawMempoolVerboseResults(s, filterType)
+			hashes := make([]string, 0, len(all))
+			for hash := range all {
+				hashes = append(hashes, hash)
 			}
-
-			tx := desc.Tx
-			mpd := &types.GetRawMempoolVerboseResult{
-				Size:             int32(tx.MsgTx().SerializeSize()),
-				Fee:              dcrutil.Amount(desc.Fee).ToCoin(),
-				Time:             desc.Added.Unix(),
-				Height:           desc.Height,
-				StartingPriority: 0,
-				CurrentPriority:  0,
-				Depends:          make([]string, len(desc.Depends)),
+			page, next, err := paginateHashes(hashes, *c.Count, c.Cursor)
+			if err != nil {
+				return nil, err
 			}
-			for j, depDesc := range desc.Depends {
-				mpd.Depends[j] = depDesc.Tx.Hash().String()
+			txns := make(map[string]*types.GetRawMempoolVerboseResult,
+				len(page))
+			for _, hash := range page {
+				txns[hash] = all[hash]
 			}
+			return &types.GetRawMempoolVerbosePageResult{
+				Transactions: txns,
+				NextCursor:   next,
+			}, nil
+		}
 
-			result[tx.Hash().String()] = mpd
+		hashes := rawMempoolHashes(s, filterType)
+		page, next, err := paginateHashes(hashes, *c.Count, c.Cursor)
+		if err != nil {
+			return nil, err
 		}
+		return &types.GetRawMempoolPageResult{
+			TxHashes:   page,
+			NextCursor: next,
+		}, nil
+	}
 
-		return result, nil
+	// Return verbose results if requested.
+	if verbose {
+		return rawMempoolVerboseResults(s, filterType), nil
 	}
 
 	// The response is simply an array of the transaction hashes if the
 	// verbose flag is not set.
-	descs := s.cfg.TxMempooler.TxDescs()
-	hashStrings := make([]string, 0, len(descs))
-	for i := range descs {
-		if filterType != nil && descs[i].Type != *filterType {
-			continue
-		}
-		hashStrings = append(hashStrings, descs[i].Tx.Hash().String())
-	}
-	return hashStrings, nil
+	return rawMempoolHashes(s, filterType), nil
 }
 
 // handleGetRawTransaction implements the getrawtransaction command.
@@ -2784,9 +3680,10 @@ func handleGetRawTransaction(_ context.Context, s *Server, cmd interface{}) (int
 		return nil, rpcDecodeHexError(c.Txid)
 	}
 
-	verbose := false
+	verbose, verbosePrevOut := false, false
 	if c.Verbose != nil {
 		verbose = *c.Verbose != 0
+		verbosePrevOut = *c.Verbose > 1
 	}
 
 	// Try to fetch the transaction from the memory pool and if that fails,
@@ -2936,9 +3833,142 @@ func handleGetRawTransaction(_ context.Context, s *Server, cmd interface{}) (int
 	if err != nil {
 		return nil, err
 	}
+
+	// Include the previous outputs spent by the transaction when requested.
+	// They are loaded from the spend journal of the block that contains the
+	// transaction or the unspent outputs and memory pool when it is not yet
+	// in a block.
+	if verbosePrevOut {
+		var spent map[wire.OutPoint]blockchain.SpentOutput
+		if blkHash != nil {
+			blk, err := chain.BlockByHash(blkHash)
+			if err != nil {
+				return nil, rpcBlockNotFoundError(*blkHash)
+			}
+			spent, err = chain.FetchSpentOutputs(blk)
+			if err != nil {
+				context := "Failed to fetch spent outputs"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+		} else {
+			spent, err = s.fetchUnminedSpentOutputs(mtx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		addVinPrevOuts(rawTxn.Vin, mtx, spent, s.cfg.ChainParams)
+	}
 	return *rawTxn, nil
 }
 
+// handleGetRejectedTransaction implements the getrejectedtransaction command.
+func handleGetRejectedTransaction(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetRejectedTransactionCmd)
+
+	// Convert the provided transaction hash hex to a Hash.
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	rejected, ok := s.cfg.TxMempooler.RejectedTx(txHash)
+	if !ok {
+		return nil, rpcNoTxInfoError(txHash)
+	}
+
+	// A tag of zero is used to represent transactions submitted locally via
+	// the RPC server while all others are the ID of the peer that relayed it.
+	result := &types.GetRejectedTransactionResult{
+		Txid:   rejected.Hash.String(),
+		Time:   rejected.Time.Unix(),
+		Source: "local",
+		Code:   rejected.Kind,
+		Reason: rejected.Reason,
+	}
+	if rejected.Tag != 0 {
+		result.Source = "peer"
+		result.PeerID = uint64(rejected.Tag)
+	}
+	return result, nil
+}
+
+// handleGetRPCLimitInfo implements the getrpclimitinfo command.
+func handleGetRPCLimitInfo(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	return s.limiter.info(), nil
+}
+
+// handleGetSpentInfo implements the getspentinfo command.
+func handleGetSpentInfo(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	spentIndex := s.cfg.SpentIndexer
+	if spentIndex == nil {
+		return nil, rpcInternalError("The spent output index must be "+
+			"enabled to query spent outputs (specify --spentindex)",
+			"Configuration")
+	}
+
+	c := cmd.(*types.GetSpentInfoCmd)
+
+	// Convert the provided transaction hash hex to a Hash.
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	// Ensure the spent output index is synced.
+	tHeight, tHash, err := spentIndex.Tip()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Tip")
+	}
+
+	chain := s.cfg.Chain
+
+	// Return an out-of-sync error if index is lagging a
+	// maximum reorg depth (6) blocks or more from the chain tip.
+	if chain.BestSnapshot().Height > (tHeight + 5) {
+		msg := fmt.Sprintf("%s: index not synced", spentIndex.Name())
+		return nil, rpcInternalError(msg, "Sync")
+	}
+
+sync:
+	for !chain.BestSnapshot().Hash.IsEqual(tHash) {
+		select {
+		case <-time.After(syncWait):
+			msg := fmt.Sprintf("%s: index not synced", spentIndex.Name())
+			return nil, rpcInternalError(msg, "Sync")
+		case <-spentIndex.WaitForSync():
+			break sync
+		}
+	}
+
+	outpoint := wire.OutPoint{Hash: *txHash, Index: c.Vout}
+	entry, err := spentIndex.SpentInfo(&outpoint)
+	if err != nil {
+		context := "Failed to retrieve spent output information"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	if entry == nil {
+		return nil, dcrjson.NewRPCError(dcrjson.ErrRPCNoTxInfo,
+			fmt.Sprintf("No information available about a spend of "+
+				"output %v", outpoint))
+	}
+
+	// The index is updated asynchronously, so the block that contains the
+	// spending transaction might no longer be in the main chain.
+	blockHash, err := chain.BlockHashByHeight(entry.BlockHeight)
+	if err != nil {
+		msg := fmt.Sprintf("%s: index not synced", spentIndex.Name())
+		return nil, rpcInternalError(msg, "Sync")
+	}
+
+	return &types.GetSpentInfoResult{
+		Txid:      entry.TxHash.String(),
+		Vin:       entry.InputIndex,
+		Tree:      entry.TxTree,
+		Height:    entry.BlockHeight,
+		BlockHash: blockHash.String(),
+	}, nil
+}
+
 // handleGetStakeDifficulty implements the getstakedifficulty command.
 func handleGetStakeDifficulty(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -3097,6 +4127,39 @@ func handleGetStakeVersions(_ context.Context, s *Server, cmd interface{}) (inte
 	return result, nil
 }
 
+// handleGetSubmitBlockStatus implements the getsubmitblockstatus command.
+func handleGetSubmitBlockStatus(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetSubmitBlockStatusCmd)
+
+	hash, err := chainhash.NewHashFromStr(c.ID)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.ID)
+	}
+	result, ok := s.blockSubmissions.status(*hash)
+	if !ok {
+		return nil, rpcInvalidError("Unknown block submission %s", c.ID)
+	}
+	return result, nil
+}
+
+// handleGetSyncStatus implements the getsyncstatus command.
+func handleGetSyncStatus(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	status := s.cfg.SyncMgr.SyncStatus()
+	return &types.GetSyncStatusResult{
+		Stage:            status.Stage.String(),
+		SyncPeerID:       status.SyncPeerID,
+		SyncHeight:       status.SyncHeight,
+		BestHeaderHeight: status.BestHeaderHeight,
+		HeaderProgress:   status.HeaderProgress,
+		BlocksDownloaded: status.BlocksDownloaded,
+		BlocksInFlight:   status.BlocksInFlight,
+		BestHeight:       status.BestHeight,
+		VerifyProgress:   status.VerifyProgress,
+		BlocksPerSecond:  status.BlocksPerSecond,
+		ETASeconds:       int64(status.ETA / time.Second),
+	}, nil
+}
+
 // handleGetTicketPoolValue implements the getticketpoolvalue command.
 func handleGetTicketPoolValue(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
 	amt, err := s.cfg.Chain.TicketPoolValue()
@@ -3647,6 +4710,7 @@ func handleGetTxOutSetInfo(_ context.Context, s *Server, _ interface{}) (interfa
 		DiskSize:       stats.Size,
 		TotalAmount:    stats.Total,
 		SerializedHash: stats.SerializedHash.String(),
+		MuHash:         stats.MuHash.String(),
 	}, nil
 }
 
@@ -3678,6 +4742,41 @@ func getWorkTemplateKey(header *wire.BlockHeader) [merkleRootPairSize]byte {
 	return merkleRootPair
 }
 
+// getWorkLongPollID returns the long poll identifier for the template
+// described by the provided header.  The identifier commits to the parent block
+// along with the template key, so it changes whenever the template is updated
+// for any reason other than its timestamp and difficulty bits.
+func getWorkLongPollID(header *wire.BlockHeader) string {
+	templateKey := getWorkTemplateKey(header)
+	var buf [chainhash.HashSize + merkleRootPairSize]byte
+	copy(buf[:chainhash.HashSize], header.PrevBlock[:])
+	copy(buf[chainhash.HashSize:], templateKey[:])
+	return chainhash.HashH(buf[:]).String()
+}
+
+// waitForWorkLongPoll blocks until the background block template generator
+// produces a template with a long poll identifier that differs from the
+// provided one or the context is canceled.  It returns immediately when the
+// current template already has a different identifier.
+func waitForWorkLongPoll(ctx context.Context, bt BlockTemplater, longPollID string) error {
+	// The subscription immediately sends the current template, so there is no
+	// need to separately check it.
+	templateSub := bt.Subscribe()
+	defer templateSub.Stop()
+	for {
+		select {
+		case templateNtfn := <-templateSub.C():
+			header := &templateNtfn.Template.Block.Header
+			if getWorkLongPollID(header) != longPollID {
+				return nil
+			}
+
+		case <-ctx.Done():
+			return rpcConnectionClosedError()
+		}
+	}
+}
+
 // handleGetWorkRequest is a helper for handleGetWork which deals with
 // generating and returning work to the caller.
 func handleGetWorkRequest(ctx context.Context, s *Server) (interface{}, error) {
@@ -3816,8 +4915,9 @@ func handleGetWorkRequest(ctx context.Context, s *Server) (interface{}, error) {
 	// and is now required for compatibility.
 	target := bigToLEUint256(standalone.CompactToBig(headerCopy.Bits))
 	reply := &types.GetWorkResult{
-		Data:   hex.EncodeToString(data),
-		Target: hex.EncodeToString(target[:]),
+		Data:       hex.EncodeToString(data),
+		Target:     hex.EncodeToString(target[:]),
+		LongPollID: getWorkLongPollID(&headerCopy),
 	}
 	return reply, nil
 }
@@ -3846,9 +4946,10 @@ func handleGetWorkSubmission(_ context.Context, s *Server, hexData string) (inte
 		return false, rpcInvalidError("Invalid block header: %v", err)
 	}
 
-	// Ensure the submitted block hash is less than the target difficulty.
-	blockHash := submittedHeader.BlockHash()
-	err = standalone.CheckProofOfWork(&blockHash, submittedHeader.Bits,
+	// Ensure the submitted proof of work hash is less than the target
+	// difficulty.
+	powHash := s.cfg.ChainParams.PowHash(&submittedHeader)
+	err = standalone.CheckProofOfWork(&powHash, submittedHeader.Bits,
 		s.cfg.ChainParams.PowLimit)
 	if err != nil {
 		// Anything other than a rule violation is an unexpected error, so
@@ -3952,6 +5053,18 @@ func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}
 	}
 
 	c := cmd.(*types.GetWorkCmd)
+	isSubmission := c.Data != nil && *c.Data != ""
+
+	// Wait for the template to change when the caller is requesting work with
+	// the long poll identifier of the work it already has.  This is done prior
+	// to acquiring the work semaphore so that waiting callers do not block
+	// other requests and submissions.
+	if !isSubmission && c.LongPollID != nil && *c.LongPollID != "" {
+		err := waitForWorkLongPoll(ctx, s.cfg.BlockTemplater, *c.LongPollID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Protect concurrent access from multiple RPC invocations for work requests
 	// and submission.  A single item semaphore is used over a mutex to support
@@ -3966,7 +5079,7 @@ func handleGetWork(ctx context.Context, s *Server, cmd interface{}) (interface{}
 	// When the caller provides data, it is a submission of a supposedly
 	// solved block that needs to be checked and submitted to the network
 	// if valid.
-	if c.Data != nil && *c.Data != "" {
+	if isSubmission {
 		return handleGetWorkSubmission(ctx, s, *c.Data)
 	}
 
@@ -4040,94 +5153,463 @@ func handleInvalidateBlock(_ context.Context, s *Server, cmd interface{}) (inter
 }
 
 // handleLiveTickets implements the livetickets command.
-func handleLiveTickets(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+func handleLiveTickets(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.LiveTicketsCmd)
 	lt, err := s.cfg.Chain.LiveTickets()
 	if err != nil {
 		return nil, rpcInternalError("Could not get live tickets "+
 			err.Error(), "")
 	}
 
-	ltString := make([]string, len(lt))
-	for i := range lt {
-		ltString[i] = lt[i].String()
+	ltString := make([]string, len(lt))
+	for i := range lt {
+		ltString[i] = lt[i].String()
+	}
+
+	// Return a single page of tickets along with the cursor for the next page
+	// when a count is provided.
+	if c.Count != nil {
+		page, next, err := paginateHashes(ltString, *c.Count, c.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		return types.LiveTicketsResult{Tickets: page, NextCursor: next}, nil
+	}
+
+	return types.LiveTicketsResult{Tickets: ltString}, nil
+}
+
+// handlePing implements the ping command.
+func handlePing(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	// Ask server to ping \o_
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return nil, rpcInternalError("Not sending ping - failed to "+
+			"generate nonce: "+err.Error(), "")
+	}
+	s.cfg.ConnMgr.BroadcastMessage(wire.NewMsgPing(nonce))
+
+	return nil, nil
+}
+
+// handlePrioritiseTransaction implements the prioritisetransaction command.
+func handlePrioritiseTransaction(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.PrioritiseTransactionCmd)
+
+	// Convert the provided transaction hash hex to a Hash.
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	return s.cfg.TxMempooler.PrioritiseTransaction(txHash, c.FeeDelta), nil
+}
+
+// handleReconsiderBlock implements the reconsiderblock command.
+func handleReconsiderBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.ReconsiderBlockCmd)
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.BlockHash)
+	}
+
+	chain := s.cfg.Chain
+	err = chain.ReconsiderBlock(hash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrUnknownBlock) {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCBlockNotFound,
+				Message: fmt.Sprintf("Block not found: %v", hash),
+			}
+		}
+
+		// Use separate error code for failed validation.
+		allRuleErrs := func(err error) bool {
+			var rErr blockchain.RuleError
+			if !errors.As(err, &rErr) {
+				return false
+			}
+
+			var mErr blockchain.MultiError
+			if errors.As(err, &mErr) {
+				for _, e := range mErr {
+					if !errors.As(e, &rErr) {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+		if allRuleErrs(err) {
+			return nil, &dcrjson.RPCError{
+				Code: dcrjson.ErrRPCReconsiderFailure,
+				Message: fmt.Sprintf("Reconsidering block %s led to one or "+
+					"more validation failures: %v", hash, err),
+			}
+		}
+
+		// Fall back to an internal error.
+		context := fmt.Sprintf("Error while reconsidering block %s", hash)
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	return nil, nil
+}
+
+// handleRegenTemplate implements the regentemplate command.
+func handleRegenTemplate(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	bt := s.cfg.BlockTemplater
+	if bt == nil {
+		return nil, rpcInternalError("Node is not configured for mining", "")
+	}
+	bt.ForceRegen()
+	return nil, nil
+}
+
+// handleRPCDiscover implements the rpc.discover command.
+func handleRPCDiscover(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
+	doc, err := s.helpCacher.OpenRPCDocument()
+	if err != nil {
+		context := "Failed to generate OpenRPC document"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	return doc, nil
+}
+
+// utxoScan houses the state of a scan of the utxo set that is in progress via
+// the scantxoutset command.
+type utxoScan struct {
+	// The following fields are accessed atomically.
+	//
+	// progress is the first four bytes of the hash of the most recently
+	// scanned outpoint.  Since the utxo set is scanned in order of the
+	// outpoints, it approximates the progress of the scan.
+	//
+	// abort is set to a non-zero value to request the scan is aborted.
+	progress uint32
+	abort    int32
+}
+
+// percentDone returns the approximate percentage of the utxo set that has been
+// scanned.
+func (scan *utxoScan) percentDone() float64 {
+	progress := float64(atomic.LoadUint32(&scan.progress))
+	return math.Round(progress/(math.MaxUint32+1)*10000) / 100
+}
+
+// parseScanObjects parses the provided scantxoutset scan objects into the
+// addresses and raw public key scripts to scan for.  The returned maps are
+// keyed by the encoded address and the public key script, respectively, and
+// map to the canonical descriptor of the scan object.
+//
+// Scan objects are either addresses, an address descriptor of the form
+// addr(<address>), or a raw public key script descriptor of the form
+// raw(<hex script>).
+func parseScanObjects(scanObjects []string, params *chaincfg.Params) (map[string]string, map[string]string, error) {
+	addrs := make(map[string]string)
+	scripts := make(map[string]string)
+	for _, obj := range scanObjects {
+		switch {
+		case strings.HasPrefix(obj, "raw(") && strings.HasSuffix(obj, ")"):
+			scriptHex := obj[len("raw(") : len(obj)-1]
+			script, err := hex.DecodeString(scriptHex)
+			if err != nil || len(script) == 0 {
+				return nil, nil, rpcInvalidError("Invalid scan object %q: "+
+					"malformed script", obj)
+			}
+			scripts[string(script)] = "raw(" + hex.EncodeToString(script) + ")"
+
+		default:
+			addrStr := obj
+			if strings.HasPrefix(obj, "addr(") && strings.HasSuffix(obj, ")") {
+				addrStr = obj[len("addr(") : len(obj)-1]
+			}
+			addr, err := stdaddr.DecodeAddress(addrStr, params)
+			if err != nil {
+				return nil, nil, rpcInvalidError("Invalid scan object %q: %v",
+					obj, err)
+			}
+			encodedAddr := addr.String()
+			addrs[encodedAddr] = "addr(" + encodedAddr + ")"
+		}
+	}
+	return addrs, scripts, nil
+}
+
+// handleScanTxOutSet implements the scantxoutset command.
+func handleScanTxOutSet(ctx context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.ScanTxOutSetCmd)
+
+	s.utxoScanMtx.Lock()
+	scan := s.utxoScan
+	s.utxoScanMtx.Unlock()
+
+	switch c.Action {
+	case "status":
+		if scan == nil {
+			return nil, nil
+		}
+		return &types.ScanTxOutSetStatusResult{
+			Progress: scan.percentDone(),
+		}, nil
+
+	case "abort":
+		if scan == nil {
+			return false, nil
+		}
+		atomic.StoreInt32(&scan.abort, 1)
+		return true, nil
+
+	case "start":
+		// Handled below.
+
+	default:
+		return nil, rpcInvalidError("Invalid action %q", c.Action)
+	}
+
+	if c.ScanObjects == nil || len(*c.ScanObjects) == 0 {
+		return nil, rpcInvalidError("Scan objects must be specified to " +
+			"start a scan")
+	}
+	if len(*c.ScanObjects) > maxScanTxOutSetObjects {
+		return nil, rpcInvalidError("Too many scan objects: %d > %d",
+			len(*c.ScanObjects), maxScanTxOutSetObjects)
+	}
+	params := s.cfg.ChainParams
+	addrs, scripts, err := parseScanObjects(*c.ScanObjects, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only allow a single scan at a time since they are expensive.
+	s.utxoScanMtx.Lock()
+	if s.utxoScan != nil {
+		s.utxoScanMtx.Unlock()
+		return nil, rpcMiscError("Scan already in progress, use action " +
+			"\"abort\" or \"status\"")
+	}
+	scan = &utxoScan{}
+	s.utxoScan = scan
+	s.utxoScanMtx.Unlock()
+	defer func() {
+		s.utxoScanMtx.Lock()
+		s.utxoScan = nil
+		s.utxoScanMtx.Unlock()
+	}()
+
+	best := s.cfg.Chain.BestSnapshot()
+	result := &types.ScanTxOutSetResult{
+		Success:   true,
+		Height:    best.Height,
+		BestBlock: best.Hash.String(),
+		Unspents:  []types.ScanTxOutSetUnspent{},
+	}
+	var totalAmount int64
+	errAborted := errors.New("scan aborted")
+	err = s.cfg.Chain.ForEachUtxo(func(outpoint wire.OutPoint, entry UtxoEntry) error {
+		// Stop the scan when it is aborted or the server is shutting down.
+		if atomic.LoadInt32(&scan.abort) != 0 {
+			return errAborted
+		}
+		result.SearchedItems++
+		if result.SearchedItems%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return errAborted
+			default:
+			}
+		}
+		progress := binary.BigEndian.Uint32(outpoint.Hash[:4])
+		atomic.StoreUint32(&scan.progress, progress)
+
+		// Determine if the output matches any of the scan objects, giving
+		// precedence to raw scripts since they are cheaper to check.
+		pkScript := entry.PkScript()
+		desc, ok := scripts[string(pkScript)]
+		if !ok && len(addrs) > 0 {
+			_, scriptAddrs := stdscript.ExtractAddrs(entry.ScriptVersion(),
+				pkScript, params)
+			for _, addr := range scriptAddrs {
+				if desc, ok = addrs[addr.String()]; ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil
+		}
+
+		totalAmount += entry.Amount()
+		result.Unspents = append(result.Unspents, types.ScanTxOutSetUnspent{
+			Txid:          outpoint.Hash.String(),
+			Vout:          outpoint.Index,
+			Tree:          outpoint.Tree,
+			ScriptPubKey:  hex.EncodeToString(pkScript),
+			ScriptVersion: entry.ScriptVersion(),
+			Desc:          desc,
+			Amount:        dcrutil.Amount(entry.Amount()).ToCoin(),
+			Height:        entry.BlockHeight(),
+			Coinbase:      entry.IsCoinBase(),
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAborted) {
+		return nil, rpcInternalError(err.Error(), "Failed to scan utxo set")
+	}
+	result.Success = err == nil
+	result.TotalAmount = dcrutil.Amount(totalAmount).ToCoin()
+	return result, nil
+}
+
+// handleSearchRawTransactions implements the searchrawtransactions command.
+func handleSearchRawTransactions(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	addrIndex := s.cfg.AddrIndexer
+	if addrIndex == nil {
+		return nil, rpcInternalError("The address index must be enabled to "+
+			"search transactions by address (specify --addrindex)",
+			"Configuration")
 	}
 
-	return types.LiveTicketsResult{Tickets: ltString}, nil
-}
+	c := cmd.(*types.SearchRawTransactionsCmd)
 
-// handlePing implements the ping command.
-func handlePing(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
-	// Ask server to ping \o_
-	nonce, err := wire.RandomUint64()
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
 	if err != nil {
-		return nil, rpcInternalError("Not sending ping - failed to "+
-			"generate nonce: "+err.Error(), "")
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
 	}
-	s.cfg.ConnMgr.BroadcastMessage(wire.NewMsgPing(nonce))
 
-	return nil, nil
-}
+	verbose := true
+	if c.Verbose != nil {
+		verbose = *c.Verbose != 0
+	}
+	skip := 0
+	if c.Skip != nil {
+		skip = *c.Skip
+	}
+	count := 100
+	if c.Count != nil {
+		count = *c.Count
+	}
+	reverse := c.Reverse != nil && *c.Reverse
+	if skip < 0 {
+		return nil, rpcInvalidError("Skip must not be negative")
+	}
+	if count < 1 || count > searchRawTransactionsMaxCount {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			searchRawTransactionsMaxCount)
+	}
 
-// handleReconsiderBlock implements the reconsiderblock command.
-func handleReconsiderBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
-	c := cmd.(*types.ReconsiderBlockCmd)
-	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	// Ensure the address index is synced.
+	tHeight, tHash, err := addrIndex.Tip()
 	if err != nil {
-		return nil, rpcDecodeHexError(c.BlockHash)
+		return nil, rpcInternalError(err.Error(), "Tip")
 	}
 
 	chain := s.cfg.Chain
-	err = chain.ReconsiderBlock(hash)
+
+	// Return an out-of-sync error if index is lagging a
+	// maximum reorg depth (6) blocks or more from the chain tip.
+	if chain.BestSnapshot().Height > (tHeight + 5) {
+		msg := fmt.Sprintf("%s: index not synced", addrIndex.Name())
+		return nil, rpcInternalError(msg, "Sync")
+	}
+
+sync:
+	for !chain.BestSnapshot().Hash.IsEqual(tHash) {
+		select {
+		case <-time.After(syncWait):
+			msg := fmt.Sprintf("%s: index not synced", addrIndex.Name())
+			return nil, rpcInternalError(msg, "Sync")
+		case <-addrIndex.WaitForSync():
+			break sync
+		}
+	}
+
+	idxTxns, err := addrIndex.Transactions(addr, skip, count, reverse)
 	if err != nil {
-		if errors.Is(err, blockchain.ErrUnknownBlock) {
-			return nil, &dcrjson.RPCError{
-				Code:    dcrjson.ErrRPCBlockNotFound,
-				Message: fmt.Sprintf("Block not found: %v", hash),
-			}
+		if errors.Is(err, indexers.ErrUnsupportedAddressType) {
+			return nil, rpcInvalidError("Could not query address: %v", err)
 		}
+		context := "Failed to search transactions"
+		return nil, rpcInternalError(err.Error(), context)
+	}
 
-		// Use separate error code for failed validation.
-		allRuleErrs := func(err error) bool {
-			var rErr blockchain.RuleError
-			if !errors.As(err, &rErr) {
-				return false
+	// Load the transactions from the blocks that contain them.  The index is
+	// updated asynchronously, so ensure the transaction at the indexed
+	// location is the expected one to avoid returning stale results when the
+	// main chain changed in the mean time.
+	blocks := make(map[int64]*dcrutil.Block)
+	hexTxns := make([]string, 0, len(idxTxns))
+	results := make([]types.SearchRawTransactionsResult, 0, len(idxTxns))
+	best := chain.BestSnapshot()
+	for _, idxTx := range idxTxns {
+		block, ok := blocks[idxTx.BlockHeight]
+		if !ok {
+			block, err = chain.BlockByHeight(idxTx.BlockHeight)
+			if err != nil {
+				msg := fmt.Sprintf("%s: index not synced", addrIndex.Name())
+				return nil, rpcInternalError(msg, "Sync")
 			}
+			blocks[idxTx.BlockHeight] = block
+		}
+		txns := block.MsgBlock().Transactions
+		if idxTx.TxTree == wire.TxTreeStake {
+			txns = block.MsgBlock().STransactions
+		}
+		if idxTx.TxIndex >= uint32(len(txns)) ||
+			txns[idxTx.TxIndex].TxHash() != idxTx.TxHash {
 
-			var mErr blockchain.MultiError
-			if errors.As(err, &mErr) {
-				for _, e := range mErr {
-					if !errors.As(e, &rErr) {
-						return false
-					}
-				}
+			msg := fmt.Sprintf("%s: index not synced", addrIndex.Name())
+			return nil, rpcInternalError(msg, "Sync")
+		}
+		mtx := txns[idxTx.TxIndex]
+
+		if !verbose {
+			mtxHex, err := s.messageToHex(mtx)
+			if err != nil {
+				return nil, err
 			}
+			hexTxns = append(hexTxns, mtxHex)
+			continue
+		}
 
-			return true
+		header := &block.MsgBlock().Header
+		isTreasuryEnabled, err := s.isTreasuryAgendaActive(&header.PrevBlock)
+		if err != nil {
+			return nil, rpcInternalError(err.Error(), "Treasury Status")
 		}
-		if allRuleErrs(err) {
-			return nil, &dcrjson.RPCError{
-				Code: dcrjson.ErrRPCReconsiderFailure,
-				Message: fmt.Sprintf("Reconsidering block %s led to one or "+
-					"more validation failures: %v", hash, err),
-			}
+		confirmations := 1 + best.Height - idxTx.BlockHeight
+		rawTxn, err := s.createTxRawResult(s.cfg.ChainParams, mtx,
+			idxTx.TxHash.String(), idxTx.TxIndex, header,
+			block.Hash().String(), idxTx.BlockHeight, confirmations,
+			isTreasuryEnabled)
+		if err != nil {
+			return nil, err
 		}
 
-		// Fall back to an internal error.
-		context := fmt.Sprintf("Error while reconsidering block %s", hash)
-		return nil, rpcInternalError(err.Error(), context)
+		entries := make([]types.SearchRawTransactionsEntry, 0,
+			len(idxTx.Entries))
+		for _, entry := range idxTx.Entries {
+			entries = append(entries, types.SearchRawTransactionsEntry{
+				IsInput: entry.IsInput,
+				Index:   entry.Index,
+				Amount:  dcrutil.Amount(entry.Amount).ToCoin(),
+			})
+		}
+		results = append(results, types.SearchRawTransactionsResult{
+			Tx:      *rawTxn,
+			Tree:    idxTx.TxTree,
+			Entries: entries,
+		})
 	}
 
-	return nil, nil
-}
-
-// handleRegenTemplate implements the regentemplate command.
-func handleRegenTemplate(_ context.Context, s *Server, _ interface{}) (interface{}, error) {
-	bt := s.cfg.BlockTemplater
-	if bt == nil {
-		return nil, rpcInternalError("Node is not configured for mining", "")
+	if !verbose {
+		return hexTxns, nil
 	}
-	bt.ForceRegen()
-	return nil, nil
+	return results, nil
 }
 
 // handleSendRawTransaction implements the sendrawtransaction command.
@@ -4258,10 +5740,57 @@ func handleStop(_ context.Context, s *Server, _ interface{}) (interface{}, error
 func handleSubmitBlock(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.SubmitBlockCmd)
 
-	// Deserialize the submitted block.
-	hexStr := c.HexBlock
+	block, err := decodeSubmittedBlock(c.HexBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cfg.SyncMgr.SubmitBlock(block)
+	if err != nil {
+		return fmt.Sprintf("rejected: %v", err), nil
+	}
+
+	log.Infof("Accepted block %s via submitblock", block.Hash())
+	return nil, nil
+}
+
+// handleSubmitBlockNoWait implements the submitblocknowait command.
+func handleSubmitBlockNoWait(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SubmitBlockNoWaitCmd)
+
+	block, err := decodeSubmittedBlock(c.HexBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process the block in the background and return the hash of the block
+	// as the submission ID immediately.  There is no need to process blocks
+	// that are already being processed or were accepted.
+	hash := *block.Hash()
+	added, err := s.blockSubmissions.add(hash, s.cfg.Clock.Now())
+	if err != nil {
+		return nil, rpcMiscError(err.Error())
+	}
+	if added {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			err := s.cfg.SyncMgr.SubmitBlock(block)
+			if err == nil {
+				log.Infof("Accepted block %s via submitblocknowait", hash)
+			}
+			s.blockSubmissions.complete(hash, err, s.cfg.Clock.Now())
+		}()
+	}
+	return hash.String(), nil
+}
+
+// decodeSubmittedBlock deserializes the provided serialized, hex-encoded block
+// submitted via the submitblock family of commands.
+func decodeSubmittedBlock(hexBlock string) (*dcrutil.Block, error) {
+	hexStr := hexBlock
 	if len(hexStr)%2 != 0 {
-		hexStr = "0" + c.HexBlock
+		hexStr = "0" + hexBlock
 	}
 	serializedBlock, err := hex.DecodeString(hexStr)
 	if err != nil {
@@ -4272,14 +5801,7 @@ func handleSubmitBlock(_ context.Context, s *Server, cmd interface{}) (interface
 	if err != nil {
 		return nil, rpcInternalError(err.Error(), "Block decode")
 	}
-
-	err = s.cfg.SyncMgr.SubmitBlock(block)
-	if err != nil {
-		return fmt.Sprintf("rejected: %v", err), nil
-	}
-
-	log.Infof("Accepted block %s via submitblock", block.Hash())
-	return nil, nil
+	return block, nil
 }
 
 // min gets the minimum amount from a slice of amounts.
@@ -4884,6 +6406,48 @@ func handleVerifyMessage(_ context.Context, s *Server, cmd interface{}) (interfa
 	return address.String() == c.Address, nil
 }
 
+// handleVerifyOwnershipProof implements the verifyownershipproof command.
+func handleVerifyOwnershipProof(_ context.Context, s *Server, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.VerifyOwnershipProofCmd)
+
+	// Decode the provided address.  This also ensures the network encoded with
+	// the address matches the network the server is currently on.
+	addr, err := stdaddr.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+
+	// Decode base64 proof.
+	proof, err := base64.StdEncoding.DecodeString(c.Proof)
+	if err != nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCParse.Code,
+			Message: "Malformed base64 encoding: " + err.Error(),
+		}
+	}
+
+	// Proofs that are well formed but do not prove ownership are reported as
+	// failing to verify while all other errors are returned to the caller.
+	err = ownership.Verify(addr, c.Message, proof)
+	switch {
+	case errors.Is(err, ownership.ErrInvalidProof):
+		return false, nil
+
+	case errors.Is(err, ownership.ErrUnsupportedAddress):
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCType,
+			Message: err.Error(),
+		}
+
+	case err != nil:
+		return nil, rpcDeserializationError("Could not parse proof: %v",
+			err)
+	}
+
+	return true, nil
+}
+
 // handleVersion implements the version command.
 func handleVersion(_ context.Context, _ *Server, _ interface{}) (interface{}, error) {
 	runtimeVer := strings.ReplaceAll(runtime.Version(), ".", "-")
@@ -4919,8 +6483,11 @@ type Server struct {
 	cfg                    Config
 	hmac                   hash.Hash
 	hmacMu                 sync.Mutex
-	authsha                [sha256.Size]byte
-	limitauthsha           [sha256.Size]byte
+	legacyUsers            []UserAuth
+	openAuth               bool
+	authState              atomic.Value // *rpcAuthState
+	limiter                *rpcLimiter
+	metrics                *rpcMetrics
 	ntfnMgr                NtfnManager
 	statusLines            map[int]string
 	statusLock             sync.RWMutex
@@ -4928,6 +6495,20 @@ type Server struct {
 	workState              *workState
 	helpCacher             RPCHelpCacher
 	requestProcessShutdown chan struct{}
+
+	// utxoScanMtx protects utxoScan which houses the state of the utxo set
+	// scan in progress, if any.
+	utxoScanMtx sync.Mutex
+	utxoScan    *utxoScan
+
+	// blockSubmissions tracks the status of the blocks submitted via the
+	// submitblocknowait command.
+	blockSubmissions *blockSubmissions
+
+	// chainStateBackup is set to a non-zero value while a chain state backup
+	// requested via the backupchainstate command is in progress.  It must be
+	// accessed atomically.
+	chainStateBackup int32
 }
 
 // isTreasuryAgendaActive returns if the treasury agenda is active or not for
@@ -5034,6 +6615,13 @@ func (s *Server) shutdown() error {
 			return err
 		}
 	}
+	for _, listener := range s.cfg.UnixListeners {
+		err := listener.Close()
+		if err != nil {
+			log.Errorf("Problem shutting down rpc: %v", err)
+			return err
+		}
+	}
 	s.wg.Wait()
 	log.Infof("RPC server shutdown complete")
 	return nil
@@ -5056,6 +6644,13 @@ func (s *Server) NotifyNewTransactions(txns []*dcrutil.Tx) {
 	}
 }
 
+// NotifyMempoolEvent notifies websocket clients that have registered to
+// receive mempool events of the passed transaction event.  This function should
+// be called whenever transactions are added to or removed from the mempool.
+func (s *Server) NotifyMempoolEvent(event *mempool.TxEvent) {
+	s.ntfnMgr.NotifyMempoolEvent(event)
+}
+
 // NotifyTSpend notifies websocket clients that have registered to receive new
 // tspends in the mempool.
 func (s *Server) NotifyTSpend(tx *dcrutil.Tx) {
@@ -5086,6 +6681,14 @@ func (s *Server) NotifyReorganization(rd *blockchain.ReorganizationNtfnsData) {
 	s.ntfnMgr.NotifyReorganization(rd)
 }
 
+// NotifyTLSCertRenewed notifies all websocket clients that the TLS certificate
+// of the server was automatically regenerated so they are able to trust the
+// new certificate when reconnecting.  The provided certificate must be
+// PEM-encoded.
+func (s *Server) NotifyTLSCertRenewed(cert []byte, validUntil time.Time) {
+	s.ntfnMgr.NotifyTLSCertRenewed(cert, validUntil)
+}
+
 // NotifyWinningTickets notifies websocket clients that have registered for
 // winning ticket updates.
 func (s *Server) NotifyWinningTickets(wtnd *WinningTicketsNtfnData) {
@@ -5138,34 +6741,31 @@ func (s *Server) authMAC(dst, auth []byte) []byte {
 	return dst
 }
 
-// checkAuthMAC checks the HTTP Basic authentication string by comparing
-// it with the already generated hash.
+// checkAuthMAC checks the HTTP Basic authentication string by looking up the
+// MAC of it among the MACs of the configured users.  Since the MAC is keyed
+// with a secret random key, the lookup does not leak any timing information
+// about the credentials of the configured users.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false).
-func (s *Server) checkAuthMAC(auth, remoteAddr string) (bool, bool) {
-	mac := make([]byte, 0, sha256.Size)
-	mac = s.authMAC(mac, []byte(auth))
-
-	cmp := subtle.ConstantTimeCompare(mac, s.authsha[:])
-	limitcmp := subtle.ConstantTimeCompare(mac, s.limitauthsha[:])
-	if cmp|limitcmp == 0 {
-		// Request's auth doesn't match either user
+// It returns the authenticated user or nil when authentication failed.
+func (s *Server) checkAuthMAC(auth, remoteAddr string) *rpcAuthUser {
+	var mac [sha256.Size]byte
+	s.authMAC(mac[:0], []byte(auth))
+
+	user := s.loadAuthState().users[mac]
+	if user == nil {
+		// Request's auth doesn't match any user
 		log.Warnf("RPC authentication failure from %s", remoteAddr)
-		return false, false
+		return nil
 	}
-	return true, cmp == 1
+	return user
 }
 
 // checkAuthUserPass checks the correctness of username and password by
 // generating the corresponding HTTP Basic authentication string then
 // compare the string with the already generated hash.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false).
-func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) (bool, bool) {
+// It returns the authenticated user or nil when authentication failed.
+func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) *rpcAuthUser {
 	login := user + ":" + pass
 	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
 	return s.checkAuthMAC(auth, remoteAddr)
@@ -5177,16 +6777,22 @@ func (s *Server) checkAuthUserPass(user, pass, remoteAddr string) (bool, bool) {
 //
 // This check is time-constant.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false). The second is
-// always false if the first is.
-func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
-	// If admin-level RPC user and pass options are not set, this always
-	// succeeds.  This will be the case when TLS client certificates are
-	// being used for authentication.
-	if s.authsha == ([32]byte{}) {
-		return true, true, nil
+// The bool return value signifies auth success (true if successful) and the
+// returned user specifies the methods and notifications the authenticated user
+// is authorized for.  The user is always nil if authentication did not
+// succeed.
+func (s *Server) checkAuth(r *http.Request, require bool) (bool, *rpcAuthUser, error) {
+	// If no RPC users are configured, this always succeeds.  This will be
+	// the case when TLS client certificates are being used for
+	// authentication.
+	if s.openAuth {
+		return true, openAuthUser, nil
+	}
+
+	// Access to unix domain sockets is controlled by filesystem permissions,
+	// so all connections accepted on them are authenticated.
+	if isUnixSocketRequest(r) {
+		return true, openAuthUser, nil
 	}
 
 	authhdr := r.Header["Authorization"]
@@ -5194,17 +6800,46 @@ func (s *Server) checkAuth(r *http.Request, require bool) (bool, bool, error) {
 		if require {
 			log.Warnf("RPC authentication failure from %s",
 				r.RemoteAddr)
-			return false, false, errors.New("auth failure")
+			return false, nil, errors.New("auth failure")
 		}
 
-		return false, false, nil
+		return false, nil, nil
+	}
+
+	user := s.checkAuthMAC(authhdr[0], r.RemoteAddr)
+	if user == nil {
+		return false, nil, errors.New("auth failure")
 	}
+	return true, user, nil
+}
+
+// unixSocketCtxKey is the context key used to mark the contexts of requests
+// that were received on a unix domain socket listener.
+type unixSocketCtxKey struct{}
+
+// isUnixSocketRequest returns whether or not the provided request was received
+// on a unix domain socket listener.
+func isUnixSocketRequest(r *http.Request) bool {
+	unix, _ := r.Context().Value(unixSocketCtxKey{}).(bool)
+	return unix
+}
 
-	authed, isAdmin := s.checkAuthMAC(authhdr[0], r.RemoteAddr)
-	if !authed {
-		return false, false, errors.New("auth failure")
+// isBatchedRequest returns whether or not the provided JSON-RPC message is a
+// batched request, meaning its first non-whitespace character begins a JSON
+// array.
+func isBatchedRequest(msg []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(msg, " \t\r\n"),
+		batchedRequestPrefix)
+}
+
+// batchSizeError returns the error used to reply to batched requests that
+// contain more than the maximum allowed number of requests.
+func batchSizeError(size int) *dcrjson.RPCError {
+	return &dcrjson.RPCError{
+		Code: dcrjson.ErrRPCInvalidRequest.Code,
+		Message: fmt.Sprintf("Invalid request: batch of %d requests "+
+			"exceeds the maximum allowed of %d", size, rpcMaxBatchSize),
 	}
-	return authed, isAdmin, nil
 }
 
 // parsedRPCCmd represents a JSON-RPC request object that has been parsed into
@@ -5282,16 +6917,14 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 }
 
 // processRequest determines the incoming request type (single or batched),
-// parses it and returns a marshalled response.
-func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, isAdmin bool) []byte {
+// parses it and returns a marshalled response along with whether or not the
+// request was rejected due to exceeding the limits of the client.
+func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, user *rpcAuthUser, remoteAddr string) ([]byte, bool) {
 	var result interface{}
 	var jsonErr error
 
-	if !isAdmin {
-		if _, ok := rpcLimited[request.Method]; !ok {
-			jsonErr = rpcInvalidError("limited user not " +
-				"authorized for this method")
-		}
+	if !user.authorized(request.Method) {
+		jsonErr = rpcInvalidError("user not authorized for this method")
 	}
 
 	if jsonErr == nil {
@@ -5303,16 +6936,30 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 			msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
 			if err != nil {
 				log.Errorf("Failed to marshal reply: %v", err)
-				return nil
+				return nil, false
 			}
-			return msg
+			return msg, false
 		}
 
 		// Valid requests with no ID (notifications) must not have a response
 		// per the JSON-RPC spec.
 		if request.ID == nil {
-			return nil
+			return nil, false
+		}
+
+		// Reject the request when it would exceed the limits of the client.
+		release, limitErr := s.limiter.acquire(user, remoteAddr)
+		if limitErr != nil {
+			log.Debugf("Rejected command <%s> from %s: %v", request.Method,
+				remoteAddr, limitErr.Message)
+			msg, err := createMarshalledReply(request.Jsonrpc, request.ID, nil, limitErr)
+			if err != nil {
+				log.Errorf("Failed to marshal reply: %v", err)
+				return nil, true
+			}
+			return msg, true
 		}
+		defer release()
 
 		// Attempt to parse the JSON-RPC request into a known
 		// concrete command.
@@ -5320,7 +6967,9 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 		if parsedCmd.err != nil {
 			jsonErr = parsedCmd.err
 		} else {
+			done := s.observeCall(parsedCmd, user, remoteAddr)
 			result, jsonErr = s.standardCmdResult(ctx, parsedCmd)
+			done(jsonErr)
 		}
 	}
 
@@ -5328,13 +6977,13 @@ func (s *Server) processRequest(ctx context.Context, request *dcrjson.Request, i
 	msg, err := createMarshalledReply(request.Jsonrpc, request.ID, result, jsonErr)
 	if err != nil {
 		log.Errorf("Failed to marshal reply: %v", err)
-		return nil
+		return nil, false
 	}
-	return msg
+	return msg, false
 }
 
 // jsonRPCRead handles reading and responding to RPC messages.
-func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, isAdmin bool) {
+func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, user *rpcAuthUser) {
 	select {
 	case <-sCtx.Done():
 		return
@@ -5396,9 +7045,10 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 	var results []json.RawMessage
 	var batchSize int
 	var batchedRequest bool
+	var limited bool
 
 	// Determine request type
-	if bytes.HasPrefix(body, batchedRequestPrefix) {
+	if isBatchedRequest(body) {
 		batchedRequest = true
 	}
 
@@ -5417,7 +7067,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 				log.Errorf("Failed to create reply: %v", err)
 			}
 		} else {
-			resp = s.processRequest(ctx, &req, isAdmin)
+			resp, limited = s.processRequest(ctx, &req, user, r.RemoteAddr)
 		}
 
 		if resp != nil {
@@ -5462,8 +7112,23 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 				}
 			}
 
+			// Respond with a single error when the batch is too large.
+			if len(batchedRequests) > rpcMaxBatchSize {
+				jsonErr := batchSizeError(len(batchedRequests))
+				resp, err = dcrjson.MarshalResponse("2.0", nil, nil, jsonErr)
+				if err != nil {
+					log.Errorf("Failed to marshal reply: %v", err)
+				}
+
+				if resp != nil {
+					results = append(results, resp)
+				}
+			}
+
 			// Process each batch entry individually
-			if len(batchedRequests) > 0 {
+			if len(batchedRequests) > 0 &&
+				len(batchedRequests) <= rpcMaxBatchSize {
+
 				batchSize = len(batchedRequests)
 
 				for _, entry := range batchedRequests {
@@ -5486,7 +7151,7 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 						continue
 					}
 
-					resp = s.processRequest(ctx, &req, isAdmin)
+					resp, _ = s.processRequest(ctx, &req, user, r.RemoteAddr)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -5521,20 +7186,34 @@ func (s *Server) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *htt
 		}
 	}
 
-	// Write the response.
-	err = s.writeHTTPResponseHeaders(r, w.Header(), http.StatusOK, buf)
+	// Write the response.  Single requests that were rejected due to
+	// exceeding the limits of the client are responded to with a too many
+	// requests status so generic HTTP clients are able to back off.
+	statusCode := http.StatusOK
+	if limited {
+		statusCode = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", "1")
+	}
+
+	// Terminate with newline to maintain compatibility with Bitcoin Core and
+	// compress the reply when the client supports it.
+	reply := make([]byte, 0, len(msg)+1)
+	reply = append(reply, msg...)
+	reply = append(reply, '\n')
+	reply, err = compressResponse(r, w.Header(), reply)
+	if err != nil {
+		log.Errorf("Failed to compress reply: %v", err)
+		return
+	}
+
+	err = s.writeHTTPResponseHeaders(r, w.Header(), statusCode, buf)
 	if err != nil {
 		log.Error(err)
 		return
 	}
-	if _, err := buf.Write(msg); err != nil {
+	if _, err := buf.Write(reply); err != nil {
 		log.Errorf("Failed to write marshalled reply: %v", err)
 	}
-
-	// Terminate with newline to maintain compatibility with Bitcoin Core.
-	if err := buf.WriteByte('\n'); err != nil {
-		log.Errorf("Failed to append terminating newline to reply: %v", err)
-	}
 }
 
 // jsonAuthFail sends a message back to the client if the http auth is rejected.
@@ -5613,19 +7292,31 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		// Keep track of the number of connected clients.
 		s.incrementClients()
 		defer s.decrementClients()
-		_, isAdmin, err := s.checkAuth(r, true)
+		_, user, err := s.checkAuth(r, true)
 		if err != nil {
 			jsonAuthFail(w)
 			return
 		}
 
 		// Read and respond to the request.
-		s.jsonRPCRead(r.Context(), w, r, isAdmin)
+		s.jsonRPCRead(r.Context(), w, r, user)
 	})
 
+	// Metrics endpoint.
+	rpcServeMux.HandleFunc(rpcMetricsPath, s.handleMetrics)
+
+	// Health and readiness endpoints.
+	rpcServeMux.HandleFunc(rpcHealthPath, s.handleProbe(false))
+	rpcServeMux.HandleFunc(rpcReadyPath, s.handleProbe(true))
+
+	// REST endpoints.
+	if s.cfg.EnableREST {
+		rpcServeMux.HandleFunc(restPathPrefix, s.handleREST)
+	}
+
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		authenticated, isAdmin, err := s.checkAuth(r, false)
+		authenticated, user, err := s.checkAuth(r, false)
 		if err != nil {
 			jsonAuthFail(w)
 			return
@@ -5635,6 +7326,12 @@ func (s *Server) route(ctx context.Context) *http.Server {
 		// default size for read/write buffers and impose a read limit that
 		// depends on whether or not the connection is authenticated yet.
 		upgrader := websocket.Upgrader{
+			// Allow clients to negotiate per-message compression since
+			// verbose replies and notifications are highly compressible.
+			// Note that the read limits are also enforced on the
+			// decompressed messages by the client input handler.
+			EnableCompression: true,
+
 			CheckOrigin: func(r *http.Request) bool {
 				// Allow requests with no origin header set.
 				origin := r.Header["Origin"]
@@ -5704,7 +7401,7 @@ func (s *Server) route(ctx context.Context) *http.Server {
 			ws.SetReadLimit(websocketReadLimitAuthenticated)
 		}
 		s.WebsocketHandler(r.Context(), ws, r.RemoteAddr, authenticated,
-			isAdmin)
+			user)
 	})
 	return httpServer
 }
@@ -5724,6 +7421,24 @@ func (s *Server) Run(ctx context.Context) {
 		}(listener)
 	}
 
+	// Connections accepted on unix domain sockets are served by a separate
+	// http server whose request contexts mark them as such so they are
+	// authenticated by filesystem permissions instead of credentials.
+	if len(s.cfg.UnixListeners) > 0 {
+		unixCtx := context.WithValue(ctx, unixSocketCtxKey{}, true)
+		unixServer := s.route(unixCtx)
+		for _, listener := range s.cfg.UnixListeners {
+			s.wg.Add(1)
+			go func(listener net.Listener) {
+				log.Infof("RPC server listening on unix socket %s",
+					listener.Addr())
+				unixServer.Serve(listener)
+				log.Tracef("RPC listener done for %s", listener.Addr())
+				s.wg.Done()
+			}(listener)
+		}
+	}
+
 	// Subscribe for async work notifications when background template
 	// generation is enabled.
 	if len(s.cfg.MiningAddrs) > 0 && s.cfg.BlockTemplater != nil {
@@ -5761,6 +7476,13 @@ type Config struct {
 	// is stopped.
 	Listeners []net.Listener
 
+	// UnixListeners defines a slice of unix domain socket listeners for
+	// which the RPC server will take ownership of and accept connections.
+	// Access to the sockets is controlled by their filesystem permissions,
+	// so connections accepted on them do not require credentials and are
+	// authorized for all methods and notifications.
+	UnixListeners []net.Listener
+
 	// StartupTime is the unix timestamp for when the server that is hosting
 	// the RPC server started.
 	StartupTime int64
@@ -5810,10 +7532,22 @@ type Config struct {
 	BlockTemplater BlockTemplater
 	CPUMiner       CPUMiner
 
+	// AddrIndexer defines the optional address indexer for the RPC server to
+	// use.
+	AddrIndexer AddrIndexer
+
+	// SpentIndexer defines the optional spent output indexer for the RPC
+	// server to use.
+	SpentIndexer SpentIndexer
+
 	// TxIndexer defines the optional transaction indexer for the RPC server to
 	// use.
 	TxIndexer TxIndexer
 
+	// ChainStateBackuper defines the source of chain state backups for the
+	// backupchainstate command.
+	ChainStateBackuper ChainStateBackuper
+
 	// NetInfo defines a slice of the available networks.
 	NetInfo []types.NetworksResult
 
@@ -5824,6 +7558,14 @@ type Config struct {
 	// Proxy defines the proxy that is being used for connections.
 	Proxy string
 
+	// BanDuration defines how long bans requested via the setban command
+	// last when no ban time is provided.
+	BanDuration time.Duration
+
+	// DataDir defines the directory that relative paths of files written by
+	// commands such as dumptxoutset are relative to.
+	DataDir string
+
 	// These fields define the username and password for RPC connections and
 	// limited RPC connections.
 	RPCUser      string
@@ -5831,6 +7573,20 @@ type Config struct {
 	RPCLimitUser string
 	RPCLimitPass string
 
+	// EnableREST enables the REST interface that serves blocks, headers, and
+	// unspent transaction outputs without requiring the RPC credentials.
+	EnableREST bool
+
+	// RESTToken defines an optional token that REST clients must provide via
+	// a bearer authorization header.  The REST interface does not require any
+	// authentication when it is empty.
+	RESTToken string
+
+	// Users defines additional RPC users along with the RPC methods and
+	// websocket notifications each of them is authorized for.  They may be
+	// replaced at runtime via SetUsers.
+	Users []UserAuth
+
 	// RPCMaxClients defines the max number of RPC clients for standard
 	// connections.
 	RPCMaxClients int
@@ -5842,6 +7598,24 @@ type Config struct {
 	// RPCMaxWebsockets defines the max number of RPC websocket connections.
 	RPCMaxWebsockets int
 
+	// RPCUserReqRate and RPCIPReqRate define the max number of RPC requests
+	// per second that may be issued by each authenticated user and each
+	// remote IP, respectively.  They are not limited when zero.
+	RPCUserReqRate float64
+	RPCIPReqRate   float64
+
+	// RPCUserMaxConcurrentReqs and RPCIPMaxConcurrentReqs define the max
+	// number of RPC requests of each authenticated user and each remote IP,
+	// respectively, that may be processed concurrently.  They are not limited
+	// when zero.
+	RPCUserMaxConcurrentReqs int
+	RPCIPMaxConcurrentReqs   int
+
+	// RPCSlowCallThreshold defines the minimum amount of time an RPC call must
+	// take in order for it to be logged along with its redacted parameters.
+	// Slow calls are not logged when it is zero.
+	RPCSlowCallThreshold time.Duration
+
 	// TestNet represents whether or not the server is using testnet.
 	TestNet bool
 
@@ -5864,6 +7638,11 @@ type Config struct {
 
 	// FiltererV2 defines the V2 filterer for the RPC server to use.
 	FiltererV2 FiltererV2
+
+	// MetricsSources defines additional sources of metrics that are served
+	// by the metrics endpoint after the RPC metrics.  This is optional, so it
+	// may be nil.
+	MetricsSources []MetricsSource
 }
 
 // New returns a new instance of the Server struct.
@@ -5873,7 +7652,9 @@ func New(config *Config) (*Server, error) {
 		statusLines:            make(map[int]string),
 		workState:              newWorkState(),
 		helpCacher:             newHelpCacher(),
+		metrics:                newRPCMetrics(),
 		requestProcessShutdown: make(chan struct{}),
+		blockSubmissions:       newBlockSubmissions(),
 	}
 	key := make([]byte, 32)
 	_, err := io.ReadFull(rand.Reader, key)
@@ -5882,17 +7663,26 @@ func New(config *Config) (*Server, error) {
 	}
 	rpc.hmac = hmac.New(sha256.New, key)
 	if config.RPCUser != "" && config.RPCPass != "" {
-		login := config.RPCUser + ":" + config.RPCPass
-		auth := "Basic " +
-			base64.StdEncoding.EncodeToString([]byte(login))
-		rpc.authMAC(rpc.authsha[:0], []byte(auth))
+		rpc.legacyUsers = append(rpc.legacyUsers, UserAuth{
+			User:          config.RPCUser,
+			Pass:          config.RPCPass,
+			Methods:       []string{allowAll},
+			Notifications: []string{allowAll},
+		})
 	}
 	if config.RPCLimitUser != "" && config.RPCLimitPass != "" {
-		login := config.RPCLimitUser + ":" + config.RPCLimitPass
-		auth := "Basic " +
-			base64.StdEncoding.EncodeToString([]byte(login))
-		rpc.authMAC(rpc.limitauthsha[:0], []byte(auth))
+		rpc.legacyUsers = append(rpc.legacyUsers,
+			limitedUserAuth(config.RPCLimitUser, config.RPCLimitPass))
+	}
+	rpc.openAuth = len(rpc.legacyUsers) == 0 && len(config.Users) == 0
+	rpc.authState.Store(&rpcAuthState{})
+	if !rpc.openAuth {
+		if err := rpc.SetUsers(config.Users); err != nil {
+			return nil, err
+		}
 	}
+	rpc.limiter = newRPCLimiter(config.RPCUserReqRate, config.RPCIPReqRate,
+		config.RPCUserMaxConcurrentReqs, config.RPCIPMaxConcurrentReqs)
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 
 	return &rpc, nil
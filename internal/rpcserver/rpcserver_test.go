@@ -7,9 +7,18 @@
 package rpcserver
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 )
 
@@ -67,7 +76,9 @@ func TestCheckAuthUserPass(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		authed, isAdmin := s.checkAuthUserPass(test.user, test.pass, "addr")
+		user := s.checkAuthUserPass(test.user, test.pass, "addr")
+		authed := user != nil
+		isAdmin := authed && user.isAdmin()
 		if authed != test.wantAuthed {
 			t.Errorf("%q: unexpected authed -- got %v, want %v", test.name, authed,
 				test.wantAuthed)
@@ -86,7 +97,8 @@ func TestCheckAuth(t *testing.T) {
 			t.Fatalf("unable to create RPC server: %v", err)
 		}
 		for i := 0; i <= 1; i++ {
-			authed, isAdmin, err := s.checkAuth(&http.Request{}, i == 0)
+			authed, user, err := s.checkAuth(&http.Request{}, i == 0)
+			isAdmin := user != nil && user.isAdmin()
 			if !authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, true)
 			}
@@ -109,7 +121,8 @@ func TestCheckAuth(t *testing.T) {
 			t.Fatalf("unable to create RPC server: %v", err)
 		}
 		for i := 0; i <= 1; i++ {
-			authed, isAdmin, err := s.checkAuth(&http.Request{}, i == 0)
+			authed, user, err := s.checkAuth(&http.Request{}, i == 0)
+			isAdmin := user != nil && user.isAdmin()
 			if authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, false)
 			}
@@ -125,7 +138,8 @@ func TestCheckAuth(t *testing.T) {
 		for i := 0; i <= 1; i++ {
 			r := &http.Request{Header: make(map[string][]string, 1)}
 			r.Header["Authorization"] = []string{"Basic Nothing"}
-			authed, isAdmin, err := s.checkAuth(r, i == 0)
+			authed, user, err := s.checkAuth(r, i == 0)
+			isAdmin := user != nil && user.isAdmin()
 			if authed {
 				t.Errorf(" unexpected authed -- got %v, want %v", authed, false)
 			}
@@ -136,6 +150,90 @@ func TestCheckAuth(t *testing.T) {
 				t.Errorf("unexpected err -- got %v, want auth failure", err)
 			}
 		}
+
+		// Requests received on unix domain sockets do not require
+		// credentials.
+		for i := 0; i <= 1; i++ {
+			ctx := context.WithValue(context.Background(),
+				unixSocketCtxKey{}, true)
+			r := (&http.Request{}).WithContext(ctx)
+			authed, user, err := s.checkAuth(r, i == 0)
+			isAdmin := user != nil && user.isAdmin()
+			if !authed {
+				t.Errorf(" unexpected authed -- got %v, want %v", authed, true)
+			}
+			if !isAdmin {
+				t.Errorf("unexpected isAdmin -- got %v, want %v", isAdmin, true)
+			}
+			if err != nil {
+				t.Errorf("unexpected err -- got %v, want %v", err, nil)
+			}
+		}
+	}
+}
+
+// TestUnixSocketListener ensures requests received on unix domain socket
+// listeners are served without credentials while requests received on other
+// listeners still require them.
+func TestUnixSocketListener(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCUser = "user"
+	cfg.RPCPass = "pass"
+	cfg.RPCMaxClients = 10
+	sockPath := filepath.Join(t.TempDir(), "rpc.sock")
+	unixListener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Skipf("unix domain sockets are not supported: %v", err)
+	}
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	cfg.Listeners = []net.Listener{tcpListener}
+	cfg.UnixListeners = []net.Listener{unixListener}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	post := func(client *http.Client, url string) int {
+		t.Helper()
+		body := `{"jsonrpc":"1.0","id":1,"method":"getblockcount","params":[]}`
+		resp, err := client.Post(url, "application/json",
+			strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error posting request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	unixClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sockPath)
+			},
+		},
+	}
+	if code := post(unixClient, "http://localhost"); code != http.StatusOK {
+		t.Fatalf("unexpected unix socket status -- got %d, want %d", code,
+			http.StatusOK)
+	}
+	tcpURL := "http://" + tcpListener.Addr().String()
+	if code := post(http.DefaultClient, tcpURL); code != http.StatusUnauthorized {
+		t.Fatalf("unexpected tcp status -- got %d, want %d", code,
+			http.StatusUnauthorized)
 	}
 }
 
@@ -151,3 +249,95 @@ func TestLimitedMethodsExist(t *testing.T) {
 		}
 	}
 }
+
+// TestBatchedRequests ensures batched JSON-RPC requests sent via HTTP POST are
+// answered in order with per-request errors and that batches exceeding the
+// maximum allowed size are rejected as a whole.
+func TestBatchedRequests(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.RPCMaxClients = 10
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpServer := httptest.NewServer(s.route(ctx).Handler)
+	defer httpServer.Close()
+
+	post := func(body string) []byte {
+		t.Helper()
+		resp, err := http.Post(httpServer.URL, "application/json",
+			strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected error posting request: %v", err)
+		}
+		defer resp.Body.Close()
+		reply, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading reply: %v", err)
+		}
+		return reply
+	}
+
+	type reply struct {
+		ID     *float64          `json:"id"`
+		Result json.RawMessage   `json:"result"`
+		Error  *dcrjson.RPCError `json:"error"`
+	}
+
+	// Ensure the responses are in request order, errors only affect the
+	// requests that caused them, and notifications are not answered.
+	body := ` [{"jsonrpc":"1.0","id":1,"method":"getblockcount","params":[]},
+		{"jsonrpc":"2.0","id":2,"method":"nonexistent","params":[]},
+		{"jsonrpc":"2.0","method":"getblockcount","params":[]},
+		{"jsonrpc":"2.0","id":3,"method":"getbestblockhash","params":[]}]`
+	var replies []reply
+	if err := json.Unmarshal(post(body), &replies); err != nil {
+		t.Fatalf("unexpected error decoding batched reply: %v", err)
+	}
+	if len(replies) != 3 {
+		t.Fatalf("unexpected number of replies -- got %d, want 3",
+			len(replies))
+	}
+	for i, wantID := range []float64{1, 2, 3} {
+		if replies[i].ID == nil || *replies[i].ID != wantID {
+			t.Fatalf("reply %d: unexpected id -- got %v, want %v", i,
+				replies[i].ID, wantID)
+		}
+	}
+	best := cfg.Chain.BestSnapshot()
+	if string(replies[0].Result) != fmt.Sprint(best.Height) ||
+		replies[0].Error != nil {
+
+		t.Fatalf("unexpected getblockcount reply: %+v", replies[0])
+	}
+	if replies[1].Error == nil ||
+		replies[1].Error.Code != dcrjson.ErrRPCMethodNotFound.Code {
+
+		t.Fatalf("unexpected nonexistent method reply: %+v", replies[1])
+	}
+	if string(replies[2].Result) != fmt.Sprintf("%q", best.Hash) ||
+		replies[2].Error != nil {
+
+		t.Fatalf("unexpected getbestblockhash reply: %+v", replies[2])
+	}
+
+	// Ensure batches that exceed the maximum size are rejected with a single
+	// error.
+	reqs := make([]string, rpcMaxBatchSize+1)
+	for i := range reqs {
+		reqs[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,`+
+			`"method":"getblockcount","params":[]}`, i)
+	}
+	var single reply
+	err = json.Unmarshal(post("["+strings.Join(reqs, ",")+"]"), &single)
+	if err != nil {
+		t.Fatalf("unexpected error decoding oversized batch reply: %v", err)
+	}
+	if single.Error == nil ||
+		single.Error.Code != dcrjson.ErrRPCInvalidRequest.Code {
+
+		t.Fatalf("unexpected oversized batch reply: %+v", single)
+	}
+}
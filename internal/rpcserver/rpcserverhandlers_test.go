@@ -12,6 +12,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"net"
@@ -19,6 +20,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -35,10 +37,12 @@ import (
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/gcs/v4"
 	"github.com/decred/dcrd/gcs/v4/blockcf2"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/peer/v3"
@@ -151,10 +155,15 @@ type testRPCChain struct {
 	checkLiveTickets              []bool
 	countVoteVersion              uint32
 	countVoteVersionErr           error
+	dumpUtxoSet                   *blockchain.UtxoSnapshotInfo
+	dumpUtxoSetErr                error
 	estimateNextStakeDifficultyFn func(hash *chainhash.Hash, newTickets int64, useMaxTickets bool) (diff int64, err error)
+	fetchSpentOutputs             map[wire.OutPoint]blockchain.SpentOutput
+	fetchSpentOutputsErr          error
 	fetchUtxoEntry                UtxoEntry
 	fetchUtxoEntryErr             error
 	fetchUtxoStats                *blockchain.UtxoStats
+	forEachUtxoFn                 func(fn func(outpoint wire.OutPoint, entry UtxoEntry) error) error
 	getStakeVersions              []blockchain.StakeVersions
 	getStakeVersionsErr           error
 	getVoteCounts                 blockchain.VoteCounts
@@ -165,7 +174,10 @@ type testRPCChain struct {
 	headerByHashErr               error
 	headerByHeight                wire.BlockHeader
 	headerByHeightErr             error
+	heightByMedianTime            int64
+	heightByMedianTimeErr         error
 	heightRangeFn                 func(startHeight, endHeight int64) ([]chainhash.Hash, error)
+	heightRangeByMedianTimeFn     func(start, end time.Time) (int64, int64)
 	invalidateBlockErr            error
 	isCurrent                     bool
 	liveTickets                   []chainhash.Hash
@@ -264,11 +276,27 @@ func (c *testRPCChain) CountVoteVersion(version uint32) (uint32, error) {
 	return c.countVoteVersion, c.countVoteVersionErr
 }
 
+// DumpUtxoSet writes a mocked utxo set snapshot to the provided writer.
+func (c *testRPCChain) DumpUtxoSet(w io.Writer) (*blockchain.UtxoSnapshotInfo, error) {
+	if c.dumpUtxoSetErr != nil {
+		return nil, c.dumpUtxoSetErr
+	}
+	if _, err := w.Write([]byte("snapshot")); err != nil {
+		return nil, err
+	}
+	return c.dumpUtxoSet, nil
+}
+
 // EstimateNextStakeDifficulty returns a mocked estimated next stake difficulty.
 func (c *testRPCChain) EstimateNextStakeDifficulty(hash *chainhash.Hash, newTickets int64, useMaxTickets bool) (int64, error) {
 	return c.estimateNextStakeDifficultyFn(hash, newTickets, useMaxTickets)
 }
 
+// FetchSpentOutputs returns mocked spent outputs.
+func (c *testRPCChain) FetchSpentOutputs(block *dcrutil.Block) (map[wire.OutPoint]blockchain.SpentOutput, error) {
+	return c.fetchSpentOutputs, c.fetchSpentOutputsErr
+}
+
 // FetchUtxoEntry returns a mocked UtxoEntry.
 func (c *testRPCChain) FetchUtxoEntry(outpoint wire.OutPoint) (UtxoEntry, error) {
 	return c.fetchUtxoEntry, c.fetchUtxoEntryErr
@@ -279,6 +307,11 @@ func (c *testRPCChain) FetchUtxoStats() (*blockchain.UtxoStats, error) {
 	return c.fetchUtxoStats, nil
 }
 
+// ForEachUtxo invokes the provided function with mocked utxos.
+func (c *testRPCChain) ForEachUtxo(fn func(outpoint wire.OutPoint, entry UtxoEntry) error) error {
+	return c.forEachUtxoFn(fn)
+}
+
 // GetStakeVersions returns a mocked cooked array of StakeVersions.
 func (c *testRPCChain) GetStakeVersions(hash *chainhash.Hash, count int32) ([]blockchain.StakeVersions, error) {
 	return c.getStakeVersions, c.getStakeVersionsErr
@@ -307,12 +340,24 @@ func (c *testRPCChain) HeaderByHeight(height int64) (wire.BlockHeader, error) {
 	return c.headerByHeight, c.headerByHeightErr
 }
 
+// HeightByMedianTime returns a mocked height of the first block in the main
+// chain with a past median time at or after the provided time.
+func (c *testRPCChain) HeightByMedianTime(t time.Time) (int64, error) {
+	return c.heightByMedianTime, c.heightByMedianTimeErr
+}
+
 // HeightRange returns a mocked range of block hashes for the given start and
 // end heights.
 func (c *testRPCChain) HeightRange(startHeight, endHeight int64) ([]chainhash.Hash, error) {
 	return c.heightRangeFn(startHeight, endHeight)
 }
 
+// HeightRangeByMedianTime returns a mocked range of heights of the blocks in
+// the main chain with a past median time in the provided time range.
+func (c *testRPCChain) HeightRangeByMedianTime(start, end time.Time) (int64, int64) {
+	return c.heightRangeByMedianTimeFn(start, end)
+}
+
 // InvalidateBlock returns a mocked error from manually invalidating a given
 // block.
 func (c *testRPCChain) InvalidateBlock(hash *chainhash.Hash) error {
@@ -517,6 +562,7 @@ type testSyncManager struct {
 	submitBlockErr        error
 	syncPeerID            int32
 	syncHeight            int64
+	syncStatus            netsync.SyncStatus
 	processTransaction    []*dcrutil.Tx
 	processTransactionErr error
 	recentlyConfirmedTxn  bool
@@ -544,6 +590,11 @@ func (s *testSyncManager) SyncHeight() int64 {
 	return s.syncHeight
 }
 
+// SyncStatus returns a mocked status of the chain sync process.
+func (s *testSyncManager) SyncStatus() netsync.SyncStatus {
+	return s.syncStatus
+}
+
 // ProcessTransaction provides a mock implementation for relaying the provided
 // transaction validation and insertion into the memory pool.
 func (s *testSyncManager) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
@@ -636,15 +687,113 @@ func (t *testTxIndexer) Entry(hash *chainhash.Hash) (*indexers.TxIndexEntry, err
 	return t.entry(hash)
 }
 
+// testAddrIndexer provides a mock address indexer by implementing the
+// AddrIndexer interface.
+type testAddrIndexer struct {
+	txns         []indexers.AddrIndexTx
+	txnsErr      error
+	tipHeight    int64
+	tipHash      *chainhash.Hash
+	tipErr       error
+	signalOnWait bool
+}
+
+// Name returns the human-readable name of the index.
+func (a *testAddrIndexer) Name() string {
+	return "testAddrIndexer"
+}
+
+// Tip returns the current index tip.
+func (a *testAddrIndexer) Tip() (int64, *chainhash.Hash, error) {
+	return a.tipHeight, a.tipHash, a.tipErr
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+func (a *testAddrIndexer) WaitForSync() chan bool {
+	c := make(chan bool)
+	if a.signalOnWait {
+		close(c)
+	}
+	return c
+}
+
+// Transactions returns the mocked transactions that involve the provided
+// address.
+func (a *testAddrIndexer) Transactions(addr stdaddr.Address, skip, count int, reverse bool) ([]indexers.AddrIndexTx, error) {
+	return a.txns, a.txnsErr
+}
+
+// testSpentIndexer provides a mock spent output indexer by implementing the
+// SpentIndexer interface.
+type testSpentIndexer struct {
+	entry        *indexers.SpentIndexEntry
+	entryErr     error
+	tipHeight    int64
+	tipHash      *chainhash.Hash
+	tipErr       error
+	signalOnWait bool
+}
+
+// Name returns the human-readable name of the index.
+func (s *testSpentIndexer) Name() string {
+	return "testSpentIndexer"
+}
+
+// Tip returns the current index tip.
+func (s *testSpentIndexer) Tip() (int64, *chainhash.Hash, error) {
+	return s.tipHeight, s.tipHash, s.tipErr
+}
+
+// WaitForSync subscribes clients for the next index sync update.
+func (s *testSpentIndexer) WaitForSync() chan bool {
+	c := make(chan bool)
+	if s.signalOnWait {
+		close(c)
+	}
+	return c
+}
+
+// SpentInfo returns the mocked details about the transaction input that spends
+// the provided outpoint.
+func (s *testSpentIndexer) SpentInfo(outpoint *wire.OutPoint) (*indexers.SpentIndexEntry, error) {
+	return s.entry, s.entryErr
+}
+
+// testChainStateBackuper provides a mock chain state backuper by implementing
+// the ChainStateBackuper interface.
+type testChainStateBackuper struct {
+	progress []database.BackupProgress
+	err      error
+}
+
+// BackupChainState creates the provided directory and reports the mocked
+// progress unless the mocked error is set.
+func (b *testChainStateBackuper) BackupChainState(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	if b.err != nil {
+		return b.err
+	}
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return err
+	}
+	for _, p := range b.progress {
+		progress(p)
+	}
+	return nil
+}
+
 // testDB provides a mock database by implementing the database.DB interface.
 type testDB struct {
-	dbType   string
-	beginTx  database.Tx
-	beginErr error
-	viewTx   database.Tx
-	updateTx database.Tx
-	closeErr error
-	flushErr error
+	dbType    string
+	beginTx   database.Tx
+	beginErr  error
+	viewTx    database.Tx
+	updateTx  database.Tx
+	closeErr  error
+	flushErr  error
+	viewErr   error
+	backupErr error
+	stats     *database.Stats
+	statsErr  error
 }
 
 // Type returns the mocked database driver type.
@@ -660,6 +809,9 @@ func (d *testDB) Begin(writable bool) (database.Tx, error) {
 // View invokes the passed function in the context of a mocked read-only
 // database transaction.
 func (d *testDB) View(fn func(tx database.Tx) error) error {
+	if d.viewErr != nil {
+		return d.viewErr
+	}
 	return fn(d.viewTx)
 }
 
@@ -679,6 +831,16 @@ func (d *testDB) Flush() error {
 	return d.flushErr
 }
 
+// Backup provides a mock implementation for the backup of the database.
+func (d *testDB) Backup(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	return d.backupErr
+}
+
+// Stats returns the mocked database statistics.
+func (d *testDB) Stats() (*database.Stats, error) {
+	return d.stats, d.statsErr
+}
+
 // testDatabaseTx provides a mock database transaction by implementing the
 // database.Tx interface.
 type testDatabaseTx struct {
@@ -784,10 +946,16 @@ type testConnManager struct {
 	connectedCount      int32
 	netTotalReceived    uint64
 	netTotalSent        uint64
+	netRecvPerMsg       map[string]uint64
+	netSentPerMsg       map[string]uint64
 	connectedPeers      []Peer
 	persistentPeers     []Peer
 	addedNodeInfo       []Peer
 	lookup              func(host string) ([]net.IP, error)
+	banErr              error
+	unbanErr            error
+	clearBannedErr      error
+	bannedSubnets       []banmgr.Entry
 }
 
 // Connect provides a mock implementation for adding the provided address as a
@@ -831,6 +999,12 @@ func (c *testConnManager) NetTotals() (uint64, uint64) {
 	return c.netTotalReceived, c.netTotalSent
 }
 
+// NetTotalsPerMsg returns mocked bytes received and sent across the network
+// for all peers keyed by message command.
+func (c *testConnManager) NetTotalsPerMsg() (map[string]uint64, map[string]uint64) {
+	return c.netRecvPerMsg, c.netSentPerMsg
+}
+
 // ConnectedPeers returns a mocked slice of all connected peers.
 func (c *testConnManager) ConnectedPeers() []Peer {
 	return c.connectedPeers
@@ -864,6 +1038,27 @@ func (c *testConnManager) Lookup(host string) ([]net.IP, error) {
 	return c.lookup(host)
 }
 
+// Ban provides a mock implementation for banning the provided subnet.
+func (c *testConnManager) Ban(subnet *net.IPNet, expires time.Time) error {
+	return c.banErr
+}
+
+// Unban provides a mock implementation for removing the ban for the provided
+// subnet.
+func (c *testConnManager) Unban(subnet *net.IPNet) error {
+	return c.unbanErr
+}
+
+// BannedSubnets returns a mocked slice of bans.
+func (c *testConnManager) BannedSubnets() []banmgr.Entry {
+	return c.bannedSubnets
+}
+
+// ClearBanned provides a mock implementation for removing all bans.
+func (c *testConnManager) ClearBanned() error {
+	return c.clearBannedErr
+}
+
 // testCPUMiner provides a mock CPU miner by implementing the CPUMiner
 // interface.
 type testCPUMiner struct {
@@ -950,6 +1145,7 @@ func (e *testFeeEstimator) EstimateFee(targetConfs int32) (dcrutil.Amount, error
 type testLogManager struct {
 	supportedSubsystems       []string
 	parseAndSetDebugLevelsErr error
+	debugLevels               map[string]string
 }
 
 // SupportedSubsystems returns a mocked slice of supported subsystems.
@@ -963,6 +1159,11 @@ func (l *testLogManager) ParseAndSetDebugLevels(debugLevel string) error {
 	return l.parseAndSetDebugLevelsErr
 }
 
+// DebugLevels returns a mocked map of subsystems to logging levels.
+func (l *testLogManager) DebugLevels() map[string]string {
+	return l.debugLevels
+}
+
 // testSanityChecker provides a mock implementation that checks the sanity
 // state of a block.
 type testSanityChecker struct {
@@ -980,6 +1181,8 @@ type testFiltererV2 struct {
 	filterByBlockHash      *gcs.FilterV2
 	filterByBlockHashProof *blockchain.HeaderProof
 	filterByBlockHashErr   error
+	mainChainFilters       []blockchain.MainChainFilter
+	mainChainFiltersErr    error
 }
 
 // FilterByBlockHash returns a mocked version 2 GCS filter for the given block
@@ -988,6 +1191,24 @@ func (f *testFiltererV2) FilterByBlockHash(hash *chainhash.Hash) (*gcs.FilterV2,
 	return f.filterByBlockHash, f.filterByBlockHashProof, f.filterByBlockHashErr
 }
 
+// MainChainFilters invokes the provided function with the mocked main chain
+// filters that are in the provided range of heights.
+func (f *testFiltererV2) MainChainFilters(ctx context.Context, startHeight, endHeight int64, fn func(*blockchain.MainChainFilter) error) error {
+	if f.mainChainFiltersErr != nil {
+		return f.mainChainFiltersErr
+	}
+	for i := range f.mainChainFilters {
+		filter := &f.mainChainFilters[i]
+		if filter.Height < startHeight || filter.Height > endHeight {
+			continue
+		}
+		if err := fn(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // testMiningState provides a mock mining state.
 type testMiningState struct {
 	allowUnsyncedMining bool
@@ -1080,6 +1301,10 @@ type testTxMempooler struct {
 	fetchTransaction    *dcrutil.Tx
 	fetchTransactionErr error
 	tspendHashes        []chainhash.Hash
+	rejectedTxns        map[chainhash.Hash]*mempool.RejectedTx
+	feeDeltas           map[chainhash.Hash]int64
+	ancestors           map[chainhash.Hash][]*mempool.TxDesc
+	descendants         map[chainhash.Hash][]*mempool.TxDesc
 }
 
 // HaveTransactions returns a mocked bool slice representing whether or not the
@@ -1100,6 +1325,31 @@ func (mp *testTxMempooler) VerboseTxDescs() []*mempool.VerboseTxDesc {
 	return mp.verboseTxDescs
 }
 
+// VerboseTxDesc returns the mock verbose descriptor for the transaction with
+// the provided hash from the mock verbose descriptors.
+func (mp *testTxMempooler) VerboseTxDesc(txHash *chainhash.Hash) (*mempool.VerboseTxDesc, bool) {
+	for _, desc := range mp.verboseTxDescs {
+		if *desc.Tx.Hash() == *txHash {
+			return desc, true
+		}
+	}
+	return nil, false
+}
+
+// Ancestors returns the mocked ancestors of the transaction with the provided
+// hash.
+func (mp *testTxMempooler) Ancestors(txHash *chainhash.Hash) ([]*mempool.TxDesc, bool) {
+	ancestors, ok := mp.ancestors[*txHash]
+	return ancestors, ok
+}
+
+// Descendants returns the mocked descendants of the transaction with the
+// provided hash.
+func (mp *testTxMempooler) Descendants(txHash *chainhash.Hash) ([]*mempool.TxDesc, bool) {
+	descendants, ok := mp.descendants[*txHash]
+	return descendants, ok
+}
+
 // Count returns a mock number of transactions in the main pool.
 func (mp *testTxMempooler) Count() int {
 	return mp.count
@@ -1117,6 +1367,19 @@ func (mp *testTxMempooler) TSpendHashes() []chainhash.Hash {
 	return mp.tspendHashes
 }
 
+// RejectedTx returns the mocked details about why the transaction with the
+// provided hash was rejected.
+func (mp *testTxMempooler) RejectedTx(hash *chainhash.Hash) (*mempool.RejectedTx, bool) {
+	rejected, ok := mp.rejectedTxns[*hash]
+	return rejected, ok
+}
+
+// PrioritiseTransaction returns the mocked existing fee delta for the
+// transaction with the provided hash adjusted by the given delta.
+func (mp *testTxMempooler) PrioritiseTransaction(hash *chainhash.Hash, feeDelta int64) int64 {
+	return mp.feeDeltas[*hash] + feeDelta
+}
+
 // testNtfnManager provides a mock notification manager by implementing the
 // NtfnManager interface.
 type testNtfnManager struct {
@@ -1142,6 +1405,10 @@ func (mgr *testNtfnManager) NotifyTSpend(tx *dcrutil.Tx) {}
 // the manager for processing.
 func (mgr *testNtfnManager) NotifyReorganization(rd *blockchain.ReorganizationNtfnsData) {}
 
+// NotifyTLSCertRenewed passes a newly generated PEM-encoded TLS certificate
+// and its expiration time to the manager for processing.
+func (mgr *testNtfnManager) NotifyTLSCertRenewed(cert []byte, validUntil time.Time) {}
+
 // NotifyWinningTickets passes newly winning tickets to the manager for
 // processing.
 func (mgr *testNtfnManager) NotifyWinningTickets(wtnd *WinningTicketsNtfnData) {}
@@ -1154,6 +1421,10 @@ func (mgr *testNtfnManager) NotifyNewTickets(tnd *blockchain.TicketNotifications
 // manager for processing.
 func (mgr *testNtfnManager) NotifyMempoolTx(tx *dcrutil.Tx, isNew bool) {}
 
+// NotifyMempoolEvent passes a transaction being added to or removed from the
+// mempool to the manager for processing.
+func (mgr *testNtfnManager) NotifyMempoolEvent(event *mempool.TxEvent) {}
+
 // NumClients returns the number of clients actively being served.
 func (mgr *testNtfnManager) NumClients() int {
 	return mgr.clients
@@ -1163,6 +1434,10 @@ func (mgr *testNtfnManager) NumClients() int {
 // websocket client.
 func (mgr *testNtfnManager) RegisterBlockUpdates(wsc *wsClient) {}
 
+// RegisterBlockUpdatesSince requests block update notifications to the passed
+// websocket client after replaying the missed notifications.
+func (mgr *testNtfnManager) RegisterBlockUpdatesSince(wsc *wsClient, since *chainhash.Hash) {}
+
 // UnregisterBlockUpdates removes block update notifications for the passed
 // websocket client.
 func (mgr *testNtfnManager) UnregisterBlockUpdates(wsc *wsClient) {}
@@ -1215,6 +1490,30 @@ func (mgr *testNtfnManager) RegisterNewMempoolTxsUpdates(wsc *wsClient) {}
 // client when new transaction are added to the memory pool.
 func (mgr *testNtfnManager) UnregisterNewMempoolTxsUpdates(wsc *wsClient) {}
 
+// RegisterMempoolEvents requests notifications to the passed websocket client
+// when transactions are added to or removed from the memory pool.
+func (mgr *testNtfnManager) RegisterMempoolEvents(wsc *wsClient) {}
+
+// UnregisterMempoolEvents removes notifications to the passed websocket client
+// when transactions are added to or removed from the memory pool.
+func (mgr *testNtfnManager) UnregisterMempoolEvents(wsc *wsClient) {}
+
+// RegisterTicketEvents requests notifications to the passed websocket client
+// when tickets are purchased, mature, vote, are missed, or are revoked.
+func (mgr *testNtfnManager) RegisterTicketEvents(wsc *wsClient) {}
+
+// UnregisterTicketEvents removes notifications to the passed websocket client
+// when tickets are purchased, mature, vote, are missed, or are revoked.
+func (mgr *testNtfnManager) UnregisterTicketEvents(wsc *wsClient) {}
+
+// RegisterTemplateDeltas requests block template delta notifications to the
+// passed websocket client.
+func (mgr *testNtfnManager) RegisterTemplateDeltas(wsc *wsClient) {}
+
+// UnregisterTemplateDeltas removes block template delta notifications for the
+// passed websocket client.
+func (mgr *testNtfnManager) UnregisterTemplateDeltas(wsc *wsClient) {}
+
 // AddClient adds the passed websocket client to the notification manager.
 func (mgr *testNtfnManager) AddClient(wsc *wsClient) {}
 
@@ -1236,6 +1535,8 @@ type testHelpCacher struct {
 	methodHelpErr error
 	usage         string
 	usageErr      error
+	openRPC       *dcrjson.OpenRPCDocument
+	openRPCErr    error
 }
 
 // RPCMethodHelp returns the mocked RPC help string for the provided method.
@@ -1248,6 +1549,11 @@ func (c *testHelpCacher) RPCUsage(includeWebsockets bool) (string, error) {
 	return c.usage, c.usageErr
 }
 
+// OpenRPCDocument returns the mocked OpenRPC document.
+func (c *testHelpCacher) OpenRPCDocument() (*dcrjson.OpenRPCDocument, error) {
+	return c.openRPC, c.openRPCErr
+}
+
 // mustParseHash converts the passed big-endian hex string into a
 // chainhash.Hash and will panic if there is an error.  It only differs from the
 // one available in chainhash in that it will panic so errors in the source code
@@ -1361,6 +1667,9 @@ type rpcTest struct {
 	setExistsAddresserNil bool
 	mockTxIndexer         *testTxIndexer
 	setTxIndexerNil       bool
+	mockAddrIndexer       *testAddrIndexer
+	mockSpentIndexer      *testSpentIndexer
+	mockBackuper          *testChainStateBackuper
 	mockDB                *testDB
 	mockConnManager       *testConnManager
 	mockClock             *testClock
@@ -1458,6 +1767,12 @@ func defaultMockRPCChain() *testRPCChain {
 			Status:    "active",
 		}},
 		chainWork: chainWork,
+		dumpUtxoSet: &blockchain.UtxoSnapshotInfo{
+			BlockHash:   *blkHash,
+			BlockHeight: blkHeight,
+			Utxos:       1593879,
+			MuHash:      *mustParseHash("2f5c7ab13b9a0de4f5e4c0c7f85e6c6f1a3d4b9e8c7f6a5b4c3d2e1f0a9b8c7d"),
+		},
 		estimateNextStakeDifficultyFn: func(*chainhash.Hash, int64, bool) (int64, error) {
 			return 14336790201, nil
 		},
@@ -1485,6 +1800,7 @@ func defaultMockRPCChain() *testRPCChain {
 			Size:           36441617,
 			Total:          1154067750680149,
 			SerializedHash: *mustParseHash("fe7b32aa188800f07268b17f3bead5f3d8a1b6d18654182066436efce6effa86"),
+			MuHash:         *mustParseHash("2f5c7ab13b9a0de4f5e4c0c7f85e6c6f1a3d4b9e8c7f6a5b4c3d2e1f0a9b8c7d"),
 		},
 		getStakeVersions: []blockchain.StakeVersions{{
 			Hash:         *blkHash,
@@ -1590,6 +1906,36 @@ func defaultMockExistsAddresser() *testExistsAddresser {
 	}
 }
 
+// defaultMockAddrIndexer provides a default mock address indexer to be used
+// throughout the tests.  The address index is optional, so it is not part of
+// the default config.  Tests can override these defaults by calling
+// defaultMockAddrIndexer, updating fields as necessary on the returned
+// *testAddrIndexer, and then setting rpcTest.mockAddrIndexer as that
+// *testAddrIndexer.
+func defaultMockAddrIndexer() *testAddrIndexer {
+	bestHash := block432100.Header.BlockHash()
+	return &testAddrIndexer{
+		tipHeight:    int64(block432100.Header.Height),
+		tipHash:      &bestHash,
+		signalOnWait: true,
+	}
+}
+
+// defaultMockSpentIndexer provides a default mock spent output indexer to be
+// used throughout the tests.  The spent output index is optional, so it is not
+// part of the default config.  Tests can override these defaults by calling
+// defaultMockSpentIndexer, updating fields as necessary on the returned
+// *testSpentIndexer, and then setting rpcTest.mockSpentIndexer as that
+// *testSpentIndexer.
+func defaultMockSpentIndexer() *testSpentIndexer {
+	bestHash := block432100.Header.BlockHash()
+	return &testSpentIndexer{
+		tipHeight:    int64(block432100.Header.Height),
+		tipHash:      &bestHash,
+		signalOnWait: true,
+	}
+}
+
 // defaultMockTxIndexer provides a default mock transaction indexer to be
 // used throughout the tests. Tests can override these defaults by calling
 // defaultMockTxIndexer, updating fields as necessary on the returned
@@ -1673,6 +2019,13 @@ func defaultMockConnManager() *testConnManager {
 		connectedCount:   4,
 		netTotalReceived: 9598159,
 		netTotalSent:     4783802,
+		netRecvPerMsg: map[string]uint64{
+			"block":   9598000,
+			"*other*": 159,
+		},
+		netSentPerMsg: map[string]uint64{
+			"getdata": 4783802,
+		},
 		connectedPeers: []Peer{
 			testPeer1,
 			testPeer2,
@@ -1717,6 +2070,11 @@ func defaultMockFeeEstimator() *testFeeEstimator {
 func defaultMockLogManager() *testLogManager {
 	return &testLogManager{
 		supportedSubsystems: []string{"DCRD", "PEER", "RPCS"},
+		debugLevels: map[string]string{
+			"DCRD": "info",
+			"PEER": "info",
+			"RPCS": "debug",
+		},
 	}
 }
 
@@ -1741,6 +2099,13 @@ func defaultMockFiltererV2() *testFiltererV2 {
 	return &testFiltererV2{
 		filterByBlockHash:      filter,
 		filterByBlockHashProof: &headerProof,
+		mainChainFilters: []blockchain.MainChainFilter{{
+			Height: int64(block432100.Header.Height),
+			Hash:   block432100.BlockHash(),
+			Header: block432100.Header,
+			Filter: filter,
+			Proof:  headerProof,
+		}},
 	}
 }
 
@@ -1878,6 +2243,28 @@ func TestHandleAddNode(t *testing.T) {
 	}})
 }
 
+func TestHandleClearBanned(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleClearBanned: ok",
+		handler: handleClearBanned,
+		cmd:     &types.ClearBannedCmd{},
+		result:  nil,
+	}, {
+		name:    "handleClearBanned: failed to persist",
+		handler: handleClearBanned,
+		cmd:     &types.ClearBannedCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.clearBannedErr = errors.New("disk full")
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}})
+}
+
 func TestHandleCreateRawSStx(t *testing.T) {
 	t.Parallel()
 
@@ -2632,6 +3019,18 @@ func TestHandleDebugLevel(t *testing.T) {
 	}})
 }
 
+func TestHandleGetDebugLevels(t *testing.T) {
+	t.Parallel()
+
+	logMgr := defaultMockLogManager()
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetDebugLevels: ok",
+		handler: handleGetDebugLevels,
+		cmd:     &types.GetDebugLevelsCmd{},
+		result:  logMgr.debugLevels,
+	}})
+}
+
 func TestHandleDecodeRawTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -2752,10 +3151,19 @@ func TestHandleDecodeScript(t *testing.T) {
 	p2sstxshRes := types.DecodeScriptResult{
 		Asm: "OP_SSTX OP_DUP OP_HASH160 0000000000000000000000000000" +
 			"000000000000 OP_EQUALVERIFY OP_CHECKSIG",
-		ReqSigs:   1,
-		Type:      "stakesubmission-pubkeyhash",
-		Addresses: []string{"DsQxuVRvS4eaJ42dhQEsCXauMWjvopWgrVg"},
-		P2sh:      "DcaBW1ecMLBzXSS9Q8YRV3aBc5qQeaA1WPo",
+		ReqSigs:       1,
+		Type:          "stakesubmission-pubkeyhash",
+		StakeSubClass: "pubkeyhash",
+		Addresses:     []string{"DsQxuVRvS4eaJ42dhQEsCXauMWjvopWgrVg"},
+		P2sh:          "DcaBW1ecMLBzXSS9Q8YRV3aBc5qQeaA1WPo",
+		Ops: []types.DecodeScriptOp{
+			{Offset: 0, Asm: "OP_SSTX"},
+			{Offset: 1, Asm: "OP_DUP"},
+			{Offset: 2, Asm: "OP_HASH160"},
+			{Offset: 3, Asm: "0000000000000000000000000000000000000000"},
+			{Offset: 24, Asm: "OP_EQUALVERIFY"},
+			{Offset: 25, Asm: "OP_CHECKSIG"},
+		},
 	}
 	aHex := "0A"
 	aHexRes := types.DecodeScriptResult{
@@ -2764,6 +3172,7 @@ func TestHandleDecodeScript(t *testing.T) {
 		Type:      "nonstandard",
 		Addresses: []string{},
 		P2sh:      "DcbuYCoW1nJZhFf1ZyGXjoPL6D3ezNwwWjj",
+		Ops:       []types.DecodeScriptOp{{Offset: 0, Asm: "[error]"}},
 	}
 	// This is a 2 of 2 multisig script.
 	multiSig := "5221030000000000000000000000000000000000000000000000000" +
@@ -2778,6 +3187,15 @@ func TestHandleDecodeScript(t *testing.T) {
 		Addresses: []string{"DsdvMfW6wGbGCXSNWidWtfP1tPmnCLNXQyC",
 			"DsSkAQDPhDW3foES4fcfmpkPYYZhnV3R4ws"},
 		P2sh: "DcexHKLpqiM49auD2jbxPH6enwm9u1ZFAo6",
+		Ops: []types.DecodeScriptOp{
+			{Offset: 0, Asm: "2"},
+			{Offset: 1, Asm: "03000000000000000000000000000000000000000000" +
+				"0000000000000000000001"},
+			{Offset: 35, Asm: "03000000000000000000000000000000000000000000" +
+				"0000000000000000000002"},
+			{Offset: 69, Asm: "2"},
+			{Offset: 70, Asm: "OP_CHECKMULTISIG"},
+		},
 	}
 	// This is a pay to script hash script.
 	p2sh := "a914000000000000000000000000000000000000000087"
@@ -2787,6 +3205,52 @@ func TestHandleDecodeScript(t *testing.T) {
 		ReqSigs:   1,
 		Type:      "scripthash",
 		Addresses: []string{"DcXTb4QtmnyRsnzUVViYQawqFE5PuYTdX2C"},
+		Ops: []types.DecodeScriptOp{
+			{Offset: 0, Asm: "OP_HASH160"},
+			{Offset: 1, Asm: "0000000000000000000000000000000000000000"},
+			{Offset: 22, Asm: "OP_EQUAL"},
+		},
+	}
+	// This is a pay to script hash script for the multisig script above along
+	// with a stake change tagged version of it.
+	multiSigScript, _ := hex.DecodeString(multiSig)
+	multiSigHash := hex.EncodeToString(stdaddr.Hash160(multiSigScript))
+	multiSigRedeemRes := types.DecodeRedeemScriptResult{
+		Asm:       multiSigRes.Asm,
+		ReqSigs:   multiSigRes.ReqSigs,
+		Type:      multiSigRes.Type,
+		Addresses: multiSigRes.Addresses,
+		P2sh:      multiSigRes.P2sh,
+		Ops:       multiSigRes.Ops,
+	}
+	p2shMultiSig := "a914" + multiSigHash + "87"
+	p2shMultiSigRes := types.DecodeScriptResult{
+		Asm:       "OP_HASH160 " + multiSigHash + " OP_EQUAL",
+		ReqSigs:   1,
+		Type:      "scripthash",
+		Addresses: []string{"DcexHKLpqiM49auD2jbxPH6enwm9u1ZFAo6"},
+		Ops: []types.DecodeScriptOp{
+			{Offset: 0, Asm: "OP_HASH160"},
+			{Offset: 1, Asm: multiSigHash},
+			{Offset: 22, Asm: "OP_EQUAL"},
+		},
+		RedeemScript: &multiSigRedeemRes,
+	}
+	p2sstxchangeMultiSig := "bd" + p2shMultiSig
+	p2sstxchangeMultiSigRes := types.DecodeScriptResult{
+		Asm:           "OP_SSTXCHANGE " + p2shMultiSigRes.Asm,
+		ReqSigs:       1,
+		Type:          "stakechange-scripthash",
+		StakeSubClass: "scripthash",
+		Addresses:     []string{"DcexHKLpqiM49auD2jbxPH6enwm9u1ZFAo6"},
+		P2sh:          "DcbCPQCkmybMnExmCW9P17w9cii2tpGYy5i",
+		Ops: []types.DecodeScriptOp{
+			{Offset: 0, Asm: "OP_SSTXCHANGE"},
+			{Offset: 1, Asm: "OP_HASH160"},
+			{Offset: 2, Asm: multiSigHash},
+			{Offset: 23, Asm: "OP_EQUAL"},
+		},
+		RedeemScript: &multiSigRedeemRes,
 	}
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleDecodeScript: ok no version",
@@ -2839,6 +3303,191 @@ func TestHandleDecodeScript(t *testing.T) {
 		},
 		wantErr: true,
 		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleDecodeScript: ok p2sh with redeem script",
+		handler: handleDecodeScript,
+		cmd: &types.DecodeScriptCmd{
+			HexScript:    p2shMultiSig,
+			RedeemScript: dcrjson.String(multiSig),
+		},
+		result: p2shMultiSigRes,
+	}, {
+		name:    "handleDecodeScript: ok stake p2sh with redeem script",
+		handler: handleDecodeScript,
+		cmd: &types.DecodeScriptCmd{
+			HexScript:    p2sstxchangeMultiSig,
+			RedeemScript: dcrjson.String(multiSig),
+		},
+		result: p2sstxchangeMultiSigRes,
+	}, {
+		name:    "handleDecodeScript: redeem script for non-p2sh",
+		handler: handleDecodeScript,
+		cmd: &types.DecodeScriptCmd{
+			HexScript:    multiSig,
+			RedeemScript: dcrjson.String(multiSig),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDecodeScript: mismatched redeem script",
+		handler: handleDecodeScript,
+		cmd: &types.DecodeScriptCmd{
+			HexScript:    p2sh,
+			RedeemScript: dcrjson.String(multiSig),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDecodeScript: invalid redeem script hex",
+		handler: handleDecodeScript,
+		cmd: &types.DecodeScriptCmd{
+			HexScript:    p2sh,
+			RedeemScript: dcrjson.String("Q"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}})
+}
+
+func TestHandleBackupChainState(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing")
+	if err := os.Mkdir(existing, 0700); err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+	okDir := filepath.Join(dir, "backup")
+	errDir := filepath.Join(dir, "error")
+
+	// Ensure the backup was renamed into place and the incomplete directory
+	// from the failed backup was removed once the parallel subtests complete.
+	t.Cleanup(func() {
+		if _, err := os.Stat(okDir); err != nil {
+			t.Errorf("backup directory not written: %v", err)
+		}
+		_, err := os.Stat(errDir + ".incomplete")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("incomplete backup directory not removed: %v", err)
+		}
+	})
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleBackupChainState: ok",
+		handler: handleBackupChainState,
+		cmd:     &types.BackupChainStateCmd{Dir: okDir},
+		mockBackuper: &testChainStateBackuper{
+			progress: []database.BackupProgress{
+				{BytesCopied: 1000, TotalBytes: 3000},
+				{BytesCopied: 3000, TotalBytes: 3000},
+			},
+		},
+		mockClock: &testClock{since: 15 * time.Second},
+		result: &types.BackupChainStateResult{
+			Dir:     okDir,
+			Bytes:   3000,
+			Elapsed: 15,
+		},
+	}, {
+		name:    "handleBackupChainState: not available",
+		handler: handleBackupChainState,
+		cmd:     &types.BackupChainStateCmd{Dir: okDir},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:         "handleBackupChainState: empty dir",
+		handler:      handleBackupChainState,
+		cmd:          &types.BackupChainStateCmd{},
+		mockBackuper: &testChainStateBackuper{},
+		wantErr:      true,
+		errCode:      dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:         "handleBackupChainState: relative dir without data dir",
+		handler:      handleBackupChainState,
+		cmd:          &types.BackupChainStateCmd{Dir: "backup"},
+		mockBackuper: &testChainStateBackuper{},
+		wantErr:      true,
+		errCode:      dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:         "handleBackupChainState: dir exists",
+		handler:      handleBackupChainState,
+		cmd:          &types.BackupChainStateCmd{Dir: existing},
+		mockBackuper: &testChainStateBackuper{},
+		wantErr:      true,
+		errCode:      dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleBackupChainState: backup error",
+		handler: handleBackupChainState,
+		cmd:     &types.BackupChainStateCmd{Dir: errDir},
+		mockBackuper: &testChainStateBackuper{
+			err: errors.New("backup error"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
+func TestHandleDumpTxOutSet(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.dat")
+	if err := os.WriteFile(existing, nil, 0600); err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+	okPath := filepath.Join(dir, "utxos.dat")
+	errPath := filepath.Join(dir, "error.dat")
+
+	// Ensure the snapshot was renamed into place and the incomplete file from
+	// the failed dump was removed once the parallel subtests complete.
+	t.Cleanup(func() {
+		if _, err := os.Stat(okPath); err != nil {
+			t.Errorf("snapshot file not written: %v", err)
+		}
+		_, err := os.Stat(errPath + ".incomplete")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("incomplete snapshot file not removed: %v", err)
+		}
+	})
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleDumpTxOutSet: ok",
+		handler: handleDumpTxOutSet,
+		cmd:     &types.DumpTxOutSetCmd{Path: okPath},
+		result: &types.DumpTxOutSetResult{
+			Utxos:     1593879,
+			BlockHash: block432100.BlockHash().String(),
+			Height:    int64(block432100.Header.Height),
+			MuHash:    "2f5c7ab13b9a0de4f5e4c0c7f85e6c6f1a3d4b9e8c7f6a5b4c3d2e1f0a9b8c7d",
+			Path:      okPath,
+		},
+	}, {
+		name:    "handleDumpTxOutSet: empty path",
+		handler: handleDumpTxOutSet,
+		cmd:     &types.DumpTxOutSetCmd{},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDumpTxOutSet: relative path without data dir",
+		handler: handleDumpTxOutSet,
+		cmd:     &types.DumpTxOutSetCmd{Path: "utxos.dat"},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDumpTxOutSet: file exists",
+		handler: handleDumpTxOutSet,
+		cmd:     &types.DumpTxOutSetCmd{Path: existing},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleDumpTxOutSet: dump error",
+		handler: handleDumpTxOutSet,
+		cmd:     &types.DumpTxOutSetCmd{Path: errPath},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.dumpUtxoSetErr = errors.New("dump error")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}})
 }
 
@@ -3785,6 +4434,25 @@ func TestHandleGetBlock(t *testing.T) {
 		rawSTxns[i] = *rawSTxn
 	}
 
+	// Create raw transaction results that include the previous outputs spent
+	// by the first regular transaction that spends an output.
+	spender := txns[1].MsgTx()
+	spentOutputs := make(map[wire.OutPoint]blockchain.SpentOutput)
+	prevOutTxns := append([]types.TxRawResult(nil), rawTxns...)
+	prevOutTxns[1].Vin = append([]types.Vin(nil), rawTxns[1].Vin...)
+	for i, txIn := range spender.TxIn {
+		txOut := spender.TxOut[0]
+		spentOutputs[txIn.PreviousOutPoint] = blockchain.SpentOutput{
+			Amount:        txOut.Value,
+			ScriptVersion: txOut.Version,
+			PkScript:      txOut.PkScript,
+		}
+		prevOutTxns[1].Vin[i].PrevOut = &types.PrevOut{
+			Value:        rawTxns[1].Vout[0].Value,
+			ScriptPubKey: rawTxns[1].Vout[0].ScriptPubKey,
+		}
+	}
+
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleGetBlock: ok",
 		handler: handleGetBlock,
@@ -3895,27 +4563,107 @@ func TestHandleGetBlock(t *testing.T) {
 			RawSTx:        rawSTxns,
 		},
 	}, {
-		name:    "handleGetBlock: invalid hash",
-		handler: handleGetBlock,
-		cmd: &types.GetBlockCmd{
-			Hash:      "invalid",
-			Verbose:   dcrjson.Bool(false),
-			VerboseTx: dcrjson.Bool(false),
-		},
-		wantErr: true,
-		errCode: dcrjson.ErrRPCDecodeHexString,
-	}, {
-		name:    "handleGetBlock: block not found",
+		name:    "handleGetBlock: ok verbose transactions with prevouts",
 		handler: handleGetBlock,
 		cmd: &types.GetBlockCmd{
-			Hash:      blkHashString,
-			Verbose:   dcrjson.Bool(false),
-			VerboseTx: dcrjson.Bool(false),
+			Hash:           blkHashString,
+			Verbose:        dcrjson.Bool(true),
+			VerboseTx:      dcrjson.Bool(true),
+			VerbosePrevOut: dcrjson.Bool(true),
 		},
 		mockChain: func() *testRPCChain {
 			chain := defaultMockRPCChain()
-			chain.blockByHashErr = errors.New("block not found")
-			return chain
+			chain.treasuryActive = false
+			chain.bestSnapshot = &blockchain.BestState{
+				Height: bestHeight,
+			}
+			chain.blockByHash = blk
+			chain.blockHashByHeight = nextHash
+			chain.fetchSpentOutputs = spentOutputs
+			return chain
+		}(),
+		result: types.GetBlockVerboseResult{
+			Hash:          blkHashString,
+			Version:       blkHeader.Version,
+			MerkleRoot:    blkHeader.MerkleRoot.String(),
+			StakeRoot:     blkHeader.StakeRoot.String(),
+			PreviousHash:  blkHeader.PrevBlock.String(),
+			Nonce:         blkHeader.Nonce,
+			VoteBits:      blkHeader.VoteBits,
+			FinalState:    hex.EncodeToString(blkHeader.FinalState[:]),
+			Voters:        blkHeader.Voters,
+			FreshStake:    blkHeader.FreshStake,
+			Revocations:   blkHeader.Revocations,
+			PoolSize:      blkHeader.PoolSize,
+			Time:          blkHeader.Timestamp.Unix(),
+			MedianTime:    time.Time{}.Unix(),
+			StakeVersion:  blkHeader.StakeVersion,
+			Confirmations: confirmations,
+			Height:        int64(blkHeader.Height),
+			Size:          int32(blkHeader.Size),
+			Bits:          strconv.FormatInt(int64(blkHeader.Bits), 16),
+			SBits:         dcrutil.Amount(blkHeader.SBits).ToCoin(),
+			Difficulty:    float64(28147398026.656624),
+			ChainWork:     fmt.Sprintf("%064x", chainWork),
+			ExtraData:     hex.EncodeToString(blkHeader.ExtraData[:]),
+			NextHash:      nextHash.String(),
+			RawTx:         prevOutTxns,
+			RawSTx:        rawSTxns,
+		},
+	}, {
+		name:    "handleGetBlock: prevouts for block not in main chain",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:           blkHashString,
+			Verbose:        dcrjson.Bool(true),
+			VerboseTx:      dcrjson.Bool(true),
+			VerbosePrevOut: dcrjson.Bool(true),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.mainChainHasBlock = false
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleGetBlock: unable to fetch spent outputs",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:           blkHashString,
+			Verbose:        dcrjson.Bool(true),
+			VerboseTx:      dcrjson.Bool(true),
+			VerbosePrevOut: dcrjson.Bool(true),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.fetchSpentOutputsErr = errors.New("unable to fetch spent outputs")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlock: invalid hash",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      "invalid",
+			Verbose:   dcrjson.Bool(false),
+			VerboseTx: dcrjson.Bool(false),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetBlock: block not found",
+		handler: handleGetBlock,
+		cmd: &types.GetBlockCmd{
+			Hash:      blkHashString,
+			Verbose:   dcrjson.Bool(false),
+			VerboseTx: dcrjson.Bool(false),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockByHashErr = errors.New("block not found")
+			return chain
 		}(),
 		wantErr: true,
 		errCode: dcrjson.ErrRPCBlockNotFound,
@@ -3981,6 +4729,72 @@ func TestHandleGetBlockCount(t *testing.T) {
 	}})
 }
 
+func TestHandleGetBlockByTime(t *testing.T) {
+	t.Parallel()
+
+	blkHash := block432100.BlockHash()
+	blkHeight := int64(block432100.Header.Height)
+	medianTime := time.Unix(1584246683, 0)
+	chain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.heightByMedianTime = blkHeight
+		chain.medianTimeByHash = medianTime
+		return chain
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetBlockByTime: ok",
+		handler: handleGetBlockByTime,
+		cmd: &types.GetBlockByTimeCmd{
+			Timestamp: 1584246000,
+		},
+		mockChain: chain(),
+		result: &types.GetBlockByTimeResult{
+			Hash:       blkHash.String(),
+			Height:     blkHeight,
+			MedianTime: medianTime.Unix(),
+		},
+	}, {
+		name:    "handleGetBlockByTime: no block at or after time",
+		handler: handleGetBlockByTime,
+		cmd: &types.GetBlockByTimeCmd{
+			Timestamp: 1584246684,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain()
+			chain.heightByMedianTimeErr = errors.New("no block")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCOutOfRange,
+	}, {
+		name:    "handleGetBlockByTime: block no longer in main chain",
+		handler: handleGetBlockByTime,
+		cmd: &types.GetBlockByTimeCmd{
+			Timestamp: 1584246000,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain()
+			chain.blockHashByHeightErr = errors.New("no block at height")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlockByTime: unable to fetch median time",
+		handler: handleGetBlockByTime,
+		cmd: &types.GetBlockByTimeCmd{
+			Timestamp: 1584246000,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain()
+			chain.medianTimeByHashErr = errors.New("unknown block")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetBlockHash(t *testing.T) {
 	t.Parallel()
 
@@ -4146,6 +4960,119 @@ func TestHandleGetBlockHeader(t *testing.T) {
 	}})
 }
 
+func TestHandleGetBlocksByTime(t *testing.T) {
+	t.Parallel()
+
+	blkHash := block432100.BlockHash()
+	blkHeight := int64(block432100.Header.Height)
+	medianTime := time.Unix(1584246683, 0)
+	chain := func(startHeight, endHeight int64) *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.heightRangeByMedianTimeFn = func(_, _ time.Time) (int64, int64) {
+			return startHeight, endHeight
+		}
+		chain.heightRangeFn = func(start, end int64) ([]chainhash.Hash, error) {
+			return make([]chainhash.Hash, end-start), nil
+		}
+		chain.medianTimeByHash = medianTime
+		return chain
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetBlocksByTime: ok",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584247000,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain(blkHeight, blkHeight+1)
+			chain.heightRangeFn = func(_, _ int64) ([]chainhash.Hash, error) {
+				return []chainhash.Hash{blkHash}, nil
+			}
+			return chain
+		}(),
+		result: []types.GetBlockByTimeResult{{
+			Hash:       blkHash.String(),
+			Height:     blkHeight,
+			MedianTime: medianTime.Unix(),
+		}},
+	}, {
+		name:    "handleGetBlocksByTime: ok, no blocks in range",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584246000,
+		},
+		mockChain: chain(blkHeight, blkHeight),
+		result:    []types.GetBlockByTimeResult{},
+	}, {
+		name:    "handleGetBlocksByTime: ok, limited to count",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584247000,
+			Count:     dcrjson.Int(1),
+		},
+		mockChain: chain(blkHeight, blkHeight+5),
+		result: []types.GetBlockByTimeResult{{
+			Hash:       zeroHash.String(),
+			Height:     blkHeight,
+			MedianTime: medianTime.Unix(),
+		}},
+	}, {
+		name:    "handleGetBlocksByTime: count too high",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584247000,
+			Count:     dcrjson.Int(getBlocksByTimeMaxCount + 1),
+		},
+		mockChain: chain(blkHeight, blkHeight+1),
+		wantErr:   true,
+		errCode:   dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetBlocksByTime: end before start",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584247000,
+			EndTime:   1584246000,
+		},
+		mockChain: chain(blkHeight, blkHeight+1),
+		wantErr:   true,
+		errCode:   dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetBlocksByTime: unable to fetch hashes",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584247000,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain(blkHeight, blkHeight+1)
+			chain.heightRangeFn = func(_, _ int64) ([]chainhash.Hash, error) {
+				return nil, errors.New("invalid range")
+			}
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetBlocksByTime: unable to fetch median time",
+		handler: handleGetBlocksByTime,
+		cmd: &types.GetBlocksByTimeCmd{
+			StartTime: 1584246000,
+			EndTime:   1584247000,
+		},
+		mockChain: func() *testRPCChain {
+			chain := chain(blkHeight, blkHeight+1)
+			chain.medianTimeByHashErr = errors.New("unknown block")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetBlockSubsidy(t *testing.T) {
 	t.Parallel()
 
@@ -4251,6 +5178,102 @@ func TestHandleGetCFilterV2(t *testing.T) {
 	}})
 }
 
+func TestHandleGetCFiltersV2(t *testing.T) {
+	t.Parallel()
+
+	blkHashString := block432100.BlockHash().String()
+	blkHeight := int64(block432100.Header.Height)
+	filter := defaultMockFiltererV2().filterByBlockHash
+	headerBytes, err := block432100.Header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing header: %v", err)
+	}
+	result := types.GetCFiltersV2Result{
+		BlockHash:   blkHashString,
+		Height:      blkHeight,
+		Header:      hex.EncodeToString(headerBytes),
+		FilterHash:  filter.Hash().String(),
+		Data:        hex.EncodeToString(filter.Bytes()),
+		ProofIndex:  blockchain.HeaderCmtFilterIndex,
+		ProofHashes: nil,
+	}
+	resultNoData := result
+	resultNoData.Data = ""
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetCFiltersV2: ok",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight - 5,
+			Count:       dcrjson.Int(100),
+			IncludeData: dcrjson.Bool(true),
+		},
+		result: []types.GetCFiltersV2Result{result},
+	}, {
+		name:    "handleGetCFiltersV2: ok without data",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight,
+			Count:       dcrjson.Int(1),
+			IncludeData: dcrjson.Bool(false),
+		},
+		result: []types.GetCFiltersV2Result{resultNoData},
+	}, {
+		name:    "handleGetCFiltersV2: range before filters",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight - 5,
+			Count:       dcrjson.Int(5),
+			IncludeData: dcrjson.Bool(true),
+		},
+		result: []types.GetCFiltersV2Result{},
+	}, {
+		name:    "handleGetCFiltersV2: invalid count",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight,
+			Count:       dcrjson.Int(getCFiltersV2MaxCount + 1),
+			IncludeData: dcrjson.Bool(true),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleGetCFiltersV2: start height after tip",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight + 1,
+			Count:       dcrjson.Int(100),
+			IncludeData: dcrjson.Bool(true),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCOutOfRange,
+	}, {
+		name:    "handleGetCFiltersV2: negative start height",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: -1,
+			Count:       dcrjson.Int(100),
+			IncludeData: dcrjson.Bool(true),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCOutOfRange,
+	}, {
+		name:    "handleGetCFiltersV2: failed to load filters",
+		handler: handleGetCFiltersV2,
+		cmd: &types.GetCFiltersV2Cmd{
+			StartHeight: blkHeight,
+			Count:       dcrjson.Int(100),
+			IncludeData: dcrjson.Bool(true),
+		},
+		mockFiltererV2: func() *testFiltererV2 {
+			testFiltererV2 := defaultMockFiltererV2()
+			testFiltererV2.mainChainFiltersErr = blockchain.ErrNoFilter
+			return testFiltererV2
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetChainTips(t *testing.T) {
 	t.Parallel()
 
@@ -4414,6 +5437,211 @@ func TestHandleGetInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetMempoolEntry(t *testing.T) {
+	t.Parallel()
+
+	newDesc := func(expiry uint32, txType stake.TxType, fee int64) *mempool.TxDesc {
+		return &mempool.TxDesc{
+			TxDesc: mining.TxDesc{
+				Tx: dcrutil.NewTx(&wire.MsgTx{
+					Expiry: expiry,
+					TxIn:   []*wire.TxIn{},
+					TxOut:  []*wire.TxOut{},
+				}),
+				Type:   txType,
+				Added:  time.Unix(1600000000, 0),
+				Height: 432100,
+				Fee:    fee,
+			},
+		}
+	}
+	parent := newDesc(0, stake.TxTypeRegular, 10000)
+	child := newDesc(1, stake.TxTypeSStx, 20000)
+	grandchild := newDesc(2, stake.TxTypeRegular, 30000)
+	parentHash := parent.Tx.Hash()
+	childHash := child.Tx.Hash()
+	grandchildHash := grandchild.Tx.Hash()
+	size := int64(parent.Tx.MsgTx().SerializeSize())
+
+	mockTxMempooler := func() *testTxMempooler {
+		mp := defaultMockTxMempooler()
+		mp.verboseTxDescs = []*mempool.VerboseTxDesc{{
+			TxDesc:  *parent,
+			SpentBy: []*mempool.TxDesc{child},
+		}, {
+			TxDesc:  *child,
+			Depends: []*mempool.TxDesc{parent},
+			SpentBy: []*mempool.TxDesc{grandchild},
+		}, {
+			TxDesc:  *grandchild,
+			Depends: []*mempool.TxDesc{child},
+		}}
+		mp.ancestors = map[chainhash.Hash][]*mempool.TxDesc{
+			*parentHash:     nil,
+			*childHash:      {parent},
+			*grandchildHash: {child, parent},
+		}
+		mp.descendants = map[chainhash.Hash][]*mempool.TxDesc{
+			*parentHash:     {child, grandchild},
+			*childHash:      {grandchild},
+			*grandchildHash: nil,
+		}
+		return mp
+	}
+	parentResult := &types.GetMempoolEntryResult{
+		Size:            int32(size),
+		Fee:             0.0001,
+		Time:            1600000000,
+		Height:          432100,
+		Type:            "regular",
+		Depends:         []string{},
+		SpentBy:         []string{childHash.String()},
+		AncestorCount:   1,
+		AncestorSize:    size,
+		AncestorFees:    0.0001,
+		DescendantCount: 3,
+		DescendantSize:  3 * size,
+		DescendantFees:  0.0006,
+	}
+	childResult := &types.GetMempoolEntryResult{
+		Size:            int32(size),
+		Fee:             0.0002,
+		Time:            1600000000,
+		Height:          432100,
+		Type:            "ticket",
+		Depends:         []string{parentHash.String()},
+		SpentBy:         []string{grandchildHash.String()},
+		AncestorCount:   2,
+		AncestorSize:    2 * size,
+		AncestorFees:    0.0003,
+		DescendantCount: 2,
+		DescendantSize:  2 * size,
+		DescendantFees:  0.0005,
+	}
+	grandchildResult := &types.GetMempoolEntryResult{
+		Size:            int32(size),
+		Fee:             0.0003,
+		Time:            1600000000,
+		Height:          432100,
+		Type:            "regular",
+		Depends:         []string{childHash.String()},
+		SpentBy:         []string{},
+		AncestorCount:   3,
+		AncestorSize:    3 * size,
+		AncestorFees:    0.0006,
+		DescendantCount: 1,
+		DescendantSize:  size,
+		DescendantFees:  0.0003,
+	}
+	sortedHashes := func(hashes ...*chainhash.Hash) []string {
+		strs := make([]string, 0, len(hashes))
+		for _, hash := range hashes {
+			strs = append(strs, hash.String())
+		}
+		sort.Strings(strs)
+		return strs
+	}
+	notFoundHash := "0000000000000000000000000000000000000000000000000000000000000001"
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleGetMempoolEntry: ok",
+		handler:         handleGetMempoolEntry,
+		cmd:             &types.GetMempoolEntryCmd{TxHash: childHash.String()},
+		mockTxMempooler: mockTxMempooler(),
+		result:          childResult,
+	}, {
+		name:            "handleGetMempoolEntry: invalid hash",
+		handler:         handleGetMempoolEntry,
+		cmd:             &types.GetMempoolEntryCmd{TxHash: "invalid"},
+		mockTxMempooler: mockTxMempooler(),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:            "handleGetMempoolEntry: not in mempool",
+		handler:         handleGetMempoolEntry,
+		cmd:             &types.GetMempoolEntryCmd{TxHash: notFoundHash},
+		mockTxMempooler: mockTxMempooler(),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleGetMempoolAncestors: ok",
+		handler: handleGetMempoolAncestors,
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxHash:  grandchildHash.String(),
+			Verbose: dcrjson.Bool(false),
+		},
+		mockTxMempooler: mockTxMempooler(),
+		result:          sortedHashes(parentHash, childHash),
+	}, {
+		name:    "handleGetMempoolAncestors: ok no ancestors",
+		handler: handleGetMempoolAncestors,
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxHash: parentHash.String(),
+		},
+		mockTxMempooler: mockTxMempooler(),
+		result:          []string{},
+	}, {
+		name:    "handleGetMempoolAncestors: ok verbose",
+		handler: handleGetMempoolAncestors,
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxHash:  grandchildHash.String(),
+			Verbose: dcrjson.Bool(true),
+		},
+		mockTxMempooler: mockTxMempooler(),
+		result: map[string]*types.GetMempoolEntryResult{
+			parentHash.String(): parentResult,
+			childHash.String():  childResult,
+		},
+	}, {
+		name:    "handleGetMempoolAncestors: invalid hash",
+		handler: handleGetMempoolAncestors,
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxHash: "invalid",
+		},
+		mockTxMempooler: mockTxMempooler(),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetMempoolAncestors: not in mempool",
+		handler: handleGetMempoolAncestors,
+		cmd: &types.GetMempoolAncestorsCmd{
+			TxHash: notFoundHash,
+		},
+		mockTxMempooler: mockTxMempooler(),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleGetMempoolDescendants: ok",
+		handler: handleGetMempoolDescendants,
+		cmd: &types.GetMempoolDescendantsCmd{
+			TxHash:  parentHash.String(),
+			Verbose: dcrjson.Bool(false),
+		},
+		mockTxMempooler: mockTxMempooler(),
+		result:          sortedHashes(childHash, grandchildHash),
+	}, {
+		name:    "handleGetMempoolDescendants: ok verbose",
+		handler: handleGetMempoolDescendants,
+		cmd: &types.GetMempoolDescendantsCmd{
+			TxHash:  childHash.String(),
+			Verbose: dcrjson.Bool(true),
+		},
+		mockTxMempooler: mockTxMempooler(),
+		result: map[string]*types.GetMempoolEntryResult{
+			grandchildHash.String(): grandchildResult,
+		},
+	}, {
+		name:    "handleGetMempoolDescendants: not in mempool",
+		handler: handleGetMempoolDescendants,
+		cmd: &types.GetMempoolDescendantsCmd{
+			TxHash: notFoundHash,
+		},
+		mockTxMempooler: mockTxMempooler(),
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCNoTxInfo,
+	}})
+}
+
 func TestHandleGetMempoolInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4448,6 +5676,81 @@ func TestHandleGetMempoolInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetRejectedTransaction(t *testing.T) {
+	t.Parallel()
+
+	localTx := dcrutil.NewTx(block432100.Transactions[0])
+	peerTx := dcrutil.NewTx(block432100.Transactions[1])
+	rejectTime := time.Unix(1700000000, 0)
+	mockTxMempooler := func() *testTxMempooler {
+		mp := defaultMockTxMempooler()
+		mp.rejectedTxns = map[chainhash.Hash]*mempool.RejectedTx{
+			*localTx.Hash(): {
+				Hash:   *localTx.Hash(),
+				Time:   rejectTime,
+				Kind:   string(mempool.ErrInsufficientFee),
+				Reason: "insufficient fee",
+			},
+			*peerTx.Hash(): {
+				Hash:   *peerTx.Hash(),
+				Tag:    7,
+				Time:   rejectTime,
+				Kind:   string(mempool.ErrOrphan),
+				Reason: "orphan transaction",
+			},
+		}
+		return mp
+	}()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleGetRejectedTransaction: local",
+		handler:         handleGetRejectedTransaction,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRejectedTransactionCmd{
+			Txid: localTx.Hash().String(),
+		},
+		result: &types.GetRejectedTransactionResult{
+			Txid:   localTx.Hash().String(),
+			Time:   rejectTime.Unix(),
+			Source: "local",
+			Code:   "ErrInsufficientFee",
+			Reason: "insufficient fee",
+		},
+	}, {
+		name:            "handleGetRejectedTransaction: peer",
+		handler:         handleGetRejectedTransaction,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRejectedTransactionCmd{
+			Txid: peerTx.Hash().String(),
+		},
+		result: &types.GetRejectedTransactionResult{
+			Txid:   peerTx.Hash().String(),
+			Time:   rejectTime.Unix(),
+			Source: "peer",
+			PeerID: 7,
+			Code:   "ErrOrphan",
+			Reason: "orphan transaction",
+		},
+	}, {
+		name:            "handleGetRejectedTransaction: not rejected",
+		handler:         handleGetRejectedTransaction,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRejectedTransactionCmd{
+			Txid: (&chainhash.Hash{0x01}).String(),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleGetRejectedTransaction: invalid hash",
+		handler: handleGetRejectedTransaction,
+		cmd: &types.GetRejectedTransactionCmd{
+			Txid: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}})
+}
+
 func TestHandleGetMiningInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4487,7 +5790,14 @@ func TestHandleGetNetTotals(t *testing.T) {
 		result: &types.GetNetTotalsResult{
 			TotalBytesRecv: uint64(9598159),
 			TotalBytesSent: uint64(4783802),
-			TimeMillis:     int64(1592931302000),
+			BytesRecvPerMsg: map[string]uint64{
+				"block":   9598000,
+				"*other*": 159,
+			},
+			BytesSentPerMsg: map[string]uint64{
+				"getdata": 4783802,
+			},
+			TimeMillis: int64(1592931302000),
 		},
 	}})
 }
@@ -4621,6 +5931,116 @@ func TestHandleGetNetworkInfo(t *testing.T) {
 	}})
 }
 
+func TestHandleGetNodeStatus(t *testing.T) {
+	t.Parallel()
+
+	bestHeight := int64(block432100.Header.Height)
+	bestHash := block432100.Header.BlockHash()
+	syncMgr := func() *testSyncManager {
+		syncManager := defaultMockSyncManager()
+		syncManager.isCurrent = true
+		return syncManager
+	}
+	clock := &testClock{since: 90 * time.Second}
+	testRPCServerHandler(t, []rpcTest{{
+		name:            "handleGetNodeStatus: ready",
+		handler:         handleGetNodeStatus,
+		cmd:             &types.GetNodeStatusCmd{},
+		mockSyncManager: syncMgr(),
+		mockClock:       clock,
+		result: &types.GetNodeStatusResult{
+			Healthy:      true,
+			Ready:        true,
+			Reasons:      []string{},
+			Synced:       true,
+			Peers:        4,
+			BestHeight:   bestHeight,
+			BestHash:     bestHash.String(),
+			LastBlockAge: 90,
+			DatabaseOK:   true,
+			Indexes: []types.NodeIndexStatus{{
+				Name:     "testTxIndexer",
+				Height:   bestHeight,
+				Synced:   true,
+				Progress: 1,
+			}, {
+				Name:     "testExistsAddresser",
+				Height:   bestHeight,
+				Synced:   true,
+				Progress: 1,
+			}},
+		},
+	}, {
+		name:                  "handleGetNodeStatus: not ready",
+		handler:               handleGetNodeStatus,
+		cmd:                   &types.GetNodeStatusCmd{},
+		mockClock:             clock,
+		setExistsAddresserNil: true,
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.connectedCount = 0
+			return connManager
+		}(),
+		mockTxIndexer: func() *testTxIndexer {
+			txIndexer := defaultMockTxIndexer()
+			txIndexer.tipHeight = bestHeight / 4
+			txIndexer.tipHash = &chainhash.Hash{}
+			return txIndexer
+		}(),
+		result: &types.GetNodeStatusResult{
+			Healthy: true,
+			Ready:   false,
+			Reasons: []string{"chain not synced", "no connected peers",
+				"testTxIndexer not synced"},
+			Synced:       false,
+			Peers:        0,
+			BestHeight:   bestHeight,
+			BestHash:     bestHash.String(),
+			LastBlockAge: 90,
+			DatabaseOK:   true,
+			Indexes: []types.NodeIndexStatus{{
+				Name:     "testTxIndexer",
+				Height:   bestHeight / 4,
+				Synced:   false,
+				Progress: float64(bestHeight/4) / float64(bestHeight),
+			}},
+		},
+	}, {
+		name:            "handleGetNodeStatus: unhealthy",
+		handler:         handleGetNodeStatus,
+		cmd:             &types.GetNodeStatusCmd{},
+		mockSyncManager: syncMgr(),
+		mockClock:       clock,
+		setTxIndexerNil: true,
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewErr = errors.New("database is closed")
+			return db
+		}(),
+		mockExistsAddresser: func() *testExistsAddresser {
+			existsAddrIndex := defaultMockExistsAddresser()
+			existsAddrIndex.tipErr = errors.New("tip unavailable")
+			return existsAddrIndex
+		}(),
+		result: &types.GetNodeStatusResult{
+			Healthy: false,
+			Ready:   false,
+			Reasons: []string{"database unavailable",
+				"testExistsAddresser unavailable"},
+			Synced:       true,
+			Peers:        4,
+			BestHeight:   bestHeight,
+			BestHash:     bestHash.String(),
+			LastBlockAge: 90,
+			DatabaseOK:   false,
+			Indexes: []types.NodeIndexStatus{{
+				Name:     "testExistsAddresser",
+				Progress: 1,
+			}},
+		},
+	}})
+}
+
 func TestHandleGetPeerInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4659,6 +6079,28 @@ func TestHandleGetPeerInfo(t *testing.T) {
 						LastPingNonce:  uint64(10),
 						LastPingTime:   time.Unix(1592918788, 0),
 						LastPingMicros: int64(0),
+						PingStats: peer.PingStats{
+							NumPings: 2,
+							Min:      100 * time.Millisecond,
+							Max:      300 * time.Millisecond,
+							Total:    400 * time.Millisecond,
+						},
+						BytesSentPerMsg: map[string]uint64{
+							"version": 3382,
+							"verack":  24,
+						},
+						BytesRecvPerMsg: map[string]uint64{
+							"version": 2474,
+							"verack":  24,
+						},
+						MsgsSentPerMsg: map[string]uint64{
+							"version": 1,
+							"verack":  1,
+						},
+						MsgsRecvPerMsg: map[string]uint64{
+							"version": 1,
+							"verack":  1,
+						},
 					},
 				},
 			}
@@ -4688,10 +6130,161 @@ func TestHandleGetPeerInfo(t *testing.T) {
 			CurrentHeight:  int64(323327),
 			BanScore:       int32(0),
 			SyncNode:       false,
+			NumPings:       2,
+			MinPingTime:    float64(100000),
+			AvgPingTime:    float64(200000),
+			MaxPingTime:    float64(300000),
+			PingHistogram: []types.PeerPingBucket{
+				{UpperBound: 10000}, {UpperBound: 25000},
+				{UpperBound: 50000}, {UpperBound: 100000},
+				{UpperBound: 250000}, {UpperBound: 500000},
+				{UpperBound: 1000000}, {UpperBound: 2500000},
+				{UpperBound: 5000000}, {},
+			},
+			BytesSentPerMsg: map[string]uint64{
+				"version": 3382,
+				"verack":  24,
+			},
+			BytesRecvPerMsg: map[string]uint64{
+				"version": 2474,
+				"verack":  24,
+			},
+			MsgsSentPerMsg: map[string]uint64{
+				"version": 1,
+				"verack":  1,
+			},
+			MsgsRecvPerMsg: map[string]uint64{
+				"version": 1,
+				"verack":  1,
+			},
 		}},
 	}})
 }
 
+func TestHandleGetSpentInfo(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkHash := blk.Hash()
+	blkHeight := blk.Height()
+	spendTx := blk.MsgBlock().Transactions[1]
+	spendTxHash := spendTx.TxHash()
+	prevOut := &spendTx.TxIn[0].PreviousOutPoint
+	spentIndex := func() *testSpentIndexer {
+		idx := defaultMockSpentIndexer()
+		idx.entry = &indexers.SpentIndexEntry{
+			TxHash:      spendTxHash,
+			InputIndex:  0,
+			TxTree:      wire.TxTreeRegular,
+			BlockHeight: blkHeight,
+		}
+		return idx
+	}()
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetSpentInfo: ok",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		mockSpentIndexer: spentIndex,
+		result: &types.GetSpentInfoResult{
+			Txid:      spendTxHash.String(),
+			Vin:       0,
+			Tree:      wire.TxTreeRegular,
+			Height:    blkHeight,
+			BlockHash: blkHash.String(),
+		},
+	}, {
+		name:    "handleGetSpentInfo: spent output index not enabled",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpentInfo: invalid hash",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: "invalid",
+			Vout: 0,
+		},
+		mockSpentIndexer: spentIndex,
+		wantErr:          true,
+		errCode:          dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetSpentInfo: output not spent",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: spendTxHash.String(),
+			Vout: 0,
+		},
+		mockSpentIndexer: defaultMockSpentIndexer(),
+		wantErr:          true,
+		errCode:          dcrjson.ErrRPCNoTxInfo,
+	}, {
+		name:    "handleGetSpentInfo: index lookup error",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		mockSpentIndexer: func() *testSpentIndexer {
+			idx := defaultMockSpentIndexer()
+			idx.entryErr = errors.New("lookup failed")
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpentInfo: index is not synced",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		mockSpentIndexer: func() *testSpentIndexer {
+			idx := defaultMockSpentIndexer()
+			idx.tipHeight = blkHeight - 6
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpentInfo: index is not synced after syncWait",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		mockSpentIndexer: func() *testSpentIndexer {
+			idx := defaultMockSpentIndexer()
+			idx.tipHash = &zeroHash
+			idx.signalOnWait = false
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetSpentInfo: spending block no longer in main chain",
+		handler: handleGetSpentInfo,
+		cmd: &types.GetSpentInfoCmd{
+			Txid: prevOut.Hash.String(),
+			Vout: prevOut.Index,
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.blockHashByHeightErr = errors.New("no block at height")
+			return chain
+		}(),
+		mockSpentIndexer: spentIndex,
+		wantErr:          true,
+		errCode:          dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleGetStakeVersionInfo(t *testing.T) {
 	t.Parallel()
 
@@ -4870,6 +6463,92 @@ func TestHandleGetStakeVersions(t *testing.T) {
 	}})
 }
 
+func TestHandleGetSubmitBlockStatus(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetSubmitBlockStatus: invalid id",
+		handler: handleGetSubmitBlockStatus,
+		cmd: &types.GetSubmitBlockStatusCmd{
+			ID: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:    "handleGetSubmitBlockStatus: unknown submission",
+		handler: handleGetSubmitBlockStatus,
+		cmd: &types.GetSubmitBlockStatusCmd{
+			ID: block432100.Header.BlockHash().String(),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
+func TestHandleGetSyncStatus(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleGetSyncStatus: syncing blocks",
+		handler: handleGetSyncStatus,
+		cmd:     &types.GetSyncStatusCmd{},
+		mockSyncManager: func() *testSyncManager {
+			syncManager := defaultMockSyncManager()
+			syncManager.syncStatus = netsync.SyncStatus{
+				Stage:            netsync.SyncStageBlocks,
+				SyncPeerID:       3,
+				SyncHeight:       463074,
+				BestHeaderHeight: 463074,
+				HeaderProgress:   100,
+				BlocksDownloaded: 12000,
+				BlocksInFlight:   32,
+				BestHeight:       461074,
+				VerifyProgress:   99.57,
+				BlocksPerSecond:  200,
+				ETA:              10 * time.Second,
+			}
+			return syncManager
+		}(),
+		result: &types.GetSyncStatusResult{
+			Stage:            "blocks",
+			SyncPeerID:       3,
+			SyncHeight:       463074,
+			BestHeaderHeight: 463074,
+			HeaderProgress:   100,
+			BlocksDownloaded: 12000,
+			BlocksInFlight:   32,
+			BestHeight:       461074,
+			VerifyProgress:   99.57,
+			BlocksPerSecond:  200,
+			ETASeconds:       10,
+		},
+	}, {
+		name:    "handleGetSyncStatus: current",
+		handler: handleGetSyncStatus,
+		cmd:     &types.GetSyncStatusCmd{},
+		mockSyncManager: func() *testSyncManager {
+			syncManager := defaultMockSyncManager()
+			syncManager.syncStatus = netsync.SyncStatus{
+				Stage:            netsync.SyncStageCurrent,
+				SyncHeight:       463074,
+				BestHeaderHeight: 463074,
+				HeaderProgress:   100,
+				BestHeight:       463074,
+				VerifyProgress:   100,
+			}
+			return syncManager
+		}(),
+		result: &types.GetSyncStatusResult{
+			Stage:            "current",
+			SyncHeight:       463074,
+			BestHeaderHeight: 463074,
+			HeaderProgress:   100,
+			BestHeight:       463074,
+			VerifyProgress:   100,
+		},
+	}})
+}
+
 func TestHandleGetTicketPoolValue(t *testing.T) {
 	t.Parallel()
 
@@ -5181,6 +6860,7 @@ func TestHandleGetTxOutSetInfo(t *testing.T) {
 			Transactions:   689819,
 			TxOuts:         1593879,
 			SerializedHash: "fe7b32aa188800f07268b17f3bead5f3d8a1b6d18654182066436efce6effa86",
+			MuHash:         "2f5c7ab13b9a0de4f5e4c0c7f85e6c6f1a3d4b9e8c7f6a5b4c3d2e1f0a9b8c7d",
 			DiskSize:       36441617,
 			TotalAmount:    1154067750680149,
 		},
@@ -5234,6 +6914,38 @@ func TestHandleInvalidateBlock(t *testing.T) {
 	}})
 }
 
+func TestHandleListBanned(t *testing.T) {
+	t.Parallel()
+
+	_, subnet, _ := net.ParseCIDR("10.0.0.0/8")
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleListBanned: ok",
+		handler: handleListBanned,
+		cmd:     &types.ListBannedCmd{},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.bannedSubnets = []banmgr.Entry{{
+				Subnet:  subnet,
+				Created: time.Unix(1700000000, 0),
+				Expires: time.Unix(1700086400, 0),
+				Reason:  banmgr.ReasonManual,
+			}}
+			return connManager
+		}(),
+		result: []types.ListBannedResult{{
+			Address:     "10.0.0.0/8",
+			BanCreated:  1700000000,
+			BannedUntil: 1700086400,
+			BanReason:   "manual",
+		}},
+	}, {
+		name:    "handleListBanned: no bans",
+		handler: handleListBanned,
+		cmd:     &types.ListBannedCmd{},
+		result:  []types.ListBannedResult{},
+	}})
+}
+
 func TestHandleLiveTickets(t *testing.T) {
 	t.Parallel()
 
@@ -5259,6 +6971,34 @@ func TestHandleLiveTickets(t *testing.T) {
 		result: types.LiveTicketsResult{
 			Tickets: []string{tkt1.String(), tkt2.String()},
 		},
+	}, {
+		name:    "handleLiveTickets: first page",
+		handler: handleLiveTickets,
+		cmd:     &types.LiveTicketsCmd{Count: dcrjson.Int(1)},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.liveTickets = []chainhash.Hash{*tkt2, *tkt1}
+			return chain
+		}(),
+		result: types.LiveTicketsResult{
+			Tickets:    []string{tkt1.String()},
+			NextCursor: tkt1.String(),
+		},
+	}, {
+		name:    "handleLiveTickets: last page",
+		handler: handleLiveTickets,
+		cmd: &types.LiveTicketsCmd{
+			Count:  dcrjson.Int(1),
+			Cursor: dcrjson.String(tkt1.String()),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.liveTickets = []chainhash.Hash{*tkt2, *tkt1}
+			return chain
+		}(),
+		result: types.LiveTicketsResult{
+			Tickets: []string{tkt2.String()},
+		},
 	}, {
 		name:    "handleLiveTickets: unable to fetch live tickets",
 		handler: handleLiveTickets,
@@ -5462,6 +7202,43 @@ func TestHandlePing(t *testing.T) {
 	}})
 }
 
+func TestHandlePrioritiseTransaction(t *testing.T) {
+	t.Parallel()
+
+	txHash := chainhash.Hash{0x01}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handlePrioritiseTransaction: ok",
+		handler: handlePrioritiseTransaction,
+		cmd: &types.PrioritiseTransactionCmd{
+			Txid:     txHash.String(),
+			FeeDelta: 10000,
+		},
+		result: int64(10000),
+	}, {
+		name:    "handlePrioritiseTransaction: existing delta",
+		handler: handlePrioritiseTransaction,
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.feeDeltas = map[chainhash.Hash]int64{txHash: 10000}
+			return mp
+		}(),
+		cmd: &types.PrioritiseTransactionCmd{
+			Txid:     txHash.String(),
+			FeeDelta: -4000,
+		},
+		result: int64(6000),
+	}, {
+		name:    "handlePrioritiseTransaction: invalid hash",
+		handler: handlePrioritiseTransaction,
+		cmd: &types.PrioritiseTransactionCmd{
+			Txid:     "invalid",
+			FeeDelta: 10000,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}})
+}
+
 // testTx holds test transaction info and is used for mocking transaction
 // details.
 type testTx struct {
@@ -5523,6 +7300,41 @@ func TestHandleSubmitBlock(t *testing.T) {
 	}})
 }
 
+func TestHandleSubmitBlockNoWait(t *testing.T) {
+	t.Parallel()
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("error serializing block: %+v", err)
+	}
+	blkHexString := hex.EncodeToString(blkBytes)
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSubmitBlockNoWait: ok",
+		handler: handleSubmitBlockNoWait,
+		cmd: &types.SubmitBlockNoWaitCmd{
+			HexBlock: blkHexString,
+		},
+		result: blk.Hash().String(),
+	}, {
+		name:    "handleSubmitBlockNoWait: invalid hex",
+		handler: handleSubmitBlockNoWait,
+		cmd: &types.SubmitBlockNoWaitCmd{
+			HexBlock: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSubmitBlockNoWait: block decode error",
+		handler: handleSubmitBlockNoWait,
+		cmd: &types.SubmitBlockNoWaitCmd{
+			HexBlock: "ffffffff",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func TestHandleValidateAddress(t *testing.T) {
 	t.Parallel()
 
@@ -5662,6 +7474,9 @@ func TestHandleGetWork(t *testing.T) {
 		return ms
 	}
 
+	const longPollID = "b2716beec877ecee3b18917ebdcfb9e8e7a550ab5cb28278a8f1" +
+		"0c9f8f6f8b07"
+
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleGetWork: CPU IsMining enabled",
 		handler: handleGetWork,
@@ -5726,6 +7541,7 @@ func TestHandleGetWork(t *testing.T) {
 				"000000070000008000000100000000000005a0",
 			Target: "000000000000000000000000000000000000000000e20f27000000" +
 				"0000000000",
+			LongPollID: longPollID,
 		},
 	}, {
 		name:            "handleGetWork: ok with no workstate entries",
@@ -5742,6 +7558,26 @@ func TestHandleGetWork(t *testing.T) {
 				"000000070000008000000100000000000005a0",
 			Target: "000000000000000000000000000000000000000000e20f27000000" +
 				"0000000000",
+			LongPollID: longPollID,
+		},
+	}, {
+		name:    "handleGetWork: ok with outdated long poll id",
+		handler: handleGetWork,
+		cmd: &types.GetWorkCmd{
+			LongPollID: dcrjson.String("00"),
+		},
+		mockMiningState: mine(),
+		result: &types.GetWorkResult{
+			Data: "070000009c3c0efea268c124d46d7daeae2d9667e78daa0523a19725" +
+				"00000000000000000bc8a255edde9901ecc4cdb93e4e573cb38ae91e84" +
+				"495ecddc0c93c019351d5d7731998be0a78e955f6fb98d2f35479905c3" +
+				"279f6257beab42a51d556cef55b9010087ba86bb2e5204000100b1a000" +
+				"00e20f27181e4afc5b03000000e4970600de0a0000d2b46d5ef63e4a6d" +
+				"d6ab3b0000000000a200ca770000000000000000000000000000000000" +
+				"000000070000008000000100000000000005a0",
+			Target: "000000000000000000000000000000000000000000e20f27000000" +
+				"0000000000",
+			LongPollID: longPollID,
 		},
 	}, {
 		name:            "handleGetWork: unable to retrieve template",
@@ -5880,6 +7716,137 @@ func TestHandleGetWork(t *testing.T) {
 	}})
 }
 
+// TestWaitForWorkLongPoll ensures waiting for work with a long poll identifier
+// returns as soon as the block template differs from the one it identifies and
+// otherwise waits until the context is canceled.
+func TestWaitForWorkLongPoll(t *testing.T) {
+	t.Parallel()
+
+	// Ensure waiting with an identifier for a different template returns
+	// immediately.
+	templater := defaultMockBlockTemplater()
+	ctx := context.Background()
+	if err := waitForWorkLongPoll(ctx, templater, "00"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure waiting with the identifier of the current template does not
+	// return until the context is canceled.
+	templater = defaultMockBlockTemplater()
+	templater.simulateNewNtfn = true
+	longPollID := getWorkLongPollID(&templater.currTemplate.Block.Header)
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+	defer cancel()
+	err := waitForWorkLongPoll(ctx, templater, longPollID)
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCMisc {
+		t.Fatalf("unexpected error -- got %v, want connection closed", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("returned before the context was canceled")
+	}
+}
+
+func TestHandleSetBan(t *testing.T) {
+	t.Parallel()
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleSetBan: ok add address",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBAdd,
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: ok add subnet with absolute time",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:   "10.0.0.0/8",
+			SubCmd:   types.SBAdd,
+			BanTime:  dcrjson.Int64(1700000000),
+			Absolute: dcrjson.Bool(true),
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: absolute time in the past",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:   "10.0.0.0/8",
+			SubCmd:   types.SBAdd,
+			BanTime:  dcrjson.Int64(1700000000),
+			Absolute: dcrjson.Bool(true),
+		},
+		mockClock: &testClock{now: time.Unix(1700000001, 0)},
+		wantErr:   true,
+		errCode:   dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: negative ban time",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet:  "10.0.0.1",
+			SubCmd:  types.SBAdd,
+			BanTime: dcrjson.Int64(-1),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: invalid subnet",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.0/33",
+			SubCmd: types.SBAdd,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: failed to persist",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBAdd,
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.banErr = errors.New("disk full")
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCMisc,
+	}, {
+		name:    "handleSetBan: ok remove",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBRemove,
+		},
+		result: nil,
+	}, {
+		name:    "handleSetBan: remove not banned",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: types.SBRemove,
+		},
+		mockConnManager: func() *testConnManager {
+			connManager := defaultMockConnManager()
+			connManager.unbanErr = banmgr.ErrNotBanned
+			return connManager
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSetBan: invalid subcommand",
+		handler: handleSetBan,
+		cmd: &types.SetBanCmd{
+			Subnet: "10.0.0.1",
+			SubCmd: "",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}})
+}
+
 func TestHandleSetGenerate(t *testing.T) {
 	t.Parallel()
 
@@ -6632,89 +8599,580 @@ func TestHandleTicketFeeInfo(t *testing.T) {
 	}})
 }
 
-func TestHandleVerifyMessage(t *testing.T) {
+func TestHandleVerifyMessage(t *testing.T) {
+	t.Parallel()
+
+	// private key : 0x0000000000000000000000000000000000000000000000000000000000000001
+	msg := "test message"
+	p2shAddr := "DcXTb4QtmnyRsnzUVViYQawqFE5PuYTdX2C"
+	compressedPKHAddr := "DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"
+	compressedCompactSig := "H18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiSRW" +
+		"dE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
+	uncompressedPKHAddr := "DsbnCMAYV13buumdjHuwiJeJWZWvgjZRTbE"
+	uncompressedCompactSig := "G18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiS" +
+		"RWdE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
+	malformedCompactSig := "MEUCIQD8wKh2jPvO/PLK3Xz7D7GO0I3S4q6EvvGkdKPTUbJ" +
+		"vAwIgD8GjULRfRvoAEBORMCgY10jCsiYVURo//Vu2OL13cgc="
+	invalidCompactSig := "II57fsP8WEHAwfrSlx3u3wu4PHqTnP1fk/r0LM9dzm0lYr6GA" +
+		"D+HAFIHWUTAN623ONsG+yq6onSbZvu5vW8YI/0=" // sig for message "test"
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleVerifyMessage: invalid address",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   "invalid",
+			Message:   msg,
+			Signature: compressedCompactSig,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleVerifyMessage: address is P2SH",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   p2shAddr,
+			Message:   msg,
+			Signature: compressedCompactSig,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCType,
+	}, {
+		name:    "handleVerifyMessage: invalid signature",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   compressedPKHAddr,
+			Message:   msg,
+			Signature: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCParse.Code,
+	}, {
+		name:    "handleVerifyMessage: address is uncompressed PKH",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   uncompressedPKHAddr,
+			Message:   msg,
+			Signature: uncompressedCompactSig,
+		},
+		result: true,
+	}, {
+		name:    "handleVerifyMessage: size is greater than the compact sig size",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   compressedPKHAddr,
+			Message:   msg,
+			Signature: malformedCompactSig,
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: invalid signature for message",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   compressedPKHAddr,
+			Message:   msg,
+			Signature: invalidCompactSig,
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyMessage: ok",
+		handler: handleVerifyMessage,
+		cmd: &types.VerifyMessageCmd{
+			Address:   compressedPKHAddr,
+			Message:   msg,
+			Signature: compressedCompactSig,
+		},
+		result: true,
+	}})
+}
+
+func TestHandleVerifyOwnershipProof(t *testing.T) {
+	t.Parallel()
+
+	// p2pkhAddr is for private key 0x01 and p2shAddr is for a 2-of-2
+	// multisig redeem script of the public keys for private keys 0x01 and
+	// 0x02.
+	msg := "test message"
+	p2pkhAddr := "DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"
+	p2pkhProof := "AWtIMEUCIQC0kXHXaU9Rz4KmfdO8MYWsTRRPqMtAWRtV7cHa2S61wQIgKI" +
+		"hVvhGTUMcfG7/pym9ScfBbAWcgRJpG8Zxksd/zQPQBIQJ5vmZ++dy7rFWgYpXOhwsHAp" +
+		"v82y3OKNlZ8oFbFvgXmA=="
+	p2shAddr := "DckeCpH1pUrpZqERFynfefZLcKGxSWX8hFh"
+	p2shProof := "AdlHMEQCIFU+svvxWxrU1xqkIyEm6AxZFwJYWyZxLDrvMoxSAkMGAiB+gJ" +
+		"omJnDY2WGQWuosBLjjWO7btSR/47PB0Rqa2Og7rAFIMEUCIQD5vaJGTXWJHf4rpwsvUK" +
+		"brCRQ7gN7cMIg9FLmQnUFn+wIgHUWHXMst8Ck4fG5p2Q4cGHJD4kmR0mKP6lXH1j1mV+" +
+		"MBR1IhAnm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeYIQLGBH+UQe19bTBFQG" +
+		"6VwHzYXHeOS4zvPKerrAm5XHCe5VKu"
+	unsupportedVersionProof := "AgA="
+
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleVerifyOwnershipProof: invalid address",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: "invalid",
+			Message: msg,
+			Proof:   p2pkhProof,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+	}, {
+		name:    "handleVerifyOwnershipProof: invalid base64",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2pkhAddr,
+			Message: msg,
+			Proof:   "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCParse.Code,
+	}, {
+		name:    "handleVerifyOwnershipProof: unsupported proof version",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2pkhAddr,
+			Message: msg,
+			Proof:   unsupportedVersionProof,
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDeserialization,
+	}, {
+		name:    "handleVerifyOwnershipProof: invalid proof for message",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2pkhAddr,
+			Message: "test",
+			Proof:   p2pkhProof,
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyOwnershipProof: proof for other address",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2shAddr,
+			Message: msg,
+			Proof:   p2pkhProof,
+		},
+		result: false,
+	}, {
+		name:    "handleVerifyOwnershipProof: ok P2PKH",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2pkhAddr,
+			Message: msg,
+			Proof:   p2pkhProof,
+		},
+		result: true,
+	}, {
+		name:    "handleVerifyOwnershipProof: ok P2SH multisig",
+		handler: handleVerifyOwnershipProof,
+		cmd: &types.VerifyOwnershipProofCmd{
+			Address: p2shAddr,
+			Message: msg,
+			Proof:   p2shProof,
+		},
+		result: true,
+	}})
+}
+
+func TestHandleScanTxOutSet(t *testing.T) {
+	t.Parallel()
+
+	blkHeight := int64(block432100.Header.Height)
+	blkHash := block432100.BlockHash()
+	p2pkhAddr := "DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"
+	p2pkhScript := hexToBytes("76a914762432e9619f5ddaf122ac663684152ffe9eb0ec88ac")
+	rawScript := hexToBytes("a914780239ea1231ba67b0c5b82e786b51e2107252218787")
+	otherScript := hexToBytes("76a914000000000000000000000000000000000000000088ac")
+	hash1 := mustParseHash("1000000000000000000000000000000000000000000000000000000000000000")
+	hash2 := mustParseHash("8000000000000000000000000000000000000000000000000000000000000000")
+	hash3 := mustParseHash("f000000000000000000000000000000000000000000000000000000000000000")
+	utxos := []struct {
+		outpoint wire.OutPoint
+		entry    *testRPCUtxoEntry
+	}{{
+		outpoint: wire.OutPoint{Hash: *hash1, Index: 1},
+		entry: &testRPCUtxoEntry{
+			amount:     100000000,
+			height:     1000,
+			isCoinBase: true,
+			pkScript:   p2pkhScript,
+		},
+	}, {
+		outpoint: wire.OutPoint{Hash: *hash2, Index: 0},
+		entry: &testRPCUtxoEntry{
+			amount:   50000000,
+			height:   2000,
+			pkScript: otherScript,
+		},
+	}, {
+		outpoint: wire.OutPoint{Hash: *hash3, Index: 2, Tree: 1},
+		entry: &testRPCUtxoEntry{
+			amount:   25000000,
+			height:   3000,
+			pkScript: rawScript,
+		},
+	}}
+	mockChain := func() *testRPCChain {
+		chain := defaultMockRPCChain()
+		chain.forEachUtxoFn = func(fn func(wire.OutPoint, UtxoEntry) error) error {
+			for _, utxo := range utxos {
+				if err := fn(utxo.outpoint, utxo.entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return chain
+	}
+	scanObjects := func(objs ...string) *[]string {
+		return &objs
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleScanTxOutSet: start ok",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action: "start",
+			ScanObjects: scanObjects("addr("+p2pkhAddr+")",
+				"raw(A914780239EA1231BA67B0C5B82E786B51E2107252218787)"),
+		},
+		mockChain: mockChain(),
+		result: &types.ScanTxOutSetResult{
+			Success:       true,
+			SearchedItems: 3,
+			Height:        blkHeight,
+			BestBlock:     blkHash.String(),
+			Unspents: []types.ScanTxOutSetUnspent{{
+				Txid:         hash1.String(),
+				Vout:         1,
+				ScriptPubKey: hex.EncodeToString(p2pkhScript),
+				Desc:         "addr(" + p2pkhAddr + ")",
+				Amount:       1,
+				Height:       1000,
+				Coinbase:     true,
+			}, {
+				Txid:         hash3.String(),
+				Vout:         2,
+				Tree:         1,
+				ScriptPubKey: hex.EncodeToString(rawScript),
+				Desc:         "raw(" + hex.EncodeToString(rawScript) + ")",
+				Amount:       0.25,
+				Height:       3000,
+			}},
+			TotalAmount: 1.25,
+		},
+	}, {
+		name:    "handleScanTxOutSet: start ok with plain address and no matches",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action:      "start",
+			ScanObjects: scanObjects("DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"),
+		},
+		mockChain: mockChain(),
+		result: &types.ScanTxOutSetResult{
+			Success:       true,
+			SearchedItems: 3,
+			Height:        blkHeight,
+			BestBlock:     blkHash.String(),
+			Unspents:      []types.ScanTxOutSetUnspent{},
+		},
+	}, {
+		name:    "handleScanTxOutSet: invalid action",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action: "invalid",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleScanTxOutSet: start without scan objects",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action: "start",
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleScanTxOutSet: invalid address",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action:      "start",
+			ScanObjects: scanObjects("addr(invalid)"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleScanTxOutSet: invalid raw script",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action:      "start",
+			ScanObjects: scanObjects("raw(zz)"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleScanTxOutSet: unable to iterate utxo set",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action:      "start",
+			ScanObjects: scanObjects(p2pkhAddr),
+		},
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.forEachUtxoFn = func(func(wire.OutPoint, UtxoEntry) error) error {
+				return errors.New("unable to iterate utxo set")
+			}
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleScanTxOutSet: status without scan in progress",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action: "status",
+		},
+		result: nil,
+	}, {
+		name:    "handleScanTxOutSet: abort without scan in progress",
+		handler: handleScanTxOutSet,
+		cmd: &types.ScanTxOutSetCmd{
+			Action: "abort",
+		},
+		result: false,
+	}})
+}
+
+// TestScanTxOutSetInProgress ensures the status and abort actions of the
+// scantxoutset command report and abort a scan that is in progress and that
+// additional scans are rejected while one is in progress.
+func TestScanTxOutSetInProgress(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultMockConfig(defaultChainParams)
+	s := &Server{cfg: *cfg}
+	chain := cfg.Chain.(*testRPCChain)
+
+	scanObjects := []string{"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"}
+	startCmd := types.NewScanTxOutSetCmd("start", &scanObjects)
+	statusCmd := types.NewScanTxOutSetCmd("status", nil)
+	abortCmd := types.NewScanTxOutSetCmd("abort", nil)
+	ctx := context.Background()
+
+	// Scan a utxo set with two entries where the actions are issued while
+	// the first one is being scanned.
+	var hash chainhash.Hash
+	hash[0] = 0x80
+	var statusResult, abortResult, startErr interface{}
+	chain.forEachUtxoFn = func(fn func(wire.OutPoint, UtxoEntry) error) error {
+		entry := &testRPCUtxoEntry{pkScript: []byte{0x51}}
+		if err := fn(wire.OutPoint{Hash: hash}, entry); err != nil {
+			return err
+		}
+		statusResult, _ = handleScanTxOutSet(ctx, s, statusCmd)
+		_, startErr = handleScanTxOutSet(ctx, s, startCmd)
+		abortResult, _ = handleScanTxOutSet(ctx, s, abortCmd)
+		return fn(wire.OutPoint{Hash: hash, Index: 1}, entry)
+	}
+	result, err := handleScanTxOutSet(ctx, s, startCmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	status, ok := statusResult.(*types.ScanTxOutSetStatusResult)
+	if !ok || status.Progress != 50 {
+		t.Fatalf("unexpected status result: %v", statusResult)
+	}
+	var rpcErr *dcrjson.RPCError
+	if err, _ := startErr.(error); !errors.As(err, &rpcErr) ||
+		rpcErr.Code != dcrjson.ErrRPCMisc {
+
+		t.Fatalf("unexpected error for concurrent scan: %v", startErr)
+	}
+	if abortResult != true {
+		t.Fatalf("unexpected abort result: %v", abortResult)
+	}
+	scanResult := result.(*types.ScanTxOutSetResult)
+	if scanResult.Success || scanResult.SearchedItems != 1 {
+		t.Fatalf("unexpected result for aborted scan: %+v", scanResult)
+	}
+
+	// Ensure the scan is no longer reported once it is complete.
+	result, err = handleScanTxOutSet(ctx, s, statusCmd)
+	if err != nil || result != nil {
+		t.Fatalf("unexpected status after scan: %v (%v)", result, err)
+	}
+}
+
+func TestHandleSearchRawTransactions(t *testing.T) {
 	t.Parallel()
 
-	// private key : 0x0000000000000000000000000000000000000000000000000000000000000001
-	msg := "test message"
-	p2shAddr := "DcXTb4QtmnyRsnzUVViYQawqFE5PuYTdX2C"
-	compressedPKHAddr := "DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"
-	compressedCompactSig := "H18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiSRW" +
-		"dE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
-	uncompressedPKHAddr := "DsbnCMAYV13buumdjHuwiJeJWZWvgjZRTbE"
-	uncompressedCompactSig := "G18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiS" +
-		"RWdE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8="
-	malformedCompactSig := "MEUCIQD8wKh2jPvO/PLK3Xz7D7GO0I3S4q6EvvGkdKPTUbJ" +
-		"vAwIgD8GjULRfRvoAEBORMCgY10jCsiYVURo//Vu2OL13cgc="
-	invalidCompactSig := "II57fsP8WEHAwfrSlx3u3wu4PHqTnP1fk/r0LM9dzm0lYr6GA" +
-		"D+HAFIHWUTAN623ONsG+yq6onSbZvu5vW8YI/0=" // sig for message "test"
+	validAddr := "DcurAwesomeAddressmqDctW5wJCW1Cn2MF"
+	blk := dcrutil.NewBlock(&block432100)
+	blkHeight := blk.Height()
+	tx := blk.MsgBlock().Transactions[0]
+	txHash := tx.TxHash()
+	idxTxns := []indexers.AddrIndexTx{{
+		TxHash:      txHash,
+		BlockHeight: blkHeight,
+		TxTree:      wire.TxTreeRegular,
+		TxIndex:     0,
+		Entries: []indexers.AddrIndexEntry{{
+			IsInput: false,
+			Index:   2,
+			Amount:  tx.TxOut[2].Value,
+		}},
+	}}
+	addrIndex := func() *testAddrIndexer {
+		idx := defaultMockAddrIndexer()
+		idx.txns = idxTxns
+		return idx
+	}()
+
+	// Create the expected verbose result using the same mocked chain.
+	s := &Server{cfg: *defaultMockConfig(defaultChainParams)}
+	header := &blk.MsgBlock().Header
+	isTreasuryEnabled, err := s.isTreasuryAgendaActive(&header.PrevBlock)
+	if err != nil {
+		t.Fatalf("unable to determine treasury status: %v", err)
+	}
+	rawTxn, err := s.createTxRawResult(defaultChainParams, tx, txHash.String(),
+		0, header, blk.Hash().String(), blkHeight, 1, isTreasuryEnabled)
+	if err != nil {
+		t.Fatalf("unable to create raw tx result: %v", err)
+	}
+	txHex, err := s.messageToHex(tx)
+	if err != nil {
+		t.Fatalf("unable to serialize tx: %v", err)
+	}
 
 	testRPCServerHandler(t, []rpcTest{{
-		name:    "handleVerifyMessage: invalid address",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   "invalid",
-			Message:   msg,
-			Signature: compressedCompactSig,
+		name:    "handleSearchRawTransactions: ok, verbose",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
 		},
-		wantErr: true,
-		errCode: dcrjson.ErrRPCInvalidAddressOrKey,
+		mockAddrIndexer: addrIndex,
+		result: []types.SearchRawTransactionsResult{{
+			Tx:   *rawTxn,
+			Tree: wire.TxTreeRegular,
+			Entries: []types.SearchRawTransactionsEntry{{
+				IsInput: false,
+				Index:   2,
+				Amount:  dcrutil.Amount(tx.TxOut[2].Value).ToCoin(),
+			}},
+		}},
 	}, {
-		name:    "handleVerifyMessage: address is P2SH",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   p2shAddr,
-			Message:   msg,
-			Signature: compressedCompactSig,
+		name:    "handleSearchRawTransactions: ok, not verbose",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Verbose: dcrjson.Int(0),
 		},
-		wantErr: true,
-		errCode: dcrjson.ErrRPCType,
+		mockAddrIndexer: addrIndex,
+		result:          []string{txHex},
 	}, {
-		name:    "handleVerifyMessage: invalid signature",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   compressedPKHAddr,
-			Message:   msg,
-			Signature: "invalid",
+		name:    "handleSearchRawTransactions: ok, no transactions",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Verbose: dcrjson.Int(0),
+		},
+		mockAddrIndexer: defaultMockAddrIndexer(),
+		result:          []string{},
+	}, {
+		name:    "handleSearchRawTransactions: address index not enabled",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
 		},
 		wantErr: true,
-		errCode: dcrjson.ErrRPCParse.Code,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}, {
-		name:    "handleVerifyMessage: address is uncompressed PKH",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   uncompressedPKHAddr,
-			Message:   msg,
-			Signature: uncompressedCompactSig,
+		name:    "handleSearchRawTransactions: bad address",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: "bad address",
 		},
-		result: true,
+		mockAddrIndexer: addrIndex,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidAddressOrKey,
 	}, {
-		name:    "handleVerifyMessage: size is greater than the compact sig size",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   compressedPKHAddr,
-			Message:   msg,
-			Signature: malformedCompactSig,
+		name:    "handleSearchRawTransactions: negative skip",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Skip:    dcrjson.Int(-1),
 		},
-		result: false,
+		mockAddrIndexer: addrIndex,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
 	}, {
-		name:    "handleVerifyMessage: invalid signature for message",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   compressedPKHAddr,
-			Message:   msg,
-			Signature: invalidCompactSig,
+		name:    "handleSearchRawTransactions: count too high",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+			Count:   dcrjson.Int(searchRawTransactionsMaxCount + 1),
 		},
-		result: false,
+		mockAddrIndexer: addrIndex,
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
 	}, {
-		name:    "handleVerifyMessage: ok",
-		handler: handleVerifyMessage,
-		cmd: &types.VerifyMessageCmd{
-			Address:   compressedPKHAddr,
-			Message:   msg,
-			Signature: compressedCompactSig,
+		name:    "handleSearchRawTransactions: index is not synced",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
 		},
-		result: true,
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipHeight = blkHeight - 6
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: index is not synced after syncWait",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.tipHash = &zeroHash
+			idx.signalOnWait = false
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleSearchRawTransactions: unsupported address type",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.txnsErr = indexers.ErrUnsupportedAddressType
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "handleSearchRawTransactions: stale index entry",
+		handler: handleSearchRawTransactions,
+		cmd: &types.SearchRawTransactionsCmd{
+			Address: validAddr,
+		},
+		mockAddrIndexer: func() *testAddrIndexer {
+			idx := defaultMockAddrIndexer()
+			idx.txns = []indexers.AddrIndexTx{{
+				TxHash:      zeroHash,
+				BlockHeight: blkHeight,
+				TxTree:      wire.TxTreeRegular,
+				TxIndex:     0,
+			}}
+			return idx
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
 	}})
 }
 
@@ -7182,6 +9640,17 @@ func TestHandleGetRawMempool(t *testing.T) {
 		Time:    time.Time{}.Unix(),
 		Depends: []string{regularHash},
 	}
+	sortedHashes := []string{regularHash, ticketHash, voteHash,
+		revocationHash, tSpendHash, tAddHash}
+	sort.Strings(sortedHashes)
+	verboseResults := map[string]*types.GetRawMempoolVerboseResult{
+		regularHash:    getRawMempoolVerboseResult,
+		ticketHash:     getRawMempoolVerboseTicketResult,
+		voteHash:       getRawMempoolVerboseResult,
+		revocationHash: getRawMempoolVerboseResult,
+		tSpendHash:     getRawMempoolVerboseResult,
+		tAddHash:       getRawMempoolVerboseResult,
+	}
 
 	testRPCServerHandler(t, []rpcTest{{
 		name:            "handleGetRawMempool: ok all",
@@ -7233,14 +9702,7 @@ func TestHandleGetRawMempool(t *testing.T) {
 		cmd: &types.GetRawMempoolCmd{
 			Verbose: dcrjson.Bool(true),
 		},
-		result: map[string]*types.GetRawMempoolVerboseResult{
-			regularHash:    getRawMempoolVerboseResult,
-			ticketHash:     getRawMempoolVerboseTicketResult,
-			voteHash:       getRawMempoolVerboseResult,
-			revocationHash: getRawMempoolVerboseResult,
-			tSpendHash:     getRawMempoolVerboseResult,
-			tAddHash:       getRawMempoolVerboseResult,
-		},
+		result: verboseResults,
 	}, {
 		name:            "handleGetRawMempool: ok verbose regular",
 		handler:         handleGetRawMempool,
@@ -7259,6 +9721,58 @@ func TestHandleGetRawMempool(t *testing.T) {
 		cmd:             &types.GetRawMempoolCmd{TxType: dcrjson.String("not a type")},
 		wantErr:         true,
 		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:            "handleGetRawMempool: ok first page",
+		handler:         handleGetRawMempool,
+		mockTxMempooler: mockTxMempooler,
+		cmd:             &types.GetRawMempoolCmd{Count: dcrjson.Int(4)},
+		result: &types.GetRawMempoolPageResult{
+			TxHashes:   sortedHashes[:4],
+			NextCursor: sortedHashes[3],
+		},
+	}, {
+		name:            "handleGetRawMempool: ok last page",
+		handler:         handleGetRawMempool,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRawMempoolCmd{
+			Count:  dcrjson.Int(4),
+			Cursor: dcrjson.String(sortedHashes[3]),
+		},
+		result: &types.GetRawMempoolPageResult{
+			TxHashes: sortedHashes[4:],
+		},
+	}, {
+		name:            "handleGetRawMempool: ok verbose page",
+		handler:         handleGetRawMempool,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRawMempoolCmd{
+			Verbose: dcrjson.Bool(true),
+			Count:   dcrjson.Int(1),
+			Cursor:  dcrjson.String(sortedHashes[0]),
+		},
+		result: &types.GetRawMempoolVerbosePageResult{
+			Transactions: map[string]*types.GetRawMempoolVerboseResult{
+				sortedHashes[1]: verboseResults[sortedHashes[1]],
+			},
+			NextCursor: sortedHashes[1],
+		},
+	}, {
+		name:            "handleGetRawMempool: invalid count",
+		handler:         handleGetRawMempool,
+		mockTxMempooler: mockTxMempooler,
+		cmd:             &types.GetRawMempoolCmd{Count: dcrjson.Int(0)},
+		wantErr:         true,
+		errCode:         dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:            "handleGetRawMempool: invalid cursor",
+		handler:         handleGetRawMempool,
+		mockTxMempooler: mockTxMempooler,
+		cmd: &types.GetRawMempoolCmd{
+			Count:  dcrjson.Int(1),
+			Cursor: dcrjson.String("not a hash"),
+		},
+		wantErr: true,
+		errCode: dcrjson.ErrRPCDecodeHexString,
 	}})
 }
 
@@ -7267,6 +9781,7 @@ func TestHandleGetRawTransaction(t *testing.T) {
 
 	nonVerboseTx := 0
 	verboseTx := 1
+	verbosePrevOutTx := 2
 	txid := "c720b8991e3345e13858607cdbbaf8fc535a15cd36f22d42623dba56586c94d5"
 	nonVerboseResult := "0100000002b761292042421b09196a2a9cdf56001a95df8c" +
 		"508dacf1170bba8b0c813fc8210300000001ffffffffdca0b996c9078ed14749" +
@@ -7423,6 +9938,27 @@ func TestHandleGetRawTransaction(t *testing.T) {
 		return idx
 	}()
 
+	// Create the results that include the previous outputs.  The mocked chain
+	// returns the same previous output for all inputs.
+	prevOutScript := hexToBytes("76a914762432e9619f5ddaf122ac663684152ffe9eb0ec88ac")
+	spentOutputs := make(map[wire.OutPoint]blockchain.SpentOutput)
+	for _, txIn := range tx.TxIn {
+		spentOutputs[txIn.PreviousOutPoint] = blockchain.SpentOutput{
+			Amount:   100000000,
+			PkScript: prevOutScript,
+		}
+	}
+	withPrevOuts := func(result types.TxRawResult) types.TxRawResult {
+		result.Vin = append([]types.Vin(nil), result.Vin...)
+		for i := range result.Vin {
+			result.Vin[i].PrevOut = &types.PrevOut{
+				Value:        1,
+				ScriptPubKey: verboseResult.Vout[0].ScriptPubKey,
+			}
+		}
+		return result
+	}
+
 	testRPCServerHandler(t, []rpcTest{{
 		name:    "handleGetRawTransaction: invalid txid",
 		handler: handleGetRawTransaction,
@@ -7660,6 +10196,80 @@ func TestHandleGetRawTransaction(t *testing.T) {
 			return chain
 		}(),
 		result: verboseMempoolResult,
+	}, {
+		name:    "handleGetRawTransaction: ok, verbose with prevouts",
+		handler: handleGetRawTransaction,
+		cmd: &types.GetRawTransactionCmd{
+			Txid:    txid,
+			Verbose: &verbosePrevOutTx,
+		},
+		mockTxMempooler: txPool,
+		mockTxIndexer:   txIndex,
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewTx = &testDatabaseTx{
+				fetchBlockRegion: func(region *database.BlockRegion) ([]byte, error) {
+					return hexToBytes(tx0TestTx.hex), nil
+				},
+			}
+			return db
+		}(),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.treasuryActive = true
+			chain.fetchSpentOutputs = spentOutputs
+			return chain
+		}(),
+		result: withPrevOuts(verboseResult),
+	}, {
+		name:    "handleGetRawTransaction: unable to fetch spent outputs",
+		handler: handleGetRawTransaction,
+		cmd: &types.GetRawTransactionCmd{
+			Txid:    txid,
+			Verbose: &verbosePrevOutTx,
+		},
+		mockTxMempooler: txPool,
+		mockTxIndexer:   txIndex,
+		mockDB: func() *testDB {
+			db := defaultMockDB()
+			db.viewTx = &testDatabaseTx{
+				fetchBlockRegion: func(region *database.BlockRegion) ([]byte, error) {
+					return hexToBytes(tx0TestTx.hex), nil
+				},
+			}
+			return db
+		}(),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.treasuryActive = true
+			chain.fetchSpentOutputsErr = errors.New("unable to fetch spent outputs")
+			return chain
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}, {
+		name:    "handleGetRawTransaction: ok, verbose with prevouts, tx from mempool",
+		handler: handleGetRawTransaction,
+		cmd: &types.GetRawTransactionCmd{
+			Txid:    txid,
+			Verbose: &verbosePrevOutTx,
+		},
+		mockTxMempooler: func() *testTxMempooler {
+			mp := defaultMockTxMempooler()
+			mp.fetchTransaction = dcrutil.NewTx(&tx)
+			mp.fetchTransactionErr = nil
+			return mp
+		}(),
+		mockChain: func() *testRPCChain {
+			chain := defaultMockRPCChain()
+			chain.treasuryActive = true
+			chain.fetchUtxoEntry = &testRPCUtxoEntry{
+				amount:   100000000,
+				pkScript: prevOutScript,
+			}
+			return chain
+		}(),
+		result: withPrevOuts(verboseMempoolResult),
 	}, {
 		name:    "handleGetRawTransaction: ok, not verbose, tx from mempool",
 		handler: handleGetRawTransaction,
@@ -7848,6 +10458,37 @@ func TestHandleHelp(t *testing.T) {
 	}})
 }
 
+func TestHandleRPCDiscover(t *testing.T) {
+	t.Parallel()
+
+	doc := &dcrjson.OpenRPCDocument{
+		OpenRPC: dcrjson.OpenRPCVersion,
+		Info:    dcrjson.OpenRPCInfo{Title: "test", Version: "1.0.0"},
+	}
+	testRPCServerHandler(t, []rpcTest{{
+		name:    "handleRPCDiscover: ok",
+		handler: handleRPCDiscover,
+		cmd:     &types.RPCDiscoverCmd{},
+		mockHelpCacher: func() *testHelpCacher {
+			return &testHelpCacher{
+				openRPC: doc,
+			}
+		}(),
+		result: doc,
+	}, {
+		name:    "handleRPCDiscover: unable to generate document",
+		handler: handleRPCDiscover,
+		cmd:     &types.RPCDiscoverCmd{},
+		mockHelpCacher: func() *testHelpCacher {
+			return &testHelpCacher{
+				openRPCErr: errors.New("unable to generate document"),
+			}
+		}(),
+		wantErr: true,
+		errCode: dcrjson.ErrRPCInternal.Code,
+	}})
+}
+
 func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 	t.Helper()
 
@@ -7886,6 +10527,15 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 			if test.setTxIndexerNil {
 				rpcserverConfig.TxIndexer = nil
 			}
+			if test.mockAddrIndexer != nil {
+				rpcserverConfig.AddrIndexer = test.mockAddrIndexer
+			}
+			if test.mockSpentIndexer != nil {
+				rpcserverConfig.SpentIndexer = test.mockSpentIndexer
+			}
+			if test.mockBackuper != nil {
+				rpcserverConfig.ChainStateBackuper = test.mockBackuper
+			}
 			if test.mockDB != nil {
 				rpcserverConfig.DB = test.mockDB
 			}
@@ -7936,10 +10586,11 @@ func testRPCServerHandler(t *testing.T, tests []rpcTest) {
 
 			ctx := context.Background()
 			testServer := &Server{
-				cfg:        *rpcserverConfig,
-				ntfnMgr:    new(testNtfnManager),
-				workState:  workState,
-				helpCacher: helpCacher,
+				cfg:              *rpcserverConfig,
+				ntfnMgr:          new(testNtfnManager),
+				workState:        workState,
+				helpCacher:       helpCacher,
+				blockSubmissions: newBlockSubmissions(),
 			}
 			result, err := test.handler(ctx, testServer, test.cmd)
 			if test.wantErr {
@@ -35,12 +35,43 @@ var helpDescsEnUS = map[string]string{
 	"addnode-addr":      "IP address and port of the peer to operate on",
 	"addnode-subcmd":    "'add' to add a persistent peer, 'remove' to remove a persistent peer, or 'onetry' to try a single connection to a peer",
 
+	// BackupChainStateCmd help.
+	"backupchainstate--synopsis": "Writes a consistent copy of the block and unspent transaction output databases to a directory while the node continues to run.\n" +
+		"The directory uses the same layout as the network data directory, so the node can be started from a copy of it in place of the original.\n" +
+		"Any recently processed blocks that are not yet in the copy of the unspent transaction output database are reprocessed when the node is started from it.",
+	"backupchainstate-dir": "The directory to write, which must not already exist.  Relative paths are relative to the data directory.",
+
+	// BackupChainStateResult help.
+	"backupchainstateresult-dir":     "The absolute path of the written directory.",
+	"backupchainstateresult-bytes":   "The number of bytes copied.",
+	"backupchainstateresult-elapsed": "The number of seconds the backup took.",
+
 	// NodeCmd help.
 	"node--synopsis":     "Attempts to add or remove a peer.",
 	"node-subcmd":        "'disconnect' to remove all matching non-persistent peers, 'remove' to remove a persistent peer, or 'connect' to connect to a peer",
 	"node-target":        "Either the IP address and port of the peer to operate on, or a valid peer ID.",
 	"node-connectsubcmd": "'perm' to make the connected peer a permanent one, 'temp' to try a single connect to a peer",
 
+	// SetBanCmd help.
+	"setban--synopsis": "Attempts to add or remove a ban for an IP address or subnet.\n" +
+		"Connected peers within a newly banned subnet are disconnected and no connections to or from it are allowed until the ban expires.",
+	"setban-subnet":   "The IP address or subnet in CIDR notation (e.g. 192.168.0.0/24) to operate on",
+	"setban-subcmd":   "'add' to add a ban or 'remove' to remove one",
+	"setban-bantime":  "The number of seconds the ban lasts, or the unix time it expires at when absolute is true -- 0 uses the --banduration setting (ignored when removing)",
+	"setban-absolute": "Whether or not the ban time is an absolute unix time",
+
+	// ListBannedCmd help.
+	"listbanned--synopsis": "Returns all IP addresses and subnets that are currently banned.",
+
+	// ListBannedResult help.
+	"listbannedresult-address":     "The banned IP address or subnet in CIDR notation",
+	"listbannedresult-bancreated":  "The unix time the ban was created",
+	"listbannedresult-banneduntil": "The unix time the ban expires",
+	"listbannedresult-banreason":   "The reason for the ban ('manual' or 'misbehaving')",
+
+	// ClearBannedCmd help.
+	"clearbanned--synopsis": "Removes all bans.",
+
 	// TransactionInput help.
 	"transactioninput-amount": "The previous output amount in coins",
 	"transactioninput-txid":   "The hash of the input transaction",
@@ -93,8 +124,8 @@ var helpDescsEnUS = map[string]string{
 	"scriptsig-hex": "Hex-encoded bytes of the script",
 
 	// PrevOut help.
-	"prevout-addresses": "previous output addresses",
-	"prevout-value":     "previous output value",
+	"prevout-value":        "The value of the previous output in coins",
+	"prevout-scriptPubKey": "The public key script of the previous output",
 
 	// VinPrevOut help.
 	"vinprevout-coinbase":      "The hex-encoded bytes of the signature script (coinbase txns only)",
@@ -124,6 +155,7 @@ var helpDescsEnUS = map[string]string{
 	"vin-blockindex":    "The block idx of the origin transaction",
 	"vin-blockheight":   "The block height of the origin transaction",
 	"vin-amountin":      "The amount in",
+	"vin-prevOut":       "The previous output spent by the input (non-coinbase txns only and only when previous outputs are requested)",
 
 	// ScriptPubKeyResult help.
 	"scriptpubkeyresult-asm":       "Disassembly of the script",
@@ -153,16 +185,45 @@ var helpDescsEnUS = map[string]string{
 	"decoderawtransaction-hextx":     "Serialized, hex-encoded transaction",
 
 	// DecodeScriptResult help.
-	"decodescriptresult-asm":       "Disassembly of the script",
-	"decodescriptresult-reqSigs":   "The number of required signatures",
-	"decodescriptresult-type":      "The type of the script (e.g. 'pubkeyhash')",
-	"decodescriptresult-addresses": "The Decred addresses associated with this script",
-	"decodescriptresult-p2sh":      "The script hash for use in pay-to-script-hash transactions (only present if the provided redeem script is not already a pay-to-script-hash script)",
+	"decodescriptresult-asm":           "Disassembly of the script",
+	"decodescriptresult-reqSigs":       "The number of required signatures",
+	"decodescriptresult-type":          "The type of the script (e.g. 'pubkeyhash')",
+	"decodescriptresult-addresses":     "The Decred addresses associated with this script",
+	"decodescriptresult-p2sh":          "The script hash for use in pay-to-script-hash transactions (only present if the provided redeem script is not already a pay-to-script-hash script)",
+	"decodescriptresult-stakesubclass": "The type of the script tagged by the stake opcode for stake scripts (e.g. 'scripthash')",
+	"decodescriptresult-ops":           "The disassembly of each opcode in the script along with its offset",
+	"decodescriptresult-redeemscript":  "The decoded redeem script when one was provided for a pay-to-script-hash script",
+
+	// DecodeRedeemScriptResult help.
+	"decoderedeemscriptresult-asm":           "Disassembly of the redeem script",
+	"decoderedeemscriptresult-reqSigs":       "The number of required signatures",
+	"decoderedeemscriptresult-type":          "The type of the redeem script (e.g. 'multisig')",
+	"decoderedeemscriptresult-stakesubclass": "The type of the script tagged by the stake opcode for stake scripts (e.g. 'scripthash')",
+	"decoderedeemscriptresult-addresses":     "The Decred addresses associated with the redeem script",
+	"decoderedeemscriptresult-p2sh":          "The script hash of the redeem script (only present if the redeem script is not already a pay-to-script-hash script)",
+	"decoderedeemscriptresult-ops":           "The disassembly of each opcode in the redeem script along with its offset",
+
+	// DecodeScriptOp help.
+	"decodescriptop-offset": "The offset of the opcode in the script",
+	"decodescriptop-asm":    "Disassembly of the opcode and any data it pushes ([error] when the remainder of the script does not parse)",
 
 	// DecodeScriptCmd help.
-	"decodescript--synopsis": "Returns a JSON object with information about the provided hex-encoded script.",
-	"decodescript-hexscript": "Hex-encoded script",
-	"decodescript-version":   "The script version, defaults to version 0 if not set.",
+	"decodescript--synopsis":    "Returns a JSON object with information about the provided hex-encoded script.",
+	"decodescript-hexscript":    "Hex-encoded script",
+	"decodescript-version":      "The script version, defaults to version 0 if not set.",
+	"decodescript-redeemscript": "Hex-encoded redeem script to decode when the script is a pay-to-script-hash script",
+
+	// DumpTxOutSetCmd help.
+	"dumptxoutset--synopsis": "Writes a snapshot of the unspent transaction output set as of the current best block to a file.\n" +
+		"Blocks are not processed while the snapshot is written.",
+	"dumptxoutset-path": "The path of the file to write, which must not already exist.  Relative paths are relative to the data directory.",
+
+	// DumpTxOutSetResult help.
+	"dumptxoutsetresult-utxos":     "The number of unspent transaction outputs written.",
+	"dumptxoutsetresult-blockhash": "The hash of the block the snapshot is of.",
+	"dumptxoutsetresult-height":    "The height of the block the snapshot is of.",
+	"dumptxoutsetresult-muhash":    "The rolling muhash of the unspent transaction output set.",
+	"dumptxoutsetresult-path":      "The absolute path of the written file.",
 
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
@@ -225,13 +286,23 @@ var helpDescsEnUS = map[string]string{
 	"getbestblockhash--result0":  "The hex-encoded block hash",
 
 	// GetBlockCmd help.
-	"getblock--synopsis":   "Returns information about a block given its hash.",
-	"getblock-hash":        "The hash of the block",
-	"getblock-verbose":     "Specifies the block is returned as a JSON object instead of hex-encoded string",
-	"getblock-verbosetx":   "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (dcrd extension)",
-	"getblock--condition0": "verbose=false",
-	"getblock--condition1": "verbose=true",
-	"getblock--result0":    "Hex-encoded bytes of the serialized block",
+	"getblock--synopsis":      "Returns information about a block given its hash.",
+	"getblock-hash":           "The hash of the block",
+	"getblock-verbose":        "Specifies the block is returned as a JSON object instead of hex-encoded string",
+	"getblock-verbosetx":      "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (dcrd extension)",
+	"getblock-verboseprevout": "Specifies that the previous output spent by each transaction input is included and only applies if the verbosetx flag is true (dcrd extension)",
+	"getblock--condition0":    "verbose=false",
+	"getblock--condition1":    "verbose=true",
+	"getblock--result0":       "Hex-encoded bytes of the serialized block",
+
+	// GetBlockByTimeCmd help.
+	"getblockbytime--synopsis": "Returns the first block in the main chain with a median time at or after the provided time.",
+	"getblockbytime-timestamp": "The time in seconds since 1 Jan 1970 GMT",
+
+	// GetBlockByTimeResult help.
+	"getblockbytimeresult-hash":       "The hash of the block",
+	"getblockbytimeresult-height":     "The height of the block",
+	"getblockbytimeresult-mediantime": "The median time of the block in seconds since 1 Jan 1970 GMT",
 
 	// GetBlockchainInfoCmd help.
 	"getblockchaininfo--synopsis": "Returns information about the current state of the block chain.",
@@ -347,6 +418,12 @@ var helpDescsEnUS = map[string]string{
 	"getblockheaderverboseresult-extradata":         "Extra data field for the requested block",
 	"getblockheaderverboseresult-stakeversion":      "The stake version of the block",
 
+	// GetBlocksByTimeCmd help.
+	"getblocksbytime--synopsis": "Returns the blocks in the main chain with a median time in the provided time range in chain order.",
+	"getblocksbytime-starttime": "The inclusive start of the time range in seconds since 1 Jan 1970 GMT",
+	"getblocksbytime-endtime":   "The exclusive end of the time range in seconds since 1 Jan 1970 GMT",
+	"getblocksbytime-count":     "The maximum number of blocks to return (1 to 1000)",
+
 	// GetBlockSubsidyCmd help.
 	"getblocksubsidy--synopsis": "Returns information regarding subsidy amounts.",
 	"getblocksubsidy-height":    "The block height",
@@ -358,6 +435,21 @@ var helpDescsEnUS = map[string]string{
 	"getblocksubsidyresult-pow":       "The Proof-of-Work subsidy",
 	"getblocksubsidyresult-total":     "The total subsidy",
 
+	// GetCFiltersV2Cmd help.
+	"getcfiltersv2--synopsis":   "Returns the headers of a range of main chain blocks along with their version 2 block filters and proofs that can be used to prove the filters are committed to by the headers.",
+	"getcfiltersv2-startheight": "The height of the first block in the range",
+	"getcfiltersv2-count":       "The maximum number of blocks to return (1 to 1000)",
+	"getcfiltersv2-includedata": "Include the serialized filters in addition to their hashes",
+
+	// GetCFiltersV2Result help.
+	"getcfiltersv2result-blockhash":   "The hash of the block",
+	"getcfiltersv2result-height":      "The height of the block",
+	"getcfiltersv2result-header":      "Hex-encoded bytes of the serialized block header",
+	"getcfiltersv2result-filterhash":  "The hash of the filter which is the leaf committed to by the header commitment",
+	"getcfiltersv2result-data":        "Hex-encoded bytes of the serialized filter (only when includedata is true)",
+	"getcfiltersv2result-proofindex":  "The index of the leaf that represents the filter hash in the header commitment",
+	"getcfiltersv2result-proofhashes": "The hashes needed to prove the filter is committed to by the header commitment",
+
 	// GetCFilterV2Cmd help.
 	"getcfilterv2--synopsis": "Returns the version 2 block filter for the given block along with a proof that can be used to prove the filter is committed to by the block header",
 	"getcfilterv2-blockhash": "The block hash of the filter to retrieve",
@@ -392,6 +484,13 @@ var helpDescsEnUS = map[string]string{
 	"getcurrentnet--synopsis": "Get Decred network the server is running on.",
 	"getcurrentnet--result0":  "The network identifier",
 
+	// GetDebugLevelsCmd help.
+	"getdebuglevels--synopsis": "Returns the current debug logging level of each subsystem.\n" +
+		"The levels may be changed at runtime with debuglevel.",
+	"getdebuglevels--result0--desc":  "Debug logging levels keyed by the subsystem",
+	"getdebuglevels--result0--key":   "The subsystem",
+	"getdebuglevels--result0--value": "The debug logging level of the subsystem",
+
 	// GetDifficultyCmd help.
 	"getdifficulty--synopsis": "Returns the proof-of-work difficulty as a multiple of the minimum difficulty.",
 	"getdifficulty--result0":  "The difficulty",
@@ -427,6 +526,34 @@ var helpDescsEnUS = map[string]string{
 	"versionbits-version":                  "The version of the vote.",
 	"versionbits-bits":                     "The bits assigned by the vote.",
 
+	// GetSubmitBlockStatusCmd help.
+	"getsubmitblockstatus--synopsis": "Returns the status of a block submitted via submitblocknowait.\n" +
+		"The status of the most recent submissions is retained for a limited time after they are processed.",
+	"getsubmitblockstatus-id": "The submission ID returned by submitblocknowait",
+
+	// GetSubmitBlockStatusResult help.
+	"getsubmitblockstatusresult-id":           "The submission ID which is the hash of the submitted block",
+	"getsubmitblockstatusresult-status":       "The status of the submission (pending, accepted, or rejected)",
+	"getsubmitblockstatusresult-reason":       "The reason the block was rejected (only when rejected)",
+	"getsubmitblockstatusresult-submittime":   "The time the block was submitted in seconds since 1 Jan 1970 GMT",
+	"getsubmitblockstatusresult-completetime": "The time the block finished processing in seconds since 1 Jan 1970 GMT (only when not pending)",
+
+	// GetSyncStatusCmd help.
+	"getsyncstatus--synopsis": "Returns the progress of the chain sync process broken down by stage along with the current block validation rate and estimated time remaining.",
+
+	// GetSyncStatusResult help.
+	"getsyncstatusresult-stage":            "The current stage of the sync process (headers, blocks, or current)",
+	"getsyncstatusresult-syncpeerid":       "The id of the peer headers are being synced from or 0 when there is no sync peer",
+	"getsyncstatusresult-syncheight":       "The latest known block height being synced to as reported by peers",
+	"getsyncstatusresult-bestheaderheight": "The height of the best known block header",
+	"getsyncstatusresult-headerprogress":   "The estimated progress of the headers sync as a percentage",
+	"getsyncstatusresult-blocksdownloaded": "The number of blocks downloaded from peers since the server started",
+	"getsyncstatusresult-blocksinflight":   "The number of blocks requested from peers that have not yet been received",
+	"getsyncstatusresult-bestheight":       "The height of the best fully validated block",
+	"getsyncstatusresult-verifyprogress":   "The estimated progress of the block validation as a percentage",
+	"getsyncstatusresult-blockspersecond":  "The rate blocks were validated over the last minute in blocks per second",
+	"getsyncstatusresult-etaseconds":       "The estimated number of seconds until blocks are validated up to the best known header or 0 when unknown or not syncing blocks",
+
 	// GetVoteInfo
 	"getvoteinfo--synopsis":           "Returns the vote info statistics.",
 	"getvoteinfo-version":             "The stake version.",
@@ -484,6 +611,47 @@ var helpDescsEnUS = map[string]string{
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetMempoolAncestorsCmd help.
+	"getmempoolancestors--synopsis":       "Returns the unconfirmed transactions in the memory pool that the provided transaction directly or indirectly spends outputs of.",
+	"getmempoolancestors-txhash":          "The hash of the transaction in the memory pool",
+	"getmempoolancestors-verbose":         "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempoolancestors--condition0":     "verbose=false",
+	"getmempoolancestors--condition1":     "verbose=true",
+	"getmempoolancestors--result0":        "Array of transaction hashes of the ancestors",
+	"getmempoolancestors--result1--desc":  "Mempool entries of the ancestors keyed by the transaction hash",
+	"getmempoolancestors--result1--key":   "txhash",
+	"getmempoolancestors--result1--value": "object",
+
+	// GetMempoolDescendantsCmd help.
+	"getmempooldescendants--synopsis":       "Returns the unconfirmed transactions in the memory pool that directly or indirectly spend outputs of the provided transaction.",
+	"getmempooldescendants-txhash":          "The hash of the transaction in the memory pool",
+	"getmempooldescendants-verbose":         "Returns JSON object when true or an array of transaction hashes when false",
+	"getmempooldescendants--condition0":     "verbose=false",
+	"getmempooldescendants--condition1":     "verbose=true",
+	"getmempooldescendants--result0":        "Array of transaction hashes of the descendants",
+	"getmempooldescendants--result1--desc":  "Mempool entries of the descendants keyed by the transaction hash",
+	"getmempooldescendants--result1--key":   "txhash",
+	"getmempooldescendants--result1--value": "object",
+
+	// GetMempoolEntryCmd help.
+	"getmempoolentry--synopsis": "Returns details about a single transaction in the memory pool.",
+	"getmempoolentry-txhash":    "The hash of the transaction in the memory pool",
+
+	// GetMempoolEntryResult help.
+	"getmempoolentryresult-size":            "Transaction size in bytes",
+	"getmempoolentryresult-fee":             "Transaction fee in decred",
+	"getmempoolentryresult-time":            "Local time transaction entered pool in seconds since 1 Jan 1970 GMT",
+	"getmempoolentryresult-height":          "Block height when transaction entered the pool",
+	"getmempoolentryresult-type":            "The type of the transaction (regular/ticket/vote/revocation/tadd/tspend)",
+	"getmempoolentryresult-depends":         "Unconfirmed transactions used as inputs for this transaction",
+	"getmempoolentryresult-spentby":         "Unconfirmed transactions spending outputs of this transaction",
+	"getmempoolentryresult-ancestorcount":   "Number of in-mempool ancestor transactions including this one",
+	"getmempoolentryresult-ancestorsize":    "Total size in bytes of the in-mempool ancestors including this one",
+	"getmempoolentryresult-ancestorfees":    "Total fees in decred of the in-mempool ancestors including this one",
+	"getmempoolentryresult-descendantcount": "Number of in-mempool descendant transactions including this one",
+	"getmempoolentryresult-descendantsize":  "Total size in bytes of the in-mempool descendants including this one",
+	"getmempoolentryresult-descendantfees":  "Total fees in decred of the in-mempool descendants including this one",
+
 	// GetMempoolInfoCmd help.
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
@@ -514,6 +682,29 @@ var helpDescsEnUS = map[string]string{
 	"getnetworkhashps-height":    "Perform estimate ending with this height or -1 for current best chain block height",
 	"getnetworkhashps--result0":  "Estimated hashes per second",
 
+	// GetNodeStatusCmd help.
+	"getnodestatus--synopsis": "Returns the health and readiness of the node along with the details they are based on.\n" +
+		"The node is healthy when its database is available and ready when it is also synced to the network, connected to at least one peer, and its optional indexes are caught up to the best chain.\n" +
+		"The same determinations are available without authentication via the /health and /ready HTTP endpoints.",
+
+	// GetNodeStatusResult help.
+	"getnodestatusresult-healthy":      "Whether or not the node is healthy",
+	"getnodestatusresult-ready":        "Whether or not the node is ready to serve requests",
+	"getnodestatusresult-reasons":      "The reasons the node is not healthy or ready, if any",
+	"getnodestatusresult-synced":       "Whether or not the chain is believed to be synced to the network",
+	"getnodestatusresult-peers":        "The number of connected peers",
+	"getnodestatusresult-bestheight":   "The height of the best block",
+	"getnodestatusresult-besthash":     "The hash of the best block",
+	"getnodestatusresult-lastblockage": "The number of seconds since the timestamp of the best block",
+	"getnodestatusresult-databaseok":   "Whether or not the database is available",
+	"getnodestatusresult-indexes":      "The sync status of the enabled optional indexes",
+
+	// NodeIndexStatus help.
+	"nodeindexstatus-name":     "The name of the index",
+	"nodeindexstatus-height":   "The height of the index tip",
+	"nodeindexstatus-synced":   "Whether or not the index is caught up to the best block",
+	"nodeindexstatus-progress": "The fraction of the best chain that has been indexed",
+
 	// GetNetworkInfoCmd help.
 	"getnetworkinfo--synopsis": "Returns a JSON object containing network-related information.",
 
@@ -544,31 +735,64 @@ var helpDescsEnUS = map[string]string{
 	"getnettotals--synopsis": "Returns a JSON object containing network traffic statistics.",
 
 	// GetNetTotalsResult help.
-	"getnettotalsresult-totalbytesrecv": "Total bytes received",
-	"getnettotalsresult-totalbytessent": "Total bytes sent",
-	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
+	"getnettotalsresult-totalbytesrecv":         "Total bytes received",
+	"getnettotalsresult-totalbytessent":         "Total bytes sent",
+	"getnettotalsresult-bytesrecvpermsg":        "Total bytes received from all peers per message command",
+	"getnettotalsresult-bytesrecvpermsg--desc":  "The number of bytes keyed by message command",
+	"getnettotalsresult-bytesrecvpermsg--key":   "command",
+	"getnettotalsresult-bytesrecvpermsg--value": "n",
+	"getnettotalsresult-bytessentpermsg":        "Total bytes sent to all peers per message command",
+	"getnettotalsresult-bytessentpermsg--desc":  "The number of bytes keyed by message command",
+	"getnettotalsresult-bytessentpermsg--key":   "command",
+	"getnettotalsresult-bytessentpermsg--value": "n",
+	"getnettotalsresult-timemillis":             "Number of milliseconds since 1 Jan 1970 GMT",
 
 	// GetPeerInfoResult help.
-	"getpeerinforesult-id":             "A unique node ID",
-	"getpeerinforesult-addr":           "The ip address and port of the peer",
-	"getpeerinforesult-addrlocal":      "Local address",
-	"getpeerinforesult-services":       "Services bitmask which represents the services supported by the peer",
-	"getpeerinforesult-relaytxes":      "Peer has requested transactions be relayed to it",
-	"getpeerinforesult-lastsend":       "Time the last message was received in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-lastrecv":       "Time the last message was sent in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-bytessent":      "Total bytes sent",
-	"getpeerinforesult-bytesrecv":      "Total bytes received",
-	"getpeerinforesult-conntime":       "Time the connection was made in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-timeoffset":     "The time offset of the peer",
-	"getpeerinforesult-pingtime":       "Number of microseconds the last ping took",
-	"getpeerinforesult-pingwait":       "Number of microseconds a queued ping has been waiting for a response",
-	"getpeerinforesult-version":        "The protocol version of the peer",
-	"getpeerinforesult-subver":         "The user agent of the peer",
-	"getpeerinforesult-inbound":        "Whether or not the peer is an inbound connection",
-	"getpeerinforesult-startingheight": "The latest block height the peer knew about when the connection was established",
-	"getpeerinforesult-currentheight":  "The current height of the peer",
-	"getpeerinforesult-banscore":       "The ban score",
-	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
+	"getpeerinforesult-id":                     "A unique node ID",
+	"getpeerinforesult-addr":                   "The ip address and port of the peer",
+	"getpeerinforesult-addrlocal":              "Local address",
+	"getpeerinforesult-services":               "Services bitmask which represents the services supported by the peer",
+	"getpeerinforesult-relaytxes":              "Peer has requested transactions be relayed to it",
+	"getpeerinforesult-lastsend":               "Time the last message was received in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-lastrecv":               "Time the last message was sent in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-bytessent":              "Total bytes sent",
+	"getpeerinforesult-bytesrecv":              "Total bytes received",
+	"getpeerinforesult-conntime":               "Time the connection was made in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-timeoffset":             "The time offset of the peer",
+	"getpeerinforesult-pingtime":               "Number of microseconds the last ping took",
+	"getpeerinforesult-pingwait":               "Number of microseconds a queued ping has been waiting for a response",
+	"getpeerinforesult-version":                "The protocol version of the peer",
+	"getpeerinforesult-subver":                 "The user agent of the peer",
+	"getpeerinforesult-inbound":                "Whether or not the peer is an inbound connection",
+	"getpeerinforesult-startingheight":         "The latest block height the peer knew about when the connection was established",
+	"getpeerinforesult-currentheight":          "The current height of the peer",
+	"getpeerinforesult-banscore":               "The ban score",
+	"getpeerinforesult-syncnode":               "Whether or not the peer is the sync peer",
+	"getpeerinforesult-bytessentpermsg":        "Total bytes sent to the peer per message command",
+	"getpeerinforesult-bytessentpermsg--desc":  "The number of bytes keyed by message command",
+	"getpeerinforesult-bytessentpermsg--key":   "command",
+	"getpeerinforesult-bytessentpermsg--value": "n",
+	"getpeerinforesult-bytesrecvpermsg":        "Total bytes received from the peer per message command",
+	"getpeerinforesult-bytesrecvpermsg--desc":  "The number of bytes keyed by message command",
+	"getpeerinforesult-bytesrecvpermsg--key":   "command",
+	"getpeerinforesult-bytesrecvpermsg--value": "n",
+	"getpeerinforesult-msgssentpermsg":         "Total messages sent to the peer per message command",
+	"getpeerinforesult-msgssentpermsg--desc":   "The number of messages keyed by message command",
+	"getpeerinforesult-msgssentpermsg--key":    "command",
+	"getpeerinforesult-msgssentpermsg--value":  "n",
+	"getpeerinforesult-msgsrecvpermsg":         "Total messages received from the peer per message command",
+	"getpeerinforesult-msgsrecvpermsg--desc":   "The number of messages keyed by message command",
+	"getpeerinforesult-msgsrecvpermsg--key":    "command",
+	"getpeerinforesult-msgsrecvpermsg--value":  "n",
+	"getpeerinforesult-numpings":               "Number of pings that have been answered by the peer",
+	"getpeerinforesult-minpingtime":            "Number of microseconds the fastest answered ping took",
+	"getpeerinforesult-avgpingtime":            "Average number of microseconds the answered pings took",
+	"getpeerinforesult-maxpingtime":            "Number of microseconds the slowest answered ping took",
+	"getpeerinforesult-pinghistogram":          "Histogram of the round-trip times of the answered pings ordered by upper bound",
+
+	// PeerPingBucket help.
+	"peerpingbucket-upperbound": "The inclusive upper bound of the round-trip times counted by the bucket in microseconds (omitted for the final unbounded bucket)",
+	"peerpingbucket-count":      "The number of round-trip times counted by the bucket",
 
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
@@ -586,18 +810,80 @@ var helpDescsEnUS = map[string]string{
 	"getrawmempool--synopsis":   "Returns information about all of the transactions currently in the memory pool.",
 	"getrawmempool-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
 	"getrawmempool-txtype":      "Type of tx to return (regular/tickets/votes/revocations/tspend/tadd/all)",
-	"getrawmempool--condition0": "verbose=false",
-	"getrawmempool--condition1": "verbose=true",
+	"getrawmempool-count":       "Maximum number of transactions to return per page -- when set, a single page of results ordered by transaction hash is returned along with the cursor for the next page",
+	"getrawmempool-cursor":      "The cursor returned with the previous page to continue from (default: the first page)",
+	"getrawmempool--condition0": "verbose=false and count not set",
+	"getrawmempool--condition1": "verbose=true and count not set",
+	"getrawmempool--condition2": "verbose=false and count set",
+	"getrawmempool--condition3": "verbose=true and count set",
 	"getrawmempool--result0":    "Array of transaction hashes",
 
+	// GetRawMempoolPageResult help.
+	"getrawmempoolpageresult-txhashes":   "Array of transaction hashes in the page",
+	"getrawmempoolpageresult-nextcursor": "The cursor to provide to retrieve the next page (omitted when there are no more pages)",
+
+	// GetRawMempoolVerbosePageResult help.
+	"getrawmempoolverbosepageresult-transactions":        "The transactions in the page",
+	"getrawmempoolverbosepageresult-transactions--key":   "txhash",
+	"getrawmempoolverbosepageresult-transactions--value": "object",
+	"getrawmempoolverbosepageresult-transactions--desc":  "Transaction details keyed by the transaction hash",
+	"getrawmempoolverbosepageresult-nextcursor":          "The cursor to provide to retrieve the next page (omitted when there are no more pages)",
+
 	// GetRawTransactionCmd help.
 	"getrawtransaction--synopsis":   "Returns information about a transaction given its hash.",
 	"getrawtransaction-txid":        "The hash of the transaction",
-	"getrawtransaction-verbose":     "Specifies the transaction is returned as a JSON object instead of a hex-encoded string",
+	"getrawtransaction-verbose":     "Specifies the transaction is returned as a JSON object instead of a hex-encoded string when non-zero and that the previous output spent by each input is included when 2",
 	"getrawtransaction--condition0": "verbose=false",
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetRejectedTransactionCmd help.
+	"getrejectedtransaction--synopsis": "Returns the reason a transaction was recently rejected by the memory pool.\n" +
+		"Only a limited number of the most recent rejections are tracked and any record of a rejection is removed should the transaction later be accepted.",
+	"getrejectedtransaction-txid": "The hash of the rejected transaction",
+
+	// GetRejectedTransactionResult help.
+	"getrejectedtransactionresult-txid":   "The hash of the rejected transaction",
+	"getrejectedtransactionresult-time":   "The time the transaction was rejected in seconds since 1 Jan 1970 GMT",
+	"getrejectedtransactionresult-source": "The source of the transaction (local or peer)",
+	"getrejectedtransactionresult-peerid": "The ID of the peer that relayed the transaction when the source is a peer",
+	"getrejectedtransactionresult-code":   "The specific kind of rule violation that caused the rejection or empty if it is unknown",
+	"getrejectedtransactionresult-reason": "A human-readable description of the reason the transaction was rejected",
+
+	// GetRPCLimitInfoCmd help.
+	"getrpclimitinfo--synopsis": "Returns the configured per-user and per-IP RPC request limits along with statistics about the requests that were allowed and rejected due to them.\n" +
+		"Clients are only tracked while limits apply to them and are removed once they have been idle for a while.",
+
+	// GetRPCLimitInfoResult help.
+	"getrpclimitinforesult-userreqrate":           "The maximum number of requests per second per authenticated user (0 means no limit)",
+	"getrpclimitinforesult-ipreqrate":             "The maximum number of requests per second per remote IP (0 means no limit)",
+	"getrpclimitinforesult-usermaxconcurrentreqs": "The maximum number of concurrent requests per authenticated user (0 means no limit)",
+	"getrpclimitinforesult-ipmaxconcurrentreqs":   "The maximum number of concurrent requests per remote IP (0 means no limit)",
+	"getrpclimitinforesult-allowed":               "The total number of requests subject to the limits that were allowed",
+	"getrpclimitinforesult-ratelimited":           "The total number of requests rejected due to exceeding a request rate limit",
+	"getrpclimitinforesult-concurrencylimited":    "The total number of requests rejected due to exceeding a concurrent request limit",
+	"getrpclimitinforesult-clients":               "The clients that are currently tracked",
+
+	// RPCLimitClientInfo help.
+	"rpclimitclientinfo-type":     "The type of the client (user or ip)",
+	"rpclimitclientinfo-id":       "The username or remote IP of the client",
+	"rpclimitclientinfo-inflight": "The number of requests of the client that are currently being processed",
+	"rpclimitclientinfo-allowed":  "The number of requests of the client that were allowed",
+	"rpclimitclientinfo-limited":  "The number of requests of the client that were rejected due to the limits",
+
+	// GetSpentInfoCmd help.
+	"getspentinfo--synopsis": "Returns information about the transaction input that spends a transaction output in the main chain.\n" +
+		"This requires the spent output index to be enabled (--spentindex).",
+	"getspentinfo-txid": "The hash of the transaction that contains the output",
+	"getspentinfo-vout": "The index of the output",
+
+	// GetSpentInfoResult help.
+	"getspentinforesult-txid":      "The hash of the transaction that spends the output",
+	"getspentinforesult-vin":       "The index of the input of the spending transaction that spends the output",
+	"getspentinforesult-tree":      "The tree of the block that contains the spending transaction",
+	"getspentinforesult-height":    "The height of the block that contains the spending transaction",
+	"getspentinforesult-blockhash": "The hash of the block that contains the spending transaction",
+
 	// GetTicketPoolValue help.
 	"getticketpoolvalue--synopsis": "Return the current value of all locked funds in the ticket pool",
 	"getticketpoolvalue--result0":  "Total value of ticket pool",
@@ -658,18 +944,21 @@ var helpDescsEnUS = map[string]string{
 	"gettxoutsetinforesult-transactions":   "The number of unique transactions referenced by outputs.",
 	"gettxoutsetinforesult-txouts":         "The number of transaction outputs.",
 	"gettxoutsetinforesult-serializedhash": "The merklized hash of the utxo set.",
+	"gettxoutsetinforesult-muhash":         "The rolling muhash of the utxo set, which is independent of the order of the outputs.",
 	"gettxoutsetinforesult-disksize":       "The size of the utxo set on disk, in bytes.",
 	"gettxoutsetinforesult-totalamount":    "The total value of the utxo set.",
 
 	// GetWorkResult help.
-	"getworkresult-data":     "Hex-encoded block data",
-	"getworkresult-hash1":    "(DEPRECATED) Hex-encoded formatted hash buffer",
-	"getworkresult-midstate": "(DEPRECATED) Hex-encoded precomputed hash state after hashing first half of the data",
-	"getworkresult-target":   "Hex-encoded little-endian hash target",
+	"getworkresult-data":       "Hex-encoded block data",
+	"getworkresult-hash1":      "(DEPRECATED) Hex-encoded formatted hash buffer",
+	"getworkresult-midstate":   "(DEPRECATED) Hex-encoded precomputed hash state after hashing first half of the data",
+	"getworkresult-target":     "Hex-encoded little-endian hash target",
+	"getworkresult-longpollid": "Identifier of the block template the work is based on that may be provided to wait for new work",
 
 	// GetWorkCmd help.
 	"getwork--synopsis":   "Returns formatted hash data to work on or checks and submits solved data.",
 	"getwork-data":        "Hex-encoded data to check",
+	"getwork-longpollid":  "Wait to return work until the block template differs from the one identified by this value when requesting work",
 	"getwork--condition0": "no data provided",
 	"getwork--condition1": "data provided",
 	"getwork--result1":    "Whether or not the solved data is valid and was added to the chain",
@@ -691,6 +980,15 @@ var helpDescsEnUS = map[string]string{
 	"ping--synopsis": "Queues a ping to be sent to each connected peer.\n" +
 		"Ping times are provided by getpeerinfo via the pingtime and pingwait fields.",
 
+	// PrioritiseTransactionCmd help.
+	"prioritisetransaction--synopsis": "Adjusts the fee of a transaction by the provided delta when prioritizing it for inclusion in block templates generated by this node.\n" +
+		"The fee the transaction actually pays is not modified and deltas are cumulative, so calling it multiple times for the same transaction adds to the existing delta.\n" +
+		"The transaction does not need to be in the memory pool and the delta remains in effect until it is reset by applying the negated total delta, the transaction is mined or expires, or, for transactions that are not in the memory pool, it has not been set for 24 hours.\n" +
+		"At most 10000 deltas are retained and the oldest ones are removed to make room for new ones.",
+	"prioritisetransaction-txid":     "The hash of the transaction to prioritize",
+	"prioritisetransaction-feedelta": "The amount in atoms to add to the fee of the transaction (negative values deprioritize it)",
+	"prioritisetransaction--result0": "The total fee delta in atoms for the transaction",
+
 	// RebroadcastWinnersCmd help.
 	"rebroadcastwinners--synopsis": "Asks the daemon to rebroadcast the winners of the voting lottery.",
 
@@ -700,6 +998,60 @@ var helpDescsEnUS = map[string]string{
 	"reconsiderblock-blockhash": "The hash of the block to reconsider",
 
 	// SendRawTransactionCmd help.
+	// ScanTxOutSetCmd help.
+	"scantxoutset--synopsis": "Scans the unspent transaction output set for outputs that match any of the provided scan objects.\n" +
+		"Only a single scan may be in progress at a time and scans may take a significant amount of time to complete.",
+	"scantxoutset-action":      "The action to perform: 'start' to start a scan and wait for its results, 'status' to return the progress of a scan in progress, or 'abort' to abort a scan in progress",
+	"scantxoutset-scanobjects": "The addresses, address descriptors of the form addr(<address>), or raw public key script descriptors of the form raw(<hex script>) to scan for (required when starting a scan)",
+	"scantxoutset--condition0": "action=start",
+	"scantxoutset--condition1": "action=status (null when no scan is in progress)",
+	"scantxoutset--condition2": "action=abort",
+	"scantxoutset--result2":    "Whether or not a scan in progress was aborted",
+
+	// ScanTxOutSetResult help.
+	"scantxoutsetresult-success":       "Whether or not the scan completed without being aborted",
+	"scantxoutsetresult-searcheditems": "The number of unspent transaction outputs that were scanned",
+	"scantxoutsetresult-height":        "The height of the best block when the scan started",
+	"scantxoutsetresult-bestblock":     "The hash of the best block when the scan started",
+	"scantxoutsetresult-unspents":      "The unspent transaction outputs that match the scan objects",
+	"scantxoutsetresult-totalamount":   "The total amount of all matching unspent transaction outputs in coins",
+
+	// ScanTxOutSetUnspent help.
+	"scantxoutsetunspent-txid":          "The hash of the transaction that contains the output",
+	"scantxoutsetunspent-vout":          "The index of the output",
+	"scantxoutsetunspent-tree":          "The tree of the transaction that contains the output",
+	"scantxoutsetunspent-scriptpubkey":  "The hex-encoded public key script of the output",
+	"scantxoutsetunspent-scriptversion": "The version of the public key script",
+	"scantxoutsetunspent-desc":          "The descriptor of the scan object the output matches",
+	"scantxoutsetunspent-amount":        "The amount of the output in coins",
+	"scantxoutsetunspent-height":        "The height of the block that contains the output",
+	"scantxoutsetunspent-coinbase":      "Whether or not the output is from a coinbase transaction",
+
+	// ScanTxOutSetStatusResult help.
+	"scantxoutsetstatusresult-progress": "The approximate percentage of the unspent transaction output set that has been scanned",
+
+	// SearchRawTransactionsCmd help.
+	"searchrawtransactions--synopsis": "Returns the main chain transactions that involve the provided address by way of either their inputs or outputs.\n" +
+		"This requires the address index to be enabled (--addrindex).",
+	"searchrawtransactions-address":     "The address to search for",
+	"searchrawtransactions-verbose":     "Specifies the transactions are returned as JSON objects instead of hex-encoded strings when non-zero",
+	"searchrawtransactions-skip":        "The number of transactions to skip",
+	"searchrawtransactions-count":       "The maximum number of transactions to return (1 to 1000)",
+	"searchrawtransactions-reverse":     "Specifies the transactions are returned in reverse chain order",
+	"searchrawtransactions--condition0": "verbose=0",
+	"searchrawtransactions--condition1": "verbose=1",
+	"searchrawtransactions--result0":    "Hex-encoded bytes of the serialized transactions",
+
+	// SearchRawTransactionsResult help.
+	"searchrawtransactionsresult-tx":      "The transaction as a JSON object",
+	"searchrawtransactionsresult-tree":    "The tree of the block that contains the transaction",
+	"searchrawtransactionsresult-entries": "The inputs and outputs of the transaction that involve the address",
+
+	// SearchRawTransactionsEntry help.
+	"searchrawtransactionsentry-isinput": "Whether the address is involved by way of an input that spends an output paying to it instead of an output",
+	"searchrawtransactionsentry-index":   "The index of the input or output",
+	"searchrawtransactionsentry-amount":  "The amount of the input or output in coins (the committed amount for ticket commitments)",
+
 	"sendrawtransaction--synopsis":     "Submits the serialized, hex-encoded transaction to the local peer and relays it to the network.",
 	"sendrawtransaction-hextx":         "Serialized, hex-encoded signed transaction",
 	"sendrawtransaction-allowhighfees": "Whether or not to allow insanely high fees (dcrd does not yet implement this parameter, so it has no effect)",
@@ -725,6 +1077,14 @@ var helpDescsEnUS = map[string]string{
 	"submitblock--condition1": "Block rejected",
 	"submitblock--result1":    "The reason the block was rejected",
 
+	// SubmitBlockNoWaitCmd help.
+	"submitblocknowait--synopsis": "Submits a new serialized, hex-encoded block to the network without waiting for it to be processed.\n" +
+		"The returned submission ID may be used with getsubmitblockstatus to determine whether or not the block was accepted.\n" +
+		"Submitting a block that is already being processed or was accepted does not process it again.",
+	"submitblocknowait-hexblock": "Serialized, hex-encoded block",
+	"submitblocknowait-options":  "This parameter is currently ignored",
+	"submitblocknowait--result0": "The submission ID which is the hash of the submitted block",
+
 	// ValidateAddressResult help.
 	"validateaddresschainresult-isvalid": "Whether or not the address is valid",
 	"validateaddresschainresult-address": "The Decred address (only when isvalid is true)",
@@ -750,6 +1110,14 @@ var helpDescsEnUS = map[string]string{
 	"verifymessage-message":   "The signed message",
 	"verifymessage--result0":  "Whether or not the signature verified",
 
+	// VerifyOwnershipProofCmd help.
+	"verifyownershipproof--synopsis": "Verify a script-based proof of ownership of an address for a message.\n" +
+		"Unlike verifymessage, pay-to-pubkey, pay-to-pubkey-hash, and pay-to-script-hash addresses are supported.",
+	"verifyownershipproof-address":  "The Decred address the proof claims ownership of",
+	"verifyownershipproof-message":  "The message the proof commits to",
+	"verifyownershipproof-proof":    "The base-64 encoded serialized proof",
+	"verifyownershipproof--result0": "Whether or not the proof verified",
+
 	// -------- Websocket-specific help --------
 
 	// Session help.
@@ -763,7 +1131,9 @@ var helpDescsEnUS = map[string]string{
 	"notifywinningtickets--synopsis": "Request notifications for whenever any tickets are chosen to vote.",
 
 	// NotifyBlocksCmd help.
-	"notifyblocks--synopsis": "Request notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"notifyblocks--synopsis": "Request notifications for whenever a block is connected or disconnected from the main (best) chain.\n" +
+		"Reconnecting clients may specify the most recent block they were notified of to have the notifications for any blocks they missed replayed in order before any new notifications.",
+	"notifyblocks-since": "The hash or height of the most recent block the client was notified of (at most 2000 blocks may be replayed)",
 
 	// NotifyWorkCmd help.
 	"notifywork--synopsis": "Request notifications for whenever a new block template is generated.",
@@ -780,6 +1150,24 @@ var helpDescsEnUS = map[string]string{
 	// StopNotifyTSpendCmd help.
 	"stopnotifytspend--synopsis": "Cancel registered notifications for whenever a new tspend arrives in the mempool.",
 
+	// NotifyMempoolEventsCmd help.
+	"notifymempoolevents--synopsis": "Request mempoolevent notifications for whenever a transaction is added to or removed from the mempool.",
+
+	// StopNotifyMempoolEventsCmd help.
+	"stopnotifymempoolevents--synopsis": "Cancel registered mempoolevent notifications for whenever a transaction is added to or removed from the mempool.",
+
+	// NotifyTicketEventsCmd help.
+	"notifyticketevents--synopsis": "Request ticketevent notifications for whenever a ticket is purchased, matures, votes, is missed, or is revoked, either in the mempool or in a block connected to the main (best) chain.",
+
+	// StopNotifyTicketEventsCmd help.
+	"stopnotifyticketevents--synopsis": "Cancel registered ticketevent notifications for whenever a ticket is purchased, matures, votes, is missed, or is revoked.",
+
+	// NotifyTemplateDeltasCmd help.
+	"notifytemplatedeltas--synopsis": "Request templatedelta notifications for whenever a new block template is generated that only include the transactions that were not part of the previous template.",
+
+	// StopNotifyTemplateDeltasCmd help.
+	"stopnotifytemplatedeltas--synopsis": "Cancel registered templatedelta notifications for whenever a new block template is generated.",
+
 	// NotifyNewTransactionsCmd help.
 	"notifynewtransactions--synopsis": "Send either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool.",
 	"notifynewtransactions-verbose":   "Specifies which type of notification to receive. If verbose is true, then the caller receives txacceptedverbose, otherwise the caller receives txaccepted",
@@ -802,6 +1190,30 @@ var helpDescsEnUS = map[string]string{
 	"rescan--synopsis":            "Rescan blocks for transactions matching the loaded transaction filter.",
 	"rescan-blockhashes":          "Array of block hashes to rescan.  Each subsequent block after the first one must be a child of the previous.",
 	"rescanresult-discovereddata": "The data matching the loaded transaction filter as JSON objects.",
+
+	// RescanFromHeightCmd help.
+	"rescanfromheight--synopsis": "Rescan main chain blocks starting at a given height for transactions matching the loaded transaction filter.\n" +
+		"At most 2000 blocks are rescanned per request, so clients should continue with the next height after the last rescanned block until reaching the best block.",
+	"rescanfromheight-beginheight": "The height of the first block to rescan",
+	"rescanfromheight-endheight":   "The height of the last block to rescan (default: the best block)",
+
+	// RescanFromHeightResult help.
+	"rescanfromheightresult-discovereddata": "The data matching the loaded transaction filter as JSON objects.",
+	"rescanfromheightresult-lastheight":     "The height of the last rescanned block.",
+	"rescanfromheightresult-lasthash":       "The hash of the last rescanned block.",
+
+	// StreamRawMempoolCmd help.
+	"streamrawmempool--synopsis": "Streams the transactions currently in the memory pool ordered by transaction hash as a series of rawmempoolpage notifications.\n" +
+		"The final notification is flagged as such and at least one notification is always sent.",
+	"streamrawmempool-verbose":  "Include transaction details in the notifications instead of only the transaction hashes",
+	"streamrawmempool-txtype":   "Type of tx to stream (regular/tickets/votes/revocations/tspend/tadd/all)",
+	"streamrawmempool-pagesize": "Maximum number of transactions per notification",
+
+	// StreamRawMempoolResult help.
+	"streamrawmempoolresult-pages":        "The number of rawmempoolpage notifications sent",
+	"streamrawmempoolresult-transactions": "The total number of transactions sent",
+
+	// RescannedBlock help.
 	"rescannedblock-hash":         "The hash of the block containing matching transactions.",
 	"rescannedblock-transactions": "Array of hex-encoded bytes of the serialized matching transactions.",
 
@@ -831,8 +1243,11 @@ var helpDescsEnUS = map[string]string{
 	"getcoinsupply--result0":  "Current coin supply in atoms",
 
 	// LiveTickets help.
-	"livetickets--synopsis":     "Returns live ticket hashes from the ticket database",
-	"liveticketsresult-tickets": "List of live tickets",
+	"livetickets--synopsis":        "Returns live ticket hashes from the ticket database",
+	"livetickets-count":            "Maximum number of tickets to return per page -- when set, a single page of tickets ordered by hash is returned along with the cursor for the next page",
+	"livetickets-cursor":           "The cursor returned with the previous page to continue from (default: the first page)",
+	"liveticketsresult-tickets":    "List of live tickets",
+	"liveticketsresult-nextcursor": "The cursor to provide to retrieve the next page (omitted when there are no more pages or count is not set)",
 
 	// TicketBuckets help.
 	"ticketbuckets--synopsis": "Request for the number of tickets currently in each bucket of the ticket database.",
@@ -913,84 +1328,114 @@ var helpDescsEnUS = map[string]string{
 
 	// regentemplate help
 	"regentemplate--synopsis": "Asks the node to regenerate its block mining template.",
+
+	// RPCDiscoverCmd help.
+	"rpc.discover--synopsis":       "Returns an OpenRPC document that describes the parameters, results, and errors of all supported commands.",
+	"rpc.discover--result0--key":   "field",
+	"rpc.discover--result0--value": "value",
+	"rpc.discover--result0--desc":  "The fields of the OpenRPC document (openrpc, info, and methods)",
 }
 
 // rpcResultTypes specifies the result types that each RPC command can return.
 // This information is used to generate the help.  Each result type must be a
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[types.Method][]interface{}{
-	"addnode":               nil,
-	"createrawsstx":         {(*string)(nil)},
-	"createrawssrtx":        {(*string)(nil)},
-	"createrawtransaction":  {(*string)(nil)},
-	"debuglevel":            {(*string)(nil), (*string)(nil)},
-	"decoderawtransaction":  {(*types.TxRawDecodeResult)(nil)},
-	"decodescript":          {(*types.DecodeScriptResult)(nil)},
-	"estimatefee":           {(*float64)(nil)},
-	"estimatesmartfee":      {(*types.EstimateSmartFeeResult)(nil)},
-	"estimatestakediff":     {(*types.EstimateStakeDiffResult)(nil)},
-	"existsaddress":         {(*bool)(nil)},
-	"existsaddresses":       {(*string)(nil)},
-	"existsliveticket":      {(*bool)(nil)},
-	"existslivetickets":     {(*string)(nil)},
-	"existsmempooltxs":      {(*string)(nil)},
-	"getaddednodeinfo":      {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
-	"getbestblock":          {(*types.GetBestBlockResult)(nil)},
-	"generate":              {(*[]string)(nil)},
-	"getbestblockhash":      {(*string)(nil)},
-	"getblock":              {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
-	"getblockchaininfo":     {(*types.GetBlockChainInfoResult)(nil)},
-	"getblockcount":         {(*int64)(nil)},
-	"getblockhash":          {(*string)(nil)},
-	"getblockheader":        {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
-	"getblocksubsidy":       {(*types.GetBlockSubsidyResult)(nil)},
-	"getcfilterv2":          {(*types.GetCFilterV2Result)(nil)},
-	"getchaintips":          {(*[]types.GetChainTipsResult)(nil)},
-	"getconnectioncount":    {(*int32)(nil)},
-	"getcurrentnet":         {(*uint32)(nil)},
-	"getdifficulty":         {(*float64)(nil)},
-	"getstakedifficulty":    {(*types.GetStakeDifficultyResult)(nil)},
-	"getstakeversioninfo":   {(*types.GetStakeVersionInfoResult)(nil)},
-	"getstakeversions":      {(*types.GetStakeVersionsResult)(nil)},
-	"getgenerate":           {(*bool)(nil)},
-	"gethashespersec":       {(*float64)(nil)},
-	"getheaders":            {(*types.GetHeadersResult)(nil)},
-	"getinfo":               {(*types.InfoChainResult)(nil)},
-	"getmempoolinfo":        {(*types.GetMempoolInfoResult)(nil)},
-	"getmininginfo":         {(*types.GetMiningInfoResult)(nil)},
-	"getnettotals":          {(*types.GetNetTotalsResult)(nil)},
-	"getnetworkhashps":      {(*int64)(nil)},
-	"getnetworkinfo":        {(*[]types.GetNetworkInfoResult)(nil)},
-	"getpeerinfo":           {(*[]types.GetPeerInfoResult)(nil)},
-	"getrawmempool":         {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
-	"getrawtransaction":     {(*string)(nil), (*types.TxRawResult)(nil)},
-	"getticketpoolvalue":    {(*float64)(nil)},
-	"gettreasurybalance":    {(*types.GetTreasuryBalanceResult)(nil)},
-	"gettreasuryspendvotes": {(*types.GetTreasurySpendVotesResult)(nil)},
-	"gettxout":              {(*types.GetTxOutResult)(nil)},
-	"gettxoutsetinfo":       {(*types.GetTxOutSetInfoResult)(nil)},
-	"getvoteinfo":           {(*types.GetVoteInfoResult)(nil)},
-	"getwork":               {(*types.GetWorkResult)(nil), (*bool)(nil)},
-	"getcoinsupply":         {(*int64)(nil)},
-	"help":                  {(*string)(nil), (*string)(nil)},
-	"invalidateblock":       nil,
-	"livetickets":           {(*types.LiveTicketsResult)(nil)},
-	"node":                  nil,
-	"ping":                  nil,
-	"reconsiderblock":       nil,
-	"regentemplate":         nil,
-	"sendrawtransaction":    {(*string)(nil)},
-	"setgenerate":           nil,
-	"stop":                  {(*string)(nil)},
-	"submitblock":           {nil, (*string)(nil)},
-	"ticketfeeinfo":         {(*types.TicketFeeInfoResult)(nil)},
-	"ticketsforaddress":     {(*types.TicketsForAddressResult)(nil)},
-	"ticketvwap":            {(*float64)(nil)},
-	"txfeeinfo":             {(*types.TxFeeInfoResult)(nil)},
-	"validateaddress":       {(*types.ValidateAddressChainResult)(nil)},
-	"verifychain":           {(*bool)(nil)},
-	"verifymessage":         {(*bool)(nil)},
-	"version":               {(*map[string]types.VersionResult)(nil)},
+	"addnode":                nil,
+	"backupchainstate":       {(*types.BackupChainStateResult)(nil)},
+	"clearbanned":            nil,
+	"createrawsstx":          {(*string)(nil)},
+	"createrawssrtx":         {(*string)(nil)},
+	"createrawtransaction":   {(*string)(nil)},
+	"debuglevel":             {(*string)(nil), (*string)(nil)},
+	"decoderawtransaction":   {(*types.TxRawDecodeResult)(nil)},
+	"decodescript":           {(*types.DecodeScriptResult)(nil)},
+	"dumptxoutset":           {(*types.DumpTxOutSetResult)(nil)},
+	"estimatefee":            {(*float64)(nil)},
+	"estimatesmartfee":       {(*types.EstimateSmartFeeResult)(nil)},
+	"estimatestakediff":      {(*types.EstimateStakeDiffResult)(nil)},
+	"existsaddress":          {(*bool)(nil)},
+	"existsaddresses":        {(*string)(nil)},
+	"existsliveticket":       {(*bool)(nil)},
+	"existslivetickets":      {(*string)(nil)},
+	"existsmempooltxs":       {(*string)(nil)},
+	"getaddednodeinfo":       {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
+	"getbestblock":           {(*types.GetBestBlockResult)(nil)},
+	"generate":               {(*[]string)(nil)},
+	"getbestblockhash":       {(*string)(nil)},
+	"getblock":               {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
+	"getblockbytime":         {(*types.GetBlockByTimeResult)(nil)},
+	"getblockchaininfo":      {(*types.GetBlockChainInfoResult)(nil)},
+	"getblockcount":          {(*int64)(nil)},
+	"getblockhash":           {(*string)(nil)},
+	"getblockheader":         {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
+	"getblocksbytime":        {(*[]types.GetBlockByTimeResult)(nil)},
+	"getblocksubsidy":        {(*types.GetBlockSubsidyResult)(nil)},
+	"getcfiltersv2":          {(*[]types.GetCFiltersV2Result)(nil)},
+	"getcfilterv2":           {(*types.GetCFilterV2Result)(nil)},
+	"getchaintips":           {(*[]types.GetChainTipsResult)(nil)},
+	"getconnectioncount":     {(*int32)(nil)},
+	"getcurrentnet":          {(*uint32)(nil)},
+	"getdebuglevels":         {(*map[string]string)(nil)},
+	"getdifficulty":          {(*float64)(nil)},
+	"getstakedifficulty":     {(*types.GetStakeDifficultyResult)(nil)},
+	"getstakeversioninfo":    {(*types.GetStakeVersionInfoResult)(nil)},
+	"getstakeversions":       {(*types.GetStakeVersionsResult)(nil)},
+	"getsubmitblockstatus":   {(*types.GetSubmitBlockStatusResult)(nil)},
+	"getsyncstatus":          {(*types.GetSyncStatusResult)(nil)},
+	"getgenerate":            {(*bool)(nil)},
+	"gethashespersec":        {(*float64)(nil)},
+	"getheaders":             {(*types.GetHeadersResult)(nil)},
+	"getinfo":                {(*types.InfoChainResult)(nil)},
+	"getmempoolancestors":    {(*[]string)(nil), (*map[string]types.GetMempoolEntryResult)(nil)},
+	"getmempooldescendants":  {(*[]string)(nil), (*map[string]types.GetMempoolEntryResult)(nil)},
+	"getmempoolentry":        {(*types.GetMempoolEntryResult)(nil)},
+	"getmempoolinfo":         {(*types.GetMempoolInfoResult)(nil)},
+	"getmininginfo":          {(*types.GetMiningInfoResult)(nil)},
+	"getnettotals":           {(*types.GetNetTotalsResult)(nil)},
+	"getnetworkhashps":       {(*int64)(nil)},
+	"getnodestatus":          {(*types.GetNodeStatusResult)(nil)},
+	"getnetworkinfo":         {(*[]types.GetNetworkInfoResult)(nil)},
+	"getpeerinfo":            {(*[]types.GetPeerInfoResult)(nil)},
+	"getrawmempool":          {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil), (*types.GetRawMempoolPageResult)(nil), (*types.GetRawMempoolVerbosePageResult)(nil)},
+	"getrawtransaction":      {(*string)(nil), (*types.TxRawResult)(nil)},
+	"getrejectedtransaction": {(*types.GetRejectedTransactionResult)(nil)},
+	"getrpclimitinfo":        {(*types.GetRPCLimitInfoResult)(nil)},
+	"getspentinfo":           {(*types.GetSpentInfoResult)(nil)},
+	"getticketpoolvalue":     {(*float64)(nil)},
+	"gettreasurybalance":     {(*types.GetTreasuryBalanceResult)(nil)},
+	"gettreasuryspendvotes":  {(*types.GetTreasurySpendVotesResult)(nil)},
+	"gettxout":               {(*types.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":        {(*types.GetTxOutSetInfoResult)(nil)},
+	"getvoteinfo":            {(*types.GetVoteInfoResult)(nil)},
+	"getwork":                {(*types.GetWorkResult)(nil), (*bool)(nil)},
+	"getcoinsupply":          {(*int64)(nil)},
+	"help":                   {(*string)(nil), (*string)(nil)},
+	"invalidateblock":        nil,
+	"listbanned":             {(*[]types.ListBannedResult)(nil)},
+	"livetickets":            {(*types.LiveTicketsResult)(nil)},
+	"node":                   nil,
+	"ping":                   nil,
+	"prioritisetransaction":  {(*int64)(nil)},
+	"reconsiderblock":        nil,
+	"regentemplate":          nil,
+	"rpc.discover":           {(*map[string]interface{})(nil)},
+	"scantxoutset":           {(*types.ScanTxOutSetResult)(nil), (*types.ScanTxOutSetStatusResult)(nil), (*bool)(nil)},
+	"searchrawtransactions":  {(*[]string)(nil), (*[]types.SearchRawTransactionsResult)(nil)},
+	"sendrawtransaction":     {(*string)(nil)},
+	"setban":                 nil,
+	"setgenerate":            nil,
+	"stop":                   {(*string)(nil)},
+	"submitblock":            {nil, (*string)(nil)},
+	"submitblocknowait":      {(*string)(nil)},
+	"ticketfeeinfo":          {(*types.TicketFeeInfoResult)(nil)},
+	"ticketsforaddress":      {(*types.TicketsForAddressResult)(nil)},
+	"ticketvwap":             {(*float64)(nil)},
+	"txfeeinfo":              {(*types.TxFeeInfoResult)(nil)},
+	"validateaddress":        {(*types.ValidateAddressChainResult)(nil)},
+	"verifychain":            {(*bool)(nil)},
+	"verifymessage":          {(*bool)(nil)},
+	"verifyownershipproof":   {(*bool)(nil)},
+	"version":                {(*map[string]types.VersionResult)(nil)},
 
 	// Websocket commands.
 	"loadtxfilter":              nil,
@@ -999,13 +1444,21 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"notifyblocks":              nil,
 	"notifywork":                nil,
 	"notifytspend":              nil,
+	"notifymempoolevents":       nil,
+	"notifyticketevents":        nil,
+	"notifytemplatedeltas":      nil,
 	"notifynewtransactions":     nil,
 	"rebroadcastwinners":        nil,
 	"rescan":                    {(*types.RescanResult)(nil)},
+	"rescanfromheight":          {(*types.RescanFromHeightResult)(nil)},
 	"session":                   {(*types.SessionResult)(nil)},
+	"streamrawmempool":          {(*types.StreamRawMempoolResult)(nil)},
 	"stopnotifyblocks":          nil,
 	"stopnotifywork":            nil,
 	"stopnotifytspend":          nil,
+	"stopnotifymempoolevents":   nil,
+	"stopnotifyticketevents":    nil,
+	"stopnotifytemplatedeltas":  nil,
 	"stopnotifynewtransactions": nil,
 }
 
@@ -1015,6 +1468,7 @@ type helpCacher struct {
 	sync.Mutex
 	usage      string
 	methodHelp map[types.Method]string
+	openRPC    *dcrjson.OpenRPCDocument
 }
 
 // RPCMethodHelp returns an RPC help string for the provided method.
@@ -1083,6 +1537,68 @@ func (c *helpCacher) RPCUsage(includeWebsockets bool) (string, error) {
 	return c.usage, nil
 }
 
+// openRPCErrors are the errors that may be returned by any command and are
+// included in the OpenRPC description of every method.
+var openRPCErrors = []*dcrjson.RPCError{
+	dcrjson.ErrRPCInvalidParams,
+	dcrjson.ErrRPCInternal,
+	{Code: dcrjson.ErrRPCMisc, Message: "Miscellaneous error"},
+	{Code: dcrjson.ErrRPCInvalidParameter, Message: "Invalid parameter"},
+}
+
+// OpenRPCDocument returns an OpenRPC document that describes all supported RPC
+// commands, including the websocket commands.
+//
+// This function is safe for concurrent access.
+func (c *helpCacher) OpenRPCDocument() (*dcrjson.OpenRPCDocument, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	// Return the cached document if it is available.
+	if c.openRPC != nil {
+		return c.openRPC, nil
+	}
+
+	// Generate a method description for every command sorted by method name.
+	methods := make([]string, 0, len(rpcHandlers)+len(wsHandlers))
+	for k := range rpcHandlers {
+		methods = append(methods, string(k))
+	}
+	for k := range wsHandlers {
+		if _, ok := rpcHandlers[k]; !ok {
+			methods = append(methods, string(k))
+		}
+	}
+	sort.Strings(methods)
+	doc := &dcrjson.OpenRPCDocument{
+		OpenRPC: dcrjson.OpenRPCVersion,
+		Info: dcrjson.OpenRPCInfo{
+			Title:       "dcrd JSON-RPC API",
+			Description: "The JSON-RPC API provided by dcrd.",
+			Version:     jsonrpcSemverString,
+		},
+		Methods: make([]*dcrjson.OpenRPCMethod, 0, len(methods)),
+	}
+	for _, k := range methods {
+		method := types.Method(k)
+		resultTypes, ok := rpcResultTypes[method]
+		if !ok {
+			return nil, errors.New("no result types specified for method " +
+				k)
+		}
+		m, err := dcrjson.GenerateOpenRPCMethod(method, helpDescsEnUS,
+			resultTypes...)
+		if err != nil {
+			return nil, err
+		}
+		m.Errors = openRPCErrors
+		doc.Methods = append(doc.Methods, m)
+	}
+
+	c.openRPC = doc
+	return c.openRPC, nil
+}
+
 // newHelpCacher returns a new instance of a help cacher which provides help and
 // usage for the RPC server commands and caches the results for future calls.
 func newHelpCacher() *helpCacher {
@@ -5,7 +5,10 @@
 
 package rpcserver
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 // TestHelp ensures the help is reasonably accurate by checking that every
 // command specified also has result types defined and the one-line usage and
@@ -62,3 +65,41 @@ func TestHelp(t *testing.T) {
 		}
 	}
 }
+
+// TestOpenRPCDocument ensures the OpenRPC document can be generated, describes
+// every command, and can be marshalled to JSON.
+func TestOpenRPCDocument(t *testing.T) {
+	helpCacher := newHelpCacher()
+	doc, err := helpCacher.OpenRPCDocument()
+	if err != nil {
+		t.Fatalf("Failed to generate OpenRPC document: %v", err)
+	}
+	cached, err := helpCacher.OpenRPCDocument()
+	if err != nil {
+		t.Fatalf("Failed to generate OpenRPC document (cached): %v", err)
+	}
+	if cached != doc {
+		t.Fatal("OpenRPC document was not cached")
+	}
+
+	described := make(map[string]struct{}, len(doc.Methods))
+	for _, m := range doc.Methods {
+		if m.Summary == "" {
+			t.Errorf("Method '%v' does not have a summary", m.Name)
+		}
+		described[m.Name] = struct{}{}
+	}
+	for k := range rpcHandlers {
+		if _, ok := described[string(k)]; !ok {
+			t.Errorf("Method '%v' is not described", k)
+		}
+	}
+	for k := range wsHandlers {
+		if _, ok := described[string(k)]; !ok {
+			t.Errorf("Method '%v' is not described", k)
+		}
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("Failed to marshal OpenRPC document: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+const (
+	// maxBlockSubmissions is the maximum number of blocks submitted via the
+	// submitblocknowait command whose status is tracked.  The oldest
+	// completed submissions are forgotten to make room for new ones once the
+	// limit is reached.
+	maxBlockSubmissions = 100
+
+	// The following constants are the possible statuses of a block submitted
+	// via the submitblocknowait command.
+	submitStatusPending  = "pending"
+	submitStatusAccepted = "accepted"
+	submitStatusRejected = "rejected"
+)
+
+// errTooManyBlockSubmissions is the error returned when a block is submitted
+// while the maximum number of submissions are still being processed.
+var errTooManyBlockSubmissions = errors.New("too many block submissions are " +
+	"still being processed")
+
+// blockSubmission houses the status of a block submitted via the
+// submitblocknowait command.
+type blockSubmission struct {
+	status       string
+	reason       string
+	submitTime   time.Time
+	completeTime time.Time
+}
+
+// blockSubmissions tracks the status of the blocks submitted via the
+// submitblocknowait command so it can be queried via the getsubmitblockstatus
+// command.  Submissions are identified by the hash of the submitted block.
+type blockSubmissions struct {
+	mtx         sync.Mutex
+	submissions map[chainhash.Hash]*blockSubmission
+	order       []chainhash.Hash
+}
+
+// newBlockSubmissions returns a new empty block submission tracker.
+func newBlockSubmissions() *blockSubmissions {
+	return &blockSubmissions{
+		submissions: make(map[chainhash.Hash]*blockSubmission),
+	}
+}
+
+// add starts tracking a submission of the block with the provided hash as
+// pending.  It returns false when the block is already pending or was accepted
+// since it does not need to be processed again.  Blocks that were previously
+// rejected are tracked as pending again so they can be resubmitted.
+//
+// errTooManyBlockSubmissions is returned when the maximum number of tracked
+// submissions are all still pending.
+//
+// This function is safe for concurrent access.
+func (b *blockSubmissions) add(hash chainhash.Hash, now time.Time) (bool, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if sub, ok := b.submissions[hash]; ok {
+		if sub.status != submitStatusRejected {
+			return false, nil
+		}
+		*sub = blockSubmission{status: submitStatusPending, submitTime: now}
+		return true, nil
+	}
+
+	// Forget the oldest completed submission when the limit is reached.
+	if len(b.order) >= maxBlockSubmissions {
+		evict := -1
+		for i, oldHash := range b.order {
+			if b.submissions[oldHash].status != submitStatusPending {
+				evict = i
+				break
+			}
+		}
+		if evict == -1 {
+			return false, errTooManyBlockSubmissions
+		}
+		delete(b.submissions, b.order[evict])
+		b.order = append(b.order[:evict], b.order[evict+1:]...)
+	}
+
+	b.submissions[hash] = &blockSubmission{
+		status:     submitStatusPending,
+		submitTime: now,
+	}
+	b.order = append(b.order, hash)
+	return true, nil
+}
+
+// complete marks the submission of the block with the provided hash as accepted
+// when the passed error is nil and rejected with the error as the reason
+// otherwise.
+//
+// This function is safe for concurrent access.
+func (b *blockSubmissions) complete(hash chainhash.Hash, err error, now time.Time) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	sub, ok := b.submissions[hash]
+	if !ok {
+		return
+	}
+	sub.status = submitStatusAccepted
+	if err != nil {
+		sub.status = submitStatusRejected
+		sub.reason = err.Error()
+	}
+	sub.completeTime = now
+}
+
+// status returns the status of the submission of the block with the provided
+// hash and whether or not it is tracked.
+//
+// This function is safe for concurrent access.
+func (b *blockSubmissions) status(hash chainhash.Hash) (*types.GetSubmitBlockStatusResult, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	sub, ok := b.submissions[hash]
+	if !ok {
+		return nil, false
+	}
+	result := &types.GetSubmitBlockStatusResult{
+		ID:         hash.String(),
+		Status:     sub.status,
+		Reason:     sub.reason,
+		SubmitTime: sub.submitTime.Unix(),
+	}
+	if !sub.completeTime.IsZero() {
+		result.CompleteTime = sub.completeTime.Unix()
+	}
+	return result, true
+}
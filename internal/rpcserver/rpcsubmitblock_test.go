@@ -0,0 +1,166 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+// TestBlockSubmissions ensures the status of block submissions is tracked as
+// expected, including forgetting the oldest completed submissions once the
+// limit is reached.
+func TestBlockSubmissions(t *testing.T) {
+	t.Parallel()
+
+	b := newBlockSubmissions()
+	submitTime := time.Unix(1600000000, 0)
+	completeTime := submitTime.Add(time.Second)
+	hashN := func(n int) chainhash.Hash {
+		return chainhash.HashH([]byte{byte(n), byte(n >> 8)})
+	}
+	mustAdd := func(hash chainhash.Hash, wantAdded bool) {
+		t.Helper()
+		added, err := b.add(hash, submitTime)
+		if err != nil {
+			t.Fatalf("unexpected error adding submission: %v", err)
+		}
+		if added != wantAdded {
+			t.Fatalf("unexpected added result -- got %v, want %v", added,
+				wantAdded)
+		}
+	}
+	checkStatus := func(hash chainhash.Hash, want *types.GetSubmitBlockStatusResult) {
+		t.Helper()
+		got, ok := b.status(hash)
+		if want == nil {
+			if ok {
+				t.Fatalf("unexpected status for untracked submission: %+v",
+					got)
+			}
+			return
+		}
+		if !ok || !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected status -- got %+v, want %+v", got, want)
+		}
+	}
+
+	// Ensure pending and accepted blocks are not processed again.
+	accepted := hashN(0)
+	mustAdd(accepted, true)
+	checkStatus(accepted, &types.GetSubmitBlockStatusResult{
+		ID:         accepted.String(),
+		Status:     submitStatusPending,
+		SubmitTime: submitTime.Unix(),
+	})
+	mustAdd(accepted, false)
+	b.complete(accepted, nil, completeTime)
+	checkStatus(accepted, &types.GetSubmitBlockStatusResult{
+		ID:           accepted.String(),
+		Status:       submitStatusAccepted,
+		SubmitTime:   submitTime.Unix(),
+		CompleteTime: completeTime.Unix(),
+	})
+	mustAdd(accepted, false)
+
+	// Ensure rejected blocks are tracked with the reason and may be
+	// resubmitted.
+	rejected := hashN(1)
+	mustAdd(rejected, true)
+	b.complete(rejected, errors.New("bad block"), completeTime)
+	checkStatus(rejected, &types.GetSubmitBlockStatusResult{
+		ID:           rejected.String(),
+		Status:       submitStatusRejected,
+		Reason:       "bad block",
+		SubmitTime:   submitTime.Unix(),
+		CompleteTime: completeTime.Unix(),
+	})
+	mustAdd(rejected, true)
+	checkStatus(rejected, &types.GetSubmitBlockStatusResult{
+		ID:         rejected.String(),
+		Status:     submitStatusPending,
+		SubmitTime: submitTime.Unix(),
+	})
+
+	// Ensure the oldest completed submission is forgotten once the limit is
+	// reached and that new submissions are refused when all of the tracked
+	// submissions are pending.
+	for i := 2; i <= maxBlockSubmissions; i++ {
+		mustAdd(hashN(i), true)
+	}
+	checkStatus(accepted, nil)
+	if _, err := b.add(hashN(maxBlockSubmissions+1), submitTime); !errors.Is(err,
+		errTooManyBlockSubmissions) {
+
+		t.Fatalf("unexpected error with all submissions pending: %v", err)
+	}
+	b.complete(hashN(2), nil, completeTime)
+	mustAdd(hashN(maxBlockSubmissions+1), true)
+	checkStatus(hashN(2), nil)
+	checkStatus(rejected, &types.GetSubmitBlockStatusResult{
+		ID:         rejected.String(),
+		Status:     submitStatusPending,
+		SubmitTime: submitTime.Unix(),
+	})
+}
+
+// TestSubmitBlockNoWait ensures blocks submitted via submitblocknowait are
+// processed in the background and their status is available via
+// getsubmitblockstatus once processing completes.
+func TestSubmitBlockNoWait(t *testing.T) {
+	t.Parallel()
+
+	cfg := defaultMockConfig(defaultChainParams)
+	syncMgr := defaultMockSyncManager()
+	syncMgr.submitBlockErr = errors.New("block rejected")
+	cfg.SyncMgr = syncMgr
+	cfg.Clock = &testClock{now: time.Unix(1600000000, 0)}
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create RPC server: %v", err)
+	}
+
+	blk := dcrutil.NewBlock(&block432100)
+	blkBytes, err := blk.Bytes()
+	if err != nil {
+		t.Fatalf("error serializing block: %v", err)
+	}
+	ctx := context.Background()
+	submitCmd := types.NewSubmitBlockNoWaitCmd(hex.EncodeToString(blkBytes),
+		nil)
+	id, err := handleSubmitBlockNoWait(ctx, s, submitCmd)
+	if err != nil {
+		t.Fatalf("unexpected error submitting block: %v", err)
+	}
+	if id != blk.Hash().String() {
+		t.Fatalf("unexpected submission id -- got %v, want %v", id,
+			blk.Hash())
+	}
+	s.wg.Wait()
+
+	statusCmd := types.NewGetSubmitBlockStatusCmd(id.(string))
+	result, err := handleGetSubmitBlockStatus(ctx, s, statusCmd)
+	if err != nil {
+		t.Fatalf("unexpected error fetching submission status: %v", err)
+	}
+	want := &types.GetSubmitBlockStatusResult{
+		ID:           blk.Hash().String(),
+		Status:       submitStatusRejected,
+		Reason:       "block rejected",
+		SubmitTime:   1600000000,
+		CompleteTime: 1600000000,
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("unexpected status -- got %+v, want %+v", result, want)
+	}
+}
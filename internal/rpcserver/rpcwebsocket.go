@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -28,6 +29,7 @@ import (
 	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
@@ -54,6 +56,27 @@ const (
 	// websocketPongTimeout is the maximum amount of time attempts to respond to
 	// websocket ping messages with a pong will wait before giving up.
 	websocketPongTimeout = time.Second * 5
+
+	// maxRescanFromHeightBlocks is the maximum number of blocks rescanned by
+	// a single rescanfromheight request.
+	maxRescanFromHeightBlocks = 2000
+
+	// maxNotifyBlocksReplayBlocks is the maximum number of blocks that are
+	// replayed to clients that register for block notifications since a
+	// given block.  Clients that missed more blocks than this must rescan.
+	maxNotifyBlocksReplayBlocks = 2000
+)
+
+// Constants for the events and sources reported by ticketevent notifications.
+const (
+	ticketEventPurchased = "purchased"
+	ticketEventMatured   = "matured"
+	ticketEventVoted     = "voted"
+	ticketEventMissed    = "missed"
+	ticketEventRevoked   = "revoked"
+
+	ticketEventSourceMempool = "mempool"
+	ticketEventSourceBlock   = "block"
 )
 
 type semaphore chan struct{}
@@ -83,16 +106,24 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"notifyblocks":              handleNotifyBlocks,
 	"notifywork":                handleNotifyWork,
 	"notifytspend":              handleNotifyTSpend,
+	"notifymempoolevents":       handleNotifyMempoolEvents,
+	"notifytemplatedeltas":      handleNotifyTemplateDeltas,
 	"notifywinningtickets":      handleWinningTickets,
 	"notifynewtickets":          handleNewTickets,
+	"notifyticketevents":        handleNotifyTicketEvents,
 	"notifynewtransactions":     handleNotifyNewTransactions,
 	"rebroadcastwinners":        handleRebroadcastWinners,
 	"rescan":                    handleRescan,
+	"rescanfromheight":          handleRescanFromHeight,
 	"session":                   handleSession,
+	"streamrawmempool":          handleStreamRawMempool,
 	"stopnotifyblocks":          handleStopNotifyBlocks,
 	"stopnotifywork":            handleStopNotifyWork,
 	"stopnotifytspend":          handleStopNotifyTSpend,
+	"stopnotifymempoolevents":   handleStopNotifyMempoolEvents,
+	"stopnotifytemplatedeltas":  handleStopNotifyTemplateDeltas,
 	"stopnotifynewtransactions": handleStopNotifyNewTransactions,
+	"stopnotifyticketevents":    handleStopNotifyTicketEvents,
 }
 
 // WebsocketHandler handles a new websocket client by creating a new wsClient,
@@ -100,7 +131,7 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 // must be run in a separate goroutine.  It should be invoked from the websocket
 // server handler which runs each new connection in a new goroutine thereby
 // satisfying the requirement.
-func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, remoteAddr string, authenticated bool, isAdmin bool) {
+func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, remoteAddr string, authenticated bool, user *rpcAuthUser) {
 	// Clear the read deadline that was set before the websocket hijacked
 	// the connection.
 	conn.SetReadDeadline(timeZeroVal)
@@ -118,7 +149,7 @@ func (s *Server) WebsocketHandler(ctx context.Context, conn *websocket.Conn, rem
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shutdown.  Once it has shutdown (and hence
 	// disconnected), remove it and any notifications it registered for.
-	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, isAdmin)
+	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, user)
 	if err != nil {
 		log.Errorf("Failed to serve client %s: %v", remoteAddr, err)
 		conn.Close()
@@ -247,6 +278,21 @@ func (m *wsNotificationManager) NotifyReorganization(rd *blockchain.Reorganizati
 	}
 }
 
+// NotifyTLSCertRenewed passes a newly generated PEM-encoded TLS certificate and
+// its expiration time to the notification manager for notifying all websocket
+// clients.
+func (m *wsNotificationManager) NotifyTLSCertRenewed(cert []byte, validUntil time.Time) {
+	n := &notificationTLSCertRenewed{
+		cert:       cert,
+		validUntil: validUntil,
+	}
+
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+	}
+}
+
 // NotifyWinningTickets passes newly winning tickets for an incoming block
 // to the notification manager for further processing.
 func (m *wsNotificationManager) NotifyWinningTickets(wtnd *WinningTicketsNtfnData) {
@@ -281,6 +327,16 @@ func (m *wsNotificationManager) NotifyMempoolTx(tx *dcrutil.Tx, isNew bool) {
 	}
 }
 
+// NotifyMempoolEvent passes a transaction being added to or removed from the
+// mempool to the notification manager for mempool event notification
+// processing.
+func (m *wsNotificationManager) NotifyMempoolEvent(event *mempool.TxEvent) {
+	select {
+	case m.queueNotification <- (*notificationMempoolEvent)(event):
+	case <-m.quit:
+	}
+}
+
 // WinningTicketsNtfnData is the data that is used to generate
 // winning ticket notifications (which indicate a block and
 // the tickets eligible to vote on it).
@@ -411,11 +467,20 @@ type notificationTxAcceptedByMempool struct {
 	isNew bool
 	tx    *dcrutil.Tx
 }
+type notificationMempoolEvent mempool.TxEvent
+type notificationTLSCertRenewed struct {
+	cert       []byte
+	validUntil time.Time
+}
 
 // Notification control requests
 type notificationRegisterClient wsClient
 type notificationUnregisterClient wsClient
 type notificationRegisterBlocks wsClient
+type notificationRegisterBlocksSince struct {
+	wsc   *wsClient
+	since chainhash.Hash
+}
 type notificationUnregisterBlocks wsClient
 type notificationRegisterWork wsClient
 type notificationUnregisterWork wsClient
@@ -425,8 +490,14 @@ type notificationRegisterWinningTickets wsClient
 type notificationUnregisterWinningTickets wsClient
 type notificationRegisterNewTickets wsClient
 type notificationUnregisterNewTickets wsClient
+type notificationRegisterTicketEvents wsClient
+type notificationUnregisterTicketEvents wsClient
 type notificationRegisterNewMempoolTxs wsClient
 type notificationUnregisterNewMempoolTxs wsClient
+type notificationRegisterMempoolEvents wsClient
+type notificationUnregisterMempoolEvents wsClient
+type notificationRegisterTemplateDeltas wsClient
+type notificationUnregisterTemplateDeltas wsClient
 
 // notificationHandler reads notifications and control messages from the queue
 // handler and processes one at a time.
@@ -447,6 +518,20 @@ func (m *wsNotificationManager) notificationHandler(ctx context.Context) {
 	winningTicketNotifications := make(map[chan struct{}]*wsClient)
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
 	txNotifications := make(map[chan struct{}]*wsClient)
+	mempoolEventNotifications := make(map[chan struct{}]*wsClient)
+	templateDeltaNotifications := make(map[chan struct{}]*wsClient)
+	ticketEventNotifications := make(map[chan struct{}]*wsClient)
+
+	// replayHeights houses the height of the final block replayed to clients
+	// that registered for block updates since a given block.  It is used to
+	// avoid notifying those clients of the same blocks again when the related
+	// block connected notifications are processed after the replay.
+	replayHeights := make(map[chan struct{}]int64)
+
+	// prevTemplate houses the most recent block template so that template
+	// delta notifications only need to include the transactions that were not
+	// part of it.
+	var prevTemplate *mining.BlockTemplate
 
 out:
 	for {
@@ -462,14 +547,29 @@ out:
 			}
 			switch n := n.(type) {
 			case *notificationBlockConnected:
-				m.notifyBlockConnected(blockNotifications, (*dcrutil.Block)(n))
+				block := (*dcrutil.Block)(n)
+				clients := skipReplayedClients(blockNotifications,
+					replayHeights, block.Height())
+				m.notifyBlockConnected(clients, block)
+				m.notifyBlockTicketEvents(ticketEventNotifications, block)
 
 			case *notificationBlockDisconnected:
-				m.notifyBlockDisconnected(blockNotifications,
-					(*dcrutil.Block)(n))
+				// Ensure clients that were replayed blocks are notified of
+				// any blocks connected in place of the disconnected one.
+				block := (*dcrutil.Block)(n)
+				for quit, replayHeight := range replayHeights {
+					if replayHeight >= block.Height() {
+						replayHeights[quit] = block.Height() - 1
+					}
+				}
+				m.notifyBlockDisconnected(blockNotifications, block)
 
 			case *notificationWork:
-				m.notifyWork(workNotifications, (*mining.TemplateNtfn)(n))
+				templateNtfn := (*mining.TemplateNtfn)(n)
+				m.notifyWork(workNotifications, templateNtfn)
+				m.notifyTemplateDelta(templateDeltaNotifications, prevTemplate,
+					templateNtfn.Template, templateNtfn.Reason)
+				prevTemplate = templateNtfn.Template
 
 			case *notificationTSpend:
 				m.notifyTSpend(tspendNotifications, (*dcrutil.Tx)(n))
@@ -483,8 +583,9 @@ out:
 					(*WinningTicketsNtfnData)(n))
 
 			case *notificationNewTickets:
-				m.notifyNewTickets(ticketNewNotifications,
-					(*blockchain.TicketNotificationsData)(n))
+				tnd := (*blockchain.TicketNotificationsData)(n)
+				m.notifyNewTickets(ticketNewNotifications, tnd)
+				m.notifyMaturedAndMissedTickets(ticketEventNotifications, tnd)
 
 			case *notificationTxAcceptedByMempool:
 				if n.isNew && len(txNotifications) != 0 {
@@ -492,13 +593,26 @@ out:
 				}
 				m.notifyRelevantTxAccepted(n.tx, clients)
 
+			case *notificationMempoolEvent:
+				event := (*mempool.TxEvent)(n)
+				m.notifyMempoolEvent(mempoolEventNotifications, event)
+				m.notifyMempoolTicketEvent(ticketEventNotifications, event)
+
+			case *notificationTLSCertRenewed:
+				m.notifyTLSCertRenewed(clients, n.cert, n.validUntil)
+
 			case *notificationRegisterBlocks:
 				wsc := (*wsClient)(n)
 				blockNotifications[wsc.quit] = wsc
 
+			case *notificationRegisterBlocksSince:
+				blockNotifications[n.wsc.quit] = n.wsc
+				replayHeights[n.wsc.quit] = m.replayBlocks(n.wsc, &n.since)
+
 			case *notificationUnregisterBlocks:
 				wsc := (*wsClient)(n)
 				delete(blockNotifications, wsc.quit)
+				delete(replayHeights, wsc.quit)
 
 			case *notificationRegisterWork:
 				wsc := (*wsClient)(n)
@@ -532,6 +646,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(ticketNewNotifications, wsc.quit)
 
+			case *notificationRegisterTicketEvents:
+				wsc := (*wsClient)(n)
+				ticketEventNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterTicketEvents:
+				wsc := (*wsClient)(n)
+				delete(ticketEventNotifications, wsc.quit)
+
 			case *notificationRegisterClient:
 				wsc := (*wsClient)(n)
 				clients[wsc.quit] = wsc
@@ -541,11 +663,15 @@ out:
 				// Remove any requests made by the client as well as
 				// the client itself.
 				delete(blockNotifications, wsc.quit)
+				delete(replayHeights, wsc.quit)
 				delete(workNotifications, wsc.quit)
 				delete(tspendNotifications, wsc.quit)
 				delete(txNotifications, wsc.quit)
+				delete(mempoolEventNotifications, wsc.quit)
+				delete(templateDeltaNotifications, wsc.quit)
 				delete(winningTicketNotifications, wsc.quit)
 				delete(ticketNewNotifications, wsc.quit)
+				delete(ticketEventNotifications, wsc.quit)
 				delete(clients, wsc.quit)
 
 			case *notificationRegisterNewMempoolTxs:
@@ -556,6 +682,29 @@ out:
 				wsc := (*wsClient)(n)
 				delete(txNotifications, wsc.quit)
 
+			case *notificationRegisterMempoolEvents:
+				wsc := (*wsClient)(n)
+				mempoolEventNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterMempoolEvents:
+				wsc := (*wsClient)(n)
+				delete(mempoolEventNotifications, wsc.quit)
+
+			case *notificationRegisterTemplateDeltas:
+				// Send the full current template to newly registered clients
+				// so that subsequent deltas can be applied to it.
+				wsc := (*wsClient)(n)
+				templateDeltaNotifications[wsc.quit] = wsc
+				if prevTemplate != nil {
+					client := map[chan struct{}]*wsClient{wsc.quit: wsc}
+					m.notifyTemplateDelta(client, nil, prevTemplate,
+						mining.TURNewParent)
+				}
+
+			case *notificationUnregisterTemplateDeltas:
+				wsc := (*wsClient)(n)
+				delete(templateDeltaNotifications, wsc.quit)
+
 			default:
 				log.Warnf("Unhandled notification type: %T", n)
 			}
@@ -589,6 +738,19 @@ func (m *wsNotificationManager) RegisterBlockUpdates(wsc *wsClient) {
 	}
 }
 
+// RegisterBlockUpdatesSince requests block update notifications to the passed
+// websocket client after replaying the notifications for all blocks connected
+// to the main chain after the block with the provided hash.  Notifications for
+// any blocks since the provided one that are no longer part of the main chain
+// are replayed as disconnected blocks first.
+func (m *wsNotificationManager) RegisterBlockUpdatesSince(wsc *wsClient, since *chainhash.Hash) {
+	n := &notificationRegisterBlocksSince{wsc: wsc, since: *since}
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+	}
+}
+
 // UnregisterBlockUpdates removes block update notifications for the passed
 // websocket client.
 func (m *wsNotificationManager) UnregisterBlockUpdates(wsc *wsClient) {
@@ -634,6 +796,60 @@ func (m *wsNotificationManager) UnregisterTSpendUpdates(wsc *wsClient) {
 	}
 }
 
+// RegisterMempoolEvents requests notifications to the passed websocket client
+// when transactions are added to or removed from the memory pool.
+func (m *wsNotificationManager) RegisterMempoolEvents(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationRegisterMempoolEvents)(wsc):
+	case <-m.quit:
+	}
+}
+
+// UnregisterMempoolEvents removes notifications to the passed websocket client
+// when transactions are added to or removed from the memory pool.
+func (m *wsNotificationManager) UnregisterMempoolEvents(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationUnregisterMempoolEvents)(wsc):
+	case <-m.quit:
+	}
+}
+
+// RegisterTicketEvents requests notifications to the passed websocket client
+// when tickets are purchased, mature, vote, are missed, or are revoked.
+func (m *wsNotificationManager) RegisterTicketEvents(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationRegisterTicketEvents)(wsc):
+	case <-m.quit:
+	}
+}
+
+// UnregisterTicketEvents removes notifications to the passed websocket client
+// when tickets are purchased, mature, vote, are missed, or are revoked.
+func (m *wsNotificationManager) UnregisterTicketEvents(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationUnregisterTicketEvents)(wsc):
+	case <-m.quit:
+	}
+}
+
+// RegisterTemplateDeltas requests block template delta notifications to the
+// passed websocket client.
+func (m *wsNotificationManager) RegisterTemplateDeltas(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationRegisterTemplateDeltas)(wsc):
+	case <-m.quit:
+	}
+}
+
+// UnregisterTemplateDeltas removes block template delta notifications for the
+// passed websocket client.
+func (m *wsNotificationManager) UnregisterTemplateDeltas(wsc *wsClient) {
+	select {
+	case m.queueNotification <- (*notificationUnregisterTemplateDeltas)(wsc):
+	case <-m.quit:
+	}
+}
+
 // subscribedClients returns the set of all websocket client quit channels that
 // are registered to receive notifications regarding tx, either due to tx
 // spending a watched output or outputting to a watched address.  Matching
@@ -777,6 +993,74 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 	}
 }
 
+// skipReplayedClients returns the provided clients without any that were
+// already notified of the block at the provided height by a replay.  Clients
+// that were replayed blocks are removed from the provided replay heights once
+// they are notified of a block after the final replayed one.
+func skipReplayedClients(clients map[chan struct{}]*wsClient, replayHeights map[chan struct{}]int64, height int64) map[chan struct{}]*wsClient {
+	if len(replayHeights) == 0 {
+		return clients
+	}
+
+	filtered := make(map[chan struct{}]*wsClient, len(clients))
+	for quit, wsc := range clients {
+		if replayHeight, ok := replayHeights[quit]; ok {
+			if height <= replayHeight {
+				continue
+			}
+			delete(replayHeights, quit)
+		}
+		filtered[quit] = wsc
+	}
+	return filtered
+}
+
+// replayBlocks notifies the passed websocket client of all blocks connected to
+// the main chain after the block with the provided hash and returns the height
+// of the final block it was notified of.  When the provided block is no longer
+// part of the main chain, the client is first notified that it and any other
+// blocks since the fork point were disconnected.
+//
+// This function MUST be called from the notification handler goroutine so the
+// replayed notifications are delivered in order with any new notifications.
+func (m *wsNotificationManager) replayBlocks(wsc *wsClient, since *chainhash.Hash) int64 {
+	chain := m.server.cfg.Chain
+	client := map[chan struct{}]*wsClient{wsc.quit: wsc}
+
+	// Notify the client of the blocks that are no longer part of the main
+	// chain in reverse order until reaching the fork point.
+	hash := *since
+	for i := 0; !chain.MainChainHasBlock(&hash); i++ {
+		block, err := chain.BlockByHash(&hash)
+		if err != nil || i >= maxNotifyBlocksReplayBlocks {
+			log.Warnf("Unable to replay block notifications since %v to "+
+				"websocket client %s", since, wsc.addr)
+			return chain.BestSnapshot().Height
+		}
+		m.notifyBlockDisconnected(client, block)
+		hash = block.MsgBlock().Header.PrevBlock
+	}
+	forkHeight, err := chain.BlockHeightByHash(&hash)
+	if err != nil {
+		log.Warnf("Unable to replay block notifications since %v to "+
+			"websocket client %s: %v", since, wsc.addr, err)
+		return chain.BestSnapshot().Height
+	}
+
+	// Notify the client of the main chain blocks after the fork point.
+	bestHeight := chain.BestSnapshot().Height
+	for height := forkHeight + 1; height <= bestHeight; height++ {
+		block, err := chain.BlockByHeight(height)
+		if err != nil {
+			log.Warnf("Unable to replay block notification at height %d "+
+				"to websocket client %s: %v", height, wsc.addr, err)
+			return height - 1
+		}
+		m.notifyBlockConnected(client, block)
+	}
+	return bestHeight
+}
+
 // notifyBlockDisconnected notifies websocket clients that have registered for
 // block updates when a block is disconnected from the main chain (due to a
 // reorganize).
@@ -902,6 +1186,85 @@ func (m *wsNotificationManager) notifyWork(clients map[chan struct{}]*wsClient,
 	}
 }
 
+// notifyTemplateDelta notifies websocket clients that have registered for
+// template delta updates when a new block template is generated.  Only the
+// transactions that were not part of the provided previous template are
+// included in full, while the complete ordered list of transaction hashes
+// allows clients to reconstruct the new template from the previous one.  All
+// transactions are included when there is no previous template.
+func (m *wsNotificationManager) notifyTemplateDelta(clients map[chan struct{}]*wsClient,
+	prevTemplate, template *mining.BlockTemplate, reason mining.TemplateUpdateReason) {
+
+	// Skip notification creation if no clients have requested template delta
+	// notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	var prevTxns map[chainhash.Hash]struct{}
+	if prevTemplate != nil {
+		prevBlock := prevTemplate.Block
+		numPrevTxns := len(prevBlock.Transactions) +
+			len(prevBlock.STransactions)
+		prevTxns = make(map[chainhash.Hash]struct{}, numPrevTxns)
+		for _, tx := range prevBlock.Transactions {
+			prevTxns[tx.TxHash()] = struct{}{}
+		}
+		for _, tx := range prevBlock.STransactions {
+			prevTxns[tx.TxHash()] = struct{}{}
+		}
+	}
+
+	// Determine the hashes of all transactions in the new template along with
+	// the serialized transactions that were not in the previous one.
+	var newTxns []string
+	txHashes := func(txns []*wire.MsgTx) ([]string, error) {
+		hashes := make([]string, 0, len(txns))
+		for _, tx := range txns {
+			txHash := tx.TxHash()
+			hashes = append(hashes, txHash.String())
+			if _, ok := prevTxns[txHash]; ok {
+				continue
+			}
+			txBytes, err := tx.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			newTxns = append(newTxns, hex.EncodeToString(txBytes))
+		}
+		return hashes, nil
+	}
+	block := template.Block
+	regularHashes, err := txHashes(block.Transactions)
+	if err != nil {
+		log.Errorf("Failed to serialize template transaction: %v", err)
+		return
+	}
+	stakeHashes, err := txHashes(block.STransactions)
+	if err != nil {
+		log.Errorf("Failed to serialize template transaction: %v", err)
+		return
+	}
+
+	headerBytes, err := block.Header.Bytes()
+	if err != nil {
+		log.Errorf("Failed to serialize template header: %v", err)
+		return
+	}
+	ntfn := types.NewTemplateDeltaNtfn(hex.EncodeToString(headerBytes),
+		updateReasonToWorkNtfnString(reason), prevTemplate == nil,
+		regularHashes, stakeHashes, newTxns)
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal template delta notification: %v", err)
+		return
+	}
+
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // notifyTSpend notifies websocket clients that have registered for mempool
 // tspend arrivals.
 func (m *wsNotificationManager) notifyTSpend(clients map[chan struct{}]*wsClient,
@@ -933,6 +1296,38 @@ func (m *wsNotificationManager) notifyTSpend(clients map[chan struct{}]*wsClient
 	}
 }
 
+// notifyMempoolEvent notifies websocket clients that have registered for
+// mempool events about the passed transaction being added to or removed from
+// the mempool.
+func (m *wsNotificationManager) notifyMempoolEvent(clients map[chan struct{}]*wsClient,
+	event *mempool.TxEvent) {
+
+	// Skip notification creation if no clients have requested mempool event
+	// notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	var reason, replacedBy string
+	if event.Type == mempool.TxEventRemoved {
+		reason = event.Reason.String()
+	}
+	if event.ReplacedBy != nil {
+		replacedBy = event.ReplacedBy.String()
+	}
+	ntfn := types.NewMempoolEventNtfn(event.Sequence, event.Type.String(),
+		event.Tx.Hash().String(), reason, replacedBy)
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal mempool event notification: %v", err)
+		return
+	}
+
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // notifyReorganization notifies websocket clients that have registered for
 // block updates when the blockchain is beginning a reorganization.
 func (m *wsNotificationManager) notifyReorganization(clients map[chan struct{}]*wsClient, rd *blockchain.ReorganizationNtfnsData) {
@@ -958,6 +1353,28 @@ func (m *wsNotificationManager) notifyReorganization(clients map[chan struct{}]*
 	}
 }
 
+// notifyTLSCertRenewed notifies the provided websocket clients that the TLS
+// certificate of the server was regenerated.  Unlike most notifications, all
+// connected clients are notified without needing to register since they are
+// otherwise unable to reconnect when they only trust the previous
+// certificate.
+func (*wsNotificationManager) notifyTLSCertRenewed(clients map[chan struct{}]*wsClient, cert []byte, validUntil time.Time) {
+	if len(clients) == 0 {
+		return
+	}
+
+	ntfn := types.NewTLSCertRenewedNtfn(string(cert), validUntil.Unix())
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		log.Errorf("Failed to marshal tls certificate renewed "+
+			"notification: %v", err)
+		return
+	}
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // RegisterWinningTickets requests winning tickets update notifications
 // to the passed websocket client.
 func (m *wsNotificationManager) RegisterWinningTickets(wsc *wsClient) {
@@ -1045,6 +1462,115 @@ func (*wsNotificationManager) notifyNewTickets(clients map[chan struct{}]*wsClie
 	}
 }
 
+// stakeTxTicketEvent returns the ticket event the passed transaction represents
+// along with the hash of the ticket it applies to.  Ticket purchases apply to
+// themselves while votes and revocations apply to the ticket they spend.  The
+// final return value is false when the transaction is not a ticket purchase,
+// vote, or revocation.
+func stakeTxTicketEvent(tx *wire.MsgTx) (string, chainhash.Hash, bool) {
+	switch stake.DetermineTxType(tx) {
+	case stake.TxTypeSStx:
+		return ticketEventPurchased, tx.TxHash(), true
+	case stake.TxTypeSSGen:
+		return ticketEventVoted, tx.TxIn[1].PreviousOutPoint.Hash, true
+	case stake.TxTypeSSRtx:
+		return ticketEventRevoked, tx.TxIn[0].PreviousOutPoint.Hash, true
+	}
+	return "", chainhash.Hash{}, false
+}
+
+// notifyTicketEvents sends the passed ticket event notifications to the passed
+// websocket clients.
+func notifyTicketEvents(clients map[chan struct{}]*wsClient, ntfns []*types.TicketEventNtfn) {
+	for _, ntfn := range ntfns {
+		marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+		if err != nil {
+			log.Errorf("Failed to marshal ticket event notification: %v",
+				err)
+			continue
+		}
+		for _, wsc := range clients {
+			wsc.QueueNotification(marshalledJSON)
+		}
+	}
+}
+
+// notifyMempoolTicketEvent notifies websocket clients that have registered for
+// ticket events about ticket purchases, votes, and revocations that are added
+// to the mempool.  Votes report the block they vote on.
+func (*wsNotificationManager) notifyMempoolTicketEvent(clients map[chan struct{}]*wsClient, event *mempool.TxEvent) {
+	// Skip notification creation if no clients have requested ticket event
+	// notifications.
+	if len(clients) == 0 || event.Type != mempool.TxEventAdded {
+		return
+	}
+
+	msgTx := event.Tx.MsgTx()
+	ticketEvent, ticket, ok := stakeTxTicketEvent(msgTx)
+	if !ok {
+		return
+	}
+	var blockHash string
+	var blockHeight int64
+	if ticketEvent == ticketEventVoted {
+		hash, height := stake.SSGenBlockVotedOn(msgTx)
+		blockHash, blockHeight = hash.String(), int64(height)
+	}
+	ntfn := types.NewTicketEventNtfn(ticketEvent, ticketEventSourceMempool,
+		ticket.String(), event.Tx.Hash().String(), blockHash, blockHeight)
+	notifyTicketEvents(clients, []*types.TicketEventNtfn{ntfn})
+}
+
+// notifyBlockTicketEvents notifies websocket clients that have registered for
+// ticket events about the ticket purchases, votes, and revocations included in
+// the passed block that was connected to the main chain.
+func (*wsNotificationManager) notifyBlockTicketEvents(clients map[chan struct{}]*wsClient, block *dcrutil.Block) {
+	// Skip notification creation if no clients have requested ticket event
+	// notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	blockHash := block.Hash().String()
+	var ntfns []*types.TicketEventNtfn
+	for _, tx := range block.STransactions() {
+		ticketEvent, ticket, ok := stakeTxTicketEvent(tx.MsgTx())
+		if !ok {
+			continue
+		}
+		ntfns = append(ntfns, types.NewTicketEventNtfn(ticketEvent,
+			ticketEventSourceBlock, ticket.String(), tx.Hash().String(),
+			blockHash, block.Height()))
+	}
+	notifyTicketEvents(clients, ntfns)
+}
+
+// notifyMaturedAndMissedTickets notifies websocket clients that have registered
+// for ticket events about the tickets that matured and the tickets that were
+// missed in a block that was connected to the main chain.
+func (*wsNotificationManager) notifyMaturedAndMissedTickets(clients map[chan struct{}]*wsClient, tnd *blockchain.TicketNotificationsData) {
+	// Skip notification creation if no clients have requested ticket event
+	// notifications.
+	if len(clients) == 0 {
+		return
+	}
+
+	blockHash := tnd.Hash.String()
+	ntfns := make([]*types.TicketEventNtfn, 0, len(tnd.TicketsNew)+
+		len(tnd.TicketsMissed))
+	for _, ticket := range tnd.TicketsNew {
+		ntfns = append(ntfns, types.NewTicketEventNtfn(ticketEventMatured,
+			ticketEventSourceBlock, ticket.String(), "", blockHash,
+			tnd.Height))
+	}
+	for _, ticket := range tnd.TicketsMissed {
+		ntfns = append(ntfns, types.NewTicketEventNtfn(ticketEventMissed,
+			ticketEventSourceBlock, ticket.String(), "", blockHash,
+			tnd.Height))
+	}
+	notifyTicketEvents(clients, ntfns)
+}
+
 // RegisterNewMempoolTxsUpdates requests notifications to the passed websocket
 // client when new transactions are added to the memory pool.
 func (m *wsNotificationManager) RegisterNewMempoolTxsUpdates(wsc *wsClient) {
@@ -1280,9 +1806,9 @@ type wsClient struct {
 	// and therefore is allowed to communicated over the websocket.
 	authenticated bool
 
-	// isAdmin specifies whether a client may change the state of the server;
-	// false means its access is only to the limited set of RPC calls.
-	isAdmin bool
+	// user specifies the RPC methods and notifications an authenticated
+	// client is authorized for.  It is nil for unauthenticated clients.
+	user *rpcAuthUser
 
 	// sessionID is a random ID generated for each client when connected.
 	// These IDs may be queried by a client using the session RPC.  A change
@@ -1323,12 +1849,42 @@ func (c *wsClient) shouldLogReadError(err error) bool {
 	return true
 }
 
+// readMessage reads the next message from the websocket connection while
+// enforcing the read limit that applies to the client on the decompressed
+// message.  The read limit of the connection itself only applies to the bytes
+// received over the wire, so it would otherwise allow a small message that
+// uses per-message compression to decompress to an arbitrarily large one.
+//
+// This function MUST only be called from the input handler.
+func (c *wsClient) readMessage() ([]byte, error) {
+	_, r, err := c.conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	limit := int64(websocketReadLimitUnauthenticated)
+	if c.authenticated {
+		limit = websocketReadLimitAuthenticated
+	}
+	msg, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(msg)) > limit {
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseMessageTooBig,
+			"")
+		c.conn.WriteControl(websocket.CloseMessage, closeMsg,
+			time.Now().Add(websocketPongTimeout))
+		return nil, websocket.ErrReadLimit
+	}
+	return msg, nil
+}
+
 // inHandler handles all incoming messages for the websocket connection.  It
 // must be run as a goroutine.
 func (c *wsClient) inHandler(ctx context.Context) {
 out:
 	for atomic.LoadInt32(&c.disconnected) == 0 {
-		_, msg, err := c.conn.ReadMessage()
+		msg, err := c.readMessage()
 		if err != nil {
 			// Log the error if it's not due to disconnecting.
 			if c.shouldLogReadError(err) {
@@ -1340,7 +1896,7 @@ out:
 		var batchedRequest bool
 
 		// Determine request type
-		if bytes.HasPrefix(msg, batchedRequestPrefix) {
+		if isBatchedRequest(msg) {
 			batchedRequest = true
 		}
 
@@ -1425,8 +1981,9 @@ out:
 				break out
 			case !c.authenticated:
 				// Check credentials.
-				c.authenticated, c.isAdmin = c.rpcServer.checkAuthUserPass(
-					authCmd.Username, authCmd.Passphrase, c.addr)
+				c.user = c.rpcServer.checkAuthUserPass(authCmd.Username,
+					authCmd.Passphrase, c.addr)
+				c.authenticated = c.user != nil
 				if !c.authenticated {
 					break out
 				}
@@ -1445,24 +2002,44 @@ out:
 				continue
 			}
 
-			// Check if the client is using limited RPC credentials and
-			// error when not authorized to call the supplied RPC.
-			if !c.isAdmin {
-				if _, ok := rpcLimited[req.Method]; !ok {
-					jsonErr := &dcrjson.RPCError{
-						Code:    dcrjson.ErrRPCInvalidParams.Code,
-						Message: "limited user not authorized for this method",
-					}
-					// Marshal and send response.
-					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
-					if err != nil {
-						log.Errorf("Failed to marshal parse failure "+
-							"reply: %v", err)
-						continue
-					}
-					c.SendMessage(reply, nil)
+			// Refresh the authorization of the client so changes to the
+			// configured users take effect immediately and disconnect it
+			// when its credentials are no longer valid.
+			c.user = c.rpcServer.currentAuthUser(c.user)
+			if c.user == nil {
+				log.Warnf("Websocket client %s credentials are no longer "+
+					"valid", c.addr)
+				break out
+			}
+
+			// Error when the client is not authorized to call the supplied
+			// RPC.
+			if !c.user.authorized(req.Method) {
+				jsonErr := &dcrjson.RPCError{
+					Code:    dcrjson.ErrRPCInvalidParams.Code,
+					Message: "user not authorized for this method",
+				}
+				// Marshal and send response.
+				reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
+				if err != nil {
+					log.Errorf("Failed to marshal parse failure "+
+						"reply: %v", err)
 					continue
 				}
+				c.SendMessage(reply, nil)
+				continue
+			}
+
+			// Error when the request would exceed the limits of the client.
+			release, limitErr := c.rpcServer.limiter.acquire(c.user, c.addr)
+			if limitErr != nil {
+				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, limitErr)
+				if err != nil {
+					log.Errorf("Failed to marshal reply: %v", err)
+					continue
+				}
+				c.SendMessage(reply, nil)
+				continue
 			}
 
 			// Asynchronously handle the request.  A semaphore is used to
@@ -1488,6 +2065,7 @@ out:
 			c.serviceRequestSem.acquire()
 			go func() {
 				c.serviceRequest(ctx, cmd)
+				release()
 				c.serviceRequestSem.release()
 			}()
 		}
@@ -1542,8 +2120,27 @@ out:
 					}
 				}
 
+				// Respond with a single error when the batch is too large.
+				if len(batchedRequests) > rpcMaxBatchSize {
+					if !c.authenticated {
+						break out
+					}
+
+					jsonErr := batchSizeError(len(batchedRequests))
+					reply, err = dcrjson.MarshalResponse("2.0", nil, nil, jsonErr)
+					if err != nil {
+						log.Errorf("Failed to marshal reply: %v", err)
+					}
+
+					if reply != nil {
+						results = append(results, reply)
+					}
+				}
+
 				// Process each batch entry individually
-				if len(batchedRequests) > 0 {
+				if len(batchedRequests) > 0 &&
+					len(batchedRequests) <= rpcMaxBatchSize {
+
 					batchSize = len(batchedRequests)
 					for _, entry := range batchedRequests {
 						var req dcrjson.Request
@@ -1634,8 +2231,9 @@ out:
 							break out
 						case !c.authenticated:
 							// Check credentials.
-							c.authenticated, c.isAdmin = c.rpcServer.checkAuthUserPass(
+							c.user = c.rpcServer.checkAuthUserPass(
 								authCmd.Username, authCmd.Passphrase, c.addr)
+							c.authenticated = c.user != nil
 							if !c.authenticated {
 								break out
 							}
@@ -1654,32 +2252,56 @@ out:
 							continue
 						}
 
-						// Check if the client is using limited RPC credentials and
-						// error when not authorized to call the supplied RPC.
-						if !c.isAdmin {
-							if _, ok := rpcLimited[req.Method]; !ok {
-								jsonErr := &dcrjson.RPCError{
-									Code:    dcrjson.ErrRPCInvalidParams.Code,
-									Message: "limited user not authorized for this method",
-								}
-								// Marshal and send response.
-								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
-								if err != nil {
-									log.Errorf("Failed to marshal parse failure "+
-										"reply: %v", err)
-									continue
-								}
-
-								if reply != nil {
-									results = append(results, reply)
-								}
+						// Refresh the authorization of the client so changes to
+						// the configured users take effect immediately and
+						// disconnect it when its credentials are no longer
+						// valid.
+						c.user = c.rpcServer.currentAuthUser(c.user)
+						if c.user == nil {
+							log.Warnf("Websocket client %s credentials are no "+
+								"longer valid", c.addr)
+							break out
+						}
+
+						// Error when the client is not authorized to call the
+						// supplied RPC.
+						if !c.user.authorized(req.Method) {
+							jsonErr := &dcrjson.RPCError{
+								Code:    dcrjson.ErrRPCInvalidParams.Code,
+								Message: "user not authorized for this method",
+							}
+							// Marshal and send response.
+							reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
+							if err != nil {
+								log.Errorf("Failed to marshal parse failure "+
+									"reply: %v", err)
 								continue
 							}
+
+							if reply != nil {
+								results = append(results, reply)
+							}
+							continue
+						}
+
+						// Error when the request would exceed the limits of
+						// the client.
+						release, limitErr := c.rpcServer.limiter.acquire(c.user,
+							c.addr)
+						if limitErr != nil {
+							reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, limitErr)
+							if err != nil {
+								log.Errorf("Failed to marshal reply: %v", err)
+								continue
+							}
+							results = append(results, reply)
+							continue
 						}
 
 						// Lookup the websocket extension for the command, if it doesn't
 						// exist fallback to handling the command as a standard command.
 						var resp interface{}
+						done := c.rpcServer.observeCall(cmd, c.user, c.addr)
 						wsHandler, ok := wsHandlers[cmd.method]
 						if ok {
 							resp, err = wsHandler(ctx, c, cmd.params)
@@ -1687,6 +2309,8 @@ out:
 							resp, err = c.rpcServer.standardCmdResult(ctx,
 								cmd)
 						}
+						done(err)
+						release()
 
 						// Marshal request output.
 						reply, err := createMarshalledReply(cmd.jsonrpc, cmd.id, resp, err)
@@ -1752,12 +2376,14 @@ func (c *wsClient) serviceRequest(ctx context.Context, r *parsedRPCCmd) {
 
 	// Lookup the websocket extension for the command and if it doesn't
 	// exist fallback to handling the command as a standard command.
+	done := c.rpcServer.observeCall(r, c.user, c.addr)
 	wsHandler, ok := wsHandlers[r.method]
 	if ok {
 		result, err = wsHandler(ctx, c, r.params)
 	} else {
 		result, err = c.rpcServer.standardCmdResult(ctx, r)
 	}
+	done(err)
 	reply, err := createMarshalledReply(r.jsonrpc, r.id, result, err)
 	if err != nil {
 		log.Errorf("Failed to marshal reply for <%s> "+
@@ -1840,6 +2466,9 @@ out:
 		// Send any messages ready for send until the context is done.
 		select {
 		case r := <-c.sendChan:
+			// Only compress messages that are large enough to benefit when
+			// the client negotiated compression.
+			c.conn.EnableWriteCompression(len(r.msg) >= compressMinSize)
 			err := c.conn.WriteMessage(websocket.TextMessage, r.msg)
 			if err != nil {
 				c.Disconnect()
@@ -1968,7 +2597,7 @@ func (c *wsClient) Run(ctx context.Context) {
 // incoming and outgoing messages in separate goroutines complete with queuing
 // and asynchronous handling for long-running operations.
 func newWebsocketClient(server *Server, conn *websocket.Conn,
-	remoteAddr string, authenticated bool, isAdmin bool) (*wsClient, error) {
+	remoteAddr string, authenticated bool, user *rpcAuthUser) (*wsClient, error) {
 
 	sessionID, err := wire.RandomUint64()
 	if err != nil {
@@ -1979,7 +2608,7 @@ func newWebsocketClient(server *Server, conn *websocket.Conn,
 		conn:              conn,
 		addr:              remoteAddr,
 		authenticated:     authenticated,
-		isAdmin:           isAdmin,
+		user:              user,
 		sessionID:         sessionID,
 		rpcServer:         server,
 		serviceRequestSem: makeSemaphore(server.cfg.RPCMaxConcurrentReqs),
@@ -2080,8 +2709,64 @@ func handleLoadTxFilter(_ context.Context, wsc *wsClient, icmd interface{}) (int
 
 // handleNotifyBlocks implements the notifyblocks command extension for
 // websocket connections.
-func handleNotifyBlocks(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
-	wsc.rpcServer.ntfnMgr.RegisterBlockUpdates(wsc)
+func handleNotifyBlocks(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyBlocksCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	if cmd.Since == nil {
+		wsc.rpcServer.ntfnMgr.RegisterBlockUpdates(wsc)
+		return nil, nil
+	}
+
+	// Determine the block to replay the notifications since which is either
+	// specified by its hash or by its height in the main chain.
+	chain := wsc.rpcServer.cfg.Chain
+	bestHeight := chain.BestSnapshot().Height
+	var since *chainhash.Hash
+	var sinceHeight int64
+	if len(*cmd.Since) == chainhash.MaxHashStringSize {
+		hash, err := chainhash.NewHashFromStr(*cmd.Since)
+		if err != nil {
+			return nil, rpcDecodeHexError(*cmd.Since)
+		}
+		header, err := chain.HeaderByHash(hash)
+		if err != nil {
+			return nil, rpcBlockNotFoundError(*hash)
+		}
+		since, sinceHeight = hash, int64(header.Height)
+	} else {
+		height, err := strconv.ParseInt(*cmd.Since, 10, 64)
+		if err != nil {
+			return nil, rpcInvalidError("Since must be a block hash or " +
+				"height")
+		}
+		if height < 0 || height > bestHeight {
+			return nil, &dcrjson.RPCError{
+				Code: dcrjson.ErrRPCOutOfRange,
+				Message: fmt.Sprintf("Since height %d is out of range "+
+					"[0, %d]", height, bestHeight),
+			}
+		}
+		hash, err := chain.BlockHashByHeight(height)
+		if err != nil {
+			context := "Failed to get block hash"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		since, sinceHeight = hash, height
+	}
+	if bestHeight-sinceHeight > maxNotifyBlocksReplayBlocks {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Unable to replay the %d blocks since %s "+
+				"which exceeds the maximum of %d -- perform a rescan "+
+				"instead", bestHeight-sinceHeight, *cmd.Since,
+				maxNotifyBlocksReplayBlocks),
+		}
+	}
+
+	wsc.rpcServer.ntfnMgr.RegisterBlockUpdatesSince(wsc, since)
 	return nil, nil
 }
 
@@ -2130,6 +2815,20 @@ func handleNotifyTSpend(_ context.Context, wsc *wsClient, _ interface{}) (interf
 	return nil, nil
 }
 
+// handleNotifyMempoolEvents implements the notifymempoolevents command
+// extension for websocket connections.
+func handleNotifyMempoolEvents(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterMempoolEvents(wsc)
+	return nil, nil
+}
+
+// handleNotifyTemplateDeltas implements the notifytemplatedeltas command
+// extension for websocket connections.
+func handleNotifyTemplateDeltas(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterTemplateDeltas(wsc)
+	return nil, nil
+}
+
 // handleSession implements the session command extension for websocket
 // connections.
 func handleSession(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2150,6 +2849,13 @@ func handleNewTickets(_ context.Context, wsc *wsClient, _ interface{}) (interfac
 	return nil, nil
 }
 
+// handleNotifyTicketEvents implements the notifyticketevents command extension
+// for websocket connections.
+func handleNotifyTicketEvents(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterTicketEvents(wsc)
+	return nil, nil
+}
+
 // handleStopNotifyBlocks implements the stopnotifyblocks command extension for
 // websocket connections.
 func handleStopNotifyBlocks(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
@@ -2171,6 +2877,27 @@ func handleStopNotifyTSpend(_ context.Context, wsc *wsClient, _ interface{}) (in
 	return nil, nil
 }
 
+// handleStopNotifyMempoolEvents implements the stopnotifymempoolevents command
+// extension for websocket connections.
+func handleStopNotifyMempoolEvents(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterMempoolEvents(wsc)
+	return nil, nil
+}
+
+// handleStopNotifyTicketEvents implements the stopnotifyticketevents command
+// extension for websocket connections.
+func handleStopNotifyTicketEvents(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterTicketEvents(wsc)
+	return nil, nil
+}
+
+// handleStopNotifyTemplateDeltas implements the stopnotifytemplatedeltas
+// command extension for websocket connections.
+func handleStopNotifyTemplateDeltas(_ context.Context, wsc *wsClient, _ interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterTemplateDeltas(wsc)
+	return nil, nil
+}
+
 // handleNotifyNewTransations implements the notifynewtransactions command
 // extension for websocket connections.
 func handleNotifyNewTransactions(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2342,6 +3069,177 @@ func handleRescan(_ context.Context, wsc *wsClient, icmd interface{}) (interface
 	return &types.RescanResult{DiscoveredData: discoveredData}, nil
 }
 
+// handleRescanFromHeight implements the rescanfromheight command extension for
+// websocket connections.
+func handleRescanFromHeight(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.RescanFromHeightCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	// Load client's transaction filter.  Must exist in order to continue.
+	wsc.Lock()
+	filter := wsc.filterData
+	wsc.Unlock()
+	if filter == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: "Transaction filter must be loaded before rescanning",
+		}
+	}
+
+	// Determine the range of main chain blocks to rescan.  It is limited to
+	// the current best block and to the max number of blocks per request.
+	rpcServer := wsc.rpcServer
+	cfg := rpcServer.cfg
+	bc := cfg.Chain
+	bestHeight := bc.BestSnapshot().Height
+	if cmd.BeginHeight < 0 || cmd.BeginHeight > bestHeight {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Begin height %d is out of range [0, %d]",
+				cmd.BeginHeight, bestHeight),
+		}
+	}
+	endHeight := bestHeight
+	if cmd.EndHeight != nil {
+		if *cmd.EndHeight < cmd.BeginHeight {
+			return nil, rpcInvalidError("End height %d is less than the "+
+				"begin height %d", *cmd.EndHeight, cmd.BeginHeight)
+		}
+		if *cmd.EndHeight < endHeight {
+			endHeight = *cmd.EndHeight
+		}
+	}
+	if endHeight-cmd.BeginHeight >= maxRescanFromHeightBlocks {
+		endHeight = cmd.BeginHeight + maxRescanFromHeightBlocks - 1
+	}
+
+	// Iterate over each block in the range and rescan.  When a block contains
+	// relevant transactions, add it to the response.  The blocks are
+	// required to connect to each other in order to detect the main chain
+	// being reorganized during the rescan.
+	discoveredData := make([]types.RescannedBlock, 0)
+	var lastBlockHash *chainhash.Hash
+	for height := cmd.BeginHeight; height <= endHeight; height++ {
+		block, err := bc.BlockByHeight(height)
+		if err != nil {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCBlockNotFound,
+				Message: "Failed to fetch block: " + err.Error(),
+			}
+		}
+		prevBlkHash := block.MsgBlock().Header.PrevBlock
+		if lastBlockHash != nil && prevBlkHash != *lastBlockHash {
+			return nil, &dcrjson.RPCError{
+				Code: dcrjson.ErrRPCMisc,
+				Message: fmt.Sprintf("Main chain was reorganized during "+
+					"rescan at height %d", height),
+			}
+		}
+		lastBlockHash = block.Hash()
+
+		// Determine if the treasury rules are active as of the block.
+		isTreasuryEnabled, err := rpcServer.isTreasuryAgendaActive(&prevBlkHash)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions := rescanBlock(filter, block, cfg.ChainParams,
+			isTreasuryEnabled)
+		if len(transactions) != 0 {
+			discoveredData = append(discoveredData, types.RescannedBlock{
+				Hash:         lastBlockHash.String(),
+				Transactions: transactions,
+			})
+		}
+	}
+
+	return &types.RescanFromHeightResult{
+		DiscoveredData: discoveredData,
+		LastHeight:     endHeight,
+		LastHash:       lastBlockHash.String(),
+	}, nil
+}
+
+// handleStreamRawMempool implements the streamrawmempool command extension for
+// websocket connections.  It queues the transactions in the mempool to the
+// client as a series of rawmempoolpage notifications ordered by transaction
+// hash so that very large mempools do not need to be sent in a single reply.
+func handleStreamRawMempool(_ context.Context, wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.StreamRawMempoolCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	pageSize := 1000
+	if cmd.PageSize != nil {
+		pageSize = *cmd.PageSize
+	}
+	if pageSize <= 0 {
+		return nil, rpcInvalidError("Page size must be positive: %d",
+			pageSize)
+	}
+	filterType, err := parseRawMempoolTxType(cmd.TxType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gather the transactions to stream while tracking the verbose results
+	// when requested.
+	verbose := cmd.Verbose != nil && *cmd.Verbose
+	var hashes []string
+	var verboseTxns map[string]*types.GetRawMempoolVerboseResult
+	if verbose {
+		verboseTxns = rawMempoolVerboseResults(wsc.rpcServer, filterType)
+		hashes = make([]string, 0, len(verboseTxns))
+		for hash := range verboseTxns {
+			hashes = append(hashes, hash)
+		}
+	} else {
+		hashes = rawMempoolHashes(wsc.rpcServer, filterType)
+	}
+	sort.Strings(hashes)
+
+	// Queue a notification for each page.  At least one page flagged as the
+	// final page is always sent so clients are able to detect the end of the
+	// stream even when the mempool is empty.
+	var pages int
+	for start := 0; start == 0 || start < len(hashes); start += pageSize {
+		end := start + pageSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		page := hashes[start:end]
+		final := end == len(hashes)
+
+		ntfn := types.NewRawMempoolPageNtfn(pages, final, nil, nil)
+		if verbose {
+			ntfn.Transactions = make(map[string]*types.GetRawMempoolVerboseResult,
+				len(page))
+			for _, hash := range page {
+				ntfn.Transactions[hash] = verboseTxns[hash]
+			}
+		} else {
+			ntfn.TxHashes = page
+		}
+		marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+		if err != nil {
+			context := "Failed to marshal raw mempool page notification"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		if err := wsc.QueueNotification(marshalledJSON); err != nil {
+			return nil, rpcConnectionClosedError()
+		}
+		pages++
+	}
+
+	return &types.StreamRawMempoolResult{
+		Pages:        pages,
+		Transactions: len(hashes),
+	}, nil
+}
+
 func init() {
 	wsHandlers = wsHandlersBeforeInit
 }
@@ -0,0 +1,665 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcserver
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/stake/v5"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/mining"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestHandleRescanFromHeight ensures the rescanfromheight websocket command
+// rescans the requested range of main chain blocks with the loaded filter and
+// rejects invalid ranges.
+func TestHandleRescanFromHeight(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	s := &Server{cfg: *cfg}
+	chain := cfg.Chain.(*testRPCChain)
+	bestHeight := chain.bestSnapshot.Height
+	wsc := &wsClient{rpcServer: s}
+
+	blk := dcrutil.NewBlock(&block432100)
+	cmd := types.NewRescanFromHeightCmd(bestHeight, nil)
+	_, err := handleRescanFromHeight(context.Background(), wsc, cmd)
+	if err == nil {
+		t.Fatal("did not receive expected error without a loaded filter")
+	}
+
+	// Watch an outpoint spent by the first non-coinbase regular transaction
+	// of the block.
+	spendTx := block432100.Transactions[1]
+	spent := spendTx.TxIn[0].PreviousOutPoint
+	wsc.filterData = makeWSClientFilter(nil, []*wire.OutPoint{&spent},
+		cfg.ChainParams)
+
+	result, err := handleRescanFromHeight(context.Background(), wsc, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := result.(*types.RescanFromHeightResult)
+	if res.LastHeight != bestHeight || res.LastHash != blk.Hash().String() {
+		t.Fatalf("unexpected last block: %d (%s)", res.LastHeight,
+			res.LastHash)
+	}
+	if len(res.DiscoveredData) != 1 ||
+		res.DiscoveredData[0].Hash != blk.Hash().String() ||
+		len(res.DiscoveredData[0].Transactions) != 1 ||
+		res.DiscoveredData[0].Transactions[0] != txHexString(spendTx) {
+
+		t.Fatalf("unexpected discovered data: %+v", res.DiscoveredData)
+	}
+
+	// Ensure invalid ranges are rejected and that the mock chain, which
+	// returns the same block for all heights, is detected as a chain that
+	// was reorganized during the rescan.
+	tests := []struct {
+		name string
+		cmd  *types.RescanFromHeightCmd
+		code dcrjson.RPCErrorCode
+	}{{
+		name: "negative begin height",
+		cmd:  types.NewRescanFromHeightCmd(-1, nil),
+		code: dcrjson.ErrRPCOutOfRange,
+	}, {
+		name: "begin height after best block",
+		cmd:  types.NewRescanFromHeightCmd(bestHeight+1, nil),
+		code: dcrjson.ErrRPCOutOfRange,
+	}, {
+		name: "end height before begin height",
+		cmd:  types.NewRescanFromHeightCmd(bestHeight, dcrjson.Int64(0)),
+		code: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name: "disconnected blocks",
+		cmd:  types.NewRescanFromHeightCmd(bestHeight-1, nil),
+		code: dcrjson.ErrRPCMisc,
+	}}
+	for _, test := range tests {
+		_, err := handleRescanFromHeight(context.Background(), wsc, test.cmd)
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != test.code {
+			t.Errorf("%s: unexpected error -- got %v, want code %d",
+				test.name, err, test.code)
+		}
+	}
+}
+
+// TestNotifyTemplateDelta ensures template delta notifications include all
+// transactions when there is no previous template and only the transactions
+// that were not part of the previous template otherwise.
+func TestNotifyTemplateDelta(t *testing.T) {
+	wsc := &wsClient{
+		ntfnChan: make(chan []byte, 1),
+		quit:     make(chan struct{}),
+	}
+	clients := map[chan struct{}]*wsClient{wsc.quit: wsc}
+	m := &wsNotificationManager{}
+
+	// receiveNtfn returns the next template delta notification queued for
+	// the client.
+	receiveNtfn := func() *types.TemplateDeltaNtfn {
+		t.Helper()
+		var req dcrjson.Request
+		if err := json.Unmarshal(<-wsc.ntfnChan, &req); err != nil {
+			t.Fatalf("unable to unmarshal notification: %v", err)
+		}
+		ntfn, err := dcrjson.ParseParams(types.Method(req.Method), req.Params)
+		if err != nil {
+			t.Fatalf("unable to parse notification: %v", err)
+		}
+		return ntfn.(*types.TemplateDeltaNtfn)
+	}
+
+	template := &mining.BlockTemplate{Block: &block432100}
+	numTxns := len(block432100.Transactions) + len(block432100.STransactions)
+	m.notifyTemplateDelta(clients, nil, template, mining.TURNewParent)
+	ntfn := receiveNtfn()
+	if !ntfn.Full || ntfn.Reason != "newparent" || len(ntfn.NewTxns) != numTxns {
+		t.Fatalf("unexpected full notification: full %v, reason %q, %d new "+
+			"txns", ntfn.Full, ntfn.Reason, len(ntfn.NewTxns))
+	}
+	if len(ntfn.TxHashes) != len(block432100.Transactions) ||
+		len(ntfn.STxHashes) != len(block432100.STransactions) {
+
+		t.Fatalf("unexpected number of hashes: %d regular, %d stake",
+			len(ntfn.TxHashes), len(ntfn.STxHashes))
+	}
+
+	// Ensure only the regular transactions that are missing from the previous
+	// template are included in full.
+	prevBlock := block432100
+	prevBlock.Transactions = prevBlock.Transactions[:1]
+	prevTemplate := &mining.BlockTemplate{Block: &prevBlock}
+	m.notifyTemplateDelta(clients, prevTemplate, template, mining.TURNewTxns)
+	ntfn = receiveNtfn()
+	if ntfn.Full || ntfn.Reason != "newtxns" {
+		t.Fatalf("unexpected delta notification: full %v, reason %q",
+			ntfn.Full, ntfn.Reason)
+	}
+	wantNewTxns := len(block432100.Transactions) - 1
+	if len(ntfn.NewTxns) != wantNewTxns {
+		t.Fatalf("unexpected number of new txns -- got %d, want %d",
+			len(ntfn.NewTxns), wantNewTxns)
+	}
+	if ntfn.NewTxns[0] != txHexString(block432100.Transactions[1]) {
+		t.Fatalf("unexpected new txn %s", ntfn.NewTxns[0])
+	}
+}
+
+// TestNotifyTLSCertRenewed ensures TLS certificate renewal notifications are
+// sent to all of the provided clients with the expected certificate and
+// expiration time.
+func TestNotifyTLSCertRenewed(t *testing.T) {
+	clients := make(map[chan struct{}]*wsClient)
+	for i := 0; i < 2; i++ {
+		wsc := &wsClient{
+			ntfnChan: make(chan []byte, 1),
+			quit:     make(chan struct{}),
+		}
+		clients[wsc.quit] = wsc
+	}
+	m := &wsNotificationManager{}
+
+	cert := []byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n")
+	validUntil := time.Unix(1700000000, 0)
+	m.notifyTLSCertRenewed(clients, cert, validUntil)
+	for _, wsc := range clients {
+		var req dcrjson.Request
+		if err := json.Unmarshal(<-wsc.ntfnChan, &req); err != nil {
+			t.Fatalf("unable to unmarshal notification: %v", err)
+		}
+		ntfn, err := dcrjson.ParseParams(types.Method(req.Method), req.Params)
+		if err != nil {
+			t.Fatalf("unable to parse notification: %v", err)
+		}
+		renewed := ntfn.(*types.TLSCertRenewedNtfn)
+		if renewed.Cert != string(cert) {
+			t.Fatalf("unexpected cert -- got %q, want %q", renewed.Cert, cert)
+		}
+		if renewed.ValidUntil != validUntil.Unix() {
+			t.Fatalf("unexpected valid until -- got %d, want %d",
+				renewed.ValidUntil, validUntil.Unix())
+		}
+	}
+}
+
+// TestNotifyTicketEvents ensures ticket event notifications are created for the
+// ticket purchases, votes, and revocations added to the mempool or included in
+// connected blocks as well as for matured and missed tickets.
+func TestNotifyTicketEvents(t *testing.T) {
+	wsc := &wsClient{
+		ntfnChan: make(chan []byte, 64),
+		quit:     make(chan struct{}),
+	}
+	clients := map[chan struct{}]*wsClient{wsc.quit: wsc}
+	m := &wsNotificationManager{}
+
+	// receiveNtfns returns all ticket event notifications queued for the
+	// client.
+	receiveNtfns := func() []*types.TicketEventNtfn {
+		t.Helper()
+		var ntfns []*types.TicketEventNtfn
+		for {
+			select {
+			case marshalled := <-wsc.ntfnChan:
+				var req dcrjson.Request
+				if err := json.Unmarshal(marshalled, &req); err != nil {
+					t.Fatalf("unable to unmarshal notification: %v", err)
+				}
+				ntfn, err := dcrjson.ParseParams(types.Method(req.Method),
+					req.Params)
+				if err != nil {
+					t.Fatalf("unable to parse notification: %v", err)
+				}
+				ntfns = append(ntfns, ntfn.(*types.TicketEventNtfn))
+			default:
+				return ntfns
+			}
+		}
+	}
+
+	// Ensure the ticket purchases and votes in a connected block are notified
+	// in order.
+	block := dcrutil.NewBlock(&block432100)
+	var wantEvents []string
+	var vote *dcrutil.Tx
+	for _, tx := range block.STransactions() {
+		switch stake.DetermineTxType(tx.MsgTx()) {
+		case stake.TxTypeSStx:
+			wantEvents = append(wantEvents, ticketEventPurchased)
+		case stake.TxTypeSSGen:
+			wantEvents = append(wantEvents, ticketEventVoted)
+			if vote == nil {
+				vote = tx
+			}
+		case stake.TxTypeSSRtx:
+			wantEvents = append(wantEvents, ticketEventRevoked)
+		}
+	}
+	if vote == nil {
+		t.Fatal("test block does not contain any votes")
+	}
+	m.notifyBlockTicketEvents(clients, block)
+	ntfns := receiveNtfns()
+	if len(ntfns) != len(wantEvents) {
+		t.Fatalf("unexpected number of block notifications -- got %d, want "+
+			"%d", len(ntfns), len(wantEvents))
+	}
+	for i, ntfn := range ntfns {
+		if ntfn.Event != wantEvents[i] || ntfn.Source != "block" ||
+			ntfn.BlockHash != block.Hash().String() ||
+			ntfn.BlockHeight != block.Height() {
+
+			t.Fatalf("unexpected block notification %d: %+v", i, ntfn)
+		}
+	}
+	voteTicket := vote.MsgTx().TxIn[1].PreviousOutPoint.Hash.String()
+	if ntfns[0].Ticket != voteTicket || ntfns[0].TxID != vote.Hash().String() {
+		t.Fatalf("unexpected vote notification: %+v", ntfns[0])
+	}
+
+	// Ensure votes added to the mempool are notified along with the block
+	// they vote on while removed votes and regular transactions are not.
+	m.notifyMempoolTicketEvent(clients, &mempool.TxEvent{
+		Type: mempool.TxEventAdded,
+		Tx:   vote,
+	})
+	m.notifyMempoolTicketEvent(clients, &mempool.TxEvent{
+		Type:   mempool.TxEventRemoved,
+		Tx:     vote,
+		Reason: mempool.RemovalReasonMined,
+	})
+	m.notifyMempoolTicketEvent(clients, &mempool.TxEvent{
+		Type: mempool.TxEventAdded,
+		Tx:   block.Transactions()[1],
+	})
+	ntfns = receiveNtfns()
+	votedOn := block432100.Header.PrevBlock.String()
+	want := types.NewTicketEventNtfn("voted", "mempool", voteTicket,
+		vote.Hash().String(), votedOn, block.Height()-1)
+	if len(ntfns) != 1 || *ntfns[0] != *want {
+		t.Fatalf("unexpected mempool notifications: %+v", ntfns)
+	}
+
+	// Ensure matured and missed tickets are notified.
+	matured, missed := chainhash.Hash{0x01}, chainhash.Hash{0x02}
+	m.notifyMaturedAndMissedTickets(clients, &blockchain.TicketNotificationsData{
+		Hash:          *block.Hash(),
+		Height:        block.Height(),
+		TicketsNew:    []chainhash.Hash{matured},
+		TicketsMissed: []chainhash.Hash{missed},
+	})
+	ntfns = receiveNtfns()
+	wantNtfns := []*types.TicketEventNtfn{
+		types.NewTicketEventNtfn("matured", "block", matured.String(), "",
+			block.Hash().String(), block.Height()),
+		types.NewTicketEventNtfn("missed", "block", missed.String(), "",
+			block.Hash().String(), block.Height()),
+	}
+	if len(ntfns) != len(wantNtfns) {
+		t.Fatalf("unexpected number of ticket notifications: %d", len(ntfns))
+	}
+	for i, ntfn := range ntfns {
+		if *ntfn != *wantNtfns[i] {
+			t.Fatalf("unexpected ticket notification %d -- got %+v, want %+v",
+				i, ntfn, wantNtfns[i])
+		}
+	}
+}
+
+// TestHandleStreamRawMempool ensures the streamrawmempool websocket command
+// queues the mempool transactions ordered by hash in pages of the requested
+// size with only the last one flagged as final.
+func TestHandleStreamRawMempool(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	mp := defaultMockTxMempooler()
+	var hashes []string
+	var txns []*wire.MsgTx
+	txns = append(txns, block432100.Transactions...)
+	txns = append(txns, block432100.STransactions...)
+	for _, tx := range txns[:5] {
+		utilTx := dcrutil.NewTx(tx)
+		desc := &mempool.TxDesc{TxDesc: mining.TxDesc{Tx: utilTx}}
+		mp.txDescs = append(mp.txDescs, desc)
+		mp.verboseTxDescs = append(mp.verboseTxDescs,
+			&mempool.VerboseTxDesc{TxDesc: *desc})
+		hashes = append(hashes, utilTx.Hash().String())
+	}
+	sort.Strings(hashes)
+	cfg.TxMempooler = mp
+	s := &Server{cfg: *cfg}
+	wsc := &wsClient{
+		rpcServer: s,
+		ntfnChan:  make(chan []byte, len(hashes)+1),
+		quit:      make(chan struct{}),
+	}
+
+	// receiveNtfn returns the next raw mempool page notification queued for
+	// the client.
+	receiveNtfn := func() *types.RawMempoolPageNtfn {
+		t.Helper()
+		var req dcrjson.Request
+		if err := json.Unmarshal(<-wsc.ntfnChan, &req); err != nil {
+			t.Fatalf("unable to unmarshal notification: %v", err)
+		}
+		ntfn, err := dcrjson.ParseParams(types.Method(req.Method), req.Params)
+		if err != nil {
+			t.Fatalf("unable to parse notification: %v", err)
+		}
+		return ntfn.(*types.RawMempoolPageNtfn)
+	}
+
+	cmd := types.NewStreamRawMempoolCmd(nil, nil, dcrjson.Int(2))
+	result, err := handleStreamRawMempool(context.Background(), wsc, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := result.(*types.StreamRawMempoolResult)
+	if res.Pages != 3 || res.Transactions != len(hashes) {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for i := 0; i < res.Pages; i++ {
+		ntfn := receiveNtfn()
+		start, end := i*2, i*2+2
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		if ntfn.Page != i || ntfn.Final != (i == res.Pages-1) {
+			t.Fatalf("unexpected page %d: index %d, final %v", i, ntfn.Page,
+				ntfn.Final)
+		}
+		if len(ntfn.TxHashes) != end-start || ntfn.Transactions != nil {
+			t.Fatalf("unexpected page %d contents: %+v", i, ntfn)
+		}
+		for j, hash := range ntfn.TxHashes {
+			if hash != hashes[start+j] {
+				t.Fatalf("unexpected hash in page %d: got %s, want %s", i,
+					hash, hashes[start+j])
+			}
+		}
+	}
+
+	// Ensure verbose results are sent in a single final page when the page
+	// size exceeds the number of transactions.
+	cmd = types.NewStreamRawMempoolCmd(dcrjson.Bool(true), nil, nil)
+	if _, err := handleStreamRawMempool(context.Background(), wsc, cmd); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ntfn := receiveNtfn()
+	if !ntfn.Final || len(ntfn.Transactions) != len(hashes) {
+		t.Fatalf("unexpected verbose page: %+v", ntfn)
+	}
+
+	// Ensure a single empty final page is sent for an empty mempool.
+	mp.txDescs = nil
+	cmd = types.NewStreamRawMempoolCmd(nil, nil, nil)
+	result, err = handleStreamRawMempool(context.Background(), wsc, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res = result.(*types.StreamRawMempoolResult)
+	if ntfn := receiveNtfn(); res.Pages != 1 || !ntfn.Final ||
+		len(ntfn.TxHashes) != 0 {
+
+		t.Fatalf("unexpected empty mempool result: %+v", res)
+	}
+
+	// Ensure an invalid page size is rejected.
+	cmd = types.NewStreamRawMempoolCmd(nil, nil, dcrjson.Int(0))
+	_, err = handleStreamRawMempool(context.Background(), wsc, cmd)
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCInvalidParameter {
+		t.Fatalf("unexpected error for invalid page size: %v", err)
+	}
+}
+
+// replayTestChain provides a mock chain with a main chain and a side chain of
+// blocks for testing block notification replays.
+type replayTestChain struct {
+	*testRPCChain
+	mainChain []*dcrutil.Block
+	blocks    map[chainhash.Hash]*dcrutil.Block
+}
+
+// BlockByHash returns the block with the given hash from either chain.
+func (c *replayTestChain) BlockByHash(hash *chainhash.Hash) (*dcrutil.Block, error) {
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+	return block, nil
+}
+
+// BlockByHeight returns the main chain block at the given height.
+func (c *replayTestChain) BlockByHeight(height int64) (*dcrutil.Block, error) {
+	if height < 0 || height >= int64(len(c.mainChain)) {
+		return nil, errors.New("block not found")
+	}
+	return c.mainChain[height], nil
+}
+
+// BlockHeightByHash returns the height of the main chain block with the given
+// hash.
+func (c *replayTestChain) BlockHeightByHash(hash *chainhash.Hash) (int64, error) {
+	if !c.MainChainHasBlock(hash) {
+		return 0, errors.New("block not in main chain")
+	}
+	return c.blocks[*hash].Height(), nil
+}
+
+// MainChainHasBlock returns whether or not the block with the given hash is in
+// the main chain.
+func (c *replayTestChain) MainChainHasBlock(hash *chainhash.Hash) bool {
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return false
+	}
+	height := block.Height()
+	return height < int64(len(c.mainChain)) &&
+		*c.mainChain[height].Hash() == *hash
+}
+
+// TestReplayBlocks ensures clients that register for block notifications since
+// a given block are notified of the missed blocks in order, including any that
+// are no longer part of the main chain, and are not notified of the replayed
+// blocks again.
+func TestReplayBlocks(t *testing.T) {
+	// Create a main chain of blocks at heights 0 through 5 and a side chain
+	// at heights 3 and 4 that forks from the main chain at height 2.
+	chain := &replayTestChain{
+		testRPCChain: defaultMockRPCChain(),
+		blocks:       make(map[chainhash.Hash]*dcrutil.Block),
+	}
+	newBlock := func(parent *dcrutil.Block, nonce uint32) *dcrutil.Block {
+		var header wire.BlockHeader
+		if parent != nil {
+			header.PrevBlock = *parent.Hash()
+			header.Height = uint32(parent.Height() + 1)
+		}
+		header.Nonce = nonce
+		block := dcrutil.NewBlock(&wire.MsgBlock{Header: header})
+		chain.blocks[*block.Hash()] = block
+		return block
+	}
+	var parent *dcrutil.Block
+	for i := 0; i < 6; i++ {
+		parent = newBlock(parent, 0)
+		chain.mainChain = append(chain.mainChain, parent)
+	}
+	side3 := newBlock(chain.mainChain[2], 1)
+	side4 := newBlock(side3, 1)
+	chain.bestSnapshot = &blockchain.BestState{Height: 5}
+
+	cfg := defaultMockConfig(defaultChainParams)
+	cfg.Chain = chain
+	m := &wsNotificationManager{server: &Server{cfg: *cfg}}
+	wsc := &wsClient{
+		ntfnChan: make(chan []byte, 10),
+		quit:     make(chan struct{}),
+	}
+
+	// checkNtfns ensures the notifications queued for the client are for the
+	// provided blocks with the provided methods.
+	type wantNtfn struct {
+		method string
+		block  *dcrutil.Block
+	}
+	checkNtfns := func(want []wantNtfn) {
+		t.Helper()
+		if len(wsc.ntfnChan) != len(want) {
+			t.Fatalf("unexpected number of notifications -- got %d, want %d",
+				len(wsc.ntfnChan), len(want))
+		}
+		for i, w := range want {
+			var req dcrjson.Request
+			if err := json.Unmarshal(<-wsc.ntfnChan, &req); err != nil {
+				t.Fatalf("unable to unmarshal notification: %v", err)
+			}
+			ntfn, err := dcrjson.ParseParams(types.Method(req.Method),
+				req.Params)
+			if err != nil {
+				t.Fatalf("unable to parse notification: %v", err)
+			}
+			var header string
+			switch ntfn := ntfn.(type) {
+			case *types.BlockConnectedNtfn:
+				header = ntfn.Header
+			case *types.BlockDisconnectedNtfn:
+				header = ntfn.Header
+			}
+			headerBytes, _ := w.block.MsgBlock().Header.Bytes()
+			if req.Method != w.method ||
+				header != hex.EncodeToString(headerBytes) {
+
+				t.Fatalf("unexpected notification %d: %s for block at "+
+					"height %d", i, req.Method, w.block.Height())
+			}
+		}
+	}
+
+	// Ensure the blocks after a main chain block are replayed.
+	replayHeight := m.replayBlocks(wsc, chain.mainChain[3].Hash())
+	if replayHeight != 5 {
+		t.Fatalf("unexpected replay height %d", replayHeight)
+	}
+	checkNtfns([]wantNtfn{
+		{"blockconnected", chain.mainChain[4]},
+		{"blockconnected", chain.mainChain[5]},
+	})
+
+	// Ensure the side chain blocks are disconnected in reverse order before
+	// the main chain blocks after the fork point are replayed.
+	replayHeight = m.replayBlocks(wsc, side4.Hash())
+	if replayHeight != 5 {
+		t.Fatalf("unexpected replay height %d", replayHeight)
+	}
+	checkNtfns([]wantNtfn{
+		{"blockdisconnected", side4},
+		{"blockdisconnected", side3},
+		{"blockconnected", chain.mainChain[3]},
+		{"blockconnected", chain.mainChain[4]},
+		{"blockconnected", chain.mainChain[5]},
+	})
+
+	// Ensure clients are not notified of replayed blocks again and are no
+	// longer tracked once they are notified of a new block.
+	other := &wsClient{quit: make(chan struct{})}
+	clients := map[chan struct{}]*wsClient{wsc.quit: wsc, other.quit: other}
+	replayHeights := map[chan struct{}]int64{wsc.quit: 5}
+	filtered := skipReplayedClients(clients, replayHeights, 5)
+	if _, ok := filtered[wsc.quit]; ok || len(filtered) != 1 {
+		t.Fatalf("replayed client was not skipped")
+	}
+	filtered = skipReplayedClients(clients, replayHeights, 6)
+	if len(filtered) != 2 || len(replayHeights) != 0 {
+		t.Fatalf("client that caught up was not notified")
+	}
+}
+
+// TestHandleNotifyBlocks ensures the notifyblocks websocket command validates
+// the block to replay the notifications since.
+func TestHandleNotifyBlocks(t *testing.T) {
+	cfg := defaultMockConfig(defaultChainParams)
+	chain := cfg.Chain.(*testRPCChain)
+	bestHeight := chain.bestSnapshot.Height
+	s := &Server{cfg: *cfg, ntfnMgr: &testNtfnManager{}}
+	wsc := &wsClient{rpcServer: s}
+
+	tests := []struct {
+		name  string
+		since *string
+		code  dcrjson.RPCErrorCode
+	}{{
+		name: "no since",
+	}, {
+		name:  "since hash",
+		since: dcrjson.String(block432100.BlockHash().String()),
+	}, {
+		name:  "since best height",
+		since: dcrjson.String(strconv.FormatInt(bestHeight, 10)),
+	}, {
+		name:  "invalid since",
+		since: dcrjson.String("invalid"),
+		code:  dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:  "invalid since hash",
+		since: dcrjson.String(strings.Repeat("z", 64)),
+		code:  dcrjson.ErrRPCDecodeHexString,
+	}, {
+		name:  "negative since height",
+		since: dcrjson.String("-1"),
+		code:  dcrjson.ErrRPCOutOfRange,
+	}, {
+		name:  "since height after best block",
+		since: dcrjson.String(strconv.FormatInt(bestHeight+1, 10)),
+		code:  dcrjson.ErrRPCOutOfRange,
+	}, {
+		name: "too many blocks to replay",
+		since: dcrjson.String(strconv.FormatInt(
+			bestHeight-maxNotifyBlocksReplayBlocks-1, 10)),
+		code: dcrjson.ErrRPCOutOfRange,
+	}}
+	for _, test := range tests {
+		cmd := &types.NotifyBlocksCmd{Since: test.since}
+		_, err := handleNotifyBlocks(context.Background(), wsc, cmd)
+		if test.code == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != test.code {
+			t.Errorf("%s: unexpected error -- got %v, want code %d",
+				test.name, err, test.code)
+		}
+	}
+
+	// Ensure an unknown block hash is rejected.
+	chain.headerByHashErr = errors.New("block not found")
+	cmd := &types.NotifyBlocksCmd{
+		Since: dcrjson.String(block432100.BlockHash().String()),
+	}
+	_, err := handleNotifyBlocks(context.Background(), wsc, cmd)
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != dcrjson.ErrRPCBlockNotFound {
+		t.Fatalf("unexpected error for unknown block: %v", err)
+	}
+}
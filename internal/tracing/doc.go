@@ -0,0 +1,38 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package tracing provides lightweight tracing of block and transaction
+processing that is exported to an OpenTelemetry collector via the OTLP/HTTP
+protocol with JSON encoding.
+
+Tracing is disabled by default.  The instrumented subsystems accept an optional
+tracer in their configuration and all methods of the tracer and the spans it
+creates treat nil instances as disabled, so subsystems that are not provided
+with a tracer only incur the cost of a nil check.
+
+# Traces
+
+Each block and transaction is traced in a separate trace with an ID that is
+derived from its hash along with a root span that also has an ID derived from
+its hash.  This allows the subsystems involved in processing a block or
+transaction to add spans to its trace without having to pass any context
+between them.  For example, the sync manager starts the root span of a block
+when it is downloaded, the chain adds spans for validating and connecting it,
+and the server adds spans for relaying it and handling its notifications.
+
+# Sampling
+
+Traces are sampled based on their trace ID according to the configured ratio.
+Since the trace ID is derived from the hash of the block or transaction, all
+subsystems make the same sampling decision for a given trace and unsampled
+traces do not incur any further overhead.
+
+# Exporting
+
+Ended spans are queued and exported in batches by Run.  Spans are dropped when
+the queue is full, such as when the collector is unavailable, so tracing never
+blocks processing.
+*/
+package tracing
@@ -0,0 +1,256 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// exportTimeout is the maximum amount of time allowed for exporting a
+	// batch of spans.
+	exportTimeout = 10 * time.Second
+
+	// instrumentationScope is the name of the instrumentation scope reported
+	// for all spans.
+	instrumentationScope = "github.com/decred/dcrd/internal/tracing"
+
+	// Span kinds and status codes as defined by OTLP.
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+// The following types define the subset of the OTLP JSON encoding of trace
+// export requests that is used to export spans.  Note that trace and span IDs
+// are hex encoded and 64-bit integers are encoded as strings as required by
+// the encoding.
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// otlpValue returns the OTLP encoding of the provided attribute value, which
+// must be one of the types allowed by SetAttribute.
+func otlpValue(value interface{}) otlpAnyValue {
+	switch v := value.(type) {
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	}
+	s := fmt.Sprint(value)
+	return otlpAnyValue{StringValue: &s}
+}
+
+// otlpString returns the provided string as an OTLP attribute with the
+// provided key.
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpValue(value)}
+}
+
+// unixNano returns the provided time in nanoseconds since the unix epoch
+// encoded as a string.
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// exportRequest returns the OTLP JSON encoded export request for the provided
+// ended spans.
+func (t *Tracer) exportRequest(spans []*Span) ([]byte, error) {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mtx.Lock()
+		span := otlpSpan{
+			TraceID:           s.traceID.String(),
+			SpanID:            s.spanID.String(),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+		}
+		if s.parent != (SpanID{}) {
+			span.ParentSpanID = s.parent.String()
+		}
+		for _, attr := range s.attrs {
+			span.Attributes = append(span.Attributes, otlpKeyValue{
+				Key:   attr.key,
+				Value: otlpValue(attr.value),
+			})
+		}
+		if s.hasError {
+			span.Status = otlpStatus{Code: statusCodeError, Message: s.errMsg}
+		}
+		s.mtx.Unlock()
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	resourceAttrs := []otlpKeyValue{otlpString("service.name",
+		t.cfg.ServiceName)}
+	if t.cfg.ServiceVersion != "" {
+		resourceAttrs = append(resourceAttrs, otlpString("service.version",
+			t.cfg.ServiceVersion))
+	}
+	req := otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: resourceAttrs},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationScope},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+	return json.Marshal(&req)
+}
+
+// export exports the provided ended spans to the configured endpoint.
+func (t *Tracer) export(ctx context.Context, spans []*Span) error {
+	body, err := t.exportRequest(spans)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		t.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %q", resp.Status)
+	}
+	return nil
+}
+
+// exportBatch exports the provided ended spans and logs any errors.
+func (t *Tracer) exportBatch(ctx context.Context, spans []*Span) {
+	if len(spans) == 0 {
+		return
+	}
+	if err := t.export(ctx, spans); err != nil {
+		log.Warnf("Failed to export %d spans: %v", len(spans), err)
+		return
+	}
+	log.Tracef("Exported %d spans", len(spans))
+}
+
+// Run exports ended spans in batches until the provided context is cancelled.
+// Any spans that are queued when the context is cancelled are exported before
+// it returns.
+func (t *Tracer) Run(ctx context.Context) {
+	log.Infof("Exporting traces to %s (sample ratio %v)", t.cfg.Endpoint,
+		t.cfg.SampleRatio)
+
+	ticker := time.NewTicker(t.cfg.ExportInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, t.cfg.BatchSize)
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) < t.cfg.BatchSize {
+				continue
+			}
+			t.exportBatch(ctx, batch)
+			batch = batch[:0]
+
+		case <-ticker.C:
+			t.exportBatch(ctx, batch)
+			batch = batch[:0]
+
+		case <-ctx.Done():
+			// Export the remaining queued spans without the cancelled context
+			// so they are not lost.
+			t.drain(batch)
+			log.Infof("Trace exporter shutdown complete")
+			return
+		}
+	}
+}
+
+// drain exports the provided ended spans along with all spans that are
+// currently queued.
+func (t *Tracer) drain(batch []*Span) {
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) == t.cfg.BatchSize {
+				t.exportBatch(context.Background(), batch)
+				batch = batch[:0]
+			}
+
+		default:
+			t.exportBatch(context.Background(), batch)
+			return
+		}
+	}
+}
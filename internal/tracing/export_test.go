@@ -0,0 +1,120 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestExport ensures queued spans are exported to the configured endpoint in
+// the OTLP JSON encoding when the tracer is stopped.
+func TestExport(t *testing.T) {
+	requests := make(chan otlpExportRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost ||
+			r.Header.Get("Content-Type") != "application/json" {
+
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var req otlpExportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests <- req
+	}))
+	defer srv.Close()
+
+	tracer := New(&Config{
+		Endpoint:       srv.URL,
+		ServiceName:    "dcrd",
+		ServiceVersion: "1.0.0",
+		SampleRatio:    1,
+		ExportInterval: time.Hour,
+	})
+	hash := chainhash.HashH([]byte("block"))
+	start := time.Unix(1700000000, 0)
+	root := tracer.StartRoot(&hash, "root", start)
+	span := root.StartChildAt("child", start)
+	span.SetAttribute("height", uint32(100))
+	span.SetAttribute("valid", false)
+	span.SetAttribute("kind", "test")
+	span.SetAttribute("ratio", 0.5)
+	span.SetError(errors.New("failed"))
+	span.EndAt(start.Add(time.Second))
+	root.EndAt(start.Add(2 * time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		tracer.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	var req otlpExportRequest
+	select {
+	case req = <-requests:
+	default:
+		t.Fatal("spans were not exported")
+	}
+	if len(req.ResourceSpans) != 1 {
+		t.Fatalf("unexpected resource spans %+v", req.ResourceSpans)
+	}
+	resource := req.ResourceSpans[0]
+	wantResourceAttrs := []otlpKeyValue{
+		otlpString("service.name", "dcrd"),
+		otlpString("service.version", "1.0.0"),
+	}
+	if !reflect.DeepEqual(resource.Resource.Attributes, wantResourceAttrs) {
+		t.Fatalf("unexpected resource attributes %+v",
+			resource.Resource.Attributes)
+	}
+	if len(resource.ScopeSpans) != 1 ||
+		len(resource.ScopeSpans[0].Spans) != 2 {
+
+		t.Fatalf("unexpected scope spans %+v", resource.ScopeSpans)
+	}
+
+	height, valid, ratio := "100", false, 0.5
+	kind := "test"
+	want := []otlpSpan{{
+		TraceID:           HashTraceID(&hash).String(),
+		SpanID:            span.ID().String(),
+		ParentSpanID:      HashRootSpanID(&hash).String(),
+		Name:              "child",
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: "1700000000000000000",
+		EndTimeUnixNano:   "1700000001000000000",
+		Attributes: []otlpKeyValue{
+			{Key: "height", Value: otlpAnyValue{IntValue: &height}},
+			{Key: "valid", Value: otlpAnyValue{BoolValue: &valid}},
+			{Key: "kind", Value: otlpAnyValue{StringValue: &kind}},
+			{Key: "ratio", Value: otlpAnyValue{DoubleValue: &ratio}},
+		},
+		Status: otlpStatus{Code: statusCodeError, Message: "failed"},
+	}, {
+		TraceID:           HashTraceID(&hash).String(),
+		SpanID:            HashRootSpanID(&hash).String(),
+		Name:              "root",
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: "1700000000000000000",
+		EndTimeUnixNano:   "1700000002000000000",
+	}}
+	if got := resource.ScopeSpans[0].Spans; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected spans\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// DefaultQueueSize is the default maximum number of ended spans that are
+	// queued for export.
+	DefaultQueueSize = 4096
+
+	// DefaultBatchSize is the default maximum number of spans that are
+	// exported in a single request.
+	DefaultBatchSize = 512
+
+	// DefaultExportInterval is the default maximum amount of time ended spans
+	// are queued before they are exported.
+	DefaultExportInterval = 5 * time.Second
+)
+
+// TraceID uniquely identifies a trace.
+type TraceID [16]byte
+
+// String returns the trace ID as a hex encoded string.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID uniquely identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the span ID as a hex encoded string.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// HashTraceID returns the ID of the trace of the block or transaction with the
+// provided hash.
+func HashTraceID(hash *chainhash.Hash) TraceID {
+	var id TraceID
+	copy(id[:], hash[:])
+	return id
+}
+
+// HashRootSpanID returns the ID of the root span of the trace of the block or
+// transaction with the provided hash.
+func HashRootSpanID(hash *chainhash.Hash) SpanID {
+	var id SpanID
+	copy(id[:], hash[:])
+	return id
+}
+
+// Config is a descriptor containing the tracer configuration.
+type Config struct {
+	// Endpoint is the URL of the OTLP/HTTP traces endpoint of the collector
+	// the spans are exported to, such as http://localhost:4318/v1/traces.
+	Endpoint string
+
+	// ServiceName and ServiceVersion identify the service that produced the
+	// spans to the collector.
+	ServiceName    string
+	ServiceVersion string
+
+	// SampleRatio is the ratio of traces that are sampled in the range
+	// [0, 1].
+	SampleRatio float64
+
+	// QueueSize is the maximum number of ended spans that are queued for
+	// export.  DefaultQueueSize is used when it is zero.
+	QueueSize int
+
+	// BatchSize is the maximum number of spans that are exported in a single
+	// request.  DefaultBatchSize is used when it is zero.
+	BatchSize int
+
+	// ExportInterval is the maximum amount of time ended spans are queued
+	// before they are exported.  DefaultExportInterval is used when it is
+	// zero.
+	ExportInterval time.Duration
+}
+
+// Tracer creates spans for sampled traces and exports them once they are
+// ended.  A nil tracer is valid and does not create any spans.
+type Tracer struct {
+	cfg Config
+
+	// sampleAll is set when all traces are sampled and sampleBound is the
+	// exclusive upper bound of the portion of the trace IDs that are sampled
+	// otherwise.
+	sampleAll   bool
+	sampleBound uint64
+
+	queue   chan *Span
+	dropped uint64 // Updated atomically.
+}
+
+// New returns a new tracer with the provided config.  The spans it creates are
+// only exported while Run is running.
+func New(cfg *Config) *Tracer {
+	t := &Tracer{cfg: *cfg}
+	if t.cfg.QueueSize == 0 {
+		t.cfg.QueueSize = DefaultQueueSize
+	}
+	if t.cfg.BatchSize == 0 {
+		t.cfg.BatchSize = DefaultBatchSize
+	}
+	if t.cfg.ExportInterval == 0 {
+		t.cfg.ExportInterval = DefaultExportInterval
+	}
+	switch ratio := t.cfg.SampleRatio; {
+	case ratio >= 1:
+		t.sampleAll = true
+	case ratio > 0:
+		t.sampleBound = uint64(ratio * math.Exp2(64))
+	}
+	t.queue = make(chan *Span, t.cfg.QueueSize)
+	return t
+}
+
+// Sampled returns whether or not the trace with the provided ID is sampled.
+// The decision only depends on the trace ID, so it is the same for all spans
+// of a trace.
+//
+// This function is safe for concurrent access.
+func (t *Tracer) Sampled(id TraceID) bool {
+	if t == nil {
+		return false
+	}
+	if t.sampleAll {
+		return true
+	}
+	return binary.BigEndian.Uint64(id[8:]) < t.sampleBound
+}
+
+// Dropped returns the number of ended spans that were dropped because the
+// export queue was full.
+//
+// This function is safe for concurrent access.
+func (t *Tracer) Dropped() uint64 {
+	if t == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&t.dropped)
+}
+
+// startSpan returns a new span with the provided details or nil when the trace
+// is not sampled.  A random span ID is generated when the provided one is
+// zero.
+func (t *Tracer) startSpan(trace TraceID, id, parent SpanID, name string, start time.Time) *Span {
+	if !t.Sampled(trace) {
+		return nil
+	}
+	if id == (SpanID{}) {
+		// Reading random bytes never fails on supported platforms, but
+		// ensure the span ID is not zero regardless since that would make it
+		// invalid.
+		if _, err := rand.Read(id[:]); err != nil || id == (SpanID{}) {
+			id[0] = 1
+		}
+	}
+	return &Span{
+		tracer:  t,
+		traceID: trace,
+		spanID:  id,
+		parent:  parent,
+		name:    name,
+		start:   start,
+	}
+}
+
+// StartRoot returns the root span of the trace of the block or transaction
+// with the provided hash that starts at the provided time.  It returns nil when
+// the trace is not sampled.
+//
+// This function is safe for concurrent access.
+func (t *Tracer) StartRoot(hash *chainhash.Hash, name string, start time.Time) *Span {
+	return t.startSpan(HashTraceID(hash), HashRootSpanID(hash), SpanID{},
+		name, start)
+}
+
+// Start returns a new span that is a child of the root span of the trace of
+// the block or transaction with the provided hash.  It returns nil when the
+// trace is not sampled.
+//
+// This function is safe for concurrent access.
+func (t *Tracer) Start(hash *chainhash.Hash, name string) *Span {
+	return t.startSpan(HashTraceID(hash), SpanID{}, HashRootSpanID(hash),
+		name, time.Now())
+}
+
+// enqueue queues the provided ended span for export or drops it when the
+// queue is full.
+func (t *Tracer) enqueue(s *Span) {
+	select {
+	case t.queue <- s:
+	default:
+		if atomic.AddUint64(&t.dropped, 1) == 1 {
+			log.Warnf("Dropping spans since the export queue is full")
+		}
+	}
+}
+
+// attribute is a key-value pair that describes a span.
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+// Span represents a single operation within a trace.  A nil span is valid and
+// ignores all method calls, which allows callers to avoid checking whether or
+// not the trace is sampled.
+type Span struct {
+	tracer  *Tracer
+	traceID TraceID
+	spanID  SpanID
+	parent  SpanID
+	name    string
+	start   time.Time
+
+	mtx      sync.Mutex
+	end      time.Time
+	attrs    []attribute
+	errMsg   string
+	hasError bool
+	ended    bool
+}
+
+// ID returns the ID of the span.  It returns a zero ID for a nil span.
+func (s *Span) ID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.spanID
+}
+
+// StartChild returns a new span that is a child of the span.  It returns nil
+// for a nil span.
+//
+// This function is safe for concurrent access.
+func (s *Span) StartChild(name string) *Span {
+	return s.StartChildAt(name, time.Now())
+}
+
+// StartChildAt returns a new span that is a child of the span and starts at
+// the provided time.  It returns nil for a nil span.
+//
+// This function is safe for concurrent access.
+func (s *Span) StartChildAt(name string, start time.Time) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.startSpan(s.traceID, SpanID{}, s.spanID, name, start)
+}
+
+// SetAttribute sets an attribute that describes the span.  Supported values
+// are strings, booleans, integers, and floats.  Other values are converted to
+// strings.
+//
+// This function is safe for concurrent access.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	switch v := value.(type) {
+	case string, bool, int64, float64:
+	case int:
+		value = int64(v)
+	case int32:
+		value = int64(v)
+	case uint32:
+		value = int64(v)
+	case uint64:
+		value = int64(v)
+	case float32:
+		value = float64(v)
+	default:
+		value = fmt.Sprint(v)
+	}
+	s.mtx.Lock()
+	s.attrs = append(s.attrs, attribute{key: key, value: value})
+	s.mtx.Unlock()
+}
+
+// SetError marks the span as failed with the provided error when it is not
+// nil.
+//
+// This function is safe for concurrent access.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mtx.Lock()
+	s.hasError = true
+	s.errMsg = err.Error()
+	s.mtx.Unlock()
+}
+
+// End ends the span and queues it for export.  Calls after the first one have
+// no effect.
+//
+// This function is safe for concurrent access.
+func (s *Span) End() {
+	s.EndAt(time.Now())
+}
+
+// EndAt ends the span at the provided time and queues it for export.  Calls
+// after the first one have no effect.
+//
+// This function is safe for concurrent access.
+func (s *Span) EndAt(end time.Time) {
+	if s == nil {
+		return
+	}
+	s.mtx.Lock()
+	if s.ended {
+		s.mtx.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = end
+	s.mtx.Unlock()
+	s.tracer.enqueue(s)
+}
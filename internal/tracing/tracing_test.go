@@ -0,0 +1,128 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestNilTracer ensures nil tracers and spans are valid and do not create any
+// spans.
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	hash := chainhash.HashH([]byte("block"))
+	if tracer.Sampled(HashTraceID(&hash)) {
+		t.Fatal("nil tracer sampled trace")
+	}
+	root := tracer.StartRoot(&hash, "root", time.Now())
+	if root != nil {
+		t.Fatalf("nil tracer created root span %v", root)
+	}
+	span := tracer.Start(&hash, "span")
+	if span != nil {
+		t.Fatalf("nil tracer created span %v", span)
+	}
+
+	// None of these should panic.
+	child := span.StartChild("child")
+	if child != nil {
+		t.Fatalf("nil span created child span %v", child)
+	}
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("failed"))
+	span.End()
+	if id := span.ID(); id != (SpanID{}) {
+		t.Fatalf("nil span has ID %v", id)
+	}
+	if dropped := tracer.Dropped(); dropped != 0 {
+		t.Fatalf("nil tracer dropped %d spans", dropped)
+	}
+}
+
+// TestSampling ensures traces are sampled according to the configured ratio
+// and the decision is consistent for a given trace.
+func TestSampling(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float64
+		min   int
+		max   int
+	}{
+		{name: "none", ratio: 0, min: 0, max: 0},
+		{name: "negative", ratio: -1, min: 0, max: 0},
+		{name: "quarter", ratio: 0.25, min: 200, max: 300},
+		{name: "all", ratio: 1, min: 1000, max: 1000},
+		{name: "above all", ratio: 2, min: 1000, max: 1000},
+	}
+
+	for _, test := range tests {
+		tracer := New(&Config{SampleRatio: test.ratio})
+		var sampled int
+		for i := 0; i < 1000; i++ {
+			hash := chainhash.HashH([]byte{byte(i), byte(i >> 8)})
+			id := HashTraceID(&hash)
+			isSampled := tracer.Sampled(id)
+			if isSampled != tracer.Sampled(id) {
+				t.Fatalf("%q: inconsistent sampling decision", test.name)
+			}
+			if isSampled {
+				sampled++
+			}
+		}
+		if sampled < test.min || sampled > test.max {
+			t.Fatalf("%q: unexpected number of sampled traces -- got %d, "+
+				"want [%d, %d]", test.name, sampled, test.min, test.max)
+		}
+	}
+}
+
+// TestSpans ensures spans are created with the expected IDs and parents, are
+// only queued for export once, and are dropped when the queue is full.
+func TestSpans(t *testing.T) {
+	tracer := New(&Config{SampleRatio: 1, QueueSize: 3})
+	hash := chainhash.HashH([]byte("block"))
+	traceID, rootID := HashTraceID(&hash), HashRootSpanID(&hash)
+
+	root := tracer.StartRoot(&hash, "root", time.Now())
+	if root.traceID != traceID || root.spanID != rootID ||
+		root.parent != (SpanID{}) {
+
+		t.Fatalf("unexpected root span IDs %v %v %v", root.traceID,
+			root.spanID, root.parent)
+	}
+	span := tracer.Start(&hash, "span")
+	if span.traceID != traceID || span.parent != rootID ||
+		span.spanID == (SpanID{}) || span.spanID == rootID {
+
+		t.Fatalf("unexpected span IDs %v %v %v", span.traceID, span.spanID,
+			span.parent)
+	}
+	child := span.StartChild("child")
+	if child.traceID != traceID || child.parent != span.ID() {
+		t.Fatalf("unexpected child span IDs %v %v", child.traceID,
+			child.parent)
+	}
+
+	// Ensure ending a span more than once only queues it once.
+	child.End()
+	child.End()
+	span.End()
+	root.End()
+	if len(tracer.queue) != 3 {
+		t.Fatalf("unexpected number of queued spans -- got %d, want 3",
+			len(tracer.queue))
+	}
+
+	// Ensure spans are dropped when the queue is full.
+	tracer.Start(&hash, "dropped").End()
+	if dropped := tracer.Dropped(); dropped != 1 {
+		t.Fatalf("unexpected number of dropped spans -- got %d, want 1",
+			dropped)
+	}
+}
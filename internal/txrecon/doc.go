@@ -0,0 +1,43 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package txrecon implements sketch-based transaction set reconciliation between
+peers.
+
+Rather than announcing every transaction to every peer via inventory messages,
+peers that both support reconciliation accumulate the transactions they intend
+to announce to each other into per-peer sets and periodically reconcile them.
+Only the transactions in the set difference are then announced, which
+dramatically reduces the announcement bandwidth for well-connected nodes since
+most transactions are learned from another peer before they would otherwise be
+announced.
+
+Transactions are identified during reconciliation by 32-bit short ids which are
+keyed by salts contributed by both peers to prevent an attacker from
+precomputing collisions.
+
+The sketch is an invertible Bloom lookup table that encodes a set of short ids
+in space proportional to the expected size of the set difference rather than
+the size of the set itself.  Subtracting two sketches of the same size yields a
+sketch of the symmetric difference between the encoded sets which can be
+decoded so long as the difference does not exceed the capacity of the sketch.
+
+A reconciliation round proceeds as follows:
+
+ 1. The initiator, which is the peer that made the outbound connection, sends
+    a reqrecon message with the size of its set.
+ 2. The responder replies with a sketch message encoding its set, sized
+    according to the estimated difference between the sets.
+ 3. The initiator subtracts a sketch of its own set and decodes the difference.
+    It then announces the transactions the responder is missing via inventory
+    messages and sends a reconcildiff message with the short ids of the
+    transactions it is missing.
+ 4. The responder announces the requested transactions via inventory messages.
+
+When the difference can't be decoded, the initiator sends a reconcildiff
+message that indicates failure and both peers fall back to announcing their
+entire sets via inventory messages.
+*/
+package txrecon
@@ -0,0 +1,347 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// Version is the transaction reconciliation protocol version implemented
+	// by this package.
+	Version = 1
+
+	// MaxSetSize is the maximum number of transactions that are tracked for
+	// announcement to a single peer via reconciliation.  Transactions beyond
+	// this limit must be announced via inventory messages instead.
+	MaxSetSize = 3000
+
+	// defaultQ is the default coefficient used to estimate the size of the
+	// set difference prior to the first successful reconciliation.
+	defaultQ = 0.25
+
+	// qScale is the scale factor used to encode the coefficient used to
+	// estimate the size of the set difference on the wire.
+	qScale = 1 << 16
+
+	// requestTimeout is the amount of time the initiator waits for a sketch
+	// in response to a reconciliation request before it allows another
+	// request to be made.
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	// ErrUnexpectedMessage indicates a reconciliation message was received
+	// that is not valid for the current role or state of the reconciliation
+	// with the peer.
+	ErrUnexpectedMessage = errors.New("unexpected reconciliation message")
+)
+
+// PeerState houses the state of transaction reconciliation with a single peer.
+// The peer that made the outbound connection is the initiator and requests
+// reconciliation rounds while the other peer is the responder.
+//
+// It is safe for concurrent access.
+type PeerState struct {
+	// These fields are set at creation time and never modified, so they do
+	// not need to be protected by the mutex.
+	k0, k1      uint64
+	isInitiator bool
+
+	mtx sync.Mutex
+
+	// localSet houses the transactions to announce to the peer keyed by
+	// their short id.  localIDs maps the transactions back to their short
+	// ids.
+	localSet map[uint32]chainhash.Hash
+	localIDs map[chainhash.Hash]uint32
+
+	// q is the coefficient used to estimate the size of the set difference.
+	// It is only updated by the initiator.
+	q float64
+
+	// requestedAt is the time the initiator last requested a sketch.  It is
+	// the zero time when there is no outstanding request.
+	requestedAt time.Time
+
+	// snapshot houses the transactions the responder included in the most
+	// recent sketch it sent while it waits for the initiator to respond with
+	// the difference.  It is nil when there is no outstanding sketch.
+	snapshot map[uint32]chainhash.Hash
+}
+
+// NewPeerState returns a new reconciliation state for a peer given the salt
+// contributed by the local peer, the salt contributed by the remote peer, and
+// whether or not the local peer is the initiator.
+func NewPeerState(localSalt, remoteSalt uint64, isInitiator bool) *PeerState {
+	k0, k1 := shortIDKeys(localSalt, remoteSalt)
+	return &PeerState{
+		k0:          k0,
+		k1:          k1,
+		isInitiator: isInitiator,
+		localSet:    make(map[uint32]chainhash.Hash),
+		localIDs:    make(map[chainhash.Hash]uint32),
+		q:           defaultQ,
+	}
+}
+
+// IsInitiator returns whether or not the local peer is the initiator of
+// reconciliation rounds with the peer.
+func (s *PeerState) IsInitiator() bool {
+	return s.isInitiator
+}
+
+// ShortID returns the short id of the provided transaction hash for the peer.
+func (s *PeerState) ShortID(txHash *chainhash.Hash) uint32 {
+	return shortID(s.k0, s.k1, txHash)
+}
+
+// AddTx adds the provided transaction to the set of transactions to announce
+// to the peer via reconciliation.  It returns false when the transaction can't
+// be added because the set is full or its short id collides with another
+// transaction in the set, in which case the caller must announce it via an
+// inventory message instead.
+func (s *PeerState) AddTx(txHash *chainhash.Hash) bool {
+	id := s.ShortID(txHash)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.localIDs[*txHash]; ok {
+		return true
+	}
+	if _, ok := s.localSet[id]; ok || len(s.localSet) >= MaxSetSize {
+		return false
+	}
+	s.localSet[id] = *txHash
+	s.localIDs[*txHash] = id
+	return true
+}
+
+// RemoveTx removes the provided transaction from the set of transactions to
+// announce to the peer.  It should be called when the peer is already known to
+// have the transaction, such as when the peer announces or sends it.
+func (s *PeerState) RemoveTx(txHash *chainhash.Hash) {
+	s.mtx.Lock()
+	if id, ok := s.localIDs[*txHash]; ok {
+		delete(s.localSet, id)
+		delete(s.localIDs, *txHash)
+	}
+	s.mtx.Unlock()
+}
+
+// SetSize returns the number of transactions to announce to the peer via
+// reconciliation.
+func (s *PeerState) SetSize() int {
+	s.mtx.Lock()
+	size := len(s.localSet)
+	s.mtx.Unlock()
+	return size
+}
+
+// drainLocalSet returns the hashes of all transactions in the set of
+// transactions to announce to the peer and resets the set.
+//
+// This function MUST be called with the mutex held.
+func (s *PeerState) drainLocalSet() []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, len(s.localSet))
+	for _, hash := range s.localSet {
+		hashes = append(hashes, hash)
+	}
+	s.localSet = make(map[uint32]chainhash.Hash)
+	s.localIDs = make(map[chainhash.Hash]uint32)
+	return hashes
+}
+
+// InitiateReconciliation returns a reqrecon message to request a sketch from
+// the peer.  It returns nil when the local peer is not the initiator or there
+// is already an outstanding request that has not timed out.
+func (s *PeerState) InitiateReconciliation() *wire.MsgReqRecon {
+	if !s.isInitiator {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	if !s.requestedAt.IsZero() && now.Sub(s.requestedAt) < requestTimeout {
+		return nil
+	}
+	s.requestedAt = now
+	q := uint16(math.Min(s.q*qScale, math.MaxUint16))
+	return wire.NewMsgReqRecon(uint32(len(s.localSet)), q)
+}
+
+// ProcessSketch processes a sketch sent by the peer in response to a previous
+// reconciliation request.  It returns the reconcildiff message to send to the
+// peer along with the hashes of the transactions the peer is missing that must
+// be announced to it via inventory messages.
+//
+// When the set difference can't be decoded, the returned message indicates
+// failure and all of the transactions in the set are returned for announcement
+// so that both peers fall back to flooding.
+//
+// ErrUnexpectedMessage is returned when the local peer is not the initiator or
+// there is no outstanding request while ErrMalformedSketch is returned when the
+// sketch is invalid.
+func (s *PeerState) ProcessSketch(msg *wire.MsgSketch) (*wire.MsgReconcilDiff, []chainhash.Hash, error) {
+	if !s.isInitiator {
+		return nil, nil, fmt.Errorf("%w: sketch sent to responder",
+			ErrUnexpectedMessage)
+	}
+	remoteSketch, err := ParseSketch(msg.Sketch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.requestedAt.IsZero() {
+		return nil, nil, fmt.Errorf("%w: unrequested sketch",
+			ErrUnexpectedMessage)
+	}
+	s.requestedAt = time.Time{}
+
+	// Subtract the remote sketch from a sketch of the local set and decode
+	// the difference.  Fall back to flooding the entire set when the
+	// difference can't be decoded.
+	localSketch := newSketchWithCells(remoteSketch.NumCells())
+	for id := range s.localSet {
+		localSketch.Add(id)
+	}
+	if err := localSketch.Subtract(remoteSketch); err != nil {
+		return nil, nil, err
+	}
+	localOnly, remoteOnly, ok := localSketch.Decode()
+	if !ok || len(remoteOnly) > wire.MaxReconcilDiffShortIDs {
+		return wire.NewMsgReconcilDiff(false), s.drainLocalSet(), nil
+	}
+
+	// Update the coefficient used to estimate the size of future set
+	// differences based on the actual difference.
+	localSize := len(s.localSet)
+	remoteSize := localSize - len(localOnly) + len(remoteOnly)
+	minSize, sizeDiff := localSize, remoteSize-localSize
+	if remoteSize < minSize {
+		minSize = remoteSize
+	}
+	if sizeDiff < 0 {
+		sizeDiff = -sizeDiff
+	}
+	if minSize > 0 {
+		diff := len(localOnly) + len(remoteOnly)
+		s.q = math.Max(0, float64(diff-sizeDiff)/float64(minSize))
+	}
+
+	diffMsg := wire.NewMsgReconcilDiff(true)
+	diffMsg.ShortIDs = append(diffMsg.ShortIDs, remoteOnly...)
+	announce := make([]chainhash.Hash, 0, len(localOnly))
+	for _, id := range localOnly {
+		if hash, ok := s.localSet[id]; ok {
+			announce = append(announce, hash)
+		}
+	}
+	s.drainLocalSet()
+	return diffMsg, announce, nil
+}
+
+// RespondToReqRecon returns a sketch of the set of transactions to announce to
+// the peer in response to the passed reconciliation request.  The set is moved
+// to a snapshot that is used to respond to the subsequent reconcildiff message.
+//
+// A request that arrives while there is already an outstanding sketch means the
+// initiator abandoned the previous round, so the snapshot from that round is
+// merged back into the set prior to creating the new sketch.
+//
+// ErrUnexpectedMessage is returned when the local peer is the initiator.
+func (s *PeerState) RespondToReqRecon(msg *wire.MsgReqRecon) (*wire.MsgSketch, error) {
+	if s.isInitiator {
+		return nil, fmt.Errorf("%w: reconciliation request sent to "+
+			"initiator", ErrUnexpectedMessage)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for id, hash := range s.snapshot {
+		if _, ok := s.localSet[id]; !ok {
+			s.localSet[id] = hash
+			s.localIDs[hash] = id
+		}
+	}
+	s.snapshot = nil
+
+	// Estimate the size of the set difference from the set sizes and the
+	// coefficient provided by the initiator.
+	localSize, remoteSize := len(s.localSet), int(msg.SetSize)
+	minSize, sizeDiff := localSize, remoteSize-localSize
+	if remoteSize < minSize {
+		minSize = remoteSize
+	}
+	if sizeDiff < 0 {
+		sizeDiff = -sizeDiff
+	}
+	q := float64(msg.Q) / qScale
+	capacity := sizeDiff + int(q*float64(minSize)) + 1
+
+	sketch := NewSketch(capacity)
+	for id := range s.localSet {
+		sketch.Add(id)
+	}
+	s.snapshot = s.localSet
+	s.localSet = make(map[uint32]chainhash.Hash)
+	s.localIDs = make(map[chainhash.Hash]uint32)
+	return wire.NewMsgSketch(sketch.Bytes()), nil
+}
+
+// ProcessReconcilDiff processes the result of a reconciliation round sent by
+// the initiator in response to a previously sent sketch.  It returns the hashes
+// of the transactions that must be announced to the peer via inventory
+// messages.  That is the requested transactions when the round succeeded or
+// the entire snapshot when it failed.
+//
+// ErrUnexpectedMessage is returned when the local peer is the initiator or
+// there is no outstanding sketch.
+func (s *PeerState) ProcessReconcilDiff(msg *wire.MsgReconcilDiff) ([]chainhash.Hash, error) {
+	if s.isInitiator {
+		return nil, fmt.Errorf("%w: reconciliation difference sent to "+
+			"initiator", ErrUnexpectedMessage)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.snapshot == nil {
+		return nil, fmt.Errorf("%w: unrequested reconciliation difference",
+			ErrUnexpectedMessage)
+	}
+	snapshot := s.snapshot
+	s.snapshot = nil
+
+	if !msg.Success {
+		hashes := make([]chainhash.Hash, 0, len(snapshot))
+		for _, hash := range snapshot {
+			hashes = append(hashes, hash)
+		}
+		return hashes, nil
+	}
+
+	hashes := make([]chainhash.Hash, 0, len(msg.ShortIDs))
+	for _, id := range msg.ShortIDs {
+		if hash, ok := snapshot[id]; ok {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
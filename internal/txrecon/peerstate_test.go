@@ -0,0 +1,232 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// sortedHashes returns a sorted copy of the provided hashes.
+func sortedHashes(hashes []chainhash.Hash) []chainhash.Hash {
+	sorted := make([]chainhash.Hash, len(hashes))
+	copy(sorted, hashes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
+
+// equalHashes returns whether or not the provided hashes contain the same
+// elements regardless of order.
+func equalHashes(a, b []chainhash.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortedHashes(a), sortedHashes(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// makeHashes returns the requested number of unique hashes starting from the
+// provided seed.
+func makeHashes(seed, n int) []chainhash.Hash {
+	hashes := make([]chainhash.Hash, 0, n)
+	for i := 0; i < n; i++ {
+		hashes = append(hashes, chainhash.HashH([]byte{byte(seed),
+			byte(i), byte(i >> 8)}))
+	}
+	return hashes
+}
+
+// TestReconciliationRound ensures a full reconciliation round between an
+// initiator and a responder results in each side announcing exactly the
+// transactions the other side is missing.
+func TestReconciliationRound(t *testing.T) {
+	const initiatorSalt, responderSalt = 0x0123456789abcdef, 0xfedcba9876543210
+	initiator := NewPeerState(initiatorSalt, responderSalt, true)
+	responder := NewPeerState(responderSalt, initiatorSalt, false)
+
+	// Ensure both sides derive the same short ids.
+	hash := chainhash.HashH([]byte("tx"))
+	if initiator.ShortID(&hash) != responder.ShortID(&hash) {
+		t.Fatal("mismatched short ids")
+	}
+
+	shared := makeHashes(0, 200)
+	initiatorOnly := makeHashes(1, 10)
+	responderOnly := makeHashes(2, 15)
+	for i := range shared {
+		initiator.AddTx(&shared[i])
+		responder.AddTx(&shared[i])
+	}
+	for i := range initiatorOnly {
+		initiator.AddTx(&initiatorOnly[i])
+	}
+	for i := range responderOnly {
+		responder.AddTx(&responderOnly[i])
+	}
+
+	// Ensure removed transactions are not reconciled.
+	removed := makeHashes(3, 1)[0]
+	initiator.AddTx(&removed)
+	initiator.RemoveTx(&removed)
+	if got, want := initiator.SetSize(), len(shared)+len(initiatorOnly); got != want {
+		t.Fatalf("unexpected set size -- got %d, want %d", got, want)
+	}
+
+	// Ensure only the initiator can request reconciliation and that only a
+	// single request may be outstanding.
+	if responder.InitiateReconciliation() != nil {
+		t.Fatal("responder initiated reconciliation")
+	}
+	reqMsg := initiator.InitiateReconciliation()
+	if reqMsg == nil {
+		t.Fatal("initiator did not initiate reconciliation")
+	}
+	if initiator.InitiateReconciliation() != nil {
+		t.Fatal("initiator initiated multiple reconciliation rounds")
+	}
+
+	sketchMsg, err := responder.RespondToReqRecon(reqMsg)
+	if err != nil {
+		t.Fatalf("unexpected error responding to request: %v", err)
+	}
+	if responder.SetSize() != 0 {
+		t.Fatal("responder set not moved to snapshot")
+	}
+	diffMsg, initiatorAnnounce, err := initiator.ProcessSketch(sketchMsg)
+	if err != nil {
+		t.Fatalf("unexpected error processing sketch: %v", err)
+	}
+	if !diffMsg.Success {
+		t.Fatal("reconciliation unexpectedly failed")
+	}
+	if !equalHashes(initiatorAnnounce, initiatorOnly) {
+		t.Fatalf("unexpected initiator announcements -- got %d txns, want "+
+			"%d txns", len(initiatorAnnounce), len(initiatorOnly))
+	}
+	responderAnnounce, err := responder.ProcessReconcilDiff(diffMsg)
+	if err != nil {
+		t.Fatalf("unexpected error processing difference: %v", err)
+	}
+	if !equalHashes(responderAnnounce, responderOnly) {
+		t.Fatalf("unexpected responder announcements -- got %d txns, want "+
+			"%d txns", len(responderAnnounce), len(responderOnly))
+	}
+	if initiator.SetSize() != 0 {
+		t.Fatal("initiator set not cleared")
+	}
+
+	// Ensure an abandoned round results in the snapshot being merged back
+	// into the set for the next round.
+	responder.AddTx(&removed)
+	if _, err := responder.RespondToReqRecon(reqMsg); err != nil {
+		t.Fatalf("unexpected error responding to request: %v", err)
+	}
+	newHash := makeHashes(4, 1)[0]
+	responder.AddTx(&newHash)
+	if _, err := responder.RespondToReqRecon(reqMsg); err != nil {
+		t.Fatalf("unexpected error responding to request: %v", err)
+	}
+	responderAnnounce, err = responder.ProcessReconcilDiff(wire.NewMsgReconcilDiff(false))
+	if err != nil {
+		t.Fatalf("unexpected error processing difference: %v", err)
+	}
+	want := []chainhash.Hash{removed, newHash}
+	if !equalHashes(responderAnnounce, want) {
+		t.Fatalf("unexpected responder announcements -- got %d txns, want "+
+			"%d txns", len(responderAnnounce), len(want))
+	}
+
+	// Ensure messages that are not valid for the current state are rejected.
+	if _, _, err := initiator.ProcessSketch(sketchMsg); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("unexpected error for unrequested sketch -- got %v, want %v",
+			err, ErrUnexpectedMessage)
+	}
+	if _, err := responder.ProcessReconcilDiff(diffMsg); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("unexpected error for unrequested difference -- got %v, "+
+			"want %v", err, ErrUnexpectedMessage)
+	}
+	if _, err := initiator.RespondToReqRecon(reqMsg); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("unexpected error for request to initiator -- got %v, "+
+			"want %v", err, ErrUnexpectedMessage)
+	}
+	if _, _, err := responder.ProcessSketch(sketchMsg); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("unexpected error for sketch to responder -- got %v, want %v",
+			err, ErrUnexpectedMessage)
+	}
+}
+
+// TestReconciliationFallback ensures both sides fall back to announcing their
+// entire sets when the set difference can't be decoded.
+func TestReconciliationFallback(t *testing.T) {
+	initiator := NewPeerState(1, 2, true)
+	responder := NewPeerState(2, 1, false)
+
+	// Populate the initiator with a large set and request reconciliation
+	// while claiming an empty set so the sketch is undersized.
+	initiatorOnly := makeHashes(1, 500)
+	responderOnly := makeHashes(2, 5)
+	for i := range initiatorOnly {
+		initiator.AddTx(&initiatorOnly[i])
+	}
+	for i := range responderOnly {
+		responder.AddTx(&responderOnly[i])
+	}
+	if initiator.InitiateReconciliation() == nil {
+		t.Fatal("initiator did not initiate reconciliation")
+	}
+	sketchMsg, err := responder.RespondToReqRecon(wire.NewMsgReqRecon(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error responding to request: %v", err)
+	}
+	diffMsg, initiatorAnnounce, err := initiator.ProcessSketch(sketchMsg)
+	if err != nil {
+		t.Fatalf("unexpected error processing sketch: %v", err)
+	}
+	if diffMsg.Success {
+		t.Fatal("reconciliation unexpectedly succeeded")
+	}
+	if !equalHashes(initiatorAnnounce, initiatorOnly) {
+		t.Fatalf("unexpected initiator announcements -- got %d txns, want "+
+			"%d txns", len(initiatorAnnounce), len(initiatorOnly))
+	}
+	responderAnnounce, err := responder.ProcessReconcilDiff(diffMsg)
+	if err != nil {
+		t.Fatalf("unexpected error processing difference: %v", err)
+	}
+	if !equalHashes(responderAnnounce, responderOnly) {
+		t.Fatalf("unexpected responder announcements -- got %d txns, want "+
+			"%d txns", len(responderAnnounce), len(responderOnly))
+	}
+}
+
+// TestAddTxLimits ensures transactions are rejected once the set is full.
+func TestAddTxLimits(t *testing.T) {
+	state := NewPeerState(1, 2, false)
+	hashes := makeHashes(0, MaxSetSize+1)
+	for i := 0; i < MaxSetSize; i++ {
+		if !state.AddTx(&hashes[i]) {
+			t.Fatalf("failed to add tx %d", i)
+		}
+	}
+	if state.AddTx(&hashes[MaxSetSize]) {
+		t.Fatal("added tx to full set")
+	}
+
+	// Ensure adding a transaction already in the set succeeds even when full.
+	if !state.AddTx(&hashes[0]) {
+		t.Fatal("failed to add tx already in set")
+	}
+}
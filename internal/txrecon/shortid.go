@@ -0,0 +1,37 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"encoding/binary"
+
+	"github.com/dchest/siphash"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// shortIDKeyTag is the tag that is prepended to the salts when deriving the
+// short id keys for a peer.
+const shortIDKeyTag = "txrecon shortid"
+
+// shortIDKeys derives the keys used to compute transaction short ids from the
+// salts contributed by both peers.  The salts are ordered so both peers derive
+// the same keys.
+func shortIDKeys(salt1, salt2 uint64) (uint64, uint64) {
+	if salt1 > salt2 {
+		salt1, salt2 = salt2, salt1
+	}
+	var b [len(shortIDKeyTag) + 16]byte
+	copy(b[:], shortIDKeyTag)
+	binary.LittleEndian.PutUint64(b[len(shortIDKeyTag):], salt1)
+	binary.LittleEndian.PutUint64(b[len(shortIDKeyTag)+8:], salt2)
+	h := chainhash.HashH(b[:])
+	return binary.LittleEndian.Uint64(h[0:8]), binary.LittleEndian.Uint64(h[8:16])
+}
+
+// shortID returns the short id for the provided transaction hash using the
+// passed keys.
+func shortID(k0, k1 uint64, txHash *chainhash.Hash) uint32 {
+	return uint32(siphash.Hash(k0, k1, txHash[:]))
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// numCellHashes is the number of cells each short id is stored in.  The
+	// cells of a sketch are partitioned into this many equally-sized
+	// subtables and each short id is stored in exactly one cell of each
+	// subtable.
+	numCellHashes = 3
+
+	// cellSize is the number of bytes a serialized cell occupies.
+	cellSize = 12
+
+	// MinSketchCells is the minimum number of cells a sketch may have.
+	MinSketchCells = 4 * numCellHashes
+
+	// MaxSketchCells is the maximum number of cells a sketch may have.  It is
+	// the largest multiple of the number of cell hashes that fits within the
+	// maximum sketch size allowed by the wire protocol.
+	MaxSketchCells = wire.MaxSketchSize / cellSize / numCellHashes *
+		numCellHashes
+)
+
+// ErrMalformedSketch indicates a serialized sketch is not valid.
+var ErrMalformedSketch = errors.New("malformed sketch")
+
+// cell is a single cell of a sketch.  It houses the number of short ids stored
+// in the cell along with the XOR of all of the short ids and the XOR of their
+// checksums.  A cell with a count of one (or negative one in the case of a
+// difference) and a checksum that matches its id sum is said to be pure and
+// its id sum is a short id in the encoded set.
+type cell struct {
+	count   int32
+	idSum   uint32
+	hashSum uint32
+}
+
+// Sketch is an invertible Bloom lookup table that encodes a set of transaction
+// short ids.  See the package documentation for details.
+type Sketch struct {
+	cells []cell
+}
+
+// mix64 returns a well-distributed 64-bit hash of the provided value.  It is
+// the finalizer from the splitmix64 pseudorandom number generator.
+func mix64(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xbf58476d1ce4e5b9
+	v ^= v >> 27
+	v *= 0x94d049bb133111eb
+	v ^= v >> 31
+	return v
+}
+
+// checksum returns the checksum for the provided short id that is used to
+// determine whether or not a cell is pure.
+func checksum(id uint32) uint32 {
+	return uint32(mix64(uint64(id) ^ 0x9e3779b97f4a7c15))
+}
+
+// cellsForCapacity returns the number of cells a sketch needs in order to
+// decode a set difference of up to the provided capacity with high
+// probability.  The result is always a multiple of the number of cell hashes
+// and is clamped to the minimum and maximum allowed number of cells.
+func cellsForCapacity(capacity int) int {
+	if capacity < 0 {
+		capacity = 0
+	}
+	numCells := capacity + capacity/2 + 2*numCellHashes
+	numCells = (numCells + numCellHashes - 1) / numCellHashes * numCellHashes
+	if numCells < MinSketchCells {
+		return MinSketchCells
+	}
+	if numCells > MaxSketchCells {
+		return MaxSketchCells
+	}
+	return numCells
+}
+
+// NewSketch returns a new empty sketch sized to be able to decode a set
+// difference of up to the provided capacity with high probability.
+func NewSketch(capacity int) *Sketch {
+	return &Sketch{cells: make([]cell, cellsForCapacity(capacity))}
+}
+
+// NumCells returns the number of cells in the sketch.
+func (s *Sketch) NumCells() int {
+	return len(s.cells)
+}
+
+// cellIndex returns the index of the cell in the provided subtable the passed
+// short id is stored in.
+func (s *Sketch) cellIndex(id uint32, subtable int) int {
+	subtableSize := len(s.cells) / numCellHashes
+	h := mix64(uint64(id) | uint64(subtable+1)<<32)
+	return subtable*subtableSize + int(h%uint64(subtableSize))
+}
+
+// update adds the passed short id to, or removes it from when the count delta
+// is negative, all of the cells it is stored in.
+func (s *Sketch) update(id uint32, countDelta int32) {
+	idHash := checksum(id)
+	for i := 0; i < numCellHashes; i++ {
+		c := &s.cells[s.cellIndex(id, i)]
+		c.count += countDelta
+		c.idSum ^= id
+		c.hashSum ^= idHash
+	}
+}
+
+// Add adds the passed short id to the sketch.
+func (s *Sketch) Add(id uint32) {
+	s.update(id, 1)
+}
+
+// Subtract removes all of the short ids encoded by the passed sketch from the
+// receiver so that it encodes the symmetric difference of the two sets.  Both
+// sketches must have the same number of cells.
+func (s *Sketch) Subtract(other *Sketch) error {
+	if len(s.cells) != len(other.cells) {
+		return fmt.Errorf("unable to subtract sketch with %d cells from "+
+			"sketch with %d cells", len(other.cells), len(s.cells))
+	}
+	for i := range s.cells {
+		s.cells[i].count -= other.cells[i].count
+		s.cells[i].idSum ^= other.cells[i].idSum
+		s.cells[i].hashSum ^= other.cells[i].hashSum
+	}
+	return nil
+}
+
+// Decode attempts to recover the short ids encoded by a sketch that was the
+// result of subtracting another sketch.  It returns the short ids that were
+// only in the receiver prior to subtracting and the short ids that were only in
+// the subtracted sketch.  The final return value is false when the difference
+// exceeds the capacity of the sketch and therefore can't be fully decoded.
+//
+// Since every short id that is peeled from a sketch empties at least one cell
+// of an honestly constructed sketch and each short id may only be in one of
+// the sets, decoding fails once more short ids than there are cells are
+// recovered or a short id is recovered more than once.  This ensures decoding
+// terminates for maliciously crafted sketches.
+//
+// The sketch is not modified.
+func (s *Sketch) Decode() ([]uint32, []uint32, bool) {
+	work := &Sketch{cells: make([]cell, len(s.cells))}
+	copy(work.cells, s.cells)
+
+	isPure := func(c *cell) bool {
+		return (c.count == 1 || c.count == -1) && c.hashSum == checksum(c.idSum)
+	}
+
+	// Repeatedly peel pure cells until none remain.
+	var local, remote []uint32
+	recovered := make(map[uint32]struct{})
+	pending := make([]int, 0, len(work.cells))
+	for i := range work.cells {
+		if isPure(&work.cells[i]) {
+			pending = append(pending, i)
+		}
+	}
+	for len(pending) > 0 {
+		idx := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		c := &work.cells[idx]
+		if !isPure(c) {
+			continue
+		}
+
+		id, count := c.idSum, c.count
+		if _, ok := recovered[id]; ok || len(recovered) == len(work.cells) {
+			return nil, nil, false
+		}
+		recovered[id] = struct{}{}
+		if count == 1 {
+			local = append(local, id)
+		} else {
+			remote = append(remote, id)
+		}
+		work.update(id, -count)
+		for i := 0; i < numCellHashes; i++ {
+			cellIdx := work.cellIndex(id, i)
+			if isPure(&work.cells[cellIdx]) {
+				pending = append(pending, cellIdx)
+			}
+		}
+	}
+
+	// The difference was only fully decoded when all cells are empty.
+	for i := range work.cells {
+		c := &work.cells[i]
+		if c.count != 0 || c.idSum != 0 || c.hashSum != 0 {
+			return nil, nil, false
+		}
+	}
+	return local, remote, true
+}
+
+// Bytes returns the serialized sketch.
+func (s *Sketch) Bytes() []byte {
+	b := make([]byte, len(s.cells)*cellSize)
+	for i := range s.cells {
+		offset := i * cellSize
+		c := &s.cells[i]
+		binary.LittleEndian.PutUint32(b[offset:], uint32(c.count))
+		binary.LittleEndian.PutUint32(b[offset+4:], c.idSum)
+		binary.LittleEndian.PutUint32(b[offset+8:], c.hashSum)
+	}
+	return b
+}
+
+// ParseSketch parses a serialized sketch.  ErrMalformedSketch is returned when
+// the serialized sketch does not consist of a valid number of cells.
+func ParseSketch(b []byte) (*Sketch, error) {
+	if len(b)%cellSize != 0 {
+		return nil, fmt.Errorf("%w: size %d is not a multiple of the cell "+
+			"size", ErrMalformedSketch, len(b))
+	}
+	numCells := len(b) / cellSize
+	if numCells < MinSketchCells || numCells > MaxSketchCells ||
+		numCells%numCellHashes != 0 {
+
+		return nil, fmt.Errorf("%w: invalid number of cells %d",
+			ErrMalformedSketch, numCells)
+	}
+
+	cells := make([]cell, numCells)
+	for i := range cells {
+		offset := i * cellSize
+		cells[i] = cell{
+			count:   int32(binary.LittleEndian.Uint32(b[offset:])),
+			idSum:   binary.LittleEndian.Uint32(b[offset+4:]),
+			hashSum: binary.LittleEndian.Uint32(b[offset+8:]),
+		}
+	}
+	return &Sketch{cells: cells}, nil
+}
+
+// newSketchWithCells returns a new empty sketch with the provided number of
+// cells.  The caller must ensure the number of cells is valid.
+func newSketchWithCells(numCells int) *Sketch {
+	return &Sketch{cells: make([]cell, numCells)}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txrecon
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// sortedIDs returns a sorted copy of the provided short ids.
+func sortedIDs(ids []uint32) []uint32 {
+	sorted := make([]uint32, len(ids))
+	copy(sorted, ids)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// equalIDs returns whether or not the provided short ids contain the same
+// elements regardless of order.
+func equalIDs(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortedIDs(a), sortedIDs(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSketchDecode ensures the symmetric difference of sets encoded by
+// sketches is decoded as expected.
+func TestSketchDecode(t *testing.T) {
+	tests := []struct {
+		name       string // test description
+		numShared  int    // number of ids in both sets
+		numLocal   int    // number of ids only in the local set
+		numRemote  int    // number of ids only in the remote set
+		capacity   int    // capacity of the sketches
+		wantDecode bool   // whether the difference should be decoded
+	}{{
+		name:       "empty sets",
+		capacity:   0,
+		wantDecode: true,
+	}, {
+		name:       "identical sets",
+		numShared:  500,
+		capacity:   0,
+		wantDecode: true,
+	}, {
+		name:       "local only",
+		numShared:  100,
+		numLocal:   5,
+		capacity:   5,
+		wantDecode: true,
+	}, {
+		name:       "remote only",
+		numShared:  100,
+		numRemote:  5,
+		capacity:   5,
+		wantDecode: true,
+	}, {
+		name:       "both sides with large shared set",
+		numShared:  2000,
+		numLocal:   40,
+		numRemote:  60,
+		capacity:   100,
+		wantDecode: true,
+	}, {
+		name:       "difference far exceeds capacity",
+		numShared:  100,
+		numLocal:   200,
+		numRemote:  200,
+		capacity:   10,
+		wantDecode: false,
+	}}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, test := range tests {
+		// Generate unique ids for the sets.
+		seen := make(map[uint32]struct{})
+		genIDs := func(n int) []uint32 {
+			ids := make([]uint32, 0, n)
+			for len(ids) < n {
+				id := rng.Uint32()
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+			return ids
+		}
+		shared := genIDs(test.numShared)
+		localOnly := genIDs(test.numLocal)
+		remoteOnly := genIDs(test.numRemote)
+
+		local, remote := NewSketch(test.capacity), NewSketch(test.capacity)
+		for _, id := range shared {
+			local.Add(id)
+			remote.Add(id)
+		}
+		for _, id := range localOnly {
+			local.Add(id)
+		}
+		for _, id := range remoteOnly {
+			remote.Add(id)
+		}
+
+		// Ensure the sketches survive a serialization round trip.
+		parsed, err := ParseSketch(remote.Bytes())
+		if err != nil {
+			t.Errorf("%q: unexpected parse error: %v", test.name, err)
+			continue
+		}
+
+		if err := local.Subtract(parsed); err != nil {
+			t.Errorf("%q: unexpected subtract error: %v", test.name, err)
+			continue
+		}
+		gotLocal, gotRemote, ok := local.Decode()
+		if ok != test.wantDecode {
+			t.Errorf("%q: unexpected decode result -- got %v, want %v",
+				test.name, ok, test.wantDecode)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !equalIDs(gotLocal, localOnly) {
+			t.Errorf("%q: mismatched local ids -- got %v, want %v",
+				test.name, sortedIDs(gotLocal), sortedIDs(localOnly))
+		}
+		if !equalIDs(gotRemote, remoteOnly) {
+			t.Errorf("%q: mismatched remote ids -- got %v, want %v",
+				test.name, sortedIDs(gotRemote), sortedIDs(remoteOnly))
+		}
+	}
+}
+
+// TestSketchErrors ensures malformed sketches and mismatched subtractions are
+// rejected.
+func TestSketchErrors(t *testing.T) {
+	// Ensure sketches that are not a multiple of the cell size, have too few
+	// cells, or have too many cells are rejected.
+	badSketches := [][]byte{
+		make([]byte, MinSketchCells*cellSize+1),
+		make([]byte, (MinSketchCells-numCellHashes)*cellSize),
+		make([]byte, (MinSketchCells+1)*cellSize),
+		make([]byte, (MaxSketchCells+numCellHashes)*cellSize),
+	}
+	for i, b := range badSketches {
+		_, err := ParseSketch(b)
+		if !errors.Is(err, ErrMalformedSketch) {
+			t.Errorf("#%d: unexpected error -- got %v, want %v", i, err,
+				ErrMalformedSketch)
+		}
+	}
+
+	// Ensure sketches with a different number of cells can't be subtracted.
+	small, large := NewSketch(0), NewSketch(100)
+	if err := small.Subtract(large); err == nil {
+		t.Fatal("subtracted sketches with a different number of cells")
+	}
+
+	// Ensure the capacity is clamped to the allowed number of cells.
+	if got := NewSketch(-1).NumCells(); got != MinSketchCells {
+		t.Fatalf("unexpected number of cells -- got %d, want %d", got,
+			MinSketchCells)
+	}
+	if got := NewSketch(1 << 20).NumCells(); got != MaxSketchCells {
+		t.Fatalf("unexpected number of cells -- got %d, want %d", got,
+			MaxSketchCells)
+	}
+}
+
+// TestSketchDecodeHostile ensures decoding maliciously crafted sketches that
+// would otherwise cause the same short id to be peeled repeatedly terminates
+// and reports failure.
+func TestSketchDecodeHostile(t *testing.T) {
+	// Craft a sketch where peeling the short id from the first cell it is
+	// stored in makes the other cells it is stored in pure with the same short
+	// id, which in turn makes the first cell pure again and so on.
+	const id = 0x01020304
+	sketch := NewSketch(0)
+	first := &sketch.cells[sketch.cellIndex(id, 0)]
+	*first = cell{count: 1, idSum: id, hashSum: checksum(id)}
+	second := &sketch.cells[sketch.cellIndex(id, 1)]
+	second.count = 2
+
+	// Round trip the sketch through its serialization to mimic receiving it
+	// from a peer.
+	hostile, err := ParseSketch(sketch.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected parse error: %v", err)
+	}
+
+	done := make(chan bool)
+	go func() {
+		_, _, ok := hostile.Decode()
+		done <- ok
+	}()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Fatal("decoded hostile sketch")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout decoding hostile sketch")
+	}
+}
@@ -14,18 +14,24 @@ import (
 	"github.com/decred/dcrd/blockchain/stake/v5"
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/diagnostics"
 	"github.com/decred/dcrd/internal/fees"
+	"github.com/decred/dcrd/internal/grpcserver"
+	"github.com/decred/dcrd/internal/logging"
+	"github.com/decred/dcrd/internal/logrotate"
 	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/metrics"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/netsync"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/tracing"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/slog"
-	"github.com/jrick/logrotate/rotator"
 )
 
 // logWriter implements an io.Writer that outputs to both standard output and
@@ -52,11 +58,11 @@ var (
 	// backendLog is the logging backend used to create all subsystem loggers.
 	// The backend must not be used before the log rotator has been initialized,
 	// or data races and/or nil pointer dereferences will occur.
-	backendLog = slog.NewBackend(logWriter{})
+	backendLog = logging.NewBackend(logWriter{})
 
 	// logRotator is one of the logging outputs.  It should be closed on
 	// application shutdown.
-	logRotator *rotator.Rotator
+	logRotator *logrotate.Rotator
 
 	adxrLog = backendLog.Logger("ADXR")
 	amgrLog = backendLog.Logger("AMGR")
@@ -64,16 +70,20 @@ var (
 	chanLog = backendLog.Logger("CHAN")
 	cmgrLog = backendLog.Logger("CMGR")
 	dcrdLog = backendLog.Logger("DCRD")
+	diagLog = backendLog.Logger("DIAG")
 	discLog = backendLog.Logger("DISC")
 	feesLog = backendLog.Logger("FEES")
+	grpcLog = backendLog.Logger("GRPC")
 	indxLog = backendLog.Logger("INDX")
 	minrLog = backendLog.Logger("MINR")
+	mtrcLog = backendLog.Logger("MTRC")
 	peerLog = backendLog.Logger("PEER")
 	rpcsLog = backendLog.Logger("RPCS")
 	scrpLog = backendLog.Logger("SCRP")
 	srvrLog = backendLog.Logger("SRVR")
 	stkeLog = backendLog.Logger("STKE")
 	syncLog = backendLog.Logger("SYNC")
+	trceLog = backendLog.Logger("TRCE")
 	txmpLog = backendLog.Logger("TXMP")
 	trsyLog = backendLog.Logger("TRSY")
 )
@@ -83,17 +93,22 @@ func init() {
 	addrmgr.UseLogger(amgrLog)
 	blockchain.UseLogger(chanLog)
 	blockchain.UseTreasuryLogger(trsyLog)
+	banmgr.UseLogger(srvrLog)
 	connmgr.UseLogger(cmgrLog)
 	database.UseLogger(bcdbLog)
+	diagnostics.UseLogger(diagLog)
 	fees.UseLogger(feesLog)
+	grpcserver.UseLogger(grpcLog)
 	indexers.UseLogger(indxLog)
 	mempool.UseLogger(txmpLog)
+	metrics.UseLogger(mtrcLog)
 	mining.UseLogger(minrLog)
 	cpuminer.UseLogger(minrLog)
 	peer.UseLogger(peerLog)
 	rpcserver.UseLogger(rpcsLog)
 	stake.UseLogger(stkeLog)
 	netsync.UseLogger(syncLog)
+	tracing.UseLogger(trceLog)
 	txscript.UseLogger(scrpLog)
 }
 
@@ -105,34 +120,38 @@ var subsystemLoggers = map[string]slog.Logger{
 	"CHAN": chanLog,
 	"CMGR": cmgrLog,
 	"DCRD": dcrdLog,
+	"DIAG": diagLog,
 	"DISC": discLog,
 	"FEES": feesLog,
+	"GRPC": grpcLog,
 	"INDX": indxLog,
 	"MINR": minrLog,
+	"MTRC": mtrcLog,
 	"PEER": peerLog,
 	"RPCS": rpcsLog,
 	"SCRP": scrpLog,
 	"SRVR": srvrLog,
 	"STKE": stkeLog,
 	"SYNC": syncLog,
+	"TRCE": trceLog,
 	"TXMP": txmpLog,
 	"TRSY": trsyLog,
 }
 
-// initLogRotator initializes the logging rotater to write logs to logFile and
-// create roll files in the same directory.  logSize is the size in KiB after
-// which a log file will be rotated and compressed.
+// initLogRotator initializes the logging rotater to write logs to the log file
+// and create roll files in the same directory according to the provided
+// rotation configuration.
 //
 // This function must be called before the package-global log rotater variables
 // are used.
-func initLogRotator(logFile string, logSize int64) {
-	logDir, _ := filepath.Split(logFile)
+func initLogRotator(cfg *logrotate.Config) {
+	logDir, _ := filepath.Split(cfg.Filename)
 	err := os.MkdirAll(logDir, 0700)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create log directory: %v\n", err)
 		os.Exit(1)
 	}
-	r, err := rotator.New(logFile, logSize, false, 3)
+	r, err := logrotate.New(cfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create file rotator: %v\n", err)
 		os.Exit(1)
@@ -156,6 +175,25 @@ func setLogLevel(subsystemID string, logLevel string) {
 	logger.SetLevel(level)
 }
 
+// logLevelNames defines the names of the logging levels as accepted by
+// setLogLevel indexed by level.
+var logLevelNames = [...]string{"trace", "debug", "info", "warn", "error",
+	"critical", "off"}
+
+// subsystemLogLevels returns the current logging level of each subsystem keyed
+// by the subsystem identifier.
+func subsystemLogLevels() map[string]string {
+	levels := make(map[string]string, len(subsystemLoggers))
+	for subsystemID, logger := range subsystemLoggers {
+		level := logger.Level()
+		if level > slog.LevelOff {
+			level = slog.LevelOff
+		}
+		levels[subsystemID] = logLevelNames[level]
+	}
+	return levels
+}
+
 // setLogLevels sets the log level for all subsystem loggers to the passed
 // level.  It also dynamically creates the subsystem loggers as needed, so it
 // can be used to initialize the logging system.
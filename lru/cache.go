@@ -15,6 +15,8 @@ import (
 //
 // The NewCache function must be used to create a usable cache since the zero
 // value of this struct is not valid.
+//
+// Deprecated: Use lru.Set from github.com/decred/dcrd/container/lru instead.
 type Cache struct {
 	mtx   sync.Mutex
 	cache map[interface{}]*list.Element // nearly O(1) lookups
@@ -95,6 +97,8 @@ func (m *Cache) Delete(item interface{}) {
 
 // NewCache returns an initialized and empty LRU cache.  See the documentation
 // for Cache for more details.
+//
+// Deprecated: Use lru.NewSet from github.com/decred/dcrd/container/lru instead.
 func NewCache(limit uint) Cache {
 	return Cache{
 		cache: make(map[interface{}]*list.Element),
@@ -20,5 +20,9 @@ This package has intentionally been designed so it can be used as a standalone
 package for any projects needing to make use of a well-test least-recently-used
 cache with near O(1) performance characteristics for lookups, inserts, and
 deletions.
+
+Deprecated: This package is superseded by the type safe generics-based
+implementation in github.com/decred/dcrd/container/lru, which additionally
+supports expiration, cost-based capacities, and eviction callbacks.
 */
 package lru
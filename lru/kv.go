@@ -22,6 +22,8 @@ type kv struct {
 //
 // The NewKVCache function must be used to create a usable cache since the zero
 // value of this struct is not valid.
+//
+// Deprecated: Use lru.Map from github.com/decred/dcrd/container/lru instead.
 type KVCache struct {
 	mtx   sync.Mutex
 	cache map[interface{}]*list.Element // nearly O(1) lookups
@@ -125,6 +127,8 @@ func (m *KVCache) Delete(key interface{}) {
 
 // NewKVCache returns an initialized and empty KV LRU cache.
 // See the documentation for KV for more details.
+//
+// Deprecated: Use lru.NewMap from github.com/decred/dcrd/container/lru instead.
 func NewKVCache(limit uint) KVCache {
 	return KVCache{
 		cache: make(map[interface{}]*list.Element),
@@ -0,0 +1,413 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// pmpPort is the UDP port NAT-PMP and PCP servers listen on.
+	pmpPort = 5351
+
+	// pmpVersion and pcpVersion are the protocol versions of NAT-PMP
+	// (RFC 6886) and PCP (RFC 6887), respectively.
+	pmpVersion = 0
+	pcpVersion = 2
+
+	// pmpOpExternalAddr, pmpOpMapUDP, and pmpOpMapTCP are the NAT-PMP
+	// opcodes used to request the external address of the gateway and to map
+	// UDP and TCP ports, respectively.
+	pmpOpExternalAddr = 0
+	pmpOpMapUDP       = 1
+	pmpOpMapTCP       = 2
+
+	// pcpOpAnnounce and pcpOpMap are the PCP opcodes used to probe the
+	// gateway and to map ports, respectively.
+	pcpOpAnnounce = 0
+	pcpOpMap      = 1
+
+	// pmpResponseFlag is set in the opcode of responses.
+	pmpResponseFlag = 0x80
+
+	// pcpResultUnsuppVersion is the result code returned by a gateway that
+	// does not support the requested protocol version.
+	pcpResultUnsuppVersion = 1
+
+	// pmpInitialTimeout and pmpMaxTries control the retransmission of
+	// requests.  The timeout is doubled after each try as recommended by the
+	// RFCs, although fewer tries are made in order to give up on gateways
+	// that do not respond in a reasonable amount of time.
+	pmpInitialTimeout = 250 * time.Millisecond
+	pmpMaxTries       = 4
+)
+
+var (
+	// errPMPNoResponse indicates the gateway did not respond to a request.
+	errPMPNoResponse = errors.New("no response from NAT-PMP/PCP gateway")
+
+	// errPMPNoExternalAddr indicates the external address is not yet known
+	// because no port has been mapped via PCP.
+	errPMPNoExternalAddr = errors.New("external address is not known")
+
+	// errPCPUnsupported indicates the gateway does not support PCP.
+	errPCPUnsupported = errors.New("gateway does not support PCP")
+)
+
+// pmpNAT implements the NAT interface via the Port Control Protocol (PCP) when
+// the gateway supports it and falls back to its predecessor, the NAT Port
+// Mapping Protocol (NAT-PMP), otherwise.
+type pmpNAT struct {
+	gateway *net.UDPAddr
+	ourIP   net.IP
+
+	mtx          sync.Mutex
+	usePCP       bool
+	nonce        [12]byte
+	externalAddr net.IP
+}
+
+// discoverPMP attempts to find a gateway that supports PCP or NAT-PMP on the
+// default route and returns a NAT for it if so.
+func discoverPMP() (*pmpNAT, error) {
+	gatewayIP, err := defaultGateway()
+	if err != nil {
+		return nil, err
+	}
+	nat, err := newPMPNAT(&net.UDPAddr{IP: gatewayIP, Port: pmpPort})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := nat.probe(); err != nil {
+		return nil, err
+	}
+	return nat, nil
+}
+
+// newPMPNAT returns a NAT for the PCP or NAT-PMP server at the provided
+// address.  PCP is assumed until the gateway is probed.
+func newPMPNAT(gateway *net.UDPAddr) (*pmpNAT, error) {
+	// Determine the local address used to reach the gateway.  Note that this
+	// does not send any traffic since it is a UDP socket.
+	conn, err := net.DialUDP("udp", nil, gateway)
+	if err != nil {
+		return nil, err
+	}
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+	conn.Close()
+
+	nat := &pmpNAT{
+		gateway: gateway,
+		ourIP:   localAddr.IP,
+		usePCP:  true,
+	}
+
+	// PCP requires a nonce that identifies the client across requests.
+	if _, err := rand.Read(nat.nonce[:]); err != nil {
+		return nil, err
+	}
+	return nat, nil
+}
+
+// defaultGateway returns the IPv4 address of the gateway on the default route.
+// The routing table is consulted when it is available and, otherwise, the
+// first address of the subnet of the local address used to reach the internet
+// is assumed since that is the convention for nearly all home routers.
+func defaultGateway() (net.IP, error) {
+	if gateway, err := linuxDefaultGateway(); err == nil {
+		return gateway, nil
+	}
+
+	// Note that this does not send any traffic since it is a UDP socket.
+	conn, err := net.Dial("udp4", "192.0.2.1:9")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok || localAddr.IP.To4() == nil {
+		return nil, errors.New("unable to determine default gateway")
+	}
+	gateway := localAddr.IP.To4().Mask(net.CIDRMask(24, 32))
+	gateway[3] = 1
+	return gateway, nil
+}
+
+// linuxDefaultGateway returns the gateway of the default IPv4 route from the
+// Linux routing table.
+func linuxDefaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseLinuxRoutes(f)
+}
+
+// parseLinuxRoutes parses the gateway of the default IPv4 route from the
+// provided reader which must be in the format of /proc/net/route.
+func parseLinuxRoutes(r io.Reader) (net.IP, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// The fields are the interface, destination, and gateway followed
+		// by others that are not needed.  The addresses are hex-encoded
+		// in host byte order.
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != net.IPv4len {
+			continue
+		}
+		gateway := net.IPv4(b[3], b[2], b[1], b[0])
+		if gateway.IsUnspecified() {
+			continue
+		}
+		return gateway, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no default route")
+}
+
+// roundTrip sends the provided request to the gateway and returns the first
+// response that is accepted by the provided function.  The request is
+// retransmitted with an exponentially increasing timeout until a response is
+// received or the maximum number of tries is reached.
+func (n *pmpNAT) roundTrip(req []byte, accept func([]byte) bool) ([]byte, error) {
+	conn, err := net.DialUDP("udp", nil, n.gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp := make([]byte, 1100)
+	timeout := pmpInitialTimeout
+	for i := 0; i < pmpMaxTries; i++ {
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+		deadline := time.Now().Add(timeout)
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		for {
+			nr, err := conn.Read(resp)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+				return nil, err
+			}
+			if accept(resp[:nr]) {
+				return resp[:nr], nil
+			}
+		}
+		timeout *= 2
+	}
+	return nil, errPMPNoResponse
+}
+
+// pmpResultError returns an error for the provided non-zero NAT-PMP or PCP
+// result code.
+func pmpResultError(resultCode uint16) error {
+	return fmt.Errorf("gateway returned result code %d", resultCode)
+}
+
+// isPMPUnsupportedVersion returns whether the provided response is from a
+// gateway that only supports NAT-PMP rejecting a PCP request.
+func isPMPUnsupportedVersion(resp []byte) bool {
+	return len(resp) >= 4 && resp[0] == pmpVersion &&
+		binary.BigEndian.Uint16(resp[2:4]) == pcpResultUnsuppVersion
+}
+
+// probe determines which of PCP and NAT-PMP the gateway supports by sending a
+// PCP announcement and falling back to requesting the external address via
+// NAT-PMP when the gateway does not support PCP.
+func (n *pmpNAT) probe() error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	req := make([]byte, 24)
+	req[0] = pcpVersion
+	req[1] = pcpOpAnnounce
+	copy(req[8:24], n.ourIP.To16())
+	resp, err := n.roundTrip(req, func(resp []byte) bool {
+		if isPMPUnsupportedVersion(resp) {
+			return true
+		}
+		return len(resp) >= 24 && resp[0] == pcpVersion &&
+			resp[1] == pmpResponseFlag|pcpOpAnnounce
+	})
+	if err != nil {
+		return err
+	}
+	if !isPMPUnsupportedVersion(resp) {
+		if resultCode := resp[3]; resultCode != 0 {
+			return pmpResultError(uint16(resultCode))
+		}
+		n.usePCP = true
+		return nil
+	}
+
+	n.usePCP = false
+	_, err = n.pmpExternalAddress()
+	return err
+}
+
+// pmpExternalAddress requests the external address of the gateway via
+// NAT-PMP.
+func (n *pmpNAT) pmpExternalAddress() (net.IP, error) {
+	req := []byte{pmpVersion, pmpOpExternalAddr}
+	resp, err := n.roundTrip(req, func(resp []byte) bool {
+		return len(resp) >= 12 && resp[0] == pmpVersion &&
+			resp[1] == pmpResponseFlag|pmpOpExternalAddr
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resultCode := binary.BigEndian.Uint16(resp[2:4]); resultCode != 0 {
+		return nil, pmpResultError(resultCode)
+	}
+	return net.IPv4(resp[8], resp[9], resp[10], resp[11]), nil
+}
+
+// pmpMap requests a port mapping via NAT-PMP.  A lifetime of zero removes the
+// mapping.
+func (n *pmpNAT) pmpMap(protocol string, externalPort, internalPort int, lifetime uint32) (int, error) {
+	op := byte(pmpOpMapTCP)
+	if strings.EqualFold(protocol, "udp") {
+		op = pmpOpMapUDP
+	}
+	req := make([]byte, 12)
+	req[0] = pmpVersion
+	req[1] = op
+	binary.BigEndian.PutUint16(req[4:6], uint16(internalPort))
+	binary.BigEndian.PutUint16(req[6:8], uint16(externalPort))
+	binary.BigEndian.PutUint32(req[8:12], lifetime)
+	resp, err := n.roundTrip(req, func(resp []byte) bool {
+		return len(resp) >= 16 && resp[0] == pmpVersion &&
+			resp[1] == pmpResponseFlag|op &&
+			binary.BigEndian.Uint16(resp[8:10]) == uint16(internalPort)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resultCode := binary.BigEndian.Uint16(resp[2:4]); resultCode != 0 {
+		return 0, pmpResultError(resultCode)
+	}
+	return int(binary.BigEndian.Uint16(resp[10:12])), nil
+}
+
+// pcpMap requests a port mapping via PCP and returns the assigned external
+// port and address.  A lifetime of zero removes the mapping.
+//
+// errPCPUnsupported is returned when the gateway only supports NAT-PMP.
+func (n *pmpNAT) pcpMap(protocol string, externalPort, internalPort int, lifetime uint32) (int, net.IP, error) {
+	proto := byte(6) // TCP
+	if strings.EqualFold(protocol, "udp") {
+		proto = 17
+	}
+
+	// The request consists of the common header followed by the MAP opcode
+	// data.  The suggested external address is left unspecified.
+	req := make([]byte, 60)
+	req[0] = pcpVersion
+	req[1] = pcpOpMap
+	binary.BigEndian.PutUint32(req[4:8], lifetime)
+	copy(req[8:24], n.ourIP.To16())
+	copy(req[24:36], n.nonce[:])
+	req[36] = proto
+	binary.BigEndian.PutUint16(req[40:42], uint16(internalPort))
+	binary.BigEndian.PutUint16(req[42:44], uint16(externalPort))
+	copy(req[44:60], net.IPv4zero.To16())
+	resp, err := n.roundTrip(req, func(resp []byte) bool {
+		if isPMPUnsupportedVersion(resp) {
+			return true
+		}
+		return len(resp) >= 60 && resp[0] == pcpVersion &&
+			resp[1] == pmpResponseFlag|pcpOpMap &&
+			string(resp[24:36]) == string(n.nonce[:])
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if isPMPUnsupportedVersion(resp) {
+		return 0, nil, errPCPUnsupported
+	}
+	if resultCode := resp[3]; resultCode != 0 {
+		return 0, nil, pmpResultError(uint16(resultCode))
+	}
+	mappedPort := int(binary.BigEndian.Uint16(resp[42:44]))
+	externalAddr := net.IP(append([]byte(nil), resp[44:60]...))
+	return mappedPort, externalAddr, nil
+}
+
+// GetExternalAddress implements the NAT interface by fetching the external IP
+// from the gateway.  Since PCP has no dedicated request for it, the address
+// assigned to the most recent port mapping is returned in that case.
+func (n *pmpNAT) GetExternalAddress() (net.IP, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.usePCP {
+		if n.externalAddr == nil {
+			return nil, errPMPNoExternalAddr
+		}
+		return n.externalAddr, nil
+	}
+	return n.pmpExternalAddress()
+}
+
+// AddPortMapping implements the NAT interface by setting up a port forwarding
+// from the gateway to the local machine with the given ports and protocol for
+// the given number of seconds.
+func (n *pmpNAT) AddPortMapping(protocol string, externalPort, internalPort int, description string, timeout int) (int, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.usePCP {
+		mappedPort, externalAddr, err := n.pcpMap(protocol, externalPort,
+			internalPort, uint32(timeout))
+		if err == nil {
+			n.externalAddr = externalAddr
+			return mappedPort, nil
+		}
+		if !errors.Is(err, errPCPUnsupported) {
+			return 0, err
+		}
+		n.usePCP = false
+	}
+	return n.pmpMap(protocol, externalPort, internalPort, uint32(timeout))
+}
+
+// DeletePortMapping implements the NAT interface by removing a port forwarding
+// from the gateway to the local machine with the given ports and protocol.
+func (n *pmpNAT) DeletePortMapping(protocol string, externalPort, internalPort int) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.usePCP {
+		_, _, err := n.pcpMap(protocol, 0, internalPort, 0)
+		return err
+	}
+	_, err := n.pmpMap(protocol, 0, internalPort, 0)
+	return err
+}
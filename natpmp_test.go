@@ -0,0 +1,170 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+)
+
+// testPMPGateway is a minimal NAT-PMP and optionally PCP server used to test
+// the client.
+type testPMPGateway struct {
+	conn         *net.UDPConn
+	supportsPCP  bool
+	externalAddr net.IP
+	mappedPort   uint16
+}
+
+// newTestPMPGateway starts a test gateway that listens on the loopback
+// interface and responds to requests until the test completes.
+func newTestPMPGateway(t *testing.T, supportsPCP bool) *testPMPGateway {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	g := &testPMPGateway{
+		conn:         conn,
+		supportsPCP:  supportsPCP,
+		externalAddr: net.IPv4(203, 0, 113, 7).To4(),
+		mappedPort:   19108,
+	}
+	go g.serve()
+	return g
+}
+
+// serve responds to requests until the connection is closed.
+func (g *testPMPGateway) serve() {
+	req := make([]byte, 1100)
+	for {
+		n, addr, err := g.conn.ReadFromUDP(req)
+		if err != nil {
+			return
+		}
+		if resp := g.respond(req[:n]); resp != nil {
+			g.conn.WriteToUDP(resp, addr)
+		}
+	}
+}
+
+// respond returns the response to the provided request.
+func (g *testPMPGateway) respond(req []byte) []byte {
+	switch {
+	case req[0] == pcpVersion && !g.supportsPCP:
+		resp := make([]byte, 8)
+		resp[0] = pmpVersion
+		resp[1] = pmpResponseFlag | req[1]
+		binary.BigEndian.PutUint16(resp[2:4], pcpResultUnsuppVersion)
+		return resp
+
+	case req[0] == pcpVersion && req[1] == pcpOpAnnounce:
+		resp := make([]byte, 24)
+		resp[0] = pcpVersion
+		resp[1] = pmpResponseFlag | pcpOpAnnounce
+		return resp
+
+	case req[0] == pcpVersion && req[1] == pcpOpMap:
+		resp := make([]byte, 60)
+		copy(resp, req)
+		resp[1] = pmpResponseFlag | pcpOpMap
+		binary.BigEndian.PutUint16(resp[42:44], g.mappedPort)
+		copy(resp[44:60], g.externalAddr.To16())
+		return resp
+
+	case req[0] == pmpVersion && req[1] == pmpOpExternalAddr:
+		resp := make([]byte, 12)
+		resp[1] = pmpResponseFlag | pmpOpExternalAddr
+		copy(resp[8:12], g.externalAddr)
+		return resp
+
+	case req[0] == pmpVersion && (req[1] == pmpOpMapTCP || req[1] == pmpOpMapUDP):
+		resp := make([]byte, 16)
+		resp[1] = pmpResponseFlag | req[1]
+		copy(resp[8:10], req[4:6])
+		binary.BigEndian.PutUint16(resp[10:12], g.mappedPort)
+		copy(resp[12:16], req[8:12])
+		return resp
+	}
+	return nil
+}
+
+// TestPMPNAT ensures the NAT-PMP/PCP client negotiates the protocol supported
+// by the gateway, maps ports, and reports the external address.
+func TestPMPNAT(t *testing.T) {
+	tests := []struct {
+		name        string
+		supportsPCP bool
+	}{
+		{"pcp gateway", true},
+		{"nat-pmp only gateway", false},
+	}
+
+	for _, test := range tests {
+		gateway := newTestPMPGateway(t, test.supportsPCP)
+		nat, err := newPMPNAT(gateway.conn.LocalAddr().(*net.UDPAddr))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.name, err)
+		}
+		if err := nat.probe(); err != nil {
+			t.Fatalf("%q: unexpected probe error: %v", test.name, err)
+		}
+		if nat.usePCP != test.supportsPCP {
+			t.Fatalf("%q: unexpected protocol - got pcp %v, want pcp %v",
+				test.name, nat.usePCP, test.supportsPCP)
+		}
+
+		mappedPort, err := nat.AddPortMapping("tcp", 9108, 9108, "test", 1200)
+		if err != nil {
+			t.Fatalf("%q: unexpected error mapping port: %v", test.name, err)
+		}
+		if mappedPort != int(gateway.mappedPort) {
+			t.Fatalf("%q: unexpected mapped port - got %d, want %d",
+				test.name, mappedPort, gateway.mappedPort)
+		}
+
+		externalAddr, err := nat.GetExternalAddress()
+		if err != nil {
+			t.Fatalf("%q: unexpected error getting external address: %v",
+				test.name, err)
+		}
+		if !externalAddr.Equal(gateway.externalAddr) {
+			t.Fatalf("%q: unexpected external address - got %v, want %v",
+				test.name, externalAddr, gateway.externalAddr)
+		}
+
+		if err := nat.DeletePortMapping("tcp", 9108, 9108); err != nil {
+			t.Fatalf("%q: unexpected error deleting mapping: %v", test.name,
+				err)
+		}
+	}
+}
+
+// TestParseLinuxRoutes ensures the default gateway is parsed from the Linux
+// routing table as expected.
+func TestParseLinuxRoutes(t *testing.T) {
+	const routes = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\n" +
+		"eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\n" +
+		"eth0\t00000000\t0100A8C0\t0003\t0\t0\t0\t00000000\n"
+
+	gateway, err := parseLinuxRoutes(strings.NewReader(routes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := net.IPv4(192, 168, 0, 1); !gateway.Equal(want) {
+		t.Fatalf("unexpected gateway - got %v, want %v", gateway, want)
+	}
+
+	// Ensure an error is returned when there is no default route.
+	const noDefault = "Iface\tDestination\tGateway \tFlags\n" +
+		"eth0\t0000A8C0\t00000000\t0001\n"
+	if _, err := parseLinuxRoutes(strings.NewReader(noDefault)); err == nil {
+		t.Fatal("expected error for routing table without default route")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+
+	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/metrics"
+)
+
+// nodeMetrics houses the metrics that instrument block validation and the
+// transaction memory pool.  The instruments are only created when the metrics
+// listener is enabled so they do not impose any overhead otherwise.
+//
+// It implements the metrics.Source and rpcserver.MetricsSource interfaces.
+type nodeMetrics struct {
+	chain     *blockchain.BlockChain
+	txMemPool *mempool.TxPool
+
+	processBlockTime *metrics.Histogram
+	acceptedTxns     *metrics.Counter
+	orphanedTxns     *metrics.Counter
+	rejectedTxns     *metrics.CounterVec
+}
+
+// newNodeMetrics returns a new instance of node metrics with all of the
+// instruments created.  The chain and memory pool must be set before the
+// metrics are written.
+func newNodeMetrics() *nodeMetrics {
+	return &nodeMetrics{
+		processBlockTime: metrics.NewHistogram(metrics.DurationBuckets),
+		acceptedTxns:     metrics.NewCounter(),
+		orphanedTxns:     metrics.NewCounter(),
+		rejectedTxns:     metrics.NewCounterVec(),
+	}
+}
+
+// WriteMetrics writes the current block validation and memory pool metrics to
+// the provided writer in the Prometheus text exposition format.
+//
+// This is part of the metrics.Source interface implementation.
+func (m *nodeMetrics) WriteMetrics(w io.Writer) error {
+	mw := metrics.NewWriter(w)
+
+	best := m.chain.BestSnapshot()
+	mw.Gauge("dcrd_chain_best_height", "Height of the current best chain "+
+		"tip.", float64(best.Height))
+	processBlockTime := m.processBlockTime.Snapshot()
+	mw.Histogram("dcrd_chain_block_process_seconds", "Time taken to "+
+		"validate and connect new blocks.", &processBlockTime)
+
+	var poolBytes int
+	descs := m.txMemPool.TxDescs()
+	for _, desc := range descs {
+		poolBytes += desc.Tx.MsgTx().SerializeSize()
+	}
+	orphans := m.txMemPool.OrphanStats()
+	mw.Gauge("dcrd_mempool_transactions", "Number of transactions in the "+
+		"memory pool.", float64(len(descs)))
+	mw.Gauge("dcrd_mempool_bytes", "Total serialized size of the "+
+		"transactions in the memory pool.", float64(poolBytes))
+	mw.Gauge("dcrd_mempool_orphans", "Number of transactions in the orphan "+
+		"pool.", float64(orphans.Count))
+	mw.Counter("dcrd_mempool_accepted_total", "Total number of transactions "+
+		"accepted to the memory pool.", m.acceptedTxns.Value())
+	mw.Counter("dcrd_mempool_orphaned_total", "Total number of transactions "+
+		"added to the orphan pool.", m.orphanedTxns.Value())
+	mw.CounterVec("dcrd_mempool_rejected_total", "Total number of "+
+		"transactions rejected by the memory pool by the kind of error.",
+		"kind", m.rejectedTxns.Values())
+
+	return mw.Flush()
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/decred/dcrd/addrmgr/v2"
+	"github.com/decred/dcrd/connmgr/v3"
+	"github.com/decred/dcrd/internal/i2p"
+	"github.com/decred/dcrd/internal/metrics"
+)
+
+// Reasons the server rejects peers during the initial protocol negotiation in
+// addition to those reported by the peer package.  They are used as labels
+// for the handshake failure metrics.
+const (
+	handshakeObsoleteProtocol     = "obsolete_protocol"
+	handshakeEncryptionFailed     = "encryption_failed"
+	handshakeEncryptionMissing    = "encryption_not_advertised"
+	handshakeEncryptionDowngraded = "encryption_downgraded"
+	handshakeMissingServices      = "missing_services"
+)
+
+// directionLabel returns the label used for the direction of a connection in
+// the metrics.
+func directionLabel(inbound bool) string {
+	if inbound {
+		return "inbound"
+	}
+	return "outbound"
+}
+
+// addrNetwork returns the label used for the network of the provided address
+// in the metrics.
+func addrNetwork(addr net.Addr) string {
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		if addr.IP.To4() != nil {
+			return "ipv4"
+		}
+		return "ipv6"
+	case *onionAddr:
+		return "onion"
+	case *i2p.Addr:
+		return "i2p"
+	}
+	return "unknown"
+}
+
+// network returns the label used for the network of the remote peer in the
+// metrics.
+func (sp *serverPeer) network() string {
+	if sp.netAddr != nil {
+		switch sp.netAddr.Type {
+		case addrmgr.TORv3Address:
+			return "onion"
+		case addrmgr.I2PAddress:
+			return "i2p"
+		}
+	}
+	na := sp.NA()
+	if na == nil {
+		return "unknown"
+	}
+	if na.IP.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// handshakeFailureKey identifies a series of the handshake failure metric.
+type handshakeFailureKey struct {
+	direction string
+	reason    string
+}
+
+// peerKey identifies a series of the connected peers metric.
+type peerKey struct {
+	direction string
+	network   string
+}
+
+// connReqKey identifies a series of the outbound connection requests metric.
+type connReqKey struct {
+	state   string
+	network string
+}
+
+// p2pSnapshot houses the state of the peer-to-peer connections at the time
+// the metrics are collected.
+type p2pSnapshot struct {
+	peers     map[peerKey]uint64
+	connReqs  map[connReqKey]uint64
+	connStats connmgr.Stats
+	bytesRecv map[string]uint64
+	bytesSent map[string]uint64
+}
+
+// p2pMetrics tracks the connection-level metrics of the peer-to-peer network
+// that are not otherwise tracked by the server and provides them along with
+// the remaining connection state to the metrics endpoint of the RPC server.
+//
+// It implements the metrics.Source and rpcserver.MetricsSource interfaces.
+type p2pMetrics struct {
+	server *server
+
+	mtx               sync.Mutex
+	handshakeFailures map[handshakeFailureKey]uint64
+}
+
+// newP2PMetrics returns a new instance of the peer-to-peer metrics for the
+// provided server.
+func newP2PMetrics(s *server) *p2pMetrics {
+	return &p2pMetrics{
+		server:            s,
+		handshakeFailures: make(map[handshakeFailureKey]uint64),
+	}
+}
+
+// handshakeFailed records a failed protocol negotiation with a peer in the
+// provided direction for the provided reason.
+//
+// This function is safe for concurrent access.
+func (m *p2pMetrics) handshakeFailed(inbound bool, reason string) {
+	key := handshakeFailureKey{directionLabel(inbound), reason}
+	m.mtx.Lock()
+	m.handshakeFailures[key]++
+	m.mtx.Unlock()
+}
+
+// snapshot collects the current state of the peer-to-peer connections from
+// the server.
+func (m *p2pMetrics) snapshot() *p2pSnapshot {
+	s := m.server
+	snap := &p2pSnapshot{
+		peers:    make(map[peerKey]uint64),
+		connReqs: make(map[connReqKey]uint64),
+	}
+
+	replyChan := make(chan []*serverPeer)
+	select {
+	case s.query <- getPeersMsg{reply: replyChan}:
+		for _, sp := range <-replyChan {
+			snap.peers[peerKey{directionLabel(sp.Inbound()), sp.network()}]++
+		}
+	case <-s.quit:
+	}
+
+	_ = s.connManager.ForEachConnReq(func(c *connmgr.ConnReq) error {
+		snap.connReqs[connReqKey{c.State().String(), addrNetwork(c.Addr)}]++
+		return nil
+	})
+	snap.connStats = s.connManager.Stats()
+	snap.bytesRecv, snap.bytesSent = s.NetTotalsPerMsg()
+	return snap
+}
+
+// sortedKeys returns the keys of the provided map in sorted order.
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeTo writes the provided snapshot along with the tracked metrics to the
+// provided writer in the Prometheus text exposition format.
+func (m *p2pMetrics) writeTo(w io.Writer, snap *p2pSnapshot) error {
+	m.mtx.Lock()
+	failures := make([]handshakeFailureKey, 0, len(m.handshakeFailures))
+	failureCounts := make(map[handshakeFailureKey]uint64,
+		len(m.handshakeFailures))
+	for key, count := range m.handshakeFailures {
+		failures = append(failures, key)
+		failureCounts[key] = count
+	}
+	m.mtx.Unlock()
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].direction != failures[j].direction {
+			return failures[i].direction < failures[j].direction
+		}
+		return failures[i].reason < failures[j].reason
+	})
+
+	peers := make([]peerKey, 0, len(snap.peers))
+	for key := range snap.peers {
+		peers = append(peers, key)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].direction != peers[j].direction {
+			return peers[i].direction < peers[j].direction
+		}
+		return peers[i].network < peers[j].network
+	})
+
+	connReqs := make([]connReqKey, 0, len(snap.connReqs))
+	for key := range snap.connReqs {
+		connReqs = append(connReqs, key)
+	}
+	sort.Slice(connReqs, func(i, j int) bool {
+		if connReqs[i].state != connReqs[j].state {
+			return connReqs[i].state < connReqs[j].state
+		}
+		return connReqs[i].network < connReqs[j].network
+	})
+
+	mw := metrics.NewWriter(w)
+	mw.Header("dcrd_p2p_peers", "Number of connected peers by direction and "+
+		"network.", "gauge")
+	for _, key := range peers {
+		mw.Sample("dcrd_p2p_peers", snap.peers[key], "direction",
+			key.direction, "network", key.network)
+	}
+
+	mw.Header("dcrd_p2p_outbound_requests", "Number of outbound connection "+
+		"requests by state and network.", "gauge")
+	for _, key := range connReqs {
+		mw.Sample("dcrd_p2p_outbound_requests", snap.connReqs[key], "state",
+			key.state, "network", key.network)
+	}
+
+	mw.Counter("dcrd_p2p_dial_attempts_total", "Number of outbound "+
+		"connection attempts.", snap.connStats.DialAttempts)
+	mw.Counter("dcrd_p2p_dial_failures_total", "Number of outbound "+
+		"connection attempts that failed to connect.",
+		snap.connStats.DialFailures)
+	mw.Counter("dcrd_p2p_accepted_total", "Number of inbound connections "+
+		"accepted.", snap.connStats.Accepted)
+
+	mw.Header("dcrd_p2p_handshake_failures_total", "Number of failed "+
+		"protocol negotiations by direction and reason.", "counter")
+	for _, key := range failures {
+		mw.Sample("dcrd_p2p_handshake_failures_total", failureCounts[key],
+			"direction", key.direction, "reason", key.reason)
+	}
+
+	mw.Header("dcrd_p2p_bytes_total", "Number of bytes sent and received by "+
+		"direction and message command.", "counter")
+	for _, cmd := range sortedKeys(snap.bytesRecv) {
+		mw.Sample("dcrd_p2p_bytes_total", snap.bytesRecv[cmd], "direction",
+			"received", "command", cmd)
+	}
+	for _, cmd := range sortedKeys(snap.bytesSent) {
+		mw.Sample("dcrd_p2p_bytes_total", snap.bytesSent[cmd], "direction",
+			"sent", "command", cmd)
+	}
+
+	return mw.Flush()
+}
+
+// WriteMetrics writes the current peer-to-peer connection metrics to the
+// provided writer in the Prometheus text exposition format.
+//
+// This is part of the rpcserver.MetricsSource interface implementation.
+func (m *p2pMetrics) WriteMetrics(w io.Writer) error {
+	return m.writeTo(w, m.snapshot())
+}
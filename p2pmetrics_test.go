@@ -0,0 +1,115 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/connmgr/v3"
+	"github.com/decred/dcrd/internal/i2p"
+)
+
+// TestP2PMetrics ensures the peer-to-peer connection metrics are written in
+// the expected format.
+func TestP2PMetrics(t *testing.T) {
+	m := newP2PMetrics(nil)
+	m.handshakeFailed(true, "timeout")
+	m.handshakeFailed(true, "timeout")
+	m.handshakeFailed(false, handshakeMissingServices)
+
+	snap := &p2pSnapshot{
+		peers: map[peerKey]uint64{
+			{"outbound", "ipv6"}: 1,
+			{"inbound", "ipv4"}:  3,
+			{"outbound", "ipv4"}: 5,
+		},
+		connReqs: map[connReqKey]uint64{
+			{"pending", "onion"}:    1,
+			{"established", "ipv4"}: 5,
+		},
+		connStats: connmgr.Stats{
+			DialAttempts: 10,
+			DialFailures: 4,
+			Accepted:     7,
+		},
+		bytesRecv: map[string]uint64{"block": 1000, "inv": 100},
+		bytesSent: map[string]uint64{"getdata": 50},
+	}
+
+	var buf bytes.Buffer
+	if err := m.writeTo(&buf, snap); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	wantLines := []string{
+		`# TYPE dcrd_p2p_peers gauge`,
+		`dcrd_p2p_peers{direction="inbound",network="ipv4"} 3`,
+		`dcrd_p2p_peers{direction="outbound",network="ipv4"} 5`,
+		`dcrd_p2p_peers{direction="outbound",network="ipv6"} 1`,
+		`# TYPE dcrd_p2p_outbound_requests gauge`,
+		`dcrd_p2p_outbound_requests{state="established",network="ipv4"} 5`,
+		`dcrd_p2p_outbound_requests{state="pending",network="onion"} 1`,
+		`dcrd_p2p_dial_attempts_total 10`,
+		`dcrd_p2p_dial_failures_total 4`,
+		`dcrd_p2p_accepted_total 7`,
+		`# TYPE dcrd_p2p_handshake_failures_total counter`,
+		`dcrd_p2p_handshake_failures_total{direction="inbound",reason="timeout"} 2`,
+		`dcrd_p2p_handshake_failures_total{direction="outbound",reason="missing_services"} 1`,
+		`# TYPE dcrd_p2p_bytes_total counter`,
+		`dcrd_p2p_bytes_total{direction="received",command="block"} 1000`,
+		`dcrd_p2p_bytes_total{direction="received",command="inv"} 100`,
+		`dcrd_p2p_bytes_total{direction="sent",command="getdata"} 50`,
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("missing expected line %q in:\n%s", line, got)
+		}
+	}
+	if strings.Index(got, `direction="inbound",network="ipv4"`) >
+		strings.Index(got, `direction="outbound",network="ipv4"`) {
+
+		t.Errorf("peers are not sorted:\n%s", got)
+	}
+}
+
+// TestAddrNetwork ensures the network labels of connection request addresses
+// are determined as expected.
+func TestAddrNetwork(t *testing.T) {
+	tests := []struct {
+		name string
+		addr net.Addr
+		want string
+	}{{
+		name: "ipv4",
+		addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9108},
+		want: "ipv4",
+	}, {
+		name: "ipv6",
+		addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9108},
+		want: "ipv6",
+	}, {
+		name: "onion",
+		addr: &onionAddr{addr: "abcdefghijklmnop.onion:9108"},
+		want: "onion",
+	}, {
+		name: "i2p",
+		addr: &i2p.Addr{Host: "abcdefghijklmnop.b32.i2p"},
+		want: "i2p",
+	}, {
+		name: "other",
+		addr: &net.UnixAddr{Name: "/tmp/dcrd.sock", Net: "unix"},
+		want: "unknown",
+	}}
+
+	for _, test := range tests {
+		if got := addrNetwork(test.addr); got != test.want {
+			t.Errorf("%s: unexpected network -- got %q, want %q", test.name,
+				got, test.want)
+		}
+	}
+}
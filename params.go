@@ -6,6 +6,11 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/decred/dcrd/chaincfg/v3"
 )
 
@@ -13,7 +18,8 @@ import (
 // network and test networks.
 type params struct {
 	*chaincfg.Params
-	rpcPort string
+	rpcPort  string
+	grpcPort string
 }
 
 // mainNetParams contains parameters specific to the main network
@@ -23,27 +29,73 @@ type params struct {
 // it does not handle on to dcrd.  This approach allows the wallet process
 // to emulate the full reference implementation RPC API.
 var mainNetParams = params{
-	Params:  chaincfg.MainNetParams(),
-	rpcPort: "9109",
+	Params:   chaincfg.MainNetParams(),
+	rpcPort:  "9109",
+	grpcPort: "9112",
 }
 
 // testNet3Params contains parameters specific to the test network (version 3)
 // (wire.TestNet3).
 var testNet3Params = params{
-	Params:  chaincfg.TestNet3Params(),
-	rpcPort: "19109",
+	Params:   chaincfg.TestNet3Params(),
+	rpcPort:  "19109",
+	grpcPort: "19112",
 }
 
 // simNetParams contains parameters specific to the simulation test network
 // (wire.SimNet).
 var simNetParams = params{
-	Params:  chaincfg.SimNetParams(),
-	rpcPort: "19556",
+	Params:   chaincfg.SimNetParams(),
+	rpcPort:  "19556",
+	grpcPort: "19559",
 }
 
 // regNetParams contains parameters specific to the regression test
 // network (wire.RegNet).
 var regNetParams = params{
-	Params:  chaincfg.RegNetParams(),
-	rpcPort: "18656",
+	Params:   chaincfg.RegNetParams(),
+	rpcPort:  "18656",
+	grpcPort: "18659",
+}
+
+// customNetDefinition is the format of the file that defines the parameters of
+// a custom network.  The chain parameters are in the format accepted by
+// chaincfg.ParamsFromJSON.
+type customNetDefinition struct {
+	RPCPort  string          `json:"rpcPort"`
+	GRPCPort string          `json:"grpcPort"`
+	Params   json.RawMessage `json:"params"`
+}
+
+// loadCustomNetParams loads the parameters of a custom network, such as a
+// private network, from the JSON definition in the file at the provided path.
+func loadCustomNetParams(path string) (*params, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def customNetDefinition
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid network definition: %w", err)
+	}
+	if def.RPCPort == "" || def.GRPCPort == "" {
+		return nil, fmt.Errorf("the RPC and gRPC ports of the network are " +
+			"not specified")
+	}
+	if def.Params == nil {
+		return nil, fmt.Errorf("the chain parameters of the network are not " +
+			"specified")
+	}
+	chainParams, err := chaincfg.ParamsFromJSON(def.Params)
+	if err != nil {
+		return nil, err
+	}
+	return &params{
+		Params:   chainParams,
+		rpcPort:  def.RPCPort,
+		grpcPort: def.GRPCPort,
+	}, nil
 }
@@ -1,11 +1,11 @@
 module github.com/decred/dcrd/peer/v3
 
-go 1.17
+go 1.18
 
 require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.3
-	github.com/decred/dcrd/lru v1.1.0
+	github.com/decred/dcrd/container/lru v1.0.0
 	github.com/decred/dcrd/txscript/v4 v4.0.0
 	github.com/decred/dcrd/wire v1.5.0
 	github.com/decred/go-socks v1.1.0
@@ -21,3 +21,8 @@ require (
 	github.com/decred/dcrd/dcrec/edwards/v2 v2.0.2 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.0.1 // indirect
 )
+
+replace (
+	github.com/decred/dcrd/container/lru => ../container/lru
+	github.com/decred/dcrd/wire => ../wire
+)
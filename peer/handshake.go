@@ -0,0 +1,78 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import "fmt"
+
+// HandshakeFailure identifies the reason the initial protocol negotiation with
+// a remote peer failed.
+type HandshakeFailure uint8
+
+const (
+	// HandshakeTimeout indicates the negotiation did not complete within the
+	// allowed time.
+	HandshakeTimeout HandshakeFailure = iota
+
+	// HandshakeReadFailed indicates the version message of the remote peer
+	// could not be read.  This includes malformed messages, messages for the
+	// wrong network, and connections closed by the remote peer.
+	HandshakeReadFailed
+
+	// HandshakeWriteFailed indicates the local version message could not be
+	// created or written to the remote peer.
+	HandshakeWriteFailed
+
+	// HandshakeNoVersion indicates the remote peer sent a message other than
+	// a version message first.
+	HandshakeNoVersion
+
+	// HandshakeSelfConnection indicates the remote peer is the local peer.
+	HandshakeSelfConnection
+
+	// HandshakeObsoleteVersion indicates the remote peer advertised a
+	// protocol version that is too old.
+	HandshakeObsoleteVersion
+
+	// numHandshakeFailures is the number of handshake failure reasons.  It is
+	// only used for tests and must be the final entry.
+	numHandshakeFailures
+)
+
+// handshakeFailureStrings is a map of handshake failure reasons back to their
+// constant names for pretty printing.
+var handshakeFailureStrings = map[HandshakeFailure]string{
+	HandshakeTimeout:         "timeout",
+	HandshakeReadFailed:      "read_failed",
+	HandshakeWriteFailed:     "write_failed",
+	HandshakeNoVersion:       "no_version",
+	HandshakeSelfConnection:  "self_connection",
+	HandshakeObsoleteVersion: "obsolete_version",
+}
+
+// String returns the HandshakeFailure in human-readable form.  The strings are
+// suitable for use as metric labels.
+func (f HandshakeFailure) String() string {
+	if s, ok := handshakeFailureStrings[f]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown HandshakeFailure (%d)", uint8(f))
+}
+
+// handshakeError associates an error that occurred during the initial protocol
+// negotiation with the reason it is classified under.
+type handshakeError struct {
+	reason HandshakeFailure
+	err    error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *handshakeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e *handshakeError) Unwrap() error {
+	return e.err
+}
@@ -19,7 +19,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/decred/dcrd/chaincfg/chainhash"
-	"github.com/decred/dcrd/lru"
+	"github.com/decred/dcrd/container/lru"
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
 	"github.com/decred/slog"
@@ -27,7 +27,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.RemoveRejectVersion
+	MaxProtocolVersion = wire.I2PAddrVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -50,13 +50,16 @@ const (
 
 	// stallResponseTimeout is the base maximum amount of time messages that
 	// expect a response will wait before disconnecting the peer for
-	// stalling.  The deadlines are adjusted for callback running times and
-	// only checked on each stall tick interval.
+	// stalling.  The deadlines are extended by the time it is expected to
+	// take to transfer the response given the observed throughput of the
+	// peer, adjusted for callback running times, and only checked on each
+	// stall tick interval.
 	stallResponseTimeout = 30 * time.Second
 
-	// trickleTimeout is the duration of the ticker which trickles down the
-	// inventory to a peer.
-	trickleTimeout = 500 * time.Millisecond
+	// defaultTrickleInterval is the default average interval between the
+	// batches of inventory trickled to a peer when a peer is created with the
+	// trickle interval configuration option set to 0.
+	defaultTrickleInterval = 500 * time.Millisecond
 
 	// defaultIdleTimeout is the default duration of inactivity before a peer is
 	// timed out when a peer is created with the idle timeout configuration
@@ -75,7 +78,7 @@ var (
 
 	// sentNonces houses the unique nonces that are generated when pushing
 	// version messages that are used to detect self connections.
-	sentNonces = lru.NewCache(50)
+	sentNonces = lru.NewSet[uint64](50)
 
 	// allowSelfConns is only used to allow the tests to bypass the self
 	// connection detecting and disconnect logic since they intentionally
@@ -99,6 +102,9 @@ type MessageListeners struct {
 	// OnAddr is invoked when a peer receives an addr wire message.
 	OnAddr func(p *Peer, msg *wire.MsgAddr)
 
+	// OnAddrV2 is invoked when a peer receives an addrv2 wire message.
+	OnAddrV2 func(p *Peer, msg *wire.MsgAddrV2)
+
 	// OnPing is invoked when a peer receives a ping wire message.
 	OnPing func(p *Peer, msg *wire.MsgPing)
 
@@ -196,6 +202,34 @@ type MessageListeners struct {
 	// OnInitState is invoked when a peer receives an initstate message.
 	OnInitState func(p *Peer, msg *wire.MsgInitState)
 
+	// OnSendTxRecon is invoked when a peer receives a sendtxrcncl wire
+	// message.
+	OnSendTxRecon func(p *Peer, msg *wire.MsgSendTxRecon)
+
+	// OnReqRecon is invoked when a peer receives a reqrecon wire message.
+	OnReqRecon func(p *Peer, msg *wire.MsgReqRecon)
+
+	// OnSketch is invoked when a peer receives a sketch wire message.
+	OnSketch func(p *Peer, msg *wire.MsgSketch)
+
+	// OnReconcilDiff is invoked when a peer receives a reconcildiff wire
+	// message.
+	OnReconcilDiff func(p *Peer, msg *wire.MsgReconcilDiff)
+
+	// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.
+	OnSendCmpct func(p *Peer, msg *wire.MsgSendCmpct)
+
+	// OnCmpctBlock is invoked when a peer receives a cmpctblock wire
+	// message.
+	OnCmpctBlock func(p *Peer, msg *wire.MsgCmpctBlock)
+
+	// OnGetBlockTxn is invoked when a peer receives a getblocktxn wire
+	// message.
+	OnGetBlockTxn func(p *Peer, msg *wire.MsgGetBlockTxn)
+
+	// OnBlockTxn is invoked when a peer receives a blocktxn wire message.
+	OnBlockTxn func(p *Peer, msg *wire.MsgBlockTxn)
+
 	// OnRead is invoked when a peer receives a wire message.  It consists
 	// of the number of bytes read, the message, and whether or not an error
 	// in the read occurred.  Typically, callers will opt to use the
@@ -268,9 +302,35 @@ type Config struct {
 	// messages.
 	Listeners MessageListeners
 
+	// OnHandshakeFailure is invoked when the initial protocol negotiation
+	// with the remote peer fails along with the reason and underlying error.
+	// It is not invoked when the peer is disconnected locally during the
+	// negotiation.  This is optional, so it may be nil.
+	OnHandshakeFailure func(p *Peer, reason HandshakeFailure, err error)
+
 	// IdleTimeout is the duration of inactivity before a peer is timed
 	// out in seconds.
 	IdleTimeout time.Duration
+
+	// TrickleInterval is the average interval between the batches of
+	// inventory trickled to the peer.  The actual intervals are randomized
+	// such that the batches are sent according to a Poisson process, which
+	// makes it harder to infer the origin of transactions from the timing
+	// of their announcements.  Defaults to 500ms when not set.
+	TrickleInterval time.Duration
+
+	// DownloadLimiters specifies the rate limiters that govern how quickly
+	// messages are read from the remote peer.  Each message read is charged
+	// against every limiter and the next message is not read until all of
+	// them permit it.  This is optional, so it may be nil in which case
+	// reads are not limited.
+	DownloadLimiters []*RateLimiter
+
+	// UploadLimiters specifies the rate limiters that govern how quickly
+	// messages are written to the remote peer in the same manner as
+	// DownloadLimiters.  This is optional, so it may be nil in which case
+	// writes are not limited.
+	UploadLimiters []*RateLimiter
 }
 
 // minUint32 is a helper function to return the minimum of two uint32s.
@@ -352,6 +412,26 @@ const (
 type stallControlMsg struct {
 	command stallControlCmd
 	message wire.Message
+
+	// size is the number of bytes read for received messages.
+	size int
+}
+
+// pendingResponse houses the details of a response that is expected from the
+// remote peer.
+type pendingResponse struct {
+	// deadline is the time by which the response is expected before
+	// adjusting for callback running times.
+	deadline time.Time
+
+	// requested is the time the request for the response was sent.
+	requested time.Time
+
+	// handlersDuration is the total time spent executing callbacks at the
+	// time the request was sent.  It is used to exclude the time spent
+	// executing callbacks while waiting for the response from the observed
+	// throughput of the peer.
+	handlersDuration time.Duration
 }
 
 // StatsSnap is a snapshot of peer stats at a point in time.
@@ -373,8 +453,28 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+
+	// PingStats houses statistics about the round-trip times of all answered
+	// pings including a histogram of them.
+	PingStats PingStats
+
+	// BytesSentPerMsg and BytesRecvPerMsg are the total number of bytes sent
+	// and received, respectively, keyed by message command.  Bytes that are
+	// not attributable to a known message are keyed by OtherMsgCmd.
+	BytesSentPerMsg map[string]uint64
+	BytesRecvPerMsg map[string]uint64
+
+	// MsgsSentPerMsg and MsgsRecvPerMsg are the total number of messages sent
+	// and received, respectively, keyed by message command.
+	MsgsSentPerMsg map[string]uint64
+	MsgsRecvPerMsg map[string]uint64
 }
 
+// OtherMsgCmd is the key used to account for bytes that are not attributable
+// to a known message, such as those read prior to a failure to decode a
+// message.
+const OtherMsgCmd = "*other*"
+
 // HashFunc is a function which returns a block hash, height and error
 // It is used as a callback to get newest block details.
 type HashFunc func() (hash *chainhash.Hash, height int64, err error)
@@ -442,7 +542,7 @@ type Peer struct {
 	versionSent          bool
 	verAckReceived       bool
 
-	knownInventory     lru.Cache
+	knownInventory     *lru.Set[wire.InvVect]
 	prevGetBlocksMtx   sync.Mutex
 	prevGetBlocksBegin *chainhash.Hash
 	prevGetBlocksStop  *chainhash.Hash
@@ -452,14 +552,19 @@ type Peer struct {
 
 	// These fields keep track of statistics for the peer and are protected
 	// by the statsMtx mutex.
-	statsMtx       sync.RWMutex
-	timeOffset     int64
-	timeConnected  time.Time
-	startingHeight int64
-	lastBlock      int64
-	lastPingNonce  uint64    // Set to nonce if we have a pending ping.
-	lastPingTime   time.Time // Time we sent last ping.
-	lastPingMicros int64     // Time for last ping to return.
+	statsMtx        sync.RWMutex
+	timeOffset      int64
+	timeConnected   time.Time
+	startingHeight  int64
+	lastBlock       int64
+	lastPingNonce   uint64    // Set to nonce if we have a pending ping.
+	lastPingTime    time.Time // Time we sent last ping.
+	lastPingMicros  int64     // Time for last ping to return.
+	pingStats       PingStats
+	bytesSentPerMsg map[string]uint64
+	bytesRecvPerMsg map[string]uint64
+	msgsSentPerMsg  map[string]uint64
+	msgsRecvPerMsg  map[string]uint64
 
 	stallControl  chan stallControlMsg
 	outputQueue   chan outMsg
@@ -500,7 +605,9 @@ func (p *Peer) UpdateLastBlockHeight(newHeight int64) {
 //
 // This function is safe for concurrent access.
 func (p *Peer) AddKnownInventory(invVect *wire.InvVect) {
-	p.knownInventory.Add(invVect)
+	// The inventory is stored by value so that lookups match regardless of
+	// which instance of the inventory vector is provided.
+	p.knownInventory.Put(*invVect)
 }
 
 // IsKnownInventory returns whether the passed inventory already exists in
@@ -508,7 +615,7 @@ func (p *Peer) AddKnownInventory(invVect *wire.InvVect) {
 //
 // This function is safe for concurrent access.
 func (p *Peer) IsKnownInventory(invVect *wire.InvVect) bool {
-	return p.knownInventory.Contains(invVect)
+	return p.knownInventory.Contains(*invVect)
 }
 
 // StatsSnapshot returns a snapshot of the current peer flags and statistics.
@@ -527,23 +634,40 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 
 	// Get a copy of all relevant flags and stats.
 	statsSnap := &StatsSnap{
-		ID:             id,
-		Addr:           addr,
-		UserAgent:      userAgent,
-		Services:       services,
-		LastSend:       p.LastSend(),
-		LastRecv:       p.LastRecv(),
-		BytesSent:      p.BytesSent(),
-		BytesRecv:      p.BytesReceived(),
-		ConnTime:       p.timeConnected,
-		TimeOffset:     p.timeOffset,
-		Version:        protocolVersion,
-		Inbound:        p.inbound,
-		StartingHeight: p.startingHeight,
-		LastBlock:      p.lastBlock,
-		LastPingNonce:  p.lastPingNonce,
-		LastPingMicros: p.lastPingMicros,
-		LastPingTime:   p.lastPingTime,
+		ID:              id,
+		Addr:            addr,
+		UserAgent:       userAgent,
+		Services:        services,
+		LastSend:        p.LastSend(),
+		LastRecv:        p.LastRecv(),
+		BytesSent:       p.BytesSent(),
+		BytesRecv:       p.BytesReceived(),
+		ConnTime:        p.timeConnected,
+		TimeOffset:      p.timeOffset,
+		Version:         protocolVersion,
+		Inbound:         p.inbound,
+		StartingHeight:  p.startingHeight,
+		LastBlock:       p.lastBlock,
+		LastPingNonce:   p.lastPingNonce,
+		LastPingMicros:  p.lastPingMicros,
+		LastPingTime:    p.lastPingTime,
+		PingStats:       p.pingStats,
+		BytesSentPerMsg: make(map[string]uint64, len(p.bytesSentPerMsg)),
+		BytesRecvPerMsg: make(map[string]uint64, len(p.bytesRecvPerMsg)),
+		MsgsSentPerMsg:  make(map[string]uint64, len(p.msgsSentPerMsg)),
+		MsgsRecvPerMsg:  make(map[string]uint64, len(p.msgsRecvPerMsg)),
+	}
+	for cmd, n := range p.bytesSentPerMsg {
+		statsSnap.BytesSentPerMsg[cmd] = n
+	}
+	for cmd, n := range p.bytesRecvPerMsg {
+		statsSnap.BytesRecvPerMsg[cmd] = n
+	}
+	for cmd, n := range p.msgsSentPerMsg {
+		statsSnap.MsgsSentPerMsg[cmd] = n
+	}
+	for cmd, n := range p.msgsRecvPerMsg {
+		statsSnap.MsgsRecvPerMsg[cmd] = n
 	}
 
 	p.statsMtx.RUnlock()
@@ -815,6 +939,39 @@ func (p *Peer) PushAddrMsg(addresses []*wire.NetAddress) ([]*wire.NetAddress, er
 	return msg.AddrList, nil
 }
 
+// PushAddrV2Msg sends an addrv2 message to the connected peer using the
+// provided addresses.  It is the addrv2 equivalent of PushAddrMsg and must only
+// be used with peers that negotiated a protocol version of at least
+// wire.AddrV2Version.  It returns the addresses that were actually sent and no
+// message will be sent if there are no entries in the provided addresses slice.
+//
+// This function is safe for concurrent access.
+func (p *Peer) PushAddrV2Msg(addresses []*wire.NetAddressV2) ([]*wire.NetAddressV2, error) {
+	// Nothing to send.
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	msg := wire.NewMsgAddrV2()
+	msg.AddrList = make([]*wire.NetAddressV2, len(addresses))
+	copy(msg.AddrList, addresses)
+
+	// Randomize the addresses sent if there are more than the maximum allowed.
+	if len(msg.AddrList) > wire.MaxAddrPerV2Msg {
+		// Shuffle the address list.
+		for i := range msg.AddrList {
+			j := rand.Intn(i + 1)
+			msg.AddrList[i], msg.AddrList[j] = msg.AddrList[j], msg.AddrList[i]
+		}
+
+		// Truncate it to the maximum size.
+		msg.AddrList = msg.AddrList[:wire.MaxAddrPerV2Msg]
+	}
+
+	p.QueueMessage(msg, nil)
+	return msg.AddrList, nil
+}
+
 // PushGetBlocksMsg sends a getblocks message for the provided block locator
 // and stop hash.  It will ignore back-to-back duplicate requests.
 //
@@ -927,29 +1084,38 @@ func (p *Peer) handlePongMsg(msg *wire.MsgPong) {
 	// enough that if they overlap we would have timed out the peer.
 	p.statsMtx.Lock()
 	if p.lastPingNonce != 0 && msg.Nonce == p.lastPingNonce {
-		p.lastPingMicros = time.Since(p.lastPingTime).Nanoseconds()
-		p.lastPingMicros /= 1000 // convert to usec.
+		rtt := time.Since(p.lastPingTime)
+		p.lastPingMicros = rtt.Microseconds()
 		p.lastPingNonce = 0
+		p.pingStats.add(rtt)
 	}
 	p.statsMtx.Unlock()
 }
 
-// readMessage reads the next wire message from the peer with logging.
-func (p *Peer) readMessage() (wire.Message, []byte, error) {
+// readMessage reads the next wire message from the peer with logging and
+// returns the number of bytes read along with the message.  The returned raw
+// bytes of messages other than blocks are borrowed from the wire payload pool
+// and may be released via wire.ReleasePayload once they are no longer needed.
+func (p *Peer) readMessage() (int, wire.Message, []byte, error) {
 	err := p.conn.SetReadDeadline(time.Now().Add(p.cfg.IdleTimeout))
 	if err != nil {
-		return nil, nil, err
+		return 0, nil, nil, err
 	}
-	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
+	n, msg, buf, err := wire.ReadPooledMessageN(p.conn, p.ProtocolVersion(),
 		p.cfg.Net)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
+	p.addMsgStats(p.bytesRecvPerMsg, p.msgsRecvPerMsg, msg, n)
 	if p.cfg.Listeners.OnRead != nil {
 		p.cfg.Listeners.OnRead(p, n, msg, err)
 	}
 	if err != nil {
-		return nil, nil, err
+		return n, nil, nil, err
 	}
 
+	// Delay reading the next message as needed to respect the configured
+	// download rate limits.
+	p.throttle(p.cfg.DownloadLimiters, n)
+
 	// Only construct expensive log strings when the logging level requires it.
 	if log.Level() <= slog.LevelDebug {
 		// Debug summary of message.
@@ -964,7 +1130,7 @@ func (p *Peer) readMessage() (wire.Message, []byte, error) {
 		log.Trace(spew.Sdump(buf))
 	}
 
-	return msg, buf, nil
+	return n, msg, buf, nil
 }
 
 // writeMessage sends a wire message to the peer with logging.
@@ -996,10 +1162,67 @@ func (p *Peer) writeMessage(msg wire.Message) error {
 	// Write the message to the peer.
 	n, err := wire.WriteMessageN(p.conn, msg, p.ProtocolVersion(), p.cfg.Net)
 	atomic.AddUint64(&p.bytesSent, uint64(n))
+	p.addMsgStats(p.bytesSentPerMsg, p.msgsSentPerMsg, msg, n)
 	if p.cfg.Listeners.OnWrite != nil {
 		p.cfg.Listeners.OnWrite(p, n, msg, err)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Delay writing the next message as needed to respect the configured
+	// upload rate limits.
+	p.throttle(p.cfg.UploadLimiters, n)
+	return nil
+}
+
+// addMsgStats adds the provided number of bytes to the entry for the command
+// of the provided message in the given per-message byte counts and increments
+// the entry in the given per-message message counts.  The bytes are attributed
+// to OtherMsgCmd and no message is counted when the message is nil.
+//
+// This function is safe for concurrent access.
+func (p *Peer) addMsgStats(byteCounts, msgCounts map[string]uint64, msg wire.Message, n int) {
+	if n == 0 {
+		return
+	}
+	cmd := OtherMsgCmd
+	if msg != nil {
+		cmd = msg.Command()
+	}
+	p.statsMtx.Lock()
+	byteCounts[cmd] += uint64(n)
+	if msg != nil {
+		msgCounts[cmd]++
+	}
+	p.statsMtx.Unlock()
+}
+
+// throttle charges the provided number of bytes against all of the provided
+// rate limiters and blocks until all of them permit further transfers or the
+// peer is disconnected.
+func (p *Peer) throttle(limiters []*RateLimiter, n int) {
+	if len(limiters) == 0 || n == 0 {
+		return
+	}
+
+	var wait time.Duration
+	now := time.Now()
+	for _, limiter := range limiters {
+		if d := limiter.reserve(n, now); d > wait {
+			wait = d
+		}
+	}
+	if wait <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-p.quit:
+	}
 }
 
 // shouldHandleReadError returns whether or not the passed error, which is
@@ -1029,8 +1252,11 @@ func (p *Peer) shouldHandleReadError(err error) bool {
 }
 
 // maybeAddDeadline potentially adds a deadline for the appropriate expected
-// response for the passed wire protocol command to the pending responses map.
-func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd string) {
+// response for the passed wire protocol message to the pending responses map.
+// The deadline is extended by the time it is expected to take to transfer the
+// response given the provided estimated throughput of the remote peer so that
+// large responses from slow peers are not mistakenly considered stalled.
+func (p *Peer) maybeAddDeadline(pendingResponses map[string]pendingResponse, msg wire.Message, throughput *throughputEstimator, handlersDuration time.Duration) {
 	// Setup a deadline for each message being sent that expects a response.
 	//
 	// NOTE: Pings are intentionally ignored here since they are typically
@@ -1041,7 +1267,14 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 	// Also, getheaders is intentionally ignored since there is no guaranteed
 	// response if the remote peer does not have any headers for the locator.
 	var addedDeadline bool
-	deadline := time.Now().Add(stallResponseTimeout)
+	now := time.Now()
+	timeout := throughput.responseTimeout(expectedResponseSize(msg))
+	deadline := pendingResponse{
+		deadline:         now.Add(timeout),
+		requested:        now,
+		handlersDuration: handlersDuration,
+	}
+	msgCmd := msg.Command()
 	switch msgCmd {
 	case wire.CmdVersion:
 		// Expects a verack message.
@@ -1075,7 +1308,8 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 	}
 
 	if addedDeadline {
-		log.Debugf("Adding deadline for command %s for peer %s", msgCmd, p.addr)
+		log.Debugf("Adding deadline of %v for command %s for peer %s",
+			timeout, msgCmd, p.addr)
 	}
 }
 
@@ -1092,8 +1326,18 @@ func (p *Peer) stallHandler() {
 	var handlersStartTime time.Time
 	var deadlineOffset time.Duration
 
-	// pendingResponses tracks the expected response deadline times.
-	pendingResponses := make(map[string]time.Time)
+	// handlersDuration is the total time spent executing callbacks.  It is
+	// used to exclude the time spent executing callbacks from the observed
+	// throughput of the remote peer.
+	var handlersDuration time.Duration
+
+	// pendingResponses tracks the expected responses and their deadlines.
+	pendingResponses := make(map[string]pendingResponse)
+
+	// throughput estimates the rate at which the remote peer delivers the
+	// responses to requests and is used to adapt the deadlines to the
+	// expected size of the responses.
+	var throughput throughputEstimator
 
 	// stallTicker is used to periodically check pending responses that have
 	// exceeded the expected deadline and disconnect the peer due to
@@ -1112,15 +1356,26 @@ out:
 			case sccSendMessage:
 				// Add a deadline for the expected response
 				// message if needed.
-				p.maybeAddDeadline(pendingResponses,
-					msg.message.Command())
+				p.maybeAddDeadline(pendingResponses, msg.message,
+					&throughput, handlersDuration)
 
 			case sccReceiveMessage:
+				// Update the observed throughput of the remote
+				// peer when the message is an expected response
+				// while excluding the time spent executing
+				// callbacks since it was requested.
+				msgCmd := msg.message.Command()
+				if pending, ok := pendingResponses[msgCmd]; ok {
+					elapsed := time.Since(pending.requested) -
+						(handlersDuration - pending.handlersDuration)
+					throughput.addSample(msg.size, elapsed)
+				}
+
 				// Remove received messages from the expected
 				// response map.  Since certain commands expect
 				// one of a group of responses, remove
 				// everything in the expected group accordingly.
-				switch msgCmd := msg.message.Command(); msgCmd {
+				switch msgCmd {
 				case wire.CmdBlock:
 					fallthrough
 				case wire.CmdTx:
@@ -1159,6 +1414,7 @@ out:
 				// to execute the callback.
 				duration := time.Since(handlersStartTime)
 				deadlineOffset += duration
+				handlersDuration += duration
 				handlerActive = false
 
 			default:
@@ -1178,11 +1434,12 @@ out:
 
 			// Disconnect the peer if any of the pending responses
 			// don't arrive by their adjusted deadline.
-			for command, deadline := range pendingResponses {
+			for command, pending := range pendingResponses {
 				if command == wire.CmdMiningState {
 					continue
 				}
 
+				deadline := pending.deadline
 				if now.Before(deadline.Add(offset)) {
 					log.Debugf("Stall ticker rolling over for peer %s on "+
 						"cmd %s (deadline for data: %s)", p, command,
@@ -1239,7 +1496,7 @@ out:
 		// Read a message and stop the idle timer as soon as the read
 		// is done.  The timer is reset below for the next iteration if
 		// needed.
-		rmsg, buf, err := p.readMessage()
+		n, rmsg, buf, err := p.readMessage()
 		if err != nil {
 			// Only log the error if the local peer is not forcibly
 			// disconnecting and the remote peer has not disconnected.
@@ -1256,10 +1513,18 @@ out:
 			break out
 		}
 		atomic.StoreInt64(&p.lastRecv, time.Now().Unix())
-		p.stallControl <- stallControlMsg{sccReceiveMessage, rmsg}
+		p.stallControl <- stallControlMsg{sccReceiveMessage, rmsg, n}
+
+		// The raw bytes are only provided to the block listener, which
+		// owns them since block payloads are not pooled, so return them to
+		// the pool right away for all other messages.
+		if _, ok := rmsg.(*wire.MsgBlock); !ok {
+			wire.ReleasePayload(buf)
+			buf = nil
+		}
 
 		// Handle each supported message type.
-		p.stallControl <- stallControlMsg{sccHandlerStart, rmsg}
+		p.stallControl <- stallControlMsg{sccHandlerStart, rmsg, 0}
 		switch msg := rmsg.(type) {
 		case *wire.MsgVersion:
 			// Limit to one version message per peer.
@@ -1292,6 +1557,11 @@ out:
 				p.cfg.Listeners.OnAddr(p, msg)
 			}
 
+		case *wire.MsgAddrV2:
+			if p.cfg.Listeners.OnAddrV2 != nil {
+				p.cfg.Listeners.OnAddrV2(p, msg)
+			}
+
 		case *wire.MsgPing:
 			p.handlePingMsg(msg)
 			if p.cfg.Listeners.OnPing != nil {
@@ -1428,11 +1698,51 @@ out:
 				p.cfg.Listeners.OnInitState(p, msg)
 			}
 
+		case *wire.MsgSendTxRecon:
+			if p.cfg.Listeners.OnSendTxRecon != nil {
+				p.cfg.Listeners.OnSendTxRecon(p, msg)
+			}
+
+		case *wire.MsgReqRecon:
+			if p.cfg.Listeners.OnReqRecon != nil {
+				p.cfg.Listeners.OnReqRecon(p, msg)
+			}
+
+		case *wire.MsgSketch:
+			if p.cfg.Listeners.OnSketch != nil {
+				p.cfg.Listeners.OnSketch(p, msg)
+			}
+
+		case *wire.MsgReconcilDiff:
+			if p.cfg.Listeners.OnReconcilDiff != nil {
+				p.cfg.Listeners.OnReconcilDiff(p, msg)
+			}
+
+		case *wire.MsgSendCmpct:
+			if p.cfg.Listeners.OnSendCmpct != nil {
+				p.cfg.Listeners.OnSendCmpct(p, msg)
+			}
+
+		case *wire.MsgCmpctBlock:
+			if p.cfg.Listeners.OnCmpctBlock != nil {
+				p.cfg.Listeners.OnCmpctBlock(p, msg)
+			}
+
+		case *wire.MsgGetBlockTxn:
+			if p.cfg.Listeners.OnGetBlockTxn != nil {
+				p.cfg.Listeners.OnGetBlockTxn(p, msg)
+			}
+
+		case *wire.MsgBlockTxn:
+			if p.cfg.Listeners.OnBlockTxn != nil {
+				p.cfg.Listeners.OnBlockTxn(p, msg)
+			}
+
 		default:
 			log.Debugf("Received unhandled message of type %v "+
 				"from %v", rmsg.Command(), p)
 		}
-		p.stallControl <- stallControlMsg{sccHandlerDone, rmsg}
+		p.stallControl <- stallControlMsg{sccHandlerDone, rmsg, 0}
 	}
 
 	// Ensure connection is closed.
@@ -1442,6 +1752,14 @@ out:
 	log.Tracef("Peer input handler done for %s", p)
 }
 
+// nextTrickleDelay returns a random delay until the next batch of inventory is
+// trickled to the peer.  The delays are drawn from an exponential distribution
+// with a mean of the configured trickle interval so that the batches are sent
+// according to a Poisson process.
+func (p *Peer) nextTrickleDelay() time.Duration {
+	return time.Duration(rand.ExpFloat64() * float64(p.cfg.TrickleInterval))
+}
+
 // queueHandler handles the queuing of outgoing data for the peer. This runs as
 // a muxer for various sources of input so we can ensure that server and peer
 // handlers will not block on us sending a message.  That data is then passed on
@@ -1449,8 +1767,9 @@ out:
 func (p *Peer) queueHandler() {
 	var pendingMsgs []outMsg
 	var invSendQueue []*wire.InvVect
-	trickleTicker := time.NewTicker(trickleTimeout)
-	defer trickleTicker.Stop()
+	invSendSet := make(map[wire.InvVect]struct{})
+	trickleTimer := time.NewTimer(p.nextTrickleDelay())
+	defer trickleTimer.Stop()
 
 	// We keep the waiting flag so that we know if we have a message queued
 	// to the outHandler or not.  We could use the presence of a head of
@@ -1495,11 +1814,21 @@ out:
 
 		case iv := <-p.outputInvChan:
 			// No handshake?  They'll find out soon enough.
-			if p.VersionKnown() {
-				invSendQueue = append(invSendQueue, iv)
+			if !p.VersionKnown() {
+				continue
+			}
+
+			// Batch each inventory item only once regardless of how
+			// many times it is queued before the next trickle.
+			if _, ok := invSendSet[*iv]; ok {
+				continue
 			}
+			invSendSet[*iv] = struct{}{}
+			invSendQueue = append(invSendQueue, iv)
+
+		case <-trickleTimer.C:
+			trickleTimer.Reset(p.nextTrickleDelay())
 
-		case <-trickleTicker.C:
 			// Don't send anything if we're disconnecting or there
 			// is no queued inventory.
 			// version is known if send queue has any entries.
@@ -1515,7 +1844,7 @@ out:
 			for _, iv := range invSendQueue {
 				// Don't send inventory that became known after
 				// the initial check.
-				if p.knownInventory.Contains(iv) {
+				if p.IsKnownInventory(iv) {
 					continue
 				}
 
@@ -1536,6 +1865,7 @@ out:
 					&pendingMsgs, waiting)
 			}
 			invSendQueue = nil
+			invSendSet = make(map[wire.InvVect]struct{})
 
 		case <-p.quit:
 			break out
@@ -1609,7 +1939,7 @@ out:
 				p.statsMtx.Unlock()
 			}
 
-			p.stallControl <- stallControlMsg{sccSendMessage, msg.msg}
+			p.stallControl <- stallControlMsg{sccSendMessage, msg.msg, 0}
 			if err := p.writeMessage(msg.msg); err != nil {
 				p.Disconnect()
 				if p.shouldLogWriteError(err) {
@@ -1694,7 +2024,7 @@ func (p *Peer) QueueMessage(msg wire.Message, doneChan chan<- struct{}) {
 func (p *Peer) QueueInventory(invVect *wire.InvVect) {
 	// Don't add the inventory to the send queue if the peer is already
 	// known to have it.
-	if p.knownInventory.Contains(invVect) {
+	if p.IsKnownInventory(invVect) {
 		return
 	}
 
@@ -1717,7 +2047,7 @@ func (p *Peer) QueueInventory(invVect *wire.InvVect) {
 // This function is safe for concurrent access.
 func (p *Peer) QueueInventoryImmediate(invVect *wire.InvVect) {
 	// Don't announce the inventory if the peer is already known to have it.
-	if p.knownInventory.Contains(invVect) {
+	if p.IsKnownInventory(invVect) {
 		return
 	}
 
@@ -1763,20 +2093,22 @@ func (p *Peer) Disconnect() {
 // acceptable then return an error.
 func (p *Peer) readRemoteVersionMsg() error {
 	// Read their version message.
-	remoteMsg, _, err := p.readMessage()
+	_, remoteMsg, _, err := p.readMessage()
 	if err != nil {
-		return err
+		return &handshakeError{HandshakeReadFailed, err}
 	}
 
 	// Disconnect clients if the first message is not a version message.
 	msg, ok := remoteMsg.(*wire.MsgVersion)
 	if !ok {
-		return errors.New("a version message must precede all others")
+		err := errors.New("a version message must precede all others")
+		return &handshakeError{HandshakeNoVersion, err}
 	}
 
 	// Detect self connections.
 	if !allowSelfConns && sentNonces.Contains(msg.Nonce) {
-		return errors.New("disconnecting peer connected to self")
+		err := errors.New("disconnecting peer connected to self")
+		return &handshakeError{HandshakeSelfConnection, err}
 	}
 
 	// Negotiate the protocol version and set the services to what the remote
@@ -1813,8 +2145,9 @@ func (p *Peer) readRemoteVersionMsg() error {
 
 	// Disconnect clients that have a protocol version that is too old.
 	if msg.ProtocolVersion < int32(wire.InitialProcotolVersion) {
-		return fmt.Errorf("protocol version must be %d or greater",
+		err := fmt.Errorf("protocol version must be %d or greater",
 			wire.InitialProcotolVersion)
+		return &handshakeError{HandshakeObsoleteVersion, err}
 	}
 
 	return nil
@@ -1866,7 +2199,7 @@ func (p *Peer) localVersionMsg() (*wire.MsgVersion, error) {
 	if err != nil {
 		return nil, err
 	}
-	sentNonces.Add(nonce)
+	sentNonces.Put(nonce)
 
 	// Version message.
 	msg := wire.NewMsgVersion(ourNA, theirNA, nonce, int32(blockNum))
@@ -1889,11 +2222,11 @@ func (p *Peer) localVersionMsg() (*wire.MsgVersion, error) {
 func (p *Peer) writeLocalVersionMsg() error {
 	localVerMsg, err := p.localVersionMsg()
 	if err != nil {
-		return err
+		return &handshakeError{HandshakeWriteFailed, err}
 	}
 
 	if err := p.writeMessage(localVerMsg); err != nil {
-		return err
+		return &handshakeError{HandshakeWriteFailed, err}
 	}
 
 	p.flagsMtx.Lock()
@@ -1924,6 +2257,24 @@ func (p *Peer) negotiateOutboundProtocol() error {
 	return p.readRemoteVersionMsg()
 }
 
+// handshakeFailed disconnects the peer after the initial protocol negotiation
+// failed with the provided error and notifies the handshake failure callback,
+// if any, unless the peer was already disconnected locally.
+func (p *Peer) handshakeFailed(err error) {
+	disconnected := atomic.LoadInt32(&p.disconnect) != 0
+	p.Disconnect()
+	if disconnected || p.cfg.OnHandshakeFailure == nil {
+		return
+	}
+
+	reason := HandshakeReadFailed
+	var hsErr *handshakeError
+	if errors.As(err, &hsErr) {
+		reason = hsErr.reason
+	}
+	p.cfg.OnHandshakeFailure(p, reason, err)
+}
+
 // start begins processing input and output messages.
 func (p *Peer) start() error {
 	log.Tracef("Starting peer %s", p)
@@ -1941,12 +2292,14 @@ func (p *Peer) start() error {
 	select {
 	case err := <-negotiateErr:
 		if err != nil {
-			p.Disconnect()
+			p.handshakeFailed(err)
 			return err
 		}
 	case <-time.After(negotiateTimeout):
-		p.Disconnect()
-		return errors.New("protocol negotiation timeout")
+		err := &handshakeError{HandshakeTimeout,
+			errors.New("protocol negotiation timeout")}
+		p.handshakeFailed(err)
+		return err
 	}
 	log.Debugf("Connected to %s", p.Addr())
 
@@ -2030,9 +2383,14 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		cfg.IdleTimeout = defaultIdleTimeout
 	}
 
+	// Set a default trickle interval if the caller did not specify one.
+	if cfg.TrickleInterval == 0 {
+		cfg.TrickleInterval = defaultTrickleInterval
+	}
+
 	p := Peer{
 		inbound:         inbound,
-		knownInventory:  lru.NewCache(maxKnownInventory),
+		knownInventory:  lru.NewSet[wire.InvVect](maxKnownInventory),
 		stallControl:    make(chan stallControlMsg, 1), // nonblocking sync
 		outputQueue:     make(chan outMsg, outputBufferSize),
 		sendQueue:       make(chan outMsg, 1),   // nonblocking sync
@@ -2042,6 +2400,10 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		queueQuit:       make(chan struct{}),
 		outQuit:         make(chan struct{}),
 		quit:            make(chan struct{}),
+		bytesSentPerMsg: make(map[string]uint64),
+		bytesRecvPerMsg: make(map[string]uint64),
+		msgsSentPerMsg:  make(map[string]uint64),
+		msgsRecvPerMsg:  make(map[string]uint64),
 		cfg:             cfg,
 		services:        cfg.Services,
 		protocolVersion: protocolVersion,
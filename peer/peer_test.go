@@ -6,6 +6,7 @@
 package peer
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"io"
@@ -104,6 +105,8 @@ type peerStats struct {
 	wantTimeOffset      int64
 	wantBytesSent       uint64
 	wantBytesReceived   uint64
+	wantMsgsSent        uint64
+	wantMsgsReceived    uint64
 }
 
 // testPeer tests the given peer's flags and stats.
@@ -202,6 +205,38 @@ func testPeer(t *testing.T, p *Peer, s peerStats) {
 		t.Errorf("testPeer: wrong LastRecv - got %v, want %v", p.LastRecv(), stats.LastRecv)
 		return
 	}
+
+	var bytesSent, bytesRecv uint64
+	for _, n := range stats.BytesSentPerMsg {
+		bytesSent += n
+	}
+	for _, n := range stats.BytesRecvPerMsg {
+		bytesRecv += n
+	}
+	if bytesSent != s.wantBytesSent {
+		t.Errorf("testPeer: wrong BytesSentPerMsg total - got %v, want %v", bytesSent, s.wantBytesSent)
+		return
+	}
+	if bytesRecv != s.wantBytesReceived {
+		t.Errorf("testPeer: wrong BytesRecvPerMsg total - got %v, want %v", bytesRecv, s.wantBytesReceived)
+		return
+	}
+
+	var msgsSent, msgsRecv uint64
+	for _, n := range stats.MsgsSentPerMsg {
+		msgsSent += n
+	}
+	for _, n := range stats.MsgsRecvPerMsg {
+		msgsRecv += n
+	}
+	if msgsSent != s.wantMsgsSent {
+		t.Errorf("testPeer: wrong MsgsSentPerMsg total - got %v, want %v", msgsSent, s.wantMsgsSent)
+		return
+	}
+	if msgsRecv != s.wantMsgsReceived {
+		t.Errorf("testPeer: wrong MsgsRecvPerMsg total - got %v, want %v", msgsRecv, s.wantMsgsReceived)
+		return
+	}
 }
 
 // TestPeerConnection tests connection between inbound and outbound peers.
@@ -242,6 +277,8 @@ func TestPeerConnection(t *testing.T) {
 		wantTimeOffset:      int64(0),
 		wantBytesSent:       158, // 134 version + 24 verack
 		wantBytesReceived:   158,
+		wantMsgsSent:        2, // version + verack
+		wantMsgsReceived:    2,
 	}
 	tests := []struct {
 		name  string
@@ -332,6 +369,9 @@ func TestPeerListeners(t *testing.T) {
 			OnAddr: func(p *Peer, msg *wire.MsgAddr) {
 				ok <- msg
 			},
+			OnAddrV2: func(p *Peer, msg *wire.MsgAddrV2) {
+				ok <- msg
+			},
 			OnPing: func(p *Peer, msg *wire.MsgPing) {
 				ok <- msg
 			},
@@ -410,6 +450,30 @@ func TestPeerListeners(t *testing.T) {
 			OnInitState: func(p *Peer, msg *wire.MsgInitState) {
 				ok <- msg
 			},
+			OnSendTxRecon: func(p *Peer, msg *wire.MsgSendTxRecon) {
+				ok <- msg
+			},
+			OnReqRecon: func(p *Peer, msg *wire.MsgReqRecon) {
+				ok <- msg
+			},
+			OnSketch: func(p *Peer, msg *wire.MsgSketch) {
+				ok <- msg
+			},
+			OnReconcilDiff: func(p *Peer, msg *wire.MsgReconcilDiff) {
+				ok <- msg
+			},
+			OnSendCmpct: func(p *Peer, msg *wire.MsgSendCmpct) {
+				ok <- msg
+			},
+			OnCmpctBlock: func(p *Peer, msg *wire.MsgCmpctBlock) {
+				ok <- msg
+			},
+			OnGetBlockTxn: func(p *Peer, msg *wire.MsgGetBlockTxn) {
+				ok <- msg
+			},
+			OnBlockTxn: func(p *Peer, msg *wire.MsgBlockTxn) {
+				ok <- msg
+			},
 		},
 		UserAgentName:    "peer",
 		UserAgentVersion: "1.0",
@@ -463,6 +527,10 @@ func TestPeerListeners(t *testing.T) {
 			"OnAddr",
 			wire.NewMsgAddr(),
 		},
+		{
+			"OnAddrV2",
+			wire.NewMsgAddrV2(),
+		},
 		{
 			"OnPing",
 			wire.NewMsgPing(42),
@@ -564,6 +632,38 @@ func TestPeerListeners(t *testing.T) {
 			"OnInitState",
 			wire.NewMsgInitState(),
 		},
+		{
+			"OnSendTxRecon",
+			wire.NewMsgSendTxRecon(1, 0),
+		},
+		{
+			"OnReqRecon",
+			wire.NewMsgReqRecon(0, 0),
+		},
+		{
+			"OnSketch",
+			wire.NewMsgSketch(nil),
+		},
+		{
+			"OnReconcilDiff",
+			wire.NewMsgReconcilDiff(true),
+		},
+		{
+			"OnSendCmpct",
+			wire.NewMsgSendCmpct(true, 1),
+		},
+		{
+			"OnCmpctBlock",
+			wire.NewMsgCmpctBlock(&wire.BlockHeader{}, 0),
+		},
+		{
+			"OnGetBlockTxn",
+			wire.NewMsgGetBlockTxn(&chainhash.Hash{}),
+		},
+		{
+			"OnBlockTxn",
+			wire.NewMsgBlockTxn(&chainhash.Hash{}),
+		},
 	}
 	t.Logf("Running %d tests", len(tests))
 	for _, test := range tests {
@@ -756,6 +856,15 @@ func TestOutboundPeer(t *testing.T) {
 		t.Errorf("PushAddrMsg: unexpected err %v\n", err)
 		return
 	}
+	var addrsV2 []*wire.NetAddressV2
+	for i := 0; i < 5; i++ {
+		na := wire.NetAddressV2{Type: wire.IPv4Address}
+		addrsV2 = append(addrsV2, &na)
+	}
+	if _, err := p2.PushAddrV2Msg(addrsV2); err != nil {
+		t.Errorf("PushAddrV2Msg: unexpected err %v\n", err)
+		return
+	}
 	if err := p2.PushGetBlocksMsg(nil, &chainhash.Hash{}); err != nil {
 		t.Errorf("PushGetBlocksMsg: unexpected err %v\n", err)
 		return
@@ -930,6 +1039,286 @@ func TestUpdateLastBlockHeight(t *testing.T) {
 	}
 }
 
+// TestTrickleInventory ensures inventory queued for trickling is announced to
+// the remote peer in batches at the configured average interval.
+func TestTrickleInventory(t *testing.T) {
+	// Ensure the default trickle interval is used when one is not specified.
+	if p := NewInboundPeer(&Config{}); p.cfg.TrickleInterval != defaultTrickleInterval {
+		t.Fatalf("unexpected default trickle interval - got %v, want %v",
+			p.cfg.TrickleInterval, defaultTrickleInterval)
+	}
+
+	// Create a pair of peers that are connected to each other using a fake
+	// connection where the local peer trickles inventory at a short interval.
+	verack := make(chan struct{})
+	invs := make(chan *wire.MsgInv, 10)
+	peerCfg := Config{
+		Listeners: MessageListeners{
+			OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+		},
+		UserAgentName:    "peer",
+		UserAgentVersion: "1.0",
+		Net:              wire.MainNet,
+		TrickleInterval:  10 * time.Millisecond,
+	}
+	remotePeerCfg := peerCfg
+	remotePeerCfg.Listeners.OnInv = func(p *Peer, msg *wire.MsgInv) {
+		invs <- msg
+	}
+	inConn, outConn := pipe(
+		&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+		&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+	)
+	localPeer, err := NewOutboundPeer(&peerCfg, inConn.laddr)
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err: %v\n", err)
+	}
+	localPeer.AssociateConnection(outConn)
+	inPeer := NewInboundPeer(&remotePeerCfg)
+	inPeer.AssociateConnection(inConn)
+	defer localPeer.Disconnect()
+	defer inPeer.Disconnect()
+
+	// Wait for the veracks from the initial protocol version negotiation.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-verack:
+		case <-time.After(time.Second):
+			t.Fatal("verack timeout")
+		}
+	}
+
+	// Queue several transactions along with a duplicate and ensure each one
+	// is announced exactly once.
+	const numTxns = 5
+	for i := 0; i < numTxns; i++ {
+		txHash := chainhash.Hash{0: byte(i)}
+		localPeer.QueueInventory(wire.NewInvVect(wire.InvTypeTx, &txHash))
+	}
+	localPeer.QueueInventory(wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{}))
+	announced := make(map[chainhash.Hash]int)
+	for len(announced) < numTxns {
+		select {
+		case msg := <-invs:
+			for _, iv := range msg.InvList {
+				announced[iv.Hash]++
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for inventory - got %d, want %d",
+				len(announced), numTxns)
+		}
+	}
+	for hash, count := range announced {
+		if count != 1 {
+			t.Fatalf("inventory %v announced %d times", hash, count)
+		}
+	}
+}
+
+// TestTrickleDelay ensures the random delays between trickled batches of
+// inventory have the configured average interval.
+func TestTrickleDelay(t *testing.T) {
+	const interval = time.Second
+	p := NewInboundPeer(&Config{TrickleInterval: interval})
+
+	const numDelays = 10000
+	var total time.Duration
+	for i := 0; i < numDelays; i++ {
+		delay := p.nextTrickleDelay()
+		if delay < 0 {
+			t.Fatalf("negative trickle delay %v", delay)
+		}
+		total += delay
+	}
+
+	// The standard deviation of the mean of exponentially distributed values
+	// is the mean divided by the square root of the number of samples, or 1%
+	// here, so a 5% tolerance is extremely unlikely to fail spuriously.
+	avg := total / numDelays
+	if avg < interval*95/100 || avg > interval*105/100 {
+		t.Fatalf("unexpected average trickle delay - got %v, want %v", avg,
+			interval)
+	}
+}
+
+// TestKnownInventory ensures inventory is known by value regardless of which
+// instance of the inventory vector is used to add and look it up.
+func TestKnownInventory(t *testing.T) {
+	p := NewInboundPeer(&Config{})
+	hash := chainhash.Hash{0x01}
+	p.AddKnownInventory(wire.NewInvVect(wire.InvTypeBlock, &hash))
+
+	hashCopy := hash
+	if !p.IsKnownInventory(wire.NewInvVect(wire.InvTypeBlock, &hashCopy)) {
+		t.Fatal("inventory added via another instance is not known")
+	}
+	if p.IsKnownInventory(wire.NewInvVect(wire.InvTypeTx, &hashCopy)) {
+		t.Fatal("inventory with a different type is known")
+	}
+}
+
+// TestHandshakeFailure ensures the handshake failure callback is invoked with
+// the expected reason when the initial protocol negotiation fails.
+func TestHandshakeFailure(t *testing.T) {
+	obsoleteVersion := wire.NewMsgVersion(wire.NewNetAddressIPPort(nil, 0, 0),
+		wire.NewNetAddressIPPort(nil, 0, 0), 1, 0)
+	obsoleteVersion.ProtocolVersion = 0
+
+	tests := []struct {
+		name       string
+		msg        wire.Message // message sent first (nil closes conn)
+		wantReason HandshakeFailure
+	}{{
+		name:       "non-version first message",
+		msg:        wire.NewMsgPing(1),
+		wantReason: HandshakeNoVersion,
+	}, {
+		name:       "obsolete protocol version",
+		msg:        obsoleteVersion,
+		wantReason: HandshakeObsoleteVersion,
+	}, {
+		name:       "connection closed",
+		wantReason: HandshakeReadFailed,
+	}}
+
+	for _, test := range tests {
+		failures := make(chan HandshakeFailure, 1)
+		peerCfg := &Config{
+			Net: wire.MainNet,
+			OnHandshakeFailure: func(p *Peer, reason HandshakeFailure, err error) {
+				failures <- reason
+			},
+		}
+		inConn, outConn := pipe(
+			&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+			&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+		)
+		inPeer := NewInboundPeer(peerCfg)
+		go func(msg wire.Message) {
+			if msg == nil {
+				outConn.Writer.(*io.PipeWriter).Close()
+				return
+			}
+			_ = wire.WriteMessage(outConn, msg, wire.ProtocolVersion,
+				wire.MainNet)
+		}(test.msg)
+		inPeer.AssociateConnection(inConn)
+
+		select {
+		case reason := <-failures:
+			if reason != test.wantReason {
+				t.Errorf("%q: unexpected reason -- got %v, want %v",
+					test.name, reason, test.wantReason)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%q: handshake failure callback not invoked", test.name)
+		}
+	}
+}
+
+// TestHandshakeFailureStringer tests the stringized output for the handshake
+// failure reasons.
+func TestHandshakeFailureStringer(t *testing.T) {
+	for reason := HandshakeFailure(0); reason < numHandshakeFailures; reason++ {
+		if _, ok := handshakeFailureStrings[reason]; !ok {
+			t.Errorf("missing string for handshake failure %d", reason)
+		}
+	}
+	want := "Unknown HandshakeFailure (255)"
+	if got := HandshakeFailure(255).String(); got != want {
+		t.Errorf("unexpected string -- got %q, want %q", got, want)
+	}
+}
+
+// TestBlockPayloadRetained ensures the raw bytes provided to the block listener
+// remain intact after the listener returns and further messages are read since
+// they are retained along with the block.
+func TestBlockPayloadRetained(t *testing.T) {
+	const numBlocks = 20
+	verack := make(chan struct{}, 2)
+	payloads := make(chan []byte, numBlocks)
+	txns := make(chan struct{}, numBlocks)
+	peerCfg := &Config{
+		Listeners: MessageListeners{
+			OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+			OnBlock: func(p *Peer, msg *wire.MsgBlock, buf []byte) {
+				payloads <- buf
+			},
+			OnTx: func(p *Peer, msg *wire.MsgTx) {
+				txns <- struct{}{}
+			},
+		},
+		UserAgentName:    "peer",
+		UserAgentVersion: "1.0",
+		Net:              wire.MainNet,
+		Services:         wire.SFNodeNetwork,
+	}
+	inConn, outConn := pipe(
+		&conn{raddr: "10.0.0.1:8333"},
+		&conn{raddr: "10.0.0.2:8333"},
+	)
+	inPeer := NewInboundPeer(peerCfg)
+	inPeer.AssociateConnection(inConn)
+	defer inPeer.Disconnect()
+
+	outPeer, err := NewOutboundPeer(peerCfg, "10.0.0.1:8333")
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err %v", err)
+	}
+	outPeer.AssociateConnection(outConn)
+	defer outPeer.Disconnect()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-verack:
+		case <-time.After(time.Second * 1):
+			t.Fatal("verack timeout")
+		}
+	}
+
+	// Send the blocks one at a time, each followed by a transaction that is
+	// read into a pooled buffer, and retain the raw bytes provided to the
+	// block listener.
+	blocks := make([][]byte, 0, numBlocks)
+	retained := make([][]byte, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		msg := wire.NewMsgBlock(wire.NewBlockHeader(0, &chainhash.Hash{},
+			&chainhash.Hash{}, &chainhash.Hash{}, 1, [6]byte{},
+			1, 1, 1, 1, 1, 1, uint32(i), 1, 1, [32]byte{},
+			binary.LittleEndian.Uint32([]byte{0xb0, 0x1d, 0xfa, 0xce})))
+		blockBytes, err := msg.Bytes()
+		if err != nil {
+			t.Fatalf("unexpected error serializing block %d: %v", i, err)
+		}
+		blocks = append(blocks, blockBytes)
+		outPeer.QueueMessage(msg, nil)
+		select {
+		case payload := <-payloads:
+			retained = append(retained, payload)
+		case <-time.After(time.Second * 1):
+			t.Fatalf("OnBlock timeout for block %d", i)
+		}
+
+		outPeer.QueueMessage(wire.NewMsgTx(), nil)
+		select {
+		case <-txns:
+		case <-time.After(time.Second * 1):
+			t.Fatalf("OnTx timeout after block %d", i)
+		}
+	}
+
+	for i, payload := range retained {
+		if !bytes.Equal(payload, blocks[i]) {
+			t.Fatalf("retained raw bytes of block %d were modified -- got "+
+				"%x, want %x", i, payload, blocks[i])
+		}
+	}
+}
+
 func init() {
 	// Allow self connection when running the tests.
 	allowSelfConns = true
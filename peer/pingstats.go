@@ -0,0 +1,99 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import "time"
+
+// pingBucketBounds are the inclusive upper bounds of the buckets of the ping
+// round-trip time histogram.  Round-trip times that exceed the final bound are
+// counted in an additional unbounded bucket.
+var pingBucketBounds = [...]time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// numPingBuckets is the total number of buckets in the ping round-trip time
+// histogram including the final unbounded bucket.
+const numPingBuckets = len(pingBucketBounds) + 1
+
+// PingBucket is a bucket of the ping round-trip time histogram.
+type PingBucket struct {
+	// UpperBound is the inclusive upper bound of the round-trip times counted
+	// by the bucket.  It is zero for the final bucket which is unbounded.
+	UpperBound time.Duration
+
+	// Count is the number of round-trip times counted by the bucket.
+	Count uint64
+}
+
+// PingStats houses statistics about the round-trip times of the pings sent to
+// a peer over the lifetime of the connection.  Unlike the time of the last
+// ping alone, the statistics and histogram make it possible to distinguish
+// peers that are consistently slow from those with occasional spikes.
+type PingStats struct {
+	// NumPings is the number of pings that have been answered.
+	NumPings uint64
+
+	// Min, Max, and Total are the minimum, maximum, and total round-trip times
+	// of all answered pings, respectively.
+	Min   time.Duration
+	Max   time.Duration
+	Total time.Duration
+
+	// buckets houses the counts of the round-trip times that fall into each
+	// bucket of the histogram.
+	buckets [numPingBuckets]uint64
+}
+
+// add records the provided round-trip time.
+func (s *PingStats) add(rtt time.Duration) {
+	if s.NumPings == 0 || rtt < s.Min {
+		s.Min = rtt
+	}
+	if rtt > s.Max {
+		s.Max = rtt
+	}
+	s.NumPings++
+	s.Total += rtt
+
+	bucket := len(pingBucketBounds)
+	for i, bound := range pingBucketBounds {
+		if rtt <= bound {
+			bucket = i
+			break
+		}
+	}
+	s.buckets[bucket]++
+}
+
+// Avg returns the average round-trip time of all answered pings or zero when
+// no pings have been answered.
+func (s *PingStats) Avg() time.Duration {
+	if s.NumPings == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.NumPings)
+}
+
+// Histogram returns the buckets of the round-trip time histogram ordered by
+// their upper bounds.  The final bucket is unbounded and therefore has an
+// upper bound of zero.
+func (s *PingStats) Histogram() []PingBucket {
+	histogram := make([]PingBucket, numPingBuckets)
+	for i := range histogram {
+		if i < len(pingBucketBounds) {
+			histogram[i].UpperBound = pingBucketBounds[i]
+		}
+		histogram[i].Count = s.buckets[i]
+	}
+	return histogram
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPingStats ensures the ping statistics track the minimum, maximum, and
+// average round-trip times and count them in the expected histogram buckets.
+func TestPingStats(t *testing.T) {
+	var stats PingStats
+	if avg := stats.Avg(); avg != 0 {
+		t.Fatalf("unexpected average without pings - got %v, want 0", avg)
+	}
+
+	rtts := []time.Duration{
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		80 * time.Millisecond,
+		90 * time.Millisecond,
+		time.Second,
+		7 * time.Second,
+	}
+	for _, rtt := range rtts {
+		stats.add(rtt)
+	}
+	if stats.NumPings != uint64(len(rtts)) {
+		t.Fatalf("unexpected number of pings - got %d, want %d",
+			stats.NumPings, len(rtts))
+	}
+	if stats.Min != 5*time.Millisecond {
+		t.Fatalf("unexpected min - got %v, want %v", stats.Min,
+			5*time.Millisecond)
+	}
+	if stats.Max != 7*time.Second {
+		t.Fatalf("unexpected max - got %v, want %v", stats.Max, 7*time.Second)
+	}
+	if avg := stats.Avg(); avg != 1364166666*time.Nanosecond {
+		t.Fatalf("unexpected average - got %v, want %v", avg,
+			1364166666*time.Nanosecond)
+	}
+
+	// Ensure round-trip times are counted in the bucket with the smallest
+	// upper bound that is not less than them and that those that exceed all
+	// bounds are counted in the final unbounded bucket.
+	wantCounts := map[time.Duration]uint64{
+		10 * time.Millisecond:  2,
+		100 * time.Millisecond: 2,
+		time.Second:            1,
+		0:                      1,
+	}
+	histogram := stats.Histogram()
+	if len(histogram) != len(pingBucketBounds)+1 {
+		t.Fatalf("unexpected number of buckets - got %d, want %d",
+			len(histogram), len(pingBucketBounds)+1)
+	}
+	if bound := histogram[len(histogram)-1].UpperBound; bound != 0 {
+		t.Fatalf("unexpected final bucket upper bound - got %v, want 0", bound)
+	}
+	for i, bucket := range histogram {
+		if i > 0 && i < len(histogram)-1 &&
+			bucket.UpperBound <= histogram[i-1].UpperBound {
+
+			t.Fatalf("bucket %d upper bound %v is not greater than previous "+
+				"bound %v", i, bucket.UpperBound, histogram[i-1].UpperBound)
+		}
+		if bucket.Count != wantCounts[bucket.UpperBound] {
+			t.Fatalf("unexpected count for bucket %v - got %d, want %d",
+				bucket.UpperBound, bucket.Count,
+				wantCounts[bucket.UpperBound])
+		}
+	}
+}
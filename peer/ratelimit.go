@@ -0,0 +1,72 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate at which bytes are transferred by means of a
+// token bucket that is refilled at a fixed rate up to a maximum burst size.
+//
+// Transfers are never rejected.  Instead, the bytes of each transfer are
+// deducted from the bucket, which may go into debt, and the caller waits until
+// the debt has been repaid.  This allows messages larger than the burst size to
+// be transferred while still limiting the long-term rate.
+//
+// A single limiter may be shared by multiple peers in order to impose an
+// aggregate limit on all of them.  It is safe for concurrent access.
+type RateLimiter struct {
+	bytesPerSec float64
+	burst       float64
+
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a rate limiter that permits transferring the given
+// number of bytes per second on average and up to burst bytes at once after
+// being idle.  A burst of zero is treated as one second worth of bytes.
+func NewRateLimiter(bytesPerSec, burst uint64) *RateLimiter {
+	if burst == 0 {
+		burst = bytesPerSec
+	}
+	return &RateLimiter{
+		bytesPerSec: float64(bytesPerSec),
+		burst:       float64(burst),
+		tokens:      float64(burst),
+	}
+}
+
+// BytesPerSec returns the average number of bytes per second permitted by the
+// limiter.
+func (l *RateLimiter) BytesPerSec() uint64 {
+	return uint64(l.bytesPerSec)
+}
+
+// reserve deducts the provided number of bytes from the bucket as of the
+// provided time and returns how long the caller must wait before the bucket is
+// no longer in debt.
+func (l *RateLimiter) reserve(n int, now time.Time) time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.bytesPerSec
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+	l.tokens -= float64(n)
+	if l.tokens >= 0 || l.bytesPerSec <= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.bytesPerSec * float64(time.Second))
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRateLimiter ensures the rate limiter deducts transferred bytes from its
+// bucket, refills it at the configured rate up to the burst size, and reports
+// the expected wait times.
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(1000, 2000)
+	if got := limiter.BytesPerSec(); got != 1000 {
+		t.Fatalf("unexpected rate - got %d, want 1000", got)
+	}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		n       int
+		want    time.Duration
+	}{
+		{"within initial burst", 0, 1500, 0},
+		{"exhausts burst", 0, 500, 0},
+		{"into debt", 0, 500, 500 * time.Millisecond},
+		{"partially repaid debt", 250 * time.Millisecond, 0, 250 * time.Millisecond},
+		{"repaid debt", 250 * time.Millisecond, 0, 0},
+		{"refill capped at burst", time.Hour, 2500, 500 * time.Millisecond},
+		{"message larger than burst", 10 * time.Second, 5000, 3 * time.Second},
+	}
+	for _, test := range tests {
+		now = now.Add(test.advance)
+		got := limiter.reserve(test.n, now)
+		if got != test.want {
+			t.Fatalf("%q: unexpected wait - got %v, want %v", test.name, got,
+				test.want)
+		}
+	}
+
+	// Ensure a burst of zero defaults to one second worth of bytes.
+	limiter = NewRateLimiter(1000, 0)
+	if got := limiter.reserve(1000, now); got != 0 {
+		t.Fatalf("unexpected wait with default burst - got %v, want 0", got)
+	}
+	if got := limiter.reserve(1, now); got != time.Millisecond {
+		t.Fatalf("unexpected wait after default burst - got %v, want 1ms", got)
+	}
+}
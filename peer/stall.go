@@ -0,0 +1,123 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// maxStallResponseTimeout is the maximum amount of time messages that
+	// expect a response will wait before disconnecting the peer for stalling
+	// regardless of how large the response is expected to be and how slow the
+	// peer is observed to be.  This bounds the amount of time a peer that
+	// purposely sends data very slowly is able to tie up resources.
+	maxStallResponseTimeout = 3 * time.Minute
+
+	// defaultStallThroughput is the throughput, in bytes per second, assumed
+	// for the remote peer before any has been observed.  It is intentionally
+	// conservative so large responses from peers on slow links, such as those
+	// connected via Tor, are not mistakenly considered stalled.
+	defaultStallThroughput = 32 * 1024
+
+	// minStallThroughput is the minimum throughput, in bytes per second, that
+	// is assumed for the remote peer when calculating deadlines regardless of
+	// the observed throughput.
+	minStallThroughput = 8 * 1024
+
+	// minThroughputSampleSize is the minimum size of a response, in bytes, for
+	// it to be used to estimate the throughput of the remote peer.  The time
+	// it takes to receive smaller responses is dominated by latency rather
+	// than throughput.
+	minThroughputSampleSize = 16 * 1024
+
+	// throughputSampleWeight is the weight given to each new throughput
+	// sample in the exponential moving average of the observed throughput.
+	throughputSampleWeight = 0.25
+
+	// maxStandardTxSize is the maximum size of a standard transaction which
+	// is the largest transaction that is expected in response to a request.
+	maxStandardTxSize = 100000
+
+	// maxInvMsgSize is the maximum size of an inv message.
+	maxInvMsgSize = wire.MaxVarIntPayload + wire.MaxInvPerMsg*(4+chainhash.HashSize)
+)
+
+// throughputEstimator estimates the rate at which the remote peer delivers the
+// responses to requests from the time it takes to receive large responses.
+type throughputEstimator struct {
+	// bytesPerSec is the exponential moving average of the observed
+	// throughput.  It is zero when no throughput has been observed yet.
+	bytesPerSec float64
+}
+
+// addSample updates the throughput estimate with a response of the provided
+// size that took the provided amount of time to receive after it was
+// requested.  Responses that are too small to meaningfully measure throughput
+// are ignored.
+func (e *throughputEstimator) addSample(size int, elapsed time.Duration) {
+	if size < minThroughputSampleSize || elapsed <= 0 {
+		return
+	}
+
+	sample := float64(size) / elapsed.Seconds()
+	if e.bytesPerSec == 0 {
+		e.bytesPerSec = sample
+		return
+	}
+	e.bytesPerSec += throughputSampleWeight * (sample - e.bytesPerSec)
+}
+
+// estimate returns the estimated throughput of the remote peer in bytes per
+// second.  The default throughput is returned when none has been observed and
+// the result is never less than the minimum throughput.
+func (e *throughputEstimator) estimate() float64 {
+	bytesPerSec := e.bytesPerSec
+	if bytesPerSec == 0 {
+		bytesPerSec = defaultStallThroughput
+	}
+	if bytesPerSec < minStallThroughput {
+		bytesPerSec = minStallThroughput
+	}
+	return bytesPerSec
+}
+
+// responseTimeout returns the amount of time to wait for a response of the
+// provided maximum expected size given the estimated throughput of the remote
+// peer.  It is the base response timeout extended by the time it would take to
+// transfer the response at the estimated throughput and is limited to the
+// maximum timeout.
+func (e *throughputEstimator) responseTimeout(size int) time.Duration {
+	transferSecs := float64(size) / e.estimate()
+	timeout := stallResponseTimeout + time.Duration(transferSecs*float64(time.Second))
+	if timeout > maxStallResponseTimeout {
+		timeout = maxStallResponseTimeout
+	}
+	return timeout
+}
+
+// expectedResponseSize returns the maximum size of the response that is
+// expected for the passed message.  It is zero for messages that expect small
+// responses or no response at all.
+func expectedResponseSize(msg wire.Message) int {
+	switch msg := msg.(type) {
+	case *wire.MsgGetData:
+		// The first response is a block when any are requested or a
+		// transaction otherwise.
+		for _, iv := range msg.InvList {
+			if iv.Type == wire.InvTypeBlock {
+				return wire.MaxBlockPayload
+			}
+		}
+		return maxStandardTxSize
+
+	case *wire.MsgMemPool, *wire.MsgGetBlocks:
+		return maxInvMsgSize
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestThroughputEstimator ensures the throughput estimator ignores small
+// samples, averages large ones, and produces the expected response timeouts.
+func TestThroughputEstimator(t *testing.T) {
+	var e throughputEstimator
+
+	// Ensure the default throughput is used before any samples and that small
+	// responses are ignored.
+	if got := e.estimate(); got != defaultStallThroughput {
+		t.Fatalf("unexpected initial estimate -- got %v, want %v", got,
+			defaultStallThroughput)
+	}
+	e.addSample(minThroughputSampleSize-1, time.Millisecond)
+	if got := e.estimate(); got != defaultStallThroughput {
+		t.Fatalf("small sample changed estimate -- got %v, want %v", got,
+			defaultStallThroughput)
+	}
+
+	// Ensure the first large sample sets the estimate and later ones are
+	// averaged into it.
+	e.addSample(1000000, time.Second)
+	if got := e.estimate(); got != 1000000 {
+		t.Fatalf("unexpected estimate after first sample -- got %v, want %v",
+			got, 1000000)
+	}
+	e.addSample(200000, time.Second)
+	want := 1000000 + throughputSampleWeight*(200000-1000000)
+	if got := e.estimate(); got != want {
+		t.Fatalf("unexpected estimate after second sample -- got %v, want %v",
+			got, want)
+	}
+
+	// Ensure the response timeout is the base timeout for empty responses
+	// and is extended by the transfer time for large ones.
+	e = throughputEstimator{bytesPerSec: 100000}
+	if got := e.responseTimeout(0); got != stallResponseTimeout {
+		t.Fatalf("unexpected timeout for empty response -- got %v, want %v",
+			got, stallResponseTimeout)
+	}
+	wantTimeout := stallResponseTimeout + 10*time.Second
+	if got := e.responseTimeout(1000000); got != wantTimeout {
+		t.Fatalf("unexpected timeout for large response -- got %v, want %v",
+			got, wantTimeout)
+	}
+
+	// Ensure very slow peers are limited to the min throughput and the max
+	// timeout.
+	e = throughputEstimator{bytesPerSec: 1}
+	if got := e.estimate(); got != minStallThroughput {
+		t.Fatalf("unexpected estimate for slow peer -- got %v, want %v", got,
+			minStallThroughput)
+	}
+	if got := e.responseTimeout(wire.MaxBlockPayload); got != maxStallResponseTimeout {
+		t.Fatalf("unexpected timeout for slow peer -- got %v, want %v", got,
+			maxStallResponseTimeout)
+	}
+}
+
+// TestExpectedResponseSize ensures the expected response sizes for messages
+// that expect responses are as expected.
+func TestExpectedResponseSize(t *testing.T) {
+	getBlock := wire.NewMsgGetData()
+	getBlock.AddInvVect(wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{}))
+	getBlock.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &chainhash.Hash{}))
+	getTx := wire.NewMsgGetData()
+	getTx.AddInvVect(wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{}))
+
+	tests := []struct {
+		name string
+		msg  wire.Message
+		want int
+	}{
+		{"getdata with block", getBlock, wire.MaxBlockPayload},
+		{"getdata with only txns", getTx, maxStandardTxSize},
+		{"mempool", wire.NewMsgMemPool(), maxInvMsgSize},
+		{"getblocks", wire.NewMsgGetBlocks(&chainhash.Hash{}), maxInvMsgSize},
+		{"verack", wire.NewMsgVerAck(), 0},
+		{"getinitstate", wire.NewMsgGetInitState(), 0},
+	}
+	for _, test := range tests {
+		if got := expectedResponseSize(test.msg); got != test.want {
+			t.Errorf("%q: unexpected size -- got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+}
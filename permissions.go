@@ -0,0 +1,153 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// peerPermissions houses the permission flags that may be granted to peers via
+// the whitelist and added peer configuration options.
+type peerPermissions uint8
+
+const (
+	// permNoBan indicates the peer is never banned or disconnected for
+	// misbehavior.
+	permNoBan peerPermissions = 1 << iota
+
+	// permNoLimits indicates the peer is not subject to the bandwidth limits
+	// and, when inbound, is not subject to the max peers and max connections
+	// per IP limits.
+	permNoLimits
+
+	// permForceRelay indicates transactions from the peer are accepted and
+	// relayed even when blocks-only mode is enabled.
+	permForceRelay
+
+	// permMempool indicates the peer may request the contents of the mempool
+	// via mempool messages as often as it likes.
+	permMempool
+
+	// permAddr indicates the peer may request known addresses via getaddr
+	// messages regardless of the direction of the connection and as often as
+	// it likes.
+	permAddr
+
+	// permAll houses all permission flags.
+	permAll = permNoBan | permNoLimits | permForceRelay | permMempool | permAddr
+
+	// defaultWhitelistPermissions are the permissions granted to whitelisted
+	// peers when no permissions are explicitly specified.
+	defaultWhitelistPermissions = permNoBan | permNoLimits
+)
+
+// permissionNames maps the permission flags to the names used to specify them
+// in the configuration options.  The names are ordered by flag.
+var permissionNames = []struct {
+	perm peerPermissions
+	name string
+}{
+	{permNoBan, "noban"},
+	{permNoLimits, "nolimits"},
+	{permForceRelay, "forcerelay"},
+	{permMempool, "mempool"},
+	{permAddr, "addr"},
+}
+
+// has returns whether all of the provided permission flags are set.
+func (p peerPermissions) has(perms peerPermissions) bool {
+	return p&perms == perms
+}
+
+// String returns the permission flags as a comma-separated list of their
+// names.
+func (p peerPermissions) String() string {
+	var names []string
+	for _, perm := range permissionNames {
+		if p.has(perm.perm) {
+			names = append(names, perm.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// parsePermissions parses the permission flags from the provided
+// comma-separated list of permission names.  The special name "all" grants all
+// permissions.
+func parsePermissions(s string) (peerPermissions, error) {
+	var perms peerPermissions
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "all" {
+			perms |= permAll
+			continue
+		}
+
+		var found bool
+		for _, perm := range permissionNames {
+			if perm.name == name {
+				perms |= perm.perm
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown permission '%s'", name)
+		}
+	}
+	return perms, nil
+}
+
+// splitPermissions splits the permission flags from the provided configuration
+// value of the form [permissions@]value and returns them along with the
+// remaining value.  The provided default permissions are returned when the
+// value does not specify any.
+func splitPermissions(s string, defaultPerms peerPermissions) (peerPermissions, string, error) {
+	i := strings.Index(s, "@")
+	if i == -1 {
+		return defaultPerms, s, nil
+	}
+	perms, err := parsePermissions(s[:i])
+	if err != nil {
+		return 0, "", err
+	}
+	return perms, s[i+1:], nil
+}
+
+// normalizePeerAddresses splits the permission flags from the provided peer
+// addresses of the form [permissions@]host[:port] and returns the normalized
+// addresses along with a map of any permissions keyed by normalized address.
+func normalizePeerAddresses(addrs []string, defaultPort string) ([]string, map[string]peerPermissions, error) {
+	var perms map[string]peerPermissions
+	stripped := make([]string, 0, len(addrs))
+	for _, entry := range addrs {
+		entryPerms, addr, err := splitPermissions(entry, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("the peer address '%s' is "+
+				"invalid: %w", entry, err)
+		}
+		norm := normalizeAddresses([]string{addr}, defaultPort,
+			normalizeInterfaceFirstAddr)
+		if entryPerms != 0 {
+			if perms == nil {
+				perms = make(map[string]peerPermissions)
+			}
+			for _, addr := range norm {
+				perms[addr] |= entryPerms
+			}
+		}
+		stripped = append(stripped, norm...)
+	}
+	return removeDuplicateAddresses(stripped), perms, nil
+}
+
+// whitelistEntry houses an IP network that is whitelisted along with the
+// permissions granted to peers within it.
+type whitelistEntry struct {
+	ipnet *net.IPNet
+	perms peerPermissions
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitPermissions ensures permissions are split from configuration values
+// and parsed as expected.
+func TestSplitPermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantPerms peerPermissions
+		wantValue string
+		wantStr   string
+		wantErr   bool
+	}{{
+		name:      "no permissions uses default",
+		value:     "192.168.1.0/24",
+		wantPerms: defaultWhitelistPermissions,
+		wantValue: "192.168.1.0/24",
+		wantStr:   "noban,nolimits",
+	}, {
+		name:      "single permission",
+		value:     "mempool@::1",
+		wantPerms: permMempool,
+		wantValue: "::1",
+		wantStr:   "mempool",
+	}, {
+		name:      "multiple permissions with spaces",
+		value:     "noban, forcerelay,addr@10.0.0.0/8",
+		wantPerms: permNoBan | permForceRelay | permAddr,
+		wantValue: "10.0.0.0/8",
+		wantStr:   "noban,forcerelay,addr",
+	}, {
+		name:      "all permissions",
+		value:     "all@10.0.0.1",
+		wantPerms: permAll,
+		wantValue: "10.0.0.1",
+		wantStr:   "noban,nolimits,forcerelay,mempool,addr",
+	}, {
+		name:    "unknown permission",
+		value:   "noban,bogus@10.0.0.1",
+		wantErr: true,
+	}, {
+		name:    "empty permissions",
+		value:   "@10.0.0.1",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		perms, value, err := splitPermissions(test.value,
+			defaultWhitelistPermissions)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if perms != test.wantPerms {
+			t.Errorf("%q: unexpected permissions -- got %v, want %v",
+				test.name, perms, test.wantPerms)
+		}
+		if value != test.wantValue {
+			t.Errorf("%q: unexpected value -- got %q, want %q", test.name,
+				value, test.wantValue)
+		}
+		if perms.String() != test.wantStr {
+			t.Errorf("%q: unexpected string -- got %q, want %q", test.name,
+				perms.String(), test.wantStr)
+		}
+	}
+}
+
+// TestNormalizePeerAddresses ensures permissions are split from peer addresses
+// and keyed by the normalized addresses.
+func TestNormalizePeerAddresses(t *testing.T) {
+	addrs, perms, err := normalizePeerAddresses([]string{
+		"10.0.0.1",
+		"noban,mempool@10.0.0.2",
+		"forcerelay@10.0.0.2:9108",
+		"[::1]:19108",
+	}, "9108")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantAddrs := []string{"10.0.0.1:9108", "10.0.0.2:9108", "[::1]:19108"}
+	if !reflect.DeepEqual(addrs, wantAddrs) {
+		t.Fatalf("unexpected addresses -- got %v, want %v", addrs, wantAddrs)
+	}
+	wantPerms := map[string]peerPermissions{
+		"10.0.0.2:9108": permNoBan | permMempool | permForceRelay,
+	}
+	if !reflect.DeepEqual(perms, wantPerms) {
+		t.Fatalf("unexpected permissions -- got %v, want %v", perms,
+			wantPerms)
+	}
+
+	if _, _, err := normalizePeerAddresses([]string{"bogus@10.0.0.1"},
+		"9108"); err == nil {
+		t.Fatal("did not receive expected error for unknown permission")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package dcrdrpc provides the protocol buffer definitions and generated Go code
+for the dcrd gRPC API.
+
+The API is defined in api.proto, which clients written in other languages may
+use with their own protocol buffer tooling in order to generate strongly-typed
+bindings.  Go clients may use the generated code in this package directly.
+
+The server implements the following services:
+
+  - VersionService: the version of the API
+  - ChainService: queries against the current best chain
+  - MempoolService: transaction submission and memory pool queries
+  - NotificationService: streams of block and memory pool events
+
+All hashes are encoded as 32 bytes in the internal byte order used by the wire
+protocol, and serialized blocks, headers, and transactions use the wire
+protocol encoding.
+
+# Authentication
+
+The gRPC server uses the same TLS certificate and credentials as the JSON-RPC
+server.  When basic authentication is in use, clients must provide the RPC
+username and password as HTTP basic authorization in the "authorization"
+metadata of every call.
+
+# Regenerating
+
+The generated code is committed to the repository.  Run regen.sh after
+modifying api.proto in order to regenerate it.
+*/
+package dcrdrpc
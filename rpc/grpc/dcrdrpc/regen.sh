@@ -0,0 +1,15 @@
+#!/bin/sh
+
+# Regenerates the Go code for the gRPC API from api.proto.  Requires protoc
+# along with the protoc-gen-go and protoc-gen-go-grpc plugins to be installed
+# and available on the PATH.
+#
+#   go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.31.0
+#   go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.3.0
+
+set -e
+
+cd "$(dirname "$0")"
+protoc --go_out=. --go_opt=paths=source_relative \
+	--go-grpc_out=. --go-grpc_opt=paths=source_relative \
+	api.proto
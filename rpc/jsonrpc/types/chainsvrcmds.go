@@ -45,6 +45,19 @@ const (
 	NDisconnect NodeSubCmd = "disconnect"
 )
 
+// SetBanSubCmd defines the type used in the setban JSON-RPC command for the
+// sub command field.
+type SetBanSubCmd string
+
+const (
+	// SBAdd indicates the specified address or subnet should be banned.
+	SBAdd SetBanSubCmd = "add"
+
+	// SBRemove indicates the ban for the specified address or subnet should
+	// be removed.
+	SBRemove SetBanSubCmd = "remove"
+)
+
 // AddNodeCmd defines the addnode JSON-RPC command.
 type AddNodeCmd struct {
 	Addr   string
@@ -60,6 +73,28 @@ func NewAddNodeCmd(addr string, subCmd AddNodeSubCmd) *AddNodeCmd {
 	}
 }
 
+// BackupChainStateCmd defines the backupchainstate JSON-RPC command.
+type BackupChainStateCmd struct {
+	Dir string
+}
+
+// NewBackupChainStateCmd returns a new instance which can be used to issue a
+// backupchainstate JSON-RPC command.
+func NewBackupChainStateCmd(dir string) *BackupChainStateCmd {
+	return &BackupChainStateCmd{
+		Dir: dir,
+	}
+}
+
+// ClearBannedCmd defines the clearbanned JSON-RPC command.
+type ClearBannedCmd struct{}
+
+// NewClearBannedCmd returns a new instance which can be used to issue a
+// clearbanned JSON-RPC command.
+func NewClearBannedCmd() *ClearBannedCmd {
+	return &ClearBannedCmd{}
+}
+
 // SStxInput represents the inputs to an SStx transaction. Specifically a
 // transactionsha and output number pair, along with the output amounts.
 type SStxInput struct {
@@ -173,8 +208,9 @@ func NewDecodeRawTransactionCmd(hexTx string) *DecodeRawTransactionCmd {
 
 // DecodeScriptCmd defines the decodescript JSON-RPC command.
 type DecodeScriptCmd struct {
-	HexScript string
-	Version   *uint16
+	HexScript    string
+	Version      *uint16
+	RedeemScript *string
 }
 
 // NewDecodeScriptCmd returns a new instance which can be used to issue a
@@ -185,6 +221,19 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DumpTxOutSetCmd defines the dumptxoutset JSON-RPC command.
+type DumpTxOutSetCmd struct {
+	Path string
+}
+
+// NewDumpTxOutSetCmd returns a new instance which can be used to issue a
+// dumptxoutset JSON-RPC command.
+func NewDumpTxOutSetCmd(path string) *DumpTxOutSetCmd {
+	return &DumpTxOutSetCmd{
+		Path: path,
+	}
+}
+
 // EstimateFeeCmd defines the estimatefee JSON-RPC command.
 type EstimateFeeCmd struct {
 	NumBlocks int64
@@ -355,9 +404,10 @@ func NewGetBestBlockHashCmd() *GetBestBlockHashCmd {
 
 // GetBlockCmd defines the getblock JSON-RPC command.
 type GetBlockCmd struct {
-	Hash      string
-	Verbose   *bool `jsonrpcdefault:"true"`
-	VerboseTx *bool `jsonrpcdefault:"false"`
+	Hash           string
+	Verbose        *bool `jsonrpcdefault:"true"`
+	VerboseTx      *bool `jsonrpcdefault:"false"`
+	VerbosePrevOut *bool `jsonrpcdefault:"false"`
 }
 
 // NewGetBlockCmd returns a new instance which can be used to issue a getblock
@@ -365,11 +415,25 @@ type GetBlockCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewGetBlockCmd(hash string, verbose, verboseTx *bool) *GetBlockCmd {
+func NewGetBlockCmd(hash string, verbose, verboseTx, verbosePrevOut *bool) *GetBlockCmd {
 	return &GetBlockCmd{
-		Hash:      hash,
-		Verbose:   verbose,
-		VerboseTx: verboseTx,
+		Hash:           hash,
+		Verbose:        verbose,
+		VerboseTx:      verboseTx,
+		VerbosePrevOut: verbosePrevOut,
+	}
+}
+
+// GetBlockByTimeCmd defines the getblockbytime JSON-RPC command.
+type GetBlockByTimeCmd struct {
+	Timestamp int64
+}
+
+// NewGetBlockByTimeCmd returns a new instance which can be used to issue a
+// getblockbytime JSON-RPC command.
+func NewGetBlockByTimeCmd(timestamp int64) *GetBlockByTimeCmd {
+	return &GetBlockByTimeCmd{
+		Timestamp: timestamp,
 	}
 }
 
@@ -419,6 +483,26 @@ func NewGetBlockHeaderCmd(hash string, verbose *bool) *GetBlockHeaderCmd {
 	}
 }
 
+// GetBlocksByTimeCmd defines the getblocksbytime JSON-RPC command.
+type GetBlocksByTimeCmd struct {
+	StartTime int64
+	EndTime   int64
+	Count     *int `jsonrpcdefault:"100"`
+}
+
+// NewGetBlocksByTimeCmd returns a new instance which can be used to issue a
+// getblocksbytime JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlocksByTimeCmd(startTime, endTime int64, count *int) *GetBlocksByTimeCmd {
+	return &GetBlocksByTimeCmd{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Count:     count,
+	}
+}
+
 // GetBlockSubsidyCmd defines the getblocksubsidy JSON-RPC command.
 type GetBlockSubsidyCmd struct {
 	Height int64
@@ -434,6 +518,26 @@ func NewGetBlockSubsidyCmd(height int64, voters uint16) *GetBlockSubsidyCmd {
 	}
 }
 
+// GetCFiltersV2Cmd defines the getcfiltersv2 JSON-RPC command.
+type GetCFiltersV2Cmd struct {
+	StartHeight int64
+	Count       *int  `jsonrpcdefault:"100"`
+	IncludeData *bool `jsonrpcdefault:"true"`
+}
+
+// NewGetCFiltersV2Cmd returns a new instance which can be used to issue a
+// getcfiltersv2 JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetCFiltersV2Cmd(startHeight int64, count *int, includeData *bool) *GetCFiltersV2Cmd {
+	return &GetCFiltersV2Cmd{
+		StartHeight: startHeight,
+		Count:       count,
+		IncludeData: includeData,
+	}
+}
+
 // GetCFilterV2Cmd defines the getcfilterv2 JSON-RPC command.
 type GetCFilterV2Cmd struct {
 	BlockHash string
@@ -483,6 +587,16 @@ func NewGetCurrentNetCmd() *GetCurrentNetCmd {
 	return &GetCurrentNetCmd{}
 }
 
+// GetDebugLevelsCmd defines the getdebuglevels JSON-RPC command.  This command
+// is not a standard Bitcoin command.  It is an extension for dcrd.
+type GetDebugLevelsCmd struct{}
+
+// NewGetDebugLevelsCmd returns a new instance which can be used to issue a
+// getdebuglevels JSON-RPC command.
+func NewGetDebugLevelsCmd() *GetDebugLevelsCmd {
+	return &GetDebugLevelsCmd{}
+}
+
 // GetDifficultyCmd defines the getdifficulty JSON-RPC command.
 type GetDifficultyCmd struct{}
 
@@ -534,6 +648,55 @@ func NewGetHeadersCmd(blockLocators []string, hashStop string) *GetHeadersCmd {
 	}
 }
 
+// GetMempoolAncestorsCmd defines the getmempoolancestors JSON-RPC command.
+type GetMempoolAncestorsCmd struct {
+	TxHash  string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolAncestorsCmd returns a new instance which can be used to issue
+// a getmempoolancestors JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolAncestorsCmd(txHash string, verbose *bool) *GetMempoolAncestorsCmd {
+	return &GetMempoolAncestorsCmd{
+		TxHash:  txHash,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolDescendantsCmd defines the getmempooldescendants JSON-RPC command.
+type GetMempoolDescendantsCmd struct {
+	TxHash  string
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetMempoolDescendantsCmd returns a new instance which can be used to
+// issue a getmempooldescendants JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetMempoolDescendantsCmd(txHash string, verbose *bool) *GetMempoolDescendantsCmd {
+	return &GetMempoolDescendantsCmd{
+		TxHash:  txHash,
+		Verbose: verbose,
+	}
+}
+
+// GetMempoolEntryCmd defines the getmempoolentry JSON-RPC command.
+type GetMempoolEntryCmd struct {
+	TxHash string
+}
+
+// NewGetMempoolEntryCmd returns a new instance which can be used to issue a
+// getmempoolentry JSON-RPC command.
+func NewGetMempoolEntryCmd(txHash string) *GetMempoolEntryCmd {
+	return &GetMempoolEntryCmd{
+		TxHash: txHash,
+	}
+}
+
 // GetMempoolInfoCmd defines the getmempoolinfo JSON-RPC command.
 type GetMempoolInfoCmd struct{}
 
@@ -588,6 +751,15 @@ func NewGetNetworkHashPSCmd(numBlocks, height *int) *GetNetworkHashPSCmd {
 	}
 }
 
+// GetNodeStatusCmd defines the getnodestatus JSON-RPC command.
+type GetNodeStatusCmd struct{}
+
+// NewGetNodeStatusCmd returns a new instance which can be used to issue a
+// getnodestatus JSON-RPC command.
+func NewGetNodeStatusCmd() *GetNodeStatusCmd {
+	return &GetNodeStatusCmd{}
+}
+
 // GetPeerInfoCmd defines the getpeerinfo JSON-RPC command.
 type GetPeerInfoCmd struct{}
 
@@ -625,9 +797,14 @@ const (
 )
 
 // GetRawMempoolCmd defines the getmempool JSON-RPC command.
+//
+// When the count is set, at most that many transactions are returned per call
+// along with a cursor that may be provided to retrieve the next page.
 type GetRawMempoolCmd struct {
 	Verbose *bool `jsonrpcdefault:"false"`
 	TxType  *string
+	Count   *int
+	Cursor  *string
 }
 
 // NewGetRawMempoolCmd returns a new instance which can be used to issue a
@@ -644,8 +821,10 @@ func NewGetRawMempoolCmd(verbose *bool, txType *string) *GetRawMempoolCmd {
 
 // GetRawTransactionCmd defines the getrawtransaction JSON-RPC command.
 //
-// NOTE: This field is an int versus a bool to remain compatible with Bitcoin
-// Core even though it really should be a bool.
+// The verbose field is an int in order to remain compatible with Bitcoin Core.
+// A value of 0 requests the hex-encoded transaction, 1 requests a JSON object
+// that describes the transaction, and 2 additionally includes the previous
+// output spent by each input.
 type GetRawTransactionCmd struct {
 	Txid    string
 	Verbose *int `jsonrpcdefault:"0"`
@@ -663,6 +842,44 @@ func NewGetRawTransactionCmd(txHash string, verbose *int) *GetRawTransactionCmd
 	}
 }
 
+// GetRejectedTransactionCmd defines the getrejectedtransaction JSON-RPC
+// command.
+type GetRejectedTransactionCmd struct {
+	Txid string
+}
+
+// NewGetRejectedTransactionCmd returns a new instance which can be used to
+// issue a getrejectedtransaction JSON-RPC command.
+func NewGetRejectedTransactionCmd(txHash string) *GetRejectedTransactionCmd {
+	return &GetRejectedTransactionCmd{
+		Txid: txHash,
+	}
+}
+
+// GetRPCLimitInfoCmd defines the getrpclimitinfo JSON-RPC command.
+type GetRPCLimitInfoCmd struct{}
+
+// NewGetRPCLimitInfoCmd returns a new instance which can be used to issue a
+// getrpclimitinfo JSON-RPC command.
+func NewGetRPCLimitInfoCmd() *GetRPCLimitInfoCmd {
+	return &GetRPCLimitInfoCmd{}
+}
+
+// GetSpentInfoCmd defines the getspentinfo JSON-RPC command.
+type GetSpentInfoCmd struct {
+	Txid string
+	Vout uint32
+}
+
+// NewGetSpentInfoCmd returns a new instance which can be used to issue a
+// getspentinfo JSON-RPC command.
+func NewGetSpentInfoCmd(txHash string, index uint32) *GetSpentInfoCmd {
+	return &GetSpentInfoCmd{
+		Txid: txHash,
+		Vout: index,
+	}
+}
+
 // GetStakeDifficultyCmd is a type handling custom marshaling and
 // unmarshaling of getstakedifficulty JSON RPC commands.
 type GetStakeDifficultyCmd struct{}
@@ -703,6 +920,28 @@ func NewGetStakeVersionsCmd(hash string, count int32) *GetStakeVersionsCmd {
 	}
 }
 
+// GetSubmitBlockStatusCmd defines the getsubmitblockstatus JSON-RPC command.
+type GetSubmitBlockStatusCmd struct {
+	ID string
+}
+
+// NewGetSubmitBlockStatusCmd returns a new instance which can be used to issue
+// a getsubmitblockstatus JSON-RPC command.
+func NewGetSubmitBlockStatusCmd(id string) *GetSubmitBlockStatusCmd {
+	return &GetSubmitBlockStatusCmd{
+		ID: id,
+	}
+}
+
+// GetSyncStatusCmd defines the getsyncstatus JSON-RPC command.
+type GetSyncStatusCmd struct{}
+
+// NewGetSyncStatusCmd returns a new instance which can be used to issue a
+// getsyncstatus JSON-RPC command.
+func NewGetSyncStatusCmd() *GetSyncStatusCmd {
+	return &GetSyncStatusCmd{}
+}
+
 // GetTicketPoolValueCmd defines the getticketpoolvalue JSON-RPC command.
 type GetTicketPoolValueCmd struct{}
 
@@ -794,7 +1033,8 @@ func NewGetTreasurySpendVotesCmd(block *string, tspends *[]string) *GetTreasuryS
 
 // GetWorkCmd defines the getwork JSON-RPC command.
 type GetWorkCmd struct {
-	Data *string
+	Data       *string
+	LongPollID *string
 }
 
 // NewGetWorkCmd returns a new instance which can be used to issue a getwork
@@ -802,9 +1042,10 @@ type GetWorkCmd struct {
 //
 // The parameters which are pointers indicate they are optional.  Passing nil
 // for optional parameters will use the default value.
-func NewGetWorkCmd(data *string) *GetWorkCmd {
+func NewGetWorkCmd(data, longPollID *string) *GetWorkCmd {
 	return &GetWorkCmd{
-		Data: data,
+		Data:       data,
+		LongPollID: longPollID,
 	}
 }
 
@@ -817,6 +1058,15 @@ func NewRegenTemplateCmd() *RegenTemplateCmd {
 	return &RegenTemplateCmd{}
 }
 
+// RPCDiscoverCmd defines the rpc.discover JSON-RPC command.
+type RPCDiscoverCmd struct{}
+
+// NewRPCDiscoverCmd returns a new instance which can be used to issue an
+// rpc.discover JSON-RPC command.
+func NewRPCDiscoverCmd() *RPCDiscoverCmd {
+	return &RPCDiscoverCmd{}
+}
+
 // HelpCmd defines the help JSON-RPC command.
 type HelpCmd struct {
 	Command *string
@@ -846,9 +1096,24 @@ func NewInvalidateBlockCmd(hash string) *InvalidateBlockCmd {
 	}
 }
 
+// ListBannedCmd defines the listbanned JSON-RPC command.
+type ListBannedCmd struct{}
+
+// NewListBannedCmd returns a new instance which can be used to issue a
+// listbanned JSON-RPC command.
+func NewListBannedCmd() *ListBannedCmd {
+	return &ListBannedCmd{}
+}
+
 // LiveTicketsCmd is a type handling custom marshaling and
 // unmarshaling of livetickets JSON RPC commands.
-type LiveTicketsCmd struct{}
+//
+// When the count is set, at most that many tickets are returned per call along
+// with a cursor that may be provided to retrieve the next page.
+type LiveTicketsCmd struct {
+	Count  *int
+	Cursor *string
+}
 
 // NewLiveTicketsCmd returns a new instance which can be used to issue a JSON-RPC
 // livetickets command.
@@ -885,6 +1150,21 @@ func NewPingCmd() *PingCmd {
 	return &PingCmd{}
 }
 
+// PrioritiseTransactionCmd defines the prioritisetransaction JSON-RPC command.
+type PrioritiseTransactionCmd struct {
+	Txid     string
+	FeeDelta int64
+}
+
+// NewPrioritiseTransactionCmd returns a new instance which can be used to
+// issue a prioritisetransaction JSON-RPC command.
+func NewPrioritiseTransactionCmd(txHash string, feeDelta int64) *PrioritiseTransactionCmd {
+	return &PrioritiseTransactionCmd{
+		Txid:     txHash,
+		FeeDelta: feeDelta,
+	}
+}
+
 // ReconsiderBlockCmd defines the reconsiderblock JSON-RPC command.
 type ReconsiderBlockCmd struct {
 	BlockHash string
@@ -898,6 +1178,52 @@ func NewReconsiderBlockCmd(hash string) *ReconsiderBlockCmd {
 	}
 }
 
+// ScanTxOutSetCmd defines the scantxoutset JSON-RPC command.
+type ScanTxOutSetCmd struct {
+	Action      string
+	ScanObjects *[]string
+}
+
+// NewScanTxOutSetCmd returns a new instance which can be used to issue a
+// scantxoutset JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewScanTxOutSetCmd(action string, scanObjects *[]string) *ScanTxOutSetCmd {
+	return &ScanTxOutSetCmd{
+		Action:      action,
+		ScanObjects: scanObjects,
+	}
+}
+
+// SearchRawTransactionsCmd defines the searchrawtransactions JSON-RPC command.
+//
+// A verbose value of 0 requests the hex-encoded transactions and 1 requests
+// JSON objects that describe the transactions along with how the address is
+// involved in each of them.
+type SearchRawTransactionsCmd struct {
+	Address string
+	Verbose *int  `jsonrpcdefault:"1"`
+	Skip    *int  `jsonrpcdefault:"0"`
+	Count   *int  `jsonrpcdefault:"100"`
+	Reverse *bool `jsonrpcdefault:"false"`
+}
+
+// NewSearchRawTransactionsCmd returns a new instance which can be used to
+// issue a searchrawtransactions JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSearchRawTransactionsCmd(address string, verbose, skip, count *int, reverse *bool) *SearchRawTransactionsCmd {
+	return &SearchRawTransactionsCmd{
+		Address: address,
+		Verbose: verbose,
+		Skip:    skip,
+		Count:   count,
+		Reverse: reverse,
+	}
+}
+
 // SendRawTransactionCmd defines the sendrawtransaction JSON-RPC command.
 type SendRawTransactionCmd struct {
 	HexTx         string
@@ -916,6 +1242,32 @@ func NewSendRawTransactionCmd(hexTx string, allowHighFees *bool) *SendRawTransac
 	}
 }
 
+// SetBanCmd defines the setban JSON-RPC command.
+//
+// The ban time is the number of seconds the ban lasts, or the unix time it
+// expires at when absolute is set.  A ban time of zero uses the default ban
+// duration of the server.  Both are ignored when removing a ban.
+type SetBanCmd struct {
+	Subnet   string
+	SubCmd   SetBanSubCmd `jsonrpcusage:"\"add|remove\""`
+	BanTime  *int64       `jsonrpcdefault:"0"`
+	Absolute *bool        `jsonrpcdefault:"false"`
+}
+
+// NewSetBanCmd returns a new instance which can be used to issue a setban
+// JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSetBanCmd(subnet string, subCmd SetBanSubCmd, banTime *int64, absolute *bool) *SetBanCmd {
+	return &SetBanCmd{
+		Subnet:   subnet,
+		SubCmd:   subCmd,
+		BanTime:  banTime,
+		Absolute: absolute,
+	}
+}
+
 // SetGenerateCmd defines the setgenerate JSON-RPC command.
 type SetGenerateCmd struct {
 	Generate     bool
@@ -968,6 +1320,24 @@ func NewSubmitBlockCmd(hexBlock string, options *SubmitBlockOptions) *SubmitBloc
 	}
 }
 
+// SubmitBlockNoWaitCmd defines the submitblocknowait JSON-RPC command.
+type SubmitBlockNoWaitCmd struct {
+	HexBlock string
+	Options  *SubmitBlockOptions
+}
+
+// NewSubmitBlockNoWaitCmd returns a new instance which can be used to issue a
+// submitblocknowait JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewSubmitBlockNoWaitCmd(hexBlock string, options *SubmitBlockOptions) *SubmitBlockNoWaitCmd {
+	return &SubmitBlockNoWaitCmd{
+		HexBlock: hexBlock,
+		Options:  options,
+	}
+}
+
 // TicketFeeInfoCmd defines the ticketfeeinfo JSON-RPC command.
 type TicketFeeInfoCmd struct {
 	Blocks  *uint32
@@ -1074,6 +1444,23 @@ func NewVerifyMessageCmd(address, signature, message string) *VerifyMessageCmd {
 	}
 }
 
+// VerifyOwnershipProofCmd defines the verifyownershipproof JSON-RPC command.
+type VerifyOwnershipProofCmd struct {
+	Address string
+	Message string
+	Proof   string
+}
+
+// NewVerifyOwnershipProofCmd returns a new instance which can be used to issue
+// a verifyownershipproof JSON-RPC command.
+func NewVerifyOwnershipProofCmd(address, message, proof string) *VerifyOwnershipProofCmd {
+	return &VerifyOwnershipProofCmd{
+		Address: address,
+		Message: message,
+		Proof:   proof,
+	}
+}
+
 // VersionCmd defines the version JSON-RPC command.
 type VersionCmd struct{}
 
@@ -1086,12 +1473,15 @@ func init() {
 	flags := dcrjson.UsageFlag(0)
 
 	dcrjson.MustRegister(Method("addnode"), (*AddNodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("backupchainstate"), (*BackupChainStateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("clearbanned"), (*ClearBannedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawssrtx"), (*CreateRawSSRtxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawsstx"), (*CreateRawSStxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawtransaction"), (*CreateRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("debuglevel"), (*DebugLevelCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decoderawtransaction"), (*DecodeRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decodescript"), (*DecodeScriptCmd)(nil), flags)
+	dcrjson.MustRegister(Method("dumptxoutset"), (*DumpTxOutSetCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatefee"), (*EstimateFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatesmartfee"), (*EstimateSmartFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatestakediff"), (*EstimateStakeDiffCmd)(nil), flags)
@@ -1105,32 +1495,45 @@ func init() {
 	dcrjson.MustRegister(Method("getbestblock"), (*GetBestBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblockhash"), (*GetBestBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblock"), (*GetBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblockbytime"), (*GetBlockByTimeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockchaininfo"), (*GetBlockChainInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockcount"), (*GetBlockCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockhash"), (*GetBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockheader"), (*GetBlockHeaderCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblocksbytime"), (*GetBlocksByTimeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblocksubsidy"), (*GetBlockSubsidyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getcfiltersv2"), (*GetCFiltersV2Cmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterv2"), (*GetCFilterV2Cmd)(nil), flags)
 	dcrjson.MustRegister(Method("getchaintips"), (*GetChainTipsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcoinsupply"), (*GetCoinSupplyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getconnectioncount"), (*GetConnectionCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcurrentnet"), (*GetCurrentNetCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getdebuglevels"), (*GetDebugLevelsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getdifficulty"), (*GetDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getgenerate"), (*GetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gethashespersec"), (*GetHashesPerSecCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getheaders"), (*GetHeadersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getinfo"), (*GetInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolancestors"), (*GetMempoolAncestorsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempooldescendants"), (*GetMempoolDescendantsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getmempoolentry"), (*GetMempoolEntryCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmempoolinfo"), (*GetMempoolInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmininginfo"), (*GetMiningInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkinfo"), (*GetNetworkInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnettotals"), (*GetNetTotalsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getnetworkhashps"), (*GetNetworkHashPSCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getnodestatus"), (*GetNodeStatusCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getpeerinfo"), (*GetPeerInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawmempool"), (*GetRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawtransaction"), (*GetRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getrejectedtransaction"), (*GetRejectedTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getrpclimitinfo"), (*GetRPCLimitInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getspentinfo"), (*GetSpentInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakedifficulty"), (*GetStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversioninfo"), (*GetStakeVersionInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversions"), (*GetStakeVersionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsubmitblockstatus"), (*GetSubmitBlockStatusCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsyncstatus"), (*GetSyncStatusCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getticketpoolvalue"), (*GetTicketPoolValueCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettreasurybalance"), (*GetTreasuryBalanceCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettreasuryspendvotes"), (*GetTreasurySpendVotesCmd)(nil), flags)
@@ -1140,15 +1543,22 @@ func init() {
 	dcrjson.MustRegister(Method("getwork"), (*GetWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("help"), (*HelpCmd)(nil), flags)
 	dcrjson.MustRegister(Method("invalidateblock"), (*InvalidateBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("listbanned"), (*ListBannedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("livetickets"), (*LiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("node"), (*NodeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
+	dcrjson.MustRegister(Method("prioritisetransaction"), (*PrioritiseTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("reconsiderblock"), (*ReconsiderBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("regentemplate"), (*RegenTemplateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("rpc.discover"), (*RPCDiscoverCmd)(nil), flags)
+	dcrjson.MustRegister(Method("scantxoutset"), (*ScanTxOutSetCmd)(nil), flags)
+	dcrjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setban"), (*SetBanCmd)(nil), flags)
 	dcrjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stop"), (*StopCmd)(nil), flags)
 	dcrjson.MustRegister(Method("submitblock"), (*SubmitBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("submitblocknowait"), (*SubmitBlockNoWaitCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketfeeinfo"), (*TicketFeeInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketsforaddress"), (*TicketsForAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketvwap"), (*TicketVWAPCmd)(nil), flags)
@@ -1156,5 +1566,6 @@ func init() {
 	dcrjson.MustRegister(Method("validateaddress"), (*ValidateAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("verifychain"), (*VerifyChainCmd)(nil), flags)
 	dcrjson.MustRegister(Method("verifymessage"), (*VerifyMessageCmd)(nil), flags)
+	dcrjson.MustRegister(Method("verifyownershipproof"), (*VerifyOwnershipProofCmd)(nil), flags)
 	dcrjson.MustRegister(Method("version"), (*VersionCmd)(nil), flags)
 }
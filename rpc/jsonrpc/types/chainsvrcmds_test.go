@@ -41,6 +41,28 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "backupchainstate",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("backupchainstate"), "backup")
+			},
+			staticCmd: func() interface{} {
+				return NewBackupChainStateCmd("backup")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"backupchainstate","params":["backup"],"id":1}`,
+			unmarshalled: &BackupChainStateCmd{Dir: "backup"},
+		},
+		{
+			name: "clearbanned",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("clearbanned"))
+			},
+			staticCmd: func() interface{} {
+				return NewClearBannedCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"clearbanned","params":[],"id":1}`,
+			unmarshalled: &ClearBannedCmd{},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +151,35 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00",1],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00", Version: dcrjson.Uint16(1)},
 		},
+		{
+			name: "decodescript redeemscript",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("decodescript"), "00", dcrjson.Uint16(0), "51")
+			},
+			staticCmd: func() interface{} {
+				cmd := NewDecodeScriptCmd("00")
+				cmd.Version = dcrjson.Uint16(0)
+				cmd.RedeemScript = dcrjson.String("51")
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"decodescript","params":["00",0,"51"],"id":1}`,
+			unmarshalled: &DecodeScriptCmd{
+				HexScript:    "00",
+				Version:      dcrjson.Uint16(0),
+				RedeemScript: dcrjson.String("51"),
+			},
+		},
+		{
+			name: "dumptxoutset",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("dumptxoutset"), "utxos.dat")
+			},
+			staticCmd: func() interface{} {
+				return NewDumpTxOutSetCmd("utxos.dat")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"dumptxoutset","params":["utxos.dat"],"id":1}`,
+			unmarshalled: &DumpTxOutSetCmd{Path: "utxos.dat"},
+		},
 		{
 			name: "estimatefee",
 			newCmd: func() (interface{}, error) {
@@ -237,13 +288,14 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getblock"), "123")
 			},
 			staticCmd: func() interface{} {
-				return NewGetBlockCmd("123", nil, nil)
+				return NewGetBlockCmd("123", nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123"],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(false),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(false),
+				VerbosePrevOut: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -256,13 +308,14 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getblock"), "123", &verbosePtr)
 			},
 			staticCmd: func() interface{} {
-				return NewGetBlockCmd("123", dcrjson.Bool(true), nil)
+				return NewGetBlockCmd("123", dcrjson.Bool(true), nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(false),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(false),
+				VerbosePrevOut: dcrjson.Bool(false),
 			},
 		},
 		{
@@ -271,13 +324,44 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getblock"), "123", true, true)
 			},
 			staticCmd: func() interface{} {
-				return NewGetBlockCmd("123", dcrjson.Bool(true), dcrjson.Bool(true))
+				return NewGetBlockCmd("123", dcrjson.Bool(true), dcrjson.Bool(true), nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true],"id":1}`,
 			unmarshalled: &GetBlockCmd{
-				Hash:      "123",
-				Verbose:   dcrjson.Bool(true),
-				VerboseTx: dcrjson.Bool(true),
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(true),
+				VerbosePrevOut: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock required optional3",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblock"), "123", true, true, true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockCmd("123", dcrjson.Bool(true), dcrjson.Bool(true),
+					dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true,true],"id":1}`,
+			unmarshalled: &GetBlockCmd{
+				Hash:           "123",
+				Verbose:        dcrjson.Bool(true),
+				VerboseTx:      dcrjson.Bool(true),
+				VerbosePrevOut: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getblockbytime",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblockbytime"), 1600000000)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockByTimeCmd(1600000000)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockbytime","params":[1600000000],"id":1}`,
+			unmarshalled: &GetBlockByTimeCmd{
+				Timestamp: 1600000000,
 			},
 		},
 		{
@@ -327,6 +411,39 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Bool(true),
 			},
 		},
+		{
+			name: "getblocksbytime",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocksbytime"), 1600000000,
+					1600086400)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlocksByTimeCmd(1600000000, 1600086400, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocksbytime","params":[1600000000,1600086400],"id":1}`,
+			unmarshalled: &GetBlocksByTimeCmd{
+				StartTime: 1600000000,
+				EndTime:   1600086400,
+				Count:     dcrjson.Int(100),
+			},
+		},
+		{
+			name: "getblocksbytime optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocksbytime"), 1600000000,
+					1600086400, 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlocksByTimeCmd(1600000000, 1600086400,
+					dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocksbytime","params":[1600000000,1600086400,10],"id":1}`,
+			unmarshalled: &GetBlocksByTimeCmd{
+				StartTime: 1600000000,
+				EndTime:   1600086400,
+				Count:     dcrjson.Int(10),
+			},
+		},
 		{
 			name: "getblocksubsidy",
 			newCmd: func() (interface{}, error) {
@@ -341,6 +458,37 @@ func TestChainSvrCmds(t *testing.T) {
 				Voters: 256,
 			},
 		},
+		{
+			name: "getcfiltersv2",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfiltersv2"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFiltersV2Cmd(100, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfiltersv2","params":[100],"id":1}`,
+			unmarshalled: &GetCFiltersV2Cmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(100),
+				IncludeData: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getcfiltersv2 optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfiltersv2"), 100, 10, false)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFiltersV2Cmd(100, dcrjson.Int(10),
+					dcrjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfiltersv2","params":[100,10,false],"id":1}`,
+			unmarshalled: &GetCFiltersV2Cmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(10),
+				IncludeData: dcrjson.Bool(false),
+			},
+		},
 		{
 			name: "getcfilterv2",
 			newCmd: func() (interface{}, error) {
@@ -387,6 +535,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getcurrentnet","params":[],"id":1}`,
 			unmarshalled: &GetCurrentNetCmd{},
 		},
+		{
+			name: "getdebuglevels",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getdebuglevels"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetDebugLevelsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getdebuglevels","params":[],"id":1}`,
+			unmarshalled: &GetDebugLevelsCmd{},
+		},
 		{
 			name: "getdifficulty",
 			newCmd: func() (interface{}, error) {
@@ -431,6 +590,75 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getinfo","params":[],"id":1}`,
 			unmarshalled: &GetInfoCmd{},
 		},
+		{
+			name: "getmempoolancestors",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				TxHash:  "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempoolancestors optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolancestors"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolAncestorsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolancestors","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolAncestorsCmd{
+				TxHash:  "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempooldescendants",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				TxHash:  "123",
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getmempooldescendants optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempooldescendants"), "123", true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolDescendantsCmd("123", dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempooldescendants","params":["123",true],"id":1}`,
+			unmarshalled: &GetMempoolDescendantsCmd{
+				TxHash:  "123",
+				Verbose: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getmempoolentry",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getmempoolentry"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetMempoolEntryCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getmempoolentry","params":["123"],"id":1}`,
+			unmarshalled: &GetMempoolEntryCmd{
+				TxHash: "123",
+			},
+		},
 		{
 			name: "getmempoolinfo",
 			newCmd: func() (interface{}, error) {
@@ -517,6 +745,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Height: dcrjson.Int(123),
 			},
 		},
+		{
+			name: "getnodestatus",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getnodestatus"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetNodeStatusCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getnodestatus","params":[],"id":1}`,
+			unmarshalled: &GetNodeStatusCmd{},
+		},
 		{
 			name: "getpeerinfo",
 			newCmd: func() (interface{}, error) {
@@ -568,6 +807,25 @@ func TestChainSvrCmds(t *testing.T) {
 				TxType:  dcrjson.String("all"),
 			},
 		},
+		{
+			name: "getrawmempool paginated",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrawmempool"), true, "all", 100, "00")
+			},
+			staticCmd: func() interface{} {
+				cmd := NewGetRawMempoolCmd(dcrjson.Bool(true), dcrjson.String("all"))
+				cmd.Count = dcrjson.Int(100)
+				cmd.Cursor = dcrjson.String("00")
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrawmempool","params":[true,"all",100,"00"],"id":1}`,
+			unmarshalled: &GetRawMempoolCmd{
+				Verbose: dcrjson.Bool(true),
+				TxType:  dcrjson.String("all"),
+				Count:   dcrjson.Int(100),
+				Cursor:  dcrjson.String("00"),
+			},
+		},
 		{
 			name: "getrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -596,6 +854,44 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Int(1),
 			},
 		},
+		{
+			name: "getrejectedtransaction",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrejectedtransaction"), "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetRejectedTransactionCmd("123")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrejectedtransaction","params":["123"],"id":1}`,
+			unmarshalled: &GetRejectedTransactionCmd{
+				Txid: "123",
+			},
+		},
+		{
+			name: "getrpclimitinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrpclimitinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetRPCLimitInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getrpclimitinfo","params":[],"id":1}`,
+			unmarshalled: &GetRPCLimitInfoCmd{},
+		},
+		{
+			name: "getspentinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getspentinfo"), "123", 1)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSpentInfoCmd("123", 1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getspentinfo","params":["123",1],"id":1}`,
+			unmarshalled: &GetSpentInfoCmd{
+				Txid: "123",
+				Vout: 1,
+			},
+		},
 		{
 			name: "getstakeversions",
 			newCmd: func() (interface{}, error) {
@@ -610,6 +906,30 @@ func TestChainSvrCmds(t *testing.T) {
 				Count: 1,
 			},
 		},
+		{
+			name: "getsubmitblockstatus",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsubmitblockstatus"), "deadbeef")
+			},
+			staticCmd: func() interface{} {
+				return NewGetSubmitBlockStatusCmd("deadbeef")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getsubmitblockstatus","params":["deadbeef"],"id":1}`,
+			unmarshalled: &GetSubmitBlockStatusCmd{
+				ID: "deadbeef",
+			},
+		},
+		{
+			name: "getsyncstatus",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsyncstatus"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetSyncStatusCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getsyncstatus","params":[],"id":1}`,
+			unmarshalled: &GetSyncStatusCmd{},
+		},
 		{
 			name: "gettxout",
 			newCmd: func() (interface{}, error) {
@@ -714,7 +1034,7 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getwork"))
 			},
 			staticCmd: func() interface{} {
-				return NewGetWorkCmd(nil)
+				return NewGetWorkCmd(nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getwork","params":[],"id":1}`,
 			unmarshalled: &GetWorkCmd{
@@ -727,13 +1047,27 @@ func TestChainSvrCmds(t *testing.T) {
 				return dcrjson.NewCmd(Method("getwork"), "00112233")
 			},
 			staticCmd: func() interface{} {
-				return NewGetWorkCmd(dcrjson.String("00112233"))
+				return NewGetWorkCmd(dcrjson.String("00112233"), nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"getwork","params":["00112233"],"id":1}`,
 			unmarshalled: &GetWorkCmd{
 				Data: dcrjson.String("00112233"),
 			},
 		},
+		{
+			name: "getwork longpollid",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getwork"), "", "abcd")
+			},
+			staticCmd: func() interface{} {
+				return NewGetWorkCmd(dcrjson.String(""), dcrjson.String("abcd"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getwork","params":["","abcd"],"id":1}`,
+			unmarshalled: &GetWorkCmd{
+				Data:       dcrjson.String(""),
+				LongPollID: dcrjson.String("abcd"),
+			},
+		},
 		{
 			name: "help",
 			newCmd: func() (interface{}, error) {
@@ -803,6 +1137,45 @@ func TestChainSvrCmds(t *testing.T) {
 				ConnectSubCmd: dcrjson.String("perm"),
 			},
 		},
+		{
+			name: "listbanned",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("listbanned"))
+			},
+			staticCmd: func() interface{} {
+				return NewListBannedCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"listbanned","params":[],"id":1}`,
+			unmarshalled: &ListBannedCmd{},
+		},
+		{
+			name: "livetickets",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("livetickets"))
+			},
+			staticCmd: func() interface{} {
+				return NewLiveTicketsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"livetickets","params":[],"id":1}`,
+			unmarshalled: &LiveTicketsCmd{},
+		},
+		{
+			name: "livetickets paginated",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("livetickets"), 10, "00")
+			},
+			staticCmd: func() interface{} {
+				cmd := NewLiveTicketsCmd()
+				cmd.Count = dcrjson.Int(10)
+				cmd.Cursor = dcrjson.String("00")
+				return cmd
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"livetickets","params":[10,"00"],"id":1}`,
+			unmarshalled: &LiveTicketsCmd{
+				Count:  dcrjson.Int(10),
+				Cursor: dcrjson.String("00"),
+			},
+		},
 		{
 			name: "ping",
 			newCmd: func() (interface{}, error) {
@@ -814,6 +1187,99 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "prioritisetransaction",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("prioritisetransaction"), "123", -1000)
+			},
+			staticCmd: func() interface{} {
+				return NewPrioritiseTransactionCmd("123", -1000)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"prioritisetransaction","params":["123",-1000],"id":1}`,
+			unmarshalled: &PrioritiseTransactionCmd{
+				Txid:     "123",
+				FeeDelta: -1000,
+			},
+		},
+		{
+			name: "rpc.discover",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("rpc.discover"))
+			},
+			staticCmd: func() interface{} {
+				return NewRPCDiscoverCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"rpc.discover","params":[],"id":1}`,
+			unmarshalled: &RPCDiscoverCmd{},
+		},
+		{
+			name: "scantxoutset",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("scantxoutset"), "status")
+			},
+			staticCmd: func() interface{} {
+				return NewScanTxOutSetCmd("status", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"scantxoutset","params":["status"],"id":1}`,
+			unmarshalled: &ScanTxOutSetCmd{
+				Action: "status",
+			},
+		},
+		{
+			name: "scantxoutset optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("scantxoutset"), "start",
+					[]string{"addr(DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3)"})
+			},
+			staticCmd: func() interface{} {
+				return NewScanTxOutSetCmd("start",
+					&[]string{"addr(DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3)"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"scantxoutset","params":["start",["addr(DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3)"]],"id":1}`,
+			unmarshalled: &ScanTxOutSetCmd{
+				Action:      "start",
+				ScanObjects: &[]string{"addr(DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3)"},
+			},
+		},
+		{
+			name: "searchrawtransactions",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"),
+					"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3")
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd(
+					"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3", nil, nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3"],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address: "DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3",
+				Verbose: dcrjson.Int(1),
+				Skip:    dcrjson.Int(0),
+				Count:   dcrjson.Int(100),
+				Reverse: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "searchrawtransactions optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("searchrawtransactions"),
+					"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3", 0, 10, 20, true)
+			},
+			staticCmd: func() interface{} {
+				return NewSearchRawTransactionsCmd(
+					"DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3", dcrjson.Int(0),
+					dcrjson.Int(10), dcrjson.Int(20), dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"searchrawtransactions","params":["DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3",0,10,20,true],"id":1}`,
+			unmarshalled: &SearchRawTransactionsCmd{
+				Address: "DsbjabD32RuS1deAj2uTjKfFZ6nSza5qVf3",
+				Verbose: dcrjson.Int(0),
+				Skip:    dcrjson.Int(10),
+				Count:   dcrjson.Int(20),
+				Reverse: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "sendrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -842,6 +1308,40 @@ func TestChainSvrCmds(t *testing.T) {
 				AllowHighFees: dcrjson.Bool(false),
 			},
 		},
+		{
+			name: "setban",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "10.0.0.0/8", SBAdd)
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("10.0.0.0/8", SBAdd, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["10.0.0.0/8","add"],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Subnet:   "10.0.0.0/8",
+				SubCmd:   SBAdd,
+				BanTime:  dcrjson.Int64(0),
+				Absolute: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "setban optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setban"), "10.0.0.1", SBAdd,
+					1700000000, true)
+			},
+			staticCmd: func() interface{} {
+				return NewSetBanCmd("10.0.0.1", SBAdd,
+					dcrjson.Int64(1700000000), dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setban","params":["10.0.0.1","add",1700000000,true],"id":1}`,
+			unmarshalled: &SetBanCmd{
+				Subnet:   "10.0.0.1",
+				SubCmd:   SBAdd,
+				BanTime:  dcrjson.Int64(1700000000),
+				Absolute: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "setgenerate",
 			newCmd: func() (interface{}, error) {
@@ -914,6 +1414,20 @@ func TestChainSvrCmds(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "submitblocknowait",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("submitblocknowait"), "112233")
+			},
+			staticCmd: func() interface{} {
+				return NewSubmitBlockNoWaitCmd("112233", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"submitblocknowait","params":["112233"],"id":1}`,
+			unmarshalled: &SubmitBlockNoWaitCmd{
+				HexBlock: "112233",
+				Options:  nil,
+			},
+		},
 		{
 			name: "validateaddress",
 			newCmd: func() (interface{}, error) {
@@ -984,6 +1498,21 @@ func TestChainSvrCmds(t *testing.T) {
 				Message:   "test",
 			},
 		},
+		{
+			name: "verifyownershipproof",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("verifyownershipproof"), "1Address", "test", "AQA=")
+			},
+			staticCmd: func() interface{} {
+				return NewVerifyOwnershipProofCmd("1Address", "test", "AQA=")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"verifyownershipproof","params":["1Address","test","AQA="],"id":1}`,
+			unmarshalled: &VerifyOwnershipProofCmd{
+				Address: "1Address",
+				Message: "test",
+				Proof:   "AQA=",
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
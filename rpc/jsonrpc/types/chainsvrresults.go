@@ -7,6 +7,14 @@ package types
 
 import "encoding/json"
 
+// BackupChainStateResult models the data returned from the backupchainstate
+// command.
+type BackupChainStateResult struct {
+	Dir     string  `json:"dir"`
+	Bytes   uint64  `json:"bytes"`
+	Elapsed float64 `json:"elapsed"`
+}
+
 // TxRawDecodeResult models the data from the decoderawtransaction command.
 type TxRawDecodeResult struct {
 	Txid     string `json:"txid"`
@@ -17,13 +25,44 @@ type TxRawDecodeResult struct {
 	Vout     []Vout `json:"vout"`
 }
 
+// DecodeScriptOp models a single opcode of a script along with its offset in
+// the script as returned from the decodescript command.
+type DecodeScriptOp struct {
+	Offset int    `json:"offset"`
+	Asm    string `json:"asm"`
+}
+
 // DecodeScriptResult models the data returned from the decodescript command.
 type DecodeScriptResult struct {
-	Asm       string   `json:"asm"`
-	ReqSigs   int32    `json:"reqSigs,omitempty"`
-	Type      string   `json:"type"`
-	Addresses []string `json:"addresses,omitempty"`
-	P2sh      string   `json:"p2sh,omitempty"`
+	Asm           string                    `json:"asm"`
+	ReqSigs       int32                     `json:"reqSigs,omitempty"`
+	Type          string                    `json:"type"`
+	StakeSubClass string                    `json:"stakesubclass,omitempty"`
+	Addresses     []string                  `json:"addresses,omitempty"`
+	P2sh          string                    `json:"p2sh,omitempty"`
+	Ops           []DecodeScriptOp          `json:"ops"`
+	RedeemScript  *DecodeRedeemScriptResult `json:"redeemscript,omitempty"`
+}
+
+// DecodeRedeemScriptResult models the data returned from the decodescript
+// command for the redeem script of a pay-to-script-hash script.
+type DecodeRedeemScriptResult struct {
+	Asm           string           `json:"asm"`
+	ReqSigs       int32            `json:"reqSigs,omitempty"`
+	Type          string           `json:"type"`
+	StakeSubClass string           `json:"stakesubclass,omitempty"`
+	Addresses     []string         `json:"addresses,omitempty"`
+	P2sh          string           `json:"p2sh,omitempty"`
+	Ops           []DecodeScriptOp `json:"ops"`
+}
+
+// DumpTxOutSetResult models the data returned from the dumptxoutset command.
+type DumpTxOutSetResult struct {
+	Utxos     int64  `json:"utxos"`
+	BlockHash string `json:"blockhash"`
+	Height    int64  `json:"height"`
+	MuHash    string `json:"muhash"`
+	Path      string `json:"path"`
 }
 
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee
@@ -115,6 +154,15 @@ type GetBestBlockResult struct {
 	Height int64  `json:"height"`
 }
 
+// GetBlockByTimeResult models the data returned from the getblockbytime
+// command and the entries of the data returned from the getblocksbytime
+// command.
+type GetBlockByTimeResult struct {
+	Hash       string `json:"hash"`
+	Height     int64  `json:"height"`
+	MedianTime int64  `json:"mediantime"`
+}
+
 // GetBlockChainInfoResult models the data returned from the getblockchaininfo
 // command.
 type GetBlockChainInfoResult struct {
@@ -179,6 +227,18 @@ type GetChainTipsResult struct {
 	Status    string `json:"status"`
 }
 
+// GetCFiltersV2Result models the entries of the data returned from the
+// getcfiltersv2 command.
+type GetCFiltersV2Result struct {
+	BlockHash   string   `json:"blockhash"`
+	Height      int64    `json:"height"`
+	Header      string   `json:"header"`
+	FilterHash  string   `json:"filterhash"`
+	Data        string   `json:"data,omitempty"`
+	ProofIndex  uint32   `json:"proofindex"`
+	ProofHashes []string `json:"proofhashes"`
+}
+
 // GetCFilterV2Result models the data returned from the getcfilterv2 command.
 type GetCFilterV2Result struct {
 	BlockHash   string   `json:"blockhash"`
@@ -208,6 +268,25 @@ type InfoChainResult struct {
 	TxIndex         bool    `json:"txindex"`
 }
 
+// GetMempoolEntryResult models the data returned from the getmempoolentry
+// command and the verbose getmempoolancestors and getmempooldescendants
+// commands.
+type GetMempoolEntryResult struct {
+	Size            int32    `json:"size"`
+	Fee             float64  `json:"fee"`
+	Time            int64    `json:"time"`
+	Height          int64    `json:"height"`
+	Type            string   `json:"type"`
+	Depends         []string `json:"depends"`
+	SpentBy         []string `json:"spentby"`
+	AncestorCount   int64    `json:"ancestorcount"`
+	AncestorSize    int64    `json:"ancestorsize"`
+	AncestorFees    float64  `json:"ancestorfees"`
+	DescendantCount int64    `json:"descendantcount"`
+	DescendantSize  int64    `json:"descendantsize"`
+	DescendantFees  float64  `json:"descendantfees"`
+}
+
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
@@ -265,9 +344,42 @@ type GetNetworkInfoResult struct {
 
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
-	TotalBytesRecv uint64 `json:"totalbytesrecv"`
-	TotalBytesSent uint64 `json:"totalbytessent"`
-	TimeMillis     int64  `json:"timemillis"`
+	TotalBytesRecv  uint64            `json:"totalbytesrecv"`
+	TotalBytesSent  uint64            `json:"totalbytessent"`
+	BytesRecvPerMsg map[string]uint64 `json:"bytesrecvpermsg"`
+	BytesSentPerMsg map[string]uint64 `json:"bytessentpermsg"`
+	TimeMillis      int64             `json:"timemillis"`
+}
+
+// NodeIndexStatus models the sync progress of an optional index as part of the
+// data returned from the getnodestatus command.
+type NodeIndexStatus struct {
+	Name     string  `json:"name"`
+	Height   int64   `json:"height"`
+	Synced   bool    `json:"synced"`
+	Progress float64 `json:"progress"`
+}
+
+// GetNodeStatusResult models the data returned from the getnodestatus command.
+type GetNodeStatusResult struct {
+	Healthy      bool              `json:"healthy"`
+	Ready        bool              `json:"ready"`
+	Reasons      []string          `json:"reasons"`
+	Synced       bool              `json:"synced"`
+	Peers        int32             `json:"peers"`
+	BestHeight   int64             `json:"bestheight"`
+	BestHash     string            `json:"besthash"`
+	LastBlockAge int64             `json:"lastblockage"`
+	DatabaseOK   bool              `json:"databaseok"`
+	Indexes      []NodeIndexStatus `json:"indexes"`
+}
+
+// PeerPingBucket models a bucket of the ping round-trip time histogram returned
+// as part of the getpeerinfo command.  The final bucket is unbounded and omits
+// the upper bound.
+type PeerPingBucket struct {
+	UpperBound float64 `json:"upperbound,omitempty"`
+	Count      uint64  `json:"count"`
 }
 
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
@@ -292,6 +404,17 @@ type GetPeerInfoResult struct {
 	CurrentHeight  int64   `json:"currentheight,omitempty"`
 	BanScore       int32   `json:"banscore"`
 	SyncNode       bool    `json:"syncnode"`
+
+	NumPings      uint64           `json:"numpings"`
+	MinPingTime   float64          `json:"minpingtime"`
+	AvgPingTime   float64          `json:"avgpingtime"`
+	MaxPingTime   float64          `json:"maxpingtime"`
+	PingHistogram []PeerPingBucket `json:"pinghistogram"`
+
+	BytesSentPerMsg map[string]uint64 `json:"bytessentpermsg"`
+	BytesRecvPerMsg map[string]uint64 `json:"bytesrecvpermsg"`
+	MsgsSentPerMsg  map[string]uint64 `json:"msgssentpermsg"`
+	MsgsRecvPerMsg  map[string]uint64 `json:"msgsrecvpermsg"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -309,6 +432,56 @@ type GetRawMempoolVerboseResult struct {
 	Depends         []string `json:"depends"`
 }
 
+// GetRawMempoolPageResult models the data returned from the getrawmempool
+// command when a count is provided and the verbose flag is not set.  The next
+// cursor is empty once there are no more transactions.
+type GetRawMempoolPageResult struct {
+	TxHashes   []string `json:"txhashes"`
+	NextCursor string   `json:"nextcursor,omitempty"`
+}
+
+// GetRawMempoolVerbosePageResult models the data returned from the
+// getrawmempool command when a count is provided and the verbose flag is set.
+// The next cursor is empty once there are no more transactions.
+type GetRawMempoolVerbosePageResult struct {
+	Transactions map[string]*GetRawMempoolVerboseResult `json:"transactions"`
+	NextCursor   string                                 `json:"nextcursor,omitempty"`
+}
+
+// GetRejectedTransactionResult models the data returned from the
+// getrejectedtransaction command.
+type GetRejectedTransactionResult struct {
+	Txid   string `json:"txid"`
+	Time   int64  `json:"time"`
+	Source string `json:"source"`
+	PeerID uint64 `json:"peerid,omitempty"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// RPCLimitClientInfo models the request statistics of a single client tracked
+// by the RPC server limits returned from the getrpclimitinfo command.
+type RPCLimitClientInfo struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	InFlight int    `json:"inflight"`
+	Allowed  uint64 `json:"allowed"`
+	Limited  uint64 `json:"limited"`
+}
+
+// GetRPCLimitInfoResult models the data returned from the getrpclimitinfo
+// command.
+type GetRPCLimitInfoResult struct {
+	UserReqRate           float64              `json:"userreqrate"`
+	IPReqRate             float64              `json:"ipreqrate"`
+	UserMaxConcurrentReqs int                  `json:"usermaxconcurrentreqs"`
+	IPMaxConcurrentReqs   int                  `json:"ipmaxconcurrentreqs"`
+	Allowed               uint64               `json:"allowed"`
+	RateLimited           uint64               `json:"ratelimited"`
+	ConcurrencyLimited    uint64               `json:"concurrencylimited"`
+	Clients               []RPCLimitClientInfo `json:"clients"`
+}
+
 // TxRawResult models the data from the getrawtransaction command.
 type TxRawResult struct {
 	Hex           string `json:"hex"`
@@ -326,6 +499,15 @@ type TxRawResult struct {
 	Blocktime     int64  `json:"blocktime,omitempty"`
 }
 
+// GetSpentInfoResult models the data returned from the getspentinfo command.
+type GetSpentInfoResult struct {
+	Txid      string `json:"txid"`
+	Vin       uint32 `json:"vin"`
+	Tree      int8   `json:"tree"`
+	Height    int64  `json:"height"`
+	BlockHash string `json:"blockhash"`
+}
+
 // GetStakeDifficultyResult models the data returned from the
 // getstakedifficulty command.
 type GetStakeDifficultyResult struct {
@@ -376,6 +558,31 @@ type GetStakeVersionsResult struct {
 	StakeVersions []StakeVersions `json:"stakeversions"`
 }
 
+// GetSubmitBlockStatusResult models the data returned from the
+// getsubmitblockstatus command.
+type GetSubmitBlockStatusResult struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	SubmitTime   int64  `json:"submittime"`
+	CompleteTime int64  `json:"completetime,omitempty"`
+}
+
+// GetSyncStatusResult models the data returned from the getsyncstatus command.
+type GetSyncStatusResult struct {
+	Stage            string  `json:"stage"`
+	SyncPeerID       int32   `json:"syncpeerid"`
+	SyncHeight       int64   `json:"syncheight"`
+	BestHeaderHeight int64   `json:"bestheaderheight"`
+	HeaderProgress   float64 `json:"headerprogress"`
+	BlocksDownloaded uint64  `json:"blocksdownloaded"`
+	BlocksInFlight   int     `json:"blocksinflight"`
+	BestHeight       int64   `json:"bestheight"`
+	VerifyProgress   float64 `json:"verifyprogress"`
+	BlocksPerSecond  float64 `json:"blockspersecond"`
+	ETASeconds       int64   `json:"etaseconds"`
+}
+
 // GetTxOutResult models the data from the gettxout command.
 type GetTxOutResult struct {
 	BestBlock     string             `json:"bestblock"`
@@ -392,10 +599,58 @@ type GetTxOutSetInfoResult struct {
 	Transactions   int64  `json:"transactions"`
 	TxOuts         int64  `json:"txouts"`
 	SerializedHash string `json:"serializedhash"`
+	MuHash         string `json:"muhash"`
 	DiskSize       int64  `json:"disksize"`
 	TotalAmount    int64  `json:"totalamount"`
 }
 
+// ScanTxOutSetUnspent models an unspent transaction output that matches one
+// of the scan objects of the scantxoutset command.
+type ScanTxOutSetUnspent struct {
+	Txid          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Tree          int8    `json:"tree"`
+	ScriptPubKey  string  `json:"scriptpubkey"`
+	ScriptVersion uint16  `json:"scriptversion"`
+	Desc          string  `json:"desc"`
+	Amount        float64 `json:"amount"`
+	Height        int64   `json:"height"`
+	Coinbase      bool    `json:"coinbase"`
+}
+
+// ScanTxOutSetResult models the data from the scantxoutset command when a scan
+// is started.
+type ScanTxOutSetResult struct {
+	Success       bool                  `json:"success"`
+	SearchedItems int64                 `json:"searcheditems"`
+	Height        int64                 `json:"height"`
+	BestBlock     string                `json:"bestblock"`
+	Unspents      []ScanTxOutSetUnspent `json:"unspents"`
+	TotalAmount   float64               `json:"totalamount"`
+}
+
+// ScanTxOutSetStatusResult models the data from the scantxoutset command when
+// the status of a scan in progress is requested.
+type ScanTxOutSetStatusResult struct {
+	Progress float64 `json:"progress"`
+}
+
+// SearchRawTransactionsEntry models an input or output of a transaction that
+// involves the address queried by the searchrawtransactions command.
+type SearchRawTransactionsEntry struct {
+	IsInput bool    `json:"isinput"`
+	Index   uint32  `json:"index"`
+	Amount  float64 `json:"amount"`
+}
+
+// SearchRawTransactionsResult models the data from the searchrawtransactions
+// command when the verbose flag is set.
+type SearchRawTransactionsResult struct {
+	Tx      TxRawResult                  `json:"tx"`
+	Tree    int8                         `json:"tree"`
+	Entries []SearchRawTransactionsEntry `json:"entries"`
+}
+
 // Choice models an individual choice inside an Agenda.
 type Choice struct {
 	ID          string  `json:"id"`
@@ -461,8 +716,9 @@ type GetTreasurySpendVotesResult struct {
 
 // GetWorkResult models the data from the getwork command.
 type GetWorkResult struct {
-	Data   string `json:"data"`
-	Target string `json:"target"`
+	Data       string `json:"data"`
+	Target     string `json:"target"`
+	LongPollID string `json:"longpollid,omitempty"`
 }
 
 // Ticket is the structure representing a ticket.
@@ -471,10 +727,19 @@ type Ticket struct {
 	Owner string `json:"owner"`
 }
 
+// ListBannedResult models the data returned from the listbanned command.
+type ListBannedResult struct {
+	Address     string `json:"address"`
+	BanCreated  int64  `json:"bancreated"`
+	BannedUntil int64  `json:"banneduntil"`
+	BanReason   string `json:"banreason"`
+}
+
 // LiveTicketsResult models the data returned from the livetickets
 // command.
 type LiveTicketsResult struct {
-	Tickets []string `json:"tickets"`
+	Tickets    []string `json:"tickets"`
+	NextCursor string   `json:"nextcursor,omitempty"`
 }
 
 // FeeInfoBlock is ticket fee information about a block.
@@ -580,6 +845,13 @@ type ScriptSig struct {
 	Hex string `json:"hex"`
 }
 
+// PrevOut models the data of the previous output spent by a transaction input.
+// It is only included when requested since it requires additional lookups.
+type PrevOut struct {
+	Value        float64            `json:"value"`
+	ScriptPubKey ScriptPubKeyResult `json:"scriptPubKey"`
+}
+
 // Vin models parts of the tx data.  It is defined separately since
 // getrawtransaction and decoderawtransaction use the same structure.
 type Vin struct {
@@ -595,6 +867,7 @@ type Vin struct {
 	BlockHeight   uint32     `json:"blockheight"`
 	BlockIndex    uint32     `json:"blockindex"`
 	ScriptSig     *ScriptSig `json:"scriptSig"`
+	PrevOut       *PrevOut   `json:"prevOut,omitempty"`
 }
 
 // IsCoinBase returns whether or not an input is a coinbase input.
@@ -689,6 +962,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight uint32     `json:"blockheight"`
 		BlockIndex  uint32     `json:"blockindex"`
 		ScriptSig   *ScriptSig `json:"scriptSig"`
+		PrevOut     *PrevOut   `json:"prevOut,omitempty"`
 	}{
 		Txid:        v.Txid,
 		Vout:        v.Vout,
@@ -698,6 +972,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight: v.BlockHeight,
 		BlockIndex:  v.BlockIndex,
 		ScriptSig:   v.ScriptSig,
+		PrevOut:     v.PrevOut,
 	}
 	return json.Marshal(txStruct)
 }
@@ -52,7 +52,13 @@ func NewLoadTxFilterCmd(reload bool, addresses []string, outPoints []OutPoint) *
 }
 
 // NotifyBlocksCmd defines the notifyblocks JSON-RPC command.
-type NotifyBlocksCmd struct{}
+//
+// Since may be set to the hash or height of the most recent block a client was
+// notified of in order to have the notifications for all blocks connected to
+// the main chain after it replayed before any new notifications.
+type NotifyBlocksCmd struct {
+	Since *string
+}
 
 // NewNotifyBlocksCmd returns a new instance which can be used to issue a
 // notifyblocks JSON-RPC command.
@@ -69,6 +75,15 @@ func NewNotifyWorkCmd() *NotifyWorkCmd {
 	return &NotifyWorkCmd{}
 }
 
+// NotifyTemplateDeltasCmd defines the notifytemplatedeltas JSON-RPC command.
+type NotifyTemplateDeltasCmd struct{}
+
+// NewNotifyTemplateDeltasCmd returns a new instance which can be used to issue
+// a notifytemplatedeltas JSON-RPC command.
+func NewNotifyTemplateDeltasCmd() *NotifyTemplateDeltasCmd {
+	return &NotifyTemplateDeltasCmd{}
+}
+
 // NotifyTSpendCmd defines the notifytspend JSON-RPC command.
 type NotifyTSpendCmd struct{}
 
@@ -78,6 +93,24 @@ func NewNotifyTSpendCmd() *NotifyTSpendCmd {
 	return &NotifyTSpendCmd{}
 }
 
+// NotifyMempoolEventsCmd defines the notifymempoolevents JSON-RPC command.
+type NotifyMempoolEventsCmd struct{}
+
+// NewNotifyMempoolEventsCmd returns a new instance which can be used to issue a
+// notifymempoolevents JSON-RPC command.
+func NewNotifyMempoolEventsCmd() *NotifyMempoolEventsCmd {
+	return &NotifyMempoolEventsCmd{}
+}
+
+// NotifyTicketEventsCmd defines the notifyticketevents JSON-RPC command.
+type NotifyTicketEventsCmd struct{}
+
+// NewNotifyTicketEventsCmd returns a new instance which can be used to issue a
+// notifyticketevents JSON-RPC command.
+func NewNotifyTicketEventsCmd() *NotifyTicketEventsCmd {
+	return &NotifyTicketEventsCmd{}
+}
+
 // NotifyWinningTicketsCmd is a type handling custom marshaling and
 // unmarshaling of notifywinningtickets JSON websocket extension
 // commands.
@@ -128,6 +161,16 @@ func NewStopNotifyWorkCmd() *StopNotifyWorkCmd {
 	return &StopNotifyWorkCmd{}
 }
 
+// StopNotifyTemplateDeltasCmd defines the stopnotifytemplatedeltas JSON-RPC
+// command.
+type StopNotifyTemplateDeltasCmd struct{}
+
+// NewStopNotifyTemplateDeltasCmd returns a new instance which can be used to
+// issue a stopnotifytemplatedeltas JSON-RPC command.
+func NewStopNotifyTemplateDeltasCmd() *StopNotifyTemplateDeltasCmd {
+	return &StopNotifyTemplateDeltasCmd{}
+}
+
 // StopNotifyTSpendCmd defines the stopnotifytspend JSON-RPC command.
 type StopNotifyTSpendCmd struct{}
 
@@ -137,6 +180,26 @@ func NewStopNotifyTSpendCmd() *StopNotifyTSpendCmd {
 	return &StopNotifyTSpendCmd{}
 }
 
+// StopNotifyMempoolEventsCmd defines the stopnotifymempoolevents JSON-RPC
+// command.
+type StopNotifyMempoolEventsCmd struct{}
+
+// NewStopNotifyMempoolEventsCmd returns a new instance which can be used to
+// issue a stopnotifymempoolevents JSON-RPC command.
+func NewStopNotifyMempoolEventsCmd() *StopNotifyMempoolEventsCmd {
+	return &StopNotifyMempoolEventsCmd{}
+}
+
+// StopNotifyTicketEventsCmd defines the stopnotifyticketevents JSON-RPC
+// command.
+type StopNotifyTicketEventsCmd struct{}
+
+// NewStopNotifyTicketEventsCmd returns a new instance which can be used to
+// issue a stopnotifyticketevents JSON-RPC command.
+func NewStopNotifyTicketEventsCmd() *StopNotifyTicketEventsCmd {
+	return &StopNotifyTicketEventsCmd{}
+}
+
 // NotifyNewTransactionsCmd defines the notifynewtransactions JSON-RPC command.
 type NotifyNewTransactionsCmd struct {
 	Verbose *bool `jsonrpcdefault:"false"`
@@ -185,6 +248,44 @@ func NewRescanCmd(blockHashes []string) *RescanCmd {
 	return &RescanCmd{BlockHashes: blockHashes}
 }
 
+// RescanFromHeightCmd defines the rescanfromheight JSON-RPC command.
+type RescanFromHeightCmd struct {
+	BeginHeight int64
+	EndHeight   *int64
+}
+
+// NewRescanFromHeightCmd returns a new instance which can be used to issue a
+// rescanfromheight JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRescanFromHeightCmd(beginHeight int64, endHeight *int64) *RescanFromHeightCmd {
+	return &RescanFromHeightCmd{
+		BeginHeight: beginHeight,
+		EndHeight:   endHeight,
+	}
+}
+
+// StreamRawMempoolCmd defines the streamrawmempool JSON-RPC command.
+type StreamRawMempoolCmd struct {
+	Verbose  *bool `jsonrpcdefault:"false"`
+	TxType   *string
+	PageSize *int `jsonrpcdefault:"1000"`
+}
+
+// NewStreamRawMempoolCmd returns a new instance which can be used to issue a
+// streamrawmempool JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewStreamRawMempoolCmd(verbose *bool, txType *string, pageSize *int) *StreamRawMempoolCmd {
+	return &StreamRawMempoolCmd{
+		Verbose:  verbose,
+		TxType:   txType,
+		PageSize: pageSize,
+	}
+}
+
 func init() {
 	// The commands in this file are only usable by websockets.
 	flags := dcrjson.UFWebsocketOnly
@@ -193,15 +294,23 @@ func init() {
 	dcrjson.MustRegister(Method("loadtxfilter"), (*LoadTxFilterCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifyblocks"), (*NotifyBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywork"), (*NotifyWorkCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifytemplatedeltas"), (*NotifyTemplateDeltasCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifytspend"), (*NotifyTSpendCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifymempoolevents"), (*NotifyMempoolEventsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifynewtransactions"), (*NotifyNewTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifynewtickets"), (*NotifyNewTicketsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifyticketevents"), (*NotifyTicketEventsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywinningtickets"), (*NotifyWinningTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastwinners"), (*RebroadcastWinnersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("session"), (*SessionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("streamrawmempool"), (*StreamRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifyblocks"), (*StopNotifyBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifywork"), (*StopNotifyWorkCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifytemplatedeltas"), (*StopNotifyTemplateDeltasCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifytspend"), (*StopNotifyTSpendCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifymempoolevents"), (*StopNotifyMempoolEventsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifynewtransactions"), (*StopNotifyNewTransactionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifyticketevents"), (*StopNotifyTicketEventsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rescan"), (*RescanCmd)(nil), flags)
+	dcrjson.MustRegister(Method("rescanfromheight"), (*RescanFromHeightCmd)(nil), flags)
 }
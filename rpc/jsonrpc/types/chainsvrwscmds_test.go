@@ -74,6 +74,19 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
 			unmarshalled: &NotifyBlocksCmd{},
 		},
+		{
+			name: "notifyblocks since",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyblocks"), "123")
+			},
+			staticCmd: func() interface{} {
+				cmd := NewNotifyBlocksCmd()
+				cmd.Since = dcrjson.String("123")
+				return cmd
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifyblocks","params":["123"],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{Since: dcrjson.String("123")},
+		},
 		{
 			name: "notifywork",
 			newCmd: func() (interface{}, error) {
@@ -96,6 +109,39 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"notifytspend","params":[],"id":1}`,
 			unmarshalled: &NotifyTSpendCmd{},
 		},
+		{
+			name: "notifytemplatedeltas",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifytemplatedeltas"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyTemplateDeltasCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifytemplatedeltas","params":[],"id":1}`,
+			unmarshalled: &NotifyTemplateDeltasCmd{},
+		},
+		{
+			name: "notifymempoolevents",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifymempoolevents"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyMempoolEventsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifymempoolevents","params":[],"id":1}`,
+			unmarshalled: &NotifyMempoolEventsCmd{},
+		},
+		{
+			name: "notifyticketevents",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyticketevents"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyTicketEventsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifyticketevents","params":[],"id":1}`,
+			unmarshalled: &NotifyTicketEventsCmd{},
+		},
 		{
 			name: "stopnotifyblocks",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +175,39 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifytspend","params":[],"id":1}`,
 			unmarshalled: &StopNotifyTSpendCmd{},
 		},
+		{
+			name: "stopnotifytemplatedeltas",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifytemplatedeltas"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyTemplateDeltasCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifytemplatedeltas","params":[],"id":1}`,
+			unmarshalled: &StopNotifyTemplateDeltasCmd{},
+		},
+		{
+			name: "stopnotifymempoolevents",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifymempoolevents"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyMempoolEventsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifymempoolevents","params":[],"id":1}`,
+			unmarshalled: &StopNotifyMempoolEventsCmd{},
+		},
+		{
+			name: "stopnotifyticketevents",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifyticketevents"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyTicketEventsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifyticketevents","params":[],"id":1}`,
+			unmarshalled: &StopNotifyTicketEventsCmd{},
+		},
 		{
 			name: "notifynewtransactions",
 			newCmd: func() (interface{}, error) {
@@ -179,6 +258,62 @@ func TestChainSvrWsCmds(t *testing.T) {
 				BlockHashes: []string{"0000000000000000000000000000000000000000000000000000000000000123"},
 			},
 		},
+		{
+			name: "rescanfromheight",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("rescanfromheight"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewRescanFromHeightCmd(100, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rescanfromheight","params":[100],"id":1}`,
+			unmarshalled: &RescanFromHeightCmd{
+				BeginHeight: 100,
+			},
+		},
+		{
+			name: "rescanfromheight optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("rescanfromheight"), 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return NewRescanFromHeightCmd(100, dcrjson.Int64(200))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rescanfromheight","params":[100,200],"id":1}`,
+			unmarshalled: &RescanFromHeightCmd{
+				BeginHeight: 100,
+				EndHeight:   dcrjson.Int64(200),
+			},
+		},
+		{
+			name: "streamrawmempool",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("streamrawmempool"))
+			},
+			staticCmd: func() interface{} {
+				return NewStreamRawMempoolCmd(nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"streamrawmempool","params":[],"id":1}`,
+			unmarshalled: &StreamRawMempoolCmd{
+				Verbose:  dcrjson.Bool(false),
+				PageSize: dcrjson.Int(1000),
+			},
+		},
+		{
+			name: "streamrawmempool optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("streamrawmempool"), true, "tickets", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewStreamRawMempoolCmd(dcrjson.Bool(true), dcrjson.String("tickets"), dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"streamrawmempool","params":[true,"tickets",10],"id":1}`,
+			unmarshalled: &StreamRawMempoolCmd{
+				Verbose:  dcrjson.Bool(true),
+				TxType:   dcrjson.String("tickets"),
+				PageSize: dcrjson.Int(10),
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -26,10 +26,30 @@ const (
 	// the chain server that a new block template has been generated.
 	WorkNtfnMethod Method = "work"
 
+	// TemplateDeltaNtfnMethod is the method used for notifications from the
+	// chain server that a new block template has been generated which only
+	// include the transactions that were not part of the previous template.
+	TemplateDeltaNtfnMethod Method = "templatedelta"
+
+	// RawMempoolPageNtfnMethod is the method used for notifications from the
+	// chain server that contain a page of the memory pool as requested by the
+	// streamrawmempool command.
+	RawMempoolPageNtfnMethod Method = "rawmempoolpage"
+
 	// TSpendNtfnMethod is the method used for notifications from the chain
 	// server that a new tspend has arrived in the mempool.
 	TSpendNtfnMethod Method = "tspend"
 
+	// MempoolEventNtfnMethod is the method used for notifications from the
+	// chain server that a transaction has been added to or removed from the
+	// mempool.
+	MempoolEventNtfnMethod Method = "mempoolevent"
+
+	// TicketEventNtfnMethod is the method used for notifications from the
+	// chain server that a ticket was purchased, matured, voted, missed, or
+	// revoked.
+	TicketEventNtfnMethod Method = "ticketevent"
+
 	// ReorganizationNtfnMethod is the method used for notifications that the
 	// block chain is in the process of a reorganization.
 	ReorganizationNtfnMethod Method = "reorganization"
@@ -49,6 +69,10 @@ const (
 	// transaction was accepted by the mempool.
 	RelevantTxAcceptedNtfnMethod Method = "relevanttxaccepted"
 
+	// TLSCertRenewedNtfnMethod is the method used for notifications from the
+	// RPC server that it automatically regenerated its TLS certificate.
+	TLSCertRenewedNtfnMethod Method = "tlscertrenewed"
+
 	// WinningTicketsNtfnMethod is the method of the daemon winningtickets
 	// notification.
 	WinningTicketsNtfnMethod Method = "winningtickets"
@@ -117,6 +141,50 @@ func NewWorkNtfn(data string, target string) *WorkNtfn {
 	}
 }
 
+// TemplateDeltaNtfn defines the templatedelta JSON-RPC notification.
+type TemplateDeltaNtfn struct {
+	Header    string   `json:"header"`
+	Reason    string   `json:"reason"`
+	Full      bool     `json:"full"`
+	TxHashes  []string `json:"txhashes"`
+	STxHashes []string `json:"stxhashes"`
+	NewTxns   []string `json:"newtxns"`
+}
+
+// NewTemplateDeltaNtfn returns a new instance which can be used to issue a
+// templatedelta JSON-RPC notification.
+func NewTemplateDeltaNtfn(header, reason string, full bool, txHashes, sTxHashes, newTxns []string) *TemplateDeltaNtfn {
+	return &TemplateDeltaNtfn{
+		Header:    header,
+		Reason:    reason,
+		Full:      full,
+		TxHashes:  txHashes,
+		STxHashes: sTxHashes,
+		NewTxns:   newTxns,
+	}
+}
+
+// RawMempoolPageNtfn defines the rawmempoolpage JSON-RPC notification.  Only
+// one of the transaction hashes or verbose transactions is set depending on
+// whether or not verbose results were requested.
+type RawMempoolPageNtfn struct {
+	Page         int
+	Final        bool
+	TxHashes     []string
+	Transactions map[string]*GetRawMempoolVerboseResult
+}
+
+// NewRawMempoolPageNtfn returns a new instance which can be used to issue a
+// rawmempoolpage JSON-RPC notification.
+func NewRawMempoolPageNtfn(page int, final bool, txHashes []string, txns map[string]*GetRawMempoolVerboseResult) *RawMempoolPageNtfn {
+	return &RawMempoolPageNtfn{
+		Page:         page,
+		Final:        final,
+		TxHashes:     txHashes,
+		Transactions: txns,
+	}
+}
+
 // TSpendNtfn defines the tspend JSON-RPC notification.
 type TSpendNtfn struct {
 	TSpend string `json:"tspend"` // Hex string encoded tspend.
@@ -130,6 +198,50 @@ func NewTSpendNtfn(tspend string) *TSpendNtfn {
 	}
 }
 
+// MempoolEventNtfn defines the mempoolevent JSON-RPC notification.
+type MempoolEventNtfn struct {
+	Sequence   uint64 `json:"sequence"`
+	Event      string `json:"event"`
+	TxID       string `json:"txid"`
+	Reason     string `json:"reason"`
+	ReplacedBy string `json:"replacedby"`
+}
+
+// NewMempoolEventNtfn returns a new instance which can be used to issue a
+// mempoolevent JSON-RPC notification.
+func NewMempoolEventNtfn(sequence uint64, event, txHash, reason, replacedBy string) *MempoolEventNtfn {
+	return &MempoolEventNtfn{
+		Sequence:   sequence,
+		Event:      event,
+		TxID:       txHash,
+		Reason:     reason,
+		ReplacedBy: replacedBy,
+	}
+}
+
+// TicketEventNtfn defines the ticketevent JSON-RPC notification.
+type TicketEventNtfn struct {
+	Event       string `json:"event"`
+	Source      string `json:"source"`
+	Ticket      string `json:"ticket"`
+	TxID        string `json:"txid"`
+	BlockHash   string `json:"blockhash"`
+	BlockHeight int64  `json:"blockheight"`
+}
+
+// NewTicketEventNtfn returns a new instance which can be used to issue a
+// ticketevent JSON-RPC notification.
+func NewTicketEventNtfn(event, source, ticket, txHash, blockHash string, blockHeight int64) *TicketEventNtfn {
+	return &TicketEventNtfn{
+		Event:       event,
+		Source:      source,
+		Ticket:      ticket,
+		TxID:        txHash,
+		BlockHash:   blockHash,
+		BlockHeight: blockHeight,
+	}
+}
+
 // ReorganizationNtfn defines the reorganization JSON-RPC notification.
 type ReorganizationNtfn struct {
 	OldHash   string `json:"oldhash"`
@@ -190,6 +302,21 @@ func NewRelevantTxAcceptedNtfn(txHex string) *RelevantTxAcceptedNtfn {
 	return &RelevantTxAcceptedNtfn{Transaction: txHex}
 }
 
+// TLSCertRenewedNtfn defines the tlscertrenewed JSON-RPC notification.
+type TLSCertRenewedNtfn struct {
+	Cert       string `json:"cert"`
+	ValidUntil int64  `json:"validuntil"`
+}
+
+// NewTLSCertRenewedNtfn returns a new instance which can be used to issue a
+// tlscertrenewed JSON-RPC notification.
+func NewTLSCertRenewedNtfn(cert string, validUntil int64) *TLSCertRenewedNtfn {
+	return &TLSCertRenewedNtfn{
+		Cert:       cert,
+		ValidUntil: validUntil,
+	}
+}
+
 // WinningTicketsNtfn is a type handling custom marshaling and
 // unmarshaling of blockconnected JSON websocket notifications.
 type WinningTicketsNtfn struct {
@@ -215,11 +342,16 @@ func init() {
 	dcrjson.MustRegister(BlockConnectedNtfnMethod, (*BlockConnectedNtfn)(nil), flags)
 	dcrjson.MustRegister(BlockDisconnectedNtfnMethod, (*BlockDisconnectedNtfn)(nil), flags)
 	dcrjson.MustRegister(WorkNtfnMethod, (*WorkNtfn)(nil), flags)
+	dcrjson.MustRegister(TemplateDeltaNtfnMethod, (*TemplateDeltaNtfn)(nil), flags)
+	dcrjson.MustRegister(RawMempoolPageNtfnMethod, (*RawMempoolPageNtfn)(nil), flags)
 	dcrjson.MustRegister(TSpendNtfnMethod, (*TSpendNtfn)(nil), flags)
+	dcrjson.MustRegister(MempoolEventNtfnMethod, (*MempoolEventNtfn)(nil), flags)
+	dcrjson.MustRegister(TicketEventNtfnMethod, (*TicketEventNtfn)(nil), flags)
 	dcrjson.MustRegister(NewTicketsNtfnMethod, (*NewTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(ReorganizationNtfnMethod, (*ReorganizationNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	dcrjson.MustRegister(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
 	dcrjson.MustRegister(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
+	dcrjson.MustRegister(TLSCertRenewedNtfnMethod, (*TLSCertRenewedNtfn)(nil), flags)
 	dcrjson.MustRegister(WinningTicketsNtfnMethod, (*WinningTicketsNtfn)(nil), flags)
 }
@@ -56,6 +56,75 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Header: "header",
 			},
 		},
+		{
+			name: "templatedelta",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("templatedelta"), "00", "newtxns", false, []string{"a", "b"}, []string{"c"}, []string{"01"})
+			},
+			staticNtfn: func() interface{} {
+				return NewTemplateDeltaNtfn("00", "newtxns", false, []string{"a", "b"}, []string{"c"}, []string{"01"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"templatedelta","params":["00","newtxns",false,["a","b"],["c"],["01"]],"id":null}`,
+			unmarshalled: &TemplateDeltaNtfn{
+				Header:    "00",
+				Reason:    "newtxns",
+				Full:      false,
+				TxHashes:  []string{"a", "b"},
+				STxHashes: []string{"c"},
+				NewTxns:   []string{"01"},
+			},
+		},
+		{
+			name: "rawmempoolpage",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("rawmempoolpage"), 2, true, []string{"a"},
+					map[string]*GetRawMempoolVerboseResult(nil))
+			},
+			staticNtfn: func() interface{} {
+				return NewRawMempoolPageNtfn(2, true, []string{"a"}, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"rawmempoolpage","params":[2,true,["a"],null],"id":null}`,
+			unmarshalled: &RawMempoolPageNtfn{
+				Page:     2,
+				Final:    true,
+				TxHashes: []string{"a"},
+			},
+		},
+		{
+			name: "mempoolevent",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("mempoolevent"), 5, "removed", "123", "conflict", "456")
+			},
+			staticNtfn: func() interface{} {
+				return NewMempoolEventNtfn(5, "removed", "123", "conflict", "456")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"mempoolevent","params":[5,"removed","123","conflict","456"],"id":null}`,
+			unmarshalled: &MempoolEventNtfn{
+				Sequence:   5,
+				Event:      "removed",
+				TxID:       "123",
+				Reason:     "conflict",
+				ReplacedBy: "456",
+			},
+		},
+		{
+			name: "ticketevent",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("ticketevent"), "voted", "block", "123", "456", "789", 100)
+			},
+			staticNtfn: func() interface{} {
+				return NewTicketEventNtfn("voted", "block", "123", "456", "789", 100)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"ticketevent","params":["voted","block","123","456","789",100],"id":null}`,
+			unmarshalled: &TicketEventNtfn{
+				Event:       "voted",
+				Source:      "block",
+				Ticket:      "123",
+				TxID:        "456",
+				BlockHash:   "789",
+				BlockHeight: 100,
+			},
+		},
 		{
 			name: "newtickets",
 			newNtfn: func() (interface{}, error) {
@@ -85,6 +154,20 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Transaction: "001122",
 			},
 		},
+		{
+			name: "tlscertrenewed",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("tlscertrenewed"), "cert", 1700000000)
+			},
+			staticNtfn: func() interface{} {
+				return NewTLSCertRenewedNtfn("cert", 1700000000)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"tlscertrenewed","params":["cert",1700000000],"id":null}`,
+			unmarshalled: &TLSCertRenewedNtfn{
+				Cert:       "cert",
+				ValidUntil: 1700000000,
+			},
+		},
 		{
 			name: "txaccepted",
 			newNtfn: func() (interface{}, error) {
@@ -15,9 +15,24 @@ type RescanResult struct {
 	DiscoveredData []RescannedBlock `json:"discovereddata"`
 }
 
+// RescanFromHeightResult models the result object returned by the
+// rescanfromheight RPC.
+type RescanFromHeightResult struct {
+	DiscoveredData []RescannedBlock `json:"discovereddata"`
+	LastHeight     int64            `json:"lastheight"`
+	LastHash       string           `json:"lasthash"`
+}
+
 // RescannedBlock contains the hash and all discovered transactions of a single
 // rescanned block.
 type RescannedBlock struct {
 	Hash         string   `json:"hash"`
 	Transactions []string `json:"transactions"`
 }
+
+// StreamRawMempoolResult models the result object returned by the
+// streamrawmempool RPC.
+type StreamRawMempoolResult struct {
+	Pages        int `json:"pages"`
+	Transactions int `json:"transactions"`
+}
@@ -8,13 +8,19 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
+	"github.com/decred/dcrd/internal/grpcserver"
 	"github.com/decred/dcrd/internal/mempool"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
@@ -220,6 +226,15 @@ func (cm *rpcConnManager) NetTotals() (uint64, uint64) {
 	return cm.server.NetTotals()
 }
 
+// NetTotalsPerMsg returns the bytes received and sent across the network for
+// all peers keyed by message command.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) NetTotalsPerMsg() (map[string]uint64, map[string]uint64) {
+	return cm.server.NetTotalsPerMsg()
+}
+
 // ConnectedPeers returns an array consisting of all connected peers.
 //
 // This function is safe for concurrent access and is part of the
@@ -306,6 +321,60 @@ func (*rpcConnManager) Lookup(host string) ([]net.IP, error) {
 	return dcrdLookup(host)
 }
 
+// Ban bans the provided subnet until the given expiration time and disconnects
+// all connected peers within it.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) Ban(subnet *net.IPNet, expires time.Time) error {
+	cm.server.banManager.Ban(subnet, expires, banmgr.ReasonManual)
+
+	// Disconnect all peers within the subnet.  Only a single matching peer
+	// is disconnected per request, so keep going until none remain.
+	replyChan := make(chan error)
+	inSubnet := func(sp *serverPeer) bool {
+		host, _, err := net.SplitHostPort(sp.Addr())
+		if err != nil {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && subnet.Contains(ip)
+	}
+	for {
+		cm.server.query <- disconnectNodeMsg{cmp: inSubnet, reply: replyChan}
+		if err := <-replyChan; err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Unban removes the ban for the provided subnet.  Attempting to remove a subnet
+// that is not banned will return an error.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) Unban(subnet *net.IPNet) error {
+	return cm.server.banManager.Unban(subnet)
+}
+
+// BannedSubnets returns all unexpired bans.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) BannedSubnets() []banmgr.Entry {
+	return cm.server.banManager.Entries()
+}
+
+// ClearBanned removes all bans.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) ClearBanned() error {
+	cm.server.banManager.Clear()
+	return nil
+}
+
 // rpcSyncMgr provides an adaptor for use with the RPC server and implements the
 // rpcserver.SyncManager interface.
 type rpcSyncMgr struct {
@@ -347,6 +416,14 @@ func (b *rpcSyncMgr) SyncHeight() int64 {
 	return b.syncMgr.SyncHeight()
 }
 
+// SyncStatus returns the current status of the chain sync process.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.SyncManager interface implementation.
+func (b *rpcSyncMgr) SyncStatus() netsync.SyncStatus {
+	return b.syncMgr.SyncStatus()
+}
+
 // ProcessTransaction relays the provided transaction validation and insertion
 // into the memory pool.
 func (b *rpcSyncMgr) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
@@ -378,6 +455,56 @@ func (u *rpcUtxoEntry) ToUtxoEntry() *blockchain.UtxoEntry {
 	return u.UtxoEntry
 }
 
+// rpcChainStateBackuper provides chain state backups for use with the RPC
+// server and implements the rpcserver.ChainStateBackuper interface.
+type rpcChainStateBackuper struct {
+	chain *blockchain.BlockChain
+	db    database.DB
+}
+
+// Ensure rpcChainStateBackuper implements the rpcserver.ChainStateBackuper
+// interface.
+var _ rpcserver.ChainStateBackuper = (*rpcChainStateBackuper)(nil)
+
+// BackupChainState writes a consistent copy of the block and UTXO databases
+// to the provided directory, which must not already exist, in the same layout
+// as the network data directory.
+//
+// The UTXO database is copied first so that it never contains state that is
+// not also in the copy of the block database.  Any blocks that are only in
+// the copy of the block database are reprocessed when the chain is loaded
+// from the copy.
+//
+// This is part of the rpcserver.ChainStateBackuper interface implementation.
+func (b *rpcChainStateBackuper) BackupChainState(ctx context.Context, dir string, progress func(database.BackupProgress)) error {
+	if progress == nil {
+		progress = func(database.BackupProgress) {}
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("backup directory %q already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	var utxoBytes uint64
+	err := b.chain.BackupUtxoDB(ctx, dir, func(p database.BackupProgress) {
+		utxoBytes = p.BytesCopied
+		progress(p)
+	})
+	if err != nil {
+		return err
+	}
+
+	blockDbDir := filepath.Join(dir, filepath.Base(blockDbPath(b.db.Type())))
+	return b.db.Backup(ctx, blockDbDir, func(p database.BackupProgress) {
+		progress(database.BackupProgress{
+			BytesCopied: utxoBytes + p.BytesCopied,
+			TotalBytes:  utxoBytes + p.TotalBytes,
+		})
+	})
+}
+
 // rpcChain provides a chain for use with the RPC server and
 // implements the rpcserver.Chain interface.
 type rpcChain struct {
@@ -405,6 +532,22 @@ func (c *rpcChain) FetchUtxoEntry(outpoint wire.OutPoint) (rpcserver.UtxoEntry,
 	return &rpcUtxoEntry{UtxoEntry: utxo}, nil
 }
 
+// ForEachUtxo invokes the provided function with every unspent transaction
+// output in the utxo set ordered by outpoint.  Iteration stops as soon as the
+// function returns an error and that error is returned.
+//
+// This function is safe for concurrent access however the entries provided to
+// the function are NOT safe to retain or modify after it returns.
+func (c *rpcChain) ForEachUtxo(fn func(outpoint wire.OutPoint, entry rpcserver.UtxoEntry) error) error {
+	// Reuse a single wrapper for all entries since they are not allowed to be
+	// retained by the provided function.
+	var wrapper rpcUtxoEntry
+	return c.BlockChain.ForEachUtxo(func(outpoint wire.OutPoint, entry *blockchain.UtxoEntry) error {
+		wrapper.UtxoEntry = entry
+		return fn(outpoint, &wrapper)
+	})
+}
+
 // rpcClock provides a clock for use with the RPC server and
 // implements the rpcserver.Clock interface.
 type rpcClock struct{}
@@ -452,6 +595,14 @@ func (*rpcLogManager) ParseAndSetDebugLevels(debugLevel string) error {
 	return parseAndSetDebugLevels(debugLevel)
 }
 
+// DebugLevels returns the current logging level of each supported subsystem
+// keyed by the subsystem.
+//
+// This function is part of the rpcserver.LogManager interface implementation.
+func (*rpcLogManager) DebugLevels() map[string]string {
+	return subsystemLogLevels()
+}
+
 // rpcSanityChecker provides a block sanity checker for use with the RPC and
 // implements the rpcserver.SanityChecker interface.
 type rpcSanityChecker struct {
@@ -542,3 +693,33 @@ func (c *rpcCPUMiner) SetNumWorkers(numWorkers int32) {
 		c.miner.SetNumWorkers(numWorkers)
 	}
 }
+
+// grpcAuthorizer provides an authorizer for use with the gRPC server that
+// authorizes calls per the users of the RPC server and implements the
+// grpcserver.Authorizer interface.
+type grpcAuthorizer struct {
+	server *rpcserver.Server
+}
+
+// Ensure grpcAuthorizer implements the grpcserver.Authorizer interface.
+var _ grpcserver.Authorizer = (*grpcAuthorizer)(nil)
+
+// AuthorizeCall authenticates the client that made a call with the provided
+// HTTP basic access authorization value, ensures the user is authorized to
+// invoke the provided RPC method, and checks the call against the request
+// limits of the RPC server.
+//
+// This function is safe for concurrent access and is part of the
+// grpcserver.Authorizer interface implementation.
+func (a *grpcAuthorizer) AuthorizeCall(auth, method, remoteAddr string) (func(), error) {
+	release, err := a.server.AuthorizeCall(auth, method, remoteAddr)
+	switch {
+	case errors.Is(err, rpcserver.ErrUnauthenticated):
+		return nil, grpcserver.ErrUnauthenticated
+	case errors.Is(err, rpcserver.ErrUnauthorized):
+		return nil, grpcserver.ErrPermissionDenied
+	case errors.Is(err, rpcserver.ErrTooManyRequests):
+		return nil, fmt.Errorf("%w: %v", grpcserver.ErrTooManyRequests, err)
+	}
+	return release, err
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/certgen"
+)
+
+const (
+	// autogeneratedCertOrg is the organization of the TLS certificates that
+	// are generated by the server.  It is used to determine whether or not an
+	// existing certificate was autogenerated and therefore may be renewed.
+	autogeneratedCertOrg = "dcrd autogenerated cert"
+
+	// certRenewalCheckInterval is the interval at which the RPC server TLS
+	// certificate is checked to determine if it needs to be renewed.
+	certRenewalCheckInterval = time.Hour
+)
+
+// genCertPair generates a key/cert pair to the paths provided using the
+// provided curve, which may be ed25519TLSCurve, and validity period.  Any
+// existing files are replaced.
+func genCertPair(certFile, keyFile string, altDNSNames []string, curve string, lifetime time.Duration) error {
+	rpcsLog.Infof("Generating TLS certificates...")
+
+	validUntil := time.Now().Add(lifetime)
+	var cert, key []byte
+	var err error
+	if curve == ed25519TLSCurve {
+		cert, key, err = certgen.NewEd25519TLSCertPair(autogeneratedCertOrg,
+			validUntil, altDNSNames)
+	} else {
+		ellipticCurve, curveErr := tlsCurve(curve)
+		if curveErr != nil {
+			return curveErr
+		}
+		cert, key, err = certgen.NewTLSCertPair(ellipticCurve,
+			autogeneratedCertOrg, validUntil, altDNSNames)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Write temporary cert and key files and then move them into place so
+	// existing files are not left partially written on failure.
+	tmpCertFile, tmpKeyFile := certFile+".new", keyFile+".new"
+	if err := os.WriteFile(tmpCertFile, cert, 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmpKeyFile, key, 0600); err != nil {
+		os.Remove(tmpCertFile)
+		return err
+	}
+	if err := os.Rename(tmpKeyFile, keyFile); err != nil {
+		os.Remove(tmpCertFile)
+		os.Remove(tmpKeyFile)
+		return err
+	}
+	if err := os.Rename(tmpCertFile, certFile); err != nil {
+		os.Remove(tmpCertFile)
+		return err
+	}
+
+	rpcsLog.Infof("Done generating TLS certificates")
+	return nil
+}
+
+// rpcCertManager provides the TLS certificate used by the RPC and gRPC servers
+// and automatically regenerates it when it was autogenerated and is about to
+// expire.
+type rpcCertManager struct {
+	certFile    string
+	keyFile     string
+	altDNSNames []string
+	curve       string
+	lifetime    time.Duration
+	renewal     time.Duration
+
+	// notifyRenewed is invoked with the PEM-encoded certificate and its
+	// expiration time after the certificate is regenerated.  It may be nil.
+	// It must be set before the manager is run.
+	notifyRenewed func(cert []byte, validUntil time.Time)
+
+	// These fields house the current certificate along with whether or not it
+	// was autogenerated and its expiration time.  They are protected by the
+	// mutex.
+	mtx           sync.Mutex
+	cert          *tls.Certificate
+	autogenerated bool
+	validUntil    time.Time
+}
+
+// newRPCCertManager returns a new TLS certificate manager for the RPC server
+// based on the active configuration.  The TLS certificate and key are
+// generated if they do not already exist.
+func newRPCCertManager() (*rpcCertManager, error) {
+	m := &rpcCertManager{
+		certFile:    cfg.RPCCert,
+		keyFile:     cfg.RPCKey,
+		altDNSNames: cfg.AltDNSNames,
+		curve:       cfg.TLSCurve,
+		lifetime:    cfg.TLSCertLifetime,
+		renewal:     cfg.TLSCertRenewal,
+	}
+
+	// Generate the TLS cert and key file if both don't already exist.
+	keyFileExists := fileExists(m.keyFile)
+	certFileExists := fileExists(m.certFile)
+	if len(m.altDNSNames) != 0 && (keyFileExists || certFileExists) {
+		rpcsLog.Warn("Additional DNS names specified when TLS " +
+			"certificates already exist will NOT be included:")
+		rpcsLog.Warnf("- In order to create TLS certs that include the "+
+			"additional DNS names, delete %q and %q and restart the server",
+			m.keyFile, m.certFile)
+	}
+	if !keyFileExists && !certFileExists {
+		err := genCertPair(m.certFile, m.keyFile, m.altDNSNames, m.curve,
+			m.lifetime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// load loads the TLS certificate and key from the configured files and makes
+// it the current certificate.
+func (m *rpcCertManager) load() error {
+	keypair, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(keypair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	// Only certificates that were generated by the server are regenerated
+	// since the others are managed externally.
+	var autogenerated bool
+	for _, org := range leaf.Subject.Organization {
+		if org == autogeneratedCertOrg {
+			autogenerated = true
+			break
+		}
+	}
+
+	m.mtx.Lock()
+	m.cert = &keypair
+	m.autogenerated = autogenerated
+	m.validUntil = leaf.NotAfter
+	m.mtx.Unlock()
+	return nil
+}
+
+// GetCertificate returns the current TLS certificate.  It is intended to be
+// used as the GetCertificate function of a TLS config so that regenerated
+// certificates are used for new connections without restarting the servers.
+//
+// This function is safe for concurrent access.
+func (m *rpcCertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mtx.Lock()
+	cert := m.cert
+	m.mtx.Unlock()
+	return cert, nil
+}
+
+// needsRenewal returns whether or not the current certificate was
+// autogenerated and expires within the configured renewal period as of the
+// provided time.
+//
+// This function is safe for concurrent access.
+func (m *rpcCertManager) needsRenewal(now time.Time) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.renewal > 0 && m.autogenerated &&
+		m.validUntil.Sub(now) <= m.renewal
+}
+
+// renew regenerates the TLS certificate and key files, makes the new
+// certificate the current one, and notifies the registered callback.
+func (m *rpcCertManager) renew() error {
+	err := genCertPair(m.certFile, m.keyFile, m.altDNSNames, m.curve,
+		m.lifetime)
+	if err != nil {
+		return err
+	}
+	if err := m.load(); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	validUntil := m.validUntil
+	m.mtx.Unlock()
+	rpcsLog.Infof("Renewed TLS certificate %q which is valid until %v",
+		m.certFile, validUntil)
+
+	if m.notifyRenewed != nil {
+		cert, err := os.ReadFile(m.certFile)
+		if err != nil {
+			return fmt.Errorf("unable to read renewed certificate: %w", err)
+		}
+		m.notifyRenewed(cert, validUntil)
+	}
+	return nil
+}
+
+// Run periodically checks if the TLS certificate needs to be renewed and
+// renews it when needed until the provided context is cancelled.  It does
+// nothing when automatic renewal is disabled.
+//
+// This must be run as a goroutine.
+func (m *rpcCertManager) Run(ctx context.Context) {
+	if m.renewal == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(certRenewalCheckInterval)
+	defer ticker.Stop()
+	for {
+		if m.needsRenewal(time.Now()) {
+			if err := m.renew(); err != nil {
+				rpcsLog.Errorf("Unable to renew TLS certificate: %v", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MaxBatchSize is the maximum number of requests included in a single batched
+// JSON-RPC request.  It matches the maximum accepted by dcrd.  Send splits the
+// queued requests into multiple batched requests as needed.
+const MaxBatchSize = 100
+
+// ErrBatchResponseMissing is the error delivered to the future of a queued
+// request when the server response to the batch did not include a response
+// for it.
+var ErrBatchResponseMissing = errors.New("no response for the request was " +
+	"included in the batched response")
+
+// batchResponse is a partially-unmarshaled JSON-RPC response that is part of
+// a batched response.
+type batchResponse struct {
+	ID *float64 `json:"id"`
+	rawResponse
+}
+
+// NewBatch creates a new RPC client that queues the requests issued via its
+// methods instead of sending them immediately.  The queued requests are sent
+// to the server in batched JSON-RPC requests when Send is called, at which
+// point the results are delivered to the futures returned by the asynchronous
+// methods in the same way as they are for individual requests.
+//
+// Batch clients must be configured to run in HTTP POST mode.
+//
+// Note that the synchronous methods block until their result is available, so
+// only the asynchronous methods are useful with batch clients.
+func NewBatch(config *ConnConfig) (*Client, error) {
+	if !config.HTTPPostMode {
+		return nil, errors.New("batch clients must be configured to run " +
+			"in HTTP POST mode")
+	}
+	client, err := New(config, nil)
+	if err != nil {
+		return nil, err
+	}
+	client.batch = true
+	return client, nil
+}
+
+// queueBatchRequest queues the passed request to be sent with the next batch.
+//
+// This function is safe for concurrent access.
+func (c *Client) queueBatchRequest(jReq *jsonRequest) {
+	c.batchMtx.Lock()
+	c.batchList = append(c.batchList, jReq)
+	c.batchMtx.Unlock()
+}
+
+// Send sends all queued requests to the server in batched JSON-RPC requests of
+// at most MaxBatchSize requests each and delivers the results to the futures
+// of the queued requests.  Errors that only affect individual requests, such as
+// an invalid parameter, are delivered to the future of the associated request
+// and do not affect the other requests.
+//
+// An error is returned when the batch as a whole fails, in which case the same
+// error is also delivered to the futures of all requests that did not receive
+// a result.
+//
+// This function will return ErrNotBatchClient if the client was not created
+// with NewBatch.
+func (c *Client) Send(ctx context.Context) error {
+	if !c.batch {
+		return ErrNotBatchClient
+	}
+
+	c.batchMtx.Lock()
+	queued := c.batchList
+	c.batchList = nil
+	c.batchMtx.Unlock()
+
+	for len(queued) > 0 {
+		n := len(queued)
+		if n > MaxBatchSize {
+			n = MaxBatchSize
+		}
+		err := c.sendBatch(ctx, queued[:n])
+		if err != nil {
+			for _, jReq := range queued[n:] {
+				jReq.responseChan <- &response{err: err}
+			}
+			return err
+		}
+		queued = queued[n:]
+	}
+	return nil
+}
+
+// sendBatch sends the passed requests to the server in a single batched
+// JSON-RPC request and delivers the responses to the associated futures.  When
+// the batch as a whole fails, the returned error is also delivered to the
+// futures of all of the passed requests.
+func (c *Client) sendBatch(ctx context.Context, reqs []*jsonRequest) error {
+	select {
+	case <-c.shutdown:
+		failBatch(reqs, ErrClientShutdown)
+		return ErrClientShutdown
+	default:
+	}
+
+	// Combine the marshalled requests into a batched request.
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, jReq := range reqs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(jReq.marshalledJSON)
+	}
+	buf.WriteByte(']')
+
+	httpReq, err := c.newPostRequest(ctx, buf.Bytes())
+	if err != nil {
+		failBatch(reqs, err)
+		return err
+	}
+	log.Tracef("Sending batch of %d commands", len(reqs))
+	httpResponse, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		failBatch(reqs, err)
+		return err
+	}
+
+	// Read the raw bytes and close the response.
+	respBytes, err := io.ReadAll(httpResponse.Body)
+	httpResponse.Body.Close()
+	if err != nil {
+		err = fmt.Errorf("error reading json reply: %v", err)
+		failBatch(reqs, err)
+		return err
+	}
+
+	// Try to unmarshal the response as a batched JSON-RPC response.  The
+	// server replies with a single response when it rejects the batch as a
+	// whole, so attempt to return the error it contains in that case.
+	var resps []batchResponse
+	if err := json.Unmarshal(respBytes, &resps); err != nil {
+		var resp rawResponse
+		if json.Unmarshal(respBytes, &resp) == nil && resp.Error != nil {
+			err = resp.Error
+		} else {
+			err = fmt.Errorf("status code: %d, response: %q",
+				httpResponse.StatusCode, string(respBytes))
+		}
+		failBatch(reqs, err)
+		return err
+	}
+
+	// Deliver the responses to the associated requests by ID.
+	pending := make(map[uint64]*jsonRequest, len(reqs))
+	for _, jReq := range reqs {
+		pending[jReq.id] = jReq
+	}
+	for i := range resps {
+		resp := &resps[i]
+		if resp.ID == nil || *resp.ID < 0 || *resp.ID != math.Trunc(*resp.ID) {
+			log.Warn("Malformed batch response: invalid identifier")
+			continue
+		}
+		id := uint64(*resp.ID)
+		jReq, ok := pending[id]
+		if !ok {
+			log.Warnf("Received unexpected batch reply: %s (id %d)",
+				resp.Result, id)
+			continue
+		}
+		delete(pending, id)
+		result, err := resp.result()
+		jReq.responseChan <- &response{result: result, err: err}
+	}
+	for _, jReq := range reqs {
+		if _, ok := pending[jReq.id]; ok {
+			jReq.responseChan <- &response{err: ErrBatchResponseMissing}
+		}
+	}
+	return nil
+}
+
+// failBatch delivers the passed error to the futures of all passed requests.
+func failBatch(reqs []*jsonRequest, err error) {
+	for _, jReq := range reqs {
+		jReq.responseChan <- &response{err: err}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+)
+
+// TestBatch ensures batch clients queue requests until Send is called, split
+// them into batches of the maximum allowed size, and deliver the responses to
+// the futures of the associated requests regardless of their order.
+func TestBatch(t *testing.T) {
+	var mtx sync.Mutex
+	var batchSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []dcrjson.Request
+		if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		batchSizes = append(batchSizes, len(reqs))
+		mtx.Unlock()
+
+		// Reply in reverse order, fail requests with an id that is a
+		// multiple of 10, and omit the reply to the request with id 42.
+		replies := make([]string, 0, len(reqs))
+		for i := len(reqs) - 1; i >= 0; i-- {
+			id := reqs[i].ID.(float64)
+			switch {
+			case id == 42:
+				continue
+			case int(id)%10 == 0:
+				replies = append(replies, fmt.Sprintf(`{"result":null,`+
+					`"error":{"code":-1,"message":"fail"},"id":%v}`, id))
+			default:
+				replies = append(replies, fmt.Sprintf(`{"result":%v,`+
+					`"error":null,"id":%v}`, id*2, id))
+			}
+		}
+		fmt.Fprintf(w, "[%s]\n", strings.Join(replies, ","))
+	}))
+	defer server.Close()
+
+	cfg := &ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}
+	c, err := NewBatch(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating batch client: %v", err)
+	}
+	defer c.Shutdown()
+
+	ctx := context.Background()
+	const numRequests = MaxBatchSize + 20
+	futures := make([]*FutureGetBlockCountResult, numRequests)
+	for i := range futures {
+		futures[i] = c.GetBlockCountAsync(ctx)
+	}
+	mtx.Lock()
+	if len(batchSizes) != 0 {
+		t.Fatal("requests were sent before calling Send")
+	}
+	mtx.Unlock()
+	if err := c.Send(ctx); err != nil {
+		t.Fatalf("unexpected error sending batch: %v", err)
+	}
+	if len(batchSizes) != 2 || batchSizes[0] != MaxBatchSize ||
+		batchSizes[1] != numRequests-MaxBatchSize {
+
+		t.Fatalf("unexpected batch sizes: %v", batchSizes)
+	}
+
+	// The request IDs start at one.
+	for i, f := range futures {
+		id := int64(i + 1)
+		count, err := f.Receive()
+		switch {
+		case id == 42:
+			if !errors.Is(err, ErrBatchResponseMissing) {
+				t.Errorf("id %d: unexpected error -- got %v, want %v",
+					id, err, ErrBatchResponseMissing)
+			}
+		case id%10 == 0:
+			var rpcErr *dcrjson.RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Message != "fail" {
+				t.Errorf("id %d: unexpected error: %v", id, err)
+			}
+		default:
+			if err != nil || count != id*2 {
+				t.Errorf("id %d: unexpected result -- got %d (err %v), "+
+					"want %d", id, count, err, id*2)
+			}
+		}
+	}
+
+	// Ensure sending with nothing queued does nothing and that regular
+	// clients reject Send.
+	if err := c.Send(ctx); err != nil {
+		t.Fatalf("unexpected error sending empty batch: %v", err)
+	}
+	if len(batchSizes) != 2 {
+		t.Fatalf("unexpected request for empty batch: %v", batchSizes)
+	}
+	regular, err := New(cfg, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	defer regular.Shutdown()
+	if err := regular.Send(ctx); !errors.Is(err, ErrNotBatchClient) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrNotBatchClient)
+	}
+}
+
+// TestBatchRejected ensures the error is delivered to all queued requests when
+// the server rejects a batch as a whole.
+func TestBatchRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"jsonrpc":"2.0","result":null,"error":`+
+			`{"code":-32600,"message":"batch too large"},"id":null}`)
+	}))
+	defer server.Close()
+
+	c, err := NewBatch(&ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating batch client: %v", err)
+	}
+	defer c.Shutdown()
+
+	ctx := context.Background()
+	f1, f2 := c.GetBlockCountAsync(ctx), c.GetBestBlockHashAsync(ctx)
+	var rpcErr *dcrjson.RPCError
+	if err := c.Send(ctx); !errors.As(err, &rpcErr) ||
+		rpcErr.Code != dcrjson.ErrRPCInvalidRequest.Code {
+
+		t.Fatalf("unexpected send error: %v", err)
+	}
+	if _, err := f1.Receive(); !errors.As(err, &rpcErr) {
+		t.Fatalf("unexpected error for first request: %v", err)
+	}
+	if _, err := f2.Receive(); !errors.As(err, &rpcErr) {
+		t.Fatalf("unexpected error for second request: %v", err)
+	}
+
+	if _, err := NewBatch(&ConnConfig{Host: "localhost:9109"}); err == nil {
+		t.Fatal("batch client without HTTP POST mode did not error")
+	}
+}
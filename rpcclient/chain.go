@@ -8,8 +8,11 @@ package rpcclient
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/dcrjson/v4"
@@ -103,7 +106,7 @@ func (c *Client) GetBlockAsync(ctx context.Context, blockHash *chainhash.Hash) *
 		hash = blockHash.String()
 	}
 
-	cmd := chainjson.NewGetBlockCmd(hash, dcrjson.Bool(false), nil)
+	cmd := chainjson.NewGetBlockCmd(hash, dcrjson.Bool(false), nil, nil)
 	return (*FutureGetBlockResult)(c.sendCmd(ctx, cmd))
 }
 
@@ -147,7 +150,7 @@ func (c *Client) GetBlockVerboseAsync(ctx context.Context, blockHash *chainhash.
 		hash = blockHash.String()
 	}
 
-	cmd := chainjson.NewGetBlockCmd(hash, dcrjson.Bool(true), &verboseTx)
+	cmd := chainjson.NewGetBlockCmd(hash, dcrjson.Bool(true), &verboseTx, nil)
 	return (*FutureGetBlockVerboseResult)(c.sendCmd(ctx, cmd))
 }
 
@@ -307,6 +310,43 @@ func (c *Client) GetInfo(ctx context.Context) (*chainjson.InfoChainResult, error
 	return c.GetInfoAsync(ctx).Receive()
 }
 
+// FutureGetBlockByTimeResult is a future promise to deliver the result of a
+// GetBlockByTimeAsync RPC invocation (or an applicable error).
+type FutureGetBlockByTimeResult cmdRes
+
+// Receive waits for the response promised by the future and returns the first
+// block in the main chain with a median time at or after the requested time.
+func (r *FutureGetBlockByTimeResult) Receive() (*chainjson.GetBlockByTimeResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblockbytime result object.
+	var block chainjson.GetBlockByTimeResult
+	err = json.Unmarshal(res, &block)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// GetBlockByTimeAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBlockByTime for the blocking version and more details.
+func (c *Client) GetBlockByTimeAsync(ctx context.Context, t time.Time) *FutureGetBlockByTimeResult {
+	cmd := chainjson.NewGetBlockByTimeCmd(t.Unix())
+	return (*FutureGetBlockByTimeResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetBlockByTime returns the hash, height, and median time of the first block
+// in the main chain with a median time at or after the provided time.
+func (c *Client) GetBlockByTime(ctx context.Context, t time.Time) (*chainjson.GetBlockByTimeResult, error) {
+	return c.GetBlockByTimeAsync(ctx, t).Receive()
+}
+
 // FutureGetBlockHashResult is a future promise to deliver the result of a
 // GetBlockHashAsync RPC invocation (or an applicable error).
 type FutureGetBlockHashResult cmdRes
@@ -433,6 +473,44 @@ func (c *Client) GetBlockHeaderVerbose(ctx context.Context, hash *chainhash.Hash
 	return c.GetBlockHeaderVerboseAsync(ctx, hash).Receive()
 }
 
+// FutureGetBlocksByTimeResult is a future promise to deliver the result of a
+// GetBlocksByTimeAsync RPC invocation (or an applicable error).
+type FutureGetBlocksByTimeResult cmdRes
+
+// Receive waits for the response promised by the future and returns the blocks
+// in the main chain with a median time in the requested time range.
+func (r *FutureGetBlocksByTimeResult) Receive() ([]chainjson.GetBlockByTimeResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getblocksbytime result objects.
+	var blocks []chainjson.GetBlockByTimeResult
+	err = json.Unmarshal(res, &blocks)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// GetBlocksByTimeAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlocksByTime for the blocking version and more details.
+func (c *Client) GetBlocksByTimeAsync(ctx context.Context, start, end time.Time, count int) *FutureGetBlocksByTimeResult {
+	cmd := chainjson.NewGetBlocksByTimeCmd(start.Unix(), end.Unix(), &count)
+	return (*FutureGetBlocksByTimeResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetBlocksByTime returns the hash, height, and median time of at most count
+// blocks in the main chain with a median time in the half open range
+// [start, end) in chain order.
+func (c *Client) GetBlocksByTime(ctx context.Context, start, end time.Time, count int) ([]chainjson.GetBlockByTimeResult, error) {
+	return c.GetBlocksByTimeAsync(ctx, start, end, count).Receive()
+}
+
 // FutureGetBlockSubsidyResult is a future promise to deliver the result of a
 // GetBlockSubsidyAsync RPC invocation (or an applicable error).
 type FutureGetBlockSubsidyResult cmdRes
@@ -602,6 +680,144 @@ func (c *Client) GetRawMempoolVerbose(ctx context.Context, txType chainjson.GetR
 	return c.GetRawMempoolVerboseAsync(ctx, txType).Receive()
 }
 
+// FutureGetRawMempoolPageResult is a future promise to deliver the result of a
+// GetRawMempoolPageAsync RPC invocation (or an applicable error).
+type FutureGetRawMempoolPageResult cmdRes
+
+// Receive waits for the response promised by the future and returns a page of
+// the transaction hashes in the memory pool along with the cursor for the next
+// page.
+func (r *FutureGetRawMempoolPageResult) Receive() (*chainjson.GetRawMempoolPageResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var page chainjson.GetRawMempoolPageResult
+	err = json.Unmarshal(res, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// GetRawMempoolPageAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetRawMempoolPage for the blocking version and more details.
+func (c *Client) GetRawMempoolPageAsync(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, cursor string) *FutureGetRawMempoolPageResult {
+	cmd := chainjson.NewGetRawMempoolCmd(dcrjson.Bool(false),
+		dcrjson.String(string(txType)))
+	cmd.Count = &count
+	if cursor != "" {
+		cmd.Cursor = &cursor
+	}
+	return (*FutureGetRawMempoolPageResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetRawMempoolPage returns a page of at most count hashes of the transactions
+// in the memory pool for the given txType ordered by hash.  An empty cursor
+// requests the first page, while subsequent pages are requested by providing
+// the cursor returned with the previous page.  The returned cursor is empty
+// once there are no more pages.
+func (c *Client) GetRawMempoolPage(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, cursor string) (*chainjson.GetRawMempoolPageResult, error) {
+	return c.GetRawMempoolPageAsync(ctx, txType, count, cursor).Receive()
+}
+
+// FutureGetMempoolEntryResult is a future promise to deliver the result of a
+// GetMempoolEntryAsync RPC invocation (or an applicable error).
+type FutureGetMempoolEntryResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// details about the transaction in the memory pool.
+func (r *FutureGetMempoolEntryResult) Receive() (*chainjson.GetMempoolEntryResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry chainjson.GetMempoolEntryResult
+	err = json.Unmarshal(res, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetMempoolEntryAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetMempoolEntry for the blocking version and more details.
+func (c *Client) GetMempoolEntryAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolEntryResult {
+	cmd := chainjson.NewGetMempoolEntryCmd(txHash.String())
+	return (*FutureGetMempoolEntryResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolEntry returns details about the transaction with the provided hash
+// in the memory pool, including the aggregate size and fees of its unconfirmed
+// ancestors and descendants.
+func (c *Client) GetMempoolEntry(ctx context.Context, txHash *chainhash.Hash) (*chainjson.GetMempoolEntryResult, error) {
+	return c.GetMempoolEntryAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolAncestorsResult is a future promise to deliver the result of
+// a GetMempoolAncestorsAsync RPC invocation (or an applicable error).
+type FutureGetMempoolAncestorsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hashes
+// of the ancestors of the transaction in the memory pool.
+func (r *FutureGetMempoolAncestorsResult) Receive() ([]*chainhash.Hash, error) {
+	return (*FutureGetRawMempoolResult)(r).Receive()
+}
+
+// GetMempoolAncestorsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetMempoolAncestors for the blocking version and more details.
+func (c *Client) GetMempoolAncestorsAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolAncestorsResult {
+	cmd := chainjson.NewGetMempoolAncestorsCmd(txHash.String(),
+		dcrjson.Bool(false))
+	return (*FutureGetMempoolAncestorsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolAncestors returns the hashes of all transactions in the memory pool
+// that the transaction with the provided hash directly or indirectly spends
+// outputs of.
+func (c *Client) GetMempoolAncestors(ctx context.Context, txHash *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.GetMempoolAncestorsAsync(ctx, txHash).Receive()
+}
+
+// FutureGetMempoolDescendantsResult is a future promise to deliver the result
+// of a GetMempoolDescendantsAsync RPC invocation (or an applicable error).
+type FutureGetMempoolDescendantsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the hashes
+// of the descendants of the transaction in the memory pool.
+func (r *FutureGetMempoolDescendantsResult) Receive() ([]*chainhash.Hash, error) {
+	return (*FutureGetRawMempoolResult)(r).Receive()
+}
+
+// GetMempoolDescendantsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetMempoolDescendants for the blocking version and more details.
+func (c *Client) GetMempoolDescendantsAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetMempoolDescendantsResult {
+	cmd := chainjson.NewGetMempoolDescendantsCmd(txHash.String(),
+		dcrjson.Bool(false))
+	return (*FutureGetMempoolDescendantsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetMempoolDescendants returns the hashes of all transactions in the memory
+// pool that directly or indirectly spend outputs of the transaction with the
+// provided hash.
+func (c *Client) GetMempoolDescendants(ctx context.Context, txHash *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.GetMempoolDescendantsAsync(ctx, txHash).Receive()
+}
+
 // FutureValidateAddressResult is a future promise to deliver the result of a
 // ValidateAddressAsync RPC invocation (or an applicable error).
 type FutureValidateAddressResult cmdRes
@@ -681,6 +897,47 @@ func (c *Client) VerifyMessage(ctx context.Context, address stdaddr.Address, sig
 	return c.VerifyMessageAsync(ctx, address, signature, message).Receive()
 }
 
+// FutureVerifyOwnershipProofResult is a future promise to deliver the result
+// of a VerifyOwnershipProofAsync RPC invocation (or an applicable error).
+type FutureVerifyOwnershipProofResult cmdRes
+
+// Receive waits for the response promised by the future and returns whether or
+// not the proof of ownership was successfully verified.
+func (r *FutureVerifyOwnershipProofResult) Receive() (bool, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return false, err
+	}
+
+	// Unmarshal result as a boolean.
+	var verified bool
+	err = json.Unmarshal(res, &verified)
+	if err != nil {
+		return false, err
+	}
+
+	return verified, nil
+}
+
+// VerifyOwnershipProofAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See VerifyOwnershipProof for the blocking version and more details.
+func (c *Client) VerifyOwnershipProofAsync(ctx context.Context, address stdaddr.Address, message string, proof []byte) *FutureVerifyOwnershipProofResult {
+	addr := address.String()
+	proofStr := base64.StdEncoding.EncodeToString(proof)
+	cmd := chainjson.NewVerifyOwnershipProofCmd(addr, message, proofStr)
+	return (*FutureVerifyOwnershipProofResult)(c.sendCmd(ctx, cmd))
+}
+
+// VerifyOwnershipProof verifies a proof of ownership of an address for a
+// message such as those produced by the Sign function of the
+// txscript/v4/ownership package.
+func (c *Client) VerifyOwnershipProof(ctx context.Context, address stdaddr.Address, message string, proof []byte) (bool, error) {
+	return c.VerifyOwnershipProofAsync(ctx, address, message, proof).Receive()
+}
+
 // FutureVerifyChainResult is a future promise to deliver the result of a
 // VerifyChainAsync, VerifyChainLevelAsyncRPC, or VerifyChainBlocksAsync
 // invocation (or an applicable error).
@@ -855,6 +1112,51 @@ func (c *Client) GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uin
 	return c.GetTxOutAsync(ctx, txHash, index, tree, mempool).Receive()
 }
 
+// FutureGetSpentInfoResult is a future promise to deliver the result of a
+// GetSpentInfoAsync RPC invocation (or an applicable error).
+type FutureGetSpentInfoResult cmdRes
+
+// Receive waits for the response promised by the future and returns details
+// about the transaction input that spends the requested output.
+func (r *FutureGetSpentInfoResult) Receive() (*chainjson.GetSpentInfoResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getspentinfo result object.
+	var spentInfo chainjson.GetSpentInfoResult
+	err = json.Unmarshal(res, &spentInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spentInfo, nil
+}
+
+// GetSpentInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetSpentInfo for the blocking version and more details.
+func (c *Client) GetSpentInfoAsync(ctx context.Context, txHash *chainhash.Hash, index uint32) *FutureGetSpentInfoResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := chainjson.NewGetSpentInfoCmd(hash, index)
+	return (*FutureGetSpentInfoResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSpentInfo returns details about the main chain transaction input that
+// spends the provided transaction output.
+//
+// NOTE: This requires the server to have the spent output index enabled.
+func (c *Client) GetSpentInfo(ctx context.Context, txHash *chainhash.Hash, index uint32) (*chainjson.GetSpentInfoResult, error) {
+	return c.GetSpentInfoAsync(ctx, txHash, index).Receive()
+}
+
 // FutureRescanResult is a future promise to deliver the result of a
 // RescanAsynnc RPC invocation (or an applicable error).
 type FutureRescanResult cmdRes
@@ -898,6 +1200,48 @@ func (c *Client) Rescan(ctx context.Context, blockHashes []chainhash.Hash) (*cha
 	return c.RescanAsync(ctx, blockHashes).Receive()
 }
 
+// FutureRescanFromHeightResult is a future promise to deliver the result of a
+// RescanFromHeightAsync RPC invocation (or an applicable error).
+type FutureRescanFromHeightResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// discovered rescan data along with the last rescanned block.
+func (r *FutureRescanFromHeightResult) Receive() (*chainjson.RescanFromHeightResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var rescanResult *chainjson.RescanFromHeightResult
+	err = json.Unmarshal(res, &rescanResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return rescanResult, nil
+}
+
+// RescanFromHeightAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See RescanFromHeight for the blocking version and more details.
+func (c *Client) RescanFromHeightAsync(ctx context.Context, beginHeight int64, endHeight *int64) *FutureRescanFromHeightResult {
+	cmd := chainjson.NewRescanFromHeightCmd(beginHeight, endHeight)
+	return (*FutureRescanFromHeightResult)(c.sendCmd(ctx, cmd))
+}
+
+// RescanFromHeight rescans the main chain blocks starting at beginHeight using
+// the client's loaded transaction filter.  The blocks through endHeight, or the
+// best block when it is nil, are rescanned.  The server limits the number of
+// blocks rescanned per request, so callers should continue rescanning from the
+// height after the returned last height until reaching the desired height.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) RescanFromHeight(ctx context.Context, beginHeight int64, endHeight *int64) (*chainjson.RescanFromHeightResult, error) {
+	return c.RescanFromHeightAsync(ctx, beginHeight, endHeight).Receive()
+}
+
 // CFilterV2Result is the result of calling the GetCFilterV2 and
 // GetCFilterV2Async methods.
 type CFilterV2Result struct {
@@ -973,6 +1317,167 @@ func (c *Client) GetCFilterV2(ctx context.Context, blockHash *chainhash.Hash) (*
 	return c.GetCFilterV2Async(ctx, blockHash).Receive()
 }
 
+// CFiltersV2Entry is an entry of the result of calling the GetCFiltersV2 and
+// GetCFiltersV2Async methods and is also provided by StreamCFiltersV2.  It
+// houses the header of a main chain block along with its version 2 block filter
+// and the proof that links the hash of the filter to the header commitment in
+// the header.  The filter is nil when the filter data was not requested.
+type CFiltersV2Entry struct {
+	BlockHash   chainhash.Hash
+	Height      int64
+	Header      wire.BlockHeader
+	FilterHash  chainhash.Hash
+	Filter      *gcs.FilterV2
+	ProofIndex  uint32
+	ProofHashes []chainhash.Hash
+}
+
+// FutureGetCFiltersV2Result is a future promise to deliver the result of a
+// GetCFiltersV2Async RPC invocation (or an applicable error).
+type FutureGetCFiltersV2Result cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// headers, filters, and proofs of the requested range of main chain blocks.
+func (r *FutureGetCFiltersV2Result) Receive() ([]CFiltersV2Entry, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var filterResults []chainjson.GetCFiltersV2Result
+	err = json.Unmarshal(res, &filterResults)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]CFiltersV2Entry, len(filterResults))
+	for i := range filterResults {
+		filterResult := &filterResults[i]
+		entry := &entries[i]
+		entry.Height = filterResult.Height
+		entry.ProofIndex = filterResult.ProofIndex
+
+		err := chainhash.Decode(&entry.BlockHash, filterResult.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		err = chainhash.Decode(&entry.FilterHash, filterResult.FilterHash)
+		if err != nil {
+			return nil, err
+		}
+
+		headerBytes, err := hex.DecodeString(filterResult.Header)
+		if err != nil {
+			return nil, err
+		}
+		err = entry.Header.Deserialize(bytes.NewReader(headerBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		if filterResult.Data != "" {
+			filterBytes, err := hex.DecodeString(filterResult.Data)
+			if err != nil {
+				return nil, err
+			}
+			entry.Filter, err = gcs.FromBytesV2(blockcf2.B, blockcf2.M,
+				filterBytes)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		entry.ProofHashes = make([]chainhash.Hash, len(filterResult.ProofHashes))
+		for j, proofHashStr := range filterResult.ProofHashes {
+			err := chainhash.Decode(&entry.ProofHashes[j], proofHashStr)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// GetCFiltersV2Async returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetCFiltersV2 for the blocking version and more details.
+func (c *Client) GetCFiltersV2Async(ctx context.Context, startHeight int64, count int, includeData bool) *FutureGetCFiltersV2Result {
+	cmd := chainjson.NewGetCFiltersV2Cmd(startHeight, &count, &includeData)
+	return (*FutureGetCFiltersV2Result)(c.sendCmd(ctx, cmd))
+}
+
+// GetCFiltersV2 returns the headers of up to the provided number of main chain
+// blocks starting at the provided height along with their version 2 block
+// filters, when requested, and the proofs that can be used to prove the filters
+// are committed to by the block headers.
+func (c *Client) GetCFiltersV2(ctx context.Context, startHeight int64, count int, includeData bool) ([]CFiltersV2Entry, error) {
+	return c.GetCFiltersV2Async(ctx, startHeight, count, includeData).Receive()
+}
+
+// StreamCFiltersV2 invokes the provided function with the header, version 2
+// block filter, when requested, and header commitment proof of each main chain
+// block in the range [startHeight, endHeight] in order of increasing height.
+// The range is truncated to the current tip of the main chain.  Iteration stops
+// early when the function returns an error, which is then returned.
+//
+// The entries are requested in batches of the maximum size allowed by the
+// server and the next batch is requested while the function is invoked with the
+// entries of the current one.
+func (c *Client) StreamCFiltersV2(ctx context.Context, startHeight, endHeight int64, includeData bool, fn func(*CFiltersV2Entry) error) error {
+	// maxBatchSize is the maximum number of entries the server returns for a
+	// single request.
+	const maxBatchSize = 1000
+
+	request := func(height int64) (*FutureGetCFiltersV2Result, int) {
+		count := maxBatchSize
+		if remaining := endHeight - height + 1; remaining < maxBatchSize {
+			count = int(remaining)
+		}
+		return c.GetCFiltersV2Async(ctx, height, count, includeData), count
+	}
+
+	if startHeight < 0 {
+		startHeight = 0
+	}
+	if endHeight < startHeight {
+		return nil
+	}
+	future, count := request(startHeight)
+	for height := startHeight; ; {
+		entries, err := future.Receive()
+		if err != nil {
+			// The tip of the main chain was reached when a batch other than
+			// the first one starts after it.
+			var rpcErr *dcrjson.RPCError
+			if height > startHeight && errors.As(err, &rpcErr) &&
+				rpcErr.Code == dcrjson.ErrRPCOutOfRange {
+				return nil
+			}
+			return err
+		}
+		height += int64(len(entries))
+
+		// Request the next batch before processing the current one unless the
+		// end of the range or the tip of the main chain was reached.
+		done := height > endHeight || len(entries) < count
+		if !done {
+			future, count = request(height)
+		}
+
+		for i := range entries {
+			if err := fn(&entries[i]); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
 // FutureEstimateSmartFeeResult is a future promise to deliver the result of a
 // EstimateSmartFee RPC invocation (or an applicable error).
 type FutureEstimateSmartFeeResult cmdRes
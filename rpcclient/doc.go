@@ -56,6 +56,27 @@ the Receive method on the returned instance will either return the result
 immediately if it has already arrived, or block until it has.  This is useful
 since it provides the caller with greater control over concurrency.
 
+# Batched Requests
+
+Clients created with NewBatch queue the requests issued via the asynchronous
+API instead of sending them immediately.  Invoking Send delivers the queued
+requests to the server in batched JSON-RPC requests of at most MaxBatchSize
+requests each, which avoids the overhead of a separate HTTP POST request for
+every call.  The results are then available via the Receive method of the
+returned futures as usual and errors that only affect a single request are
+delivered solely to its future.  Batch clients must be configured to run in
+HTTP POST mode.
+
+# Connection Pools
+
+Pools created with NewPool multiplex concurrent calls across several clients
+that all connect to the same RPC server, which is useful for workloads that
+issue a large number of concurrent calls.  Calls are issued via the Do method,
+which routes each call to the connection with the fewest calls in flight and
+applies the configured per-call timeout.  Connections with too many
+consecutive failed calls are skipped until a cooldown elapses, at which point
+a single call is routed to them to probe whether they recovered.
+
 # Notifications
 
 The first important part of notifications is to realize that they will only
@@ -92,6 +113,12 @@ notifications are automatically re-registered and any in-flight commands are
 re-issued.  This means from the caller's perspective, the request simply takes
 longer to complete.
 
+Notifications that were stopped, such as via StopNotifyBlocks, are not
+re-registered.  When the ReplayMissedBlocks flag is set in the connection config,
+block notifications are re-registered since the most recent block the client was
+notified of so the server also delivers the notifications for any blocks that
+were connected or disconnected while the client was disconnected.
+
 The caller may invoke the Shutdown method on the client to force the client
 to cease reconnect attempts and return ErrClientShutdown for all outstanding
 commands.
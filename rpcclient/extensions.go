@@ -70,6 +70,47 @@ func (c *Client) DebugLevel(ctx context.Context, levelSpec string) (string, erro
 	return c.DebugLevelAsync(ctx, levelSpec).Receive()
 }
 
+// FutureGetDebugLevelsResult is a future promise to deliver the result of a
+// GetDebugLevelsAsync RPC invocation (or an applicable error).
+type FutureGetDebugLevelsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// current debug logging level of each subsystem keyed by the subsystem.
+func (r *FutureGetDebugLevelsResult) Receive() (map[string]string, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal the result as a map of subsystems to levels.
+	var levels map[string]string
+	err = json.Unmarshal(res, &levels)
+	if err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// GetDebugLevelsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See GetDebugLevels for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetDebugLevelsAsync(ctx context.Context) *FutureGetDebugLevelsResult {
+	cmd := chainjson.NewGetDebugLevelsCmd()
+	return (*FutureGetDebugLevelsResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetDebugLevels returns the current debug logging level of each subsystem
+// keyed by the subsystem.  The levels may be changed with DebugLevel.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetDebugLevels(ctx context.Context) (map[string]string, error) {
+	return c.GetDebugLevelsAsync(ctx).Receive()
+}
+
 // FutureEstimateStakeDiffResult is a future promise to deliver the result of a
 // EstimateStakeDiffAsync RPC invocation (or an applicable error).
 type FutureEstimateStakeDiffResult cmdRes
@@ -710,6 +751,50 @@ func (c *Client) LiveTickets(ctx context.Context) ([]*chainhash.Hash, error) {
 	return c.LiveTicketsAsync(ctx).Receive()
 }
 
+// FutureLiveTicketsPageResult is a future promise to deliver the result of a
+// LiveTicketsPageAsync RPC invocation (or an applicable error).
+type FutureLiveTicketsPageResult cmdRes
+
+// Receive waits for the response promised by the future and returns a page of
+// live tickets along with the cursor for the next page.
+func (r *FutureLiveTicketsPageResult) Receive() (*chainjson.LiveTicketsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var page chainjson.LiveTicketsResult
+	err = json.Unmarshal(res, &page)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// LiveTicketsPageAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See LiveTicketsPage for the blocking version and more details.
+func (c *Client) LiveTicketsPageAsync(ctx context.Context, count int, cursor string) *FutureLiveTicketsPageResult {
+	cmd := chainjson.NewLiveTicketsCmd()
+	cmd.Count = &count
+	if cursor != "" {
+		cmd.Cursor = &cursor
+	}
+	return (*FutureLiveTicketsPageResult)(c.sendCmd(ctx, cmd))
+}
+
+// LiveTicketsPage returns a page of at most count live tickets ordered by hash.
+// An empty cursor requests the first page, while subsequent pages are requested
+// by providing the cursor returned with the previous page.  The returned cursor
+// is empty once there are no more pages.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) LiveTicketsPage(ctx context.Context, count int, cursor string) (*chainjson.LiveTicketsResult, error) {
+	return c.LiveTicketsPageAsync(ctx, count, cursor).Receive()
+}
+
 // FutureSessionResult is a future promise to deliver the result of a
 // SessionAsync RPC invocation (or an applicable error).
 type FutureSessionResult cmdRes
@@ -77,6 +77,12 @@ var (
 	// a request was canceled by the caller by terminating the passed
 	// context.
 	ErrRequestCanceled = errors.New("request was canceled by the caller")
+
+	// ErrNotBatchClient is an error to describe the condition of calling a
+	// Client method intended for a batch client when the client was not
+	// created with NewBatch.
+	ErrNotBatchClient = errors.New("client is not configured for batched " +
+		"requests")
 )
 
 const (
@@ -151,6 +157,12 @@ type Client struct {
 	// It is protected by mtx.
 	wsConn *websocket.Conn
 
+	// renewedCerts houses the PEM-encoded certificates the server announced
+	// it automatically regenerated when the TrustRenewedCerts connection
+	// parameter is set.  They are trusted in addition to the configured
+	// certificates when reconnecting.  It is protected by mtx.
+	renewedCerts []byte
+
 	// cancel is a function used to cancel the client context which forces a
 	// shutdown.
 	cancel func()
@@ -168,6 +180,13 @@ type Client struct {
 	ntfnStateLock sync.Mutex
 	ntfnState     *notificationState
 
+	// batch indicates the client queues requests until they are sent in one
+	// or more batched requests via Send.  batchList houses the queued
+	// requests and is protected by batchMtx.
+	batch     bool
+	batchMtx  sync.Mutex
+	batchList []*jsonRequest
+
 	// Networking infrastructure.
 	sendChan        chan []byte
 	sendPostChan    chan *sendPostDetails
@@ -182,16 +201,16 @@ type Client struct {
 func (c *Client) String() string {
 	var u url.URL
 	switch {
-	case c.config.HTTPPostMode && c.config.DisableTLS:
+	case c.config.HTTPPostMode && !c.config.useTLS():
 		u.Scheme = "http"
 	case c.config.HTTPPostMode:
 		u.Scheme = "https"
-	case c.config.DisableTLS:
+	case !c.config.useTLS():
 		u.Scheme = "ws"
 	default:
 		u.Scheme = "wss"
 	}
-	u.Host = c.config.Host
+	u.Host = c.config.host()
 	u.Path = c.config.Endpoint
 	return u.String()
 }
@@ -266,6 +285,7 @@ func (c *Client) removeAllRequests() {
 // trackRegisteredNtfns examines the passed command to see if it is one of
 // the notification commands and updates the notification state that is used
 // to automatically re-establish registered notifications on reconnects.
+// Notifications that are stopped are no longer re-established.
 func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 	// Nothing to do if the caller is not interested in notifications.
 	if c.ntfnHandlers == nil {
@@ -297,6 +317,38 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 
 	case *chainjson.NotifyTSpendCmd:
 		c.ntfnState.notifyTSpend = true
+
+	case *chainjson.NotifyMempoolEventsCmd:
+		c.ntfnState.notifyMempoolEvents = true
+
+	case *chainjson.NotifyTicketEventsCmd:
+		c.ntfnState.notifyTicketEvents = true
+
+	case *chainjson.NotifyTemplateDeltasCmd:
+		c.ntfnState.notifyTemplateDeltas = true
+
+	case *chainjson.StopNotifyBlocksCmd:
+		c.ntfnState.notifyBlocks = false
+		c.ntfnState.lastBlock = nil
+
+	case *chainjson.StopNotifyNewTransactionsCmd:
+		c.ntfnState.notifyNewTx = false
+		c.ntfnState.notifyNewTxVerbose = false
+
+	case *chainjson.StopNotifyWorkCmd:
+		c.ntfnState.notifyWork = false
+
+	case *chainjson.StopNotifyTSpendCmd:
+		c.ntfnState.notifyTSpend = false
+
+	case *chainjson.StopNotifyMempoolEventsCmd:
+		c.ntfnState.notifyMempoolEvents = false
+
+	case *chainjson.StopNotifyTicketEventsCmd:
+		c.ntfnState.notifyTicketEvents = false
+
+	case *chainjson.StopNotifyTemplateDeltasCmd:
+		c.ntfnState.notifyTemplateDeltas = false
 	}
 }
 
@@ -551,11 +603,28 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 	stateCopy := c.ntfnState.Copy()
 	c.ntfnStateLock.Unlock()
 
-	// Reregister notifyblocks if needed.
+	// Reregister notifyblocks if needed.  The notifications for any blocks
+	// that were missed while disconnected are replayed when requested and
+	// the most recent block is known.  Fall back to only registering for new
+	// notifications when the server is unable to replay them, such as when
+	// too many blocks were missed.
 	if stateCopy.notifyBlocks {
-		log.Debugf("Reregistering [notifyblocks]")
-		if err := c.NotifyBlocks(ctx); err != nil {
-			return err
+		var replayed bool
+		if c.config.ReplayMissedBlocks && stateCopy.lastBlock != nil {
+			log.Debugf("Reregistering [notifyblocks] (since %v)",
+				stateCopy.lastBlock)
+			err := c.NotifyBlocksSince(ctx, stateCopy.lastBlock)
+			if err != nil {
+				log.Warnf("Unable to replay missed block notifications "+
+					"since %v: %v", stateCopy.lastBlock, err)
+			}
+			replayed = err == nil
+		}
+		if !replayed {
+			log.Debugf("Reregistering [notifyblocks]")
+			if err := c.NotifyBlocks(ctx); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -575,6 +644,30 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 		}
 	}
 
+	// Reregister notifymempoolevents if needed.
+	if stateCopy.notifyMempoolEvents {
+		log.Debugf("Reregistering [notifymempoolevents]")
+		if err := c.NotifyMempoolEvents(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Reregister notifyticketevents if needed.
+	if stateCopy.notifyTicketEvents {
+		log.Debugf("Reregistering [notifyticketevents]")
+		if err := c.NotifyTicketEvents(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Reregister notifytemplatedeltas if needed.
+	if stateCopy.notifyTemplateDeltas {
+		log.Debugf("Reregistering [notifytemplatedeltas]")
+		if err := c.NotifyTemplateDeltas(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Reregister notifywinningtickets if needed.
 	if stateCopy.notifyWinningTickets {
 		log.Debugf("Reregistering [notifywinningtickets]")
@@ -690,7 +783,7 @@ out:
 			default:
 			}
 
-			wsConn, err := dial(c.config)
+			wsConn, err := dial(c.config, c.trustedRenewedCerts())
 			if err != nil {
 				retryCount++
 				log.Infof("Failed to connect to %s: %v",
@@ -860,29 +953,39 @@ func receiveFuture(ctx context.Context, f chan *response) ([]byte, error) {
 	}
 }
 
-// sendPost sends the passed request to the server by issuing an HTTP POST
-// request using the provided response channel for the reply.  Typically a new
-// connection is opened and closed for each command when using this method,
-// however, the underlying HTTP client might coalesce multiple commands
-// depending on several factors including the remote server configuration.
-func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
+// newPostRequest returns a new HTTP POST request to the configured RPC server
+// with the passed marshalled JSON as the body.
+func (c *Client) newPostRequest(ctx context.Context, marshalledJSON []byte) (*http.Request, error) {
 	// Generate a request to the configured RPC server.
 	protocol := "http"
-	if !c.config.DisableTLS {
+	if c.config.useTLS() {
 		protocol = "https"
 	}
-	url := protocol + "://" + c.config.Host
-	bodyReader := bytes.NewReader(jReq.marshalledJSON)
+	url := protocol + "://" + c.config.host()
+	bodyReader := bytes.NewReader(marshalledJSON)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
 	if err != nil {
-		jReq.responseChan <- &response{result: nil, err: err}
-		return
+		return nil, err
 	}
 	httpReq.Close = true
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// Configure basic access authorization.
 	httpReq.SetBasicAuth(c.config.User, c.config.Pass)
+	return httpReq, nil
+}
+
+// sendPost sends the passed request to the server by issuing an HTTP POST
+// request using the provided response channel for the reply.  Typically a new
+// connection is opened and closed for each command when using this method,
+// however, the underlying HTTP client might coalesce multiple commands
+// depending on several factors including the remote server configuration.
+func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
+	httpReq, err := c.newPostRequest(ctx, jReq.marshalledJSON)
+	if err != nil {
+		jReq.responseChan <- &response{result: nil, err: err}
+		return
+	}
 
 	log.Tracef("Sending command [%s] with id %d", jReq.method, jReq.id)
 	c.sendPostRequest(httpReq, jReq)
@@ -892,6 +995,13 @@ func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
 // provided response channel for the reply.  It handles both websocket and HTTP
 // POST mode depending on the configuration of the client.
 func (c *Client) sendRequest(ctx context.Context, jReq *jsonRequest) {
+	// Queue the request when the client is running in batch mode.  It is
+	// sent along with the other queued requests when Send is called.
+	if c.batch {
+		c.queueBatchRequest(jReq)
+		return
+	}
+
 	// Choose which marshal and send function to use depending on whether
 	// the client running in HTTP POST mode or not.  When running in HTTP
 	// POST mode, the command is issued via an HTTP client.  Otherwise,
@@ -1110,9 +1220,17 @@ func (c *Client) WaitForShutdown() {
 // This
 type ConnConfig struct {
 	// Host is the IP address and port of the RPC server you want to connect
-	// to.
+	// to.  It is only used as the HTTP host when UnixSocket is set and
+	// defaults to localhost in that case when empty.
 	Host string
 
+	// UnixSocket is the path of a unix domain socket to connect to the RPC
+	// server through instead of Host.  Transport layer security is never used
+	// with unix domain sockets since access to them is controlled by
+	// filesystem permissions, so the DisableTLS, Certificates, and proxy
+	// parameters have no effect when it is set.
+	UnixSocket string
+
 	// Endpoint is the websocket endpoint on the RPC server.  This is
 	// typically "ws".
 	Endpoint string
@@ -1134,6 +1252,14 @@ type ConnConfig struct {
 	// is true.
 	Certificates []byte
 
+	// TrustRenewedCerts specifies that the certificates the server announces
+	// via tlscertrenewed notifications when it automatically regenerates its
+	// TLS certificate should be trusted in addition to Certificates when
+	// reconnecting.  The notifications are only received over connections
+	// that are already secured by a trusted certificate.  It has no effect
+	// if Certificates is empty or the DisableTLS parameter is true.
+	TrustRenewedCerts bool
+
 	// Proxy specifies to connect through a SOCKS 5 proxy server.  It may
 	// be an empty string if a proxy is not required.
 	Proxy string
@@ -1152,6 +1278,13 @@ type ConnConfig struct {
 	// try to reconnect to the server when it has been disconnected.
 	DisableAutoReconnect bool
 
+	// ReplayMissedBlocks specifies that the notifications for any blocks
+	// connected to the main chain while the client was disconnected should
+	// be replayed by the server when block notifications are automatically
+	// re-established on reconnect.  It has no effect unless block
+	// notifications were registered with NotifyBlocks or NotifyBlocksSince.
+	ReplayMissedBlocks bool
+
 	// DisableConnectOnNew specifies that a websocket client connection
 	// should not be tried when creating the client with New.  Instead, the
 	// client is created and returned unconnected, and Connect must be
@@ -1167,9 +1300,42 @@ type ConnConfig struct {
 	HTTPPostMode bool
 }
 
+// useTLS returns whether or not connections to the RPC server use transport
+// layer security according to the connection configuration.
+func (config *ConnConfig) useTLS() bool {
+	return !config.DisableTLS && config.UnixSocket == ""
+}
+
+// host returns the host to use in requests to the RPC server.
+func (config *ConnConfig) host() string {
+	if config.UnixSocket != "" && config.Host == "" {
+		return "localhost"
+	}
+	return config.Host
+}
+
+// dialUnix returns a function that connects to the unix domain socket of the
+// connection configuration regardless of the requested network and address.
+func (config *ConnConfig) dialUnix() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", config.UnixSocket)
+	}
+}
+
 // newHTTPClient returns a new http client that is configured according to the
 // proxy and TLS settings in the associated connection configuration.
 func newHTTPClient(config *ConnConfig) (*http.Client, error) {
+	// Connect through the unix domain socket when one is configured.
+	if config.UnixSocket != "" {
+		client := http.Client{
+			Transport: &http.Transport{
+				DialContext: config.dialUnix(),
+			},
+		}
+		return &client, nil
+	}
+
 	// Set proxy function if there is a proxy configured.
 	var proxyFunc func(*http.Request) (*url.URL, error)
 	if config.Proxy != "" {
@@ -1202,30 +1368,67 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 	return &client, nil
 }
 
+// trustedRenewedCerts returns the PEM-encoded certificates the server announced
+// it regenerated that are trusted for new connections.
+//
+// This function is safe for concurrent access.
+func (c *Client) trustedRenewedCerts() []byte {
+	c.mtx.Lock()
+	certs := c.renewedCerts
+	c.mtx.Unlock()
+	return certs
+}
+
+// trustRenewedCert adds the provided PEM-encoded certificate to the
+// certificates that are trusted for new connections.
+//
+// This function is safe for concurrent access.
+func (c *Client) trustRenewedCert(cert []byte) {
+	c.mtx.Lock()
+	certs := make([]byte, 0, len(c.renewedCerts)+len(cert))
+	certs = append(certs, c.renewedCerts...)
+	c.renewedCerts = append(certs, cert...)
+	c.mtx.Unlock()
+}
+
 // dial opens a websocket connection using the passed connection configuration
-// details.
-func dial(config *ConnConfig) (*websocket.Conn, error) {
+// details.  The provided renewed certificates, if any, are trusted in addition
+// to the configured certificates.
+func dial(config *ConnConfig, renewedCerts []byte) (*websocket.Conn, error) {
 	// Setup TLS if not disabled.
 	var tlsConfig *tls.Config
 	var scheme = "ws"
-	if !config.DisableTLS {
+	if config.useTLS() {
 		tlsConfig = &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		}
 		if len(config.Certificates) > 0 {
 			pool := x509.NewCertPool()
 			pool.AppendCertsFromPEM(config.Certificates)
+			if len(renewedCerts) > 0 {
+				pool.AppendCertsFromPEM(renewedCerts)
+			}
 			tlsConfig.RootCAs = pool
 		}
 		scheme = "wss"
 	}
 
 	// Create a websocket dialer that will be used to make the connection.
-	// It is modified by the proxy setting below as needed.
-	dialer := websocket.Dialer{TLSClientConfig: tlsConfig}
+	// It is modified by the proxy setting below as needed.  Per-message
+	// compression is requested since verbose replies and notifications are
+	// highly compressible.
+	dialer := websocket.Dialer{
+		TLSClientConfig:   tlsConfig,
+		EnableCompression: true,
+	}
 
-	// Setup the proxy if one is configured.
-	if config.Proxy != "" {
+	// Connect through the unix domain socket when one is configured or
+	// setup the proxy if one is configured.
+	switch {
+	case config.UnixSocket != "":
+		dialer.NetDialContext = config.dialUnix()
+
+	case config.Proxy != "":
 		proxy := &socks.Proxy{
 			Addr:     config.Proxy,
 			Username: config.ProxyUser,
@@ -1242,7 +1445,7 @@ func dial(config *ConnConfig) (*websocket.Conn, error) {
 	requestHeader.Add("Authorization", auth)
 
 	// Dial the connection.
-	url := fmt.Sprintf("%s://%s/%s", scheme, config.Host, config.Endpoint)
+	url := fmt.Sprintf("%s://%s/%s", scheme, config.host(), config.Endpoint)
 	wsConn, resp, err := dialer.Dial(url, requestHeader)
 	if resp != nil {
 		resp.Body.Close()
@@ -1321,7 +1524,7 @@ func New(config *ConnConfig, ntfnHandlers *NotificationHandlers) (*Client, error
 	} else {
 		if !config.DisableConnectOnNew {
 			var err error
-			wsConn, err = dial(config)
+			wsConn, err = dial(config, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -1393,7 +1596,7 @@ func (c *Client) Connect(ctx context.Context, retry bool) error {
 	// attempt, up to a maximum of one minute.
 	var backoff time.Duration
 	for {
-		wsConn, err := dial(c.config)
+		wsConn, err := dial(c.config, c.trustedRenewedCerts())
 		if err != nil {
 			if !retry {
 				return err
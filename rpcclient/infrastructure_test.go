@@ -10,16 +10,21 @@ func TestClientStringer(t *testing.T) {
 	type test struct {
 		url      string
 		host     string
+		unix     string
 		endpoint string
 		post     bool
 	}
 	tests := []test{
-		{"https://localhost:9109", "localhost:9109", "", true},
-		{"wss://localhost:9109/ws", "localhost:9109", "ws", false},
+		{"https://localhost:9109", "localhost:9109", "", "", true},
+		{"wss://localhost:9109/ws", "localhost:9109", "", "ws", false},
+		{"http://localhost", "", "/tmp/dcrd.sock", "", true},
+		{"ws://localhost/ws", "", "/tmp/dcrd.sock", "ws", false},
+		{"ws://dcrd/ws", "dcrd", "/tmp/dcrd.sock", "ws", false},
 	}
 	for _, test := range tests {
 		cfg := &ConnConfig{
 			Host:                test.host,
+			UnixSocket:          test.unix,
 			Endpoint:            test.endpoint,
 			HTTPPostMode:        test.post,
 			DisableTLS:          false,
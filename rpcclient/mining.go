@@ -314,7 +314,7 @@ func (r *FutureGetWork) Receive() (*chainjson.GetWorkResult, error) {
 //
 // See GetWork for the blocking version and more details.
 func (c *Client) GetWorkAsync(ctx context.Context) *FutureGetWork {
-	cmd := chainjson.NewGetWorkCmd(nil)
+	cmd := chainjson.NewGetWorkCmd(nil, nil)
 	return (*FutureGetWork)(c.sendCmd(ctx, cmd))
 }
 
@@ -325,6 +325,27 @@ func (c *Client) GetWork(ctx context.Context) (*chainjson.GetWorkResult, error)
 	return c.GetWorkAsync(ctx).Receive()
 }
 
+// GetWorkLongPollAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetWorkLongPoll for the blocking version and more details.
+func (c *Client) GetWorkLongPollAsync(ctx context.Context, longPollID string) *FutureGetWork {
+	data := ""
+	cmd := chainjson.NewGetWorkCmd(&data, &longPollID)
+	return (*FutureGetWork)(c.sendCmd(ctx, cmd))
+}
+
+// GetWorkLongPoll returns hash data to work on once the block template differs
+// from the one identified by the provided long poll identifier, which is
+// obtained from the result of a previous call to GetWork or GetWorkLongPoll.
+// It returns immediately when the template has already changed.
+//
+// See GetWorkSubmit to submit the found solution.
+func (c *Client) GetWorkLongPoll(ctx context.Context, longPollID string) (*chainjson.GetWorkResult, error) {
+	return c.GetWorkLongPollAsync(ctx, longPollID).Receive()
+}
+
 // FutureGetWorkSubmit is a future promise to deliver the result of a
 // GetWorkSubmitAsync RPC invocation (or an applicable error).
 type FutureGetWorkSubmit cmdRes
@@ -353,7 +374,7 @@ func (r *FutureGetWorkSubmit) Receive() (bool, error) {
 //
 // See GetWorkSubmit for the blocking version and more details.
 func (c *Client) GetWorkSubmitAsync(ctx context.Context, data string) *FutureGetWorkSubmit {
-	cmd := chainjson.NewGetWorkCmd(&data)
+	cmd := chainjson.NewGetWorkCmd(&data, nil)
 	return (*FutureGetWorkSubmit)(c.sendCmd(ctx, cmd))
 }
 
@@ -416,6 +437,91 @@ func (c *Client) SubmitBlock(ctx context.Context, block *dcrutil.Block, options
 	return c.SubmitBlockAsync(ctx, block, options).Receive()
 }
 
+// FutureSubmitBlockNoWaitResult is a future promise to deliver the result of a
+// SubmitBlockNoWaitAsync RPC invocation (or an applicable error).
+type FutureSubmitBlockNoWaitResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// submission ID, which is the hash of the submitted block.
+func (r *FutureSubmitBlockNoWaitResult) Receive() (*chainhash.Hash, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a string.
+	var id string
+	err = json.Unmarshal(res, &id)
+	if err != nil {
+		return nil, err
+	}
+	return chainhash.NewHashFromStr(id)
+}
+
+// SubmitBlockNoWaitAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See SubmitBlockNoWait for the blocking version and more details.
+func (c *Client) SubmitBlockNoWaitAsync(ctx context.Context, block *dcrutil.Block, options *chainjson.SubmitBlockOptions) *FutureSubmitBlockNoWaitResult {
+	blockHex := ""
+	if block != nil {
+		blockBytes, err := block.Bytes()
+		if err != nil {
+			return (*FutureSubmitBlockNoWaitResult)(newFutureError(ctx, err))
+		}
+
+		blockHex = hex.EncodeToString(blockBytes)
+	}
+
+	cmd := chainjson.NewSubmitBlockNoWaitCmd(blockHex, options)
+	return (*FutureSubmitBlockNoWaitResult)(c.sendCmd(ctx, cmd))
+}
+
+// SubmitBlockNoWait submits a new block into the Decred network without waiting
+// for the server to process it.  It returns a submission ID that may be passed
+// to GetSubmitBlockStatus to determine whether or not the block was accepted.
+func (c *Client) SubmitBlockNoWait(ctx context.Context, block *dcrutil.Block, options *chainjson.SubmitBlockOptions) (*chainhash.Hash, error) {
+	return c.SubmitBlockNoWaitAsync(ctx, block, options).Receive()
+}
+
+// FutureGetSubmitBlockStatusResult is a future promise to deliver the result of
+// a GetSubmitBlockStatusAsync RPC invocation (or an applicable error).
+type FutureGetSubmitBlockStatusResult cmdRes
+
+// Receive waits for the response promised by the future and returns the status
+// of the block submission.
+func (r *FutureGetSubmitBlockStatusResult) Receive() (*chainjson.GetSubmitBlockStatusResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getsubmitblockstatus result object.
+	var status chainjson.GetSubmitBlockStatusResult
+	err = json.Unmarshal(res, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetSubmitBlockStatusAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetSubmitBlockStatus for the blocking version and more details.
+func (c *Client) GetSubmitBlockStatusAsync(ctx context.Context, id *chainhash.Hash) *FutureGetSubmitBlockStatusResult {
+	cmd := chainjson.NewGetSubmitBlockStatusCmd(id.String())
+	return (*FutureGetSubmitBlockStatusResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSubmitBlockStatus returns the status of a block submitted via
+// SubmitBlockNoWait, including the reason it was rejected, if any.
+func (c *Client) GetSubmitBlockStatus(ctx context.Context, id *chainhash.Hash) (*chainjson.GetSubmitBlockStatusResult, error) {
+	return c.GetSubmitBlockStatusAsync(ctx, id).Receive()
+}
+
 // FutureRegenTemplateResult is a future promise to deliver the result of a
 // RegenTemplate RPC invocation (or an applicable error).
 type FutureRegenTemplateResult cmdRes
@@ -442,3 +548,47 @@ func (c *Client) RegenTemplateAsync(ctx context.Context) *FutureRegenTemplateRes
 func (c *Client) RegenTemplate(ctx context.Context) error {
 	return c.RegenTemplateAsync(ctx).Receive()
 }
+
+// FuturePrioritiseTransactionResult is a future promise to deliver the result
+// of a PrioritiseTransactionAsync RPC invocation (or an applicable error).
+type FuturePrioritiseTransactionResult cmdRes
+
+// Receive waits for the response promised by the future and returns the total
+// fee delta in atoms for the transaction.
+func (r *FuturePrioritiseTransactionResult) Receive() (int64, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return 0, err
+	}
+
+	// Unmarshal the result as an int64.
+	var feeDelta int64
+	err = json.Unmarshal(res, &feeDelta)
+	if err != nil {
+		return 0, err
+	}
+	return feeDelta, nil
+}
+
+// PrioritiseTransactionAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See PrioritiseTransaction for the blocking version and more details.
+func (c *Client) PrioritiseTransactionAsync(ctx context.Context, txHash *chainhash.Hash, feeDelta int64) *FuturePrioritiseTransactionResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := chainjson.NewPrioritiseTransactionCmd(hash, feeDelta)
+	return (*FuturePrioritiseTransactionResult)(c.sendCmd(ctx, cmd))
+}
+
+// PrioritiseTransaction adjusts the fee of the transaction with the provided
+// hash by the given delta in atoms when the node prioritizes it for inclusion
+// in the block templates it generates and returns the resulting total delta.
+// The fee the transaction actually pays is not modified.
+func (c *Client) PrioritiseTransaction(ctx context.Context, txHash *chainhash.Hash, feeDelta int64) (int64, error) {
+	return c.PrioritiseTransactionAsync(ctx, txHash, feeDelta).Receive()
+}
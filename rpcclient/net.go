@@ -322,3 +322,174 @@ func (c *Client) GetNetworkInfoAsync(ctx context.Context) *FutureGetNetworkInfoR
 func (c *Client) GetNetworkInfo(ctx context.Context) (*chainjson.GetNetworkInfoResult, error) {
 	return c.GetNetworkInfoAsync(ctx).Receive()
 }
+
+// FutureGetNodeStatusResult is a future promise to deliver the result of a
+// GetNodeStatusAsync RPC invocation (or an applicable error).
+type FutureGetNodeStatusResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// health and readiness of the node.
+func (r *FutureGetNodeStatusResult) Receive() (*chainjson.GetNodeStatusResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getnodestatus result object.
+	var nodeStatus chainjson.GetNodeStatusResult
+	err = json.Unmarshal(res, &nodeStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeStatus, nil
+}
+
+// GetNodeStatusAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetNodeStatus for the blocking version and more details.
+func (c *Client) GetNodeStatusAsync(ctx context.Context) *FutureGetNodeStatusResult {
+	cmd := chainjson.NewGetNodeStatusCmd()
+	return (*FutureGetNodeStatusResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetNodeStatus returns the health and readiness of the node along with the
+// details they are based on, such as whether or not it is synced, its number
+// of connected peers, and the sync progress of its optional indexes.
+func (c *Client) GetNodeStatus(ctx context.Context) (*chainjson.GetNodeStatusResult, error) {
+	return c.GetNodeStatusAsync(ctx).Receive()
+}
+
+// FutureGetSyncStatusResult is a future promise to deliver the result of a
+// GetSyncStatusAsync RPC invocation (or an applicable error).
+type FutureGetSyncStatusResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// progress of the chain sync process.
+func (r *FutureGetSyncStatusResult) Receive() (*chainjson.GetSyncStatusResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getsyncstatus result object.
+	var syncStatus chainjson.GetSyncStatusResult
+	err = json.Unmarshal(res, &syncStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncStatus, nil
+}
+
+// GetSyncStatusAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetSyncStatus for the blocking version and more details.
+func (c *Client) GetSyncStatusAsync(ctx context.Context) *FutureGetSyncStatusResult {
+	cmd := chainjson.NewGetSyncStatusCmd()
+	return (*FutureGetSyncStatusResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetSyncStatus returns the progress of the chain sync process broken down by
+// stage along with the current block validation rate and the estimated time
+// remaining.
+func (c *Client) GetSyncStatus(ctx context.Context) (*chainjson.GetSyncStatusResult, error) {
+	return c.GetSyncStatusAsync(ctx).Receive()
+}
+
+// FutureSetBanResult is a future promise to deliver the result of a SetBanAsync
+// RPC invocation (or an applicable error).
+type FutureSetBanResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r *FutureSetBanResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// SetBanAsync returns an instance of a type that can be used to get the result
+// of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SetBan for the blocking version and more details.
+func (c *Client) SetBanAsync(ctx context.Context, subnet string, command chainjson.SetBanSubCmd, banTime *int64, absolute *bool) *FutureSetBanResult {
+	cmd := chainjson.NewSetBanCmd(subnet, command, banTime, absolute)
+	return (*FutureSetBanResult)(c.sendCmd(ctx, cmd))
+}
+
+// SetBan adds or removes a ban for the passed IP address or subnet in CIDR
+// notation.  When adding a ban, the ban time is the number of seconds the ban
+// lasts, or the unix time it expires at when absolute is true.  A nil or zero
+// ban time uses the default ban duration of the server.
+func (c *Client) SetBan(ctx context.Context, subnet string, command chainjson.SetBanSubCmd, banTime *int64, absolute *bool) error {
+	return c.SetBanAsync(ctx, subnet, command, banTime, absolute).Receive()
+}
+
+// FutureListBannedResult is a future promise to deliver the result of a
+// ListBannedAsync RPC invocation (or an applicable error).
+type FutureListBannedResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// currently banned IP addresses and subnets.
+func (r *FutureListBannedResult) Receive() ([]chainjson.ListBannedResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of listbanned result objects.
+	var bans []chainjson.ListBannedResult
+	err = json.Unmarshal(res, &bans)
+	if err != nil {
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// ListBannedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ListBanned for the blocking version and more details.
+func (c *Client) ListBannedAsync(ctx context.Context) *FutureListBannedResult {
+	cmd := chainjson.NewListBannedCmd()
+	return (*FutureListBannedResult)(c.sendCmd(ctx, cmd))
+}
+
+// ListBanned returns the currently banned IP addresses and subnets along with
+// when each ban was created, when it expires, and why it was made.
+func (c *Client) ListBanned(ctx context.Context) ([]chainjson.ListBannedResult, error) {
+	return c.ListBannedAsync(ctx).Receive()
+}
+
+// FutureClearBannedResult is a future promise to deliver the result of a
+// ClearBannedAsync RPC invocation (or an applicable error).
+type FutureClearBannedResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r *FutureClearBannedResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// ClearBannedAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ClearBanned for the blocking version and more details.
+func (c *Client) ClearBannedAsync(ctx context.Context) *FutureClearBannedResult {
+	cmd := chainjson.NewClearBannedCmd()
+	return (*FutureClearBannedResult)(c.sendCmd(ctx, cmd))
+}
+
+// ClearBanned removes all bans.
+func (c *Client) ClearBanned(ctx context.Context) error {
+	return c.ClearBannedAsync(ctx).Receive()
+}
@@ -7,13 +7,17 @@ package rpcclient
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
@@ -40,6 +44,14 @@ type notificationState struct {
 	notifyNewTickets     bool
 	notifyNewTx          bool
 	notifyNewTxVerbose   bool
+	notifyMempoolEvents  bool
+	notifyTicketEvents   bool
+	notifyTemplateDeltas bool
+
+	// lastBlock is the hash of the most recent main chain block the client
+	// was notified of.  It is only tracked when replaying missed blocks on
+	// reconnect is enabled and is nil until the first block notification.
+	lastBlock *chainhash.Hash
 }
 
 // Copy returns a deep copy of the receiver.
@@ -52,6 +64,13 @@ func (s *notificationState) Copy() *notificationState {
 	stateCopy.notifyNewTickets = s.notifyNewTickets
 	stateCopy.notifyNewTx = s.notifyNewTx
 	stateCopy.notifyNewTxVerbose = s.notifyNewTxVerbose
+	stateCopy.notifyMempoolEvents = s.notifyMempoolEvents
+	stateCopy.notifyTicketEvents = s.notifyTicketEvents
+	stateCopy.notifyTemplateDeltas = s.notifyTemplateDeltas
+	if s.lastBlock != nil {
+		lastBlock := *s.lastBlock
+		stateCopy.lastBlock = &lastBlock
+	}
 
 	return &stateCopy
 }
@@ -103,6 +122,20 @@ type NotificationHandlers struct {
 	// been made to register for the notification and the function is non-nil.
 	OnWork func(data []byte, target []byte, reason string)
 
+	// OnTemplateDelta is invoked when a new block template is generated.  The
+	// delta includes the ordered hashes of all transactions in the template,
+	// but only the serialized transactions that were not part of the previous
+	// template unless the full flag is set.  It will only be invoked if a
+	// preceding call to NotifyTemplateDeltas has been made to register for the
+	// notification and the function is non-nil.
+	OnTemplateDelta func(delta *chainjson.TemplateDeltaNtfn)
+
+	// OnRawMempoolPage is invoked for each page of mempool transactions
+	// streamed to the client.  The final page is flagged as such.  It will
+	// only be invoked if a preceding call to StreamRawMempool has been made
+	// and the function is non-nil.
+	OnRawMempoolPage func(page *chainjson.RawMempoolPageNtfn)
+
 	// OnTSpend is invoked when a new tspend arrives in the mempool.  It
 	// will only be invoked if a preceding call to NotifyTSpend has been
 	// made to register for the notification and the function is non-nil.
@@ -147,6 +180,30 @@ type NotificationHandlers struct {
 	// made to register for the notification and the function is non-nil.
 	OnTxAcceptedVerbose func(txDetails *chainjson.TxRawResult)
 
+	// OnMempoolEvent is invoked when a transaction is added to or removed
+	// from the memory pool.  The sequence number increases by one for every
+	// event, so gaps indicate missed events.  The reason is only set for
+	// removed transactions and replacedBy is only non-nil for transactions
+	// that were removed due to a conflicting transaction.  It will only be
+	// invoked if a preceding call to NotifyMempoolEvents has been made to
+	// register for the notification and the function is non-nil.
+	OnMempoolEvent func(sequence uint64, event string, txHash *chainhash.Hash,
+		reason string, replacedBy *chainhash.Hash)
+
+	// OnTicketEvent is invoked when a ticket is purchased, matures, votes, is
+	// missed, or is revoked, either in the memory pool or in a block
+	// connected to the main chain.  It will only be invoked if a preceding
+	// call to NotifyTicketEvents has been made to register for the
+	// notification and the function is non-nil.
+	OnTicketEvent func(ticketEvent *chainjson.TicketEventNtfn)
+
+	// OnTLSCertRenewed is invoked when the server automatically regenerates
+	// its TLS certificate before it expires with the new PEM-encoded
+	// certificate and its expiration time.  Unlike most notifications, no
+	// registration is required.  See the TrustRenewedCerts connection
+	// parameter to automatically trust the new certificate when reconnecting.
+	OnTLSCertRenewed func(cert []byte, validUntil time.Time)
+
 	// OnUnknownNotification is invoked when an unrecognized notification
 	// is received.  This typically means the notification handling code
 	// for this package needs to be updated for a new notification type or
@@ -155,11 +212,49 @@ type NotificationHandlers struct {
 	OnUnknownNotification func(method string, params []json.RawMessage)
 }
 
+// trackLastBlock updates the most recent main chain block the client was
+// notified of from the parameters of a blockconnected or blockdisconnected
+// notification so the notifications for any blocks missed while disconnected
+// can be replayed on reconnect.  The parent of a disconnected block becomes the
+// most recent block.
+//
+// It does nothing unless replaying missed blocks is enabled.
+func (c *Client) trackLastBlock(params []json.RawMessage, disconnected bool) {
+	if !c.config.ReplayMissedBlocks || len(params) == 0 {
+		return
+	}
+
+	headerBytes, err := parseHexParam(params[0])
+	if err != nil {
+		return
+	}
+	var header wire.BlockHeader
+	if err := header.FromBytes(headerBytes); err != nil {
+		return
+	}
+	lastBlock := header.BlockHash()
+	if disconnected {
+		lastBlock = header.PrevBlock
+	}
+
+	c.ntfnStateLock.Lock()
+	c.ntfnState.lastBlock = &lastBlock
+	c.ntfnStateLock.Unlock()
+}
+
 // handleNotification examines the passed notification type, performs
 // conversions to get the raw notification types into higher level types and
 // delivers the notification to the appropriate On<X> handler registered with
 // the client.
 func (c *Client) handleNotification(ntfn *rawNotification) {
+	// TLS certificate renewals are handled separately since renewed
+	// certificates may need to be trusted regardless of whether or not there
+	// are any notification handlers.
+	if chainjson.Method(ntfn.Method) == chainjson.TLSCertRenewedNtfnMethod {
+		c.handleTLSCertRenewed(ntfn.Params)
+		return
+	}
+
 	// Ignore the notification if the client is not interested in any
 	// notifications.
 	if c.ntfnHandlers == nil {
@@ -170,6 +265,8 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 	switch chainjson.Method(ntfn.Method) {
 	// OnBlockConnected
 	case chainjson.BlockConnectedNtfnMethod:
+		c.trackLastBlock(ntfn.Params, false)
+
 		// Ignore the notification if the client is not interested in
 		// it.
 		if c.ntfnHandlers.OnBlockConnected == nil {
@@ -187,6 +284,8 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 	// OnBlockDisconnected
 	case chainjson.BlockDisconnectedNtfnMethod:
+		c.trackLastBlock(ntfn.Params, true)
+
 		// Ignore the notification if the client is not interested in
 		// it.
 		if c.ntfnHandlers.OnBlockDisconnected == nil {
@@ -218,6 +317,40 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnWork(data, target, reason)
 
+	// OnTemplateDelta
+	case chainjson.TemplateDeltaNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnTemplateDelta == nil {
+			return
+		}
+
+		delta, err := parseTemplateDeltaParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid template delta notification: %v",
+				err)
+			return
+		}
+
+		c.ntfnHandlers.OnTemplateDelta(delta)
+
+	// OnRawMempoolPage
+	case chainjson.RawMempoolPageNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnRawMempoolPage == nil {
+			return
+		}
+
+		page, err := parseRawMempoolPageParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid raw mempool page notification: %v",
+				err)
+			return
+		}
+
+		c.ntfnHandlers.OnRawMempoolPage(page)
+
 	// OnTSpend
 	case chainjson.TSpendNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -325,6 +458,41 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnTxAccepted(hash, amt)
 
+	// OnMempoolEvent
+	case chainjson.MempoolEventNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnMempoolEvent == nil {
+			return
+		}
+
+		seq, event, txHash, reason, replacedBy, err :=
+			parseMempoolEventNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid mempool event "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnMempoolEvent(seq, event, txHash, reason, replacedBy)
+
+	// OnTicketEvent
+	case chainjson.TicketEventNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnTicketEvent == nil {
+			return
+		}
+
+		ticketEvent, err := parseTicketEventNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid ticket event notification: %v",
+				err)
+			return
+		}
+
+		c.ntfnHandlers.OnTicketEvent(ticketEvent)
+
 	// OnTxAcceptedVerbose
 	case chainjson.TxAcceptedVerboseNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -352,6 +520,33 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 	}
 }
 
+// handleTLSCertRenewed trusts the certificate included in a tlscertrenewed
+// notification for future connections when the client is configured to do so
+// and delivers the notification to the OnTLSCertRenewed handler when one is
+// registered.
+func (c *Client) handleTLSCertRenewed(params []json.RawMessage) {
+	trust := c.config.TrustRenewedCerts && len(c.config.Certificates) > 0
+	handlerSet := c.ntfnHandlers != nil && c.ntfnHandlers.OnTLSCertRenewed != nil
+	if !trust && !handlerSet {
+		return
+	}
+
+	cert, validUntil, err := parseTLSCertRenewedNtfnParams(params)
+	if err != nil {
+		log.Warnf("Received invalid tlscertrenewed notification: %v", err)
+		return
+	}
+
+	if trust {
+		c.trustRenewedCert(cert)
+		log.Infof("Trusting renewed TLS certificate for %s that is valid "+
+			"until %v", c.config.Host, validUntil)
+	}
+	if handlerSet {
+		c.ntfnHandlers.OnTLSCertRenewed(cert, validUntil)
+	}
+}
+
 // wrongNumParams is an error type describing an unparseable JSON-RPC
 // notification due to an incorrect number of parameters for the
 // expected notification type.  The value is the number of parameters
@@ -425,6 +620,44 @@ func parseWorkParams(params []json.RawMessage) (data, target []byte, reason stri
 	return data, target, reason, nil
 }
 
+// parseTemplateDeltaParams parses out the parameters included in a
+// templatedelta notification.
+func parseTemplateDeltaParams(params []json.RawMessage) (*chainjson.TemplateDeltaNtfn, error) {
+	if len(params) != 6 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	var delta chainjson.TemplateDeltaNtfn
+	fields := []interface{}{&delta.Header, &delta.Reason, &delta.Full,
+		&delta.TxHashes, &delta.STxHashes, &delta.NewTxns}
+	for i, field := range fields {
+		if err := json.Unmarshal(params[i], field); err != nil {
+			return nil, err
+		}
+	}
+
+	return &delta, nil
+}
+
+// parseRawMempoolPageParams parses out the parameters included in a
+// rawmempoolpage notification.
+func parseRawMempoolPageParams(params []json.RawMessage) (*chainjson.RawMempoolPageNtfn, error) {
+	if len(params) != 4 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	var page chainjson.RawMempoolPageNtfn
+	fields := []interface{}{&page.Page, &page.Final, &page.TxHashes,
+		&page.Transactions}
+	for i, field := range fields {
+		if err := json.Unmarshal(params[i], field); err != nil {
+			return nil, err
+		}
+	}
+
+	return &page, nil
+}
+
 // parseTSpendParams parses out the parameters included in a tspend
 // notification.
 func parseTSpendParams(params []json.RawMessage) ([]byte, error) {
@@ -460,6 +693,42 @@ func parseRelevantTxAcceptedParams(params []json.RawMessage) (transaction []byte
 	return parseHexParam(params[0])
 }
 
+// parseTLSCertRenewedNtfnParams parses out the PEM-encoded certificate and
+// its expiration time from the parameters of a tlscertrenewed notification.
+// An error is returned if the certificate is not a valid PEM-encoded x.509
+// certificate.
+func parseTLSCertRenewedNtfnParams(params []json.RawMessage) ([]byte, time.Time, error) {
+	if len(params) != 2 {
+		return nil, time.Time{}, wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var certStr string
+	err := json.Unmarshal(params[0], &certStr)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// Unmarshal second parameter as an integer.
+	var validUntil int64
+	err = json.Unmarshal(params[1], &validUntil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// Ensure the certificate is a valid PEM-encoded certificate.
+	cert := []byte(certStr)
+	block, _ := pem.Decode(cert)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, time.Time{}, errors.New("certificate is not PEM-encoded")
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return cert, time.Unix(validUntil, 0), nil
+}
+
 func parseReorganizationNtfnParams(params []json.RawMessage) (*chainhash.Hash,
 	int32, *chainhash.Hash, int32, error) {
 	errorOut := func(err error) (*chainhash.Hash, int32, *chainhash.Hash,
@@ -667,6 +936,91 @@ func parseTxAcceptedNtfnParams(params []json.RawMessage) (*chainhash.Hash,
 	return txHash, amt, nil
 }
 
+// parseMempoolEventNtfnParams parses out the sequence number, event type,
+// transaction hash, removal reason, and replacing transaction hash from the
+// parameters of a mempoolevent notification.
+func parseMempoolEventNtfnParams(params []json.RawMessage) (uint64, string,
+	*chainhash.Hash, string, *chainhash.Hash, error) {
+
+	if len(params) != 5 {
+		return 0, "", nil, "", nil, wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as an unsigned integer.
+	var seq uint64
+	err := json.Unmarshal(params[0], &seq)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+
+	// Unmarshal second parameter as a string.
+	var event string
+	err = json.Unmarshal(params[1], &event)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+
+	// Unmarshal third parameter as a string.
+	var txHashStr string
+	err = json.Unmarshal(params[2], &txHashStr)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+
+	// Unmarshal fourth parameter as a string.
+	var reason string
+	err = json.Unmarshal(params[3], &reason)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+
+	// Unmarshal fifth parameter as a string.
+	var replacedByStr string
+	err = json.Unmarshal(params[4], &replacedByStr)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+
+	// Decode string encoding of transaction hashes.
+	txHash, err := chainhash.NewHashFromStr(txHashStr)
+	if err != nil {
+		return 0, "", nil, "", nil, err
+	}
+	var replacedBy *chainhash.Hash
+	if replacedByStr != "" {
+		replacedBy, err = chainhash.NewHashFromStr(replacedByStr)
+		if err != nil {
+			return 0, "", nil, "", nil, err
+		}
+	}
+
+	return seq, event, txHash, reason, replacedBy, nil
+}
+
+// parseTicketEventNtfnParams parses out the details of a ticket event from the
+// parameters of a ticketevent notification.
+func parseTicketEventNtfnParams(params []json.RawMessage) (*chainjson.TicketEventNtfn, error) {
+	if len(params) != 6 {
+		return nil, wrongNumParams(len(params))
+	}
+
+	// Unmarshal the first five parameters as strings and the sixth as an
+	// integer.
+	var ticketEvent chainjson.TicketEventNtfn
+	strParams := []*string{&ticketEvent.Event, &ticketEvent.Source,
+		&ticketEvent.Ticket, &ticketEvent.TxID, &ticketEvent.BlockHash}
+	for i, strParam := range strParams {
+		if err := json.Unmarshal(params[i], strParam); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(params[5], &ticketEvent.BlockHeight); err != nil {
+		return nil, err
+	}
+
+	return &ticketEvent, nil
+}
+
 // parseTxAcceptedVerboseNtfnParams parses out details about a raw transaction
 // from the parameters of a txacceptedverbose notification.
 func parseTxAcceptedVerboseNtfnParams(params []json.RawMessage) (*chainjson.TxRawResult,
@@ -805,6 +1159,43 @@ func (c *Client) NotifyBlocks(ctx context.Context) error {
 	return c.NotifyBlocksAsync(ctx).Receive()
 }
 
+// NotifyBlocksSinceAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See NotifyBlocksSince for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksSinceAsync(ctx context.Context, since *chainhash.Hash) *FutureNotifyBlocksResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyBlocksResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyBlocksResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyBlocksCmd()
+	cmd.Since = dcrjson.String(since.String())
+	return (*FutureNotifyBlocksResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyBlocksSince registers the client to receive notifications when blocks
+// are connected and disconnected from the main chain after first replaying the
+// notifications for all blocks connected to the main chain after the block
+// with the provided hash.  This allows clients that were briefly disconnected
+// to receive the notifications they missed in order instead of rescanning.
+//
+// See NotifyBlocks for more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksSince(ctx context.Context, since *chainhash.Hash) error {
+	return c.NotifyBlocksSinceAsync(ctx, since).Receive()
+}
+
 // NotifyWork registers the client to receive notifications when a new block
 // template has been generated.
 //
@@ -973,6 +1364,208 @@ func (c *Client) NotifyNewTransactions(ctx context.Context, verbose bool) error
 	return c.NotifyNewTransactionsAsync(ctx, verbose).Receive()
 }
 
+// FutureNotifyTemplateDeltasResult is a future promise to deliver the result
+// of a NotifyTemplateDeltasAsync RPC invocation (or an applicable error).
+type FutureNotifyTemplateDeltasResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyTemplateDeltasResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyTemplateDeltasAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyTemplateDeltas for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTemplateDeltasAsync(ctx context.Context) *FutureNotifyTemplateDeltasResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyTemplateDeltasResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyTemplateDeltasResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyTemplateDeltasCmd()
+	return (*FutureNotifyTemplateDeltasResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyTemplateDeltas registers the client to receive notifications every
+// time a new block template is generated.  Each notification only includes the
+// transactions that were not part of the previous template, with the exception
+// of the first notification after registering which includes the full current
+// template.  The notifications are delivered to the notification handlers
+// associated with the client.  Calling this function has no effect if there are
+// no notification handlers and will result in an error if the client is
+// configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via
+// OnTemplateDelta.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTemplateDeltas(ctx context.Context) error {
+	return c.NotifyTemplateDeltasAsync(ctx).Receive()
+}
+
+// FutureStreamRawMempoolResult is a future promise to deliver the result of a
+// StreamRawMempoolAsync RPC invocation (or an applicable error).
+type FutureStreamRawMempoolResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// number of pages and transactions that were streamed.
+func (r *FutureStreamRawMempoolResult) Receive() (*chainjson.StreamRawMempoolResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	var result chainjson.StreamRawMempoolResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StreamRawMempoolAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See StreamRawMempool for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) StreamRawMempoolAsync(ctx context.Context, verbose bool, txType chainjson.GetRawMempoolTxTypeCmd, pageSize int) *FutureStreamRawMempoolResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureStreamRawMempoolResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	cmd := chainjson.NewStreamRawMempoolCmd(&verbose,
+		dcrjson.String(string(txType)), &pageSize)
+	return (*FutureStreamRawMempoolResult)(c.sendCmd(ctx, cmd))
+}
+
+// StreamRawMempool requests the transactions in the memory pool for the given
+// txType be streamed to the client as a series of pages of at most pageSize
+// transactions.  Only the transaction hashes are included unless verbose is
+// set.
+//
+// The notifications delivered as a result of this call will be via
+// OnRawMempoolPage.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) StreamRawMempool(ctx context.Context, verbose bool, txType chainjson.GetRawMempoolTxTypeCmd, pageSize int) (*chainjson.StreamRawMempoolResult, error) {
+	return c.StreamRawMempoolAsync(ctx, verbose, txType, pageSize).Receive()
+}
+
+// FutureNotifyMempoolEventsResult is a future promise to deliver the result
+// of a NotifyMempoolEventsAsync RPC invocation (or an applicable error).
+type FutureNotifyMempoolEventsResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyMempoolEventsResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyMempoolEventsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyMempoolEvents for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyMempoolEventsAsync(ctx context.Context) *FutureNotifyMempoolEventsResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyMempoolEventsResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyMempoolEventsResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyMempoolEventsCmd()
+	return (*FutureNotifyMempoolEventsResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyMempoolEvents registers the client to receive notifications every time
+// a transaction is added to or removed from the memory pool.  The
+// notifications are delivered to the notification handlers associated with the
+// client.  Calling this function has no effect if there are no notification
+// handlers and will result in an error if the client is configured to run in
+// HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via
+// OnMempoolEvent.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyMempoolEvents(ctx context.Context) error {
+	return c.NotifyMempoolEventsAsync(ctx).Receive()
+}
+
+// FutureNotifyTicketEventsResult is a future promise to deliver the result
+// of a NotifyTicketEventsAsync RPC invocation (or an applicable error).
+type FutureNotifyTicketEventsResult cmdRes
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r *FutureNotifyTicketEventsResult) Receive() error {
+	_, err := receiveFuture(r.ctx, r.c)
+	return err
+}
+
+// NotifyTicketEventsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyTicketEvents for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTicketEventsAsync(ctx context.Context) *FutureNotifyTicketEventsResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return (*FutureNotifyTicketEventsResult)(newFutureError(ctx, ErrWebsocketsRequired))
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return (*FutureNotifyTicketEventsResult)(newNilFutureResult(ctx))
+	}
+
+	cmd := chainjson.NewNotifyTicketEventsCmd()
+	return (*FutureNotifyTicketEventsResult)(c.sendCmd(ctx, cmd))
+}
+
+// NotifyTicketEvents registers the client to receive notifications every time
+// a ticket is purchased, matures, votes, is missed, or is revoked.  Ticket
+// purchases, votes, and revocations are notified both when they are added to
+// the memory pool and when they are included in a block connected to the main
+// chain.  The notifications are delivered to the notification handlers
+// associated with the client.  Calling this function has no effect if there
+// are no notification handlers and will result in an error if the client is
+// configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via
+// OnTicketEvent.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTicketEvents(ctx context.Context) error {
+	return c.NotifyTicketEventsAsync(ctx).Receive()
+}
+
 // FutureLoadTxFilterResult is a future promise to deliver the result
 // of a LoadTxFilterAsync RPC invocation (or an applicable error).
 type FutureLoadTxFilterResult cmdRes
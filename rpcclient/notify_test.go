@@ -0,0 +1,244 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+	"github.com/decred/dcrd/wire"
+	"github.com/gorilla/websocket"
+)
+
+// TestReconnectReplayMissedBlocks ensures websocket clients re-register block
+// notifications on reconnect and request the notifications for any blocks
+// missed while disconnected to be replayed when configured to do so.
+func TestReconnectReplayMissedBlocks(t *testing.T) {
+	conns := make(chan *websocket.Conn)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conns <- conn
+	}))
+	defer server.Close()
+
+	// nextConn returns the next connection established by the client.
+	nextConn := func() *websocket.Conn {
+		t.Helper()
+		select {
+		case conn := <-conns:
+			return conn
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for client connection")
+		}
+		return nil
+	}
+
+	// readRequest reads the next request sent over the provided connection
+	// and replies to it with a null result.
+	readRequest := func(conn *websocket.Conn) *dcrjson.Request {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		var req dcrjson.Request
+		if err := conn.ReadJSON(&req); err != nil {
+			t.Fatalf("unexpected error reading request: %v", err)
+		}
+		reply := fmt.Sprintf(`{"result":null,"error":null,"id":%v}`, req.ID)
+		err := conn.WriteMessage(websocket.TextMessage, []byte(reply))
+		if err != nil {
+			t.Fatalf("unexpected error writing reply: %v", err)
+		}
+		return &req
+	}
+
+	cfg := &ConnConfig{
+		Host:               strings.TrimPrefix(server.URL, "http://"),
+		Endpoint:           "ws",
+		DisableTLS:         true,
+		ReplayMissedBlocks: true,
+	}
+	connected := make(chan struct{}, 1)
+	c, err := New(cfg, &NotificationHandlers{
+		OnBlockConnected: func(blockHeader []byte, transactions [][]byte) {
+			connected <- struct{}{}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	defer c.Shutdown()
+
+	// Register for block notifications and notify the client of a block.
+	conn := nextConn()
+	errChan := make(chan error, 1)
+	go func() { errChan <- c.NotifyBlocks(context.Background()) }()
+	if req := readRequest(conn); req.Method != "notifyblocks" ||
+		len(req.Params) != 0 {
+
+		t.Fatalf("unexpected initial request: %s %s", req.Method,
+			req.Params)
+	}
+	if err := <-errChan; err != nil {
+		t.Fatalf("unexpected error registering for blocks: %v", err)
+	}
+	header := wire.BlockHeader{Height: 100, Nonce: 1}
+	headerBytes, err := header.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error serializing header: %v", err)
+	}
+	ntfn := fmt.Sprintf(`{"jsonrpc":"1.0","method":"blockconnected",`+
+		`"params":["%x",[]],"id":null}`, headerBytes)
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(ntfn)); err != nil {
+		t.Fatalf("unexpected error writing notification: %v", err)
+	}
+	select {
+	case <-connected:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for block connected notification")
+	}
+
+	// Drop the connection and ensure the client re-registers for block
+	// notifications since the last block it was notified of.
+	conn.Close()
+	conn = nextConn()
+	defer conn.Close()
+	req := readRequest(conn)
+	blockHash := header.BlockHash()
+	wantParams := fmt.Sprintf(`["%s"]`, blockHash)
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling params: %v", err)
+	}
+	if req.Method != "notifyblocks" || string(params) != wantParams {
+		t.Fatalf("unexpected reregistration request: %s %s (want params "+
+			"%s)", req.Method, params, wantParams)
+	}
+}
+
+// TestTLSCertRenewed ensures tlscertrenewed notifications are delivered to the
+// registered handler and that the renewed certificates are only trusted for
+// future connections when configured to do so and they are valid.
+func TestTLSCertRenewed(t *testing.T) {
+	// Create a self-signed certificate to include in the notifications.
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+	validUntil := time.Unix(1700000000, 0)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotAfter:     validUntil,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template,
+		priv.Public(), priv)
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	// makeNtfn returns a raw tlscertrenewed notification for the provided
+	// certificate.
+	makeNtfn := func(cert []byte) *rawNotification {
+		certJSON, _ := json.Marshal(string(cert))
+		return &rawNotification{
+			Method: "tlscertrenewed",
+			Params: []json.RawMessage{certJSON, []byte("1700000000")},
+		}
+	}
+
+	tests := []struct {
+		name      string // test description
+		cert      []byte // certificate in the notification
+		trust     bool   // whether to trust renewed certificates
+		pinned    []byte // configured certificates
+		wantTrust bool   // whether the certificate is expected to be trusted
+		wantNtfn  bool   // whether the handler is expected to be invoked
+	}{{
+		name:      "trusted with pinned certs",
+		cert:      cert,
+		trust:     true,
+		pinned:    []byte("pinned"),
+		wantTrust: true,
+		wantNtfn:  true,
+	}, {
+		name:     "not configured to trust",
+		cert:     cert,
+		pinned:   []byte("pinned"),
+		wantNtfn: true,
+	}, {
+		name:     "no pinned certs",
+		cert:     cert,
+		trust:    true,
+		wantNtfn: true,
+	}, {
+		name:   "invalid certificate",
+		cert:   []byte("not a certificate"),
+		trust:  true,
+		pinned: []byte("pinned"),
+	}}
+
+	for _, test := range tests {
+		var gotCert []byte
+		var gotValidUntil time.Time
+		c := &Client{
+			config: &ConnConfig{
+				Certificates:      test.pinned,
+				TrustRenewedCerts: test.trust,
+			},
+			ntfnHandlers: &NotificationHandlers{
+				OnTLSCertRenewed: func(cert []byte, validUntil time.Time) {
+					gotCert, gotValidUntil = cert, validUntil
+				},
+			},
+		}
+		c.handleNotification(makeNtfn(test.cert))
+
+		trusted := c.trustedRenewedCerts()
+		if gotTrust := bytes.Equal(trusted, test.cert); gotTrust != test.wantTrust {
+			t.Errorf("%q: unexpected trusted certs -- got %q", test.name,
+				trusted)
+			continue
+		}
+		if gotNtfn := gotCert != nil; gotNtfn != test.wantNtfn {
+			t.Errorf("%q: unexpected handler invocation -- got %v, want %v",
+				test.name, gotNtfn, test.wantNtfn)
+			continue
+		}
+		if test.wantNtfn && (!bytes.Equal(gotCert, test.cert) ||
+			!gotValidUntil.Equal(validUntil)) {
+
+			t.Errorf("%q: unexpected notification -- got cert %q valid "+
+				"until %v", test.name, gotCert, gotValidUntil)
+		}
+	}
+
+	// Ensure the certificate is still trusted when there are no notification
+	// handlers.
+	c := &Client{config: &ConnConfig{
+		Certificates:      []byte("pinned"),
+		TrustRenewedCerts: true,
+	}}
+	c.handleNotification(makeNtfn(cert))
+	if trusted := c.trustedRenewedCerts(); !bytes.Equal(trusted, cert) {
+		t.Fatalf("unexpected trusted certs without handlers -- got %q",
+			trusted)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+)
+
+const (
+	// DefaultPoolSize is the default number of connections in a pool.
+	DefaultPoolSize = 4
+
+	// DefaultBreakerThreshold is the default number of consecutive failed
+	// calls after which the circuit breaker of a pooled connection opens.
+	DefaultBreakerThreshold = 5
+
+	// DefaultBreakerCooldown is the default amount of time the circuit
+	// breaker of a pooled connection stays open before a call is allowed
+	// through to probe whether the connection recovered.
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrNoPoolClients is the error returned by the pool when the circuit breakers
+// of all of its connections are open.
+var ErrNoPoolClients = errors.New("no pooled connections are available")
+
+// PoolConfig describes the configuration parameters for a pool of clients.
+// The zero value of each field selects its default.
+type PoolConfig struct {
+	// Size is the number of connections in the pool.  It defaults to
+	// DefaultPoolSize.
+	Size int
+
+	// CallTimeout is the maximum amount of time each call is allowed to take
+	// before it is canceled.  It only applies when the context passed to the
+	// call does not already expire sooner.  No timeout is applied when it is
+	// zero.
+	CallTimeout time.Duration
+
+	// BreakerThreshold is the number of consecutive calls that must fail on
+	// a connection before its circuit breaker opens and calls are no longer
+	// routed to it.  Errors returned by the server, such as an invalid
+	// parameter, do not count as failures.  It defaults to
+	// DefaultBreakerThreshold.
+	BreakerThreshold int
+
+	// BreakerCooldown is the amount of time the circuit breaker of a
+	// connection stays open before a single call is routed to it to probe
+	// whether it recovered.  It defaults to DefaultBreakerCooldown.
+	BreakerCooldown time.Duration
+}
+
+// poolConn houses a pooled client along with the state used to route calls to
+// it.
+type poolConn struct {
+	client *Client
+
+	// The following fields are protected by the pool mutex.
+	inFlight  int
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// Pool multiplexes concurrent calls across several connections to the same RPC
+// server.  Each call is routed to the connection with the fewest calls in
+// flight whose circuit breaker is closed.  It is intended for workloads that
+// issue a large number of concurrent calls, such as indexers, which would
+// otherwise be limited by a single connection.
+//
+// The pooled clients do not have any notification handlers, so pools are not
+// suitable for registering for notifications.
+type Pool struct {
+	cfg PoolConfig
+
+	mtx   sync.Mutex
+	conns []*poolConn
+	next  int
+}
+
+// NewPool creates a new pool of clients that all connect to the RPC server
+// described by the passed connection configuration.  The clients are created
+// in the same way as New, so the pool runs in HTTP POST mode when configured
+// to do so and uses websockets otherwise.
+func NewPool(config *ConnConfig, poolConfig *PoolConfig) (*Pool, error) {
+	var cfg PoolConfig
+	if poolConfig != nil {
+		cfg = *poolConfig
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultPoolSize
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = DefaultBreakerCooldown
+	}
+
+	p := &Pool{
+		cfg:   cfg,
+		conns: make([]*poolConn, 0, cfg.Size),
+	}
+	for i := 0; i < cfg.Size; i++ {
+		connConfig := *config
+		client, err := New(&connConfig, nil)
+		if err != nil {
+			p.Shutdown()
+			return nil, err
+		}
+		p.conns = append(p.conns, &poolConn{client: client})
+	}
+	return p, nil
+}
+
+// acquire selects the connection the next call is routed to and marks the
+// call as in flight on it.  It returns ErrNoPoolClients when the circuit
+// breakers of all connections are open.
+//
+// This function is safe for concurrent access.
+func (p *Pool) acquire() (*poolConn, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	// Start the search at the next connection in round-robin order so calls
+	// are spread evenly among connections with the same number of calls in
+	// flight.
+	now := time.Now()
+	var best *poolConn
+	for i := 0; i < len(p.conns); i++ {
+		pc := p.conns[(p.next+i)%len(p.conns)]
+		if pc.failures >= p.cfg.BreakerThreshold {
+			// Only route a single probe call to connections with an
+			// open breaker once the cooldown elapses.
+			if now.Before(pc.openUntil) || pc.probing {
+				continue
+			}
+			best = pc
+			break
+		}
+		if best == nil || pc.inFlight < best.inFlight {
+			best = pc
+		}
+	}
+	if best == nil {
+		return nil, ErrNoPoolClients
+	}
+	if best.failures >= p.cfg.BreakerThreshold {
+		best.probing = true
+	}
+	best.inFlight++
+	p.next = (p.next + 1) % len(p.conns)
+	return best, nil
+}
+
+// release marks a call that was routed to the passed connection as complete
+// and updates its circuit breaker according to whether or not the call failed.
+//
+// This function is safe for concurrent access.
+func (p *Pool) release(pc *poolConn, failed bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	pc.inFlight--
+	pc.probing = false
+	if !failed {
+		pc.failures = 0
+		return
+	}
+	pc.failures++
+	if pc.failures >= p.cfg.BreakerThreshold {
+		if pc.failures == p.cfg.BreakerThreshold {
+			log.Warnf("Too many failed calls to %s -- pausing calls on "+
+				"connection for %v", pc.client.config.Host,
+				p.cfg.BreakerCooldown)
+		}
+		pc.openUntil = time.Now().Add(p.cfg.BreakerCooldown)
+	}
+}
+
+// isCallFailure returns whether or not the passed error returned by a call
+// indicates a problem with the connection the call was routed to as opposed
+// to an error returned by the server or the caller canceling the call.
+func isCallFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var rpcErr *dcrjson.RPCError
+	return !errors.As(err, &rpcErr)
+}
+
+// Do routes a call to one of the pooled clients by invoking the passed function
+// with it.  The context passed to the function has the configured call timeout
+// applied.  The error returned by the function is returned and determines
+// whether or not the call counts as a failure for the purposes of circuit
+// breaking.
+//
+// For example:
+//
+//	var count int64
+//	err := pool.Do(ctx, func(ctx context.Context, c *rpcclient.Client) error {
+//		var err error
+//		count, err = c.GetBlockCount(ctx)
+//		return err
+//	})
+//
+// This function will return ErrNoPoolClients without invoking the function
+// when the circuit breakers of all connections are open.
+func (p *Pool) Do(ctx context.Context, f func(context.Context, *Client) error) error {
+	pc, err := p.acquire()
+	if err != nil {
+		return err
+	}
+
+	callCtx := ctx
+	if p.cfg.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, p.cfg.CallTimeout)
+		defer cancel()
+	}
+	err = f(callCtx, pc.client)
+	p.release(pc, isCallFailure(ctx, err))
+	return err
+}
+
+// RawRequest sends a raw or custom request to the server via one of the pooled
+// clients.  See Client.RawRequest for more details.
+func (p *Pool) RawRequest(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := p.Do(ctx, func(ctx context.Context, c *Client) error {
+		var err error
+		result, err = c.RawRequest(ctx, method, params)
+		return err
+	})
+	return result, err
+}
+
+// Shutdown shuts down all of the pooled clients.
+func (p *Pool) Shutdown() {
+	for _, pc := range p.conns {
+		pc.client.Shutdown()
+	}
+}
+
+// WaitForShutdown blocks until all of the pooled clients are shut down.
+func (p *Pool) WaitForShutdown() {
+	for _, pc := range p.conns {
+		pc.client.WaitForShutdown()
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v4"
+)
+
+// TestPool ensures pools route calls to their clients, apply the configured
+// call timeout, and stop routing calls to connections with too many failed
+// calls until the cooldown elapses.
+func TestPool(t *testing.T) {
+	// The server replies based on the requested method.
+	var failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dcrjson.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if atomic.LoadInt32(&failing) != 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		switch req.Method {
+		case "slow":
+			time.Sleep(500 * time.Millisecond)
+		case "invalid":
+			fmt.Fprintf(w, `{"result":null,"error":{"code":-8,`+
+				`"message":"invalid"},"id":%v}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"result":%q,"error":null,"id":%v}`, req.Method,
+			req.ID)
+	}))
+	defer server.Close()
+
+	cfg := &ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}
+	const cooldown = 100 * time.Millisecond
+	p, err := NewPool(cfg, &PoolConfig{
+		Size:             2,
+		CallTimeout:      50 * time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  cooldown,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating pool: %v", err)
+	}
+	defer p.Shutdown()
+
+	ctx := context.Background()
+	result, err := p.RawRequest(ctx, "ping", nil)
+	if err != nil || string(result) != `"ping"` {
+		t.Fatalf("unexpected result: %s (err %v)", result, err)
+	}
+
+	// Ensure calls that exceed the call timeout are canceled.
+	_, err = p.RawRequest(ctx, "slow", nil)
+	if !errors.Is(err, ErrRequestCanceled) {
+		t.Fatalf("unexpected error for slow call: %v", err)
+	}
+
+	// Ensure errors returned by the server do not open the breakers.
+	for i := 0; i < 4; i++ {
+		_, err := p.RawRequest(ctx, "invalid", nil)
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) {
+			t.Fatalf("unexpected error for invalid call: %v", err)
+		}
+	}
+	if _, err := p.RawRequest(ctx, "ping", nil); err != nil {
+		t.Fatalf("unexpected error after server errors: %v", err)
+	}
+
+	// Ensure the breakers of all connections open after enough failed calls
+	// and that calls are routed to them again once the cooldown elapses.
+	atomic.StoreInt32(&failing, 1)
+	for i := 0; i < 4; i++ {
+		if _, err := p.RawRequest(ctx, "ping", nil); err == nil {
+			t.Fatal("call to failing server did not fail")
+		}
+	}
+	_, err = p.RawRequest(ctx, "ping", nil)
+	if !errors.Is(err, ErrNoPoolClients) {
+		t.Fatalf("unexpected error with open breakers: %v", err)
+	}
+	atomic.StoreInt32(&failing, 0)
+	time.Sleep(cooldown)
+	for i := 0; i < 4; i++ {
+		if _, err := p.RawRequest(ctx, "ping", nil); err != nil {
+			t.Fatalf("unexpected error after cooldown: %v", err)
+		}
+	}
+}
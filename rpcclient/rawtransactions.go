@@ -75,6 +75,50 @@ func (c *Client) GetRawTransaction(ctx context.Context, txHash *chainhash.Hash)
 	return c.GetRawTransactionAsync(ctx, txHash).Receive()
 }
 
+// FutureGetRejectedTransactionResult is a future promise to deliver the result
+// of a GetRejectedTransactionAsync RPC invocation (or an applicable error).
+type FutureGetRejectedTransactionResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// reason a transaction was recently rejected by the memory pool.
+func (r *FutureGetRejectedTransactionResult) Receive() (*chainjson.GetRejectedTransactionResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getrejectedtransaction result object.
+	var rejectedResult chainjson.GetRejectedTransactionResult
+	err = json.Unmarshal(res, &rejectedResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rejectedResult, nil
+}
+
+// GetRejectedTransactionAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRejectedTransaction for the blocking version and more details.
+func (c *Client) GetRejectedTransactionAsync(ctx context.Context, txHash *chainhash.Hash) *FutureGetRejectedTransactionResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+
+	cmd := chainjson.NewGetRejectedTransactionCmd(hash)
+	return (*FutureGetRejectedTransactionResult)(c.sendCmd(ctx, cmd))
+}
+
+// GetRejectedTransaction returns the reason the transaction with the provided
+// hash was recently rejected by the memory pool of the server.  An error is
+// returned when the server has no record of the transaction being rejected.
+func (c *Client) GetRejectedTransaction(ctx context.Context, txHash *chainhash.Hash) (*chainjson.GetRejectedTransactionResult, error) {
+	return c.GetRejectedTransactionAsync(ctx, txHash).Receive()
+}
+
 // FutureGetRawTransactionVerboseResult is a future promise to deliver the
 // result of a GetRawTransactionVerboseAsync RPC invocation (or an applicable
 // error).
@@ -121,6 +165,112 @@ func (c *Client) GetRawTransactionVerbose(ctx context.Context, txHash *chainhash
 	return c.GetRawTransactionVerboseAsync(ctx, txHash).Receive()
 }
 
+// FutureSearchRawTransactionsResult is a future promise to deliver the result
+// of a SearchRawTransactionsAsync RPC invocation (or an applicable error).
+type FutureSearchRawTransactionsResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// transactions that involve an address.
+func (r *FutureSearchRawTransactionsResult) Receive() ([]*wire.MsgTx, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of strings.
+	var txHexes []string
+	err = json.Unmarshal(res, &txHexes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode and deserialize each transaction.
+	msgTxns := make([]*wire.MsgTx, 0, len(txHexes))
+	for _, txHex := range txHexes {
+		serializedTx, err := hex.DecodeString(txHex)
+		if err != nil {
+			return nil, err
+		}
+
+		var msgTx wire.MsgTx
+		if err := msgTx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
+			return nil, err
+		}
+		msgTxns = append(msgTxns, &msgTx)
+	}
+	return msgTxns, nil
+}
+
+// SearchRawTransactionsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SearchRawTransactions for the blocking version and more details.
+func (c *Client) SearchRawTransactionsAsync(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) *FutureSearchRawTransactionsResult {
+	cmd := chainjson.NewSearchRawTransactionsCmd(address.String(),
+		dcrjson.Int(0), &skip, &count, &reverse)
+	return (*FutureSearchRawTransactionsResult)(c.sendCmd(ctx, cmd))
+}
+
+// SearchRawTransactions returns the main chain transactions that involve the
+// passed address.  The provided number of transactions are skipped and at most
+// count transactions are returned in chain order, or in reverse chain order
+// when the reverse flag is set.
+//
+// NOTE: This requires the server to have the address index enabled.
+//
+// See SearchRawTransactionsVerbose to retrieve a list of data structures with
+// information about the transactions instead of the transactions themselves.
+func (c *Client) SearchRawTransactions(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) ([]*wire.MsgTx, error) {
+	return c.SearchRawTransactionsAsync(ctx, address, skip, count, reverse).Receive()
+}
+
+// FutureSearchRawTransactionsVerboseResult is a future promise to deliver the
+// result of a SearchRawTransactionsVerboseAsync RPC invocation (or an
+// applicable error).
+type FutureSearchRawTransactionsVerboseResult cmdRes
+
+// Receive waits for the response promised by the future and returns the
+// details of the transactions that involve an address.
+func (r *FutureSearchRawTransactionsVerboseResult) Receive() ([]chainjson.SearchRawTransactionsResult, error) {
+	res, err := receiveFuture(r.ctx, r.c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of searchrawtransactions result objects.
+	var result []chainjson.SearchRawTransactionsResult
+	err = json.Unmarshal(res, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SearchRawTransactionsVerboseAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See SearchRawTransactionsVerbose for the blocking version and more details.
+func (c *Client) SearchRawTransactionsVerboseAsync(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) *FutureSearchRawTransactionsVerboseResult {
+	cmd := chainjson.NewSearchRawTransactionsCmd(address.String(),
+		dcrjson.Int(1), &skip, &count, &reverse)
+	return (*FutureSearchRawTransactionsVerboseResult)(c.sendCmd(ctx, cmd))
+}
+
+// SearchRawTransactionsVerbose returns details about the main chain
+// transactions that involve the passed address along with the inputs and
+// outputs through which the address is involved.
+//
+// NOTE: This requires the server to have the address index enabled.
+//
+// See SearchRawTransactions to retrieve a list of raw transactions instead.
+func (c *Client) SearchRawTransactionsVerbose(ctx context.Context, address stdaddr.Address, skip, count int, reverse bool) ([]chainjson.SearchRawTransactionsResult, error) {
+	return c.SearchRawTransactionsVerboseAsync(ctx, address, skip, count,
+		reverse).Receive()
+}
+
 // FutureDecodeRawTransactionResult is a future promise to deliver the result
 // of a DecodeRawTransactionAsync RPC invocation (or an applicable error).
 type FutureDecodeRawTransactionResult cmdRes
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// unixListener wraps a unix domain socket listener in order to remove the
+// socket from the path it was linked to when the listener is closed.
+type unixListener struct {
+	*net.UnixListener
+	path string
+}
+
+// Close closes the listener and removes its socket.
+//
+// This is part of the net.Listener interface.
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+// listenUnixPrivate returns a listener for a unix domain socket at the
+// provided path that is only accessible by its owner.
+//
+// The socket is initially created inside of a new directory that is only
+// accessible by the owner and it is only linked to the provided path once its
+// permissions have been restricted.  This ensures other users are never able
+// to connect to the socket, even briefly, regardless of the umask of the
+// process.  Much like listening on the path directly, an error is returned
+// when the path already exists.
+func listenUnixPrivate(path string) (net.Listener, error) {
+	// Keep the temporary path short since the length of socket paths is
+	// limited.
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".rpc")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmpPath := filepath.Join(dir, "s")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{
+		Name: tmpPath,
+		Net:  "unix",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The temporary directory, including the socket path in it, is removed
+	// once the socket is linked to the provided path, so prevent the listener
+	// from attempting to remove it when it is closed.
+	listener.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Link(tmpPath, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &unixListener{UnixListener: listener, path: path}, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// TestListenUnixPrivate ensures unix domain sockets are only accessible by
+// their owner, even with a permissive umask, that they do not replace existing
+// files, and that they are removed once the listener is closed.
+func TestListenUnixPrivate(t *testing.T) {
+	oldMask := syscall.Umask(0)
+	defer syscall.Umask(oldMask)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rpc.sock")
+	listener, err := listenUnixPrivate(path)
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		listener.Close()
+		t.Fatalf("unable to stat socket: %v", err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0600 {
+		t.Errorf("unexpected socket mode %v", fi.Mode())
+	}
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		t.Errorf("socket is not owned by the current user")
+	}
+
+	// Ensure the socket accepts connections at the provided path and that
+	// no temporary files are left behind.
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Errorf("unable to connect to socket: %v", err)
+	} else {
+		conn.Close()
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("unexpected directory entries: %v (%v)", entries, err)
+	}
+
+	// Ensure existing paths are not replaced.
+	if l, err := listenUnixPrivate(path); err == nil {
+		l.Close()
+		t.Error("listened on a path that already exists")
+	}
+
+	listener.Close()
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Fatalf("socket was not removed when the listener was closed: %v",
+			err)
+	}
+}
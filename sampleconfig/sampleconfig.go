@@ -62,16 +62,31 @@ const fileContents = `[Application Options]
 ; to correlate connections.
 ; torisolation=1
 
+; Connect to and accept connections from peers on the I2P anonymity network
+; (https://geti2p.net) via the SAM v3 bridge of an I2P router.  The private key
+; of the I2P destination is stored in the data directory so the same I2P address
+; is used across restarts.  NOTE: Accepting connections from I2P peers is
+; disabled when listening is disabled.
+; i2psam=127.0.0.1:7656
+
 ; Use Universal Plug and Play (UPnP) to automatically open the listen port
 ; and obtain the external IP address from supported devices.  NOTE: This option
 ; will have no effect if external IP addresses are specified.
 ; upnp=1
 
+; Use the NAT Port Mapping Protocol (NAT-PMP) or its successor, the Port Control
+; Protocol (PCP), to automatically open the listen port and obtain the external
+; IP address from the default gateway.  The mapping is renewed periodically and
+; changes to the external IP address are detected.  When both this and the
+; 'upnp' option are enabled, this is only used when no UPnP device is found.
+; NOTE: This option will have no effect if external IP addresses are specified.
+; natpmp=1
+
 ; Specify the external IP addresses your node is listening on.  One address per
 ; line.  dcrd will not contact 3rd-party sites to obtain external ip addresses.
 ; This means if you are behind NAT, your node will not be able to advertise a
-; reachable address unless you specify it here or enable the 'upnp' option (and
-; have a supported device).
+; reachable address unless you specify it here or enable the 'upnp' or 'natpmp'
+; option (and have a supported device).
 ; externalip=1.2.3.4
 ; externalip=2002::1234
 
@@ -119,6 +134,38 @@ const fileContents = `[Application Options]
 ; Maximum number of inbound and outbound peers.
 ; maxpeers=8
 
+; Encrypt connections with peers that support it in order to prevent passive
+; network observers from identifying the traffic and fingerprinting the origin
+; of transactions.  Unencrypted connections are still accepted from peers that
+; do not support it.
+; p2pencryption=1
+
+; Spread outbound peers across the autonomous systems (network operators) that
+; announce their addresses in addition to their /16 netgroups.  The file maps
+; IP prefixes to AS numbers with one prefix in CIDR notation followed by its AS
+; number per line (eg. 203.0.113.0/24 AS64496).  Lines starting with '#' are
+; ignored.
+; asmap=~/.dcrd/asmap.txt
+
+; Persist connections to up to two long-lived outbound peers (anchors) across
+; restarts and re-establish them before making any other outbound connections.
+; This makes it harder for an attacker that floods the address manager with
+; addresses it controls while the node is down to monopolize its outbound
+; connections.
+; eclipseresistance=1
+
+; Limit the rate at which data is uploaded to and downloaded from peers in
+; KiB/s, which is useful for nodes on metered or otherwise constrained links.
+; The combined rates limit the total across all peers while the inbound and
+; outbound rates limit each individual peer of that kind.  Whitelisted peers
+; are not limited.  A rate of 0, which is the default, disables the limit.
+; maxuploadrate=1024
+; maxdownloadrate=4096
+; maxinbounduploadrate=64
+; maxinbounddownloadrate=64
+; maxoutbounduploadrate=256
+; maxoutbounddownloadrate=1024
+
 ; Disable banning of misbehaving peers.
 ; nobanning=1
 
@@ -188,6 +235,14 @@ const fileContents = `[Application Options]
 ; rpcuser=whatever_username_you_want
 ; rpcpass=
 
+; Add RPC users that are only authorized for specific RPC methods and websocket
+; notification types in the form user:pass:methods:notifications.  Both lists
+; are comma-separated and may be empty, while * allows all methods or
+; notification types.  The entries in this file are reloaded without
+; restarting when dcrd receives SIGUSR1 on platforms that support it.
+; rpcauth=explorer:explorerpass:getblock,getblockhash,getbestblock:blocks
+; rpcauth=watcher:watcherpass::blocks,newtransactions
+
 ; Specify the interfaces for the RPC server listen on.  One listen address per
 ; line.  NOTE: The default port is modified by some options such as 'testnet',
 ; so it is recommended to not specify a port and allow a proper default to be
@@ -218,12 +273,93 @@ const fileContents = `[Application Options]
 ; All ipv6 interfaces on non-standard port 8337:
 ;   rpclisten=[::]:8337
 
+; Specify unix domain socket paths for the RPC server to listen on without TLS,
+; one path per line.  The sockets are only accessible by the user dcrd runs as
+; and connections on them do not require the RPC credentials, which makes them
+; well suited to co-located services such as wallets and indexers.  The RPC
+; server is enabled without credentials when only unix domain sockets are
+; specified.
+;   rpclistenunix=~/.dcrd/rpc.sock
+
 ; Specify the maximum number of concurrent RPC clients for standard connections.
 ; rpcmaxclients=10
 
 ; Specify the maximum number of concurrent RPC websocket clients.
 ; rpcmaxwebsockets=25
 
+; Limit the requests of each authenticated user and each remote IP so a single
+; misbehaving client is unable to starve the others.  The request rates are in
+; requests per second and allow short bursts, while the other limits are the
+; maximum number of requests that are processed concurrently.  Requests that
+; exceed a limit are rejected with a too many requests error.  A value of 0
+; disables the associated limit.
+; rpcuserreqrate=0
+; rpcipreqrate=0
+; rpcusermaxreqs=0
+; rpcipmaxreqs=0
+
+; Log RPC calls that take at least the specified duration along with the client
+; that issued them and their parameters.  Parameters that look like credentials
+; are redacted and long parameters are elided.  Valid time units are {s, m, h}.
+; A value of 0 disables the log.
+; rpcslowcall=0
+
+; Enable the REST interface on the RPC listeners.  It serves blocks, headers,
+; and unspent transaction outputs at /rest/block/<hash>.<format>,
+; /rest/headers/<count>/<hash>.<format>, and
+; /rest/getutxos[/checkmempool]/<txid>-<index>[-<tree>]/....<format> where the
+; format is one of bin, hex, or json.  REST requests do not require the RPC
+; credentials, so set resttoken to require clients to provide the token via a
+; bearer authorization header.
+; rest=1
+; resttoken=
+
+; Specify the interfaces for the gRPC server to listen on.  The gRPC server
+; uses the same users, including those added with rpcauth, and TLS settings as
+; the RPC server and is disabled unless at least one interface is specified.
+; Users are only authorized for a gRPC method when they are authorized for the
+; equivalent RPC method, such as sendrawtransaction for SubmitTransaction.  The
+; default port is 9112 for mainnet and 19112 for testnet.
+; Only ipv4 localhost on the default port:
+;   grpclisten=127.0.0.1
+; All interfaces on port 9112:
+;   grpclisten=:9112
+
+; Specify the interfaces to serve metrics on in the Prometheus text exposition
+; format via HTTP at /metrics.  The metrics include block validation timing,
+; mempool size and acceptance statistics, peer bandwidth, RPC latency, and
+; database statistics.  Interfaces other than loopback interfaces require
+; clients to authenticate with the metricsuser and metricspass credentials via
+; HTTP basic authentication.  Metrics are disabled unless at least one
+; interface is specified.  The default port is 9190.
+; Only ipv4 localhost on the default port:
+;   metricslisten=127.0.0.1
+; All interfaces on port 9190 with authentication:
+;   metricslisten=:9190
+;   metricsuser=whatever_metrics_username_you_want
+;   metricspass=
+
+; Specify the interfaces to serve diagnostics on via HTTP.  The diagnostics
+; include pprof profiles at /debug/pprof/, runtime metrics at /debug/runtime,
+; and goroutine dumps at /debug/goroutines.  They require the RPC admin
+; credentials (rpcuser and rpcpass), or a client certificate when authtype is
+; set to clientcert, and use the same TLS settings as the RPC server.
+; Diagnostics are disabled unless at least one interface is specified.  The
+; default port is 9191.
+; Only ipv4 localhost on the default port:
+;   diaglisten=127.0.0.1
+
+; Export traces of block and transaction processing to the OTLP/HTTP traces
+; endpoint of an OpenTelemetry collector.  Blocks are traced from the time they
+; are requested through validation, connection, and the handling of the
+; resulting notifications, and transactions are traced through acceptance to
+; the mempool and relay.  Tracing is disabled unless an endpoint is specified.
+; tracingendpoint=http://127.0.0.1:4318/v1/traces
+
+; The ratio of blocks and transactions that are traced when tracing is enabled.
+; The decision is made consistently per block and transaction.
+; tracingsampleratio=1
+
 ; Use the following setting to disable the RPC server even if the rpcuser and
 ; rpcpass are specified above.  This allows one to quickly disable the RPC
 ; server without having to remove credentials from the config file.
@@ -233,9 +369,24 @@ const fileContents = `[Application Options]
 ; Note that after changing this the rpccert/rpckey files need to be deleted so
 ; that they are recreated with the new curve.
 ;
-; Supported curves: P-521, P-256.
+; Supported curves: P-521, P-256, Ed25519.
 ; tlscurve=P-521
 
+; Specify the validity period of the generated TLS certificate for the rpc
+; endpoint.  Valid time units are {m, h}.  The default is 10 years.
+; tlscertlifetime=8760h
+
+; Automatically regenerate the TLS certificate for the rpc endpoint when it was
+; autogenerated and expires within the specified duration.  All connected
+; websocket clients are notified of the new certificate so they are able to
+; trust it when reconnecting.  Valid time units are {m, h}.  The default is 30
+; days.  A value of 0 disables automatic regeneration.
+; tlscertrenewal=720h
+
+; Specify additional DNS names or IP addresses to include as subject alternative
+; names when generating the TLS certificate for the rpc endpoint.
+; altdnsnames=dcrd.example.com,192.0.2.10
+
 
 ; ------------------------------------------------------------------------------
 ; Mempool Settings
@@ -247,9 +398,16 @@ const fileContents = `[Application Options]
 ; Limit orphan transaction pool to 100 transactions.
 ; maxorphantx=100
 
+; Limit the number of orphan transactions received from any single peer to 25.
+; maxorphantxperpeer=25
+
 ; Do not accept transactions from remote peers.
 ; blocksonly=1
 
+; Announce transactions to peers that support it via sketch-based set
+; reconciliation instead of flooding in order to reduce bandwidth usage.
+; txreconciliation=1
+
 ; Accept and relay non-standard transactions to the network regardless of the
 ; default network settings.
 ; acceptnonstd=1
@@ -300,6 +458,11 @@ const fileContents = `[Application Options]
 ; miningaddr=youraddress2
 ; miningaddr=youraddress3
 
+; Specify a file that contains the hex-encoded secp256k1 private key used to
+; sign generated blocks.  This is only valid on networks that require blocks to
+; be signed, such as custom networks that define a block signing challenge.
+; blocksignkeyfile=~/.dcrd/blocksign.key
+
 ; Specify the maximum block size in bytes to create.  This value will be limited
 ; to the consensus limit.
 ; blockmaxsize=375000
@@ -324,10 +487,38 @@ const fileContents = `[Application Options]
 ; Size of log file before it is rotated and compressed.
 ; logsize=10M
 
+; Interval at which the log file is rotated regardless of its size, such as 24h
+; for daily rotation.  Rotations are aligned to multiples of the interval in
+; UTC, so 24h rotates the log file at midnight UTC.  The default of 0 disables
+; time-based rotation.
+; logrotateinterval=0
+
+; Disable gzip compression of rotated log files.
+; nologcompression=false
+
+; Retention policy for rotated log files.  The oldest rotated log files are
+; removed once any of the following limits is exceeded.  A value of 0 disables
+; the respective limit.
+;
+; Maximum number of rotated log files to keep.
+; logmaxrolls=3
+;
+; Maximum age of rotated log files to keep, such as 720h for 30 days.
+; logmaxage=0
+;
+; Maximum total size of rotated log files to keep, such as 1G.
+; logmaxtotalsize=0
+
 ; Disable application file logging entirely and log exclusively to standard
-; output.
+; output.  This is useful for containerized deployments where the container
+; runtime collects and rotates the output.
 ; nofilelogging=false
 
+; Format of log output.  Valid formats are {text, json}.  The json format writes
+; each log entry as a JSON object on a single line along with fields such as the
+; subsystem and peer for consumption by log aggregation systems.
+; logformat=text
+
 ; Log verbosity.
 ; Valid levels are {trace, debug, info, warn, error, critical}
 ; You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set
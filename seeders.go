@@ -0,0 +1,238 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// seederStatsFilename is the name of the file in the data directory that
+	// houses the statistics used to score the seeders across restarts.
+	seederStatsFilename = "seeders.json"
+
+	// seederStatsDecay is the factor the statistics of a seeder are scaled by
+	// each time it is queried so that its recent behavior carries more weight
+	// than its behavior in the distant past.
+	seederStatsDecay = 0.9
+
+	// maxSeededAddrs is the maximum number of addresses returned by seeders
+	// that are tracked in order to attribute the outcome of connection
+	// attempts to them.
+	maxSeededAddrs = 5000
+)
+
+// seederStats houses statistics about the quality of a seeder which are used
+// to score it.  The values are decayed over time, so they are not integers.
+type seederStats struct {
+	// Queries and Failures are the number of times the seeder was queried and
+	// the number of those queries that failed or did not return any
+	// addresses, respectively.
+	Queries  float64 `json:"queries"`
+	Failures float64 `json:"failures"`
+
+	// Attempts and Connections are the number of connection attempts made to
+	// addresses returned by the seeder and the number of those attempts that
+	// resulted in a successful connection, respectively.
+	Attempts    float64 `json:"attempts"`
+	Connections float64 `json:"connections"`
+}
+
+// score returns the score of the seeder in the range (0, 1) where higher scores
+// indicate seeders that are more reliable and return higher quality addresses.
+// Seeders without any statistics have a neutral score of 0.25.
+func (s *seederStats) score() float64 {
+	reliability := (s.Queries - s.Failures + 1) / (s.Queries + 2)
+	quality := (s.Connections + 1) / (s.Attempts + 2)
+	return reliability * quality
+}
+
+// seededAddr houses the details of an address that was returned by a seeder.
+type seededAddr struct {
+	seeder    string
+	attempted bool
+}
+
+// seederScorer tracks the outcome of queries to seeders along with the outcome
+// of connection attempts to the addresses they return in order to score them
+// over time.
+type seederScorer struct {
+	mtx    sync.Mutex
+	stats  map[string]*seederStats
+	seeded map[string]*seededAddr
+}
+
+// newSeederScorer returns a new seeder scorer without any statistics.
+func newSeederScorer() *seederScorer {
+	return &seederScorer{
+		stats:  make(map[string]*seederStats),
+		seeded: make(map[string]*seededAddr),
+	}
+}
+
+// statsFor returns the statistics for the provided seeder, creating them
+// when needed.
+//
+// This function MUST be called with the mutex held (for writes).
+func (s *seederScorer) statsFor(seeder string) *seederStats {
+	stats, ok := s.stats[seeder]
+	if !ok {
+		stats = new(seederStats)
+		s.stats[seeder] = stats
+	}
+	return stats
+}
+
+// recordQuery records the outcome of a query to the provided seeder along with
+// the keys of the addresses it returned, if any.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) recordQuery(seeder string, addrKeys []string, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	stats := s.statsFor(seeder)
+	stats.Queries *= seederStatsDecay
+	stats.Failures *= seederStatsDecay
+	stats.Attempts *= seederStatsDecay
+	stats.Connections *= seederStatsDecay
+
+	stats.Queries++
+	if err != nil || len(addrKeys) == 0 {
+		stats.Failures++
+		return
+	}
+	for _, key := range addrKeys {
+		if len(s.seeded) >= maxSeededAddrs {
+			break
+		}
+		if _, ok := s.seeded[key]; !ok {
+			s.seeded[key] = &seededAddr{seeder: seeder}
+		}
+	}
+}
+
+// recordAttempt records a connection attempt to the address with the provided
+// key.  Only the first attempt to each address returned by a seeder counts
+// towards its score.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) recordAttempt(addrKey string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	addr, ok := s.seeded[addrKey]
+	if !ok || addr.attempted {
+		return
+	}
+	addr.attempted = true
+	s.statsFor(addr.seeder).Attempts++
+}
+
+// recordConnection records a successful connection to the address with the
+// provided key.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) recordConnection(addrKey string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	addr, ok := s.seeded[addrKey]
+	if !ok || !addr.attempted {
+		return
+	}
+	delete(s.seeded, addrKey)
+	s.statsFor(addr.seeder).Connections++
+}
+
+// rank returns the provided seeders ordered by descending score.  Seeders with
+// the same score retain their relative order.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) rank(seeders []string) []string {
+	s.mtx.Lock()
+	scores := make(map[string]float64, len(seeders))
+	for _, seeder := range seeders {
+		stats, ok := s.stats[seeder]
+		if !ok {
+			stats = new(seederStats)
+		}
+		scores[seeder] = stats.score()
+	}
+	s.mtx.Unlock()
+
+	ranked := make([]string, len(seeders))
+	copy(ranked, seeders)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+	return ranked
+}
+
+// load loads the seeder statistics persisted to the provided path.  Missing
+// or invalid files are ignored so that scoring starts over.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) load(path string) {
+	serialized, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			srvrLog.Warnf("Unable to read seeder stats: %v", err)
+		}
+		return
+	}
+	var stats map[string]*seederStats
+	if err := json.Unmarshal(serialized, &stats); err != nil {
+		srvrLog.Warnf("Unable to parse seeder stats: %v", err)
+		return
+	}
+
+	s.mtx.Lock()
+	for seeder, seederStats := range stats {
+		if seederStats != nil {
+			s.stats[seeder] = seederStats
+		}
+	}
+	s.mtx.Unlock()
+}
+
+// save persists the seeder statistics to the provided path.
+//
+// This function is safe for concurrent access.
+func (s *seederScorer) save(path string) error {
+	s.mtx.Lock()
+	serialized, err := json.Marshal(s.stats)
+	s.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, serialized, 0600)
+}
+
+// filterSeeders returns the seeders from the provided operator-specified list
+// that apply to the network with the provided name.  Seeders may be prefixed
+// with the name of a network followed by a colon to only use them on that
+// network.  Seeders without a prefix apply to all networks.
+func filterSeeders(seeders []string, netName string) []string {
+	var filtered []string
+	for _, seeder := range seeders {
+		if i := strings.Index(seeder, ":"); i != -1 {
+			switch prefix := seeder[:i]; prefix {
+			case netName:
+				seeder = seeder[i+1:]
+			case mainNetParams.Name, testNet3Params.Name, simNetParams.Name,
+				regNetParams.Name:
+				continue
+			}
+		}
+		filtered = append(filtered, seeder)
+	}
+	return filtered
+}
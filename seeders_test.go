@@ -0,0 +1,111 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSeederScorer ensures seeders are scored and ranked according to the
+// outcome of queries to them and connections to the addresses they return.
+func TestSeederScorer(t *testing.T) {
+	const good, bad, unreliable, unknown = "good", "bad", "unreliable", "unknown"
+	scorer := newSeederScorer()
+
+	// Ensure seeders without statistics retain their relative order.
+	seeders := []string{unknown, unreliable, bad, good}
+	if got := scorer.rank(seeders); !reflect.DeepEqual(got, seeders) {
+		t.Fatalf("unexpected initial ranking -- got %v, want %v", got,
+			seeders)
+	}
+
+	// Record queries that return addresses of differing quality along with
+	// a seeder that fails to respond.
+	scorer.recordQuery(good, []string{"1.1.1.1:9108", "1.1.1.2:9108"}, nil)
+	scorer.recordQuery(bad, []string{"2.2.2.1:9108", "2.2.2.2:9108"}, nil)
+	scorer.recordQuery(unreliable, nil, errors.New("timeout"))
+	for _, addr := range []string{"1.1.1.1:9108", "1.1.1.2:9108",
+		"2.2.2.1:9108", "2.2.2.2:9108"} {
+
+		scorer.recordAttempt(addr)
+		scorer.recordAttempt(addr)
+	}
+	scorer.recordConnection("1.1.1.1:9108")
+	scorer.recordConnection("1.1.1.2:9108")
+
+	// Ensure connections are only credited once and only for attempts.
+	scorer.recordConnection("1.1.1.1:9108")
+	scorer.recordConnection("3.3.3.3:9108")
+	if got := scorer.stats[good].Connections; got != 2 {
+		t.Fatalf("unexpected connections -- got %v, want 2", got)
+	}
+	if got := scorer.stats[bad].Attempts; got != 2 {
+		t.Fatalf("unexpected attempts -- got %v, want 2", got)
+	}
+
+	want := []string{good, unknown, unreliable, bad}
+	if got := scorer.rank(seeders); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected ranking -- got %v, want %v", got, want)
+	}
+
+	// Ensure the statistics survive a round trip through the file system and
+	// rank the seeders the same way.
+	path := filepath.Join(t.TempDir(), seederStatsFilename)
+	if err := scorer.save(path); err != nil {
+		t.Fatalf("unexpected error saving stats: %v", err)
+	}
+	loaded := newSeederScorer()
+	loaded.load(path)
+	if !reflect.DeepEqual(loaded.stats, scorer.stats) {
+		t.Fatalf("mismatched stats -- got %v, want %v", loaded.stats,
+			scorer.stats)
+	}
+	if got := loaded.rank(seeders); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected ranking after load -- got %v, want %v", got,
+			want)
+	}
+
+	// Ensure past failures decay as a seeder becomes reliable again.
+	before := scorer.stats[unreliable].score()
+	scorer.recordQuery(unreliable, []string{"4.4.4.4:9108"}, nil)
+	if after := scorer.stats[unreliable].score(); after <= before {
+		t.Fatalf("score did not improve -- before %v, after %v", before,
+			after)
+	}
+}
+
+// TestFilterSeeders ensures operator-specified seeders are filtered according
+// to their optional network prefix.
+func TestFilterSeeders(t *testing.T) {
+	seeders := []string{
+		"seed.example.com",
+		"seed.example.com:8443",
+		"mainnet:mainnet-seed.example.com",
+		"testnet3:testnet-seed.example.com:8443",
+	}
+
+	tests := []struct {
+		netName string
+		want    []string
+	}{{
+		netName: mainNetParams.Name,
+		want: []string{"seed.example.com", "seed.example.com:8443",
+			"mainnet-seed.example.com"},
+	}, {
+		netName: testNet3Params.Name,
+		want: []string{"seed.example.com", "seed.example.com:8443",
+			"testnet-seed.example.com:8443"},
+	}}
+	for _, test := range tests {
+		got := filterSeeders(seeders, test.netName)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected seeders -- got %v, want %v",
+				test.netName, got, test.want)
+		}
+	}
+}
@@ -7,18 +7,21 @@ package main
 
 import (
 	"context"
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net"
 	"os"
+	"os/signal"
 	"path"
+	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,26 +31,41 @@ import (
 	"github.com/decred/dcrd/addrmgr/v2"
 	"github.com/decred/dcrd/blockchain/stake/v5"
 	"github.com/decred/dcrd/blockchain/standalone/v2"
-	"github.com/decred/dcrd/certgen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/container/apbf"
+	"github.com/decred/dcrd/container/lru"
 	"github.com/decred/dcrd/database/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/internal/banmgr"
 	"github.com/decred/dcrd/internal/blockchain"
 	"github.com/decred/dcrd/internal/blockchain/indexers"
+	"github.com/decred/dcrd/internal/cmpctblock"
+	"github.com/decred/dcrd/internal/diagnostics"
 	"github.com/decred/dcrd/internal/fees"
+	"github.com/decred/dcrd/internal/grpcserver"
+	"github.com/decred/dcrd/internal/i2p"
+	"github.com/decred/dcrd/internal/logging"
 	"github.com/decred/dcrd/internal/mempool"
+	"github.com/decred/dcrd/internal/metrics"
 	"github.com/decred/dcrd/internal/mining"
 	"github.com/decred/dcrd/internal/mining/cpuminer"
 	"github.com/decred/dcrd/internal/netsync"
+	"github.com/decred/dcrd/internal/p2ptransport"
 	"github.com/decred/dcrd/internal/rpcserver"
+	"github.com/decred/dcrd/internal/tracing"
+	"github.com/decred/dcrd/internal/txrecon"
 	"github.com/decred/dcrd/internal/version"
 	"github.com/decred/dcrd/math/uint256"
 	"github.com/decred/dcrd/peer/v3"
 	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/sign"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/dcrd/wire"
+	"github.com/decred/slog"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
@@ -74,7 +92,7 @@ const (
 	connectionRetryInterval = time.Second * 5
 
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.RemoveRejectVersion
+	maxProtocolVersion = wire.I2PAddrVersion
 
 	// These fields are used to track known addresses on a per-peer basis.
 	//
@@ -124,6 +142,59 @@ const (
 	// These values result in about 183 KiB memory usage including overhead.
 	maxRecentlyConfirmedTxns    = 23000
 	recentlyConfirmedTxnsFPRate = 0.000001
+
+	// txReconInterval is the interval at which transaction reconciliation
+	// rounds are requested from outbound peers when transaction
+	// reconciliation is enabled.
+	txReconInterval = time.Second * 2
+
+	// i2pPrivKeyFilename is the name of the file in the data directory that
+	// houses the private key of the I2P destination.
+	i2pPrivKeyFilename = "i2p_private_key"
+
+	// banListFilename is the name of the file in the data directory that
+	// houses the persisted bans.
+	banListFilename = "banlist.json"
+
+	// anchorsFilename is the name of the file in the data directory that
+	// houses the addresses of the anchor connections persisted on shutdown
+	// when eclipse resistance is enabled.
+	anchorsFilename = "anchors.json"
+
+	// maxAnchors is the maximum number of outbound connections that are
+	// persisted as anchors on shutdown.
+	maxAnchors = 2
+
+	// minAnchorLifetime is the minimum amount of time an outbound peer must
+	// have been connected for to be considered for use as an anchor.
+	minAnchorLifetime = 10 * time.Minute
+
+	// natLeaseDuration is the requested lifetime of the port mapping for the
+	// listening port when NAT traversal is enabled.
+	natLeaseDuration = 20 * time.Minute
+
+	// natRenewInterval is the interval at which the port mapping is renewed
+	// and the external address is refreshed.  It must be less than the lease
+	// duration so the mapping does not lapse.
+	natRenewInterval = 15 * time.Minute
+
+	// maxAnnounceHeaders is the maximum number of headers pushed to peers
+	// that prefer headers when announcing a new block.  Announcements that
+	// would require more headers in order to connect to a block the peer is
+	// known to have, such as those after a deep reorg, fall back to an
+	// inventory announcement.
+	maxAnnounceHeaders = 8
+
+	// maxBlockTxnDepth is the maximum depth relative to the current best
+	// block of blocks whose transactions are served in response to getblocktxn
+	// messages.  Compact blocks are only relayed for blocks near the tip, so
+	// requests for older blocks are ignored in order to prevent peers from
+	// using them to cheaply force loading arbitrary historical blocks.
+	maxBlockTxnDepth = 10
+
+	// maxEncryptedReconnects is the maximum number of outbound connections
+	// that are queued to be re-established over the encrypted transport.
+	maxEncryptedReconnects = 8
 )
 
 var (
@@ -191,18 +262,22 @@ type relayMsg struct {
 
 // naSubmission represents a network address submission from an outbound peer.
 type naSubmission struct {
-	na           *wire.NetAddress
-	netType      addrmgr.NetAddressType
-	reach        addrmgr.NetAddressReach
-	score        uint32
-	lastAccessed int64
+	na      *wire.NetAddress
+	netType addrmgr.NetAddressType
+	reach   addrmgr.NetAddressReach
+	score   uint32
 }
 
-// naSubmissionCache represents a bounded map for network address submisions.
+// naSubmissionCache represents a bounded cache for network address submisions
+// that evicts the least-recently-used submission when the limit is reached.
 type naSubmissionCache struct {
-	cache map[string]*naSubmission
-	limit int
-	mtx   sync.Mutex
+	cache *lru.Map[string, *naSubmission]
+}
+
+// newNaSubmissionCache returns a new network address submission cache that is
+// limited to the provided maximum number of submissions.
+func newNaSubmissionCache(limit uint64) *naSubmissionCache {
+	return &naSubmissionCache{cache: lru.NewMap[string, *naSubmission](limit)}
 }
 
 // add caches the provided address submission.
@@ -216,31 +291,8 @@ func (sc *naSubmissionCache) add(sub *naSubmission) error {
 		return fmt.Errorf("submission key cannot be an empty string")
 	}
 
-	sc.mtx.Lock()
-	defer sc.mtx.Unlock()
-
-	// Remove the oldest submission if cache limit has been reached.
-	if len(sc.cache) == sc.limit {
-		var oldestSub *naSubmission
-		for _, sub := range sc.cache {
-			if oldestSub == nil {
-				oldestSub = sub
-				continue
-			}
-
-			if sub.lastAccessed < oldestSub.lastAccessed {
-				oldestSub = sub
-			}
-		}
-
-		if oldestSub != nil {
-			delete(sc.cache, oldestSub.na.IP.String())
-		}
-	}
-
 	sub.score = 1
-	sub.lastAccessed = time.Now().Unix()
-	sc.cache[key] = sub
+	sc.cache.Put(key, sub)
 	return nil
 }
 
@@ -250,9 +302,7 @@ func (sc *naSubmissionCache) exists(key string) bool {
 		return false
 	}
 
-	sc.mtx.Lock()
-	_, ok := sc.cache[key]
-	sc.mtx.Unlock()
+	_, ok := sc.cache.Peek(key)
 	return ok
 }
 
@@ -263,56 +313,55 @@ func (sc *naSubmissionCache) incrementScore(key string) error {
 		return fmt.Errorf("submission key cannot be an empty string")
 	}
 
-	sc.mtx.Lock()
-	defer sc.mtx.Unlock()
-
-	sub, ok := sc.cache[key]
+	sub, ok := sc.cache.Get(key)
 	if !ok {
 		return fmt.Errorf("submission key not found: %s", key)
 	}
 
-	sub.score++
-	sub.lastAccessed = time.Now().Unix()
-	sc.cache[key] = sub
+	// Replace the submission with an updated copy so submissions previously
+	// returned by bestSubmission are never modified.
+	updated := *sub
+	updated.score++
+	sc.cache.Put(key, &updated)
 	return nil
 }
 
 // bestSubmission fetches the best scoring submission of the provided
 // network interface.
 func (sc *naSubmissionCache) bestSubmission(net addrmgr.NetAddressType) *naSubmission {
-	sc.mtx.Lock()
-	defer sc.mtx.Unlock()
-
 	var best *naSubmission
-	for _, sub := range sc.cache {
+	sc.cache.Range(func(_ string, sub *naSubmission) bool {
 		if sub.netType != net {
-			continue
-		}
-
-		if best == nil {
-			best = sub
-			continue
+			return true
 		}
 
-		if sub.score > best.score {
+		if best == nil || sub.score > best.score {
 			best = sub
 		}
-	}
+		return true
+	})
 
 	return best
 }
 
 // peerState maintains state of inbound, persistent, outbound peers as well
-// as banned peers and outbound groups.
+// as outbound groups.
 type peerState struct {
 	inboundPeers    map[int32]*serverPeer
 	outboundPeers   map[int32]*serverPeer
 	persistentPeers map[int32]*serverPeer
-	banned          map[string]time.Time
 	outboundGroups  map[string]int
 	subCache        *naSubmissionCache
 }
 
+// adjustOutboundGroups adds the provided delta to the number of outbound peers
+// in each of the provided groups.
+func (ps *peerState) adjustOutboundGroups(keys []string, delta int) {
+	for _, key := range keys {
+		ps.outboundGroups[key] += delta
+	}
+}
+
 // ConnectionsWithIP returns the number of connections with the given IP.
 func (ps *peerState) ConnectionsWithIP(ip net.IP) int {
 	var total int
@@ -481,10 +530,25 @@ type server struct {
 
 	chainParams          *chaincfg.Params
 	addrManager          *addrmgr.AddrManager
+	banManager           *banmgr.Manager
+	anchors              map[string]struct{}
+	permanentPeerPerms   map[string]peerPermissions
+	seederScorer         *seederScorer
+	p2pMetrics           *p2pMetrics
+	nodeMetrics          *nodeMetrics
+	pendingAnchorsMtx    sync.Mutex
+	pendingAnchors       []net.Addr
+	encReconnectsMtx     sync.Mutex
+	encReconnects        []net.Addr
 	connManager          *connmgr.ConnManager
 	sigCache             *txscript.SigCache
 	subsidyCache         *standalone.SubsidyCache
 	rpcServer            *rpcserver.Server
+	grpcServer           *grpcserver.Server
+	metricsServer        *metrics.Server
+	diagServer           *diagnostics.Server
+	tracer               *tracing.Tracer
+	rpcCertMgr           *rpcCertManager
 	syncManager          *netsync.SyncManager
 	bg                   *mining.BgBlkTmplGenerator
 	chain                *blockchain.BlockChain
@@ -499,10 +563,11 @@ type server struct {
 	relayInv             chan relayMsg
 	broadcast            chan broadcastMsg
 	wg                   sync.WaitGroup
-	nat                  *upnpNAT
+	nat                  NAT
 	db                   database.DB
 	timeSource           blockchain.MedianTimeSource
 	services             wire.ServiceFlag
+	i2pSession           *i2p.Session
 	quit                 chan struct{}
 
 	// The following fields are used for optional indexes.  They will be nil
@@ -512,6 +577,8 @@ type server struct {
 	indexSubscriber *indexers.IndexSubscriber
 	txIndex         *indexers.TxIndex
 	existsAddrIndex *indexers.ExistsAddrIndex
+	addrIndex       *indexers.AddrIndex
+	spentIndex      *indexers.SpentIndex
 
 	// These following fields are used to filter duplicate block lottery data
 	// anouncements.
@@ -521,6 +588,19 @@ type server struct {
 	// recentlyConfirmedTxns tracks transactions that have been confirmed in the
 	// most recent blocks.
 	recentlyConfirmedTxns *apbf.Filter
+
+	// uploadLimiter and downloadLimiter limit the combined rate at which data
+	// is sent to and received from all peers that are not whitelisted.  They
+	// are nil when the respective limit is disabled.
+	uploadLimiter   *peer.RateLimiter
+	downloadLimiter *peer.RateLimiter
+
+	// bytesPerMsgMtx protects the following fields which track the total
+	// number of bytes sent to and received from all peers since start keyed
+	// by message command.
+	bytesPerMsgMtx  sync.Mutex
+	bytesSentPerMsg map[string]uint64
+	bytesRecvPerMsg map[string]uint64
 }
 
 // serverPeer extends the peer to maintain state shared by the server.
@@ -533,11 +613,19 @@ type serverPeer struct {
 	continueHash   *chainhash.Hash
 	relayMtx       sync.Mutex
 	disableRelayTx bool
-	isWhitelisted  bool
+	permissions    peerPermissions
+	isAnchor       bool
+	encrypted      bool
 	knownAddresses *apbf.Filter
-	banScore       connmgr.DynamicBanScore
+	banScore       banmgr.Scorer
 	quit           chan struct{}
 
+	// netAddr is the address manager network address of the remote peer when
+	// it is not an IP address, such as TORv3 and I2P addresses, since those
+	// addresses can't be represented by the wire network address tracked by
+	// the peer.  It is only set when the peer is created.
+	netAddr *addrmgr.NetAddress
+
 	// addrsSent, getMiningStateSent and initState all track whether or not
 	// the peer has already sent the respective request.  It is used to
 	// prevent more than one response per connection.
@@ -559,6 +647,25 @@ type serverPeer struct {
 	// announcedBlock tracks the most recent block announced to this peer and is
 	// used to filter duplicates.
 	announcedBlock *chainhash.Hash
+
+	// txReconSalt is the salt the local peer contributed to transaction
+	// reconciliation with the peer.  It is zero when reconciliation was not
+	// offered to the peer.
+	//
+	// txRecon houses the state of transaction reconciliation with the peer
+	// once both peers have offered it.  It is protected by the relay mutex.
+	txReconSalt uint64
+	txRecon     *txrecon.PeerState
+
+	// cmpctBlocks tracks whether or not the peer requested new blocks to be
+	// announced via compact blocks.  It is protected by the relay mutex.
+	cmpctBlocks bool
+
+	// feeFilter is the minimum fee rate in atoms/kB of transactions the peer
+	// requested to be announced to it via a feefilter message.  It is zero
+	// when the peer has not requested any filtering.  It is protected by the
+	// relay mutex.
+	feeFilter int64
 }
 
 // newServerPeer returns a new serverPeer instance. The peer needs to be set by
@@ -568,6 +675,7 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 		server:         s,
 		persistent:     isPersistent,
 		knownAddresses: apbf.NewFilter(maxKnownAddrsPerPeer, knownAddrsFPRate),
+		banScore:       s.banManager.NewScorer(),
 		quit:           make(chan struct{}),
 		txProcessed:    make(chan struct{}, 1),
 		blockProcessed: make(chan struct{}, 1),
@@ -619,6 +727,89 @@ func (sp *serverPeer) relayTxDisabled() bool {
 	return isDisabled
 }
 
+// setFeeFilter sets the minimum fee rate in atoms/kB of transactions to
+// announce to the peer.
+// It is safe for concurrent access.
+func (sp *serverPeer) setFeeFilter(minFee int64) {
+	sp.relayMtx.Lock()
+	sp.feeFilter = minFee
+	sp.relayMtx.Unlock()
+}
+
+// feeFilterRate returns the minimum fee rate in atoms/kB of transactions to
+// announce to the peer.  It is zero when the peer has not requested any
+// filtering.
+// It is safe for concurrent access.
+func (sp *serverPeer) feeFilterRate() int64 {
+	sp.relayMtx.Lock()
+	minFee := sp.feeFilter
+	sp.relayMtx.Unlock()
+
+	return minFee
+}
+
+// passesFeeFilter returns whether or not the provided transaction descriptor
+// has a fee rate that is at least the minimum requested by the peer.
+// It is safe for concurrent access.
+func (sp *serverPeer) passesFeeFilter(txDesc *mempool.TxDesc) bool {
+	minFee := sp.feeFilterRate()
+	return minFee == 0 || txFeeRate(txDesc) >= minFee
+}
+
+// txFeeRate returns the fee rate in atoms/kB of the provided transaction
+// descriptor.
+func txFeeRate(txDesc *mempool.TxDesc) int64 {
+	if txDesc.TxSize <= 0 {
+		return 0
+	}
+	return txDesc.Fee * 1000 / txDesc.TxSize
+}
+
+// txReconState returns the state of transaction reconciliation with the peer
+// or nil when reconciliation is not in use with the peer.
+// It is safe for concurrent access.
+func (sp *serverPeer) txReconState() *txrecon.PeerState {
+	sp.relayMtx.Lock()
+	state := sp.txRecon
+	sp.relayMtx.Unlock()
+
+	return state
+}
+
+// wantsCmpctBlocks returns whether or not the peer requested new blocks to be
+// announced via compact blocks.
+// It is safe for concurrent access.
+func (sp *serverPeer) wantsCmpctBlocks() bool {
+	sp.relayMtx.Lock()
+	wantsCmpctBlocks := sp.cmpctBlocks
+	sp.relayMtx.Unlock()
+
+	return wantsCmpctBlocks
+}
+
+// withFields returns a logger derived from the provided subsystem logger that
+// includes the address and direction of the peer in all of its records.
+func (sp *serverPeer) withFields(log slog.Logger) slog.Logger {
+	return logging.With(log, "peer", sp.String(), "direction",
+		directionString(sp.Inbound()))
+}
+
+// remoteNetAddress returns the address manager network address of the remote
+// peer.
+func (sp *serverPeer) remoteNetAddress() *addrmgr.NetAddress {
+	na := sp.NA()
+	if sp.netAddr == nil || na == nil {
+		return wireToAddrmgrNetAddress(na)
+	}
+
+	// Use the services and timestamp tracked by the peer since they are
+	// updated once the version message is received.
+	netAddr := sp.netAddr.Clone()
+	netAddr.Services = na.Services
+	netAddr.Timestamp = na.Timestamp
+	return netAddr
+}
+
 // wireToAddrmgrNetAddress converts a wire NetAddress to an address manager
 // NetAddress.
 func wireToAddrmgrNetAddress(netAddr *wire.NetAddress) *addrmgr.NetAddress {
@@ -644,12 +835,64 @@ func addrmgrToWireNetAddress(netAddr *addrmgr.NetAddress) *wire.NetAddress {
 		netAddr.IP, netAddr.Port)
 }
 
+// wireToAddrmgrNetAddressV2 converts a wire version 2 net address to an
+// address manager net address.  The encoded address of all supported types is
+// stored directly as the IP of the address manager net address.
+func wireToAddrmgrNetAddressV2(netAddr *wire.NetAddressV2) *addrmgr.NetAddress {
+	var addrType addrmgr.NetAddressType
+	switch netAddr.Type {
+	case wire.TORv3Address:
+		addrType = addrmgr.TORv3Address
+	case wire.I2PAddress:
+		addrType = addrmgr.I2PAddress
+	default:
+		newNetAddr := addrmgr.NewNetAddressIPPort(net.IP(netAddr.EncodedAddr),
+			netAddr.Port, netAddr.Services)
+		newNetAddr.Timestamp = netAddr.Timestamp
+		return newNetAddr
+	}
+	return &addrmgr.NetAddress{
+		IP:        netAddr.EncodedAddr,
+		Port:      netAddr.Port,
+		Timestamp: netAddr.Timestamp,
+		Services:  netAddr.Services,
+		Type:      addrType,
+	}
+}
+
+// addrmgrToWireNetAddressV2 converts an address manager net address to a wire
+// version 2 net address.  TORv2 addresses are converted to IPv6 addresses
+// since they are encoded in the OnionCat range.
+func addrmgrToWireNetAddressV2(netAddr *addrmgr.NetAddress) *wire.NetAddressV2 {
+	ip := net.IP(netAddr.IP)
+	addrType, encodedAddr := wire.IPv6Address, []byte(ip.To16())
+	switch {
+	case netAddr.Type == addrmgr.TORv3Address:
+		addrType, encodedAddr = wire.TORv3Address, netAddr.IP
+	case netAddr.Type == addrmgr.I2PAddress:
+		addrType, encodedAddr = wire.I2PAddress, netAddr.IP
+	case ip.To4() != nil:
+		addrType, encodedAddr = wire.IPv4Address, ip.To4()
+	}
+	return wire.NewNetAddressV2(addrType, encodedAddr, netAddr.Port,
+		netAddr.Timestamp, netAddr.Services)
+}
+
 // pushAddrMsg sends an addr message to the connected peer using the provided
-// addresses.
+// addresses.  An addrv2 message is sent instead when the peer supports it.
 func (sp *serverPeer) pushAddrMsg(addresses []*addrmgr.NetAddress) {
-	// Filter addresses already known to the peer.
+	if sp.ProtocolVersion() >= wire.AddrV2Version {
+		sp.pushAddrV2Msg(addresses)
+		return
+	}
+
+	// Filter addresses already known to the peer as well as TORv3 and I2P
+	// addresses since they can't be encoded in addr messages.
 	addrs := make([]*wire.NetAddress, 0, len(addresses))
 	for _, addr := range addresses {
+		if addr.Type == addrmgr.TORv3Address || addr.Type == addrmgr.I2PAddress {
+			continue
+		}
 		if !sp.addressKnown(addr) {
 			wireNetAddr := addrmgrToWireNetAddress(addr)
 			addrs = append(addrs, wireNetAddr)
@@ -666,6 +909,35 @@ func (sp *serverPeer) pushAddrMsg(addresses []*addrmgr.NetAddress) {
 	sp.addKnownAddresses(knownNetAddrs)
 }
 
+// pushAddrV2Msg sends an addrv2 message to the connected peer using the
+// provided addresses.
+func (sp *serverPeer) pushAddrV2Msg(addresses []*addrmgr.NetAddress) {
+	// Filter addresses already known to the peer as well as I2P addresses
+	// when the peer does not support them.
+	supportsI2P := sp.ProtocolVersion() >= wire.I2PAddrVersion
+	addrs := make([]*wire.NetAddressV2, 0, len(addresses))
+	for _, addr := range addresses {
+		if addr.Type == addrmgr.I2PAddress && !supportsI2P {
+			continue
+		}
+		if !sp.addressKnown(addr) {
+			addrs = append(addrs, addrmgrToWireNetAddressV2(addr))
+		}
+	}
+	known, err := sp.PushAddrV2Msg(addrs)
+	if err != nil {
+		peerLog.Errorf("Can't push address message to %s: %v", sp.Peer, err)
+		sp.Disconnect()
+		return
+	}
+
+	knownNetAddrs := make([]*addrmgr.NetAddress, 0, len(known))
+	for _, na := range known {
+		knownNetAddrs = append(knownNetAddrs, wireToAddrmgrNetAddressV2(na))
+	}
+	sp.addKnownAddresses(knownNetAddrs)
+}
+
 // addBanScore increases the persistent and decaying ban score fields by the
 // values passed as parameters. If the resulting score exceeds half of the ban
 // threshold, a warning is logged including the reason provided. Further, if
@@ -676,7 +948,7 @@ func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) b
 	if cfg.DisableBanning {
 		return false
 	}
-	if sp.isWhitelisted {
+	if sp.permissions.has(permNoBan) {
 		peerLog.Debugf("Misbehaving whitelisted peer %s: %s", sp, reason)
 		return false
 	}
@@ -687,17 +959,19 @@ func (sp *serverPeer) addBanScore(persistent, transient uint32, reason string) b
 		// logged if the score is above the warn threshold.
 		score := sp.banScore.Int()
 		if score > warnThreshold {
-			peerLog.Warnf("Misbehaving peer %s: %s -- ban score is %d, "+
-				"it was not increased this time", sp, reason, score)
+			sp.withFields(peerLog).Warnf("Misbehaving peer %s: %s -- ban "+
+				"score is %d, it was not increased this time", sp, reason,
+				score)
 		}
 		return false
 	}
 	score := sp.banScore.Increase(persistent, transient)
 	if score > warnThreshold {
-		peerLog.Warnf("Misbehaving peer %s: %s -- ban score increased to %d",
+		log := sp.withFields(peerLog)
+		log.Warnf("Misbehaving peer %s: %s -- ban score increased to %d",
 			sp, reason, score)
 		if score > cfg.BanThreshold {
-			peerLog.Warnf("Misbehaving peer %s -- banning and disconnecting",
+			log.Warnf("Misbehaving peer %s -- banning and disconnecting",
 				sp)
 			sp.server.BanPeer(sp)
 			sp.Disconnect()
@@ -713,6 +987,15 @@ func hasServices(advertised, desired wire.ServiceFlag) bool {
 	return advertised&desired == desired
 }
 
+// encryptionDowngraded returns whether or not a connection in the provided
+// direction to a peer that advertised the provided services was established
+// without the encrypted transport even though the local node initiated it and
+// the peer supports it.
+func encryptionDowngraded(encrypted, inbound bool, advertised wire.ServiceFlag) bool {
+	return !encrypted && !inbound &&
+		hasServices(advertised, wire.SFNodeEncryption)
+}
+
 // OnVersion is invoked when a peer receives a version wire message and is used
 // to negotiate the protocol version details as well as kick start the
 // communications.
@@ -728,7 +1011,7 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 	// it is updated regardless in the case a new minimum protocol version is
 	// enforced and the remote node has not upgraded yet.
 	isInbound := sp.Inbound()
-	remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+	remoteAddr := sp.remoteNetAddress()
 	addrManager := sp.server.addrManager
 	if !cfg.SimNet && !cfg.RegNet && !isInbound {
 		err := addrManager.SetServices(remoteAddr, msg.Services)
@@ -742,10 +1025,57 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 		srvrLog.Debugf("Rejecting peer %s with protocol version %d prior to "+
 			"the required version %d", sp.Peer, msg.ProtocolVersion,
 			wire.SendHeadersVersion)
+		sp.server.p2pMetrics.handshakeFailed(isInbound,
+			handshakeObsoleteProtocol)
+		sp.Disconnect()
+		return
+	}
+
+	// Reject peers that negotiated the encrypted transport without
+	// advertising support for it since that indicates the connection was
+	// tampered with.
+	supportsEncryption := hasServices(msg.Services, wire.SFNodeEncryption)
+	if sp.encrypted && !supportsEncryption {
+		srvrLog.Debugf("Rejecting encrypted peer %s that does not advertise "+
+			"the encryption service", sp.Peer)
+		sp.server.p2pMetrics.handshakeFailed(isInbound,
+			handshakeEncryptionMissing)
 		sp.Disconnect()
 		return
 	}
 
+	// Disconnect outbound peers that advertise support for the encrypted
+	// transport when the connection was not encrypted and reconnect to them
+	// over it.  That means the address manager did not know the peer supports
+	// it, which could be the result of an attacker stripping the service flag
+	// from address relay in order to observe or tamper with the connection.
+	//
+	// The services of the address were updated above, so the new connection
+	// is encrypted.  Connections are kept when the address manager still does
+	// not know the peer supports encryption, such as on the test networks that
+	// do not update it, since the new connection would not be encrypted
+	// either.  Permanent peers are reconnected by the connection manager.
+	if cfg.P2PEncryption && encryptionDowngraded(sp.encrypted, isInbound,
+		msg.Services) {
+
+		services, err := addrManager.Services(remoteAddr)
+		if err != nil || !hasServices(services, wire.SFNodeEncryption) {
+			srvrLog.Debugf("Unencrypted connection to peer %s which "+
+				"supports encryption", sp.Peer)
+		} else {
+			srvrLog.Debugf("Disconnecting unencrypted peer %s which "+
+				"supports encryption to reconnect over the encrypted "+
+				"transport", sp.Peer)
+			sp.server.p2pMetrics.handshakeFailed(isInbound,
+				handshakeEncryptionDowngraded)
+			if sp.connReq != nil && !sp.connReq.Permanent {
+				sp.server.queueEncryptedReconnect(sp.connReq.Addr)
+			}
+			sp.Disconnect()
+			return
+		}
+	}
+
 	// Reject outbound peers that are not full nodes.
 	wantServices := wire.SFNodeNetwork
 	if !isInbound && !hasServices(msg.Services, wantServices) {
@@ -753,6 +1083,8 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 		srvrLog.Debugf("Rejecting peer %s with services %v due to not "+
 			"providing desired services %v", sp.Peer, msg.Services,
 			missingServices)
+		sp.server.p2pMetrics.handshakeFailed(isInbound,
+			handshakeMissingServices)
 		sp.Disconnect()
 		return
 	}
@@ -787,6 +1119,9 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 		if err != nil {
 			srvrLog.Errorf("Marking address as good failed: %v", err)
 		}
+
+		// Credit the seeder that returned the address, if any.
+		sp.server.seederScorer.recordConnection(remoteAddr.Key())
 	}
 
 	sp.peerNaMtx.Lock()
@@ -806,20 +1141,265 @@ func (sp *serverPeer) OnVersion(_ *peer.Peer, msg *wire.MsgVersion) {
 
 // OnVerAck is invoked when a peer receives a verack wire message.  It creates
 // and sends a sendheaders message to request all block annoucements are made
-// via full headers instead of the inv message.
+// via full headers instead of the inv message.  It also requests block
+// announcements are made via compact blocks when the peer supports them.
 func (sp *serverPeer) OnVerAck(_ *peer.Peer, msg *wire.MsgVerAck) {
 	sp.QueueMessage(wire.NewMsgSendHeaders(), nil)
+	if sp.ProtocolVersion() >= wire.CompactBlockVersion {
+		sp.QueueMessage(wire.NewMsgSendCmpct(true, cmpctblock.Version), nil)
+	}
+
+	// Request the peer to not announce transactions with a fee rate below
+	// the minimum relay fee since they would be rejected anyway.  There is
+	// no need when transactions are not accepted at all since relay is
+	// disabled via the version message in that case.
+	if sp.ProtocolVersion() >= wire.FeeFilterVersion && !cfg.BlocksOnly {
+		minFee := int64(cfg.minRelayTxFee)
+		sp.QueueMessage(wire.NewMsgFeeFilter(minFee), nil)
+	}
+
+	// Offer transaction reconciliation when it is enabled, the peer supports
+	// it, and the peer has not disabled transaction relay.
+	if !hasServices(sp.server.services, wire.SFNodeTxRecon) ||
+		!hasServices(sp.Services(), wire.SFNodeTxRecon) ||
+		sp.ProtocolVersion() < wire.TxReconciliationVersion ||
+		sp.relayTxDisabled() {
+
+		return
+	}
+	salt, err := wire.RandomUint64()
+	if err != nil {
+		srvrLog.Errorf("Unable to generate transaction reconciliation salt: %v",
+			err)
+		return
+	}
+	sp.txReconSalt = salt
+	sp.QueueMessage(wire.NewMsgSendTxRecon(txrecon.Version, salt), nil)
+}
+
+// OnSendTxRecon is invoked when a peer receives a sendtxrcncl wire message.  It
+// enables transaction reconciliation with the peer when the local peer also
+// offered it.
+func (sp *serverPeer) OnSendTxRecon(_ *peer.Peer, msg *wire.MsgSendTxRecon) {
+	if sp.txReconSalt == 0 || sp.txReconState() != nil ||
+		msg.Version < txrecon.Version {
+
+		peerLog.Debugf("Ignoring unexpected %s message from %v", msg.Command(),
+			sp)
+		return
+	}
+
+	// The peer that made the outbound connection initiates reconciliation
+	// rounds.
+	state := txrecon.NewPeerState(sp.txReconSalt, msg.Salt, !sp.Inbound())
+	sp.relayMtx.Lock()
+	sp.txRecon = state
+	sp.relayMtx.Unlock()
+	peerLog.Debugf("Enabled transaction reconciliation with %v", sp)
+}
+
+// handleTxReconError increases the ban score of the peer for sending a
+// transaction reconciliation message that is either malformed or unexpected
+// for the current state of reconciliation with it.
+func (sp *serverPeer) handleTxReconError(cmd string, err error) {
+	peerLog.Debugf("Invalid %s message from %v: %v", cmd, sp, err)
+	if errors.Is(err, txrecon.ErrMalformedSketch) {
+		sp.addBanScore(100, 0, cmd)
+		sp.Disconnect()
+		return
+	}
+	sp.addBanScore(0, 10, cmd)
+}
+
+// announceReconciledTxns announces the provided transactions that are still in
+// the main pool to the peer via inventory messages.
+func (sp *serverPeer) announceReconciledTxns(hashes []chainhash.Hash) {
+	txMemPool := sp.server.txMemPool
+	for i := range hashes {
+		txDesc, ok := txMemPool.TxDesc(&hashes[i])
+		if !ok || !sp.passesFeeFilter(txDesc) {
+			continue
+		}
+		sp.QueueInventory(wire.NewInvVect(wire.InvTypeTx, &hashes[i]))
+	}
+}
+
+// OnReqRecon is invoked when a peer receives a reqrecon wire message.  It
+// responds with a sketch of the transactions to announce to the peer.
+func (sp *serverPeer) OnReqRecon(_ *peer.Peer, msg *wire.MsgReqRecon) {
+	state := sp.txReconState()
+	if state == nil {
+		sp.handleTxReconError(msg.Command(), txrecon.ErrUnexpectedMessage)
+		return
+	}
+
+	sketchMsg, err := state.RespondToReqRecon(msg)
+	if err != nil {
+		sp.handleTxReconError(msg.Command(), err)
+		return
+	}
+	sp.QueueMessage(sketchMsg, nil)
+}
+
+// OnSketch is invoked when a peer receives a sketch wire message.  It
+// reconciles the sketch with the transactions to announce to the peer,
+// announces the transactions the peer is missing, and requests the
+// transactions the local peer is missing.
+func (sp *serverPeer) OnSketch(_ *peer.Peer, msg *wire.MsgSketch) {
+	state := sp.txReconState()
+	if state == nil {
+		sp.handleTxReconError(msg.Command(), txrecon.ErrUnexpectedMessage)
+		return
+	}
+
+	diffMsg, announce, err := state.ProcessSketch(msg)
+	if err != nil {
+		sp.handleTxReconError(msg.Command(), err)
+		return
+	}
+	if !diffMsg.Success {
+		peerLog.Debugf("Transaction reconciliation with %v failed -- "+
+			"announcing %d transactions", sp, len(announce))
+	}
+	sp.QueueMessage(diffMsg, nil)
+	sp.announceReconciledTxns(announce)
+}
+
+// OnReconcilDiff is invoked when a peer receives a reconcildiff wire message.
+// It announces the transactions requested by the peer or, when reconciliation
+// failed, all of the transactions included in the sketch sent to the peer.
+func (sp *serverPeer) OnReconcilDiff(_ *peer.Peer, msg *wire.MsgReconcilDiff) {
+	state := sp.txReconState()
+	if state == nil {
+		sp.handleTxReconError(msg.Command(), txrecon.ErrUnexpectedMessage)
+		return
+	}
+
+	announce, err := state.ProcessReconcilDiff(msg)
+	if err != nil {
+		sp.handleTxReconError(msg.Command(), err)
+		return
+	}
+	sp.announceReconciledTxns(announce)
+}
+
+// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.  It
+// configures whether or not new blocks are announced to the peer via compact
+// blocks.
+func (sp *serverPeer) OnSendCmpct(_ *peer.Peer, msg *wire.MsgSendCmpct) {
+	if msg.Version < cmpctblock.Version {
+		peerLog.Debugf("Ignoring %s message with unsupported version %d from "+
+			"%v", msg.Command(), msg.Version, sp)
+		return
+	}
+
+	sp.relayMtx.Lock()
+	sp.cmpctBlocks = msg.Announce
+	sp.relayMtx.Unlock()
+	peerLog.Debugf("Peer %v requested compact block announcements: %v", sp,
+		msg.Announce)
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock wire message.  It
+// blocks until the block announced via the compact block has either been
+// processed or its missing transactions have been requested.
+func (sp *serverPeer) OnCmpctBlock(_ *peer.Peer, msg *wire.MsgCmpctBlock) {
+	// Add the block to the known inventory for the peer.
+	blockHash := msg.Header.BlockHash()
+	iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+	sp.AddKnownInventory(iv)
+
+	sp.server.syncManager.QueueCmpctBlock(msg, sp.Peer, sp.blockProcessed)
+	<-sp.blockProcessed
+}
+
+// selectBlockTxns returns the transactions at the provided indexes of the
+// passed transaction tree.  It returns false when the indexes are not in
+// ascending order or are out of range.
+func selectBlockTxns(txns []*wire.MsgTx, indexes []uint32) ([]*wire.MsgTx, bool) {
+	selected := make([]*wire.MsgTx, 0, len(indexes))
+	for i, index := range indexes {
+		if int(index) >= len(txns) || (i > 0 && index <= indexes[i-1]) {
+			return nil, false
+		}
+		selected = append(selected, txns[index])
+	}
+	return selected, true
+}
+
+// OnGetBlockTxn is invoked when a peer receives a getblocktxn wire message.  It
+// sends the requested transactions of the block to the peer via a blocktxn
+// message when the block is within the maximum depth of the current best
+// block.
+func (sp *serverPeer) OnGetBlockTxn(_ *peer.Peer, msg *wire.MsgGetBlockTxn) {
+	chain := sp.server.chain
+	header, err := chain.HeaderByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch header %v requested via %s by %v: %v",
+			msg.BlockHash, msg.Command(), sp, err)
+		return
+	}
+	bestHeight := chain.BestSnapshot().Height
+	if int64(header.Height)+maxBlockTxnDepth < bestHeight {
+		peerLog.Debugf("Ignoring %s request from %v for block %v at height "+
+			"%d which is too deep (best height %d)", msg.Command(), sp,
+			msg.BlockHash, header.Height, bestHeight)
+		return
+	}
+
+	block, err := chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch block %v requested via %s by %v: %v",
+			msg.BlockHash, msg.Command(), sp, err)
+		return
+	}
+
+	msgBlock := block.MsgBlock()
+	blockTxn := wire.NewMsgBlockTxn(&msg.BlockHash)
+	var ok bool
+	blockTxn.Transactions, ok = selectBlockTxns(msgBlock.Transactions,
+		msg.TxIndexes)
+	if ok {
+		blockTxn.STransactions, ok = selectBlockTxns(msgBlock.STransactions,
+			msg.STxIndexes)
+	}
+	if !ok {
+		peerLog.Debugf("Invalid transaction indexes for block %v requested "+
+			"via %s by %v", msg.BlockHash, msg.Command(), sp)
+		sp.addBanScore(0, 10, msg.Command())
+		return
+	}
+	sp.QueueMessage(blockTxn, nil)
+}
+
+// OnBlockTxn is invoked when a peer receives a blocktxn wire message.  It
+// blocks until the block the transactions complete has been fully processed.
+func (sp *serverPeer) OnBlockTxn(_ *peer.Peer, msg *wire.MsgBlockTxn) {
+	sp.server.syncManager.QueueBlockTxn(msg, sp.Peer, sp.blockProcessed)
+	<-sp.blockProcessed
 }
 
 // OnMemPool is invoked when a peer receives a mempool wire message.  It creates
 // and sends an inventory message with the contents of the memory pool up to the
 // maximum inventory allowed per message.
 func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
+	// Ignore mempool requests in blocks-only mode unless the peer has the
+	// mempool permission since the mempool only contains transactions that
+	// were submitted locally in that case and revealing them would allow the
+	// peer to determine their origin.
+	canRequestMempool := sp.permissions.has(permMempool)
+	if cfg.BlocksOnly && !canRequestMempool {
+		peerLog.Tracef("Ignoring mempool request from %v - blocksonly "+
+			"enabled", sp)
+		return
+	}
+
 	// A decaying ban score increase is applied to prevent flooding.
 	// The ban score accumulates and passes the ban threshold if a burst of
 	// mempool messages comes from a peer. The score decays each minute to
-	// half of its value.
-	if sp.addBanScore(0, 33, "mempool") {
+	// half of its value.  Peers with the mempool permission may request the
+	// mempool as often as they like.
+	if !canRequestMempool && sp.addBanScore(0, 33, "mempool") {
 		return
 	}
 
@@ -831,8 +1411,12 @@ func (sp *serverPeer) OnMemPool(_ *peer.Peer, msg *wire.MsgMemPool) {
 	txMemPool := sp.server.txMemPool
 	txDescs := txMemPool.TxDescs()
 
-	// Send the inventory message if there is anything to send.
+	// Send the inventory message if there is anything to send.  Transactions
+	// with a fee rate below the minimum requested by the peer are skipped.
 	for _, txDesc := range txDescs {
+		if !sp.passesFeeFilter(txDesc) {
+			continue
+		}
 		iv := wire.NewInvVect(wire.InvTypeTx, txDesc.Tx.Hash())
 		sp.QueueInventory(iv)
 	}
@@ -1059,7 +1643,9 @@ func (sp *serverPeer) OnInitState(_ *peer.Peer, msg *wire.MsgInitState) {
 // serialize all transactions through a single thread transactions don't rely on
 // the previous one in a linear fashion like blocks.
 func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
-	if cfg.BlocksOnly {
+	// Ignore transactions in blocks-only mode unless the peer has the
+	// forcerelay permission.
+	if cfg.BlocksOnly && !sp.permissions.has(permForceRelay) {
 		peerLog.Tracef("Ignoring tx %v from %v - blocksonly enabled",
 			msg.TxHash(), sp)
 		return
@@ -1071,6 +1657,9 @@ func (sp *serverPeer) OnTx(_ *peer.Peer, msg *wire.MsgTx) {
 	tx := dcrutil.NewTx(msg)
 	iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 	sp.AddKnownInventory(iv)
+	if txRecon := sp.txReconState(); txRecon != nil {
+		txRecon.RemoveTx(tx.Hash())
+	}
 
 	// Queue the transaction up to be handled by the net sync manager and
 	// intentionally block further receives until the transaction is fully
@@ -1115,6 +1704,16 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 		return
 	}
 
+	// There is no need to reconcile transactions the peer announces since it
+	// already has them.
+	if txRecon := sp.txReconState(); txRecon != nil {
+		for _, invVect := range msg.InvList {
+			if invVect.Type == wire.InvTypeTx {
+				txRecon.RemoveTx(&invVect.Hash)
+			}
+		}
+	}
+
 	if !cfg.BlocksOnly {
 		sp.server.syncManager.QueueInv(msg, sp.Peer)
 		return
@@ -1123,6 +1722,12 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 	newInv := wire.NewMsgInvSizeHint(uint(len(msg.InvList)))
 	for _, invVect := range msg.InvList {
 		if invVect.Type == wire.InvTypeTx {
+			// Transactions are never requested in blocks-only mode, so peers
+			// with the forcerelay permission must send them directly instead
+			// of announcing them.  Ignore their announcements.
+			if sp.permissions.has(permForceRelay) {
+				continue
+			}
 			peerLog.Infof("Peer %v is announcing transactions -- disconnecting",
 				sp)
 			sp.Disconnect()
@@ -1141,6 +1746,13 @@ func (sp *serverPeer) OnInv(_ *peer.Peer, msg *wire.MsgInv) {
 // OnHeaders is invoked when a peer receives a headers wire message.  The
 // message is passed down to the net sync manager.
 func (sp *serverPeer) OnHeaders(_ *peer.Peer, msg *wire.MsgHeaders) {
+	// Note the peer is known to have the announced blocks so they are not
+	// announced back to it and future header announcements are able to
+	// connect to them.
+	for _, header := range msg.Headers {
+		hash := header.BlockHash()
+		sp.AddKnownInventory(wire.NewInvVect(wire.InvTypeBlock, &hash))
+	}
 	sp.server.syncManager.QueueHeaders(msg, sp.Peer)
 }
 
@@ -1298,6 +1910,18 @@ func (sp *serverPeer) OnGetHeaders(_ *peer.Peer, msg *wire.MsgGetHeaders) {
 		blockHeaders[i] = &headers[i]
 	}
 	sp.QueueMessage(&wire.MsgHeaders{Headers: blockHeaders}, nil)
+
+	// Note the peer is known to have the first block in its locator, which is
+	// its best known block, along with the final header that was sent so that
+	// future blocks built on them may be announced by pushing their headers.
+	if len(locatorHashes) > 0 {
+		iv := wire.NewInvVect(wire.InvTypeBlock, locatorHashes[0])
+		sp.AddKnownInventory(iv)
+	}
+	if len(headers) > 0 {
+		hash := headers[len(headers)-1].BlockHash()
+		sp.AddKnownInventory(wire.NewInvVect(wire.InvTypeBlock, &hash))
+	}
 }
 
 // enforceNodeCFFlag bans the peer if it has negotiated to a protocol version
@@ -1375,15 +1999,17 @@ func (sp *serverPeer) OnGetAddr(_ *peer.Peer, msg *wire.MsgGetAddr) {
 		return
 	}
 
-	// Do not accept getaddr requests from outbound peers.  This reduces
-	// fingerprinting attacks.
-	if !sp.Inbound() {
+	// Do not accept getaddr requests from outbound peers unless they have the
+	// addr permission.  This reduces fingerprinting attacks.
+	canRequestAddrs := sp.permissions.has(permAddr)
+	if !sp.Inbound() && !canRequestAddrs {
 		return
 	}
 
-	// Only respond with addresses once per connection.  This helps reduce
-	// traffic and further reduces fingerprinting attacks.
-	if sp.addrsSent {
+	// Only respond with addresses once per connection unless the peer has the
+	// addr permission.  This helps reduce traffic and further reduces
+	// fingerprinting attacks.
+	if sp.addrsSent && !canRequestAddrs {
 		peerLog.Tracef("Ignoring getaddr from %v - already sent", sp.Peer)
 		return
 	}
@@ -1417,8 +2043,42 @@ func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
 		return
 	}
 
+	sp.addAddresses(wireToAddrmgrNetAddresses(msg.AddrList))
+}
+
+// OnAddrV2 is invoked when a peer receives an addrv2 wire message and is used
+// to notify the server about advertised addresses, including those that can't
+// be relayed via addr messages such as TORv3 addresses.
+func (sp *serverPeer) OnAddrV2(_ *peer.Peer, msg *wire.MsgAddrV2) {
+	// Ignore addresses when running on the simulation and regression test
+	// networks.  This helps prevent the networks from becoming another public
+	// test network since they will not be able to learn about other peers that
+	// have not specifically been provided.
+	if cfg.SimNet || cfg.RegNet {
+		return
+	}
+
+	// A message that has no addresses is invalid.
+	if len(msg.AddrList) == 0 {
+		peerLog.Errorf("Command [%s] from %s does not contain any addresses",
+			msg.Command(), sp)
+
+		// Ban peers sending empty address requests.
+		sp.server.BanPeer(sp)
+		return
+	}
+
+	addrList := make([]*addrmgr.NetAddress, 0, len(msg.AddrList))
+	for _, na := range msg.AddrList {
+		addrList = append(addrList, wireToAddrmgrNetAddressV2(na))
+	}
+	sp.addAddresses(addrList)
+}
+
+// addAddresses adds the provided addresses advertised by the peer to the known
+// addresses of the peer and to the server address manager.
+func (sp *serverPeer) addAddresses(addrList []*addrmgr.NetAddress) {
 	now := time.Now()
-	addrList := wireToAddrmgrNetAddresses(msg.AddrList)
 	for _, na := range addrList {
 		// Don't add more address if we're disconnecting.
 		if !sp.Connected() {
@@ -1439,7 +2099,7 @@ func (sp *serverPeer) OnAddr(_ *peer.Peer, msg *wire.MsgAddr) {
 	// Add addresses to server address manager.  The address manager handles
 	// the details of things such as preventing duplicate addresses, max
 	// addresses, and last seen updates.
-	remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+	remoteAddr := sp.remoteNetAddress()
 	sp.server.addrManager.AddAddresses(addrList, remoteAddr)
 }
 
@@ -1453,18 +2113,67 @@ func (sp *serverPeer) OnRead(_ *peer.Peer, bytesRead int, msg wire.Message, err
 		sp.server.BanPeer(sp)
 	}
 
-	sp.server.AddBytesReceived(uint64(bytesRead))
+	sp.server.AddBytesReceived(msgCommand(msg), uint64(bytesRead))
 }
 
 // OnWrite is invoked when a peer sends a message and it is used to update
 // the bytes sent by the server.
 func (sp *serverPeer) OnWrite(_ *peer.Peer, bytesWritten int, msg wire.Message, err error) {
-	sp.server.AddBytesSent(uint64(bytesWritten))
+	sp.server.AddBytesSent(msgCommand(msg), uint64(bytesWritten))
 }
 
-// OnNotFound is invoked when a peer sends a notfound message.
-func (sp *serverPeer) OnNotFound(_ *peer.Peer, msg *wire.MsgNotFound) {
-	if !sp.Connected() {
+// msgCommand returns the command of the provided message or peer.OtherMsgCmd
+// when it is nil, such as when a message could not be decoded.
+func msgCommand(msg wire.Message) string {
+	if msg == nil {
+		return peer.OtherMsgCmd
+	}
+	return msg.Command()
+}
+
+// rateLimiters returns the rate limiters that apply to the peer based on
+// whether it is inbound or outbound along with the combined limiter shared by
+// all peers when it is not nil.  No limiters apply to peers with the nolimits
+// permission.
+func (sp *serverPeer) rateLimiters(inbound bool, combined *peer.RateLimiter, inboundKiBps, outboundKiBps uint32) []*peer.RateLimiter {
+	if sp.permissions.has(permNoLimits) {
+		return nil
+	}
+
+	var limiters []*peer.RateLimiter
+	if combined != nil {
+		limiters = append(limiters, combined)
+	}
+	perPeerKiBps := outboundKiBps
+	if inbound {
+		perPeerKiBps = inboundKiBps
+	}
+	if perPeerKiBps != 0 {
+		limiter := peer.NewRateLimiter(uint64(perPeerKiBps)*1024, 0)
+		limiters = append(limiters, limiter)
+	}
+	return limiters
+}
+
+// OnFeeFilter is invoked when a peer receives a feefilter wire message and is
+// used by remote peers to request that no transactions which have a fee rate
+// lower than the provided value are announced to them.  The peer will be
+// disconnected if an invalid fee filter value is provided.
+func (sp *serverPeer) OnFeeFilter(_ *peer.Peer, msg *wire.MsgFeeFilter) {
+	// Check that the passed minimum fee is a valid amount.
+	if msg.MinFee < 0 || msg.MinFee > dcrutil.MaxAmount {
+		peerLog.Debugf("Peer %v sent an invalid feefilter '%v' -- "+
+			"disconnecting", sp, dcrutil.Amount(msg.MinFee))
+		sp.Disconnect()
+		return
+	}
+
+	sp.setFeeFilter(msg.MinFee)
+}
+
+// OnNotFound is invoked when a peer sends a notfound message.
+func (sp *serverPeer) OnNotFound(_ *peer.Peer, msg *wire.MsgNotFound) {
+	if !sp.Connected() {
 		return
 	}
 
@@ -1541,6 +2250,15 @@ func (s *server) attemptDcrdDial(ctx context.Context, network, addr string) (net
 		if err != nil {
 			srvrLog.Errorf("Marking address as attempted failed: %v", err)
 		}
+		s.seederScorer.recordAttempt(remoteAddr.Key())
+	}
+
+	// I2P addresses are dialed via the I2P session.
+	if strings.Contains(addr, ".b32.i2p:") {
+		if s.i2pSession == nil {
+			return nil, errors.New("i2p has not been enabled")
+		}
+		return s.i2pSession.Dial(ctx, network, addr)
 	}
 
 	return dcrdDial(ctx, network, addr)
@@ -1586,6 +2304,20 @@ func (s *server) relayTransactions(txns []*dcrutil.Tx) {
 // websocket clients of the passed transactions.  This function should be
 // called whenever new transactions are added to the mempool.
 func (s *server) AnnounceNewTransactions(txns []*dcrutil.Tx) {
+	// Trace the relay of the transactions when requested.
+	if s.tracer != nil {
+		spans := make([]*tracing.Span, 0, len(txns))
+		for _, tx := range txns {
+			span := s.tracer.Start(tx.Hash(), "server.relay_transaction")
+			spans = append(spans, span)
+		}
+		defer func() {
+			for _, span := range spans {
+				span.End()
+			}
+		}()
+	}
+
 	// Generate and relay inventory vectors for all newly accepted
 	// transactions.
 	s.relayTransactions(txns)
@@ -1706,32 +2438,30 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		sp.Disconnect()
 		return false
 	}
-	if banEnd, ok := state.banned[host]; ok {
-		if time.Now().Before(banEnd) {
+	if ip := net.ParseIP(host); ip != nil {
+		if ban, banned := s.banManager.IsBanned(ip); banned {
 			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
+				host, time.Until(ban.Expires))
 			sp.Disconnect()
 			return false
 		}
-
-		srvrLog.Infof("Peer %s is no longer banned", host)
-		delete(state.banned, host)
 	}
 
 	// Limit max number of connections from a single IP.  However, allow
-	// whitelisted inbound peers and localhost connections regardless.
-	isInboundWhitelisted := sp.isWhitelisted && sp.Inbound()
+	// inbound peers with the nolimits permission, localhost connections, and
+	// peers that are not identified by an IP, such as I2P peers, regardless.
+	isInboundWhitelisted := sp.permissions.has(permNoLimits) && sp.Inbound()
 	peerIP := sp.NA().IP
 	if cfg.MaxSameIP > 0 && !isInboundWhitelisted && !peerIP.IsLoopback() &&
-		state.ConnectionsWithIP(peerIP)+1 > cfg.MaxSameIP {
+		sp.netAddr == nil && state.ConnectionsWithIP(peerIP)+1 > cfg.MaxSameIP {
 		srvrLog.Infof("Max connections with %s reached [%d] - "+
 			"disconnecting peer", sp, cfg.MaxSameIP)
 		sp.Disconnect()
 		return false
 	}
 
-	// Limit max number of total peers.  However, allow whitelisted inbound
-	// peers regardless.
+	// Limit max number of total peers.  However, allow inbound peers with the
+	// nolimits permission regardless.
 	if state.Count()+1 > cfg.MaxPeers && !isInboundWhitelisted {
 		srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
 			cfg.MaxPeers, sp)
@@ -1746,7 +2476,7 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 	sp.peerNaMtx.Unlock()
 
 	// Add the new peer and start it.
-	srvrLog.Debugf("New peer %s", sp)
+	sp.withFields(srvrLog).Debugf("New peer %s", sp)
 	if sp.Inbound() {
 		state.inboundPeers[sp.ID()] = sp
 
@@ -1763,8 +2493,8 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 			}
 		}
 	} else {
-		remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-		state.outboundGroups[remoteAddr.GroupKey()]++
+		remoteAddr := sp.remoteNetAddress()
+		state.adjustOutboundGroups(s.outboundGroupKeys(remoteAddr), 1)
 		if sp.persistent {
 			state.persistentPeers[sp.ID()] = sp
 		} else {
@@ -1783,11 +2513,12 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		//	- If there is an external ip explicitly set (--externalip).
 		//	- If listening has been disabled (--nolisten, listen
 		//	disabled because of --connect, etc).
-		//	- If Universal Plug and Play is enabled (--upnp).
+		//	- If NAT traversal is enabled (--upnp, --natpmp).
 		//	- If the active network is simnet or regnet.
 		if (cfg.Proxy != "" || cfg.OnionProxy != "") ||
 			cfg.NoDiscoverIP || len(cfg.ExternalIPs) > 0 ||
-			(cfg.DisableListen || len(cfg.Listeners) == 0) || cfg.Upnp ||
+			(cfg.DisableListen || len(cfg.Listeners) == 0) ||
+			cfg.Upnp || cfg.NATPMP ||
 			s.chainParams.Name == simNetParams.Name ||
 			s.chainParams.Name == regNetParams.Name {
 			return true
@@ -1850,14 +2581,14 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 	}
 	if _, ok := list[sp.ID()]; ok {
 		if !sp.Inbound() && sp.VersionKnown() {
-			remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-			state.outboundGroups[remoteAddr.GroupKey()]--
+			remoteAddr := sp.remoteNetAddress()
+			state.adjustOutboundGroups(s.outboundGroupKeys(remoteAddr), -1)
 		}
 		if !sp.Inbound() && sp.connReq != nil {
 			s.connManager.Disconnect(sp.connReq.ID())
 		}
 		delete(list, sp.ID())
-		srvrLog.Debugf("Removed peer %s", sp)
+		sp.withFields(srvrLog).Debugf("Removed peer %s", sp)
 		return
 	}
 
@@ -1868,7 +2599,7 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 	// Update the address' last seen time if the peer has acknowledged
 	// our version and has sent us its version as well.
 	if sp.VerAckReceived() && sp.VersionKnown() && sp.NA() != nil {
-		remoteAddr := wireToAddrmgrNetAddress(sp.NA())
+		remoteAddr := sp.remoteNetAddress()
 		err := s.addrManager.Connected(remoteAddr)
 		if err != nil {
 			srvrLog.Errorf("Marking address as connected failed: %v", err)
@@ -1887,15 +2618,33 @@ func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
 		srvrLog.Debugf("can't split ban peer %s %v", sp.Addr(), err)
 		return
 	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		srvrLog.Debugf("can't ban peer %s: not identified by an IP address",
+			sp.Addr())
+		return
+	}
 	direction := directionString(sp.Inbound())
 	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
 		cfg.BanDuration)
-	state.banned[host] = time.Now().Add(cfg.BanDuration)
+	expires := time.Now().Add(cfg.BanDuration)
+	s.banManager.Ban(banmgr.SubnetForIP(ip), expires,
+		banmgr.ReasonMisbehaving)
 }
 
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	// The compact block for block announcements is created on demand the
+	// first time it is needed and shared by all peers.
+	var cmpctBlock *wire.MsgCmpctBlock
+
+	// Similarly, the fee rate of transactions is only looked up the first time
+	// it is needed to apply a peer fee filter and the headers to push for
+	// block announcements are only loaded the first time they are needed.
+	var txFeeRateKnown bool
+	var relayTxFeeRate int64
+	var announceHeaders []blockAnnounceHeader
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
@@ -1917,23 +2666,56 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 			sp.announcedBlock = &iv.Hash
 		}
 
+		// Generate and send a compact block instead of an inventory message
+		// for block announcements when the peer prefers compact blocks and is
+		// not already known to have the block.
+		if isBlockAnnouncement && sp.wantsCmpctBlocks() &&
+			!sp.IsKnownInventory(iv) {
+
+			if cmpctBlock == nil {
+				block, ok := msg.data.(*dcrutil.Block)
+				if !ok {
+					peerLog.Warnf("Underlying data for compact block is " +
+						"not a block")
+					return
+				}
+				nonce, err := wire.RandomUint64()
+				if err != nil {
+					peerLog.Errorf("Unable to generate compact block "+
+						"nonce: %v", err)
+					return
+				}
+				cmpctBlock = cmpctblock.NewMsgCmpctBlock(block.MsgBlock(),
+					nonce)
+			}
+			sp.AddKnownInventory(iv)
+			sp.QueueMessage(cmpctBlock, nil)
+			return
+		}
+
 		// Generate and send a headers message instead of an inventory message
-		// for block announcements when the peer prefers headers.
-		if isBlockAnnouncement && sp.WantsHeaders() {
-			blockHeader, ok := msg.data.(wire.BlockHeader)
-			if !ok {
-				peerLog.Warnf("Underlying data for headers" +
-					" is not a block header")
-				return
+		// for block announcements when the peer prefers headers and is not
+		// already known to have the block.  This saves the peer a round trip
+		// since it is able to request the block right away.
+		//
+		// The headers of any ancestors the peer is not known to have are
+		// pushed as well so the announcement connects to a block the peer
+		// already knows about.  Announcements that would not connect fall
+		// back to an inventory announcement below.
+		if isBlockAnnouncement && sp.WantsHeaders() && !sp.IsKnownInventory(iv) {
+			if announceHeaders == nil {
+				block, ok := msg.data.(*dcrutil.Block)
+				if !ok {
+					peerLog.Warnf("Underlying data for headers" +
+						" is not a block")
+					return
+				}
+				announceHeaders = s.blockAnnounceHeaders(block)
 			}
-			msgHeaders := wire.NewMsgHeaders()
-			if err := msgHeaders.AddBlockHeader(&blockHeader); err != nil {
-				peerLog.Errorf("Failed to add block"+
-					" header: %v", err)
+			if msgHeaders := sp.headersAnnouncement(announceHeaders); msgHeaders != nil {
+				sp.QueueMessage(msgHeaders, nil)
 				return
 			}
-			sp.QueueMessage(msgHeaders, nil)
-			return
 		}
 
 		if iv.Type == wire.InvTypeTx {
@@ -1942,6 +2724,35 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 			if sp.relayTxDisabled() {
 				return
 			}
+
+			// Don't relay the transaction to the peer when its fee rate is
+			// below the minimum the peer requested via a fee filter.
+			// Transactions that are no longer in the mempool are not
+			// filtered since their fee rate is unknown.
+			if minFee := sp.feeFilterRate(); minFee > 0 {
+				if !txFeeRateKnown {
+					txFeeRateKnown = true
+					relayTxFeeRate = -1
+					txDesc, ok := s.txMemPool.TxDesc(&iv.Hash)
+					if ok {
+						relayTxFeeRate = txFeeRate(txDesc)
+					}
+				}
+				if relayTxFeeRate >= 0 && relayTxFeeRate < minFee {
+					return
+				}
+			}
+
+			// Add the transaction to the set of transactions to reconcile
+			// with the peer instead of announcing it when reconciliation is
+			// in use with the peer.  Transactions that can't be added to the
+			// set are announced as usual.
+			txRecon := sp.txReconState()
+			if txRecon != nil && !sp.IsKnownInventory(iv) &&
+				txRecon.AddTx(&iv.Hash) {
+
+				return
+			}
 		}
 
 		// Either queue the inventory to be relayed immediately or with
@@ -1957,6 +2768,81 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 	})
 }
 
+// blockAnnounceHeader houses a block header used in block announcements along
+// with its hash.
+type blockAnnounceHeader struct {
+	header wire.BlockHeader
+	hash   chainhash.Hash
+}
+
+// blockAnnounceHeaders returns the headers to consider pushing to peers that
+// prefer headers when announcing the provided block.  They consist of the
+// header of the block along with the headers of up to maxAnnounceHeaders-1 of
+// its most recent ancestors ordered from oldest to newest.
+func (s *server) blockAnnounceHeaders(block *dcrutil.Block) []blockAnnounceHeader {
+	headers := make([]blockAnnounceHeader, maxAnnounceHeaders)
+	i := len(headers) - 1
+	headers[i] = blockAnnounceHeader{block.MsgBlock().Header, *block.Hash()}
+	for i > 0 && headers[i].header.Height > 0 {
+		prevHash := headers[i].header.PrevBlock
+		prevHeader, err := s.chain.HeaderByHash(&prevHash)
+		if err != nil {
+			break
+		}
+		i--
+		headers[i] = blockAnnounceHeader{prevHeader, prevHash}
+	}
+	return headers[i:]
+}
+
+// headersAnnouncement returns a headers message that announces the final
+// provided header to the peer.  It includes all of the provided headers after
+// the most recent one whose parent the peer is known to have so that the
+// announcement connects.  Nil is returned when the peer is not known to have
+// the parent of any of the headers.
+//
+// The headers in the returned message are added to the known inventory for the
+// peer.
+func (sp *serverPeer) headersAnnouncement(headers []blockAnnounceHeader) *wire.MsgHeaders {
+	for i := len(headers) - 1; i >= 0; i-- {
+		prevHash := &headers[i].header.PrevBlock
+		prevIV := wire.NewInvVect(wire.InvTypeBlock, prevHash)
+		if !sp.IsKnownInventory(prevIV) {
+			continue
+		}
+
+		msgHeaders := wire.NewMsgHeaders()
+		for j := i; j < len(headers); j++ {
+			// The number of headers is limited well below the max allowed
+			// per message, so this can't fail.
+			_ = msgHeaders.AddBlockHeader(&headers[j].header)
+			sp.AddKnownInventory(wire.NewInvVect(wire.InvTypeBlock,
+				&headers[j].hash))
+		}
+		return msgHeaders
+	}
+	return nil
+}
+
+// handleTxReconTick requests a transaction reconciliation round from all
+// outbound peers that have reconciliation enabled.  It is invoked from the
+// peerHandler goroutine.
+func (s *server) handleTxReconTick(state *peerState) {
+	state.forAllOutboundPeers(func(sp *serverPeer) {
+		if !sp.Connected() {
+			return
+		}
+
+		txRecon := sp.txReconState()
+		if txRecon == nil {
+			return
+		}
+		if msg := txRecon.InitiateReconciliation(); msg != nil {
+			sp.QueueMessage(msg, nil)
+		}
+	})
+}
+
 // handleBroadcastMsg deals with broadcasting messages to peers.  It is invoked
 // from the peerHandler goroutine.
 func (s *server) handleBroadcastMsg(state *peerState, bmsg *broadcastMsg) {
@@ -2082,8 +2968,8 @@ func (s *server) handleQuery(ctx context.Context, state *peerState, querymsg int
 		found := disconnectPeer(state.persistentPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-			state.outboundGroups[remoteAddr.GroupKey()]--
+			remoteAddr := sp.remoteNetAddress()
+			state.adjustOutboundGroups(s.outboundGroupKeys(remoteAddr), -1)
 
 			peerLog.Debugf("Removing persistent peer %s (reqid %d)", remoteAddr,
 				sp.connReq.ID())
@@ -2138,8 +3024,8 @@ func (s *server) handleQuery(ctx context.Context, state *peerState, querymsg int
 		found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-			state.outboundGroups[remoteAddr.GroupKey()]--
+			remoteAddr := sp.remoteNetAddress()
+			state.adjustOutboundGroups(s.outboundGroupKeys(remoteAddr), -1)
 		})
 		if found {
 			// If there are multiple outbound connections to the same
@@ -2147,8 +3033,8 @@ func (s *server) handleQuery(ctx context.Context, state *peerState, querymsg int
 			// peers are found.
 			for found {
 				found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
-					remoteAddr := wireToAddrmgrNetAddress(sp.NA())
-					state.outboundGroups[remoteAddr.GroupKey()]--
+					remoteAddr := sp.remoteNetAddress()
+					state.adjustOutboundGroups(s.outboundGroupKeys(remoteAddr), -1)
 				})
 			}
 			msg.reply <- nil
@@ -2184,12 +3070,20 @@ func disconnectPeer(peerList map[int32]*serverPeer, compareFunc func(*serverPeer
 }
 
 // newPeerConfig returns the configuration for the given serverPeer.
-func newPeerConfig(sp *serverPeer) *peer.Config {
+func newPeerConfig(sp *serverPeer, inbound bool) *peer.Config {
 	var userAgentComments []string
 	if version.PreRelease != "" {
 		userAgentComments = append(userAgentComments, version.PreRelease)
 	}
 
+	// Trickle inventory to inbound peers at twice the interval of outbound
+	// peers since they are more likely to be controlled by an observer trying
+	// to infer the origin of transactions by connecting to many nodes.
+	trickleInterval := cfg.TrickleInterval
+	if inbound {
+		trickleInterval *= 2
+	}
+
 	return &peer.Config{
 		Listeners: peer.MessageListeners{
 			OnVersion:        sp.OnVersion,
@@ -2199,6 +3093,14 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnMiningState:    sp.OnMiningState,
 			OnGetInitState:   sp.OnGetInitState,
 			OnInitState:      sp.OnInitState,
+			OnSendTxRecon:    sp.OnSendTxRecon,
+			OnReqRecon:       sp.OnReqRecon,
+			OnSketch:         sp.OnSketch,
+			OnReconcilDiff:   sp.OnReconcilDiff,
+			OnSendCmpct:      sp.OnSendCmpct,
+			OnCmpctBlock:     sp.OnCmpctBlock,
+			OnGetBlockTxn:    sp.OnGetBlockTxn,
+			OnBlockTxn:       sp.OnBlockTxn,
 			OnTx:             sp.OnTx,
 			OnBlock:          sp.OnBlock,
 			OnInv:            sp.OnInv,
@@ -2212,9 +3114,14 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			OnGetCFTypes:     sp.OnGetCFTypes,
 			OnGetAddr:        sp.OnGetAddr,
 			OnAddr:           sp.OnAddr,
+			OnAddrV2:         sp.OnAddrV2,
 			OnRead:           sp.OnRead,
 			OnWrite:          sp.OnWrite,
 			OnNotFound:       sp.OnNotFound,
+			OnFeeFilter:      sp.OnFeeFilter,
+		},
+		OnHandshakeFailure: func(p *peer.Peer, reason peer.HandshakeFailure, err error) {
+			sp.server.p2pMetrics.handshakeFailed(inbound, reason.String())
 		},
 		NewestBlock: sp.newestBlock,
 		HostToNetAddress: func(host string, port uint16, services wire.ServiceFlag) (*wire.NetAddress, error) {
@@ -2222,6 +3129,11 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 			if err != nil {
 				return nil, err
 			}
+			if address.Type == addrmgr.TORv3Address ||
+				address.Type == addrmgr.I2PAddress {
+
+				sp.netAddr = address
+			}
 			return addrmgrToWireNetAddress(address), nil
 		},
 		Proxy:             cfg.Proxy,
@@ -2233,6 +3145,11 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		DisableRelayTx:    cfg.BlocksOnly,
 		ProtocolVersion:   maxProtocolVersion,
 		IdleTimeout:       cfg.PeerIdleTimeout,
+		TrickleInterval:   trickleInterval,
+		DownloadLimiters: sp.rateLimiters(inbound, sp.server.downloadLimiter,
+			cfg.MaxInboundDownloadRate, cfg.MaxOutboundDownloadRate),
+		UploadLimiters: sp.rateLimiters(inbound, sp.server.uploadLimiter,
+			cfg.MaxInboundUploadRate, cfg.MaxOutboundUploadRate),
 	}
 }
 
@@ -2242,8 +3159,41 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 // for disconnection.
 func (s *server) inboundPeerConnected(conn net.Conn) {
 	sp := newServerPeer(s, false)
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
-	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
+
+	// Detect whether the remote peer is using the encrypted transport and
+	// perform the handshake when it is.
+	if cfg.P2PEncryption {
+		transportConn, encrypted, err := p2ptransport.Accept(conn,
+			s.chainParams.Net)
+		if err != nil {
+			srvrLog.Debugf("Failed to accept inbound connection from %s: %v",
+				conn.RemoteAddr(), err)
+			s.p2pMetrics.handshakeFailed(true, handshakeEncryptionFailed)
+			conn.Close()
+			return
+		}
+		if encConn, ok := transportConn.(*p2ptransport.Conn); ok {
+			srvrLog.Debugf("Encrypted inbound connection from %s (session "+
+				"%x)", conn.RemoteAddr(), encConn.SessionID())
+		}
+		conn = transportConn
+		sp.encrypted = encrypted
+	}
+
+	// Track the address of inbound I2P peers since it can't be represented by
+	// the wire network address tracked by the peer.
+	if addr, ok := conn.RemoteAddr().(*i2p.Addr); ok {
+		netAddr, err := s.addrManager.HostToNetAddress(addr.Host, i2p.Port, 0)
+		if err != nil {
+			srvrLog.Debugf("Failed to parse I2P address %s: %v", addr, err)
+			conn.Close()
+			return
+		}
+		sp.netAddr = netAddr
+	}
+
+	sp.permissions = whitelistPermissions(conn.RemoteAddr())
+	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp, true))
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
 }
@@ -2254,7 +3204,11 @@ func (s *server) inboundPeerConnected(conn net.Conn) {
 // request instance and the connection itself.
 func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	sp := newServerPeer(s, c.Permanent)
-	p, err := peer.NewOutboundPeer(newPeerConfig(sp), c.Addr.String())
+	sp.permissions = whitelistPermissions(conn.RemoteAddr())
+	if c.Permanent {
+		sp.permissions |= s.permanentPeerPerms[c.Addr.String()]
+	}
+	p, err := peer.NewOutboundPeer(newPeerConfig(sp, false), c.Addr.String())
 	if err != nil {
 		srvrLog.Debugf("Cannot create outbound peer %s: %v", c.Addr, err)
 		s.connManager.Disconnect(c.ID())
@@ -2262,7 +3216,32 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	}
 	sp.Peer = p
 	sp.connReq = c
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	_, sp.isAnchor = s.anchors[c.Addr.String()]
+
+	// Use the encrypted transport when the remote peer is known to support
+	// it.  Notice that the connection is intentionally not retried without
+	// encryption on failure since that would allow an attacker to downgrade
+	// it by interfering with the handshake.
+	if cfg.P2PEncryption {
+		remoteAddr := sp.remoteNetAddress()
+		services, err := s.addrManager.Services(remoteAddr)
+		if err == nil && hasServices(services, wire.SFNodeEncryption) {
+			encConn, err := p2ptransport.Initiate(conn, s.chainParams.Net)
+			if err != nil {
+				srvrLog.Debugf("Failed encrypted handshake with %s: %v",
+					c.Addr, err)
+				s.p2pMetrics.handshakeFailed(false,
+					handshakeEncryptionFailed)
+				s.connManager.Disconnect(c.ID())
+				return
+			}
+			srvrLog.Debugf("Encrypted outbound connection to %s (session "+
+				"%x)", c.Addr, encConn.SessionID())
+			conn = encConn
+			sp.encrypted = true
+		}
+	}
+
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
 }
@@ -2303,18 +3282,30 @@ func (s *server) peerHandler(ctx context.Context) {
 	// faster to simply start and stop it in this handler.
 	s.addrManager.Start()
 
+	// Query the seeders now that the address manager has loaded the known
+	// addresses so it is able to accurately determine whether or not more
+	// addresses are needed.
+	if !cfg.DisableSeeders {
+		s.querySeeders(ctx)
+	}
+
 	srvrLog.Tracef("Starting peer handler")
 
 	state := &peerState{
 		inboundPeers:    make(map[int32]*serverPeer),
 		persistentPeers: make(map[int32]*serverPeer),
 		outboundPeers:   make(map[int32]*serverPeer),
-		banned:          make(map[string]time.Time),
 		outboundGroups:  make(map[string]int),
-		subCache: &naSubmissionCache{
-			cache: make(map[string]*naSubmission, maxCachedNaSubmissions),
-			limit: maxCachedNaSubmissions,
-		},
+		subCache:        newNaSubmissionCache(maxCachedNaSubmissions),
+	}
+
+	// Periodically request transaction reconciliation rounds from outbound
+	// peers when transaction reconciliation is enabled.
+	var txReconTicker <-chan time.Time
+	if hasServices(s.services, wire.SFNodeTxRecon) {
+		ticker := time.NewTicker(txReconInterval)
+		defer ticker.Stop()
+		txReconTicker = ticker.C
 	}
 
 out:
@@ -2353,9 +3344,27 @@ out:
 		case qmsg := <-s.query:
 			s.handleQuery(ctx, state, qmsg)
 
+		case <-txReconTicker:
+			s.handleTxReconTick(state)
+
 		case <-ctx.Done():
 			close(s.quit)
 
+			// Persist the longest-lived outbound connections so they are
+			// re-established on the next start.
+			if cfg.EclipseResist {
+				s.saveAnchors(state)
+			}
+
+			// Persist the seeder statistics so the seeders continue to be
+			// scored across restarts.
+			if !cfg.DisableSeeders {
+				path := filepath.Join(cfg.DataDir, seederStatsFilename)
+				if err := s.seederScorer.save(path); err != nil {
+					srvrLog.Errorf("Unable to save seeder stats: %v", err)
+				}
+			}
+
 			// Disconnect all peers on server shutdown.
 			state.forAllPeers(func(sp *serverPeer) {
 				srvrLog.Tracef("Shutdown peer %s", sp)
@@ -2387,9 +3396,9 @@ func (s *server) DonePeer(sp *serverPeer) {
 }
 
 // BanPeer bans a peer that has already been connected to the server by ip
-// unless banning is disabled or the peer has been whitelisted.
+// unless banning is disabled or the peer has the noban permission.
 func (s *server) BanPeer(sp *serverPeer) {
-	if cfg.DisableBanning || sp.isWhitelisted {
+	if cfg.DisableBanning || sp.permissions.has(permNoBan) {
 		return
 	}
 	sp.Disconnect()
@@ -2418,7 +3427,7 @@ func (s *server) RelayBlockAnnouncement(block *dcrutil.Block, reqServices wire.S
 	case <-s.quit:
 	case s.relayInv <- relayMsg{
 		invVect:     invVect,
-		data:        block.MsgBlock().Header,
+		data:        block,
 		immediate:   true,
 		reqServices: reqServices,
 	}:
@@ -2445,6 +3454,177 @@ func (s *server) ConnectedCount() int32 {
 	}
 }
 
+// saveAnchors persists the addresses of up to maxAnchors of the longest-lived
+// non-persistent outbound peers so the connections to them can be
+// re-established on the next start.  Peers that were themselves connected as
+// anchors are preferred so the same anchors are kept across restarts.  It is
+// invoked from the peerHandler goroutine.
+func (s *server) saveAnchors(state *peerState) {
+	var candidates []*serverPeer
+	for _, sp := range state.outboundPeers {
+		if !sp.Connected() || !sp.VersionKnown() ||
+			time.Since(sp.TimeConnected()) < minAnchorLifetime {
+
+			continue
+		}
+		candidates = append(candidates, sp)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].isAnchor != candidates[j].isAnchor {
+			return candidates[i].isAnchor
+		}
+		return candidates[i].TimeConnected().Before(candidates[j].TimeConnected())
+	})
+	if len(candidates) > maxAnchors {
+		candidates = candidates[:maxAnchors]
+	}
+
+	anchors := make([]string, 0, len(candidates))
+	for _, sp := range candidates {
+		anchors = append(anchors, sp.Addr())
+	}
+	serialized, err := json.Marshal(anchors)
+	if err != nil {
+		srvrLog.Errorf("Unable to serialize anchors: %v", err)
+		return
+	}
+	path := filepath.Join(cfg.DataDir, anchorsFilename)
+	if err := os.WriteFile(path, serialized, 0600); err != nil {
+		srvrLog.Errorf("Unable to save anchors: %v", err)
+		return
+	}
+	srvrLog.Debugf("Saved %d %s", len(anchors),
+		pickNoun(uint64(len(anchors)), "anchor", "anchors"))
+}
+
+// loadPendingAnchors loads the anchor connections persisted on the previous
+// shutdown and queues them to be handed out as the first outbound addresses.
+// Anchors that are banned or otherwise invalid are ignored.
+func (s *server) loadPendingAnchors() {
+	anchors := loadAnchors(filepath.Join(cfg.DataDir, anchorsFilename))
+	s.anchors = make(map[string]struct{}, len(anchors))
+	s.pendingAnchors = make([]net.Addr, 0, len(anchors))
+	for _, addr := range anchors {
+		netAddr, err := addrStringToNetAddr(addr)
+		if err != nil {
+			srvrLog.Debugf("Ignoring invalid anchor %s: %v", addr, err)
+			continue
+		}
+		if tcpAddr, ok := netAddr.(*net.TCPAddr); ok {
+			if _, banned := s.banManager.IsBanned(tcpAddr.IP); banned {
+				srvrLog.Debugf("Ignoring banned anchor %s", addr)
+				continue
+			}
+		}
+		s.anchors[netAddr.String()] = struct{}{}
+		s.pendingAnchors = append(s.pendingAnchors, netAddr)
+	}
+}
+
+// nextPendingAnchor removes and returns the next anchor connection from the
+// previous shutdown that has not yet been handed out or nil when there are
+// none left.
+//
+// This function is safe for concurrent access.
+func (s *server) nextPendingAnchor() net.Addr {
+	s.pendingAnchorsMtx.Lock()
+	defer s.pendingAnchorsMtx.Unlock()
+
+	if len(s.pendingAnchors) == 0 {
+		return nil
+	}
+	anchor := s.pendingAnchors[0]
+	s.pendingAnchors = s.pendingAnchors[1:]
+	return anchor
+}
+
+// queueEncryptedReconnect queues an outbound connection to the provided address
+// that was established without the encrypted transport to be re-established
+// over it.  The address is dropped when the maximum number of reconnects are
+// already queued.
+//
+// This function is safe for concurrent access.
+func (s *server) queueEncryptedReconnect(addr net.Addr) {
+	s.encReconnectsMtx.Lock()
+	defer s.encReconnectsMtx.Unlock()
+
+	if len(s.encReconnects) >= maxEncryptedReconnects {
+		return
+	}
+	s.encReconnects = append(s.encReconnects, addr)
+}
+
+// nextEncryptedReconnect removes and returns the next queued outbound
+// connection to re-establish over the encrypted transport or nil when there
+// are none left.
+//
+// This function is safe for concurrent access.
+func (s *server) nextEncryptedReconnect() net.Addr {
+	s.encReconnectsMtx.Lock()
+	defer s.encReconnectsMtx.Unlock()
+
+	if len(s.encReconnects) == 0 {
+		return nil
+	}
+	addr := s.encReconnects[0]
+	s.encReconnects = s.encReconnects[1:]
+	return addr
+}
+
+// loadAnchors returns the addresses of the anchor connections persisted on the
+// previous shutdown.  The file is removed once read so that the anchors are
+// not reused should the node not shut down cleanly.
+func loadAnchors(path string) []string {
+	serialized, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			srvrLog.Warnf("Unable to read anchors: %v", err)
+		}
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		srvrLog.Warnf("Unable to remove anchors file: %v", err)
+	}
+
+	var anchors []string
+	if err := json.Unmarshal(serialized, &anchors); err != nil {
+		srvrLog.Warnf("Unable to parse anchors: %v", err)
+		return nil
+	}
+	if len(anchors) > maxAnchors {
+		anchors = anchors[:maxAnchors]
+	}
+	return anchors
+}
+
+// loadASMap loads the AS map used to diversify outbound connections across
+// autonomous systems from the provided file.
+func loadASMap(path string) (*addrmgr.ASMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open AS map: %w", err)
+	}
+	defer f.Close()
+
+	asmap, err := addrmgr.ParseASMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse AS map %s: %w", path, err)
+	}
+	return asmap, nil
+}
+
+// outboundGroupKeys returns the keys of the groups used to diversify outbound
+// connections that the provided address belongs to.  This is always its /16
+// (IPv4) or /32 (IPv6) netgroup and, when an AS map is configured and includes
+// the address, also the autonomous system that announces it.
+func (s *server) outboundGroupKeys(na *addrmgr.NetAddress) []string {
+	keys := []string{na.GroupKey()}
+	if asn := s.addrManager.ASN(na); asn != 0 {
+		keys = append(keys, fmt.Sprintf("as%d", asn))
+	}
+	return keys
+}
+
 // OutboundGroupCount returns the number of peers connected to the given
 // outbound group key.
 func (s *server) OutboundGroupCount(key string) int {
@@ -2470,15 +3650,27 @@ func (s *server) AddedNodeInfo() []*serverPeer {
 }
 
 // AddBytesSent adds the passed number of bytes to the total bytes sent counter
-// for the server.  It is safe for concurrent access.
-func (s *server) AddBytesSent(bytesSent uint64) {
+// for the server as well as the counter for the provided message command.  It
+// is safe for concurrent access.
+func (s *server) AddBytesSent(cmd string, bytesSent uint64) {
 	atomic.AddUint64(&s.bytesSent, bytesSent)
+	if bytesSent != 0 {
+		s.bytesPerMsgMtx.Lock()
+		s.bytesSentPerMsg[cmd] += bytesSent
+		s.bytesPerMsgMtx.Unlock()
+	}
 }
 
 // AddBytesReceived adds the passed number of bytes to the total bytes received
-// counter for the server.  It is safe for concurrent access.
-func (s *server) AddBytesReceived(bytesReceived uint64) {
+// counter for the server as well as the counter for the provided message
+// command.  It is safe for concurrent access.
+func (s *server) AddBytesReceived(cmd string, bytesReceived uint64) {
 	atomic.AddUint64(&s.bytesReceived, bytesReceived)
+	if bytesReceived != 0 {
+		s.bytesPerMsgMtx.Lock()
+		s.bytesRecvPerMsg[cmd] += bytesReceived
+		s.bytesPerMsgMtx.Unlock()
+	}
 }
 
 // NetTotals returns the sum of all bytes received and sent across the network
@@ -2488,6 +3680,23 @@ func (s *server) NetTotals() (uint64, uint64) {
 		atomic.LoadUint64(&s.bytesSent)
 }
 
+// NetTotalsPerMsg returns the total bytes received and sent across the network
+// for all peers keyed by message command.  It is safe for concurrent access.
+func (s *server) NetTotalsPerMsg() (map[string]uint64, map[string]uint64) {
+	s.bytesPerMsgMtx.Lock()
+	defer s.bytesPerMsgMtx.Unlock()
+
+	recv := make(map[string]uint64, len(s.bytesRecvPerMsg))
+	for cmd, n := range s.bytesRecvPerMsg {
+		recv[cmd] = n
+	}
+	sent := make(map[string]uint64, len(s.bytesSentPerMsg))
+	for cmd, n := range s.bytesSentPerMsg {
+		sent[cmd] = n
+	}
+	return recv, sent
+}
+
 // notifiedWinningTickets returns whether or not the winning tickets
 // notification for the specified block hash has already been sent.
 func (s *server) notifiedWinningTickets(hash *chainhash.Hash) bool {
@@ -2543,7 +3752,9 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 		}
 
 		// Relay the block announcement immediately to full nodes.
+		span := s.tracer.Start(block.Hash(), "server.relay_block")
 		s.RelayBlockAnnouncement(block, wire.SFNodeNetwork)
+		span.End()
 
 	// A block has been accepted into the block chain.  Relay it to other peers
 	// (will be ignored if already relayed via NTNewTipBlockChecked) and
@@ -2658,6 +3869,10 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 		block := ntfn.Block
 		parentBlock := ntfn.ParentBlock
 
+		// Trace the handling of the notification when requested.
+		span := s.tracer.Start(block.Hash(), "server.block_connected")
+		defer span.End()
+
 		// Determine active agendas based on flags.
 		isTreasuryEnabled := ntfn.CheckTxFlags.IsTreasuryEnabled()
 
@@ -2750,6 +3965,9 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 			// Notify registered websocket clients of incoming block.
 			r.NotifyBlockConnected(block)
 		}
+		if g := s.grpcServer; g != nil {
+			g.NotifyBlockConnected(block)
+		}
 
 		if s.bg != nil {
 			s.bg.BlockConnected(block)
@@ -2873,6 +4091,9 @@ func (s *server) handleBlockchainNotification(notification *blockchain.Notificat
 			// Notify registered websocket clients.
 			r.NotifyBlockDisconnected(block)
 		}
+		if g := s.grpcServer; g != nil {
+			g.NotifyBlockDisconnected(block)
+		}
 
 	// Chain reorganization has commenced.
 	case blockchain.NTChainReorgStarted:
@@ -2999,43 +4220,113 @@ func (s *server) rebroadcastHandler(ctx context.Context) {
 	}
 }
 
-// querySeeders queries the configured seeders to discover peers that supported
-// the required services and adds the discovered peers to the address manager.
-// Each seeder is contacted in a separate goroutine.
+// querySeeder queries the provided seeder to discover peers that support the
+// required services, adds the discovered peers to the address manager, and
+// records the outcome so the seeder is scored accordingly.
+func (s *server) querySeeder(ctx context.Context, seeder string) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	addrs, err := connmgr.SeedAddrs(queryCtx, seeder, dcrdDial,
+		connmgr.SeedFilterServices(defaultRequiredServices))
+	if err != nil {
+		// Don't penalize the seeder when the query was interrupted by
+		// shutdown.
+		if ctx.Err() != nil {
+			return
+		}
+		srvrLog.Infof("seeder '%s' error: %v", seeder, err)
+		s.seederScorer.recordQuery(seeder, nil, err)
+		return
+	}
+
+	addresses := wireToAddrmgrNetAddresses(addrs)
+	addrKeys := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		addrKeys = append(addrKeys, addr.Key())
+	}
+	s.seederScorer.recordQuery(seeder, addrKeys, nil)
+
+	// Nothing to do if the seeder didn't return any addresses.
+	if len(addrs) == 0 {
+		return
+	}
+
+	// Lookup the IP of the https seeder to use as the source of the seeded
+	// addresses.  In the incredibly rare event that the lookup fails after it
+	// just succeeded, fall back to using the first returned address as the
+	// source.
+	srcAddr := addresses[0]
+	srcIPs, err := dcrdLookup(seeder)
+	if err == nil && len(srcIPs) > 0 {
+		const httpsPort = 443
+		srcAddr = addrmgr.NewNetAddressIPPort(srcIPs[0], httpsPort, 0)
+	}
+	s.addrManager.AddAddresses(addresses, srcAddr)
+}
+
+// querySeeders queries the seeders to discover peers that support the required
+// services and adds the discovered peers to the address manager.  All of the
+// additional seeders specified by the operator are queried first.  The default
+// seeders for the network are then only queried, one at a time in order of
+// their scores, for as long as the address manager needs more addresses.  The
+// queries are made in a separate goroutine.
 func (s *server) querySeeders(ctx context.Context) {
-	// Add peers discovered through DNS to the address manager.
-	seeders := s.chainParams.Seeders()
-	for _, seeder := range seeders {
-		go func(seeder string) {
-			ctx, cancel := context.WithTimeout(ctx, time.Minute)
-			defer cancel()
-
-			addrs, err := connmgr.SeedAddrs(ctx, seeder, dcrdDial,
-				connmgr.SeedFilterServices(defaultRequiredServices))
-			if err != nil {
-				srvrLog.Infof("seeder '%s' error: %v", seeder, err)
+	go func() {
+		var wg sync.WaitGroup
+		for _, seeder := range cfg.Seeders {
+			wg.Add(1)
+			go func(seeder string) {
+				s.querySeeder(ctx, seeder)
+				wg.Done()
+			}(seeder)
+		}
+		wg.Wait()
+
+		seeders := s.seederScorer.rank(s.chainParams.Seeders())
+		for _, seeder := range seeders {
+			if ctx.Err() != nil || !s.addrManager.NeedMoreAddresses() {
 				return
 			}
+			s.querySeeder(ctx, seeder)
+		}
+	}()
+}
 
-			// Nothing to do if the seeder didn't return any addresses.
-			if len(addrs) == 0 {
-				return
-			}
+// rpcAuthReloadHandler reloads the per-user RPC authorization entries from the
+// config file and applies them to the RPC server each time one of the reload
+// signals is received.  The existing users remain in effect when the entries
+// fail to load.
+//
+// It must be run as a goroutine.
+func (s *server) rpcAuthReloadHandler(ctx context.Context) {
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, reloadSignals...)
+	defer signal.Stop(reloadChan)
 
-			// Lookup the IP of the https seeder to use as the source of the
-			// seeded addresses.  In the incredibly rare event that the lookup
-			// fails after it just succeeded, fall back to using the first
-			// returned address as the source.
-			srcAddr := wireToAddrmgrNetAddress(addrs[0])
-			srcIPs, err := dcrdLookup(seeder)
-			if err == nil && len(srcIPs) > 0 {
-				const httpsPort = 443
-				srcAddr = addrmgr.NewNetAddressIPPort(srcIPs[0], httpsPort, 0)
+out:
+	for {
+		select {
+		case sig := <-reloadChan:
+			srvrLog.Infof("Received signal (%s).  Reloading RPC users from "+
+				"%s", sig, cfg.ConfigFile)
+			users, err := loadRPCAuth(cfg.ConfigFile)
+			if err != nil {
+				srvrLog.Errorf("Unable to reload RPC users: %v", err)
+				continue
+			}
+			if err := s.rpcServer.SetUsers(users); err != nil {
+				srvrLog.Errorf("Unable to reload RPC users: %v", err)
+				continue
 			}
-			addresses := wireToAddrmgrNetAddresses(addrs)
-			s.addrManager.AddAddresses(addresses, srcAddr)
-		}(seeder)
+			srvrLog.Infof("Reloaded %d RPC users", len(users))
+
+		case <-ctx.Done():
+			break out
+		}
 	}
+
+	s.wg.Done()
 }
 
 // Run starts the server and blocks until the provided context is cancelled.
@@ -3054,19 +4345,23 @@ func (s *server) Run(ctx context.Context) {
 		s.wg.Done()
 	}(ctx, s)
 
-	// Query the seeders and start the connection manager.
+	// Start the connection manager.
 	s.wg.Add(1)
 	go func(ctx context.Context, s *server) {
-		if !cfg.DisableSeeders {
-			s.querySeeders(ctx)
-		}
 		s.connManager.Run(ctx)
 		s.wg.Done()
 	}(ctx, s)
 
+	// Start the ban manager which persists changes to the bans.
+	s.wg.Add(1)
+	go func(ctx context.Context, s *server) {
+		s.banManager.Run(ctx)
+		s.wg.Done()
+	}(ctx, s)
+
 	if s.nat != nil {
 		s.wg.Add(1)
-		go s.upnpUpdateThread(ctx)
+		go s.natUpdateThread(ctx)
 	}
 
 	if !cfg.DisableRPC {
@@ -3080,6 +4375,52 @@ func (s *server) Run(ctx context.Context) {
 			s.rpcServer.Run(ctx)
 			s.wg.Done()
 		}(ctx, s)
+
+		if s.grpcServer != nil {
+			s.wg.Add(1)
+			go func(ctx context.Context, s *server) {
+				s.grpcServer.Run(ctx)
+				s.wg.Done()
+			}(ctx, s)
+		}
+
+		// Automatically renew the TLS certificate before it expires.
+		if s.rpcCertMgr != nil {
+			s.wg.Add(1)
+			go func(ctx context.Context, s *server) {
+				s.rpcCertMgr.Run(ctx)
+				s.wg.Done()
+			}(ctx, s)
+		}
+
+		if cfg.RPCAuthType == authTypeBasic && len(reloadSignals) > 0 {
+			s.wg.Add(1)
+			go s.rpcAuthReloadHandler(ctx)
+		}
+	}
+
+	if s.metricsServer != nil {
+		s.wg.Add(1)
+		go func(ctx context.Context, s *server) {
+			s.metricsServer.Run(ctx)
+			s.wg.Done()
+		}(ctx, s)
+	}
+
+	if s.diagServer != nil {
+		s.wg.Add(1)
+		go func(ctx context.Context, s *server) {
+			s.diagServer.Run(ctx)
+			s.wg.Done()
+		}(ctx, s)
+	}
+
+	if s.tracer != nil {
+		s.wg.Add(1)
+		go func(ctx context.Context, s *server) {
+			s.tracer.Run(ctx)
+			s.wg.Done()
+		}(ctx, s)
 	}
 
 	// Start the background block template generator and CPU miner if the config
@@ -3117,6 +4458,10 @@ func (s *server) Run(ctx context.Context) {
 
 	s.feeEstimator.Close()
 
+	if s.i2pSession != nil {
+		s.i2pSession.Close()
+	}
+
 	s.chain.ShutdownUtxoCache()
 
 	s.wg.Wait()
@@ -3167,48 +4512,66 @@ func parseListeners(addrs []string) ([]net.Addr, error) {
 	return netAddrs, nil
 }
 
-func (s *server) upnpUpdateThread(ctx context.Context) {
+// natUpdateThread maps the listening port via the NAT traversal method in use
+// and periodically renews the mapping before its lease expires.  The external
+// address of the NAT is refreshed on each renewal and advertised via the
+// address manager so that changes, such as an ISP assigning a new address,
+// are picked up without a restart.
+//
+// This must be run as a goroutine.
+func (s *server) natUpdateThread(ctx context.Context) {
 	// Go off immediately to prevent code duplication, thereafter we renew
-	// lease every 15 minutes.
+	// the lease periodically.
 	timer := time.NewTimer(0 * time.Second)
 	lport, _ := strconv.ParseInt(s.chainParams.DefaultPort, 10, 16)
 
-	first := true
+	var localAddr *addrmgr.NetAddress
 out:
 	for {
 		select {
 		case <-timer.C:
+			timer.Reset(natRenewInterval)
+
 			// TODO: pick external port more cleverly
 			// TODO: know which ports we are listening to on an external net.
 			// TODO: if specific listen port doesn't work then ask for wildcard
 			// listen port?
-			// XXX this assumes timeout is in seconds.
-			listenPort, err := s.nat.AddPortMapping("tcp", int(lport), int(lport),
-				"dcrd listen port", 20*60)
+			listenPort, err := s.nat.AddPortMapping("tcp", int(lport),
+				int(lport), "dcrd listen port",
+				int(natLeaseDuration/time.Second))
 			if err != nil {
-				srvrLog.Warnf("can't add UPnP port mapping: %v", err)
+				srvrLog.Warnf("Can't add NAT port mapping: %v", err)
+				continue
 			}
-			if first && err == nil {
-				// TODO: look this up periodically to see if upnp domain changed
-				// and so did ip.
-				externalip, err := s.nat.GetExternalAddress()
-				if err != nil {
-					srvrLog.Warnf("UPnP can't get external address: %v", err)
-					continue out
-				}
-				localAddr := addrmgr.NewNetAddressIPPort(externalip,
-					uint16(listenPort), s.services)
-				err = s.addrManager.AddLocalAddress(localAddr, addrmgr.UpnpPrio)
-				if err != nil {
-					srvrLog.Warnf("Failed to add UPnP local address %s: %v",
-						localAddr, err)
-				} else {
-					srvrLog.Warnf("Successfully bound via UPnP to %s",
-						localAddr)
-					first = false
-				}
+			externalip, err := s.nat.GetExternalAddress()
+			if err != nil {
+				srvrLog.Warnf("Can't get NAT external address: %v", err)
+				continue
+			}
+
+			// Nothing more to do when the external address is unchanged.
+			na := addrmgr.NewNetAddressIPPort(externalip,
+				uint16(listenPort), s.services)
+			if localAddr != nil && localAddr.Key() == na.Key() {
+				continue
+			}
+
+			// Stop advertising the previous external address since it is
+			// no longer reachable.
+			if localAddr != nil {
+				srvrLog.Infof("NAT external address changed from %s to %s",
+					localAddr, na)
+				s.addrManager.RemoveLocalAddress(localAddr)
+				localAddr = nil
+			}
+			err = s.addrManager.AddLocalAddress(na, addrmgr.UpnpPrio)
+			if err != nil {
+				srvrLog.Warnf("Failed to add NAT local address %s: %v", na,
+					err)
+				continue
 			}
-			timer.Reset(time.Minute * 15)
+			srvrLog.Infof("Successfully bound via NAT traversal to %s", na)
+			localAddr = na
 
 		case <-ctx.Done():
 			break out
@@ -3219,9 +4582,9 @@ out:
 
 	err := s.nat.DeletePortMapping("tcp", int(lport), int(lport))
 	if err != nil {
-		srvrLog.Warnf("unable to remove UPnP port mapping: %v", err)
+		srvrLog.Warnf("Unable to remove NAT port mapping: %v", err)
 	} else {
-		srvrLog.Debugf("successfully disestablished UPnP port mapping")
+		srvrLog.Debugf("Successfully disestablished NAT port mapping")
 	}
 
 	s.wg.Done()
@@ -3257,35 +4620,98 @@ func standardScriptVerifyFlags(chain *blockchain.BlockChain) (txscript.ScriptFla
 	return scriptFlags, nil
 }
 
-// genCertPair generates a key/cert pair to the paths provided.
-func genCertPair(certFile, keyFile string, altDNSNames []string, tlsCurve elliptic.Curve) error {
-	rpcsLog.Infof("Generating TLS certificates...")
+// newBlockSigner returns a function that signs blocks with the provided private
+// key such that the signature satisfies the block signing challenge of the
+// provided network.
+func newBlockSigner(params *chaincfg.Params, key *secp256k1.PrivateKey) func(*wire.MsgBlock) error {
+	challenge := params.BlockSignChallenge
+	keyBytes := key.Serialize()
+	getKey := sign.KeyClosure(func(stdaddr.Address) ([]byte, dcrec.SignatureType, bool, error) {
+		return keyBytes, dcrec.STEcdsaSecp256k1, true, nil
+	})
+	getScript := sign.ScriptClosure(func(stdaddr.Address) ([]byte, error) {
+		return nil, errors.New("block signing challenges that require " +
+			"scripts are not supported")
+	})
+	return func(block *wire.MsgBlock) error {
+		tx, err := blockchain.BlockSigningTx(block, challenge)
+		if err != nil {
+			return err
+		}
+		sigScript, err := sign.SignTxOutput(params, tx, 0, challenge,
+			txscript.SigHashAll, getKey, getScript, nil, false)
+		if err != nil {
+			return err
+		}
+		return blockchain.SetBlockSig(block, sigScript)
+	}
+}
 
-	org := "dcrd autogenerated cert"
-	validUntil := time.Now().Add(10 * 365 * 24 * time.Hour)
-	cert, key, err := certgen.NewTLSCertPair(tlsCurve, org,
-		validUntil, altDNSNames)
-	if err != nil {
-		return err
+// blockSigOverhead returns the number of bytes to reserve in block templates
+// for the signature the provided block signer adds to them.  The size of the
+// signature depends on the block signing challenge of the network, so it is
+// determined by signing a block that only consists of a coinbase.  Since the
+// length of the encoded signatures varies slightly between blocks, a small
+// amount of additional space is reserved for them as well.
+func blockSigOverhead(signBlock func(*wire.MsgBlock) error) (uint32, error) {
+	// maxSigLenVariance is the max difference in length between any two
+	// signatures a signer may produce.
+	const maxSigLenVariance = 8
+
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex, wire.TxTreeRegular),
+		SignatureScript: []byte{txscript.OP_0, txscript.OP_0},
+	})
+	coinbase.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+	block := wire.MsgBlock{Transactions: []*wire.MsgTx{coinbase}}
+	unsignedSize := block.SerializeSize()
+	if err := signBlock(&block); err != nil {
+		return 0, err
 	}
+	return uint32(block.SerializeSize()-unsignedSize) + maxSigLenVariance, nil
+}
 
-	// Write cert and key files.
-	if err = os.WriteFile(certFile, cert, 0644); err != nil {
-		return err
+// rpcTLSConfig returns the TLS configuration to use for the RPC and gRPC
+// servers.  The TLS certificate is provided by the passed certificate manager,
+// which is created if it is nil, so that the servers use any regenerated
+// certificates for new connections.
+func rpcTLSConfig(certMgr **rpcCertManager) (*tls.Config, error) {
+	if *certMgr == nil {
+		var err error
+		*certMgr, err = newRPCCertManager()
+		if err != nil {
+			return nil, err
+		}
 	}
-	if err = os.WriteFile(keyFile, key, 0600); err != nil {
-		os.Remove(certFile)
-		return err
+
+	tlsConfig := tls.Config{
+		GetCertificate: (*certMgr).GetCertificate,
+		MinVersion:     tls.VersionTLS12,
 	}
 
-	rpcsLog.Infof("Done generating TLS certificates")
-	return nil
+	if cfg.RPCAuthType == authTypeClientCert {
+		pemCerts, err := os.ReadFile(cfg.RPCClientCAs)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(pemCerts) {
+			return nil, fmt.Errorf("no certificates found in %q",
+				cfg.RPCClientCAs)
+		}
+	}
+
+	return &tlsConfig, nil
 }
 
 // setupRPCListeners returns a slice of listeners that are configured for use
 // with the RPC server depending on the configuration settings for listen
-// addresses and TLS.
-func setupRPCListeners() ([]net.Listener, error) {
+// addresses and TLS.  The passed TLS certificate manager is created when TLS is
+// used and it is nil.
+func setupRPCListeners(certMgr **rpcCertManager) ([]net.Listener, error) {
 	var notifyAddrServer boundAddrEventServer
 	if cfg.BoundAddrEvents {
 		notifyAddrServer = newBoundAddrEventServer(outgoingPipeMessages)
@@ -3293,53 +4719,15 @@ func setupRPCListeners() ([]net.Listener, error) {
 
 	// Setup TLS if not disabled.
 	listenFunc := net.Listen
-	if !cfg.DisableRPC && !cfg.DisableTLS {
-		// Generate the TLS cert and key file if both don't already exist.
-		keyFileExists := fileExists(cfg.RPCKey)
-		certFileExists := fileExists(cfg.RPCCert)
-		if len(cfg.AltDNSNames) != 0 && (keyFileExists || certFileExists) {
-			rpcsLog.Warn("Additional DNS names specified when TLS " +
-				"certificates already exist will NOT be included:")
-			rpcsLog.Warnf("- In order to create TLS certs that include the "+
-				"additional DNS names, delete %q and %q and restart the server",
-				cfg.RPCKey, cfg.RPCCert)
-		}
-		if !keyFileExists && !certFileExists {
-			curve, err := tlsCurve(cfg.TLSCurve)
-			if err != nil {
-				return nil, err
-			}
-			err = genCertPair(cfg.RPCCert, cfg.RPCKey, cfg.AltDNSNames, curve)
-			if err != nil {
-				return nil, err
-			}
-		}
-		keypair, err := tls.LoadX509KeyPair(cfg.RPCCert, cfg.RPCKey)
+	if !cfg.DisableRPC && !cfg.DisableTLS && len(cfg.RPCListeners) > 0 {
+		tlsConfig, err := rpcTLSConfig(certMgr)
 		if err != nil {
 			return nil, err
 		}
 
-		tlsConfig := tls.Config{
-			Certificates: []tls.Certificate{keypair},
-			MinVersion:   tls.VersionTLS12,
-		}
-
-		if cfg.RPCAuthType == authTypeClientCert {
-			pemCerts, err := os.ReadFile(cfg.RPCClientCAs)
-			if err != nil {
-				return nil, err
-			}
-			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
-			tlsConfig.ClientCAs = x509.NewCertPool()
-			if !tlsConfig.ClientCAs.AppendCertsFromPEM(pemCerts) {
-				return nil, fmt.Errorf("no certificates found in %q",
-					cfg.RPCClientCAs)
-			}
-		}
-
 		// Change the standard net.Listen function to the tls one.
 		listenFunc = func(net string, laddr string) (net.Listener, error) {
-			return tls.Listen(net, laddr, &tlsConfig)
+			return tls.Listen(net, laddr, tlsConfig)
 		}
 	}
 
@@ -3362,6 +4750,98 @@ func setupRPCListeners() ([]net.Listener, error) {
 	return listeners, nil
 }
 
+// setupRPCUnixListeners returns a slice of unix domain socket listeners for the
+// configured RPC unix socket paths.  The sockets are only made accessible by
+// the owner since connections on them do not require credentials.
+func setupRPCUnixListeners() ([]net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(cfg.RPCUnixListeners))
+	for _, path := range cfg.RPCUnixListeners {
+		// Remove stale sockets left behind by an unclean shutdown.  Sockets
+		// that still accept connections are in use and left alone.
+		fi, err := os.Lstat(path)
+		if err == nil && fi.Mode()&os.ModeSocket != 0 {
+			if conn, err := net.Dial("unix", path); err == nil {
+				conn.Close()
+			} else {
+				os.Remove(path)
+			}
+		}
+
+		listener, err := listenUnixPrivate(path)
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", path, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// setupGRPCListeners returns a slice of listeners for the configured gRPC
+// listen addresses.  Unlike the RPC listeners, TLS is not handled by the
+// listeners since the gRPC server negotiates it itself.
+func setupGRPCListeners() ([]net.Listener, error) {
+	netAddrs, err := parseListeners(cfg.GRPCListeners)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			grpcLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// setupMetricsListeners returns a slice of listeners for the configured
+// metrics listen addresses.
+func setupMetricsListeners() ([]net.Listener, error) {
+	netAddrs, err := parseListeners(cfg.MetricsListeners)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			mtrcLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// setupDiagListeners returns a slice of listeners for the configured
+// diagnostics listen addresses.
+func setupDiagListeners() ([]net.Listener, error) {
+	netAddrs, err := parseListeners(cfg.DiagListeners)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(netAddrs))
+	for _, addr := range netAddrs {
+		listener, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			diagLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
 // newServer returns a new dcrd server configured to listen on addr for the
 // decred network type specified by chainParams.  Use start to begin accepting
 // connections from peers.
@@ -3369,11 +4849,39 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	utxoDb *leveldb.DB, chainParams *chaincfg.Params,
 	dataDir string) (*server, error) {
 
+	// Track the operations on the block database for the metrics endpoint
+	// when requested.
+	var dbOps *dbOpMetrics
+	if cfg.DBOpMetrics {
+		dbOps = newDBOpMetrics()
+		db = database.WithHooks(db, dbOps)
+	}
+
 	amgr := addrmgr.New(cfg.DataDir, dcrdLookup)
+	if cfg.ASMap != "" {
+		asmap, err := loadASMap(cfg.ASMap)
+		if err != nil {
+			return nil, err
+		}
+		amgr.SetASMap(asmap)
+		srvrLog.Infof("Loaded AS map from %s", cfg.ASMap)
+	}
+	banMgr := banmgr.New(&banmgr.Config{
+		BanFile: filepath.Join(cfg.DataDir, banListFilename),
+	})
+	if err := banMgr.Load(); err != nil {
+		srvrLog.Warnf("Unable to load persisted bans: %v", err)
+	}
 	services := defaultServices
+	if cfg.TxReconciliation && !cfg.BlocksOnly {
+		services |= wire.SFNodeTxRecon
+	}
+	if cfg.P2PEncryption {
+		services |= wire.SFNodeEncryption
+	}
 
 	var listeners []net.Listener
-	var nat *upnpNAT
+	var nat NAT
 	if !cfg.DisableListen {
 		var err error
 		listeners, nat, err = initListeners(ctx, chainParams, amgr, listenAddrs,
@@ -3386,6 +4894,25 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 		}
 	}
 
+	// Create a session with the I2P SAM bridge when requested in order to
+	// connect to and accept connections from I2P peers.  Failure to do so is
+	// not fatal since the other networks are still usable.
+	var i2pSession *i2p.Session
+	if cfg.I2PSAM != "" {
+		var err error
+		i2pSession, err = newI2PSession(ctx)
+		if err != nil {
+			srvrLog.Warnf("Unable to create I2P session with SAM bridge %s: %v",
+				cfg.I2PSAM, err)
+		} else if !cfg.DisableListen {
+			listeners = append(listeners, i2pSession)
+			addr := i2pSession.Addr().String()
+			if err := addLocalAddress(amgr, addr, services); err != nil {
+				amgrLog.Warnf("Skipping I2P address %s: %v", addr, err)
+			}
+		}
+	}
+
 	// Create a SigCache instance.
 	sigCache, err := txscript.NewSigCache(cfg.SigCacheMaxSize)
 	if err != nil {
@@ -3395,6 +4922,8 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	s := server{
 		chainParams:          chainParams,
 		addrManager:          amgr,
+		banManager:           banMgr,
+		seederScorer:         newSeederScorer(),
 		newPeers:             make(chan *serverPeer, cfg.MaxPeers),
 		donePeers:            make(chan *serverPeer, cfg.MaxPeers),
 		banPeers:             make(chan *serverPeer, cfg.MaxPeers),
@@ -3406,6 +4935,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 		db:                   db,
 		timeSource:           blockchain.NewMedianTime(),
 		services:             services,
+		i2pSession:           i2pSession,
 		sigCache:             sigCache,
 		subsidyCache:         standalone.NewSubsidyCache(chainParams),
 		lotteryDataBroadcast: make(map[chainhash.Hash]struct{}),
@@ -3413,6 +4943,31 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 			recentlyConfirmedTxnsFPRate),
 		indexSubscriber: indexers.NewIndexSubscriber(ctx),
 		quit:            make(chan struct{}),
+		bytesSentPerMsg: make(map[string]uint64),
+		bytesRecvPerMsg: make(map[string]uint64),
+	}
+	s.p2pMetrics = newP2PMetrics(&s)
+	s.nodeMetrics = &nodeMetrics{}
+	if len(cfg.MetricsListeners) > 0 {
+		s.nodeMetrics = newNodeMetrics()
+	}
+	if cfg.TracingEndpoint != "" {
+		s.tracer = tracing.New(&tracing.Config{
+			Endpoint:       cfg.TracingEndpoint,
+			ServiceName:    "dcrd",
+			ServiceVersion: version.String(),
+			SampleRatio:    cfg.TracingSampleRatio,
+		})
+	}
+	if !cfg.DisableSeeders {
+		s.seederScorer.load(filepath.Join(cfg.DataDir, seederStatsFilename))
+	}
+	if cfg.MaxUploadRate != 0 {
+		s.uploadLimiter = peer.NewRateLimiter(uint64(cfg.MaxUploadRate)*1024, 0)
+	}
+	if cfg.MaxDownloadRate != 0 {
+		s.downloadLimiter = peer.NewRateLimiter(
+			uint64(cfg.MaxDownloadRate)*1024, 0)
 	}
 
 	// Convert the minimum known work to a uint256 when it exists.  Ideally, the
@@ -3469,7 +5024,8 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	}
 
 	// Create a new block chain instance with the appropriate configuration.
-	utxoBackend := blockchain.NewLevelDbUtxoBackend(utxoDb)
+	utxoBackend := blockchain.NewEncryptedLevelDbUtxoBackend(utxoDb,
+		cfg.dbOpts.EncryptionKey)
 	utxoCache := blockchain.NewUtxoCache(&blockchain.UtxoCacheConfig{
 		Backend:      utxoBackend,
 		FlushBlockDB: s.db.Flush,
@@ -3477,16 +5033,18 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	})
 	s.chain, err = blockchain.New(ctx,
 		&blockchain.Config{
-			DB:              s.db,
-			UtxoBackend:     utxoBackend,
-			ChainParams:     s.chainParams,
-			AssumeValid:     assumeValid,
-			TimeSource:      s.timeSource,
-			Notifications:   s.handleBlockchainNotification,
-			SigCache:        s.sigCache,
-			SubsidyCache:    s.subsidyCache,
-			IndexSubscriber: s.indexSubscriber,
-			UtxoCache:       utxoCache,
+			DB:               s.db,
+			UtxoBackend:      utxoBackend,
+			ChainParams:      s.chainParams,
+			AssumeValid:      assumeValid,
+			TimeSource:       s.timeSource,
+			Notifications:    s.handleBlockchainNotification,
+			SigCache:         s.sigCache,
+			SubsidyCache:     s.subsidyCache,
+			IndexSubscriber:  s.indexSubscriber,
+			UtxoCache:        utxoCache,
+			ProcessBlockTime: s.nodeMetrics.processBlockTime,
+			Tracer:           s.tracer,
 		})
 	if err != nil {
 		return nil, err
@@ -3508,17 +5066,36 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 			return nil, err
 		}
 	}
-	err = s.indexSubscriber.CatchUp(ctx, s.db, queryer)
-	if err != nil {
-		return nil, err
+	if cfg.AddrIndex {
+		indxLog.Info("Address index is enabled")
+		s.addrIndex, err = indexers.NewAddrIndex(s.indexSubscriber, db, queryer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SpentIndex {
+		indxLog.Info("Spent output index is enabled")
+		s.spentIndex, err = indexers.NewSpentIndex(s.indexSubscriber, db,
+			queryer)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	// Catch up the indexes in the background so the server is able to start
+	// serving while any newly enabled indexes are created.  The RPCs that
+	// depend on an index report it is not synced until it is caught up.
+	s.indexSubscriber.CatchUpInBackground(queryer)
+
 	txC := mempool.Config{
 		Policy: mempool.Policy{
 			EnableAncestorTracking: len(cfg.miningAddrs) > 0,
 			AcceptNonStd:           cfg.AcceptNonStd,
+			TxPolicies:             mempool.DefaultTxPolicies(),
 			MaxOrphanTxs:           cfg.MaxOrphanTxs,
 			MaxOrphanTxSize:        mempool.MaxStandardTxSize,
+			MaxOrphanTxsPerTag:     cfg.MaxOrphanTxsPeer,
+			MaxOrphanPoolSize:      mempool.DefaultMaxOrphanPoolSize,
 			MaxSigOpsPerTx:         blockchain.MaxSigOpsPerBlock / 5,
 			MinRelayTxFee:          cfg.minRelayTxFee,
 			AllowOldVotes:          cfg.AllowOldVotes,
@@ -3566,6 +5143,14 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 				s.rpcServer.NotifyTSpend(tx)
 			}
 		},
+		OnTxEvent: func(event *mempool.TxEvent) {
+			if s.rpcServer != nil {
+				s.rpcServer.NotifyMempoolEvent(event)
+			}
+			if s.grpcServer != nil {
+				s.grpcServer.NotifyMempoolEvent(event)
+			}
+		},
 		IsTreasuryAgendaActive: func() (bool, error) {
 			tipHash := &s.chain.BestSnapshot().Hash
 			return s.chain.IsTreasuryAgendaActive(tipHash)
@@ -3582,8 +5167,14 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 			tipHash := s.chain.BestSnapshot().Hash
 			return s.chain.CheckTSpendExists(tipHash, tspend)
 		},
+		AcceptedTxns: s.nodeMetrics.acceptedTxns,
+		OrphanedTxns: s.nodeMetrics.orphanedTxns,
+		RejectedTxns: s.nodeMetrics.rejectedTxns,
+		Tracer:       s.tracer,
 	}
 	s.txMemPool = mempool.New(&txC)
+	s.nodeMetrics.chain = s.chain
+	s.nodeMetrics.txMemPool = s.txMemPool
 
 	s.syncManager = netsync.New(&netsync.Config{
 		PeerNotifier:          &s,
@@ -3592,9 +5183,11 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 		TimeSource:            s.timeSource,
 		TxMemPool:             s.txMemPool,
 		NoMiningStateSync:     cfg.NoMiningStateSync,
+		BlocksOnly:            cfg.BlocksOnly,
 		MaxPeers:              cfg.MaxPeers,
 		MaxOrphanTxs:          cfg.MaxOrphanTxs,
 		RecentlyConfirmedTxns: s.recentlyConfirmedTxns,
+		Tracer:                s.tracer,
 	})
 
 	// Dump the blockchain and quit if requested.
@@ -3623,6 +5216,16 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 				return standardScriptVerifyFlags(s.chain)
 			},
 		}
+		var signBlock func(*wire.MsgBlock) error
+		var sigOverhead uint32
+		if cfg.blockSignKey != nil {
+			signBlock = newBlockSigner(s.chainParams, cfg.blockSignKey)
+			sigOverhead, err = blockSigOverhead(signBlock)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign blocks with the "+
+					"block signing key: %w", err)
+			}
+		}
 		tg := mining.NewBlkTmplGenerator(&mining.Config{
 			Policy:                     &policy,
 			TxSource:                   s.txMemPool,
@@ -3661,7 +5264,9 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 			NewUtxoViewpoint: func() *blockchain.UtxoViewpoint {
 				return blockchain.NewUtxoViewpoint(utxoCache)
 			},
-			TipGeneration: s.chain.TipGeneration,
+			SignBlock:        signBlock,
+			BlockSigOverhead: sigOverhead,
+			TipGeneration:    s.chain.TipGeneration,
 			ValidateTransactionScripts: func(tx *dcrutil.Tx,
 				utxoView *blockchain.UtxoViewpoint, flags txscript.ScriptFlags,
 				isAutoRevocationsEnabled bool) error {
@@ -3697,7 +5302,33 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	// network.
 	var newAddressFunc func() (net.Addr, error)
 	if !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0 {
+		// Load the anchor connections persisted on the previous shutdown when
+		// eclipse resistance is enabled so they are re-established before any
+		// addresses are selected from the address manager.
+		if cfg.EclipseResist {
+			s.loadPendingAnchors()
+		}
+
+		onionDialable := !cfg.NoOnion && (cfg.Proxy != "" ||
+			cfg.OnionProxy != "")
 		newAddressFunc = func() (net.Addr, error) {
+			// Prefer connections to re-establish over the encrypted
+			// transport so the peers are not replaced.
+			if addr := s.nextEncryptedReconnect(); addr != nil {
+				srvrLog.Debugf("Reconnecting to %s over the encrypted "+
+					"transport", addr)
+				return addr, nil
+			}
+
+			// Prefer the anchor connections from the previous run.  They are
+			// not permanent, so they are replaced by the usual address
+			// selection below when they are lost.
+			if anchor := s.nextPendingAnchor(); anchor != nil {
+				srvrLog.Infof("Re-establishing anchor connection to %s",
+					anchor)
+				return anchor, nil
+			}
+
 			for tries := 0; tries < 100; tries++ {
 				addr := s.addrManager.GetAddress()
 				if addr == nil {
@@ -3707,22 +5338,51 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 				// Address will not be invalid, local or unroutable
 				// because addrmanager rejects those on addition.
 				// Just check that we don't already have an address
-				// in the same group so that we are not connecting
-				// to the same network segment at the expense of
-				// others.
+				// in the same netgroup or autonomous system so that
+				// we are not connecting to the same network segment
+				// or operator at the expense of others.
 				netAddr := addr.NetAddress()
-				if s.OutboundGroupCount(netAddr.GroupKey()) != 0 {
+				var groupInUse bool
+				for _, key := range s.outboundGroupKeys(netAddr) {
+					if s.OutboundGroupCount(key) != 0 {
+						groupInUse = true
+						break
+					}
+				}
+				if groupInUse {
 					continue
 				}
 
+				// Skip TORv3 addresses when there is no proxy available
+				// to dial them and I2P addresses when there is no I2P
+				// session available to dial them.
+				if netAddr.Type == addrmgr.TORv3Address && !onionDialable {
+					continue
+				}
+				isI2P := netAddr.Type == addrmgr.I2PAddress
+				if isI2P && s.i2pSession == nil {
+					continue
+				}
+
+				// Skip banned addresses.
+				if netAddr.Type == addrmgr.IPv4Address ||
+					netAddr.Type == addrmgr.IPv6Address {
+
+					_, banned := s.banManager.IsBanned(netAddr.IP)
+					if banned {
+						continue
+					}
+				}
+
 				// only allow recent nodes (10mins) after we failed 30
 				// times
 				if tries < 30 && time.Since(addr.LastAttempt()) < 10*time.Minute {
 					continue
 				}
 
-				// allow nondefault ports after 50 failed tries.
-				if fmt.Sprintf("%d", netAddr.Port) !=
+				// allow nondefault ports after 50 failed tries.  I2P
+				// addresses do not have ports.
+				if !isI2P && fmt.Sprintf("%d", netAddr.Port) !=
 					s.chainParams.DefaultPort && tries < 50 {
 					continue
 				}
@@ -3755,15 +5415,20 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 	s.connManager = cmgr
 
 	// Start up persistent peers.
-	permanentPeers := cfg.ConnectPeers
+	permanentPeers, permanentPeerPerms := cfg.ConnectPeers, cfg.connectPeerPerms
 	if len(permanentPeers) == 0 {
-		permanentPeers = cfg.AddPeers
+		permanentPeers, permanentPeerPerms = cfg.AddPeers, cfg.addPeerPerms
 	}
+	s.permanentPeerPerms = make(map[string]peerPermissions,
+		len(permanentPeerPerms))
 	for _, addr := range permanentPeers {
 		tcpAddr, err := addrStringToNetAddr(addr)
 		if err != nil {
 			return nil, err
 		}
+		if perms, ok := permanentPeerPerms[addr]; ok {
+			s.permanentPeerPerms[tcpAddr.String()] = perms
+		}
 
 		go s.connManager.Connect(ctx,
 			&connmgr.ConnReq{
@@ -3772,55 +5437,88 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 			})
 	}
 
+	// The database metrics are served by both the RPC and metrics servers.
+	dbm := &dbMetrics{
+		blockDB:    db,
+		utxoDB:     utxoDb,
+		utxoDBOpts: cfg.dbOpts,
+		chain:      s.chain,
+		ops:        dbOps,
+	}
+
 	if !cfg.DisableRPC {
 		// Setup listeners for the configured RPC listen addresses and
 		// TLS settings.
-		rpcListeners, err := setupRPCListeners()
+		rpcListeners, err := setupRPCListeners(&s.rpcCertMgr)
+		if err != nil {
+			return nil, err
+		}
+		rpcUnixListeners, err := setupRPCUnixListeners()
 		if err != nil {
 			return nil, err
 		}
 
-		if len(rpcListeners) == 0 {
+		if len(rpcListeners) == 0 && len(rpcUnixListeners) == 0 {
 			return nil, errors.New("no usable rpc listen addresses")
 		}
 
 		rpcsConfig := rpcserver.Config{
-			Listeners:    rpcListeners,
-			ConnMgr:      &rpcConnManager{&s},
-			SyncMgr:      &rpcSyncMgr{server: &s, syncMgr: s.syncManager},
-			FeeEstimator: s.feeEstimator,
-			TimeSource:   s.timeSource,
-			Services:     s.services,
-			AddrManager:  s.addrManager,
-			Clock:        &rpcClock{},
-			SubsidyCache: s.subsidyCache,
-			Chain:        &rpcChain{s.chain},
-			ChainParams:  chainParams,
+			Listeners:     rpcListeners,
+			UnixListeners: rpcUnixListeners,
+			ConnMgr:       &rpcConnManager{&s},
+			SyncMgr:       &rpcSyncMgr{server: &s, syncMgr: s.syncManager},
+			FeeEstimator:  s.feeEstimator,
+			TimeSource:    s.timeSource,
+			Services:      s.services,
+			AddrManager:   s.addrManager,
+			Clock:         &rpcClock{},
+			SubsidyCache:  s.subsidyCache,
+			Chain:         &rpcChain{s.chain},
+			ChainStateBackuper: &rpcChainStateBackuper{
+				chain: s.chain,
+				db:    db,
+			},
+			ChainParams: chainParams,
 			SanityChecker: &rpcSanityChecker{
 				chain:       s.chain,
 				timeSource:  s.timeSource,
 				chainParams: chainParams,
 			},
-			DB:                   db,
-			TxMempooler:          s.txMemPool,
-			CPUMiner:             &rpcCPUMiner{s.cpuMiner},
-			NetInfo:              cfg.generateNetworkInfo(),
-			MinRelayTxFee:        cfg.minRelayTxFee,
-			Proxy:                cfg.Proxy,
-			RPCUser:              cfg.RPCUser,
-			RPCPass:              cfg.RPCPass,
-			RPCLimitUser:         cfg.RPCLimitUser,
-			RPCLimitPass:         cfg.RPCLimitPass,
-			RPCMaxClients:        cfg.RPCMaxClients,
-			RPCMaxConcurrentReqs: cfg.RPCMaxConcurrentReqs,
-			RPCMaxWebsockets:     cfg.RPCMaxWebsockets,
-			TestNet:              cfg.TestNet,
-			MiningAddrs:          cfg.miningAddrs,
-			AllowUnsyncedMining:  cfg.AllowUnsyncedMining,
-			MaxProtocolVersion:   maxProtocolVersion,
-			UserAgentVersion:     userAgentVersion,
-			LogManager:           &rpcLogManager{},
-			FiltererV2:           s.chain,
+			DB:                       db,
+			TxMempooler:              s.txMemPool,
+			CPUMiner:                 &rpcCPUMiner{s.cpuMiner},
+			NetInfo:                  cfg.generateNetworkInfo(),
+			MinRelayTxFee:            cfg.minRelayTxFee,
+			Proxy:                    cfg.Proxy,
+			BanDuration:              cfg.BanDuration,
+			DataDir:                  cfg.DataDir,
+			RPCUser:                  cfg.RPCUser,
+			RPCPass:                  cfg.RPCPass,
+			RPCLimitUser:             cfg.RPCLimitUser,
+			RPCLimitPass:             cfg.RPCLimitPass,
+			Users:                    cfg.rpcUsers,
+			EnableREST:               cfg.EnableREST,
+			RESTToken:                cfg.RESTToken,
+			RPCMaxClients:            cfg.RPCMaxClients,
+			RPCMaxConcurrentReqs:     cfg.RPCMaxConcurrentReqs,
+			RPCMaxWebsockets:         cfg.RPCMaxWebsockets,
+			RPCUserReqRate:           cfg.RPCUserReqRate,
+			RPCIPReqRate:             cfg.RPCIPReqRate,
+			RPCUserMaxConcurrentReqs: cfg.RPCUserMaxReqs,
+			RPCIPMaxConcurrentReqs:   cfg.RPCIPMaxReqs,
+			RPCSlowCallThreshold:     cfg.RPCSlowCall,
+			TestNet:                  cfg.TestNet,
+			MiningAddrs:              cfg.miningAddrs,
+			AllowUnsyncedMining:      cfg.AllowUnsyncedMining,
+			MaxProtocolVersion:       maxProtocolVersion,
+			UserAgentVersion:         userAgentVersion,
+			LogManager:               &rpcLogManager{},
+			FiltererV2:               s.chain,
+			MetricsSources:           []rpcserver.MetricsSource{s.p2pMetrics, dbm},
+		}
+		if len(cfg.MetricsListeners) > 0 {
+			rpcsConfig.MetricsSources = append(rpcsConfig.MetricsSources,
+				s.nodeMetrics)
 		}
 		if s.existsAddrIndex != nil {
 			rpcsConfig.ExistsAddresser = s.existsAddrIndex
@@ -3831,17 +5529,102 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 		if s.txIndex != nil {
 			rpcsConfig.TxIndexer = s.txIndex
 		}
+		if s.addrIndex != nil {
+			rpcsConfig.AddrIndexer = s.addrIndex
+		}
+		if s.spentIndex != nil {
+			rpcsConfig.SpentIndexer = s.spentIndex
+		}
 
 		s.rpcServer, err = rpcserver.New(&rpcsConfig)
 		if err != nil {
 			return nil, err
 		}
+		if s.rpcCertMgr != nil {
+			s.rpcCertMgr.notifyRenewed = s.rpcServer.NotifyTLSCertRenewed
+		}
 
 		// Signal process shutdown when the RPC server requests it.
 		go func() {
 			<-s.rpcServer.RequestedProcessShutdown()
 			shutdownRequestChannel <- struct{}{}
 		}()
+
+		if len(cfg.GRPCListeners) > 0 {
+			grpcListeners, err := setupGRPCListeners()
+			if err != nil {
+				return nil, err
+			}
+			if len(grpcListeners) == 0 {
+				return nil, errors.New("no usable grpc listen addresses")
+			}
+
+			grpcsConfig := grpcserver.Config{
+				Listeners:   grpcListeners,
+				Chain:       s.chain,
+				TxMempooler: s.txMemPool,
+				ConnMgr:     &rpcConnManager{&s},
+				Authorizer:  &grpcAuthorizer{server: s.rpcServer},
+			}
+			if !cfg.DisableTLS {
+				grpcsConfig.TLSConfig, err = rpcTLSConfig(&s.rpcCertMgr)
+				if err != nil {
+					return nil, err
+				}
+			}
+			s.grpcServer = grpcserver.New(&grpcsConfig)
+		}
+	}
+
+	if len(cfg.MetricsListeners) > 0 {
+		metricsListeners, err := setupMetricsListeners()
+		if err != nil {
+			return nil, err
+		}
+		if len(metricsListeners) == 0 {
+			return nil, errors.New("no usable metrics listen addresses")
+		}
+
+		// Serve the RPC metrics along with the others when the RPC server
+		// is enabled.
+		sources := []metrics.Source{s.p2pMetrics, dbm, s.nodeMetrics}
+		if s.rpcServer != nil {
+			sources = append(sources, s.rpcServer)
+		}
+		s.metricsServer = metrics.New(&metrics.Config{
+			Listeners: metricsListeners,
+			Sources:   sources,
+			Username:  cfg.MetricsUser,
+			Password:  cfg.MetricsPass,
+		})
+	}
+
+	if len(cfg.DiagListeners) > 0 {
+		diagListeners, err := setupDiagListeners()
+		if err != nil {
+			return nil, err
+		}
+		if len(diagListeners) == 0 {
+			return nil, errors.New("no usable diagnostics listen addresses")
+		}
+
+		// The diagnostics server uses the admin credentials and TLS settings
+		// of the RPC server.
+		diagConfig := diagnostics.Config{
+			Listeners: diagListeners,
+			Username:  cfg.RPCUser,
+			Password:  cfg.RPCPass,
+		}
+		if !cfg.DisableTLS {
+			diagConfig.TLSConfig, err = rpcTLSConfig(&s.rpcCertMgr)
+			if err != nil {
+				return nil, err
+			}
+		}
+		s.diagServer, err = diagnostics.New(&diagConfig)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &s, nil
@@ -3850,7 +5633,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB,
 // initListeners initializes the configured net listeners and adds any bound
 // addresses to the address manager. Returns the listeners and a NAT interface,
 // which is non-nil if UPnP is in use.
-func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.AddrManager, listenAddrs []string, services wire.ServiceFlag) ([]net.Listener, *upnpNAT, error) {
+func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.AddrManager, listenAddrs []string, services wire.ServiceFlag) ([]net.Listener, NAT, error) {
 	// Listen for TCP connections at the configured addresses
 	netAddrs, err := parseListeners(listenAddrs)
 	if err != nil {
@@ -3874,7 +5657,7 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 		notifyAddrServer.notifyP2PAddress(listener.Addr().String())
 	}
 
-	var nat *upnpNAT
+	var nat NAT
 	if len(cfg.ExternalIPs) != 0 {
 		defaultPort, err := strconv.ParseUint(params.DefaultPort, 10, 16)
 		if err != nil {
@@ -3911,13 +5694,23 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 			}
 		}
 	} else {
+		// A nil nat here is fine, it just means no NAT traversal method is
+		// available on the network.
 		if cfg.Upnp {
-			var err error
-			nat, err = discover(ctx)
+			upnp, err := discover(ctx)
 			if err != nil {
 				srvrLog.Warnf("Can't discover upnp: %v", err)
+			} else {
+				nat = upnp
+			}
+		}
+		if nat == nil && cfg.NATPMP {
+			pmp, err := discoverPMP()
+			if err != nil {
+				srvrLog.Warnf("Can't discover NAT-PMP or PCP gateway: %v", err)
+			} else {
+				nat = pmp
 			}
-			// nil nat here is fine, just means no upnp on network.
 		}
 
 		// Add bound addresses to address manager to be advertised to peers.
@@ -3933,15 +5726,54 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 	return listeners, nat, nil
 }
 
+// onionAddr implements the net.Addr interface and represents a tor address.
+// It is used for onion addresses since they can't be resolved to IP addresses
+// and must instead be dialed by name via the tor proxy.
+type onionAddr struct {
+	addr string
+}
+
+// String returns the onion address.
+//
+// This is part of the net.Addr interface.
+func (oa *onionAddr) String() string {
+	return oa.addr
+}
+
+// Network returns "tcp" since onion addresses are dialed via tcp through the
+// tor proxy.
+//
+// This is part of the net.Addr interface.
+func (oa *onionAddr) Network() string {
+	return "tcp"
+}
+
 // addrStringToNetAddr takes an address in the form of 'host:port' and returns
 // a net.Addr which maps to the original address with any host names resolved
-// to IP addresses.
+// to IP addresses.  Onion addresses are not resolved since they are dialed by
+// name via the tor proxy.
 func addrStringToNetAddr(addr string) (net.Addr, error) {
 	host, strPort, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, err
 	}
 
+	// Onion addresses are dialed by name.
+	if strings.HasSuffix(host, ".onion") {
+		if cfg.NoOnion {
+			return nil, errors.New("tor has been disabled")
+		}
+		return &onionAddr{addr: addr}, nil
+	}
+
+	// I2P addresses are dialed by name via the I2P session.
+	if strings.HasSuffix(host, ".b32.i2p") {
+		if cfg.I2PSAM == "" {
+			return nil, errors.New("i2p has not been enabled")
+		}
+		return &i2p.Addr{Host: host}, nil
+	}
+
 	// Attempt to look up an IP address associated with the parsed host.
 	// The dcrdLookup function will transparently handle performing the
 	// lookup over Tor if necessary.
@@ -3964,6 +5796,35 @@ func addrStringToNetAddr(addr string) (net.Addr, error) {
 	}, nil
 }
 
+// newI2PSession creates a new session with the I2P SAM bridge specified by the
+// configuration.  The private key of the I2P destination is loaded from the
+// data directory when it exists, or otherwise stored there once it is created,
+// so that the same I2P address is used across restarts.
+func newI2PSession(ctx context.Context) (*i2p.Session, error) {
+	keyPath := filepath.Join(cfg.DataDir, i2pPrivKeyFilename)
+	privKey, err := os.ReadFile(keyPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	session, err := i2p.NewSession(ctx, &i2p.Config{
+		SAMAddr:    cfg.I2PSAM,
+		PrivateKey: strings.TrimSpace(string(privKey)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(privKey) == 0 {
+		err := os.WriteFile(keyPath, []byte(session.PrivateKey()), 0600)
+		if err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
+	srvrLog.Infof("Created I2P session with address %s", session.Addr())
+	return session, nil
+}
+
 // addLocalAddress adds an address that this node is listening on to the
 // address manager so that it may be relayed to peers.
 func addLocalAddress(addrMgr *addrmgr.AddrManager, addr string, services wire.ServiceFlag) error {
@@ -4011,28 +5872,29 @@ func addLocalAddress(addrMgr *addrmgr.AddrManager, addr string, services wire.Se
 	return nil
 }
 
-// isWhitelisted returns whether the IP address is included in the whitelisted
-// networks and IPs.
-func isWhitelisted(addr net.Addr) bool {
+// whitelistPermissions returns the combined permissions of all whitelisted
+// networks and IPs that include the IP address.
+func whitelistPermissions(addr net.Addr) peerPermissions {
 	if len(cfg.whitelists) == 0 {
-		return false
+		return 0
 	}
 
 	host, _, err := net.SplitHostPort(addr.String())
 	if err != nil {
 		srvrLog.Warnf("Unable to SplitHostPort on '%s': %v", addr, err)
-		return false
+		return 0
 	}
 	ip := net.ParseIP(host)
 	if ip == nil {
 		srvrLog.Warnf("Unable to parse IP '%s'", addr)
-		return false
+		return 0
 	}
 
-	for _, ipnet := range cfg.whitelists {
-		if ipnet.Contains(ip) {
-			return true
+	var perms peerPermissions
+	for _, entry := range cfg.whitelists {
+		if entry.ipnet.Contains(ip) {
+			perms |= entry.perms
 		}
 	}
-	return false
+	return perms
 }
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestEncryptionDowngraded ensures only outbound connections that were not
+// encrypted to peers that advertise support for the encrypted transport are
+// detected as downgraded.
+func TestEncryptionDowngraded(t *testing.T) {
+	const encSvcs = wire.SFNodeNetwork | wire.SFNodeEncryption
+	tests := []struct {
+		name      string
+		encrypted bool
+		inbound   bool
+		services  wire.ServiceFlag
+		want      bool
+	}{{
+		name:     "unencrypted outbound to peer with encryption",
+		services: encSvcs,
+		want:     true,
+	}, {
+		name:      "encrypted outbound to peer with encryption",
+		encrypted: true,
+		services:  encSvcs,
+	}, {
+		name:     "unencrypted outbound to peer without encryption",
+		services: wire.SFNodeNetwork,
+	}, {
+		name:     "unencrypted inbound from peer with encryption",
+		inbound:  true,
+		services: encSvcs,
+	}}
+	for _, test := range tests {
+		got := encryptionDowngraded(test.encrypted, test.inbound,
+			test.services)
+		if got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestEncryptedReconnects ensures connections queued to be re-established over
+// the encrypted transport are handed out in order and that the queue is
+// limited.
+func TestEncryptedReconnects(t *testing.T) {
+	s := &server{}
+	if addr := s.nextEncryptedReconnect(); addr != nil {
+		t.Fatalf("unexpected reconnect from empty queue: %v", addr)
+	}
+
+	addrs := make([]net.Addr, 0, maxEncryptedReconnects+1)
+	for i := 0; i < maxEncryptedReconnects+1; i++ {
+		addr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("10.0.0.%d:9108",
+			i+1))
+		if err != nil {
+			t.Fatalf("unable to resolve address: %v", err)
+		}
+		addrs = append(addrs, addr)
+		s.queueEncryptedReconnect(addr)
+	}
+	for i := 0; i < maxEncryptedReconnects; i++ {
+		addr := s.nextEncryptedReconnect()
+		if addr != addrs[i] {
+			t.Fatalf("unexpected reconnect %d -- got %v, want %v", i, addr,
+				addrs[i])
+		}
+	}
+	if addr := s.nextEncryptedReconnect(); addr != nil {
+		t.Fatalf("reconnect queue exceeded its limit: %v", addr)
+	}
+}
+
+// TestBlockSigOverhead ensures the space reserved for block signatures is
+// large enough for the signatures added to blocks.
+func TestBlockSigOverhead(t *testing.T) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	params := *chaincfg.SimNetParams()
+	pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, &params)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	_, params.BlockSignChallenge = addr.PaymentScript()
+	signBlock := newBlockSigner(&params, key)
+
+	overhead, err := blockSigOverhead(signBlock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Sign several blocks with differing contents, and therefore signatures,
+	// and ensure the signature never exceeds the reserved space.
+	for i := 0; i < 50; i++ {
+		coinbase := wire.NewMsgTx()
+		coinbase.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+				wire.MaxPrevOutIndex, wire.TxTreeRegular),
+			SignatureScript: []byte{0x51, 0x51},
+		})
+		coinbase.AddTxOut(wire.NewTxOut(int64(i), []byte{0x51}))
+		block := wire.MsgBlock{Transactions: []*wire.MsgTx{coinbase}}
+		unsignedSize := block.SerializeSize()
+		if err := signBlock(&block); err != nil {
+			t.Fatalf("unexpected error signing block %d: %v", i, err)
+		}
+		sigSize := uint32(block.SerializeSize() - unsignedSize)
+		if sigSize > overhead {
+			t.Fatalf("signature size %d of block %d exceeds the reserved "+
+				"overhead %d", sigSize, i, overhead)
+		}
+	}
+
+	// Ensure the reserved space is not excessive for a challenge that only
+	// requires a single signature and public key.
+	if overhead > 150 {
+		t.Fatalf("unexpectedly large overhead %d", overhead)
+	}
+}
@@ -19,6 +19,11 @@ var shutdownRequestChannel = make(chan struct{})
 // shutdown.  This may be modified during init depending on the platform.
 var interruptSignals = []os.Signal{os.Interrupt}
 
+// reloadSignals defines the signals to catch in order to reload the parts of
+// the configuration that support it, such as the RPC users, without
+// restarting.  This may be modified during init depending on the platform.
+var reloadSignals []os.Signal
+
 // shutdownListener listens for OS Signals such as SIGINT (Ctrl+C) and shutdown
 // requests from shutdownRequestChannel.  It returns a context that is canceled
 // when either signal is received.
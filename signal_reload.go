@@ -0,0 +1,15 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+//
+//go:build aix || android || darwin || dragonfly || freebsd || hurd || illumos || ios || linux || netbsd || openbsd || solaris
+
+package main
+
+import (
+	"syscall"
+)
+
+func init() {
+	reloadSignals = append(reloadSignals, syscall.SIGUSR1)
+}
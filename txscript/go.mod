@@ -1,10 +1,11 @@
 module github.com/decred/dcrd/txscript/v4
 
-go 1.17
+go 1.18
 
 require (
 	github.com/dchest/siphash v1.2.2
 	github.com/decred/base58 v1.0.3
+	github.com/decred/dcrd/bech32 v1.1.2
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.3
 	github.com/decred/dcrd/chaincfg/v3 v3.1.0
 	github.com/decred/dcrd/crypto/blake256 v1.0.0
@@ -17,3 +18,5 @@ require (
 )
 
 require github.com/agl/ed25519 v0.0.0-20170116200512-5312a6153412 // indirect
+
+replace github.com/decred/dcrd/bech32 => ../bech32
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package ownership provides proofs of ownership of addresses for arbitrary
+messages that are based on the scripts the addresses pay to.
+
+The legacy message signing scheme only supports pay-to-pubkey-hash addresses
+with secp256k1 ECDSA signatures since it relies on public key recovery from a
+compact signature.  The proofs provided by this package instead demonstrate the
+ability to spend an output that pays to the address.  This means they support
+any address with a standard script, including pay-to-pubkey, pay-to-pubkey-hash,
+and pay-to-script-hash addresses with multisignature redeem scripts, and that
+they are produced and verified with the same code that signs and verifies
+transactions.
+
+# Challenge
+
+The challenge is the BLAKE-256 hash of the following fields, each serialized as
+a variable length string:
+
+  - The tag "Decred Ownership Proof:\n"
+  - The encoded address, which commits to the network
+  - The message
+
+# Virtual Transactions
+
+Proofs are signatures for a virtual transaction that spends the single output
+of another virtual transaction that pays to the address.  Neither transaction
+is valid on the network.
+
+The transaction that pays to the address is version 1 with a lock time and
+expiry of zero and has:
+
+  - A single input that spends index 0xffffffff of the challenge in the regular
+    tree with a sequence number of 0xffffffff, a value of zero, and an empty
+    signature script
+  - A single zero-valued output with the version 0 payment script of the
+    address
+
+The transaction that is signed is version 1 with a lock time and expiry of zero
+and has:
+
+  - A single input that spends the output of the first transaction in the
+    regular tree with a sequence number of 0 and the signature script of the
+    proof
+  - A single zero-valued output with a version 0 script that only consists of
+    OP_RETURN
+
+Since the hash of the first transaction commits to the challenge, and therefore
+the address and message, so do all signatures of the second transaction.
+
+# Serialization
+
+Proofs are serialized as a single version byte, which is currently 1, followed
+by the signature script serialized as variable length bytes.
+
+# Verification
+
+A proof is valid when the signature script and the payment script of the
+address execute successfully using the signature script of the proof as the
+input of the signed transaction with the clean stack, push only signature
+script, CHECKLOCKTIMEVERIFY, CHECKSEQUENCEVERIFY, and SHA256 script flags.
+*/
+package ownership
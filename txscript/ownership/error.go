@@ -0,0 +1,55 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ownership
+
+// ErrorKind identifies a kind of error.
+type ErrorKind string
+
+// These constants are used to identify a specific ErrorKind.
+const (
+	// ErrUnsupportedAddress indicates that proofs of ownership are not
+	// supported for a given address type.
+	ErrUnsupportedAddress = ErrorKind("ErrUnsupportedAddress")
+
+	// ErrMalformedProof indicates a serialized proof failed to parse.
+	ErrMalformedProof = ErrorKind("ErrMalformedProof")
+
+	// ErrUnsupportedProofVersion indicates a serialized proof has a version
+	// that is not supported.
+	ErrUnsupportedProofVersion = ErrorKind("ErrUnsupportedProofVersion")
+
+	// ErrInvalidProof indicates a proof parsed successfully, but it does not
+	// prove ownership of the address for the message.
+	ErrInvalidProof = ErrorKind("ErrInvalidProof")
+)
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrorKind) Error() string {
+	return string(e)
+}
+
+// Error identifies an ownership proof related error.
+//
+// It has full support for errors.Is and errors.As, so the caller can ascertain
+// the specific reason for the error by checking the underlying error.
+type Error struct {
+	Err         error
+	Description string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e Error) Error() string {
+	return e.Description
+}
+
+// Unwrap returns the underlying wrapped error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// makeError creates an Error given a set of arguments.
+func makeError(kind ErrorKind, desc string) Error {
+	return Error{Err: kind, Description: desc}
+}
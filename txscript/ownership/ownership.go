@@ -0,0 +1,211 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ownership
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/sign"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// ProofVersion is the version of the serialized proofs produced by this
+	// package.
+	ProofVersion = 1
+
+	// challengeTag is the tag that is committed to by the challenge in order
+	// to domain separate it from other uses of the same hash function.
+	challengeTag = "Decred Ownership Proof:\n"
+
+	// maxSigScriptSize is the maximum allowed size of the signature script of
+	// a proof.  It matches the maximum size of scripts allowed by consensus.
+	maxSigScriptSize = txscript.MaxScriptSize
+
+	// verifyFlags are the script flags used when executing the scripts of a
+	// proof.
+	verifyFlags = txscript.ScriptVerifyCleanStack |
+		txscript.ScriptVerifySigPushOnly |
+		txscript.ScriptVerifyCheckLockTimeVerify |
+		txscript.ScriptVerifyCheckSequenceVerify |
+		txscript.ScriptVerifySHA256
+)
+
+// Challenge returns the challenge that a proof of ownership of the provided
+// address for the provided message commits to.  It is the BLAKE-256 hash of
+// the tag "Decred Ownership Proof:\n", the encoded address, and the message,
+// each serialized as a variable length string.
+//
+// Since the encoded address includes the network, proofs for one network are
+// not valid for any other networks.
+func Challenge(addr stdaddr.Address, message string) chainhash.Hash {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, challengeTag)
+	wire.WriteVarString(&buf, 0, addr.String())
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.HashH(buf.Bytes())
+}
+
+// paymentScript returns the script that pays to the provided address after
+// ensuring it is a supported address type.
+func paymentScript(addr stdaddr.Address) ([]byte, error) {
+	switch addr.(type) {
+	case *stdaddr.AddressPubKeyEcdsaSecp256k1V0,
+		*stdaddr.AddressPubKeyEd25519V0,
+		*stdaddr.AddressPubKeySchnorrSecp256k1V0,
+		*stdaddr.AddressPubKeyHashEcdsaSecp256k1V0,
+		*stdaddr.AddressPubKeyHashEd25519V0,
+		*stdaddr.AddressPubKeyHashSchnorrSecp256k1V0,
+		*stdaddr.AddressScriptHashV0:
+
+	default:
+		str := fmt.Sprintf("address %s of type %T is not supported", addr,
+			addr)
+		return nil, makeError(ErrUnsupportedAddress, str)
+	}
+
+	_, script := addr.PaymentScript()
+	return script, nil
+}
+
+// virtualTx returns the virtual transaction that spends an output paying to
+// the provided payment script that is committed to the provided challenge.
+// The signature script of the proof is used to spend the output, so it is
+// left empty here.
+//
+// The transaction consists of a single input that spends the first output of
+// a virtual transaction with a single output that pays to the payment script
+// and an input that spends the challenge as if it were a transaction hash.
+// Since transaction hashes only commit to the transaction prefix, this ensures
+// signatures commit to the challenge.  The single output of the returned
+// transaction is a zero-valued null data script.
+func virtualTx(challenge *chainhash.Hash, pkScript []byte) *wire.MsgTx {
+	toSpend := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(challenge, wire.MaxPrevOutIndex,
+		wire.TxTreeRegular)
+	toSpend.AddTxIn(wire.NewTxIn(prevOut, 0, nil))
+	toSpend.AddTxOut(wire.NewTxOut(0, pkScript))
+
+	toSign := wire.NewMsgTx()
+	toSpendHash := toSpend.TxHash()
+	prevOut = wire.NewOutPoint(&toSpendHash, 0, wire.TxTreeRegular)
+	txIn := wire.NewTxIn(prevOut, 0, nil)
+	txIn.Sequence = 0
+	toSign.AddTxIn(txIn)
+	toSign.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	return toSign
+}
+
+// serializeProof returns the serialized proof for the provided signature
+// script.  It consists of the proof version followed by the signature script
+// serialized as variable length bytes.
+func serializeProof(sigScript []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(1 + wire.VarIntSerializeSize(uint64(len(sigScript))) +
+		len(sigScript))
+	buf.WriteByte(ProofVersion)
+	wire.WriteVarBytes(&buf, 0, sigScript)
+	return buf.Bytes()
+}
+
+// parseProof returns the signature script of the provided serialized proof.
+func parseProof(proof []byte) ([]byte, error) {
+	if len(proof) == 0 {
+		return nil, makeError(ErrMalformedProof, "proof is empty")
+	}
+	if proof[0] != ProofVersion {
+		str := fmt.Sprintf("proof version %d is not supported", proof[0])
+		return nil, makeError(ErrUnsupportedProofVersion, str)
+	}
+
+	r := bytes.NewReader(proof[1:])
+	sigScript, err := wire.ReadVarBytes(r, 0, maxSigScriptSize, "sigScript")
+	if err != nil {
+		str := fmt.Sprintf("malformed signature script: %v", err)
+		return nil, makeError(ErrMalformedProof, str)
+	}
+	if r.Len() != 0 {
+		str := fmt.Sprintf("proof has %d trailing bytes", r.Len())
+		return nil, makeError(ErrMalformedProof, str)
+	}
+	return sigScript, nil
+}
+
+// Sign returns a serialized proof of ownership of the provided address for the
+// provided message.  The proof demonstrates the ability to spend an output
+// that pays to the address, so it is produced with the same key and script
+// databases used to sign transactions.
+//
+// Pay-to-pubkey, pay-to-pubkey-hash, and pay-to-script-hash addresses are
+// supported.  The redeem script of pay-to-script-hash addresses is obtained
+// from the script database and standard multisignature redeem scripts are
+// signed with all of the keys available in the key database.  When a previous
+// proof for the same address and message is provided, the signatures are
+// merged with it, which allows the signers of a multisignature script to
+// produce a proof in turn.
+//
+// Note that a proof for a multisignature script that does not have enough
+// signatures yet is returned without error, but it will not verify.
+func Sign(params stdaddr.AddressParams, addr stdaddr.Address, message string,
+	kdb sign.KeyDB, sdb sign.ScriptDB, prevProof []byte) ([]byte, error) {
+
+	pkScript, err := paymentScript(addr)
+	if err != nil {
+		return nil, err
+	}
+	var prevSigScript []byte
+	if len(prevProof) != 0 {
+		prevSigScript, err = parseProof(prevProof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	challenge := Challenge(addr, message)
+	tx := virtualTx(&challenge, pkScript)
+	const isTreasuryEnabled = false
+	sigScript, err := sign.SignTxOutput(params, tx, 0, pkScript,
+		txscript.SigHashAll, kdb, sdb, prevSigScript, isTreasuryEnabled)
+	if err != nil {
+		return nil, err
+	}
+	return serializeProof(sigScript), nil
+}
+
+// Verify ensures the provided serialized proof proves ownership of the
+// provided address for the provided message.  It returns an error of type
+// Error with the ErrInvalidProof kind when the proof is well formed but does
+// not prove ownership.
+func Verify(addr stdaddr.Address, message string, proof []byte) error {
+	pkScript, err := paymentScript(addr)
+	if err != nil {
+		return err
+	}
+	sigScript, err := parseProof(proof)
+	if err != nil {
+		return err
+	}
+
+	challenge := Challenge(addr, message)
+	tx := virtualTx(&challenge, pkScript)
+	tx.TxIn[0].SignatureScript = sigScript
+	const scriptVersion = 0
+	vm, err := txscript.NewEngine(pkScript, tx, 0, verifyFlags, scriptVersion,
+		nil)
+	if err != nil {
+		str := fmt.Sprintf("unable to verify proof: %v", err)
+		return makeError(ErrInvalidProof, str)
+	}
+	if err := vm.Execute(); err != nil {
+		str := fmt.Sprintf("proof does not prove ownership of %s: %v", addr,
+			err)
+		return makeError(ErrInvalidProof, str)
+	}
+	return nil
+}
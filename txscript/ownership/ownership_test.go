@@ -0,0 +1,386 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ownership
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/txscript/v4/sign"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected. It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// testKey houses a private key along with the signature type it signs with.
+type testKey struct {
+	privKey []byte
+	sigType dcrec.SignatureType
+}
+
+// testKeyDB is a key and script database for use in the tests that houses
+// keys and scripts by their encoded address.
+type testKeyDB struct {
+	keys    map[string]testKey
+	scripts map[string][]byte
+}
+
+// newTestKeyDB returns a new empty key and script database.
+func newTestKeyDB() *testKeyDB {
+	return &testKeyDB{
+		keys:    make(map[string]testKey),
+		scripts: make(map[string][]byte),
+	}
+}
+
+// GetKey returns the private key associated with the provided address.
+//
+// This is part of the sign.KeyDB interface.
+func (db *testKeyDB) GetKey(addr stdaddr.Address) ([]byte, dcrec.SignatureType, bool, error) {
+	key, ok := db.keys[addr.String()]
+	if !ok {
+		return nil, 0, false, fmt.Errorf("no key for address %s", addr)
+	}
+	return key.privKey, key.sigType, true, nil
+}
+
+// GetScript returns the redeem script associated with the provided address.
+//
+// This is part of the sign.ScriptDB interface.
+func (db *testKeyDB) GetScript(addr stdaddr.Address) ([]byte, error) {
+	script, ok := db.scripts[addr.String()]
+	if !ok {
+		return nil, fmt.Errorf("no script for address %s", addr)
+	}
+	return script, nil
+}
+
+// Ensure testKeyDB implements the sign.KeyDB and sign.ScriptDB interfaces.
+var _ sign.KeyDB = (*testKeyDB)(nil)
+var _ sign.ScriptDB = (*testKeyDB)(nil)
+
+// testPrivKeys houses private keys used throughout the tests.
+var testPrivKeys = [][]byte{
+	hexToBytes("22a47fa09a223f2aa079edf85a7c2d4f8720ee63e502ee2869afab7de234b80c"),
+	hexToBytes("4c7a3b2c5e0f8f3a4ef8ebb0bd8d5ab3b0b3c0a37dfd6ba0f1a1b91bdbd8b7e5"),
+	hexToBytes("a9b2a51e7e2b3fce1c62bbd4c87d6c6e26c9b5d7d2ad6b6d9d6e2b0f2b1d6c4a"),
+}
+
+// testAddrs returns the addresses used throughout the tests along with a key
+// and script database that is able to sign for all of them except the
+// multisignature address.  The multisignature address is a 2-of-2 of the
+// first two keys.
+func testAddrs(t *testing.T, params stdaddr.AddressParams) (map[string]stdaddr.Address, *testKeyDB) {
+	t.Helper()
+
+	db := newTestKeyDB()
+	addrs := make(map[string]stdaddr.Address)
+	pubKey := secp256k1.PrivKeyFromBytes(testPrivKeys[0]).PubKey()
+	p2pk, err := stdaddr.NewAddressPubKeyEcdsaSecp256k1V0(pubKey, params)
+	if err != nil {
+		t.Fatalf("unexpected error creating p2pk address: %v", err)
+	}
+	addrs["p2pk-ecdsa-secp256k1"] = p2pk
+	db.keys[p2pk.String()] = testKey{testPrivKeys[0], dcrec.STEcdsaSecp256k1}
+
+	pkHash := stdaddr.Hash160(pubKey.SerializeCompressed())
+	p2pkh, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, params)
+	if err != nil {
+		t.Fatalf("unexpected error creating p2pkh address: %v", err)
+	}
+	addrs["p2pkh-ecdsa-secp256k1"] = p2pkh
+	db.keys[p2pkh.String()] = testKey{testPrivKeys[0], dcrec.STEcdsaSecp256k1}
+
+	pubKey = secp256k1.PrivKeyFromBytes(testPrivKeys[1]).PubKey()
+	pkHash = stdaddr.Hash160(pubKey.SerializeCompressed())
+	p2pkhSchnorr, err := stdaddr.NewAddressPubKeyHashSchnorrSecp256k1V0(pkHash,
+		params)
+	if err != nil {
+		t.Fatalf("unexpected error creating p2pkh address: %v", err)
+	}
+	addrs["p2pkh-schnorr-secp256k1"] = p2pkhSchnorr
+	db.keys[p2pkhSchnorr.String()] = testKey{testPrivKeys[1],
+		dcrec.STSchnorrSecp256k1}
+
+	redeemScript, err := stdscript.MultiSigScriptV0(2,
+		secp256k1.PrivKeyFromBytes(testPrivKeys[0]).PubKey().SerializeCompressed(),
+		secp256k1.PrivKeyFromBytes(testPrivKeys[1]).PubKey().SerializeCompressed())
+	if err != nil {
+		t.Fatalf("unexpected error creating multisig script: %v", err)
+	}
+	p2sh, err := stdaddr.NewAddressScriptHashV0(redeemScript, params)
+	if err != nil {
+		t.Fatalf("unexpected error creating p2sh address: %v", err)
+	}
+	addrs["p2sh-multisig"] = p2sh
+	db.scripts[p2sh.String()] = redeemScript
+	return addrs, db
+}
+
+// multiSigSignerDB returns a key and script database that is only able to sign
+// for the provided key of the multisignature address.
+func multiSigSignerDB(t *testing.T, params stdaddr.AddressParams, p2sh stdaddr.Address, redeemScript []byte, privKey []byte) *testKeyDB {
+	t.Helper()
+
+	pubKey := secp256k1.PrivKeyFromBytes(privKey).PubKey()
+	addr, err := stdaddr.NewAddressPubKeyEcdsaSecp256k1V0(pubKey, params)
+	if err != nil {
+		t.Fatalf("unexpected error creating p2pk address: %v", err)
+	}
+	db := newTestKeyDB()
+	db.keys[addr.String()] = testKey{privKey, dcrec.STEcdsaSecp256k1}
+	db.scripts[p2sh.String()] = redeemScript
+	return db
+}
+
+// TestSignVerify ensures proofs of ownership produced by Sign verify for the
+// address and message they were created for and do not verify otherwise.
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	mainNetParams := chaincfg.MainNetParams()
+	addrs, db := testAddrs(t, mainNetParams)
+	testNetAddrs, _ := testAddrs(t, chaincfg.TestNet3Params())
+	const message = "The quick brown fox jumps over the lazy dog."
+
+	for _, name := range []string{"p2pk-ecdsa-secp256k1",
+		"p2pkh-ecdsa-secp256k1", "p2pkh-schnorr-secp256k1"} {
+
+		addr := addrs[name]
+		proof, err := Sign(mainNetParams, addr, message, db, db, nil)
+		if err != nil {
+			t.Errorf("%q: unexpected error signing: %v", name, err)
+			continue
+		}
+
+		// Ensure the proof verifies for the address and message.
+		if err := Verify(addr, message, proof); err != nil {
+			t.Errorf("%q: unexpected error verifying: %v", name, err)
+			continue
+		}
+
+		// Ensure the proof does not verify for a different message.
+		err = Verify(addr, message+"!", proof)
+		if !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%q: mismatched err for different message -- got %v, "+
+				"want %v", name, err, ErrInvalidProof)
+			continue
+		}
+
+		// Ensure the proof does not verify for the same address on a
+		// different network.
+		err = Verify(testNetAddrs[name], message, proof)
+		if !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%q: mismatched err for different network -- got %v, "+
+				"want %v", name, err, ErrInvalidProof)
+			continue
+		}
+
+		// Ensure the proof does not verify for a different address.
+		otherName := "p2pkh-schnorr-secp256k1"
+		if name == otherName {
+			otherName = "p2pkh-ecdsa-secp256k1"
+		}
+		err = Verify(addrs[otherName], message, proof)
+		if !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%q: mismatched err for different address -- got %v, "+
+				"want %v", name, err, ErrInvalidProof)
+			continue
+		}
+	}
+}
+
+// TestSignVerifyMultiSig ensures proofs of ownership of a pay-to-script-hash
+// multisignature address only verify once all of the required signers have
+// signed and that the signatures of separate signers are merged.
+func TestSignVerifyMultiSig(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams()
+	addrs, db := testAddrs(t, params)
+	p2sh := addrs["p2sh-multisig"]
+	redeemScript := db.scripts[p2sh.String()]
+	const message = "multisig ownership"
+
+	// Ensure a proof with only the first signature does not verify.
+	db1 := multiSigSignerDB(t, params, p2sh, redeemScript, testPrivKeys[0])
+	partial, err := Sign(params, p2sh, message, db1, db1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error signing with first key: %v", err)
+	}
+	err = Verify(p2sh, message, partial)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("mismatched err for partial proof -- got %v, want %v", err,
+			ErrInvalidProof)
+	}
+
+	// Ensure adding the second signature produces a proof that verifies.
+	db2 := multiSigSignerDB(t, params, p2sh, redeemScript, testPrivKeys[1])
+	proof, err := Sign(params, p2sh, message, db2, db2, partial)
+	if err != nil {
+		t.Fatalf("unexpected error signing with second key: %v", err)
+	}
+	if err := Verify(p2sh, message, proof); err != nil {
+		t.Fatalf("unexpected error verifying merged proof: %v", err)
+	}
+
+	// Ensure a proof from the unrelated third key does not verify even when
+	// merged with the partial proof.
+	db3 := multiSigSignerDB(t, params, p2sh, redeemScript, testPrivKeys[2])
+	badProof, err := Sign(params, p2sh, message, db3, db3, partial)
+	if err != nil {
+		t.Fatalf("unexpected error signing with third key: %v", err)
+	}
+	err = Verify(p2sh, message, badProof)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("mismatched err for unrelated key -- got %v, want %v", err,
+			ErrInvalidProof)
+	}
+
+	// Ensure the merged proof does not verify for a different message.
+	err = Verify(p2sh, message+"!", proof)
+	if !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("mismatched err for different message -- got %v, want %v",
+			err, ErrInvalidProof)
+	}
+}
+
+// TestProofErrors ensures malformed proofs and unsupported addresses are
+// rejected with the expected errors.
+func TestProofErrors(t *testing.T) {
+	t.Parallel()
+
+	params := chaincfg.MainNetParams()
+	addrs, db := testAddrs(t, params)
+	addr := addrs["p2pkh-ecdsa-secp256k1"]
+	const message = "error tests"
+	proof, err := Sign(params, addr, message, db, db, nil)
+	if err != nil {
+		t.Fatalf("unexpected error signing: %v", err)
+	}
+
+	unknownAddr, err := stdaddr.NewAddressUnknownVersion(1, []byte{0x51},
+		params)
+	if err != nil {
+		t.Fatalf("unexpected error creating address: %v", err)
+	}
+	badVersion := append([]byte(nil), proof...)
+	badVersion[0] = ProofVersion + 1
+
+	tests := []struct {
+		name    string
+		addr    stdaddr.Address
+		proof   []byte
+		wantErr error
+	}{{
+		name:    "unsupported address",
+		addr:    unknownAddr,
+		proof:   proof,
+		wantErr: ErrUnsupportedAddress,
+	}, {
+		name:    "empty proof",
+		addr:    addr,
+		proof:   nil,
+		wantErr: ErrMalformedProof,
+	}, {
+		name:    "unsupported proof version",
+		addr:    addr,
+		proof:   badVersion,
+		wantErr: ErrUnsupportedProofVersion,
+	}, {
+		name:    "truncated proof",
+		addr:    addr,
+		proof:   proof[:len(proof)-1],
+		wantErr: ErrMalformedProof,
+	}, {
+		name:    "trailing bytes",
+		addr:    addr,
+		proof:   append(append([]byte(nil), proof...), 0x00),
+		wantErr: ErrMalformedProof,
+	}, {
+		name:    "non push only signature script",
+		addr:    addr,
+		proof:   serializeProof([]byte{0x51, 0x76}),
+		wantErr: ErrInvalidProof,
+	}, {
+		name:    "empty signature script",
+		addr:    addr,
+		proof:   serializeProof(nil),
+		wantErr: ErrInvalidProof,
+	}}
+
+	for _, test := range tests {
+		err := Verify(test.addr, message, test.proof)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: mismatched err -- got %v, want %v", test.name, err,
+				test.wantErr)
+			continue
+		}
+	}
+
+	// Ensure signing rejects unsupported addresses and malformed previous
+	// proofs.
+	_, err = Sign(params, unknownAddr, message, db, db, nil)
+	if !errors.Is(err, ErrUnsupportedAddress) {
+		t.Errorf("mismatched sign err for unsupported address -- got %v, "+
+			"want %v", err, ErrUnsupportedAddress)
+	}
+	_, err = Sign(params, addr, message, db, db, badVersion)
+	if !errors.Is(err, ErrUnsupportedProofVersion) {
+		t.Errorf("mismatched sign err for bad previous proof -- got %v, "+
+			"want %v", err, ErrUnsupportedProofVersion)
+	}
+}
+
+// TestProofSerialization ensures proofs are serialized as expected and that
+// the challenge commits to all of its inputs.
+func TestProofSerialization(t *testing.T) {
+	t.Parallel()
+
+	sigScript := hexToBytes("0102030405")
+	proof := serializeProof(sigScript)
+	want := hexToBytes("01050102030405")
+	if !bytes.Equal(proof, want) {
+		t.Fatalf("mismatched serialized proof -- got %x, want %x", proof, want)
+	}
+	parsed, err := parseProof(proof)
+	if err != nil {
+		t.Fatalf("unexpected error parsing proof: %v", err)
+	}
+	if !bytes.Equal(parsed, sigScript) {
+		t.Fatalf("mismatched parsed signature script -- got %x, want %x",
+			parsed, sigScript)
+	}
+
+	// Ensure the challenge differs when the address or message differ.
+	params := chaincfg.MainNetParams()
+	addrs, _ := testAddrs(t, params)
+	addr := addrs["p2pkh-ecdsa-secp256k1"]
+	otherAddr := addrs["p2pk-ecdsa-secp256k1"]
+	challenge := Challenge(addr, "message")
+	if challenge == Challenge(addr, "message2") {
+		t.Fatal("challenge does not commit to the message")
+	}
+	if challenge == Challenge(otherAddr, "message") {
+		t.Fatal("challenge does not commit to the address")
+	}
+}
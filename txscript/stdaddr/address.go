@@ -7,8 +7,12 @@
 package stdaddr
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/decred/base58"
 	"github.com/decred/dcrd/crypto/ripemd160"
 )
 
@@ -415,6 +419,123 @@ func NewAddressScriptHash(scriptVersion uint16, redeemScript []byte,
 	return nil, makeError(ErrUnsupportedScriptVersion, str)
 }
 
+// AddressUnknownVersion is an opaque address that represents a payment
+// destination which imposes an encumbrance defined by a script with a version
+// that is not recognized by this package.
+//
+// It allows callers, such as block explorers, to represent, display, and
+// round trip payment scripts for future script versions via the same Address
+// interface used for known address types without needing to understand them.
+// Since the script is not understood, addresses of this type do not implement
+// any of the interfaces for additional capabilities, such as StakeAddress.
+//
+// The string encoding is the script version in decimal prefixed by "v" and
+// followed by a colon and the base58 check encoding of the script along with
+// the version 0 pay-to-script-hash network identifier of the network.  For
+// example, "v1:" followed by the encoded script.  Since the colon is not part
+// of the base58 alphabet, it can never be confused with a version 0 address.
+type AddressUnknownVersion struct {
+	netID         [2]byte
+	scriptVersion uint16
+	script        []byte
+}
+
+// Ensure AddressUnknownVersion implements the Address interface.
+var _ Address = (*AddressUnknownVersion)(nil)
+
+// NewAddressUnknownVersion returns an opaque address that represents a payment
+// destination which imposes an encumbrance defined by the provided script with
+// the provided script version for the network identified by the provided
+// parameters.
+//
+// The script version must not be one that is supported by this package since
+// the addresses for those versions are represented by their specific types.
+// Thus, version 0 is not allowed.
+func NewAddressUnknownVersion(scriptVersion uint16, script []byte,
+	params AddressParams) (*AddressUnknownVersion, error) {
+
+	if scriptVersion == 0 {
+		str := fmt.Sprintf("unknown version addresses for version %d are not "+
+			"supported since the version is known", scriptVersion)
+		return nil, makeError(ErrUnsupportedScriptVersion, str)
+	}
+
+	scriptCopy := make([]byte, len(script))
+	copy(scriptCopy, script)
+	return &AddressUnknownVersion{
+		netID:         params.AddrIDScriptHashV0(),
+		scriptVersion: scriptVersion,
+		script:        scriptCopy,
+	}, nil
+}
+
+// String returns the string encoding of the payment address for the associated
+// script version and payment script.
+//
+// This is part of the Address interface implementation.
+func (addr *AddressUnknownVersion) String() string {
+	return "v" + strconv.FormatUint(uint64(addr.scriptVersion), 10) + ":" +
+		base58.CheckEncode(addr.script, addr.netID)
+}
+
+// PaymentScript returns the script version associated with the address along
+// with a script to pay a transaction output to the address.
+//
+// This is part of the Address interface implementation.
+func (addr *AddressUnknownVersion) PaymentScript() (uint16, []byte) {
+	script := make([]byte, len(addr.script))
+	copy(script, addr.script)
+	return addr.scriptVersion, script
+}
+
+// probablyUnknownVersionAddr returns true when the provided string looks like
+// the string encoding of an unknown version address as determined by its
+// prefix.
+func probablyUnknownVersionAddr(s string) bool {
+	if len(s) < 3 || s[0] != 'v' {
+		return false
+	}
+	sep := strings.IndexByte(s, ':')
+	if sep < 2 {
+		return false
+	}
+	for _, r := range s[1:sep] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeAddressUnknownVersion decodes the string encoding of an unknown
+// version address and returns it if it is a valid encoding for the network
+// identified by the provided parameters.
+func decodeAddressUnknownVersion(addr string, params AddressParams) (Address, error) {
+	sep := strings.IndexByte(addr, ':')
+	scriptVersion, err := strconv.ParseUint(addr[1:sep], 10, 16)
+	if err != nil {
+		str := fmt.Sprintf("failed to decode address %q: invalid script "+
+			"version: %v", addr, err)
+		return nil, makeError(ErrMalformedAddress, str)
+	}
+
+	script, netID, err := base58.CheckDecode(addr[sep+1:])
+	if err != nil {
+		kind := ErrMalformedAddress
+		if errors.Is(err, base58.ErrChecksum) {
+			kind = ErrBadAddressChecksum
+		}
+		str := fmt.Sprintf("failed to decode address %q: %v", addr, err)
+		return nil, makeError(kind, str)
+	}
+	if netID != params.AddrIDScriptHashV0() {
+		str := fmt.Sprintf("address %q is not for the provided network", addr)
+		return nil, makeError(ErrUnsupportedAddress, str)
+	}
+
+	return NewAddressUnknownVersion(uint16(scriptVersion), script, params)
+}
+
 // probablyV0Base58Addr returns true when the provided string looks like a
 // version 0 base58 address as determined by their length and only containing
 // runes in the base58 alphabet used by Decred for version 0 addresses.
@@ -442,14 +563,27 @@ func probablyV0Base58Addr(s string) bool {
 // DecodeAddress decodes the string encoding of an address and returns the
 // relevant Address if it is a valid encoding for a known address type and is
 // for the provided network.
+//
+// Version 1 addresses are only recognized when the provided parameters also
+// implement the AddressParamsV1 interface.
+//
+// The string encoding of addresses for script versions that are not recognized
+// by this package, as produced by AddressUnknownVersion, are decoded to an
+// AddressUnknownVersion.
 func DecodeAddress(addr string, params AddressParams) (Address, error) {
 	// Parsing code for future address/script versions should be added as the
 	// most recent case in the switch statement.  The expectation is that newer
 	// version addresses will become more common, so they should be checked
 	// first.
 	switch {
+	case probablyV1Bech32Addr(addr, params):
+		return DecodeAddressV1(addr, params.(AddressParamsV1))
+
 	case probablyV0Base58Addr(addr):
 		return DecodeAddressV0(addr, params)
+
+	case probablyUnknownVersionAddr(addr):
+		return decodeAddressUnknownVersion(addr, params)
 	}
 
 	str := fmt.Sprintf("address %q is not a supported type", addr)
@@ -8,9 +8,12 @@ import (
 	"bytes"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/decred/base58"
+	"github.com/decred/dcrd/bech32"
 	"github.com/decred/dcrd/crypto/ripemd160"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -25,6 +28,7 @@ type mockAddrParams struct {
 	pkhSchnorrID [2]byte
 	scriptHashID [2]byte
 	privKeyID    [2]byte
+	hrpV1        string
 }
 
 // AddrIDPubKeyV0 returns the magic prefix bytes associated with the mock params
@@ -70,6 +74,14 @@ func (p *mockAddrParams) AddrIDScriptHashV0() [2]byte {
 	return p.scriptHashID
 }
 
+// AddrHRPV1 returns the human-readable part associated with the mock params for
+// version 1 addresses.
+//
+// This is part of the AddressParamsV1 interface.
+func (p *mockAddrParams) AddrHRPV1() string {
+	return p.hrpV1
+}
+
 // mockMainNetParams returns mock mainnet address parameters to use throughout
 // the tests.  They match the Decred mainnet params as of the time this comment
 // was written.
@@ -81,6 +93,7 @@ func mockMainNetParams() *mockAddrParams {
 		pkhSchnorrID: [2]byte{0x07, 0x01}, // starts with DS
 		scriptHashID: [2]byte{0x07, 0x1a}, // starts with Dc
 		privKeyID:    [2]byte{0x22, 0xde}, // starts with Pm
+		hrpV1:        "ds",
 	}
 }
 
@@ -95,6 +108,7 @@ func mockTestNetParams() *mockAddrParams {
 		pkhSchnorrID: [2]byte{0x0e, 0xe3}, // starts with TS
 		scriptHashID: [2]byte{0x0e, 0xfc}, // starts with Tc
 		privKeyID:    [2]byte{0x23, 0x0e}, // starts with Pt
+		hrpV1:        "ts",
 	}
 }
 
@@ -109,6 +123,7 @@ func mockRegNetParams() *mockAddrParams {
 		pkhSchnorrID: [2]byte{0x0d, 0xc2}, // starts with RS
 		scriptHashID: [2]byte{0x0d, 0xdb}, // starts with Rc
 		privKeyID:    [2]byte{0x22, 0xfe}, // starts with Pr
+		hrpV1:        "rs",
 	}
 }
 
@@ -1417,3 +1432,314 @@ func TestProbablyV0Base58Addr(t *testing.T) {
 		}
 	}
 }
+
+// TestAddressUnknownVersion ensures addresses for unknown script versions are
+// created, encoded, decoded, and round tripped as expected.
+func TestAddressUnknownVersion(t *testing.T) {
+	mainNetParams := mockMainNetParams()
+	testNetParams := mockTestNetParams()
+
+	tests := []struct {
+		name    string        // test description
+		version uint16        // script version
+		script  []byte        // payment script
+		net     AddressParams // params for network
+		newErr  error         // expected error from new
+	}{{
+		name:    "version 1 script",
+		version: 1,
+		script:  hexToBytes("a914f0b4e85100aee1a996f22915eb3c3f764d53779a87"),
+		net:     mainNetParams,
+	}, {
+		name:    "max version empty script",
+		version: 65535,
+		script:  nil,
+		net:     testNetParams,
+	}, {
+		name:    "version 0 is known",
+		version: 0,
+		script:  hexToBytes("51"),
+		net:     mainNetParams,
+		newErr:  ErrUnsupportedScriptVersion,
+	}}
+
+	for _, test := range tests {
+		addr, err := NewAddressUnknownVersion(test.version, test.script,
+			test.net)
+		if !errors.Is(err, test.newErr) {
+			t.Errorf("%s: mismatched err -- got %v, want %v", test.name, err,
+				test.newErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		// Ensure the payment script and version are the provided ones.
+		gotVer, gotScript := addr.PaymentScript()
+		if gotVer != test.version || !bytes.Equal(gotScript, test.script) {
+			t.Errorf("%s: mismatched payment script -- got (%d, %x), want "+
+				"(%d, %x)", test.name, gotVer, gotScript, test.version,
+				test.script)
+			continue
+		}
+
+		// Ensure the address round trips through its string encoding.
+		decoded, err := DecodeAddress(addr.String(), test.net)
+		if err != nil {
+			t.Errorf("%s: unexpected decode error: %v", test.name, err)
+			continue
+		}
+		if decoded.String() != addr.String() {
+			t.Errorf("%s: mismatched decoded address -- got %s, want %s",
+				test.name, decoded, addr)
+			continue
+		}
+		gotVer, gotScript = decoded.PaymentScript()
+		if gotVer != test.version || !bytes.Equal(gotScript, test.script) {
+			t.Errorf("%s: mismatched decoded payment script -- got (%d, %x), "+
+				"want (%d, %x)", test.name, gotVer, gotScript, test.version,
+				test.script)
+			continue
+		}
+	}
+
+	// Ensure invalid encodings are rejected.
+	addr, err := NewAddressUnknownVersion(1, hexToBytes("51"), mainNetParams)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encodedScript := addr.String()[3:]
+	decodeTests := []struct {
+		name      string // test description
+		addr      string // address to decode
+		decodeErr error  // expected error from decode
+	}{{
+		name:      "version out of range",
+		addr:      "v65536:" + encodedScript,
+		decodeErr: ErrMalformedAddress,
+	}, {
+		name:      "version 0",
+		addr:      "v0:" + encodedScript,
+		decodeErr: ErrUnsupportedScriptVersion,
+	}, {
+		name:      "bad checksum",
+		addr:      "v1:" + encodedScript[:len(encodedScript)-1] + "1",
+		decodeErr: ErrBadAddressChecksum,
+	}, {
+		name:      "invalid base58",
+		addr:      "v1:0" + encodedScript,
+		decodeErr: ErrMalformedAddress,
+	}, {
+		name:      "missing version",
+		addr:      "v:" + encodedScript,
+		decodeErr: ErrUnsupportedAddress,
+	}}
+	for _, test := range decodeTests {
+		_, err := DecodeAddress(test.addr, mainNetParams)
+		if !errors.Is(err, test.decodeErr) {
+			t.Errorf("%s: mismatched err -- got %v, want %v", test.name, err,
+				test.decodeErr)
+		}
+	}
+
+	// Ensure addresses for other networks are rejected.
+	_, err = DecodeAddress(addr.String(), testNetParams)
+	if !errors.Is(err, ErrUnsupportedAddress) {
+		t.Errorf("mismatched err for wrong network -- got %v, want %v", err,
+			ErrUnsupportedAddress)
+	}
+}
+
+// TestAddressProgramV1 ensures version 1 addresses are created, encoded, and
+// decoded as expected, that they produce the expected payment scripts, and
+// that likely typos are pointed out.
+func TestAddressProgramV1(t *testing.T) {
+	mainNetParams := mockMainNetParams()
+	testNetParams := mockTestNetParams()
+
+	tests := []struct {
+		name    string          // test description
+		program []byte          // address program
+		net     AddressParamsV1 // params for network
+		newErr  error           // expected error from new
+		addr    string          // expected address
+		script  []byte          // expected payment script
+	}{{
+		name:    "32-byte program mainnet",
+		program: hexToBytes("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		net:     mainNetParams,
+		addr:    "ds1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq52ln4v",
+		script:  hexToBytes("2079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+	}, {
+		name:    "min length program testnet",
+		program: hexToBytes("751e"),
+		net:     testNetParams,
+		addr:    "ts1pw50qn08hsv",
+		script:  hexToBytes("02751e"),
+	}, {
+		name:    "max length program testnet",
+		program: bytes.Repeat([]byte{0x75}, 40),
+		net:     testNetParams,
+		addr:    "ts1pw46h2at4w46h2at4w46h2at4w46h2at4w46h2at4w46h2at4w46h2at4w46h2at4s29jah",
+		script:  append([]byte{0x28}, bytes.Repeat([]byte{0x75}, 40)...),
+	}, {
+		name:    "program too short",
+		program: hexToBytes("75"),
+		net:     mainNetParams,
+		newErr:  ErrInvalidProgramLen,
+	}, {
+		name:    "program too long",
+		program: bytes.Repeat([]byte{0x75}, 41),
+		net:     mainNetParams,
+		newErr:  ErrInvalidProgramLen,
+	}}
+
+	for _, test := range tests {
+		addr, err := NewAddressProgramV1(test.program, test.net)
+		if !errors.Is(err, test.newErr) {
+			t.Errorf("%s: mismatched err -- got %v, want %v", test.name, err,
+				test.newErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		// Ensure the encoding, payment script, and program are the expected
+		// values.
+		if addr.String() != test.addr {
+			t.Errorf("%s: mismatched address -- got %s, want %s", test.name,
+				addr, test.addr)
+			continue
+		}
+		gotVer, gotScript := addr.PaymentScript()
+		if gotVer != 1 || !bytes.Equal(gotScript, test.script) {
+			t.Errorf("%s: mismatched payment script -- got (%d, %x), want "+
+				"(1, %x)", test.name, gotVer, gotScript, test.script)
+			continue
+		}
+		if !bytes.Equal(addr.Program(), test.program) {
+			t.Errorf("%s: mismatched program -- got %x, want %x", test.name,
+				addr.Program(), test.program)
+			continue
+		}
+
+		// Ensure the address round trips through both the lowercase and
+		// uppercase forms of its string encoding.
+		for _, encoded := range []string{test.addr, strings.ToUpper(test.addr)} {
+			decoded, err := DecodeAddress(encoded, test.net.(AddressParams))
+			if err != nil {
+				t.Errorf("%s: unexpected decode error: %v", test.name, err)
+				continue
+			}
+			if decoded.String() != test.addr {
+				t.Errorf("%s: mismatched decoded address -- got %s, want %s",
+					test.name, decoded, test.addr)
+				continue
+			}
+		}
+	}
+
+	// encodeRaw returns the bech32m encoding of the provided script version
+	// and 5-bit data with the human-readable part of the main network.
+	encodeRaw := func(scriptVersion byte, data []byte) string {
+		encoded, err := bech32.EncodeM("ds", append([]byte{scriptVersion},
+			data...))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return encoded
+	}
+
+	// Ensure invalid encodings are rejected and likely typos are pointed out.
+	const valid = "ds1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq52ln4v"
+	bech32Addr, err := bech32.Encode("ds", append([]byte{1},
+		make([]byte, 52)...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decodeTests := []struct {
+		name      string        // test description
+		addr      string        // address to decode
+		net       AddressParams // params for network
+		decodeErr error         // expected error from decode
+		typoPos   int           // expected position of typo or -1 for none
+	}{{
+		name:      "typo",
+		addr:      valid[:10] + "q" + valid[11:],
+		net:       mainNetParams,
+		decodeErr: ErrBadAddressChecksum,
+		typoPos:   10,
+	}, {
+		name:      "character not in charset",
+		addr:      valid[:20] + "b" + valid[21:],
+		net:       mainNetParams,
+		decodeErr: ErrMalformedAddress,
+		typoPos:   20,
+	}, {
+		name:      "bech32 checksum",
+		addr:      bech32Addr,
+		net:       mainNetParams,
+		decodeErr: ErrBadAddressChecksum,
+		typoPos:   -1,
+	}, {
+		name:      "unsupported version",
+		addr:      encodeRaw(2, make([]byte, 52)),
+		net:       mainNetParams,
+		decodeErr: ErrUnsupportedScriptVersion,
+		typoPos:   -1,
+	}, {
+		name:      "missing version",
+		addr:      "ds1" + valid[len(valid)-6:],
+		net:       mainNetParams,
+		decodeErr: ErrBadAddressChecksum,
+		typoPos:   -1,
+	}, {
+		name:      "non-zero padding",
+		addr:      encodeRaw(1, []byte{0x1f, 0x1f, 0x1f, 0x1f}),
+		net:       mainNetParams,
+		decodeErr: ErrMalformedAddressData,
+		typoPos:   -1,
+	}, {
+		name:      "program too short",
+		addr:      encodeRaw(1, []byte{0x1f, 0x10}),
+		net:       mainNetParams,
+		decodeErr: ErrInvalidProgramLen,
+		typoPos:   -1,
+	}, {
+		name:      "wrong network",
+		addr:      valid,
+		net:       testNetParams,
+		decodeErr: ErrUnsupportedAddress,
+		typoPos:   -1,
+	}, {
+		name:      "network without version 1 support",
+		addr:      valid,
+		net:       struct{ AddressParamsV0 }{mainNetParams},
+		decodeErr: ErrUnsupportedAddress,
+		typoPos:   -1,
+	}}
+	for _, test := range decodeTests {
+		_, err := DecodeAddress(test.addr, test.net)
+		if !errors.Is(err, test.decodeErr) {
+			t.Errorf("%s: mismatched err -- got %v, want %v", test.name, err,
+				test.decodeErr)
+			continue
+		}
+		hint := fmt.Sprintf("position(s) [%d]", test.typoPos)
+		if gotHint := strings.Contains(err.Error(), hint); gotHint !=
+			(test.typoPos != -1) {
+
+			t.Errorf("%s: mismatched typo hint -- got %q, want position %d",
+				test.name, err, test.typoPos)
+		}
+	}
+
+	// Ensure the hrp is verified when decoding directly.
+	_, err = DecodeAddressV1(valid, testNetParams)
+	if !errors.Is(err, ErrUnsupportedAddress) {
+		t.Errorf("mismatched err for wrong network -- got %v, want %v", err,
+			ErrUnsupportedAddress)
+	}
+}
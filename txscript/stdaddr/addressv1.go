@@ -0,0 +1,187 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stdaddr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/bech32"
+	"github.com/decred/dcrd/txscript/v4"
+)
+
+const (
+	// minProgramLenV1 is the minimum allowed length of the program committed
+	// to by version 1 addresses.
+	minProgramLenV1 = 2
+
+	// maxProgramLenV1 is the maximum allowed length of the program committed
+	// to by version 1 addresses.
+	maxProgramLenV1 = 40
+
+	// MaxHRPLenV1 is the maximum allowed length of the human-readable part of
+	// version 1 addresses.  It is the maximum length of a bech32m string less
+	// the separator, the script version, the base32 encoding of the longest
+	// allowed program, and the checksum.
+	MaxHRPLenV1 = 90 - 1 - 1 - (maxProgramLenV1*8+4)/5 - 6
+)
+
+// AddressParamsV1 defines an interface that is used to provide the parameters
+// required when encoding and decoding addresses for version 1 scripts.  These
+// values are typically well-defined and unique per network.
+//
+// Support for version 1 addresses is optional, so this interface is not part
+// of AddressParams.  Instead, DecodeAddress only recognizes version 1 addresses
+// when the provided parameters also implement this interface.
+type AddressParamsV1 interface {
+	// AddrHRPV1 returns the human-readable part used in the bech32m encoding
+	// of version 1 addresses.  It must consist of at most MaxHRPLenV1
+	// lowercase characters.
+	AddrHRPV1() string
+}
+
+// AddressProgramV1 specifies an address that represents a payment destination
+// which imposes an encumbrance defined by a program that is interpreted
+// according to the rules for version 1 scripts.
+//
+// The payment script is a single canonical data push of the program.
+//
+// The string encoding is bech32m, as specified in BIP 350, with the
+// human-readable part of the network followed by the script version and the
+// program as the data part.  Since version 1 is encoded as the character 'p',
+// the addresses start with the human-readable part followed by "1p".  For
+// example, "ds1p" on the main network.
+type AddressProgramV1 struct {
+	hrp     string
+	program []byte
+}
+
+// Ensure AddressProgramV1 implements the Address interface.
+var _ Address = (*AddressProgramV1)(nil)
+
+// NewAddressProgramV1 returns an address that represents a payment destination
+// which imposes an encumbrance defined by the provided program that is
+// interpreted according to the rules for version 1 scripts.
+//
+// The provided program must be between 2 and 40 bytes.
+func NewAddressProgramV1(program []byte,
+	params AddressParamsV1) (*AddressProgramV1, error) {
+
+	// Check for a valid program length.
+	if len(program) < minProgramLenV1 || len(program) > maxProgramLenV1 {
+		str := fmt.Sprintf("program is %d bytes vs required %d to %d bytes",
+			len(program), minProgramLenV1, maxProgramLenV1)
+		return nil, makeError(ErrInvalidProgramLen, str)
+	}
+
+	programCopy := make([]byte, len(program))
+	copy(programCopy, program)
+	return &AddressProgramV1{
+		hrp:     params.AddrHRPV1(),
+		program: programCopy,
+	}, nil
+}
+
+// String returns the string encoding of the payment address for the associated
+// script version and payment script.
+//
+// This is part of the Address interface implementation.
+func (addr *AddressProgramV1) String() string {
+	// The format for the data portion of version 1 addresses is the script
+	// version followed by the base32 encoding of the program:
+	//   5-bit script version || program
+	converted, _ := bech32.ConvertBits(addr.program, 8, 5, true)
+	data := make([]byte, 0, 1+len(converted))
+	data = append(data, 1)
+	data = append(data, converted...)
+
+	// This can't fail since the data only consists of 5-bit values.
+	encoded, _ := bech32.EncodeM(addr.hrp, data)
+	return encoded
+}
+
+// PaymentScript returns the script version associated with the address along
+// with a script to pay a transaction output to the address.
+//
+// This is part of the Address interface implementation.
+func (addr *AddressProgramV1) PaymentScript() (uint16, []byte) {
+	// A version 1 payment script is of the form:
+	//  <2 to 40 byte program>
+	script := make([]byte, 1+len(addr.program))
+	script[0] = txscript.OP_DATA_1 + byte(len(addr.program)-1)
+	copy(script[1:], addr.program)
+	return 1, script
+}
+
+// Program returns the program committed to by the address.
+func (addr *AddressProgramV1) Program() []byte {
+	program := make([]byte, len(addr.program))
+	copy(program, addr.program)
+	return program
+}
+
+// probablyV1Bech32Addr returns true when the provided string looks like a
+// version 1 bech32m address for the network identified by the provided
+// parameters as determined by its human-readable part and separator along with
+// it not having mixed case since that is not allowed by bech32m.  It always
+// returns false when the parameters do not support version 1 addresses.
+func probablyV1Bech32Addr(s string, params AddressParams) bool {
+	paramsV1, ok := params.(AddressParamsV1)
+	if !ok {
+		return false
+	}
+	prefix := paramsV1.AddrHRPV1() + "1"
+	if len(s) <= len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return false
+	}
+	return s == strings.ToLower(s) || s == strings.ToUpper(s)
+}
+
+// DecodeAddressV1 decodes the string encoding of an address and returns the
+// relevant Address if it is a valid encoding for a known version 1 address
+// type and is for the network identified by the provided parameters.
+//
+// The description of the error returned for strings with typos includes the
+// positions of the characters that are likely to be mistyped when they can be
+// determined.
+func DecodeAddressV1(addr string, params AddressParamsV1) (Address, error) {
+	// Attempt to decode the address and point out likely typos on failure.
+	hrp, data, err := bech32.DecodeM(addr)
+	if err != nil {
+		kind := ErrMalformedAddress
+		var errChecksum bech32.ErrInvalidChecksum
+		if errors.As(err, &errChecksum) {
+			kind = ErrBadAddressChecksum
+		}
+		str := fmt.Sprintf("failed to decode address %q: %v", addr, err)
+		if typos := bech32.LocateErrors(addr, bech32.VersionM); len(typos) > 0 {
+			str += fmt.Sprintf(" (likely typo at position(s) %v)", typos)
+		}
+		return nil, makeError(kind, str)
+	}
+	if hrp != params.AddrHRPV1() {
+		str := fmt.Sprintf("address %q is not for the provided network", addr)
+		return nil, makeError(ErrUnsupportedAddress, str)
+	}
+
+	// Ensure the decoded data has the expected script version.
+	if len(data) < 1 {
+		str := fmt.Sprintf("address %q decoded data is empty", addr)
+		return nil, makeError(ErrMalformedAddressData, str)
+	}
+	if data[0] != 1 {
+		str := fmt.Sprintf("address %q is for unsupported script version %d",
+			addr, data[0])
+		return nil, makeError(ErrUnsupportedScriptVersion, str)
+	}
+
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		str := fmt.Sprintf("address %q has malformed program: %v", addr, err)
+		return nil, makeError(ErrMalformedAddressData, str)
+	}
+	return NewAddressProgramV1(program, params)
+}
@@ -39,6 +39,10 @@ const (
 	// ErrInvalidHashLen indicates that either a public key hash or a script
 	// hash is not an allowed length.
 	ErrInvalidHashLen = ErrorKind("ErrInvalidHashLen")
+
+	// ErrInvalidProgramLen indicates that the program committed to by a
+	// version 1 address is not an allowed length.
+	ErrInvalidProgramLen = ErrorKind("ErrInvalidProgramLen")
 )
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -24,6 +24,7 @@ func TestErrorKindStringer(t *testing.T) {
 		{ErrInvalidPubKey, "ErrInvalidPubKey"},
 		{ErrInvalidPubKeyFormat, "ErrInvalidPubKeyFormat"},
 		{ErrInvalidHashLen, "ErrInvalidHashLen"},
+		{ErrInvalidProgramLen, "ErrInvalidProgramLen"},
 	}
 
 	for i, test := range tests {
@@ -0,0 +1,141 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+// SigHashTestVector describes a single signature hash test vector suitable for
+// validating other implementations.  All byte fields are hex encoded and the
+// JSON field names are stable.
+//
+// Note that the signature hash is not reversed like block and transaction
+// hashes.
+type SigHashTestVector struct {
+	// Tx is the serialized transaction.
+	Tx string `json:"tx"`
+
+	// Script is the version 0 script being signed.
+	Script string `json:"script"`
+
+	// InputIndex is the index of the transaction input being signed.
+	InputIndex int `json:"inputindex"`
+
+	// HashType is the signature hash type.
+	HashType SigHashType `json:"hashtype"`
+
+	// SigHash is the resulting 32-byte signature hash.
+	SigHash string `json:"sighash"`
+}
+
+// testVectorSource produces a deterministic stream of pseudorandom values
+// derived from a seed for generating test vectors.
+type testVectorSource struct {
+	seed    []byte
+	counter uint64
+}
+
+// bytes returns the next 32 bytes from the source.
+func (s *testVectorSource) bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(s.seed)
+	var counter [8]byte
+	binary.LittleEndian.PutUint64(counter[:], s.counter)
+	buf.Write(counter[:])
+	s.counter++
+	return chainhash.HashB(buf.Bytes())
+}
+
+// uint32n returns the next value from the source in the range [0, n).
+func (s *testVectorSource) uint32n(n uint32) uint32 {
+	return binary.LittleEndian.Uint32(s.bytes()) % n
+}
+
+// p2pkhScript returns a version 0 pay-to-pubkey-hash script that pays to a
+// public key hash from the source.
+func (s *testVectorSource) p2pkhScript() []byte {
+	script := make([]byte, 0, 25)
+	script = append(script, OP_DUP, OP_HASH160, OP_DATA_20)
+	script = append(script, s.bytes()[:20]...)
+	return append(script, OP_EQUALVERIFY, OP_CHECKSIG)
+}
+
+// tx returns a transaction with one to three inputs and at least as many
+// outputs as inputs populated from the source.
+func (s *testVectorSource) tx() *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	tx.LockTime = s.uint32n(500000)
+	numInputs := s.uint32n(3) + 1
+	for i := uint32(0); i < numInputs; i++ {
+		var hash chainhash.Hash
+		copy(hash[:], s.bytes())
+		prevOut := wire.NewOutPoint(&hash, s.uint32n(4), wire.TxTreeRegular)
+		txIn := wire.NewTxIn(prevOut, int64(s.uint32n(1e9)), nil)
+		txIn.Sequence = wire.MaxTxInSequenceNum - s.uint32n(2)
+		txIn.BlockHeight = s.uint32n(500000)
+		txIn.BlockIndex = s.uint32n(100)
+		txIn.SignatureScript = s.bytes()[:s.uint32n(32)]
+		tx.AddTxIn(txIn)
+	}
+	numOutputs := numInputs + s.uint32n(2)
+	for i := uint32(0); i < numOutputs; i++ {
+		tx.AddTxOut(wire.NewTxOut(int64(s.uint32n(1e9)), s.p2pkhScript()))
+	}
+	return tx
+}
+
+// testVectorHashTypes houses the signature hash types the generated test
+// vectors cycle through.
+var testVectorHashTypes = []SigHashType{
+	SigHashAll,
+	SigHashNone,
+	SigHashSingle,
+	SigHashAll | SigHashAnyOneCanPay,
+	SigHashNone | SigHashAnyOneCanPay,
+	SigHashSingle | SigHashAnyOneCanPay,
+}
+
+// GenerateSigHashTestVectors returns the provided number of signature hash
+// test vectors deterministically derived from the provided seed.  The same
+// seed and count always produce the same vectors.
+//
+// The vectors use transactions with varying numbers of inputs and outputs and
+// cycle through all of the signature hash types.
+func GenerateSigHashTestVectors(seed []byte, count int) []SigHashTestVector {
+	src := &testVectorSource{seed: seed}
+	vectors := make([]SigHashTestVector, 0, count)
+	for i := 0; i < count; i++ {
+		tx := src.tx()
+		script := src.p2pkhScript()
+		idx := int(src.uint32n(uint32(len(tx.TxIn))))
+		hashType := testVectorHashTypes[i%len(testVectorHashTypes)]
+		sigHash, err := CalcSignatureHash(script, hashType, tx, idx, nil)
+		if err != nil {
+			// This is not possible since the generated scripts always parse
+			// and there are always at least as many outputs as inputs, but
+			// skip the vector rather than producing an incorrect one.
+			continue
+		}
+		serializedTx, err := tx.Bytes()
+		if err != nil {
+			continue
+		}
+
+		vectors = append(vectors, SigHashTestVector{
+			Tx:         hex.EncodeToString(serializedTx),
+			Script:     hex.EncodeToString(script),
+			InputIndex: idx,
+			HashType:   hashType,
+			SigHash:    hex.EncodeToString(sigHash),
+		})
+	}
+	return vectors
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestGenerateSigHashTestVectors ensures the generated signature hash test
+// vectors are deterministic, survive a JSON round trip, and match the
+// signature hashes calculated from their decoded fields.
+func TestGenerateSigHashTestVectors(t *testing.T) {
+	seed := []byte("sighash test vectors")
+	vectors := GenerateSigHashTestVectors(seed, 24)
+	if len(vectors) != 24 {
+		t.Fatalf("unexpected number of vectors -- got %d, want 24",
+			len(vectors))
+	}
+	if !reflect.DeepEqual(vectors, GenerateSigHashTestVectors(seed, 24)) {
+		t.Fatal("generated vectors are not deterministic")
+	}
+
+	serialized, err := json.Marshal(vectors)
+	if err != nil {
+		t.Fatalf("unable to marshal vectors: %v", err)
+	}
+	var decoded []SigHashTestVector
+	if err := json.Unmarshal(serialized, &decoded); err != nil {
+		t.Fatalf("unable to unmarshal vectors: %v", err)
+	}
+
+	hashTypes := make(map[SigHashType]struct{})
+	for i, vector := range decoded {
+		rawTx, err := hex.DecodeString(vector.Tx)
+		if err != nil {
+			t.Fatalf("vector #%d: bad tx hex: %v", i, err)
+		}
+		var tx wire.MsgTx
+		if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+			t.Fatalf("vector #%d: unable to deserialize tx: %v", i, err)
+		}
+		script, err := hex.DecodeString(vector.Script)
+		if err != nil {
+			t.Fatalf("vector #%d: bad script hex: %v", i, err)
+		}
+		want, err := hex.DecodeString(vector.SigHash)
+		if err != nil {
+			t.Fatalf("vector #%d: bad sighash hex: %v", i, err)
+		}
+
+		got, err := CalcSignatureHash(script, vector.HashType, &tx,
+			vector.InputIndex, nil)
+		if err != nil {
+			t.Fatalf("vector #%d: unable to calculate sighash: %v", i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("vector #%d: unexpected sighash -- got %x, want %x", i,
+				got, want)
+		}
+		hashTypes[vector.HashType] = struct{}{}
+	}
+	if len(hashTypes) != len(testVectorHashTypes) {
+		t.Fatalf("unexpected number of hash types -- got %d, want %d",
+			len(hashTypes), len(testVectorHashTypes))
+	}
+}
@@ -46,6 +46,25 @@ import (
 	"time"
 )
 
+// NAT is an interface representing a NAT traversal option, for example UPnP
+// or NAT-PMP.  It provides methods to query and manipulate this traversal to
+// allow access to services.
+type NAT interface {
+	// GetExternalAddress returns the external IP address of the NAT.
+	GetExternalAddress() (addr net.IP, err error)
+
+	// AddPortMapping adds a port mapping for the given protocol from the
+	// external port to the internal port with the provided description and
+	// timeout in seconds.  It returns the mapped external port which might
+	// differ from the requested one.
+	AddPortMapping(protocol string, externalPort, internalPort int,
+		description string, timeout int) (mappedExternalPort int, err error)
+
+	// DeletePortMapping removes the port mapping for the given protocol and
+	// ports.
+	DeletePortMapping(protocol string, externalPort, internalPort int) (err error)
+}
+
 type upnpNAT struct {
 	serviceURL string
 	ourIP      string
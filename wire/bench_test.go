@@ -665,3 +665,46 @@ func BenchmarkHashH(b *testing.B) {
 		_ = chainhash.HashH(txBytes)
 	}
 }
+
+// BenchmarkReadMessageTx performs a benchmark on how long it takes to read a
+// transaction message.
+func BenchmarkReadMessageTx(b *testing.B) {
+	var buf bytes.Buffer
+	err := WriteMessage(&buf, multiTx, ProtocolVersion, MainNet)
+	if err != nil {
+		b.Fatalf("WriteMessage: unexpected error: %v", err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Seek(0, 0)
+		_, _, _, err := ReadMessageN(r, ProtocolVersion, MainNet)
+		if err != nil {
+			b.Fatalf("ReadMessageN: unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkReadPooledMessageTx performs a benchmark on how long it takes to
+// read a transaction message using pooled payload buffers.
+func BenchmarkReadPooledMessageTx(b *testing.B) {
+	var buf bytes.Buffer
+	err := WriteMessage(&buf, multiTx, ProtocolVersion, MainNet)
+	if err != nil {
+		b.Fatalf("WriteMessage: unexpected error: %v", err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Seek(0, 0)
+		_, _, payload, err := ReadPooledMessageN(r, ProtocolVersion, MainNet)
+		if err != nil {
+			b.Fatalf("ReadPooledMessageN: unexpected error: %v", err)
+		}
+		ReleasePayload(payload)
+	}
+}
@@ -494,6 +494,44 @@ func writeElements(w io.Writer, elements ...interface{}) error {
 	return nil
 }
 
+// lenReader is an io.Reader that reports the number of bytes that remain to be
+// read from it, such as *bytes.Buffer and *bytes.Reader.  Messages are always
+// decoded from such a reader once their payload has been read.
+type lenReader interface {
+	io.Reader
+	Len() int
+}
+
+// checkRemaining returns an error when r reports that fewer than the provided
+// number of bytes remain to be read from it.  This allows the decoders to
+// reject lengths that are impossible to satisfy before allocating memory for
+// them.  The returned error is the same one io.ReadFull would return when
+// attempting to read the bytes, so the observed behavior for truncated data is
+// unchanged.  No error is returned for readers that do not report the number
+// of remaining bytes.
+func checkRemaining(r io.Reader, n uint64) error {
+	lr, ok := r.(lenReader)
+	if !ok || n <= uint64(lr.Len()) {
+		return nil
+	}
+	if lr.Len() == 0 {
+		return io.EOF
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// countFits returns whether or not the provided number of elements, each of
+// which is serialized with at least the given number of bytes, could possibly
+// be read from r.  It always returns true for readers that do not report the
+// number of remaining bytes.
+func countFits(r io.Reader, count uint64, minElemSize uint64) bool {
+	lr, ok := r.(lenReader)
+	if !ok {
+		return true
+	}
+	return count <= uint64(lr.Len())/minElemSize
+}
+
 // ReadVarInt reads a variable length integer from r and returns it as a uint64.
 func ReadVarInt(r io.Reader, pver uint32) (uint64, error) {
 	const op = "ReadVarInt"
@@ -630,6 +668,9 @@ func ReadVarString(r io.Reader, pver uint32) (string, error) {
 			"[count %d, max %d]", count, MaxMessagePayload)
 		return "", messageError(op, ErrVarStringTooLong, msg)
 	}
+	if err := checkRemaining(r, count); err != nil {
+		return "", err
+	}
 
 	buf := make([]byte, count)
 	_, err = io.ReadFull(r, buf)
@@ -716,6 +757,9 @@ func ReadVarBytes(r io.Reader, pver uint32, maxAllowed uint32,
 			"[count %d, max %d]", fieldName, count, maxAllowed)
 		return nil, messageError(op, ErrVarBytesTooLong, msg)
 	}
+	if err := checkRemaining(r, count); err != nil {
+		return nil, err
+	}
 
 	b := make([]byte, count)
 	_, err = io.ReadFull(r, b)
@@ -859,6 +859,40 @@ func TestVarBytesOverflowErrors(t *testing.T) {
 	}
 }
 
+// TestVarLenExceedsPayload ensures variable length strings and byte arrays
+// with lengths that exceed the remaining data are rejected with the same error
+// as reading the truncated data.
+func TestVarLenExceedsPayload(t *testing.T) {
+	pver := ProtocolVersion
+
+	tests := []struct {
+		name string
+		buf  []byte // Wire encoding
+		err  error  // Expected error
+	}{
+		{"no data", []byte{0xfe, 0x00, 0x00, 0x00, 0x01}, io.EOF},
+		{"partial data", []byte{0xfe, 0x00, 0x00, 0x00, 0x01, 0x01},
+			io.ErrUnexpectedEOF},
+	}
+
+	for _, test := range tests {
+		_, err := ReadVarString(bytes.NewReader(test.buf), pver)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: ReadVarString wrong error got: %v, want: %v",
+				test.name, err, test.err)
+			continue
+		}
+
+		_, err = ReadVarBytes(bytes.NewReader(test.buf), pver,
+			MaxMessagePayload, "test payload")
+		if !errors.Is(err, test.err) {
+			t.Errorf("%q: ReadVarBytes wrong error got: %v, want: %v",
+				test.name, err, test.err)
+			continue
+		}
+	}
+}
+
 // TestRandomUint64 exercises the randomness of the random number generator on
 // the system by ensuring the probability of the generated numbers.  If the RNG
 // is evenly distributed as a proper cryptographic RNG should be, there really
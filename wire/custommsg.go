@@ -0,0 +1,131 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CustomMessage describes a custom message type that is registered with
+// RegisterCustomMessage in order to allow it to be read and written on a
+// private network.
+type CustomMessage struct {
+	// Command is the command that identifies the message in message headers.
+	// It must not be the command of any of the standard messages.
+	Command string
+
+	// MaxPayload is the maximum allowed payload length of the message
+	// regardless of the protocol version.  The maximum payload length
+	// reported by the message itself for a given protocol version is also
+	// enforced.
+	MaxPayload uint32
+
+	// New returns a new empty instance of the message that the payload of
+	// received messages with the command is decoded into.  The Command method
+	// of the returned message must return the same command.
+	New func() Message
+}
+
+var (
+	// customMsgsMtx protects customMsgs.
+	customMsgsMtx sync.RWMutex
+
+	// customMsgs houses the registered custom message types keyed by network
+	// and then by command.
+	customMsgs = make(map[CurrencyNet]map[string]CustomMessage)
+)
+
+// RegisterCustomMessage registers the provided custom message type for the
+// provided network which allows messages of the type to be read and written
+// without modifying this package.  This is primarily intended for permissioned
+// deployments that need to exchange additional messages between their peers.
+//
+// Custom messages are only allowed on private networks, so an error is
+// returned when the provided network is one of the standard networks.  An
+// error is also returned when the command is malformed, is the command of a
+// standard message, or is already registered for the network.
+//
+// This function is safe for concurrent access.
+func RegisterCustomMessage(net CurrencyNet, msgType CustomMessage) error {
+	const op = "RegisterCustomMessage"
+	if _, ok := bnStrings[net]; ok {
+		str := fmt.Sprintf("custom messages are not allowed on %v", net)
+		return messageError(op, ErrCustomMsgNotAllowed, str)
+	}
+
+	cmd := msgType.Command
+	if len(cmd) > CommandSize {
+		str := fmt.Sprintf("command [%s] is too long [max %v]", cmd,
+			CommandSize)
+		return messageError(op, ErrCmdTooLong, str)
+	}
+	if cmd == "" || !isStrictAscii(cmd) {
+		str := fmt.Sprintf("invalid command %v", []byte(cmd))
+		return messageError(op, ErrMalformedCmd, str)
+	}
+	if _, err := makeEmptyMessage(cmd); err == nil {
+		str := fmt.Sprintf("command [%s] is a standard message", cmd)
+		return messageError(op, ErrDuplicateCmd, str)
+	}
+	if msgType.MaxPayload > MaxMessagePayload {
+		str := fmt.Sprintf("max payload %d for command [%s] exceeds the "+
+			"max message payload %d", msgType.MaxPayload, cmd,
+			MaxMessagePayload)
+		return messageError(op, ErrPayloadTooLarge, str)
+	}
+	if msgType.New == nil {
+		str := fmt.Sprintf("no constructor for command [%s]", cmd)
+		return messageError(op, ErrInvalidMsg, str)
+	}
+	if msg := msgType.New(); msg == nil || msg.Command() != cmd {
+		str := fmt.Sprintf("constructor for command [%s] does not create "+
+			"messages with the command", cmd)
+		return messageError(op, ErrInvalidMsg, str)
+	}
+
+	customMsgsMtx.Lock()
+	defer customMsgsMtx.Unlock()
+	netMsgs := customMsgs[net]
+	if _, ok := netMsgs[cmd]; ok {
+		str := fmt.Sprintf("command [%s] is already registered for %v", cmd,
+			net)
+		return messageError(op, ErrDuplicateCmd, str)
+	}
+	if netMsgs == nil {
+		netMsgs = make(map[string]CustomMessage)
+		customMsgs[net] = netMsgs
+	}
+	netMsgs[cmd] = msgType
+	return nil
+}
+
+// lookupCustomMessage returns the custom message type registered with the
+// provided command for the provided network and whether or not it exists.
+//
+// This function is safe for concurrent access.
+func lookupCustomMessage(net CurrencyNet, command string) (CustomMessage, bool) {
+	customMsgsMtx.RLock()
+	msgType, ok := customMsgs[net][command]
+	customMsgsMtx.RUnlock()
+	return msgType, ok
+}
+
+// makeEmptyNetMessage creates a message of the appropriate concrete type based
+// on the command, including custom messages registered for the provided
+// network, along with the maximum payload length allowed for it regardless of
+// the protocol version.
+func makeEmptyNetMessage(command string, net CurrencyNet) (Message, uint32, error) {
+	msg, err := makeEmptyMessage(command)
+	if err == nil {
+		return msg, MaxMessagePayload, nil
+	}
+
+	msgType, ok := lookupCustomMessage(net, command)
+	if !ok {
+		return nil, 0, err
+	}
+	return msgType.New(), msgType.MaxPayload, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// msgTestCustom is a custom message used to test the custom message registry.
+// It consists of a single variable length byte slice.
+type msgTestCustom struct {
+	Data []byte
+}
+
+// BtcDecode decodes r into the receiver.
+func (msg *msgTestCustom) BtcDecode(r io.Reader, pver uint32) error {
+	data, err := ReadVarBytes(r, pver, MaxMessagePayload, "msgTestCustom.Data")
+	if err != nil {
+		return err
+	}
+	msg.Data = data
+	return nil
+}
+
+// BtcEncode encodes the receiver to w.
+func (msg *msgTestCustom) BtcEncode(w io.Writer, pver uint32) error {
+	return WriteVarBytes(w, pver, msg.Data)
+}
+
+// Command returns the protocol command string for the message.
+func (msg *msgTestCustom) Command() string {
+	return "testcustom"
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.
+func (msg *msgTestCustom) MaxPayloadLength(pver uint32) uint32 {
+	return MaxMessagePayload
+}
+
+// TestRegisterCustomMessageErrors ensures registering custom message types
+// fails with the expected errors.
+func TestRegisterCustomMessageErrors(t *testing.T) {
+	const privNet = CurrencyNet(0x0badc0de)
+	newMsg := func() Message { return &msgTestCustom{} }
+	validType := CustomMessage{
+		Command:    "testcustom",
+		MaxPayload: 1024,
+		New:        newMsg,
+	}
+
+	tests := []struct {
+		name    string
+		net     CurrencyNet
+		msgType CustomMessage
+		wantErr error
+	}{{
+		name:    "mainnet",
+		net:     MainNet,
+		msgType: validType,
+		wantErr: ErrCustomMsgNotAllowed,
+	}, {
+		name:    "testnet",
+		net:     TestNet3,
+		msgType: validType,
+		wantErr: ErrCustomMsgNotAllowed,
+	}, {
+		name:    "simnet",
+		net:     SimNet,
+		msgType: validType,
+		wantErr: ErrCustomMsgNotAllowed,
+	}, {
+		name:    "regnet",
+		net:     RegNet,
+		msgType: validType,
+		wantErr: ErrCustomMsgNotAllowed,
+	}, {
+		name:    "command too long",
+		net:     privNet,
+		msgType: CustomMessage{"testcustomtoolong", 1024, newMsg},
+		wantErr: ErrCmdTooLong,
+	}, {
+		name:    "empty command",
+		net:     privNet,
+		msgType: CustomMessage{"", 1024, newMsg},
+		wantErr: ErrMalformedCmd,
+	}, {
+		name:    "non-ascii command",
+		net:     privNet,
+		msgType: CustomMessage{"test\x00", 1024, newMsg},
+		wantErr: ErrMalformedCmd,
+	}, {
+		name:    "standard command",
+		net:     privNet,
+		msgType: CustomMessage{CmdPing, 1024, newMsg},
+		wantErr: ErrDuplicateCmd,
+	}, {
+		name:    "max payload too large",
+		net:     privNet,
+		msgType: CustomMessage{"testcustom", MaxMessagePayload + 1, newMsg},
+		wantErr: ErrPayloadTooLarge,
+	}, {
+		name:    "nil constructor",
+		net:     privNet,
+		msgType: CustomMessage{"testcustom", 1024, nil},
+		wantErr: ErrInvalidMsg,
+	}, {
+		name:    "constructor command mismatch",
+		net:     privNet,
+		msgType: CustomMessage{"other", 1024, newMsg},
+		wantErr: ErrInvalidMsg,
+	}, {
+		name: "constructor returns nil",
+		net:  privNet,
+		msgType: CustomMessage{"testcustom", 1024, func() Message {
+			return nil
+		}},
+		wantErr: ErrInvalidMsg,
+	}}
+
+	for _, test := range tests {
+		err := RegisterCustomMessage(test.net, test.msgType)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: mismatched err -- got %v, want %v", test.name, err,
+				test.wantErr)
+			continue
+		}
+	}
+
+	// Ensure registering the same command for a network more than once fails
+	// while registering it for another network succeeds.
+	if err := RegisterCustomMessage(privNet, validType); err != nil {
+		t.Fatalf("unexpected error registering custom message: %v", err)
+	}
+	err := RegisterCustomMessage(privNet, validType)
+	if !errors.Is(err, ErrDuplicateCmd) {
+		t.Fatalf("mismatched err for duplicate registration -- got %v, want %v",
+			err, ErrDuplicateCmd)
+	}
+	if err := RegisterCustomMessage(privNet+1, validType); err != nil {
+		t.Fatalf("unexpected error registering custom message for other "+
+			"network: %v", err)
+	}
+}
+
+// TestCustomMessage ensures custom messages registered for a private network
+// can be written and read on that network only and that the registered max
+// payload is enforced.
+func TestCustomMessage(t *testing.T) {
+	const privNet = CurrencyNet(0x0badbeef)
+	const otherNet = CurrencyNet(0x0badbeef + 1)
+	const maxPayload = 16
+	err := RegisterCustomMessage(privNet, CustomMessage{
+		Command:    "testcustom",
+		MaxPayload: maxPayload,
+		New:        func() Message { return &msgTestCustom{} },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error registering custom message: %v", err)
+	}
+
+	// Ensure the message round trips on the private network.
+	pver := ProtocolVersion
+	msg := &msgTestCustom{Data: []byte("custom")}
+	var buf bytes.Buffer
+	if err := WriteMessage(&buf, msg, pver, privNet); err != nil {
+		t.Fatalf("unexpected error writing custom message: %v", err)
+	}
+	encoded := append([]byte(nil), buf.Bytes()...)
+	gotMsg, _, err := ReadMessage(bytes.NewReader(encoded), pver, privNet)
+	if err != nil {
+		t.Fatalf("unexpected error reading custom message: %v", err)
+	}
+	if !reflect.DeepEqual(gotMsg, msg) {
+		t.Fatalf("mismatched custom message -- got %v, want %v",
+			spew.Sdump(gotMsg), spew.Sdump(msg))
+	}
+
+	// Ensure the message is not recognized on other networks by rewriting
+	// the network magic of the encoded message.
+	for _, net := range []CurrencyNet{MainNet, otherNet} {
+		rewritten := append([]byte(nil), encoded...)
+		copy(rewritten, makeHeader(net, "", 0, 0)[:4])
+		_, _, err := ReadMessage(bytes.NewReader(rewritten), pver, net)
+		if !errors.Is(err, ErrUnknownCmd) {
+			t.Fatalf("mismatched err reading on %v -- got %v, want %v", net,
+				err, ErrUnknownCmd)
+		}
+	}
+
+	// Ensure writing a message that exceeds the registered max payload fails
+	// on the private network.
+	bigMsg := &msgTestCustom{Data: bytes.Repeat([]byte{0x01}, maxPayload)}
+	buf.Reset()
+	err = WriteMessage(&buf, bigMsg, pver, privNet)
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("mismatched err writing large message -- got %v, want %v",
+			err, ErrPayloadTooLarge)
+	}
+
+	// Ensure reading a message that exceeds the registered max payload fails
+	// on the private network.
+	buf.Reset()
+	if err := WriteMessage(&buf, bigMsg, pver, otherNet); err != nil {
+		t.Fatalf("unexpected error writing large message: %v", err)
+	}
+	bigEncoded := buf.Bytes()
+	copy(bigEncoded, makeHeader(privNet, "", 0, 0)[:4])
+	_, _, err = ReadMessage(bytes.NewReader(bigEncoded), pver, privNet)
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("mismatched err reading large message -- got %v, want %v",
+			err, ErrPayloadTooLarge)
+	}
+}
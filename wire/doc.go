@@ -48,13 +48,15 @@ interactions in no particular order.
 
 	Peer A Sends                          Peer B Responds
 	----------------------------------------------------------------------------
-	getaddr message (MsgGetAddr)          addr message (MsgAddr)
+	getaddr message (MsgGetAddr)          addr message (MsgAddr) -or-
+	                                      addrv2 message (MsgAddrV2)
 	getblocks message (MsgGetBlocks)      inv message (MsgInv)
 	inv message (MsgInv)                  getdata message (MsgGetData)
 	getdata message (MsgGetData)          block message (MsgBlock) -or-
 	                                      tx message (MsgTx) -or-
 	                                      notfound message (MsgNotFound)
 	getheaders message (MsgGetHeaders)    headers message (MsgHeaders)
+	getblocktxn message (MsgGetBlockTxn)  blocktxn message (MsgBlockTxn)
 	ping message (MsgPing)                pong message (MsgHeaders)* -or-
 	                                      (none -- Ability to send message is enough)
 
@@ -122,6 +124,13 @@ a remote node running a Decred peer.  Example syntax is:
 		// Log and handle the error
 	}
 
+Callers that read many messages and do not need their raw payloads once they
+are decoded may use ReadPooledMessageN instead in order to reuse the buffers
+that hold the raw payloads.  The buffers are returned to the pool via
+ReleasePayload once the raw payload is no longer needed.  The raw payloads of
+blocks are never pooled since they are typically retained along with the
+decoded block.
+
 # Writing Messages
 
 In order to marshall Decred messages to the wire, use the WriteMessage
@@ -140,6 +149,15 @@ from a remote peer is:
 		// Log and handle the error
 	}
 
+# Custom Messages
+
+Private networks, such as those used by permissioned deployments, may exchange
+additional messages that are not part of the standard protocol by registering
+them with RegisterCustomMessage along with the maximum allowed payload size.
+Registered messages are read and written the same as the standard messages, but
+only on the networks they are registered for.  Custom messages are not allowed
+on any of the standard networks.
+
 # Errors
 
 Errors returned by this package are either the raw errors provided by underlying
@@ -133,6 +133,39 @@ const (
 	// ErrTooManyTSpends is returned when the number of tspend hashes
 	// exceeds the maximum allowed.
 	ErrTooManyTSpends
+
+	// ErrSketchTooLarge is returned when a transaction reconciliation sketch
+	// exceeds the maximum allowed size.
+	ErrSketchTooLarge
+
+	// ErrTooManyShortIDs is returned when the number of transaction short
+	// ids exceeds the maximum allowed.
+	ErrTooManyShortIDs
+
+	// ErrInvalidTxIndex is returned when a transaction index exceeds the
+	// maximum number of transactions that could possibly fit into a
+	// transaction tree.
+	ErrInvalidTxIndex
+
+	// ErrInvalidShortID is returned when a compact block transaction short id
+	// does not fit into the number of bytes used to encode it.
+	ErrInvalidShortID
+
+	// ErrUnknownNetAddrType is returned when a network address type is not
+	// one of the supported types.
+	ErrUnknownNetAddrType
+
+	// ErrInvalidNetAddrLen is returned when the length of an encoded network
+	// address does not match the length required by its type.
+	ErrInvalidNetAddrLen
+
+	// ErrCustomMsgNotAllowed is returned when attempting to register a
+	// custom message type for one of the standard networks.
+	ErrCustomMsgNotAllowed
+
+	// ErrDuplicateCmd is returned when attempting to register a custom
+	// message type with a command that is already in use.
+	ErrDuplicateCmd
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -168,6 +201,14 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrTooManyInitStateTypes:         "ErrTooManyInitStateTypes",
 	ErrInitStateTypeTooLong:          "ErrInitStateTypeTooLong",
 	ErrTooManyTSpends:                "ErrTooManyTSpends",
+	ErrSketchTooLarge:                "ErrSketchTooLarge",
+	ErrTooManyShortIDs:               "ErrTooManyShortIDs",
+	ErrInvalidTxIndex:                "ErrInvalidTxIndex",
+	ErrInvalidShortID:                "ErrInvalidShortID",
+	ErrUnknownNetAddrType:            "ErrUnknownNetAddrType",
+	ErrInvalidNetAddrLen:             "ErrInvalidNetAddrLen",
+	ErrCustomMsgNotAllowed:           "ErrCustomMsgNotAllowed",
+	ErrDuplicateCmd:                  "ErrDuplicateCmd",
 }
 
 // String returns the ErrorCode as a human-readable name.
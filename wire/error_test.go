@@ -50,6 +50,14 @@ func TestMessageErrorCodeStringer(t *testing.T) {
 		{ErrTooManyInitStateTypes, "ErrTooManyInitStateTypes"},
 		{ErrInitStateTypeTooLong, "ErrInitStateTypeTooLong"},
 		{ErrTooManyTSpends, "ErrTooManyTSpends"},
+		{ErrSketchTooLarge, "ErrSketchTooLarge"},
+		{ErrTooManyShortIDs, "ErrTooManyShortIDs"},
+		{ErrInvalidTxIndex, "ErrInvalidTxIndex"},
+		{ErrInvalidShortID, "ErrInvalidShortID"},
+		{ErrUnknownNetAddrType, "ErrUnknownNetAddrType"},
+		{ErrInvalidNetAddrLen, "ErrInvalidNetAddrLen"},
+		{ErrCustomMsgNotAllowed, "ErrCustomMsgNotAllowed"},
+		{ErrDuplicateCmd, "ErrDuplicateCmd"},
 
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
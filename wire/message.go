@@ -58,6 +58,15 @@ const (
 	CmdCFilterV2      = "cfilterv2"
 	CmdGetInitState   = "getinitstate"
 	CmdInitState      = "initstate"
+	CmdSendTxRecon    = "sendtxrcncl"
+	CmdReqRecon       = "reqrecon"
+	CmdSketch         = "sketch"
+	CmdReconcilDiff   = "reconcildiff"
+	CmdSendCmpct      = "sendcmpct"
+	CmdCmpctBlock     = "cmpctblock"
+	CmdGetBlockTxn    = "getblocktxn"
+	CmdBlockTxn       = "blocktxn"
+	CmdAddrV2         = "addrv2"
 )
 
 // Message is an interface that describes a Decred message.  A type that
@@ -168,6 +177,33 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdInitState:
 		msg = &MsgInitState{}
 
+	case CmdSendTxRecon:
+		msg = &MsgSendTxRecon{}
+
+	case CmdReqRecon:
+		msg = &MsgReqRecon{}
+
+	case CmdSketch:
+		msg = &MsgSketch{}
+
+	case CmdReconcilDiff:
+		msg = &MsgReconcilDiff{}
+
+	case CmdSendCmpct:
+		msg = &MsgSendCmpct{}
+
+	case CmdCmpctBlock:
+		msg = &MsgCmpctBlock{}
+
+	case CmdGetBlockTxn:
+		msg = &MsgGetBlockTxn{}
+
+	case CmdBlockTxn:
+		msg = &MsgBlockTxn{}
+
+	case CmdAddrV2:
+		msg = &MsgAddrV2{}
+
 	default:
 		str := fmt.Sprintf("unhandled command [%s]", command)
 		return nil, messageError(op, ErrUnknownCmd, str)
@@ -260,8 +296,14 @@ func WriteMessageN(w io.Writer, msg Message, pver uint32, dcrnet CurrencyNet) (i
 		return totalBytes, messageError(op, ErrPayloadTooLarge, msg)
 	}
 
-	// Enforce maximum message payload based on the message type.
+	// Enforce maximum message payload based on the message type, including
+	// the maximum of custom messages registered for the network.
 	mpl := msg.MaxPayloadLength(pver)
+	if msgType, ok := lookupCustomMessage(dcrnet, cmd); ok &&
+		msgType.MaxPayload < mpl {
+
+		mpl = msgType.MaxPayload
+	}
 	if uint32(lenp) > mpl {
 		str := fmt.Sprintf("message payload is too large - encoded "+
 			"%d bytes, but maximum message payload size for "+
@@ -300,12 +342,11 @@ func WriteMessage(w io.Writer, msg Message, pver uint32, dcrnet CurrencyNet) err
 	return err
 }
 
-// ReadMessageN reads, validates, and parses the next Decred Message from r for
-// the provided protocol version and Decred network.  It returns the number of
-// bytes read in addition to the parsed Message and raw bytes which comprise the
-// message.  This function is the same as ReadMessage except it also returns the
-// number of bytes read.
-func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, []byte, error) {
+// readMessageN reads, validates, and parses the next Decred Message from r for
+// the provided protocol version and Decred network.  The payload buffer is
+// borrowed from the payload pool when the pooled flag is set, except for block
+// payloads, and returned to it when any errors occur.
+func readMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet, pooled bool) (int, Message, []byte, error) {
 	const op = "ReadMessage"
 	totalBytes := 0
 	n, hdr, err := readMessageHeader(r)
@@ -337,8 +378,9 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 		return totalBytes, nil, nil, messageError(op, ErrMalformedCmd, msg)
 	}
 
-	// Create struct of appropriate message type based on the command.
-	msg, err := makeEmptyMessage(command)
+	// Create struct of appropriate message type based on the command,
+	// including any custom messages registered for the network.
+	msg, mpl, err := makeEmptyNetMessage(command, dcrnet)
 	if err != nil {
 		discardInput(r, hdr.length)
 		return totalBytes, nil, nil, err
@@ -347,7 +389,9 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 	// Check for maximum length based on the message type as a malicious client
 	// could otherwise create a well-formed header and set the length to max
 	// numbers in order to exhaust the machine's memory.
-	mpl := msg.MaxPayloadLength(pver)
+	if msgMpl := msg.MaxPayloadLength(pver); msgMpl < mpl {
+		mpl = msgMpl
+	}
 	if hdr.length > mpl {
 		discardInput(r, hdr.length)
 		msg := fmt.Sprintf("payload exceeds max length - header "+
@@ -356,11 +400,24 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 		return totalBytes, nil, nil, messageError(op, ErrPayloadTooLarge, msg)
 	}
 
-	// Read payload.
-	payload := make([]byte, hdr.length)
+	// Read payload.  Block payloads are never borrowed from the pool since
+	// they are typically retained along with the decoded block.
+	pooled = pooled && command != CmdBlock
+	var payload []byte
+	if pooled {
+		payload = payloadPool.Borrow(hdr.length)
+	} else {
+		payload = make([]byte, hdr.length)
+	}
+	releasePayload := func() {
+		if pooled {
+			payloadPool.Return(payload)
+		}
+	}
 	n, err = io.ReadFull(r, payload)
 	totalBytes += n
 	if err != nil {
+		releasePayload()
 		return totalBytes, nil, nil, err
 	}
 
@@ -369,6 +426,7 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 	if !bytes.Equal(checksum, hdr.checksum[:]) {
 		msg := fmt.Sprintf("payload checksum failed - header indicates %v, "+
 			"but actual checksum is %v.", hdr.checksum, checksum)
+		releasePayload()
 		return totalBytes, nil, nil, messageError(op, ErrPayloadChecksum, msg)
 	}
 
@@ -377,12 +435,46 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 	pr := bytes.NewBuffer(payload)
 	err = msg.BtcDecode(pr, pver)
 	if err != nil {
+		releasePayload()
 		return totalBytes, nil, nil, err
 	}
 
 	return totalBytes, msg, payload, nil
 }
 
+// ReadMessageN reads, validates, and parses the next Decred Message from r for
+// the provided protocol version and Decred network.  It returns the number of
+// bytes read in addition to the parsed Message and raw bytes which comprise the
+// message.  This function is the same as ReadMessage except it also returns the
+// number of bytes read.
+func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, []byte, error) {
+	return readMessageN(r, pver, dcrnet, false)
+}
+
+// ReadPooledMessageN is the same as ReadMessageN except the returned raw bytes
+// which comprise the message are borrowed from a pool of reusable buffers in
+// order to reduce the number of allocations when reading many messages whose
+// raw bytes are not needed once they are decoded.
+//
+// None of the decoded messages reference the raw bytes, so the caller may
+// return them to the pool via ReleasePayload as soon as it no longer needs
+// them.  Buffers that are never released are simply garbage collected.  The raw
+// bytes MUST NOT be accessed after they have been released.
+//
+// The raw bytes of block messages are never borrowed from the pool since they
+// are typically retained along with the decoded block.  They are owned by the
+// caller and MUST NOT be released.
+func ReadPooledMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, []byte, error) {
+	return readMessageN(r, pver, dcrnet, true)
+}
+
+// ReleasePayload returns the raw message bytes obtained from
+// ReadPooledMessageN for messages other than blocks to the pool of reusable
+// buffers.  The bytes MUST NOT be accessed after calling this function.
+func ReleasePayload(payload []byte) {
+	payloadPool.Return(payload)
+}
+
 // ReadMessage reads, validates, and parses the next Decred Message from r for
 // the provided protocol version and Decred network.  It returns the parsed
 // Message and raw bytes which comprise the message.  This function only differs
@@ -80,6 +80,15 @@ func TestMessage(t *testing.T) {
 	msgReject := NewMsgReject("block", RejectDuplicate, "duplicate block")
 	msgGetInitState := NewMsgGetInitState()
 	msgInitState := NewMsgInitState()
+	msgSendTxRecon := NewMsgSendTxRecon(1, 123123)
+	msgReqRecon := NewMsgReqRecon(10, 0x4000)
+	msgSketch := NewMsgSketch([]byte("payload"))
+	msgReconcilDiff := NewMsgReconcilDiff(true)
+	msgSendCmpct := NewMsgSendCmpct(true, 1)
+	msgCmpctBlock := NewMsgCmpctBlock(&testBlock.Header, 123123)
+	msgGetBlockTxn := NewMsgGetBlockTxn(&chainhash.Hash{})
+	msgBlockTxn := NewMsgBlockTxn(&chainhash.Hash{})
+	msgAddrV2 := NewMsgAddrV2()
 
 	tests := []struct {
 		in     Message     // Value to encode
@@ -112,6 +121,15 @@ func TestMessage(t *testing.T) {
 		{msgCFTypes, msgCFTypes, pver, MainNet, 26},
 		{msgGetInitState, msgGetInitState, pver, MainNet, 25},
 		{msgInitState, msgInitState, pver, MainNet, 27},
+		{msgSendTxRecon, msgSendTxRecon, pver, MainNet, 36},
+		{msgReqRecon, msgReqRecon, pver, MainNet, 30},
+		{msgSketch, msgSketch, pver, MainNet, 32},
+		{msgReconcilDiff, msgReconcilDiff, pver, MainNet, 26},
+		{msgSendCmpct, msgSendCmpct, pver, MainNet, 29},
+		{msgCmpctBlock, msgCmpctBlock, pver, MainNet, 216},
+		{msgGetBlockTxn, msgGetBlockTxn, pver, MainNet, 58},
+		{msgBlockTxn, msgBlockTxn, pver, MainNet, 58},
+		{msgAddrV2, msgAddrV2, pver, MainNet, 25},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -151,6 +169,52 @@ func TestMessage(t *testing.T) {
 		}
 	}
 
+	// Ensure messages read using pooled payload buffers are decoded the same
+	// and are unaffected by releasing the buffers.
+	for i, test := range tests {
+		// Encode to wire format.
+		var buf bytes.Buffer
+		err := WriteMessage(&buf, test.in, test.pver, test.dcrnet)
+		if err != nil {
+			t.Errorf("WriteMessage #%d error %v", i, err)
+			continue
+		}
+
+		// Decode from wire format and release the payload after
+		// overwriting it.  Block payloads are owned by the caller, so
+		// they are only overwritten.
+		rbuf := bytes.NewReader(buf.Bytes())
+		nr, msg, payload, err := ReadPooledMessageN(rbuf, test.pver,
+			test.dcrnet)
+		if err != nil {
+			t.Errorf("ReadPooledMessageN #%d error %v, msg %v", i, err,
+				spew.Sdump(msg))
+			continue
+		}
+		if !bytes.Equal(payload, buf.Bytes()[MessageHeaderSize:]) {
+			t.Errorf("ReadPooledMessageN #%d unexpected payload - got %x, "+
+				"want %x", i, payload, buf.Bytes()[MessageHeaderSize:])
+			continue
+		}
+		for j := range payload {
+			payload[j] = 0xff
+		}
+		if _, ok := msg.(*MsgBlock); !ok {
+			ReleasePayload(payload)
+		}
+		if !reflect.DeepEqual(msg, test.out) {
+			t.Errorf("ReadPooledMessageN #%d\n got: %v want: %v", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+
+		// Ensure the number of bytes read match the expected value.
+		if nr != test.bytes {
+			t.Errorf("ReadPooledMessageN #%d unexpected num bytes read - "+
+				"got %d, want %d", i, nr, test.bytes)
+		}
+	}
+
 	// Do the same thing for Read/WriteMessage, but ignore the bytes since
 	// they don't return them.
 	t.Logf("Running %d tests", len(tests))
@@ -443,3 +507,102 @@ func TestWriteMessageWireErrors(t *testing.T) {
 		}
 	}
 }
+
+// TestMaxPayloadLengthByVersion ensures messages that were introduced by a
+// given protocol version do not permit any payload for earlier versions and
+// that such messages are rejected before their payload is read.
+func TestMaxPayloadLengthByVersion(t *testing.T) {
+	tests := []struct {
+		msg  Message // Message to check
+		pver uint32  // Protocol version that introduced the message
+	}{
+		{&MsgFeeFilter{}, FeeFilterVersion},
+		{&MsgCFilter{}, NodeCFVersion},
+		{&MsgCFHeaders{}, NodeCFVersion},
+		{&MsgCFTypes{}, NodeCFVersion},
+		{&MsgGetCFilter{}, NodeCFVersion},
+		{&MsgGetCFHeaders{}, NodeCFVersion},
+		{&MsgCFilterV2{}, CFilterV2Version},
+		{&MsgGetCFilterV2{}, CFilterV2Version},
+		{&MsgGetInitState{}, InitStateVersion},
+		{&MsgInitState{}, InitStateVersion},
+		{&MsgSendTxRecon{}, TxReconciliationVersion},
+		{&MsgReqRecon{}, TxReconciliationVersion},
+		{&MsgSketch{}, TxReconciliationVersion},
+		{&MsgReconcilDiff{}, TxReconciliationVersion},
+		{&MsgSendCmpct{}, CompactBlockVersion},
+		{&MsgCmpctBlock{}, CompactBlockVersion},
+		{&MsgGetBlockTxn{}, CompactBlockVersion},
+		{&MsgBlockTxn{}, CompactBlockVersion},
+		{&MsgAddrV2{}, AddrV2Version},
+	}
+
+	for _, test := range tests {
+		cmd := test.msg.Command()
+		if got := test.msg.MaxPayloadLength(test.pver - 1); got != 0 {
+			t.Errorf("%s: unexpected max payload length for pver %d - "+
+				"got %d, want 0", cmd, test.pver-1, got)
+			continue
+		}
+		if got := test.msg.MaxPayloadLength(test.pver); got == 0 {
+			t.Errorf("%s: unexpected max payload length for pver %d - "+
+				"got 0, want > 0", cmd, test.pver)
+			continue
+		}
+
+		// Ensure a message with a payload is rejected for the earlier
+		// protocol version before reading it.
+		payload := []byte{0x00}
+		checksum := chainhash.HashB(payload)[0:4]
+		hdr := makeHeader(MainNet, cmd, uint32(len(payload)),
+			binary.LittleEndian.Uint32(checksum))
+		r := bytes.NewReader(append(hdr, payload...))
+		_, _, _, err := ReadMessageN(r, test.pver-1, MainNet)
+		if !errors.Is(err, ErrPayloadTooLarge) {
+			t.Errorf("%s: unexpected error for pver %d - got %v, want %v",
+				cmd, test.pver-1, err, ErrPayloadTooLarge)
+			continue
+		}
+	}
+}
+
+// TestVectorCountExceedsPayload ensures messages that contain vectors are
+// rejected when the number of entries they claim to have could not possibly
+// fit into the remaining payload.
+func TestVectorCountExceedsPayload(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Encoded count of 1000 entries followed by a single byte.
+	countBytes := []byte{0xfd, 0xe8, 0x03, 0x00}
+
+	tests := []struct {
+		msg Message   // Message to decode into
+		err ErrorCode // Expected error
+	}{
+		{&MsgInv{}, ErrTooManyVectors},
+		{&MsgGetData{}, ErrTooManyVectors},
+		{&MsgNotFound{}, ErrTooManyVectors},
+		{&MsgAddr{}, ErrTooManyAddrs},
+		{&MsgAddrV2{}, ErrTooManyAddrs},
+		{&MsgHeaders{}, ErrTooManyHeaders},
+	}
+
+	for _, test := range tests {
+		err := test.msg.BtcDecode(bytes.NewBuffer(countBytes), pver)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: unexpected error - got %v, want %v",
+				test.msg.Command(), err, test.err)
+			continue
+		}
+
+		// Ensure readers that do not report the remaining length still
+		// fail when attempting to read the entries.
+		r := newFixedReader(len(countBytes), countBytes)
+		err = test.msg.BtcDecode(r, pver)
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: unexpected error - got %v, want EOF",
+				test.msg.Command(), err)
+			continue
+		}
+	}
+}
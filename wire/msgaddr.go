@@ -72,6 +72,14 @@ func (msg *MsgAddr) BtcDecode(r io.Reader, pver uint32) error {
 		return messageError(op, ErrTooManyAddrs, msg)
 	}
 
+	// Limit to the number of addresses that could possibly fit into the
+	// remaining payload.
+	if !countFits(r, count, uint64(maxNetAddressPayload(pver))) {
+		msg := fmt.Sprintf("number of addresses exceeds the "+
+			"remaining payload [count %v]", count)
+		return messageError(op, ErrTooManyAddrs, msg)
+	}
+
 	addrList := make([]NetAddress, count)
 	msg.AddrList = make([]*NetAddress, 0, count)
 	for i := uint64(0); i < count; i++ {
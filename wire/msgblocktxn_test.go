@@ -0,0 +1,195 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// testBlockTxn returns a blocktxn message that contains the transactions of
+// the test block along with its wire encoding.
+func testBlockTxn() (*MsgBlockTxn, []byte) {
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgBlockTxn(&hash)
+	msg.Transactions = testBlock.Transactions
+	msg.STransactions = testBlock.STransactions
+
+	txLoc, sTxLoc := testBlockTxLocs[0], testBlockSTxLocs[0]
+	encoded := append([]byte{}, hash[:]...) // Block hash
+	encoded = append(encoded, 0x01)         // Num txns
+	encoded = append(encoded,
+		testBlockBytes[txLoc.TxStart:txLoc.TxStart+txLoc.TxLen]...) // Tx
+	encoded = append(encoded, 0x01) // Num stake txns
+	encoded = append(encoded,
+		testBlockBytes[sTxLoc.TxStart:sTxLoc.TxStart+sTxLoc.TxLen]...) // Tx
+	return msg, encoded
+}
+
+// TestBlockTxn tests the MsgBlockTxn API against the latest protocol version.
+func TestBlockTxn(t *testing.T) {
+	pver := ProtocolVersion
+
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgBlockTxn(&hash)
+	if msg.BlockHash != hash {
+		t.Errorf("NewMsgBlockTxn: wrong block hash - got %v, want %v",
+			msg.BlockHash, hash)
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "blocktxn"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgBlockTxn: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Block hash + max block payload.
+	wantPayload := uint32(1310752)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestBlockTxnWire tests the MsgBlockTxn wire encode and decode for various
+// protocol versions.
+func TestBlockTxnWire(t *testing.T) {
+	hash := chainhash.Hash{0x01}
+	noTxns := NewMsgBlockTxn(&hash)
+	noTxnsEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Block hash
+		0x00, // Num txns
+		0x00, // Num stake txns
+	}
+
+	withTxns, withTxnsEncoded := testBlockTxn()
+
+	tests := []struct {
+		in   *MsgBlockTxn // Message to encode
+		out  *MsgBlockTxn // Expected decoded message
+		buf  []byte       // Wire encoding
+		pver uint32       // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no transactions.
+		{noTxns, noTxns, noTxnsEncoded, ProtocolVersion},
+
+		// Latest protocol version with transactions.
+		{withTxns, withTxns, withTxnsEncoded, ProtocolVersion},
+
+		// Protocol version CompactBlockVersion.
+		{withTxns, withTxns, withTxnsEncoded, CompactBlockVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgBlockTxn
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestBlockTxnWireErrors performs negative tests against wire encode and
+// decode of MsgBlockTxn to confirm error paths work correctly.
+func TestBlockTxnWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoCmpctBlock := CompactBlockVersion - 1
+	baseMsg, baseMsgEncoded := testBlockTxn()
+	txLen := testBlockTxLocs[0].TxLen
+
+	// Message with more transactions than could possibly fit into a block.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	tooManyTxns := NewMsgBlockTxn(&baseMsg.BlockHash)
+	tooManyTxns.STransactions = make([]*MsgTx, maxTxPerTree+1)
+	tooManyTxnsEncoded := append([]byte{}, baseMsgEncoded[:32]...)
+	tooManyTxnsEncoded = append(tooManyTxnsEncoded,
+		0x00,             // Num txns
+		0xfd, 0xac, 0xaa, // Num stake txns
+	)
+
+	tests := []struct {
+		in       *MsgBlockTxn // Value to encode
+		buf      []byte       // Wire encoding
+		pver     uint32       // Protocol version for wire encoding
+		max      int          // Max size of fixed buffer to induce errors
+		writeErr error        // Expected write error
+		readErr  error        // Expected read error
+	}{
+		// Force error in block hash.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in num txns.
+		{baseMsg, baseMsgEncoded, pver, 32, io.ErrShortWrite, io.EOF},
+		// Force error in txns.
+		{baseMsg, baseMsgEncoded, pver, 33, io.ErrShortWrite, io.EOF},
+		// Force error in num stake txns.
+		{baseMsg, baseMsgEncoded, pver, 33 + txLen, io.ErrShortWrite,
+			io.EOF},
+		// Force error in stake txns.
+		{baseMsg, baseMsgEncoded, pver, 34 + txLen, io.ErrShortWrite,
+			io.EOF},
+		// Force error with greater than max transactions.
+		{tooManyTxns, tooManyTxnsEncoded, pver, 36, ErrTooManyTxs,
+			ErrTooManyTxs},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoCmpctBlock, len(baseMsgEncoded),
+			ErrMsgInvalidForPVer, ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgBlockTxn
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
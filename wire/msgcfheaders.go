@@ -166,6 +166,10 @@ func (msg *MsgCFHeaders) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver. This is part of the Message interface implementation.
 func (msg *MsgCFHeaders) MaxPayloadLength(pver uint32) uint32 {
+	if pver < NodeCFVersion {
+		return 0
+	}
+
 	// Hash size + filter type + num headers (varInt) 3 bytes +
 	// (header size * max headers).
 	return chainhash.HashSize + 1 + uint32(VarIntSerializeSize(MaxCFHeadersPerMsg)) +
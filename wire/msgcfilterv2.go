@@ -144,6 +144,10 @@ func (msg *MsgCFilterV2) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgCFilterV2) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CFilterV2Version {
+		return 0
+	}
+
 	// Block hash + max filter data (including varint) +
 	// proof index + max num proof hashes (including varint).
 	return chainhash.HashSize +
@@ -122,6 +122,10 @@ func (msg *MsgCFTypes) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver. This is part of the Message interface implementation.
 func (msg *MsgCFTypes) MaxPayloadLength(pver uint32) uint32 {
+	if pver < NodeCFVersion {
+		return 0
+	}
+
 	// 3 bytes for filter count, 1 byte up to 256 bytes filter types.
 	return uint32(VarIntSerializeSize(MaxFilterTypesPerMsg)) +
 		MaxFilterTypesPerMsg
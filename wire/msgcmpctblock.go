@@ -0,0 +1,256 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// CmpctBlockShortIDSize is the number of bytes used to encode each
+	// transaction short id in a compact block.
+	CmpctBlockShortIDSize = 6
+
+	// MaxCmpctBlockShortID is the maximum value of a transaction short id in
+	// a compact block.
+	MaxCmpctBlockShortID = 1<<(CmpctBlockShortIDSize*8) - 1
+)
+
+// PrefilledTx houses a transaction that is included in full in a compact
+// block along with its index in the transaction tree of the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a cmpctblock
+// message.  It is used to relay a block to peers that likely already have most
+// of its transactions in their memory pool.
+//
+// The transactions of each tree of the block are either identified by a short
+// id, which is derived from the transaction hash and keyed by the block header
+// and nonce, or prefilled in full along with their index in the tree.  The
+// short ids are listed in the order of the transactions in the tree with the
+// prefilled transactions omitted.
+//
+// Peers are expected to reconstruct the block from the transactions they
+// already have and request any missing transactions via a getblocktxn message
+// (MsgGetBlockTxn).
+//
+// This message was not added until protocol versions starting with
+// CompactBlockVersion.
+type MsgCmpctBlock struct {
+	Header         BlockHeader
+	Nonce          uint64
+	ShortIDs       []uint64
+	PrefilledTxns  []PrefilledTx
+	SShortIDs      []uint64
+	PrefilledSTxns []PrefilledTx
+}
+
+// readCmpctTxTree reads the short ids and prefilled transactions of a
+// transaction tree of a compact block from r.
+func readCmpctTxTree(op string, r io.Reader, pver uint32) ([]uint64, []PrefilledTx, error) {
+	// Read num short ids and limit to max.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, maxTxPerTree)
+		return nil, nil, messageError(op, ErrTooManyShortIDs, msg)
+	}
+
+	shortIDs := make([]uint64, count)
+	var buf [8]byte
+	for i := uint64(0); i < count; i++ {
+		_, err := io.ReadFull(r, buf[:CmpctBlockShortIDSize])
+		if err != nil {
+			return nil, nil, err
+		}
+		shortIDs[i] = binary.LittleEndian.Uint64(buf[:])
+	}
+
+	// Read num prefilled transactions and limit the total number of
+	// transactions in the tree to max.
+	prefilledCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count+prefilledCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count+prefilledCount, maxTxPerTree)
+		return nil, nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	prefilled := make([]PrefilledTx, prefilledCount)
+	for i := uint64(0); i < prefilledCount; i++ {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return nil, nil, err
+		}
+		if index >= maxTxPerTree {
+			msg := fmt.Sprintf("prefilled transaction index %d exceeds the "+
+				"max number of transactions per tree %d", index,
+				maxTxPerTree)
+			return nil, nil, messageError(op, ErrInvalidTxIndex, msg)
+		}
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, nil, err
+		}
+		prefilled[i] = PrefilledTx{Index: uint32(index), Tx: &tx}
+	}
+
+	return shortIDs, prefilled, nil
+}
+
+// writeCmpctTxTree writes the short ids and prefilled transactions of a
+// transaction tree of a compact block to w.
+func writeCmpctTxTree(op string, w io.Writer, pver uint32, shortIDs []uint64, prefilled []PrefilledTx) error {
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(shortIDs))
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyShortIDs, msg)
+	}
+	prefilledCount := uint64(len(prefilled))
+	if count+prefilledCount > maxTxPerTree {
+		msg := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count+prefilledCount, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := WriteVarInt(w, pver, count)
+	if err != nil {
+		return err
+	}
+	var buf [8]byte
+	for _, shortID := range shortIDs {
+		if shortID > MaxCmpctBlockShortID {
+			msg := fmt.Sprintf("short id %x exceeds the max allowed value "+
+				"%x", shortID, uint64(MaxCmpctBlockShortID))
+			return messageError(op, ErrInvalidShortID, msg)
+		}
+		binary.LittleEndian.PutUint64(buf[:], shortID)
+		if _, err := w.Write(buf[:CmpctBlockShortIDSize]); err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, prefilledCount)
+	if err != nil {
+		return err
+	}
+	for _, ptx := range prefilled {
+		if uint64(ptx.Index) >= maxTxPerTree {
+			msg := fmt.Sprintf("prefilled transaction index %d exceeds the "+
+				"max number of transactions per tree %d", ptx.Index,
+				maxTxPerTree)
+			return messageError(op, ErrInvalidTxIndex, msg)
+		}
+		err := WriteVarInt(w, pver, uint64(ptx.Index))
+		if err != nil {
+			return err
+		}
+		if err := ptx.Tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = readElement(r, &msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	msg.ShortIDs, msg.PrefilledTxns, err = readCmpctTxTree(op, r, pver)
+	if err != nil {
+		return err
+	}
+	msg.SShortIDs, msg.PrefilledSTxns, err = readCmpctTxTree(op, r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgCmpctBlock.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = writeElement(w, msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = writeCmpctTxTree(op, w, pver, msg.ShortIDs, msg.PrefilledTxns)
+	if err != nil {
+		return err
+	}
+	return writeCmpctTxTree(op, w, pver, msg.SShortIDs, msg.PrefilledSTxns)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// A compact block with every transaction prefilled is the size of the
+	// full block plus the nonce and the index of every transaction.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	maxIndexesLen := 2 * maxTxPerTree * uint64(VarIntSerializeSize(maxTxPerTree))
+	return MaxBlockPayload + 8 + uint32(maxIndexesLen)
+}
+
+// NewMsgCmpctBlock returns a new cmpctblock message that conforms to the
+// Message interface using the passed parameters and defaults for the remaining
+// fields.  See MsgCmpctBlock for details.
+func NewMsgCmpctBlock(header *BlockHeader, nonce uint64) *MsgCmpctBlock {
+	return &MsgCmpctBlock{
+		Header:         *header,
+		Nonce:          nonce,
+		ShortIDs:       make([]uint64, 0),
+		PrefilledTxns:  make([]PrefilledTx, 0),
+		SShortIDs:      make([]uint64, 0),
+		PrefilledSTxns: make([]PrefilledTx, 0),
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// testCmpctBlock returns a compact block for the test block along with its
+// wire encoding.  The compact block prefills the coinbase of the test block and
+// includes a couple of short ids.
+func testCmpctBlock() (*MsgCmpctBlock, []byte) {
+	msg := NewMsgCmpctBlock(&testBlock.Header, 0x0102030405060708)
+	msg.ShortIDs = []uint64{0x010203040506, MaxCmpctBlockShortID}
+	msg.PrefilledTxns = []PrefilledTx{{Index: 0, Tx: testBlock.Transactions[0]}}
+
+	txLoc := testBlockTxLocs[0]
+	var encoded []byte
+	encoded = append(encoded, testBlockBytes[:blockHeaderLen]...) // Header
+	encoded = append(encoded,
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Nonce
+		0x02,                               // Num short ids
+		0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Short id
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // Short id
+		0x01, // Num prefilled txns
+		0x00, // Prefilled tx index
+	)
+	encoded = append(encoded,
+		testBlockBytes[txLoc.TxStart:txLoc.TxStart+txLoc.TxLen]...) // Tx
+	encoded = append(encoded,
+		0x00, // Num stake short ids
+		0x00, // Num prefilled stake txns
+	)
+	return msg, encoded
+}
+
+// TestCmpctBlock tests the MsgCmpctBlock API against the latest protocol
+// version.
+func TestCmpctBlock(t *testing.T) {
+	pver := ProtocolVersion
+
+	nonce := uint64(123123)
+	msg := NewMsgCmpctBlock(&testBlock.Header, nonce)
+	if !reflect.DeepEqual(&msg.Header, &testBlock.Header) {
+		t.Errorf("NewMsgCmpctBlock: wrong header - got %v, want %v",
+			spew.Sdump(&msg.Header), spew.Sdump(&testBlock.Header))
+	}
+	if msg.Nonce != nonce {
+		t.Errorf("NewMsgCmpctBlock: wrong nonce - got %v, want %v",
+			msg.Nonce, nonce)
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "cmpctblock"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgCmpctBlock: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Max block payload + nonce + 2 * max tx per tree * 3 byte varint
+	// indexes.
+	wantPayload := uint32(1572874)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestCmpctBlockWire tests the MsgCmpctBlock wire encode and decode for
+// various protocol versions.
+func TestCmpctBlockWire(t *testing.T) {
+	noTxns := NewMsgCmpctBlock(&testBlock.Header, 0x0102030405060708)
+	var noTxnsEncoded []byte
+	noTxnsEncoded = append(noTxnsEncoded, testBlockBytes[:blockHeaderLen]...)
+	noTxnsEncoded = append(noTxnsEncoded,
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Nonce
+		0x00, // Num short ids
+		0x00, // Num prefilled txns
+		0x00, // Num stake short ids
+		0x00, // Num prefilled stake txns
+	)
+
+	withTxns, withTxnsEncoded := testCmpctBlock()
+
+	tests := []struct {
+		in   *MsgCmpctBlock // Message to encode
+		out  *MsgCmpctBlock // Expected decoded message
+		buf  []byte         // Wire encoding
+		pver uint32         // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no transactions.
+		{noTxns, noTxns, noTxnsEncoded, ProtocolVersion},
+
+		// Latest protocol version with short ids and prefilled txns.
+		{withTxns, withTxns, withTxnsEncoded, ProtocolVersion},
+
+		// Protocol version CompactBlockVersion.
+		{withTxns, withTxns, withTxnsEncoded, CompactBlockVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgCmpctBlock
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestCmpctBlockWireErrors performs negative tests against wire encode and
+// decode of MsgCmpctBlock to confirm error paths work correctly.
+func TestCmpctBlockWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoCmpctBlock := CompactBlockVersion - 1
+	baseMsg, baseMsgEncoded := testCmpctBlock()
+	txLen := testBlockTxLocs[0].TxLen
+	headerLen := blockHeaderLen
+
+	// Message with more short ids than could possibly fit into a block.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	tooManyShortIDs := NewMsgCmpctBlock(&testBlock.Header, 0)
+	tooManyShortIDs.ShortIDs = make([]uint64, maxTxPerTree+1)
+	tooManyShortIDsEncoded := append([]byte{}, baseMsgEncoded[:headerLen+8]...)
+	tooManyShortIDsEncoded = append(tooManyShortIDsEncoded,
+		0xfd, 0xac, 0xaa) // Num short ids
+
+	// Message with more short ids and prefilled txns combined than could
+	// possibly fit into a block.
+	tooManyTxns := NewMsgCmpctBlock(&testBlock.Header, 0)
+	tooManyTxns.ShortIDs = []uint64{0}
+	tooManyTxns.PrefilledTxns = make([]PrefilledTx, maxTxPerTree)
+	tooManyTxnsEncoded := append([]byte{}, baseMsgEncoded[:headerLen+8]...)
+	tooManyTxnsEncoded = append(tooManyTxnsEncoded,
+		0x01,                               // Num short ids
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Short id
+		0xfd, 0xab, 0xaa, // Num prefilled txns
+	)
+
+	// Message with a short id that exceeds the max allowed value.
+	invalidShortID := NewMsgCmpctBlock(&testBlock.Header, 0)
+	invalidShortID.ShortIDs = []uint64{MaxCmpctBlockShortID + 1}
+
+	// Message with a prefilled tx index that exceeds the max number of
+	// transactions per tree.
+	invalidIndex := NewMsgCmpctBlock(&testBlock.Header, 0)
+	invalidIndex.PrefilledTxns = []PrefilledTx{{
+		Index: uint32(maxTxPerTree),
+		Tx:    testBlock.Transactions[0],
+	}}
+	invalidIndexEncoded := append([]byte{}, baseMsgEncoded[:headerLen+8]...)
+	invalidIndexEncoded = append(invalidIndexEncoded,
+		0x00,             // Num short ids
+		0x01,             // Num prefilled txns
+		0xfd, 0xab, 0xaa, // Prefilled tx index
+	)
+
+	tests := []struct {
+		in       *MsgCmpctBlock // Value to encode
+		buf      []byte         // Wire encoding
+		pver     uint32         // Protocol version for wire encoding
+		max      int            // Max size of fixed buffer to induce errors
+		writeErr error          // Expected write error
+		readErr  error          // Expected read error
+	}{
+		// Force error in header.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in nonce.
+		{baseMsg, baseMsgEncoded, pver, headerLen, io.ErrShortWrite, io.EOF},
+		// Force error in num short ids.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 8, io.ErrShortWrite,
+			io.EOF},
+		// Force error in short ids.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 9, io.ErrShortWrite,
+			io.EOF},
+		// Force error in num prefilled txns.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 21, io.ErrShortWrite,
+			io.EOF},
+		// Force error in prefilled tx index.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 22, io.ErrShortWrite,
+			io.EOF},
+		// Force error in prefilled tx.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 23, io.ErrShortWrite,
+			io.EOF},
+		// Force error in num stake short ids.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 23 + txLen,
+			io.ErrShortWrite, io.EOF},
+		// Force error in num prefilled stake txns.
+		{baseMsg, baseMsgEncoded, pver, headerLen + 24 + txLen,
+			io.ErrShortWrite, io.EOF},
+		// Force error with greater than max short ids.
+		{tooManyShortIDs, tooManyShortIDsEncoded, pver, headerLen + 11,
+			ErrTooManyShortIDs, ErrTooManyShortIDs},
+		// Force error with greater than max transactions.
+		{tooManyTxns, tooManyTxnsEncoded, pver, headerLen + 18,
+			ErrTooManyTxs, ErrTooManyTxs},
+		// Force error with short id that exceeds the max allowed value.
+		{invalidShortID, baseMsgEncoded, pver, headerLen + 9,
+			ErrInvalidShortID, io.EOF},
+		// Force error with prefilled tx index greater than max.
+		{invalidIndex, invalidIndexEncoded, pver, len(invalidIndexEncoded),
+			ErrInvalidTxIndex, ErrInvalidTxIndex},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoCmpctBlock, len(baseMsgEncoded),
+			ErrMsgInvalidForPVer, ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgCmpctBlock
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
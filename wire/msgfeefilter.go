@@ -55,6 +55,10 @@ func (msg *MsgFeeFilter) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgFeeFilter) MaxPayloadLength(pver uint32) uint32 {
+	if pver < FeeFilterVersion {
+		return 0
+	}
+
 	// 8 bytes min fee.
 	return 8
 }
@@ -0,0 +1,165 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// readTxIndexes reads a list of transaction indexes of a transaction tree from
+// r.
+func readTxIndexes(op string, r io.Reader, pver uint32) ([]uint32, error) {
+	// Read num indexes and limit to max.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return nil, messageError(op, ErrTooManyTxs, msg)
+	}
+
+	indexes := make([]uint32, count)
+	for i := uint64(0); i < count; i++ {
+		index, err := ReadVarInt(r, pver)
+		if err != nil {
+			return nil, err
+		}
+		if index >= maxTxPerTree {
+			msg := fmt.Sprintf("transaction index %d exceeds the max "+
+				"number of transactions per tree %d", index, maxTxPerTree)
+			return nil, messageError(op, ErrInvalidTxIndex, msg)
+		}
+		indexes[i] = uint32(index)
+	}
+	return indexes, nil
+}
+
+// writeTxIndexes writes a list of transaction indexes of a transaction tree to
+// w.
+func writeTxIndexes(op string, w io.Writer, pver uint32, indexes []uint32) error {
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(indexes))
+	if count > maxTxPerTree {
+		msg := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, ErrTooManyTxs, msg)
+	}
+
+	err := WriteVarInt(w, pver, count)
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if uint64(index) >= maxTxPerTree {
+			msg := fmt.Sprintf("transaction index %d exceeds the max "+
+				"number of transactions per tree %d", index, maxTxPerTree)
+			return messageError(op, ErrInvalidTxIndex, msg)
+		}
+		err := WriteVarInt(w, pver, uint64(index))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MsgGetBlockTxn implements the Message interface and represents a getblocktxn
+// message.  It is used to request the transactions of a block that was
+// received as a compact block (MsgCmpctBlock) which could not be found in the
+// memory pool.
+//
+// The requested transactions are identified by their indexes, in ascending
+// order, in the regular and stake transaction trees of the block,
+// respectively.  The transactions are expected to be sent in response via a
+// blocktxn message (MsgBlockTxn).
+//
+// This message was not added until protocol versions starting with
+// CompactBlockVersion.
+type MsgGetBlockTxn struct {
+	BlockHash  chainhash.Hash
+	TxIndexes  []uint32
+	STxIndexes []uint32
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgGetBlockTxn.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	msg.TxIndexes, err = readTxIndexes(op, r, pver)
+	if err != nil {
+		return err
+	}
+	msg.STxIndexes, err = readTxIndexes(op, r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgGetBlockTxn.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = writeTxIndexes(op, w, pver, msg.TxIndexes)
+	if err != nil {
+		return err
+	}
+	return writeTxIndexes(op, w, pver, msg.STxIndexes)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgGetBlockTxn) Command() string {
+	return CmdGetBlockTxn
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// Block hash + num indexes (varInt) + max indexes (varInt) for each of
+	// the regular and stake trees.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	maxIndexesLen := uint64(MaxVarIntPayload) +
+		maxTxPerTree*uint64(VarIntSerializeSize(maxTxPerTree))
+	return chainhash.HashSize + 2*uint32(maxIndexesLen)
+}
+
+// NewMsgGetBlockTxn returns a new getblocktxn message that conforms to the
+// Message interface using the passed parameters and defaults for the remaining
+// fields.  See MsgGetBlockTxn for details.
+func NewMsgGetBlockTxn(blockHash *chainhash.Hash) *MsgGetBlockTxn {
+	return &MsgGetBlockTxn{
+		BlockHash:  *blockHash,
+		TxIndexes:  make([]uint32, 0),
+		STxIndexes: make([]uint32, 0),
+	}
+}
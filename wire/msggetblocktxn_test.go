@@ -0,0 +1,215 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestGetBlockTxn tests the MsgGetBlockTxn API against the latest protocol
+// version.
+func TestGetBlockTxn(t *testing.T) {
+	pver := ProtocolVersion
+
+	hash := chainhash.Hash{0x01}
+	msg := NewMsgGetBlockTxn(&hash)
+	if msg.BlockHash != hash {
+		t.Errorf("NewMsgGetBlockTxn: wrong block hash - got %v, want %v",
+			msg.BlockHash, hash)
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "getblocktxn"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgGetBlockTxn: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Block hash + 2 * (num indexes + max tx per tree * 3 byte varint
+	// indexes).
+	wantPayload := uint32(262196)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestGetBlockTxnWire tests the MsgGetBlockTxn wire encode and decode for
+// various protocol versions.
+func TestGetBlockTxnWire(t *testing.T) {
+	hash := chainhash.Hash{0x01}
+
+	noIndexes := NewMsgGetBlockTxn(&hash)
+	noIndexesEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Block hash
+		0x00, // Num tx indexes
+		0x00, // Num stake tx indexes
+	}
+
+	withIndexes := NewMsgGetBlockTxn(&hash)
+	withIndexes.TxIndexes = []uint32{1, 300}
+	withIndexes.STxIndexes = []uint32{5}
+	withIndexesEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Block hash
+		0x02,             // Num tx indexes
+		0x01,             // Tx index
+		0xfd, 0x2c, 0x01, // Tx index
+		0x01, // Num stake tx indexes
+		0x05, // Stake tx index
+	}
+
+	tests := []struct {
+		in   *MsgGetBlockTxn // Message to encode
+		out  *MsgGetBlockTxn // Expected decoded message
+		buf  []byte          // Wire encoding
+		pver uint32          // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no indexes.
+		{noIndexes, noIndexes, noIndexesEncoded, ProtocolVersion},
+
+		// Latest protocol version with indexes.
+		{withIndexes, withIndexes, withIndexesEncoded, ProtocolVersion},
+
+		// Protocol version CompactBlockVersion.
+		{withIndexes, withIndexes, withIndexesEncoded, CompactBlockVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgGetBlockTxn
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestGetBlockTxnWireErrors performs negative tests against wire encode and
+// decode of MsgGetBlockTxn to confirm error paths work correctly.
+func TestGetBlockTxnWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoCmpctBlock := CompactBlockVersion - 1
+	hash := chainhash.Hash{0x01}
+
+	baseMsg := NewMsgGetBlockTxn(&hash)
+	baseMsg.TxIndexes = []uint32{1}
+	baseMsg.STxIndexes = []uint32{5}
+	baseMsgEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // Block hash
+		0x01, // Num tx indexes
+		0x01, // Tx index
+		0x01, // Num stake tx indexes
+		0x05, // Stake tx index
+	}
+
+	// Message with more indexes than could possibly fit into a block.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	tooManyIndexes := NewMsgGetBlockTxn(&hash)
+	tooManyIndexes.TxIndexes = make([]uint32, maxTxPerTree+1)
+	tooManyIndexesEncoded := append([]byte{}, baseMsgEncoded[:32]...)
+	tooManyIndexesEncoded = append(tooManyIndexesEncoded,
+		0xfd, 0xac, 0xaa) // Num tx indexes
+
+	// Message with an index that exceeds the max number of transactions per
+	// tree.
+	invalidIndex := NewMsgGetBlockTxn(&hash)
+	invalidIndex.STxIndexes = []uint32{uint32(maxTxPerTree)}
+	invalidIndexEncoded := append([]byte{}, baseMsgEncoded[:32]...)
+	invalidIndexEncoded = append(invalidIndexEncoded,
+		0x00,             // Num tx indexes
+		0x01,             // Num stake tx indexes
+		0xfd, 0xab, 0xaa, // Stake tx index
+	)
+
+	tests := []struct {
+		in       *MsgGetBlockTxn // Value to encode
+		buf      []byte          // Wire encoding
+		pver     uint32          // Protocol version for wire encoding
+		max      int             // Max size of fixed buffer to induce errors
+		writeErr error           // Expected write error
+		readErr  error           // Expected read error
+	}{
+		// Force error in block hash.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in num tx indexes.
+		{baseMsg, baseMsgEncoded, pver, 32, io.ErrShortWrite, io.EOF},
+		// Force error in tx indexes.
+		{baseMsg, baseMsgEncoded, pver, 33, io.ErrShortWrite, io.EOF},
+		// Force error in num stake tx indexes.
+		{baseMsg, baseMsgEncoded, pver, 34, io.ErrShortWrite, io.EOF},
+		// Force error in stake tx indexes.
+		{baseMsg, baseMsgEncoded, pver, 35, io.ErrShortWrite, io.EOF},
+		// Force error with greater than max indexes.
+		{tooManyIndexes, tooManyIndexesEncoded, pver, 35, ErrTooManyTxs,
+			ErrTooManyTxs},
+		// Force error with index greater than max.
+		{invalidIndex, invalidIndexEncoded, pver, 37, ErrInvalidTxIndex,
+			ErrInvalidTxIndex},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoCmpctBlock, 36, ErrMsgInvalidForPVer,
+			ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgGetBlockTxn
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
@@ -124,6 +124,10 @@ func (msg *MsgGetCFHeaders) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgGetCFHeaders) MaxPayloadLength(pver uint32) uint32 {
+	if pver < NodeCFVersion {
+		return 0
+	}
+
 	// Num block locator hashes (varInt) 3 bytes + max allowed
 	// block locators + hash stop + filter type 1 byte.
 	return uint32(VarIntSerializeSize(MaxBlockLocatorsPerMsg)) +
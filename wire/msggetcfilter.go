@@ -63,6 +63,10 @@ func (msg *MsgGetCFilter) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgGetCFilter) MaxPayloadLength(pver uint32) uint32 {
+	if pver < NodeCFVersion {
+		return 0
+	}
+
 	// Block hash + filter type.
 	return chainhash.HashSize + 1
 }
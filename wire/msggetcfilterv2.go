@@ -56,6 +56,10 @@ func (msg *MsgGetCFilterV2) Command() string {
 // MaxPayloadLength returns the maximum length the payload can be for the
 // receiver.  This is part of the Message interface implementation.
 func (msg *MsgGetCFilterV2) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CFilterV2Version {
+		return 0
+	}
+
 	// Block hash.
 	return chainhash.HashSize
 }
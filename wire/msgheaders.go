@@ -52,6 +52,14 @@ func (msg *MsgHeaders) BtcDecode(r io.Reader, pver uint32) error {
 		return messageError(op, ErrTooManyHeaders, msg)
 	}
 
+	// Limit to the number of block headers that could possibly fit into the
+	// remaining payload.
+	if !countFits(r, count, MaxBlockHeaderPayload+1) {
+		msg := fmt.Sprintf("number of block headers exceeds the "+
+			"remaining payload [count %v]", count)
+		return messageError(op, ErrTooManyHeaders, msg)
+	}
+
 	// Create a contiguous slice of headers to deserialize into in order to
 	// reduce the number of allocations.
 	headers := make([]BlockHeader, count)
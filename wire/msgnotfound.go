@@ -49,6 +49,14 @@ func (msg *MsgNotFound) BtcDecode(r io.Reader, pver uint32) error {
 		return messageError(op, ErrTooManyVectors, msg)
 	}
 
+	// Limit to the number of inventory vectors that could possibly fit into the
+	// remaining payload.
+	if !countFits(r, count, maxInvVectPayload) {
+		msg := fmt.Sprintf("number of inventory vectors exceeds the "+
+			"remaining payload [count %v]", count)
+		return messageError(op, ErrTooManyVectors, msg)
+	}
+
 	// Create a contiguous slice of inventory vectors to deserialize into in
 	// order to reduce the number of allocations.
 	invList := make([]InvVect, count)
@@ -0,0 +1,149 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxReconcilDiffShortIDs is the maximum number of transaction short ids
+// allowed per reconcildiff message.
+const MaxReconcilDiffShortIDs = 16384
+
+// MsgReconcilDiff implements the Message interface and represents a
+// reconcildiff message.  It is used to conclude a transaction reconciliation
+// round.
+//
+// When Success is true, ShortIDs lists the short ids of the transactions from
+// the previously delivered sketch that the sending peer does not have and
+// wants the receiving peer to announce.  When Success is false, the sending
+// peer was unable to decode the set difference and both peers fall back to
+// announcing the reconciled transactions via inventory messages.
+//
+// It is delivered in response to a sketch message (MsgSketch).
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgReconcilDiff struct {
+	Success  bool
+	ShortIDs []uint32
+}
+
+// AddShortID adds a new transaction short id to the message.  Up to
+// MaxReconcilDiffShortIDs may be added before this function errors out.
+func (msg *MsgReconcilDiff) AddShortID(shortID uint32) error {
+	const op = "MsgReconcilDiff.AddShortID"
+	if len(msg.ShortIDs)+1 > MaxReconcilDiffShortIDs {
+		msg := fmt.Sprintf("too many short ids for message [max %v]",
+			MaxReconcilDiffShortIDs)
+		return messageError(op, ErrTooManyShortIDs, msg)
+	}
+
+	msg.ShortIDs = append(msg.ShortIDs, shortID)
+	return nil
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgReconcilDiff.BtcDecode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	err := readElement(r, &msg.Success)
+	if err != nil {
+		return err
+	}
+
+	// Read num short ids and limit to max.
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxReconcilDiffShortIDs {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, MaxReconcilDiffShortIDs)
+		return messageError(op, ErrTooManyShortIDs, msg)
+	}
+
+	msg.ShortIDs = make([]uint32, count)
+	for i := uint64(0); i < count; i++ {
+		err := readElement(r, &msg.ShortIDs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgReconcilDiff.BtcEncode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	count := len(msg.ShortIDs)
+	if count > MaxReconcilDiffShortIDs {
+		msg := fmt.Sprintf("too many short ids for message "+
+			"[count %v, max %v]", count, MaxReconcilDiffShortIDs)
+		return messageError(op, ErrTooManyShortIDs, msg)
+	}
+
+	err := writeElement(w, msg.Success)
+	if err != nil {
+		return err
+	}
+
+	err = WriteVarInt(w, pver, uint64(count))
+	if err != nil {
+		return err
+	}
+
+	for _, shortID := range msg.ShortIDs {
+		err := writeElement(w, shortID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReconcilDiff) Command() string {
+	return CmdReconcilDiff
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) MaxPayloadLength(pver uint32) uint32 {
+	if pver < TxReconciliationVersion {
+		return 0
+	}
+
+	// 1 byte success flag + num short ids (varInt) + max allowed short ids.
+	return 1 + uint32(VarIntSerializeSize(MaxReconcilDiffShortIDs)) +
+		MaxReconcilDiffShortIDs*4
+}
+
+// NewMsgReconcilDiff returns a new reconcildiff message that conforms to the
+// Message interface using the passed parameters.  See MsgReconcilDiff for
+// details.
+func NewMsgReconcilDiff(success bool) *MsgReconcilDiff {
+	return &MsgReconcilDiff{
+		Success:  success,
+		ShortIDs: make([]uint32, 0),
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestReconcilDiff tests the MsgReconcilDiff API against the latest protocol
+// version.
+func TestReconcilDiff(t *testing.T) {
+	pver := ProtocolVersion
+
+	msg := NewMsgReconcilDiff(true)
+	if !msg.Success || len(msg.ShortIDs) != 0 {
+		t.Errorf("NewMsgReconcilDiff: wrong fields - got %v", spew.Sdump(msg))
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "reconcildiff"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgReconcilDiff: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Success flag + num short ids (varInt) + max allowed short ids.
+	wantPayload := uint32(65540)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+
+	// Ensure short ids are added properly.
+	if err := msg.AddShortID(0x01020304); err != nil {
+		t.Fatalf("AddShortID: unexpected error: %v", err)
+	}
+	if len(msg.ShortIDs) != 1 || msg.ShortIDs[0] != 0x01020304 {
+		t.Fatalf("AddShortID: wrong short ids - got %v", msg.ShortIDs)
+	}
+
+	// Ensure adding more than the max allowed number of short ids errors.
+	for i := 1; i < MaxReconcilDiffShortIDs; i++ {
+		if err := msg.AddShortID(uint32(i)); err != nil {
+			t.Fatalf("AddShortID: unexpected error: %v", err)
+		}
+	}
+	if err := msg.AddShortID(0); !errors.Is(err, ErrTooManyShortIDs) {
+		t.Fatalf("AddShortID: wrong error - got %v, want %v", err,
+			ErrTooManyShortIDs)
+	}
+}
+
+// TestReconcilDiffWire tests the MsgReconcilDiff wire encode and decode for
+// various protocol versions.
+func TestReconcilDiffWire(t *testing.T) {
+	noShortIDs := NewMsgReconcilDiff(false)
+	noShortIDsEncoded := []byte{
+		0x00, // Success
+		0x00, // Varint for number of short ids
+	}
+
+	multiShortIDs := NewMsgReconcilDiff(true)
+	multiShortIDs.AddShortID(0x01020304)
+	multiShortIDs.AddShortID(0x05060708)
+	multiShortIDsEncoded := []byte{
+		0x01,                   // Success
+		0x02,                   // Varint for number of short ids
+		0x04, 0x03, 0x02, 0x01, // Short id 1
+		0x08, 0x07, 0x06, 0x05, // Short id 2
+	}
+
+	tests := []struct {
+		in   *MsgReconcilDiff // Message to encode
+		out  *MsgReconcilDiff // Expected decoded message
+		buf  []byte           // Wire encoding
+		pver uint32           // Protocol version for wire encoding
+	}{
+		// Latest protocol version with no short ids.
+		{noShortIDs, noShortIDs, noShortIDsEncoded, ProtocolVersion},
+
+		// Latest protocol version with multiple short ids.
+		{multiShortIDs, multiShortIDs, multiShortIDsEncoded, ProtocolVersion},
+
+		// Protocol version TxReconciliationVersion with multiple short ids.
+		{multiShortIDs, multiShortIDs, multiShortIDsEncoded,
+			TxReconciliationVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgReconcilDiff
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestReconcilDiffWireErrors performs negative tests against wire encode and
+// decode of MsgReconcilDiff to confirm error paths work correctly.
+func TestReconcilDiffWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoTxRecon := TxReconciliationVersion - 1
+
+	baseMsg := NewMsgReconcilDiff(true)
+	baseMsg.AddShortID(0x01020304)
+	baseMsgEncoded := []byte{
+		0x01,                   // Success
+		0x01,                   // Varint for number of short ids
+		0x04, 0x03, 0x02, 0x01, // Short id
+	}
+
+	// Message that forces an error by having more than the max allowed
+	// short ids.
+	maxShortIDs := NewMsgReconcilDiff(true)
+	maxShortIDs.ShortIDs = make([]uint32, MaxReconcilDiffShortIDs+1)
+	maxShortIDsEncoded := []byte{
+		0x01,             // Success
+		0xfd, 0x01, 0x40, // Varint for number of short ids (16385)
+	}
+
+	tests := []struct {
+		in       *MsgReconcilDiff // Value to encode
+		buf      []byte           // Wire encoding
+		pver     uint32           // Protocol version for wire encoding
+		max      int              // Max size of fixed buffer to induce errors
+		writeErr error            // Expected write error
+		readErr  error            // Expected read error
+	}{
+		// Force error in success flag.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in number of short ids varint.
+		{baseMsg, baseMsgEncoded, pver, 1, io.ErrShortWrite, io.EOF},
+		// Force error in first short id.
+		{baseMsg, baseMsgEncoded, pver, 2, io.ErrShortWrite, io.EOF},
+		// Force error with greater than allowed number of short ids.
+		{maxShortIDs, maxShortIDsEncoded, pver, 4, ErrTooManyShortIDs,
+			ErrTooManyShortIDs},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoTxRecon, 6, ErrMsgInvalidForPVer,
+			ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgReconcilDiff
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
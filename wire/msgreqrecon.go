@@ -0,0 +1,78 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgReqRecon implements the Message interface and represents a reqrecon
+// message.  It is used to request the receiving peer sends a sketch (MsgSketch)
+// of the set of transactions it intends to announce to the sending peer.
+//
+// SetSize is the number of transactions the sending peer intends to announce
+// to the receiving peer and Q is a coefficient, scaled by 2^16, the receiving
+// peer uses along with both set sizes to estimate the size of the set
+// difference.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgReqRecon struct {
+	SetSize uint32
+	Q       uint16
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgReqRecon.BtcDecode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return readElements(r, &msg.SetSize, &msg.Q)
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgReqRecon.BtcEncode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return writeElements(w, msg.SetSize, msg.Q)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReqRecon) Command() string {
+	return CmdReqRecon
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReqRecon) MaxPayloadLength(pver uint32) uint32 {
+	if pver < TxReconciliationVersion {
+		return 0
+	}
+
+	// 4 bytes set size + 2 bytes q.
+	return 6
+}
+
+// NewMsgReqRecon returns a new reqrecon message that conforms to the Message
+// interface using the passed parameters.  See MsgReqRecon for details.
+func NewMsgReqRecon(setSize uint32, q uint16) *MsgReqRecon {
+	return &MsgReqRecon{
+		SetSize: setSize,
+		Q:       q,
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgSendCmpct implements the Message interface and represents a sendcmpct
+// message.  It is used to signal support for compact block relay along with
+// the version of compact blocks the sending peer supports.
+//
+// When Announce is true, the sending peer requests new blocks to be announced
+// by sending them directly as compact blocks (MsgCmpctBlock) instead of
+// announcing them via headers messages (MsgHeaders).
+//
+// This message was not added until protocol versions starting with
+// CompactBlockVersion.
+type MsgSendCmpct struct {
+	Announce bool
+	Version  uint32
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgSendCmpct.BtcDecode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return readElements(r, &msg.Announce, &msg.Version)
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgSendCmpct.BtcEncode"
+	if pver < CompactBlockVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return writeElements(w, msg.Announce, msg.Version)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSendCmpct) Command() string {
+	return CmdSendCmpct
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSendCmpct) MaxPayloadLength(pver uint32) uint32 {
+	if pver < CompactBlockVersion {
+		return 0
+	}
+
+	// 1 byte announce flag + 4 bytes version.
+	return 5
+}
+
+// NewMsgSendCmpct returns a new sendcmpct message that conforms to the Message
+// interface using the passed parameters.  See MsgSendCmpct for details.
+func NewMsgSendCmpct(announce bool, version uint32) *MsgSendCmpct {
+	return &MsgSendCmpct{
+		Announce: announce,
+		Version:  version,
+	}
+}
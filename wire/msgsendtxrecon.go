@@ -0,0 +1,76 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgSendTxRecon implements the Message interface and represents a sendtxrcncl
+// message.  It is used to signal support for sketch-based transaction
+// reconciliation and to provide the salt the sending peer contributes to the
+// short transaction ids used during reconciliation.  It is only sent to peers
+// that advertise the SFNodeTxRecon service flag.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgSendTxRecon struct {
+	Version uint32
+	Salt    uint64
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSendTxRecon) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgSendTxRecon.BtcDecode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return readElements(r, &msg.Version, &msg.Salt)
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSendTxRecon) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgSendTxRecon.BtcEncode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	return writeElements(w, msg.Version, msg.Salt)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSendTxRecon) Command() string {
+	return CmdSendTxRecon
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSendTxRecon) MaxPayloadLength(pver uint32) uint32 {
+	if pver < TxReconciliationVersion {
+		return 0
+	}
+
+	// 4 bytes version + 8 bytes salt.
+	return 12
+}
+
+// NewMsgSendTxRecon returns a new sendtxrcncl message that conforms to the
+// Message interface using the passed parameters.  See MsgSendTxRecon for
+// details.
+func NewMsgSendTxRecon(version uint32, salt uint64) *MsgSendTxRecon {
+	return &MsgSendTxRecon{
+		Version: version,
+		Salt:    salt,
+	}
+}
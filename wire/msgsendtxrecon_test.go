@@ -0,0 +1,146 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSendTxRecon tests the MsgSendTxRecon API against the latest protocol
+// version.
+func TestSendTxRecon(t *testing.T) {
+	pver := ProtocolVersion
+
+	msg := NewMsgSendTxRecon(1, 0x0102030405060708)
+	if msg.Version != 1 || msg.Salt != 0x0102030405060708 {
+		t.Errorf("NewMsgSendTxRecon: wrong fields - got %v", spew.Sdump(msg))
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "sendtxrcncl"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgSendTxRecon: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(12)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+}
+
+// TestSendTxReconWire tests the MsgSendTxRecon wire encode and decode for
+// various protocol versions.
+func TestSendTxReconWire(t *testing.T) {
+	msg := NewMsgSendTxRecon(1, 0x0102030405060708)
+	msgEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, // Version
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Salt
+	}
+
+	tests := []struct {
+		in   *MsgSendTxRecon // Message to encode
+		out  *MsgSendTxRecon // Expected decoded message
+		buf  []byte          // Wire encoding
+		pver uint32          // Protocol version for wire encoding
+	}{
+		// Latest protocol version.
+		{msg, msg, msgEncoded, ProtocolVersion},
+
+		// Protocol version TxReconciliationVersion.
+		{msg, msg, msgEncoded, TxReconciliationVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgSendTxRecon
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestSendTxReconWireErrors performs negative tests against wire encode and
+// decode of MsgSendTxRecon to confirm error paths work correctly.
+func TestSendTxReconWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoTxRecon := TxReconciliationVersion - 1
+
+	baseMsg := NewMsgSendTxRecon(1, 0x0102030405060708)
+	baseMsgEncoded := []byte{
+		0x01, 0x00, 0x00, 0x00, // Version
+		0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, // Salt
+	}
+
+	tests := []struct {
+		in       *MsgSendTxRecon // Value to encode
+		buf      []byte          // Wire encoding
+		pver     uint32          // Protocol version for wire encoding
+		max      int             // Max size of fixed buffer to induce errors
+		writeErr error           // Expected write error
+		readErr  error           // Expected read error
+	}{
+		// Force error in version.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in salt.
+		{baseMsg, baseMsgEncoded, pver, 4, io.ErrShortWrite, io.EOF},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoTxRecon, 12, ErrMsgInvalidForPVer,
+			ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgSendTxRecon
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
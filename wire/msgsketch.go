@@ -0,0 +1,86 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxSketchSize is the maximum number of bytes a serialized transaction
+// reconciliation sketch can be.
+const MaxSketchSize = 1 << 16
+
+// MsgSketch implements the Message interface and represents a sketch message.
+// It is used to deliver a serialized sketch of the short ids of the
+// transactions the sending peer intends to announce to the receiving peer.
+//
+// It is delivered in response to a reqrecon message (MsgReqRecon).
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgSketch struct {
+	Sketch []byte
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSketch) BtcDecode(r io.Reader, pver uint32) error {
+	const op = "MsgSketch.BtcDecode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	var err error
+	msg.Sketch, err = ReadVarBytes(r, pver, MaxSketchSize, "sketch")
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSketch) BtcEncode(w io.Writer, pver uint32) error {
+	const op = "MsgSketch.BtcEncode"
+	if pver < TxReconciliationVersion {
+		msg := fmt.Sprintf("%s message invalid for protocol version %d",
+			msg.Command(), pver)
+		return messageError(op, ErrMsgInvalidForPVer, msg)
+	}
+
+	size := len(msg.Sketch)
+	if size > MaxSketchSize {
+		msg := fmt.Sprintf("sketch size too large for message "+
+			"[size %v, max %v]", size, MaxSketchSize)
+		return messageError(op, ErrSketchTooLarge, msg)
+	}
+
+	return WriteVarBytes(w, pver, msg.Sketch)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSketch) Command() string {
+	return CmdSketch
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSketch) MaxPayloadLength(pver uint32) uint32 {
+	if pver < TxReconciliationVersion {
+		return 0
+	}
+
+	// Num sketch bytes (varInt) + max allowed sketch bytes.
+	return uint32(VarIntSerializeSize(MaxSketchSize)) + MaxSketchSize
+}
+
+// NewMsgSketch returns a new sketch message that conforms to the Message
+// interface using the passed parameters.  See MsgSketch for details.
+func NewMsgSketch(sketch []byte) *MsgSketch {
+	return &MsgSketch{
+		Sketch: sketch,
+	}
+}
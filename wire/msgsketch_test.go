@@ -0,0 +1,165 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSketch tests the MsgSketch API against the latest protocol version.
+func TestSketch(t *testing.T) {
+	pver := ProtocolVersion
+
+	sketch := []byte{0x01, 0x02, 0x03}
+	msg := NewMsgSketch(sketch)
+	if !bytes.Equal(msg.Sketch, sketch) {
+		t.Errorf("NewMsgSketch: wrong sketch - got %x, want %x", msg.Sketch,
+			sketch)
+	}
+
+	// Ensure the command is expected value.
+	wantCmd := "sketch"
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgSketch: wrong command - got %v want %v", cmd,
+			wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Num sketch bytes (varInt) + max allowed sketch bytes.
+	wantPayload := uint32(65541)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for protocol "+
+			"version %d - got %v, want %v", pver, maxPayload, wantPayload)
+	}
+
+	// Ensure max payload length is not more than MaxMessagePayload.
+	if maxPayload > MaxMessagePayload {
+		t.Fatalf("MaxPayloadLength: payload length (%v) for protocol "+
+			"version %d exceeds MaxMessagePayload (%v).", maxPayload, pver,
+			MaxMessagePayload)
+	}
+}
+
+// TestSketchWire tests the MsgSketch wire encode and decode for various
+// protocol versions.
+func TestSketchWire(t *testing.T) {
+	msg := NewMsgSketch([]byte{0x01, 0x02, 0x03})
+	msgEncoded := []byte{
+		0x03,             // Varint for sketch size
+		0x01, 0x02, 0x03, // Sketch
+	}
+
+	tests := []struct {
+		in   *MsgSketch // Message to encode
+		out  *MsgSketch // Expected decoded message
+		buf  []byte     // Wire encoding
+		pver uint32     // Protocol version for wire encoding
+	}{
+		// Latest protocol version.
+		{msg, msg, msgEncoded, ProtocolVersion},
+
+		// Protocol version TxReconciliationVersion.
+		{msg, msg, msgEncoded, TxReconciliationVersion},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		// Decode the message from wire format.
+		var msg MsgSketch
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestSketchWireErrors performs negative tests against wire encode and decode
+// of MsgSketch to confirm error paths work correctly.
+func TestSketchWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	pverNoTxRecon := TxReconciliationVersion - 1
+
+	baseMsg := NewMsgSketch([]byte{0x01, 0x02, 0x03})
+	baseMsgEncoded := []byte{
+		0x03,             // Varint for sketch size
+		0x01, 0x02, 0x03, // Sketch
+	}
+
+	// Message that forces an error by having a sketch that exceeds the max
+	// allowed size.
+	maxSketch := NewMsgSketch(make([]byte, MaxSketchSize+1))
+	maxSketchEncoded := []byte{
+		0xfe, 0x01, 0x00, 0x01, 0x00, // Varint for sketch size
+	}
+
+	tests := []struct {
+		in       *MsgSketch // Value to encode
+		buf      []byte     // Wire encoding
+		pver     uint32     // Protocol version for wire encoding
+		max      int        // Max size of fixed buffer to induce errors
+		writeErr error      // Expected write error
+		readErr  error      // Expected read error
+	}{
+		// Force error in sketch size varint.
+		{baseMsg, baseMsgEncoded, pver, 0, io.ErrShortWrite, io.EOF},
+		// Force error in sketch.
+		{baseMsg, baseMsgEncoded, pver, 1, io.ErrShortWrite, io.EOF},
+		// Force error with greater than allowed sketch size.
+		{maxSketch, maxSketchEncoded, pver, 5, ErrSketchTooLarge,
+			ErrVarBytesTooLong},
+		// Force error due to unsupported protocol version.
+		{baseMsg, baseMsgEncoded, pverNoTxRecon, 4, ErrMsgInvalidForPVer,
+			ErrMsgInvalidForPVer},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode to wire format.
+		w := newFixedWriter(test.max)
+		err := test.in.BtcEncode(w, test.pver)
+		if !errors.Is(err, test.writeErr) {
+			t.Errorf("BtcEncode #%d wrong error - got: %v, want: %v", i, err,
+				test.writeErr)
+			continue
+		}
+
+		// Decode from wire format.
+		var msg MsgSketch
+		r := newFixedReader(test.max, test.buf)
+		err = msg.BtcDecode(r, test.pver)
+		if !errors.Is(err, test.readErr) {
+			t.Errorf("BtcDecode #%d wrong error - got: %v, want: %v", i, err,
+				test.readErr)
+			continue
+		}
+	}
+}
@@ -237,6 +237,9 @@ func readScript(r io.Reader, pver uint32, maxAllowed uint32, fieldName string) (
 			"[count %d, max %d]", fieldName, count, maxAllowed)
 		return nil, messageError(op, ErrVarBytesTooLong, msg)
 	}
+	if err := checkRemaining(r, count); err != nil {
+		return nil, err
+	}
 
 	b := scriptPool.Borrow(count)
 	_, err = io.ReadFull(r, b)
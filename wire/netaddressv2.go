@@ -0,0 +1,192 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NetAddressType identifies the network a NetAddressV2 belongs to and
+// therefore how its address is encoded.
+type NetAddressType uint8
+
+const (
+	// UnknownAddressType is the network address type of an address that is
+	// not one of the supported types.
+	UnknownAddressType NetAddressType = iota
+
+	// IPv4Address is the network address type of an IPv4 address.  It is
+	// encoded as 4 bytes.
+	IPv4Address
+
+	// IPv6Address is the network address type of an IPv6 address.  It is
+	// encoded as 16 bytes.
+	IPv6Address
+
+	// TORv3Address is the network address type of a TORv3 onion address.  It
+	// is encoded as the 32-byte ed25519 public key of the onion service.
+	TORv3Address
+
+	// I2PAddress is the network address type of an I2P address.  It is
+	// encoded as the 32-byte SHA-256 hash of the I2P destination.
+	//
+	// This address type was not added until protocol versions starting with
+	// I2PAddrVersion.
+	I2PAddress
+)
+
+// Map of network address types back to their constant names for pretty
+// printing.
+var addrTypeStrings = map[NetAddressType]string{
+	UnknownAddressType: "UnknownAddressType",
+	IPv4Address:        "IPv4Address",
+	IPv6Address:        "IPv6Address",
+	TORv3Address:       "TORv3Address",
+	I2PAddress:         "I2PAddress",
+}
+
+// String returns the NetAddressType in human-readable form.
+func (t NetAddressType) String() string {
+	if s, ok := addrTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown NetAddressType (%d)", uint8(t))
+}
+
+// encodedAddrLen returns the length of the encoded address for the network
+// address type at the provided protocol version.  It returns 0 for types that
+// are unknown at the protocol version.
+func (t NetAddressType) encodedAddrLen(pver uint32) int {
+	switch t {
+	case IPv4Address:
+		return 4
+	case IPv6Address:
+		return 16
+	case TORv3Address:
+		return 32
+	case I2PAddress:
+		if pver >= I2PAddrVersion {
+			return 32
+		}
+	}
+	return 0
+}
+
+// maxNetAddressV2Payload is the max payload size for a NetAddressV2.
+//
+// Timestamp 4 bytes + services 8 bytes + type 1 byte + max address 32 bytes +
+// port 2 bytes.
+const maxNetAddressV2Payload = 4 + 8 + 1 + 32 + 2
+
+// minNetAddressV2Payload is the min payload size for a NetAddressV2.
+//
+// Timestamp 4 bytes + services 8 bytes + type 1 byte + min address 4 bytes +
+// port 2 bytes.
+const minNetAddressV2Payload = 4 + 8 + 1 + 4 + 2
+
+// NetAddressV2 defines information about a peer on the network including the
+// time it was last seen, the services it supports, its address, and port.
+//
+// Unlike NetAddress, the address is not required to be an IP address and its
+// encoding depends on its type which allows addresses such as TORv3 onion
+// addresses to be relayed natively.
+type NetAddressV2 struct {
+	// Last time the address was seen.  This is encoded as a uint32 on the
+	// wire and therefore is limited to 2106.
+	Timestamp time.Time
+
+	// Bitfield which identifies the services supported by the address.
+	Services ServiceFlag
+
+	// Type is the network the address belongs to.
+	Type NetAddressType
+
+	// EncodedAddr is the address encoded according to its type.
+	EncodedAddr []byte
+
+	// Port the peer is using.  This is encoded in big endian on the wire
+	// for consistency with NetAddress.
+	Port uint16
+}
+
+// HasService returns whether the specified service is supported by the address.
+func (na *NetAddressV2) HasService(service ServiceFlag) bool {
+	return na.Services&service == service
+}
+
+// NewNetAddressV2 returns a new NetAddressV2 using the provided type, encoded
+// address, port, timestamp, and supported services.  The timestamp is rounded
+// to single second precision.
+func NewNetAddressV2(addrType NetAddressType, encodedAddr []byte, port uint16,
+	timestamp time.Time, services ServiceFlag) *NetAddressV2 {
+
+	return &NetAddressV2{
+		Timestamp:   time.Unix(timestamp.Unix(), 0),
+		Services:    services,
+		Type:        addrType,
+		EncodedAddr: encodedAddr,
+		Port:        port,
+	}
+}
+
+// readNetAddressV2 reads an encoded NetAddressV2 from r.
+func readNetAddressV2(op string, r io.Reader, pver uint32, na *NetAddressV2) error {
+	err := readElements(r, (*uint32Time)(&na.Timestamp), &na.Services)
+	if err != nil {
+		return err
+	}
+
+	var addrType [1]byte
+	if _, err := io.ReadFull(r, addrType[:]); err != nil {
+		return err
+	}
+	na.Type = NetAddressType(addrType[0])
+	addrLen := na.Type.encodedAddrLen(pver)
+	if addrLen == 0 {
+		msg := fmt.Sprintf("unknown network address type %d for protocol "+
+			"version %d", addrType[0], pver)
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	na.EncodedAddr = make([]byte, addrLen)
+	if _, err := io.ReadFull(r, na.EncodedAddr); err != nil {
+		return err
+	}
+
+	// Sigh.  Decred protocol mixes little and big endian.
+	na.Port, err = binarySerializer.Uint16(r, bigEndian)
+	return err
+}
+
+// writeNetAddressV2 serializes a NetAddressV2 to w.
+func writeNetAddressV2(op string, w io.Writer, pver uint32, na *NetAddressV2) error {
+	addrLen := na.Type.encodedAddrLen(pver)
+	if addrLen == 0 {
+		msg := fmt.Sprintf("unknown network address type %d for protocol "+
+			"version %d", na.Type, pver)
+		return messageError(op, ErrUnknownNetAddrType, msg)
+	}
+	if len(na.EncodedAddr) != addrLen {
+		msg := fmt.Sprintf("%v address is %d bytes instead of the required "+
+			"%d bytes", na.Type, len(na.EncodedAddr), addrLen)
+		return messageError(op, ErrInvalidNetAddrLen, msg)
+	}
+
+	err := writeElements(w, uint32(na.Timestamp.Unix()), na.Services)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{byte(na.Type)}); err != nil {
+		return err
+	}
+	if _, err := w.Write(na.EncodedAddr); err != nil {
+		return err
+	}
+
+	// Sigh.  Decred protocol mixes little and big endian.
+	return binary.Write(w, bigEndian, na.Port)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNetAddressV2 tests the NetAddressV2 API.
+func TestNetAddressV2(t *testing.T) {
+	timestamp := time.Unix(0x495fab29, 500)
+	na := NewNetAddressV2(TORv3Address, testTORv3Key, 9108, timestamp,
+		SFNodeNetwork)
+
+	// Ensure the timestamp is rounded to single second precision.
+	if want := time.Unix(0x495fab29, 0); !na.Timestamp.Equal(want) {
+		t.Errorf("NewNetAddressV2: wrong timestamp - got %v, want %v",
+			na.Timestamp, want)
+	}
+
+	// Ensure the services are reported as expected.
+	if !na.HasService(SFNodeNetwork) {
+		t.Errorf("HasService: SFNodeNetwork service not set")
+	}
+	if na.HasService(SFNodeCF) {
+		t.Errorf("HasService: SFNodeCF service is set")
+	}
+}
+
+// TestNetAddressTypeStringer tests the stringized output for the
+// NetAddressType type.
+func TestNetAddressTypeStringer(t *testing.T) {
+	tests := []struct {
+		in   NetAddressType
+		want string
+	}{
+		{UnknownAddressType, "UnknownAddressType"},
+		{IPv4Address, "IPv4Address"},
+		{IPv6Address, "IPv6Address"},
+		{TORv3Address, "TORv3Address"},
+		{I2PAddress, "I2PAddress"},
+		{0xff, "Unknown NetAddressType (255)"},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		result := test.in.String()
+		if result != test.want {
+			t.Errorf("String #%d\n got: %s want: %s", i, result,
+				test.want)
+			continue
+		}
+	}
+}
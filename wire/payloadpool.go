@@ -0,0 +1,78 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"math/bits"
+	"sync"
+)
+
+const (
+	// minPayloadClassBits is the base-2 logarithm of the size of the
+	// smallest buffer class in the payload pool.
+	minPayloadClassBits = 9 // 512 bytes
+
+	// maxPayloadClassBits is the base-2 logarithm of the size of the largest
+	// buffer class in the payload pool.  It must be large enough to hold the
+	// maximum message payload.
+	maxPayloadClassBits = 25 // 32MiB
+
+	// numPayloadClasses is the number of buffer classes in the payload pool.
+	numPayloadClasses = maxPayloadClassBits - minPayloadClassBits + 1
+)
+
+// payloadFreeList defines a pool of reusable byte slices used to read message
+// payloads.  The buffers are grouped into classes with power of two capacities
+// so that a buffer from a given class is never more than twice as large as the
+// payload it holds.
+//
+// Pointers to the slices are stored in the pools since slices are not
+// pointer-shaped and would otherwise be copied into a new interface value.
+type payloadFreeList [numPayloadClasses]sync.Pool
+
+// payloadClass returns the index of the smallest buffer class with a capacity
+// of at least the provided size.
+func payloadClass(size uint32) int {
+	if size <= 1<<minPayloadClassBits {
+		return 0
+	}
+	return bits.Len32(size-1) - minPayloadClassBits
+}
+
+// Borrow returns a byte slice from the free list with a length of the provided
+// size.  A new buffer is allocated if there are no buffers of the appropriate
+// class available.
+func (c *payloadFreeList) Borrow(size uint32) []byte {
+	// Fall back to a plain allocation for sizes that exceed the largest
+	// class.  This can't happen in practice since payloads are limited to
+	// the maximum message payload.
+	class := payloadClass(size)
+	if class >= numPayloadClasses {
+		return make([]byte, size)
+	}
+
+	if buf, ok := c[class].Get().(*[]byte); ok {
+		return (*buf)[:size]
+	}
+	return make([]byte, size, 1<<(class+minPayloadClassBits))
+}
+
+// Return puts the provided byte slice back on the free list.  Slices that do
+// not have the capacity of one of the buffer classes are ignored and left for
+// the garbage collector.
+func (c *payloadFreeList) Return(buf []byte) {
+	capacity := cap(buf)
+	if capacity < 1<<minPayloadClassBits || capacity > 1<<maxPayloadClassBits ||
+		capacity&(capacity-1) != 0 {
+
+		return
+	}
+
+	buf = buf[:0]
+	c[payloadClass(uint32(capacity))].Put(&buf)
+}
+
+// payloadPool is the concurrent safe free list used to read message payloads.
+var payloadPool payloadFreeList
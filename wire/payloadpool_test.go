@@ -0,0 +1,52 @@
+// Copyright (c) 2023 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"testing"
+)
+
+// TestPayloadFreeList ensures the payload free list returns buffers of the
+// requested size from the expected class.
+func TestPayloadFreeList(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    uint32
+		wantCap int
+	}{
+		{"empty", 0, 512},
+		{"smaller than min class", 1, 512},
+		{"exactly min class", 512, 512},
+		{"just over min class", 513, 1024},
+		{"exactly mid class", 1 << 16, 1 << 16},
+		{"just under max payload", MaxMessagePayload - 1, MaxMessagePayload},
+		{"max payload", MaxMessagePayload, MaxMessagePayload},
+		{"over max payload", MaxMessagePayload + 1, MaxMessagePayload + 1},
+	}
+
+	var pool payloadFreeList
+	for _, test := range tests {
+		buf := pool.Borrow(test.size)
+		if len(buf) != int(test.size) {
+			t.Errorf("%q: unexpected len - got %d, want %d", test.name,
+				len(buf), test.size)
+			continue
+		}
+		if cap(buf) != test.wantCap {
+			t.Errorf("%q: unexpected cap - got %d, want %d", test.name,
+				cap(buf), test.wantCap)
+			continue
+		}
+		pool.Return(buf)
+	}
+
+	// Ensure buffers that do not have the capacity of a class are ignored.
+	pool.Return(make([]byte, 600))
+	for i := 0; i < 10; i++ {
+		if buf := pool.Borrow(513); cap(buf) != 1024 {
+			t.Fatalf("unexpected cap - got %d, want %d", cap(buf), 1024)
+		}
+	}
+}
@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 9
+	ProtocolVersion uint32 = 13
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -51,6 +51,23 @@ const (
 	// RemoveRejectVersion is the protocol version which removes support for the
 	// reject message.
 	RemoveRejectVersion uint32 = 9
+
+	// TxReconciliationVersion is the protocol version which adds the
+	// SFNodeTxRecon service flag and the sendtxrcncl, reqrecon, sketch and
+	// reconcildiff messages.
+	TxReconciliationVersion uint32 = 10
+
+	// CompactBlockVersion is the protocol version which adds the sendcmpct,
+	// cmpctblock, getblocktxn, and blocktxn messages.
+	CompactBlockVersion uint32 = 11
+
+	// AddrV2Version is the protocol version which adds the addrv2 message
+	// which supports relaying TORv3 addresses.
+	AddrV2Version uint32 = 12
+
+	// I2PAddrVersion is the protocol version which adds support for relaying
+	// I2P addresses via the addrv2 message.
+	I2PAddrVersion uint32 = 13
 )
 
 // ServiceFlag identifies services supported by a Decred peer.
@@ -67,13 +84,23 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports v1 gcs filters
 	// (CFs).
 	SFNodeCF
+
+	// SFNodeTxRecon is a flag used to indicate a peer supports sketch-based
+	// transaction reconciliation.
+	SFNodeTxRecon
+
+	// SFNodeEncryption is a flag used to indicate a peer supports the
+	// encrypted and authenticated peer-to-peer transport.
+	SFNodeEncryption
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:    "SFNodeNetwork",
+	SFNodeBloom:      "SFNodeBloom",
+	SFNodeCF:         "SFNodeCF",
+	SFNodeTxRecon:    "SFNodeTxRecon",
+	SFNodeEncryption: "SFNodeEncryption",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -82,6 +109,8 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeTxRecon,
+	SFNodeEncryption,
 }
 
 // String returns the ServiceFlag in human-readable form.
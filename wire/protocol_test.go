@@ -17,7 +17,9 @@ func TestServiceFlagStringer(t *testing.T) {
 		{SFNodeNetwork, "SFNodeNetwork"},
 		{SFNodeBloom, "SFNodeBloom"},
 		{SFNodeCF, "SFNodeCF"},
-		{0xffffffff, "SFNodeNetwork|SFNodeBloom|SFNodeCF|0xfffffff8"},
+		{SFNodeTxRecon, "SFNodeTxRecon"},
+		{SFNodeEncryption, "SFNodeEncryption"},
+		{0xffffffff, "SFNodeNetwork|SFNodeBloom|SFNodeCF|SFNodeTxRecon|SFNodeEncryption|0xffffffe0"},
 	}
 
 	t.Logf("Running %d tests", len(tests))